@@ -0,0 +1,251 @@
+// Package accesstoken issues opaque access tokens and verifies access tokens of either kind.
+// Opaque tokens are random strings whose claims are kept in the cache store, so they can be
+// revoked at once; JWTs carry their claims and stay valid until they expire.
+package accesstoken
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/utils"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Prefix marks opaque access tokens, telling them apart from JWTs and API tokens
+const Prefix = "at_"
+
+// noSession stands in for the session of tokens issued without one
+const noSession = "none"
+
+// ErrInvalidToken is returned for opaque tokens that are unknown, expired or revoked
+var ErrInvalidToken = errors.New("invalid access token")
+
+// payload is what the cache store keeps for an opaque token
+type payload struct {
+	UserID    string   `json:"sub"`
+	SessionID string   `json:"sid,omitempty"`
+	Role      string   `json:"role"`
+	Scopes    []string `json:"scopes"`
+	Plan      string   `json:"plan,omitempty"`
+	IssuedAt  int64    `json:"iat"`
+}
+
+// Store issues, resolves and revokes opaque access tokens. A token is kept under
+// access:token:{sha256(token)}. So a user's or a session's tokens can be revoked without scanning
+// the keyspace, the hashes are indexed in the set access:session:{userID}:{sessionID}, and the
+// user's sessions in the set access:user:{userID}; each member expires with its token.
+type Store struct {
+	store cache.Store
+	index cache.Indexer
+}
+
+// ErrNoIndex is returned when issuing from a cache store that cannot index the tokens
+var ErrNoIndex = errors.New("cache store cannot index access tokens")
+
+// current is the store opaque tokens are issued from; nil while access tokens are JWTs
+var current atomic.Pointer[Store]
+
+// NewStore keeps opaque tokens in store, which must implement cache.Indexer to issue them
+func NewStore(store cache.Store) *Store {
+	index, _ := store.(cache.Indexer)
+	return &Store{store: store, index: index}
+}
+
+// Enable issues opaque access tokens from s instead of JWTs
+func Enable(s *Store) {
+	current.Store(s)
+}
+
+// Current returns the store opaque tokens are issued from, or nil in JWT mode
+func Current() *Store {
+	return current.Load()
+}
+
+// IsOpaque reports whether token looks like an opaque access token
+func IsOpaque(token string) bool {
+	return strings.HasPrefix(token, Prefix)
+}
+
+// Issue stores claims under a new opaque token that expires at expires
+func (s *Store) Issue(claims utils.AccessClaims, expires time.Time) (string, error) {
+	if !cache.IsStoreAvailable(s.store) {
+		return "", cache.ErrStoreUnavailable
+	}
+	if s.index == nil {
+		return "", ErrNoIndex
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := Prefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	data, err := json.Marshal(payload{
+		UserID:    claims.UserID,
+		SessionID: claims.SessionID,
+		Role:      claims.Role,
+		Scopes:    claims.Scopes,
+		Plan:      claims.Plan,
+		IssuedAt:  claims.IssuedAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ttl := time.Until(expires)
+	if ttl <= 0 {
+		return "", errors.New("access token already expired")
+	}
+
+	hash := tokenHash(token)
+	sessionID := sessionOrNone(claims.SessionID)
+	// The indexes go first: a token stored without them could not be revoked
+	ctx := context.Background()
+	if err := s.index.AddMember(ctx, userKey(claims.UserID), sessionID, ttl); err != nil {
+		return "", fmt.Errorf("failed to index access token: %w", err)
+	}
+	if err := s.index.AddMember(ctx, sessionKey(claims.UserID, sessionID), hash, ttl); err != nil {
+		return "", fmt.Errorf("failed to index access token: %w", err)
+	}
+	if err := s.store.Set(tokenKey(hash), data, ttl); err != nil {
+		return "", fmt.Errorf("failed to store access token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Resolve returns the claims of an opaque token, or ErrInvalidToken if it is not known
+func (s *Store) Resolve(token string) (*utils.AccessClaims, error) {
+	if s == nil || !IsOpaque(token) {
+		return nil, ErrInvalidToken
+	}
+
+	data, err := s.store.Get(tokenKey(tokenHash(token)))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrInvalidToken
+	}
+
+	var stored payload
+	if err := json.Unmarshal(data, &stored); err != nil || stored.UserID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &utils.AccessClaims{
+		UserID:    stored.UserID,
+		SessionID: stored.SessionID,
+		Role:      stored.Role,
+		Scopes:    stored.Scopes,
+		Plan:      stored.Plan,
+		IssuedAt:  time.Unix(stored.IssuedAt, 0),
+	}, nil
+}
+
+// RevokeSession deletes the opaque tokens issued to one session of the user. It does nothing on a
+// nil store, so callers need not check for JWT mode.
+func (s *Store) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if s == nil || s.index == nil {
+		return nil
+	}
+
+	keys, err := s.sessionTokens(ctx, userID, sessionOrNone(sessionID))
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteKeys(ctx, keys...)
+}
+
+// RevokeUser deletes every opaque token issued to the user. It does nothing on a nil store.
+func (s *Store) RevokeUser(ctx context.Context, userID string) error {
+	if s == nil || s.index == nil {
+		return nil
+	}
+
+	sessions, err := s.index.Members(ctx, userKey(userID))
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userKey(userID)}
+	for _, sessionID := range sessions {
+		tokens, err := s.sessionTokens(ctx, userID, sessionID)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, tokens...)
+	}
+	return s.store.DeleteKeys(ctx, keys...)
+}
+
+// sessionTokens returns the keys of the session's tokens along with the key of their index
+func (s *Store) sessionTokens(ctx context.Context, userID, sessionID string) ([]string, error) {
+	hashes, err := s.index.Members(ctx, sessionKey(userID, sessionID))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(hashes)+1)
+	keys = append(keys, sessionKey(userID, sessionID))
+	for _, hash := range hashes {
+		keys = append(keys, tokenKey(hash))
+	}
+	return keys, nil
+}
+
+// Verify checks an access token of either kind and returns its claims
+func Verify(token string) (*utils.AccessClaims, error) {
+	if IsOpaque(token) {
+		return Current().Resolve(token)
+	}
+	return utils.VerifyAccessToken(token, config.JWTSecret, config.TokenTypeAccess)
+}
+
+// VerifySession checks an access token of either kind and returns its subject and session ID. Unlike
+// Verify, it accepts JWTs issued without role and scope claims.
+func VerifySession(token string) (string, string, error) {
+	if IsOpaque(token) {
+		claims, err := Current().Resolve(token)
+		if err != nil {
+			return "", "", err
+		}
+		return claims.UserID, claims.SessionID, nil
+	}
+	return utils.VerifySessionToken(token, config.JWTSecret, config.TokenTypeAccess)
+}
+
+// tokenHash hashes the token so it never appears in the cache keyspace
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func tokenKey(hash string) string {
+	return cache.AccessTokenKeyPrefix + "token:" + hash
+}
+
+func userKey(userID string) string {
+	return cache.AccessTokenKeyPrefix + "user:" + userID
+}
+
+func sessionKey(userID, sessionID string) string {
+	return cache.AccessTokenKeyPrefix + "session:" + userID + ":" + sessionID
+}
+
+func sessionOrNone(sessionID string) string {
+	if sessionID == "" {
+		return noSession
+	}
+	return sessionID
+}
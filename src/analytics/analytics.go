@@ -0,0 +1,275 @@
+// Package analytics counts unique visitors in Redis HyperLogLogs: the users active per day and per
+// month, and the client IPs of each endpoint per day. A HyperLogLog estimates how many distinct
+// values it was given with a standard error of 0.81%, in 12 KB at most, and keeps none of the values
+// themselves. The sketches of the current periods are saved to the database, so the counts survive
+// Redis losing its data.
+package analytics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"app/src/clock"
+	"app/src/redis"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// KeyPrefix is the prefix of every analytics key
+// Format: analytics:dau:{day}, analytics:mau:{month}, analytics:ips:{day}:{endpoint} and
+// analytics:endpoints:{day}, the set of the endpoints visited that day
+const KeyPrefix = "analytics:"
+
+// Sketch kinds
+const (
+	KindDailyUsers   = "dau"
+	KindMonthlyUsers = "mau"
+	KindEndpointIPs  = "ips"
+)
+
+// Period formats
+const (
+	DayFormat   = time.DateOnly
+	MonthFormat = "2006-01"
+)
+
+// keep is how long a sketch stays in Redis after its period ended, for the last visits of the
+// period to be saved
+const keep = 48 * time.Hour
+
+// maxPending caps the visits an instance notes between flushes; more are not counted
+const maxPending = 100_000
+
+// mergeScript merges the saved sketch ARGV[1] into KEYS[1], through the scratch key KEYS[2], and
+// returns the merged sketch with its count. Merging keeps the largest of each register, so merging
+// a sketch twice changes nothing.
+var mergeScript = goredis.NewScript(`
+if ARGV[1] ~= "" then
+	redis.call("SET", KEYS[2], ARGV[1])
+	redis.call("PFMERGE", KEYS[1], KEYS[2])
+	redis.call("DEL", KEYS[2])
+end
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return {"", 0}
+end
+redis.call("PEXPIREAT", KEYS[1], ARGV[2])
+return {redis.call("GET", KEYS[1]), redis.call("PFCOUNT", KEYS[1])}
+`)
+
+// Sketch identifies the HyperLogLog of a period; Endpoint is only set for KindEndpointIPs
+type Sketch struct {
+	Kind     string
+	Period   string
+	Endpoint string
+}
+
+// DailyUsers is the sketch of the users active on day
+func DailyUsers(day time.Time) Sketch {
+	return Sketch{Kind: KindDailyUsers, Period: day.Format(DayFormat)}
+}
+
+// MonthlyUsers is the sketch of the users active in the month of day
+func MonthlyUsers(day time.Time) Sketch {
+	return Sketch{Kind: KindMonthlyUsers, Period: day.Format(MonthFormat)}
+}
+
+// EndpointIPs is the sketch of the client IPs of endpoint on day
+func EndpointIPs(day time.Time, endpoint string) Sketch {
+	return Sketch{Kind: KindEndpointIPs, Period: day.Format(DayFormat), Endpoint: endpoint}
+}
+
+func (s Sketch) key() string {
+	if s.Kind == KindEndpointIPs {
+		return KeyPrefix + s.Kind + ":" + s.Period + ":" + s.Endpoint
+	}
+	return KeyPrefix + s.Kind + ":" + s.Period
+}
+
+// expireAt is when the sketch leaves Redis, keep after its period
+func (s Sketch) expireAt() time.Time {
+	if s.Kind == KindMonthlyUsers {
+		month, _ := time.Parse(MonthFormat, s.Period)
+		return month.AddDate(0, 1, 0).Add(keep)
+	}
+	day, _ := time.Parse(DayFormat, s.Period)
+	return day.AddDate(0, 0, 1).Add(keep)
+}
+
+func endpointsKey(day string) string {
+	return KeyPrefix + "endpoints:" + day
+}
+
+// Tracker notes visits in memory and adds them to the sketches in Redis on every flush, so a
+// request costs no round trip
+type Tracker struct {
+	redisClient *redis.RedisClient
+	clock       clock.Clock
+
+	mu      sync.Mutex
+	pending map[Sketch]map[string]struct{}
+	noted   int
+}
+
+// NewTracker creates a tracker of the sketches in Redis; days are UTC days of clk
+func NewTracker(redisClient *redis.RedisClient, clk clock.Clock) *Tracker {
+	return &Tracker{
+		redisClient: redisClient,
+		clock:       clock.OrSystem(clk),
+		pending:     map[Sketch]map[string]struct{}{},
+	}
+}
+
+// Record notes a visit of endpoint from ip, by userID when it was authenticated
+func (t *Tracker) Record(endpoint, ip string, userID *uuid.UUID) {
+	day := t.clock.Now().UTC()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.noted >= maxPending {
+		return
+	}
+	t.note(EndpointIPs(day, endpoint), ip)
+	if userID != nil {
+		t.note(DailyUsers(day), userID.String())
+		t.note(MonthlyUsers(day), userID.String())
+	}
+}
+
+func (t *Tracker) note(sketch Sketch, value string) {
+	values, ok := t.pending[sketch]
+	if !ok {
+		values = map[string]struct{}{}
+		t.pending[sketch] = values
+	}
+	if _, ok := values[value]; !ok {
+		values[value] = struct{}{}
+		t.noted++
+	}
+}
+
+// Start flushes the visits noted every interval until ctx is done, then flushes once more
+func (t *Tracker) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			t.flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			t.flush(ctx)
+		}
+	}
+}
+
+// flush adds the visits noted to their sketches. Counting is best-effort: while Redis is
+// unavailable the visits are dropped rather than piling up.
+func (t *Tracker) flush(ctx context.Context) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending, t.noted = map[Sketch]map[string]struct{}{}, 0
+	t.mu.Unlock()
+
+	if len(pending) == 0 || !redis.IsAvailable() {
+		return
+	}
+
+	_, err := t.redisClient.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for sketch, values := range pending {
+			members := make([]interface{}, 0, len(values))
+			for value := range values {
+				members = append(members, value)
+			}
+
+			key := t.redisClient.Key(sketch.key())
+			pipe.PFAdd(ctx, key, members...)
+			pipe.ExpireAt(ctx, key, sketch.expireAt())
+			if sketch.Kind == KindEndpointIPs {
+				endpoints := t.redisClient.Key(endpointsKey(sketch.Period))
+				pipe.SAdd(ctx, endpoints, sketch.Endpoint)
+				pipe.ExpireAt(ctx, endpoints, sketch.expireAt())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Debugf("Failed to count unique visitors: %v", err)
+	}
+}
+
+// Count estimates the distinct values of the sketches in Redis
+func (t *Tracker) Count(ctx context.Context, sketches []Sketch) ([]int64, error) {
+	if !redis.IsAvailable() {
+		return nil, redis.ErrRedisUnavailable
+	}
+
+	counts := make([]*goredis.IntCmd, len(sketches))
+	_, err := t.redisClient.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, sketch := range sketches {
+			counts[i] = pipe.PFCount(ctx, t.redisClient.Key(sketch.key()))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]int64, len(sketches))
+	for i, count := range counts {
+		result[i] = count.Val()
+	}
+	return result, nil
+}
+
+// Endpoints lists the endpoints visited on day, as far as Redis still knows
+func (t *Tracker) Endpoints(ctx context.Context, day string) ([]string, error) {
+	if !redis.IsAvailable() {
+		return nil, redis.ErrRedisUnavailable
+	}
+
+	result, err := t.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return t.redisClient.GetClient().SMembers(ctx, t.redisClient.Key(endpointsKey(day))).Result()
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpoints, _ := result.([]string)
+	return endpoints, nil
+}
+
+// Merge merges the saved sketch into the one in Redis and returns the result with its count, nil
+// if neither holds any visit. A sketch Redis lost is restored from the saved one this way, and one
+// saved while Redis was empty keeps what it held.
+func (t *Tracker) Merge(ctx context.Context, sketch Sketch, saved []byte) ([]byte, int64, error) {
+	if !redis.IsAvailable() {
+		return nil, 0, redis.ErrRedisUnavailable
+	}
+
+	key := t.redisClient.Key(sketch.key())
+	result, err := t.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return mergeScript.Run(ctx, t.redisClient.GetClient(), []string{key, key + ":merge"},
+			saved, sketch.expireAt().UnixMilli()).Slice()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	merged, ok := result.([]interface{})
+	if !ok || len(merged) != 2 {
+		return nil, 0, errors.New("unexpected reply merging a sketch")
+	}
+	registers, _ := merged[0].(string)
+	count, _ := merged[1].(int64)
+	if registers == "" {
+		return nil, 0, nil
+	}
+	return []byte(registers), count, nil
+}
@@ -0,0 +1,247 @@
+// Package backup exports the user dataset to a versioned archive and restores it into another
+// database, for moving accounts between environments. An archive is gzipped JSON lines: a manifest,
+// one line per row, and a trailer with the row counts and a checksum, so a truncated or edited
+// archive is refused. With a passphrase the gzip stream is encrypted as well (see crypt.go).
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+// Format identifies backup archives in their manifest
+const Format = "app-backup"
+
+// Version is the archive layout written; readers refuse newer layouts
+const Version = 1
+
+// maxLine caps one line of an archive, i.e. one row
+const maxLine = 16 << 20
+
+// Manifest describes an archive
+type Manifest struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	// SchemaVersion is the last database migration applied where the archive was made; rows only
+	// fit a database migrated to the same version
+	SchemaVersion int64     `json:"schema_version"`
+	Environment   string    `json:"environment"`
+	CreatedAt     time.Time `json:"created_at"`
+	Tables        []string  `json:"tables"`
+}
+
+// trailer ends an archive
+type trailer struct {
+	Rows map[string]int64 `json:"rows"`
+	// SHA256 is the checksum of every row line, in order
+	SHA256 string `json:"sha256"`
+}
+
+type line struct {
+	Manifest *Manifest       `json:"manifest,omitempty"`
+	Table    string          `json:"table,omitempty"`
+	Row      json.RawMessage `json:"row,omitempty"`
+	Trailer  *trailer        `json:"trailer,omitempty"`
+}
+
+// ErrCorrupt is returned for archives that are truncated, edited or not archives at all
+var ErrCorrupt = errors.New("backup archive is corrupt or truncated")
+
+// Writer writes an archive
+type Writer struct {
+	closers []io.Closer
+	gzip    *gzip.Writer
+	encoder *json.Encoder
+	rows    map[string]int64
+	hash    hash.Hash
+}
+
+// NewWriter starts an archive on w, encrypted when passphrase is not empty
+func NewWriter(w io.Writer, manifest Manifest, passphrase string) (*Writer, error) {
+	archive := &Writer{rows: map[string]int64{}}
+
+	if passphrase != "" {
+		encrypted, err := newEncryptWriter(w, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		archive.closers = append(archive.closers, encrypted)
+		w = encrypted
+	}
+
+	archive.gzip = gzip.NewWriter(w)
+	archive.encoder = newEncoder(archive.gzip)
+	archive.hash = sha256.New()
+
+	manifest.Format = Format
+	manifest.Version = Version
+	if err := archive.encoder.Encode(line{Manifest: &manifest}); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// WriteRow appends a row of table, as the JSON object Postgres renders for it
+func (w *Writer) WriteRow(table string, row json.RawMessage) error {
+	entry := line{Table: table, Row: row}
+	if err := newEncoder(w.hash).Encode(entry); err != nil {
+		return err
+	}
+	if err := w.encoder.Encode(entry); err != nil {
+		return err
+	}
+	w.rows[table]++
+	return nil
+}
+
+// Close writes the trailer and flushes the archive; the archive is incomplete without it
+func (w *Writer) Close() (map[string]int64, error) {
+	if err := w.encoder.Encode(line{Trailer: &trailer{Rows: w.rows, SHA256: checksum(w.hash)}}); err != nil {
+		return nil, err
+	}
+	if err := w.gzip.Close(); err != nil {
+		return nil, err
+	}
+	for _, closer := range w.closers {
+		if err := closer.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return w.rows, nil
+}
+
+// Reader reads an archive, verifying its trailer once the last row is read
+type Reader struct {
+	manifest Manifest
+	scanner  *bufio.Scanner
+	rows     map[string]int64
+	hash     hash.Hash
+}
+
+// NewReader opens an archive, decrypting it with passphrase when it is encrypted, and reads its manifest
+func NewReader(r io.Reader, passphrase string) (*Reader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(len(encryptedMagic))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+
+	var source io.Reader = buffered
+	if string(magic) == encryptedMagic {
+		if passphrase == "" {
+			return nil, errors.New("backup archive is encrypted, set BACKUP_PASSPHRASE")
+		}
+		if source, err = newDecryptReader(buffered, passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	unzipped, err := gzip.NewReader(source)
+	if errors.Is(err, ErrDecrypt) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+
+	archive := &Reader{
+		scanner: bufio.NewScanner(unzipped),
+		rows:    map[string]int64{},
+		hash:    sha256.New(),
+	}
+	archive.scanner.Buffer(make([]byte, 64<<10), maxLine)
+
+	first, err := archive.next()
+	if err != nil {
+		return nil, err
+	}
+	if first.Manifest == nil || first.Manifest.Format != Format {
+		return nil, ErrCorrupt
+	}
+	if first.Manifest.Version > Version {
+		return nil, fmt.Errorf("backup archive version %d is newer than this app supports (%d)",
+			first.Manifest.Version, Version)
+	}
+	archive.manifest = *first.Manifest
+	return archive, nil
+}
+
+// Manifest describes the archive
+func (r *Reader) Manifest() Manifest {
+	return r.manifest
+}
+
+// Next returns the next row and its table, or io.EOF once the trailer matched the rows read
+func (r *Reader) Next() (string, json.RawMessage, error) {
+	entry, err := r.next()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if entry.Trailer != nil {
+		if entry.Trailer.SHA256 != checksum(r.hash) || !sameCounts(entry.Trailer.Rows, r.rows) {
+			return "", nil, ErrCorrupt
+		}
+		// The archive must end with the trailer, and the gzip stream with it
+		if r.scanner.Scan() || r.scanner.Err() != nil {
+			return "", nil, ErrCorrupt
+		}
+		return "", nil, io.EOF
+	}
+	if entry.Table == "" || len(entry.Row) == 0 {
+		return "", nil, ErrCorrupt
+	}
+
+	if err := newEncoder(r.hash).Encode(line{Table: entry.Table, Row: entry.Row}); err != nil {
+		return "", nil, err
+	}
+	r.rows[entry.Table]++
+	return entry.Table, entry.Row, nil
+}
+
+func (r *Reader) next() (*line, error) {
+	if !r.scanner.Scan() {
+		// A missing trailer means the archive was cut short
+		if err := r.scanner.Err(); errors.Is(err, ErrDecrypt) {
+			return nil, err
+		}
+		return nil, ErrCorrupt
+	}
+
+	entry := new(line)
+	if err := json.Unmarshal(r.scanner.Bytes(), entry); err != nil {
+		return nil, ErrCorrupt
+	}
+	return entry, nil
+}
+
+// newEncoder writes rows as Postgres rendered them, without escaping HTML characters
+func newEncoder(w io.Writer) *json.Encoder {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder
+}
+
+func sameCounts(expected, read map[string]int64) bool {
+	if len(expected) != len(read) {
+		return false
+	}
+	for table, rows := range expected {
+		if read[table] != rows {
+			return false
+		}
+	}
+	return true
+}
+
+func checksum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,226 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"app/src/schema"
+
+	"gorm.io/gorm"
+)
+
+// restoreBatch caps the rows inserted per statement on restore
+const restoreBatch = 500
+
+// IntegrityError reports a restored row referencing a row the archive does not hold
+type IntegrityError struct {
+	Table  string
+	Column string
+	Value  string
+	Parent string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("%s.%s references %s %s, which is not in the archive", e.Table, e.Column, e.Parent, e.Value)
+}
+
+// Export writes every table of the dataset to w as one archive, encrypted when passphrase is not
+// empty. The tables are read in one read-only snapshot, so the archive is consistent while the app
+// keeps serving. The rows written per table are returned.
+func Export(
+	ctx context.Context, db *gorm.DB, w io.Writer, environment, passphrase string,
+) (map[string]int64, error) {
+	var rows map[string]int64
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		version, err := schema.Version(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		manifest := Manifest{SchemaVersion: version, Environment: environment, CreatedAt: time.Now().UTC()}
+		for _, table := range Tables {
+			manifest.Tables = append(manifest.Tables, table.Name)
+		}
+
+		archive, err := NewWriter(w, manifest, passphrase)
+		if err != nil {
+			return err
+		}
+		for _, table := range Tables {
+			if err := exportTable(tx, archive, table); err != nil {
+				return fmt.Errorf("export %s: %w", table.Name, err)
+			}
+		}
+
+		rows, err = archive.Close()
+		return err
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+
+	return rows, err
+}
+
+func exportTable(tx *gorm.DB, archive *Writer, table Table) error {
+	cursor, err := tx.Raw(fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t ORDER BY %s", table.Name, table.Key)).Rows()
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		var row string
+		if err := cursor.Scan(&row); err != nil {
+			return err
+		}
+		if err := archive.WriteRow(table.Name, json.RawMessage(row)); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Restore loads an archive into db in one transaction, so a failed restore leaves nothing behind.
+// The database must be migrated to the version the archive was made at and its tables of the dataset
+// must be empty. Every reference of a row is checked against the rows restored before it, so an
+// archive whose rows do not reference each other consistently is refused with an IntegrityError.
+// The rows restored per table are returned.
+func Restore(ctx context.Context, db *gorm.DB, r io.Reader, passphrase string) (map[string]int64, error) {
+	archive, err := NewReader(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	manifest := archive.Manifest()
+
+	version, err := schema.Version(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.SchemaVersion != version {
+		return nil, fmt.Errorf("backup archive was made at schema version %d but the database is at %d, "+
+			"migrate both to the same version", manifest.SchemaVersion, version)
+	}
+
+	rows := map[string]int64{}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkEmpty(tx); err != nil {
+			return err
+		}
+
+		restore := &restorer{tx: tx, keys: map[string]map[string]bool{}, rows: rows}
+		for {
+			table, row, err := archive.Next()
+			if errors.Is(err, io.EOF) {
+				return restore.flush()
+			}
+			if err != nil {
+				return err
+			}
+			if err := restore.add(table, row); err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func checkEmpty(tx *gorm.DB) error {
+	for _, table := range Tables {
+		var found int64
+		if err := tx.Table(table.Name).Limit(1).Count(&found).Error; err != nil {
+			return err
+		}
+		if found > 0 {
+			return fmt.Errorf("table %s is not empty, restore into a freshly migrated database", table.Name)
+		}
+	}
+	return nil
+}
+
+type restorer struct {
+	tx *gorm.DB
+	// keys holds the IDs restored per referenced table
+	keys  map[string]map[string]bool
+	rows  map[string]int64
+	index int
+	table string
+	batch []json.RawMessage
+}
+
+func (r *restorer) add(name string, row json.RawMessage) error {
+	if name != r.table {
+		if err := r.flush(); err != nil {
+			return err
+		}
+		// Tables come in the order of Tables, so parents are restored before their references
+		index := tableIndex(name)
+		if index < r.index {
+			return fmt.Errorf("backup archive holds unknown table %s or tables out of order", name)
+		}
+		r.index, r.table = index, name
+	}
+
+	var columns map[string]any
+	if err := json.Unmarshal(row, &columns); err != nil {
+		return ErrCorrupt
+	}
+
+	table := Tables[r.index]
+	for _, reference := range table.References {
+		value, _ := columns[reference.Column].(string)
+		if value == "" && reference.Nullable {
+			continue
+		}
+		if !r.keys[reference.Table][value] {
+			return &IntegrityError{Table: table.Name, Column: reference.Column, Value: value, Parent: reference.Table}
+		}
+	}
+	if id, ok := columns["id"].(string); ok {
+		if r.keys[table.Name] == nil {
+			r.keys[table.Name] = map[string]bool{}
+		}
+		r.keys[table.Name][id] = true
+	}
+
+	r.batch = append(r.batch, row)
+	if len(r.batch) == restoreBatch {
+		return r.flush()
+	}
+	return nil
+}
+
+// flush inserts the rows batched; json_populate_recordset maps them onto the columns of the table
+func (r *restorer) flush() error {
+	if len(r.batch) == 0 {
+		return nil
+	}
+
+	batch, err := json.Marshal(r.batch)
+	if err != nil {
+		return err
+	}
+	statement := fmt.Sprintf("INSERT INTO %[1]s SELECT * FROM json_populate_recordset(NULL::%[1]s, ?::json)", r.table)
+	if err := r.tx.Exec(statement, string(batch)).Error; err != nil {
+		return fmt.Errorf("restore %s: %w", r.table, err)
+	}
+
+	r.rows[r.table] += int64(len(r.batch))
+	r.batch = r.batch[:0]
+	return nil
+}
+
+func tableIndex(name string) int {
+	for i, table := range Tables {
+		if table.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,191 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted archives start with encryptedMagic, a salt and a nonce prefix, followed by chunks of at
+// most chunkSize bytes, each sealed with AES-256-GCM under a key derived from the passphrase with
+// scrypt. A chunk's nonce is the prefix, its index and whether it is the last one, so chunks cannot
+// be reordered, dropped or cut off without failing authentication.
+const (
+	encryptedMagic = "APPBAK1E"
+	saltSize       = 16
+	noncePrefix    = 7
+	chunkSize      = 64 << 10
+)
+
+// ErrDecrypt is returned when an encrypted archive does not open with the passphrase given
+var ErrDecrypt = errors.New("backup archive cannot be decrypted, check BACKUP_PASSPHRASE")
+
+func deriveKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, index uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefix:], index)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix []byte
+	index  uint32
+	buf    []byte
+}
+
+func newEncryptWriter(w io.Writer, passphrase string) (*encryptWriter, error) {
+	header := make([]byte, len(encryptedMagic)+saltSize+noncePrefix)
+	copy(header, encryptedMagic)
+	if _, err := rand.Read(header[len(encryptedMagic):]); err != nil {
+		return nil, err
+	}
+
+	aead, err := deriveKey(passphrase, header[len(encryptedMagic):len(encryptedMagic)+saltSize])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		prefix: header[len(encryptedMagic)+saltSize:],
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+// Write seals a chunk only once more data follows it, so the last chunk is sealed as such by Close
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return 0, err
+			}
+		}
+		n := copy(e.buf[len(e.buf):chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.prefix, e.index, last), e.buf, nil)
+
+	frame := make([]byte, 5)
+	if last {
+		frame[0] = 1
+	}
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(sealed)))
+	if _, err := e.w.Write(frame); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+
+	e.index++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+type decryptReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	index  uint32
+	buf    []byte
+	done   bool
+}
+
+func newDecryptReader(r *bufio.Reader, passphrase string) (*decryptReader, error) {
+	header := make([]byte, len(encryptedMagic)+saltSize+noncePrefix)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrCorrupt
+	}
+
+	aead, err := deriveKey(passphrase, header[len(encryptedMagic):len(encryptedMagic)+saltSize])
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{r: r, aead: aead, prefix: header[len(encryptedMagic)+saltSize:]}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	frame := make([]byte, 5)
+	if _, err := io.ReadFull(d.r, frame); err != nil {
+		// Running out before the last chunk means the archive was cut short
+		return io.ErrUnexpectedEOF
+	}
+
+	size := binary.BigEndian.Uint32(frame[1:])
+	if size > chunkSize+uint32(d.aead.Overhead()) {
+		return ErrCorrupt
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+
+	last := frame[0] == 1
+	plain, err := d.aead.Open(nil, chunkNonce(d.prefix, d.index, last), sealed, nil)
+	if err != nil {
+		return ErrDecrypt
+	}
+
+	if last {
+		if _, err := d.r.ReadByte(); err != io.EOF {
+			return ErrCorrupt
+		}
+		d.done = true
+	}
+	d.index++
+	d.buf = plain
+	return nil
+}
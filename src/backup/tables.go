@@ -0,0 +1,50 @@
+package backup
+
+// Reference is a foreign key a restored row must satisfy
+type Reference struct {
+	Column string
+	Table  string
+	// Nullable references may be empty
+	Nullable bool
+}
+
+// Table is one table of the dataset
+type Table struct {
+	Name string
+	// Key orders the rows of the table in the archive
+	Key        string
+	References []Reference
+}
+
+// Tables is the dataset archived, parents before the tables referencing them so a restore can check
+// every reference against the rows read so far. Roles are the role column of users; their rights are
+// configuration. API tokens are kept as their hashes, so they keep working after a restore, while
+// sessions and one-time tokens are left out and users sign in again. Encrypted columns are archived
+// as stored and need the same ENCRYPTION_KEYS where they are restored.
+var Tables = []Table{
+	{Name: "users", Key: "created_at, id"},
+	{Name: "service_accounts", Key: "created_at, id", References: []Reference{
+		{Column: "user_id", Table: "users"},
+		{Column: "created_by", Table: "users", Nullable: true},
+	}},
+	{Name: "user_tags", Key: "user_id, tag", References: []Reference{
+		{Column: "user_id", Table: "users"},
+	}},
+	{Name: "legal_holds", Key: "user_id", References: []Reference{
+		{Column: "user_id", Table: "users"},
+		{Column: "placed_by", Table: "users", Nullable: true},
+	}},
+	{Name: "oauth_clients", Key: "created_at, id", References: []Reference{
+		{Column: "created_by", Table: "users", Nullable: true},
+	}},
+	{Name: "oauth_consents", Key: "created_at, id", References: []Reference{
+		{Column: "user_id", Table: "users"},
+		{Column: "client_id", Table: "oauth_clients"},
+	}},
+	{Name: "api_tokens", Key: "created_at, id", References: []Reference{
+		{Column: "user_id", Table: "users"},
+		{Column: "client_id", Table: "oauth_clients", Nullable: true},
+	}},
+	// Audit entries outlive their users, so their user_id is no reference
+	{Name: "audit_logs", Key: "created_at, id"},
+}
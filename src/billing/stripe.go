@@ -0,0 +1,88 @@
+// Package billing talks to Stripe: it creates customers through the REST API and verifies and
+// decodes the webhooks Stripe sends when subscriptions change.
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"app/src/httpclient"
+)
+
+// Client calls the Stripe API with a secret key
+type Client struct {
+	baseURL   string
+	secretKey string
+	http      *http.Client
+}
+
+// Customer is the part of a Stripe customer the app uses
+type Customer struct {
+	ID string `json:"id"`
+}
+
+// apiError is the error body returned by Stripe
+type apiError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewClient creates a Stripe client for the API at baseURL
+func NewClient(baseURL, secretKey string) *Client {
+	return &Client{
+		baseURL:   baseURL,
+		secretKey: secretKey,
+		http:      httpclient.New("stripe"),
+	}
+}
+
+// CreateCustomer creates a customer for the user. The user ID is the idempotency key, so a retried
+// call returns the same customer instead of creating a duplicate.
+func (c *Client) CreateCustomer(ctx context.Context, userID, email, name string) (*Customer, error) {
+	form := url.Values{
+		"email":             {email},
+		"name":              {name},
+		"metadata[user_id]": {userID},
+	}
+
+	customer := new(Customer)
+	if err := c.post(ctx, "/v1/customers", form, "customer-"+userID, customer); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// post sends a form-encoded POST and decodes the JSON response into out
+func (c *Client) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	body := form.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.secretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		failure := new(apiError)
+		_ = json.NewDecoder(resp.Body).Decode(failure)
+		return fmt.Errorf("stripe returned status %d: %s", resp.StatusCode, failure.Error.Message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode stripe response: %w", err)
+	}
+	return nil
+}
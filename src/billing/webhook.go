@@ -0,0 +1,141 @@
+package billing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the webhook timestamp and HMAC signatures
+const SignatureHeader = "Stripe-Signature"
+
+// Subscription event types handled by the app
+const (
+	EventSubscriptionCreated = "customer.subscription.created"
+	EventSubscriptionUpdated = "customer.subscription.updated"
+	EventSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+// EntitledStatuses are the subscription statuses that grant the subscribed plan. Past-due
+// subscriptions keep it while Stripe retries the payment.
+var EntitledStatuses = []string{"active", "trialing", "past_due"}
+
+var (
+	// ErrInvalidSignature means the webhook was not signed with the endpoint secret
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrExpiredSignature means the webhook was signed too long ago and may be a replay
+	ErrExpiredSignature = errors.New("webhook signature has expired")
+)
+
+// Event is a Stripe webhook event
+type Event struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// Subscription is the part of a Stripe subscription the app stores
+type Subscription struct {
+	ID                string `json:"id"`
+	Customer          string `json:"customer"`
+	Status            string `json:"status"`
+	CancelAtPeriodEnd bool   `json:"cancel_at_period_end"`
+	CurrentPeriodEnd  int64  `json:"current_period_end"`
+	Items             struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// ConstructEvent verifies the Stripe-Signature header of a webhook and decodes its payload. The
+// header holds a timestamp and one or more v1 signatures, any of which may match (several are sent
+// while the endpoint secret is being rolled).
+func ConstructEvent(payload []byte, header, secret string, tolerance time.Duration, now time.Time) (*Event, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return nil, ErrInvalidSignature
+	}
+
+	expected := Sign(payload, secret, unix)
+	valid := false
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, ErrInvalidSignature
+	}
+
+	if tolerance > 0 && now.Sub(time.Unix(unix, 0)) > tolerance {
+		return nil, ErrExpiredSignature
+	}
+
+	event := new(Event)
+	if err := json.Unmarshal(payload, event); err != nil || event.ID == "" {
+		return nil, errors.New("invalid webhook payload")
+	}
+	return event, nil
+}
+
+// Sign returns the v1 signature of a payload sent at the given Unix time
+func Sign(payload []byte, secret string, timestamp int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// PriceID returns the price of the subscription's first item
+func (s *Subscription) PriceID() string {
+	if len(s.Items.Data) == 0 {
+		return ""
+	}
+	return s.Items.Data[0].Price.ID
+}
+
+// PeriodEnd returns when the current billing period ends; newer API versions only set it per item
+func (s *Subscription) PeriodEnd() *time.Time {
+	end := s.CurrentPeriodEnd
+	if end == 0 && len(s.Items.Data) > 0 {
+		end = s.Items.Data[0].CurrentPeriodEnd
+	}
+	if end == 0 {
+		return nil
+	}
+
+	t := time.Unix(end, 0).UTC()
+	return &t
+}
+
+// Entitled reports whether a subscription in this status grants its plan
+func Entitled(status string) bool {
+	return slices.Contains(EntitledStatuses, status)
+}
@@ -0,0 +1,119 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"app/src/validation"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// ErrInvalidBody is returned when the payload cannot be decoded into the target struct
+var ErrInvalidBody = fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+
+// Bind decodes a JSON, form or multipart body into out and validates it.
+// Use `json` tags for JSON payloads and `form` tags for form and multipart fields;
+// a struct may carry both so one endpoint accepts either encoding.
+func Bind(c *fiber.Ctx, validate *validator.Validate, out interface{}) error {
+	if err := c.BodyParser(out); err != nil {
+		if errors.Is(err, fiber.ErrUnprocessableEntity) {
+			return fiber.NewError(fiber.StatusUnsupportedMediaType, "Unsupported content type")
+		}
+		return ErrInvalidBody
+	}
+
+	return validate.Struct(out)
+}
+
+// FileRule limits an uploaded file part
+type FileRule struct {
+	Required bool
+	// MaxSize is the maximum file size in bytes (0 means no limit beyond the app BodyLimit)
+	MaxSize int64
+	// AllowedTypes are media types matched against the sniffed content, not the client-declared
+	// type; CSV and other plain text files sniff as "text/plain"
+	AllowedTypes []string
+}
+
+// File extracts and checks the multipart file part named field.
+// It returns nil without error when an optional file is absent.
+func File(c *fiber.Ctx, field string, rule FileRule) (*multipart.FileHeader, error) {
+	header, err := c.FormFile(field)
+	if err != nil {
+		missing := errors.Is(err, fasthttp.ErrMissingFile) || errors.Is(err, fasthttp.ErrNoMultipartForm)
+		if missing && !rule.Required {
+			return nil, nil
+		}
+		return nil, validation.FieldErrors{field: fmt.Sprintf("Field %s must be a file upload", field)}
+	}
+
+	if rule.MaxSize > 0 && header.Size > rule.MaxSize {
+		return nil, validation.FieldErrors{
+			field: fmt.Sprintf("File %s must not exceed %s", field, formatSize(rule.MaxSize)),
+		}
+	}
+
+	if len(rule.AllowedTypes) > 0 {
+		contentType, err := sniffContentType(header)
+		if err != nil {
+			return nil, ErrInvalidBody
+		}
+		if !allowed(contentType, rule.AllowedTypes) {
+			return nil, validation.FieldErrors{
+				field: fmt.Sprintf("File %s must be one of: %s", field, strings.Join(rule.AllowedTypes, ", ")),
+			}
+		}
+	}
+
+	return header, nil
+}
+
+// sniffContentType detects the media type from the first 512 bytes of the file
+func sniffContentType(header *multipart.FileHeader) (string, error) {
+	file, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 512)
+	n, err := file.Read(buf)
+	if err != nil && n == 0 {
+		return "", err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+	if err != nil {
+		return "", err
+	}
+	return mediaType, nil
+}
+
+func allowed(contentType string, allowedTypes []string) bool {
+	for _, t := range allowedTypes {
+		if strings.EqualFold(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSize renders a byte count for error messages
+func formatSize(size int64) string {
+	const unit = 1024
+	switch {
+	case size >= unit*unit:
+		return fmt.Sprintf("%d MB", size/(unit*unit))
+	case size >= unit:
+		return fmt.Sprintf("%d KB", size/unit)
+	default:
+		return fmt.Sprintf("%d bytes", size)
+	}
+}
@@ -0,0 +1,185 @@
+package binding
+
+import (
+	"encoding/json"
+	"maps"
+	"mime"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Media types of PATCH bodies besides plain JSON
+const (
+	MIMEJSONPatch  = "application/json-patch+json"
+	MIMEMergePatch = "application/merge-patch+json"
+)
+
+// Patch is the outcome of a JSON Patch (RFC 6902) or merge patch (RFC 7396) on a flat document
+type Patch struct {
+	// Changes maps each field the patch changed to its new value; nil clears the field
+	Changes map[string]any
+	// Tests maps fields tested before any change to the value they had to have, so the update can
+	// be made conditional on them still having it
+	Tests map[string]any
+}
+
+// patchOperation is one operation of a JSON Patch document
+type patchOperation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// IsPatch reports whether the request body is a JSON Patch or a merge patch
+func IsPatch(c *fiber.Ctx) bool {
+	mediaType := patchMediaType(c)
+	return mediaType == MIMEJSONPatch || mediaType == MIMEMergePatch
+}
+
+// BindPatch applies the JSON Patch or merge patch in the body to doc, the current values of the
+// resource's fields, and returns what changed. doc is left untouched. Only top-level fields of doc
+// can be patched; a failed "test" operation is answered with 409.
+func BindPatch(c *fiber.Ctx, doc map[string]any) (*Patch, error) {
+	patched := maps.Clone(doc)
+	patch := &Patch{Changes: map[string]any{}, Tests: map[string]any{}}
+
+	var err error
+	switch patchMediaType(c) {
+	case MIMEJSONPatch:
+		err = applyJSONPatch(c.Body(), doc, patched, patch.Tests)
+	case MIMEMergePatch:
+		err = applyMergePatch(c.Body(), doc, patched)
+	default:
+		return nil, fiber.NewError(fiber.StatusUnsupportedMediaType, "Unsupported content type")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for field, value := range doc {
+		if patchedValue, ok := patched[field]; !ok {
+			patch.Changes[field] = nil
+		} else if !reflect.DeepEqual(value, patchedValue) {
+			patch.Changes[field] = patchedValue
+		}
+	}
+
+	return patch, nil
+}
+
+func patchMediaType(c *fiber.Ctx) string {
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	return mediaType
+}
+
+// applyJSONPatch runs the operations in order on doc, whose fields are those of fields, recording
+// the tests made before their field was changed
+func applyJSONPatch(body []byte, fields, doc, tests map[string]any) error {
+	var operations []patchOperation
+	if err := json.Unmarshal(body, &operations); err != nil {
+		return ErrInvalidBody
+	}
+
+	changed := map[string]bool{}
+	for _, operation := range operations {
+		field, err := patchField(fields, operation.Path)
+		if err != nil {
+			return err
+		}
+
+		var value any
+		switch operation.Op {
+		case "add", "replace", "test":
+			if operation.Value == nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Patch operation "+operation.Op+" needs a value")
+			}
+			if err := json.Unmarshal(*operation.Value, &value); err != nil {
+				return ErrInvalidBody
+			}
+		case "move", "copy":
+			from, err := patchField(fields, operation.From)
+			if err != nil {
+				return err
+			}
+			if _, ok := doc[from]; !ok {
+				return patchPathMissing(operation.From)
+			}
+			value = doc[from]
+			if operation.Op == "move" {
+				delete(doc, from)
+				changed[from] = true
+			}
+		case "remove":
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "Unsupported patch operation "+operation.Op)
+		}
+
+		_, exists := doc[field]
+		switch operation.Op {
+		case "test":
+			if !exists || !reflect.DeepEqual(doc[field], value) {
+				return fiber.NewError(fiber.StatusConflict, "Patch test failed for "+operation.Path)
+			}
+			if !changed[field] {
+				tests[field] = value
+			}
+		case "remove":
+			if !exists {
+				return patchPathMissing(operation.Path)
+			}
+			delete(doc, field)
+			changed[field] = true
+		case "replace":
+			if !exists {
+				return patchPathMissing(operation.Path)
+			}
+			fallthrough
+		default:
+			doc[field] = value
+			changed[field] = true
+		}
+	}
+
+	return nil
+}
+
+// applyMergePatch sets the members of the patch object on doc, removing those set to null
+func applyMergePatch(body []byte, fields, doc map[string]any) error {
+	var members map[string]any
+	if err := json.Unmarshal(body, &members); err != nil || members == nil {
+		return ErrInvalidBody
+	}
+
+	for field, value := range members {
+		if _, known := fields[field]; !known {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, "Field "+field+" cannot be patched")
+		}
+		if value == nil {
+			delete(doc, field)
+		} else {
+			doc[field] = value
+		}
+	}
+
+	return nil
+}
+
+// patchField resolves a JSON Pointer to one of the top-level fields
+func patchField(fields map[string]any, path string) (string, error) {
+	if !strings.HasPrefix(path, "/") || strings.Count(path, "/") > 1 {
+		return "", fiber.NewError(fiber.StatusUnprocessableEntity, "Path "+path+" cannot be patched")
+	}
+
+	field := strings.NewReplacer("~1", "/", "~0", "~").Replace(path[1:])
+	if _, known := fields[field]; !known {
+		return "", fiber.NewError(fiber.StatusUnprocessableEntity, "Path "+path+" cannot be patched")
+	}
+	return field, nil
+}
+
+func patchPathMissing(path string) error {
+	return fiber.NewError(fiber.StatusUnprocessableEntity, "Path "+path+" does not exist")
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeyPrefixes lists the key namespaces reported in cache statistics
+var KeyPrefixes = []string{
+	ResponseKeyPrefix,
+	SessionKeyPrefix,
+	AccessTokenKeyPrefix,
+	RateLimitKeyPrefix,
+	ThrottleKeyPrefix,
+	LoginDelayKeyPrefix,
+	CaptchaKeyPrefix,
+	RiskKeyPrefix,
+	DebugCaptureKeyPrefix,
+	QuotaKeyPrefix,
+	UsageKeyPrefix,
+	EmailRateKeyPrefix,
+	NegativeKeyPrefix,
+}
+
+// PurgeablePrefixes lists the namespaces a purge by pattern may reach: cached responses, sessions
+// and lookups, which are rebuilt on demand. Locks, tokens, counters and switches are left alone.
+var PurgeablePrefixes = []string{ResponseKeyPrefix, SessionKeyPrefix, NegativeKeyPrefix}
+
+// ErrNotPurgeable is returned for purge patterns that could match keys outside PurgeablePrefixes
+var ErrNotPurgeable = errors.New("pattern is outside the purgeable cache namespaces")
+
+// TagPatterns maps purge tags to the key patterns they cover
+var TagPatterns = map[string]string{
+	"responses": ResponseKeyPrefix + "*",
+	"sessions":  SessionKeyPrefix + "*",
+	"users":     ResponseKeyPrefix + "*/v1/users*",
+}
+
+// Stats holds a snapshot of cache statistics
+type Stats struct {
+	Enabled     bool
+	Backend     string
+	KeyCounts   map[string]int64
+	Hits        uint64
+	Misses      uint64
+	HitRatio    float64
+	MemoryUsage map[string]string
+}
+
+// CacheAdmin provides operator-facing cache inspection and purge operations
+type CacheAdmin struct {
+	store       Store
+	invalidator *CacheInvalidator
+}
+
+// NewCacheAdmin creates a new cache admin
+// Returns nil if store is nil (no cache to administer if caching disabled)
+func NewCacheAdmin(store Store, invalidator *CacheInvalidator) *CacheAdmin {
+	if store == nil {
+		return nil
+	}
+	return &CacheAdmin{
+		store:       store,
+		invalidator: invalidator,
+	}
+}
+
+// Stats collects key counts by prefix, hit/miss ratio and backend memory usage
+func (ca *CacheAdmin) Stats(ctx context.Context) (*Stats, error) {
+	hits, misses := ResponseCacheCounts()
+
+	stats := &Stats{
+		Enabled:   IsResponseCacheEnabled(),
+		Backend:   ca.store.Backend(),
+		KeyCounts: make(map[string]int64, len(KeyPrefixes)),
+		Hits:      hits,
+		Misses:    misses,
+	}
+
+	if total := hits + misses; total > 0 {
+		stats.HitRatio = float64(hits) / float64(total)
+	}
+
+	for _, prefix := range KeyPrefixes {
+		keys, err := ca.store.Keys(ctx, prefix+"*")
+		if err != nil {
+			return nil, err
+		}
+		stats.KeyCounts[prefix] = int64(len(keys))
+	}
+
+	if reporter, ok := ca.store.(MemoryReporter); ok {
+		memory, err := reporter.MemoryInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		stats.MemoryUsage = memory
+	}
+
+	return stats, nil
+}
+
+// PurgeByPattern deletes all keys matching the pattern, which must be purgeable
+func (ca *CacheAdmin) PurgeByPattern(ctx context.Context, pattern string) (InvalidationResult, error) {
+	if !IsPurgeable(pattern) {
+		return InvalidationResult{}, ErrNotPurgeable
+	}
+	return ca.invalidator.InvalidateByPattern(ctx, pattern)
+}
+
+// PurgeByTag deletes all keys covered by a purge tag
+func (ca *CacheAdmin) PurgeByTag(ctx context.Context, tag string) (InvalidationResult, error) {
+	pattern, err := PatternForTag(tag)
+	if err != nil {
+		return InvalidationResult{}, err
+	}
+	return ca.invalidator.InvalidateByPattern(ctx, pattern)
+}
+
+// DryRun reports which keys a purge by pattern or tag would delete
+func (ca *CacheAdmin) DryRun(ctx context.Context, pattern, tag string) (InvalidationResult, error) {
+	if tag != "" {
+		var err error
+		if pattern, err = PatternForTag(tag); err != nil {
+			return InvalidationResult{}, err
+		}
+	} else if !IsPurgeable(pattern) {
+		return InvalidationResult{}, ErrNotPurgeable
+	}
+	return ca.invalidator.DryRunByPattern(ctx, pattern)
+}
+
+// PatternForTag resolves a purge tag to its key pattern
+func PatternForTag(tag string) (string, error) {
+	pattern, ok := TagPatterns[tag]
+	if !ok {
+		return "", fmt.Errorf("unknown cache tag: %s", tag)
+	}
+	return pattern, nil
+}
+
+// IsPurgeable reports whether pattern starts with one of PurgeablePrefixes, so it only matches keys
+// in those namespaces
+func IsPurgeable(pattern string) bool {
+	for _, prefix := range PurgeablePrefixes {
+		if strings.HasPrefix(pattern, prefix) {
+			return true
+		}
+	}
+	return false
+}
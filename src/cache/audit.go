@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"time"
+
+	"app/src/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxDryRunKeys caps how many matched keys a dry run returns
+const MaxDryRunKeys = 100
+
+// InvalidationResult describes what a pattern invalidation matched and deleted
+type InvalidationResult struct {
+	Pattern  string
+	Matched  int
+	Deleted  int
+	Duration time.Duration
+	DryRun   bool
+	// Keys holds up to MaxDryRunKeys matched keys, for dry runs only
+	Keys []string
+}
+
+var (
+	invalidationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Name:      "cache_invalidations_total",
+		Help:      "Cache invalidations by mode (delete, dry_run) and outcome (success, error).",
+	}, []string{"mode", "outcome"})
+
+	invalidatedKeysTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Name:      "cache_invalidation_keys_total",
+		Help:      "Cache keys matched and deleted by invalidations.",
+	}, []string{"kind"})
+
+	invalidationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Name:      "cache_invalidation_duration_seconds",
+		Help:      "Time spent scanning and deleting keys per invalidation.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .5, 1, 5},
+	}, []string{"mode"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(invalidationsTotal, invalidatedKeysTotal, invalidationDuration)
+}
+
+// recordInvalidation exports an invalidation result to metrics and the debug log
+func recordInvalidation(result InvalidationResult, err error) {
+	mode := "delete"
+	if result.DryRun {
+		mode = "dry_run"
+	}
+
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+
+	invalidationsTotal.WithLabelValues(mode, outcome).Inc()
+	invalidationDuration.WithLabelValues(mode).Observe(result.Duration.Seconds())
+	if !result.DryRun {
+		invalidatedKeysTotal.WithLabelValues("matched").Add(float64(result.Matched))
+		invalidatedKeysTotal.WithLabelValues("deleted").Add(float64(result.Deleted))
+	}
+
+	entry := logrus.WithFields(logrus.Fields{
+		"pattern":  result.Pattern,
+		"matched":  result.Matched,
+		"deleted":  result.Deleted,
+		"duration": result.Duration,
+		"dry_run":  result.DryRun,
+	})
+	if err != nil {
+		entry.WithError(err).Warn("Cache invalidation failed")
+		return
+	}
+	entry.Debug("Cache invalidation")
+}
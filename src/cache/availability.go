@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"app/src/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SubscribeAvailability forwards Redis availability transitions to fn when the store is Redis-backed.
+// In-memory stores never become unavailable, so nothing is registered for them.
+func SubscribeAvailability(store Store, fn func(available bool)) {
+	if store == nil || store.Backend() != BackendRedis {
+		return
+	}
+	redis.Subscribe(fn)
+}
+
+// IsStoreAvailable reports whether the store can currently serve requests
+func IsStoreAvailable(store Store) bool {
+	if store == nil {
+		return false
+	}
+	if store.Backend() != BackendRedis {
+		return true
+	}
+	return redis.IsAvailable()
+}
+
+// PurgePrefix deletes every key under prefix
+// Used after an outage, since invalidations issued while Redis was down were lost
+func PurgePrefix(store Store, prefix string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	keys, err := store.Keys(ctx, prefix+"*")
+	if err != nil {
+		logrus.Warnf("Failed to list %s keys for purge: %v", prefix, err)
+		return
+	}
+
+	if err := store.DeleteKeys(ctx, keys...); err != nil {
+		logrus.Warnf("Failed to purge %s keys: %v", prefix, err)
+		return
+	}
+
+	logrus.Infof("Purged %d %s keys", len(keys), prefix)
+}
@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Writes an invalidation rule reacts to
+const (
+	WriteCreate = 1 << iota
+	WriteUpdate
+	WriteDelete
+)
+
+// InvalidationRule maps writes to a table to the cache entries they make stale
+type InvalidationRule struct {
+	// UserColumn holds the user a row belongs to; that user's session and responses are dropped
+	UserColumn string
+	// Writes are the statements the rule reacts to
+	Writes int
+	// Tags are purge tags (see TagPatterns) dropped along with the users' entries
+	Tags []string
+}
+
+// InvalidationRules lists the tables whose writes invalidate cached entries, by table name. New
+// tokens only come with logins and refreshes, which cache the session they start themselves.
+var InvalidationRules = map[string]InvalidationRule{
+	"users":     {UserColumn: "id", Writes: WriteUpdate | WriteDelete, Tags: []string{"users"}},
+	"user_tags": {UserColumn: "user_id", Writes: WriteCreate | WriteUpdate | WriteDelete},
+	"tokens":    {UserColumn: "user_id", Writes: WriteDelete},
+}
+
+// affectedKey stores the users an update or delete is about to change between its callbacks
+const affectedKey = "cache:affected"
+
+type affected struct {
+	userIDs []string
+	tags    []string
+}
+
+// GormPlugin drops the cache entries of the users a write changes, following InvalidationRules, so
+// services don't have to. The users are read from the written values when they hold the user
+// column, or else loaded with the statement's conditions before it runs; soft-deleted rows are
+// skipped like in any query unless the statement is unscoped. Entries are dropped once the
+// statement succeeds, which inside a transaction is before the commit. Raw SQL is not tracked.
+type GormPlugin struct {
+	invalidator *CacheInvalidator
+}
+
+var _ gorm.Plugin = GormPlugin{}
+
+// NewGormPlugin creates the plugin invalidating through invalidator
+func NewGormPlugin(invalidator *CacheInvalidator) GormPlugin {
+	return GormPlugin{invalidator: invalidator}
+}
+
+func (GormPlugin) Name() string {
+	return "cache"
+}
+
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().After("gorm:create").Register("cache:after_create", p.afterCreate),
+		callbacks.Update().Before("gorm:update").Register("cache:before_update", before(WriteUpdate)),
+		callbacks.Update().After("gorm:update").Register("cache:after_update", p.after),
+		callbacks.Delete().Before("gorm:delete").Register("cache:before_delete", before(WriteDelete)),
+		callbacks.Delete().After("gorm:delete").Register("cache:after_delete", p.after),
+	)
+}
+
+// ruleFor returns the rule of the statement's table if it reacts to the write
+func ruleFor(db *gorm.DB, write int) (InvalidationRule, *schema.Field, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return InvalidationRule{}, nil, false
+	}
+
+	rule, ok := InvalidationRules[db.Statement.Schema.Table]
+	if !ok || rule.Writes&write == 0 {
+		return InvalidationRule{}, nil, false
+	}
+
+	field := db.Statement.Schema.LookUpField(rule.UserColumn)
+	return rule, field, field != nil
+}
+
+func (p GormPlugin) afterCreate(db *gorm.DB) {
+	rule, field, ok := ruleFor(db, WriteCreate)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+
+	if userIDs, _ := valueUsers(db, field); len(userIDs) > 0 {
+		p.invalidate(db, affected{userIDs: userIDs, tags: rule.Tags})
+	}
+}
+
+func before(write int) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		rule, field, ok := ruleFor(db, write)
+		if !ok {
+			return
+		}
+
+		userIDs, complete := valueUsers(db, field)
+		if !complete {
+			var err error
+			if userIDs, err = queryUsers(db, field); err != nil {
+				logrus.Warnf("Failed to load users changed in %s, cache not invalidated: %v", db.Statement.Schema.Table, err)
+				return
+			}
+		}
+
+		db.InstanceSet(affectedKey, affected{userIDs: userIDs, tags: rule.Tags})
+	}
+}
+
+func (p GormPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(affectedKey)
+	if !ok || db.Error != nil || db.RowsAffected == 0 {
+		return
+	}
+	p.invalidate(db, value.(affected))
+}
+
+func (p GormPlugin) invalidate(db *gorm.DB, changed affected) {
+	if len(changed.userIDs) == 0 {
+		return
+	}
+
+	// The statement's own deadline ends with it
+	ctx := context.WithoutCancel(db.Statement.Context)
+	p.invalidator.InvalidateUsers(ctx, changed.userIDs...)
+	for _, tag := range changed.tags {
+		if _, err := p.invalidator.InvalidateByPattern(ctx, TagPatterns[tag]); err != nil {
+			logrus.Warnf("Failed to invalidate %s cache: %v", tag, err)
+		}
+	}
+}
+
+// valueUsers returns the user column of the rows in the statement's value, and whether every row had
+// it set, as when a loaded row is updated or deleted
+func valueUsers(db *gorm.DB, field *schema.Field) ([]string, bool) {
+	var userIDs []string
+	complete := true
+
+	var each func(value reflect.Value)
+	each = func(value reflect.Value) {
+		switch value.Kind() {
+		case reflect.Struct:
+			if value.Type() != db.Statement.Schema.ModelType {
+				complete = false
+				return
+			}
+			userID, zero := field.ValueOf(db.Statement.Context, value)
+			if zero {
+				complete = false
+				return
+			}
+			userIDs = append(userIDs, fmt.Sprint(userID))
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < value.Len(); i++ {
+				each(reflect.Indirect(value.Index(i)))
+			}
+		default:
+			complete = false
+		}
+	}
+	each(db.Statement.ReflectValue)
+
+	return userIDs, complete && len(userIDs) > 0
+}
+
+// queryUsers loads the user column of the rows the statement's conditions match, on its connection so
+// it shares the transaction. Without conditions gorm refuses the statement anyway.
+func queryUsers(db *gorm.DB, field *schema.Field) ([]string, error) {
+	where, ok := db.Statement.Clauses["WHERE"]
+	if !ok {
+		return nil, nil
+	}
+
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).
+		Model(reflect.New(db.Statement.Schema.ModelType).Interface()).
+		Clauses(where.Expression)
+	if db.Statement.Unscoped {
+		tx = tx.Unscoped()
+	}
+
+	var userIDs []string
+	err := tx.Distinct(field.DBName).Pluck(field.DBName, &userIDs).Error
+	return userIDs, err
+}
@@ -3,27 +3,24 @@ package cache
 import (
 	"context"
 	"fmt"
+	"time"
 
-	"app/src/redis"
-
-	goredis "github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
 // CacheInvalidator handles cache invalidation operations
 type CacheInvalidator struct {
-	redisClient *goredis.Client
+	store Store
 }
 
 // NewCacheInvalidator creates a new cache invalidator
-// Returns nil if redisClient is nil (no invalidation if Redis disabled)
-func NewCacheInvalidator(redisClient *redis.RedisClient) *CacheInvalidator {
-	if redisClient == nil {
+// Returns nil if store is nil (no invalidation if caching disabled)
+func NewCacheInvalidator(store Store) *CacheInvalidator {
+	if store == nil {
 		return nil
 	}
-	goRedisClient := redisClient.GetClient()
 	return &CacheInvalidator{
-		redisClient: goRedisClient,
+		store: store,
 	}
 }
 
@@ -46,13 +43,35 @@ func (ci *CacheInvalidator) InvalidateUserRelatedCache(ctx context.Context, user
 	// Use existing GetAPIResponseKeyPattern(userID)
 	apiPattern := GetAPIResponseKeyPattern(userID) // "api:response:*:user:{userID}:*"
 
-	if err := ci.InvalidateByPattern(ctx, apiPattern); err != nil {
+	if _, err := ci.InvalidateByPattern(ctx, apiPattern); err != nil {
 		logrus.Warnf("Failed to invalidate API response cache for user %s: %v", userID, err)
 	}
 
 	return nil
 }
 
+// InvalidateUsers drops the sessions of the users with a single DEL, and their API responses.
+// Failures are logged; the entries expire with their TTL.
+func (ci *CacheInvalidator) InvalidateUsers(ctx context.Context, userIDs ...string) {
+	if ci == nil || len(userIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = GetSessionKey(userID)
+	}
+	if err := ci.store.DeleteKeys(ctx, keys...); err != nil {
+		logrus.Warnf("Failed to invalidate session cache for %d users: %v", len(userIDs), err)
+	}
+
+	for _, userID := range userIDs {
+		if _, err := ci.InvalidateByPattern(ctx, GetAPIResponseKeyPattern(userID)); err != nil {
+			logrus.Warnf("Failed to invalidate API response cache for user %s: %v", userID, err)
+		}
+	}
+}
+
 // InvalidateSessionCache invalidates session cache for user
 // Uses pattern-based deletion with SCAN for session:user:{userID}
 func (ci *CacheInvalidator) InvalidateSessionCache(ctx context.Context, userID string) error {
@@ -67,35 +86,49 @@ func (ci *CacheInvalidator) InvalidateSessionCache(ctx context.Context, userID s
 	logrus.Infof("Invalidating session cache for user %s (pattern: %s)", userID, sessionPattern)
 
 	// Use existing InvalidateByPattern (SCAN-based deletion)
-	return ci.InvalidateByPattern(ctx, sessionPattern)
+	_, err := ci.InvalidateByPattern(ctx, sessionPattern)
+	return err
 }
 
 // InvalidateByPattern deletes all cache keys matching the given pattern
-// Uses SCAN instead of KEYS to avoid blocking Redis server in production
-func (ci *CacheInvalidator) InvalidateByPattern(ctx context.Context, pattern string) error {
-	if ci == nil || ci.redisClient == nil {
-		return nil
-	}
+func (ci *CacheInvalidator) InvalidateByPattern(ctx context.Context, pattern string) (InvalidationResult, error) {
+	return ci.invalidate(ctx, pattern, false)
+}
 
-	// Use SCAN to find keys matching pattern (DO NOT use KEYS - it's blocking)
-	iter := ci.redisClient.Scan(ctx, 0, pattern, 0).Iterator()
-	var keys []string
-	for iter.Next(ctx) {
-		keys = append(keys, iter.Val())
-	}
+// DryRunByPattern reports which keys an invalidation would delete without deleting them
+func (ci *CacheInvalidator) DryRunByPattern(ctx context.Context, pattern string) (InvalidationResult, error) {
+	return ci.invalidate(ctx, pattern, true)
+}
 
-	// Check for iteration errors
-	if err := iter.Err(); err != nil {
-		return fmt.Errorf("scan iterator error: %w", err)
+func (ci *CacheInvalidator) invalidate(ctx context.Context, pattern string, dryRun bool) (InvalidationResult, error) {
+	result := InvalidationResult{Pattern: pattern, DryRun: dryRun}
+	if ci == nil || ci.store == nil {
+		return result, nil
 	}
 
-	// Delete found keys
-	if len(keys) > 0 {
-		if err := ci.redisClient.Del(ctx, keys...).Err(); err != nil {
-			return fmt.Errorf("failed to delete keys: %w", err)
+	start := time.Now()
+
+	// Store.Keys uses SCAN on Redis (DO NOT use KEYS - it's blocking)
+	keys, err := ci.store.Keys(ctx, pattern)
+	if err == nil {
+		result.Matched = len(keys)
+
+		if dryRun {
+			result.Keys = keys
+			if len(result.Keys) > MaxDryRunKeys {
+				result.Keys = result.Keys[:MaxDryRunKeys]
+			}
+		} else if len(keys) > 0 {
+			if err = ci.store.DeleteKeys(ctx, keys...); err != nil {
+				err = fmt.Errorf("failed to delete keys: %w", err)
+			} else {
+				result.Deleted = len(keys)
+			}
 		}
-		logrus.Debugf("Invalidated %d cache keys matching pattern: %s", len(keys), pattern)
 	}
 
-	return nil
+	result.Duration = time.Since(start)
+	recordInvalidation(result, err)
+
+	return result, err
 }
@@ -0,0 +1,236 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryJanitorInterval is how often expired entries are evicted
+const memoryJanitorInterval = time.Minute
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time            // zero means no expiration
+	members   map[string]time.Time // the expiry of each member of a set
+}
+
+// MemoryStore is an in-process Store for single-node deployments without Redis
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewMemoryStore creates an in-memory store and starts its expiration janitor
+func NewMemoryStore() *MemoryStore {
+	store := &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		done:    make(chan struct{}),
+	}
+	go store.janitor()
+	return store
+}
+
+// Get returns the value for key, or nil if it does not exist or has expired
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	entry, ok := s.entries[key]
+	s.mu.RUnlock()
+
+	if !ok || entry.expired(time.Now()) {
+		return nil, nil
+	}
+
+	return entry.value, nil
+}
+
+// Set stores the value for key; a zero expiration keeps it until deleted
+func (s *MemoryStore) Set(key string, val []byte, exp time.Duration) error {
+	if key == "" || len(val) == 0 {
+		return nil
+	}
+
+	entry := memoryEntry{value: append([]byte(nil), val...)}
+	if exp > 0 {
+		entry.expiresAt = time.Now().Add(exp)
+	}
+
+	s.mu.Lock()
+	s.entries[key] = entry
+	s.mu.Unlock()
+
+	return nil
+}
+
+// IncrBy adds n to the counter at key, starting from zero when it does not exist or has expired
+func (s *MemoryStore) IncrBy(_ context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := s.entries[key]
+	if !ok || entry.expired(now) {
+		entry = memoryEntry{}
+		if ttl > 0 {
+			entry.expiresAt = now.Add(ttl)
+		}
+	}
+
+	value, _ := strconv.ParseInt(string(entry.value), 10, 64)
+	value += n
+	entry.value = []byte(strconv.FormatInt(value, 10))
+	s.entries[key] = entry
+
+	return value, nil
+}
+
+// AddMember adds member to the set at key until ttl elapses, dropping the members that expired
+func (s *MemoryStore) AddMember(_ context.Context, key, member string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := s.entries[key]
+	if !ok || entry.expired(now) || entry.members == nil {
+		entry = memoryEntry{members: make(map[string]time.Time)}
+	}
+	for m, expiresAt := range entry.members {
+		if !expiresAt.After(now) {
+			delete(entry.members, m)
+		}
+	}
+
+	expiresAt := now.Add(ttl)
+	if expiresAt.After(entry.members[member]) {
+		entry.members[member] = expiresAt
+	}
+	if expiresAt.After(entry.expiresAt) {
+		entry.expiresAt = expiresAt
+	}
+	s.entries[key] = entry
+
+	return nil
+}
+
+// Members returns the live members of the set at key
+func (s *MemoryStore) Members(_ context.Context, key string) ([]string, error) {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var members []string
+	for member, expiresAt := range s.entries[key].members {
+		if expiresAt.After(now) {
+			members = append(members, member)
+		}
+	}
+
+	return members, nil
+}
+
+// Delete removes key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// Reset removes all entries
+func (s *MemoryStore) Reset() error {
+	s.mu.Lock()
+	s.entries = make(map[string]memoryEntry)
+	s.mu.Unlock()
+	return nil
+}
+
+// Close stops the expiration janitor
+func (s *MemoryStore) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return nil
+}
+
+// Keys returns all live keys matching the glob pattern
+func (s *MemoryStore) Keys(_ context.Context, pattern string) ([]string, error) {
+	matcher := globToRegexp(pattern)
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []string
+	for key, entry := range s.entries {
+		if !entry.expired(now) && matcher.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// DeleteKeys removes all given keys
+func (s *MemoryStore) DeleteKeys(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// Backend returns the backend name
+func (s *MemoryStore) Backend() string {
+	return BackendMemory
+}
+
+// janitor periodically evicts expired entries until the store is closed
+func (s *MemoryStore) janitor() {
+	ticker := time.NewTicker(memoryJanitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, entry := range s.entries {
+				if entry.expired(now) {
+					delete(s.entries, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// MemoryInfo reports the number of entries and bytes held in process memory
+func (s *MemoryStore) MemoryInfo(_ context.Context) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var size int
+	for key, entry := range s.entries {
+		size += len(key) + len(entry.value)
+		for member := range entry.members {
+			size += len(member)
+		}
+	}
+
+	return map[string]string{
+		"entries":    strconv.Itoa(len(s.entries)),
+		"used_bytes": strconv.Itoa(size),
+	}, nil
+}
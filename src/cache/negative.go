@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// NegativeKindUserID marks user lookups by ID that found no record
+	NegativeKindUserID = "user:id"
+
+	// NegativeKindUserEmail marks user lookups by email that found no record
+	NegativeKindUserEmail = "user:email"
+)
+
+// NegativeCache stores short-lived "not found" markers so repeated lookups
+// of missing records (deleted users, random IDs) don't reach the database.
+// Values are hashed, so emails never appear in the keyspace. Keys hash the lowercased value, so
+// Forget clears a marker whatever the case of the address; the marker holds the hash of the exact
+// value, as lookups are case-sensitive and another casing may exist.
+type NegativeCache struct {
+	store Store
+	ttl   time.Duration
+}
+
+// NewNegativeCache creates a new negative cache
+// Returns nil if store is nil (every lookup goes to the database if caching disabled)
+func NewNegativeCache(store Store, ttl time.Duration) *NegativeCache {
+	if store == nil {
+		return nil
+	}
+	return &NegativeCache{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// IsMissing returns true if the lookup is known to have found no record
+// Any store error is treated as unknown so the caller falls back to the database
+func (nc *NegativeCache) IsMissing(_ context.Context, kind, value string) bool {
+	if nc == nil {
+		return false
+	}
+
+	data, err := nc.store.Get(negativeKey(kind, value))
+	return err == nil && bytes.Equal(data, negativeMarker(value))
+}
+
+// MarkMissing records that the lookup found no record
+func (nc *NegativeCache) MarkMissing(_ context.Context, kind, value string) {
+	if nc == nil {
+		return
+	}
+
+	if err := nc.store.Set(negativeKey(kind, value), negativeMarker(value), nc.ttl); err != nil {
+		logrus.Warnf("Failed to set negative cache entry for %s: %v", kind, err)
+	}
+}
+
+// Forget removes a not-found marker, e.g. once the record is created
+func (nc *NegativeCache) Forget(_ context.Context, kind, value string) {
+	if nc == nil {
+		return
+	}
+
+	if err := nc.store.Delete(negativeKey(kind, value)); err != nil {
+		logrus.Warnf("Failed to remove negative cache entry for %s: %v", kind, err)
+	}
+}
+
+func negativeKey(kind, value string) string {
+	return GetNegativeKey(kind, hashNegativeValue(strings.ToLower(value)))
+}
+
+// negativeMarker is the value stored for a not-found entry of value
+func negativeMarker(value string) []byte {
+	return []byte(hashNegativeValue(value))
+}
+
+func hashNegativeValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -8,6 +8,78 @@ const (
 	// SessionKeyPrefix is the prefix for session cache keys
 	// Format: session:user:{userID}
 	SessionKeyPrefix = "session:user:"
+
+	// EvictedSessionKeyPrefix marks refresh tokens ended by the concurrent session limit
+	// Format: session:evicted:{sha256(refreshToken)}
+	EvictedSessionKeyPrefix = "session:evicted:"
+
+	// SessionActivityKeyPrefix buffers the last activity of a session until it is flushed to the database
+	// Format: session:active:{sessionID}
+	SessionActivityKeyPrefix = "session:active:"
+
+	// AccessTokenKeyPrefix is the prefix for opaque access tokens and the indexes used to revoke them
+	// Format: access:token:{sha256(token)}, access:session:{userID}:{sessionID} and access:user:{userID}
+	AccessTokenKeyPrefix = "access:"
+
+	// ResponseKeyPrefix is the prefix for API response cache keys (see middleware/cache/keygen.go)
+	// Format: api:response:{method}:{path}?{query}
+	ResponseKeyPrefix = "api:response:"
+
+	// RateLimitKeyPrefix is the prefix for rate limiter counters
+	// Format: rate_limit:{user|ip}:{id}
+	RateLimitKeyPrefix = "rate_limit:"
+
+	// ThrottleKeyPrefix is the prefix for per-target auth throttling state
+	// Format: throttle:{endpoint}:{sha256(target)}
+	ThrottleKeyPrefix = "throttle:"
+
+	// LoginDelayKeyPrefix is the prefix for failed login counts and the attempt gates of their delays
+	// Format: login_delay:{sha256(email)}[:{failures}]
+	LoginDelayKeyPrefix = "login_delay:"
+
+	// CaptchaKeyPrefix is the prefix for failure counters that trigger CAPTCHA challenges
+	// Format: captcha:{action}:{sha256(target)}
+	CaptchaKeyPrefix = "captcha:"
+
+	// RiskKeyPrefix is the prefix for login risk state (known locations, failure velocity)
+	// Format: risk:{history|velocity|pending}:{id}
+	RiskKeyPrefix = "risk:"
+
+	// DebugCaptureKeyPrefix is the prefix for captured request/response bodies
+	// Format: debug:capture:{requestID}
+	DebugCaptureKeyPrefix = "debug:capture:"
+
+	// OAuthStateKeyPrefix is the prefix for pending OAuth logins (PKCE verifier and nonce)
+	// Format: oauth:state:{stateID}
+	OAuthStateKeyPrefix = "oauth:state:"
+
+	// OAuthCodeKeyPrefix is the prefix for authorization codes issued to third-party clients
+	// Format: oauth:code:{sha256(code)}[:claimed]
+	OAuthCodeKeyPrefix = "oauth:code:"
+
+	// OAuthClientRateKeyPrefix is the prefix for the per-minute request counters of third-party clients
+	// Format: oauth:client_rate:{clientID}:{minuteStart}
+	OAuthClientRateKeyPrefix = "oauth:client_rate:"
+
+	// QuotaKeyPrefix is the prefix for request quota counters, persisted to the database periodically
+	// Format: quota:{user|token}:{id}:{day|month}:{periodStart}
+	QuotaKeyPrefix = "quota:"
+
+	// UsageKeyPrefix is the prefix for usage metering counters, rolled up into the database periodically
+	// Format: usage:{day}:{userID}:{user|token}:{id}:{metric}
+	UsageKeyPrefix = "usage:"
+
+	// EmailRateKeyPrefix is the prefix for outbound email counters of a recipient or of all instances
+	// Format: email_rate:{recipient:{sha256(email)}|global}:{windowStart}
+	EmailRateKeyPrefix = "email_rate:"
+
+	// PhoneVerificationKeyPrefix is the prefix for phone numbers awaiting verification and their attempt counts
+	// Format: phone_verify:{userID}[:attempts]
+	PhoneVerificationKeyPrefix = "phone_verify:"
+
+	// NegativeKeyPrefix is the prefix for negative (not-found) lookup entries
+	// Format: negative:{kind}:{sha256(lowercase(value))}
+	NegativeKeyPrefix = "negative:"
 )
 
 // GetSessionKey returns user session cache key
@@ -22,9 +94,51 @@ func GetSessionPattern(userID string) string {
 	return fmt.Sprintf("%s%s", SessionKeyPrefix, userID)
 }
 
+// GetEvictedSessionKey returns the eviction marker key for a hashed refresh token
+// Format: session:evicted:{tokenHash}
+func GetEvictedSessionKey(tokenHash string) string {
+	return fmt.Sprintf("%s%s", EvictedSessionKeyPrefix, tokenHash)
+}
+
+// GetSessionActivityKey returns the buffered activity key of a session
+// Format: session:active:{sessionID}
+func GetSessionActivityKey(sessionID string) string {
+	return fmt.Sprintf("%s%s", SessionActivityKeyPrefix, sessionID)
+}
+
+// GetQuotaKey returns the request counter key of a quota subject for the period starting at start
+// Format: quota:{subject}:{period}:{periodStart}
+func GetQuotaKey(subject, period, start string) string {
+	return fmt.Sprintf("%s%s:%s:%s", QuotaKeyPrefix, subject, period, start)
+}
+
+// GetUsageKey returns the counter key of a metric for a subject on a UTC day
+// Format: usage:{day}:{userID}:{subject}:{metric}
+func GetUsageKey(day, userID, subject, metric string) string {
+	return fmt.Sprintf("%s%s:%s:%s:%s", UsageKeyPrefix, day, userID, subject, metric)
+}
+
 // GetAPIResponseKeyPattern returns pattern for API response cache invalidation
 // Matches all API response cache keys containing user data: api:response:*:user:{userID}:*
 // Format: api:response:{method}:{path}?{query}:user:{userID} (from Phase 3 middleware/keygen.go)
 func GetAPIResponseKeyPattern(userID string) string {
 	return fmt.Sprintf("api:response:*:user:%s:*", userID)
 }
+
+// GetDebugCaptureKey returns the key of a captured request
+// Format: debug:capture:{requestID}
+func GetDebugCaptureKey(requestID string) string {
+	return fmt.Sprintf("%s%s", DebugCaptureKeyPrefix, requestID)
+}
+
+// GetNegativeKey returns the negative cache key for a lookup kind and the hash of its lowercased value
+// Format: negative:{kind}:{valueHash}
+func GetNegativeKey(kind, valueHash string) string {
+	return fmt.Sprintf("%s%s:%s", NegativeKeyPrefix, kind, valueHash)
+}
+
+// GetOAuthStateKey returns the key of a pending OAuth login
+// Format: oauth:state:{stateID}
+func GetOAuthStateKey(stateID string) string {
+	return fmt.Sprintf("%s%s", OAuthStateKeyPrefix, stateID)
+}
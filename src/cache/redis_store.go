@@ -0,0 +1,296 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/src/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// addMemberScript adds ARGV[1] to the sorted set KEYS[1], scored with its expiry ARGV[2] in unix
+// milliseconds, drops the members that expired by ARGV[3] and keeps the set until its last member expires
+var addMemberScript = goredis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[3])
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if not score or tonumber(score) < tonumber(ARGV[2]) then
+	redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+end
+local last = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+redis.call("PEXPIREAT", KEYS[1], last[2])
+return 1
+`)
+
+// RedisStore is a Store backed by Redis with circuit breaker protection. Keys are given and
+// returned without the Redis namespace, which the store adds itself.
+type RedisStore struct {
+	redisClient *redis.RedisClient
+}
+
+// NewRedisStore creates a Redis-backed store
+func NewRedisStore(redisClient *redis.RedisClient) *RedisStore {
+	return &RedisStore{
+		redisClient: redisClient,
+	}
+}
+
+// Get returns the value for key, or nil if it does not exist
+func (s *RedisStore) Get(key string) ([]byte, error) {
+	if key == "" {
+		return nil, nil
+	}
+	if !redis.IsAvailable() {
+		return nil, ErrStoreUnavailable
+	}
+
+	ctx := context.Background()
+	result, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		data, err := s.redisClient.GetClient().Get(ctx, s.redisClient.Key(key)).Bytes()
+		if errors.Is(err, goredis.Nil) {
+			return []byte(nil), nil
+		}
+		return data, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, _ := result.([]byte)
+	return data, nil
+}
+
+// Set stores the value for key; a zero expiration keeps it until deleted
+func (s *RedisStore) Set(key string, val []byte, exp time.Duration) error {
+	if key == "" || len(val) == 0 {
+		return nil
+	}
+	if !redis.IsAvailable() {
+		return ErrStoreUnavailable
+	}
+
+	ctx := context.Background()
+	_, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return nil, s.redisClient.GetClient().Set(ctx, s.redisClient.Key(key), val, exp).Err()
+	})
+	return err
+}
+
+// IncrBy adds n to the counter at key with INCRBY and sets ttl when the increment created it
+func (s *RedisStore) IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	if !redis.IsAvailable() {
+		return 0, ErrStoreUnavailable
+	}
+
+	result, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		client := s.redisClient.GetClient()
+		value, err := client.IncrBy(ctx, s.redisClient.Key(key), n).Result()
+		if err != nil {
+			return int64(0), err
+		}
+		if value == n && ttl > 0 {
+			err = client.Expire(ctx, s.redisClient.Key(key), ttl).Err()
+		}
+		return value, err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	value, _ := result.(int64)
+	return value, nil
+}
+
+// GetMany returns the values of keys with a single MGET, with nil for the missing ones
+func (s *RedisStore) GetMany(ctx context.Context, keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if !redis.IsAvailable() {
+		return nil, ErrStoreUnavailable
+	}
+
+	result, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return s.redisClient.GetClient().MGet(ctx, s.namespaced(keys)...).Result()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, _ := result.([]interface{})
+	values := make([][]byte, len(keys))
+	for i := range raw {
+		if value, ok := raw[i].(string); ok {
+			values[i] = []byte(value)
+		}
+	}
+	return values, nil
+}
+
+// SetMany stores all entries in one pipeline; MSET is not used as it cannot set expirations
+func (s *RedisStore) SetMany(ctx context.Context, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if !redis.IsAvailable() {
+		return ErrStoreUnavailable
+	}
+
+	_, err := s.redisClient.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, entry := range entries {
+			if entry.Key == "" || len(entry.Value) == 0 {
+				continue
+			}
+			pipe.Set(ctx, s.redisClient.Key(entry.Key), entry.Value, entry.TTL)
+		}
+		return nil
+	})
+	return err
+}
+
+// AddMember adds member to the sorted set at key, scored with the time it expires
+func (s *RedisStore) AddMember(ctx context.Context, key, member string, ttl time.Duration) error {
+	if !redis.IsAvailable() {
+		return ErrStoreUnavailable
+	}
+
+	now := time.Now()
+	_, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return nil, addMemberScript.Run(ctx, s.redisClient.GetClient(), []string{s.redisClient.Key(key)},
+			member, now.Add(ttl).UnixMilli(), now.UnixMilli()).Err()
+	})
+	return err
+}
+
+// Members returns the members of the sorted set at key that have not expired yet
+func (s *RedisStore) Members(ctx context.Context, key string) ([]string, error) {
+	if !redis.IsAvailable() {
+		return nil, ErrStoreUnavailable
+	}
+
+	result, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return s.redisClient.GetClient().ZRangeByScore(ctx, s.redisClient.Key(key), &goredis.ZRangeBy{
+			Min: "(" + strconv.FormatInt(time.Now().UnixMilli(), 10),
+			Max: "+inf",
+		}).Result()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members, _ := result.([]string)
+	return members, nil
+}
+
+// Delete removes key
+func (s *RedisStore) Delete(key string) error {
+	if key == "" {
+		return nil
+	}
+	return s.DeleteKeys(context.Background(), key)
+}
+
+// Reset removes all keys owned by the application namespaces
+// FLUSHDB is deliberately avoided because the Redis database may be shared
+func (s *RedisStore) Reset() error {
+	ctx := context.Background()
+	for _, prefix := range KeyPrefixes {
+		keys, err := s.Keys(ctx, prefix+"*")
+		if err != nil {
+			return err
+		}
+		if err := s.DeleteKeys(ctx, keys...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close is a no-op - the underlying client is owned by the redis package
+func (s *RedisStore) Close() error {
+	return nil
+}
+
+// Keys returns all keys matching the pattern, without the namespace
+// Uses SCAN instead of KEYS to avoid blocking Redis server in production
+func (s *RedisStore) Keys(ctx context.Context, pattern string) ([]string, error) {
+	if !redis.IsAvailable() {
+		return nil, ErrStoreUnavailable
+	}
+
+	namespace := s.redisClient.Namespace()
+	iter := s.redisClient.GetClient().Scan(ctx, 0, namespace+pattern, 0).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, strings.TrimPrefix(iter.Val(), namespace))
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("scan iterator error: %w", err)
+	}
+
+	return keys, nil
+}
+
+// DeleteKeys removes all given keys with a single DEL
+func (s *RedisStore) DeleteKeys(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if !redis.IsAvailable() {
+		return ErrStoreUnavailable
+	}
+
+	_, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return nil, s.redisClient.GetClient().Del(ctx, s.namespaced(keys)...).Err()
+	})
+	return err
+}
+
+// Backend returns the backend name
+func (s *RedisStore) Backend() string {
+	return BackendRedis
+}
+
+// namespaced returns keys inside the client's namespace
+func (s *RedisStore) namespaced(keys []string) []string {
+	if s.redisClient.Namespace() == "" {
+		return keys
+	}
+
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = s.redisClient.Key(key)
+	}
+	return names
+}
+
+// MemoryInfo parses the memory section of Redis INFO into human-readable fields
+func (s *RedisStore) MemoryInfo(ctx context.Context) (map[string]string, error) {
+	info, err := s.redisClient.GetClient().Info(ctx, "memory").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redis memory info: %w", err)
+	}
+
+	fields := map[string]string{
+		"used_memory_human":      "",
+		"used_memory_peak_human": "",
+		"maxmemory_human":        "",
+		"maxmemory_policy":       "",
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(info))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if _, wanted := fields[key]; found && wanted {
+			fields[key] = value
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Skip rule kinds
+const (
+	// SkipPath skips a path prefix, matched on whole segments: "/v1/admin" covers "/v1/admin/cache" but
+	// not "/v1/administrators"
+	SkipPath = "path"
+	// SkipPattern skips paths matching a regular expression
+	SkipPattern = "pattern"
+	// SkipMethod skips a request method, such as HEAD
+	SkipMethod = "method"
+	// SkipHeader skips requests carrying a header ("X-Preview") or a header value ("X-Preview=1")
+	SkipHeader = "header"
+)
+
+// SkipRule keeps matching requests out of the response cache and request deduplication
+type SkipRule struct {
+	Kind  string
+	Value string
+}
+
+type headerRule struct {
+	name  string
+	value string
+	any   bool
+}
+
+// SkipList is a compiled set of skip rules
+type SkipList struct {
+	paths    []string
+	patterns []*regexp.Regexp
+	methods  []string
+	headers  []headerRule
+}
+
+// skipList holds the rules applied to requests; it is replaced whole when rules change
+var skipList atomic.Pointer[SkipList]
+
+// NewSkipList compiles rules, failing on the first invalid one
+func NewSkipList(rules []SkipRule) (*SkipList, error) {
+	list := new(SkipList)
+
+	for _, rule := range rules {
+		value := strings.TrimSpace(rule.Value)
+		if value == "" {
+			return nil, fmt.Errorf("empty %s skip rule", rule.Kind)
+		}
+
+		switch rule.Kind {
+		case SkipPath:
+			if !strings.HasPrefix(value, "/") {
+				return nil, fmt.Errorf("skip path %q must start with /", value)
+			}
+			list.paths = append(list.paths, strings.TrimSuffix(value, "/"))
+		case SkipPattern:
+			pattern, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid skip pattern %q: %w", value, err)
+			}
+			list.patterns = append(list.patterns, pattern)
+		case SkipMethod:
+			list.methods = append(list.methods, strings.ToUpper(value))
+		case SkipHeader:
+			name, headerValue, found := strings.Cut(value, "=")
+			if name = strings.TrimSpace(name); name == "" {
+				return nil, fmt.Errorf("skip header %q has no name", value)
+			}
+			list.headers = append(list.headers, headerRule{
+				name:  name,
+				value: strings.TrimSpace(headerValue),
+				any:   !found,
+			})
+		default:
+			return nil, fmt.Errorf("unknown skip rule kind %q", rule.Kind)
+		}
+	}
+
+	return list, nil
+}
+
+// ValidateSkipRule reports why a rule would not compile, or nil
+func ValidateSkipRule(rule SkipRule) error {
+	_, err := NewSkipList([]SkipRule{rule})
+	return err
+}
+
+// Skips reports whether a request bypasses the cache. header returns a request header, or nil when
+// there is no request, as when describing a route; header rules then never match.
+func (l *SkipList) Skips(method, path string, header func(name string) string) bool {
+	if l == nil {
+		return false
+	}
+
+	for _, prefix := range l.paths {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	for _, pattern := range l.patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	for _, skipped := range l.methods {
+		if method == skipped {
+			return true
+		}
+	}
+
+	if header == nil {
+		return false
+	}
+	for _, rule := range l.headers {
+		value := header(rule.name)
+		if (rule.any && value != "") || (!rule.any && strings.EqualFold(value, rule.value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSkipList replaces the rules applied to requests
+func SetSkipList(list *SkipList) {
+	skipList.Store(list)
+}
+
+// CurrentSkipList returns the rules applied to requests, or nil if none were set
+func CurrentSkipList() *SkipList {
+	return skipList.Load()
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"sync/atomic"
+)
+
+var (
+	// responseCacheDisabled is the runtime toggle for the response cache (false = enabled)
+	responseCacheDisabled atomic.Bool
+
+	// responseCacheHits and responseCacheMisses count response cache lookups since startup
+	responseCacheHits   atomic.Uint64
+	responseCacheMisses atomic.Uint64
+)
+
+// SetResponseCacheEnabled enables or disables the response cache on this instance; ResponseCacheSwitch
+// switches it on every instance
+func SetResponseCacheEnabled(enabled bool) {
+	responseCacheDisabled.Store(!enabled)
+}
+
+// IsResponseCacheEnabled returns true if the response cache is enabled
+func IsResponseCacheEnabled() bool {
+	return !responseCacheDisabled.Load()
+}
+
+// RecordResponseCacheHit increments the response cache hit counter
+func RecordResponseCacheHit() {
+	responseCacheHits.Add(1)
+}
+
+// RecordResponseCacheMiss increments the response cache miss counter
+func RecordResponseCacheMiss() {
+	responseCacheMisses.Add(1)
+}
+
+// ResponseCacheCounts returns the response cache hit and miss counters
+func ResponseCacheCounts() (uint64, uint64) {
+	return responseCacheHits.Load(), responseCacheMisses.Load()
+}
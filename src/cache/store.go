@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"app/src/redis"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BackendRedis stores cache data in Redis (shared across instances)
+	BackendRedis = "redis"
+
+	// BackendMemory stores cache data in process memory (single-node deployments)
+	BackendMemory = "memory"
+)
+
+// ErrStoreUnavailable is returned when the cache backend cannot serve requests
+var ErrStoreUnavailable = errors.New("cache store unavailable")
+
+// Store is the cache backend abstraction used by the session service, response cache
+// and rate limiter. It satisfies fiber.Storage so middlewares can use it directly.
+type Store interface {
+	fiber.Storage
+
+	// Keys returns all keys matching a Redis-style glob pattern (*, ?)
+	Keys(ctx context.Context, pattern string) ([]string, error)
+
+	// DeleteKeys removes the given keys in a single operation where supported
+	DeleteKeys(ctx context.Context, keys ...string) error
+
+	// Backend returns the backend name (redis, memory)
+	Backend() string
+}
+
+// Counter is implemented by stores that can increment a numeric value atomically
+type Counter interface {
+	// IncrBy adds n to the counter at key and returns the new value; ttl is applied when the key is created
+	IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error)
+}
+
+// Entry is a value to store under Key for TTL (zero keeps it until deleted)
+type Entry struct {
+	Key   string
+	Value []byte
+	TTL   time.Duration
+}
+
+// Batcher is implemented by stores that can read or write many keys in one round trip
+type Batcher interface {
+	// GetMany returns the values of keys in order, with nil for the missing ones
+	GetMany(ctx context.Context, keys []string) ([][]byte, error)
+
+	// SetMany stores all entries
+	SetMany(ctx context.Context, entries []Entry) error
+}
+
+// Indexer is implemented by stores that keep sets of members that expire one by one, used to index
+// other keys so they can be found without scanning the keyspace
+type Indexer interface {
+	// AddMember adds member to the set at key for ttl, which must be positive. The set lives as long
+	// as its last member.
+	AddMember(ctx context.Context, key, member string, ttl time.Duration) error
+
+	// Members returns the live members of the set at key
+	Members(ctx context.Context, key string) ([]string, error)
+}
+
+// MemoryReporter is implemented by stores that can report backend memory usage
+type MemoryReporter interface {
+	MemoryInfo(ctx context.Context) (map[string]string, error)
+}
+
+// NewStore creates the cache store for the configured backend
+// Returns nil if the Redis backend is selected but Redis is unavailable (graceful degradation)
+func NewStore(backend string, redisClient *redis.RedisClient) Store {
+	switch backend {
+	case BackendMemory:
+		logrus.Info("Cache store: in-memory (single-node mode)")
+		return NewMemoryStore()
+	default:
+		if redisClient == nil {
+			return nil
+		}
+		logrus.Info("Cache store: Redis")
+		return NewRedisStore(redisClient)
+	}
+}
+
+// GetMany reads keys in one round trip when the store is a Batcher, or one by one otherwise.
+// The values are in the order of keys, with nil for the missing ones.
+func GetMany(ctx context.Context, store Store, keys []string) ([][]byte, error) {
+	if batcher, ok := store.(Batcher); ok {
+		return batcher.GetMany(ctx, keys)
+	}
+
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := store.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// SetMany writes entries in one round trip when the store is a Batcher, or one by one otherwise
+func SetMany(ctx context.Context, store Store, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if batcher, ok := store.(Batcher); ok {
+		return batcher.SetMany(ctx, entries)
+	}
+
+	for _, entry := range entries {
+		if err := store.Set(entry.Key, entry.Value, entry.TTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// globToRegexp converts a Redis-style glob pattern into an anchored regular expression
+func globToRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
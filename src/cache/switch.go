@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ResponseCacheSwitchKey holds the admin switch of the response cache while it is off
+const ResponseCacheSwitchKey = "api:response-cache:disabled"
+
+// ResponseCacheSwitch shares the admin on/off switch of the response cache through the cache store,
+// so every instance follows it. With the in-memory store it applies to this instance only.
+type ResponseCacheSwitch struct {
+	store Store
+}
+
+// NewResponseCacheSwitch creates the switch shared through store, which may be nil
+func NewResponseCacheSwitch(store Store) *ResponseCacheSwitch {
+	return &ResponseCacheSwitch{store: store}
+}
+
+// Start follows the switch flipped on other instances, reading it every interval until ctx is done.
+// While the store is unavailable the last state read is kept.
+func (s *ResponseCacheSwitch) Start(ctx context.Context, interval time.Duration) {
+	if s.store == nil {
+		return
+	}
+
+	s.refresh()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refresh()
+		}
+	}
+}
+
+func (s *ResponseCacheSwitch) refresh() {
+	if !IsStoreAvailable(s.store) {
+		return
+	}
+
+	// The key only exists while the cache is switched off
+	disabled, err := s.store.Get(ResponseCacheSwitchKey)
+	if err != nil {
+		logrus.Debugf("Failed to read the response cache switch: %v", err)
+		return
+	}
+	SetResponseCacheEnabled(disabled == nil)
+}
+
+// Set switches the response cache on or off for every instance; the others follow within their
+// sync interval
+func (s *ResponseCacheSwitch) Set(ctx context.Context, enabled bool) error {
+	if s.store != nil {
+		if !IsStoreAvailable(s.store) {
+			return ErrStoreUnavailable
+		}
+
+		var err error
+		if enabled {
+			err = s.store.DeleteKeys(ctx, ResponseCacheSwitchKey)
+		} else {
+			err = s.store.Set(ResponseCacheSwitchKey, []byte("1"), 0)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	SetResponseCacheEnabled(enabled)
+	return nil
+}
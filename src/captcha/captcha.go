@@ -0,0 +1,180 @@
+package captcha
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/src/cache"
+	"app/src/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// HeaderToken is the request header carrying the CAPTCHA token
+const HeaderToken = "X-Captcha-Token"
+
+// Verifier checks CAPTCHA tokens on incoming requests
+type Verifier struct {
+	provider Provider
+	minScore float64
+	bypass   bool
+}
+
+// New creates a verifier from configuration
+// Returns nil if CAPTCHA is disabled (every check then passes)
+func New(cfg config.CaptchaConfig) *Verifier {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Bypass {
+		logrus.Warn("CAPTCHA bypass mode enabled - tokens are not verified")
+		return &Verifier{bypass: true}
+	}
+
+	provider := NewRecaptcha(cfg.Secret)
+	if cfg.Provider == config.CaptchaProviderHCaptcha {
+		provider = NewHCaptcha(cfg.Secret)
+	}
+
+	return NewVerifier(provider, cfg.MinScore)
+}
+
+// NewVerifier creates a verifier for a provider; minScore applies to scored providers only
+func NewVerifier(provider Provider, minScore float64) *Verifier {
+	return &Verifier{
+		provider: provider,
+		minScore: minScore,
+	}
+}
+
+// Check verifies the request's CAPTCHA token; the action is only compared for reCAPTCHA v3
+func (v *Verifier) Check(c *fiber.Ctx, action string) error {
+	if v == nil || v.bypass {
+		return nil
+	}
+
+	token := requestToken(c)
+	if token == "" {
+		return fiber.NewError(fiber.StatusForbidden, "CAPTCHA verification required")
+	}
+
+	result, err := v.provider.Verify(c.UserContext(), token, c.IP())
+	if err != nil {
+		// Fail closed: protected endpoints are the ones under attack
+		logrus.Errorf("CAPTCHA verification failed: %v", err)
+		return fiber.NewError(fiber.StatusServiceUnavailable, "CAPTCHA verification unavailable")
+	}
+
+	if !result.Success {
+		return fiber.NewError(fiber.StatusForbidden, "CAPTCHA verification failed")
+	}
+
+	if v.provider.Scored() {
+		if result.Score < v.minScore {
+			return fiber.NewError(fiber.StatusForbidden, "CAPTCHA verification failed")
+		}
+		if action != "" && result.Action != "" && result.Action != action {
+			return fiber.NewError(fiber.StatusForbidden, "CAPTCHA verification failed")
+		}
+	}
+
+	return nil
+}
+
+// Hook returns a check bound to an action, for use as a middleware.CaptchaHook
+func (v *Verifier) Hook(action string) func(c *fiber.Ctx) error {
+	if v == nil {
+		return nil
+	}
+	return func(c *fiber.Ctx) error {
+		return v.Check(c, action)
+	}
+}
+
+// Require rejects requests without a valid CAPTCHA token for the action
+func (v *Verifier) Require(action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := v.Check(c, action); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// RequireAfterFailures demands a CAPTCHA once a target (e.g. a login email) has failed threshold
+// times within window. Failures are 401 responses from the handler; a success clears them.
+func (v *Verifier) RequireAfterFailures(
+	store cache.Store, action string, threshold int, window time.Duration, target func(c *fiber.Ctx) string,
+) fiber.Handler {
+	if v == nil || store == nil || threshold <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		subject := target(c)
+		if subject == "" || !cache.IsStoreAvailable(store) {
+			return c.Next()
+		}
+
+		key := failureKey(action, subject)
+
+		if failures := loadFailures(store, key); failures >= threshold {
+			if err := v.Check(c, action); err != nil {
+				return err
+			}
+		}
+
+		err := c.Next()
+
+		var fiberErr *fiber.Error
+		switch {
+		case errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusUnauthorized:
+			if setErr := store.Set(key, []byte(strconv.Itoa(loadFailures(store, key)+1)), window); setErr != nil {
+				logrus.Warnf("Failed to record %s failure: %v", action, setErr)
+			}
+		case err == nil && c.Response().StatusCode() < fiber.StatusBadRequest:
+			_ = store.Delete(key)
+		}
+
+		return err
+	}
+}
+
+func loadFailures(store cache.Store, key string) int {
+	data, err := store.Get(key)
+	if err != nil || data == nil {
+		return 0
+	}
+	failures, _ := strconv.Atoi(string(data))
+	return failures
+}
+
+// failureKey hashes the target so emails don't appear in the cache keyspace
+func failureKey(action, target string) string {
+	sum := sha256.Sum256([]byte(target))
+	return fmt.Sprintf("%s%s:%s", cache.CaptchaKeyPrefix, action, hex.EncodeToString(sum[:]))
+}
+
+// requestToken reads the token from the header, falling back to a "captcha_token" body field
+func requestToken(c *fiber.Ctx) string {
+	if token := strings.TrimSpace(c.Get(HeaderToken)); token != "" {
+		return token
+	}
+
+	body := struct {
+		Token string `json:"captcha_token" form:"captcha_token"`
+	}{}
+	if err := c.BodyParser(&body); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(body.Token)
+}
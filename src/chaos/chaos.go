@@ -0,0 +1,110 @@
+// Package chaos injects latency, errors and dropped connections into the HTTP layer, Redis and the
+// database, so degraded paths such as the cache fallback and the Redis circuit breaker can be
+// exercised. Injectors are only installed outside production, when CHAOS_ENABLED is set.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"app/src/config"
+	"app/src/metrics"
+)
+
+var (
+	// ErrInjected is returned by a call failed on purpose
+	ErrInjected = errors.New("chaos: injected error")
+
+	// ErrDropped is returned by a call whose connection was dropped on purpose
+	ErrDropped = errors.New("chaos: connection dropped")
+)
+
+// Outcome is what happens to a call after any injected latency
+type Outcome int
+
+const (
+	// Pass lets the call through
+	Pass Outcome = iota
+	// Fail makes the call return an error
+	Fail
+	// Drop makes the call behave as if its connection was lost
+	Drop
+)
+
+var (
+	mu     sync.RWMutex
+	faults = map[string]config.ChaosFault{}
+)
+
+// Load replaces the active faults with the configured ones
+func Load(cfg config.ChaosConfig) {
+	mu.Lock()
+	faults = maps.Clone(cfg.Faults)
+	mu.Unlock()
+}
+
+// Set changes the faults injected into a dependency at runtime, e.g. from an integration test
+func Set(dependency string, fault config.ChaosFault) {
+	mu.Lock()
+	faults[dependency] = fault
+	mu.Unlock()
+}
+
+// Reset stops injecting faults into every dependency
+func Reset() {
+	mu.Lock()
+	clear(faults)
+	mu.Unlock()
+}
+
+// Inject applies the dependency's faults to a call: it may sleep for the injected latency, cut short
+// if ctx is done, then decides whether the call passes, fails or is dropped
+func Inject(ctx context.Context, dependency string) Outcome {
+	mu.RLock()
+	fault, ok := faults[dependency]
+	mu.RUnlock()
+	if !ok {
+		return Pass
+	}
+
+	if fault.Latency > 0 && roll(fault.LatencyPercent) {
+		metrics.ChaosInjected(dependency, "latency")
+		timer := time.NewTimer(fault.Latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	switch {
+	case roll(fault.DropPercent):
+		metrics.ChaosInjected(dependency, "drop")
+		return Drop
+	case roll(fault.ErrorPercent):
+		metrics.ChaosInjected(dependency, "error")
+		return Fail
+	default:
+		return Pass
+	}
+}
+
+// Err returns the error a call with this outcome should fail with, or nil
+func (o Outcome) Err() error {
+	switch o {
+	case Fail:
+		return ErrInjected
+	case Drop:
+		return ErrDropped
+	default:
+		return nil
+	}
+}
+
+func roll(percent float64) bool {
+	return percent > 0 && rand.Float64()*100 < percent
+}
@@ -0,0 +1,41 @@
+package chaos
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	"app/src/config"
+
+	"gorm.io/gorm"
+)
+
+// GormPlugin injects the db faults before every statement. Dropped connections fail with
+// driver.ErrBadConn, as a lost Postgres connection would.
+type GormPlugin struct{}
+
+var _ gorm.Plugin = GormPlugin{}
+
+func (GormPlugin) Name() string {
+	return "chaos"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("chaos:create", inject),
+		callbacks.Query().Before("gorm:query").Register("chaos:query", inject),
+		callbacks.Update().Before("gorm:update").Register("chaos:update", inject),
+		callbacks.Delete().Before("gorm:delete").Register("chaos:delete", inject),
+		callbacks.Row().Before("gorm:row").Register("chaos:row", inject),
+		callbacks.Raw().Before("gorm:raw").Register("chaos:raw", inject),
+	)
+}
+
+func inject(db *gorm.DB) {
+	switch Inject(db.Statement.Context, config.ChaosDB) {
+	case Fail:
+		_ = db.AddError(ErrInjected)
+	case Drop:
+		_ = db.AddError(driver.ErrBadConn)
+	}
+}
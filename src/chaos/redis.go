@@ -0,0 +1,42 @@
+package chaos
+
+import (
+	"context"
+
+	"app/src/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHook injects the redis faults into every command. Failures surface through the circuit
+// breaker like real ones, so enough of them open it.
+type RedisHook struct{}
+
+var _ redis.Hook = RedisHook{}
+
+// DialHook leaves dialing alone; faults are injected per command
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := Inject(ctx, config.ChaosRedis).Err(); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := Inject(ctx, config.ChaosRedis).Err(); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
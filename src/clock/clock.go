@@ -0,0 +1,60 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time to services, so tests can control token expiry and TTL logic
+type Clock interface {
+	// Now returns the current time in UTC
+	Now() time.Time
+}
+
+type system struct{}
+
+// System is the wall clock
+var System Clock = system{}
+
+func (system) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// OrSystem returns c, or the wall clock when c is nil
+func OrSystem(c Clock) Clock {
+	if c == nil {
+		return System
+	}
+	return c
+}
+
+// Mock is a clock that only moves when told to
+type Mock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewMock creates a mock clock stopped at now
+func NewMock(now time.Time) *Mock {
+	return &Mock{now: now.UTC()}
+}
+
+func (m *Mock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Set moves the clock to now
+func (m *Mock) Set(now time.Time) {
+	m.mu.Lock()
+	m.now = now.UTC()
+	m.mu.Unlock()
+}
+
+// Advance moves the clock forward by d
+func (m *Mock) Advance(d time.Duration) {
+	m.mu.Lock()
+	m.now = m.now.Add(d)
+	m.mu.Unlock()
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// AnalyticsConfig controls counting unique visitors in Redis HyperLogLogs
+type AnalyticsConfig struct {
+	Enabled bool
+	// FlushInterval is how often the visits noted by an instance are added to the sketches in Redis
+	FlushInterval time.Duration
+	// PersistInterval is how often the sketches of the current day and month are saved to the database
+	PersistInterval time.Duration
+}
+
+// Analytics is the loaded analytics configuration
+var Analytics AnalyticsConfig
+
+// LoadAnalyticsConfig loads unique visitor tracking settings from environment
+func LoadAnalyticsConfig() {
+	Analytics = AnalyticsConfig{
+		Enabled:         viper.GetBool("ANALYTICS_ENABLED"),
+		FlushInterval:   time.Second,
+		PersistInterval: 5 * time.Minute,
+	}
+
+	if interval := viper.GetInt("ANALYTICS_FLUSH_INTERVAL"); interval > 0 {
+		Analytics.FlushInterval = time.Duration(interval) * time.Second
+	}
+	if interval := viper.GetInt("ANALYTICS_PERSIST_INTERVAL"); interval > 0 {
+		Analytics.PersistInterval = time.Duration(interval) * time.Second
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Policies applied when a login would exceed MaxSessionsPerUser
+const (
+	SessionLimitEvictOldest = "evict_oldest"
+	SessionLimitReject      = "reject"
+)
+
+// Access token modes
+const (
+	AccessTokenJWT    = "jwt"
+	AccessTokenOpaque = "opaque"
+)
+
+// AccessTokenMode is AccessTokenJWT or AccessTokenOpaque
+var AccessTokenMode string
+
+// AuthStatelessGroups lists route groups that authorize purely from access token claims
+var AuthStatelessGroups []string
+
+// MaxSessionsPerUser caps concurrent sessions (active refresh tokens) per user; 0 is unlimited
+var MaxSessionsPerUser int
+
+// SessionLimitPolicy is SessionLimitEvictOldest or SessionLimitReject
+var SessionLimitPolicy string
+
+// RefreshBindingCookie carries the secret a refresh token is bound to
+const RefreshBindingCookie = "refresh_binding"
+
+// RefreshBinding requires refresh calls to come with the cookie set when the refresh token was issued
+var RefreshBinding bool
+
+// RefreshBindingCrossSite sends the binding cookie with SameSite=None and Secure, for browser
+// clients served from another site than the API
+var RefreshBindingCrossSite bool
+
+// RefreshBindUserAgent also binds refresh tokens to the User-Agent they were issued to
+var RefreshBindUserAgent bool
+
+// LoadAuthConfig loads auth middleware configuration from environment
+// AUTH_STATELESS_GROUPS is a comma-separated list of route groups (e.g. "users")
+func LoadAuthConfig() {
+	AuthStatelessGroups = splitList(viper.GetString("AUTH_STATELESS_GROUPS"))
+
+	AccessTokenMode = AccessTokenJWT
+	if viper.GetString("ACCESS_TOKEN_MODE") == AccessTokenOpaque {
+		AccessTokenMode = AccessTokenOpaque
+	}
+
+	// One session per user matches the behaviour before the limit was configurable
+	MaxSessionsPerUser = 1
+	if viper.IsSet("MAX_SESSIONS_PER_USER") {
+		MaxSessionsPerUser = viper.GetInt("MAX_SESSIONS_PER_USER")
+	}
+	if MaxSessionsPerUser < 0 {
+		MaxSessionsPerUser = 0
+	}
+
+	SessionLimitPolicy = SessionLimitEvictOldest
+	if viper.GetString("SESSION_LIMIT_POLICY") == SessionLimitReject {
+		SessionLimitPolicy = SessionLimitReject
+	}
+
+	// Clients that cannot keep cookies, such as some native apps, need the binding turned off
+	RefreshBinding = true
+	if viper.IsSet("REFRESH_TOKEN_BINDING") {
+		RefreshBinding = viper.GetBool("REFRESH_TOKEN_BINDING")
+	}
+	// SameSite=Lax cookies are not sent on cross-site fetches, which would make every refresh of a
+	// frontend on another site look like a replay
+	RefreshBindingCrossSite = viper.GetBool("REFRESH_TOKEN_BINDING_CROSS_SITE")
+	RefreshBindUserAgent = viper.GetBool("REFRESH_TOKEN_BIND_USER_AGENT")
+}
+
+// IsStatelessGroup reports whether the route group should use stateless (claims-only) auth
+func IsStatelessGroup(group string) bool {
+	for _, g := range AuthStatelessGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList parses a comma-separated environment value into trimmed, non-empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
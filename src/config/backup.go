@@ -0,0 +1,16 @@
+package config
+
+import "github.com/spf13/viper"
+
+// BackupPassphrase encrypts the archives of the backup command and decrypts them on restore; empty
+// writes unencrypted archives
+var BackupPassphrase string
+
+// BackupEnvironment names where an archive was made, from APP_ENV
+var BackupEnvironment string
+
+// LoadBackupConfig loads the backup and restore commands configuration from environment
+func LoadBackupConfig() {
+	BackupPassphrase = viper.GetString("BACKUP_PASSPHRASE")
+	BackupEnvironment = viper.GetString("APP_ENV")
+}
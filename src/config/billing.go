@@ -0,0 +1,57 @@
+package config
+
+import (
+	"app/src/utils"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// StripeAPIURL is Stripe's API base URL
+const StripeAPIURL = "https://api.stripe.com"
+
+// BillingConfig holds Stripe settings; customers are only created with a SecretKey and webhooks
+// only accepted with a WebhookSecret
+type BillingConfig struct {
+	SecretKey     string
+	WebhookSecret string
+	APIURL        string
+	// PricePlans maps a Stripe price ID to the plan its subscribers are moved to
+	PricePlans map[string]string
+	// WebhookTolerance is how old a signed webhook may be before it is rejected as a replay
+	WebhookTolerance time.Duration
+}
+
+// Billing is the loaded billing configuration
+var Billing BillingConfig
+
+// LoadBillingConfig loads Stripe billing configuration from environment
+func LoadBillingConfig() {
+	Billing = BillingConfig{
+		SecretKey:        viper.GetString("STRIPE_SECRET_KEY"),
+		WebhookSecret:    viper.GetString("STRIPE_WEBHOOK_SECRET"),
+		APIURL:           StripeAPIURL,
+		PricePlans:       map[string]string{},
+		WebhookTolerance: 5 * time.Minute,
+	}
+
+	if apiURL := viper.GetString("STRIPE_API_URL"); apiURL != "" {
+		Billing.APIURL = strings.TrimSuffix(apiURL, "/")
+	}
+	if tolerance := viper.GetInt("STRIPE_WEBHOOK_TOLERANCE"); tolerance > 0 {
+		Billing.WebhookTolerance = time.Duration(tolerance) * time.Second
+	}
+
+	// STRIPE_PRICE_PLANS lists price:plan pairs, e.g. "price_1Pro:pro,price_1Team:team"
+	for _, entry := range splitList(viper.GetString("STRIPE_PRICE_PLANS")) {
+		price, plan, ok := strings.Cut(entry, ":")
+		price, plan = strings.TrimSpace(price), strings.ToLower(strings.TrimSpace(plan))
+		if !ok || price == "" || plan == "" {
+			utils.Log.Warnf("Invalid STRIPE_PRICE_PLANS entry %q, expected price:plan", entry)
+			continue
+		}
+
+		Billing.PricePlans[price] = plan
+	}
+}
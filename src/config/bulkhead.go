@@ -0,0 +1,74 @@
+package config
+
+import (
+	"app/src/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// BulkheadConfig caps concurrent in-flight requests per route group
+type BulkheadConfig struct {
+	Enabled bool
+	// Limits maps a route group name to the requests it may serve at once; unlisted groups are unlimited
+	Limits map[string]int
+	// Wait is how long a request may queue for a free slot before it is rejected (0 rejects immediately)
+	Wait time.Duration
+	// RetryAfter is sent with the 503 returned when a group is saturated
+	RetryAfter time.Duration
+}
+
+// Route groups guarded by a bulkhead
+const (
+	BulkheadUsers    = "users"
+	BulkheadActivity = "activity"
+	BulkheadSearch   = "search"
+)
+
+// Bulkhead is the loaded bulkhead configuration
+var Bulkhead BulkheadConfig
+
+// LoadBulkheadConfig loads per-group concurrency limits from environment
+func LoadBulkheadConfig() {
+	Bulkhead = BulkheadConfig{
+		Enabled: true,
+		Limits: map[string]int{
+			BulkheadUsers:    50,
+			BulkheadActivity: 10,
+			BulkheadSearch:   10,
+		},
+		RetryAfter: 5 * time.Second,
+	}
+
+	if viper.IsSet("BULKHEAD_ENABLED") {
+		Bulkhead.Enabled = viper.GetBool("BULKHEAD_ENABLED")
+	}
+	if wait := viper.GetInt("BULKHEAD_WAIT"); wait > 0 {
+		Bulkhead.Wait = time.Duration(wait) * time.Millisecond
+	}
+	if retryAfter := viper.GetInt("BULKHEAD_RETRY_AFTER"); retryAfter > 0 {
+		Bulkhead.RetryAfter = time.Duration(retryAfter) * time.Second
+	}
+
+	// BULKHEAD_LIMITS overrides or adds groups, e.g. "activity:5,exports:2"; a limit of 0 removes the group's cap
+	for _, entry := range strings.Split(viper.GetString("BULKHEAD_LIMITS"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, ":")
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || limit < 0 {
+			utils.Log.Warnf("Invalid BULKHEAD_LIMITS entry %q, expected name:max", entry)
+			continue
+		}
+
+		if limit == 0 {
+			delete(Bulkhead.Limits, strings.TrimSpace(name))
+			continue
+		}
+		Bulkhead.Limits[strings.TrimSpace(name)] = limit
+	}
+}
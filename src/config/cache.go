@@ -0,0 +1,177 @@
+package config
+
+import (
+	"app/src/utils"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// NegativeCacheTTL is the TTL in seconds for not-found lookup markers
+var NegativeCacheTTL int
+
+// RequestDedupEnabled coalesces identical concurrent GET requests into one execution
+var RequestDedupEnabled bool
+
+// CacheBackend selects the cache store backend: "redis" (default) or "memory"
+var CacheBackend string
+
+// DefaultCacheSkipPaths are the routes never cached: sign-in flows, the admin and partner APIs,
+// health checks and billing webhooks
+var DefaultCacheSkipPaths = []string{
+	"/v1/auth",
+	"/v1/admin",
+	"/v1/partner",
+	"/v1/billing",
+	"/v1/health-check",
+	"/v1/readyz",
+	"/metrics",
+}
+
+// ResponseCacheConfig holds API response cache configuration
+type ResponseCacheConfig struct {
+	DefaultTTL  time.Duration            `mapstructure:"default_ttl" env:"RESPONSE_CACHE_TTL" envDefault:"30m"`
+	RouteTTLs   map[string]time.Duration `mapstructure:"route_ttls" env:"RESPONSE_CACHE_ROUTE_TTLS"`
+	BypassRoles []string                 `mapstructure:"bypass_roles" env:"RESPONSE_CACHE_BYPASS_ROLES" envDefault:"admin"`
+	// Requests matching a skip rule bypass the cache; admins can add rules at runtime
+	SkipPaths    []string `mapstructure:"skip_paths" env:"RESPONSE_CACHE_SKIP_PATHS"`
+	SkipPatterns []string `mapstructure:"skip_patterns" env:"RESPONSE_CACHE_SKIP_PATTERNS"`
+	SkipMethods  []string `mapstructure:"skip_methods" env:"RESPONSE_CACHE_SKIP_METHODS"`
+	SkipHeaders  []string `mapstructure:"skip_headers" env:"RESPONSE_CACHE_SKIP_HEADERS"`
+	// DefaultPolicy applies to routes that declare no cache policy: public, private or no-store
+	DefaultPolicy string `mapstructure:"default_policy" env:"RESPONSE_CACHE_DEFAULT_POLICY" envDefault:"public"`
+	// SyncInterval is how often an instance reads the admin on/off switch other instances may have flipped
+	SyncInterval time.Duration `mapstructure:"sync_interval" env:"RESPONSE_CACHE_SYNC_INTERVAL" envDefault:"5s"`
+}
+
+// LoadResponseCacheConfig loads response cache configuration from environment variables
+// RESPONSE_CACHE_ROUTE_TTLS format: "/v1/users=5m,/v1/health-check=10s"
+func LoadResponseCacheConfig() *ResponseCacheConfig {
+	config := ResponseCacheConfig{
+		RouteTTLs: make(map[string]time.Duration),
+	}
+
+	config.DefaultTTL = viper.GetDuration("RESPONSE_CACHE_TTL")
+	if config.DefaultTTL <= 0 {
+		config.DefaultTTL = 30 * time.Minute
+	}
+
+	for _, entry := range splitList(viper.GetString("RESPONSE_CACHE_ROUTE_TTLS")) {
+		route, rawTTL, found := strings.Cut(entry, "=")
+		if !found {
+			utils.Log.Warnf("Ignoring invalid RESPONSE_CACHE_ROUTE_TTLS entry '%s' (expected path=duration)", entry)
+			continue
+		}
+
+		ttl, err := time.ParseDuration(strings.TrimSpace(rawTTL))
+		if err != nil || ttl <= 0 {
+			utils.Log.Warnf("Ignoring invalid TTL for route '%s': %s", route, rawTTL)
+			continue
+		}
+
+		config.RouteTTLs[strings.TrimSpace(route)] = ttl
+	}
+
+	config.SyncInterval = 5 * time.Second
+	if interval := viper.GetInt("RESPONSE_CACHE_SYNC_INTERVAL"); interval > 0 {
+		config.SyncInterval = time.Duration(interval) * time.Second
+	}
+
+	config.BypassRoles = []string{"admin"}
+	if roles := viper.GetString("RESPONSE_CACHE_BYPASS_ROLES"); roles != "" {
+		config.BypassRoles = splitList(roles)
+	}
+
+	// RESPONSE_CACHE_SKIP_PATHS replaces the defaults, so it must list them to keep them
+	config.SkipPaths = DefaultCacheSkipPaths
+	if paths := viper.GetString("RESPONSE_CACHE_SKIP_PATHS"); paths != "" {
+		config.SkipPaths = splitList(paths)
+	}
+	// Patterns may contain commas, so they are separated by spaces
+	config.SkipPatterns = strings.Fields(viper.GetString("RESPONSE_CACHE_SKIP_PATTERNS"))
+	config.SkipMethods = splitList(viper.GetString("RESPONSE_CACHE_SKIP_METHODS"))
+	config.SkipHeaders = splitList(viper.GetString("RESPONSE_CACHE_SKIP_HEADERS"))
+
+	config.DefaultPolicy = "public"
+	switch policy := strings.ToLower(strings.TrimSpace(viper.GetString("RESPONSE_CACHE_DEFAULT_POLICY"))); policy {
+	case "":
+	case "public", "private", "no-store":
+		config.DefaultPolicy = policy
+	default:
+		utils.Log.Warnf("Unknown RESPONSE_CACHE_DEFAULT_POLICY '%s', using default: public", policy)
+	}
+
+	return &config
+}
+
+// TTLFor returns the cache TTL for a path, using the longest route override matching it on whole
+// segments: a TTL for /v1/users applies to /v1/users/42 but not to /v1/users-export
+func (c *ResponseCacheConfig) TTLFor(path string) time.Duration {
+	ttl := c.DefaultTTL
+	longest := -1
+
+	for route, routeTTL := range c.RouteTTLs {
+		prefix := strings.TrimSuffix(route, "/")
+		matches := path == prefix || strings.HasPrefix(path, prefix+"/")
+		if matches && len(route) > longest {
+			ttl = routeTTL
+			longest = len(route)
+		}
+	}
+
+	return ttl
+}
+
+// CanBypass reports whether the role may bypass the response cache with Cache-Control: no-cache
+func (c *ResponseCacheConfig) CanBypass(role string) bool {
+	for _, r := range c.BypassRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadNegativeCacheConfig loads negative cache TTL configuration from environment
+// Default: 60 seconds, Range: 5-600 seconds
+func LoadNegativeCacheConfig() {
+	defaultTTL := 60
+	NegativeCacheTTL = defaultTTL
+
+	negativeTTL := viper.GetInt("NEGATIVE_CACHE_TTL")
+	if negativeTTL == 0 {
+		return
+	}
+
+	// Keep markers short-lived so newly created records become visible quickly
+	if negativeTTL < 5 || negativeTTL > 600 {
+		utils.Log.Warnf("NEGATIVE_CACHE_TTL value %d seconds is outside allowed range (5-600). Using default: %d seconds", negativeTTL, defaultTTL)
+		return
+	}
+
+	NegativeCacheTTL = negativeTTL
+}
+
+// LoadRequestDedupConfig loads request deduplication configuration from environment
+func LoadRequestDedupConfig() {
+	RequestDedupEnabled = true
+	if viper.IsSet("REQUEST_DEDUP_ENABLED") {
+		RequestDedupEnabled = viper.GetBool("REQUEST_DEDUP_ENABLED")
+	}
+}
+
+// LoadCacheBackendConfig loads the cache store backend from environment
+// "memory" runs sessions, response cache and rate limiting in-process for single-node deployments
+func LoadCacheBackendConfig() {
+	CacheBackend = strings.ToLower(strings.TrimSpace(viper.GetString("CACHE_BACKEND")))
+
+	switch CacheBackend {
+	case "":
+		CacheBackend = "redis"
+	case "redis", "memory":
+	default:
+		utils.Log.Warnf("Unknown CACHE_BACKEND '%s', using default: redis", CacheBackend)
+		CacheBackend = "redis"
+	}
+}
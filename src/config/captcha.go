@@ -0,0 +1,62 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// CAPTCHA providers
+const (
+	CaptchaProviderRecaptcha = "recaptcha"
+	CaptchaProviderHCaptcha  = "hcaptcha"
+)
+
+// CaptchaConfig holds CAPTCHA verification configuration
+type CaptchaConfig struct {
+	Enabled  bool
+	Provider string
+	Secret   string
+	// MinScore is the lowest accepted reCAPTCHA v3 score (0.0 bot - 1.0 human)
+	MinScore float64
+	// Bypass accepts every request without calling the provider; ignored in production
+	Bypass bool
+	// Register and ForgotPassword require a CAPTCHA on every request to those endpoints
+	Register       bool
+	ForgotPassword bool
+	// LoginFailures is the number of failed logins for an email after which login needs a CAPTCHA (0 disables)
+	LoginFailures int
+}
+
+// Captcha is the loaded CAPTCHA configuration
+var Captcha CaptchaConfig
+
+// LoadCaptchaConfig loads CAPTCHA configuration from environment
+func LoadCaptchaConfig() {
+	Captcha = CaptchaConfig{
+		Enabled:        viper.GetBool("CAPTCHA_ENABLED"),
+		Provider:       strings.ToLower(strings.TrimSpace(viper.GetString("CAPTCHA_PROVIDER"))),
+		Secret:         viper.GetString("CAPTCHA_SECRET"),
+		MinScore:       0.5,
+		Bypass:         viper.GetBool("CAPTCHA_BYPASS") && !IsProd,
+		Register:       true,
+		ForgotPassword: true,
+		LoginFailures:  3,
+	}
+
+	if Captcha.Provider != CaptchaProviderHCaptcha {
+		Captcha.Provider = CaptchaProviderRecaptcha
+	}
+	if viper.IsSet("CAPTCHA_MIN_SCORE") {
+		Captcha.MinScore = viper.GetFloat64("CAPTCHA_MIN_SCORE")
+	}
+	if viper.IsSet("CAPTCHA_REGISTER") {
+		Captcha.Register = viper.GetBool("CAPTCHA_REGISTER")
+	}
+	if viper.IsSet("CAPTCHA_FORGOT_PASSWORD") {
+		Captcha.ForgotPassword = viper.GetBool("CAPTCHA_FORGOT_PASSWORD")
+	}
+	if viper.IsSet("CAPTCHA_LOGIN_FAILURES") {
+		Captcha.LoginFailures = viper.GetInt("CAPTCHA_LOGIN_FAILURES")
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"app/src/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Dependencies faults can be injected into
+const (
+	ChaosHTTP  = "http"
+	ChaosRedis = "redis"
+	ChaosDB    = "db"
+)
+
+// ChaosFault is the chance, in percent (0-100), of each fault on a call to a dependency
+type ChaosFault struct {
+	// Latency is added to LatencyPercent of calls, before any error or drop
+	Latency        time.Duration
+	LatencyPercent float64
+	ErrorPercent   float64
+	DropPercent    float64
+}
+
+// ChaosConfig controls fault injection for resilience testing
+type ChaosConfig struct {
+	// Enabled installs the fault injectors; it is always false in production
+	Enabled bool
+	// Faults maps a dependency to the faults injected into it
+	Faults map[string]ChaosFault
+}
+
+// Chaos is the loaded fault injection configuration
+var Chaos ChaosConfig
+
+// LoadChaosConfig loads fault injection configuration from environment
+func LoadChaosConfig() {
+	Chaos = ChaosConfig{
+		Enabled: viper.GetBool("CHAOS_ENABLED") && !IsProd,
+		Faults:  map[string]ChaosFault{},
+	}
+
+	latency := 200 * time.Millisecond
+	if ms := viper.GetInt("CHAOS_LATENCY"); ms > 0 {
+		latency = time.Duration(ms) * time.Millisecond
+	}
+
+	// CHAOS_<DEPENDENCY> lists fault:percent entries, e.g. CHAOS_REDIS="error:20,drop:5,latency:50"
+	for _, dependency := range []string{ChaosHTTP, ChaosRedis, ChaosDB} {
+		name := "CHAOS_" + strings.ToUpper(dependency)
+		spec := viper.GetString(name)
+		if spec == "" {
+			continue
+		}
+
+		fault := ChaosFault{Latency: latency}
+		for _, entry := range strings.Split(spec, ",") {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+
+			kind, value, ok := strings.Cut(entry, ":")
+			percent, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if !ok || err != nil || percent < 0 || percent > 100 {
+				utils.Log.Warnf("Invalid %s entry %q, expected fault:percent", name, entry)
+				continue
+			}
+
+			switch strings.TrimSpace(kind) {
+			case "latency":
+				fault.LatencyPercent = percent
+			case "error":
+				fault.ErrorPercent = percent
+			case "drop":
+				fault.DropPercent = percent
+			default:
+				utils.Log.Warnf("Invalid %s entry %q, faults are latency, error and drop", name, entry)
+			}
+		}
+		Chaos.Faults[dependency] = fault
+	}
+}
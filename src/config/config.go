@@ -3,6 +3,7 @@ package config
 import (
 	"app/src/utils"
 	"strconv"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -21,6 +22,9 @@ var (
 	JWTRefreshExp       int
 	JWTResetPasswordExp int
 	JWTVerifyEmailExp   int
+	JWTIssuer           string // set on issued tokens and required on verified ones, like JWTAudience
+	JWTAudience         string
+	JWTLeeway           time.Duration // clock skew tolerated on token expiry
 	SMTPHost            string
 	SMTPPort            int
 	SMTPUsername        string
@@ -35,11 +39,17 @@ var (
 	RedisPassword       string
 	RedisDB             int
 	SessionCacheTTL     int
+	SessionCacheWarmup  int
+	// SessionCompressMin is the serialized size from which cached sessions are gzipped (0 disables)
+	SessionCompressMin int
 )
 
 func init() {
 	loadConfig()
 
+	// Apply log redaction before anything else logs configuration values
+	LoadLogConfig()
+
 	// server configuration
 	IsProd = viper.GetString("APP_ENV") == "prod"
 	AppHost = viper.GetString("APP_HOST")
@@ -58,6 +68,19 @@ func init() {
 	JWTRefreshExp = viper.GetInt("JWT_REFRESH_EXP_DAYS")
 	JWTResetPasswordExp = viper.GetInt("JWT_RESET_PASSWORD_EXP_MINUTES")
 	JWTVerifyEmailExp = viper.GetInt("JWT_VERIFY_EMAIL_EXP_MINUTES")
+	JWTIssuer = viper.GetString("JWT_ISSUER")
+	if JWTIssuer == "" {
+		JWTIssuer = "go-fiber-boilerplate"
+	}
+	JWTAudience = viper.GetString("JWT_AUDIENCE")
+	if JWTAudience == "" {
+		JWTAudience = JWTIssuer
+	}
+	JWTLeeway = 30 * time.Second
+	if viper.IsSet("JWT_LEEWAY_SECONDS") {
+		JWTLeeway = time.Duration(max(viper.GetInt("JWT_LEEWAY_SECONDS"), 0)) * time.Second
+	}
+	utils.ConfigureTokenClaims(JWTIssuer, JWTAudience, JWTLeeway)
 
 	// SMTP configuration
 	SMTPHost = viper.GetString("SMTP_HOST")
@@ -82,8 +105,97 @@ func init() {
 		utils.Log.Fatal(err)
 	}
 
+	// Fail fast on invalid ACL/TLS settings instead of silently running without Redis
+	if RedisEnabled {
+		if _, err := LoadRedisConfig(); err != nil {
+			utils.Log.Fatal(err)
+		}
+	}
+
+	// Load HTTP server timeouts, limits and listeners
+	LoadServerConfig()
+	LoadListenerConfig()
+	LoadInternalConfig()
+
+	// Load the timezone dates are rendered in
+	LoadTimeConfig()
+
+	// Load the format of new IDs
+	LoadIDConfig()
+
+	// Load deadlines of calls to external dependencies
+	LoadDeadlineConfig()
+
+	// Load startup dependency wait configuration
+	LoadStartupConfig()
+	LoadSchemaConfig()
+	LoadReadOnlyConfig()
+
+	// Load database pool configuration
+	LoadDatabasePoolConfig()
+	LoadDatabaseRetryConfig()
+
 	// Load session cache configuration
 	LoadSessionCacheConfig()
+
+	// Load cache store configuration
+	LoadCacheBackendConfig()
+	LoadNegativeCacheConfig()
+	LoadRequestDedupConfig()
+
+	// Load auth middleware configuration
+	LoadAuthConfig()
+	LoadThrottleConfig()
+	LoadLoginDelayConfig()
+	LoadSessionActivityConfig()
+	LoadBulkheadConfig()
+	LoadTagRateLimitConfig()
+	LoadQuotaConfig()
+	LoadBillingConfig()
+	LoadMeteringConfig()
+	LoadAnalyticsConfig()
+	LoadEmailDomainConfig()
+	LoadEmailSendingConfig()
+	LoadSMSConfig()
+	LoadPushConfig()
+	LoadPresenceConfig()
+	LoadUsernameConfig()
+	LoadCaptchaConfig()
+	LoadOAuthConfig()
+	LoadAppleConfig()
+	LoadOAuthProviderConfig()
+	LoadRiskConfig()
+
+	// Load background job configuration
+	LoadJobConfig()
+	LoadRetentionConfig()
+
+	// Load Prometheus metrics configuration
+	LoadMetricsConfig()
+
+	// Load static file serving configuration
+	LoadStaticConfig()
+
+	// Load URL signing keys
+	LoadSignedURLConfig()
+	LoadRequestSigningConfig()
+
+	// Load column encryption keys
+	LoadEncryptionConfig()
+
+	// Load the backup and restore commands configuration
+	LoadBackupConfig()
+
+	// Load the SIEM forwarding configuration
+	LoadSIEMConfig()
+
+	// Load debug body capture configuration
+	LoadDebugCaptureConfig()
+	LoadTrafficMirrorConfig()
+	LoadChaosConfig()
+
+	// Load outbound HTTP client defaults
+	LoadHTTPClientConfig()
 }
 
 func loadConfig() {
@@ -111,6 +223,14 @@ func LoadSessionCacheConfig() {
 	defaultTTL := 30
 	SessionCacheTTL = defaultTTL
 
+	// Sessions cached at startup for the most recently active users (0 disables)
+	SessionCacheWarmup = max(viper.GetInt("SESSION_CACHE_WARMUP"), 0)
+
+	SessionCompressMin = 1024
+	if viper.IsSet("SESSION_CACHE_COMPRESS_MIN_BYTES") {
+		SessionCompressMin = max(viper.GetInt("SESSION_CACHE_COMPRESS_MIN_BYTES"), 0)
+	}
+
 	// Read from environment
 	sessionTTLStr := viper.GetString("SESSION_CACHE_TTL")
 	if sessionTTLStr == "" {
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"app/src/utils"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DatabasePoolConfig holds sql.DB connection pool and startup retry settings
+type DatabasePoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime int // minutes
+	ConnMaxIdleTime int // minutes
+	ConnectRetries  int
+	ConnectBackoff  int // seconds, doubled after each failed attempt
+}
+
+// DatabasePool is the loaded connection pool configuration
+var DatabasePool DatabasePoolConfig
+
+// MaxDBConnectBackoff caps the delay between startup connection attempts, in seconds
+const MaxDBConnectBackoff = 30
+
+// DatabaseRetryConfig bounds the retries of database operations after transient errors
+type DatabaseRetryConfig struct {
+	// MaxRetries is how many times a failed operation is run again
+	MaxRetries int
+	// BaseDelay doubles per attempt, up to MaxDelay, with full jitter
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DatabaseRetry is the loaded database retry configuration
+var DatabaseRetry DatabaseRetryConfig
+
+// LoadDatabasePoolConfig loads database pool configuration from environment
+func LoadDatabasePoolConfig() {
+	DatabasePool = DatabasePoolConfig{
+		MaxOpenConns:    100,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 60,
+		ConnMaxIdleTime: 10,
+		ConnectRetries:  10,
+		ConnectBackoff:  1,
+	}
+
+	if v := viper.GetInt("DB_MAX_OPEN_CONNS"); v > 0 {
+		DatabasePool.MaxOpenConns = v
+	}
+	if v := viper.GetInt("DB_MAX_IDLE_CONNS"); v > 0 {
+		DatabasePool.MaxIdleConns = v
+	}
+	if viper.IsSet("DB_CONN_MAX_LIFETIME") {
+		DatabasePool.ConnMaxLifetime = viper.GetInt("DB_CONN_MAX_LIFETIME")
+	}
+	if viper.IsSet("DB_CONN_MAX_IDLE_TIME") {
+		DatabasePool.ConnMaxIdleTime = viper.GetInt("DB_CONN_MAX_IDLE_TIME")
+	}
+	if viper.IsSet("DB_CONNECT_RETRIES") {
+		DatabasePool.ConnectRetries = viper.GetInt("DB_CONNECT_RETRIES")
+	}
+	if v := viper.GetInt("DB_CONNECT_BACKOFF"); v > 0 {
+		DatabasePool.ConnectBackoff = v
+	}
+
+	// Idle connections above the open limit would never be used
+	if DatabasePool.MaxIdleConns > DatabasePool.MaxOpenConns {
+		utils.Log.Warnf("DB_MAX_IDLE_CONNS (%d) exceeds DB_MAX_OPEN_CONNS (%d), capping",
+			DatabasePool.MaxIdleConns, DatabasePool.MaxOpenConns)
+		DatabasePool.MaxIdleConns = DatabasePool.MaxOpenConns
+	}
+	if DatabasePool.ConnectRetries < 0 {
+		DatabasePool.ConnectRetries = 0
+	}
+	if DatabasePool.ConnectBackoff > MaxDBConnectBackoff {
+		DatabasePool.ConnectBackoff = MaxDBConnectBackoff
+	}
+}
+
+// LoadDatabaseRetryConfig loads database retry configuration from environment
+func LoadDatabaseRetryConfig() {
+	DatabaseRetry = DatabaseRetryConfig{
+		MaxRetries: 2,
+		BaseDelay:  50 * time.Millisecond,
+		MaxDelay:   time.Second,
+	}
+
+	if viper.IsSet("DB_MAX_RETRIES") {
+		DatabaseRetry.MaxRetries = max(viper.GetInt("DB_MAX_RETRIES"), 0)
+	}
+	if delay := viper.GetInt("DB_RETRY_BASE_DELAY"); delay > 0 {
+		DatabaseRetry.BaseDelay = time.Duration(delay) * time.Millisecond
+	}
+	if delay := viper.GetInt("DB_RETRY_MAX_DELAY"); delay > 0 {
+		DatabaseRetry.MaxDelay = time.Duration(delay) * time.Millisecond
+	}
+	if DatabaseRetry.MaxDelay < DatabaseRetry.BaseDelay {
+		DatabaseRetry.MaxDelay = DatabaseRetry.BaseDelay
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"app/src/utils"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Dependencies whose calls get a deadline
+const (
+	DeadlineDB    = "db"
+	DeadlineRedis = "redis"
+	DeadlineSMTP  = "smtp"
+	DeadlineOAuth = "oauth"
+)
+
+// DeadlineConfig bounds each call to a dependency. A call ends Margin before the deadline of the
+// request it is made for, and never runs longer than the limit of its dependency.
+type DeadlineConfig struct {
+	// Margin is left before the request deadline so the handler still has time to answer
+	Margin time.Duration
+	// Limits maps a dependency to the longest a single call may take (0 leaves it unbounded)
+	Limits map[string]time.Duration
+}
+
+// Deadlines is the loaded deadline policy
+var Deadlines DeadlineConfig
+
+// LoadDeadlineConfig loads the deadline policy from environment
+func LoadDeadlineConfig() {
+	Deadlines = DeadlineConfig{
+		Margin: 100 * time.Millisecond,
+		Limits: map[string]time.Duration{
+			DeadlineDB:    5 * time.Second,
+			DeadlineRedis: time.Second,
+			DeadlineSMTP:  10 * time.Second,
+			DeadlineOAuth: 10 * time.Second,
+		},
+	}
+
+	Deadlines.Margin = deadlineMillis("DEADLINE_MARGIN_MS", Deadlines.Margin)
+
+	// DEADLINE_<DEPENDENCY>_MS overrides the limit of a dependency, e.g. DEADLINE_DB_MS=2000
+	for dependency, limit := range Deadlines.Limits {
+		name := "DEADLINE_" + strings.ToUpper(dependency) + "_MS"
+		Deadlines.Limits[dependency] = deadlineMillis(name, limit)
+	}
+}
+
+// deadlineMillis reads a duration in milliseconds, keeping fallback when unset or negative
+func deadlineMillis(name string, fallback time.Duration) time.Duration {
+	if !viper.IsSet(name) {
+		return fallback
+	}
+
+	ms := viper.GetInt(name)
+	if ms < 0 {
+		utils.Log.Warnf("Invalid %s %d, using %v", name, ms, fallback)
+		return fallback
+	}
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DebugCaptureConfig holds request/response body capture settings for debugging
+type DebugCaptureConfig struct {
+	Enabled bool
+	// SamplePercent of requests (0-100) captured without the debug header
+	SamplePercent float64
+	// TTL is how long captures are kept
+	TTL time.Duration
+	// MaxBodyBytes truncates each captured body
+	MaxBodyBytes int
+}
+
+// DebugCaptureHeader asks for the request to be captured; honoured only for callers with the "debugRequests" right
+const DebugCaptureHeader = "X-Debug-Capture"
+
+// DebugCapture is the loaded body capture configuration
+var DebugCapture DebugCaptureConfig
+
+// LoadDebugCaptureConfig loads body capture configuration from environment
+func LoadDebugCaptureConfig() {
+	DebugCapture = DebugCaptureConfig{
+		Enabled:       viper.GetBool("DEBUG_CAPTURE_ENABLED"),
+		SamplePercent: viper.GetFloat64("DEBUG_CAPTURE_SAMPLE_PERCENT"),
+		TTL:           15 * time.Minute,
+		MaxBodyBytes:  16 * 1024,
+	}
+
+	if DebugCapture.SamplePercent < 0 {
+		DebugCapture.SamplePercent = 0
+	}
+	if DebugCapture.SamplePercent > 100 {
+		DebugCapture.SamplePercent = 100
+	}
+	if ttl := viper.GetInt("DEBUG_CAPTURE_TTL"); ttl > 0 {
+		DebugCapture.TTL = time.Duration(ttl) * time.Minute
+	}
+	if size := viper.GetInt("DEBUG_CAPTURE_MAX_BODY"); size > 0 {
+		DebugCapture.MaxBodyBytes = size
+	}
+}
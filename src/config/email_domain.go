@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultDisposableEmailListURL is a community-maintained list of disposable email domains
+const DefaultDisposableEmailListURL = "https://raw.githubusercontent.com/disposable-email-domains/" +
+	"disposable-email-domains/main/disposable_email_blocklist.conf"
+
+// EmailDomainConfig restricts which email domains may sign up; admins can add rules at runtime
+type EmailDomainConfig struct {
+	// Allow, when not empty, is the only set of domains that may sign up ("example.com" or "*.example.com")
+	Allow []string
+	Block []string
+	// BlockDisposable rejects domains on the disposable list, downloaded every DisposableRefresh
+	BlockDisposable   bool
+	DisposableListURL string
+	DisposableRefresh time.Duration
+}
+
+// EmailDomains is the loaded email domain configuration
+var EmailDomains EmailDomainConfig
+
+// LoadEmailDomainConfig loads sign-up email domain rules from environment
+func LoadEmailDomainConfig() {
+	EmailDomains = EmailDomainConfig{
+		Allow:             splitList(strings.ToLower(viper.GetString("EMAIL_DOMAIN_ALLOWLIST"))),
+		Block:             splitList(strings.ToLower(viper.GetString("EMAIL_DOMAIN_BLOCKLIST"))),
+		BlockDisposable:   viper.GetBool("EMAIL_BLOCK_DISPOSABLE"),
+		DisposableListURL: DefaultDisposableEmailListURL,
+		DisposableRefresh: 24 * time.Hour,
+	}
+
+	if viper.IsSet("DISPOSABLE_EMAIL_LIST_URL") {
+		EmailDomains.DisposableListURL = viper.GetString("DISPOSABLE_EMAIL_LIST_URL")
+	}
+	if refresh := viper.GetInt("DISPOSABLE_EMAIL_LIST_REFRESH"); refresh > 0 {
+		EmailDomains.DisposableRefresh = time.Duration(refresh) * time.Hour
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// EmailSendingConfig throttles outbound email and authenticates the provider feedback webhooks
+type EmailSendingConfig struct {
+	// RecipientLimit is how many emails one address may receive per RecipientWindow (0 disables)
+	RecipientLimit  int
+	RecipientWindow time.Duration
+	// GlobalLimit is how many emails all instances may send together per GlobalWindow, usually the
+	// provider's sending rate (0 disables)
+	GlobalLimit  int
+	GlobalWindow time.Duration
+	// WebhookSecret must be sent as the token query parameter of bounce webhooks; without it the
+	// webhooks are disabled
+	WebhookSecret string
+}
+
+// EmailSending is the loaded outbound email configuration
+var EmailSending EmailSendingConfig
+
+// LoadEmailSendingConfig loads outbound email throttles and the feedback webhook secret from environment
+func LoadEmailSendingConfig() {
+	EmailSending = EmailSendingConfig{
+		RecipientLimit:  10,
+		RecipientWindow: time.Hour,
+		GlobalWindow:    time.Minute,
+		WebhookSecret:   viper.GetString("EMAIL_WEBHOOK_SECRET"),
+	}
+
+	if viper.IsSet("EMAIL_RECIPIENT_LIMIT") {
+		EmailSending.RecipientLimit = viper.GetInt("EMAIL_RECIPIENT_LIMIT")
+	}
+	if window := viper.GetInt("EMAIL_RECIPIENT_WINDOW"); window > 0 {
+		EmailSending.RecipientWindow = time.Duration(window) * time.Minute
+	}
+	EmailSending.GlobalLimit = viper.GetInt("EMAIL_GLOBAL_LIMIT")
+	if window := viper.GetInt("EMAIL_GLOBAL_WINDOW"); window > 0 {
+		EmailSending.GlobalWindow = time.Duration(window) * time.Second
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EncryptionKey is a named, base64-encoded AES key (or a KMS-wrapped key when EncryptionKMS is set)
+type EncryptionKey struct {
+	ID  string
+	Key string
+}
+
+// EncryptionKeys encrypt sensitive columns; the first one encrypts new values, the rest only decrypt
+var EncryptionKeys []EncryptionKey
+
+// EncryptionKMS marks EncryptionKeys as wrapped by a KMS that must unwrap them at startup
+var EncryptionKMS bool
+
+// LoadEncryptionConfig loads column encryption keys from environment
+// ENCRYPTION_KEYS format: id:base64key,id:base64key (newest first)
+func LoadEncryptionConfig() {
+	EncryptionKeys = nil
+	EncryptionKMS = viper.GetBool("ENCRYPTION_KMS")
+
+	for _, entry := range strings.Split(viper.GetString("ENCRYPTION_KEYS"), ",") {
+		id, key, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" || key == "" {
+			continue
+		}
+		EncryptionKeys = append(EncryptionKeys, EncryptionKey{ID: id, Key: key})
+	}
+}
@@ -9,12 +9,18 @@ import (
 
 func FiberConfig() fiber.Config {
 	return fiber.Config{
-		Prefork:       IsProd,
+		Prefork:       Server.Prefork,
 		CaseSensitive: true,
 		ServerHeader:  "Fiber",
 		AppName:       "Fiber API",
 		ErrorHandler:  utils.ErrorHandler,
 		JSONEncoder:   sonic.Marshal,
 		JSONDecoder:   sonic.Unmarshal,
+		ReadTimeout:   Server.ReadTimeout,
+		WriteTimeout:  Server.WriteTimeout,
+		IdleTimeout:   Server.IdleTimeout,
+		BodyLimit:     Server.BodyLimit,
+		// Each listener is logged instead of printing the banner once per listener
+		DisableStartupMessage: len(Listeners) > 1,
 	}
 }
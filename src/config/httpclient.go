@@ -0,0 +1,57 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// HTTPClientConfig holds the defaults for outbound HTTP clients built by the httpclient package
+type HTTPClientConfig struct {
+	Timeout time.Duration
+	// MaxRetries is how many times an idempotent request is retried after a network error, 429 or 5xx
+	MaxRetries int
+	// RetryBaseDelay doubles per attempt, up to RetryMaxDelay, with full jitter
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// BreakerFailureThreshold consecutive failures to one host open its circuit for BreakerTimeout
+	BreakerFailureThreshold int
+	BreakerTimeout          time.Duration
+}
+
+// HTTPClient is the loaded outbound HTTP client configuration
+var HTTPClient HTTPClientConfig
+
+// LoadHTTPClientConfig loads outbound HTTP client configuration from environment
+func LoadHTTPClientConfig() {
+	HTTPClient = HTTPClientConfig{
+		Timeout:                 10 * time.Second,
+		MaxRetries:              2,
+		RetryBaseDelay:          200 * time.Millisecond,
+		RetryMaxDelay:           2 * time.Second,
+		BreakerFailureThreshold: 5,
+		BreakerTimeout:          30 * time.Second,
+	}
+
+	if timeout := viper.GetInt("HTTP_CLIENT_TIMEOUT"); timeout > 0 {
+		HTTPClient.Timeout = time.Duration(timeout) * time.Second
+	}
+	if viper.IsSet("HTTP_CLIENT_MAX_RETRIES") {
+		HTTPClient.MaxRetries = max(viper.GetInt("HTTP_CLIENT_MAX_RETRIES"), 0)
+	}
+	if delay := viper.GetInt("HTTP_CLIENT_RETRY_BASE_DELAY"); delay > 0 {
+		HTTPClient.RetryBaseDelay = time.Duration(delay) * time.Millisecond
+	}
+	if delay := viper.GetInt("HTTP_CLIENT_RETRY_MAX_DELAY"); delay > 0 {
+		HTTPClient.RetryMaxDelay = time.Duration(delay) * time.Millisecond
+	}
+	if HTTPClient.RetryMaxDelay < HTTPClient.RetryBaseDelay {
+		HTTPClient.RetryMaxDelay = HTTPClient.RetryBaseDelay
+	}
+	if threshold := viper.GetInt("HTTP_CLIENT_BREAKER_THRESHOLD"); threshold > 0 {
+		HTTPClient.BreakerFailureThreshold = threshold
+	}
+	if timeout := viper.GetInt("HTTP_CLIENT_BREAKER_TIMEOUT"); timeout > 0 {
+		HTTPClient.BreakerTimeout = time.Duration(timeout) * time.Second
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"app/src/utils"
+	"app/src/utils/id"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// LoadIDConfig selects the format of new primary keys from environment: uuidv7 (default) or ulid
+func LoadIDConfig() {
+	format := strings.ToLower(viper.GetString("ID_FORMAT"))
+	if format == "" {
+		format = id.FormatUUIDv7
+	}
+
+	if err := id.Configure(format); err != nil {
+		utils.Log.Fatal(err)
+	}
+}
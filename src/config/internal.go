@@ -0,0 +1,66 @@
+package config
+
+import (
+	"app/src/utils"
+
+	"github.com/spf13/viper"
+)
+
+// InternalConfig guards the operational endpoints: /metrics, /debug/pprof, the detailed health
+// check and the admin API
+type InternalConfig struct {
+	// Enabled serves those endpoints only on the listener from INTERNAL_ADDR
+	Enabled bool
+	// BasicAuthUser and BasicAuthPassword protect the endpoints that have no auth of their own
+	// (metrics, pprof, health check); the admin API keeps requiring an admin token
+	BasicAuthUser     string
+	BasicAuthPassword string
+	// Pprof serves Go's profiler at /debug/pprof on the internal listener
+	Pprof bool
+	// Closed answers 404 for those endpoints on every listener. It is set in production when neither
+	// an internal listener nor basic auth guards them, rather than serving them publicly.
+	Closed bool
+}
+
+// Internal is the loaded internal endpoint configuration
+var Internal InternalConfig
+
+// LoadInternalConfig loads internal endpoint configuration from environment; LoadListenerConfig
+// must run first
+func LoadInternalConfig() {
+	Internal = InternalConfig{
+		BasicAuthUser:     viper.GetString("INTERNAL_BASIC_AUTH_USER"),
+		BasicAuthPassword: viper.GetString("INTERNAL_BASIC_AUTH_PASSWORD"),
+	}
+
+	var listener *ListenerConfig
+	for i := range Listeners {
+		if Listeners[i].Name == InternalListener {
+			listener = &Listeners[i]
+		}
+	}
+	Internal.Enabled = listener != nil
+
+	if (Internal.BasicAuthUser == "") != (Internal.BasicAuthPassword == "") {
+		utils.Log.Fatal("INTERNAL_BASIC_AUTH_USER and INTERNAL_BASIC_AUTH_PASSWORD must be set together")
+	}
+
+	// The profiler exposes memory contents and can stall the process, so it never shares the public listener
+	if viper.GetBool("INTERNAL_PPROF") {
+		if Internal.Enabled {
+			Internal.Pprof = true
+		} else {
+			utils.Log.Warn("INTERNAL_PPROF ignored: pprof is only served on the INTERNAL_ADDR listener")
+		}
+	}
+
+	if IsProd && !Internal.Enabled && Internal.BasicAuthUser == "" {
+		Internal.Closed = true
+		utils.Log.Warn("Metrics, the detailed health check and the admin API are disabled in production " +
+			"without INTERNAL_ADDR or INTERNAL_BASIC_AUTH_USER")
+	}
+
+	if Internal.Enabled && listener.ClientCA == "" && Internal.BasicAuthUser == "" {
+		utils.Log.Warn("Internal listener has no auth of its own; set INTERNAL_CLIENT_CA or INTERNAL_BASIC_AUTH_USER")
+	}
+}
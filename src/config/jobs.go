@@ -0,0 +1,134 @@
+package config
+
+import (
+	"app/src/utils"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// TokenCleanupInterval is how often expired tokens are purged, in minutes (0 disables the job)
+var TokenCleanupInterval int
+
+// LeaderLeaseTTL is the leadership lease duration in seconds for background workers
+var LeaderLeaseTTL int
+
+// SecurityDigestConfig schedules the weekly security summary emailed to admins
+type SecurityDigestConfig struct {
+	Enabled    bool
+	Weekday    time.Weekday
+	Hour       int      // UTC hour the digest is sent
+	Recipients []string // empty sends to every admin account
+}
+
+// SecurityDigest holds the loaded security digest schedule
+var SecurityDigest SecurityDigestConfig
+
+// BulkActionConfig controls the queue running bulk user actions
+type BulkActionConfig struct {
+	// MaxUsers caps how many users one bulk action may target
+	MaxUsers int
+	// PollInterval is how often each instance looks for queued bulk actions
+	PollInterval time.Duration
+	// StaleAfter requeues a running action whose instance stopped reporting progress for this long
+	StaleAfter time.Duration
+}
+
+// BulkActions holds the loaded bulk action queue configuration
+var BulkActions BulkActionConfig
+
+// EmailOutboxConfig controls how queued emails are sent and retried
+type EmailOutboxConfig struct {
+	// PollInterval is how often each instance sends the emails due
+	PollInterval time.Duration
+	// MaxAttempts is how many times an email is tried before it is marked failed
+	MaxAttempts int
+	// RetryBackoff is the wait after the first failed attempt; it doubles after each further one,
+	// up to MaxBackoff
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+}
+
+// EmailOutbox holds the loaded email outbox configuration
+var EmailOutbox EmailOutboxConfig
+
+// LoadJobConfig loads background job configuration from environment
+func LoadJobConfig() {
+	TokenCleanupInterval = 60
+	if viper.IsSet("TOKEN_CLEANUP_INTERVAL") {
+		TokenCleanupInterval = viper.GetInt("TOKEN_CLEANUP_INTERVAL")
+	}
+
+	// A dead leader is replaced within one lease; keep it short but above network hiccups
+	LeaderLeaseTTL = viper.GetInt("LEADER_LEASE_TTL")
+	if LeaderLeaseTTL < 3 {
+		LeaderLeaseTTL = 15
+	}
+
+	SecurityDigest = SecurityDigestConfig{
+		Enabled: viper.GetBool("SECURITY_DIGEST_ENABLED"),
+		Weekday: time.Monday,
+		Hour:    8,
+	}
+	if viper.IsSet("SECURITY_DIGEST_DAY") {
+		if weekday, ok := parseWeekday(viper.GetString("SECURITY_DIGEST_DAY")); ok {
+			SecurityDigest.Weekday = weekday
+		} else {
+			utils.Log.Warnf("Invalid SECURITY_DIGEST_DAY %q, using %s", viper.GetString("SECURITY_DIGEST_DAY"), SecurityDigest.Weekday)
+		}
+	}
+	if viper.IsSet("SECURITY_DIGEST_HOUR") {
+		if hour := viper.GetInt("SECURITY_DIGEST_HOUR"); hour >= 0 && hour < 24 {
+			SecurityDigest.Hour = hour
+		}
+	}
+	for _, recipient := range strings.Split(viper.GetString("SECURITY_DIGEST_RECIPIENTS"), ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			SecurityDigest.Recipients = append(SecurityDigest.Recipients, recipient)
+		}
+	}
+
+	BulkActions = BulkActionConfig{
+		MaxUsers:     10000,
+		PollInterval: 5 * time.Second,
+		StaleAfter:   5 * time.Minute,
+	}
+	if maxUsers := viper.GetInt("BULK_ACTION_MAX_USERS"); maxUsers > 0 {
+		BulkActions.MaxUsers = maxUsers
+	}
+	if poll := viper.GetInt("BULK_ACTION_POLL_INTERVAL"); poll > 0 {
+		BulkActions.PollInterval = time.Duration(poll) * time.Second
+	}
+	if stale := viper.GetInt("BULK_ACTION_STALE_AFTER"); stale > 0 {
+		BulkActions.StaleAfter = time.Duration(stale) * time.Minute
+	}
+
+	EmailOutbox = EmailOutboxConfig{
+		PollInterval: 5 * time.Second,
+		MaxAttempts:  8,
+		RetryBackoff: 30 * time.Second,
+		MaxBackoff:   time.Hour,
+	}
+	if poll := viper.GetInt("EMAIL_OUTBOX_POLL_INTERVAL"); poll > 0 {
+		EmailOutbox.PollInterval = time.Duration(poll) * time.Second
+	}
+	if attempts := viper.GetInt("EMAIL_OUTBOX_MAX_ATTEMPTS"); attempts > 0 {
+		EmailOutbox.MaxAttempts = attempts
+	}
+	if backoff := viper.GetInt("EMAIL_OUTBOX_RETRY_BACKOFF"); backoff > 0 {
+		EmailOutbox.RetryBackoff = time.Duration(backoff) * time.Second
+	}
+	if EmailOutbox.MaxBackoff < EmailOutbox.RetryBackoff {
+		EmailOutbox.MaxBackoff = EmailOutbox.RetryBackoff
+	}
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), strings.TrimSpace(name)) {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"app/src/utils"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Listener networks
+const (
+	ListenerTCP     = "tcp"
+	ListenerUnix    = "unix"
+	ListenerSystemd = "systemd"
+)
+
+// DefaultListener names the listener on APP_HOST:APP_PORT used when LISTENERS is empty
+const DefaultListener = "public"
+
+// InternalListener names the listener added by INTERNAL_ADDR for metrics, pprof and the admin API
+const InternalListener = "internal"
+
+// MTLSListener names the listener added by MTLS_ADDR, where service accounts authenticate with
+// client certificates
+const MTLSListener = "mtls"
+
+// ListenerConfig is one address the server accepts connections on
+type ListenerConfig struct {
+	// Name identifies the listener to middleware.OnListener
+	Name    string
+	Network string
+	// Address is host:port for tcp, a socket path for unix, and the FileDescriptorName= or
+	// index of a socket passed by systemd socket activation
+	Address string
+	// TLSCert and TLSKey serve TLS; with ClientCA, clients must present a certificate it signed
+	TLSCert  string
+	TLSKey   string
+	ClientCA string
+}
+
+// Listeners are the loaded listeners; the first one is used alone when prefork is on
+var Listeners []ListenerConfig
+
+// UnixSocketMode is the file mode of the Unix sockets the server creates
+var UnixSocketMode os.FileMode = 0o660
+
+// LoadListenerConfig loads the server listeners from environment, e.g.
+// LISTENERS="public=tcp://0.0.0.0:3000,admin=tcp://127.0.0.1:3001,sidecar=unix:///run/app/app.sock"
+func LoadListenerConfig() {
+	Listeners = nil
+	for _, entry := range strings.Split(viper.GetString("LISTENERS"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		listener, err := parseListener(entry)
+		if err != nil {
+			utils.Log.Fatalf("Invalid LISTENERS entry %q: %v", entry, err)
+		}
+		for _, existing := range Listeners {
+			if existing.Name == listener.Name {
+				utils.Log.Fatalf("Invalid LISTENERS: listener %q is defined twice", listener.Name)
+			}
+		}
+		Listeners = append(Listeners, listener)
+	}
+
+	if len(Listeners) == 0 {
+		Listeners = []ListenerConfig{{
+			Name:    DefaultListener,
+			Network: ListenerTCP,
+			Address: fmt.Sprintf("%s:%d", AppHost, AppPort),
+		}}
+	}
+
+	if address := viper.GetString("INTERNAL_ADDR"); address != "" {
+		internal, err := parseListener(InternalListener + "=" + address)
+		if err != nil {
+			utils.Log.Fatalf("Invalid INTERNAL_ADDR %q: %v", address, err)
+		}
+		if slices.ContainsFunc(Listeners, func(l ListenerConfig) bool { return l.Name == InternalListener }) {
+			utils.Log.Fatalf("Invalid LISTENERS: %q is reserved for INTERNAL_ADDR", InternalListener)
+		}
+
+		internal.TLSCert = viper.GetString("INTERNAL_TLS_CERT")
+		internal.TLSKey = viper.GetString("INTERNAL_TLS_KEY")
+		internal.ClientCA = viper.GetString("INTERNAL_CLIENT_CA")
+		if internal.ClientCA != "" && internal.TLSCert == "" {
+			utils.Log.Fatal("INTERNAL_CLIENT_CA requires INTERNAL_TLS_CERT and INTERNAL_TLS_KEY")
+		}
+		Listeners = append(Listeners, internal)
+	}
+
+	if address := viper.GetString("MTLS_ADDR"); address != "" {
+		mtls, err := parseListener(MTLSListener + "=" + address)
+		if err != nil {
+			utils.Log.Fatalf("Invalid MTLS_ADDR %q: %v", address, err)
+		}
+		if slices.ContainsFunc(Listeners, func(l ListenerConfig) bool { return l.Name == MTLSListener }) {
+			utils.Log.Fatalf("Invalid LISTENERS: %q is reserved for MTLS_ADDR", MTLSListener)
+		}
+
+		mtls.TLSCert = viper.GetString("MTLS_TLS_CERT")
+		mtls.TLSKey = viper.GetString("MTLS_TLS_KEY")
+		mtls.ClientCA = viper.GetString("MTLS_CLIENT_CA")
+		if mtls.TLSCert == "" || mtls.TLSKey == "" || mtls.ClientCA == "" {
+			utils.Log.Fatal("MTLS_ADDR requires MTLS_TLS_CERT, MTLS_TLS_KEY and MTLS_CLIENT_CA")
+		}
+		Listeners = append(Listeners, mtls)
+	}
+
+	if mode := viper.GetString("UNIX_SOCKET_MODE"); mode != "" {
+		parsed, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			utils.Log.Warnf("Invalid UNIX_SOCKET_MODE %q, expected octal such as 660", mode)
+		} else {
+			UnixSocketMode = os.FileMode(parsed)
+		}
+	}
+
+	// Prefork binds the port itself in every child process, so it only works with one TCP listener
+	if Server.Prefork && (len(Listeners) > 1 || Listeners[0].Network != ListenerTCP) {
+		utils.Log.Warn("Prefork disabled: it only supports a single TCP listener")
+		Server.Prefork = false
+	}
+}
+
+// parseListener reads a name=address entry; addresses without a scheme are TCP
+func parseListener(entry string) (ListenerConfig, error) {
+	name, address, ok := strings.Cut(entry, "=")
+	name, address = strings.TrimSpace(name), strings.TrimSpace(address)
+	if !ok || name == "" || address == "" {
+		return ListenerConfig{}, fmt.Errorf("expected name=address")
+	}
+
+	network := ListenerTCP
+	if scheme, rest, found := strings.Cut(address, "://"); found {
+		network, address = scheme, rest
+	}
+
+	switch network {
+	case ListenerTCP, ListenerUnix, ListenerSystemd:
+	default:
+		return ListenerConfig{}, fmt.Errorf("unknown network %q (available: tcp, unix, systemd)", network)
+	}
+	if address == "" {
+		return ListenerConfig{}, fmt.Errorf("missing address")
+	}
+
+	return ListenerConfig{Name: name, Network: network, Address: address}, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"app/src/utils"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// LoadLogConfig applies log redaction settings from environment
+// LOG_REDACT_PATTERNS holds extra regular expressions separated by ";" (commas are common in regexes)
+func LoadLogConfig() {
+	enabled := true
+	if viper.IsSet("LOG_REDACT_ENABLED") {
+		enabled = viper.GetBool("LOG_REDACT_ENABLED")
+	}
+
+	var patterns, fields []string
+	for _, pattern := range strings.Split(viper.GetString("LOG_REDACT_PATTERNS"), ";") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	for _, field := range strings.Split(viper.GetString("LOG_REDACT_FIELDS"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	if err := utils.ConfigureRedaction(enabled, patterns, fields); err != nil {
+		utils.Log.Fatal(err)
+	}
+}
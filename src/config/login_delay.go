@@ -0,0 +1,46 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// LoginDelayConfig holds the progressive delays between failed password logins for one email
+type LoginDelayConfig struct {
+	Enabled bool
+	// BaseDelay is the wait after the first failure; it doubles with every further failure, up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Reset is how long an email must go without a failure, once its delay is over, before the count is forgotten
+	Reset time.Duration
+}
+
+// LoginDelay is the loaded login delay configuration
+var LoginDelay LoginDelayConfig
+
+// LoadLoginDelayConfig loads login delay configuration from environment
+func LoadLoginDelayConfig() {
+	LoginDelay = LoginDelayConfig{
+		Enabled:   true,
+		BaseDelay: time.Second,
+		MaxDelay:  15 * time.Minute,
+		Reset:     15 * time.Minute,
+	}
+
+	if viper.IsSet("LOGIN_DELAY_ENABLED") {
+		LoginDelay.Enabled = viper.GetBool("LOGIN_DELAY_ENABLED")
+	}
+	if base := viper.GetInt("LOGIN_DELAY_BASE"); base > 0 {
+		LoginDelay.BaseDelay = time.Duration(base) * time.Second
+	}
+	if maxDelay := viper.GetInt("LOGIN_DELAY_MAX"); maxDelay > 0 {
+		LoginDelay.MaxDelay = time.Duration(maxDelay) * time.Second
+	}
+	if LoginDelay.MaxDelay < LoginDelay.BaseDelay {
+		LoginDelay.MaxDelay = LoginDelay.BaseDelay
+	}
+	if reset := viper.GetInt("LOGIN_DELAY_RESET"); reset > 0 {
+		LoginDelay.Reset = time.Duration(reset) * time.Minute
+	}
+}
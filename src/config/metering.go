@@ -0,0 +1,42 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// defaultMeteringEvents are the audit actions metered when METERING_EVENTS is not set
+var defaultMeteringEvents = []string{"login.succeeded", "user.created", "email.sent"}
+
+// MeteringConfig controls usage metering of authenticated requests and business events
+type MeteringConfig struct {
+	Enabled bool
+	// Events are the audit actions counted as business events of the user they concern
+	Events []string
+	// RollupInterval is how often the counters in the cache store are rolled into daily rows
+	RollupInterval time.Duration
+}
+
+// Metering is the loaded usage metering configuration
+var Metering MeteringConfig
+
+// LoadMeteringConfig loads usage metering configuration from environment
+func LoadMeteringConfig() {
+	Metering = MeteringConfig{
+		Enabled:        viper.GetBool("METERING_ENABLED"),
+		Events:         defaultMeteringEvents,
+		RollupInterval: 5 * time.Minute,
+	}
+
+	if viper.IsSet("METERING_EVENTS") {
+		Metering.Events = nil
+		for _, event := range splitList(viper.GetString("METERING_EVENTS")) {
+			Metering.Events = append(Metering.Events, strings.ToLower(event))
+		}
+	}
+	if rollup := viper.GetInt("METERING_ROLLUP_INTERVAL"); rollup > 0 {
+		Metering.RollupInterval = time.Duration(rollup) * time.Second
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+// MetricsEnabled exposes the Prometheus scrape endpoint at /metrics
+var MetricsEnabled bool
+
+// LoadMetricsConfig loads Prometheus metrics configuration from environment
+func LoadMetricsConfig() {
+	MetricsEnabled = true
+	if viper.IsSet("METRICS_ENABLED") {
+		MetricsEnabled = viper.GetBool("METRICS_ENABLED")
+	}
+}
@@ -1,6 +1,12 @@
 package config
 
 import (
+	"app/src/utils"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
@@ -11,12 +17,19 @@ type Config struct {
 
 var AppConfig Config
 
+// GoogleAllowedDomains restricts Google sign-in to these Workspace domains (the ID token's hd claim); empty allows any account
+var GoogleAllowedDomains []string
+
+// OAuthStateTTL is how long a login started at /auth/google may take to come back to the callback
+var OAuthStateTTL time.Duration
+
 func GoogleConfig() oauth2.Config {
 	AppConfig.GoogleLoginConfig = oauth2.Config{
 		RedirectURL:  RedirectURL,
 		ClientID:     GoogleClientID,
 		ClientSecret: GoogleClientSecret,
 		Scopes: []string{
+			"openid",
 			"https://www.googleapis.com/auth/userinfo.email",
 			"https://www.googleapis.com/auth/userinfo.profile",
 		},
@@ -25,3 +38,52 @@ func GoogleConfig() oauth2.Config {
 
 	return AppConfig.GoogleLoginConfig
 }
+
+// LoadOAuthConfig loads Google sign-in restrictions from environment
+func LoadOAuthConfig() {
+	GoogleAllowedDomains = nil
+	for _, domain := range strings.Split(viper.GetString("GOOGLE_ALLOWED_DOMAINS"), ",") {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			GoogleAllowedDomains = append(GoogleAllowedDomains, domain)
+		}
+	}
+
+	OAuthStateTTL = 10 * time.Minute
+	if ttl := viper.GetInt("OAUTH_STATE_TTL"); ttl > 0 {
+		OAuthStateTTL = time.Duration(ttl) * time.Minute
+	}
+}
+
+// AppleConfig holds Sign in with Apple settings
+type AppleConfig struct {
+	// ClientID is the Services ID registered for web sign-in
+	ClientID    string
+	TeamID      string
+	KeyID       string
+	RedirectURL string
+	// PrivateKey is the PEM contents of the .p8 key used to sign the client secret
+	PrivateKey string
+}
+
+// Apple is the loaded Sign in with Apple configuration; sign-in is disabled while ClientID is empty
+var Apple AppleConfig
+
+// LoadAppleConfig loads Sign in with Apple settings, reading the .p8 key from APPLE_PRIVATE_KEY_PATH if APPLE_PRIVATE_KEY is unset
+func LoadAppleConfig() {
+	Apple = AppleConfig{
+		ClientID:    viper.GetString("APPLE_CLIENT_ID"),
+		TeamID:      viper.GetString("APPLE_TEAM_ID"),
+		KeyID:       viper.GetString("APPLE_KEY_ID"),
+		RedirectURL: viper.GetString("APPLE_REDIRECT_URL"),
+		PrivateKey:  viper.GetString("APPLE_PRIVATE_KEY"),
+	}
+
+	if path := viper.GetString("APPLE_PRIVATE_KEY_PATH"); Apple.PrivateKey == "" && path != "" {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			utils.Log.Errorf("Failed to read APPLE_PRIVATE_KEY_PATH: %v", err)
+			return
+		}
+		Apple.PrivateKey = string(key)
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"slices"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// OAuthProviderConfig holds the authorization-code grant offered to third-party clients
+type OAuthProviderConfig struct {
+	// CodeTTL is how long an authorization code may wait before it is exchanged for a token
+	CodeTTL time.Duration
+	// AccessTokenTTL is how long delegated access tokens stay valid; there are no refresh tokens, so
+	// clients send the user through consent again once it is over
+	AccessTokenTTL time.Duration
+	// ClientRateLimit is the requests per minute allowed to all tokens of a client that sets no limit
+	// of its own (0 disables)
+	ClientRateLimit int
+}
+
+// OAuthScope is a permission third-party clients can ask users for
+type OAuthScope struct {
+	// Description is shown to users on the consent screen
+	Description string
+	// Rights are granted with the scope; only users holding all of them can grant it
+	Rights []string
+}
+
+// OAuthProvider is the loaded authorization-code grant configuration
+var OAuthProvider OAuthProviderConfig
+
+// OAuthScopes are the scopes clients can be registered with. A delegated token only reaches routes
+// declaring one of its scopes with middleware.OAuthScope.
+var OAuthScopes = map[string]OAuthScope{
+	"profile:read": {Description: "Read your profile"},
+	"users:read":   {Description: "Read the profiles of other users", Rights: []string{"getUsers"}},
+}
+
+// LoadOAuthProviderConfig loads authorization-code grant configuration from environment
+func LoadOAuthProviderConfig() {
+	OAuthProvider = OAuthProviderConfig{
+		CodeTTL:         5 * time.Minute,
+		AccessTokenTTL:  time.Hour,
+		ClientRateLimit: 600,
+	}
+
+	if ttl := viper.GetInt("OAUTH_CODE_TTL"); ttl > 0 {
+		OAuthProvider.CodeTTL = time.Duration(ttl) * time.Second
+	}
+	if ttl := viper.GetInt("OAUTH_ACCESS_TOKEN_TTL"); ttl > 0 {
+		OAuthProvider.AccessTokenTTL = time.Duration(ttl) * time.Minute
+	}
+	if viper.IsSet("OAUTH_CLIENT_RATE_LIMIT") {
+		OAuthProvider.ClientRateLimit = viper.GetInt("OAUTH_CLIENT_RATE_LIMIT")
+	}
+}
+
+// OAuthScopeRights returns the rights granted with scopes, skipping unknown ones
+func OAuthScopeRights(scopes []string) []string {
+	rights := []string{}
+	for _, scope := range scopes {
+		for _, right := range OAuthScopes[scope].Rights {
+			if !slices.Contains(rights, right) {
+				rights = append(rights, right)
+			}
+		}
+	}
+	return rights
+}
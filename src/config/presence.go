@@ -0,0 +1,47 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// PresenceConfig holds how online presence is tracked from WebSocket heartbeats
+type PresenceConfig struct {
+	// HeartbeatInterval is how often clients are asked to send a heartbeat
+	HeartbeatInterval time.Duration
+	// TTL is how long a connection counts as online after its last heartbeat; silent connections are
+	// closed once it passes
+	TTL time.Duration
+	// LastSeenRetention is how long the last time a user was seen is kept
+	LastSeenRetention time.Duration
+	// MaxLookup is how many users one bulk lookup may ask for
+	MaxLookup int
+}
+
+// Presence is the loaded presence configuration
+var Presence PresenceConfig
+
+// LoadPresenceConfig loads presence settings from environment
+func LoadPresenceConfig() {
+	Presence = PresenceConfig{
+		HeartbeatInterval: 25 * time.Second,
+		LastSeenRetention: 30 * 24 * time.Hour,
+		MaxLookup:         100,
+	}
+
+	if interval := viper.GetInt("PRESENCE_HEARTBEAT_INTERVAL"); interval > 0 {
+		Presence.HeartbeatInterval = time.Duration(interval) * time.Second
+	}
+	// A connection survives one missed heartbeat by default
+	Presence.TTL = 2*Presence.HeartbeatInterval + 10*time.Second
+	if ttl := viper.GetInt("PRESENCE_TTL"); ttl > 0 {
+		Presence.TTL = max(time.Duration(ttl)*time.Second, Presence.HeartbeatInterval+time.Second)
+	}
+	if days := viper.GetInt("PRESENCE_LAST_SEEN_RETENTION_DAYS"); days > 0 {
+		Presence.LastSeenRetention = time.Duration(days) * 24 * time.Hour
+	}
+	if limit := viper.GetInt("PRESENCE_MAX_LOOKUP"); limit > 0 {
+		Presence.MaxLookup = limit
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"app/src/utils"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Push API base URLs
+const (
+	FCMAPIURL         = "https://fcm.googleapis.com"
+	APNsAPIURL        = "https://api.push.apple.com"
+	APNsSandboxAPIURL = "https://api.sandbox.push.apple.com"
+)
+
+// PushConfig holds the credentials push notifications are sent with
+type PushConfig struct {
+	// FCMCredentials is the Firebase service account key (JSON); empty disables FCM
+	FCMCredentials string
+	FCMAPIURL      string
+
+	// APNsPrivateKey is the PEM contents of the .p8 key signing APNs provider tokens
+	APNsPrivateKey string
+	// APNsKeyID is the ID of the .p8 key; empty disables APNs
+	APNsKeyID  string
+	APNsTeamID string
+	// APNsTopic is the iOS app's bundle ID
+	APNsTopic  string
+	APNsAPIURL string
+
+	// BatchSize is how many devices are sent to at once
+	BatchSize int
+	// MaxDevices is how many devices a user can register; the least recently registered are dropped
+	MaxDevices int
+}
+
+// Push is the loaded push notification configuration
+var Push PushConfig
+
+// LoadPushConfig loads FCM and APNs settings, reading the keys from FCM_CREDENTIALS_PATH and
+// APNS_PRIVATE_KEY_PATH when FCM_CREDENTIALS and APNS_PRIVATE_KEY are unset
+func LoadPushConfig() {
+	Push = PushConfig{
+		FCMCredentials: viper.GetString("FCM_CREDENTIALS"),
+		FCMAPIURL:      FCMAPIURL,
+		APNsPrivateKey: viper.GetString("APNS_PRIVATE_KEY"),
+		APNsKeyID:      viper.GetString("APNS_KEY_ID"),
+		APNsTeamID:     viper.GetString("APNS_TEAM_ID"),
+		APNsTopic:      viper.GetString("APNS_TOPIC"),
+		APNsAPIURL:     APNsAPIURL,
+		BatchSize:      50,
+		MaxDevices:     10,
+	}
+
+	if viper.GetBool("APNS_SANDBOX") {
+		Push.APNsAPIURL = APNsSandboxAPIURL
+	}
+	if apiURL := viper.GetString("FCM_API_URL"); apiURL != "" {
+		Push.FCMAPIURL = strings.TrimSuffix(apiURL, "/")
+	}
+	if apiURL := viper.GetString("APNS_API_URL"); apiURL != "" {
+		Push.APNsAPIURL = strings.TrimSuffix(apiURL, "/")
+	}
+	if size := viper.GetInt("PUSH_BATCH_SIZE"); size > 0 {
+		Push.BatchSize = size
+	}
+	if limit := viper.GetInt("PUSH_MAX_DEVICES"); limit > 0 {
+		Push.MaxDevices = limit
+	}
+
+	if path := viper.GetString("FCM_CREDENTIALS_PATH"); Push.FCMCredentials == "" && path != "" {
+		credentials, err := os.ReadFile(path)
+		if err != nil {
+			utils.Log.Errorf("Failed to read FCM_CREDENTIALS_PATH: %v", err)
+		} else {
+			Push.FCMCredentials = string(credentials)
+		}
+	}
+	if path := viper.GetString("APNS_PRIVATE_KEY_PATH"); Push.APNsPrivateKey == "" && path != "" {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			utils.Log.Errorf("Failed to read APNS_PRIVATE_KEY_PATH: %v", err)
+		} else {
+			Push.APNsPrivateKey = string(key)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"app/src/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// QuotaLimits are the requests allowed per UTC day and calendar month; 0 means unlimited
+type QuotaLimits struct {
+	Daily   int64
+	Monthly int64
+}
+
+// QuotaConfig controls plan-based request quotas, counted on top of rate limiting for usage billing
+type QuotaConfig struct {
+	Enabled bool
+	// Plans maps a plan name to its limits; users on a plan missing here get DefaultPlan's limits
+	Plans       map[string]QuotaLimits
+	DefaultPlan string
+	// PersistInterval is how often the counters in the cache store are copied to the database
+	PersistInterval time.Duration
+}
+
+// Quota is the loaded request quota configuration
+var Quota QuotaConfig
+
+// LoadQuotaConfig loads request quota configuration from environment
+func LoadQuotaConfig() {
+	Quota = QuotaConfig{
+		Enabled:         viper.GetBool("QUOTA_ENABLED"),
+		Plans:           map[string]QuotaLimits{},
+		DefaultPlan:     "free",
+		PersistInterval: time.Minute,
+	}
+
+	if plan := strings.TrimSpace(viper.GetString("QUOTA_DEFAULT_PLAN")); plan != "" {
+		Quota.DefaultPlan = strings.ToLower(plan)
+	}
+	if persist := viper.GetInt("QUOTA_PERSIST_INTERVAL"); persist > 0 {
+		Quota.PersistInterval = time.Duration(persist) * time.Second
+	}
+
+	// QUOTA_PLANS lists plan:daily/monthly entries, e.g. "free:1000/20000,pro:50000/1000000"
+	for _, entry := range splitList(viper.GetString("QUOTA_PLANS")) {
+		limits, ok := parseQuotaPlan(entry)
+		if !ok {
+			utils.Log.Warnf("Invalid QUOTA_PLANS entry %q, expected plan:daily/monthly", entry)
+			continue
+		}
+
+		plan, _, _ := strings.Cut(entry, ":")
+		Quota.Plans[strings.ToLower(strings.TrimSpace(plan))] = limits
+	}
+
+	if _, ok := Quota.Plans[Quota.DefaultPlan]; Quota.Enabled && !ok {
+		utils.Log.Warnf("QUOTA_DEFAULT_PLAN %q is not in QUOTA_PLANS, users without a known plan are unlimited",
+			Quota.DefaultPlan)
+	}
+}
+
+// PlanLimits returns the limits of the plan, falling back to the default plan
+func (q QuotaConfig) PlanLimits(plan string) QuotaLimits {
+	if limits, ok := q.Plans[plan]; ok {
+		return limits
+	}
+	return q.Plans[q.DefaultPlan]
+}
+
+// parseQuotaPlan parses the daily/monthly limits of a QUOTA_PLANS entry
+func parseQuotaPlan(entry string) (QuotaLimits, bool) {
+	plan, value, ok := strings.Cut(entry, ":")
+	if !ok || strings.TrimSpace(plan) == "" {
+		return QuotaLimits{}, false
+	}
+
+	daily, monthly, ok := strings.Cut(value, "/")
+	if !ok {
+		return QuotaLimits{}, false
+	}
+
+	d, err := strconv.ParseInt(strings.TrimSpace(daily), 10, 64)
+	if err != nil || d < 0 {
+		return QuotaLimits{}, false
+	}
+	m, err := strconv.ParseInt(strings.TrimSpace(monthly), 10, 64)
+	if err != nil || m < 0 {
+		return QuotaLimits{}, false
+	}
+
+	return QuotaLimits{Daily: d, Monthly: m}, true
+}
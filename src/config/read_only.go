@@ -0,0 +1,38 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ReadOnlyConfig controls read-only mode, under which writes are refused and background writers pause
+type ReadOnlyConfig struct {
+	// Forced keeps the app read-only whatever the admin switch says
+	Forced bool
+	// SyncInterval is how often an instance reads the switch other instances may have flipped
+	SyncInterval time.Duration
+	// Allow lists the paths, by prefix, whose writes go on in read-only mode
+	Allow []string
+}
+
+// ReadOnly is the loaded read-only mode configuration
+var ReadOnly ReadOnlyConfig
+
+// LoadReadOnlyConfig loads read-only mode settings from environment
+func LoadReadOnlyConfig() {
+	ReadOnly = ReadOnlyConfig{
+		Forced:       viper.GetBool("READ_ONLY"),
+		SyncInterval: 2 * time.Second,
+	}
+
+	if interval := viper.GetInt("READ_ONLY_SYNC_INTERVAL"); interval > 0 {
+		ReadOnly.SyncInterval = time.Duration(interval) * time.Second
+	}
+	for _, path := range strings.Split(viper.GetString("READ_ONLY_ALLOW"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			ReadOnly.Allow = append(ReadOnly.Allow, path)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 type RedisConfig struct {
 	Host         string `mapstructure:"host"`
 	Port         int    `mapstructure:"port"`
+	Username     string `mapstructure:"username"`
 	Password     string `mapstructure:"password"`
 	DB           int    `mapstructure:"db"`
 	Enabled      bool   `mapstructure:"enabled"`
@@ -22,6 +24,23 @@ type RedisConfig struct {
 	DialTimeout  int    `mapstructure:"dial_timeout"`
 	ReadTimeout  int    `mapstructure:"read_timeout"`
 	WriteTimeout int    `mapstructure:"write_timeout"`
+
+	// Namespace prefixes every key and channel, e.g. "app:staging:", so environments can share an instance
+	Namespace string `mapstructure:"namespace"`
+
+	// TLS settings for managed Redis (ElastiCache, Upstash, ...)
+	TLSEnabled            bool   `mapstructure:"tls_enabled"`
+	TLSCACert             string `mapstructure:"tls_ca_cert"`
+	TLSCert               string `mapstructure:"tls_cert"`
+	TLSKey                string `mapstructure:"tls_key"`
+	TLSServerName         string `mapstructure:"tls_server_name"`
+	TLSInsecureSkipVerify bool   `mapstructure:"tls_insecure_skip_verify"`
+
+	// Circuit breaker settings
+	BreakerFailureThreshold int `mapstructure:"breaker_failure_threshold"`
+	BreakerMaxRequests      int `mapstructure:"breaker_max_requests"`
+	BreakerInterval         int `mapstructure:"breaker_interval"`
+	BreakerTimeout          int `mapstructure:"breaker_timeout"`
 }
 
 // RateLimiterConfig holds rate limiting configuration
@@ -50,6 +69,37 @@ func (c *RedisConfig) Validate() error {
 		return fmt.Errorf("Redis host cannot be empty")
 	}
 
+	// The namespace is put in front of SCAN patterns, so it must not contain glob characters
+	if strings.ContainsAny(c.Namespace, "*?[]\\ ") {
+		return fmt.Errorf("invalid Redis namespace %q: glob characters and spaces are not allowed", c.Namespace)
+	}
+
+	return c.validateTLS()
+}
+
+// validateTLS checks that TLS certificate settings are consistent and the files are readable
+func (c *RedisConfig) validateTLS() error {
+	if !c.TLSEnabled {
+		if c.TLSCACert != "" || c.TLSCert != "" || c.TLSKey != "" {
+			return fmt.Errorf("Redis TLS certificates configured but REDIS_TLS_ENABLED is not true")
+		}
+		return nil
+	}
+
+	// Client certificate and key must be provided together (mutual TLS)
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return fmt.Errorf("REDIS_TLS_CERT and REDIS_TLS_KEY must be set together")
+	}
+
+	for _, path := range []string{c.TLSCACert, c.TLSCert, c.TLSKey} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("Redis TLS file not readable: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -116,12 +166,16 @@ func LoadRedisConfig() (*RedisConfig, error) {
 		config.Port = 6379
 	}
 
+	// ACL username (Redis 6+); empty uses the default user
+	config.Username = viper.GetString("REDIS_USERNAME")
 	config.Password = viper.GetString("REDIS_PASSWORD")
 	config.DB = viper.GetInt("REDIS_DB")
 	if config.DB < 0 {
 		config.DB = 0
 	}
 
+	config.Namespace = NormalizeRedisNamespace(viper.GetString("REDIS_NAMESPACE"))
+
 	// Connection pool parameters
 	config.MaxIdle = viper.GetInt("REDIS_MAX_IDLE")
 	if config.MaxIdle == 0 {
@@ -158,9 +212,52 @@ func LoadRedisConfig() (*RedisConfig, error) {
 		config.WriteTimeout = 5 // 5 seconds
 	}
 
+	// Circuit breaker parameters
+	config.BreakerFailureThreshold = viper.GetInt("REDIS_CB_FAILURE_THRESHOLD")
+	if config.BreakerFailureThreshold <= 0 {
+		config.BreakerFailureThreshold = 3
+	}
+
+	config.BreakerMaxRequests = viper.GetInt("REDIS_CB_MAX_REQUESTS")
+	if config.BreakerMaxRequests <= 0 {
+		config.BreakerMaxRequests = 5
+	}
+
+	config.BreakerInterval = viper.GetInt("REDIS_CB_INTERVAL")
+	if config.BreakerInterval <= 0 {
+		config.BreakerInterval = 60 // 1 minute in seconds
+	}
+
+	config.BreakerTimeout = viper.GetInt("REDIS_CB_TIMEOUT")
+	if config.BreakerTimeout <= 0 {
+		config.BreakerTimeout = 30 // 30 seconds
+	}
+
+	// TLS parameters
+	config.TLSEnabled = viper.GetBool("REDIS_TLS_ENABLED")
+	config.TLSCACert = viper.GetString("REDIS_TLS_CA_CERT")
+	config.TLSCert = viper.GetString("REDIS_TLS_CERT")
+	config.TLSKey = viper.GetString("REDIS_TLS_KEY")
+	config.TLSServerName = viper.GetString("REDIS_TLS_SERVER_NAME")
+	config.TLSInsecureSkipVerify = viper.GetBool("REDIS_TLS_INSECURE_SKIP_VERIFY")
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid Redis configuration: %w", err)
+	}
+
 	return &config, nil
 }
 
+// NormalizeRedisNamespace trims the namespace and ends it with a colon, so "app:staging" and
+// "app:staging:" name the same keys
+func NormalizeRedisNamespace(namespace string) string {
+	namespace = strings.TrimSpace(namespace)
+	if namespace != "" && !strings.HasSuffix(namespace, ":") {
+		namespace += ":"
+	}
+	return namespace
+}
+
 // LoadRateLimiterConfig loads rate limit configuration from environment variables
 func LoadRateLimiterConfig() *RateLimiterConfig {
 	var config RateLimiterConfig
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// RequestSigningMaxSkew is how far a signed partner request's timestamp may be from the server clock
+var RequestSigningMaxSkew time.Duration
+
+// LoadRequestSigningConfig loads partner request signing configuration from environment
+func LoadRequestSigningConfig() {
+	RequestSigningMaxSkew = 5 * time.Minute
+	if skew := viper.GetInt("REQUEST_SIGNING_MAX_SKEW"); skew > 0 {
+		RequestSigningMaxSkew = time.Duration(skew) * time.Second
+	}
+}
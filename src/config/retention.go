@@ -0,0 +1,86 @@
+package config
+
+import (
+	"app/src/utils"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Retention policies, each covering rows of one table or part of one
+const (
+	RetentionAuditLogs     = "audit_logs"
+	RetentionLoginHistory  = "login_history"
+	RetentionNotifications = "notifications"
+	RetentionExports       = "exports"
+	RetentionAnalytics     = "analytics"
+)
+
+// RetentionPolicies are all the retention policies, in the order they are purged
+var RetentionPolicies = []string{
+	RetentionAuditLogs, RetentionLoginHistory, RetentionNotifications, RetentionExports, RetentionAnalytics,
+}
+
+// RetentionConfig controls the job deleting rows once they are past their retention
+type RetentionConfig struct {
+	Enabled bool
+	// Interval is how often the job runs
+	Interval time.Duration
+	// BatchSize caps the rows deleted by one statement, so no purge holds locks for long
+	BatchSize int
+	// DryRun only counts and logs the rows due, without deleting them
+	DryRun bool
+	// Days maps a policy to how many days its rows are kept; policies missing from it keep rows forever
+	Days map[string]int
+}
+
+// Retention is the loaded retention configuration
+var Retention RetentionConfig
+
+// LoadRetentionConfig loads retention periods and the purge schedule from environment
+func LoadRetentionConfig() {
+	Retention = RetentionConfig{
+		Enabled:   viper.GetBool("RETENTION_ENABLED"),
+		Interval:  time.Hour,
+		BatchSize: 1000,
+		DryRun:    viper.GetBool("RETENTION_DRY_RUN"),
+		Days: map[string]int{
+			RetentionAuditLogs:     365,
+			RetentionLoginHistory:  90,
+			RetentionNotifications: 30,
+			RetentionExports:       7,
+			RetentionAnalytics:     400,
+		},
+	}
+
+	if interval := viper.GetInt("RETENTION_INTERVAL"); interval > 0 {
+		Retention.Interval = time.Duration(interval) * time.Minute
+	}
+	if batchSize := viper.GetInt("RETENTION_BATCH_SIZE"); batchSize > 0 {
+		Retention.BatchSize = batchSize
+	}
+
+	// RETENTION_DAYS overrides policies, e.g. "audit_logs:730,notifications:14"; 0 days keeps rows forever
+	for _, entry := range strings.Split(viper.GetString("RETENTION_DAYS"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		days, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || !slices.Contains(RetentionPolicies, name) || err != nil || days < 0 {
+			utils.Log.Warnf("Invalid RETENTION_DAYS entry %q, expected policy:days", entry)
+			continue
+		}
+
+		if days == 0 {
+			delete(Retention.Days, name)
+			continue
+		}
+		Retention.Days[name] = days
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"app/src/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Login risk rules
+const (
+	RiskRuleNewCountry       = "new_country"
+	RiskRuleNewASN           = "new_asn"
+	RiskRuleImpossibleTravel = "impossible_travel"
+	RiskRuleTorExit          = "tor_exit"
+	RiskRuleVelocity         = "velocity"
+)
+
+// DefaultTorExitListURL is the Tor Project's bulk exit node list (one IP per line)
+const DefaultTorExitListURL = "https://check.torproject.org/torbulkexitlist"
+
+// RiskConfig holds suspicious login detection configuration
+type RiskConfig struct {
+	Enabled bool
+	// Rules maps each enabled rule to the score it adds when it fires
+	Rules map[string]int
+	// A total score at or above ChallengeScore requires email confirmation, at or above BlockScore rejects the login
+	ChallengeScore int
+	BlockScore     int
+	// MaxTravelSpeed (km/h) between consecutive logins above which travel is impossible
+	MaxTravelSpeed float64
+	// VelocityMax failed logins from one IP within VelocityWindow indicate credential stuffing
+	VelocityMax    int
+	VelocityWindow time.Duration
+	// ConfirmExp is how long an emailed login confirmation link stays valid
+	ConfirmExp time.Duration
+	// HistoryTTL is how long known login locations are remembered
+	HistoryTTL time.Duration
+	// TorListURL is downloaded every TorRefresh; empty disables the download
+	TorListURL string
+	TorRefresh time.Duration
+	// Geo headers set by a CDN or proxy in front of the app (e.g. Cloudflare)
+	CountryHeader   string
+	ASNHeader       string
+	LatitudeHeader  string
+	LongitudeHeader string
+}
+
+// Risk is the loaded suspicious login detection configuration
+var Risk RiskConfig
+
+// LoadRiskConfig loads suspicious login detection configuration from environment
+func LoadRiskConfig() {
+	Risk = RiskConfig{
+		Enabled: viper.GetBool("RISK_ENABLED"),
+		Rules: map[string]int{
+			RiskRuleNewCountry:       30,
+			RiskRuleNewASN:           10,
+			RiskRuleImpossibleTravel: 60,
+			RiskRuleTorExit:          50,
+			RiskRuleVelocity:         80,
+		},
+		ChallengeScore:  40,
+		BlockScore:      80,
+		MaxTravelSpeed:  900,
+		VelocityMax:     20,
+		VelocityWindow:  10 * time.Minute,
+		ConfirmExp:      15 * time.Minute,
+		HistoryTTL:      90 * 24 * time.Hour,
+		TorListURL:      DefaultTorExitListURL,
+		TorRefresh:      time.Hour,
+		CountryHeader:   "CF-IPCountry",
+		ASNHeader:       viper.GetString("RISK_ASN_HEADER"),
+		LatitudeHeader:  "CF-IPLatitude",
+		LongitudeHeader: "CF-IPLongitude",
+	}
+
+	if rules := viper.GetString("RISK_RULES"); rules != "" {
+		Risk.Rules = parseRiskRules(rules)
+	}
+	if score := viper.GetInt("RISK_CHALLENGE_SCORE"); score > 0 {
+		Risk.ChallengeScore = score
+	}
+	if score := viper.GetInt("RISK_BLOCK_SCORE"); score > 0 {
+		Risk.BlockScore = score
+	}
+	if speed := viper.GetFloat64("RISK_MAX_TRAVEL_SPEED"); speed > 0 {
+		Risk.MaxTravelSpeed = speed
+	}
+	if max := viper.GetInt("RISK_VELOCITY_MAX"); max > 0 {
+		Risk.VelocityMax = max
+	}
+	if window := viper.GetInt("RISK_VELOCITY_WINDOW"); window > 0 {
+		Risk.VelocityWindow = time.Duration(window) * time.Minute
+	}
+	if exp := viper.GetInt("RISK_CONFIRM_EXP_MINUTES"); exp > 0 {
+		Risk.ConfirmExp = time.Duration(exp) * time.Minute
+	}
+	if days := viper.GetInt("RISK_HISTORY_DAYS"); days > 0 {
+		Risk.HistoryTTL = time.Duration(days) * 24 * time.Hour
+	}
+	if viper.IsSet("RISK_TOR_LIST_URL") {
+		Risk.TorListURL = viper.GetString("RISK_TOR_LIST_URL")
+	}
+	if refresh := viper.GetInt("RISK_TOR_REFRESH"); refresh > 0 {
+		Risk.TorRefresh = time.Duration(refresh) * time.Minute
+	}
+	if viper.IsSet("RISK_COUNTRY_HEADER") {
+		Risk.CountryHeader = viper.GetString("RISK_COUNTRY_HEADER")
+	}
+	if viper.IsSet("RISK_LATITUDE_HEADER") {
+		Risk.LatitudeHeader = viper.GetString("RISK_LATITUDE_HEADER")
+	}
+	if viper.IsSet("RISK_LONGITUDE_HEADER") {
+		Risk.LongitudeHeader = viper.GetString("RISK_LONGITUDE_HEADER")
+	}
+}
+
+// parseRiskRules parses "rule:score,rule:score"; rules left out are disabled
+func parseRiskRules(raw string) map[string]int {
+	rules := make(map[string]int)
+	for _, entry := range strings.Split(raw, ",") {
+		name, score, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		if name == "" {
+			continue
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(score))
+		if err != nil {
+			utils.Log.Warnf("Ignoring risk rule %q: invalid score %q", name, score)
+			continue
+		}
+		rules[name] = value
+	}
+	return rules
+}
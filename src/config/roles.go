@@ -1,13 +1,29 @@
 package config
 
 var allRoles = map[string][]string{
-	"user":  {},
-	"admin": {"getUsers", "manageUsers"},
+	"user": {},
+	"admin": {
+		"getUsers", "manageUsers", "manageCache", "manageCircuitBreaker", "manageApiTokens", "debugRequests",
+		"viewUserActivity", "manageRateLimits", "manageEmailDomains", "manageEmailSuppressions", "manageOAuthClients",
+		"manageQuotas", "viewUsage", "viewRoutes", "managePartners", "manageServiceAccounts", "adminSearch",
+		"manageRetention", "manageLegalHolds", "manageReadOnly", "exportAuditLogs", "viewAnalytics",
+		ACLAdminRight,
+	},
 }
 
+// ACLAdminRight passes every ACL permission check and lets its holders manage the sharing of any record
+const ACLAdminRight = "manageAcl"
+
 var Roles = getKeys(allRoles)
 var RoleRights = allRoles
 
+// UserFieldsByRole lists the user fields each role may change with PATCH /v1/users/:userId; roles
+// without an entry get those of "user"
+var UserFieldsByRole = map[string][]string{
+	"user":  {"name", "username", "email", "password", "timezone"},
+	"admin": {"name", "username", "email", "password", "role", "timezone"},
+}
+
 func getKeys(m map[string][]string) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -15,3 +31,23 @@ func getKeys(m map[string][]string) []string {
 	}
 	return keys
 }
+
+// RightsForRole returns the resolved rights granted to a role, or an empty list for unknown roles
+func RightsForRole(role string) []string {
+	rights, ok := RoleRights[role]
+	if !ok {
+		return []string{}
+	}
+
+	scopes := make([]string, len(rights))
+	copy(scopes, rights)
+	return scopes
+}
+
+// UserFieldsForRole returns the user fields a role may change
+func UserFieldsForRole(role string) []string {
+	if fields, ok := UserFieldsByRole[role]; ok {
+		return fields
+	}
+	return UserFieldsByRole["user"]
+}
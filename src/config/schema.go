@@ -0,0 +1,32 @@
+package config
+
+import (
+	"app/src/utils"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Schema check modes: strict refuses to start on an incompatible database schema, warn only logs it
+const (
+	SchemaCheckStrict = "strict"
+	SchemaCheckWarn   = "warn"
+	SchemaCheckOff    = "off"
+)
+
+// SchemaCheck is what startup does when the database schema does not fit this build
+var SchemaCheck string
+
+// LoadSchemaConfig loads the startup schema compatibility check from environment
+func LoadSchemaConfig() {
+	SchemaCheck = strings.ToLower(strings.TrimSpace(viper.GetString("SCHEMA_CHECK")))
+
+	switch SchemaCheck {
+	case "":
+		SchemaCheck = SchemaCheckStrict
+	case SchemaCheckStrict, SchemaCheckWarn, SchemaCheckOff:
+	default:
+		utils.Log.Warnf("Unknown SCHEMA_CHECK '%s', using default: strict", SchemaCheck)
+		SchemaCheck = SchemaCheckStrict
+	}
+}
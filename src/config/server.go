@@ -0,0 +1,77 @@
+package config
+
+import (
+	"app/src/utils"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ServerConfig holds the HTTP server's connection limits
+type ServerConfig struct {
+	// ReadTimeout bounds reading a whole request, headers and body, so slow clients cannot hold a connection
+	ReadTimeout time.Duration
+	// WriteTimeout bounds writing a response
+	WriteTimeout time.Duration
+	// IdleTimeout closes keep-alive connections without a new request for this long
+	IdleTimeout time.Duration
+	// HandlerTimeout is the deadline of each request's user context (0 disables)
+	HandlerTimeout time.Duration
+	// BodyLimit is the largest request body accepted, in bytes; larger ones get 413
+	BodyLimit int
+	// Prefork runs one process per CPU sharing the port
+	Prefork bool
+}
+
+// Server is the loaded HTTP server configuration
+var Server ServerConfig
+
+// LoadServerConfig loads HTTP server timeouts and limits from environment
+func LoadServerConfig() {
+	Server = ServerConfig{
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		IdleTimeout:    120 * time.Second,
+		HandlerTimeout: 30 * time.Second,
+		BodyLimit:      4 * 1024 * 1024,
+		Prefork:        IsProd,
+	}
+
+	Server.ReadTimeout = positiveSeconds("SERVER_READ_TIMEOUT", Server.ReadTimeout)
+	Server.WriteTimeout = positiveSeconds("SERVER_WRITE_TIMEOUT", Server.WriteTimeout)
+	Server.IdleTimeout = positiveSeconds("SERVER_IDLE_TIMEOUT", Server.IdleTimeout)
+
+	if viper.IsSet("SERVER_HANDLER_TIMEOUT") {
+		if timeout := viper.GetInt("SERVER_HANDLER_TIMEOUT"); timeout >= 0 {
+			Server.HandlerTimeout = time.Duration(timeout) * time.Second
+		} else {
+			utils.Log.Warnf("Invalid SERVER_HANDLER_TIMEOUT %d, using %v", timeout, Server.HandlerTimeout)
+		}
+	}
+	if viper.IsSet("SERVER_BODY_LIMIT") {
+		if limit := viper.GetInt("SERVER_BODY_LIMIT"); limit > 0 {
+			Server.BodyLimit = limit
+		} else {
+			utils.Log.Warnf("Invalid SERVER_BODY_LIMIT %d, using %d bytes", limit, Server.BodyLimit)
+		}
+	}
+	if viper.GetString("SERVER_PREFORK") != "" {
+		Server.Prefork = viper.GetBool("SERVER_PREFORK")
+	}
+}
+
+// positiveSeconds reads a timeout in seconds, keeping the default when it is missing or not positive;
+// a zero timeout would mean none at all
+func positiveSeconds(key string, fallback time.Duration) time.Duration {
+	if !viper.IsSet(key) {
+		return fallback
+	}
+
+	seconds := viper.GetInt(key)
+	if seconds <= 0 {
+		utils.Log.Warnf("Invalid %s %d, using %v", key, seconds, fallback)
+		return fallback
+	}
+
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// SessionActivityWindows are the "active in the last N minutes" buckets reported to admins
+var SessionActivityWindows = []int{5, 30, 1440}
+
+// SessionActivityConfig controls how session activity is recorded and when idle sessions end
+type SessionActivityConfig struct {
+	Enabled bool
+	// WriteInterval is the least time between two activity writes for the same session on one instance
+	WriteInterval time.Duration
+	// FlushInterval is how often buffered activity is copied to the database
+	FlushInterval time.Duration
+	// IdleTimeout ends sessions without activity for this long (0 keeps them until they expire)
+	IdleTimeout time.Duration
+}
+
+// SessionActivity is the loaded session activity configuration
+var SessionActivity SessionActivityConfig
+
+// LoadSessionActivityConfig loads session activity tracking configuration from environment
+func LoadSessionActivityConfig() {
+	SessionActivity = SessionActivityConfig{
+		Enabled:       true,
+		WriteInterval: time.Minute,
+		FlushInterval: time.Minute,
+	}
+
+	if viper.IsSet("SESSION_ACTIVITY_ENABLED") {
+		SessionActivity.Enabled = viper.GetBool("SESSION_ACTIVITY_ENABLED")
+	}
+	if write := viper.GetInt("SESSION_ACTIVITY_WRITE_INTERVAL"); write > 0 {
+		SessionActivity.WriteInterval = time.Duration(write) * time.Second
+	}
+	if flush := viper.GetInt("SESSION_ACTIVITY_FLUSH_INTERVAL"); flush > 0 {
+		SessionActivity.FlushInterval = time.Duration(flush) * time.Second
+	}
+	if idle := viper.GetInt("SESSION_IDLE_TIMEOUT"); idle > 0 {
+		SessionActivity.IdleTimeout = time.Duration(idle) * time.Minute
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"app/src/utils"
+
+	"github.com/spf13/viper"
+)
+
+// SIEM sinks audit entries can be forwarded to
+const (
+	SIEMSyslog  = "syslog"
+	SIEMWebhook = "webhook"
+	SIEMKafka   = "kafka"
+)
+
+// SIEMConfig controls forwarding audit entries to a SIEM as they are recorded
+type SIEMConfig struct {
+	// Sink is syslog, webhook or kafka; empty disables forwarding
+	Sink string
+	// PollInterval is how often new entries are looked for once every entry was read
+	PollInterval time.Duration
+	// BatchSize caps the entries read per query and sent per call
+	BatchSize int
+	// BufferSize caps the entries read but not sent yet; reading waits while the buffer is full
+	BufferSize int
+	// Timeout bounds one call to the sink
+	Timeout time.Duration
+
+	// SyslogNetwork is udp, tcp or tls; SyslogAddress is the host:port of the collector
+	SyslogNetwork string
+	SyslogAddress string
+	// WebhookURL receives batches as NDJSON, with WebhookAuthorization as the Authorization header
+	WebhookURL           string
+	WebhookAuthorization string
+	// KafkaRESTURL is the base URL of the Kafka REST Proxy producing to KafkaTopic
+	KafkaRESTURL string
+	KafkaTopic   string
+}
+
+// SIEM is the loaded SIEM forwarding configuration
+var SIEM SIEMConfig
+
+// LoadSIEMConfig loads the SIEM sink and its settings from environment
+func LoadSIEMConfig() {
+	SIEM = SIEMConfig{
+		Sink:                 strings.ToLower(strings.TrimSpace(viper.GetString("SIEM_SINK"))),
+		PollInterval:         time.Second,
+		BatchSize:            100,
+		BufferSize:           1000,
+		Timeout:              10 * time.Second,
+		SyslogNetwork:        "tcp",
+		SyslogAddress:        viper.GetString("SIEM_SYSLOG_ADDRESS"),
+		WebhookURL:           viper.GetString("SIEM_WEBHOOK_URL"),
+		WebhookAuthorization: viper.GetString("SIEM_WEBHOOK_AUTHORIZATION"),
+		KafkaRESTURL:         viper.GetString("SIEM_KAFKA_REST_URL"),
+		KafkaTopic:           viper.GetString("SIEM_KAFKA_TOPIC"),
+	}
+
+	if interval := viper.GetInt("SIEM_POLL_INTERVAL"); interval > 0 {
+		SIEM.PollInterval = time.Duration(interval) * time.Second
+	}
+	if batchSize := viper.GetInt("SIEM_BATCH_SIZE"); batchSize > 0 {
+		SIEM.BatchSize = batchSize
+	}
+	if bufferSize := viper.GetInt("SIEM_BUFFER_SIZE"); bufferSize > 0 {
+		SIEM.BufferSize = bufferSize
+	}
+	if timeout := viper.GetInt("SIEM_TIMEOUT"); timeout > 0 {
+		SIEM.Timeout = time.Duration(timeout) * time.Second
+	}
+	if network := viper.GetString("SIEM_SYSLOG_NETWORK"); network != "" {
+		SIEM.SyslogNetwork = strings.ToLower(network)
+	}
+
+	// A sink missing its target is disabled rather than failing every send
+	switch SIEM.Sink {
+	case "":
+	case SIEMSyslog:
+		if SIEM.SyslogAddress == "" || !slices.Contains([]string{"udp", "tcp", "tls"}, SIEM.SyslogNetwork) {
+			utils.Log.Warn("SIEM forwarding disabled: set SIEM_SYSLOG_ADDRESS and SIEM_SYSLOG_NETWORK (udp, tcp or tls)")
+			SIEM.Sink = ""
+		}
+	case SIEMWebhook:
+		if !validSIEMURL(SIEM.WebhookURL) {
+			utils.Log.Warnf("SIEM forwarding disabled: invalid SIEM_WEBHOOK_URL %q", SIEM.WebhookURL)
+			SIEM.Sink = ""
+		}
+	case SIEMKafka:
+		if !validSIEMURL(SIEM.KafkaRESTURL) || SIEM.KafkaTopic == "" {
+			utils.Log.Warn("SIEM forwarding disabled: set SIEM_KAFKA_REST_URL and SIEM_KAFKA_TOPIC")
+			SIEM.Sink = ""
+		}
+	default:
+		utils.Log.Warnf("SIEM forwarding disabled: unknown SIEM_SINK %q, expected syslog, webhook or kafka", SIEM.Sink)
+		SIEM.Sink = ""
+	}
+}
+
+func validSIEMURL(value string) bool {
+	target, err := url.Parse(value)
+	return err == nil && (target.Scheme == "https" || target.Scheme == "http") && target.Host != ""
+}
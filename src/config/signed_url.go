@@ -0,0 +1,35 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// SigningKey is a named secret used to sign temporary URLs
+type SigningKey struct {
+	ID     string
+	Secret string
+}
+
+// SignedURLKeys are the URL signing keys; the first one signs new URLs, the rest only verify
+var SignedURLKeys []SigningKey
+
+// LoadSignedURLConfig loads URL signing keys from environment
+// SIGNED_URL_KEYS format: id:secret,id:secret (newest first)
+func LoadSignedURLConfig() {
+	SignedURLKeys = nil
+
+	for _, entry := range strings.Split(viper.GetString("SIGNED_URL_KEYS"), ",") {
+		id, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" || secret == "" {
+			continue
+		}
+		SignedURLKeys = append(SignedURLKeys, SigningKey{ID: id, Secret: secret})
+	}
+
+	// Fall back to the JWT secret so signing works out of the box
+	if len(SignedURLKeys) == 0 && JWTSecret != "" {
+		SignedURLKeys = []SigningKey{{ID: "default", Secret: JWTSecret}}
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Text message providers
+const (
+	SMSProviderTwilio = "twilio"
+	SMSProviderVonage = "vonage"
+)
+
+// Provider API base URLs
+const (
+	TwilioAPIURL = "https://api.twilio.com"
+	VonageAPIURL = "https://api.nexmo.com"
+)
+
+// SMSConfig holds the provider text messages are sent through, over SMS and WhatsApp
+type SMSConfig struct {
+	// Provider is twilio or vonage; empty disables text messages
+	Provider string
+	// From is the SMS sender: a number in E.164 format, or an alphanumeric sender ID where allowed
+	From string
+	// WhatsAppFrom is the WhatsApp business number; empty disables the WhatsApp channel
+	WhatsAppFrom string
+	// StatusCallbackURL is the public URL of /v1/notifications/callbacks/{provider}, as the provider
+	// calls it. Twilio is sent it with each message and signs it; Vonage takes it from the dashboard.
+	StatusCallbackURL string
+	// CodeTTL is how long a phone verification code stays valid
+	CodeTTL time.Duration
+
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioAPIURL     string
+
+	VonageAPIKey    string
+	VonageAPISecret string
+	// VonageSignatureSecret verifies the signed JWT Vonage sends with status callbacks
+	VonageSignatureSecret string
+	VonageAPIURL          string
+}
+
+// SMS is the loaded text message configuration
+var SMS SMSConfig
+
+// LoadSMSConfig loads text message provider configuration from environment
+func LoadSMSConfig() {
+	SMS = SMSConfig{
+		Provider:              strings.ToLower(strings.TrimSpace(viper.GetString("SMS_PROVIDER"))),
+		From:                  viper.GetString("SMS_FROM"),
+		WhatsAppFrom:          viper.GetString("WHATSAPP_FROM"),
+		StatusCallbackURL:     viper.GetString("SMS_STATUS_CALLBACK_URL"),
+		CodeTTL:               10 * time.Minute,
+		TwilioAccountSID:      viper.GetString("TWILIO_ACCOUNT_SID"),
+		TwilioAuthToken:       viper.GetString("TWILIO_AUTH_TOKEN"),
+		TwilioAPIURL:          TwilioAPIURL,
+		VonageAPIKey:          viper.GetString("VONAGE_API_KEY"),
+		VonageAPISecret:       viper.GetString("VONAGE_API_SECRET"),
+		VonageSignatureSecret: viper.GetString("VONAGE_SIGNATURE_SECRET"),
+		VonageAPIURL:          VonageAPIURL,
+	}
+
+	if ttl := viper.GetInt("PHONE_CODE_TTL"); ttl > 0 {
+		SMS.CodeTTL = time.Duration(ttl) * time.Minute
+	}
+	if apiURL := viper.GetString("TWILIO_API_URL"); apiURL != "" {
+		SMS.TwilioAPIURL = strings.TrimSuffix(apiURL, "/")
+	}
+	if apiURL := viper.GetString("VONAGE_API_URL"); apiURL != "" {
+		SMS.VonageAPIURL = strings.TrimSuffix(apiURL, "/")
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+// StartupWaitTimeout is how long to wait for dependencies before serving traffic, in seconds (0 disables)
+var StartupWaitTimeout int
+
+// LoadStartupConfig loads startup dependency wait configuration from environment
+func LoadStartupConfig() {
+	StartupWaitTimeout = 60
+	if viper.IsSet("STARTUP_WAIT_TIMEOUT") {
+		StartupWaitTimeout = viper.GetInt("STARTUP_WAIT_TIMEOUT")
+	}
+	if StartupWaitTimeout < 0 {
+		StartupWaitTimeout = 0
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+// StaticConfig holds frontend static file serving configuration
+type StaticConfig struct {
+	Enabled bool
+	// Dir serves files from disk instead of the embedded build when set
+	Dir string
+	// MaxAge is the Cache-Control max-age for assets in seconds (index.html is never cached)
+	MaxAge int
+}
+
+// Static is the loaded static file serving configuration
+var Static StaticConfig
+
+// LoadStaticConfig loads static file serving configuration from environment
+func LoadStaticConfig() {
+	Static = StaticConfig{
+		Enabled: viper.GetBool("STATIC_ENABLED"),
+		Dir:     viper.GetString("STATIC_DIR"),
+		MaxAge:  86400,
+	}
+
+	if viper.IsSet("STATIC_MAX_AGE") {
+		Static.MaxAge = viper.GetInt("STATIC_MAX_AGE")
+	}
+	if Static.MaxAge < 0 {
+		Static.MaxAge = 0
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ThrottleConfig holds per-target (email) throttling for abuse-prone auth endpoints
+type ThrottleConfig struct {
+	Enabled bool
+	// Max requests per target within Window before the target is backed off
+	Max    int
+	Window time.Duration
+	// BaseBackoff doubles with every repeated violation, up to MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// CaptchaAfter is the number of violations after which the CAPTCHA hook must pass (0 disables)
+	CaptchaAfter int
+}
+
+// Throttle is the loaded per-target throttling configuration
+var Throttle ThrottleConfig
+
+// LoadThrottleConfig loads per-target throttling configuration from environment
+func LoadThrottleConfig() {
+	Throttle = ThrottleConfig{
+		Enabled:      true,
+		Max:          3,
+		Window:       15 * time.Minute,
+		BaseBackoff:  time.Minute,
+		MaxBackoff:   time.Hour,
+		CaptchaAfter: 2,
+	}
+
+	if viper.IsSet("AUTH_THROTTLE_ENABLED") {
+		Throttle.Enabled = viper.GetBool("AUTH_THROTTLE_ENABLED")
+	}
+	if max := viper.GetInt("AUTH_THROTTLE_MAX"); max > 0 {
+		Throttle.Max = max
+	}
+	if window := viper.GetInt("AUTH_THROTTLE_WINDOW"); window > 0 {
+		Throttle.Window = time.Duration(window) * time.Minute
+	}
+	if backoff := viper.GetInt("AUTH_THROTTLE_BACKOFF"); backoff > 0 {
+		Throttle.BaseBackoff = time.Duration(backoff) * time.Second
+	}
+	if maxBackoff := viper.GetInt("AUTH_THROTTLE_MAX_BACKOFF"); maxBackoff > 0 {
+		Throttle.MaxBackoff = time.Duration(maxBackoff) * time.Second
+	}
+	if Throttle.MaxBackoff < Throttle.BaseBackoff {
+		Throttle.MaxBackoff = Throttle.BaseBackoff
+	}
+	if viper.IsSet("AUTH_THROTTLE_CAPTCHA_AFTER") {
+		Throttle.CaptchaAfter = viper.GetInt("AUTH_THROTTLE_CAPTCHA_AFTER")
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"app/src/utils"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultTimezone renders dates and interprets date filters for users without a timezone
+// preference. Timestamps are always stored and returned in UTC.
+var DefaultTimezone = time.UTC
+
+// LoadTimeConfig loads the default timezone from environment, falling back to UTC when unknown
+func LoadTimeConfig() {
+	DefaultTimezone = time.UTC
+
+	name := viper.GetString("DEFAULT_TIMEZONE")
+	if name == "" {
+		return
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		utils.Log.Warnf("Invalid DEFAULT_TIMEZONE '%s': %v. Using UTC", name, err)
+		return
+	}
+	DefaultTimezone = location
+}
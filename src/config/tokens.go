@@ -5,4 +5,5 @@ const (
 	TokenTypeRefresh       = "refresh"
 	TokenTypeResetPassword = "resetPassword"
 	TokenTypeVerifyEmail   = "verifyEmail"
+	TokenTypeConfirmLogin  = "confirmLogin"
 )
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"slices"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// TrafficMirrorExcludedPaths are never mirrored, whatever TRAFFIC_MIRROR_EXCLUDE says: they carry
+// credentials, one-time tokens or admin actions
+var TrafficMirrorExcludedPaths = []string{"/v1/auth", "/v1/users/me/tokens", "/v1/admin", "/v1/docs"}
+
+// TrafficMirrorConfig controls copying a sample of live requests to a shadow deployment
+type TrafficMirrorConfig struct {
+	Enabled bool
+	// TargetURL is the base URL requests are replayed against, e.g. https://staging.example.com
+	TargetURL string
+	// SamplePercent of eligible requests (0-100) that are mirrored
+	SamplePercent float64
+	// ExcludedPaths are path prefixes never mirrored, including TrafficMirrorExcludedPaths
+	ExcludedPaths []string
+	// MaxBodyBytes skips requests with larger bodies rather than sending them truncated
+	MaxBodyBytes int
+	Timeout      time.Duration
+	// Workers send mirrored requests; QueueSize more may wait, and the rest are dropped
+	Workers   int
+	QueueSize int
+}
+
+// TrafficMirror is the loaded traffic mirroring configuration
+var TrafficMirror TrafficMirrorConfig
+
+// LoadTrafficMirrorConfig loads traffic mirroring configuration from environment
+func LoadTrafficMirrorConfig() {
+	TrafficMirror = TrafficMirrorConfig{
+		Enabled:       viper.GetBool("TRAFFIC_MIRROR_ENABLED"),
+		TargetURL:     viper.GetString("TRAFFIC_MIRROR_URL"),
+		SamplePercent: viper.GetFloat64("TRAFFIC_MIRROR_SAMPLE_PERCENT"),
+		ExcludedPaths: slices.Concat(TrafficMirrorExcludedPaths, splitList(viper.GetString("TRAFFIC_MIRROR_EXCLUDE"))),
+		MaxBodyBytes:  64 * 1024,
+		Timeout:       5 * time.Second,
+		Workers:       4,
+		QueueSize:     100,
+	}
+
+	if TrafficMirror.SamplePercent < 0 {
+		TrafficMirror.SamplePercent = 0
+	}
+	if TrafficMirror.SamplePercent > 100 {
+		TrafficMirror.SamplePercent = 100
+	}
+	if size := viper.GetInt("TRAFFIC_MIRROR_MAX_BODY"); size > 0 {
+		TrafficMirror.MaxBodyBytes = size
+	}
+	if timeout := viper.GetInt("TRAFFIC_MIRROR_TIMEOUT"); timeout > 0 {
+		TrafficMirror.Timeout = time.Duration(timeout) * time.Second
+	}
+	if workers := viper.GetInt("TRAFFIC_MIRROR_WORKERS"); workers > 0 {
+		TrafficMirror.Workers = workers
+	}
+	if TrafficMirror.TargetURL == "" {
+		TrafficMirror.Enabled = false
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"app/src/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// TagRateLimitConfig caps requests per user for users carrying certain tags
+type TagRateLimitConfig struct {
+	// Limits maps a tag to the requests a tagged user may make per Window; users with several
+	// limited tags get the lowest limit
+	Limits map[string]int
+	Window time.Duration
+	// Backoff is how long a user who exceeded their limit is blocked; it doubles on repeat violations
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// TagRateLimits is the loaded tag rate limit configuration
+var TagRateLimits TagRateLimitConfig
+
+// LoadTagRateLimitConfig loads per-tag request limits from environment
+func LoadTagRateLimitConfig() {
+	TagRateLimits = TagRateLimitConfig{
+		Limits:     map[string]int{},
+		Window:     15 * time.Minute,
+		Backoff:    time.Minute,
+		MaxBackoff: time.Hour,
+	}
+
+	if window := viper.GetInt("TAG_RATE_LIMIT_WINDOW"); window > 0 {
+		TagRateLimits.Window = time.Duration(window) * time.Minute
+	}
+	if backoff := viper.GetInt("TAG_RATE_LIMIT_BACKOFF"); backoff > 0 {
+		TagRateLimits.Backoff = time.Duration(backoff) * time.Second
+	}
+	if TagRateLimits.MaxBackoff < TagRateLimits.Backoff {
+		TagRateLimits.MaxBackoff = TagRateLimits.Backoff
+	}
+
+	// TAG_RATE_LIMITS lists tag:max pairs, e.g. "abuser:20,trial:200"
+	for _, entry := range strings.Split(viper.GetString("TAG_RATE_LIMITS"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		tag, value, ok := strings.Cut(entry, ":")
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || limit <= 0 {
+			utils.Log.Warnf("Invalid TAG_RATE_LIMITS entry %q, expected tag:max", entry)
+			continue
+		}
+
+		TagRateLimits.Limits[strings.ToLower(strings.TrimSpace(tag))] = limit
+	}
+}
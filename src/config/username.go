@@ -0,0 +1,49 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultReservedUsernames can never be taken, so they cannot be used to impersonate staff or
+// clash with routes
+var DefaultReservedUsernames = []string{
+	"admin", "administrator", "root", "system", "support", "help", "staff", "moderator", "security",
+	"official", "billing", "api", "www", "mail", "me", "null", "undefined", "anonymous",
+	"settings", "login", "logout", "register", "auth", "users",
+}
+
+// UsernameConfig controls which usernames may be chosen and how often availability may be checked
+type UsernameConfig struct {
+	// Reserved usernames, lowercased; they are matched regardless of case
+	Reserved []string
+	// CheckThrottle limits availability checks per client IP to slow down username enumeration
+	CheckThrottle ThrottleConfig
+}
+
+// Usernames is the loaded username configuration
+var Usernames UsernameConfig
+
+// LoadUsernameConfig loads reserved usernames and the availability check limit from environment
+func LoadUsernameConfig() {
+	Usernames = UsernameConfig{
+		Reserved: slices.Concat(DefaultReservedUsernames, splitList(strings.ToLower(viper.GetString("RESERVED_USERNAMES")))),
+		CheckThrottle: ThrottleConfig{
+			Enabled:     true,
+			Max:         30,
+			Window:      time.Minute,
+			BaseBackoff: time.Minute,
+			MaxBackoff:  15 * time.Minute,
+		},
+	}
+
+	if max := viper.GetInt("USERNAME_CHECK_MAX"); max > 0 {
+		Usernames.CheckThrottle.Max = max
+	}
+	if window := viper.GetInt("USERNAME_CHECK_WINDOW"); window > 0 {
+		Usernames.CheckThrottle.Window = time.Duration(window) * time.Minute
+	}
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ACLController struct {
+	ACLService service.ACLService
+}
+
+func NewACLController(aclService service.ACLService) *ACLController {
+	return &ACLController{
+		ACLService: aclService,
+	}
+}
+
+// @Tags         ACL
+// @Summary      List who a record is shared with
+// @Description  Owners of the record and admins can see its entries.
+// @Security BearerAuth
+// @Produce      json
+// @Param        resourceType  path  string  true  "Resource type"  example(document)
+// @Param        resourceId    path  string  true  "Resource id"
+// @Router       /acl/{resourceType}/{resourceId} [get]
+// @Success      200  {object}  example.GetACLEntriesResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (a *ACLController) GetEntries(c *fiber.Ctx) error {
+	entries, err := a.ACLService.ListEntries(c, c.Params("resourceType"), c.Params("resourceId"))
+	if err != nil {
+		return err
+	}
+
+	list := make([]response.ACLEntry, len(entries))
+	for i := range entries {
+		list[i] = response.NewACLEntry(&entries[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithACLEntries{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get ACL entries successfully",
+			Entries: list,
+		})
+}
+
+// @Tags         ACL
+// @Summary      Share a record
+// @Description  Owners of the record and admins can grant a user ("user:<id>"), role ("role:<role>") or tag ("tag:<tag>") read, write or owner permission. Owner implies write, which implies read.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        resourceType  path  string               true  "Resource type"  example(document)
+// @Param        resourceId    path  string               true  "Resource id"
+// @Param        request       body  validation.GrantACL  true  "Request body"
+// @Router       /acl/{resourceType}/{resourceId} [post]
+// @Success      201  {object}  example.GrantACLResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      409  {object}  example.AlreadyGranted  "Permission already granted"
+func (a *ACLController) GrantEntry(c *fiber.Ctx) error {
+	req := new(validation.GrantACL)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	entry, err := a.ACLService.AddEntry(c, c.Params("resourceType"), c.Params("resourceId"), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithACLEntry{
+			Code:    fiber.StatusCreated,
+			Status:  "success",
+			Message: "Grant permission successfully",
+			Entry:   response.NewACLEntry(entry),
+		})
+}
+
+// @Tags         ACL
+// @Summary      Stop sharing a record
+// @Description  Owners of the record and admins can revoke its entries, except its last owner.
+// @Security BearerAuth
+// @Produce      json
+// @Param        resourceType  path  string  true  "Resource type"  example(document)
+// @Param        resourceId    path  string  true  "Resource id"
+// @Param        entryId       path  string  true  "Entry id"
+// @Router       /acl/{resourceType}/{resourceId}/{entryId} [delete]
+// @Success      200  {object}  example.RevokeACLResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+// @Failure      409  {object}  example.LastOwner  "Cannot remove the last owner"
+func (a *ACLController) RevokeEntry(c *fiber.Ctx) error {
+	err := a.ACLService.RemoveEntry(c, c.Params("resourceType"), c.Params("resourceId"), utils.ParamID(c, "entryId"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Revoke permission successfully",
+		})
+}
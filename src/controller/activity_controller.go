@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+	"math"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ActivityController struct {
+	ActivityService service.ActivityService
+}
+
+func NewActivityController(activityService service.ActivityService) *ActivityController {
+	return &ActivityController{
+		ActivityService: activityService,
+	}
+}
+
+// @Tags         Users
+// @Summary      Get a user's activity timeline
+// @Description  Only admins can read a user's logins, issued tokens, sent emails and other audit entries, newest first.
+// @Security BearerAuth
+// @Produce      json
+// @Param        userId  path   string  true   "User id"
+// @Param        page    query  int     false  "Page number"  default(1)
+// @Param        limit   query  int     false  "Maximum number of events"  default(20)
+// @Param        types   query  string  false  "Comma-separated event types to include (login, token, email, message, audit)"
+// @Param        from    query  string  false  "First day, in the caller's timezone (YYYY-MM-DD)"
+// @Param        to      query  string  false  "Last day, in the caller's timezone (YYYY-MM-DD)"
+// @Router       /admin/users/{userId}/activity [get]
+// @Success      200  {object}  example.GetUserActivityResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (ac *ActivityController) GetUserActivity(c *fiber.Ctx) error {
+	query := &validation.QueryActivity{
+		Page:  c.QueryInt("page", 1),
+		Limit: c.QueryInt("limit", 20),
+		From:  c.Query("from"),
+		To:    c.Query("to"),
+	}
+	if types := c.Query("types"); types != "" {
+		query.Types = strings.Split(types, ",")
+	}
+
+	events, totalResults, err := ac.ActivityService.GetUserActivity(c, utils.ParamID(c, "userId"), query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPaginate[response.ActivityEvent]{
+			Code:         fiber.StatusOK,
+			Status:       "success",
+			Message:      "Get user activity successfully",
+			Results:      events,
+			Page:         query.Page,
+			Limit:        query.Limit,
+			TotalPages:   int64(math.Ceil(float64(totalResults) / float64(query.Limit))),
+			TotalResults: totalResults,
+		})
+}
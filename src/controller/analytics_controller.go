@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnalyticsController struct {
+	AnalyticsService service.AnalyticsService
+}
+
+func NewAnalyticsController(analyticsService service.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{
+		AnalyticsService: analyticsService,
+	}
+}
+
+// @Tags         Analytics
+// @Summary      Get active users
+// @Description  Only admins can read the number of distinct users active per UTC day and per calendar month. Counts are HyperLogLog estimates, within about 1% of the exact number; a month counts all of its days, including those outside the range.
+// @Security BearerAuth
+// @Produce      json
+// @Param        from  query  string  false  "First day (YYYY-MM-DD), defaults to the start of the month"
+// @Param        to    query  string  false  "Last day (YYYY-MM-DD), defaults to today"
+// @Router       /admin/analytics/active-users [get]
+// @Success      200  {object}  example.GetActiveUsersResponse
+// @Failure      400  {object}  example.InvalidDateRange  "Invalid date range"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (a *AnalyticsController) GetActiveUsers(c *fiber.Ctx) error {
+	query := &validation.QueryActiveUsers{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	}
+
+	activeUsers, err := a.AnalyticsService.GetActiveUsers(c, query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithActiveUsers{
+			Code:        fiber.StatusOK,
+			Status:      "success",
+			Message:     "Get active users successfully",
+			ActiveUsers: *activeUsers,
+		})
+}
+
+// @Tags         Analytics
+// @Summary      Get unique visitors per endpoint
+// @Description  Only admins can read the endpoints called on a UTC day, ranked by their number of distinct client IPs. Counts are HyperLogLog estimates, within about 1% of the exact number.
+// @Security BearerAuth
+// @Produce      json
+// @Param        day    query  string  false  "Day (YYYY-MM-DD), defaults to today"
+// @Param        limit  query  int     false  "Maximum number of endpoints"  default(100)
+// @Router       /admin/analytics/endpoints [get]
+// @Success      200  {object}  example.GetEndpointVisitorsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (a *AnalyticsController) GetEndpointVisitors(c *fiber.Ctx) error {
+	query := &validation.QueryEndpointVisitors{
+		Day:   c.Query("day"),
+		Limit: c.QueryInt("limit", 100),
+	}
+
+	visitors, err := a.AnalyticsService.GetEndpointVisitors(c, query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithEndpointVisitors{
+			Code:     fiber.StatusOK,
+			Status:   "success",
+			Message:  "Get endpoint visitors successfully",
+			Visitors: *visitors,
+		})
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APITokenController struct {
+	APITokenService service.APITokenService
+}
+
+func NewAPITokenController(apiTokenService service.APITokenService) *APITokenController {
+	return &APITokenController{
+		APITokenService: apiTokenService,
+	}
+}
+
+// @Tags         API Tokens
+// @Summary      List personal access tokens
+// @Description  Admins can list the personal access tokens they have minted.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /users/me/tokens [get]
+// @Success      200  {object}  example.GetAPITokensResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (a *APITokenController) GetTokens(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+
+	tokens, err := a.APITokenService.ListTokens(c, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	results := make([]response.APIToken, len(tokens))
+	for i := range tokens {
+		results[i] = response.NewAPIToken(&tokens[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithAPITokens{
+			Code:      fiber.StatusOK,
+			Status:    "success",
+			Message:   "Get API tokens successfully",
+			APITokens: results,
+		})
+}
+
+// @Tags         API Tokens
+// @Summary      Create a personal access token
+// @Description  Admins can mint tokens restricted to a subset of their rights. The token is only shown once.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.CreateAPIToken  true  "Request body"
+// @Router       /users/me/tokens [post]
+// @Success      201  {object}  example.CreateAPITokenResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (a *APITokenController) CreateToken(c *fiber.Ctx) error {
+	req := new(validation.CreateAPIToken)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, _ := c.Locals("user").(*model.User)
+
+	token, rawToken, err := a.APITokenService.CreateToken(c, user, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithAPIToken{
+			Code:     fiber.StatusCreated,
+			Status:   "success",
+			Message:  "Create API token successfully",
+			APIToken: response.NewAPIToken(token),
+			Token:    rawToken,
+		})
+}
+
+// @Tags         API Tokens
+// @Summary      Revoke a personal access token
+// @Description  Admins can revoke their own personal access tokens.
+// @Security BearerAuth
+// @Produce      json
+// @Param        tokenId  path  string  true  "Token id"
+// @Router       /users/me/tokens/{tokenId} [delete]
+// @Success      200  {object}  example.RevokeAPITokenResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (a *APITokenController) RevokeToken(c *fiber.Ctx) error {
+	tokenID := utils.ParamID(c, "tokenId")
+
+	user, _ := c.Locals("user").(*model.User)
+
+	if err := a.APITokenService.RevokeToken(c, user.ID.String(), tokenID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Revoke API token successfully",
+		})
+}
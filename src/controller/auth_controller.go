@@ -1,18 +1,13 @@
 package controller
 
 import (
-	"app/src/config"
+	"app/src/metrics"
 	"app/src/model"
 	"app/src/response"
 	"app/src/service"
 	"app/src/validation"
-	"context"
-	"encoding/json"
-	"io"
-	"net/http"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 type AuthController struct {
@@ -20,28 +15,39 @@ type AuthController struct {
 	UserService  service.UserService
 	TokenService service.TokenService
 	EmailService service.EmailService
+	AuditService service.AuditService
+	GoogleOAuth  service.GoogleOAuthService
+	AppleOAuth   service.AppleOAuthService
+	Billing      service.BillingService
 }
 
 func NewAuthController(
 	authService service.AuthService, userService service.UserService,
-	tokenService service.TokenService, emailService service.EmailService,
+	tokenService service.TokenService, emailService service.EmailService, auditService service.AuditService,
+	googleOAuth service.GoogleOAuthService, appleOAuth service.AppleOAuthService, billing service.BillingService,
 ) *AuthController {
 	return &AuthController{
 		AuthService:  authService,
 		UserService:  userService,
 		TokenService: tokenService,
 		EmailService: emailService,
+		AuditService: auditService,
+		GoogleOAuth:  googleOAuth,
+		AppleOAuth:   appleOAuth,
+		Billing:      billing,
 	}
 }
 
 // @Tags         Auth
 // @Summary      Register as user
+// @Description  Signs the new user in and sends them a verification email in the background, retried until it is delivered.
 // @Accept       json
 // @Produce      json
 // @Param        request  body  validation.Register  true  "Request body"
 // @Router       /auth/register [post]
 // @Success      201  {object}  example.RegisterResponse
 // @Failure      409  {object}  example.DuplicateEmail  "Email already taken"
+// @Failure      422  {object}  example.EmailDomainNotAllowed  "Email domain is not allowed"
 func (a *AuthController) Register(c *fiber.Ctx) error {
 	req := new(validation.Register)
 
@@ -49,22 +55,20 @@ func (a *AuthController) Register(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	user, err := a.AuthService.Register(c, req)
+	user, tokens, err := a.AuthService.Register(c, req)
+	metrics.RecordRegistration(err)
 	if err != nil {
 		return err
 	}
 
-	tokens, err := a.TokenService.GenerateAuthTokens(c, user)
-	if err != nil {
-		return err
-	}
+	a.Billing.EnsureCustomer(user)
 
 	return c.Status(fiber.StatusCreated).
 		JSON(response.SuccessWithTokens{
 			Code:    fiber.StatusCreated,
 			Status:  "success",
 			Message: "Register successfully",
-			User:    *user,
+			User:    response.NewUser(user),
 			Tokens:  *tokens,
 		})
 }
@@ -77,6 +81,8 @@ func (a *AuthController) Register(c *fiber.Ctx) error {
 // @Router       /auth/login [post]
 // @Success      200  {object}  example.LoginResponse
 // @Failure      401  {object}  example.FailedLogin  "Invalid email or password"
+// @Failure      403  {object}  example.SuspiciousLogin  "Login blocked or awaiting email confirmation"
+// @Failure      423  {object}  example.AccountSuspended  "Account suspended"
 func (a *AuthController) Login(c *fiber.Ctx) error {
 	req := new(validation.Login)
 
@@ -85,10 +91,13 @@ func (a *AuthController) Login(c *fiber.Ctx) error {
 	}
 
 	user, err := a.AuthService.Login(c, req)
+	metrics.RecordLogin(metrics.MethodPassword, err)
 	if err != nil {
 		return err
 	}
 
+	a.Billing.EnsureCustomer(user)
+
 	tokens, err := a.TokenService.GenerateAuthTokens(c, user)
 	if err != nil {
 		return err
@@ -99,7 +108,7 @@ func (a *AuthController) Login(c *fiber.Ctx) error {
 			Code:    fiber.StatusOK,
 			Status:  "success",
 			Message: "Login successfully",
-			User:    *user,
+			User:    response.NewUser(user),
 			Tokens:  *tokens,
 		})
 }
@@ -133,12 +142,14 @@ func (a *AuthController) Logout(c *fiber.Ctx) error {
 
 // @Tags         Auth
 // @Summary      Refresh auth tokens
+// @Description  The refresh token must come with the refresh_binding cookie set when it was issued. A token presented without it is treated as stolen: its session is revoked on every device and 401 returned.
 // @Accept       json
 // @Produce      json
 // @Param        request  body  example.RefreshToken  true  "Request body"
 // @Router       /auth/refresh-tokens [post]
 // @Success      200  {object}  example.RefreshTokenResponse
 // @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      423  {object}  example.AccountSuspended  "Account suspended"
 func (a *AuthController) RefreshTokens(c *fiber.Ctx) error {
 	req := new(validation.RefreshToken)
 
@@ -168,6 +179,7 @@ func (a *AuthController) RefreshTokens(c *fiber.Ctx) error {
 // @Router       /auth/forgot-password [post]
 // @Success      200  {object}  example.ForgotPasswordResponse
 // @Failure      404  {object}  example.NotFound  "Not found"
+// @Failure      429  {object}  example.EmailThrottled  "Too many emails sent to the address"
 func (a *AuthController) ForgotPassword(c *fiber.Ctx) error {
 	req := new(validation.ForgotPassword)
 
@@ -176,12 +188,16 @@ func (a *AuthController) ForgotPassword(c *fiber.Ctx) error {
 	}
 
 	resetPasswordToken, err := a.TokenService.GenerateResetPasswordToken(c, req)
+	if err == nil {
+		err = a.EmailService.SendResetPasswordEmail(c.UserContext(), req.Email, resetPasswordToken)
+	}
+	metrics.RecordPasswordReset(metrics.StageRequested, err)
 	if err != nil {
 		return err
 	}
 
-	if errEmail := a.EmailService.SendResetPasswordEmail(req.Email, resetPasswordToken); errEmail != nil {
-		return errEmail
+	if user, errUser := a.UserService.GetUserByEmail(c, req.Email); errUser == nil {
+		a.AuditService.Record(c, &user.ID, model.AuditActionEmailSent, map[string]any{"kind": "reset_password"})
 	}
 
 	return c.Status(fiber.StatusOK).
@@ -211,7 +227,9 @@ func (a *AuthController) ResetPassword(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	if err := a.AuthService.ResetPassword(c, query, req); err != nil {
+	err := a.AuthService.ResetPassword(c, query, req)
+	metrics.RecordPasswordReset(metrics.StageCompleted, err)
+	if err != nil {
 		return err
 	}
 
@@ -231,17 +249,23 @@ func (a *AuthController) ResetPassword(c *fiber.Ctx) error {
 // @Router       /auth/send-verification-email [post]
 // @Success      200  {object}  example.SendVerificationEmailResponse
 // @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      429  {object}  example.EmailThrottled  "Too many emails sent to the address"
 func (a *AuthController) SendVerificationEmail(c *fiber.Ctx) error {
 	user, _ := c.Locals("user").(*model.User)
+	if user.IsService() {
+		return fiber.NewError(fiber.StatusForbidden, "Service accounts cannot access this resource")
+	}
 
 	verifyEmailToken, err := a.TokenService.GenerateVerifyEmailToken(c, user)
+	if err == nil {
+		err = a.EmailService.SendVerificationEmail(c.UserContext(), user.Email, *verifyEmailToken)
+	}
+	metrics.RecordEmailVerification(metrics.StageRequested, err)
 	if err != nil {
 		return err
 	}
 
-	if errEmail := a.EmailService.SendVerificationEmail(user.Email, *verifyEmailToken); errEmail != nil {
-		return errEmail
-	}
+	a.AuditService.Record(c, &user.ID, model.AuditActionEmailSent, map[string]any{"kind": "verify_email"})
 
 	return c.Status(fiber.StatusOK).
 		JSON(response.Common{
@@ -253,17 +277,21 @@ func (a *AuthController) SendVerificationEmail(c *fiber.Ctx) error {
 
 // @Tags         Auth
 // @Summary      Verify email
+// @Description  Verification links are single-use and only valid for the email address they were sent to.
 // @Produce      json
 // @Param        token   query  string  true  "The verify email token"
 // @Router       /auth/verify-email [post]
 // @Success      200  {object}  example.VerifyEmailResponse
 // @Failure      401  {object}  example.FailedVerifyEmail  "Verify email failed"
+// @Failure      409  {object}  example.UsedVerifyEmail  "Verification link already used"
 func (a *AuthController) VerifyEmail(c *fiber.Ctx) error {
 	query := &validation.Token{
 		Token: c.Query("token"),
 	}
 
-	if err := a.AuthService.VerifyEmail(c, query); err != nil {
+	err := a.AuthService.VerifyEmail(c, query)
+	metrics.RecordEmailVerification(metrics.StageCompleted, err)
+	if err != nil {
 		return err
 	}
 
@@ -275,72 +303,110 @@ func (a *AuthController) VerifyEmail(c *fiber.Ctx) error {
 		})
 }
 
+// @Tags         Auth
+// @Summary      Confirm login
+// @Description  Approves a login that was held for email confirmation because it looked suspicious.
+// @Produce      json
+// @Param        token   query  string  true  "The confirm login token"
+// @Router       /auth/confirm-login [post]
+// @Success      200  {object}  example.LoginResponse
+// @Failure      401  {object}  example.FailedConfirmLogin  "Confirm login failed"
+func (a *AuthController) ConfirmLogin(c *fiber.Ctx) error {
+	query := &validation.Token{
+		Token: c.Query("token"),
+	}
+
+	user, err := a.AuthService.ConfirmLogin(c, query)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := a.TokenService.GenerateAuthTokens(c, user)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithTokens{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Login confirmed successfully",
+			User:    response.NewUser(user),
+			Tokens:  *tokens,
+		})
+}
+
 // @Tags         Auth
 // @Summary      Login with google
 // @Description  This route initiates the Google OAuth2 login flow. Please try this in your browser.
 // @Router       /auth/google [get]
 // @Success      200  {object}  example.GoogleLoginResponse
 func (a *AuthController) GoogleLogin(c *fiber.Ctx) error {
-	// Generate a random state
-	state := uuid.New().String()
-
-	c.Cookie(&fiber.Cookie{
-		Name:   "oauth_state",
-		Value:  state,
-		MaxAge: 30,
-	})
-
-	url := config.AppConfig.GoogleLoginConfig.AuthCodeURL(state)
+	url, err := a.GoogleOAuth.AuthURL(c)
+	if err != nil {
+		return err
+	}
 
 	return c.Status(fiber.StatusSeeOther).Redirect(url)
 }
 
 func (a *AuthController) GoogleCallback(c *fiber.Ctx) error {
-	state := c.Query("state")
-	storedState := c.Cookies("oauth_state")
-
-	if state != storedState {
-		return fiber.NewError(fiber.StatusUnauthorized, "States don't Match!")
-	}
-
-	code := c.Query("code")
-	googlecon := config.GoogleConfig()
-
-	token, err := googlecon.Exchange(context.Background(), code)
+	user, err := a.GoogleOAuth.Login(c)
+	metrics.RecordLogin(metrics.MethodGoogle, err)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(
-		c.Context(), http.MethodGet,
-		"https://www.googleapis.com/oauth2/v2/userinfo?access_token="+token.AccessToken,
-		nil,
-	)
-	if err != nil {
-		return err
-	}
+	a.AuditService.Record(c, &user.ID, model.AuditActionLoginSucceeded, map[string]any{"method": "google"})
+	a.Billing.EnsureCustomer(user)
 
-	resp, err := http.DefaultClient.Do(req)
+	tokens, err := a.TokenService.GenerateAuthTokens(c, user)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	userData, err := io.ReadAll(resp.Body)
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithTokens{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Login successfully",
+			User:    response.NewUser(user),
+			Tokens:  *tokens,
+		})
+
+	// TODO: replace this url with the link to the oauth google success page of your front-end app
+	// googleLoginURL := fmt.Sprintf("http://link-to-app/google/success?access_token=%s&refresh_token=%s",
+	// 	tokens.Access.Token, tokens.Refresh.Token)
+
+	// return c.Status(fiber.StatusSeeOther).Redirect(googleLoginURL)
+}
+
+// @Tags         Auth
+// @Summary      Login with Apple
+// @Description  This route initiates the Sign in with Apple flow. Please try this in your browser.
+// @Router       /auth/apple [get]
+// @Success      303
+// @Failure      404  {object}  example.NotFound  "Not configured"
+func (a *AuthController) AppleLogin(c *fiber.Ctx) error {
+	url, err := a.AppleOAuth.AuthURL(c)
 	if err != nil {
 		return err
 	}
 
-	googleUser := new(validation.GoogleLogin)
-	if errJSON := json.Unmarshal(userData, googleUser); errJSON != nil {
-		return errJSON
-	}
+	return c.Status(fiber.StatusSeeOther).Redirect(url)
+}
 
-	user, err := a.UserService.CreateGoogleUser(c, googleUser)
+// AppleCallback handles Apple's form_post redirect
+func (a *AuthController) AppleCallback(c *fiber.Ctx) error {
+	user, err := a.AppleOAuth.Login(c)
+	metrics.RecordLogin(metrics.MethodApple, err)
 	if err != nil {
 		return err
 	}
 
+	a.AuditService.Record(c, &user.ID, model.AuditActionLoginSucceeded, map[string]any{"method": "apple"})
+	a.Billing.EnsureCustomer(user)
+
 	tokens, err := a.TokenService.GenerateAuthTokens(c, user)
 	if err != nil {
 		return err
@@ -351,13 +417,7 @@ func (a *AuthController) GoogleCallback(c *fiber.Ctx) error {
 			Code:    fiber.StatusOK,
 			Status:  "success",
 			Message: "Login successfully",
-			User:    *user,
+			User:    response.NewUser(user),
 			Tokens:  *tokens,
 		})
-
-	// TODO: replace this url with the link to the oauth google success page of your front-end app
-	// googleLoginURL := fmt.Sprintf("http://link-to-app/google/success?access_token=%s&refresh_token=%s",
-	// 	tokens.Access.Token, tokens.Refresh.Token)
-
-	// return c.Status(fiber.StatusSeeOther).Redirect(googleLoginURL)
 }
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BillingController struct {
+	BillingService service.BillingService
+}
+
+func NewBillingController(billingService service.BillingService) *BillingController {
+	return &BillingController{
+		BillingService: billingService,
+	}
+}
+
+// @Tags         Billing
+// @Summary      Stripe webhook
+// @Description  Receives Stripe subscription events signed with STRIPE_WEBHOOK_SECRET. Redelivered events are acknowledged without being applied again.
+// @Accept       json
+// @Produce      json
+// @Param        Stripe-Signature  header  string  true  "Stripe webhook signature"
+// @Router       /billing/webhook [post]
+// @Success      200  {object}  example.BillingWebhookResponse
+// @Failure      400  {object}  example.InvalidWebhookSignature  "Invalid webhook signature"
+// @Failure      404  {object}  example.NotFound  "Not configured"
+func (b *BillingController) Webhook(c *fiber.Ctx) error {
+	if err := b.BillingService.HandleWebhook(c); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Webhook processed successfully",
+		})
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+	"encoding/csv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BulkActionController struct {
+	BulkActionService service.BulkActionService
+}
+
+func NewBulkActionController(bulkActionService service.BulkActionService) *BulkActionController {
+	return &BulkActionController{
+		BulkActionService: bulkActionService,
+	}
+}
+
+// @Tags         Users
+// @Summary      Apply an action to many users
+// @Description  Only admins can run bulk actions. The action (deactivate, delete, assign-role with a role, add-tag with a tag) targets the listed user_ids, or the users a filter selects like the query parameters of GET /users.
+// @Description  The targeted users are resolved at once and the action is queued; poll the returned job for progress and download its report once completed. Your own account is skipped, except by add-tag.
+// @Description  With dryRun=true nothing is queued; the response counts the targeted users.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        dryRun  query  bool  false  "Only report which users would be targeted"
+// @Param        request  body  validation.BulkUserAction  true  "Request body"
+// @Router       /admin/users/bulk [post]
+// @Success      202  {object}  example.SubmitBulkActionResponse
+// @Failure      400  {object}  example.InvalidBulkAction  "Invalid bulk action"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (bc *BulkActionController) SubmitBulkAction(c *fiber.Ctx) error {
+	req := new(validation.BulkUserAction)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	job, err := bc.BulkActionService.SubmitBulkAction(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).
+		JSON(response.SuccessWithBulkJob{
+			Code:    fiber.StatusAccepted,
+			Status:  "success",
+			Message: "Bulk action queued",
+			BulkJob: response.NewBulkJob(job),
+		})
+}
+
+// @Tags         Users
+// @Summary      Get the progress of a bulk action
+// @Description  Only admins can follow bulk actions. The job is queued, running or completed, with the users processed so far by outcome.
+// @Security BearerAuth
+// @Produce      json
+// @Param        jobId  path  string  true  "Bulk job id"
+// @Router       /admin/users/bulk/{jobId} [get]
+// @Success      200  {object}  example.GetBulkJobResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (bc *BulkActionController) GetBulkJob(c *fiber.Ctx) error {
+	job, err := bc.BulkActionService.GetBulkJob(c, utils.ParamID(c, "jobId"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithBulkJob{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get bulk job successfully",
+			BulkJob: response.NewBulkJob(job),
+		})
+}
+
+// @Tags         Users
+// @Summary      Download the report of a bulk action
+// @Description  Only admins can download bulk action reports. The CSV lists every targeted user with the outcome (pending, succeeded, skipped or failed) and the reason for skipped and failed ones.
+// @Security BearerAuth
+// @Produce      text/csv
+// @Param        jobId  path  string  true  "Bulk job id"
+// @Router       /admin/users/bulk/{jobId}/report [get]
+// @Success      200  {string}  string  "user_id,status,reason"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (bc *BulkActionController) GetBulkJobReport(c *fiber.Ctx) error {
+	jobID := utils.ParamID(c, "jobId")
+
+	items, err := bc.BulkActionService.GetBulkJobItems(c, jobID)
+	if err != nil {
+		return err
+	}
+
+	c.Attachment("bulk-job-" + jobID + ".csv")
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+
+	report := csv.NewWriter(c.Response().BodyWriter())
+	_ = report.Write([]string{"user_id", "status", "reason"})
+	for _, item := range items {
+		_ = report.Write([]string{item.UserID.String(), item.Status, item.Reason})
+	}
+	report.Flush()
+
+	return report.Error()
+}
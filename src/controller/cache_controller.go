@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CacheController struct {
+	CacheService service.CacheService
+}
+
+func NewCacheController(cacheService service.CacheService) *CacheController {
+	return &CacheController{
+		CacheService: cacheService,
+	}
+}
+
+// @Tags         Cache
+// @Summary      Get cache statistics
+// @Description  Only admins can inspect key counts, hit/miss ratio and Redis memory usage.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/cache/stats [get]
+// @Success      200  {object}  example.CacheStatsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (cc *CacheController) GetStats(c *fiber.Ctx) error {
+	stats, err := cc.CacheService.GetStats(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithCacheStats{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get cache stats successfully",
+			Stats:   *stats,
+		})
+}
+
+// @Tags         Cache
+// @Summary      Purge cache
+// @Description  Only admins can purge cache keys by pattern or tag. Patterns must start with api:response:, session:user: or negative:. Purges are audited as cache.purged. With dryRun=true the keys that would be purged are counted and sampled instead.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body   validation.PurgeCache  true   "Request body"
+// @Param        dryRun   query  bool                   false  "Only report what would be purged"
+// @Router       /admin/cache/purge [post]
+// @Success      200  {object}  example.PurgeCacheResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (cc *CacheController) Purge(c *fiber.Ctx) error {
+	req := new(validation.PurgeCache)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	purged, err := cc.CacheService.Purge(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPurged{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: fmt.Sprintf("Purged %d cache keys", purged),
+			Purged:  purged,
+		})
+}
+
+// @Tags         Cache
+// @Summary      Dry-run a cache purge
+// @Description  Only admins can preview which keys a purge by pattern or tag would delete. Patterns must start with api:response:, session:user: or negative:. Nothing is deleted.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.PurgeCache  true  "Request body"
+// @Router       /admin/cache/purge/dry-run [post]
+// @Success      200  {object}  example.DryRunPurgeCacheResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (cc *CacheController) DryRunPurge(c *fiber.Ctx) error {
+	req := new(validation.PurgeCache)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	invalidation, err := cc.CacheService.DryRunPurge(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithCacheInvalidation{
+			Code:         fiber.StatusOK,
+			Status:       "success",
+			Message:      fmt.Sprintf("Dry run matched %d cache keys", invalidation.Matched),
+			Invalidation: *invalidation,
+		})
+}
+
+// @Tags         Cache
+// @Summary      Enable or disable the response cache
+// @Description  Only admins can toggle the response cache at runtime. The switch is kept in the cache store, and other instances follow it within RESPONSE_CACHE_SYNC_INTERVAL seconds.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.CacheState  true  "Request body"
+// @Router       /admin/cache/state [put]
+// @Success      200  {object}  example.CacheStateResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (cc *CacheController) SetState(c *fiber.Ctx) error {
+	req := new(validation.CacheState)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := cc.CacheService.SetEnabled(c, req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Update cache state successfully",
+		})
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CacheSkipRuleController struct {
+	CacheSkipRuleService service.CacheSkipRuleService
+}
+
+func NewCacheSkipRuleController(cacheSkipRuleService service.CacheSkipRuleService) *CacheSkipRuleController {
+	return &CacheSkipRuleController{
+		CacheSkipRuleService: cacheSkipRuleService,
+	}
+}
+
+// @Tags         Cache
+// @Summary      List cache skip rules
+// @Description  Only admins can list the rules keeping requests out of the response cache. Rules from the environment have source "config" and cannot be deleted here.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/cache/skip-rules [get]
+// @Success      200  {object}  example.GetCacheSkipRulesResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (cc *CacheSkipRuleController) GetRules(c *fiber.Ctx) error {
+	rules, err := cc.CacheSkipRuleService.ListRules(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithCacheSkipRules{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get cache skip rules successfully",
+			Rules:   rules,
+		})
+}
+
+// @Tags         Cache
+// @Summary      Add a cache skip rule
+// @Description  Only admins can add rules. A rule skips a path prefix (kind "path"), paths matching a regular expression ("pattern"), a method ("method") or requests carrying a header, by name or name=value ("header"). Other instances apply the rule within a minute.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.CreateCacheSkipRule  true  "Request body"
+// @Router       /admin/cache/skip-rules [post]
+// @Success      201  {object}  example.CreateCacheSkipRuleResponse
+// @Failure      400  {object}  example.InvalidCacheSkipRule  "Invalid rule"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      409  {object}  example.DuplicateCacheSkipRule  "Rule already exists"
+func (cc *CacheSkipRuleController) CreateRule(c *fiber.Ctx) error {
+	req := new(validation.CreateCacheSkipRule)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	rule, err := cc.CacheSkipRuleService.CreateRule(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithCacheSkipRule{
+			Code:    fiber.StatusCreated,
+			Status:  "success",
+			Message: "Create cache skip rule successfully",
+			Rule:    response.NewCacheSkipRule(rule),
+		})
+}
+
+// @Tags         Cache
+// @Summary      Delete a cache skip rule
+// @Description  Only admins can delete rules they added through the API.
+// @Security BearerAuth
+// @Produce      json
+// @Param        ruleId  path  string  true  "Rule id"
+// @Router       /admin/cache/skip-rules/{ruleId} [delete]
+// @Success      200  {object}  example.DeleteCacheSkipRuleResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (cc *CacheSkipRuleController) DeleteRule(c *fiber.Ctx) error {
+	if err := cc.CacheSkipRuleService.DeleteRule(c, utils.ParamID(c, "ruleId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Delete cache skip rule successfully",
+		})
+}
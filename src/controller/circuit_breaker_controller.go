@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CircuitBreakerController struct {
+	CircuitBreakerService service.CircuitBreakerService
+}
+
+func NewCircuitBreakerController(circuitBreakerService service.CircuitBreakerService) *CircuitBreakerController {
+	return &CircuitBreakerController{
+		CircuitBreakerService: circuitBreakerService,
+	}
+}
+
+// @Tags         Circuit Breaker
+// @Summary      Get Redis circuit breaker status
+// @Description  Only admins can inspect the breaker state, counts and recent transitions.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/circuit-breaker [get]
+// @Success      200  {object}  example.CircuitBreakerResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (cb *CircuitBreakerController) GetStatus(c *fiber.Ctx) error {
+	status, err := cb.CircuitBreakerService.GetStatus(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithCircuitBreaker{
+			Code:           fiber.StatusOK,
+			Status:         "success",
+			Message:        "Get circuit breaker status successfully",
+			CircuitBreaker: *status,
+		})
+}
+
+// @Tags         Circuit Breaker
+// @Summary      Trip the Redis circuit breaker
+// @Description  Only admins can force the breaker open; Redis calls fall back to the database until reset.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/circuit-breaker/trip [post]
+// @Success      200  {object}  example.CircuitBreakerResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (cb *CircuitBreakerController) Trip(c *fiber.Ctx) error {
+	status, err := cb.CircuitBreakerService.Trip(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithCircuitBreaker{
+			Code:           fiber.StatusOK,
+			Status:         "success",
+			Message:        "Circuit breaker tripped successfully",
+			CircuitBreaker: *status,
+		})
+}
+
+// @Tags         Circuit Breaker
+// @Summary      Reset the Redis circuit breaker
+// @Description  Only admins can clear a manual trip and close the breaker with fresh counts.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/circuit-breaker/reset [post]
+// @Success      200  {object}  example.CircuitBreakerResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (cb *CircuitBreakerController) Reset(c *fiber.Ctx) error {
+	status, err := cb.CircuitBreakerService.Reset(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithCircuitBreaker{
+			Code:           fiber.StatusOK,
+			Status:         "success",
+			Message:        "Circuit breaker reset successfully",
+			CircuitBreaker: *status,
+		})
+}
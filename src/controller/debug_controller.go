@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DebugController struct {
+	DebugService service.DebugService
+}
+
+func NewDebugController(debugService service.DebugService) *DebugController {
+	return &DebugController{
+		DebugService: debugService,
+	}
+}
+
+// @Tags         Debug
+// @Summary      Get a captured request
+// @Description  Only admins can read sanitized request/response bodies captured by sampling or the X-Debug-Capture header.
+// @Security BearerAuth
+// @Produce      json
+// @Param        requestId  path  string  true  "Request ID (X-Request-ID response header)"
+// @Router       /admin/debug/captures/{requestId} [get]
+// @Success      200  {object}  example.DebugCaptureResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (dc *DebugController) GetCapture(c *fiber.Ctx) error {
+	capture, err := dc.DebugService.GetCapture(c, c.Params("requestId"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithDebugCapture{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get debug capture successfully",
+			Capture: *capture,
+		})
+}
+
+// @Tags         Debug
+// @Summary      Trace a request
+// @Description  Only admins can look up a request ID, as quoted by a user from an error response or an email's X-Request-ID header. Returns the request's audit entries, its emails still in the outbox and its capture, if any. Log lines carry the same ID.
+// @Security BearerAuth
+// @Produce      json
+// @Param        requestId  path  string  true  "Request ID (X-Request-ID response header)"
+// @Router       /admin/debug/requests/{requestId} [get]
+// @Success      200  {object}  example.RequestTraceResponse
+// @Failure      400  {object}  example.InvalidRequestID  "Invalid request ID"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (dc *DebugController) GetRequestTrace(c *fiber.Ctx) error {
+	trace, err := dc.DebugService.GetRequestTrace(c, c.Params("requestId"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithRequestTrace{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get request trace successfully",
+			Trace:   *trace,
+		})
+}
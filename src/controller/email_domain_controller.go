@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type EmailDomainController struct {
+	EmailDomainService service.EmailDomainService
+}
+
+func NewEmailDomainController(emailDomainService service.EmailDomainService) *EmailDomainController {
+	return &EmailDomainController{
+		EmailDomainService: emailDomainService,
+	}
+}
+
+// @Tags         Email Domains
+// @Summary      List email domain rules
+// @Description  Only admins can list the rules applied to sign-up emails. Rules from the environment have source "config" and cannot be deleted here.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/email-domain-rules [get]
+// @Success      200  {object}  example.GetEmailDomainRulesResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (e *EmailDomainController) GetRules(c *fiber.Ctx) error {
+	rules, err := e.EmailDomainService.ListRules(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithEmailDomainRules{
+			Code:             fiber.StatusOK,
+			Status:           "success",
+			Message:          "Get email domain rules successfully",
+			EmailDomainRules: *rules,
+		})
+}
+
+// @Tags         Email Domains
+// @Summary      Add an email domain rule
+// @Description  Only admins can add rules. A pattern is a domain ("example.com") or its subdomains ("*.example.com"). Other instances apply the rule within a minute.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.CreateEmailDomainRule  true  "Request body"
+// @Router       /admin/email-domain-rules [post]
+// @Success      201  {object}  example.CreateEmailDomainRuleResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (e *EmailDomainController) CreateRule(c *fiber.Ctx) error {
+	req := new(validation.CreateEmailDomainRule)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	rule, err := e.EmailDomainService.CreateRule(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithEmailDomainRule{
+			Code:    fiber.StatusCreated,
+			Status:  "success",
+			Message: "Create email domain rule successfully",
+			Rule:    response.NewEmailDomainRule(rule),
+		})
+}
+
+// @Tags         Email Domains
+// @Summary      Delete an email domain rule
+// @Description  Only admins can delete rules they added through the API.
+// @Security BearerAuth
+// @Produce      json
+// @Param        ruleId  path  string  true  "Rule id"
+// @Router       /admin/email-domain-rules/{ruleId} [delete]
+// @Success      200  {object}  example.DeleteEmailDomainRuleResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (e *EmailDomainController) DeleteRule(c *fiber.Ctx) error {
+	if err := e.EmailDomainService.DeleteRule(c, utils.ParamID(c, "ruleId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Delete email domain rule successfully",
+		})
+}
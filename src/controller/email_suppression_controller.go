@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type EmailSuppressionController struct {
+	EmailSuppressionService service.EmailSuppressionService
+}
+
+func NewEmailSuppressionController(emailSuppressionService service.EmailSuppressionService) *EmailSuppressionController {
+	return &EmailSuppressionController{
+		EmailSuppressionService: emailSuppressionService,
+	}
+}
+
+// @Tags         Email Suppressions
+// @Summary      List suppressed email recipients
+// @Description  Only admins can list the addresses no email is sent to, newest first.
+// @Security BearerAuth
+// @Produce      json
+// @Param        page    query  int     false  "Page number"  default(1)
+// @Param        limit   query  int     false  "Maximum number of suppressions"  default(20)
+// @Param        search  query  string  false  "Part of the address"
+// @Param        reason  query  string  false  "Only this reason (hard_bounce, complaint, unsubscribe, manual)"
+// @Router       /admin/email/suppressions [get]
+// @Success      200  {object}  example.GetEmailSuppressionsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (e *EmailSuppressionController) GetSuppressions(c *fiber.Ctx) error {
+	query := &validation.QueryEmailSuppression{
+		Page:   c.QueryInt("page", 1),
+		Limit:  c.QueryInt("limit", 20),
+		Search: c.Query("search"),
+		Reason: c.Query("reason"),
+	}
+
+	suppressions, totalResults, err := e.EmailSuppressionService.ListSuppressions(c, query)
+	if err != nil {
+		return err
+	}
+
+	results := make([]response.EmailSuppression, len(suppressions))
+	for i := range suppressions {
+		results[i] = response.NewEmailSuppression(&suppressions[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPaginate[response.EmailSuppression]{
+			Code:         fiber.StatusOK,
+			Status:       "success",
+			Message:      "Get email suppressions successfully",
+			Results:      results,
+			Page:         query.Page,
+			Limit:        query.Limit,
+			TotalPages:   int64(math.Ceil(float64(totalResults) / float64(query.Limit))),
+			TotalResults: totalResults,
+		})
+}
+
+// @Tags         Email Suppressions
+// @Summary      Suppress an email recipient
+// @Description  Only admins can stop all email to an address, such as at the owner's request.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.CreateEmailSuppression  true  "Request body"
+// @Router       /admin/email/suppressions [post]
+// @Success      201  {object}  example.CreateEmailSuppressionResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      409  {object}  example.DuplicateSuppression  "Email is already suppressed"
+func (e *EmailSuppressionController) CreateSuppression(c *fiber.Ctx) error {
+	req := new(validation.CreateEmailSuppression)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	suppression, err := e.EmailSuppressionService.CreateSuppression(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithEmailSuppression{
+			Code:        fiber.StatusCreated,
+			Status:      "success",
+			Message:     "Create email suppression successfully",
+			Suppression: response.NewEmailSuppression(suppression),
+		})
+}
+
+// @Tags         Email Suppressions
+// @Summary      Delete an email suppression
+// @Description  Only admins can let email reach a suppressed address again, such as once a bounced mailbox was fixed.
+// @Security BearerAuth
+// @Produce      json
+// @Param        suppressionId  path  string  true  "Suppression id"
+// @Router       /admin/email/suppressions/{suppressionId} [delete]
+// @Success      200  {object}  example.DeleteEmailSuppressionResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (e *EmailSuppressionController) DeleteSuppression(c *fiber.Ctx) error {
+	if err := e.EmailSuppressionService.DeleteSuppression(c, utils.ParamID(c, "suppressionId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Delete email suppression successfully",
+		})
+}
+
+// @Tags         Email Suppressions
+// @Summary      Email provider feedback webhook
+// @Description  Receives bounce, complaint and unsubscribe notifications from Amazon SES (through SNS) or the SendGrid Event Webhook and suppresses the reported recipients. The URL registered with the provider must carry EMAIL_WEBHOOK_SECRET as the token query parameter.
+// @Accept       json
+// @Produce      json
+// @Param        provider  path   string  true  "ses or sendgrid"
+// @Param        token     query  string  true  "EMAIL_WEBHOOK_SECRET"
+// @Router       /email/webhooks/{provider} [post]
+// @Success      200  {object}  example.EmailWebhookResponse
+// @Failure      400  {object}  example.InvalidEmailWebhook  "Invalid webhook payload"
+// @Failure      401  {object}  example.Unauthorized  "Invalid webhook token"
+// @Failure      404  {object}  example.NotFound  "Not configured or unknown provider"
+func (e *EmailSuppressionController) Webhook(c *fiber.Ctx) error {
+	if err := e.EmailSuppressionService.HandleWebhook(c, c.Params("provider")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Webhook processed successfully",
+		})
+}
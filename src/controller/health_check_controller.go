@@ -87,3 +87,35 @@ func (h *HealthCheckController) Check(c *fiber.Ctx) error {
 		Result:    serviceList,
 	})
 }
+
+// @Tags Health
+// @Summary Readiness probe
+// @Description Check whether this instance can serve traffic, whether it is the background worker leader, and database pool usage
+// @Produce json
+// @Success 200 {object} example.ReadinessResponse
+// @Failure 503 {object} example.ReadinessResponse
+// @Router /readyz [get]
+func (h *HealthCheckController) Ready(c *fiber.Ctx) error {
+	statusCode := fiber.StatusOK
+	status := "success"
+	message := "Service is ready"
+	isReady := h.HealthCheckService.GormCheck() == nil
+
+	if !isReady {
+		statusCode = fiber.StatusServiceUnavailable
+		status = "error"
+		message = "Service is not ready"
+	}
+
+	// Pool stats are informational; a failure here does not affect readiness
+	dbPool, _ := h.HealthCheckService.DBPoolStats()
+
+	return c.Status(statusCode).JSON(response.ReadinessResponse{
+		Code:     statusCode,
+		Status:   status,
+		Message:  message,
+		IsReady:  isReady,
+		IsLeader: h.HealthCheckService.IsLeader(),
+		DBPool:   dbPool,
+	})
+}
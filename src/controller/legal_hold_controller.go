@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LegalHoldController struct {
+	LegalHoldService service.LegalHoldService
+}
+
+func NewLegalHoldController(legalHoldService service.LegalHoldService) *LegalHoldController {
+	return &LegalHoldController{
+		LegalHoldService: legalHoldService,
+	}
+}
+
+// @Tags         Legal Holds
+// @Summary      List legal holds
+// @Description  Only admins can list the users under legal hold, newest hold first.
+// @Security BearerAuth
+// @Produce      json
+// @Param        page   query  int  false  "Page number"  default(1)
+// @Param        limit  query  int  false  "Maximum number of holds"  default(20)
+// @Router       /admin/legal-holds [get]
+// @Success      200  {object}  example.GetLegalHoldsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (lc *LegalHoldController) GetLegalHolds(c *fiber.Ctx) error {
+	query := &validation.QueryLegalHold{
+		Page:  c.QueryInt("page", 1),
+		Limit: c.QueryInt("limit", 20),
+	}
+
+	holds, totalResults, err := lc.LegalHoldService.ListLegalHolds(c, query)
+	if err != nil {
+		return err
+	}
+
+	results := make([]response.LegalHold, len(holds))
+	for i := range holds {
+		results[i] = response.NewLegalHold(&holds[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPaginate[response.LegalHold]{
+			Code:         fiber.StatusOK,
+			Status:       "success",
+			Message:      "Get legal holds successfully",
+			Results:      results,
+			Page:         query.Page,
+			Limit:        query.Limit,
+			TotalPages:   int64(math.Ceil(float64(totalResults) / float64(query.Limit))),
+			TotalResults: totalResults,
+		})
+}
+
+// @Tags         Legal Holds
+// @Summary      Place a legal hold on a user
+// @Description  Only admins can place a legal hold. Until it is lifted the user cannot be deleted, by themselves, an admin or a bulk action, and retention purges keep their audit entries, login history and notifications.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.CreateLegalHold  true  "Request body"
+// @Router       /admin/legal-holds [post]
+// @Success      201  {object}  example.PlaceLegalHoldResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+// @Failure      409  {object}  example.DuplicateLegalHold  "User is already under legal hold"
+func (lc *LegalHoldController) PlaceLegalHold(c *fiber.Ctx) error {
+	req := new(validation.CreateLegalHold)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	hold, err := lc.LegalHoldService.PlaceLegalHold(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithLegalHold{
+			Code:      fiber.StatusCreated,
+			Status:    "success",
+			Message:   "Place legal hold successfully",
+			LegalHold: response.NewLegalHold(hold),
+		})
+}
+
+// @Tags         Legal Holds
+// @Summary      Lift a legal hold
+// @Description  Only admins can lift a legal hold, letting the user be deleted again. Their data past its retention goes with the next purge.
+// @Security BearerAuth
+// @Produce      json
+// @Param        userId  path  string  true  "User id"
+// @Router       /admin/legal-holds/{userId} [delete]
+// @Success      200  {object}  example.LiftLegalHoldResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (lc *LegalHoldController) LiftLegalHold(c *fiber.Ctx) error {
+	if err := lc.LegalHoldService.LiftLegalHold(c, utils.ParamID(c, "userId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Lift legal hold successfully",
+		})
+}
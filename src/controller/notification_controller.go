@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationController struct {
+	NotificationService service.NotificationService
+}
+
+func NewNotificationController(notificationService service.NotificationService) *NotificationController {
+	return &NotificationController{
+		NotificationService: notificationService,
+	}
+}
+
+// @Tags         Notifications
+// @Summary      Get notification settings
+// @Description  Returns the verified phone number, the channels this deployment can deliver on and the channels chosen for each kind of notification.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /users/me/notifications [get]
+// @Success      200  {object}  example.GetNotificationSettingsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+func (n *NotificationController) GetSettings(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+
+	settings, err := n.NotificationService.GetSettings(c, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithNotificationSettings{
+			Code:     fiber.StatusOK,
+			Status:   "success",
+			Message:  "Get notification settings successfully",
+			Settings: *settings,
+		})
+}
+
+// @Tags         Notifications
+// @Summary      Choose notification channels
+// @Description  Sets the channels (email, sms, whatsapp, push) one kind of notification is sent on. Text channels need a verified phone number and push a registered device. When none of the chosen channels can be used, sign-in confirmations go by email and the other kinds are not sent; only sign-in confirmations cannot be turned off with an empty list.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        kind     path  string                                   true  "Notification kind"  Enums(confirm_login, new_login, password_changed)
+// @Param        request  body  validation.UpdateNotificationPreference  true  "Request body"
+// @Router       /users/me/notifications/preferences/{kind} [put]
+// @Success      200  {object}  example.UpdateNotificationPreferenceResponse
+// @Failure      400  {object}  example.ChannelUnavailable  "Channel unavailable"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (n *NotificationController) UpdatePreference(c *fiber.Ctx) error {
+	req := new(validation.UpdateNotificationPreference)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, _ := c.Locals("user").(*model.User)
+
+	preference, err := n.NotificationService.UpdatePreference(c, user.ID, c.Params("kind"), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithNotificationPreference{
+			Code:       fiber.StatusOK,
+			Status:     "success",
+			Message:    "Update notification preference successfully",
+			Preference: *preference,
+		})
+}
+
+// @Tags         Notifications
+// @Summary      Add or change the phone number
+// @Description  Sends a six-digit verification code to the number by SMS or WhatsApp. The number replaces the current one once verified.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.SetPhone  true  "Request body"
+// @Router       /users/me/notifications/phone [put]
+// @Success      202  {object}  example.SetPhoneResponse
+// @Failure      400  {object}  example.ChannelUnavailable  "Channel unavailable"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      429  {object}  example.TooManyRequests  "Too many requests"
+func (n *NotificationController) SetPhone(c *fiber.Ctx) error {
+	req := new(validation.SetPhone)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, _ := c.Locals("user").(*model.User)
+
+	if err := n.NotificationService.SetPhone(c, user.ID, req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).
+		JSON(response.Common{
+			Code:    fiber.StatusAccepted,
+			Status:  "success",
+			Message: "Verification code sent",
+		})
+}
+
+// @Tags         Notifications
+// @Summary      Verify the phone number
+// @Description  Confirms the code sent to the new phone number and saves it. After five wrong codes a new one must be requested.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.VerifyPhone  true  "Request body"
+// @Router       /users/me/notifications/phone/verify [post]
+// @Success      200  {object}  example.VerifyPhoneResponse
+// @Failure      400  {object}  example.InvalidPhoneCode  "Invalid code"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+func (n *NotificationController) VerifyPhone(c *fiber.Ctx) error {
+	req := new(validation.VerifyPhone)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, _ := c.Locals("user").(*model.User)
+
+	if err := n.NotificationService.VerifyPhone(c, user.ID, req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Verify phone successfully",
+		})
+}
+
+// @Tags         Notifications
+// @Summary      Remove the phone number
+// @Description  Text channels stay in the preferences but are skipped until a phone number is verified again.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /users/me/notifications/phone [delete]
+// @Success      200  {object}  example.RemovePhoneResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (n *NotificationController) RemovePhone(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+
+	if err := n.NotificationService.RemovePhone(c, user.ID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Remove phone successfully",
+		})
+}
+
+// @Tags         Notifications
+// @Summary      Text message status callback
+// @Description  Receives delivery-status callbacks from Twilio (signed with X-Twilio-Signature against SMS_STATUS_CALLBACK_URL) or Vonage (a JWT signed with VONAGE_SIGNATURE_SECRET). Delivered and failed messages appear in the user's activity.
+// @Accept       json,x-www-form-urlencoded
+// @Produce      json
+// @Param        provider  path  string  true  "twilio or vonage"
+// @Router       /notifications/callbacks/{provider} [post]
+// @Success      200  {object}  example.StatusCallbackResponse
+// @Failure      400  {object}  example.InvalidStatusCallback  "Invalid callback payload"
+// @Failure      401  {object}  example.InvalidCallbackSignature  "Invalid callback signature"
+// @Failure      404  {object}  example.NotFound  "Not configured or unknown provider"
+func (n *NotificationController) StatusCallback(c *fiber.Ctx) error {
+	if err := n.NotificationService.HandleStatusCallback(c, c.Params("provider")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Callback processed successfully",
+		})
+}
+
+// @Tags         Notifications
+// @Summary      List push devices
+// @Description  Returns the devices registered for push notifications, most recently registered first.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /users/me/notifications/devices [get]
+// @Success      200  {object}  example.GetPushDevicesResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+func (n *NotificationController) GetPushDevices(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+
+	devices, err := n.NotificationService.GetPushDevices(c, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPushDevices{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get push devices successfully",
+			Devices: devices,
+		})
+}
+
+// @Tags         Notifications
+// @Summary      Register a push device
+// @Description  Registers the FCM registration token or APNs device token of an app install. Apps should call this on every launch; registering a known token refreshes it, or moves it to the caller if another account registered it. Only the most recently registered devices are kept (PUSH_MAX_DEVICES).
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.RegisterPushDevice  true  "Request body"
+// @Router       /users/me/notifications/devices [post]
+// @Success      200  {object}  example.RegisterPushDeviceResponse
+// @Failure      400  {object}  example.PlatformUnavailable  "Platform unavailable"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+func (n *NotificationController) RegisterPushDevice(c *fiber.Ctx) error {
+	req := new(validation.RegisterPushDevice)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, _ := c.Locals("user").(*model.User)
+
+	device, err := n.NotificationService.RegisterPushDevice(c, user.ID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPushDevice{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Register push device successfully",
+			Device:  *device,
+		})
+}
+
+// @Tags         Notifications
+// @Summary      Remove a push device
+// @Description  Stops push notifications to the device, e.g. when the user signs out of the app.
+// @Security BearerAuth
+// @Produce      json
+// @Param        deviceId  path  string  true  "Device ID"
+// @Router       /users/me/notifications/devices/{deviceId} [delete]
+// @Success      200  {object}  example.RemovePushDeviceResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (n *NotificationController) RemovePushDevice(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+
+	if err := n.NotificationService.RemovePushDevice(c, user.ID, c.Params("deviceId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Remove push device successfully",
+		})
+}
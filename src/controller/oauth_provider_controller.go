@@ -0,0 +1,318 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OAuthProviderController struct {
+	OAuthProviderService service.OAuthProviderService
+}
+
+func NewOAuthProviderController(oauthProviderService service.OAuthProviderService) *OAuthProviderController {
+	return &OAuthProviderController{
+		OAuthProviderService: oauthProviderService,
+	}
+}
+
+// @Tags         OAuth Clients
+// @Summary      List OAuth clients
+// @Description  Only admins can list the third-party clients users can grant access to.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/oauth/clients [get]
+// @Success      200  {object}  example.GetOAuthClientsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (oc *OAuthProviderController) GetClients(c *fiber.Ctx) error {
+	clients, err := oc.OAuthProviderService.ListClients(c)
+	if err != nil {
+		return err
+	}
+
+	list := make([]response.OAuthClient, len(clients))
+	for i := range clients {
+		list[i] = response.NewOAuthClient(&clients[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithOAuthClients{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get OAuth clients successfully",
+			Clients: list,
+		})
+}
+
+// @Tags         OAuth Clients
+// @Summary      Register an OAuth client
+// @Description  Only admins can register third-party clients, with the redirect URIs and scopes they may use. The response holds the client secret, which cannot be retrieved again.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.CreateOAuthClient  true  "Request body"
+// @Router       /admin/oauth/clients [post]
+// @Success      201  {object}  example.CreateOAuthClientResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (oc *OAuthProviderController) CreateClient(c *fiber.Ctx) error {
+	req := new(validation.CreateOAuthClient)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	client, secret, err := oc.OAuthProviderService.CreateClient(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithOAuthClient{
+			Code:         fiber.StatusCreated,
+			Status:       "success",
+			Message:      "Create OAuth client successfully",
+			Client:       response.NewOAuthClient(client),
+			ClientSecret: secret,
+		})
+}
+
+// @Tags         OAuth Clients
+// @Summary      Delete an OAuth client
+// @Description  Only admins can delete third-party clients; the consents given to them and their tokens are deleted too.
+// @Security BearerAuth
+// @Produce      json
+// @Param        clientId  path  string  true  "Client id"
+// @Router       /admin/oauth/clients/{clientId} [delete]
+// @Success      200  {object}  example.DeleteOAuthClientResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (oc *OAuthProviderController) DeleteClient(c *fiber.Ctx) error {
+	if err := oc.OAuthProviderService.DeleteClient(c, utils.ParamID(c, "clientId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Delete OAuth client successfully",
+		})
+}
+
+// @Tags         OAuth
+// @Summary      Describe an authorization request
+// @Description  The consent screen passes on the query of the client's redirect and shows the client and the requested scopes; consented is true when the user already granted them all.
+// @Security BearerAuth
+// @Produce      json
+// @Param        response_type          query  string  true   "Must be code"
+// @Param        client_id              query  string  true   "Client id"
+// @Param        redirect_uri           query  string  true   "A redirect URI registered for the client"
+// @Param        scope                  query  string  true   "Space-separated scopes"
+// @Param        state                  query  string  false  "Opaque value returned to the client"
+// @Param        code_challenge         query  string  false  "PKCE challenge"
+// @Param        code_challenge_method  query  string  false  "Must be S256"
+// @Router       /oauth/authorize [get]
+// @Success      200  {object}  example.GetOAuthAuthorizationResponse
+// @Failure      400  {object}  example.InvalidAuthorizationRequest  "Unknown client, redirect URI or scope"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (oc *OAuthProviderController) GetAuthorization(c *fiber.Ctx) error {
+	req := new(validation.OAuthAuthorize)
+
+	if err := c.QueryParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query")
+	}
+
+	user, _ := c.Locals("user").(*model.User)
+
+	authorization, err := oc.OAuthProviderService.DescribeAuthorization(c, user, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithOAuthAuthorization{
+			Code:          fiber.StatusOK,
+			Status:        "success",
+			Message:       "Get authorization request successfully",
+			Authorization: *authorization,
+		})
+}
+
+// @Tags         OAuth
+// @Summary      Answer an authorization request
+// @Description  Records the user's consent and returns the client redirect URI to send the browser to, with a single-use code, or with error=access_denied when approve is false.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.OAuthConsent  true  "Request body"
+// @Router       /oauth/authorize [post]
+// @Success      200  {object}  example.OAuthRedirectResponse
+// @Failure      400  {object}  example.InvalidAuthorizationRequest  "Unknown client, redirect URI or scope"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (oc *OAuthProviderController) Authorize(c *fiber.Ctx) error {
+	req := new(validation.OAuthConsent)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, _ := c.Locals("user").(*model.User)
+
+	redirectURI, err := oc.OAuthProviderService.Authorize(c, user, req)
+	if err != nil {
+		return err
+	}
+
+	message := "Authorization granted"
+	if !req.Approve {
+		message = "Authorization denied"
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithOAuthRedirect{
+			Code:        fiber.StatusOK,
+			Status:      "success",
+			Message:     message,
+			RedirectURI: redirectURI,
+		})
+}
+
+// @Tags         OAuth
+// @Summary      Exchange an authorization code
+// @Description  The token endpoint of the authorization-code grant (RFC 6749). Clients authenticate with HTTP Basic or client_id and client_secret in the form; code_verifier is required when the authorization request had a code_challenge. Errors use the RFC format.
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        grant_type     formData  string  true   "Must be authorization_code"
+// @Param        code           formData  string  true   "Authorization code"
+// @Param        redirect_uri   formData  string  true   "The redirect URI of the authorization request"
+// @Param        client_id      formData  string  false  "Client id, unless sent with HTTP Basic"
+// @Param        client_secret  formData  string  false  "Client secret, unless sent with HTTP Basic"
+// @Param        code_verifier  formData  string  false  "PKCE verifier"
+// @Router       /oauth/token [post]
+// @Success      200  {object}  example.OAuthTokenResponse
+// @Failure      400  {object}  example.OAuthTokenError  "Invalid request or grant"
+// @Failure      401  {object}  example.OAuthTokenError  "Client authentication failed"
+func (oc *OAuthProviderController) Token(c *fiber.Ctx) error {
+	req := new(validation.OAuthToken)
+
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(response.OAuthError{Error: "invalid_request", ErrorDescription: "Invalid request body"})
+	}
+	if clientID, secret, ok := basicClientCredentials(c); ok {
+		req.ClientID, req.ClientSecret = clientID, secret
+	}
+
+	token, err := oc.OAuthProviderService.Exchange(c, req)
+
+	var oauthErr *service.OAuthError
+	if errors.As(err, &oauthErr) {
+		return c.Status(oauthErr.Status).
+			JSON(response.OAuthError{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.OAuthToken{
+			AccessToken: token.AccessToken,
+			TokenType:   token.TokenType,
+			ExpiresIn:   token.ExpiresIn,
+			Scope:       token.Scope,
+		})
+}
+
+// @Tags         OAuth
+// @Summary      List OAuth consents
+// @Description  Lists the third-party clients the user granted access to, with the granted scopes.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /users/me/oauth/consents [get]
+// @Success      200  {object}  example.GetOAuthConsentsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (oc *OAuthProviderController) GetConsents(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+
+	consents, err := oc.OAuthProviderService.ListConsents(c, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	list := make([]response.OAuthConsent, len(consents))
+	for i := range consents {
+		list[i] = response.NewOAuthConsent(&consents[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithOAuthConsents{
+			Code:     fiber.StatusOK,
+			Status:   "success",
+			Message:  "Get OAuth consents successfully",
+			Consents: list,
+		})
+}
+
+// @Tags         OAuth
+// @Summary      Revoke an OAuth consent
+// @Description  Withdraws the access granted to a client; its tokens stop working at once.
+// @Security BearerAuth
+// @Produce      json
+// @Param        clientId  path  string  true  "Client id"
+// @Router       /users/me/oauth/consents/{clientId} [delete]
+// @Success      200  {object}  example.RevokeOAuthConsentResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (oc *OAuthProviderController) RevokeConsent(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+
+	if err := oc.OAuthProviderService.RevokeConsent(c, user.ID.String(), utils.ParamID(c, "clientId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Revoke OAuth consent successfully",
+		})
+}
+
+// basicClientCredentials reads client credentials sent with HTTP Basic, which RFC 6749 form-encodes
+// before joining them
+func basicClientCredentials(c *fiber.Ctx) (string, string, bool) {
+	encoded, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Basic ")
+	if !ok {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", "", false
+	}
+	rawID, rawSecret, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", false
+	}
+
+	clientID, idErr := url.QueryUnescape(rawID)
+	secret, secretErr := url.QueryUnescape(rawSecret)
+	if idErr != nil || secretErr != nil {
+		return "", "", false
+	}
+	return clientID, secret, true
+}
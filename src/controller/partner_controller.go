@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PartnerController struct {
+	PartnerService service.PartnerService
+}
+
+func NewPartnerController(partnerService service.PartnerService) *PartnerController {
+	return &PartnerController{
+		PartnerService: partnerService,
+	}
+}
+
+// @Tags         Partners
+// @Summary      List partners
+// @Description  Only admins can list the partners allowed to call the signed partner API.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/partners [get]
+// @Success      200  {object}  example.GetPartnersResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (pc *PartnerController) GetPartners(c *fiber.Ctx) error {
+	partners, err := pc.PartnerService.ListPartners(c)
+	if err != nil {
+		return err
+	}
+
+	list := make([]response.Partner, len(partners))
+	for i := range partners {
+		list[i] = response.NewPartner(&partners[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPartners{
+			Code:     fiber.StatusOK,
+			Status:   "success",
+			Message:  "Get partners successfully",
+			Partners: list,
+		})
+}
+
+// @Tags         Partners
+// @Summary      Register a partner
+// @Description  Only admins can register partners. The response holds the partner's signing secret, which cannot be retrieved again.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.CreatePartner  true  "Request body"
+// @Router       /admin/partners [post]
+// @Success      201  {object}  example.CreatePartnerResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      409  {object}  example.DuplicatePartner  "Partner already exists"
+func (pc *PartnerController) CreatePartner(c *fiber.Ctx) error {
+	req := new(validation.CreatePartner)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	partner, secret, err := pc.PartnerService.CreatePartner(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithPartner{
+			Code:          fiber.StatusCreated,
+			Status:        "success",
+			Message:       "Create partner successfully",
+			Partner:       response.NewPartner(partner),
+			SigningSecret: secret,
+		})
+}
+
+// @Tags         Partners
+// @Summary      Delete a partner
+// @Description  Only admins can delete partners; their signed requests are rejected from then on.
+// @Security BearerAuth
+// @Produce      json
+// @Param        partnerId  path  string  true  "Partner id"
+// @Router       /admin/partners/{partnerId} [delete]
+// @Success      200  {object}  example.DeletePartnerResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (pc *PartnerController) DeletePartner(c *fiber.Ctx) error {
+	if err := pc.PartnerService.DeletePartner(c, utils.ParamID(c, "partnerId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Delete partner successfully",
+		})
+}
+
+// @Tags         Partners
+// @Summary      Get the calling partner
+// @Description  Lets partners check their request signing. Sign "{timestamp}\n{nonce}\n{METHOD}\n{path and query}\n{body}" with HMAC-SHA256, keyed with the SHA-256 digest of the secret, and send the lowercase hex signature.
+// @Produce      json
+// @Param        X-Partner-Id  header  string  true  "Partner id"
+// @Param        X-Timestamp   header  string  true  "Unix time in seconds"
+// @Param        X-Nonce       header  string  true  "Unique value of 16 to 128 characters"
+// @Param        X-Signature   header  string  true  "Hex HMAC-SHA256 signature"
+// @Router       /partner/me [get]
+// @Success      200  {object}  example.GetPartnerResponse
+// @Failure      401  {object}  example.InvalidSignature  "Invalid, stale or replayed signature"
+func (pc *PartnerController) GetCurrentPartner(c *fiber.Ctx) error {
+	partner := c.Locals("partner").(*model.Partner)
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPartner{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get partner successfully",
+			Partner: response.NewPartner(partner),
+		})
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+	"context"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxPresenceMessage bounds heartbeat messages, which carry nothing the server reads
+const maxPresenceMessage = 512
+
+type PresenceController struct {
+	PresenceService service.PresenceService
+}
+
+func NewPresenceController(presenceService service.PresenceService) *PresenceController {
+	return &PresenceController{
+		PresenceService: presenceService,
+	}
+}
+
+// @Tags         Presence
+// @Summary      Connect for presence
+// @Description  Upgrades to a WebSocket that keeps the caller online while it is open. The server first sends a welcome message with the heartbeat_interval; the client must then send any message every heartbeat_interval seconds, and the socket is closed after PRESENCE_TTL seconds without one. Browsers pass their access token as the subprotocols "bearer" and the token.
+// @Security BearerAuth
+// @Router       /presence/ws [get]
+// @Success      101  "Switching Protocols"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      426  {object}  example.UpgradeRequired  "Upgrade required"
+func (p *PresenceController) Socket() fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		user, _ := conn.Locals("user").(*model.User)
+		conn.SetReadLimit(maxPresenceMessage)
+
+		p.PresenceService.Serve(context.Background(), conn, user.ID)
+	}, websocket.Config{Subprotocols: []string{middleware.WebSocketBearerProtocol}})
+}
+
+// @Tags         Presence
+// @Summary      Get a user's presence
+// @Description  Returns whether the user is online and when they were last seen. Users may read their own presence; others need the getUsers right.
+// @Security BearerAuth
+// @Produce      json
+// @Param        userId  path  string  true  "User id"
+// @Router       /users/{userId}/presence [get]
+// @Success      200  {object}  example.GetPresenceResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (p *PresenceController) GetPresence(c *fiber.Ctx) error {
+	presence, err := p.PresenceService.GetPresence(c, c.Params("userId"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPresence{
+			Code:     fiber.StatusOK,
+			Status:   "success",
+			Message:  "Get presence successfully",
+			Presence: *presence,
+		})
+}
+
+// @Tags         Presence
+// @Summary      Look up the presence of many users
+// @Description  Returns the presence of each user in the order asked, up to PRESENCE_MAX_LOOKUP users. Unknown users are reported offline. Requires the getUsers right.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.LookupPresence  true  "Request body"
+// @Router       /users/presence [post]
+// @Success      200  {object}  example.LookupPresenceResponse
+// @Failure      400  {object}  example.TooManyPresenceLookups  "Too many users"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (p *PresenceController) LookupPresence(c *fiber.Ctx) error {
+	req := new(validation.LookupPresence)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	presences, err := p.PresenceService.LookupPresence(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPresences{
+			Code:      fiber.StatusOK,
+			Status:    "success",
+			Message:   "Get presence successfully",
+			Presences: presences,
+		})
+}
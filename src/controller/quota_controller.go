@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type QuotaController struct {
+	QuotaService service.QuotaService
+}
+
+func NewQuotaController(quotaService service.QuotaService) *QuotaController {
+	return &QuotaController{
+		QuotaService: quotaService,
+	}
+}
+
+// @Tags         Quotas
+// @Summary      Get a user's request quota
+// @Description  Only admins can see a user's plan, daily and monthly limits (0 is unlimited) and usage.
+// @Security BearerAuth
+// @Produce      json
+// @Param        userId  path  string  true  "User id"
+// @Router       /admin/quotas/users/{userId} [get]
+// @Success      200  {object}  example.GetUserQuotaResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (q *QuotaController) GetUserQuota(c *fiber.Ctx) error {
+	quota, err := q.QuotaService.GetUserQuota(c, utils.ParamID(c, "userId"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithQuota{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get quota successfully",
+			Quota:   *quota,
+		})
+}
+
+// @Tags         Quotas
+// @Summary      Update a user's request quota
+// @Description  Only admins can change a user's plan and override its limits. An omitted plan is kept; omitted limits fall back to the plan and 0 is unlimited. Other instances apply the change within a minute.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        userId   path  string                      true  "User id"
+// @Param        request  body  validation.UpdateUserQuota  true  "Request body"
+// @Router       /admin/quotas/users/{userId} [put]
+// @Success      200  {object}  example.UpdateUserQuotaResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (q *QuotaController) UpdateUserQuota(c *fiber.Ctx) error {
+	req := new(validation.UpdateUserQuota)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	quota, err := q.QuotaService.UpdateUserQuota(c, utils.ParamID(c, "userId"), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithQuota{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Update quota successfully",
+			Quota:   *quota,
+		})
+}
+
+// @Tags         Quotas
+// @Summary      Get an API token's request quota
+// @Description  Only admins can see an API token's own limits and usage. Requests made with a token always count against its owner's quota too.
+// @Security BearerAuth
+// @Produce      json
+// @Param        tokenId  path  string  true  "API token id"
+// @Router       /admin/quotas/tokens/{tokenId} [get]
+// @Success      200  {object}  example.GetTokenQuotaResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (q *QuotaController) GetTokenQuota(c *fiber.Ctx) error {
+	quota, err := q.QuotaService.GetTokenQuota(c, utils.ParamID(c, "tokenId"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithQuota{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get quota successfully",
+			Quota:   *quota,
+		})
+}
+
+// @Tags         Quotas
+// @Summary      Update an API token's request quota
+// @Description  Only admins can give an API token its own limits. Omitted limits leave that period to the owner's quota and 0 is unlimited; omitting both removes the token's quota.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        tokenId  path  string                       true  "API token id"
+// @Param        request  body  validation.UpdateTokenQuota  true  "Request body"
+// @Router       /admin/quotas/tokens/{tokenId} [put]
+// @Success      200  {object}  example.UpdateTokenQuotaResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (q *QuotaController) UpdateTokenQuota(c *fiber.Ctx) error {
+	req := new(validation.UpdateTokenQuota)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	quota, err := q.QuotaService.UpdateTokenQuota(c, utils.ParamID(c, "tokenId"), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithQuota{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Update quota successfully",
+			Quota:   *quota,
+		})
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RateLimitController struct {
+	RateLimitService service.RateLimitService
+}
+
+func NewRateLimitController(rateLimitService service.RateLimitService) *RateLimitController {
+	return &RateLimitController{
+		RateLimitService: rateLimitService,
+	}
+}
+
+// @Tags         Rate Limits
+// @Summary      Get rate limit state
+// @Description  Only admins can read the rate limiter counters for a user or IP, or the per-email throttles for an email. Pass exactly one of user_id, ip or email.
+// @Security BearerAuth
+// @Produce      json
+// @Param        user_id  query  string  false  "User id"
+// @Param        ip       query  string  false  "Client IP address"
+// @Param        email    query  string  false  "Email address"
+// @Router       /admin/rate-limits [get]
+// @Success      200  {object}  example.GetRateLimitsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (rc *RateLimitController) GetRateLimits(c *fiber.Ctx) error {
+	states, err := rc.RateLimitService.Inspect(c, subjectQuery(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithRateLimits{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get rate limits successfully",
+			Results: states,
+		})
+}
+
+// @Tags         Rate Limits
+// @Summary      Reset rate limit state
+// @Description  Only admins can clear the rate limiter counters for a user or IP, or the per-email throttles for an email. Every reset is recorded in the audit log.
+// @Security BearerAuth
+// @Produce      json
+// @Param        user_id  query  string  false  "User id"
+// @Param        ip       query  string  false  "Client IP address"
+// @Param        email    query  string  false  "Email address"
+// @Router       /admin/rate-limits [delete]
+// @Success      200  {object}  example.ResetRateLimitsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (rc *RateLimitController) ResetRateLimits(c *fiber.Ctx) error {
+	reset, err := rc.RateLimitService.Reset(c, subjectQuery(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithRateLimitReset{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: fmt.Sprintf("Reset %d rate limits", reset),
+			Reset:   reset,
+		})
+}
+
+func subjectQuery(c *fiber.Ctx) *validation.RateLimitSubject {
+	return &validation.RateLimitSubject{
+		UserID: c.Query("user_id"),
+		IP:     c.Query("ip"),
+		Email:  c.Query("email"),
+	}
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReadOnlyController struct {
+	ReadOnlyService service.ReadOnlyService
+}
+
+func NewReadOnlyController(readOnlyService service.ReadOnlyService) *ReadOnlyController {
+	return &ReadOnlyController{
+		ReadOnlyService: readOnlyService,
+	}
+}
+
+// @Tags         Read-only mode
+// @Summary      Get read-only mode
+// @Description  Only admins can see whether the app is in read-only mode, why, since when and who switched it on. Forced is true when READ_ONLY keeps it on.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/read-only [get]
+// @Success      200  {object}  example.GetReadOnlyResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (rc *ReadOnlyController) GetReadOnly(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithReadOnly{
+			Code:     fiber.StatusOK,
+			Status:   "success",
+			Message:  "Get read-only mode successfully",
+			ReadOnly: rc.ReadOnlyService.GetReadOnly(c),
+		})
+}
+
+// @Tags         Read-only mode
+// @Summary      Switch read-only mode
+// @Description  Only admins can switch read-only mode on, with a reason, or off. Every instance follows within READ_ONLY_SYNC_INTERVAL seconds. In read-only mode requests that write answer 503 with the code read_only, while reads go on and background jobs pause. This endpoint keeps working in read-only mode.
+// @Security BearerAuth
+// @Produce      json
+// @Param        request  body  validation.SetReadOnly  true  "Request body"
+// @Router       /admin/read-only [put]
+// @Success      200  {object}  example.SetReadOnlyResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      409  {object}  example.ReadOnlyForced  "Read-only mode is forced by READ_ONLY"
+func (rc *ReadOnlyController) SetReadOnly(c *fiber.Ctx) error {
+	req := new(validation.SetReadOnly)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	state, err := rc.ReadOnlyService.SetReadOnly(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithReadOnly{
+			Code:     fiber.StatusOK,
+			Status:   "success",
+			Message:  "Set read-only mode successfully",
+			ReadOnly: state,
+		})
+}
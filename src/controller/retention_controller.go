@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RetentionController struct {
+	RetentionService service.RetentionService
+}
+
+func NewRetentionController(retentionService service.RetentionService) *RetentionController {
+	return &RetentionController{
+		RetentionService: retentionService,
+	}
+}
+
+// @Tags         Retention
+// @Summary      Get the retention report
+// @Description  Only admins can see the retention policies, each with its cutoff and the rows older than it, which the next purge deletes. Policies keeping rows forever are left out.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/retention [get]
+// @Success      200  {object}  example.RetentionReportResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (rc *RetentionController) GetReport(c *fiber.Ctx) error {
+	report, err := rc.RetentionService.GetReport(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithRetentionPolicies{
+			Code:     fiber.StatusOK,
+			Status:   "success",
+			Message:  "Get retention report successfully",
+			Policies: report,
+		})
+}
+
+// @Tags         Retention
+// @Summary      Purge rows past their retention
+// @Description  Only admins can run the retention purge at once, even with the retention job disabled. Rows are deleted in batches; a purge cut short by the request deadline is finished by later runs. With dryRun=true the rows are only counted.
+// @Security BearerAuth
+// @Produce      json
+// @Param        dryRun  query  bool  false  "Only report how many rows would be purged"
+// @Router       /admin/retention/purge [post]
+// @Success      200  {object}  example.RetentionPurgeResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (rc *RetentionController) Purge(c *fiber.Ctx) error {
+	purged, err := rc.RetentionService.PurgeNow(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithRetentionPolicies{
+			Code:     fiber.StatusOK,
+			Status:   "success",
+			Message:  "Purge rows past retention successfully",
+			Policies: purged,
+		})
+}
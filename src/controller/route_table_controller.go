@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RouteTableController struct {
+	RouteTableService service.RouteTableService
+}
+
+func NewRouteTableController(routeTableService service.RouteTableService) *RouteTableController {
+	return &RouteTableController{
+		RouteTableService: routeTableService,
+	}
+}
+
+// @Tags         Routes
+// @Summary      List the registered routes
+// @Description  Only admins can list every route the app serves, with the access policies, rate limits and cache policy applied to it and its full middleware chain in order.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/routes [get]
+// @Success      200  {object}  example.RouteTableResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (rc *RouteTableController) GetRoutes(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithRoutes{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get routes successfully",
+			Routes:  rc.RouteTableService.GetRoutes(c),
+		})
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/validation"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchController struct {
+	SearchService service.SearchService
+}
+
+func NewSearchController(searchService service.SearchService) *SearchController {
+	return &SearchController{
+		SearchService: searchService,
+	}
+}
+
+// @Tags         Search
+// @Summary      Search users, audit logs, tokens and sessions
+// @Description  Only admins can search. Matches names, emails, usernames, audit actions, IPs, user agents and metadata, API token names and live sessions by owner. An ID, a request ID, an IP or a whole API token matches exactly and ranks first.
+// @Security BearerAuth
+// @Produce      json
+// @Param        q      query  string  true   "Search text"
+// @Param        types  query  string  false  "Comma-separated entity types to include (user, audit, token, session)"
+// @Param        limit  query  int     false  "Maximum number of results"  default(20)
+// @Router       /admin/search [get]
+// @Success      200  {object}  example.SearchResponse
+// @Failure      400  {object}  example.InvalidSearch  "Bad request"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (sc *SearchController) Search(c *fiber.Ctx) error {
+	query := &validation.Search{
+		Query: strings.TrimSpace(c.Query("q")),
+		Limit: c.QueryInt("limit", 20),
+	}
+	if types := c.Query("types"); types != "" {
+		query.Types = strings.Split(types, ",")
+	}
+
+	hits, err := sc.SearchService.Search(c, query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithSearchHits{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Search successfully",
+			Results: hits,
+		})
+}
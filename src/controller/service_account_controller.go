@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ServiceAccountController struct {
+	ServiceAccountService service.ServiceAccountService
+	APITokenService       service.APITokenService
+}
+
+func NewServiceAccountController(
+	serviceAccountService service.ServiceAccountService, apiTokenService service.APITokenService,
+) *ServiceAccountController {
+	return &ServiceAccountController{
+		ServiceAccountService: serviceAccountService,
+		APITokenService:       apiTokenService,
+	}
+}
+
+// @Tags         Service Accounts
+// @Summary      List service accounts
+// @Description  Only admins can list the service accounts that authenticate with client certificates.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/service-accounts [get]
+// @Success      200  {object}  example.GetServiceAccountsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (sc *ServiceAccountController) GetServiceAccounts(c *fiber.Ctx) error {
+	accounts, err := sc.ServiceAccountService.ListServiceAccounts(c)
+	if err != nil {
+		return err
+	}
+
+	list := make([]response.ServiceAccount, len(accounts))
+	for i := range accounts {
+		list[i] = response.NewServiceAccount(&accounts[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithServiceAccounts{
+			Code:            fiber.StatusOK,
+			Status:          "success",
+			Message:         "Get service accounts successfully",
+			ServiceAccounts: list,
+		})
+}
+
+// @Tags         Service Accounts
+// @Summary      Create a service account
+// @Description  Only admins can create service accounts. Each is a user of type service, without a password or email, that authenticates with API tokens or a client certificate. A client certificate naming the identity as a URI, DNS or email SAN, or as its common name, authenticates as the account on listeners with a client CA. Rights must be a subset of the admin's own.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  validation.CreateServiceAccount  true  "Request body"
+// @Router       /admin/service-accounts [post]
+// @Success      201  {object}  example.CreateServiceAccountResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      409  {object}  example.DuplicateServiceAccount  "Service account name or identity already exists"
+func (sc *ServiceAccountController) CreateServiceAccount(c *fiber.Ctx) error {
+	req := new(validation.CreateServiceAccount)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	account, err := sc.ServiceAccountService.CreateServiceAccount(c, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithServiceAccount{
+			Code:           fiber.StatusCreated,
+			Status:         "success",
+			Message:        "Create service account successfully",
+			ServiceAccount: response.NewServiceAccount(account),
+		})
+}
+
+// @Tags         Service Accounts
+// @Summary      Delete a service account
+// @Description  Only admins can delete service accounts. Their service user and API tokens are deleted too, and their client certificates are rejected from then on.
+// @Security BearerAuth
+// @Produce      json
+// @Param        serviceAccountId  path  string  true  "Service account id"
+// @Router       /admin/service-accounts/{serviceAccountId} [delete]
+// @Success      200  {object}  example.DeleteServiceAccountResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (sc *ServiceAccountController) DeleteServiceAccount(c *fiber.Ctx) error {
+	if err := sc.ServiceAccountService.DeleteServiceAccount(c, utils.ParamID(c, "serviceAccountId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Delete service account successfully",
+		})
+}
+
+// @Tags         Service Accounts
+// @Summary      List a service account's API tokens
+// @Description  Only admins can list the API tokens of service accounts.
+// @Security BearerAuth
+// @Produce      json
+// @Param        serviceAccountId  path  string  true  "Service account id"
+// @Router       /admin/service-accounts/{serviceAccountId}/tokens [get]
+// @Success      200  {object}  example.GetAPITokensResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (sc *ServiceAccountController) GetTokens(c *fiber.Ctx) error {
+	account, err := sc.ServiceAccountService.GetServiceAccount(c, utils.ParamID(c, "serviceAccountId"))
+	if err != nil {
+		return err
+	}
+
+	tokens, err := sc.APITokenService.ListTokens(c, account.UserID.String())
+	if err != nil {
+		return err
+	}
+
+	results := make([]response.APIToken, len(tokens))
+	for i := range tokens {
+		results[i] = response.NewAPIToken(&tokens[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithAPITokens{
+			Code:      fiber.StatusOK,
+			Status:    "success",
+			Message:   "Get API tokens successfully",
+			APITokens: results,
+		})
+}
+
+// @Tags         Service Accounts
+// @Summary      Create an API token for a service account
+// @Description  Only admins can mint API tokens for service accounts, restricted to a subset of the account's rights. The token is only shown once.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        serviceAccountId  path  string  true  "Service account id"
+// @Param        request  body  validation.CreateAPIToken  true  "Request body"
+// @Router       /admin/service-accounts/{serviceAccountId}/tokens [post]
+// @Success      201  {object}  example.CreateAPITokenResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (sc *ServiceAccountController) CreateToken(c *fiber.Ctx) error {
+	req := new(validation.CreateAPIToken)
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	account, err := sc.ServiceAccountService.GetServiceAccount(c, utils.ParamID(c, "serviceAccountId"))
+	if err != nil {
+		return err
+	}
+
+	token, rawToken, err := sc.APITokenService.CreateToken(c, account.User, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).
+		JSON(response.SuccessWithAPIToken{
+			Code:     fiber.StatusCreated,
+			Status:   "success",
+			Message:  "Create API token successfully",
+			APIToken: response.NewAPIToken(token),
+			Token:    rawToken,
+		})
+}
+
+// @Tags         Service Accounts
+// @Summary      Revoke an API token of a service account
+// @Description  Only admins can revoke the API tokens of service accounts.
+// @Security BearerAuth
+// @Produce      json
+// @Param        serviceAccountId  path  string  true  "Service account id"
+// @Param        tokenId  path  string  true  "Token id"
+// @Router       /admin/service-accounts/{serviceAccountId}/tokens/{tokenId} [delete]
+// @Success      200  {object}  example.RevokeAPITokenResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (sc *ServiceAccountController) RevokeToken(c *fiber.Ctx) error {
+	account, err := sc.ServiceAccountService.GetServiceAccount(c, utils.ParamID(c, "serviceAccountId"))
+	if err != nil {
+		return err
+	}
+
+	if err := sc.APITokenService.RevokeToken(c, account.UserID.String(), utils.ParamID(c, "tokenId")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Revoke API token successfully",
+		})
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionActivityController struct {
+	SessionActivityService service.SessionActivityService
+}
+
+func NewSessionActivityController(sessionActivityService service.SessionActivityService) *SessionActivityController {
+	return &SessionActivityController{
+		SessionActivityService: sessionActivityService,
+	}
+}
+
+// @Tags         Users
+// @Summary      Get session activity
+// @Description  Only admins can see how many sessions and users were active in the last 5, 30 and 1440 minutes.
+// @Security BearerAuth
+// @Produce      json
+// @Router       /admin/sessions/activity [get]
+// @Success      200  {object}  example.GetSessionActivityResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (s *SessionActivityController) GetActivity(c *fiber.Ctx) error {
+	activity, err := s.SessionActivityService.GetActivity(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithSessionActivity{
+			Code:            fiber.StatusOK,
+			Status:          "success",
+			Message:         "Get session activity successfully",
+			SessionActivity: *activity,
+		})
+}
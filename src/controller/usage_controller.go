@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UsageController struct {
+	UsageService service.UsageService
+}
+
+func NewUsageController(usageService service.UsageService) *UsageController {
+	return &UsageController{
+		UsageService: usageService,
+	}
+}
+
+// @Tags         Usage
+// @Summary      Get your usage
+// @Description  Returns your metered requests and business events per UTC day and per API token. Usage is rolled up periodically, so the last minutes may be missing.
+// @Security BearerAuth
+// @Produce      json
+// @Param        from  query  string  false  "First day (YYYY-MM-DD), defaults to the start of the month"
+// @Param        to    query  string  false  "Last day (YYYY-MM-DD), defaults to today"
+// @Router       /usage [get]
+// @Success      200  {object}  example.GetUsageResponse
+// @Failure      400  {object}  example.InvalidDateRange  "Invalid date range"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+func (u *UsageController) GetUsage(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(*model.User)
+
+	return u.getUsage(c, user.ID.String())
+}
+
+// @Tags         Usage
+// @Summary      Get a user's usage
+// @Description  Only admins can read another user's metered requests and business events per UTC day and per API token, including deleted users'.
+// @Security BearerAuth
+// @Produce      json
+// @Param        userId  path   string  true   "User id"
+// @Param        from    query  string  false  "First day (YYYY-MM-DD), defaults to the start of the month"
+// @Param        to      query  string  false  "Last day (YYYY-MM-DD), defaults to today"
+// @Router       /admin/usage/users/{userId} [get]
+// @Success      200  {object}  example.GetUsageResponse
+// @Failure      400  {object}  example.InvalidDateRange  "Invalid date range"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (u *UsageController) GetUserUsage(c *fiber.Ctx) error {
+	return u.getUsage(c, utils.ParamID(c, "userId"))
+}
+
+// @Tags         Usage
+// @Summary      Get the usage report
+// @Description  Only admins can read the usage of all users per UTC day, the number of active users and the users with the highest count of a metric.
+// @Security BearerAuth
+// @Produce      json
+// @Param        from    query  string  false  "First day (YYYY-MM-DD), defaults to the start of the month"
+// @Param        to      query  string  false  "Last day (YYYY-MM-DD), defaults to today"
+// @Param        metric  query  string  false  "Metric to rank users by"  default(requests)
+// @Param        limit   query  int     false  "Maximum number of users"  default(10)
+// @Router       /admin/usage [get]
+// @Success      200  {object}  example.GetUsageReportResponse
+// @Failure      400  {object}  example.InvalidDateRange  "Invalid date range"
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+func (u *UsageController) GetReport(c *fiber.Ctx) error {
+	query := &validation.QueryUsageReport{
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+		Metric: c.Query("metric", model.UsageMetricRequests),
+		Limit:  c.QueryInt("limit", 10),
+	}
+
+	report, err := u.UsageService.GetReport(c, query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithUsageReport{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get usage report successfully",
+			Report:  *report,
+		})
+}
+
+func (u *UsageController) getUsage(c *fiber.Ctx, userID string) error {
+	query := &validation.QueryUsage{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	}
+
+	usage, err := u.UsageService.GetUsage(c, userID, query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithUsage{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get usage successfully",
+			Usage:   *usage,
+		})
+}
@@ -1,14 +1,16 @@
 package controller
 
 import (
+	"app/src/binding"
 	"app/src/model"
 	"app/src/response"
+	"app/src/serializer"
 	"app/src/service"
+	"app/src/utils"
 	"app/src/validation"
 	"math"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 type UserController struct {
@@ -30,16 +32,28 @@ func NewUserController(userService service.UserService, tokenService service.Tok
 // @Produce      json
 // @Param        page     query     int     false   "Page number"  default(1)
 // @Param        limit    query     int     false   "Maximum number of users"    default(10)
-// @Param        search   query     string  false  "Search by name or email or role"
+// @Param        search   query     string  false  "Search by name, email, username or role"
+// @Param        tag      query     string  false  "Only users carrying this tag"
+// @Param        username query     string  false  "Only the user with this username (case-insensitive)"
+// @Param        type     query     string  false  "Only people (human) or service accounts (service)"
+// @Param        fields   query     string  false  "Comma-separated fields to return (e.g. id,name,email)"
 // @Router       /users [get]
 // @Success      200  {object}  example.GetAllUserResponse
 // @Failure      401  {object}  example.Unauthorized  "Unauthorized"
 // @Failure      403  {object}  example.Forbidden  "Forbidden"
 func (u *UserController) GetUsers(c *fiber.Ctx) error {
 	query := &validation.QueryUser{
-		Page:   c.QueryInt("page", 1),
-		Limit:  c.QueryInt("limit", 10),
-		Search: c.Query("search", ""),
+		Page:     c.QueryInt("page", 1),
+		Limit:    c.QueryInt("limit", 10),
+		Search:   c.Query("search", ""),
+		Tag:      c.Query("tag", ""),
+		Username: c.Query("username", ""),
+		Type:     c.Query("type", ""),
+	}
+
+	fields, err := serializer.User.Fields(c)
+	if err != nil {
+		return err
 	}
 
 	users, totalResults, err := u.UserService.GetUsers(c, query)
@@ -48,11 +62,11 @@ func (u *UserController) GetUsers(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusOK).
-		JSON(response.SuccessWithPaginate[model.User]{
+		JSON(response.SuccessWithPaginate[map[string]interface{}]{
 			Code:         fiber.StatusOK,
 			Status:       "success",
 			Message:      "Get all users successfully",
-			Results:      users,
+			Results:      serializer.User.Many(users, serializer.Role(c), fields),
 			Page:         query.Page,
 			Limit:        query.Limit,
 			TotalPages:   int64(math.Ceil(float64(totalResults) / float64(query.Limit))),
@@ -66,16 +80,18 @@ func (u *UserController) GetUsers(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Produce      json
 // @Param        id  path  string  true  "User id"
+// @Param        fields  query  string  false  "Comma-separated fields to return (e.g. id,name,email)"
 // @Router       /users/{id} [get]
 // @Success      200  {object}  example.GetUserResponse
 // @Failure      401  {object}  example.Unauthorized  "Unauthorized"
 // @Failure      403  {object}  example.Forbidden  "Forbidden"
 // @Failure      404  {object}  example.NotFound  "Not found"
 func (u *UserController) GetUserByID(c *fiber.Ctx) error {
-	userID := c.Params("userId")
+	userID := utils.ParamID(c, "userId")
 
-	if _, err := uuid.Parse(userID); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	fields, err := serializer.User.Fields(c)
+	if err != nil {
+		return err
 	}
 
 	user, err := u.UserService.GetUserByID(c, userID)
@@ -84,11 +100,70 @@ func (u *UserController) GetUserByID(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusOK).
-		JSON(response.SuccessWithUser{
+		JSON(response.SuccessWithFields{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get user successfully",
+			User:    serializer.User.One(user, serializer.Role(c), fields),
+		})
+}
+
+// @Tags         Users
+// @Summary      Get a user by username
+// @Description  Only admins can look users up by username. The match ignores case.
+// @Security BearerAuth
+// @Produce      json
+// @Param        username  path  string  true  "Username"
+// @Param        fields  query  string  false  "Comma-separated fields to return (e.g. id,name,email)"
+// @Router       /users/handle/{username} [get]
+// @Success      200  {object}  example.GetUserResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (u *UserController) GetUserByHandle(c *fiber.Ctx) error {
+	fields, err := serializer.User.Fields(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.UserService.GetUserByHandle(c, c.Params("username"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithFields{
 			Code:    fiber.StatusOK,
 			Status:  "success",
 			Message: "Get user successfully",
-			User:    *user,
+			User:    serializer.User.One(user, serializer.Role(c), fields),
+		})
+}
+
+// @Tags         Users
+// @Summary      Check username availability
+// @Description  Anyone can check whether a username is free before signing up. Checks are rate limited per IP.
+// @Produce      json
+// @Param        username  query  string  true  "Username to check"
+// @Router       /users/check-username [get]
+// @Success      200  {object}  example.CheckUsernameResponse
+// @Failure      429  {object}  example.TooManyRequests  "Too many requests"
+func (u *UserController) CheckUsername(c *fiber.Ctx) error {
+	query := &validation.CheckUsername{
+		Username: c.Query("username", ""),
+	}
+
+	availability, err := u.UserService.CheckUsername(c, query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithUsernameAvailability{
+			Code:                 fiber.StatusOK,
+			Status:               "success",
+			Message:              "Check username successfully",
+			UsernameAvailability: *availability,
 		})
 }
 
@@ -103,6 +178,7 @@ func (u *UserController) GetUserByID(c *fiber.Ctx) error {
 // @Failure      401  {object}  example.Unauthorized  "Unauthorized"
 // @Failure      403  {object}  example.Forbidden  "Forbidden"
 // @Failure      409  {object}  example.DuplicateEmail  "Email already taken"
+// @Failure      422  {object}  example.EmailDomainNotAllowed  "Email domain is not allowed"
 func (u *UserController) CreateUser(c *fiber.Ctx) error {
 	req := new(validation.CreateUser)
 
@@ -120,36 +196,44 @@ func (u *UserController) CreateUser(c *fiber.Ctx) error {
 			Code:    fiber.StatusCreated,
 			Status:  "success",
 			Message: "Create user successfully",
-			User:    *user,
+			User:    response.NewUser(user),
 		})
 }
 
 // @Tags         Users
 // @Summary      Update a user
-// @Description  Logged in users can only update their own information. Only admins can update other users.
+// @Description  Logged in users can only update their own information. Only admins can update other users or change roles.
+// @Description  Absent fields are left unchanged, fields set to "" are emptied and the username or timezone set to null are cleared.
+// @Description  The body may also be a JSON Patch (application/json-patch+json) or a merge patch (application/merge-patch+json);
+// @Description  a failed "test" operation, or a tested field changed meanwhile, is answered with 409.
+// @Description  With dryRun=true nothing is changed; the response lists each column's current and new value.
 // @Security BearerAuth
+// @Accept       json,application/json-patch+json,application/merge-patch+json
 // @Produce      json
 // @Param        id  path  string  true  "User id"
+// @Param        dryRun  query  bool  false  "Only report what would change"
 // @Param        request  body  validation.UpdateUser  true  "Request body"
 // @Router       /users/{id} [patch]
 // @Success      200  {object}  example.UpdateUserResponse
 // @Failure      401  {object}  example.Unauthorized  "Unauthorized"
 // @Failure      403  {object}  example.Forbidden  "Forbidden"
 // @Failure      404  {object}  example.NotFound  "Not found"
-// @Failure      409  {object}  example.DuplicateEmail  "Email already taken"
+// @Failure      409  {object}  example.DuplicateEmail  "Email already taken, or patch test failed"
+// @Failure      422  {object}  example.UnpatchablePath  "Path cannot be patched"
 func (u *UserController) UpdateUser(c *fiber.Ctx) error {
-	req := new(validation.UpdateUser)
-	userID := c.Params("userId")
+	userID := utils.ParamID(c, "userId")
 
-	if _, err := uuid.Parse(userID); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	var user *model.User
+	var err error
+	if binding.IsPatch(c) {
+		user, err = u.UserService.PatchUser(c, userID)
+	} else {
+		req := new(validation.UpdateUser)
+		if err := c.BodyParser(req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+		user, err = u.UserService.UpdateUser(c, req, userID)
 	}
-
-	if err := c.BodyParser(req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
-	}
-
-	user, err := u.UserService.UpdateUser(c, req, userID)
 	if err != nil {
 		return err
 	}
@@ -159,40 +243,174 @@ func (u *UserController) UpdateUser(c *fiber.Ctx) error {
 			Code:    fiber.StatusOK,
 			Status:  "success",
 			Message: "Update user successfully",
-			User:    *user,
+			User:    response.NewUser(user),
 		})
 }
 
 // @Tags         Users
 // @Summary      Delete a user
-// @Description  Logged in users can delete only themselves. Only admins can delete other users.
+// @Description  Logged in users can delete only themselves. Only admins can delete other users. Users under legal hold cannot be deleted until the hold is lifted.
+// @Description  With dryRun=true nothing is deleted; the response counts the user and the rows deleted with them.
 // @Security BearerAuth
 // @Produce      json
 // @Param        id  path  string  true  "User id"
+// @Param        dryRun  query  bool  false  "Only report what would be deleted"
 // @Router       /users/{id} [delete]
 // @Success      200  {object}  example.DeleteUserResponse
 // @Failure      401  {object}  example.Unauthorized  "Unauthorized"
 // @Failure      403  {object}  example.Forbidden  "Forbidden"
 // @Failure      404  {object}  example.NotFound  "Not found"
+// @Failure      409  {object}  example.UnderLegalHold  "User is under legal hold"
 func (u *UserController) DeleteUser(c *fiber.Ctx) error {
-	userID := c.Params("userId")
+	userID := utils.ParamID(c, "userId")
 
-	if _, err := uuid.Parse(userID); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	// Deleting the user first lets a dry run stop before any token is touched
+	if err := u.UserService.DeleteUser(c, userID); err != nil {
+		return err
 	}
 
 	if err := u.TokenService.DeleteAllToken(c, userID); err != nil {
 		return err
 	}
 
-	if err := u.UserService.DeleteUser(c, userID); err != nil {
+	return c.Status(fiber.StatusOK).
+		JSON(response.Common{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Delete user successfully",
+		})
+}
+
+// @Tags         Users
+// @Summary      Suspend a user
+// @Description  Only admins can suspend users. A suspended user keeps their data but is signed out everywhere and cannot sign in until reactivated.
+// @Security BearerAuth
+// @Produce      json
+// @Param        id  path  string  true  "User id"
+// @Router       /users/{id}/suspend [post]
+// @Success      200  {object}  example.SuspendUserResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+// @Failure      409  {object}  example.AlreadySuspended  "Already suspended"
+func (u *UserController) SuspendUser(c *fiber.Ctx) error {
+	userID := utils.ParamID(c, "userId")
+
+	user, err := u.UserService.SuspendUser(c, userID)
+	if err != nil {
+		return err
+	}
+
+	// Refresh tokens go with the suspension; reactivated users sign in again
+	if err := u.TokenService.DeleteAllToken(c, userID); err != nil {
 		return err
 	}
 
 	return c.Status(fiber.StatusOK).
-		JSON(response.Common{
+		JSON(response.SuccessWithUser{
 			Code:    fiber.StatusOK,
 			Status:  "success",
-			Message: "Delete user successfully",
+			Message: "Suspend user successfully",
+			User:    response.NewUser(user),
+		})
+}
+
+// @Tags         Users
+// @Summary      Reactivate a user
+// @Description  Only admins can reactivate suspended users.
+// @Security BearerAuth
+// @Produce      json
+// @Param        id  path  string  true  "User id"
+// @Router       /users/{id}/reactivate [post]
+// @Success      200  {object}  example.ReactivateUserResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (u *UserController) ReactivateUser(c *fiber.Ctx) error {
+	userID := utils.ParamID(c, "userId")
+
+	user, err := u.UserService.ReactivateUser(c, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithUser{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Reactivate user successfully",
+			User:    response.NewUser(user),
+		})
+}
+
+// @Tags         Users
+// @Summary      Get a user's revisions
+// @Description  Logged in users can see the history of their own profile. Only admins can see other users' history.
+// @Description  Each revision maps the changed columns to their values before and after; the password hash and other hidden columns are only marked as redacted.
+// @Security BearerAuth
+// @Produce      json
+// @Param        id     path   string  true   "User id"
+// @Param        page   query  int     false  "Page number"  default(1)
+// @Param        limit  query  int     false  "Maximum number of revisions"  default(20)
+// @Router       /users/{id}/revisions [get]
+// @Success      200  {object}  example.GetUserRevisionsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (u *UserController) GetUserRevisions(c *fiber.Ctx) error {
+	query := &validation.QueryUserRevisions{
+		Page:  c.QueryInt("page", 1),
+		Limit: c.QueryInt("limit", 20),
+	}
+
+	revisions, totalResults, err := u.UserService.GetUserRevisions(c, utils.ParamID(c, "userId"), query)
+	if err != nil {
+		return err
+	}
+
+	results := make([]response.UserRevision, len(revisions))
+	for i := range revisions {
+		results[i] = response.NewUserRevision(&revisions[i])
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithPaginate[response.UserRevision]{
+			Code:         fiber.StatusOK,
+			Status:       "success",
+			Message:      "Get user revisions successfully",
+			Results:      results,
+			Page:         query.Page,
+			Limit:        query.Limit,
+			TotalPages:   int64(math.Ceil(float64(totalResults) / float64(query.Limit))),
+			TotalResults: totalResults,
+		})
+}
+
+// @Tags         Users
+// @Summary      Restore a user revision
+// @Description  Only admins can roll a user's name, username, email, role and timezone back to how they were right after a revision. The rollback is recorded as a new revision.
+// @Security BearerAuth
+// @Produce      json
+// @Param        id          path  string  true  "User id"
+// @Param        revisionId  path  string  true  "Revision id"
+// @Param        dryRun      query  bool    false  "Only report what would change"
+// @Router       /users/{id}/revisions/{revisionId}/restore [post]
+// @Success      200  {object}  example.RestoreUserRevisionResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+// @Failure      409  {object}  example.DuplicateEmail  "Email already taken"
+func (u *UserController) RestoreUserRevision(c *fiber.Ctx) error {
+	user, err := u.UserService.RestoreUserRevision(c, utils.ParamID(c, "userId"), utils.ParamID(c, "revisionId"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithUser{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Restore user revision successfully",
+			User:    response.NewUser(user),
 		})
 }
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"app/src/response"
+	"app/src/service"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserTagController struct {
+	UserTagService service.UserTagService
+}
+
+func NewUserTagController(userTagService service.UserTagService) *UserTagController {
+	return &UserTagController{
+		UserTagService: userTagService,
+	}
+}
+
+// @Tags         Users
+// @Summary      List a user's tags
+// @Description  Only admins can see user tags.
+// @Security BearerAuth
+// @Produce      json
+// @Param        id  path  string  true  "User id"
+// @Router       /users/{id}/tags [get]
+// @Success      200  {object}  example.GetUserTagsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (u *UserTagController) GetTags(c *fiber.Ctx) error {
+	userID := utils.ParamID(c, "userId")
+
+	tags, err := u.UserTagService.ListTags(c, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithTags{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Get user tags successfully",
+			Tags:    tags,
+		})
+}
+
+// @Tags         Users
+// @Summary      Tag a user
+// @Description  Only admins can tag users. Tags are lowercase and may be used to filter users, gate routes or apply rate limits.
+// @Security BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string               true  "User id"
+// @Param        request  body  validation.UserTags  true  "Request body"
+// @Router       /users/{id}/tags [post]
+// @Success      200  {object}  example.AddUserTagsResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (u *UserTagController) AddTags(c *fiber.Ctx) error {
+	req := new(validation.UserTags)
+	userID := utils.ParamID(c, "userId")
+
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	tags, err := u.UserTagService.AddTags(c, userID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithTags{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Add user tags successfully",
+			Tags:    tags,
+		})
+}
+
+// @Tags         Users
+// @Summary      Remove a tag from a user
+// @Description  Only admins can remove user tags.
+// @Security BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "User id"
+// @Param        tag  path  string  true  "Tag"
+// @Router       /users/{id}/tags/{tag} [delete]
+// @Success      200  {object}  example.RemoveUserTagResponse
+// @Failure      401  {object}  example.Unauthorized  "Unauthorized"
+// @Failure      403  {object}  example.Forbidden  "Forbidden"
+// @Failure      404  {object}  example.NotFound  "Not found"
+func (u *UserTagController) RemoveTag(c *fiber.Ctx) error {
+	tags, err := u.UserTagService.RemoveTag(c, utils.ParamID(c, "userId"), c.Params("tag"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(response.SuccessWithTags{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Remove user tag successfully",
+			Tags:    tags,
+		})
+}
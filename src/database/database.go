@@ -1,11 +1,20 @@
 package database
 
 import (
+	"app/src/chaos"
+	"app/src/clock"
 	"app/src/config"
+	"app/src/deadline"
+	"app/src/memo"
+	"app/src/metrics"
+	"app/src/revision"
 	"app/src/utils"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -13,29 +22,111 @@ import (
 
 func Connect(dbHost, dbName string) *gorm.DB {
 	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=Asia/Shanghai",
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=UTC",
 		dbHost, config.DBUser, config.DBPassword, dbName, config.DBPort,
 	)
 
+	pool := config.DatabasePool
+	backoff := time.Duration(pool.ConnectBackoff) * time.Second
+	maxBackoff := time.Duration(config.MaxDBConnectBackoff) * time.Second
+
+	// Retry with exponential backoff so the app survives Postgres starting after it
+	var db *gorm.DB
+	var err error
+	for attempt := 0; ; attempt++ {
+		db, err = open(dsn)
+		if err == nil {
+			break
+		}
+
+		if attempt >= pool.ConnectRetries {
+			utils.Log.Fatalf("Failed to connect to database after %d attempts: %+v", attempt+1, err)
+		}
+
+		utils.Log.Warnf("Database not ready (attempt %d/%d): %v - retrying in %v",
+			attempt+1, pool.ConnectRetries+1, err, backoff)
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	sqlDB, errDB := db.DB()
+	if errDB != nil {
+		utils.Log.Errorf("Failed to connect to database: %+v", errDB)
+	}
+
+	// Statements get a deadline from the request, after connecting so startup keeps its own retries
+	if err := db.Use(deadline.GormPlugin{}); err != nil {
+		utils.Log.Warnf("Failed to install database deadlines: %v", err)
+	}
+
+	// Every insert and update of a user is kept as a revision
+	if err := db.Use(revision.GormPlugin{}); err != nil {
+		utils.Log.Warnf("Failed to install user revisions: %v", err)
+	}
+
+	// Writes empty the request-scoped lookup cache
+	if err := db.Use(memo.GormPlugin{}); err != nil {
+		utils.Log.Warnf("Failed to install request cache invalidation: %v", err)
+	}
+
+	// Chaos faults are injected after connecting, so startup itself is never disrupted
+	if config.Chaos.Enabled {
+		if err := db.Use(chaos.GormPlugin{}); err != nil {
+			utils.Log.Warnf("Failed to install database fault injection: %v", err)
+		}
+	}
+
+	// Config connection pooling
+	sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(pool.ConnMaxLifetime) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(pool.ConnMaxIdleTime) * time.Minute)
+
+	// Export pool stats (open, in use, idle, wait count/duration) to Prometheus
+	err = metrics.Registry.Register(collectors.NewDBStatsCollector(sqlDB, dbName))
+	if err != nil && !errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+		utils.Log.Warnf("Failed to register database pool metrics: %v", err)
+	}
+
+	return db
+}
+
+// Logger writes SQL traces through utils.Log, so bound values (emails, hashes) are redacted
+func Logger() logger.Interface {
+	return logger.New(utils.Log, logger.Config{
+		SlowThreshold: 200 * time.Millisecond,
+		LogLevel:      logger.Info,
+		Colorful:      true,
+	})
+}
+
+// open opens the connection and verifies the server is reachable
+func open(dsn string) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger:                 logger.Default.LogMode(logger.Info),
+		Logger:                 Logger(),
 		SkipDefaultTransaction: true,
 		PrepareStmt:            true,
 		TranslateError:         true,
+		// Timestamp columns have no zone, so everything is written in UTC whatever the host's zone
+		NowFunc: clock.System.Now,
 	})
 	if err != nil {
-		utils.Log.Errorf("Failed to connect to database: %+v", err)
+		return nil, err
 	}
 
-	sqlDB, errDB := db.DB()
-	if errDB != nil {
-		utils.Log.Errorf("Failed to connect to database: %+v", errDB)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
 	}
 
-	// Config connection pooling
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(60 * time.Minute)
+	if err := sqlDB.Ping(); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
 
-	return db
+	return db, nil
 }
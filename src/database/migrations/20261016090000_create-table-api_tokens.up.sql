@@ -0,0 +1,16 @@
+CREATE TABLE api_tokens(
+    id              UUID            PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID            NOT NULL,
+    name            VARCHAR(100)    NOT NULL,
+    token_hash      VARCHAR(64)     NOT NULL UNIQUE,
+    prefix          VARCHAR(16)     NOT NULL,
+    scopes          TEXT            NOT NULL,
+    expires_at      TIMESTAMP       NOT NULL,
+    last_used_at    TIMESTAMP,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
@@ -0,0 +1,13 @@
+CREATE TABLE audit_logs(
+    id              UUID            PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID,
+    action          VARCHAR(100)    NOT NULL,
+    ip              VARCHAR(45),
+    user_agent      VARCHAR(255),
+    metadata        JSONB           NOT NULL DEFAULT '{}',
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL
+);
+
+-- No foreign key: audit entries outlive the users they describe
+CREATE INDEX idx_audit_logs_user_id_created_at ON audit_logs(user_id, created_at DESC);
+CREATE INDEX idx_audit_logs_action ON audit_logs(action);
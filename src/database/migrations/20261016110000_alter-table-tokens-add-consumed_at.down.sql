@@ -0,0 +1,2 @@
+ALTER TABLE tokens DROP COLUMN IF EXISTS email;
+ALTER TABLE tokens DROP COLUMN IF EXISTS consumed_at;
@@ -0,0 +1,5 @@
+-- Single-use tokens are marked consumed instead of deleted so a replayed link can be told apart from a bogus one
+ALTER TABLE tokens ADD COLUMN consumed_at TIMESTAMP;
+
+-- Address a verify-email token was issued for; a later email change invalidates the link
+ALTER TABLE tokens ADD COLUMN email VARCHAR(255);
@@ -0,0 +1,2 @@
+-- Stable Apple user identifier (ID token "sub"); Apple only sends the email and name on first authorization
+ALTER TABLE users ADD COLUMN apple_id VARCHAR(255) UNIQUE;
@@ -0,0 +1,2 @@
+-- Suspended accounts keep their data but cannot sign in; existing users stay active
+ALTER TABLE users ADD COLUMN is_active BOOLEAN DEFAULT TRUE NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS user_tags;
\ No newline at end of file
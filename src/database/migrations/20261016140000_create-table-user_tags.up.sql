@@ -0,0 +1,10 @@
+CREATE TABLE user_tags(
+    user_id         UUID            NOT NULL,
+    tag             VARCHAR(32)     NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    PRIMARY KEY (user_id, tag),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_tags_tag ON user_tags(tag);
@@ -0,0 +1,10 @@
+CREATE TABLE email_domain_rules(
+    id              UUID            PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pattern         VARCHAR(255)    NOT NULL,
+    action          VARCHAR(10)     NOT NULL,
+    created_by      UUID,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT uq_email_domain_rules_pattern_action UNIQUE (pattern, action),
+    CONSTRAINT fk_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,3 @@
+-- Usernames are optional and unique regardless of case; the stored value keeps the user's casing
+ALTER TABLE users ADD COLUMN username VARCHAR(30);
+CREATE UNIQUE INDEX users_username_lower_key ON users (LOWER(username));
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tokens_refresh_last_active_at_idx;
+DROP INDEX IF EXISTS tokens_session_id_idx;
+ALTER TABLE tokens DROP COLUMN IF EXISTS last_active_at;
+ALTER TABLE tokens DROP COLUMN IF EXISTS session_id;
@@ -0,0 +1,7 @@
+-- A session is a chain of rotated refresh tokens sharing one session_id; existing refresh tokens
+-- start a session of their own
+ALTER TABLE tokens ADD COLUMN session_id UUID;
+ALTER TABLE tokens ADD COLUMN last_active_at TIMESTAMP;
+UPDATE tokens SET session_id = id, last_active_at = created_at WHERE type = 'refresh';
+CREATE INDEX tokens_session_id_idx ON tokens (session_id);
+CREATE INDEX tokens_refresh_last_active_at_idx ON tokens (last_active_at) WHERE type = 'refresh';
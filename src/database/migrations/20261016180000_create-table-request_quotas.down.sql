@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS request_quota_usage;
+DROP TABLE IF EXISTS request_quotas;
+ALTER TABLE users DROP COLUMN IF EXISTS plan;
@@ -0,0 +1,23 @@
+-- The plan sets a user's default request quotas (see QUOTA_PLANS)
+ALTER TABLE users ADD COLUMN plan VARCHAR(30) NOT NULL DEFAULT 'free';
+
+-- Admin overrides of the plan quotas; subject is "user:{id}" or "token:{id}" and NULL keeps the default
+CREATE TABLE request_quotas(
+    subject         VARCHAR(64)     PRIMARY KEY,
+    daily_limit     BIGINT,
+    monthly_limit   BIGINT,
+    updated_by      UUID,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_updated_by
+        FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Request counts copied from the cache store; kept after users are deleted for billing
+CREATE TABLE request_quota_usage(
+    subject         VARCHAR(64)     NOT NULL,
+    period          VARCHAR(5)      NOT NULL,
+    period_start    DATE            NOT NULL,
+    used            BIGINT          NOT NULL DEFAULT 0,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    PRIMARY KEY (subject, period, period_start)
+);
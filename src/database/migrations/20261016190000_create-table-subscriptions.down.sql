@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS billing_events;
+DROP TABLE IF EXISTS subscriptions;
+DROP INDEX IF EXISTS users_stripe_customer_id_key;
+ALTER TABLE users DROP COLUMN IF EXISTS stripe_customer_id;
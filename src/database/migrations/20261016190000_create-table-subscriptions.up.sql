@@ -0,0 +1,28 @@
+-- Set once the user's Stripe customer is created at sign-up
+ALTER TABLE users ADD COLUMN stripe_customer_id VARCHAR(255);
+CREATE UNIQUE INDEX users_stripe_customer_id_key ON users (stripe_customer_id);
+
+-- Stripe subscriptions as last reported by webhooks; synced_at is the creation time of that event
+CREATE TABLE subscriptions(
+    id                      VARCHAR(255)    PRIMARY KEY,
+    user_id                 UUID            NOT NULL,
+    status                  VARCHAR(30)     NOT NULL,
+    price_id                VARCHAR(255)    NOT NULL,
+    plan                    VARCHAR(30),
+    current_period_end      TIMESTAMP,
+    cancel_at_period_end    BOOLEAN         DEFAULT false  NOT NULL,
+    synced_at               TIMESTAMP       NOT NULL,
+    created_at              TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at              TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_subscriptions_user_id ON subscriptions(user_id);
+
+-- Processed webhook events, so a redelivered event is only applied once
+CREATE TABLE billing_events(
+    id              VARCHAR(255)    PRIMARY KEY,
+    type            VARCHAR(100)    NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL
+);
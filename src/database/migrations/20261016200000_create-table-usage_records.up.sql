@@ -0,0 +1,13 @@
+-- Daily usage counts rolled up from the cache store; subject is "user:{id}" or "token:{id}" and
+-- user_id the user or the token's owner. Kept after users are deleted for billing
+CREATE TABLE usage_records(
+    day             DATE            NOT NULL,
+    subject         VARCHAR(64)     NOT NULL,
+    metric          VARCHAR(60)     NOT NULL,
+    user_id         UUID            NOT NULL,
+    count           BIGINT          NOT NULL DEFAULT 0,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    PRIMARY KEY (day, subject, metric)
+);
+
+CREATE INDEX idx_usage_records_user_id_day ON usage_records(user_id, day);
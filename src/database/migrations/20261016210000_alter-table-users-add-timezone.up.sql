@@ -0,0 +1,2 @@
+-- IANA timezone the user's emails and date filters are rendered in; NULL uses DEFAULT_TIMEZONE
+ALTER TABLE users ADD COLUMN timezone VARCHAR(64);
@@ -0,0 +1,5 @@
+ALTER TABLE users              ALTER COLUMN id SET DEFAULT uuid_generate_v4();
+ALTER TABLE tokens             ALTER COLUMN id SET DEFAULT uuid_generate_v4();
+ALTER TABLE api_tokens         ALTER COLUMN id SET DEFAULT uuid_generate_v4();
+ALTER TABLE audit_logs         ALTER COLUMN id SET DEFAULT uuid_generate_v4();
+ALTER TABLE email_domain_rules ALTER COLUMN id SET DEFAULT uuid_generate_v4();
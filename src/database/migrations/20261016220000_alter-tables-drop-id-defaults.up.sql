@@ -0,0 +1,7 @@
+-- IDs are generated by the app (UUIDv7 or ULID, see ID_FORMAT); existing random UUIDs stay valid,
+-- only rows created from now on are time-ordered
+ALTER TABLE users              ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE tokens             ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE api_tokens         ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE audit_logs         ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE email_domain_rules ALTER COLUMN id DROP DEFAULT;
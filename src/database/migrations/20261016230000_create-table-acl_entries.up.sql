@@ -0,0 +1,12 @@
+CREATE TABLE acl_entries(
+    id              UUID            PRIMARY KEY,
+    resource_type   VARCHAR(64)     NOT NULL,
+    resource_id     VARCHAR(64)     NOT NULL,
+    principal       VARCHAR(128)    NOT NULL,
+    permission      VARCHAR(16)     NOT NULL,
+    granted_by      UUID,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT uq_acl_entries UNIQUE (resource_type, resource_id, principal, permission)
+);
+
+CREATE INDEX idx_acl_entries_principal ON acl_entries(principal, resource_type);
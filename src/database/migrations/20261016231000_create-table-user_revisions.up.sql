@@ -0,0 +1,13 @@
+CREATE TABLE user_revisions(
+    id              UUID            PRIMARY KEY,
+    user_id         UUID            NOT NULL,
+    actor_id        UUID,
+    action          VARCHAR(16)     NOT NULL,
+    changes         JSONB           NOT NULL DEFAULT '{}',
+    restored_from   UUID,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_revisions_user_id_created_at ON user_revisions(user_id, created_at DESC);
@@ -0,0 +1,13 @@
+CREATE TABLE partners(
+    id              UUID            PRIMARY KEY,
+    name            VARCHAR(100)    NOT NULL,
+    secret_hash     VARCHAR(64)     NOT NULL,
+    prefix          VARCHAR(16)     NOT NULL,
+    created_by      UUID,
+    last_used_at    TIMESTAMP,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT uq_partners_name UNIQUE (name),
+    CONSTRAINT fk_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
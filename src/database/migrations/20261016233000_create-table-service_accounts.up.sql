@@ -0,0 +1,14 @@
+CREATE TABLE service_accounts(
+    id              UUID            PRIMARY KEY,
+    name            VARCHAR(100)    NOT NULL,
+    identity        VARCHAR(255)    NOT NULL,
+    rights          TEXT            NOT NULL,
+    created_by      UUID,
+    last_used_at    TIMESTAMP,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT uq_service_accounts_name UNIQUE (name),
+    CONSTRAINT uq_service_accounts_identity UNIQUE (identity),
+    CONSTRAINT fk_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
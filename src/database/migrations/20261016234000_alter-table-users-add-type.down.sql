@@ -0,0 +1,4 @@
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS actor_type;
+ALTER TABLE service_accounts DROP COLUMN IF EXISTS user_id;
+DELETE FROM users WHERE type = 'service';
+ALTER TABLE users DROP COLUMN IF EXISTS type;
@@ -0,0 +1,16 @@
+-- Service accounts are users without a password or a deliverable email; existing users are people
+ALTER TABLE users ADD COLUMN type VARCHAR(20) DEFAULT 'human' NOT NULL;
+
+-- Every service account is backed by a service user; existing accounts get one with the same ID
+ALTER TABLE service_accounts ADD COLUMN user_id UUID;
+INSERT INTO users (id, name, email, password, role, verified_email, type)
+SELECT id, name, 'service-account-' || id || '@service.invalid', '', 'user', TRUE, 'service'
+FROM service_accounts;
+UPDATE service_accounts SET user_id = id;
+ALTER TABLE service_accounts
+    ALTER COLUMN user_id SET NOT NULL,
+    ADD CONSTRAINT uq_service_accounts_user_id UNIQUE (user_id),
+    ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+-- Who acted: human, service or system; NULL for anonymous requests and entries recorded before
+ALTER TABLE audit_logs ADD COLUMN actor_type VARCHAR(20);
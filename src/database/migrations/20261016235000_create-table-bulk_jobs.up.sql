@@ -0,0 +1,33 @@
+CREATE TABLE bulk_jobs(
+    id              UUID            PRIMARY KEY,
+    action          VARCHAR(20)     NOT NULL,
+    value           VARCHAR(50)     NOT NULL    DEFAULT '',
+    status          VARCHAR(20)     NOT NULL,
+    total           INTEGER         NOT NULL,
+    succeeded       INTEGER         NOT NULL    DEFAULT 0,
+    skipped         INTEGER         NOT NULL    DEFAULT 0,
+    failed          INTEGER         NOT NULL    DEFAULT 0,
+    created_by      UUID,
+    started_at      TIMESTAMP,
+    finished_at     TIMESTAMP,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_bulk_jobs_status ON bulk_jobs(status, created_at);
+
+-- Items keep the user ID without a foreign key so the report outlives deleted users
+CREATE TABLE bulk_job_items(
+    id              UUID            PRIMARY KEY,
+    job_id          UUID            NOT NULL,
+    user_id         UUID            NOT NULL,
+    status          VARCHAR(20)     NOT NULL,
+    reason          VARCHAR(255)    NOT NULL    DEFAULT '',
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_job_id
+        FOREIGN KEY (job_id) REFERENCES bulk_jobs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_bulk_job_items_job_status ON bulk_job_items(job_id, status);
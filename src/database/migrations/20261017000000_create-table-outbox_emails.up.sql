@@ -0,0 +1,14 @@
+CREATE TABLE outbox_emails(
+    id                  UUID            PRIMARY KEY,
+    recipient           VARCHAR(255)    NOT NULL,
+    subject             VARCHAR(255)    NOT NULL,
+    body                TEXT            NOT NULL,
+    status              VARCHAR(20)     NOT NULL,
+    attempts            INTEGER         NOT NULL    DEFAULT 0,
+    next_attempt_at     TIMESTAMP       NOT NULL,
+    last_error          TEXT            NOT NULL    DEFAULT '',
+    created_at          TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at          TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL
+);
+
+CREATE INDEX idx_outbox_emails_due ON outbox_emails(status, next_attempt_at);
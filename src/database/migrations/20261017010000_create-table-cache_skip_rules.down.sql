@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cache_skip_rules;
\ No newline at end of file
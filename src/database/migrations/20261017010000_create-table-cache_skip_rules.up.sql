@@ -0,0 +1,10 @@
+CREATE TABLE cache_skip_rules(
+    id              UUID            PRIMARY KEY,
+    kind            VARCHAR(10)     NOT NULL,
+    value           VARCHAR(255)    NOT NULL,
+    created_by      UUID,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT uq_cache_skip_rules_kind_value UNIQUE (kind, value),
+    CONSTRAINT fk_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
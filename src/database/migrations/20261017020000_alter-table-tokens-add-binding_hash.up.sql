@@ -0,0 +1,3 @@
+-- Refresh tokens issued from now on are bound to a cookie of the device they were issued to;
+-- existing ones have no binding and keep working until they are rotated
+ALTER TABLE tokens ADD COLUMN binding_hash VARCHAR(64);
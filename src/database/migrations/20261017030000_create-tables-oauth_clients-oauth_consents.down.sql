@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_api_tokens_client_id;
+ALTER TABLE api_tokens DROP CONSTRAINT IF EXISTS fk_client;
+ALTER TABLE api_tokens DROP COLUMN IF EXISTS client_id;
+DROP TABLE IF EXISTS oauth_consents;
+DROP TABLE IF EXISTS oauth_clients;
@@ -0,0 +1,34 @@
+CREATE TABLE oauth_clients(
+    id              UUID            PRIMARY KEY,
+    name            VARCHAR(100)    NOT NULL,
+    secret_hash     VARCHAR(64)     NOT NULL,
+    prefix          VARCHAR(16)     NOT NULL,
+    redirect_uris   TEXT            NOT NULL,
+    scopes          TEXT            NOT NULL,
+    rate_limit      INTEGER         DEFAULT 0  NOT NULL,
+    created_by      UUID,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE oauth_consents(
+    id              UUID            PRIMARY KEY,
+    user_id         UUID            NOT NULL,
+    client_id       UUID            NOT NULL,
+    scopes          TEXT            NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT uq_oauth_consents_user_client UNIQUE (user_id, client_id),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_client
+        FOREIGN KEY (client_id) REFERENCES oauth_clients(id) ON DELETE CASCADE
+);
+
+-- Delegated access tokens are API tokens issued to a client; they go with the client or the consent
+ALTER TABLE api_tokens ADD COLUMN client_id UUID;
+ALTER TABLE api_tokens ADD CONSTRAINT fk_client
+    FOREIGN KEY (client_id) REFERENCES oauth_clients(id) ON DELETE CASCADE;
+CREATE INDEX idx_api_tokens_client_id ON api_tokens(client_id);
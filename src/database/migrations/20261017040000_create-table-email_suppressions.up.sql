@@ -0,0 +1,12 @@
+CREATE TABLE email_suppressions(
+    id              UUID            PRIMARY KEY,
+    email           VARCHAR(255)    NOT NULL,
+    reason          VARCHAR(20)     NOT NULL,
+    source          VARCHAR(20)     NOT NULL,
+    detail          TEXT            DEFAULT ''  NOT NULL,
+    created_by      UUID,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT uq_email_suppressions_email UNIQUE (email),
+    CONSTRAINT fk_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
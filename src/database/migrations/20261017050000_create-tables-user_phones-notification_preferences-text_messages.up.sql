@@ -0,0 +1,34 @@
+-- Phone numbers are encrypted, so they hold the sealed value rather than a bare number
+CREATE TABLE user_phones(
+    user_id         UUID            PRIMARY KEY,
+    phone           TEXT            NOT NULL,
+    verified_at     TIMESTAMP       NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE notification_preferences(
+    user_id         UUID            NOT NULL,
+    kind            VARCHAR(50)     NOT NULL,
+    channels        VARCHAR(100)    NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    PRIMARY KEY (user_id, kind),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE text_messages(
+    id                      UUID            PRIMARY KEY,
+    user_id                 UUID            NOT NULL,
+    kind                    VARCHAR(50)     NOT NULL,
+    channel                 VARCHAR(20)     NOT NULL,
+    provider                VARCHAR(20)     NOT NULL,
+    provider_message_id     VARCHAR(64)     NOT NULL,
+    status                  VARCHAR(20)     NOT NULL,
+    error                   TEXT            DEFAULT ''  NOT NULL,
+    created_at              TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at              TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT uq_text_messages_provider_message UNIQUE (provider, provider_message_id),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
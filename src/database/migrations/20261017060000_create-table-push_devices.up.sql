@@ -0,0 +1,15 @@
+-- A token identifies one app install, so it belongs to whichever user signed in on it last
+CREATE TABLE push_devices(
+    id              UUID            PRIMARY KEY,
+    user_id         UUID            NOT NULL,
+    platform        VARCHAR(10)     NOT NULL,
+    token           TEXT            NOT NULL,
+    name            VARCHAR(100)    DEFAULT ''  NOT NULL,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT uq_push_devices_token UNIQUE (token),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_push_devices_user_id ON push_devices(user_id);
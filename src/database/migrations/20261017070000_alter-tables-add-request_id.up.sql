@@ -0,0 +1,5 @@
+-- The X-Request-ID of the request that caused the entry; NULL for background work and entries recorded before
+ALTER TABLE audit_logs ADD COLUMN request_id VARCHAR(128);
+CREATE INDEX idx_audit_logs_request_id ON audit_logs(request_id) WHERE request_id IS NOT NULL;
+
+ALTER TABLE outbox_emails ADD COLUMN request_id VARCHAR(128) DEFAULT '' NOT NULL;
@@ -0,0 +1,5 @@
+-- Full-text document of the admin search (src/search/postgres.go); the expression must stay identical
+CREATE INDEX idx_audit_logs_search ON audit_logs USING GIN (
+    to_tsvector('simple', action || ' ' || translate(action, '._', '  ') || ' ' || COALESCE(ip, '') || ' ' ||
+        COALESCE(user_agent, '') || ' ' || metadata::text)
+);
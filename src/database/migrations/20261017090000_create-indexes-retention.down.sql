@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bulk_jobs_finished_at;
+DROP INDEX IF EXISTS idx_text_messages_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
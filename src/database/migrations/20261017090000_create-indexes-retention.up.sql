@@ -0,0 +1,4 @@
+-- Retention purges delete the oldest rows first, in batches
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+CREATE INDEX idx_text_messages_created_at ON text_messages(created_at);
+CREATE INDEX idx_bulk_jobs_finished_at ON bulk_jobs(finished_at) WHERE finished_at IS NOT NULL;
@@ -0,0 +1,11 @@
+-- RESTRICT makes deleting a user under hold fail, whichever code path tries it
+CREATE TABLE legal_holds(
+    user_id         UUID            PRIMARY KEY,
+    reason          VARCHAR(500)    NOT NULL,
+    placed_by       UUID,
+    created_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_placed_by
+        FOREIGN KEY (placed_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,10 @@
+-- Migrations that code written before them cannot run against record themselves here, so instances of
+-- an older release refuse to start on a database migrated past them (see src/schema)
+CREATE TABLE schema_breaking_changes(
+    version         BIGINT          PRIMARY KEY,
+    reason          VARCHAR(255)    NOT NULL
+);
+
+INSERT INTO schema_breaking_changes (version, reason) VALUES
+    (20261016220000, 'IDs are no longer generated by the database'),
+    (20261016234000, 'service accounts need a service user');
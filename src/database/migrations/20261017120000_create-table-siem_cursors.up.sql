@@ -0,0 +1,7 @@
+-- The last audit entry each SIEM sink received; forwarding resumes after it
+CREATE TABLE siem_cursors(
+    sink            VARCHAR(20)     PRIMARY KEY,
+    last_created_at TIMESTAMP       NOT NULL,
+    last_id         UUID            NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL
+);
@@ -0,0 +1,12 @@
+-- HyperLogLog sketches of unique visitors per period, saved from Redis so counts survive it losing its data
+CREATE TABLE analytics_sketches(
+    kind            VARCHAR(10)     NOT NULL,
+    period          VARCHAR(10)     NOT NULL,
+    endpoint        VARCHAR(255)    NOT NULL DEFAULT '',
+    sketch          BYTEA           NOT NULL,
+    count           BIGINT          NOT NULL,
+    updated_at      TIMESTAMP       DEFAULT CURRENT_TIMESTAMP  NOT NULL,
+    PRIMARY KEY (kind, period, endpoint)
+);
+
+CREATE INDEX idx_analytics_sketches_updated_at ON analytics_sketches(updated_at);
@@ -0,0 +1,5 @@
+-- The fencing token of the last write made under each lock; writes with an older token are rejected
+CREATE TABLE lock_fences(
+    name            VARCHAR(100)    PRIMARY KEY,
+    token           BIGINT          NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE partners RENAME COLUMN signing_key TO secret_hash;
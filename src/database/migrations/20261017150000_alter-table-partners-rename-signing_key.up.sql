@@ -0,0 +1,4 @@
+-- The signing key is encrypted by the app; keys stored before stay readable as plaintext until
+-- `make reencrypt` encrypts them
+ALTER TABLE partners RENAME COLUMN secret_hash TO signing_key;
+ALTER TABLE partners ALTER COLUMN signing_key TYPE TEXT;
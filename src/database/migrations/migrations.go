@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations, so a build knows the schema it was written for
+package migrations
+
+import "embed"
+
+// FS holds the migrations applied with golang-migrate (see the Makefile)
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,80 @@
+package dbretry
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Class tells whether a database error is worth trying again
+type Class int
+
+const (
+	// Permanent errors fail the same way when run again, e.g. constraint violations or missing rows
+	Permanent Class = iota
+	// Serialization errors abort a transaction that conflicted with another one (40001, 40P01)
+	Serialization
+	// Connection errors lose the connection to Postgres, e.g. a reset or a dropped pooled connection
+	Connection
+	// Failover errors come from a server shutting down, starting up or demoted to a read-only replica
+	Failover
+)
+
+func (c Class) String() string {
+	switch c {
+	case Serialization:
+		return "serialization"
+	case Connection:
+		return "connection"
+	case Failover:
+		return "failover"
+	default:
+		return "permanent"
+	}
+}
+
+// Transient reports whether running the operation again may succeed
+func (c Class) Transient() bool {
+	return c != Permanent
+}
+
+// Classify sorts a database error by whether it is worth trying again. Cancelled and timed out
+// contexts are permanent: the caller has given up.
+func Classify(err error) Class {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return Permanent
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001", pgErr.Code == "40P01":
+			return Serialization
+		// admin_shutdown, crash_shutdown, cannot_connect_now, read_only_sql_transaction
+		case pgErr.Code == "57P01", pgErr.Code == "57P02", pgErr.Code == "57P03", pgErr.Code == "25006":
+			return Failover
+		case strings.HasPrefix(pgErr.Code, "08"):
+			return Connection
+		}
+		return Permanent
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, net.ErrClosed) || pgconn.SafeToRetry(err) {
+		return Connection
+	}
+
+	var netErr *net.OpError
+	if errors.As(err, &netErr) && !netErr.Timeout() {
+		return Connection
+	}
+
+	return Permanent
+}
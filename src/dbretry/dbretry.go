@@ -0,0 +1,101 @@
+// Package dbretry runs GORM operations again after transient errors: serialization failures,
+// lost connections and failovers. Reads are retried on their own; a write is only retried when the
+// caller declares it Idempotent, since a write cut off mid-flight may already have been applied.
+package dbretry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"app/src/config"
+	"app/src/metrics"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type options struct {
+	idempotent bool
+	maxRetries int
+}
+
+// Option changes how an operation is retried
+type Option func(*options)
+
+// Idempotent allows a write to be retried, because running it twice has the same effect as once
+// (e.g. an upsert, a delete by key, or a transaction guarded by a unique event ID)
+func Idempotent() Option {
+	return func(o *options) { o.idempotent = true }
+}
+
+// MaxRetries overrides how many times the operation is run again (config.DatabaseRetry.MaxRetries)
+func MaxRetries(n int) Option {
+	return func(o *options) { o.maxRetries = max(n, 0) }
+}
+
+// Read runs fn, which must only read, retrying it after transient errors
+func Read(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error, opts ...Option) error {
+	return run(ctx, "read", true, opts, func() error {
+		return fn(db.WithContext(ctx))
+	})
+}
+
+// Write runs fn, retrying it after transient errors only when it is Idempotent
+func Write(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error, opts ...Option) error {
+	return run(ctx, "write", false, opts, func() error {
+		return fn(db.WithContext(ctx))
+	})
+}
+
+// Transaction runs fn in a transaction, retrying the whole transaction after transient errors only
+// when it is Idempotent. fn must not have side effects outside the database.
+func Transaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error, opts ...Option) error {
+	return run(ctx, "write", false, opts, func() error {
+		return db.WithContext(ctx).Transaction(fn)
+	})
+}
+
+func run(ctx context.Context, operation string, idempotent bool, opts []Option, fn func() error) error {
+	o := options{idempotent: idempotent, maxRetries: config.DatabaseRetry.MaxRetries}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		class := Classify(err)
+		if !o.idempotent || !class.Transient() || attempt >= o.maxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		delay := backoff(attempt)
+		logrus.WithFields(logrus.Fields{
+			"operation": operation,
+			"class":     class.String(),
+			"attempt":   attempt + 1,
+		}).Warnf("Database %s failed, retrying in %v: %v", operation, delay, err)
+		metrics.DBRetried(operation, class.String())
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff is an exponential delay with full jitter
+func backoff(attempt int) time.Duration {
+	ceiling := config.DatabaseRetry.BaseDelay << attempt
+	if ceiling <= 0 || ceiling > config.DatabaseRetry.MaxDelay {
+		ceiling = config.DatabaseRetry.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return rand.N(ceiling + 1)
+}
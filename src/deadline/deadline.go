@@ -0,0 +1,31 @@
+// Package deadline bounds calls to dependencies. The deadline of a call is the earliest of the
+// request deadline minus a safety margin and the configured limit of the dependency, so a slow
+// Postgres, Redis, SMTP server or OAuth provider cannot hold a Fiber worker past its request.
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"app/src/config"
+)
+
+// For derives the context of a call to dependency from ctx, ending at the earlier of the request
+// deadline minus config.Deadlines.Margin and the dependency's limit. The cancel func must be called
+// once the call is done.
+func For(ctx context.Context, dependency string) (context.Context, context.CancelFunc) {
+	var deadline time.Time
+	if limit := config.Deadlines.Limits[dependency]; limit > 0 {
+		deadline = time.Now().Add(limit)
+	}
+	if request, ok := ctx.Deadline(); ok {
+		if margined := request.Add(-config.Deadlines.Margin); deadline.IsZero() || margined.Before(deadline) {
+			deadline = margined
+		}
+	}
+
+	if deadline.IsZero() {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, deadline)
+}
@@ -0,0 +1,75 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+
+	"app/src/config"
+
+	"gorm.io/gorm"
+)
+
+// callKey stores the deadline of a statement between its callbacks
+const callKey = "deadline:call"
+
+// call is the deadline put on a statement, and the context it had before
+type call struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// GormPlugin puts a deadline on every statement, released once the statement finishes. The
+// statement gets its own context back afterwards, so a chain reused for several statements
+// (e.g. Count then Find) gives each its own deadline.
+type GormPlugin struct{}
+
+var _ gorm.Plugin = GormPlugin{}
+
+func (GormPlugin) Name() string {
+	return "deadline"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("deadline:before_create", begin),
+		callbacks.Create().After("gorm:after_create").Register("deadline:after_create", end),
+		callbacks.Query().Before("gorm:query").Register("deadline:before_query", begin),
+		callbacks.Query().After("gorm:after_query").Register("deadline:after_query", end),
+		callbacks.Update().Before("gorm:update").Register("deadline:before_update", begin),
+		callbacks.Update().After("gorm:after_update").Register("deadline:after_update", end),
+		callbacks.Delete().Before("gorm:delete").Register("deadline:before_delete", begin),
+		callbacks.Delete().After("gorm:after_delete").Register("deadline:after_delete", end),
+		callbacks.Raw().Before("gorm:raw").Register("deadline:before_raw", begin),
+		callbacks.Raw().After("gorm:raw").Register("deadline:after_raw", end),
+		// Rows are scanned by the caller after the callbacks return, so their deadline is only
+		// released when it passes
+		callbacks.Row().Before("gorm:row").Register("deadline:before_row", begin),
+		callbacks.Row().After("gorm:row").Register("deadline:after_row", func(db *gorm.DB) { restore(db) }),
+	)
+}
+
+func begin(db *gorm.DB) {
+	parent := db.Statement.Context
+	ctx, cancel := For(parent, config.DeadlineDB)
+	db.Statement.Context = ctx
+	db.InstanceSet(callKey, call{parent: parent, cancel: cancel})
+}
+
+func end(db *gorm.DB) {
+	if c := restore(db); c != nil {
+		c.cancel()
+	}
+}
+
+// restore gives the statement its context from before the deadline back
+func restore(db *gorm.DB) *call {
+	value, ok := db.InstanceGet(callKey)
+	if !ok {
+		return nil
+	}
+
+	c := value.(call)
+	db.Statement.Context = c.parent
+	return &c
+}
@@ -0,0 +1,35 @@
+package deadline
+
+import (
+	"context"
+
+	"app/src/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHook puts a deadline on every command and pipeline
+type RedisHook struct{}
+
+var _ redis.Hook = RedisHook{}
+
+// DialHook leaves dialing to the client's DialTimeout
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, cancel := For(ctx, config.DeadlineRedis)
+		defer cancel()
+		return next(ctx, cmd)
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, cancel := For(ctx, config.DeadlineRedis)
+		defer cancel()
+		return next(ctx, cmds)
+	}
+}
@@ -0,0 +1,38 @@
+// Package dryrun lets destructive admin endpoints report what they would affect instead of acting.
+// A service checks Requested right before it changes anything and returns Stop with its report;
+// utils.ErrorHandler answers a Preview with 200 and the report, so controllers need no changes.
+package dryrun
+
+import (
+	"app/src/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SampleSize is how many affected IDs or keys a report lists
+const SampleSize = 10
+
+// Requested reports whether the request asked for a dry run with ?dryRun=true
+func Requested(c *fiber.Ctx) bool {
+	return c.QueryBool("dryRun")
+}
+
+// Preview ends a dry run with the report of what the action would have done
+type Preview struct {
+	Report response.DryRun
+}
+
+func (p *Preview) Error() string {
+	return "dry run: " + p.Report.Action
+}
+
+// Stop returns the report as a Preview, keeping at most SampleSize sample entries
+func Stop(report response.DryRun) error {
+	if report.Sample == nil {
+		report.Sample = []string{}
+	}
+	if len(report.Sample) > SampleSize {
+		report.Sample = report.Sample[:SampleSize]
+	}
+	return &Preview{Report: report}
+}
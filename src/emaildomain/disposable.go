@@ -0,0 +1,131 @@
+package emaildomain
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"app/src/httpclient"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DisposableList is an in-memory set of disposable email domains refreshed from a downloadable list
+type DisposableList struct {
+	url    string
+	client *http.Client
+
+	mu      sync.RWMutex
+	domains map[string]struct{}
+}
+
+// NewDisposableList creates an empty list that downloads from url
+func NewDisposableList(url string) *DisposableList {
+	return &DisposableList{
+		url:     url,
+		client:  httpclient.New("disposable-email-list", httpclient.WithTimeout(30*time.Second)),
+		domains: make(map[string]struct{}),
+	}
+}
+
+// Contains reports whether domain, or a domain it belongs to, is disposable
+func (d *DisposableList) Contains(domain string) bool {
+	if d == nil {
+		return false
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for domain != "" {
+		if _, ok := d.domains[domain]; ok {
+			return true
+		}
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+	return false
+}
+
+// Len returns the number of known disposable domains
+func (d *DisposableList) Len() int {
+	if d == nil {
+		return 0
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.domains)
+}
+
+// Refresh downloads the list and replaces the current set; on failure the old set is kept
+func (d *DisposableList) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download disposable email list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download disposable email list: status %d", resp.StatusCode)
+	}
+
+	domains, err := parseDisposableList(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.domains = domains
+	d.mu.Unlock()
+
+	return nil
+}
+
+// Start refreshes the list immediately and then every interval until ctx is cancelled
+func (d *DisposableList) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Refresh(ctx); err != nil {
+			logrus.Warnf("Disposable email list refresh failed: %v", err)
+		} else {
+			logrus.Debugf("Disposable email list refreshed (%d domains)", d.Len())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseDisposableList reads one domain per line, skipping blanks and comments
+func parseDisposableList(r io.Reader) (map[string]struct{}, error) {
+	domains := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		domains[line] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read disposable email list: %w", err)
+	}
+	return domains, nil
+}
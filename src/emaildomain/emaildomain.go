@@ -0,0 +1,31 @@
+package emaildomain
+
+import "strings"
+
+// Domain returns the lowercased domain of an email address, or "" if it has none
+func Domain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(email[at+1:])), ".")
+}
+
+// Matches reports whether domain matches pattern: either an exact domain ("example.com") or a
+// wildcard for its subdomains ("*.example.com", which does not match example.com itself)
+func Matches(pattern, domain string) bool {
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(domain, "."+suffix)
+	}
+	return domain == pattern
+}
+
+// MatchesAny reports whether domain matches at least one of the patterns
+func MatchesAny(patterns []string, domain string) bool {
+	for _, pattern := range patterns {
+		if Matches(pattern, domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+// Package emailfeedback parses the bounce, complaint and unsubscribe notifications email providers
+// post to the app, so the recipients they report can be suppressed.
+package emailfeedback
+
+import (
+	"errors"
+	"strings"
+)
+
+// Providers whose notifications can be parsed
+const (
+	ProviderSES      = "ses"
+	ProviderSendGrid = "sendgrid"
+)
+
+// Reasons a recipient is suppressed
+const (
+	ReasonHardBounce  = "hard_bounce"
+	ReasonComplaint   = "complaint"
+	ReasonUnsubscribe = "unsubscribe"
+)
+
+var (
+	// ErrUnknownProvider means no parser exists for the provider
+	ErrUnknownProvider = errors.New("unknown email provider")
+
+	// ErrInvalidPayload means the notification could not be decoded
+	ErrInvalidPayload = errors.New("invalid email feedback payload")
+)
+
+// Event reports that mail to Email should stop for Reason
+type Event struct {
+	Email  string
+	Reason string
+	// Detail is the provider's explanation, such as a diagnostic code or complaint type
+	Detail string
+}
+
+// Feedback is what one notification reported
+type Feedback struct {
+	Events []Event
+	// SubscribeURL must be visited to confirm an SNS subscription; set only on confirmation requests
+	SubscribeURL string
+}
+
+// Parse decodes a notification posted by provider. Events that do not call for suppression, such
+// as soft bounces and deliveries, are left out.
+func Parse(provider string, payload []byte) (*Feedback, error) {
+	switch provider {
+	case ProviderSES:
+		return parseSES(payload)
+	case ProviderSendGrid:
+		return parseSendGrid(payload)
+	default:
+		return nil, ErrUnknownProvider
+	}
+}
+
+// newEvent normalizes the address, returning false when there is none
+func newEvent(email, reason, detail string) (Event, bool) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return Event{}, false
+	}
+	return Event{Email: email, Reason: reason, Detail: detail}, true
+}
@@ -0,0 +1,53 @@
+package emailfeedback
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// sendGridEvent is one entry of a SendGrid Event Webhook batch
+type sendGridEvent struct {
+	Email  string `json:"email"`
+	Event  string `json:"event"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+	Status string `json:"status"`
+}
+
+// parseSendGrid decodes a SendGrid Event Webhook batch. Bounces other than blocks, spam reports
+// and unsubscribes are reported; deliveries, opens, clicks and deferrals are left out.
+func parseSendGrid(payload []byte) (*Feedback, error) {
+	var events []sendGridEvent
+	if err := json.Unmarshal(payload, &events); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	feedback := new(Feedback)
+	for _, entry := range events {
+		var event Event
+		var ok bool
+
+		switch entry.Event {
+		case "bounce":
+			// Blocks are temporary refusals by the receiving server, not invalid addresses
+			if entry.Type == "blocked" {
+				continue
+			}
+			detail := entry.Reason
+			if detail == "" {
+				detail = entry.Status
+			}
+			event, ok = newEvent(entry.Email, ReasonHardBounce, detail)
+		case "spamreport":
+			event, ok = newEvent(entry.Email, ReasonComplaint, "spamreport")
+		case "unsubscribe", "group_unsubscribe":
+			event, ok = newEvent(entry.Email, ReasonUnsubscribe, entry.Event)
+		}
+
+		if ok {
+			feedback.Events = append(feedback.Events, event)
+		}
+	}
+
+	return feedback, nil
+}
@@ -0,0 +1,92 @@
+package emailfeedback
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// snsEnvelope is the SNS message SES notifications are delivered in
+type snsEnvelope struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// sesNotification is an SES feedback notification; notificationType is set by identity
+// notifications and eventType by configuration set event publishing
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Bounce           struct {
+		BounceType        string `json:"bounceType"`
+		BounceSubType     string `json:"bounceSubType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// parseSES decodes an SNS message carrying an SES notification, or the bare notification when the
+// subscription uses raw message delivery. Only permanent bounces and complaints are reported.
+func parseSES(payload []byte) (*Feedback, error) {
+	var envelope snsEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	message := payload
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		return &Feedback{SubscribeURL: envelope.SubscribeURL}, nil
+	case "Notification":
+		message = []byte(envelope.Message)
+	case "":
+	default:
+		// UnsubscribeConfirmation and future message types carry no feedback
+		return &Feedback{}, nil
+	}
+
+	var notification sesNotification
+	if err := json.Unmarshal(message, &notification); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	feedback := new(Feedback)
+	kind := notification.NotificationType
+	if kind == "" {
+		kind = notification.EventType
+	}
+
+	switch kind {
+	case "Bounce":
+		// Transient bounces (full mailbox, greylisting) may succeed later
+		if notification.Bounce.BounceType != "Permanent" {
+			break
+		}
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			detail := recipient.DiagnosticCode
+			if detail == "" {
+				detail = notification.Bounce.BounceSubType
+			}
+			if event, ok := newEvent(recipient.EmailAddress, ReasonHardBounce, detail); ok {
+				feedback.Events = append(feedback.Events, event)
+			}
+		}
+	case "Complaint":
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			event, ok := newEvent(recipient.EmailAddress, ReasonComplaint, notification.Complaint.ComplaintFeedbackType)
+			if ok {
+				feedback.Events = append(feedback.Events, event)
+			}
+		}
+	}
+
+	return feedback, nil
+}
@@ -0,0 +1,132 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Prefix marks an encrypted value: enc:v1:{keyID}:{base64(nonce|ciphertext)}
+const Prefix = "enc:v1:"
+
+var (
+	// ErrNoKeys is returned when encrypting without a configured keyring
+	ErrNoKeys = errors.New("encryption: no keys configured")
+	// ErrUnknownKey is returned when a value was encrypted with a key that is no longer configured
+	ErrUnknownKey = errors.New("encryption: unknown key")
+	// ErrMalformed is returned for values that carry the prefix but can't be decoded
+	ErrMalformed = errors.New("encryption: malformed value")
+)
+
+// Keyring encrypts with its primary key and decrypts with any of its keys, so keys can be rotated
+type Keyring struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring from raw AES keys (16, 24 or 32 bytes); primary encrypts new values
+func NewKeyring(keys map[string][]byte, primary string) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("encryption: primary key %q not found", primary)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("encryption: key id %q must not contain ':'", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption: key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("encryption: key %q: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+
+	return &Keyring{primary: primary, aeads: aeads}, nil
+}
+
+// Primary returns the ID of the key used for new values
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// Encrypt seals plaintext with the primary key
+func (k *Keyring) Encrypt(plaintext []byte) (string, error) {
+	if k == nil {
+		return "", ErrNoKeys
+	}
+
+	aead := k.aeads[k.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return Prefix + k.primary + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt with whichever key sealed it
+// Values without the prefix are returned unchanged, so a column can be encrypted after it holds data
+func (k *Keyring) Decrypt(value string) ([]byte, error) {
+	if !IsEncrypted(value) {
+		return []byte(value), nil
+	}
+	if k == nil {
+		return nil, ErrNoKeys
+	}
+
+	id, payload, ok := strings.Cut(strings.TrimPrefix(value, Prefix), ":")
+	if !ok {
+		return nil, ErrMalformed
+	}
+
+	aead, ok := k.aeads[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return plaintext, nil
+}
+
+// NeedsRotation reports whether a stored value is plaintext or sealed with a non-primary key
+func (k *Keyring) NeedsRotation(value string) bool {
+	return !strings.HasPrefix(value, Prefix+k.primary+":")
+}
+
+// IsEncrypted reports whether a stored value carries the encryption prefix
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// Use sets the keyring used by the "encrypted" GORM serializer
+func Use(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+// Default returns the keyring set by Use, or nil
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
@@ -0,0 +1,43 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"app/src/config"
+)
+
+// KMSClient unwraps data keys that were encrypted by a key management service (AWS KMS, GCP KMS, Vault transit)
+type KMSClient interface {
+	Decrypt(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// NewFromConfig builds a keyring from ENCRYPTION_KEYS; the first key is primary
+// Returns nil without error when no keys are configured. When ENCRYPTION_KMS is set,
+// keys are unwrapped with kms, which must then be non-nil.
+func NewFromConfig(ctx context.Context, kms KMSClient) (*Keyring, error) {
+	if len(config.EncryptionKeys) == 0 {
+		return nil, nil
+	}
+	if config.EncryptionKMS && kms == nil {
+		return nil, fmt.Errorf("encryption: ENCRYPTION_KMS is set but no KMS client is configured")
+	}
+
+	keys := make(map[string][]byte, len(config.EncryptionKeys))
+	for _, entry := range config.EncryptionKeys {
+		key, err := base64.StdEncoding.DecodeString(entry.Key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption: key %q is not valid base64: %w", entry.ID, err)
+		}
+
+		if config.EncryptionKMS {
+			if key, err = kms.Decrypt(ctx, key); err != nil {
+				return nil, fmt.Errorf("encryption: unwrap key %q: %w", entry.ID, err)
+			}
+		}
+		keys[entry.ID] = key
+	}
+
+	return NewKeyring(keys, config.EncryptionKeys[0].ID)
+}
@@ -0,0 +1,92 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Result summarises re-encryption of one column
+type Result struct {
+	Column
+	Scanned     int
+	Reencrypted int
+	Failed      int
+}
+
+type row struct {
+	ID    string
+	Value string
+}
+
+// Reencrypt rewrites every registered column value that is plaintext or sealed with an old key
+// under the primary key. Rows are processed in batches by primary key, and a row is only
+// updated if it did not change meanwhile, so it is safe to run against a live database.
+// Values that fail to decrypt are counted and left untouched.
+func Reencrypt(ctx context.Context, db *gorm.DB, k *Keyring, batchSize int) ([]Result, error) {
+	if k == nil {
+		return nil, ErrNoKeys
+	}
+
+	var results []Result
+	for _, col := range Columns() {
+		result, err := reencryptColumn(ctx, db, k, col, batchSize)
+		results = append(results, result)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+func reencryptColumn(ctx context.Context, db *gorm.DB, k *Keyring, col Column, batchSize int) (Result, error) {
+	result := Result{Column: col}
+	current := Prefix + k.Primary() + ":%"
+	lastID := ""
+
+	for {
+		var rows []row
+		err := db.WithContext(ctx).
+			Table(col.Table).
+			Select(fmt.Sprintf("id::text AS id, %s AS value", quote(col.Column))).
+			Where(fmt.Sprintf("id::text > ? AND %s IS NOT NULL AND %s NOT LIKE ?", quote(col.Column), quote(col.Column)), lastID, current).
+			Order("id::text").
+			Limit(batchSize).
+			Scan(&rows).Error
+		if err != nil {
+			return result, fmt.Errorf("encryption: scan %s.%s: %w", col.Table, col.Column, err)
+		}
+		if len(rows) == 0 {
+			return result, nil
+		}
+
+		for _, r := range rows {
+			lastID = r.ID
+			result.Scanned++
+
+			plaintext, err := k.Decrypt(r.Value)
+			if err != nil {
+				result.Failed++
+				continue
+			}
+			sealed, err := k.Encrypt(plaintext)
+			if err != nil {
+				return result, err
+			}
+
+			err = db.WithContext(ctx).
+				Table(col.Table).
+				Where(fmt.Sprintf("id::text = ? AND %s = ?", quote(col.Column)), r.ID, r.Value).
+				Update(col.Column, sealed).Error
+			if err != nil {
+				return result, fmt.Errorf("encryption: update %s.%s: %w", col.Table, col.Column, err)
+			}
+			result.Reencrypted++
+		}
+	}
+}
+
+func quote(identifier string) string {
+	return `"` + identifier + `"`
+}
@@ -0,0 +1,108 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the GORM serializer for encrypted columns:
+//
+//	Phone string `gorm:"serializer:encrypted"`
+//
+// Supported field types are string, *string and []byte. Call Register for the column too,
+// so the re-encryption command covers it after a key rotation.
+const SerializerName = "encrypted"
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Column identifies an encrypted column
+type Column struct {
+	Table  string
+	Column string
+}
+
+var (
+	columnsMu sync.Mutex
+	columns   []Column
+)
+
+// Register adds a column to the set re-encrypted after a key rotation
+func Register(table, column string) {
+	columnsMu.Lock()
+	defer columnsMu.Unlock()
+	columns = append(columns, Column{Table: table, Column: column})
+}
+
+// Columns returns the registered encrypted columns
+func Columns() []Column {
+	columnsMu.Lock()
+	defer columnsMu.Unlock()
+	return append([]Column(nil), columns...)
+}
+
+// Serializer encrypts field values on write and decrypts them on read using the Default keyring
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var stored string
+		switch v := dbValue.(type) {
+		case []byte:
+			stored = string(v)
+		case string:
+			stored = v
+		default:
+			return fmt.Errorf("encryption: unsupported database value %T for %s", dbValue, field.Name)
+		}
+
+		plaintext, err := Default().Decrypt(stored)
+		if err != nil {
+			return fmt.Errorf("encryption: decrypt %s: %w", field.Name, err)
+		}
+
+		switch field.FieldType.Kind() {
+		case reflect.String:
+			fieldValue.Elem().SetString(string(plaintext))
+		case reflect.Ptr:
+			text := string(plaintext)
+			fieldValue.Elem().Set(reflect.ValueOf(&text))
+		case reflect.Slice:
+			fieldValue.Elem().SetBytes(plaintext)
+		default:
+			return fmt.Errorf("encryption: unsupported field type %s for %s", field.FieldType, field.Name)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+func (Serializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	var plaintext []byte
+	switch v := fieldValue.(type) {
+	case string:
+		plaintext = []byte(v)
+	case *string:
+		if v == nil {
+			return nil, nil
+		}
+		plaintext = []byte(*v)
+	case []byte:
+		if v == nil {
+			return nil, nil
+		}
+		plaintext = v
+	default:
+		return nil, fmt.Errorf("encryption: unsupported field type %T for %s", fieldValue, field.Name)
+	}
+
+	return Default().Encrypt(plaintext)
+}
@@ -0,0 +1,77 @@
+// Package httpclient builds the outbound HTTP clients used to call third-party APIs. Every client
+// shares the same timeouts, retries idempotent requests with jittered backoff, opens a circuit
+// breaker per host after repeated failures, and reports each attempt to logs and Prometheus.
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"app/src/config"
+)
+
+// Option customises a client built by New
+type Option func(*options)
+
+type options struct {
+	timeout        time.Duration
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	breakerFailure int
+	breakerTimeout time.Duration
+	transport      http.RoundTripper
+}
+
+// WithTimeout overrides the overall timeout of each call, retries included
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.timeout = timeout }
+}
+
+// WithRetries overrides how many times an idempotent request is retried (0 disables retries)
+func WithRetries(maxRetries int) Option {
+	return func(o *options) { o.maxRetries = maxRetries }
+}
+
+// WithRetryDelay overrides the backoff bounds between retries
+func WithRetryDelay(base, max time.Duration) Option {
+	return func(o *options) {
+		o.retryBaseDelay = base
+		o.retryMaxDelay = max
+	}
+}
+
+// WithBreaker overrides how many consecutive failures open a host's circuit, and for how long
+func WithBreaker(failureThreshold int, timeout time.Duration) Option {
+	return func(o *options) {
+		o.breakerFailure = failureThreshold
+		o.breakerTimeout = timeout
+	}
+}
+
+// WithTransport sets the underlying transport, e.g. for tests or custom TLS
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) { o.transport = transport }
+}
+
+// New returns a client named after the service it calls ("google", "captcha"), used as the
+// client label on metrics and logs. Defaults come from config.HTTPClient.
+func New(name string, opts ...Option) *http.Client {
+	o := options{
+		timeout:        config.HTTPClient.Timeout,
+		maxRetries:     config.HTTPClient.MaxRetries,
+		retryBaseDelay: config.HTTPClient.RetryBaseDelay,
+		retryMaxDelay:  config.HTTPClient.RetryMaxDelay,
+		breakerFailure: config.HTTPClient.BreakerFailureThreshold,
+		breakerTimeout: config.HTTPClient.BreakerTimeout,
+		transport:      http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &http.Client{
+		Timeout:   o.timeout,
+		Transport: newTransport(name, o),
+	}
+}
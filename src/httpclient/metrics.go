@@ -0,0 +1,65 @@
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"app/src/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Request outcomes
+const (
+	outcomeSuccess     = "success"
+	outcomeClientError = "client_error"
+	outcomeServerError = "server_error"
+	outcomeError       = "error"
+	outcomeCircuitOpen = "circuit_open"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Name:      "http_client_requests_total",
+		Help:      "Outbound HTTP attempts by client, host and outcome.",
+	}, []string{"client", "host", "outcome"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Name:      "http_client_request_duration_seconds",
+		Help:      "Outbound HTTP attempt latency by client and host.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"client", "host"})
+
+	breakerTransitionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Name:      "http_client_circuit_breaker_transitions_total",
+		Help:      "Outbound HTTP circuit breaker state transitions by client and host.",
+	}, []string{"client", "host", "from", "to"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, requestDuration, breakerTransitionsTotal)
+}
+
+func recordRequest(client, host, outcome string, duration time.Duration) {
+	requestsTotal.WithLabelValues(client, host, outcome).Inc()
+	if outcome != outcomeCircuitOpen {
+		requestDuration.WithLabelValues(client, host).Observe(duration.Seconds())
+	}
+}
+
+func recordTransition(client, host, from, to string) {
+	breakerTransitionsTotal.WithLabelValues(client, host, from, to).Inc()
+}
+
+func outcomeFor(status int) string {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return outcomeServerError
+	case status >= http.StatusBadRequest:
+		return outcomeClientError
+	}
+	return outcomeSuccess
+}
@@ -0,0 +1,171 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sony/gobreaker/v2"
+)
+
+// ErrCircuitOpen is returned without calling the host while its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// transport wraps a RoundTripper with per-host circuit breakers, retries and instrumentation
+type transport struct {
+	name string
+	opts options
+
+	mu       sync.Mutex
+	breakers map[string]*gobreaker.TwoStepCircuitBreaker[any]
+}
+
+func newTransport(name string, opts options) *transport {
+	return &transport{
+		name:     name,
+		opts:     opts,
+		breakers: make(map[string]*gobreaker.TwoStepCircuitBreaker[any]),
+	}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := isIdempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := t.attempt(req, attempt)
+		if errors.Is(err, ErrCircuitOpen) || !retryable || attempt >= t.opts.maxRetries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+		if resp != nil {
+			// Drain so the connection can be reused by the next attempt
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends the request once through the host's circuit breaker
+func (t *transport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	host := req.URL.Host
+
+	done, err := t.breaker(host).Allow()
+	if err != nil {
+		recordRequest(t.name, host, outcomeCircuitOpen, 0)
+		return nil, fmt.Errorf("%s %s: %w", t.name, host, ErrCircuitOpen)
+	}
+
+	start := time.Now()
+	resp, err := t.opts.transport.RoundTrip(req)
+	duration := time.Since(start)
+
+	done(err == nil && resp.StatusCode < http.StatusInternalServerError)
+
+	entry := logrus.WithFields(logrus.Fields{
+		"client":   t.name,
+		"method":   req.Method,
+		"host":     host,
+		"path":     req.URL.Path,
+		"attempt":  attempt + 1,
+		"duration": duration.String(),
+	})
+
+	if err != nil {
+		recordRequest(t.name, host, outcomeError, duration)
+		entry.WithError(err).Debug("Outbound request failed")
+		return nil, err
+	}
+
+	recordRequest(t.name, host, outcomeFor(resp.StatusCode), duration)
+	entry.WithField("status", resp.StatusCode).Debug("Outbound request")
+
+	return resp, nil
+}
+
+// breaker returns the circuit breaker for host, creating it on first use
+func (t *transport) breaker(host string) *gobreaker.TwoStepCircuitBreaker[any] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if cb, ok := t.breakers[host]; ok {
+		return cb
+	}
+
+	threshold := uint32(t.opts.breakerFailure)
+	cb := gobreaker.NewTwoStepCircuitBreaker[any](gobreaker.Settings{
+		Name:        t.name + ":" + host,
+		MaxRequests: 1,
+		Timeout:     t.opts.breakerTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= threshold },
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			logrus.Warnf("Circuit breaker '%s' state changed: %s -> %s", name, from, to)
+			recordTransition(t.name, host, from.String(), to.String())
+		},
+	})
+	t.breakers[host] = cb
+
+	return cb
+}
+
+// backoff is an exponential delay with full jitter, or the server's Retry-After when it is shorter than the cap
+func (t *transport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			if delay := time.Duration(seconds) * time.Second; delay <= t.opts.retryMaxDelay {
+				return delay
+			}
+		}
+	}
+
+	ceiling := t.opts.retryBaseDelay << attempt
+	if ceiling <= 0 || ceiling > t.opts.retryMaxDelay {
+		ceiling = t.opts.retryMaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return rand.N(ceiling + 1)
+}
+
+// isIdempotent reports whether the request can be sent again without side effects.
+// POSTs are only retried with an Idempotency-Key (Stripe): OAuth codes and CAPTCHA tokens are single use.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return req.Header.Get("Idempotency-Key") != ""
+	}
+	return false
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
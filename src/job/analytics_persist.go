@@ -0,0 +1,96 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/src/leader"
+	"app/src/locks"
+	"app/src/readonly"
+	"app/src/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// analyticsPersistLock guards the persist so only one instance saves the sketches per tick
+const analyticsPersistLock = "job:analytics-persist"
+
+// AnalyticsPersistJob periodically saves the unique visitor sketches from Redis to the database
+type AnalyticsPersistJob struct {
+	analyticsService service.AnalyticsService
+	locker           *locks.Locker
+	elector          *leader.Elector
+	interval         time.Duration
+	ctx              context.Context
+	cancel           context.CancelFunc
+	stopChan         chan struct{}
+}
+
+// NewAnalyticsPersistJob creates a new analytics persist job
+func NewAnalyticsPersistJob(
+	analyticsService service.AnalyticsService, locker *locks.Locker, elector *leader.Elector, interval time.Duration,
+) *AnalyticsPersistJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &AnalyticsPersistJob{
+		analyticsService: analyticsService,
+		locker:           locker,
+		elector:          elector,
+		interval:         interval,
+		ctx:              ctx,
+		cancel:           cancel,
+		stopChan:         make(chan struct{}),
+	}
+}
+
+// Start runs the persist on every tick until Stop is called
+func (j *AnalyticsPersistJob) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			logrus.Info("Analytics persist job stopped")
+			close(j.stopChan)
+			return
+		case <-ticker.C:
+			j.run()
+		}
+	}
+}
+
+// Stop gracefully shuts down the job
+func (j *AnalyticsPersistJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+func (j *AnalyticsPersistJob) run() {
+	if !j.elector.IsLeader() {
+		return
+	}
+
+	// Writers pause in read-only mode and catch up once it ends
+	if readonly.Enabled() {
+		logrus.Debug("Analytics persist skipped - read-only mode")
+		return
+	}
+
+	err := j.locker.WithLock(j.ctx, analyticsPersistLock, j.interval/2, func(ctx context.Context) error {
+		persisted, err := j.analyticsService.Persist(ctx)
+		if err != nil {
+			return err
+		}
+		logrus.Debugf("Analytics persisted for %d sketches", persisted)
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, locks.ErrLockNotAcquired):
+		logrus.Debug("Analytics persist skipped - running on another instance")
+	case err != nil:
+		logrus.Warnf("Analytics persist failed: %v", err)
+	}
+}
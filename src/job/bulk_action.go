@@ -0,0 +1,76 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/src/readonly"
+	"app/src/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BulkActionJob runs queued bulk user actions. Every instance polls the queue; a job is claimed by
+// one of them under a row lock, so no leader or distributed lock is needed.
+type BulkActionJob struct {
+	bulkActionService service.BulkActionService
+	interval          time.Duration
+	ctx               context.Context
+	cancel            context.CancelFunc
+	stopChan          chan struct{}
+}
+
+// NewBulkActionJob creates a job that checks the queue every interval
+func NewBulkActionJob(bulkActionService service.BulkActionService, interval time.Duration) *BulkActionJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &BulkActionJob{
+		bulkActionService: bulkActionService,
+		interval:          interval,
+		ctx:               ctx,
+		cancel:            cancel,
+		stopChan:          make(chan struct{}),
+	}
+}
+
+// Start runs queued jobs on every tick until Stop is called
+func (j *BulkActionJob) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			logrus.Info("Bulk action job stopped")
+			close(j.stopChan)
+			return
+		case <-ticker.C:
+			j.run()
+		}
+	}
+}
+
+// Stop gracefully shuts down the job; a bulk action cut short is resumed by another instance
+func (j *BulkActionJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+// run drains the queue, so jobs submitted together do not wait a tick each
+func (j *BulkActionJob) run() {
+	// In read-only mode no job is claimed, and a running one stops between batches
+	for j.ctx.Err() == nil && !readonly.Enabled() {
+		ran, err := j.bulkActionService.RunNext(j.ctx)
+		if err != nil {
+			// Stopping leaves the job running, to be resumed by another instance
+			if j.ctx.Err() == nil && !errors.Is(err, readonly.ErrReadOnly) {
+				logrus.Warnf("Bulk action failed: %v", err)
+			}
+			return
+		}
+		if !ran {
+			return
+		}
+	}
+}
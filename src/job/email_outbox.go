@@ -0,0 +1,72 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"app/src/readonly"
+	"app/src/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EmailOutboxJob sends the emails queued in the outbox. Every instance runs it; emails are leased
+// under a row lock, so each is sent by one of them.
+type EmailOutboxJob struct {
+	outboxService service.EmailOutboxService
+	interval      time.Duration
+	ctx           context.Context
+	cancel        context.CancelFunc
+	stopChan      chan struct{}
+}
+
+// NewEmailOutboxJob creates a job that sends the emails due every interval
+func NewEmailOutboxJob(outboxService service.EmailOutboxService, interval time.Duration) *EmailOutboxJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &EmailOutboxJob{
+		outboxService: outboxService,
+		interval:      interval,
+		ctx:           ctx,
+		cancel:        cancel,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start sends the emails due on every tick until Stop is called
+func (j *EmailOutboxJob) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			logrus.Info("Email outbox job stopped")
+			close(j.stopChan)
+			return
+		case <-ticker.C:
+			j.run()
+		}
+	}
+}
+
+// Stop gracefully shuts down the job
+func (j *EmailOutboxJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+func (j *EmailOutboxJob) run() {
+	// Queued emails wait for read-only mode to end, as sending them updates the outbox
+	if readonly.Enabled() {
+		return
+	}
+
+	sent, err := j.outboxService.DeliverDue(j.ctx)
+	if err != nil && j.ctx.Err() == nil {
+		logrus.Warnf("Email outbox delivery failed: %v", err)
+	}
+	if sent > 0 {
+		logrus.Debugf("Email outbox sent %d emails", sent)
+	}
+}
@@ -0,0 +1,96 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/src/leader"
+	"app/src/locks"
+	"app/src/readonly"
+	"app/src/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// quotaPersistLock guards the persist so only one instance copies the counters per tick
+const quotaPersistLock = "job:quota-persist"
+
+// QuotaPersistJob periodically copies request quota counters from the cache store to the database
+type QuotaPersistJob struct {
+	quotaService service.QuotaService
+	locker       *locks.Locker
+	elector      *leader.Elector
+	interval     time.Duration
+	ctx          context.Context
+	cancel       context.CancelFunc
+	stopChan     chan struct{}
+}
+
+// NewQuotaPersistJob creates a new quota persist job
+func NewQuotaPersistJob(
+	quotaService service.QuotaService, locker *locks.Locker, elector *leader.Elector, interval time.Duration,
+) *QuotaPersistJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &QuotaPersistJob{
+		quotaService: quotaService,
+		locker:       locker,
+		elector:      elector,
+		interval:     interval,
+		ctx:          ctx,
+		cancel:       cancel,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start runs the persist on every tick until Stop is called
+func (j *QuotaPersistJob) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			logrus.Info("Quota persist job stopped")
+			close(j.stopChan)
+			return
+		case <-ticker.C:
+			j.run()
+		}
+	}
+}
+
+// Stop gracefully shuts down the job
+func (j *QuotaPersistJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+func (j *QuotaPersistJob) run() {
+	if !j.elector.IsLeader() {
+		return
+	}
+
+	// Writers pause in read-only mode and catch up once it ends
+	if readonly.Enabled() {
+		logrus.Debug("Quota persist skipped - read-only mode")
+		return
+	}
+
+	err := j.locker.WithLock(j.ctx, quotaPersistLock, j.interval/2, func(ctx context.Context) error {
+		persisted, err := j.quotaService.Persist(ctx)
+		if err != nil {
+			return err
+		}
+		logrus.Debugf("Request quota usage persisted for %d counters", persisted)
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, locks.ErrLockNotAcquired):
+		logrus.Debug("Quota persist skipped - running on another instance")
+	case err != nil:
+		logrus.Warnf("Quota persist failed: %v", err)
+	}
+}
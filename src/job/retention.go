@@ -0,0 +1,97 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/src/leader"
+	"app/src/locks"
+	"app/src/readonly"
+	"app/src/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// retentionLock guards the purge so only one instance deletes rows per tick
+const retentionLock = "job:retention"
+
+// RetentionJob periodically deletes rows past the retention of their policy
+type RetentionJob struct {
+	retentionService service.RetentionService
+	locker           *locks.Locker
+	elector          *leader.Elector
+	interval         time.Duration
+	ctx              context.Context
+	cancel           context.CancelFunc
+	stopChan         chan struct{}
+}
+
+// NewRetentionJob creates a new retention job
+func NewRetentionJob(
+	retentionService service.RetentionService, locker *locks.Locker, elector *leader.Elector, interval time.Duration,
+) *RetentionJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &RetentionJob{
+		retentionService: retentionService,
+		locker:           locker,
+		elector:          elector,
+		interval:         interval,
+		ctx:              ctx,
+		cancel:           cancel,
+		stopChan:         make(chan struct{}),
+	}
+}
+
+// Start runs the purge on every tick until Stop is called
+func (j *RetentionJob) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			logrus.Info("Retention job stopped")
+			close(j.stopChan)
+			return
+		case <-ticker.C:
+			j.run()
+		}
+	}
+}
+
+// Stop gracefully shuts down the job
+func (j *RetentionJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+// run purges rows past their retention while holding the retention lock
+func (j *RetentionJob) run() {
+	if !j.elector.IsLeader() {
+		return
+	}
+
+	// Writers pause in read-only mode and catch up once it ends
+	if readonly.Enabled() {
+		logrus.Debug("Retention purge skipped - read-only mode")
+		return
+	}
+
+	// The lock expires after half the interval and cuts the purge short with it; batches already
+	// deleted stay deleted and the next tick carries on
+	err := j.locker.WithLock(j.ctx, retentionLock, j.interval/2, func(ctx context.Context) error {
+		_, err := j.retentionService.Purge(ctx)
+		return err
+	})
+
+	switch {
+	case errors.Is(err, locks.ErrLockNotAcquired):
+		logrus.Debug("Retention purge skipped - running on another instance")
+	case errors.Is(err, context.DeadlineExceeded):
+		logrus.Info("Retention purge ran out of time, continuing on the next run")
+	case err != nil:
+		logrus.Warnf("Retention purge failed: %v", err)
+	}
+}
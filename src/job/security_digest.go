@@ -0,0 +1,119 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/src/leader"
+	"app/src/locks"
+	"app/src/readonly"
+	"app/src/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// securityDigestLock guards the digest so a single instance sends it
+const securityDigestLock = "job:security-digest"
+
+// securityDigestPeriod is the span of audit log each digest covers
+const securityDigestPeriod = 7 * 24 * time.Hour
+
+// SecurityDigestJob emails admins a weekly summary of security events
+type SecurityDigestJob struct {
+	digestService service.SecurityDigestService
+	locker        *locks.Locker
+	elector       *leader.Elector
+	weekday       time.Weekday
+	hour          int
+	ctx           context.Context
+	cancel        context.CancelFunc
+	stopChan      chan struct{}
+}
+
+// NewSecurityDigestJob creates a job that sends the digest every weekday at hour (UTC)
+func NewSecurityDigestJob(
+	digestService service.SecurityDigestService, locker *locks.Locker, elector *leader.Elector,
+	weekday time.Weekday, hour int,
+) *SecurityDigestJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &SecurityDigestJob{
+		digestService: digestService,
+		locker:        locker,
+		elector:       elector,
+		weekday:       weekday,
+		hour:          hour,
+		ctx:           ctx,
+		cancel:        cancel,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// NextWeeklyRun returns the first time after now that falls on weekday at hour:00 UTC
+func NextWeeklyRun(now time.Time, weekday time.Weekday, hour int) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	next = next.AddDate(0, 0, (int(weekday)-int(next.Weekday())+7)%7)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+// Start waits for each scheduled time and sends the digest until Stop is called
+func (j *SecurityDigestJob) Start() {
+	for {
+		scheduled := NextWeeklyRun(time.Now(), j.weekday, j.hour)
+		timer := time.NewTimer(time.Until(scheduled))
+
+		select {
+		case <-j.ctx.Done():
+			timer.Stop()
+			logrus.Info("Security digest job stopped")
+			close(j.stopChan)
+			return
+		case <-timer.C:
+			j.run(scheduled)
+		}
+	}
+}
+
+// Stop gracefully shuts down the job
+func (j *SecurityDigestJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+// run sends the digest covering the week up to scheduled, unless another instance already did
+func (j *SecurityDigestJob) run(scheduled time.Time) {
+	if !j.elector.IsLeader() {
+		return
+	}
+
+	// Writers pause in read-only mode; a digest due while read-only is skipped until next week
+	if readonly.Enabled() {
+		logrus.Warn("Security digest skipped - read-only mode")
+		return
+	}
+
+	err := j.locker.WithLock(j.ctx, securityDigestLock, time.Hour, func(ctx context.Context) error {
+		sent, err := j.digestService.SentSince(ctx, scheduled)
+		if err != nil || sent {
+			return err
+		}
+
+		if err := j.digestService.Send(ctx, scheduled.Add(-securityDigestPeriod), scheduled); err != nil {
+			return err
+		}
+		logrus.Info("Security digest sent")
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, locks.ErrLockNotAcquired):
+		logrus.Debug("Security digest skipped - running on another instance")
+	case err != nil:
+		logrus.Warnf("Security digest failed: %v", err)
+	}
+}
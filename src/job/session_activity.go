@@ -0,0 +1,105 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/src/leader"
+	"app/src/locks"
+	"app/src/readonly"
+	"app/src/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sessionActivityLock guards the flush so only one instance copies buffered activity per tick
+const sessionActivityLock = "job:session-activity"
+
+// SessionActivityJob periodically flushes buffered session activity to the database and ends idle sessions
+type SessionActivityJob struct {
+	activityService service.SessionActivityService
+	locker          *locks.Locker
+	elector         *leader.Elector
+	interval        time.Duration
+	ctx             context.Context
+	cancel          context.CancelFunc
+	stopChan        chan struct{}
+}
+
+// NewSessionActivityJob creates a new session activity job
+func NewSessionActivityJob(
+	activityService service.SessionActivityService, locker *locks.Locker, elector *leader.Elector, interval time.Duration,
+) *SessionActivityJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &SessionActivityJob{
+		activityService: activityService,
+		locker:          locker,
+		elector:         elector,
+		interval:        interval,
+		ctx:             ctx,
+		cancel:          cancel,
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// Start runs the flush on every tick until Stop is called
+func (j *SessionActivityJob) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			logrus.Info("Session activity job stopped")
+			close(j.stopChan)
+			return
+		case <-ticker.C:
+			j.run()
+		}
+	}
+}
+
+// Stop gracefully shuts down the job
+func (j *SessionActivityJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+// run flushes activity and then reaps idle sessions, so sessions used since the last flush survive
+func (j *SessionActivityJob) run() {
+	if !j.elector.IsLeader() {
+		return
+	}
+
+	// Writers pause in read-only mode and catch up once it ends
+	if readonly.Enabled() {
+		logrus.Debug("Session activity flush skipped - read-only mode")
+		return
+	}
+
+	err := j.locker.WithLock(j.ctx, sessionActivityLock, j.interval/2, func(ctx context.Context) error {
+		flushed, err := j.activityService.Flush(ctx)
+		if err != nil {
+			return err
+		}
+		logrus.Debugf("Session activity flushed for %d sessions", flushed)
+
+		reaped, err := j.activityService.ReapIdle(ctx)
+		if err != nil {
+			return err
+		}
+		if reaped > 0 {
+			logrus.Infof("Ended %d idle sessions", reaped)
+		}
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, locks.ErrLockNotAcquired):
+		logrus.Debug("Session activity flush skipped - running on another instance")
+	case err != nil:
+		logrus.Warnf("Session activity flush failed: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"app/src/leader"
+	"app/src/readonly"
+	"app/src/siem"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SIEMForwardJob runs the SIEM forwarder on the leader, so every audit entry is sent by one instance.
+// Leadership is checked every interval: the forwarder starts on the instance winning it and stops
+// on the one losing it, and the new leader resumes after the last entry delivered.
+type SIEMForwardJob struct {
+	forwarder *siem.Forwarder
+	sink      siem.Sink
+	elector   *leader.Elector
+	interval  time.Duration
+	ctx       context.Context
+	cancel    context.CancelFunc
+	stopChan  chan struct{}
+
+	// stopForwarder stops the running forwarder, whose Run returns on done; nil while it is stopped
+	stopForwarder context.CancelFunc
+	done          chan struct{}
+}
+
+// NewSIEMForwardJob creates a job running forwarder on the leader, checked every interval
+func NewSIEMForwardJob(
+	forwarder *siem.Forwarder, sink siem.Sink, elector *leader.Elector, interval time.Duration,
+) *SIEMForwardJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &SIEMForwardJob{
+		forwarder: forwarder,
+		sink:      sink,
+		elector:   elector,
+		interval:  interval,
+		ctx:       ctx,
+		cancel:    cancel,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start follows leadership on every tick until Stop is called
+func (j *SIEMForwardJob) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			j.stop()
+			_ = j.sink.Close()
+			logrus.Info("SIEM forward job stopped")
+			close(j.stopChan)
+			return
+		case <-ticker.C:
+			j.run()
+		}
+	}
+}
+
+// Stop gracefully shuts down the job
+func (j *SIEMForwardJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+// run starts or stops the forwarder as leadership and read-only mode change
+func (j *SIEMForwardJob) run() {
+	// The forwarder writes its cursor, so it pauses in read-only mode and catches up once it ends
+	active := j.elector.IsLeader() && !readonly.Enabled()
+
+	if j.done != nil {
+		select {
+		case <-j.done:
+			// Run gave up, e.g. on a database error loading the cursor; the next tick starts it again
+			j.stopForwarder()
+			j.stopForwarder, j.done = nil, nil
+		default:
+		}
+	}
+
+	switch {
+	case active && j.done == nil:
+		ctx, cancel := context.WithCancel(j.ctx)
+		j.stopForwarder, j.done = cancel, make(chan struct{})
+		go func(done chan struct{}) {
+			defer close(done)
+			if err := j.forwarder.Run(ctx); err != nil {
+				logrus.Warnf("SIEM forwarding failed: %v", err)
+			}
+		}(j.done)
+		logrus.Debug("SIEM forwarding started on this instance")
+	case !active && j.done != nil:
+		j.stop()
+		logrus.Debug("SIEM forwarding stopped - not the leader or read-only mode")
+	}
+}
+
+// stop stops the running forwarder and waits for it to return
+func (j *SIEMForwardJob) stop() {
+	if j.done == nil {
+		return
+	}
+	j.stopForwarder()
+	<-j.done
+	j.stopForwarder, j.done = nil, nil
+}
@@ -0,0 +1,99 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/src/leader"
+	"app/src/locks"
+	"app/src/readonly"
+	"app/src/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// tokenCleanupLock is the lock name guarding the cleanup so only one instance runs it per tick
+const tokenCleanupLock = "job:token-cleanup"
+
+// TokenCleanupJob periodically deletes expired tokens
+type TokenCleanupJob struct {
+	tokenService service.TokenService
+	locker       *locks.Locker
+	elector      *leader.Elector
+	interval     time.Duration
+	ctx          context.Context
+	cancel       context.CancelFunc
+	stopChan     chan struct{}
+}
+
+// NewTokenCleanupJob creates a new token cleanup job
+func NewTokenCleanupJob(
+	tokenService service.TokenService, locker *locks.Locker, elector *leader.Elector, interval time.Duration,
+) *TokenCleanupJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &TokenCleanupJob{
+		tokenService: tokenService,
+		locker:       locker,
+		elector:      elector,
+		interval:     interval,
+		ctx:          ctx,
+		cancel:       cancel,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start runs the cleanup on every tick until Stop is called
+func (j *TokenCleanupJob) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			logrus.Info("Token cleanup job stopped")
+			close(j.stopChan)
+			return
+		case <-ticker.C:
+			j.run()
+		}
+	}
+}
+
+// Stop gracefully shuts down the job
+func (j *TokenCleanupJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+// run deletes expired tokens while holding the cleanup lock
+func (j *TokenCleanupJob) run() {
+	// Only the elected leader runs scheduled work; the lock guards leadership handover races
+	if !j.elector.IsLeader() {
+		return
+	}
+
+	// Writers pause in read-only mode and catch up once it ends
+	if readonly.Enabled() {
+		logrus.Debug("Token cleanup skipped - read-only mode")
+		return
+	}
+
+	// Lock for at most half the interval so a crashed holder never blocks the next tick
+	err := j.locker.WithLock(j.ctx, tokenCleanupLock, j.interval/2, func(ctx context.Context) error {
+		deleted, err := j.tokenService.DeleteExpiredTokens(ctx)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("Token cleanup removed %d expired tokens", deleted)
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, locks.ErrLockNotAcquired):
+		logrus.Debug("Token cleanup skipped - running on another instance")
+	case err != nil:
+		logrus.Warnf("Token cleanup failed: %v", err)
+	}
+}
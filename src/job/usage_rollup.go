@@ -0,0 +1,96 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/src/leader"
+	"app/src/locks"
+	"app/src/readonly"
+	"app/src/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// usageRollupLock guards the rollup so only one instance copies the counters per tick
+const usageRollupLock = "job:usage-rollup"
+
+// UsageRollupJob periodically rolls usage counters from the cache store into daily rows in the database
+type UsageRollupJob struct {
+	usageService service.UsageService
+	locker       *locks.Locker
+	elector      *leader.Elector
+	interval     time.Duration
+	ctx          context.Context
+	cancel       context.CancelFunc
+	stopChan     chan struct{}
+}
+
+// NewUsageRollupJob creates a new usage rollup job
+func NewUsageRollupJob(
+	usageService service.UsageService, locker *locks.Locker, elector *leader.Elector, interval time.Duration,
+) *UsageRollupJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &UsageRollupJob{
+		usageService: usageService,
+		locker:       locker,
+		elector:      elector,
+		interval:     interval,
+		ctx:          ctx,
+		cancel:       cancel,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start runs the rollup on every tick until Stop is called
+func (j *UsageRollupJob) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			logrus.Info("Usage rollup job stopped")
+			close(j.stopChan)
+			return
+		case <-ticker.C:
+			j.run()
+		}
+	}
+}
+
+// Stop gracefully shuts down the job
+func (j *UsageRollupJob) Stop() {
+	j.cancel()
+	<-j.stopChan
+}
+
+func (j *UsageRollupJob) run() {
+	if !j.elector.IsLeader() {
+		return
+	}
+
+	// Writers pause in read-only mode and catch up once it ends
+	if readonly.Enabled() {
+		logrus.Debug("Usage rollup skipped - read-only mode")
+		return
+	}
+
+	err := j.locker.WithLock(j.ctx, usageRollupLock, j.interval/2, func(ctx context.Context) error {
+		rolledUp, err := j.usageService.Rollup(ctx)
+		if err != nil {
+			return err
+		}
+		logrus.Debugf("Usage rolled up for %d counters", rolledUp)
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, locks.ErrLockNotAcquired):
+		logrus.Debug("Usage rollup skipped - running on another instance")
+	case err != nil:
+		logrus.Warnf("Usage rollup failed: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+
+	"app/src/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// LeaderKeyPrefix is the prefix for leadership lease keys
+// Format: leader:{name}
+const LeaderKeyPrefix = "leader:"
+
+// renewScript extends the lease only if it is still held by the caller
+var renewScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// resignScript drops the lease only if it is still held by the caller
+var resignScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Elector campaigns for a Redis lease so background workers run on exactly one instance.
+// The leader renews the lease every ttl/3; if it dies, the lease expires and another
+// instance takes over on its next attempt (automatic failover).
+type Elector struct {
+	redisClient *redis.RedisClient
+	name        string
+	owner       string
+	ttl         time.Duration
+	isLeader    atomic.Bool
+	ctx         context.Context
+	cancel      context.CancelFunc
+	stopChan    chan struct{}
+}
+
+// NewElector creates a new leader elector
+// Without Redis the instance is assumed to be alone and is always the leader
+func NewElector(redisClient *redis.RedisClient, name string, ttl time.Duration) *Elector {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	elector := &Elector{
+		redisClient: redisClient,
+		name:        name,
+		owner:       instanceID(),
+		ttl:         ttl,
+		ctx:         ctx,
+		cancel:      cancel,
+		stopChan:    make(chan struct{}),
+	}
+
+	if redisClient == nil {
+		elector.isLeader.Store(true)
+	}
+
+	return elector
+}
+
+// Start campaigns for leadership until Stop is called
+func (e *Elector) Start() {
+	defer close(e.stopChan)
+
+	if e.redisClient == nil {
+		<-e.ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	e.campaign()
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			e.resign()
+			logrus.Infof("Leader election '%s' stopped", e.name)
+			return
+		case <-ticker.C:
+			e.campaign()
+		}
+	}
+}
+
+// Stop ends the campaign and releases leadership so another instance can take over immediately
+func (e *Elector) Stop() {
+	e.cancel()
+	<-e.stopChan
+}
+
+// IsLeader returns true if this instance currently holds the lease
+func (e *Elector) IsLeader() bool {
+	if e == nil {
+		return true
+	}
+	return e.isLeader.Load()
+}
+
+// campaign renews the lease when leading, or tries to acquire it otherwise
+func (e *Elector) campaign() {
+	ctx, cancel := context.WithTimeout(e.ctx, e.ttl/3)
+	defer cancel()
+
+	client := e.redisClient.GetClient()
+	key := e.redisClient.Key(LeaderKeyPrefix + e.name)
+
+	var leading bool
+	if e.isLeader.Load() {
+		renewed, err := renewScript.Run(ctx, client, []string{key}, e.owner, e.ttl.Milliseconds()).Int()
+		leading = err == nil && renewed == 1
+	} else {
+		acquired, err := client.SetNX(ctx, key, e.owner, e.ttl).Result()
+		leading = err == nil && acquired
+	}
+
+	if previous := e.isLeader.Swap(leading); previous != leading {
+		if leading {
+			logrus.Infof("Instance %s became leader for '%s'", e.owner, e.name)
+		} else {
+			logrus.Warnf("Instance %s lost leadership for '%s'", e.owner, e.name)
+		}
+	}
+}
+
+// resign releases the lease if held
+func (e *Elector) resign() {
+	if !e.isLeader.Swap(false) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := e.redisClient.Key(LeaderKeyPrefix + e.name)
+	if err := resignScript.Run(ctx, e.redisClient.GetClient(), []string{key}, e.owner).Err(); err != nil {
+		logrus.Warnf("Failed to resign leadership for '%s': %v", e.name, err)
+	}
+}
+
+// instanceID generates a unique identifier for this process
+func instanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
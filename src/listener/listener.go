@@ -0,0 +1,187 @@
+// Package listener opens the server's TCP, Unix and systemd-activated sockets and remembers which
+// listener accepted each connection, so routes can be limited to some listeners. On listeners with
+// a client CA it also exposes the verified client certificate.
+package listener
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"app/src/config"
+)
+
+// listenFDsStart is the first file descriptor systemd passes to socket-activated services
+const listenFDsStart = 3
+
+// Open listens on the configured address. Connections it accepts report the listener's name to Name.
+func Open(cfg config.ListenerConfig) (net.Listener, error) {
+	var ln net.Listener
+	var err error
+
+	switch cfg.Network {
+	case config.ListenerUnix:
+		ln, err = unixListener(cfg.Address)
+	case config.ListenerSystemd:
+		ln, err = systemdListener(cfg.Address)
+	default:
+		ln, err = net.Listen("tcp", cfg.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.TLSCert != "" {
+		tlsConfig, err := serverTLS(cfg)
+		if err != nil {
+			_ = ln.Close()
+			return nil, err
+		}
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	return &namedListener{Listener: ln, name: cfg.Name}, nil
+}
+
+// Name returns the name of the listener that accepted conn. Connections the server accepted
+// itself (prefork, tests) belong to the first configured listener.
+func Name(conn net.Conn) string {
+	switch named := conn.(type) {
+	case *namedConn:
+		return named.name
+	case *namedTLSConn:
+		return named.name
+	}
+	if len(config.Listeners) > 0 {
+		return config.Listeners[0].Name
+	}
+	return config.DefaultListener
+}
+
+// ClientCertificate returns the client certificate verified on conn against its listener's client
+// CA, or nil for connections without one
+func ClientCertificate(conn net.Conn) *x509.Certificate {
+	named, ok := conn.(*namedTLSConn)
+	if !ok {
+		return nil
+	}
+	state := named.ConnectionState()
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
+
+// CertificateIdentities returns the identities a client certificate names, most specific first:
+// URI SANs (e.g. SPIFFE IDs), DNS SANs, email SANs and the subject common name
+func CertificateIdentities(cert *x509.Certificate) []string {
+	var identities []string
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	identities = append(identities, cert.DNSNames...)
+	identities = append(identities, cert.EmailAddresses...)
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+	return identities
+}
+
+type namedListener struct {
+	net.Listener
+	name string
+}
+
+func (l *namedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	// Keep TLS connections recognisable, so fasthttp still reports them as TLS
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		return &namedTLSConn{Conn: tlsConn, name: l.name}, nil
+	}
+	return &namedConn{Conn: conn, name: l.name}, nil
+}
+
+type namedConn struct {
+	net.Conn
+	name string
+}
+
+type namedTLSConn struct {
+	*tls.Conn
+	name string
+}
+
+// serverTLS loads the listener's certificate and, with a client CA, requires client certificates
+func serverTLS(cfg config.ListenerConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("load certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}}
+
+	if cfg.ClientCA != "" {
+		caCert, err := os.ReadFile(cfg.ClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("parse client CA: %s", cfg.ClientCA)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// unixListener creates the socket, replacing one left behind by a previous run
+func unixListener(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, config.UnixSocketMode); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("set socket mode: %w", err)
+	}
+
+	return ln, nil
+}
+
+// systemdListener takes over a socket passed by systemd, found by its FileDescriptorName= or index
+func systemdListener(name string) (net.Listener, error) {
+	if pid, _ := strconv.Atoi(os.Getenv("LISTEN_PID")); pid != os.Getpid() {
+		return nil, errors.New("no sockets passed by systemd")
+	}
+
+	count, _ := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	for i := 0; i < count; i++ {
+		if strconv.Itoa(i) != name && (i >= len(names) || names[i] != name) {
+			continue
+		}
+
+		file := os.NewFile(uintptr(listenFDsStart+i), name)
+		// FileListener duplicates the descriptor, so the original is closed either way
+		defer file.Close()
+		return net.FileListener(file)
+	}
+
+	return nil, fmt.Errorf("systemd socket %q not found", name)
+}
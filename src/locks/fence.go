@@ -0,0 +1,112 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrFenced is returned by a write made under a lock that another holder has taken since
+var ErrFenced = errors.New("lock lost to a newer holder")
+
+// fenceSQL records the token of a write, unless a write with a newer token was recorded. The row
+// stays locked until the write's transaction ends, so a newer holder waits for it.
+const fenceSQL = `INSERT INTO lock_fences (name, token) VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE SET token = EXCLUDED.token WHERE lock_fences.token <= EXCLUDED.token`
+
+// startedKey marks a statement whose transaction the plugin began
+const startedKey = "locks:started_transaction"
+
+type fenceKey struct{}
+
+type fence struct {
+	name  string
+	token int64
+}
+
+// WithFencingToken returns ctx carrying the fencing token of the named lock, for the writes of a
+// holder that took it with Acquire. WithLock passes such a context to its function.
+func WithFencingToken(ctx context.Context, name string, token int64) context.Context {
+	return context.WithValue(ctx, fenceKey{}, fence{name: name, token: token})
+}
+
+// FencingToken returns the token of the lock held by WithLock's ctx, and false outside a lock or
+// without Redis. Writes through GORM check it themselves; pass it on to other systems that can.
+func FencingToken(ctx context.Context) (int64, bool) {
+	f, ok := ctx.Value(fenceKey{}).(fence)
+	return f.token, ok
+}
+
+// GormPlugin fences the writes made with the context of WithLock: each insert, update, delete and
+// raw statement first records its lock's token in lock_fences, in the same transaction, and fails
+// with ErrFenced if a newer holder already wrote. A holder whose lock expired while it was paused
+// thus cannot overwrite the work of the next one. Reads are not fenced.
+type GormPlugin struct{}
+
+var _ gorm.Plugin = GormPlugin{}
+
+// NewGormPlugin creates the plugin fencing writes made under a lock
+func NewGormPlugin() GormPlugin {
+	return GormPlugin{}
+}
+
+func (GormPlugin) Name() string {
+	return "locks"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("locks:before_create", beginFenced),
+		callbacks.Create().After("gorm:commit_or_rollback_transaction").Register("locks:after_create", endFenced),
+		callbacks.Update().Before("gorm:update").Register("locks:before_update", beginFenced),
+		callbacks.Update().After("gorm:commit_or_rollback_transaction").Register("locks:after_update", endFenced),
+		callbacks.Delete().Before("gorm:delete").Register("locks:before_delete", beginFenced),
+		callbacks.Delete().After("gorm:commit_or_rollback_transaction").Register("locks:after_delete", endFenced),
+		callbacks.Raw().Before("gorm:raw").Register("locks:before_raw", beginFenced),
+		callbacks.Raw().After("gorm:raw").Register("locks:after_raw", endFenced),
+	)
+}
+
+// beginFenced starts a transaction for the statement, unless it already runs in one, and checks
+// its fencing token there
+func beginFenced(db *gorm.DB) {
+	f, ok := db.Statement.Context.Value(fenceKey{}).(fence)
+	if !ok || db.Error != nil || db.DryRun {
+		return
+	}
+
+	if tx := db.Begin(); tx.Error == nil {
+		db.Statement.ConnPool = tx.Statement.ConnPool
+		db.InstanceSet(startedKey, true)
+	} else if !errors.Is(tx.Error, gorm.ErrInvalidTransaction) {
+		// ErrInvalidTransaction means the statement is part of a transaction already
+		_ = db.AddError(tx.Error)
+		return
+	}
+
+	result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, fenceSQL, f.name, f.token)
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("failed to check fencing token of lock %s: %w", f.name, err))
+		return
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		_ = db.AddError(fmt.Errorf("%w: lock %s, token %d", ErrFenced, f.name, f.token))
+	}
+}
+
+// endFenced commits the transaction beginFenced started, or rolls it back if the statement failed
+func endFenced(db *gorm.DB) {
+	if _, ok := db.InstanceGet(startedKey); !ok {
+		return
+	}
+
+	if db.Error != nil {
+		db.Rollback()
+	} else {
+		db.Commit()
+	}
+	db.Statement.ConnPool = db.ConnPool
+}
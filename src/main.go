@@ -1,16 +1,36 @@
 package main
 
 import (
+	"app/src/analytics"
+	"app/src/backup"
+	"app/src/cache"
 	"app/src/config"
 	"app/src/database"
+	"app/src/encryption"
+	"app/src/leader"
+	"app/src/listener"
+	"app/src/locks"
 	"app/src/middleware"
+	"app/src/presence"
+	"app/src/readonly"
+	"app/src/redis"
+	"app/src/requestsig"
 	"app/src/router"
+	"app/src/routetable"
+	"app/src/schema"
+	"app/src/signedurl"
+	"app/src/startup"
 	"app/src/utils"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
@@ -33,16 +53,25 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	setupEncryption(ctx)
+
+	// One-off maintenance commands run instead of the server
+	if len(os.Args) > 1 {
+		runCommand(ctx, os.Args[1])
+		return
+	}
+
+	waitForDependencies(ctx)
+
 	app := setupFiberApp()
 	db := setupDatabase()
 	defer closeDatabase(db)
+	checkSchema(ctx, db)
 	setupRoutes(app, db)
 
-	address := fmt.Sprintf("%s:%d", config.AppHost, config.AppPort)
-
 	// Start server and handle graceful shutdown
-	serverErrors := make(chan error, 1)
-	go startServer(app, address, serverErrors)
+	serverErrors := make(chan error, len(config.Listeners))
+	startServer(app, serverErrors)
 	handleGracefulShutdown(ctx, app, serverErrors)
 }
 
@@ -52,6 +81,10 @@ func setupFiberApp() *fiber.App {
 	// Middleware setup
 	// TODO: Will be updated in Plan 02 with Redis-based rate limiter
 	// app.Use("/v1/auth", middleware.LimiterConfig())
+	app.Use(middleware.RequestID())
+	if config.Server.HandlerTimeout > 0 {
+		app.Use(middleware.Timeout(config.Server.HandlerTimeout))
+	}
 	app.Use(middleware.LoggerConfig())
 	app.Use(helmet.New())
 	app.Use(compress.New())
@@ -61,6 +94,270 @@ func setupFiberApp() *fiber.App {
 	return app
 }
 
+// checkSchema refuses to start on a database schema this build cannot run against (SCHEMA_CHECK=strict),
+// such as one not migrated yet or one a newer release migrated past a breaking change
+func checkSchema(ctx context.Context, db *gorm.DB) {
+	if config.SchemaCheck == config.SchemaCheckOff {
+		return
+	}
+
+	compatibility, err := schema.Check(ctx, db)
+	if err == nil && compatibility.Compatible() {
+		if compatibility.Database > compatibility.Code {
+			utils.Log.Infof("Database schema %d is ahead of this build (%d) and compatible with it",
+				compatibility.Database, compatibility.Code)
+		}
+		return
+	}
+	if err == nil {
+		err = errors.New(compatibility.Reason)
+	}
+
+	if config.SchemaCheck == config.SchemaCheckStrict {
+		utils.Log.Fatalf("Incompatible database schema: %v", err)
+	}
+	utils.Log.Warnf("Incompatible database schema, starting anyway (SCHEMA_CHECK=warn): %v", err)
+}
+
+// waitForDependencies blocks until Postgres (required), Redis and SMTP are reachable
+func waitForDependencies(ctx context.Context) {
+	if config.StartupWaitTimeout == 0 {
+		return
+	}
+
+	deps := []startup.Dependency{
+		{Name: "postgres", Required: true, Check: startup.TCPCheck(config.DBHost, config.DBPort)},
+	}
+	if redisConfig, err := config.LoadRedisConfig(); err == nil && redisConfig.Enabled {
+		deps = append(deps, startup.Dependency{Name: "redis", Check: startup.TCPCheck(redisConfig.Host, redisConfig.Port)})
+	}
+	if config.SMTPHost != "" {
+		deps = append(deps, startup.Dependency{Name: "smtp", Check: startup.TCPCheck(config.SMTPHost, config.SMTPPort)})
+	}
+
+	timeout := time.Duration(config.StartupWaitTimeout) * time.Second
+	if err := startup.Wait(ctx, deps, timeout); err != nil {
+		utils.Log.Fatalf("Startup aborted: %v", err)
+	}
+}
+
+// setupEncryption loads the column encryption keyring used by `gorm:"serializer:encrypted"` fields
+func setupEncryption(ctx context.Context) {
+	// Pass a KMS client here when ENCRYPTION_KEYS holds KMS-wrapped keys
+	keyring, err := encryption.NewFromConfig(ctx, nil)
+	if err != nil {
+		utils.Log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+	encryption.Use(keyring)
+}
+
+func runCommand(ctx context.Context, name string) {
+	switch name {
+	case "reencrypt":
+		reencrypt(ctx)
+	case "routes":
+		printRoutes()
+	case "redis-namespace":
+		migrateRedisNamespace(ctx, os.Args[2:])
+	case "backup":
+		exportBackup(ctx, os.Args[2:])
+	case "restore":
+		restoreBackup(ctx, os.Args[2:])
+	case "schema-compat":
+		printSchemaMatrix(os.Args[2:])
+	default:
+		utils.Log.Fatalf("Unknown command %q (available: reencrypt, routes, redis-namespace, backup, restore, "+
+			"schema-compat)", name)
+	}
+}
+
+// migrateRedisNamespace moves the app's keys into REDIS_NAMESPACE, from no namespace or from the
+// namespace given as argument, so setting or changing it does not drop sessions and counters
+func migrateRedisNamespace(ctx context.Context, args []string) {
+	redisConfig, err := config.LoadRedisConfig()
+	if err != nil || !redisConfig.Enabled {
+		utils.Log.Fatalf("Redis is not configured: %v", err)
+	}
+
+	client, err := redis.NewRedisClient(*redisConfig)
+	if err != nil {
+		utils.Log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer client.Close()
+
+	var from string
+	if len(args) > 0 {
+		from = config.NormalizeRedisNamespace(args[0])
+	}
+
+	prefixes := append([]string{
+		cache.EvictedSessionKeyPrefix,
+		cache.SessionActivityKeyPrefix,
+		cache.OAuthStateKeyPrefix,
+		cache.OAuthCodeKeyPrefix,
+		cache.OAuthClientRateKeyPrefix,
+		cache.PhoneVerificationKeyPrefix,
+		leader.LeaderKeyPrefix,
+		locks.LockKeyPrefix,
+		presence.KeyPrefix,
+		analytics.KeyPrefix,
+		readonly.Key,
+		cache.ResponseCacheSwitchKey,
+		signedurl.NonceKeyPrefix,
+		requestsig.NonceKeyPrefix,
+	}, cache.KeyPrefixes...)
+
+	moved, skipped, err := client.MigrateNamespace(ctx, from, prefixes)
+	utils.Log.Infof("Moved %d Redis keys from namespace %q to %q, %d skipped as already present",
+		moved, from, redisConfig.Namespace, skipped)
+	if err != nil {
+		utils.Log.Fatalf("Redis namespace migration failed: %v", err)
+	}
+}
+
+// exportBackup writes users, their tokens and the audit log to the archive file given as argument
+func exportBackup(ctx context.Context, args []string) {
+	if len(args) == 0 {
+		utils.Log.Fatal("Usage: backup <file>")
+	}
+
+	file, err := os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		utils.Log.Fatalf("Failed to create backup archive: %v", err)
+	}
+
+	db := setupDatabase()
+	defer closeDatabase(db)
+
+	rows, err := backup.Export(ctx, db, file, config.BackupEnvironment, config.BackupPassphrase)
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(args[0])
+		utils.Log.Fatalf("Backup failed: %v", err)
+	}
+	for _, table := range backup.Tables {
+		utils.Log.Infof("Backed up %d rows of %s", rows[table.Name], table.Name)
+	}
+	if config.BackupPassphrase == "" {
+		utils.Log.Warn("Backup archive is not encrypted, set BACKUP_PASSPHRASE to encrypt it")
+	}
+}
+
+// restoreBackup loads the archive file given as argument into an empty, migrated database
+func restoreBackup(ctx context.Context, args []string) {
+	if len(args) == 0 {
+		utils.Log.Fatal("Usage: restore <file>")
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		utils.Log.Fatalf("Failed to open backup archive: %v", err)
+	}
+	defer file.Close()
+
+	db := setupDatabase()
+	defer closeDatabase(db)
+
+	rows, err := backup.Restore(ctx, db, file, config.BackupPassphrase)
+	if err != nil {
+		utils.Log.Fatalf("Restore failed, nothing was restored: %v", err)
+	}
+	for _, table := range backup.Tables {
+		utils.Log.Infof("Restored %d rows of %s", rows[table.Name], table.Name)
+	}
+}
+
+// printSchemaMatrix prints which builds run against which database schema versions, and with a
+// version as argument whether this build runs against a database at it
+func printSchemaMatrix(args []string) {
+	migrations, err := schema.Migrations()
+	if err != nil {
+		utils.Log.Fatalf("Invalid migrations: %v", err)
+	}
+	expected := schema.Expected(migrations)
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "VERSION\tMIGRATION\tOLDEST BUILD ON IT\tNEWEST DATABASE FOR ITS BUILD\tBREAKING")
+	for _, row := range schema.Matrix(migrations) {
+		version := strconv.FormatInt(row.Version, 10)
+		if row.Version == expected {
+			version += " (this build)"
+		}
+		newest := "latest"
+		if row.NewestDatabase != 0 {
+			newest = strconv.FormatInt(row.NewestDatabase, 10)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\n", version, row.Name, row.OldestCode, newest, row.Breaking)
+	}
+	_ = table.Flush()
+
+	if len(args) == 0 {
+		return
+	}
+	database, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		utils.Log.Fatalf("Invalid schema version %q", args[0])
+	}
+	if compatibility := schema.Compare(expected, database, migrations); !compatibility.Compatible() {
+		utils.Log.Fatalf("This build cannot run against it: %s", compatibility.Reason)
+	}
+	if database > expected {
+		fmt.Printf("This build (%d) runs against a database at %d unless a later release marked a migration "+
+			"after %d as breaking, which that release's schema-compat lists\n", expected, database, expected)
+		return
+	}
+	fmt.Printf("This build (%d) runs against a database at %d\n", expected, database)
+}
+
+// reencrypt rewrites encrypted columns under the primary key after a key rotation
+func reencrypt(ctx context.Context) {
+	db := setupDatabase()
+	defer closeDatabase(db)
+
+	results, err := encryption.Reencrypt(ctx, db, encryption.Default(), 500)
+	for _, result := range results {
+		utils.Log.Infof("Re-encrypted %s.%s: %d scanned, %d re-encrypted, %d failed",
+			result.Table, result.Column, result.Scanned, result.Reencrypted, result.Failed)
+	}
+	if err != nil {
+		utils.Log.Fatalf("Re-encryption failed: %v", err)
+	}
+	if len(results) == 0 {
+		utils.Log.Info("No encrypted columns registered")
+	}
+}
+
+// printRoutes builds the app as the server does, with the same configuration, and prints every
+// route with its access policies, rate limits, cache policy and middleware chain
+func printRoutes() {
+	app := setupFiberApp()
+	db := setupDatabase()
+	defer closeDatabase(db)
+	setupRoutes(app, db)
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "METHOD\tPATH\tACCESS\tRATE LIMITS\tCACHE\tCHAIN")
+	for _, route := range routetable.Table(app) {
+		access := "public"
+		if len(route.Access) > 0 {
+			access = strings.Join(route.Access, " + ")
+		}
+
+		chain := make([]string, 0, len(route.Middleware)+1)
+		for _, step := range route.Middleware {
+			chain = append(chain, step.Name)
+		}
+		chain = append(chain, route.Handler)
+
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", route.Method, route.Path, access,
+			strings.Join(route.RateLimits, "; "), route.Cache, strings.Join(chain, " > "))
+	}
+	_ = table.Flush()
+}
+
 func setupDatabase() *gorm.DB {
 	db := database.Connect(config.DBHost, config.DBName)
 	// Add any additional database setup if needed
@@ -72,9 +369,30 @@ func setupRoutes(app *fiber.App, db *gorm.DB) {
 	app.Use(utils.NotFoundHandler)
 }
 
-func startServer(app *fiber.App, address string, errs chan<- error) {
-	if err := app.Listen(address); err != nil {
-		errs <- fmt.Errorf("error starting server: %w", err)
+// startServer serves the app on every configured listener; prefork binds its single TCP address itself
+func startServer(app *fiber.App, errs chan<- error) {
+	if config.Server.Prefork {
+		go func() {
+			if err := app.Listen(config.Listeners[0].Address); err != nil {
+				errs <- fmt.Errorf("error starting server: %w", err)
+			}
+		}()
+		return
+	}
+
+	for _, cfg := range config.Listeners {
+		ln, err := listener.Open(cfg)
+		if err != nil {
+			errs <- fmt.Errorf("error opening %s listener on %s: %w", cfg.Name, cfg.Address, err)
+			return
+		}
+		utils.Log.Infof("Listening on %s://%s (%s)", cfg.Network, ln.Addr(), cfg.Name)
+
+		go func() {
+			if err := app.Listener(ln); err != nil {
+				errs <- fmt.Errorf("error starting %s listener: %w", cfg.Name, err)
+			}
+		}()
 	}
 }
 
@@ -0,0 +1,31 @@
+package memo
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// GormPlugin empties the request cache after every write made with the request's context, raw SQL
+// included, so lookups after a change load the new rows
+type GormPlugin struct{}
+
+var _ gorm.Plugin = GormPlugin{}
+
+func (GormPlugin) Name() string {
+	return "memo"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().After("gorm:create").Register("memo:after_create", flush),
+		callbacks.Update().After("gorm:update").Register("memo:after_update", flush),
+		callbacks.Delete().After("gorm:delete").Register("memo:after_delete", flush),
+		callbacks.Raw().After("gorm:raw").Register("memo:after_raw", flush),
+	)
+}
+
+func flush(db *gorm.DB) {
+	Flush(db.Statement.Context)
+}
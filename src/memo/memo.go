@@ -0,0 +1,97 @@
+// Package memo memoizes lookups for the lifetime of one request. A lookup made again in the same
+// request, such as the user loaded by the auth middleware and then by the handler, is served from
+// memory. The cache is dropped when the request ends and emptied by every write made through GORM
+// with the request's context, so a request never reads back a row it has changed.
+package memo
+
+import (
+	"context"
+	"sync"
+
+	"app/src/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type contextKey struct{}
+
+var lookupsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Name:      "request_memo_lookups_total",
+	Help:      "Request-scoped lookups by kind; hits are queries saved.",
+}, []string{"kind", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(lookupsTotal)
+}
+
+// Cache holds the values memoized for one request
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]any
+	closed  bool
+}
+
+// WithCache returns ctx with an empty request cache, and the function that drops it
+func WithCache(ctx context.Context) (context.Context, func()) {
+	cache := &Cache{entries: map[string]any{}}
+	return context.WithValue(ctx, contextKey{}, cache), cache.close
+}
+
+// Flush forgets everything memoized in ctx's request
+func Flush(ctx context.Context) {
+	if cache := from(ctx); cache != nil {
+		cache.mu.Lock()
+		clear(cache.entries)
+		cache.mu.Unlock()
+	}
+}
+
+// Load returns the value memoized under kind and key in ctx's request, or calls load and memoizes
+// what it returns. Errors are not memoized. Outside a request, or once it has ended, load is
+// always called.
+func Load[T any](ctx context.Context, kind, key string, load func() (T, error)) (T, error) {
+	cache := from(ctx)
+	if cache == nil {
+		return load()
+	}
+
+	id := kind + ":" + key
+	cache.mu.Lock()
+	value, ok := cache.entries[id]
+	cache.mu.Unlock()
+	if ok {
+		lookupsTotal.WithLabelValues(kind, "hit").Inc()
+		return value.(T), nil
+	}
+
+	lookupsTotal.WithLabelValues(kind, "miss").Inc()
+	result, err := load()
+	if err != nil {
+		return result, err
+	}
+
+	cache.mu.Lock()
+	if !cache.closed {
+		cache.entries[id] = result
+	}
+	cache.mu.Unlock()
+
+	return result, nil
+}
+
+func from(ctx context.Context) *Cache {
+	if ctx == nil {
+		return nil
+	}
+	cache, _ := ctx.Value(contextKey{}).(*Cache)
+	return cache
+}
+
+// close drops the entries; goroutines still holding the request's context load afresh
+func (c *Cache) close() {
+	c.mu.Lock()
+	c.entries = nil
+	c.closed = true
+	c.mu.Unlock()
+}
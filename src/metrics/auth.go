@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Login methods
+const (
+	MethodPassword = "password"
+	MethodGoogle   = "google"
+	MethodApple    = "apple"
+	// MethodMagicLink is reserved for passwordless email sign-in
+	MethodMagicLink = "magic_link"
+)
+
+// Flow stages for multi-step flows
+const (
+	StageRequested = "requested"
+	StageCompleted = "completed"
+)
+
+// Outcomes of an auth flow step
+const (
+	OutcomeSuccess = "success"
+	// OutcomeInvalid means the request failed validation
+	OutcomeInvalid = "invalid"
+	// OutcomeRejected means the request was well-formed but refused (wrong password, bad token, conflict)
+	OutcomeRejected = "rejected"
+	// OutcomeError means an internal failure
+	OutcomeError = "error"
+)
+
+var (
+	registrationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "auth_registrations_total",
+		Help:      "User registrations by outcome.",
+	}, []string{"outcome"})
+
+	loginsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "auth_logins_total",
+		Help:      "Login attempts by method and outcome.",
+	}, []string{"method", "outcome"})
+
+	passwordResetsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "auth_password_resets_total",
+		Help:      "Password reset requests and completions by outcome.",
+	}, []string{"stage", "outcome"})
+
+	emailVerificationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "auth_email_verifications_total",
+		Help:      "Verification email requests and completed verifications by outcome.",
+	}, []string{"stage", "outcome"})
+
+	loginDelaySeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "auth_login_delay_seconds",
+		Help:      "Delays imposed on an email by a failed password login.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 11),
+	})
+
+	loginsDelayedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "auth_logins_delayed_total",
+		Help:      "Password logins refused because the email's delay had not passed.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		registrationsTotal, loginsTotal, passwordResetsTotal, emailVerificationsTotal,
+		loginDelaySeconds, loginsDelayedTotal,
+	)
+
+	// Export every series from the start so funnel queries see zeros instead of gaps
+	outcomes := []string{OutcomeSuccess, OutcomeInvalid, OutcomeRejected, OutcomeError}
+	for _, outcome := range outcomes {
+		registrationsTotal.WithLabelValues(outcome)
+		for _, method := range []string{MethodPassword, MethodGoogle, MethodApple, MethodMagicLink} {
+			loginsTotal.WithLabelValues(method, outcome)
+		}
+		for _, stage := range []string{StageRequested, StageCompleted} {
+			passwordResetsTotal.WithLabelValues(stage, outcome)
+			emailVerificationsTotal.WithLabelValues(stage, outcome)
+		}
+	}
+}
+
+// Outcome classifies the error returned by an auth flow step
+func Outcome(err error) string {
+	if err == nil {
+		return OutcomeSuccess
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return OutcomeInvalid
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) && fiberErr.Code < fiber.StatusInternalServerError {
+		return OutcomeRejected
+	}
+
+	return OutcomeError
+}
+
+// RecordRegistration counts a registration attempt
+func RecordRegistration(err error) {
+	registrationsTotal.WithLabelValues(Outcome(err)).Inc()
+}
+
+// RecordLogin counts a login attempt for the given method
+func RecordLogin(method string, err error) {
+	loginsTotal.WithLabelValues(method, Outcome(err)).Inc()
+}
+
+// RecordPasswordReset counts a password reset request or completion
+func RecordPasswordReset(stage string, err error) {
+	passwordResetsTotal.WithLabelValues(stage, Outcome(err)).Inc()
+}
+
+// RecordEmailVerification counts a verification email being sent or an address being verified
+func RecordEmailVerification(stage string, err error) {
+	emailVerificationsTotal.WithLabelValues(stage, Outcome(err)).Inc()
+}
+
+// RecordLoginDelay records the delay a failed password login imposed on its email
+func RecordLoginDelay(delay time.Duration) {
+	loginDelaySeconds.Observe(delay.Seconds())
+}
+
+// RecordLoginDelayed counts a password login refused because its email's delay had not passed
+func RecordLoginDelayed() {
+	loginsDelayedTotal.Inc()
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	bulkheadInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "bulkhead_in_flight",
+		Help:      "Requests currently being served per bulkhead route group.",
+	}, []string{"group"})
+
+	bulkheadRejectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "bulkhead_rejected_total",
+		Help:      "Requests rejected because their bulkhead route group was saturated.",
+	}, []string{"group"})
+)
+
+func init() {
+	Registry.MustRegister(bulkheadInFlight, bulkheadRejectedTotal)
+}
+
+// BulkheadAcquired counts a request entering a bulkhead group
+func BulkheadAcquired(group string) {
+	bulkheadInFlight.WithLabelValues(group).Inc()
+}
+
+// BulkheadReleased counts a request leaving a bulkhead group
+func BulkheadReleased(group string) {
+	bulkheadInFlight.WithLabelValues(group).Dec()
+}
+
+// BulkheadRejected counts a request turned away from a saturated bulkhead group
+func BulkheadRejected(group string) {
+	bulkheadRejectedTotal.WithLabelValues(group).Inc()
+}
@@ -0,0 +1,18 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var chaosFaultsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Name:      "chaos_faults_injected_total",
+	Help:      "Faults injected by chaos mode by dependency (http, redis, db) and fault (latency, error, drop).",
+}, []string{"dependency", "fault"})
+
+func init() {
+	Registry.MustRegister(chaosFaultsTotal)
+}
+
+// ChaosInjected counts a fault injected into a dependency
+func ChaosInjected(dependency, fault string) {
+	chaosFaultsTotal.WithLabelValues(dependency, fault).Inc()
+}
@@ -0,0 +1,18 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var dbRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Name:      "db_retries_total",
+	Help:      "Database operations run again by operation (read, write) and error class (serialization, connection, failover).",
+}, []string{"operation", "class"})
+
+func init() {
+	Registry.MustRegister(dbRetriesTotal)
+}
+
+// DBRetried counts a database operation run again after a transient error
+func DBRetried(operation, class string) {
+	dbRetriesTotal.WithLabelValues(operation, class).Inc()
+}
@@ -0,0 +1,18 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var quotaExceededTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Name:      "quota_exceeded_total",
+	Help:      "Requests rejected because a daily or monthly request quota was used up.",
+}, []string{"period"})
+
+func init() {
+	Registry.MustRegister(quotaExceededTotal)
+}
+
+// QuotaExceeded counts a request rejected by the quota of the period (day, month)
+func QuotaExceeded(period string) {
+	quotaExceededTotal.WithLabelValues(period).Inc()
+}
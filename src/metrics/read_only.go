@@ -0,0 +1,22 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var readOnlyMode = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: Namespace,
+	Name:      "read_only_mode",
+	Help:      "1 while the instance is in read-only mode and refuses writes, 0 otherwise.",
+})
+
+func init() {
+	Registry.MustRegister(readOnlyMode)
+}
+
+// ReadOnly records whether the instance is in read-only mode
+func ReadOnly(enabled bool) {
+	if enabled {
+		readOnlyMode.Set(1)
+		return
+	}
+	readOnlyMode.Set(0)
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	retentionPurgedRowsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "retention_purged_rows_total",
+		Help:      "Rows deleted because they were past the retention of their policy.",
+	}, []string{"policy"})
+
+	retentionDueRows = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "retention_due_rows",
+		Help:      "Rows past the retention of their policy when last counted, by a dry run or report.",
+	}, []string{"policy"})
+)
+
+func init() {
+	Registry.MustRegister(retentionPurgedRowsTotal, retentionDueRows)
+}
+
+// RetentionPurged counts rows deleted by a retention policy
+func RetentionPurged(policy string, rows int64) {
+	retentionPurgedRowsTotal.WithLabelValues(policy).Add(float64(rows))
+}
+
+// RetentionDue records how many rows of a retention policy are waiting to be purged
+func RetentionDue(policy string, rows int64) {
+	retentionDueRows.WithLabelValues(policy).Set(float64(rows))
+}
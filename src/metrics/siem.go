@@ -0,0 +1,49 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	siemForwardedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "siem_forwarded_events_total",
+		Help:      "Audit entries delivered to the SIEM sink.",
+	}, []string{"sink"})
+
+	siemFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "siem_send_failures_total",
+		Help:      "Failed calls to the SIEM sink; the batch is retried until it is delivered.",
+	}, []string{"sink"})
+
+	siemBuffered = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "siem_buffered_events",
+		Help:      "Audit entries read from the database and waiting to be sent to the SIEM sink.",
+	}, []string{"sink"})
+
+	siemLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "siem_lag_seconds",
+		Help:      "Age of the last audit entry delivered to the SIEM sink when it was delivered.",
+	}, []string{"sink"})
+)
+
+func init() {
+	Registry.MustRegister(siemForwardedTotal, siemFailuresTotal, siemBuffered, siemLag)
+}
+
+// SIEMForwarded counts audit entries delivered to a SIEM sink and how late the last one arrived
+func SIEMForwarded(sink string, events int, lagSeconds float64) {
+	siemForwardedTotal.WithLabelValues(sink).Add(float64(events))
+	siemLag.WithLabelValues(sink).Set(lagSeconds)
+}
+
+// SIEMFailed counts a failed call to a SIEM sink
+func SIEMFailed(sink string) {
+	siemFailuresTotal.WithLabelValues(sink).Inc()
+}
+
+// SIEMBuffered records how many audit entries wait to be sent to a SIEM sink
+func SIEMBuffered(sink string, events int) {
+	siemBuffered.WithLabelValues(sink).Set(float64(events))
+}
@@ -0,0 +1,18 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var trafficMirrorTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Name:      "traffic_mirror_requests_total",
+	Help:      "Sampled requests mirrored to the shadow target by outcome (sent, failed, dropped, skipped).",
+}, []string{"outcome"})
+
+func init() {
+	Registry.MustRegister(trafficMirrorTotal)
+}
+
+// TrafficMirrored counts a sampled request by what happened to its mirrored copy
+func TrafficMirrored(outcome string) {
+	trafficMirrorTotal.WithLabelValues(outcome).Inc()
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/routetable"
+	"app/src/service"
+	"app/src/utils"
+	"fmt"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission only lets through callers holding the permission on the record of resourceType
+// whose ID is the idParam route parameter, granted to them directly or through their role or tags.
+// Holders of the manageAcl right pass without a grant. It must run after an auth middleware.
+func RequirePermission(acl service.ACLService, resourceType, idParam, permission string) fiber.Handler {
+	return routetable.Describe(
+		requirePermission(acl, func(*fiber.Ctx) string { return resourceType }, idParam, permission),
+		routetable.Info{Kind: routetable.KindAuth, Detail: fmt.Sprintf("acl(%s %s :%s)", permission, resourceType, idParam)},
+	)
+}
+
+// RequirePermissionParam is RequirePermission for routes taking the resource type from typeParam
+func RequirePermissionParam(acl service.ACLService, typeParam, idParam, permission string) fiber.Handler {
+	return routetable.Describe(
+		requirePermission(acl, func(c *fiber.Ctx) string { return c.Params(typeParam) }, idParam, permission),
+		routetable.Info{Kind: routetable.KindAuth, Detail: fmt.Sprintf("acl(%s :%s :%s)", permission, typeParam, idParam)},
+	)
+}
+
+func requirePermission(
+	acl service.ACLService, resourceType func(*fiber.Ctx) string, idParam, permission string,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(*model.User)
+		if !ok || user == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+		}
+
+		if slices.Contains(callerRights(c, user), config.ACLAdminRight) {
+			return c.Next()
+		}
+
+		allowed, err := acl.Check(c.UserContext(), user, resourceType(c), utils.ParamID(c, idParam), permission)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return fiber.NewError(fiber.StatusForbidden, "You don't have permission to access this resource")
+		}
+
+		return c.Next()
+	}
+}
+
+// callerRights returns the rights of the authenticated caller: the scopes of an API token or
+// stateless access token, or else those of the user's role
+func callerRights(c *fiber.Ctx, user *model.User) []string {
+	if scopes, ok := c.Locals("scopes").([]string); ok {
+		return scopes
+	}
+	return config.RoleRights[user.Role]
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"app/src/analytics"
+	"app/src/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Analytics counts the unique client IPs of every endpoint and the active users. It runs after the
+// handler, once the route is matched and the user authenticated; requests matching no route are not
+// counted, so scans of random paths cannot flood the sketches.
+func Analytics(tracker *analytics.Tracker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		route := c.Route()
+		if route.Method == "USE" {
+			return err
+		}
+
+		var userID *uuid.UUID
+		if user, ok := c.Locals("user").(*model.User); ok && user != nil {
+			userID = &user.ID
+		}
+		tracker.Record(c.Method()+" "+route.Path, c.IP(), userID)
+
+		return err
+	}
+}
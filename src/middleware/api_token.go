@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"app/src/config"
+	"app/src/policy"
+	"app/src/routetable"
+	"app/src/service"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// apiTokenService resolves personal access tokens; nil leaves them disabled
+var apiTokenService service.APITokenService
+
+// EnableAPITokens lets the auth middlewares accept personal access tokens alongside JWTs
+func EnableAPITokens(s service.APITokenService) {
+	apiTokenService = s
+}
+
+// RequireInteractive rejects requests authenticated with a personal access token, a token delegated
+// to a third-party client or a client certificate
+func RequireInteractive() fiber.Handler {
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		token := bearerToken(c)
+		if isAPIToken(token) {
+			return fiber.NewError(fiber.StatusForbidden, "API tokens cannot access this resource")
+		}
+		if token == "" && clientCertificate(c) != nil {
+			return fiber.NewError(fiber.StatusForbidden, "Service accounts cannot access this resource")
+		}
+		return c.Next()
+	}, routetable.Info{Kind: routetable.KindAuth, Detail: "interactive"})
+}
+
+func bearerToken(c *fiber.Ctx) string {
+	return strings.TrimSpace(strings.TrimPrefix(c.Get("Authorization"), "Bearer "))
+}
+
+func isAPIToken(token string) bool {
+	return strings.HasPrefix(token, service.APITokenPrefix) || strings.HasPrefix(token, service.OAuthTokenPrefix)
+}
+
+// authenticateAPIToken authorizes a personal access token with the rights it was minted with, or a
+// client token with the rights of its scopes, narrowed to what the owner still holds
+func authenticateAPIToken(c *fiber.Ctx, rawToken string, p policy.Policy) error {
+	if apiTokenService == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	token, user, err := apiTokenService.Authenticate(c, rawToken)
+	if errors.Is(err, service.ErrAccountSuspended) {
+		return err
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	granted := token.ScopeList()
+	if token.ClientID != nil {
+		if err := authorizeClient(c, token); err != nil {
+			return err
+		}
+		granted = config.OAuthScopeRights(granted)
+	}
+
+	// Service accounts hold their own rights rather than their role's
+	userRights := service.RightsOf(user)
+	scopes := make([]string, 0, len(granted))
+	for _, scope := range granted {
+		for _, right := range userRights {
+			if scope == right {
+				scopes = append(scopes, scope)
+				break
+			}
+		}
+	}
+
+	setUser(c, user)
+	c.Locals("scopes", scopes)
+
+	subject := policy.Subject{UserID: user.ID.String(), Role: user.Role, Rights: scopes, Tags: user.TagNames()}
+	if err := authorize(c, subject, p); err != nil {
+		return err
+	}
+
+	if err := consumeQuota(c, user, token); err != nil {
+		return err
+	}
+
+	meterRequest(c, user, token)
+
+	return limitTagged(c, user)
+}
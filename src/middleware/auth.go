@@ -1,44 +1,77 @@
 package middleware
 
 import (
+	"app/src/accesstoken"
 	"app/src/config"
 	"app/src/model"
+	"app/src/policy"
+	"app/src/revision"
+	"app/src/routetable"
 	"app/src/service"
 	"app/src/utils"
 	"context"
 	"errors"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
+// Auth authenticates the caller and requires every listed right
 func Auth(userService service.UserService, sessionService service.SessionService, requiredRights ...string) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		authHeader := c.Get("Authorization")
-		token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+	return AuthPolicy(userService, sessionService, policy.HasRights(requiredRights...))
+}
+
+// AuthPolicy authenticates the caller via the session cache or database and enforces the policy
+func AuthPolicy(userService service.UserService, sessionService service.SessionService, p policy.Policy) fiber.Handler {
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		token := bearerToken(c)
 
 		if token == "" {
+			if cert := clientCertificate(c); cert != nil {
+				return authenticateClientCertificate(c, cert, p)
+			}
 			return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
 		}
 
-		userID, err := utils.VerifyToken(token, config.JWTSecret, config.TokenTypeAccess)
+		if isAPIToken(token) {
+			return authenticateAPIToken(c, token, p)
+		}
+
+		userID, sessionID, err := accesstoken.VerifySession(token)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
 		}
 
+		// Sessions ended by the session limit reject their access tokens before they expire
+		if revocations.IsSessionRevoked(sessionID) {
+			return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+		}
+
 		// Try cache first (SESS-02)
-		sessionData, err := sessionService.GetUserSession(c.Context(), userID)
+		sessionData, err := sessionService.GetUserSession(c.UserContext(), userID)
 		var user *model.User
 
+		// A cached session whose ID does not parse is treated as a miss instead of panicking
+		var cachedID uuid.UUID
+		if err == nil && sessionData != nil {
+			cachedID, err = uuid.Parse(sessionData.ID)
+		}
+
 		if err == nil && sessionData != nil {
 			// Cache hit - convert SessionData to model.User
 			user = &model.User{
-				ID:            uuid.MustParse(sessionData.ID),
+				ID:            cachedID,
 				Name:          sessionData.Name,
+				Username:      sessionData.Username,
 				Email:         sessionData.Email,
 				Role:          sessionData.Role,
 				VerifiedEmail: sessionData.VerifiedEmail,
+				IsActive:      !sessionData.Suspended,
+				Plan:          sessionData.Plan,
+				Timezone:      sessionData.Timezone,
+			}
+			for _, tag := range sessionData.Tags {
+				user.Tags = append(user.Tags, model.UserTag{UserID: user.ID, Tag: tag})
 			}
 			// Skip database call
 		} else {
@@ -60,29 +93,128 @@ func Auth(userService service.UserService, sessionService service.SessionService
 			}()
 		}
 
-		c.Locals("user", user)
+		// Suspension revokes tokens, but an access token stays valid until it expires
+		if !user.IsActive {
+			return service.ErrAccountSuspended
+		}
+
+		// Service accounts never sign in, so no session can stand for one
+		if user.IsService() {
+			return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+		}
+
+		setUser(c, user)
+
+		subject := policy.Subject{
+			UserID: userID, Role: user.Role, Rights: config.RoleRights[user.Role], Tags: user.TagNames(),
+		}
+		if err := authorize(c, subject, p); err != nil {
+			return err
+		}
+
+		touchSession(c, sessionID)
+
+		if err := consumeQuota(c, user, nil); err != nil {
+			return err
+		}
 
-		if len(requiredRights) > 0 {
-			userRights, hasRights := config.RoleRights[user.Role]
-			if (!hasRights || !hasAllRights(userRights, requiredRights)) && c.Params("userId") != userID {
-				return fiber.NewError(fiber.StatusForbidden, "You don't have permission to access this resource")
+		meterRequest(c, user, nil)
+
+		return limitTagged(c, user)
+	}, routetable.Info{Kind: routetable.KindAuth, Detail: policy.Describe(p)})
+}
+
+// StatelessAuth authorizes purely from the role and scopes embedded in the access token,
+// skipping the session cache and database lookup for high-throughput routes
+func StatelessAuth(requiredRights ...string) fiber.Handler {
+	return StatelessAuthPolicy(policy.HasRights(requiredRights...))
+}
+
+// StatelessAuthPolicy authenticates from the access token claims and enforces the policy
+func StatelessAuthPolicy(p policy.Policy) fiber.Handler {
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		token := bearerToken(c)
+
+		if token == "" {
+			if cert := clientCertificate(c); cert != nil {
+				return authenticateClientCertificate(c, cert, p)
 			}
+			return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+		}
+
+		if isAPIToken(token) {
+			return authenticateAPIToken(c, token, p)
+		}
+
+		claims, err := accesstoken.Verify(token)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+		}
+
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+		}
+
+		// Claims outlive deletions and role changes; honour revocations broadcast since the token was issued
+		if revocations.IsRevoked(claims.UserID, claims.IssuedAt) ||
+			revocations.IsSessionRevoked(claims.SessionID) {
+			return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+		}
+
+		// Only the claims are known here - handlers needing the full profile must load it
+		user := &model.User{
+			ID:   userID,
+			Role: claims.Role,
+			Plan: claims.Plan,
+		}
+		setUser(c, user)
+		c.Locals("scopes", claims.Scopes)
+
+		subject := policy.Subject{UserID: claims.UserID, Role: claims.Role, Rights: claims.Scopes}
+		if err := authorize(c, subject, p); err != nil {
+			return err
+		}
+
+		touchSession(c, claims.SessionID)
+
+		if err := consumeQuota(c, user, nil); err != nil {
+			return err
 		}
 
+		meterRequest(c, user, nil)
+
 		return c.Next()
-	}
+	}, routetable.Info{Kind: routetable.KindAuth, Detail: policy.Describe(p)})
 }
 
-func hasAllRights(userRights, requiredRights []string) bool {
-	rightSet := make(map[string]struct{}, len(userRights))
-	for _, right := range userRights {
-		rightSet[right] = struct{}{}
-	}
+// GroupAuth returns an auth middleware factory for a route group, using stateless auth
+// when the group is listed in AUTH_STATELESS_GROUPS and session-backed auth otherwise
+func GroupAuth(
+	group string, userService service.UserService, sessionService service.SessionService,
+) func(p policy.Policy) fiber.Handler {
+	stateless := config.IsStatelessGroup(group)
 
-	for _, right := range requiredRights {
-		if _, exists := rightSet[right]; !exists {
-			return false
+	return func(p policy.Policy) fiber.Handler {
+		if stateless {
+			return StatelessAuthPolicy(p)
 		}
+		return AuthPolicy(userService, sessionService, p)
 	}
-	return true
+}
+
+// setUser stores the authenticated user for handlers and records them as the actor of the request's
+// changes to users
+func setUser(c *fiber.Ctx, user *model.User) {
+	c.Locals("user", user)
+	c.SetUserContext(revision.WithActor(c.UserContext(), user.ID))
+}
+
+// authorize enforces the route policy for the authenticated subject
+func authorize(c *fiber.Ctx, subject policy.Subject, p policy.Policy) error {
+	if !p(c, subject) {
+		return fiber.NewError(fiber.StatusForbidden, "You don't have permission to access this resource")
+	}
+
+	return nil
 }
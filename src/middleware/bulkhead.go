@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/src/config"
+	"app/src/metrics"
+	"app/src/response"
+	"app/src/routetable"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// NewBulkhead limits how many requests in the named route group are served at once, so a burst
+// of expensive calls cannot exhaust the DB pool for everything else. Requests beyond the limit
+// wait up to cfg.Wait for a slot and are then rejected with 503 and Retry-After.
+// The limit is per instance; groups without a configured limit pass through.
+func NewBulkhead(name string, cfg config.BulkheadConfig) fiber.Handler {
+	limit := cfg.Limits[name]
+	if !cfg.Enabled || limit <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	slots := make(chan struct{}, limit)
+
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		if !acquireSlot(slots, cfg.Wait) {
+			metrics.BulkheadRejected(name)
+			logrus.Warnf("Bulkhead %s saturated (%d in flight), rejecting %s %s", name, limit, c.Method(), c.Path())
+			return saturated(c, cfg.RetryAfter)
+		}
+
+		metrics.BulkheadAcquired(name)
+		defer func() {
+			<-slots
+			metrics.BulkheadReleased(name)
+		}()
+
+		return c.Next()
+	}, routetable.Info{Kind: routetable.KindMiddleware, Detail: fmt.Sprintf("%s: %d concurrent", name, limit)})
+}
+
+// acquireSlot takes a free slot, waiting up to wait for one to be released
+func acquireSlot(slots chan struct{}, wait time.Duration) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if wait <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func saturated(c *fiber.Ctx, retryAfter time.Duration) error {
+	seconds := int(retryAfter.Round(time.Second).Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+
+	return response.Error(c, fiber.StatusServiceUnavailable, "Server is busy. Please try again later.", nil)
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"app/src/listener"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/sync/singleflight"
+)
+
+// sharedResponse is the leader's response replayed to coalesced waiters
+type sharedResponse struct {
+	status  int
+	body    []byte
+	headers map[string][]byte
+}
+
+// NewRequestDedupMiddleware coalesces identical concurrent GET requests into one execution.
+// Requests share a result only when they came through the same listener with the same cache key,
+// Authorization header and verified client certificate, so per-caller responses are never handed
+// to another caller. The group is shared by all listeners.
+func NewRequestDedupMiddleware() fiber.Handler {
+	var group singleflight.Group
+
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet || shouldSkipCache(c) {
+			return c.Next()
+		}
+
+		key := dedupKey(c)
+		leader := false
+
+		result, err, shared := group.Do(key, func() (interface{}, error) {
+			leader = true
+			if err := c.Next(); err != nil {
+				return nil, err
+			}
+			return captureResponse(c), nil
+		})
+
+		// The leader already wrote its own response (or returns its own error)
+		if leader {
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		replayResponse(c, result.(*sharedResponse))
+		if shared {
+			c.Set("X-Dedup", "shared")
+		}
+		return nil
+	}
+}
+
+// dedupKey combines the response cache key with the listener and a hash of the caller's
+// credentials: its Authorization header and its client certificate
+func dedupKey(c *fiber.Ctx) string {
+	key := GenerateCacheKey(c.Method(), c.Path(), string(c.Request().URI().QueryString()))
+	key += ":listener:" + listener.Name(c.Context().Conn())
+
+	if auth := c.Get(fiber.HeaderAuthorization); auth != "" {
+		sum := sha256.Sum256([]byte(auth))
+		key += ":auth:" + hex.EncodeToString(sum[:8])
+	}
+	if cert := listener.ClientCertificate(c.Context().Conn()); cert != nil {
+		sum := sha256.Sum256(cert.Raw)
+		key += ":cert:" + hex.EncodeToString(sum[:8])
+	}
+
+	return key
+}
+
+// captureResponse copies the response, since fasthttp reuses its buffers after the request
+func captureResponse(c *fiber.Ctx) *sharedResponse {
+	resp := &sharedResponse{
+		status:  c.Response().StatusCode(),
+		body:    append([]byte(nil), c.Response().Body()...),
+		headers: make(map[string][]byte),
+	}
+
+	// Cookies are never shared between callers
+	c.Response().Header.VisitAll(func(key, value []byte) {
+		if string(key) == fiber.HeaderSetCookie {
+			return
+		}
+		resp.headers[string(key)] = append([]byte(nil), value...)
+	})
+
+	return resp
+}
+
+// replayResponse writes a captured response to a waiter's context
+func replayResponse(c *fiber.Ctx, resp *sharedResponse) {
+	for key, value := range resp.headers {
+		c.Response().Header.SetBytesV(key, value)
+	}
+	c.Status(resp.status)
+	c.Response().SetBodyRaw(resp.body)
+}
@@ -5,6 +5,10 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+
+	"app/src/cache"
+
+	"github.com/gofiber/fiber/v2"
 )
 
 const (
@@ -61,19 +65,9 @@ func sortQueryParams(queryString string) string {
 	return strings.Join(sortedParts, "&")
 }
 
-// shouldSkipCache determines if a given path should bypass caching
-func shouldSkipCache(path string) bool {
-	skipPaths := []string{
-		"/login",
-		"/register",
-		"/auth/token",
-		"/auth/refresh",
-	}
-
-	for _, skipPath := range skipPaths {
-		if strings.HasPrefix(path, skipPath) {
-			return true
-		}
-	}
-	return false
+// shouldSkipCache reports whether a request bypasses caching under the current skip rules
+func shouldSkipCache(c *fiber.Ctx) bool {
+	return cache.CurrentSkipList().Skips(c.Method(), c.Path(), func(name string) string {
+		return c.Get(name)
+	})
 }
@@ -1,29 +1,28 @@
 package cache
 
 import (
+	"app/src/accesstoken"
+	"strings"
 	"time"
 
-	"app/src/redis"
+	"app/src/cache"
+	"app/src/config"
+	"app/src/routetable"
+	"app/src/static"
 
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	fibercache "github.com/gofiber/fiber/v2/middleware/cache"
-	redisstorage "github.com/gofiber/storage/redis/v3"
 )
 
-// NewResponseCacheMiddleware creates a Fiber cache middleware with Redis storage backend
-// Returns nil if Redis client is unavailable (graceful degradation)
-func NewResponseCacheMiddleware(redisClient *redis.RedisClient) fiber.Handler {
-	// If Redis client is nil, disable caching gracefully
-	if redisClient == nil {
+// NewResponseCacheMiddleware creates a Fiber cache middleware backed by the shared cache store
+// Returns nil if the cache store is unavailable (graceful degradation)
+func NewResponseCacheMiddleware(store cache.Store, cacheConfig *config.ResponseCacheConfig) fiber.Handler {
+	// If the cache store is nil, disable caching gracefully
+	if store == nil || cacheConfig == nil {
 		return nil
 	}
 
-	// Get underlying go-redis client from our RedisClient wrapper
-	goRedisClient := redisClient.GetClient()
-
-	// Create Redis storage backend
-	store := redisstorage.NewFromConnection(goRedisClient)
-
 	// Configure cache middleware
 	config := fibercache.Config{
 		// Next determines if we should skip caching for this request
@@ -36,8 +35,18 @@ func NewResponseCacheMiddleware(redisClient *redis.RedisClient) fiber.Handler {
 				return true
 			}
 
-			// Skip auth endpoints
-			if shouldSkipCache(path) {
+			// Skip WebSocket handshakes, which must reach the handler to switch protocols
+			if websocket.IsWebSocketUpgrade(c) {
+				return true
+			}
+
+			// Skip requests matching a skip rule and anything outside the API (e.g. static frontend files)
+			if !static.IsAPIPath(path) || shouldSkipCache(c) {
+				return true
+			}
+
+			// Skip routes whose responses differ by caller (see Policy)
+			if routePolicy(c, cacheConfig.DefaultPolicy) != PolicyPublic {
 				return true
 			}
 
@@ -50,9 +59,14 @@ func NewResponseCacheMiddleware(redisClient *redis.RedisClient) fiber.Handler {
 			return false
 		},
 
-		// Expiration: 30 minutes (aligns with Phase 1 decision for API cache TTL)
+		// Expiration: default TTL (30 minutes unless RESPONSE_CACHE_TTL is set)
 		// Within the 5-30 minute range recommended for API response cache
-		Expiration: 30 * time.Minute,
+		Expiration: cacheConfig.DefaultTTL,
+
+		// ExpirationGenerator: per-route TTL overrides from RESPONSE_CACHE_ROUTE_TTLS
+		ExpirationGenerator: func(c *fiber.Ctx, _ *fibercache.Config) time.Duration {
+			return cacheConfig.TTLFor(normalizePath(c.Path()))
+		},
 
 		// CacheHeader: X-Cache (shows hit/miss/unreachable status)
 		CacheHeader: "X-Cache",
@@ -62,7 +76,7 @@ func NewResponseCacheMiddleware(redisClient *redis.RedisClient) fiber.Handler {
 			return GenerateCacheKey(c.Method(), c.Path(), string(c.Request().URI().QueryString()))
 		},
 
-		// Storage: Redis backend
+		// Storage: shared cache store (Redis or in-memory)
 		Storage: store,
 
 		// Methods: Only cache safe methods (GET, HEAD)
@@ -72,6 +86,74 @@ func NewResponseCacheMiddleware(redisClient *redis.RedisClient) fiber.Handler {
 		CacheControl: true,
 	}
 
-	// Return cache middleware handler
-	return fibercache.New(config)
+	cacheHandler := fibercache.New(config)
+
+	// Responses cached before an outage may have missed invalidations; drop them on recovery
+	cache.SubscribeAvailability(store, func(available bool) {
+		if available {
+			go cache.PurgePrefix(store, cache.ResponseKeyPrefix)
+		}
+	})
+
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		// Response cache can be disabled at runtime through the cache admin API,
+		// and is bypassed while the store is down
+		if !cache.IsResponseCacheEnabled() || !cache.IsStoreAvailable(store) {
+			return c.Next()
+		}
+
+		// Only privileged roles may force a fresh read with Cache-Control: no-cache/no-store
+		if hasBypassDirective(c) && !canBypassCache(c, cacheConfig) {
+			c.Request().Header.Del(fiber.HeaderCacheControl)
+		}
+
+		err := cacheHandler(c)
+
+		// Track hit/miss ratio for cache statistics
+		switch string(c.Response().Header.Peek("X-Cache")) {
+		case "hit":
+			cache.RecordResponseCacheHit()
+		case "miss":
+			cache.RecordResponseCacheMiss()
+		}
+
+		return err
+	}, routetable.Info{Kind: routetable.KindCache, DetailFor: func(method, path string) string {
+		return cachePolicy(cacheConfig, method, path)
+	}})
+}
+
+// cachePolicy describes how responses of a route are cached, matching Next above
+func cachePolicy(cacheConfig *config.ResponseCacheConfig, method, path string) string {
+	// Header rules depend on the request, so only path and method rules show here
+	if (method != fiber.MethodGet && method != fiber.MethodHead) || !static.IsAPIPath(path) ||
+		cache.CurrentSkipList().Skips(method, path, nil) {
+		return "off"
+	}
+	if cacheConfig.DefaultPolicy != PolicyPublic {
+		return cacheConfig.DefaultPolicy
+	}
+	return "ttl " + cacheConfig.TTLFor(normalizePath(path)).String()
+}
+
+// hasBypassDirective reports whether the request asks to skip cached responses
+func hasBypassDirective(c *fiber.Ctx) bool {
+	cacheControl := c.Get(fiber.HeaderCacheControl)
+	return strings.Contains(cacheControl, "no-cache") || strings.Contains(cacheControl, "no-store")
+}
+
+// canBypassCache checks the role embedded in the caller's access token
+// The cache runs before the auth middleware, so the token is inspected directly
+func canBypassCache(c *fiber.Ctx, cacheConfig *config.ResponseCacheConfig) bool {
+	token := strings.TrimSpace(strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "))
+	if token == "" {
+		return false
+	}
+
+	claims, err := accesstoken.Verify(token)
+	if err != nil {
+		return false
+	}
+
+	return cacheConfig.CanBypass(claims.Role)
 }
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"app/src/cache"
+	"app/src/routetable"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Route cache policies
+const (
+	// PolicyPublic responses are stored in the shared cache and served to every caller, before any
+	// auth middleware runs, so only use it for responses that are the same for everyone
+	PolicyPublic = "public"
+	// PolicyPrivate responses are never stored in the shared cache; the caller's browser may keep them
+	PolicyPrivate = "private"
+	// PolicyNoStore responses are not kept anywhere
+	PolicyNoStore = "no-store"
+)
+
+// policyKey holds the route's policy in c.Locals for the response cache to read after the handler
+const policyKey = "cachePolicy"
+
+// Policy declares how the responses of the routes it is added to are cached, overriding
+// RESPONSE_CACHE_DEFAULT_POLICY. Add it to a group or a route; the one closest to the handler wins.
+// Private and no-store responses get a matching Cache-Control header unless the handler set one.
+// Policy panics on an unknown policy.
+func Policy(policy string) fiber.Handler {
+	if policy != PolicyPublic && policy != PolicyPrivate && policy != PolicyNoStore {
+		panic("cache: unknown policy " + policy)
+	}
+
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		c.Locals(policyKey, policy)
+
+		err := c.Next()
+
+		if policy != PolicyPublic && len(c.Response().Header.Peek(fiber.HeaderCacheControl)) == 0 {
+			c.Set(fiber.HeaderCacheControl, policy)
+		}
+		return err
+	}, routetable.Info{Kind: routetable.KindCache, DetailFor: func(method, path string) string {
+		if method != fiber.MethodGet && method != fiber.MethodHead {
+			return "off"
+		}
+		if policy == PolicyPublic && cache.CurrentSkipList().Skips(method, path, nil) {
+			return "off"
+		}
+		return policy
+	}})
+}
+
+// routePolicy returns the policy the route declared, or the default
+func routePolicy(c *fiber.Ctx, defaultPolicy string) string {
+	if policy, ok := c.Locals(policyKey).(string); ok {
+		return policy
+	}
+	return defaultPolicy
+}
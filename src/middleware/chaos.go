@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"app/src/chaos"
+	"app/src/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Chaos injects the http faults into requests: latency before the handler runs, a 503 in place of
+// the response, or a connection closed without any response
+func Chaos() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch chaos.Inject(c.UserContext(), config.ChaosHTTP) {
+		case chaos.Fail:
+			return fiber.NewError(fiber.StatusServiceUnavailable, "Injected fault")
+		case chaos.Drop:
+			return c.Context().Conn().Close()
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"app/src/listener"
+	"app/src/policy"
+	"app/src/service"
+	"crypto/x509"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// serviceAccountService resolves client certificates to service accounts; nil leaves them disabled
+var serviceAccountService service.ServiceAccountService
+
+// EnableClientCertificates lets the auth middlewares authenticate service accounts by the client
+// certificate verified on listeners with a client CA, for requests without a bearer token
+func EnableClientCertificates(s service.ServiceAccountService) {
+	serviceAccountService = s
+}
+
+// clientCertificate returns the verified client certificate of the request, if service accounts
+// are enabled
+func clientCertificate(c *fiber.Ctx) *x509.Certificate {
+	if serviceAccountService == nil {
+		return nil
+	}
+	return listener.ClientCertificate(c.Context().Conn())
+}
+
+// authenticateClientCertificate authorizes the service user of a client certificate with its
+// account's rights
+func authenticateClientCertificate(c *fiber.Ctx, cert *x509.Certificate, p policy.Policy) error {
+	account, err := serviceAccountService.AuthenticateCertificate(c, cert)
+	if err != nil {
+		return err
+	}
+
+	user := account.User
+	if !user.IsActive {
+		return service.ErrAccountSuspended
+	}
+
+	rights := account.RightList()
+	setUser(c, user)
+	c.Locals("scopes", rights)
+
+	subject := policy.Subject{UserID: user.ID.String(), Role: user.Role, Rights: rights}
+	if err := authorize(c, subject, p); err != nil {
+		return err
+	}
+
+	if err := consumeQuota(c, user, nil); err != nil {
+		return err
+	}
+
+	meterRequest(c, user, nil)
+
+	return limitTagged(c, user)
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"app/src/accesstoken"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"app/src/cache"
+	"app/src/config"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/utils/id"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Capture reasons
+const (
+	CaptureReasonSampled = "sampled"
+	CaptureReasonHeader  = "header"
+)
+
+// debugRight lets a caller force a capture with the debug header
+const debugRight = "debugRequests"
+
+// sensitiveHeaders are never stored, even redacted
+var sensitiveHeaders = []string{
+	fiber.HeaderAuthorization, fiber.HeaderCookie, fiber.HeaderSetCookie, fiber.HeaderProxyAuthorization, "X-Captcha-Token",
+}
+
+// textualTypes are the content types whose bodies are captured; others are summarised by size
+var textualTypes = []string{"json", "text/", "xml", "x-www-form-urlencoded"}
+
+// NewDebugCapture stores sanitized request/response bodies for a sample of requests, or for
+// requests carrying the debug header from a caller with the "debugRequests" right.
+// Captures are keyed by the X-Request-ID response header and expire after cfg.TTL.
+func NewDebugCapture(store cache.Store, cfg config.DebugCaptureConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		reason := captureReason(c, cfg)
+		if reason == "" || !cache.IsStoreAvailable(store) {
+			return c.Next()
+		}
+
+		requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+		if requestID == "" {
+			requestID = id.NewString()
+			c.Set(fiber.HeaderXRequestID, requestID)
+		}
+
+		capture := response.DebugCapture{
+			RequestID:      requestID,
+			Reason:         reason,
+			Method:         c.Method(),
+			Path:           c.Path(),
+			Query:          utils.Redact(string(c.Request().URI().QueryString())),
+			RequestHeaders: make(map[string]string),
+			RequestBody:    captureBody(c.Get(fiber.HeaderContentType), c.Body(), cfg.MaxBodyBytes),
+			CapturedAt:     time.Now().UTC(),
+		}
+		c.Request().Header.VisitAll(func(key, value []byte) {
+			capture.RequestHeaders[string(key)] = captureHeader(string(key), string(value))
+		})
+
+		start := time.Now()
+		err := c.Next()
+		capture.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+
+		// Errors are rendered by the app's error handler after the middleware chain returns
+		capture.Status = c.Response().StatusCode()
+		if err != nil {
+			capture.Status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				capture.Status = fiberErr.Code
+			}
+			capture.Error = utils.Redact(err.Error())
+		}
+
+		capture.ResponseHeaders = make(map[string]string)
+		c.Response().Header.VisitAll(func(key, value []byte) {
+			capture.ResponseHeaders[string(key)] = captureHeader(string(key), string(value))
+		})
+		capture.ResponseBody = captureBody(
+			string(c.Response().Header.ContentType()), c.Response().Body(), cfg.MaxBodyBytes,
+		)
+
+		if data, marshalErr := json.Marshal(capture); marshalErr == nil {
+			if setErr := store.Set(cache.GetDebugCaptureKey(requestID), data, cfg.TTL); setErr != nil {
+				logrus.Warnf("Failed to store debug capture: %v", setErr)
+			}
+		}
+
+		return err
+	}
+}
+
+// captureReason decides whether to capture the request; empty means no
+func captureReason(c *fiber.Ctx, cfg config.DebugCaptureConfig) string {
+	if c.Get(config.DebugCaptureHeader) != "" && canForceCapture(c) {
+		return CaptureReasonHeader
+	}
+	if cfg.SamplePercent > 0 && rand.Float64()*100 < cfg.SamplePercent {
+		return CaptureReasonSampled
+	}
+	return ""
+}
+
+// canForceCapture checks the caller's access token directly, since capture runs before route auth
+func canForceCapture(c *fiber.Ctx) bool {
+	token := bearerToken(c)
+	if token == "" || isAPIToken(token) {
+		return false
+	}
+
+	claims, err := accesstoken.Verify(token)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(claims.Scopes, debugRight)
+}
+
+func captureHeader(name, value string) string {
+	for _, sensitive := range sensitiveHeaders {
+		if strings.EqualFold(name, sensitive) {
+			return utils.Redacted
+		}
+	}
+	return utils.Redact(value)
+}
+
+func captureBody(contentType string, body []byte, limit int) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if !isTextual(contentType) {
+		if contentType == "" {
+			contentType = "unknown"
+		}
+		return fmt.Sprintf("[%s body, %d bytes]", contentType, len(body))
+	}
+
+	text := string(body)
+	if len(text) > limit {
+		text = text[:limit] + "...[truncated]"
+	}
+	return utils.Redact(text)
+}
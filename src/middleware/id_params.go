@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"app/src/utils"
+	"app/src/utils/id"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ValidateIDs parses the named route params (such as "userId") as IDs before the rest of the chain
+// runs, answering 400 for malformed ones so they never reach policies or services. Handlers read
+// the parsed IDs with utils.ParamID.
+func ValidateIDs(names ...string) fiber.Handler {
+	messages := make([]string, len(names))
+	for i, name := range names {
+		messages[i] = "Invalid " + idLabel(name)
+	}
+
+	return func(c *fiber.Ctx) error {
+		for i, name := range names {
+			parsed, err := id.Parse(c.Params(name))
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, messages[i])
+			}
+			utils.SetParamID(c, name, parsed)
+		}
+		return c.Next()
+	}
+}
+
+// idLabel turns a param name into words for error messages: "userId" is "user ID", "id" is "ID"
+func idLabel(name string) string {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "Id"), "ID")
+	if name == "" || strings.EqualFold(name, "id") {
+		return "ID"
+	}
+
+	var label strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			label.WriteByte(' ')
+		}
+		label.WriteRune(unicode.ToLower(r))
+	}
+	return label.String() + " ID"
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"app/src/config"
+	"app/src/routetable"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/basicauth"
+)
+
+// InternalOnly limits the routes behind it to the internal listener, when INTERNAL_ADDR is set. In
+// production without it or basic auth, the routes answer 404 on every listener.
+func InternalOnly() fiber.Handler {
+	if config.Internal.Closed {
+		return routetable.Describe(func(c *fiber.Ctx) error {
+			return fiber.NewError(fiber.StatusNotFound, "Endpoint Not Found")
+		}, routetable.Info{Kind: routetable.KindAuth, Detail: "closed"})
+	}
+	if !config.Internal.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	return OnListener(config.InternalListener)
+}
+
+// InternalBasicAuth checks the internal basic auth credentials, when set. It is meant for endpoints
+// with no auth of their own, since it takes over the Authorization header; chain it after
+// InternalOnly so other listeners answer 404 rather than asking for credentials.
+func InternalBasicAuth() fiber.Handler {
+	if config.Internal.BasicAuthUser == "" {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	return basicauth.New(basicauth.Config{
+		Users: map[string]string{config.Internal.BasicAuthUser: config.Internal.BasicAuthPassword},
+		Realm: "internal",
+	})
+}
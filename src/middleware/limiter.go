@@ -1,42 +1,38 @@
 package middleware
 
 import (
+	"app/src/cache"
 	"app/src/config"
-	"app/src/redis"
 	"app/src/response"
+	"app/src/routetable"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
-	redisstorage "github.com/gofiber/storage/redis/v3"
 	"github.com/sirupsen/logrus"
 )
 
-// NewRateLimiterMiddleware creates rate limiter middleware with Redis storage and sliding window algorithm
-func NewRateLimiterMiddleware(redisClient *redis.RedisClient, rateLimitConfig *config.RateLimiterConfig) fiber.Handler {
-	// RATE-05: Graceful degradation - return nil if Redis unavailable
-	if redisClient == nil || rateLimitConfig == nil || !rateLimitConfig.Enabled {
-		logrus.Info("Rate limiter disabled (Redis unavailable or disabled)")
+// NewRateLimiterMiddleware creates rate limiter middleware with the shared cache store and sliding window algorithm
+func NewRateLimiterMiddleware(store cache.Store, rateLimitConfig *config.RateLimiterConfig) fiber.Handler {
+	// RATE-05: Graceful degradation - return nil if the cache store is unavailable
+	if store == nil || rateLimitConfig == nil || !rateLimitConfig.Enabled {
+		logrus.Info("Rate limiter disabled (cache store unavailable or disabled)")
 		return nil
 	}
 
-	// Create Redis storage from existing client
-	// Reuse Phase 1's Redis client - DO NOT create new connection
-	store := redisstorage.NewFromConnection(redisClient.GetClient())
-
 	// Use the higher max and larger window to accommodate both authenticated and unauthenticated users
 	// Fiber v2 doesn't support dynamic MaxFunc/ExpirationFunc, so we use single configuration
-	maxRequests := rateLimitConfig.AuthMax
-	if rateLimitConfig.DefaultMax > maxRequests {
-		maxRequests = rateLimitConfig.DefaultMax
-	}
+	maxRequests, windowDuration := rateLimitSettings(rateLimitConfig)
 
-	windowDuration := rateLimitConfig.AuthWindow
-	if rateLimitConfig.DefaultWindow > windowDuration {
-		windowDuration = rateLimitConfig.DefaultWindow
-	}
+	cache.SubscribeAvailability(store, func(available bool) {
+		if available {
+			logrus.Info("Rate limiter resumed (cache store available)")
+		} else {
+			logrus.Warn("Rate limiter suspended (cache store unavailable)")
+		}
+	})
 
 	// Configure rate limiter with sliding window
-	return limiter.New(limiter.Config{
+	limiterHandler := limiter.New(limiter.Config{
 		// RATE-03: Use higher limit (supports both authenticated and unauthenticated)
 		Max: maxRequests,
 		// RATE-02: Use larger window (supports both authenticated and unauthenticated)
@@ -45,30 +41,36 @@ func NewRateLimiterMiddleware(redisClient *redis.RedisClient, rateLimitConfig *c
 		KeyGenerator: func(c *fiber.Ctx) string {
 			// Check for authenticated user first
 			if userID := c.Locals("user_id"); userID != nil {
-				return fmt.Sprintf("rate_limit:user:%v", userID)
+				return rateLimitKey(fmt.Sprintf("user:%v", userID))
 			}
 			// RATE-01: Check for proxy headers (X-Forwarded-For, CF-Connecting-IP)
 			if forwardedFor := c.Get("X-Forwarded-For"); forwardedFor != "" {
-				return fmt.Sprintf("rate_limit:ip:%s", forwardedFor)
+				return rateLimitKey("ip:" + forwardedFor)
 			}
 			if cfIP := c.Get("CF-Connecting-IP"); cfIP != "" {
-				return fmt.Sprintf("rate_limit:ip:%s", cfIP)
+				return rateLimitKey("ip:" + cfIP)
 			}
 			// Fallback to connection IP
-			return fmt.Sprintf("rate_limit:ip:%s", c.IP())
+			return rateLimitKey("ip:" + c.IP())
 		},
 		LimitReached: func(c *fiber.Ctx) error {
 			// RATE-04: Return 429 Too Many Requests
 			// Fiber automatically sets Retry-After header based on Expiration
-			return c.Status(fiber.StatusTooManyRequests).
-				JSON(response.Common{
-					Code:    fiber.StatusTooManyRequests,
-					Status:  "error",
-					Message: "Too many requests. Please try again later.",
-				})
+			return response.Error(c, fiber.StatusTooManyRequests, "Too many requests. Please try again later.", nil)
 		},
-		Storage:                store,                   // RATE-01: Redis storage backend
+		Storage:                store,                   // RATE-01: Shared storage backend (Redis or in-memory)
 		LimiterMiddleware:      limiter.SlidingWindow{}, // RATE-02: Sliding window algorithm
 		SkipSuccessfulRequests: true,                    // Don't count successful requests towards limit
 	})
+
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		// Fail open while the store is down instead of erroring every request
+		if !cache.IsStoreAvailable(store) {
+			return c.Next()
+		}
+		return limiterHandler(c)
+	}, routetable.Info{
+		Kind:   routetable.KindRateLimit,
+		Detail: fmt.Sprintf("%d failed requests per %s per user or IP", maxRequests, windowDuration),
+	})
 }
@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/response"
+	"bytes"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// rateLimitSettings resolves the single max/window pair the global limiter runs with;
+// Fiber v2 has no per-request limits, so the larger of the anonymous and authenticated values wins
+func rateLimitSettings(cfg *config.RateLimiterConfig) (int, time.Duration) {
+	maxRequests := cfg.AuthMax
+	if cfg.DefaultMax > maxRequests {
+		maxRequests = cfg.DefaultMax
+	}
+
+	window := cfg.AuthWindow
+	if cfg.DefaultWindow > window {
+		window = cfg.DefaultWindow
+	}
+
+	return maxRequests, window
+}
+
+// rateLimitKey returns the global limiter key for a subject
+// Format: rate_limit:{user|ip}:{id}
+func rateLimitKey(subject string) string {
+	return cache.RateLimitKeyPrefix + subject
+}
+
+// SlidingWindowInspector reads and clears the global limiter's state for a subject
+// ("user:{id}" or "ip:{addr}"), decoding Fiber's storage format
+type SlidingWindowInspector struct {
+	store  cache.Store
+	max    int
+	window time.Duration
+}
+
+// NewRateLimitInspector inspects the global sliding window rate limiter
+func NewRateLimitInspector(store cache.Store, cfg *config.RateLimiterConfig) *SlidingWindowInspector {
+	maxRequests, window := rateLimitSettings(cfg)
+	return &SlidingWindowInspector{store: store, max: maxRequests, window: window}
+}
+
+func (i *SlidingWindowInspector) Name() string {
+	return "rate_limit"
+}
+
+// Inspect decodes Fiber's msgp-encoded limiter entry and weighs it the way limiter.SlidingWindow does
+func (i *SlidingWindowInspector) Inspect(subject string) (*response.RateLimitState, error) {
+	key := rateLimitKey(subject)
+
+	data, err := i.store.Get(key)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	currHits, prevHits, exp, err := decodeLimiterItem(data)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uint64(time.Now().Unix())
+	expiration := uint64(i.window.Seconds())
+
+	// A lapsed window has not been rolled over yet because no request arrived since
+	if now >= exp {
+		prevHits, currHits = currHits, 0
+		if elapsed := now - exp; elapsed >= expiration {
+			prevHits = 0
+			exp = now + expiration
+		} else {
+			exp = now + expiration - elapsed
+		}
+	}
+
+	weight := float64(exp-now) / float64(expiration)
+	hits := int(float64(prevHits)*weight) + currHits
+	resetAt := time.Unix(int64(exp), 0).UTC()
+
+	return &response.RateLimitState{
+		Limiter:   i.Name(),
+		Key:       key,
+		Hits:      hits,
+		Limit:     i.max,
+		Remaining: max(i.max-hits, 0),
+		Blocked:   hits >= i.max,
+		ResetAt:   &resetAt,
+	}, nil
+}
+
+func (i *SlidingWindowInspector) Reset(subject string) (bool, error) {
+	return resetKey(i.store, rateLimitKey(subject))
+}
+
+// decodeLimiterItem reads the limiter's {currHits, prevHits, exp} map
+func decodeLimiterItem(data []byte) (int, int, uint64, error) {
+	reader := msgp.NewReader(bytes.NewReader(data))
+
+	size, err := reader.ReadMapHeader()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var currHits, prevHits int
+	var exp uint64
+	for ; size > 0; size-- {
+		field, err := reader.ReadString()
+		if err != nil {
+			return 0, 0, 0, err
+		}
+
+		switch field {
+		case "currHits":
+			currHits, err = reader.ReadInt()
+		case "prevHits":
+			prevHits, err = reader.ReadInt()
+		case "exp":
+			exp, err = reader.ReadUint64()
+		default:
+			err = reader.Skip()
+		}
+		if err != nil {
+			return 0, 0, 0, err
+		}
+	}
+
+	return currHits, prevHits, exp, nil
+}
+
+// ThrottleInspector reads and clears a per-target throttle's state for a lowercased email
+type ThrottleInspector struct {
+	store cache.Store
+	name  string
+	cfg   config.ThrottleConfig
+}
+
+// NewThrottleInspector inspects the per-target throttle registered under name with NewTargetThrottle
+func NewThrottleInspector(store cache.Store, name string, cfg config.ThrottleConfig) *ThrottleInspector {
+	return &ThrottleInspector{store: store, name: name, cfg: cfg}
+}
+
+func (i *ThrottleInspector) Name() string {
+	return "throttle:" + i.name
+}
+
+func (i *ThrottleInspector) Inspect(subject string) (*response.RateLimitState, error) {
+	key := throttleKey(i.name, subject)
+
+	data, err := i.store.Get(key)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	state := loadThrottleState(i.store, key)
+
+	now := time.Now()
+	hits := state.Count
+	resetAt := time.Unix(state.WindowStart, 0).Add(i.cfg.Window).UTC()
+	if !now.Before(resetAt) {
+		hits = 0
+	}
+
+	snapshot := &response.RateLimitState{
+		Limiter:   i.Name(),
+		Key:       key,
+		Hits:      hits,
+		Limit:     i.cfg.Max,
+		Remaining: max(i.cfg.Max-hits, 0),
+		ResetAt:   &resetAt,
+		Strikes:   state.Strikes,
+	}
+
+	if blockedUntil := time.Unix(state.BlockedUntil, 0).UTC(); now.Before(blockedUntil) {
+		snapshot.Blocked = true
+		snapshot.BlockedUntil = &blockedUntil
+	}
+
+	return snapshot, nil
+}
+
+func (i *ThrottleInspector) Reset(subject string) (bool, error) {
+	return resetKey(i.store, throttleKey(i.name, subject))
+}
+
+// LoginDelayInspector reads and clears the failed login count of a lowercased email
+type LoginDelayInspector struct {
+	store cache.Store
+	cfg   config.LoginDelayConfig
+}
+
+// NewLoginDelayInspector inspects the delays applied by NewLoginDelay
+func NewLoginDelayInspector(store cache.Store, cfg config.LoginDelayConfig) *LoginDelayInspector {
+	return &LoginDelayInspector{store: store, cfg: cfg}
+}
+
+func (i *LoginDelayInspector) Name() string {
+	return "login-delay"
+}
+
+// Inspect reports failed logins as hits; the email is blocked until its delay is over
+func (i *LoginDelayInspector) Inspect(subject string) (*response.RateLimitState, error) {
+	key := loginDelayKey(subject)
+
+	data, err := i.store.Get(key)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	state := loadLoginDelayState(i.store, key)
+
+	nextAt := time.UnixMilli(state.NextAt).UTC()
+	resetAt := nextAt.Add(i.cfg.Reset)
+	snapshot := &response.RateLimitState{
+		Limiter: i.Name(),
+		Key:     key,
+		Hits:    state.Failures,
+		ResetAt: &resetAt,
+	}
+
+	if time.Now().Before(nextAt) {
+		snapshot.Blocked = true
+		snapshot.BlockedUntil = &nextAt
+	}
+
+	return snapshot, nil
+}
+
+func (i *LoginDelayInspector) Reset(subject string) (bool, error) {
+	return resetKey(i.store, loginDelayKey(subject))
+}
+
+func resetKey(store cache.Store, key string) (bool, error) {
+	data, err := store.Get(key)
+	if err != nil || data == nil {
+		return false, err
+	}
+	return true, store.Delete(key)
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"app/src/listener"
+	"app/src/routetable"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OnListener serves the routes behind it only to connections accepted by one of the named
+// listeners; on any other listener they answer 404 as if they did not exist
+func OnListener(names ...string) fiber.Handler {
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		if !slices.Contains(names, listener.Name(c.Context().Conn())) {
+			return fiber.NewError(fiber.StatusNotFound, "Endpoint Not Found")
+		}
+		return c.Next()
+	}, routetable.Info{Kind: routetable.KindAuth, Detail: "listener(" + strings.Join(names, ", ") + ")"})
+}
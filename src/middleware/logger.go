@@ -7,7 +7,7 @@ import (
 
 func LoggerConfig() fiber.Handler {
 	return logger.New(logger.Config{
-		Format:     "${time} ${method} ${status} ${path} in ${latency}\n",
+		Format:     "${time} ${respHeader:X-Request-ID} ${method} ${status} ${path} in ${latency}\n",
 		TimeFormat: "15:04:05.00",
 	})
 }
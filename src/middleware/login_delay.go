@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/metrics"
+	"app/src/response"
+	"app/src/routetable"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// loginDelayState is the per-target record of failed logins kept in the cache store
+type loginDelayState struct {
+	Failures int `json:"failures"`
+	// NextAt is when the next attempt may be made, in Unix milliseconds
+	NextAt int64 `json:"next_at"`
+}
+
+// NewLoginDelay makes a target (an email) wait longer after each failed login, whatever IP the
+// attempts come from. Failures are 401 responses from the handler: the first delays the next attempt
+// by BaseDelay, and each further one doubles it, up to MaxDelay. Attempts made before the delay is over
+// get 429 with Retry-After and never reach the handler. A successful login clears the count.
+// With a store implementing cache.Counter, only one attempt goes through per delay, even in parallel.
+func NewLoginDelay(store cache.Store, cfg config.LoginDelayConfig, target ThrottleTarget) fiber.Handler {
+	if store == nil || !cfg.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	counter, _ := store.(cache.Counter)
+
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		subject := target(c)
+		if subject == "" || !cache.IsStoreAvailable(store) {
+			return c.Next()
+		}
+
+		key := loginDelayKey(subject)
+		state := loadLoginDelayState(store, key)
+
+		if nextAt := time.UnixMilli(state.NextAt); time.Now().Before(nextAt) {
+			metrics.RecordLoginDelayed()
+			return loginDelayed(c, time.Until(nextAt))
+		}
+
+		// Parallel attempts all find the delay over; only the first one for this failure count goes on
+		if counter != nil && state.Failures > 0 {
+			delay := loginDelay(cfg, state.Failures)
+			gate := fmt.Sprintf("%s:%d", key, state.Failures)
+			if attempts, err := counter.IncrBy(c.UserContext(), gate, 1, delay); err == nil && attempts > 1 {
+				metrics.RecordLoginDelayed()
+				return loginDelayed(c, delay)
+			}
+		}
+
+		err := c.Next()
+
+		var fiberErr *fiber.Error
+		switch {
+		case errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusUnauthorized:
+			state.Failures++
+			delay := loginDelay(cfg, state.Failures)
+			state.NextAt = time.Now().Add(delay).UnixMilli()
+			saveLoginDelayState(store, key, state, delay+cfg.Reset)
+			metrics.RecordLoginDelay(delay)
+		case err == nil && c.Response().StatusCode() < fiber.StatusBadRequest && state.Failures > 0:
+			_ = store.Delete(key)
+		}
+
+		return err
+	}, routetable.Info{
+		Kind:   routetable.KindRateLimit,
+		Detail: fmt.Sprintf("login delay: %s doubled per failed login per target, up to %s", cfg.BaseDelay, cfg.MaxDelay),
+	})
+}
+
+// loginDelay doubles the base delay per previous failure, capped at MaxDelay
+func loginDelay(cfg config.LoginDelayConfig, failures int) time.Duration {
+	delay := cfg.BaseDelay
+	for i := 1; i < failures && delay < cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > cfg.MaxDelay {
+		delay = cfg.MaxDelay
+	}
+	return delay
+}
+
+// loginDelayKey hashes the target so emails don't appear in the cache keyspace
+func loginDelayKey(target string) string {
+	sum := sha256.Sum256([]byte(target))
+	return cache.LoginDelayKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+func loadLoginDelayState(store cache.Store, key string) loginDelayState {
+	var state loginDelayState
+	data, err := store.Get(key)
+	if err != nil || data == nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return loginDelayState{}
+	}
+	return state
+}
+
+func saveLoginDelayState(store cache.Store, key string, state loginDelayState, ttl time.Duration) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	if err := store.Set(key, data, ttl); err != nil {
+		logrus.Warnf("Failed to save login delay state: %v", err)
+	}
+}
+
+func loginDelayed(c *fiber.Ctx, retryAfter time.Duration) error {
+	// Round up so a client waiting exactly Retry-After is never early
+	seconds := int((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+
+	return response.Error(c, fiber.StatusTooManyRequests, "Too many failed login attempts. Please try again later.", nil)
+}
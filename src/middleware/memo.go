@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"app/src/memo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Memo gives each request its own lookup cache through its user context, dropped once the
+// request has been handled
+func Memo() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, done := memo.WithCache(c.UserContext())
+		defer done()
+		c.SetUserContext(ctx)
+
+		return c.Next()
+	}
+}
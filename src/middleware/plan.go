@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/routetable"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrPlanRequired rejects users whose plan does not include the resource; 402 lets clients offer an upgrade
+var ErrPlanRequired = fiber.NewError(fiber.StatusPaymentRequired, "Upgrade your plan to access this resource")
+
+// RequirePlan only lets through users on one of the plans; it must run after an auth middleware
+func RequirePlan(plans ...string) fiber.Handler {
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		if !HasPlan(c, plans...) {
+			return ErrPlanRequired
+		}
+		return c.Next()
+	}, routetable.Info{Kind: routetable.KindAuth, Detail: "plan(" + strings.Join(plans, ", ") + ")"})
+}
+
+// HasPlan reports whether the authenticated user is on one of the plans, for handlers that only
+// gate part of a response. Users without a plan are on the default plan.
+func HasPlan(c *fiber.Ctx, plans ...string) bool {
+	user, ok := c.Locals("user").(*model.User)
+	if !ok || user == nil {
+		return false
+	}
+
+	plan := user.Plan
+	if plan == "" {
+		plan = config.Quota.DefaultPlan
+	}
+	return slices.Contains(plans, plan)
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"app/src/model"
+	"app/src/service"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// quotaService counts requests against plan quotas; nil leaves quotas disabled
+var quotaService service.QuotaService
+
+// EnableQuotas makes the auth middlewares count authenticated requests against the caller's daily
+// and monthly quotas, on top of rate limiting
+func EnableQuotas(s service.QuotaService) {
+	quotaService = s
+}
+
+// consumeQuota counts the request and reports the caller's quota in the X-Quota-* headers; the reset
+// headers, like X-RateLimit-Reset, are seconds from now
+func consumeQuota(c *fiber.Ctx, user *model.User, token *model.APIToken) error {
+	if quotaService == nil {
+		return nil
+	}
+
+	usage, err := quotaService.Consume(c.UserContext(), user, token)
+
+	now := time.Now()
+	for _, u := range usage {
+		prefix := "X-Quota-Daily-"
+		if u.Period == model.QuotaMonthly {
+			prefix = "X-Quota-Monthly-"
+		}
+		reset := int(u.Reset.Sub(now).Seconds())
+
+		c.Set(prefix+"Limit", strconv.FormatInt(u.Limit, 10))
+		c.Set(prefix+"Remaining", strconv.FormatInt(u.Remaining(), 10))
+		c.Set(prefix+"Reset", strconv.Itoa(reset))
+
+		if (u.Period == model.QuotaDaily && errors.Is(err, service.ErrDailyQuotaExceeded)) ||
+			(u.Period == model.QuotaMonthly && errors.Is(err, service.ErrMonthlyQuotaExceeded)) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(reset))
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"strings"
+
+	"app/src/dryrun"
+	"app/src/readonly"
+	"app/src/response"
+	"app/src/routetable"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReadOnly refuses requests that write with 503 and the code read_only while the app is in read-only
+// mode. Reads, dry runs and the paths in allow (by prefix) go on.
+func ReadOnly(allow []string) fiber.Handler {
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		if !readonly.Enabled() || !writes(c.Method()) || dryrun.Requested(c) {
+			return c.Next()
+		}
+		for _, prefix := range allow {
+			if strings.HasPrefix(c.Path(), prefix) {
+				return c.Next()
+			}
+		}
+
+		state := readonly.Current()
+		return response.Error(c, fiber.StatusServiceUnavailable, "Service is in read-only mode, try again later",
+			response.ReadOnlyRefusal{Code: "read_only", Reason: state.Reason, Since: state.Since})
+	}, routetable.Info{Kind: routetable.KindMiddleware, Detail: "read-only mode"})
+}
+
+func writes(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return false
+	}
+	return true
+}
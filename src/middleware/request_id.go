@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"app/src/tracing"
+	"app/src/utils/id"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestID gives each request an ID, returned in the X-Request-ID response header and carried by
+// its user context (see tracing.RequestID). An ID set by the client or a proxy is kept when it is
+// well-formed, so one ID follows the request across services.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(fiber.HeaderXRequestID)
+		if !tracing.ValidRequestID(requestID) {
+			requestID = id.NewString()
+		}
+
+		c.Set(fiber.HeaderXRequestID, requestID)
+		c.SetUserContext(tracing.WithRequestID(c.UserContext(), requestID))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"app/src/routetable"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireSignature only lets through partner requests carrying a valid, fresh and unreplayed
+// HMAC signature (see requestsig); handlers read the partner from Locals("partner")
+func RequireSignature(partnerService service.PartnerService) fiber.Handler {
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		partner, err := partnerService.Authenticate(c)
+		if err != nil {
+			return err
+		}
+
+		c.Locals("partner", partner)
+		return c.Next()
+	}, routetable.Info{Kind: routetable.KindAuth, Detail: "signature(partner)"})
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import "app/src/revocation"
+
+// revocations rejects stateless tokens of revoked users, and any token of a revoked session; nil
+// disables the check
+var revocations *revocation.Bus
+
+// EnableRevocation makes auth reject the tokens of revoked sessions, and stateless auth the tokens
+// issued before a user was revoked
+func EnableRevocation(bus *revocation.Bus) {
+	revocations = bus
+}
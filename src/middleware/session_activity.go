@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sessionActivity records when sessions were last used; nil leaves activity untracked
+var sessionActivity service.SessionActivityService
+
+// EnableSessionActivity makes the auth middlewares record activity for access tokens issued to a session
+func EnableSessionActivity(activity service.SessionActivityService) {
+	sessionActivity = activity
+}
+
+// touchSession marks the caller's session as active; API tokens and older access tokens have none
+func touchSession(c *fiber.Ctx, sessionID string) {
+	if sessionActivity != nil && sessionID != "" {
+		sessionActivity.Touch(c.UserContext(), sessionID)
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"app/src/signedurl"
+	"errors"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireSignedURL only lets requests through whose URL carries a valid, unexpired signature
+func RequireSignedURL(signer *signedurl.Signer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+		if err != nil {
+			return fiber.NewError(fiber.StatusForbidden, "Invalid or expired link")
+		}
+
+		err = signer.VerifyQuery(c.UserContext(), c.Path(), query)
+		switch {
+		case err == nil:
+			return c.Next()
+		case errors.Is(err, signedurl.ErrAlreadyUsed):
+			return fiber.NewError(fiber.StatusGone, "Link has already been used")
+		case errors.Is(err, signedurl.ErrInvalidSignature),
+			errors.Is(err, signedurl.ErrExpired),
+			errors.Is(err, signedurl.ErrUnknownKey):
+			return fiber.NewError(fiber.StatusForbidden, "Invalid or expired link")
+		default:
+			return fiber.NewError(fiber.StatusServiceUnavailable, "Link verification is temporarily unavailable")
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// tagLimit is the throttle for users carrying one rate-limited tag
+type tagLimit struct {
+	max     int
+	handler fiber.Handler
+}
+
+// tagLimits throttles tagged users per tag; nil leaves them to the global limiter only
+var tagLimits map[string]tagLimit
+
+// EnableTagRateLimits makes the auth middlewares apply the configured per-tag limits. The limits are
+// per user and come on top of the global rate limiter, so they can only tighten it.
+func EnableTagRateLimits(store cache.Store, cfg config.TagRateLimitConfig) {
+	tagLimits = nil
+	if store == nil || len(cfg.Limits) == 0 {
+		return
+	}
+
+	tagLimits = make(map[string]tagLimit, len(cfg.Limits))
+	for tag, max := range cfg.Limits {
+		throttle := config.ThrottleConfig{
+			Enabled:     true,
+			Max:         max,
+			Window:      cfg.Window,
+			BaseBackoff: cfg.Backoff,
+			MaxBackoff:  cfg.MaxBackoff,
+		}
+		tagLimits[tag] = tagLimit{max: max, handler: NewTargetThrottle(store, "tag:"+tag, throttle, userIDFromUser, nil)}
+	}
+}
+
+// limitTagged continues the chain through the throttle of the user's most restrictive limited tag
+func limitTagged(c *fiber.Ctx, user *model.User) error {
+	var strictest *tagLimit
+	for _, tag := range user.Tags {
+		if limit, ok := tagLimits[tag.Tag]; ok && (strictest == nil || limit.max < strictest.max) {
+			strictest = &limit
+		}
+	}
+
+	if strictest == nil {
+		return c.Next()
+	}
+	return strictest.handler(c)
+}
+
+// userIDFromUser reads the throttle target from the authenticated user
+func userIDFromUser(c *fiber.Ctx) string {
+	user, ok := c.Locals("user").(*model.User)
+	if !ok || user == nil {
+		return ""
+	}
+	return user.ID.String()
+}
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/model"
+	"app/src/response"
+	"app/src/routetable"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// ThrottleTarget extracts the identity being targeted (e.g. an email) from the request;
+// an empty result skips throttling
+type ThrottleTarget func(c *fiber.Ctx) string
+
+// CaptchaHook verifies a CAPTCHA solution on the request, returning an error to reject it
+type CaptchaHook func(c *fiber.Ctx) error
+
+// throttleState is the per-target record kept in the cache store
+type throttleState struct {
+	Count        int   `json:"count"`
+	WindowStart  int64 `json:"window_start"`
+	Strikes      int   `json:"strikes"`
+	BlockedUntil int64 `json:"blocked_until"`
+}
+
+// NewTargetThrottle limits requests per target rather than per IP, so one address can't be
+// spammed from many IPs. Each violation blocks the target for an exponentially growing backoff,
+// and after repeated violations the CAPTCHA hook (if any) must pass as well.
+// Counting is read-modify-write, so concurrent bursts may slightly exceed Max.
+func NewTargetThrottle(
+	store cache.Store, name string, cfg config.ThrottleConfig, target ThrottleTarget, captcha CaptchaHook,
+) fiber.Handler {
+	if store == nil || !cfg.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return routetable.Describe(func(c *fiber.Ctx) error {
+		subject := target(c)
+		if subject == "" || !cache.IsStoreAvailable(store) {
+			return c.Next()
+		}
+
+		key := throttleKey(name, subject)
+		now := time.Now()
+
+		state := loadThrottleState(store, key)
+
+		if blockedUntil := time.Unix(state.BlockedUntil, 0); now.Before(blockedUntil) {
+			return throttled(c, blockedUntil.Sub(now))
+		}
+
+		if captcha != nil && cfg.CaptchaAfter > 0 && state.Strikes >= cfg.CaptchaAfter {
+			if err := captcha(c); err != nil {
+				return err
+			}
+		}
+
+		if now.Sub(time.Unix(state.WindowStart, 0)) >= cfg.Window {
+			state.Count = 0
+			state.WindowStart = now.Unix()
+		}
+		state.Count++
+
+		if state.Count > cfg.Max {
+			backoff := throttleBackoff(cfg, state.Strikes)
+			state.Strikes++
+			state.Count = 0
+			state.WindowStart = now.Unix()
+			state.BlockedUntil = now.Add(backoff).Unix()
+			saveThrottleState(store, key, state, backoff+cfg.Window)
+
+			logrus.Warnf("Throttled %s for target %s (strike %d, backoff %v)", name, key, state.Strikes, backoff)
+			return throttled(c, backoff)
+		}
+
+		// Strikes are forgotten once the target stays quiet for a whole window after its last block
+		ttl := cfg.Window
+		if remaining := time.Until(time.Unix(state.BlockedUntil, 0)); remaining > 0 {
+			ttl += remaining
+		}
+		if state.Strikes > 0 {
+			ttl += cfg.Window
+		}
+		saveThrottleState(store, key, state, ttl)
+
+		return c.Next()
+	}, routetable.Info{
+		Kind:   routetable.KindRateLimit,
+		Detail: fmt.Sprintf("%s: %d per %s per target", name, cfg.Max, cfg.Window),
+	})
+}
+
+// EmailFromBody reads the target email from a JSON or form body with an "email" field
+func EmailFromBody(c *fiber.Ctx) string {
+	body := struct {
+		Email string `json:"email" form:"email"`
+	}{}
+	if err := c.BodyParser(&body); err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(body.Email))
+}
+
+// EmailFromUser reads the target email from the authenticated user (run after Auth)
+func EmailFromUser(c *fiber.Ctx) string {
+	user, ok := c.Locals("user").(*model.User)
+	if !ok || user == nil {
+		return ""
+	}
+	return strings.ToLower(user.Email)
+}
+
+// ClientIP targets the caller's IP, for public endpoints without an account to throttle
+func ClientIP(c *fiber.Ctx) string {
+	return c.IP()
+}
+
+// throttleBackoff doubles the base backoff per previous strike, capped at MaxBackoff
+func throttleBackoff(cfg config.ThrottleConfig, strikes int) time.Duration {
+	backoff := cfg.BaseBackoff
+	for i := 0; i < strikes && backoff < cfg.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > cfg.MaxBackoff {
+		backoff = cfg.MaxBackoff
+	}
+	return backoff
+}
+
+// throttleKey hashes the target so emails don't appear in the cache keyspace
+func throttleKey(name, target string) string {
+	sum := sha256.Sum256([]byte(target))
+	return fmt.Sprintf("%s%s:%s", cache.ThrottleKeyPrefix, name, hex.EncodeToString(sum[:]))
+}
+
+func loadThrottleState(store cache.Store, key string) throttleState {
+	var state throttleState
+	data, err := store.Get(key)
+	if err != nil || data == nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return throttleState{}
+	}
+	return state
+}
+
+func saveThrottleState(store cache.Store, key string, state throttleState, ttl time.Duration) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	if err := store.Set(key, data, ttl); err != nil {
+		logrus.Warnf("Failed to save throttle state: %v", err)
+	}
+}
+
+func throttled(c *fiber.Ctx, retryAfter time.Duration) error {
+	seconds := int(retryAfter.Round(time.Second).Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+
+	return response.Error(c, fiber.StatusTooManyRequests, "Too many requests for this account. Please try again later.", nil)
+}
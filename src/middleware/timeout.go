@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Timeout gives each request a deadline through its user context. Handlers are not interrupted,
+// but calls made with c.UserContext() are cancelled once it passes, and a request that failed
+// because of the deadline is answered with 408.
+func Timeout(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if err == nil {
+			return nil
+		}
+
+		// Errors already mapped to a status (e.g. 404) stand even if the deadline passed meanwhile
+		var fiberErr *fiber.Error
+		if errors.Is(err, context.DeadlineExceeded) || (ctx.Err() != nil && !errors.As(err, &fiberErr)) {
+			return fiber.NewError(fiber.StatusRequestTimeout, "Request timed out")
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"app/src/config"
+	"app/src/httpclient"
+	"app/src/metrics"
+	"app/src/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// TrafficMirrorHeader marks mirrored requests so the shadow deployment can tell them apart
+const TrafficMirrorHeader = "X-Traffic-Mirror"
+
+// Mirror outcomes reported to metrics
+const (
+	mirrorSent    = "sent"
+	mirrorFailed  = "failed"
+	mirrorDropped = "dropped"
+	mirrorSkipped = "skipped"
+)
+
+// mirrorDroppedHeaders identify the client or only make sense for the original hop
+var mirrorDroppedHeaders = []string{
+	"X-Forwarded-For", "X-Real-Ip", "Cf-Connecting-Ip", "True-Client-Ip", "Forwarded",
+	fiber.HeaderHost, fiber.HeaderConnection, fiber.HeaderContentLength, config.DebugCaptureHeader,
+}
+
+// mirroredRequest is a sanitized copy of a request, detached from the fasthttp context
+type mirroredRequest struct {
+	method string
+	path   string
+	query  string
+	header http.Header
+	body   []byte
+}
+
+type trafficMirror struct {
+	cfg    config.TrafficMirrorConfig
+	target *url.URL
+	client *http.Client
+	queue  chan mirroredRequest
+}
+
+// NewTrafficMirror copies a sample of requests to cfg.TargetURL in the background, e.g. to try an
+// upgrade against live traffic. Credentials and client IPs are dropped, PII in the query and body is
+// redacted, and excluded paths such as /v1/auth are never mirrored. The shadow's responses are
+// discarded, and when the queue is full mirrored requests are dropped rather than slowing the app.
+func NewTrafficMirror(cfg config.TrafficMirrorConfig) fiber.Handler {
+	target, err := url.Parse(cfg.TargetURL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		logrus.Warnf("Traffic mirroring disabled: invalid TRAFFIC_MIRROR_URL %q", cfg.TargetURL)
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	m := &trafficMirror{
+		cfg:    cfg,
+		target: target,
+		// Mirroring is best-effort; a failed copy is never retried
+		client: httpclient.New("traffic-mirror", httpclient.WithTimeout(cfg.Timeout), httpclient.WithRetries(0)),
+		queue:  make(chan mirroredRequest, cfg.QueueSize),
+	}
+	for i := 0; i < max(cfg.Workers, 1); i++ {
+		go m.work()
+	}
+
+	return func(c *fiber.Ctx) error {
+		if m.sampled(c) {
+			m.enqueue(c)
+		}
+		return c.Next()
+	}
+}
+
+// sampled picks cfg.SamplePercent of the requests outside the excluded paths
+func (m *trafficMirror) sampled(c *fiber.Ctx) bool {
+	path := c.Path()
+	for _, excluded := range m.cfg.ExcludedPaths {
+		if path == excluded || strings.HasPrefix(path, strings.TrimSuffix(excluded, "/")+"/") {
+			return false
+		}
+	}
+
+	return m.cfg.SamplePercent > 0 && rand.Float64()*100 < m.cfg.SamplePercent
+}
+
+// enqueue copies the request for a worker. Bodies that are binary or too large to send whole are
+// not mirrored, since they cannot be scrubbed or would arrive truncated.
+func (m *trafficMirror) enqueue(c *fiber.Ctx) {
+	body := c.Body()
+	if len(body) > m.cfg.MaxBodyBytes || (len(body) > 0 && !isTextual(c.Get(fiber.HeaderContentType))) {
+		metrics.TrafficMirrored(mirrorSkipped)
+		return
+	}
+
+	req := mirroredRequest{
+		method: c.Method(),
+		path:   c.Path(),
+		query:  utils.Redact(string(c.Request().URI().QueryString())),
+		header: make(http.Header),
+		body:   []byte(utils.Redact(string(body))),
+	}
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		name := string(key)
+		if !mirrorsHeader(name) {
+			return
+		}
+		req.header.Add(name, utils.Redact(string(value)))
+	})
+	req.header.Set(TrafficMirrorHeader, "1")
+
+	select {
+	case m.queue <- req:
+	default:
+		metrics.TrafficMirrored(mirrorDropped)
+	}
+}
+
+func (m *trafficMirror) work() {
+	for req := range m.queue {
+		if err := m.send(req); err != nil {
+			logrus.Debugf("Traffic mirror request failed: %v", err)
+			metrics.TrafficMirrored(mirrorFailed)
+			continue
+		}
+		metrics.TrafficMirrored(mirrorSent)
+	}
+}
+
+func (m *trafficMirror) send(req mirroredRequest) error {
+	target := *m.target
+	target.Path = strings.TrimSuffix(target.Path, "/") + req.path
+	target.RawQuery = req.query
+
+	httpReq, err := http.NewRequestWithContext(context.Background(), req.method, target.String(), bytes.NewReader(req.body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header = req.header
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
+
+// mirrorsHeader reports whether a header may be copied to the shadow deployment
+func mirrorsHeader(name string) bool {
+	for _, header := range sensitiveHeaders {
+		if strings.EqualFold(name, header) {
+			return false
+		}
+	}
+	for _, header := range mirrorDroppedHeaders {
+		if strings.EqualFold(name, header) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTextual reports whether a body of this content type can be scrubbed as text
+func isTextual(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, kind := range textualTypes {
+		if strings.Contains(contentType, kind) {
+			return true
+		}
+	}
+	return false
+}
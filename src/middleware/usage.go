@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"app/src/model"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// usageService meters authenticated requests; nil leaves metering disabled
+var usageService service.UsageService
+
+// EnableUsageMetering makes the auth middlewares count authenticated requests for usage reporting
+func EnableUsageMetering(s service.UsageService) {
+	usageService = s
+}
+
+// meterRequest counts a request that passed authentication and quotas
+func meterRequest(c *fiber.Ctx, user *model.User, token *model.APIToken) {
+	if usageService != nil {
+		usageService.RecordRequest(c.UserContext(), user, token)
+	}
+}
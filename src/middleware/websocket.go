@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebSocketBearerProtocol is the subprotocol browsers offer before their access token, as they
+// cannot set the Authorization header of a WebSocket handshake
+const WebSocketBearerProtocol = "bearer"
+
+// WebSocketUpgrade rejects requests that are not WebSocket handshakes and moves an access token
+// offered as the subprotocol after "bearer" into the Authorization header, so Auth can verify it
+func WebSocketUpgrade() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return fiber.NewError(fiber.StatusUpgradeRequired, "WebSocket upgrade required")
+		}
+
+		if c.Get(fiber.HeaderAuthorization) == "" {
+			protocols := strings.Split(c.Get("Sec-WebSocket-Protocol"), ",")
+			for i := 0; i+1 < len(protocols); i++ {
+				if strings.TrimSpace(protocols[i]) == WebSocketBearerProtocol {
+					c.Request().Header.Set(fiber.HeaderAuthorization, "Bearer "+strings.TrimSpace(protocols[i+1]))
+					break
+				}
+			}
+		}
+
+		return c.Next()
+	}
+}
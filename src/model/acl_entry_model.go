@@ -0,0 +1,64 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ACL permissions, each implying the ones before it
+const (
+	ACLRead  = "read"
+	ACLWrite = "write"
+	ACLOwner = "owner"
+)
+
+// ACLPermissions lists the permissions from weakest to strongest
+var ACLPermissions = []string{ACLRead, ACLWrite, ACLOwner}
+
+// ACL principal prefixes; a principal is "user:<id>", "role:<role>" or "tag:<tag>"
+const (
+	ACLPrincipalUser = "user:"
+	ACLPrincipalRole = "role:"
+	ACLPrincipalTag  = "tag:"
+)
+
+// ACLEntry grants a principal a permission on one record of an application resource, such as
+// ("document", "42", "user:<id>", "write"). Grants to deleted users are left behind; IDs are never
+// reused, so they grant nothing.
+type ACLEntry struct {
+	ID           uuid.UUID `gorm:"primaryKey;not null"`
+	ResourceType string    `gorm:"not null"`
+	ResourceID   string    `gorm:"not null"`
+	Principal    string    `gorm:"not null"`
+	Permission   string    `gorm:"not null"`
+	GrantedBy    *uuid.UUID
+	CreatedAt    time.Time `gorm:"autoCreateTime:milli"`
+}
+
+func (entry *ACLEntry) BeforeCreate(_ *gorm.DB) error {
+	entry.ID = id.New()
+	return nil
+}
+
+// ImpliedBy returns the permissions that grant permission: itself and every stronger one. Unknown
+// permissions are only granted by themselves.
+func ImpliedBy(permission string) []string {
+	for i, p := range ACLPermissions {
+		if p == permission {
+			return ACLPermissions[i:]
+		}
+	}
+	return []string{permission}
+}
+
+// Principals returns the ACL principals the user acts as: themselves, their role and their tags
+func (user *User) Principals() []string {
+	principals := []string{ACLPrincipalUser + user.ID.String(), ACLPrincipalRole + user.Role}
+	for _, tag := range user.Tags {
+		principals = append(principals, ACLPrincipalTag+tag.Tag)
+	}
+	return principals
+}
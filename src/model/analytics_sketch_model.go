@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// AnalyticsSketch is the HyperLogLog of the unique visitors of a period, as last saved from Redis.
+// Endpoint is empty for the sketches of active users.
+type AnalyticsSketch struct {
+	Kind     string `gorm:"primaryKey"`
+	Period   string `gorm:"primaryKey"`
+	Endpoint string `gorm:"primaryKey"`
+	// Sketch holds the registers of the HyperLogLog, not the values counted
+	Sketch    []byte `gorm:"not null"`
+	Count     int64
+	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIToken is a personal access token restricted to a subset of its owner's rights, or an access
+// token a user delegated to a third-party client, restricted to the granted OAuth scopes
+type APIToken struct {
+	ID         uuid.UUID `gorm:"primaryKey;not null"`
+	UserID     uuid.UUID `gorm:"not null"`
+	ClientID   *uuid.UUID
+	Name       string    `gorm:"not null"`
+	TokenHash  string    `gorm:"uniqueIndex;not null"`
+	Prefix     string    `gorm:"not null"`
+	Scopes     string    `gorm:"not null"` // comma-separated rights, or OAuth scopes for client tokens
+	ExpiresAt  time.Time `gorm:"not null"`
+	LastUsedAt *time.Time
+	CreatedAt  time.Time    `gorm:"autoCreateTime:milli"`
+	UpdatedAt  time.Time    `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+	User       *User        `gorm:"foreignKey:user_id;references:id"`
+	Client     *OAuthClient `gorm:"foreignKey:client_id;references:id"`
+}
+
+func (token *APIToken) BeforeCreate(_ *gorm.DB) error {
+	token.ID = id.New()
+	return nil
+}
+
+// ScopeList returns the token's rights, or the OAuth scopes of a client token, as a slice
+func (token *APIToken) ScopeList() []string {
+	if token.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(token.Scopes, ",")
+}
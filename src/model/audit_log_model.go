@@ -0,0 +1,83 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Audit log actions
+const (
+	AuditActionLoginAssessed         = "login.risk_assessed"
+	AuditActionLoginConfirm          = "login.confirmed"
+	AuditActionLoginSucceeded        = "login.succeeded"
+	AuditActionLoginFailed           = "login.failed"
+	AuditActionRefreshReplayed       = "token.refresh_replayed"
+	AuditActionEmailSent             = "email.sent"
+	AuditActionUserCreated           = "user.created"
+	AuditActionRoleChanged           = "user.role_changed"
+	AuditActionSuspended             = "user.suspended"
+	AuditActionReactivated           = "user.reactivated"
+	AuditActionUserRestored          = "user.restored"
+	AuditActionPasswordChanged       = "user.password_changed"
+	AuditActionDigestSent            = "security.digest_sent"
+	AuditActionRateLimitReset        = "ratelimit.reset"
+	AuditActionDomainRuleAdded       = "emaildomain.rule_added"
+	AuditActionDomainRuleRemoved     = "emaildomain.rule_removed"
+	AuditActionSuppressionAdded      = "email.suppression_added"
+	AuditActionSuppressionRemoved    = "email.suppression_removed"
+	AuditActionCacheSkipRuleAdded    = "cache.skip_rule_added"
+	AuditActionCacheSkipRuleRemoved  = "cache.skip_rule_removed"
+	AuditActionCachePurged           = "cache.purged"
+	AuditActionQuotaUpdated          = "quota.updated"
+	AuditActionACLGranted            = "acl.granted"
+	AuditActionACLRevoked            = "acl.revoked"
+	AuditActionPartnerCreated        = "partner.created"
+	AuditActionPartnerDeleted        = "partner.deleted"
+	AuditActionServiceAccountCreated = "service_account.created"
+	AuditActionServiceAccountDeleted = "service_account.deleted"
+	AuditActionOAuthClientCreated    = "oauth.client_created"
+	AuditActionOAuthClientDeleted    = "oauth.client_deleted"
+	AuditActionOAuthConsentGranted   = "oauth.consent_granted"
+	AuditActionOAuthConsentRevoked   = "oauth.consent_revoked"
+	AuditActionBulkActionQueued      = "user.bulk_action_queued"
+	AuditActionBulkActionCompleted   = "user.bulk_action_completed"
+	AuditActionPhoneVerified         = "user.phone_verified"
+	AuditActionPhoneRemoved          = "user.phone_removed"
+	AuditActionMessageSent           = "message.sent"
+	AuditActionMessageDelivered      = "message.delivered"
+	AuditActionMessageFailed         = "message.failed"
+	AuditActionRetentionPurged       = "retention.purged"
+	AuditActionLegalHoldPlaced       = "user.legal_hold_placed"
+	AuditActionLegalHoldLifted       = "user.legal_hold_lifted"
+	AuditActionReadOnlyEnabled       = "read_only.enabled"
+	AuditActionReadOnlyDisabled      = "read_only.disabled"
+	AuditActionAuditLogsExported     = "audit_logs.exported"
+)
+
+// AuditActorSystem is the actor type of entries recorded by background work
+const AuditActorSystem = "system"
+
+// AuditLog is an append-only record of a security-relevant event
+type AuditLog struct {
+	ID     uuid.UUID  `gorm:"primaryKey;not null"`
+	UserID *uuid.UUID `gorm:"index"`
+	// ActorType is the type of the user who acted (UserTypeHuman or UserTypeService), AuditActorSystem
+	// for background work, or nil for anonymous requests
+	ActorType *string
+	Action    string `gorm:"not null"`
+	IP        string
+	UserAgent string
+	Metadata  string `gorm:"type:jsonb;not null;default:'{}'"`
+	// RequestID is the X-Request-ID of the request that caused the entry, nil for background work
+	// not started by a request
+	RequestID *string
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
+
+func (log *AuditLog) BeforeCreate(_ *gorm.DB) error {
+	log.ID = id.New()
+	return nil
+}
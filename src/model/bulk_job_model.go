@@ -0,0 +1,79 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Bulk user actions
+const (
+	BulkActionDeactivate = "deactivate"
+	BulkActionDelete     = "delete"
+	BulkActionAssignRole = "assign-role"
+	BulkActionAddTag     = "add-tag"
+)
+
+// Bulk job states
+const (
+	BulkJobQueued    = "queued"
+	BulkJobRunning   = "running"
+	BulkJobCompleted = "completed"
+)
+
+// Bulk job item outcomes
+const (
+	BulkItemPending   = "pending"
+	BulkItemSucceeded = "succeeded"
+	BulkItemSkipped   = "skipped"
+	BulkItemFailed    = "failed"
+)
+
+// BulkJob is a bulk user action queued for the background worker. Its targets are resolved when it
+// is submitted and stored as BulkJobItems, so a job picked up again after a crash resumes where it
+// stopped.
+type BulkJob struct {
+	ID     uuid.UUID `gorm:"primaryKey;not null"`
+	Action string    `gorm:"not null"`
+	// Value is the role assign-role gives or the tag add-tag adds
+	Value      string `gorm:"not null;default:''"`
+	Status     string `gorm:"not null"`
+	Total      int    `gorm:"not null"`
+	Succeeded  int    `gorm:"not null;default:0"`
+	Skipped    int    `gorm:"not null;default:0"`
+	Failed     int    `gorm:"not null;default:0"`
+	CreatedBy  *uuid.UUID
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime:milli"`
+	// UpdatedAt moves with every processed item; a running job that stops moving is requeued
+	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (job *BulkJob) BeforeCreate(_ *gorm.DB) error {
+	job.ID = id.New()
+	return nil
+}
+
+// Processed counts the items done, whatever their outcome
+func (job *BulkJob) Processed() int {
+	return job.Succeeded + job.Skipped + job.Failed
+}
+
+// BulkJobItem is the outcome of a bulk action for one user
+type BulkJobItem struct {
+	ID     uuid.UUID `gorm:"primaryKey;not null"`
+	JobID  uuid.UUID `gorm:"not null;index:idx_bulk_job_items_job_status"`
+	UserID uuid.UUID `gorm:"not null"`
+	Status string    `gorm:"not null;index:idx_bulk_job_items_job_status"`
+	// Reason explains a skipped or failed item
+	Reason    string    `gorm:"not null;default:''"`
+	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (item *BulkJobItem) BeforeCreate(_ *gorm.DB) error {
+	item.ID = id.New()
+	return nil
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CacheSkipRule keeps matching requests out of the response cache; added by an admin at runtime
+type CacheSkipRule struct {
+	ID        uuid.UUID `gorm:"primaryKey;not null"`
+	Kind      string    `gorm:"not null"` // path, pattern, method or header (see cache.SkipRule)
+	Value     string    `gorm:"not null"`
+	CreatedBy *uuid.UUID
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
+
+func (rule *CacheSkipRule) BeforeCreate(_ *gorm.DB) error {
+	rule.ID = id.New()
+	return nil
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Email domain rule actions
+const (
+	EmailDomainAllow = "allow"
+	EmailDomainBlock = "block"
+)
+
+// EmailDomainRule is a sign-up domain rule added by an admin at runtime
+type EmailDomainRule struct {
+	ID        uuid.UUID `gorm:"primaryKey;not null"`
+	Pattern   string    `gorm:"not null"` // "example.com" or "*.example.com"
+	Action    string    `gorm:"not null"` // allow or block
+	CreatedBy *uuid.UUID
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
+
+func (rule *EmailDomainRule) BeforeCreate(_ *gorm.DB) error {
+	rule.ID = id.New()
+	return nil
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailSuppressionManual marks a recipient suppressed by an admin; the other reasons come from
+// provider feedback (see package emailfeedback)
+const EmailSuppressionManual = "manual"
+
+// EmailSuppression is a recipient no email is sent to
+type EmailSuppression struct {
+	ID        uuid.UUID `gorm:"primaryKey;not null"`
+	Email     string    `gorm:"not null"` // lowercased
+	Reason    string    `gorm:"not null"` // hard_bounce, complaint, unsubscribe or manual
+	Source    string    `gorm:"not null"` // ses, sendgrid or admin
+	Detail    string
+	CreatedBy *uuid.UUID
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
+
+func (suppression *EmailSuppression) BeforeCreate(_ *gorm.DB) error {
+	suppression.ID = id.New()
+	return nil
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LegalHold keeps a user and their data from being deleted, whether by the user, an admin, a bulk
+// action or a retention purge, until it is lifted. Lifting deletes it; who placed and lifted it is
+// kept in the audit log.
+type LegalHold struct {
+	UserID uuid.UUID `gorm:"primaryKey;not null"`
+	// Reason is shown to the compliance team, e.g. the case the data is preserved for
+	Reason    string `gorm:"not null"`
+	PlacedBy  *uuid.UUID
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
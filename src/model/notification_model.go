@@ -0,0 +1,68 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserPhone is a user's verified phone number, used for the SMS and WhatsApp channels
+type UserPhone struct {
+	UserID     uuid.UUID `gorm:"primaryKey;not null"`
+	Phone      string    `gorm:"serializer:encrypted;not null"` // E.164
+	VerifiedAt time.Time `gorm:"not null"`
+}
+
+// NotificationPreference is the channels a user chose for one kind of notification
+type NotificationPreference struct {
+	UserID    uuid.UUID `gorm:"primaryKey;not null"`
+	Kind      string    `gorm:"primaryKey;not null"`
+	Channels  string    `gorm:"not null"` // comma-separated
+	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+// ChannelList returns the chosen channels as a slice
+func (preference *NotificationPreference) ChannelList() []string {
+	if preference.Channels == "" {
+		return []string{}
+	}
+	return strings.Split(preference.Channels, ",")
+}
+
+// TextMessage is an SMS or WhatsApp message sent to a user, updated by provider status callbacks
+type TextMessage struct {
+	ID                uuid.UUID `gorm:"primaryKey;not null"`
+	UserID            uuid.UUID `gorm:"not null"`
+	Kind              string    `gorm:"not null"`
+	Channel           string    `gorm:"not null"` // sms or whatsapp
+	Provider          string    `gorm:"not null"`
+	ProviderMessageID string    `gorm:"not null"`
+	Status            string    `gorm:"not null"` // see package sms
+	Error             string
+	CreatedAt         time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt         time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (message *TextMessage) BeforeCreate(_ *gorm.DB) error {
+	message.ID = id.New()
+	return nil
+}
+
+// PushDevice is an app install registered for push notifications
+type PushDevice struct {
+	ID        uuid.UUID `gorm:"primaryKey;not null"`
+	UserID    uuid.UUID `gorm:"not null"`
+	Platform  string    `gorm:"not null"` // fcm or apns
+	Token     string    `gorm:"uniqueIndex;not null"`
+	Name      string
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (device *PushDevice) BeforeCreate(_ *gorm.DB) error {
+	device.ID = id.New()
+	return nil
+}
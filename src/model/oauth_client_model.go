@@ -0,0 +1,69 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OAuthClient is a third-party application users can grant scoped access to their account
+type OAuthClient struct {
+	ID   uuid.UUID `gorm:"primaryKey;not null"`
+	Name string    `gorm:"not null"`
+	// SecretHash is the hex SHA-256 of the client secret
+	SecretHash string `gorm:"not null"`
+	Prefix     string `gorm:"not null"`
+	// RedirectURIs are space-separated; authorization requests must match one exactly
+	RedirectURIs string `gorm:"column:redirect_uris;not null"`
+	Scopes       string `gorm:"not null"` // comma-separated scopes the client may request
+	// RateLimit is the requests per minute allowed to all of the client's tokens; 0 uses the default
+	RateLimit int `gorm:"not null"`
+	CreatedBy *uuid.UUID
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (client *OAuthClient) BeforeCreate(_ *gorm.DB) error {
+	client.ID = id.New()
+	return nil
+}
+
+// RedirectURIList returns the client's registered redirect URIs as a slice
+func (client *OAuthClient) RedirectURIList() []string {
+	return strings.Fields(client.RedirectURIs)
+}
+
+// ScopeList returns the scopes the client may request as a slice
+func (client *OAuthClient) ScopeList() []string {
+	if client.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(client.Scopes, ",")
+}
+
+// OAuthConsent records the scopes a user granted to a client, so they are not asked again
+type OAuthConsent struct {
+	ID        uuid.UUID    `gorm:"primaryKey;not null"`
+	UserID    uuid.UUID    `gorm:"not null"`
+	ClientID  uuid.UUID    `gorm:"not null"`
+	Scopes    string       `gorm:"not null"` // comma-separated scopes
+	CreatedAt time.Time    `gorm:"autoCreateTime:milli"`
+	UpdatedAt time.Time    `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+	Client    *OAuthClient `gorm:"foreignKey:client_id;references:id"`
+}
+
+func (consent *OAuthConsent) BeforeCreate(_ *gorm.DB) error {
+	consent.ID = id.New()
+	return nil
+}
+
+// ScopeList returns the granted scopes as a slice
+func (consent *OAuthConsent) ScopeList() []string {
+	if consent.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(consent.Scopes, ",")
+}
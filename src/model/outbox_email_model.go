@@ -0,0 +1,38 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Outbox email states; sent emails are deleted
+const (
+	OutboxEmailPending = "pending"
+	OutboxEmailFailed  = "failed"
+)
+
+// OutboxEmail is an email waiting to be sent by the outbox job, which retries it with backoff until
+// it is sent or runs out of attempts
+type OutboxEmail struct {
+	ID        uuid.UUID `gorm:"primaryKey;not null"`
+	Recipient string    `gorm:"not null"`
+	Subject   string    `gorm:"not null"`
+	// Body holds links with tokens, so it is never exposed and deleted once sent
+	Body          string    `gorm:"not null" json:"-"`
+	Status        string    `gorm:"not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null"`
+	LastError     string    `gorm:"not null;default:''"`
+	// RequestID is the X-Request-ID of the request that queued the email, sent along as its header
+	RequestID string    `gorm:"not null;default:''"`
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (email *OutboxEmail) BeforeCreate(_ *gorm.DB) error {
+	email.ID = id.New()
+	return nil
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Partner is an external system calling the partner API with HMAC-signed requests
+type Partner struct {
+	ID   uuid.UUID `gorm:"primaryKey;not null"`
+	Name string    `gorm:"uniqueIndex;not null"`
+	// SigningKey is the hex HMAC key derived from the partner's secret. Verifying a signature needs
+	// the key itself, so it is encrypted at rest rather than hashed.
+	SigningKey string `gorm:"serializer:encrypted;not null"`
+	Prefix     string `gorm:"not null"`
+	CreatedBy  *uuid.UUID
+	LastUsedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt  time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (partner *Partner) BeforeCreate(_ *gorm.DB) error {
+	partner.ID = id.New()
+	return nil
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request quota periods
+const (
+	QuotaDaily   = "day"
+	QuotaMonthly = "month"
+)
+
+// RequestQuota overrides the plan quotas of a user or API token; a nil limit keeps the default
+// and 0 means unlimited
+type RequestQuota struct {
+	Subject      string `gorm:"primaryKey"` // see UserQuotaSubject and TokenQuotaSubject
+	DailyLimit   *int64
+	MonthlyLimit *int64
+	UpdatedBy    *uuid.UUID
+	UpdatedAt    time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+// RequestQuotaUsage is the request count of a subject in one period, as last persisted
+type RequestQuotaUsage struct {
+	Subject     string    `gorm:"primaryKey"`
+	Period      string    `gorm:"primaryKey"` // day or month
+	PeriodStart time.Time `gorm:"primaryKey;type:date"`
+	Used        int64     `gorm:"not null"`
+	UpdatedAt   time.Time
+}
+
+func (RequestQuotaUsage) TableName() string {
+	return "request_quota_usage"
+}
+
+// UserQuotaSubject identifies a user's quota
+func UserQuotaSubject(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// TokenQuotaSubject identifies an API token's quota
+func TokenQuotaSubject(tokenID uuid.UUID) string {
+	return "token:" + tokenID.String()
+}
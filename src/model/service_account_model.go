@@ -0,0 +1,39 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ServiceAccount is the profile of a service user: the certificate identity it authenticates with
+// and the rights it holds in place of its role's
+type ServiceAccount struct {
+	ID     uuid.UUID `gorm:"primaryKey;not null"`
+	UserID uuid.UUID `gorm:"uniqueIndex;not null"`
+	Name   string    `gorm:"uniqueIndex;not null"`
+	// Identity is the certificate URI, DNS or email SAN, or common name the account is matched by
+	Identity   string `gorm:"uniqueIndex;not null"`
+	Rights     string `gorm:"not null"` // comma-separated rights
+	CreatedBy  *uuid.UUID
+	LastUsedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt  time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+	User       *User     `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (account *ServiceAccount) BeforeCreate(_ *gorm.DB) error {
+	account.ID = id.New()
+	return nil
+}
+
+// RightList returns the account's rights as a slice
+func (account *ServiceAccount) RightList() []string {
+	if account.Rights == "" {
+		return []string{}
+	}
+	return strings.Split(account.Rights, ",")
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SIEMCursor is the last audit entry forwarded to a SIEM sink. Entries are forwarded in the order
+// of (created_at, id), so every entry after the cursor is still to be sent.
+type SIEMCursor struct {
+	Sink          string `gorm:"primaryKey"`
+	LastCreatedAt time.Time
+	LastID        uuid.UUID
+	UpdatedAt     time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
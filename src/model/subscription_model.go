@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subscription is a user's Stripe subscription as last reported by a webhook
+type Subscription struct {
+	ID                string    `gorm:"primaryKey"` // Stripe subscription ID
+	UserID            uuid.UUID `gorm:"not null"`
+	Status            string    `gorm:"not null"`
+	PriceID           string    `gorm:"not null"`
+	Plan              *string   // nil when the price is not mapped to a plan
+	CurrentPeriodEnd  *time.Time
+	CancelAtPeriodEnd bool      `gorm:"not null"`
+	SyncedAt          time.Time `gorm:"not null"` // creation time of the event the row reflects
+	CreatedAt         time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt         time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+// BillingEvent records a processed Stripe webhook event
+type BillingEvent struct {
+	ID        string    `gorm:"primaryKey"` // Stripe event ID
+	Type      string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"app/src/utils/id"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,17 +9,23 @@ import (
 )
 
 type Token struct {
-	ID        uuid.UUID `gorm:"primaryKey;not null"`
-	Token     string    `gorm:"not null"`
-	UserID    uuid.UUID `gorm:"not null"`
-	Type      string    `gorm:"not null"`
-	Expires   time.Time `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
-	UpdatedAt time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
-	User      *User     `gorm:"foreignKey:user_id;references:id"`
+	ID         uuid.UUID `gorm:"primaryKey;not null"`
+	Token      string    `gorm:"not null"`
+	UserID     uuid.UUID `gorm:"not null"`
+	Type       string    `gorm:"not null"`
+	Expires    time.Time `gorm:"not null"`
+	Email      *string   // address a verify-email token was issued for
+	ConsumedAt *time.Time
+	// SessionID groups a refresh token with the tokens it was rotated from and into
+	SessionID    *uuid.UUID
+	LastActiveAt *time.Time
+	BindingHash  *string   // hash of the device binding a refresh token must be presented with
+	CreatedAt    time.Time `gorm:"autoCreateTime:milli"`
+	UpdatedAt    time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli"`
+	User         *User     `gorm:"foreignKey:user_id;references:id"`
 }
 
 func (token *Token) BeforeCreate(_ *gorm.DB) error {
-	token.ID = uuid.New()
+	token.ID = id.New()
 	return nil
 }
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsageMetricRequests counts authenticated requests; other metrics are metered audit actions
+const UsageMetricRequests = "requests"
+
+// UsageRecord is a subject's count of one metric on one UTC day, rolled up from the cache store
+type UsageRecord struct {
+	Day       time.Time `gorm:"primaryKey;type:date"`
+	Subject   string    `gorm:"primaryKey"` // see UserQuotaSubject and TokenQuotaSubject
+	Metric    string    `gorm:"primaryKey"`
+	UserID    uuid.UUID `gorm:"not null"` // the user, or the owner of the API token
+	Count     int64     `gorm:"not null"`
+	UpdatedAt time.Time
+}
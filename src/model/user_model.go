@@ -1,25 +1,54 @@
 package model
 
 import (
+	"app/src/utils/id"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// User types: people sign in themselves, service accounts authenticate with API tokens or client
+// certificates and have no password or deliverable email
+const (
+	UserTypeHuman   = "human"
+	UserTypeService = "service"
+)
+
+// ServiceAccountEmailDomain is reserved for service accounts, which are never sent email
+const ServiceAccountEmailDomain = "service.invalid"
+
 type User struct {
-	ID            uuid.UUID `gorm:"primaryKey;not null" json:"id"`
-	Name          string    `gorm:"not null" json:"name"`
-	Email         string    `gorm:"uniqueIndex;not null" json:"email"`
-	Password      string    `gorm:"not null" json:"-"`
-	Role          string    `gorm:"default:user;not null" json:"role"`
-	VerifiedEmail bool      `gorm:"default:false;not null" json:"verified_email"`
-	CreatedAt     time.Time `gorm:"autoCreateTime:milli" json:"-"`
-	UpdatedAt     time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli" json:"-"`
-	Token         []Token   `gorm:"foreignKey:user_id;references:id" json:"-"`
+	ID               uuid.UUID `gorm:"primaryKey;not null" json:"id"`
+	Name             string    `gorm:"not null" json:"name"`
+	Username         *string   `gorm:"size:30" json:"username"` // unique case-insensitively, nil until chosen
+	Email            string    `gorm:"uniqueIndex;not null" json:"email"`
+	Password         string    `gorm:"not null" json:"-"`
+	Role             string    `gorm:"default:user;not null" json:"role"`
+	Type             string    `gorm:"default:human;not null" json:"type"`
+	VerifiedEmail    bool      `gorm:"default:false;not null" json:"verified_email"`
+	AppleID          *string   `gorm:"uniqueIndex" json:"-"`                   // Sign in with Apple subject
+	IsActive         bool      `gorm:"default:true;not null" json:"is_active"` // false while suspended
+	Plan             string    `gorm:"default:free;not null" json:"plan"`      // sets the default request quotas
+	StripeCustomerID *string   `gorm:"uniqueIndex" json:"-"`
+	Timezone         *string   `gorm:"size:64" json:"timezone"` // IANA name, nil for the default timezone
+	CreatedAt        time.Time `gorm:"autoCreateTime:milli" json:"-"`
+	UpdatedAt        time.Time `gorm:"autoCreateTime:milli;autoUpdateTime:milli" json:"-"`
+	Token            []Token   `gorm:"foreignKey:user_id;references:id" json:"-"`
+	Tags             []UserTag `gorm:"foreignKey:user_id;references:id" json:"-"`
+	// ServiceAccount holds the identity and rights of a service user; it is only loaded for them
+	ServiceAccount *ServiceAccount `gorm:"foreignKey:user_id;references:id" json:"-"`
 }
 
 func (user *User) BeforeCreate(_ *gorm.DB) error {
-	user.ID = uuid.New() // Generate UUID before create
+	user.ID = id.New() // Time-ordered for index locality
+	if user.Type == UserTypeService && user.Email == "" {
+		user.Email = "service-account-" + user.ID.String() + "@" + ServiceAccountEmailDomain
+	}
 	return nil
 }
+
+// IsService reports whether the user is a service account rather than a person
+func (user *User) IsService() bool {
+	return user.Type == UserTypeService
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"app/src/utils/id"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// User revision actions
+const (
+	UserRevisionCreated  = "created"
+	UserRevisionUpdated  = "updated"
+	UserRevisionRestored = "restored" // a rollback to an earlier revision
+)
+
+// UserRevision is one change to a user row, recorded by revision.GormPlugin
+type UserRevision struct {
+	ID           uuid.UUID  `gorm:"primaryKey;not null"`
+	UserID       uuid.UUID  `gorm:"not null"`
+	ActorID      *uuid.UUID // nil for changes made outside a request, such as webhooks
+	Action       string     `gorm:"not null"`
+	Changes      string     `gorm:"type:jsonb;not null;default:'{}'"` // column name to FieldChange
+	RestoredFrom *uuid.UUID
+	CreatedAt    time.Time `gorm:"autoCreateTime:milli"`
+}
+
+func (revision *UserRevision) BeforeCreate(_ *gorm.DB) error {
+	revision.ID = id.New()
+	return nil
+}
+
+// FieldChange is the value of a column before and after a revision. Values of columns hidden from
+// JSON, such as the password hash, are not kept.
+type FieldChange struct {
+	From     any  `json:"from"`
+	To       any  `json:"to"`
+	Redacted bool `json:"redacted,omitempty"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserTag is a free-form label (e.g. beta, vip, abuser) an admin attached to a user
+type UserTag struct {
+	UserID    uuid.UUID `gorm:"primaryKey;not null"`
+	Tag       string    `gorm:"primaryKey;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime:milli"`
+}
+
+// TagNames returns the user's tags, which must have been preloaded
+func (user *User) TagNames() []string {
+	names := make([]string, len(user.Tags))
+	for i, tag := range user.Tags {
+		names[i] = tag.Tag
+	}
+	return names
+}
+
+// HasTag reports whether the user carries the tag
+func (user *User) HasTag(tag string) bool {
+	for _, t := range user.Tags {
+		if t.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
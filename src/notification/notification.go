@@ -0,0 +1,172 @@
+// Package notification defines what users are notified about, the channels notifications go out on
+// and the templates rendering each kind for each channel.
+package notification
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+)
+
+// Channels a notification can be delivered on
+const (
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+	// ChannelPush goes to every device the user registered for push notifications
+	ChannelPush = "push"
+)
+
+// Notification kinds
+const (
+	// KindConfirmLogin asks the user to confirm a sign-in the risk engine held back
+	KindConfirmLogin = "confirm_login"
+	// KindPhoneVerification carries the code confirming a phone number; it always goes to that number
+	KindPhoneVerification = "phone_verification"
+	// KindNewLogin tells the user about a sign-in to their account
+	KindNewLogin = "new_login"
+	// KindPasswordChanged tells the user their password was changed or reset
+	KindPasswordChanged = "password_changed"
+)
+
+// Preferable lists the kinds users choose channels for, with the channels used until they do
+var Preferable = map[string][]string{
+	KindConfirmLogin:    {ChannelEmail},
+	KindNewLogin:        {ChannelPush},
+	KindPasswordChanged: {ChannelPush},
+}
+
+// required lists the kinds the user cannot act without; they fall back to email when none of the
+// chosen channels can reach the user, while the others are then not sent at all
+var required = map[string]bool{
+	KindConfirmLogin: true,
+}
+
+// Required reports whether kind falls back to email when none of the chosen channels can reach the user
+func Required(kind string) bool {
+	return required[kind]
+}
+
+// ErrNoTemplate means a kind cannot be sent over a channel
+var ErrNoTemplate = errors.New("no template for this notification and channel")
+
+// Message is a rendered notification; Subject is only set for email, and is the title of pushes
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Data is what templates render from
+type Data struct {
+	// URL is the link the user should follow, such as the sign-in confirmation page
+	URL string
+	// Code is a one-time code to enter, such as a phone verification code
+	Code string
+	// IP is the address a sign-in came from
+	IP string
+}
+
+// channelTemplates renders one kind on one channel
+type channelTemplates struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// templates holds each kind per channel; text messages are kept to one SMS segment (160 characters)
+// where the link allows it
+var templates = map[string]map[string]channelTemplates{
+	KindConfirmLogin: {
+		ChannelEmail: {
+			subject: parse("Confirm your sign-in"),
+			body: parse(`Dear user,
+
+We noticed a sign-in to your account from an unusual location or network.
+If this was you, confirm it by clicking on this link: {{.URL}}
+
+If this was not you, ignore this email and change your password.`),
+		},
+		ChannelSMS: {body: parse("Unusual sign-in to your account. If this was you, confirm it: {{.URL}}")},
+		ChannelWhatsApp: {
+			body: parse("We noticed an unusual sign-in to your account. If this was you, confirm it here: {{.URL}}"),
+		},
+	},
+	KindPhoneVerification: {
+		ChannelSMS:      {body: parse("Your verification code is {{.Code}}. Do not share it with anyone.")},
+		ChannelWhatsApp: {body: parse("Your verification code is {{.Code}}. Do not share it with anyone.")},
+	},
+	KindNewLogin: {
+		ChannelEmail: {
+			subject: parse("New sign-in to your account"),
+			body: parse(`Dear user,
+
+Your account was just signed in to from {{.IP}}.
+
+If this was not you, change your password and sign out of your other sessions.`),
+		},
+		ChannelSMS:      {body: parse("New sign-in to your account from {{.IP}}. Not you? Change your password.")},
+		ChannelWhatsApp: {body: parse("Your account was just signed in to from {{.IP}}. Not you? Change your password.")},
+		ChannelPush: {
+			subject: parse("New sign-in"),
+			body:    parse("Your account was just signed in to from {{.IP}}. Not you? Change your password."),
+		},
+	},
+	KindPasswordChanged: {
+		ChannelEmail: {
+			subject: parse("Your password was changed"),
+			body: parse(`Dear user,
+
+The password of your account was just changed.
+
+If this was not you, reset your password right away.`),
+		},
+		ChannelSMS:      {body: parse("Your password was changed. Not you? Reset your password right away.")},
+		ChannelWhatsApp: {body: parse("The password of your account was changed. Not you? Reset it right away.")},
+		ChannelPush: {
+			subject: parse("Password changed"),
+			body:    parse("The password of your account was changed. Not you? Reset it right away."),
+		},
+	},
+}
+
+func parse(text string) *template.Template {
+	return template.Must(template.New("").Parse(text))
+}
+
+// Supports reports whether kind can be sent over channel
+func Supports(kind, channel string) bool {
+	_, ok := templates[kind][channel]
+	return ok
+}
+
+// Render renders kind for channel
+func Render(kind, channel string, data Data) (*Message, error) {
+	tmpl, ok := templates[kind][channel]
+	if !ok {
+		return nil, ErrNoTemplate
+	}
+
+	message := new(Message)
+	if tmpl.subject != nil {
+		subject, err := execute(tmpl.subject, data)
+		if err != nil {
+			return nil, err
+		}
+		message.Subject = subject
+	}
+
+	body, err := execute(tmpl.body, data)
+	if err != nil {
+		return nil, err
+	}
+	message.Body = body
+
+	return message, nil
+}
+
+func execute(tmpl *template.Template, data Data) (string, error) {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
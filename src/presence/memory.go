@@ -0,0 +1,116 @@
+package presence
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"app/src/config"
+)
+
+// memoryBackend keeps presence in process for single-node deployments
+type memoryBackend struct {
+	ttl       time.Duration
+	retention time.Duration
+
+	mu sync.Mutex
+	// conns holds the heartbeat expiry of each connection per user
+	conns map[string]map[string]time.Time
+	// online holds the users announced online and not yet offline
+	online map[string]bool
+	seen   map[string]time.Time
+}
+
+func newMemoryBackend(cfg config.PresenceConfig) *memoryBackend {
+	return &memoryBackend{
+		ttl:       cfg.TTL,
+		retention: cfg.LastSeenRetention,
+		conns:     make(map[string]map[string]time.Time),
+		online:    make(map[string]bool),
+		seen:      make(map[string]time.Time),
+	}
+}
+
+func (b *memoryBackend) touch(_ context.Context, userID, connID string, now time.Time) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conns[userID] == nil {
+		b.conns[userID] = make(map[string]time.Time)
+	}
+	b.conns[userID][connID] = now.Add(b.ttl)
+	b.seen[userID] = now
+
+	if b.online[userID] {
+		return false, nil
+	}
+	b.online[userID] = true
+	return true, nil
+}
+
+func (b *memoryBackend) leave(_ context.Context, userID, connID string, now time.Time) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.conns[userID], connID)
+	b.seen[userID] = now
+	return b.expireLocked(userID, now), nil
+}
+
+func (b *memoryBackend) expire(_ context.Context, now time.Time) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expired := []string{}
+	for userID := range b.online {
+		if b.expireLocked(userID, now) {
+			expired = append(expired, userID)
+		}
+	}
+	for userID, seenAt := range b.seen {
+		if now.Sub(seenAt) > b.retention {
+			delete(b.seen, userID)
+		}
+	}
+	return expired, nil
+}
+
+func (b *memoryBackend) lookup(_ context.Context, userIDs []string, now time.Time) ([]Presence, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result := make([]Presence, len(userIDs))
+	for i, userID := range userIDs {
+		result[i] = Presence{UserID: userID}
+		for _, expiresAt := range b.conns[userID] {
+			if expiresAt.After(now) {
+				result[i].Online = true
+				break
+			}
+		}
+		if seenAt, ok := b.seen[userID]; ok && now.Sub(seenAt) <= b.retention {
+			result[i].LastSeen = &seenAt
+		}
+	}
+	return result, nil
+}
+
+// expireLocked drops the user's expired connections and takes them offline if none are left,
+// reporting whether they went offline
+func (b *memoryBackend) expireLocked(userID string, now time.Time) bool {
+	for connID, expiresAt := range b.conns[userID] {
+		if !expiresAt.After(now) {
+			delete(b.conns[userID], connID)
+		}
+	}
+	if len(b.conns[userID]) > 0 {
+		return false
+	}
+
+	delete(b.conns, userID)
+	if !b.online[userID] {
+		return false
+	}
+	delete(b.online, userID)
+	return true
+}
@@ -0,0 +1,266 @@
+// Package presence tracks which users are online from the heartbeats of their WebSocket connections
+// and announces when a user comes online or goes offline.
+package presence
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"app/src/clock"
+	"app/src/config"
+	"app/src/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// KeyPrefix is the prefix of every presence key
+	// Format: presence:online, presence:conns:{userID} and presence:seen:{userID}
+	KeyPrefix = "presence:"
+
+	// Channel is the Redis pub/sub channel presence changes are broadcast on
+	Channel = "presence:events"
+)
+
+// Presence statuses
+const (
+	StatusOnline  = "online"
+	StatusOffline = "offline"
+)
+
+// sweepBatch is how many expired users one sweep handles at most
+const sweepBatch = 500
+
+// Event announces that a user came online or went offline
+type Event struct {
+	UserID string    `json:"user_id"`
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+	// Origin is the publishing instance, which has already delivered the event locally
+	Origin string `json:"origin"`
+}
+
+// Presence is whether a user is online, and when they were last seen; LastSeen is nil for users who
+// were never seen or not within the retention
+type Presence struct {
+	UserID   string
+	Online   bool
+	LastSeen *time.Time
+}
+
+// backend stores connections and decides the transitions; it must report each transition once
+// across instances
+type backend interface {
+	// touch records a heartbeat of the connection, reporting whether the user just came online
+	touch(ctx context.Context, userID, connID string, now time.Time) (bool, error)
+	// leave removes the connection, reporting whether the user just went offline
+	leave(ctx context.Context, userID, connID string, now time.Time) (bool, error)
+	// expire removes users whose every connection missed its heartbeats, returning them
+	expire(ctx context.Context, now time.Time) ([]string, error)
+	lookup(ctx context.Context, userIDs []string, now time.Time) ([]Presence, error)
+}
+
+// Tracker records connection heartbeats, in Redis so every instance sees the same presence, and
+// delivers presence changes to the handlers of every instance
+type Tracker struct {
+	redisClient *redis.RedisClient
+	backend     backend
+	instanceID  string
+	clock       clock.Clock
+	nextConn    atomic.Uint64
+
+	mu       sync.Mutex
+	handlers map[int]func(Event)
+	nextID   int
+}
+
+// NewTracker creates a tracker; without Redis, presence is kept in memory and only covers the
+// connections to this instance
+func NewTracker(redisClient *redis.RedisClient, cfg config.PresenceConfig, clk clock.Clock) *Tracker {
+	var store backend = newMemoryBackend(cfg)
+	if redisClient != nil {
+		store = newRedisBackend(redisClient, cfg)
+	}
+
+	return &Tracker{
+		redisClient: redisClient,
+		backend:     store,
+		instanceID:  newInstanceID(),
+		clock:       clock.OrSystem(clk),
+		handlers:    make(map[int]func(Event)),
+	}
+}
+
+// Connect registers a new connection of the user and returns its ID
+func (t *Tracker) Connect(ctx context.Context, userID string) (string, error) {
+	connID := fmt.Sprintf("%s:%d", t.instanceID, t.nextConn.Add(1))
+	if err := t.Heartbeat(ctx, userID, connID); err != nil {
+		return "", err
+	}
+	return connID, nil
+}
+
+// Heartbeat keeps the connection online for another config.Presence.TTL
+func (t *Tracker) Heartbeat(ctx context.Context, userID, connID string) error {
+	now := t.clock.Now()
+	cameOnline, err := t.backend.touch(ctx, userID, connID, now)
+	if err != nil {
+		return err
+	}
+	if cameOnline {
+		t.publish(ctx, Event{UserID: userID, Status: StatusOnline, At: now})
+	}
+	return nil
+}
+
+// Disconnect removes the connection; the user goes offline with their last connection
+func (t *Tracker) Disconnect(ctx context.Context, userID, connID string) error {
+	now := t.clock.Now()
+	wentOffline, err := t.backend.leave(ctx, userID, connID, now)
+	if err != nil {
+		return err
+	}
+	if wentOffline {
+		t.publish(ctx, Event{UserID: userID, Status: StatusOffline, At: now})
+	}
+	return nil
+}
+
+// Lookup returns the presence of each user, in order
+func (t *Tracker) Lookup(ctx context.Context, userIDs []string) ([]Presence, error) {
+	if len(userIDs) == 0 {
+		return []Presence{}, nil
+	}
+	return t.backend.lookup(ctx, userIDs, t.clock.Now())
+}
+
+// Sweep takes offline the users whose connections all stopped sending heartbeats, e.g. because
+// the instance holding them crashed
+func (t *Tracker) Sweep(ctx context.Context) error {
+	now := t.clock.Now()
+	userIDs, err := t.backend.expire(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		t.publish(ctx, Event{UserID: userID, Status: StatusOffline, At: now})
+	}
+	return nil
+}
+
+// Subscribe registers fn to run for every presence change, local or remote.
+// The returned function removes the subscription.
+func (t *Tracker) Subscribe(fn func(Event)) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := t.nextID
+	t.nextID++
+	t.handlers[id] = fn
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.handlers, id)
+	}
+}
+
+// Start sweeps expired connections every interval and, with Redis, listens for presence changes
+// from other instances until ctx is cancelled
+func (t *Tracker) Start(ctx context.Context, interval time.Duration) {
+	if t.redisClient != nil {
+		go t.listen(ctx)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := t.Sweep(ctx); err != nil {
+			logrus.Warnf("Failed to sweep expired presence: %v", err)
+		}
+	}
+}
+
+func (t *Tracker) listen(ctx context.Context) {
+	pubsub := t.redisClient.GetClient().Subscribe(ctx, t.redisClient.Key(Channel))
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				logrus.Warnf("Ignoring malformed presence message: %v", err)
+				continue
+			}
+			if event.Origin == t.instanceID {
+				continue
+			}
+
+			t.deliver(event)
+		}
+	}
+}
+
+// publish delivers the event to this instance's handlers and broadcasts it to the others.
+// Broadcasting is best-effort; presence itself is always read from the backend.
+func (t *Tracker) publish(ctx context.Context, event Event) {
+	event.Origin = t.instanceID
+	t.deliver(event)
+
+	if t.redisClient == nil || !redis.IsAvailable() {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, err = t.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return nil, t.redisClient.GetClient().Publish(ctx, t.redisClient.Key(Channel), payload).Err()
+	})
+	if err != nil {
+		logrus.Warnf("Failed to broadcast presence of user %s: %v", event.UserID, err)
+	}
+}
+
+func (t *Tracker) deliver(event Event) {
+	t.mu.Lock()
+	handlers := make([]func(Event), 0, len(t.handlers))
+	for _, fn := range t.handlers {
+		handlers = append(handlers, fn)
+	}
+	t.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(event)
+	}
+}
+
+func newInstanceID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().Format(time.RFC3339Nano)
+	}
+	return hex.EncodeToString(buf)
+}
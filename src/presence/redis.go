@@ -0,0 +1,157 @@
+package presence
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"app/src/config"
+	"app/src/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Redis keys, below KeyPrefix. The online set scores each user with the latest heartbeat expiry of
+// their connections; a user is in it from their online event until their offline event.
+const (
+	onlineKey      = KeyPrefix + "online"
+	connsKeyPrefix = KeyPrefix + "conns:"
+	seenKeyPrefix  = KeyPrefix + "seen:"
+)
+
+// touchScript records a heartbeat; adding the user to the online set claims their online event
+var touchScript = goredis.NewScript(`
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+redis.call("SET", KEYS[3], ARGV[4], "PX", ARGV[5])
+local added = redis.call("ZADD", KEYS[2], "NX", ARGV[2], ARGV[6])
+if added == 0 then
+	redis.call("ZADD", KEYS[2], "GT", ARGV[2], ARGV[6])
+end
+return added
+`)
+
+// leaveScript removes a connection; removing the user from the online set claims their offline event
+var leaveScript = goredis.NewScript(`
+redis.call("ZREM", KEYS[1], ARGV[1])
+redis.call("SET", KEYS[3], ARGV[2], "PX", ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[2])
+if redis.call("ZCARD", KEYS[1]) > 0 then
+	return 0
+end
+return redis.call("ZREM", KEYS[2], ARGV[4])
+`)
+
+// expireScript takes a user whose online score passed offline unless a connection is still alive,
+// in which case the score is brought back to that connection's expiry
+var expireScript = goredis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])
+local latest = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+if #latest > 0 then
+	redis.call("ZADD", KEYS[2], latest[2], ARGV[2])
+	return 0
+end
+return redis.call("ZREM", KEYS[2], ARGV[2])
+`)
+
+type redisBackend struct {
+	redisClient *redis.RedisClient
+	ttl         time.Duration
+	retention   time.Duration
+}
+
+func newRedisBackend(redisClient *redis.RedisClient, cfg config.PresenceConfig) *redisBackend {
+	return &redisBackend{redisClient: redisClient, ttl: cfg.TTL, retention: cfg.LastSeenRetention}
+}
+
+func (b *redisBackend) touch(ctx context.Context, userID, connID string, now time.Time) (bool, error) {
+	keys := []string{b.key(connsKeyPrefix + userID), b.key(onlineKey), b.key(seenKeyPrefix + userID)}
+	added, err := b.run(ctx, touchScript, keys,
+		connID, now.Add(b.ttl).UnixMilli(), b.ttl.Milliseconds(), now.UnixMilli(), b.retention.Milliseconds(), userID)
+	return added == 1, err
+}
+
+func (b *redisBackend) leave(ctx context.Context, userID, connID string, now time.Time) (bool, error) {
+	keys := []string{b.key(connsKeyPrefix + userID), b.key(onlineKey), b.key(seenKeyPrefix + userID)}
+	removed, err := b.run(ctx, leaveScript, keys, connID, now.UnixMilli(), b.retention.Milliseconds(), userID)
+	return removed == 1, err
+}
+
+func (b *redisBackend) expire(ctx context.Context, now time.Time) ([]string, error) {
+	if !redis.IsAvailable() {
+		return nil, redis.ErrRedisUnavailable
+	}
+
+	candidates, err := b.redisClient.GetClient().ZRangeByScore(ctx, b.key(onlineKey), &goredis.ZRangeBy{
+		Min: "-inf", Max: strconv.FormatInt(now.UnixMilli(), 10), Count: sweepBatch,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	expired := []string{}
+	for _, userID := range candidates {
+		keys := []string{b.key(connsKeyPrefix + userID), b.key(onlineKey)}
+		removed, err := b.run(ctx, expireScript, keys, now.UnixMilli(), userID)
+		if err != nil {
+			return expired, err
+		}
+		if removed == 1 {
+			expired = append(expired, userID)
+		}
+	}
+	return expired, nil
+}
+
+func (b *redisBackend) lookup(ctx context.Context, userIDs []string, now time.Time) ([]Presence, error) {
+	if !redis.IsAvailable() {
+		return nil, redis.ErrRedisUnavailable
+	}
+
+	scores := make([]*goredis.FloatCmd, len(userIDs))
+	seen := make([]*goredis.StringCmd, len(userIDs))
+	_, err := b.redisClient.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, userID := range userIDs {
+			scores[i] = pipe.ZScore(ctx, b.key(onlineKey), userID)
+			seen[i] = pipe.Get(ctx, b.key(seenKeyPrefix+userID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Presence, len(userIDs))
+	for i, userID := range userIDs {
+		result[i] = Presence{UserID: userID}
+		if score, err := scores[i].Result(); err == nil {
+			result[i].Online = int64(score) > now.UnixMilli()
+		}
+		if millis, err := seen[i].Int64(); err == nil {
+			seenAt := time.UnixMilli(millis).UTC()
+			result[i].LastSeen = &seenAt
+		}
+	}
+	return result, nil
+}
+
+func (b *redisBackend) run(ctx context.Context, script *goredis.Script, keys []string, args ...any) (int64, error) {
+	if !redis.IsAvailable() {
+		return 0, redis.ErrRedisUnavailable
+	}
+
+	result, err := b.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return script.Run(ctx, b.redisClient.GetClient(), keys, args...).Int64()
+	})
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return 0, err
+	}
+
+	value, _ := result.(int64)
+	return value, nil
+}
+
+func (b *redisBackend) key(key string) string {
+	return b.redisClient.Key(key)
+}
@@ -0,0 +1,139 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"app/src/httpclient"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// apnsTokenTTL is how long a provider token is reused; Apple rejects tokens older than an hour and
+// throttles tokens refreshed more often than every 20 minutes
+const apnsTokenTTL = 50 * time.Minute
+
+// apnsInvalidTokenReasons are the APNs rejection reasons meaning the token will never work again
+var apnsInvalidTokenReasons = map[string]bool{
+	"BadDeviceToken":         true,
+	"DeviceTokenNotForTopic": true,
+	"Unregistered":           true,
+}
+
+type apns struct {
+	baseURL string
+	key     *ecdsa.PrivateKey
+	keyID   string
+	teamID  string
+	topic   string
+	client  *http.Client
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNs creates an APNs sender authenticated with a provider token signed by the .p8 key (ES256).
+// topic is the app's bundle ID.
+func NewAPNs(baseURL, privateKey, keyID, teamID, topic string) (Sender, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs key: %w", err)
+	}
+
+	return &apns{
+		baseURL: baseURL,
+		key:     key,
+		keyID:   keyID,
+		teamID:  teamID,
+		topic:   topic,
+		client:  httpclient.New("apns"),
+	}, nil
+}
+
+func (s *apns) Platform() string {
+	return PlatformAPNs
+}
+
+// Send posts one alert to the device's APNs endpoint
+func (s *apns) Send(ctx context.Context, token string, msg Message) error {
+	providerToken, err := s.providerToken()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range msg.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", s.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+
+	if resp.StatusCode == http.StatusGone || apnsInvalidTokenReasons[result.Reason] {
+		return fmt.Errorf("%w: %s", ErrInvalidToken, result.Reason)
+	}
+	return fmt.Errorf("apns returned status %d: %s", resp.StatusCode, result.Reason)
+}
+
+// providerToken returns the cached provider token, signing a new one once it is apnsTokenTTL old
+func (s *apns) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.token != "" && now.Sub(s.issuedAt) < apnsTokenTTL {
+		return s.token, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": s.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = s.keyID
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("apns token signing failed: %w", err)
+	}
+	s.token, s.issuedAt = signed, now
+	return signed, nil
+}
@@ -0,0 +1,119 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"app/src/httpclient"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// fcmInvalidTokenCodes are the FCM error codes meaning the token will never work again: the app was
+// uninstalled or the token belongs to another Firebase project
+var fcmInvalidTokenCodes = map[string]bool{
+	"UNREGISTERED":       true,
+	"SENDER_ID_MISMATCH": true,
+}
+
+type fcm struct {
+	baseURL   string
+	projectID string
+	tokens    oauth2.TokenSource
+	client    *http.Client
+}
+
+// NewFCM creates an FCM HTTP v1 sender authenticated with a Firebase service account key (JSON).
+// Access tokens are fetched from the key's token_uri and reused until they expire.
+func NewFCM(baseURL string, credentialsJSON []byte) (Sender, error) {
+	var key struct {
+		ProjectID string `json:"project_id"`
+	}
+	if err := json.Unmarshal(credentialsJSON, &key); err != nil {
+		return nil, fmt.Errorf("invalid FCM credentials: %w", err)
+	}
+	if key.ProjectID == "" {
+		return nil, errors.New("invalid FCM credentials: missing project_id")
+	}
+
+	jwtConfig, err := google.JWTConfigFromJSON(credentialsJSON, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FCM credentials: %w", err)
+	}
+
+	client := httpclient.New("fcm")
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+
+	return &fcm{
+		baseURL:   baseURL,
+		projectID: key.ProjectID,
+		tokens:    oauth2.ReuseTokenSource(nil, jwtConfig.TokenSource(ctx)),
+		client:    client,
+	}, nil
+}
+
+func (s *fcm) Platform() string {
+	return PlatformFCM
+}
+
+// Send posts one message to the FCM v1 API, which takes a single token per request
+func (s *fcm) Send(ctx context.Context, token string, msg Message) error {
+	accessToken, err := s.tokens.Token()
+	if err != nil {
+		return fmt.Errorf("fcm authentication failed: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"token":        token,
+			"notification": map[string]string{"title": msg.Title, "body": msg.Body},
+			"data":         msg.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", s.baseURL, s.projectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	accessToken.SetAuthHeader(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+
+	for _, detail := range result.Error.Details {
+		if fcmInvalidTokenCodes[detail.ErrorCode] {
+			return fmt.Errorf("%w: %s", ErrInvalidToken, detail.ErrorCode)
+		}
+	}
+	return fmt.Errorf("fcm returned status %d: %s %s", resp.StatusCode, result.Error.Status, result.Error.Message)
+}
@@ -0,0 +1,88 @@
+// Package push sends push notifications to mobile and web devices through Firebase Cloud Messaging
+// and the Apple Push Notification service.
+package push
+
+import (
+	"app/src/config"
+	"app/src/utils"
+	"context"
+	"errors"
+	"sync"
+)
+
+// Platforms a device token can belong to
+const (
+	// PlatformFCM covers Android and web devices, and iOS apps that use Firebase
+	PlatformFCM  = "fcm"
+	PlatformAPNs = "apns"
+)
+
+// ErrInvalidToken means the device token is no longer valid, e.g. the app was uninstalled, and
+// should be removed
+var ErrInvalidToken = errors.New("invalid device token")
+
+// Message is a push notification shown to the user
+type Message struct {
+	Title string
+	Body  string
+	// Data is passed to the app with the notification
+	Data map[string]string
+}
+
+// Sender delivers push notifications for one platform
+type Sender interface {
+	Platform() string
+	// Send delivers msg to one device, returning ErrInvalidToken for tokens to remove
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// New creates the senders of the configured platforms, keyed by platform. Platforms that are not
+// configured, or whose credentials cannot be read, are left out.
+func New(cfg config.PushConfig) map[string]Sender {
+	senders := map[string]Sender{}
+
+	if cfg.FCMCredentials != "" {
+		sender, err := NewFCM(cfg.FCMAPIURL, []byte(cfg.FCMCredentials))
+		if err != nil {
+			utils.Log.Errorf("FCM push disabled: %v", err)
+		} else {
+			senders[PlatformFCM] = sender
+		}
+	}
+
+	if cfg.APNsKeyID != "" {
+		sender, err := NewAPNs(cfg.APNsAPIURL, cfg.APNsPrivateKey, cfg.APNsKeyID, cfg.APNsTeamID, cfg.APNsTopic)
+		if err != nil {
+			utils.Log.Errorf("APNs push disabled: %v", err)
+		} else {
+			senders[PlatformAPNs] = sender
+		}
+	}
+
+	return senders
+}
+
+// SendBatch delivers msg to every token, sending up to batchSize at a time, and returns the error
+// of each token in order (nil when delivered)
+func SendBatch(ctx context.Context, sender Sender, tokens []string, msg Message, batchSize int) []error {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	errs := make([]error, len(tokens))
+	for start := 0; start < len(tokens); start += batchSize {
+		end := min(start+batchSize, len(tokens))
+
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = sender.Send(ctx, tokens[i], msg)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	return errs
+}
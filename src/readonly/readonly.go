@@ -0,0 +1,169 @@
+// Package readonly puts the app in read-only mode, e.g. during a migration or to contain an incident:
+// requests that write are refused and background writers pause, while reads go on. READ_ONLY forces
+// the mode from configuration; admins flip it at runtime through a switch kept in Redis, so every
+// instance follows it.
+package readonly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"app/src/metrics"
+	"app/src/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Key is the Redis key holding the switch while it is on
+const Key = "read-only"
+
+// ErrReadOnly is returned by background writers stopping for read-only mode
+var ErrReadOnly = errors.New("read-only mode")
+
+// State is the read-only switch
+type State struct {
+	Enabled bool       `json:"enabled"`
+	Reason  string     `json:"reason,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+	// By is the ID of the admin who switched it on
+	By string `json:"by,omitempty"`
+	// Forced is set when READ_ONLY forces the mode, which the switch cannot lift
+	Forced bool `json:"-"`
+}
+
+var (
+	forced  atomic.Bool
+	current atomic.Pointer[State]
+)
+
+// Force keeps the app read-only regardless of the switch, for READ_ONLY
+func Force(on bool) {
+	forced.Store(on)
+	metrics.ReadOnly(Enabled())
+}
+
+// Enabled reports whether writes must wait for read-only mode to end
+func Enabled() bool {
+	return Current().Enabled
+}
+
+// Current returns the switch of this instance, enabled when READ_ONLY forces it
+func Current() State {
+	var state State
+	if shared := current.Load(); shared != nil {
+		state = *shared
+	}
+	state.Forced = forced.Load()
+	state.Enabled = state.Enabled || state.Forced
+	return state
+}
+
+func apply(state State) {
+	previous := current.Swap(&state)
+	metrics.ReadOnly(Enabled())
+
+	if previous == nil || previous.Enabled == state.Enabled {
+		if previous == nil && state.Enabled {
+			logrus.Warnf("Read-only mode is on: %s", state.Reason)
+		}
+		return
+	}
+	if state.Enabled {
+		logrus.Warnf("Read-only mode switched on: %s", state.Reason)
+		return
+	}
+	logrus.Warn("Read-only mode switched off")
+}
+
+// Switch shares the read-only switch through Redis; without Redis it applies to this instance only
+type Switch struct {
+	redisClient *redis.RedisClient
+}
+
+// NewSwitch creates the switch shared through redisClient, which may be nil
+func NewSwitch(redisClient *redis.RedisClient) *Switch {
+	return &Switch{redisClient: redisClient}
+}
+
+// Start follows the switch flipped on other instances, reading it every interval until ctx is done.
+// While Redis is unavailable the last state read is kept.
+func (s *Switch) Start(ctx context.Context, interval time.Duration) {
+	if s.redisClient == nil {
+		return
+	}
+
+	s.refresh(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+func (s *Switch) refresh(ctx context.Context) {
+	if !redis.IsAvailable() {
+		return
+	}
+
+	result, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		payload, err := s.redisClient.GetClient().Get(ctx, s.redisClient.Key(Key)).Bytes()
+		if errors.Is(err, goredis.Nil) {
+			return []byte(nil), nil
+		}
+		return payload, err
+	})
+	if err != nil {
+		logrus.Debugf("Failed to read the read-only switch: %v", err)
+		return
+	}
+
+	// The key only exists while the switch is on
+	payload, _ := result.([]byte)
+	if payload == nil {
+		apply(State{})
+		return
+	}
+	var state State
+	if err := json.Unmarshal(payload, &state); err != nil {
+		logrus.Warnf("Ignoring malformed read-only switch: %v", err)
+		return
+	}
+	apply(state)
+}
+
+// Set flips the switch for every instance; the others follow within their sync interval
+func (s *Switch) Set(ctx context.Context, state State) error {
+	if s.redisClient != nil {
+		if !redis.IsAvailable() {
+			return redis.ErrRedisUnavailable
+		}
+
+		payload, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		_, err = s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+			if !state.Enabled {
+				return nil, s.redisClient.GetClient().Del(ctx, s.redisClient.Key(Key)).Err()
+			}
+			return nil, s.redisClient.GetClient().Set(ctx, s.redisClient.Key(Key), payload, 0).Err()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	apply(state)
+	return nil
+}
@@ -0,0 +1,174 @@
+package redis
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"app/src/config"
+	"app/src/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/sony/gobreaker/v2"
+)
+
+// maxBreakerTransitions bounds the in-memory transition history
+const maxBreakerTransitions = 50
+
+// BreakerTransition records a single circuit breaker state change
+type BreakerTransition struct {
+	From string
+	To   string
+	At   time.Time
+}
+
+// BreakerStatus is a point-in-time view of the Redis circuit breaker
+type BreakerStatus struct {
+	Name        string
+	State       string
+	Forced      bool
+	Counts      gobreaker.Counts
+	Transitions []BreakerTransition
+}
+
+var (
+	// breakerSettings are kept so a manual reset can rebuild the breaker
+	breakerSettings gobreaker.Settings
+
+	// breakerForcedOpen is set when an operator trips the breaker manually
+	breakerForcedOpen atomic.Bool
+
+	breakerMu          sync.Mutex
+	breakerTransitions []BreakerTransition
+
+	breakerTransitionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Name:      "redis_circuit_breaker_transitions_total",
+		Help:      "Redis circuit breaker state transitions.",
+	}, []string{"from", "to"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		breakerTransitionsTotal,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Name:      "redis_circuit_breaker_state",
+			Help:      "Redis circuit breaker state (0 = closed, 1 = half-open, 2 = open).",
+		}, func() float64 { return float64(currentState()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Name:      "redis_circuit_breaker_consecutive_failures",
+			Help:      "Consecutive failures counted by the Redis circuit breaker.",
+		}, func() float64 { return float64(currentCounts().ConsecutiveFailures) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Name:      "redis_circuit_breaker_requests",
+			Help:      "Requests counted by the Redis circuit breaker in the current interval.",
+		}, func() float64 { return float64(currentCounts().Requests) }),
+	)
+}
+
+// newCircuitBreaker creates the Redis circuit breaker from configuration
+func newCircuitBreaker(cfg config.RedisConfig) *gobreaker.CircuitBreaker[interface{}] {
+	threshold := uint32(cfg.BreakerFailureThreshold)
+
+	breakerSettings = gobreaker.Settings{
+		Name:        "Redis",
+		MaxRequests: uint32(cfg.BreakerMaxRequests),
+		Interval:    time.Duration(cfg.BreakerInterval) * time.Second,
+		Timeout:     time.Duration(cfg.BreakerTimeout) * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures > threshold },
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			logrus.Infof("Circuit breaker '%s' state changed: %s -> %s", name, from, to)
+			recordTransition(from.String(), to.String())
+		},
+	}
+
+	return gobreaker.NewCircuitBreaker[interface{}](breakerSettings)
+}
+
+// recordTransition appends to the bounded history and the transition counter
+func recordTransition(from, to string) {
+	breakerTransitionsTotal.WithLabelValues(from, to).Inc()
+
+	breakerMu.Lock()
+	defer breakerMu.Unlock()
+
+	breakerTransitions = append(breakerTransitions, BreakerTransition{From: from, To: to, At: time.Now().UTC()})
+	if len(breakerTransitions) > maxBreakerTransitions {
+		breakerTransitions = breakerTransitions[len(breakerTransitions)-maxBreakerTransitions:]
+	}
+}
+
+// currentState returns the effective breaker state, honoring a manual trip
+func currentState() gobreaker.State {
+	if breakerForcedOpen.Load() {
+		return gobreaker.StateOpen
+	}
+
+	cb := redisCB.Load()
+	if cb == nil {
+		return gobreaker.StateClosed
+	}
+	return cb.State()
+}
+
+// currentCounts returns the breaker counts, or zero counts without Redis
+func currentCounts() gobreaker.Counts {
+	cb := redisCB.Load()
+	if cb == nil {
+		return gobreaker.Counts{}
+	}
+	return cb.Counts()
+}
+
+// GetBreakerStatus returns the breaker state, counts and recent transitions
+func GetBreakerStatus() (*BreakerStatus, error) {
+	if redisCB.Load() == nil {
+		return nil, ErrRedisUnavailable
+	}
+
+	breakerMu.Lock()
+	transitions := make([]BreakerTransition, len(breakerTransitions))
+	copy(transitions, breakerTransitions)
+	breakerMu.Unlock()
+
+	return &BreakerStatus{
+		Name:        breakerSettings.Name,
+		State:       currentState().String(),
+		Forced:      breakerForcedOpen.Load(),
+		Counts:      currentCounts(),
+		Transitions: transitions,
+	}, nil
+}
+
+// TripBreaker forces the breaker open so all Redis calls fall back until reset
+func TripBreaker() error {
+	if redisCB.Load() == nil {
+		return ErrRedisUnavailable
+	}
+
+	if !breakerForcedOpen.Swap(true) {
+		logrus.Warn("Circuit breaker 'Redis' manually tripped")
+		recordTransition(redisCB.Load().State().String(), "manual-open")
+	}
+	return nil
+}
+
+// ResetBreaker clears a manual trip and replaces the breaker with a fresh closed one
+func ResetBreaker() error {
+	cb := redisCB.Load()
+	if cb == nil {
+		return ErrRedisUnavailable
+	}
+
+	from := currentState().String()
+	redisCB.Store(gobreaker.NewCircuitBreaker[interface{}](breakerSettings))
+	breakerForcedOpen.Store(false)
+
+	logrus.Warn("Circuit breaker 'Redis' manually reset")
+	recordTransition(from, "manual-closed")
+	return nil
+}
@@ -2,11 +2,16 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
 	"sync/atomic"
 	"time"
 
+	"app/src/chaos"
 	"app/src/config"
+	"app/src/deadline"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"github.com/sony/gobreaker/v2"
@@ -23,8 +28,8 @@ var (
 	// 0 = unavailable, 1 = available
 	redisAvailable int32
 
-	// redisCB is the circuit breaker instance
-	redisCB *gobreaker.CircuitBreaker[interface{}]
+	// redisCB is the circuit breaker instance (swapped on manual reset)
+	redisCB atomic.Pointer[gobreaker.CircuitBreaker[interface{}]]
 
 	// healthMonitor is the health monitor instance
 	healthMonitor *HealthMonitor
@@ -32,8 +37,8 @@ var (
 
 // RedisClient wraps the go-redis client with circuit breaker protection
 type RedisClient struct {
-	client         *redis.Client
-	circuitBreaker *gobreaker.CircuitBreaker[interface{}]
+	client    *redis.Client
+	namespace string
 }
 
 // NewRedisClient creates a new Redis client with circuit breaker
@@ -47,6 +52,7 @@ func NewRedisClient(cfg config.RedisConfig) (*RedisClient, error) {
 	// Create Redis options with connection pool
 	opts := &redis.Options{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Username:     cfg.Username,
 		Password:     cfg.Password,
 		DB:           cfg.DB,
 		PoolSize:     cfg.MaxActive,
@@ -63,23 +69,22 @@ func NewRedisClient(cfg config.RedisConfig) (*RedisClient, error) {
 		MaxRetryBackoff: 32 * time.Millisecond,
 	}
 
+	// Enable TLS for managed Redis providers
+	if cfg.TLSEnabled {
+		tlsConfig, err := buildTLSConfig(cfg)
+		if err != nil {
+			setAvailable(false)
+			return nil, fmt.Errorf("redis TLS configuration failed: %w", err)
+		}
+		opts.TLSConfig = tlsConfig
+	}
+
 	// Create client
 	client := redis.NewClient(opts)
 
 	// Create circuit breaker
-	cb := gobreaker.NewCircuitBreaker[interface{}](gobreaker.Settings{
-		Name:        "Redis",
-		MaxRequests: 5,
-		Interval:    time.Minute,
-		Timeout:     30 * time.Second,
-		ReadyToTrip: func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures > 3 },
-		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
-			logrus.Infof("Circuit breaker '%s' state changed: %s -> %s", name, from, to)
-		},
-	})
-
 	redisClient = client
-	redisCB = cb
+	redisCB.Store(newCircuitBreaker(cfg))
 
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.DialTimeout)*time.Second)
@@ -91,18 +96,69 @@ func NewRedisClient(cfg config.RedisConfig) (*RedisClient, error) {
 		return nil, fmt.Errorf("redis connection failed: %w", err)
 	}
 
+	// Commands get a deadline from the request, so a slow Redis cannot hold a worker
+	client.AddHook(deadline.RedisHook{})
+
+	// Chaos faults are injected after the connection test, so startup itself is never disrupted
+	if config.Chaos.Enabled {
+		client.AddHook(chaos.RedisHook{})
+	}
+
 	// Connection successful
 	setAvailable(true)
-	logrus.Infof("Redis connected successfully: %s:%d (DB: %d)", cfg.Host, cfg.Port, cfg.DB)
+	logrus.Infof("Redis connected successfully: %s:%d (DB: %d, TLS: %t, namespace: %q)",
+		cfg.Host, cfg.Port, cfg.DB, cfg.TLSEnabled, cfg.Namespace)
 
 	redisClientInstance := &RedisClient{
-		client:         client,
-		circuitBreaker: cb,
+		client:    client,
+		namespace: cfg.Namespace,
 	}
 
 	return redisClientInstance, nil
 }
 
+// buildTLSConfig creates the TLS configuration from CA and client certificate paths
+func buildTLSConfig(cfg config.RedisConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSInsecureSkipVerify, //nolint:gosec // opt-in for self-signed dev setups
+	}
+
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = cfg.Host
+	}
+
+	if cfg.TLSInsecureSkipVerify {
+		logrus.Warn("Redis TLS certificate verification is disabled - do not use in production")
+	}
+
+	// Custom CA bundle (otherwise the system roots are used)
+	if cfg.TLSCACert != "" {
+		caCert, err := os.ReadFile(cfg.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate: %s", cfg.TLSCACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// Client certificate for mutual TLS
+	if cfg.TLSCert != "" && cfg.TLSKey != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
 // testConnection tests Redis connectivity with PING command
 func testConnection(ctx context.Context, client *redis.Client) error {
 	return client.Ping(ctx).Err()
@@ -115,29 +171,32 @@ func IsAvailable() bool {
 	}
 
 	// Check circuit breaker state
-	if redisCB != nil && redisCB.State() == gobreaker.StateOpen {
+	if currentState() == gobreaker.StateOpen {
 		return false
 	}
 
 	return true
 }
 
-// setAvailable sets the atomic availability flag
+// setAvailable sets the atomic availability flag and notifies subscribers on transitions
 func setAvailable(available bool) {
 	var v int32 = 0
 	if available {
 		v = 1
 	}
-	atomic.StoreInt32(&redisAvailable, v)
+	if atomic.SwapInt32(&redisAvailable, v) != v {
+		notifySubscribers(available)
+	}
 }
 
 // InitHealthMonitor creates and initializes the health monitor for Redis
-func InitHealthMonitor(interval time.Duration, onStateChange func(available bool)) *HealthMonitor {
+// State changes are delivered through Subscribe
+func InitHealthMonitor(interval time.Duration) *HealthMonitor {
 	if redisClient == nil {
 		logrus.Warn("Cannot create health monitor: Redis client not initialized")
 		return nil
 	}
-	healthMonitor = NewHealthMonitor(redisClient, interval, onStateChange)
+	healthMonitor = NewHealthMonitor(redisClient, interval)
 	return healthMonitor
 }
 
@@ -174,7 +233,12 @@ func (r *RedisClient) ExecuteWithCircuitBreaker(ctx context.Context, fn func() (
 		return nil, ErrRedisUnavailable
 	}
 
-	result, err := r.circuitBreaker.Execute(fn)
+	// A manually tripped breaker rejects calls like an open one
+	if breakerForcedOpen.Load() {
+		return nil, gobreaker.ErrOpenState
+	}
+
+	result, err := redisCB.Load().Execute(fn)
 	return result, err
 }
 
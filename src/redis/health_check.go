@@ -2,40 +2,33 @@ package redis
 
 import (
 	"context"
-	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
-// HealthMonitor manages Redis health checks in background goroutine
+// HealthMonitor periodically pings Redis and drives the client's availability flag.
+// It keeps no state of its own, so IsAvailable() and every subscriber see the same transitions.
 type HealthMonitor struct {
-	client        *redis.Client
-	interval      time.Duration
-	ticker        *time.Ticker
-	stopChan      chan struct{}
-	available     *atomic.Bool
-	ctx           context.Context
-	cancel        context.CancelFunc
-	onStateChange func(available bool)
+	client   *redis.Client
+	interval time.Duration
+	ticker   *time.Ticker
+	stopChan chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
 }
 
 // NewHealthMonitor creates a new health monitor
-func NewHealthMonitor(client *redis.Client, interval time.Duration, onStateChange func(available bool)) *HealthMonitor {
-	available := &atomic.Bool{}
-	available.Store(false) // Default to false, will update on first check
-
+func NewHealthMonitor(client *redis.Client, interval time.Duration) *HealthMonitor {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &HealthMonitor{
-		client:        client,
-		interval:      interval,
-		stopChan:      make(chan struct{}),
-		available:     available,
-		ctx:           ctx,
-		cancel:        cancel,
-		onStateChange: onStateChange,
+		client:   client,
+		interval: interval,
+		stopChan: make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
@@ -46,7 +39,7 @@ func (hm *HealthMonitor) Start() {
 
 	// Initial check
 	available := hm.checkHealth()
-	hm.available.Store(available)
+	setAvailable(available)
 
 	// Log initial state
 	if available {
@@ -55,11 +48,6 @@ func (hm *HealthMonitor) Start() {
 		logrus.Warn("Redis is unavailable (initial check)")
 	}
 
-	// Call state change callback if provided
-	if hm.onStateChange != nil {
-		hm.onStateChange(available)
-	}
-
 	for {
 		select {
 		case <-hm.ctx.Done():
@@ -67,21 +55,8 @@ func (hm *HealthMonitor) Start() {
 			close(hm.stopChan)
 			return
 		case <-hm.ticker.C:
-			available := hm.checkHealth()
-			previousAvailable := hm.available.Load()
-			hm.available.Store(available)
-
-			// Only log on state change
-			if available != previousAvailable {
-				if hm.onStateChange != nil {
-					hm.onStateChange(available)
-				}
-				if available {
-					logrus.Info("Redis is now available")
-				} else {
-					logrus.Warn("Redis is now unavailable")
-				}
-			}
+			// Only notifies subscribers on state change
+			setAvailable(hm.checkHealth())
 		}
 	}
 }
@@ -103,7 +78,7 @@ func (hm *HealthMonitor) Stop() {
 	<-hm.stopChan
 }
 
-// IsAvailable returns current Redis availability
+// IsAvailable returns current Redis availability as seen by the client
 func (hm *HealthMonitor) IsAvailable() bool {
-	return hm.available.Load()
+	return IsAvailable()
 }
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Key returns key inside the client's namespace (REDIS_NAMESPACE). Every key and channel the app
+// uses goes through it, so environments sharing a Redis instance never read each other's data.
+func (r *RedisClient) Key(key string) string {
+	if r == nil {
+		return key
+	}
+	return r.namespace + key
+}
+
+// Namespace returns the prefix Key puts in front of keys, empty when none is configured
+func (r *RedisClient) Namespace() string {
+	if r == nil {
+		return ""
+	}
+	return r.namespace
+}
+
+// MigrateNamespace renames the keys under each prefix from the namespace from into the client's
+// namespace, e.g. from "" when REDIS_NAMESPACE is first set. Keys keep their TTL. A key whose new
+// name is already taken is left where it is and counted as skipped.
+func (r *RedisClient) MigrateNamespace(ctx context.Context, from string, prefixes []string) (moved, skipped int, err error) {
+	if r == nil {
+		return 0, 0, ErrRedisUnavailable
+	}
+	if from == r.namespace {
+		return 0, 0, nil
+	}
+
+	for _, prefix := range prefixes {
+		iter := r.client.Scan(ctx, 0, from+prefix+"*", 500).Iterator()
+		for iter.Next(ctx) {
+			key := iter.Val()
+			// Scanning "" + prefix also matches keys of a namespace whose name starts like the prefix
+			if from == "" && r.namespace != "" && strings.HasPrefix(key, r.namespace) {
+				continue
+			}
+
+			renamed, err := r.client.RenameNX(ctx, key, r.namespace+strings.TrimPrefix(key, from)).Result()
+			if err != nil {
+				return moved, skipped, fmt.Errorf("failed to rename %s: %w", key, err)
+			}
+			if renamed {
+				moved++
+			} else {
+				skipped++
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return moved, skipped, fmt.Errorf("scan iterator error: %w", err)
+		}
+	}
+
+	return moved, skipped, nil
+}
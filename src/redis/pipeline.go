@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"errors"
+
+	"app/src/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+var pipelineCommands = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Namespace: metrics.Namespace,
+	Name:      "redis_pipeline_commands",
+	Help:      "Commands sent per Redis pipeline.",
+	Buckets:   prometheus.ExponentialBuckets(1, 4, 7),
+})
+
+func init() {
+	metrics.Registry.MustRegister(pipelineCommands)
+}
+
+// Pipelined queues the commands fn adds and sends them in one round trip.
+// The whole batch counts as a single call of the circuit breaker, so a burst of batched commands
+// weighs like the one request it is and a failed batch like one failure. Missing keys (redis.Nil)
+// are not failures; check each command's result for them.
+func (r *RedisClient) Pipelined(ctx context.Context, fn func(pipe redis.Pipeliner) error) ([]redis.Cmder, error) {
+	result, err := r.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		pipe := r.client.Pipeline()
+		if err := fn(pipe); err != nil {
+			pipe.Discard()
+			return nil, err
+		}
+
+		pipelineCommands.Observe(float64(pipe.Len()))
+
+		cmds, err := pipe.Exec(ctx)
+		if errors.Is(err, redis.Nil) {
+			err = nil
+		}
+		return cmds, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cmds, _ := result.([]redis.Cmder)
+	return cmds, nil
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	subscribersMu    sync.RWMutex
+	subscribers      = map[int]func(available bool){}
+	nextSubscriberID int
+)
+
+// Subscribe registers fn to be called on every Redis availability transition.
+// Callbacks run synchronously on the health monitor goroutine, so they should be quick.
+// The returned function removes the subscription.
+func Subscribe(fn func(available bool)) func() {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	id := nextSubscriberID
+	nextSubscriberID++
+	subscribers[id] = fn
+
+	return func() {
+		subscribersMu.Lock()
+		defer subscribersMu.Unlock()
+		delete(subscribers, id)
+	}
+}
+
+// notifySubscribers fans out an availability transition to all subscribers
+func notifySubscribers(available bool) {
+	if available {
+		logrus.Info("Redis is now available")
+	} else {
+		logrus.Warn("Redis is now unavailable")
+	}
+
+	subscribersMu.RLock()
+	callbacks := make([]func(bool), 0, len(subscribers))
+	for _, fn := range subscribers {
+		callbacks = append(callbacks, fn)
+	}
+	subscribersMu.RUnlock()
+
+	for _, fn := range callbacks {
+		fn(available)
+	}
+}
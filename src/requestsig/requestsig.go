@@ -0,0 +1,132 @@
+// Package requestsig verifies partner requests signed with HMAC-SHA256. A partner sends its ID,
+// a Unix timestamp, a unique nonce and the hex signature of
+//
+//	{timestamp}\n{nonce}\n{METHOD}\n{path and query}\n{body}
+//
+// keyed with the SHA-256 digest of its secret, so the secret itself never has to be stored.
+// Requests outside the allowed clock skew are stale, and each nonce is accepted only once.
+package requestsig
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/src/cache"
+	"app/src/clock"
+)
+
+// Headers of a signed request
+const (
+	HeaderPartnerID = "X-Partner-Id"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// NonceKeyPrefix is the prefix for the nonces of accepted requests
+// Format: requestsig:nonce:{partnerID}:{nonce}
+const NonceKeyPrefix = "requestsig:nonce:"
+
+// Nonces must be long enough to be unique per partner and short enough to keep as keys
+const (
+	minNonceLength = 16
+	maxNonceLength = 128
+)
+
+var (
+	// ErrInvalidSignature is returned for unsigned, malformed or tampered requests
+	ErrInvalidSignature = errors.New("invalid request signature")
+
+	// ErrStale is returned when the timestamp is further from the server clock than the allowed skew
+	ErrStale = errors.New("request timestamp outside the allowed skew")
+
+	// ErrReplayed is returned when a nonce is presented a second time
+	ErrReplayed = errors.New("request nonce already used")
+
+	// ErrNonceStoreUnavailable is returned when replays cannot be checked; verification fails closed
+	ErrNonceStoreUnavailable = errors.New("nonce store unavailable")
+)
+
+// Request is the part of an HTTP request a signature covers
+type Request struct {
+	Timestamp string
+	Nonce     string
+	Method    string
+	// URI is the path with the raw query string, if any
+	URI  string
+	Body []byte
+}
+
+// Key derives the HMAC signing key from a partner secret. It signs requests as well as the secret
+// does, so it must be kept as secret; the server stores it encrypted.
+func Key(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// Sign returns the hex signature of the request
+func Sign(key []byte, r Request) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(r.Timestamp + "\n" + r.Nonce + "\n" + r.Method + "\n" + r.URI + "\n"))
+	mac.Write(r.Body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verifier checks signatures, timestamps and nonces
+type Verifier struct {
+	nonces  cache.Counter
+	maxSkew time.Duration
+	clock   clock.Clock
+}
+
+// NewVerifier creates a verifier accepting timestamps within maxSkew of clk (the wall clock if
+// nil). nonces may be nil, in which case every request is rejected with ErrNonceStoreUnavailable.
+func NewVerifier(nonces cache.Counter, maxSkew time.Duration, clk clock.Clock) *Verifier {
+	return &Verifier{
+		nonces:  nonces,
+		maxSkew: maxSkew,
+		clock:   clock.OrSystem(clk),
+	}
+}
+
+// Verify checks the request of partnerID against its key, then claims its nonce. The signature is
+// checked before the nonce so forged requests cannot use up a partner's nonces.
+func (v *Verifier) Verify(ctx context.Context, partnerID string, key []byte, r Request, signature string) error {
+	if signature == "" || len(r.Nonce) < minNonceLength || len(r.Nonce) > maxNonceLength {
+		return ErrInvalidSignature
+	}
+
+	timestamp, err := strconv.ParseInt(r.Timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if skew := v.clock.Now().Sub(time.Unix(timestamp, 0)); skew > v.maxSkew || skew < -v.maxSkew {
+		return ErrStale
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(key, r))) {
+		return ErrInvalidSignature
+	}
+
+	if v.nonces == nil {
+		return ErrNonceStoreUnavailable
+	}
+
+	// Requests are accepted up to maxSkew either side of their timestamp, so the nonce only needs
+	// to be remembered for twice that
+	uses, err := v.nonces.IncrBy(ctx, NonceKeyPrefix+partnerID+":"+r.Nonce, 1, 2*v.maxSkew)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNonceStoreUnavailable, err)
+	}
+	if uses > 1 {
+		return ErrReplayed
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ACLEntry is a permission a record is shared with
+type ACLEntry struct {
+	ID           uuid.UUID  `json:"id"`
+	ResourceType string     `json:"resource_type"`
+	ResourceID   string     `json:"resource_id"`
+	Principal    string     `json:"principal"`
+	Permission   string     `json:"permission"`
+	GrantedBy    *uuid.UUID `json:"granted_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// NewACLEntry maps an ACL entry to its response DTO
+func NewACLEntry(entry *model.ACLEntry) ACLEntry {
+	return ACLEntry{
+		ID:           entry.ID,
+		ResourceType: entry.ResourceType,
+		ResourceID:   entry.ResourceID,
+		Principal:    entry.Principal,
+		Permission:   entry.Permission,
+		GrantedBy:    entry.GrantedBy,
+		CreatedAt:    entry.CreatedAt,
+	}
+}
+
+type SuccessWithACLEntries struct {
+	Code    int        `json:"code"`
+	Status  string     `json:"status"`
+	Message string     `json:"message"`
+	Entries []ACLEntry `json:"entries"`
+}
+
+type SuccessWithACLEntry struct {
+	Code    int      `json:"code"`
+	Status  string   `json:"status"`
+	Message string   `json:"message"`
+	Entry   ACLEntry `json:"entry"`
+}
@@ -0,0 +1,41 @@
+package response
+
+// ActivePeriod is the number of distinct users active in a UTC day or calendar month
+type ActivePeriod struct {
+	Period string `json:"period"`
+	Users  int64  `json:"users"`
+}
+
+// ActiveUsers is the daily and monthly active users over a date range; counts are estimates
+type ActiveUsers struct {
+	From   string         `json:"from"`
+	To     string         `json:"to"`
+	Days   []ActivePeriod `json:"days"`
+	Months []ActivePeriod `json:"months"` // whole months, including days outside the range
+}
+
+// EndpointVisitor is the number of distinct client IPs that called an endpoint
+type EndpointVisitor struct {
+	Endpoint  string `json:"endpoint"`
+	UniqueIPs int64  `json:"unique_ips"`
+}
+
+// EndpointVisitors ranks the endpoints called on a UTC day by their unique client IPs
+type EndpointVisitors struct {
+	Day       string            `json:"day"`
+	Endpoints []EndpointVisitor `json:"endpoints"`
+}
+
+type SuccessWithActiveUsers struct {
+	Code        int         `json:"code"`
+	Status      string      `json:"status"`
+	Message     string      `json:"message"`
+	ActiveUsers ActiveUsers `json:"active_users"`
+}
+
+type SuccessWithEndpointVisitors struct {
+	Code     int              `json:"code"`
+	Status   string           `json:"status"`
+	Message  string           `json:"message"`
+	Visitors EndpointVisitors `json:"visitors"`
+}
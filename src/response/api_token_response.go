@@ -0,0 +1,48 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIToken is the public representation of a personal access token; the token hash is never exposed
+type APIToken struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewAPIToken maps a token model to its response DTO
+func NewAPIToken(token *model.APIToken) APIToken {
+	return APIToken{
+		ID:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.ScopeList(),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+type SuccessWithAPIToken struct {
+	Code     int      `json:"code"`
+	Status   string   `json:"status"`
+	Message  string   `json:"message"`
+	APIToken APIToken `json:"api_token"`
+	// Token is the plaintext token, returned only when it is created
+	Token string `json:"token"`
+}
+
+type SuccessWithAPITokens struct {
+	Code      int        `json:"code"`
+	Status    string     `json:"status"`
+	Message   string     `json:"message"`
+	APITokens []APIToken `json:"api_tokens"`
+}
@@ -0,0 +1,52 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BulkJob is the state and progress of a bulk user action
+type BulkJob struct {
+	ID     uuid.UUID `json:"id"`
+	Action string    `json:"action"`
+	Value  string    `json:"value,omitempty"`
+	Status string    `json:"status"`
+	Total  int       `json:"total"`
+	// Processed counts the users done so far, whatever the outcome
+	Processed  int        `json:"processed"`
+	Succeeded  int        `json:"succeeded"`
+	Skipped    int        `json:"skipped"`
+	Failed     int        `json:"failed"`
+	CreatedBy  *uuid.UUID `json:"created_by"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewBulkJob maps a bulk job model to its response DTO
+func NewBulkJob(job *model.BulkJob) BulkJob {
+	return BulkJob{
+		ID:         job.ID,
+		Action:     job.Action,
+		Value:      job.Value,
+		Status:     job.Status,
+		Total:      job.Total,
+		Processed:  job.Processed(),
+		Succeeded:  job.Succeeded,
+		Skipped:    job.Skipped,
+		Failed:     job.Failed,
+		CreatedBy:  job.CreatedBy,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+		CreatedAt:  job.CreatedAt,
+	}
+}
+
+type SuccessWithBulkJob struct {
+	Code    int     `json:"code"`
+	Status  string  `json:"status"`
+	Message string  `json:"message"`
+	BulkJob BulkJob `json:"bulk_job"`
+}
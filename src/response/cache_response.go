@@ -0,0 +1,40 @@
+package response
+
+type CacheStats struct {
+	Enabled     bool              `json:"enabled"`
+	Backend     string            `json:"backend"`
+	KeyCounts   map[string]int64  `json:"key_counts"`
+	Hits        uint64            `json:"hits"`
+	Misses      uint64            `json:"misses"`
+	HitRatio    float64           `json:"hit_ratio"`
+	MemoryUsage map[string]string `json:"memory_usage"`
+}
+
+type SuccessWithCacheStats struct {
+	Code    int        `json:"code"`
+	Status  string     `json:"status"`
+	Message string     `json:"message"`
+	Stats   CacheStats `json:"stats"`
+}
+
+type SuccessWithPurged struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Purged  int    `json:"purged"`
+}
+
+type CacheInvalidation struct {
+	Pattern    string   `json:"pattern"`
+	Matched    int      `json:"matched"`
+	Keys       []string `json:"keys"`
+	Truncated  bool     `json:"truncated"`
+	DurationMs float64  `json:"duration_ms"`
+}
+
+type SuccessWithCacheInvalidation struct {
+	Code         int               `json:"code"`
+	Status       string            `json:"status"`
+	Message      string            `json:"message"`
+	Invalidation CacheInvalidation `json:"invalidation"`
+}
@@ -0,0 +1,42 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CacheSkipRule is a response cache skip rule; configured rules have no ID and cannot be deleted
+type CacheSkipRule struct {
+	ID        *uuid.UUID `json:"id,omitempty"`
+	Kind      string     `json:"kind"`
+	Value     string     `json:"value"`
+	Source    string     `json:"source"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// NewCacheSkipRule maps an admin rule to its response DTO
+func NewCacheSkipRule(rule *model.CacheSkipRule) CacheSkipRule {
+	return CacheSkipRule{
+		ID:        &rule.ID,
+		Kind:      rule.Kind,
+		Value:     rule.Value,
+		Source:    "admin",
+		CreatedAt: &rule.CreatedAt,
+	}
+}
+
+type SuccessWithCacheSkipRules struct {
+	Code    int             `json:"code"`
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Rules   []CacheSkipRule `json:"rules"`
+}
+
+type SuccessWithCacheSkipRule struct {
+	Code    int           `json:"code"`
+	Status  string        `json:"status"`
+	Message string        `json:"message"`
+	Rule    CacheSkipRule `json:"rule"`
+}
@@ -0,0 +1,32 @@
+package response
+
+import "time"
+
+type CircuitBreakerCounts struct {
+	Requests             uint32 `json:"requests"`
+	TotalSuccesses       uint32 `json:"total_successes"`
+	TotalFailures        uint32 `json:"total_failures"`
+	ConsecutiveSuccesses uint32 `json:"consecutive_successes"`
+	ConsecutiveFailures  uint32 `json:"consecutive_failures"`
+}
+
+type CircuitBreakerTransition struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+type CircuitBreakerStatus struct {
+	Name        string                     `json:"name"`
+	State       string                     `json:"state"`
+	Forced      bool                       `json:"forced"`
+	Counts      CircuitBreakerCounts       `json:"counts"`
+	Transitions []CircuitBreakerTransition `json:"transitions"`
+}
+
+type SuccessWithCircuitBreaker struct {
+	Code           int                  `json:"code"`
+	Status         string               `json:"status"`
+	Message        string               `json:"message"`
+	CircuitBreaker CircuitBreakerStatus `json:"circuit_breaker"`
+}
@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+type DebugCapture struct {
+	RequestID       string            `json:"request_id"`
+	Reason          string            `json:"reason"`
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	Query           string            `json:"query"`
+	Status          int               `json:"status"`
+	DurationMs      float64           `json:"duration_ms"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	RequestBody     string            `json:"request_body"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    string            `json:"response_body"`
+	Error           string            `json:"error,omitempty"`
+	CapturedAt      time.Time         `json:"captured_at"`
+}
+
+type SuccessWithDebugCapture struct {
+	Code    int          `json:"code"`
+	Status  string       `json:"status"`
+	Message string       `json:"message"`
+	Capture DebugCapture `json:"capture"`
+}
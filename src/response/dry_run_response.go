@@ -0,0 +1,19 @@
+package response
+
+// DryRun describes what a destructive action would have done
+type DryRun struct {
+	Action string `json:"action"`
+	// Affected counts the records or keys the action would change or delete, by kind
+	Affected map[string]int64 `json:"affected"`
+	// Sample holds the IDs or keys of some of them
+	Sample []string `json:"sample"`
+	// Changes maps fields to their current and new values, for updates
+	Changes map[string]any `json:"changes,omitempty"`
+}
+
+type SuccessWithDryRun struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	DryRun  DryRun `json:"dry_run"`
+}
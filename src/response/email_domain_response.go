@@ -0,0 +1,49 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailDomainRule is a sign-up domain rule; configured rules have no ID and cannot be deleted
+type EmailDomainRule struct {
+	ID        *uuid.UUID `json:"id,omitempty"`
+	Pattern   string     `json:"pattern"`
+	Action    string     `json:"action"`
+	Source    string     `json:"source"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// NewEmailDomainRule maps an admin rule to its response DTO
+func NewEmailDomainRule(rule *model.EmailDomainRule) EmailDomainRule {
+	return EmailDomainRule{
+		ID:        &rule.ID,
+		Pattern:   rule.Pattern,
+		Action:    rule.Action,
+		Source:    "admin",
+		CreatedAt: &rule.CreatedAt,
+	}
+}
+
+// EmailDomainRules is every rule applied to sign-ups
+type EmailDomainRules struct {
+	Rules             []EmailDomainRule `json:"rules"`
+	BlockDisposable   bool              `json:"block_disposable"`
+	DisposableDomains int               `json:"disposable_domains"`
+}
+
+type SuccessWithEmailDomainRules struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	EmailDomainRules
+}
+
+type SuccessWithEmailDomainRule struct {
+	Code    int             `json:"code"`
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Rule    EmailDomainRule `json:"rule"`
+}
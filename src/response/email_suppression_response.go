@@ -0,0 +1,36 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailSuppression struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Reason    string    `json:"reason"`
+	Source    string    `json:"source"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewEmailSuppression maps a suppressed recipient to its response DTO
+func NewEmailSuppression(suppression *model.EmailSuppression) EmailSuppression {
+	return EmailSuppression{
+		ID:        suppression.ID,
+		Email:     suppression.Email,
+		Reason:    suppression.Reason,
+		Source:    suppression.Source,
+		Detail:    suppression.Detail,
+		CreatedAt: suppression.CreatedAt,
+	}
+}
+
+type SuccessWithEmailSuppression struct {
+	Code        int              `json:"code"`
+	Status      string           `json:"status"`
+	Message     string           `json:"message"`
+	Suppression EmailSuppression `json:"suppression"`
+}
@@ -5,20 +5,26 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Error answers with an error payload carrying the request ID from the X-Request-ID response header,
+// which users can quote to support
 func Error(c *fiber.Ctx, statusCode int, message string, details interface{}) error {
+	requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+
 	var errRes error
 	if details != nil {
 		errRes = c.Status(statusCode).JSON(ErrorDetails{
-			Code:    statusCode,
-			Status:  "error",
-			Message: message,
-			Errors:  details,
+			Code:      statusCode,
+			Status:    "error",
+			Message:   message,
+			Errors:    details,
+			RequestID: requestID,
 		})
 	} else {
 		errRes = c.Status(statusCode).JSON(Common{
-			Code:    statusCode,
-			Status:  "error",
-			Message: message,
+			Code:      statusCode,
+			Status:    "error",
+			Message:   message,
+			RequestID: requestID,
 		})
 	}
 
@@ -0,0 +1,37 @@
+package example
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ACLEntry struct {
+	ID           uuid.UUID  `json:"id" example:"01928f6e-8b21-7d04-a1c3-5e7f9b2d4a61"`
+	ResourceType string     `json:"resource_type" example:"document"`
+	ResourceID   string     `json:"resource_id" example:"42"`
+	Principal    string     `json:"principal" example:"user:01928f6e-7a3c-7cc2-9b1e-3f5a2d4c8e10"`
+	Permission   string     `json:"permission" example:"write"`
+	GrantedBy    *uuid.UUID `json:"granted_by" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	CreatedAt    time.Time  `json:"created_at" example:"2026-10-16T12:00:00Z"`
+}
+
+type GetACLEntriesResponse struct {
+	Code    int        `json:"code" example:"200"`
+	Status  string     `json:"status" example:"success"`
+	Message string     `json:"message" example:"Get ACL entries successfully"`
+	Entries []ACLEntry `json:"entries"`
+}
+
+type GrantACLResponse struct {
+	Code    int      `json:"code" example:"201"`
+	Status  string   `json:"status" example:"success"`
+	Message string   `json:"message" example:"Grant permission successfully"`
+	Entry   ACLEntry `json:"entry"`
+}
+
+type RevokeACLResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Revoke permission successfully"`
+}
@@ -0,0 +1,22 @@
+package example
+
+type ActivityEvent struct {
+	ID         string         `json:"id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Type       string         `json:"type" example:"login"`
+	Action     string         `json:"action" example:"login.succeeded"`
+	IP         string         `json:"ip,omitempty" example:"203.0.113.7"`
+	UserAgent  string         `json:"user_agent,omitempty" example:"Mozilla/5.0"`
+	Metadata   map[string]any `json:"metadata"`
+	OccurredAt string         `json:"occurred_at" example:"2026-10-16T09:30:00Z"`
+}
+
+type GetUserActivityResponse struct {
+	Code         int             `json:"code" example:"200"`
+	Status       string          `json:"status" example:"success"`
+	Message      string          `json:"message" example:"Get user activity successfully"`
+	Results      []ActivityEvent `json:"results"`
+	Page         int             `json:"page" example:"1"`
+	Limit        int             `json:"limit" example:"20"`
+	TotalPages   int64           `json:"total_pages" example:"1"`
+	TotalResults int64           `json:"total_results" example:"3"`
+}
@@ -0,0 +1,37 @@
+package example
+
+type ActivePeriod struct {
+	Period string `json:"period" example:"2026-10-16"`
+	Users  int64  `json:"users" example:"1480"`
+}
+
+type ActiveUsers struct {
+	From   string         `json:"from" example:"2026-10-01"`
+	To     string         `json:"to" example:"2026-10-16"`
+	Days   []ActivePeriod `json:"days"`
+	Months []ActivePeriod `json:"months"`
+}
+
+type EndpointVisitor struct {
+	Endpoint  string `json:"endpoint" example:"GET /v1/users/:userId"`
+	UniqueIPs int64  `json:"unique_ips" example:"912"`
+}
+
+type EndpointVisitors struct {
+	Day       string            `json:"day" example:"2026-10-16"`
+	Endpoints []EndpointVisitor `json:"endpoints"`
+}
+
+type GetActiveUsersResponse struct {
+	Code        int         `json:"code" example:"200"`
+	Status      string      `json:"status" example:"success"`
+	Message     string      `json:"message" example:"Get active users successfully"`
+	ActiveUsers ActiveUsers `json:"active_users"`
+}
+
+type GetEndpointVisitorsResponse struct {
+	Code     int              `json:"code" example:"200"`
+	Status   string           `json:"status" example:"success"`
+	Message  string           `json:"message" example:"Get endpoint visitors successfully"`
+	Visitors EndpointVisitors `json:"visitors"`
+}
@@ -0,0 +1,34 @@
+package example
+
+import "time"
+
+type APIToken struct {
+	ID         string    `json:"id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Name       string    `json:"name" example:"reporting"`
+	Prefix     string    `json:"prefix" example:"pat_Xk2f9a"`
+	Scopes     []string  `json:"scopes" example:"getUsers"`
+	ExpiresAt  time.Time `json:"expires_at" example:"2025-02-01T00:00:00Z"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2025-01-15T08:30:00Z"`
+	CreatedAt  time.Time `json:"created_at" example:"2025-01-01T00:00:00Z"`
+}
+
+type CreateAPITokenResponse struct {
+	Code     int      `json:"code" example:"201"`
+	Status   string   `json:"status" example:"success"`
+	Message  string   `json:"message" example:"Create API token successfully"`
+	APIToken APIToken `json:"api_token"`
+	Token    string   `json:"token" example:"pat_Xk2f9aQ8mRZ1x7tWcVbN3sLpE0yHuJdK4gOiTqAeF6M"`
+}
+
+type GetAPITokensResponse struct {
+	Code      int        `json:"code" example:"200"`
+	Status    string     `json:"status" example:"success"`
+	Message   string     `json:"message" example:"Get API tokens successfully"`
+	APITokens []APIToken `json:"api_tokens"`
+}
+
+type RevokeAPITokenResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Revoke API token successfully"`
+}
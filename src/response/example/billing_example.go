@@ -0,0 +1,14 @@
+package example
+
+type BillingWebhookResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Webhook processed successfully"`
+}
+
+type InvalidWebhookSignature struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Invalid webhook signature"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
@@ -0,0 +1,40 @@
+package example
+
+import "time"
+
+type BulkJob struct {
+	ID         string    `json:"id" example:"0192f1a4-7c2e-7b61-9d3a-5e8f2c4b6a21"`
+	Action     string    `json:"action" example:"add-tag"`
+	Value      string    `json:"value" example:"beta"`
+	Status     string    `json:"status" example:"running"`
+	Total      int       `json:"total" example:"250"`
+	Processed  int       `json:"processed" example:"120"`
+	Succeeded  int       `json:"succeeded" example:"112"`
+	Skipped    int       `json:"skipped" example:"7"`
+	Failed     int       `json:"failed" example:"1"`
+	CreatedBy  string    `json:"created_by" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	StartedAt  time.Time `json:"started_at" example:"2025-01-15T08:30:05Z"`
+	FinishedAt time.Time `json:"finished_at" example:"2025-01-15T08:31:40Z"`
+	CreatedAt  time.Time `json:"created_at" example:"2025-01-15T08:30:00Z"`
+}
+
+type SubmitBulkActionResponse struct {
+	Code    int     `json:"code" example:"202"`
+	Status  string  `json:"status" example:"success"`
+	Message string  `json:"message" example:"Bulk action queued"`
+	BulkJob BulkJob `json:"bulk_job"`
+}
+
+type GetBulkJobResponse struct {
+	Code    int     `json:"code" example:"200"`
+	Status  string  `json:"status" example:"success"`
+	Message string  `json:"message" example:"Get bulk job successfully"`
+	BulkJob BulkJob `json:"bulk_job"`
+}
+
+type InvalidBulkAction struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Give either user_ids or a filter"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
@@ -0,0 +1,46 @@
+package example
+
+type CacheStats struct {
+	Enabled     bool              `json:"enabled" example:"true"`
+	Backend     string            `json:"backend" example:"redis"`
+	KeyCounts   map[string]int64  `json:"key_counts"`
+	Hits        uint64            `json:"hits" example:"120"`
+	Misses      uint64            `json:"misses" example:"30"`
+	HitRatio    float64           `json:"hit_ratio" example:"0.8"`
+	MemoryUsage map[string]string `json:"memory_usage"`
+}
+
+type CacheStatsResponse struct {
+	Code    int        `json:"code" example:"200"`
+	Status  string     `json:"status" example:"success"`
+	Message string     `json:"message" example:"Get cache stats successfully"`
+	Stats   CacheStats `json:"stats"`
+}
+
+type PurgeCacheResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Purged 12 cache keys"`
+	Purged  int    `json:"purged" example:"12"`
+}
+
+type CacheInvalidation struct {
+	Pattern    string   `json:"pattern" example:"api:response:GET:/v1/users*"`
+	Matched    int      `json:"matched" example:"2"`
+	Keys       []string `json:"keys" example:"api:response:GET:/v1/users?page=1"`
+	Truncated  bool     `json:"truncated" example:"false"`
+	DurationMs float64  `json:"duration_ms" example:"1.42"`
+}
+
+type DryRunPurgeCacheResponse struct {
+	Code         int               `json:"code" example:"200"`
+	Status       string            `json:"status" example:"success"`
+	Message      string            `json:"message" example:"Dry run matched 2 cache keys"`
+	Invalidation CacheInvalidation `json:"invalidation"`
+}
+
+type CacheStateResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Update cache state successfully"`
+}
@@ -0,0 +1,49 @@
+package example
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CacheSkipRule struct {
+	ID        uuid.UUID `json:"id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Kind      string    `json:"kind" example:"path"`
+	Value     string    `json:"value" example:"/v1/reports"`
+	Source    string    `json:"source" example:"admin"`
+	CreatedAt time.Time `json:"created_at" example:"2026-10-17T12:00:00Z"`
+}
+
+type GetCacheSkipRulesResponse struct {
+	Code    int             `json:"code" example:"200"`
+	Status  string          `json:"status" example:"success"`
+	Message string          `json:"message" example:"Get cache skip rules successfully"`
+	Rules   []CacheSkipRule `json:"rules"`
+}
+
+type CreateCacheSkipRuleResponse struct {
+	Code    int           `json:"code" example:"201"`
+	Status  string        `json:"status" example:"success"`
+	Message string        `json:"message" example:"Create cache skip rule successfully"`
+	Rule    CacheSkipRule `json:"rule"`
+}
+
+type InvalidCacheSkipRule struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"skip path \"reports\" must start with /"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type DuplicateCacheSkipRule struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Rule already exists"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type DeleteCacheSkipRuleResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Delete cache skip rule successfully"`
+}
@@ -0,0 +1,32 @@
+package example
+
+import "time"
+
+type CircuitBreakerCounts struct {
+	Requests             uint32 `json:"requests" example:"42"`
+	TotalSuccesses       uint32 `json:"total_successes" example:"40"`
+	TotalFailures        uint32 `json:"total_failures" example:"2"`
+	ConsecutiveSuccesses uint32 `json:"consecutive_successes" example:"12"`
+	ConsecutiveFailures  uint32 `json:"consecutive_failures" example:"0"`
+}
+
+type CircuitBreakerTransition struct {
+	From string    `json:"from" example:"closed"`
+	To   string    `json:"to" example:"open"`
+	At   time.Time `json:"at" example:"2025-01-01T00:00:00Z"`
+}
+
+type CircuitBreakerStatus struct {
+	Name        string                     `json:"name" example:"Redis"`
+	State       string                     `json:"state" example:"closed"`
+	Forced      bool                       `json:"forced" example:"false"`
+	Counts      CircuitBreakerCounts       `json:"counts"`
+	Transitions []CircuitBreakerTransition `json:"transitions"`
+}
+
+type CircuitBreakerResponse struct {
+	Code           int                  `json:"code" example:"200"`
+	Status         string               `json:"status" example:"success"`
+	Message        string               `json:"message" example:"Get circuit breaker status successfully"`
+	CircuitBreaker CircuitBreakerStatus `json:"circuit_breaker"`
+}
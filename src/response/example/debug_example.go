@@ -0,0 +1,59 @@
+package example
+
+type DebugCapture struct {
+	RequestID       string            `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+	Reason          string            `json:"reason" example:"header"`
+	Method          string            `json:"method" example:"POST"`
+	Path            string            `json:"path" example:"/v1/auth/login"`
+	Query           string            `json:"query" example:""`
+	Status          int               `json:"status" example:"401"`
+	DurationMs      float64           `json:"duration_ms" example:"84.2"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	RequestBody     string            `json:"request_body" example:"{\"email\":\"f***@example.com\",\"password\":\"[REDACTED]\"}"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    string            `json:"response_body" example:""`
+	Error           string            `json:"error,omitempty" example:"Invalid email or password"`
+	CapturedAt      string            `json:"captured_at" example:"2026-10-16T09:30:00Z"`
+}
+
+type DebugCaptureResponse struct {
+	Code    int          `json:"code" example:"200"`
+	Status  string       `json:"status" example:"success"`
+	Message string       `json:"message" example:"Get debug capture successfully"`
+	Capture DebugCapture `json:"capture"`
+}
+
+type RequestTraceAudit struct {
+	ID        string            `json:"id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	UserID    string            `json:"user_id" example:"0f5d4a1b-7c2e-4b3f-9a8d-6e1c2b3a4d5f"`
+	ActorType string            `json:"actor_type" example:"human"`
+	Action    string            `json:"action" example:"login.failed"`
+	IP        string            `json:"ip,omitempty" example:"203.0.113.7"`
+	UserAgent string            `json:"user_agent,omitempty" example:"Mozilla/5.0"`
+	Metadata  map[string]string `json:"metadata"`
+	CreatedAt string            `json:"created_at" example:"2026-10-16T09:30:00Z"`
+}
+
+type RequestTraceEmail struct {
+	ID        string `json:"id" example:"9b2f6c1e-3d4a-4e5b-8c7d-1a2b3c4d5e6f"`
+	Recipient string `json:"recipient" example:"f***@example.com"`
+	Subject   string `json:"subject" example:"Email Verification"`
+	Status    string `json:"status" example:"failed"`
+	Attempts  int    `json:"attempts" example:"5"`
+	LastError string `json:"last_error,omitempty" example:"dial tcp: connection refused"`
+	CreatedAt string `json:"created_at" example:"2026-10-16T09:30:00Z"`
+}
+
+type RequestTrace struct {
+	RequestID string              `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+	AuditLogs []RequestTraceAudit `json:"audit_logs"`
+	Emails    []RequestTraceEmail `json:"emails"`
+	Capture   *DebugCapture       `json:"capture,omitempty"`
+}
+
+type RequestTraceResponse struct {
+	Code    int          `json:"code" example:"200"`
+	Status  string       `json:"status" example:"success"`
+	Message string       `json:"message" example:"Get request trace successfully"`
+	Trace   RequestTrace `json:"trace"`
+}
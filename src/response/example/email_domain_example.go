@@ -0,0 +1,37 @@
+package example
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailDomainRule struct {
+	ID        uuid.UUID `json:"id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Pattern   string    `json:"pattern" example:"*.example.com"`
+	Action    string    `json:"action" example:"block"`
+	Source    string    `json:"source" example:"admin"`
+	CreatedAt time.Time `json:"created_at" example:"2026-10-16T12:00:00Z"`
+}
+
+type GetEmailDomainRulesResponse struct {
+	Code              int               `json:"code" example:"200"`
+	Status            string            `json:"status" example:"success"`
+	Message           string            `json:"message" example:"Get email domain rules successfully"`
+	Rules             []EmailDomainRule `json:"rules"`
+	BlockDisposable   bool              `json:"block_disposable" example:"true"`
+	DisposableDomains int               `json:"disposable_domains" example:"3412"`
+}
+
+type CreateEmailDomainRuleResponse struct {
+	Code    int             `json:"code" example:"201"`
+	Status  string          `json:"status" example:"success"`
+	Message string          `json:"message" example:"Create email domain rule successfully"`
+	Rule    EmailDomainRule `json:"rule"`
+}
+
+type DeleteEmailDomainRuleResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Delete email domain rule successfully"`
+}
@@ -0,0 +1,53 @@
+package example
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailSuppression struct {
+	ID        uuid.UUID `json:"id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Email     string    `json:"email" example:"bounced@example.com"`
+	Reason    string    `json:"reason" example:"hard_bounce"`
+	Source    string    `json:"source" example:"ses"`
+	Detail    string    `json:"detail,omitempty" example:"smtp; 550 5.1.1 user unknown"`
+	CreatedAt time.Time `json:"created_at" example:"2026-10-17T09:30:00Z"`
+}
+
+type GetEmailSuppressionsResponse struct {
+	Code         int                `json:"code" example:"200"`
+	Status       string             `json:"status" example:"success"`
+	Message      string             `json:"message" example:"Get email suppressions successfully"`
+	Results      []EmailSuppression `json:"results"`
+	Page         int                `json:"page" example:"1"`
+	Limit        int                `json:"limit" example:"20"`
+	TotalPages   int64              `json:"total_pages" example:"1"`
+	TotalResults int64              `json:"total_results" example:"1"`
+}
+
+type CreateEmailSuppressionResponse struct {
+	Code        int              `json:"code" example:"201"`
+	Status      string           `json:"status" example:"success"`
+	Message     string           `json:"message" example:"Create email suppression successfully"`
+	Suppression EmailSuppression `json:"suppression"`
+}
+
+type DeleteEmailSuppressionResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Delete email suppression successfully"`
+}
+
+type EmailWebhookResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Webhook processed successfully"`
+}
+
+type InvalidEmailWebhook struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Invalid webhook payload"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
@@ -1,43 +1,240 @@
 package example
 
 type Unauthorized struct {
-	Code    int    `json:"code" example:"401"`
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"Please authenticate"`
+	Code      int    `json:"code" example:"401"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Please authenticate"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
 }
 
 type FailedLogin struct {
-	Code    int    `json:"code" example:"401"`
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"Invalid email or password"`
+	Code      int    `json:"code" example:"401"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Invalid email or password"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
 }
 
 type FailedResetPassword struct {
-	Code    int    `json:"code" example:"401"`
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"Password reset failed"`
+	Code      int    `json:"code" example:"401"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Password reset failed"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
 }
 
 type FailedVerifyEmail struct {
-	Code    int    `json:"code" example:"401"`
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"Verify email failed"`
+	Code      int    `json:"code" example:"401"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Verify email failed"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type UsedVerifyEmail struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Verification link has already been used"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type EmailDomainNotAllowed struct {
+	Code      int    `json:"code" example:"422"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Email domain is not allowed"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type AccountSuspended struct {
+	Code      int    `json:"code" example:"423"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Account suspended"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type AlreadySuspended struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"User is already suspended"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type SuspiciousLogin struct {
+	Code      int    `json:"code" example:"403"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Unusual sign-in detected. Check your email or phone to confirm this login"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type FailedConfirmLogin struct {
+	Code      int    `json:"code" example:"401"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Invalid Token"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
 }
 
 type Forbidden struct {
-	Code    int    `json:"code" example:"403"`
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"You don't have permission to access this resource"`
+	Code      int    `json:"code" example:"403"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"You don't have permission to access this resource"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
 }
 
 type NotFound struct {
-	Code    int    `json:"code" example:"404"`
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"Not found"`
+	Code      int    `json:"code" example:"404"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Not found"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
 }
 
 type DuplicateEmail struct {
-	Code    int    `json:"code" example:"409"`
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"Email already taken"`
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Email already taken"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type UnpatchablePath struct {
+	Code      int    `json:"code" example:"422"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Path /verified_email cannot be patched"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type TooManyRequests struct {
+	Code      int    `json:"code" example:"429"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Too many requests for this account. Please try again later."`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type AlreadyGranted struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Permission already granted"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type LastOwner struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Cannot remove the last owner"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type DuplicatePartner struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Partner already exists"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type InvalidSignature struct {
+	Code      int    `json:"code" example:"401"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Invalid request signature"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type DuplicateServiceAccount struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Service account name or identity already exists"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type InvalidAuthorizationRequest struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Redirect URI is not registered for this client"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type InsufficientScope struct {
+	Code      int    `json:"code" example:"403"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Token lacks the scope this resource requires"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type DuplicateSuppression struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Email is already suppressed"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type EmailThrottled struct {
+	Code      int    `json:"code" example:"429"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Too many emails sent. Please try again later."`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type ChannelUnavailable struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Verify a phone number before choosing text channels"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type PlatformUnavailable struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Platform 'apns' is not available"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type InvalidPhoneCode struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Invalid or expired verification code"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type TooManyPresenceLookups struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"At most 100 users can be looked up at once"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type UpgradeRequired struct {
+	Code      int    `json:"code" example:"426"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"WebSocket upgrade required"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type InvalidRequestID struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Invalid request ID"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type UnderLegalHold struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"User is under legal hold"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type DuplicateLegalHold struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"User is already under legal hold"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type ReadOnlyMode struct {
+	Code      int             `json:"code" example:"503"`
+	Status    string          `json:"status" example:"error"`
+	Message   string          `json:"message" example:"Service is in read-only mode, try again later"`
+	Errors    ReadOnlyRefusal `json:"errors"`
+	RequestID string          `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type ReadOnlyForced struct {
+	Code      int    `json:"code" example:"409"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Read-only mode is forced by READ_ONLY"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
 }
@@ -97,3 +97,17 @@ type DeleteUserResponse struct {
 	Status  string `json:"status" example:"success"`
 	Message string `json:"message" example:"Delete user successfully"`
 }
+
+type SuspendUserResponse struct {
+	Code    int           `json:"code" example:"200"`
+	Status  string        `json:"status" example:"success"`
+	Message string        `json:"message" example:"Suspend user successfully"`
+	User    SuspendedUser `json:"user"`
+}
+
+type ReactivateUserResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Reactivate user successfully"`
+	User    User   `json:"user"`
+}
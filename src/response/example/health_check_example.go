@@ -14,6 +14,26 @@ type HealthCheckResponse struct {
 	Result    []HealthCheck `json:"result"`
 }
 
+type DBPoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections" example:"100"`
+	OpenConnections    int    `json:"open_connections" example:"12"`
+	InUse              int    `json:"in_use" example:"3"`
+	Idle               int    `json:"idle" example:"9"`
+	WaitCount          int64  `json:"wait_count" example:"0"`
+	WaitDuration       string `json:"wait_duration" example:"0s"`
+	MaxIdleClosed      int64  `json:"max_idle_closed" example:"4"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed" example:"1"`
+}
+
+type ReadinessResponse struct {
+	Code     int         `json:"code" example:"200"`
+	Status   string      `json:"status" example:"success"`
+	Message  string      `json:"message" example:"Service is ready"`
+	IsReady  bool        `json:"is_ready" example:"true"`
+	IsLeader bool        `json:"is_leader" example:"true"`
+	DBPool   DBPoolStats `json:"db_pool"`
+}
+
 type HealthCheckError struct {
 	Name    string  `json:"name" example:"Postgre"`
 	Status  string  `json:"status" example:"Down"`
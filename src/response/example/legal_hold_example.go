@@ -0,0 +1,38 @@
+package example
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LegalHold struct {
+	UserID    uuid.UUID `json:"user_id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Reason    string    `json:"reason" example:"Preserved for litigation case 2026-114"`
+	PlacedBy  uuid.UUID `json:"placed_by" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+	CreatedAt time.Time `json:"created_at" example:"2026-10-17T09:30:00Z"`
+}
+
+type GetLegalHoldsResponse struct {
+	Code         int         `json:"code" example:"200"`
+	Status       string      `json:"status" example:"success"`
+	Message      string      `json:"message" example:"Get legal holds successfully"`
+	Results      []LegalHold `json:"results"`
+	Page         int         `json:"page" example:"1"`
+	Limit        int         `json:"limit" example:"20"`
+	TotalPages   int64       `json:"total_pages" example:"1"`
+	TotalResults int64       `json:"total_results" example:"1"`
+}
+
+type PlaceLegalHoldResponse struct {
+	Code      int       `json:"code" example:"201"`
+	Status    string    `json:"status" example:"success"`
+	Message   string    `json:"message" example:"Place legal hold successfully"`
+	LegalHold LegalHold `json:"legal_hold"`
+}
+
+type LiftLegalHoldResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Lift legal hold successfully"`
+}
@@ -0,0 +1,95 @@
+package example
+
+import "time"
+
+type NotificationPreference struct {
+	Kind     string   `json:"kind" example:"confirm_login"`
+	Channels []string `json:"channels" example:"email,sms"`
+}
+
+type NotificationSettings struct {
+	Phone           *string                  `json:"phone" example:"+14155552671"`
+	PhoneVerifiedAt *time.Time               `json:"phone_verified_at" example:"2026-10-17T09:30:00Z"`
+	Channels        []string                 `json:"channels" example:"email,sms,whatsapp,push"`
+	Preferences     []NotificationPreference `json:"preferences"`
+}
+
+type GetNotificationSettingsResponse struct {
+	Code     int                  `json:"code" example:"200"`
+	Status   string               `json:"status" example:"success"`
+	Message  string               `json:"message" example:"Get notification settings successfully"`
+	Settings NotificationSettings `json:"settings"`
+}
+
+type UpdateNotificationPreferenceResponse struct {
+	Code       int                    `json:"code" example:"200"`
+	Status     string                 `json:"status" example:"success"`
+	Message    string                 `json:"message" example:"Update notification preference successfully"`
+	Preference NotificationPreference `json:"preference"`
+}
+
+type SetPhoneResponse struct {
+	Code    int    `json:"code" example:"202"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Verification code sent"`
+}
+
+type VerifyPhoneResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Verify phone successfully"`
+}
+
+type RemovePhoneResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Remove phone successfully"`
+}
+
+type StatusCallbackResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Callback processed successfully"`
+}
+
+type InvalidStatusCallback struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Invalid callback payload"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type InvalidCallbackSignature struct {
+	Code      int    `json:"code" example:"401"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"Invalid callback signature"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
+
+type PushDevice struct {
+	ID        string    `json:"id" example:"4f3c2b1a-0e9d-4c8b-a7f6-5e4d3c2b1a09"`
+	Platform  string    `json:"platform" example:"fcm"`
+	Name      string    `json:"name" example:"Pixel 8"`
+	CreatedAt time.Time `json:"created_at" example:"2026-10-17T09:30:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2026-10-17T09:30:00Z"`
+}
+
+type GetPushDevicesResponse struct {
+	Code    int          `json:"code" example:"200"`
+	Status  string       `json:"status" example:"success"`
+	Message string       `json:"message" example:"Get push devices successfully"`
+	Devices []PushDevice `json:"devices"`
+}
+
+type RegisterPushDeviceResponse struct {
+	Code    int        `json:"code" example:"200"`
+	Status  string     `json:"status" example:"success"`
+	Message string     `json:"message" example:"Register push device successfully"`
+	Device  PushDevice `json:"device"`
+}
+
+type RemovePushDeviceResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Remove push device successfully"`
+}
@@ -0,0 +1,93 @@
+package example
+
+import "time"
+
+type OAuthClient struct {
+	ID           string    `json:"id" example:"0192f1a4-7c2e-7b61-9d3a-5e8f2c4b6a10"`
+	Name         string    `json:"name" example:"Acme Reports"`
+	Prefix       string    `json:"prefix" example:"ocs_Q3vT8k"`
+	RedirectURIs []string  `json:"redirect_uris" example:"https://reports.example.com/callback"`
+	Scopes       []string  `json:"scopes" example:"profile:read"`
+	RateLimit    int       `json:"rate_limit" example:"600"`
+	CreatedAt    time.Time `json:"created_at" example:"2025-01-01T00:00:00Z"`
+}
+
+type CreateOAuthClientResponse struct {
+	Code         int         `json:"code" example:"201"`
+	Status       string      `json:"status" example:"success"`
+	Message      string      `json:"message" example:"Create OAuth client successfully"`
+	Client       OAuthClient `json:"client"`
+	ClientSecret string      `json:"client_secret" example:"ocs_Q3vT8kZ1x7tWcVbN3sLpE0yHuJdK4gOiTqAeF6MmRa9"`
+}
+
+type GetOAuthClientsResponse struct {
+	Code    int           `json:"code" example:"200"`
+	Status  string        `json:"status" example:"success"`
+	Message string        `json:"message" example:"Get OAuth clients successfully"`
+	Clients []OAuthClient `json:"clients"`
+}
+
+type DeleteOAuthClientResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Delete OAuth client successfully"`
+}
+
+type OAuthScope struct {
+	Name        string `json:"name" example:"profile:read"`
+	Description string `json:"description" example:"Read your profile"`
+}
+
+type OAuthAuthorization struct {
+	ClientID   string       `json:"client_id" example:"0192f1a4-7c2e-7b61-9d3a-5e8f2c4b6a10"`
+	ClientName string       `json:"client_name" example:"Acme Reports"`
+	Scopes     []OAuthScope `json:"scopes"`
+	Consented  bool         `json:"consented" example:"false"`
+}
+
+type GetOAuthAuthorizationResponse struct {
+	Code          int                `json:"code" example:"200"`
+	Status        string             `json:"status" example:"success"`
+	Message       string             `json:"message" example:"Get authorization request successfully"`
+	Authorization OAuthAuthorization `json:"authorization"`
+}
+
+type OAuthRedirectResponse struct {
+	Code        int    `json:"code" example:"200"`
+	Status      string `json:"status" example:"success"`
+	Message     string `json:"message" example:"Authorization granted"`
+	RedirectURI string `json:"redirect_uri" example:"https://reports.example.com/callback?code=SplxlOBeZQQYbYS6WxSbIA&state=af0ifjsldkj"`
+}
+
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token" example:"oat_Xk2f9aQ8mRZ1x7tWcVbN3sLpE0yHuJdK4gOiTqAeF6M"`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	ExpiresIn   int    `json:"expires_in" example:"3600"`
+	Scope       string `json:"scope" example:"profile:read"`
+}
+
+type OAuthTokenError struct {
+	Error            string `json:"error" example:"invalid_grant"`
+	ErrorDescription string `json:"error_description" example:"Authorization code is invalid or expired"`
+}
+
+type OAuthConsent struct {
+	ClientID   string    `json:"client_id" example:"0192f1a4-7c2e-7b61-9d3a-5e8f2c4b6a10"`
+	ClientName string    `json:"client_name" example:"Acme Reports"`
+	Scopes     []string  `json:"scopes" example:"profile:read"`
+	CreatedAt  time.Time `json:"created_at" example:"2025-01-01T00:00:00Z"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2025-01-15T08:30:00Z"`
+}
+
+type GetOAuthConsentsResponse struct {
+	Code     int            `json:"code" example:"200"`
+	Status   string         `json:"status" example:"success"`
+	Message  string         `json:"message" example:"Get OAuth consents successfully"`
+	Consents []OAuthConsent `json:"consents"`
+}
+
+type RevokeOAuthConsentResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Revoke OAuth consent successfully"`
+}
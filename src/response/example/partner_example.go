@@ -0,0 +1,39 @@
+package example
+
+import "time"
+
+type Partner struct {
+	ID         string    `json:"id" example:"0192f1a4-7c2e-7b61-9d3a-5e8f2c4b6a10"`
+	Name       string    `json:"name" example:"Acme Payments"`
+	Prefix     string    `json:"prefix" example:"psk_Q3vT8k"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2025-01-15T08:30:00Z"`
+	CreatedAt  time.Time `json:"created_at" example:"2025-01-01T00:00:00Z"`
+}
+
+type CreatePartnerResponse struct {
+	Code          int     `json:"code" example:"201"`
+	Status        string  `json:"status" example:"success"`
+	Message       string  `json:"message" example:"Create partner successfully"`
+	Partner       Partner `json:"partner"`
+	SigningSecret string  `json:"signing_secret" example:"psk_Q3vT8kZ1x7tWcVbN3sLpE0yHuJdK4gOiTqAeF6MmRa9"`
+}
+
+type GetPartnersResponse struct {
+	Code     int       `json:"code" example:"200"`
+	Status   string    `json:"status" example:"success"`
+	Message  string    `json:"message" example:"Get partners successfully"`
+	Partners []Partner `json:"partners"`
+}
+
+type GetPartnerResponse struct {
+	Code    int     `json:"code" example:"200"`
+	Status  string  `json:"status" example:"success"`
+	Message string  `json:"message" example:"Get partner successfully"`
+	Partner Partner `json:"partner"`
+}
+
+type DeletePartnerResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Delete partner successfully"`
+}
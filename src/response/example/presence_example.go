@@ -0,0 +1,23 @@
+package example
+
+import "time"
+
+type Presence struct {
+	UserID   string     `json:"user_id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Status   string     `json:"status" example:"online"`
+	LastSeen *time.Time `json:"last_seen" example:"2026-10-17T09:30:00Z"`
+}
+
+type GetPresenceResponse struct {
+	Code     int      `json:"code" example:"200"`
+	Status   string   `json:"status" example:"success"`
+	Message  string   `json:"message" example:"Get presence successfully"`
+	Presence Presence `json:"presence"`
+}
+
+type LookupPresenceResponse struct {
+	Code      int        `json:"code" example:"200"`
+	Status    string     `json:"status" example:"success"`
+	Message   string     `json:"message" example:"Get presence successfully"`
+	Presences []Presence `json:"presences"`
+}
@@ -0,0 +1,58 @@
+package example
+
+import "time"
+
+type QuotaPeriod struct {
+	Limit    int64     `json:"limit" example:"1000"`
+	Used     int64     `json:"used" example:"412"`
+	ResetsAt time.Time `json:"resets_at" example:"2026-10-17T00:00:00Z"`
+}
+
+type MonthlyQuotaPeriod struct {
+	Limit    int64     `json:"limit" example:"20000"`
+	Used     int64     `json:"used" example:"7310"`
+	ResetsAt time.Time `json:"resets_at" example:"2026-11-01T00:00:00Z"`
+}
+
+type UserQuota struct {
+	Subject    string             `json:"subject" example:"user:e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Plan       string             `json:"plan" example:"free"`
+	Overridden bool               `json:"overridden" example:"false"`
+	Daily      QuotaPeriod        `json:"daily"`
+	Monthly    MonthlyQuotaPeriod `json:"monthly"`
+}
+
+type TokenQuota struct {
+	Subject    string             `json:"subject" example:"token:5b6c7a52-5a3e-4c1f-9d2e-3f0c8e1a7b44"`
+	Overridden bool               `json:"overridden" example:"true"`
+	Daily      QuotaPeriod        `json:"daily"`
+	Monthly    MonthlyQuotaPeriod `json:"monthly"`
+}
+
+type GetUserQuotaResponse struct {
+	Code    int       `json:"code" example:"200"`
+	Status  string    `json:"status" example:"success"`
+	Message string    `json:"message" example:"Get quota successfully"`
+	Quota   UserQuota `json:"quota"`
+}
+
+type UpdateUserQuotaResponse struct {
+	Code    int       `json:"code" example:"200"`
+	Status  string    `json:"status" example:"success"`
+	Message string    `json:"message" example:"Update quota successfully"`
+	Quota   UserQuota `json:"quota"`
+}
+
+type GetTokenQuotaResponse struct {
+	Code    int        `json:"code" example:"200"`
+	Status  string     `json:"status" example:"success"`
+	Message string     `json:"message" example:"Get quota successfully"`
+	Quota   TokenQuota `json:"quota"`
+}
+
+type UpdateTokenQuotaResponse struct {
+	Code    int        `json:"code" example:"200"`
+	Status  string     `json:"status" example:"success"`
+	Message string     `json:"message" example:"Update quota successfully"`
+	Quota   TokenQuota `json:"quota"`
+}
@@ -0,0 +1,27 @@
+package example
+
+type RateLimitState struct {
+	Limiter      string `json:"limiter" example:"throttle:forgot-password"`
+	Key          string `json:"key" example:"throttle:forgot-password:b4c9a289323b21a01c3e940f150eb9b8c542587f1abfd8f0e1cc1ffc5e475514"`
+	Hits         int    `json:"hits" example:"5"`
+	Limit        int    `json:"limit" example:"5"`
+	Remaining    int    `json:"remaining" example:"0"`
+	Blocked      bool   `json:"blocked" example:"true"`
+	ResetAt      string `json:"reset_at,omitempty" example:"2026-10-16T09:45:00Z"`
+	Strikes      int    `json:"strikes,omitempty" example:"1"`
+	BlockedUntil string `json:"blocked_until,omitempty" example:"2026-10-16T10:00:00Z"`
+}
+
+type GetRateLimitsResponse struct {
+	Code    int              `json:"code" example:"200"`
+	Status  string           `json:"status" example:"success"`
+	Message string           `json:"message" example:"Get rate limits successfully"`
+	Results []RateLimitState `json:"results"`
+}
+
+type ResetRateLimitsResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Reset 1 rate limits"`
+	Reset   int    `json:"reset" example:"1"`
+}
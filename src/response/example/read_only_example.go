@@ -0,0 +1,31 @@
+package example
+
+import "time"
+
+type ReadOnly struct {
+	Enabled bool      `json:"enabled" example:"true"`
+	Reason  string    `json:"reason" example:"Database migration"`
+	Since   time.Time `json:"since" example:"2026-10-17T09:30:00Z"`
+	By      string    `json:"by" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Forced  bool      `json:"forced" example:"false"`
+}
+
+type ReadOnlyRefusal struct {
+	Code   string    `json:"code" example:"read_only"`
+	Reason string    `json:"reason" example:"Database migration"`
+	Since  time.Time `json:"since" example:"2026-10-17T09:30:00Z"`
+}
+
+type GetReadOnlyResponse struct {
+	Code     int      `json:"code" example:"200"`
+	Status   string   `json:"status" example:"success"`
+	Message  string   `json:"message" example:"Get read-only mode successfully"`
+	ReadOnly ReadOnly `json:"read_only"`
+}
+
+type SetReadOnlyResponse struct {
+	Code     int      `json:"code" example:"200"`
+	Status   string   `json:"status" example:"success"`
+	Message  string   `json:"message" example:"Set read-only mode successfully"`
+	ReadOnly ReadOnly `json:"read_only"`
+}
@@ -0,0 +1,23 @@
+package example
+
+type RetentionPolicy struct {
+	Name          string `json:"name" example:"audit_logs"`
+	Table         string `json:"table" example:"audit_logs"`
+	RetentionDays int    `json:"retention_days" example:"365"`
+	Cutoff        string `json:"cutoff" example:"2025-10-17T09:30:00Z"`
+	Rows          int64  `json:"rows" example:"1200"`
+}
+
+type RetentionReportResponse struct {
+	Code     int               `json:"code" example:"200"`
+	Status   string            `json:"status" example:"success"`
+	Message  string            `json:"message" example:"Get retention report successfully"`
+	Policies []RetentionPolicy `json:"policies"`
+}
+
+type RetentionPurgeResponse struct {
+	Code     int               `json:"code" example:"200"`
+	Status   string            `json:"status" example:"success"`
+	Message  string            `json:"message" example:"Purge rows past retention successfully"`
+	Policies []RetentionPolicy `json:"policies"`
+}
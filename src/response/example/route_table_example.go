@@ -0,0 +1,24 @@
+package example
+
+type RouteStep struct {
+	Name   string `json:"name" example:"middleware.AuthPolicy"`
+	Kind   string `json:"kind" example:"auth"`
+	Detail string `json:"detail,omitempty" example:"anyOf(rights(getUsers), owner(userId))"`
+}
+
+type Route struct {
+	Method     string      `json:"method" example:"GET"`
+	Path       string      `json:"path" example:"/v1/users/:userId"`
+	Access     []string    `json:"access" example:"anyOf(rights(getUsers), owner(userId))"`
+	RateLimits []string    `json:"rate_limits" example:"500 failed requests per 15m0s per user or IP"`
+	Cache      string      `json:"cache" example:"ttl 30m0s"`
+	Middleware []RouteStep `json:"middleware"`
+	Handler    string      `json:"handler" example:"controller.(*UserController).GetUserByID"`
+}
+
+type RouteTableResponse struct {
+	Code    int     `json:"code" example:"200"`
+	Status  string  `json:"status" example:"success"`
+	Message string  `json:"message" example:"Get routes successfully"`
+	Routes  []Route `json:"routes"`
+}
@@ -0,0 +1,26 @@
+package example
+
+type SearchHit struct {
+	Type       string  `json:"type" example:"user"`
+	ID         string  `json:"id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	UserID     string  `json:"user_id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Title      string  `json:"title" example:"fake name"`
+	Detail     string  `json:"detail" example:"fake@example.com"`
+	Score      float64 `json:"score" example:"1.06"`
+	OccurredAt string  `json:"occurred_at" example:"2026-10-16T09:30:00Z"`
+}
+
+type SearchResponse struct {
+	Code    int         `json:"code" example:"200"`
+	Status  string      `json:"status" example:"success"`
+	Message string      `json:"message" example:"Search successfully"`
+	Results []SearchHit `json:"results"`
+}
+
+type InvalidSearch struct {
+	Code      int               `json:"code" example:"400"`
+	Status    string            `json:"status" example:"error"`
+	Message   string            `json:"message" example:"Bad Request"`
+	Errors    map[string]string `json:"errors"`
+	RequestID string            `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
@@ -0,0 +1,33 @@
+package example
+
+import "time"
+
+type ServiceAccount struct {
+	ID         string    `json:"id" example:"0192f1a4-7c2e-7b61-9d3a-5e8f2c4b6a11"`
+	UserID     string    `json:"user_id" example:"0192f1a4-7c2e-7b61-9d3a-5e8f2c4b6a12"`
+	Name       string    `json:"name" example:"billing-worker"`
+	Identity   string    `json:"identity" example:"spiffe://example.com/billing-worker"`
+	Rights     []string  `json:"rights" example:"getUsers"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2025-01-15T08:30:00Z"`
+	CreatedAt  time.Time `json:"created_at" example:"2025-01-01T00:00:00Z"`
+}
+
+type CreateServiceAccountResponse struct {
+	Code           int            `json:"code" example:"201"`
+	Status         string         `json:"status" example:"success"`
+	Message        string         `json:"message" example:"Create service account successfully"`
+	ServiceAccount ServiceAccount `json:"service_account"`
+}
+
+type GetServiceAccountsResponse struct {
+	Code            int              `json:"code" example:"200"`
+	Status          string           `json:"status" example:"success"`
+	Message         string           `json:"message" example:"Get service accounts successfully"`
+	ServiceAccounts []ServiceAccount `json:"service_accounts"`
+}
+
+type DeleteServiceAccountResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Delete service account successfully"`
+}
@@ -0,0 +1,15 @@
+package example
+
+type SessionActivityWindow struct {
+	Minutes  int   `json:"minutes" example:"5"`
+	Sessions int64 `json:"sessions" example:"42"`
+	Users    int64 `json:"users" example:"37"`
+}
+
+type GetSessionActivityResponse struct {
+	Code        int                     `json:"code" example:"200"`
+	Status      string                  `json:"status" example:"success"`
+	Message     string                  `json:"message" example:"Get session activity successfully"`
+	Windows     []SessionActivityWindow `json:"windows"`
+	IdleTimeout int                     `json:"idle_timeout" example:"0"`
+}
@@ -0,0 +1,56 @@
+package example
+
+type UsageDay struct {
+	Date    string           `json:"date" example:"2026-10-01"`
+	Metrics map[string]int64 `json:"metrics" example:"requests:1240,login.succeeded:3"`
+}
+
+type UsageKey struct {
+	TokenID string           `json:"token_id" example:"5b6c7a52-5a3e-4c1f-9d2e-3f0c8e1a7b44"`
+	Metrics map[string]int64 `json:"metrics" example:"requests:860"`
+}
+
+type Usage struct {
+	From   string           `json:"from" example:"2026-10-01"`
+	To     string           `json:"to" example:"2026-10-16"`
+	Totals map[string]int64 `json:"totals" example:"requests:18230,login.succeeded:41"`
+	Days   []UsageDay       `json:"days"`
+	Keys   []UsageKey       `json:"keys"`
+}
+
+type UsageUser struct {
+	UserID string `json:"user_id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Email  string `json:"email" example:"fake@example.com"`
+	Count  int64  `json:"count" example:"18230"`
+}
+
+type UsageReport struct {
+	From        string           `json:"from" example:"2026-10-01"`
+	To          string           `json:"to" example:"2026-10-16"`
+	Totals      map[string]int64 `json:"totals" example:"requests:912400,login.succeeded:5120,user.created:310"`
+	Days        []UsageDay       `json:"days"`
+	ActiveUsers int64            `json:"active_users" example:"1480"`
+	Metric      string           `json:"metric" example:"requests"`
+	TopUsers    []UsageUser      `json:"top_users"`
+}
+
+type GetUsageResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Get usage successfully"`
+	Usage   Usage  `json:"usage"`
+}
+
+type GetUsageReportResponse struct {
+	Code    int         `json:"code" example:"200"`
+	Status  string      `json:"status" example:"success"`
+	Message string      `json:"message" example:"Get usage report successfully"`
+	Report  UsageReport `json:"report"`
+}
+
+type InvalidDateRange struct {
+	Code      int    `json:"code" example:"400"`
+	Status    string `json:"status" example:"error"`
+	Message   string `json:"message" example:"The from date must not be after the to date"`
+	RequestID string `json:"request_id" example:"3f0c2d4e-8a1b-4c6d-9e7f-0a1b2c3d4e5f"`
+}
@@ -5,9 +5,13 @@ import "github.com/google/uuid"
 type User struct {
 	ID            uuid.UUID `json:"id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
 	Name          string    `json:"name" example:"fake name"`
+	Username      string    `json:"username" example:"fake_name"`
 	Email         string    `json:"email" example:"fake@example.com"`
 	Role          string    `json:"role" example:"user"`
+	Type          string    `json:"type" example:"human"`
 	VerifiedEmail bool      `json:"verified_email" example:"false"`
+	IsActive      bool      `json:"is_active" example:"true"`
+	Timezone      string    `json:"timezone" example:"Asia/Jakarta"`
 }
 
 type GoogleUser struct {
@@ -16,4 +20,24 @@ type GoogleUser struct {
 	Email         string    `json:"email" example:"fake@example.com"`
 	Role          string    `json:"role" example:"user"`
 	VerifiedEmail bool      `json:"verified_email" example:"true"`
+	IsActive      bool      `json:"is_active" example:"true"`
+}
+
+type SuspendedUser struct {
+	ID            uuid.UUID `json:"id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Name          string    `json:"name" example:"fake name"`
+	Username      string    `json:"username" example:"fake_name"`
+	Email         string    `json:"email" example:"fake@example.com"`
+	Role          string    `json:"role" example:"user"`
+	VerifiedEmail bool      `json:"verified_email" example:"true"`
+	IsActive      bool      `json:"is_active" example:"false"`
+}
+
+type CheckUsernameResponse struct {
+	Code      int    `json:"code" example:"200"`
+	Status    string `json:"status" example:"success"`
+	Message   string `json:"message" example:"Check username successfully"`
+	Username  string `json:"username" example:"fake_name"`
+	Available bool   `json:"available" example:"false"`
+	Reason    string `json:"reason,omitempty" example:"taken"`
 }
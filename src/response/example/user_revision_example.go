@@ -0,0 +1,29 @@
+package example
+
+type UserRevision struct {
+	ID           string         `json:"id" example:"01928f6e-9c40-7e15-b2d4-6f8a0c3e5b72"`
+	UserID       string         `json:"user_id" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	ActorID      string         `json:"actor_id" example:"01928f6e-7a3c-7cc2-9b1e-3f5a2d4c8e10"`
+	Action       string         `json:"action" example:"updated"`
+	Changes      map[string]any `json:"changes"`
+	RestoredFrom string         `json:"restored_from,omitempty" example:""`
+	CreatedAt    string         `json:"created_at" example:"2026-10-16T09:30:00Z"`
+}
+
+type GetUserRevisionsResponse struct {
+	Code         int            `json:"code" example:"200"`
+	Status       string         `json:"status" example:"success"`
+	Message      string         `json:"message" example:"Get user revisions successfully"`
+	Results      []UserRevision `json:"results"`
+	Page         int            `json:"page" example:"1"`
+	Limit        int            `json:"limit" example:"20"`
+	TotalPages   int64          `json:"total_pages" example:"1"`
+	TotalResults int64          `json:"total_results" example:"2"`
+}
+
+type RestoreUserRevisionResponse struct {
+	Code    int    `json:"code" example:"200"`
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Restore user revision successfully"`
+	User    User   `json:"user"`
+}
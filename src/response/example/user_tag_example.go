@@ -0,0 +1,22 @@
+package example
+
+type GetUserTagsResponse struct {
+	Code    int      `json:"code" example:"200"`
+	Status  string   `json:"status" example:"success"`
+	Message string   `json:"message" example:"Get user tags successfully"`
+	Tags    []string `json:"tags" example:"beta,vip"`
+}
+
+type AddUserTagsResponse struct {
+	Code    int      `json:"code" example:"200"`
+	Status  string   `json:"status" example:"success"`
+	Message string   `json:"message" example:"Add user tags successfully"`
+	Tags    []string `json:"tags" example:"beta,vip"`
+}
+
+type RemoveUserTagResponse struct {
+	Code    int      `json:"code" example:"200"`
+	Status  string   `json:"status" example:"success"`
+	Message string   `json:"message" example:"Remove user tag successfully"`
+	Tags    []string `json:"tags" example:"beta"`
+}
@@ -14,3 +14,23 @@ type HealthCheckResponse struct {
 	IsHealthy bool          `json:"is_healthy"`
 	Result    []HealthCheck `json:"result"`
 }
+
+type DBPoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+type ReadinessResponse struct {
+	Code     int          `json:"code"`
+	Status   string       `json:"status"`
+	Message  string       `json:"message"`
+	IsReady  bool         `json:"is_ready"`
+	IsLeader bool         `json:"is_leader"`
+	DBPool   *DBPoolStats `json:"db_pool,omitempty"`
+}
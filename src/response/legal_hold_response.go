@@ -0,0 +1,32 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LegalHold struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Reason    string     `json:"reason"`
+	PlacedBy  *uuid.UUID `json:"placed_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NewLegalHold maps a legal hold to its response DTO
+func NewLegalHold(hold *model.LegalHold) LegalHold {
+	return LegalHold{
+		UserID:    hold.UserID,
+		Reason:    hold.Reason,
+		PlacedBy:  hold.PlacedBy,
+		CreatedAt: hold.CreatedAt,
+	}
+}
+
+type SuccessWithLegalHold struct {
+	Code      int       `json:"code"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	LegalHold LegalHold `json:"legal_hold"`
+}
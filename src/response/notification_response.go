@@ -0,0 +1,57 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationPreference struct {
+	Kind     string   `json:"kind"`
+	Channels []string `json:"channels"`
+}
+
+type NotificationSettings struct {
+	// Phone is the verified phone number, nil without one
+	Phone           *string    `json:"phone"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"`
+	// Channels are the channels this deployment can deliver on
+	Channels    []string                 `json:"channels"`
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+type SuccessWithNotificationSettings struct {
+	Code     int                  `json:"code"`
+	Status   string               `json:"status"`
+	Message  string               `json:"message"`
+	Settings NotificationSettings `json:"settings"`
+}
+
+type SuccessWithNotificationPreference struct {
+	Code       int                    `json:"code"`
+	Status     string                 `json:"status"`
+	Message    string                 `json:"message"`
+	Preference NotificationPreference `json:"preference"`
+}
+
+type PushDevice struct {
+	ID        uuid.UUID `json:"id"`
+	Platform  string    `json:"platform"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SuccessWithPushDevice struct {
+	Code    int        `json:"code"`
+	Status  string     `json:"status"`
+	Message string     `json:"message"`
+	Device  PushDevice `json:"device"`
+}
+
+type SuccessWithPushDevices struct {
+	Code    int          `json:"code"`
+	Status  string       `json:"status"`
+	Message string       `json:"message"`
+	Devices []PushDevice `json:"devices"`
+}
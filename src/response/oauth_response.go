@@ -0,0 +1,122 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthClient is the public representation of a third-party client; its secret is never exposed
+type OAuthClient struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Prefix       string    `json:"prefix"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	RateLimit    int       `json:"rate_limit"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewOAuthClient maps a client model to its response DTO
+func NewOAuthClient(client *model.OAuthClient) OAuthClient {
+	return OAuthClient{
+		ID:           client.ID,
+		Name:         client.Name,
+		Prefix:       client.Prefix,
+		RedirectURIs: client.RedirectURIList(),
+		Scopes:       client.ScopeList(),
+		RateLimit:    client.RateLimit,
+		CreatedAt:    client.CreatedAt,
+	}
+}
+
+// OAuthScope is a scope shown on the consent screen
+type OAuthScope struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// OAuthAuthorization describes an authorization request for the consent screen
+type OAuthAuthorization struct {
+	ClientID   uuid.UUID    `json:"client_id"`
+	ClientName string       `json:"client_name"`
+	Scopes     []OAuthScope `json:"scopes"`
+	// Consented is true when the user already granted every requested scope to the client
+	Consented bool `json:"consented"`
+}
+
+// OAuthConsent is a client the user granted access to
+type OAuthConsent struct {
+	ClientID   uuid.UUID `json:"client_id"`
+	ClientName string    `json:"client_name"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NewOAuthConsent maps a consent model, with its client loaded, to its response DTO
+func NewOAuthConsent(consent *model.OAuthConsent) OAuthConsent {
+	result := OAuthConsent{
+		ClientID:  consent.ClientID,
+		Scopes:    consent.ScopeList(),
+		CreatedAt: consent.CreatedAt,
+		UpdatedAt: consent.UpdatedAt,
+	}
+	if consent.Client != nil {
+		result.ClientName = consent.Client.Name
+	}
+	return result
+}
+
+// OAuthToken is the token endpoint's response, in the RFC 6749 format
+type OAuthToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// OAuthError is an error of the token endpoint, in the RFC 6749 format
+type OAuthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type SuccessWithOAuthClient struct {
+	Code    int         `json:"code"`
+	Status  string      `json:"status"`
+	Message string      `json:"message"`
+	Client  OAuthClient `json:"client"`
+	// ClientSecret is the plaintext secret, returned only when the client is created
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type SuccessWithOAuthClients struct {
+	Code    int           `json:"code"`
+	Status  string        `json:"status"`
+	Message string        `json:"message"`
+	Clients []OAuthClient `json:"clients"`
+}
+
+type SuccessWithOAuthAuthorization struct {
+	Code          int                `json:"code"`
+	Status        string             `json:"status"`
+	Message       string             `json:"message"`
+	Authorization OAuthAuthorization `json:"authorization"`
+}
+
+type SuccessWithOAuthRedirect struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	// RedirectURI is where the browser goes next, carrying the code or the error for the client
+	RedirectURI string `json:"redirect_uri"`
+}
+
+type SuccessWithOAuthConsents struct {
+	Code     int            `json:"code"`
+	Status   string         `json:"status"`
+	Message  string         `json:"message"`
+	Consents []OAuthConsent `json:"consents"`
+}
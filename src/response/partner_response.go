@@ -0,0 +1,44 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Partner is the public representation of a partner; its signing key is never exposed
+type Partner struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewPartner maps a partner model to its response DTO
+func NewPartner(partner *model.Partner) Partner {
+	return Partner{
+		ID:         partner.ID,
+		Name:       partner.Name,
+		Prefix:     partner.Prefix,
+		LastUsedAt: partner.LastUsedAt,
+		CreatedAt:  partner.CreatedAt,
+	}
+}
+
+type SuccessWithPartner struct {
+	Code    int     `json:"code"`
+	Status  string  `json:"status"`
+	Message string  `json:"message"`
+	Partner Partner `json:"partner"`
+	// SigningSecret is the plaintext signing secret, returned only when the partner is created
+	SigningSecret string `json:"signing_secret,omitempty"`
+}
+
+type SuccessWithPartners struct {
+	Code     int       `json:"code"`
+	Status   string    `json:"status"`
+	Message  string    `json:"message"`
+	Partners []Partner `json:"partners"`
+}
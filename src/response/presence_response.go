@@ -0,0 +1,36 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Presence struct {
+	UserID uuid.UUID `json:"user_id"`
+	// Status is online or offline
+	Status string `json:"status"`
+	// LastSeen is the last heartbeat or disconnect, nil if the user was not seen lately
+	LastSeen *time.Time `json:"last_seen"`
+}
+
+type SuccessWithPresence struct {
+	Code     int      `json:"code"`
+	Status   string   `json:"status"`
+	Message  string   `json:"message"`
+	Presence Presence `json:"presence"`
+}
+
+type SuccessWithPresences struct {
+	Code      int        `json:"code"`
+	Status    string     `json:"status"`
+	Message   string     `json:"message"`
+	Presences []Presence `json:"presences"`
+}
+
+// PresenceWelcome is the first message of a presence WebSocket, telling the client how often to
+// send heartbeats
+type PresenceWelcome struct {
+	Type              string `json:"type"`
+	HeartbeatInterval int    `json:"heartbeat_interval"`
+}
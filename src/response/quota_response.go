@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+// QuotaPeriod is a subject's usage of one quota period
+type QuotaPeriod struct {
+	Limit    int64     `json:"limit"` // 0 means unlimited
+	Used     int64     `json:"used"`
+	ResetsAt time.Time `json:"resets_at"`
+}
+
+// Quota is the request quota of a user or API token
+type Quota struct {
+	Subject    string      `json:"subject"`
+	Plan       string      `json:"plan,omitempty"` // users only
+	Overridden bool        `json:"overridden"`     // an admin set limits replacing the plan's
+	Daily      QuotaPeriod `json:"daily"`
+	Monthly    QuotaPeriod `json:"monthly"`
+}
+
+type SuccessWithQuota struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Quota   Quota  `json:"quota"`
+}
@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+// RateLimitState is a snapshot of one limiter's counters for a key
+type RateLimitState struct {
+	Limiter      string     `json:"limiter"`
+	Key          string     `json:"key"`
+	Hits         int        `json:"hits"`
+	Limit        int        `json:"limit"`
+	Remaining    int        `json:"remaining"`
+	Blocked      bool       `json:"blocked"`
+	ResetAt      *time.Time `json:"reset_at,omitempty"`
+	Strikes      int        `json:"strikes,omitempty"`
+	BlockedUntil *time.Time `json:"blocked_until,omitempty"`
+}
+
+type SuccessWithRateLimits struct {
+	Code    int              `json:"code"`
+	Status  string           `json:"status"`
+	Message string           `json:"message"`
+	Results []RateLimitState `json:"results"`
+}
+
+type SuccessWithRateLimitReset struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Reset   int    `json:"reset"`
+}
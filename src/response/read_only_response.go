@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+type ReadOnly struct {
+	Enabled bool       `json:"enabled"`
+	Reason  string     `json:"reason,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+	By      string     `json:"by,omitempty"`
+	// Forced is set when READ_ONLY keeps the app read-only whatever the switch says
+	Forced bool `json:"forced"`
+}
+
+// ReadOnlyRefusal details why a write was refused in read-only mode; Code is always "read_only"
+type ReadOnlyRefusal struct {
+	Code   string     `json:"code"`
+	Reason string     `json:"reason,omitempty"`
+	Since  *time.Time `json:"since,omitempty"`
+}
+
+type SuccessWithReadOnly struct {
+	Code     int      `json:"code"`
+	Status   string   `json:"status"`
+	Message  string   `json:"message"`
+	ReadOnly ReadOnly `json:"read_only"`
+}
@@ -0,0 +1,45 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestTrace is what a request left behind, looked up by its ID for troubleshooting
+type RequestTrace struct {
+	RequestID string              `json:"request_id"`
+	AuditLogs []RequestTraceAudit `json:"audit_logs"`
+	// Emails are those still in the outbox, pending or failed; sent emails are deleted from it
+	Emails  []RequestTraceEmail `json:"emails"`
+	Capture *DebugCapture       `json:"capture,omitempty"`
+}
+
+type RequestTraceAudit struct {
+	ID        uuid.UUID       `json:"id"`
+	UserID    *uuid.UUID      `json:"user_id"`
+	ActorType *string         `json:"actor_type"`
+	Action    string          `json:"action"`
+	IP        string          `json:"ip,omitempty"`
+	UserAgent string          `json:"user_agent,omitempty"`
+	Metadata  json.RawMessage `json:"metadata"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type RequestTraceEmail struct {
+	ID        uuid.UUID `json:"id"`
+	Recipient string    `json:"recipient"`
+	Subject   string    `json:"subject"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SuccessWithRequestTrace struct {
+	Code    int          `json:"code"`
+	Status  string       `json:"status"`
+	Message string       `json:"message"`
+	Trace   RequestTrace `json:"trace"`
+}
@@ -1,26 +1,33 @@
 package response
 
-import "app/src/model"
-
 type Common struct {
 	Code    int    `json:"code"`
 	Status  string `json:"status"`
 	Message string `json:"message"`
+	// RequestID is set on errors, for support to look the request up (see response.Error)
+	RequestID string `json:"request_id,omitempty"`
 }
 
 type SuccessWithUser struct {
-	Code    int        `json:"code"`
-	Status  string     `json:"status"`
-	Message string     `json:"message"`
-	User    model.User `json:"user"`
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	User    User   `json:"user"`
+}
+
+type SuccessWithFields struct {
+	Code    int                    `json:"code"`
+	Status  string                 `json:"status"`
+	Message string                 `json:"message"`
+	User    map[string]interface{} `json:"user"`
 }
 
 type SuccessWithTokens struct {
-	Code    int        `json:"code"`
-	Status  string     `json:"status"`
-	Message string     `json:"message"`
-	User    model.User `json:"user"`
-	Tokens  Tokens     `json:"tokens"`
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	User    User   `json:"user"`
+	Tokens  Tokens `json:"tokens"`
 }
 
 type SuccessWithPaginate[T any] struct {
@@ -35,8 +42,9 @@ type SuccessWithPaginate[T any] struct {
 }
 
 type ErrorDetails struct {
-	Code    int         `json:"code"`
-	Status  string      `json:"status"`
-	Message string      `json:"message"`
-	Errors  interface{} `json:"errors"`
+	Code      int         `json:"code"`
+	Status    string      `json:"status"`
+	Message   string      `json:"message"`
+	Errors    interface{} `json:"errors"`
+	RequestID string      `json:"request_id,omitempty"`
 }
@@ -0,0 +1,20 @@
+package response
+
+import "time"
+
+// RetentionPolicy is a retention policy with the rows it has past its cutoff, or has just purged
+type RetentionPolicy struct {
+	Name          string    `json:"name"`
+	Table         string    `json:"table"`
+	RetentionDays int       `json:"retention_days"`
+	Cutoff        time.Time `json:"cutoff"`
+	// Rows counts the rows older than the cutoff in a report, and the rows deleted by a purge
+	Rows int64 `json:"rows"`
+}
+
+type SuccessWithRetentionPolicies struct {
+	Code     int               `json:"code"`
+	Status   string            `json:"status"`
+	Message  string            `json:"message"`
+	Policies []RetentionPolicy `json:"policies"`
+}
@@ -0,0 +1,25 @@
+package response
+
+type RouteStep struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type Route struct {
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	Name       string      `json:"name,omitempty"`
+	Access     []string    `json:"access"`
+	RateLimits []string    `json:"rate_limits"`
+	Cache      string      `json:"cache"`
+	Middleware []RouteStep `json:"middleware"`
+	Handler    string      `json:"handler"`
+}
+
+type SuccessWithRoutes struct {
+	Code    int     `json:"code"`
+	Status  string  `json:"status"`
+	Message string  `json:"message"`
+	Routes  []Route `json:"routes"`
+}
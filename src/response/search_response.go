@@ -0,0 +1,25 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchHit is one record found by the admin search
+type SearchHit struct {
+	Type       string     `json:"type"`
+	ID         string     `json:"id"`
+	UserID     *uuid.UUID `json:"user_id"`
+	Title      string     `json:"title"`
+	Detail     string     `json:"detail"`
+	Score      float64    `json:"score"`
+	OccurredAt time.Time  `json:"occurred_at"`
+}
+
+type SuccessWithSearchHits struct {
+	Code    int         `json:"code"`
+	Status  string      `json:"status"`
+	Message string      `json:"message"`
+	Results []SearchHit `json:"results"`
+}
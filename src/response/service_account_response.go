@@ -0,0 +1,47 @@
+package response
+
+import (
+	"app/src/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceAccount is the public representation of a service account; UserID is the service user it
+// acts as
+type ServiceAccount struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Identity   string     `json:"identity"`
+	Rights     []string   `json:"rights"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewServiceAccount maps a service account model to its response DTO
+func NewServiceAccount(account *model.ServiceAccount) ServiceAccount {
+	return ServiceAccount{
+		ID:         account.ID,
+		UserID:     account.UserID,
+		Name:       account.Name,
+		Identity:   account.Identity,
+		Rights:     account.RightList(),
+		LastUsedAt: account.LastUsedAt,
+		CreatedAt:  account.CreatedAt,
+	}
+}
+
+type SuccessWithServiceAccount struct {
+	Code           int            `json:"code"`
+	Status         string         `json:"status"`
+	Message        string         `json:"message"`
+	ServiceAccount ServiceAccount `json:"service_account"`
+}
+
+type SuccessWithServiceAccounts struct {
+	Code            int              `json:"code"`
+	Status          string           `json:"status"`
+	Message         string           `json:"message"`
+	ServiceAccounts []ServiceAccount `json:"service_accounts"`
+}
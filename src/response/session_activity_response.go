@@ -0,0 +1,21 @@
+package response
+
+// SessionActivityWindow counts the sessions and distinct users active within the last Minutes
+type SessionActivityWindow struct {
+	Minutes  int   `json:"minutes"`
+	Sessions int64 `json:"sessions"`
+	Users    int64 `json:"users"`
+}
+
+// SessionActivity reports recent session activity; IdleTimeout is in minutes, 0 when idle sessions are kept
+type SessionActivity struct {
+	Windows     []SessionActivityWindow `json:"windows"`
+	IdleTimeout int                     `json:"idle_timeout"`
+}
+
+type SuccessWithSessionActivity struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	SessionActivity
+}
@@ -0,0 +1,56 @@
+package response
+
+import "github.com/google/uuid"
+
+// UsageDay is the usage of every metric on one UTC day
+type UsageDay struct {
+	Date    string           `json:"date"`
+	Metrics map[string]int64 `json:"metrics"`
+}
+
+// UsageKey is the usage of the requests made with one of the user's API tokens
+type UsageKey struct {
+	TokenID uuid.UUID        `json:"token_id"`
+	Metrics map[string]int64 `json:"metrics"`
+}
+
+// Usage is a user's metered usage over a date range
+type Usage struct {
+	From   string           `json:"from"`
+	To     string           `json:"to"`
+	Totals map[string]int64 `json:"totals"`
+	Days   []UsageDay       `json:"days"`
+	Keys   []UsageKey       `json:"keys"`
+}
+
+// UsageUser is a user's count of the metric a usage report ranks by
+type UsageUser struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email,omitempty"` // empty once the user is deleted
+	Count  int64     `json:"count"`
+}
+
+// UsageReport aggregates the usage of all users over a date range
+type UsageReport struct {
+	From        string           `json:"from"`
+	To          string           `json:"to"`
+	Totals      map[string]int64 `json:"totals"`
+	Days        []UsageDay       `json:"days"`
+	ActiveUsers int64            `json:"active_users"` // users with any usage in the range
+	Metric      string           `json:"metric"`
+	TopUsers    []UsageUser      `json:"top_users"`
+}
+
+type SuccessWithUsage struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Usage   Usage  `json:"usage"`
+}
+
+type SuccessWithUsageReport struct {
+	Code    int         `json:"code"`
+	Status  string      `json:"status"`
+	Message string      `json:"message"`
+	Report  UsageReport `json:"report"`
+}
@@ -1,6 +1,10 @@
 package response
 
-import "github.com/google/uuid"
+import (
+	"app/src/model"
+
+	"github.com/google/uuid"
+)
 
 type CreateUser struct {
 	Name            string `json:"name"`
@@ -16,3 +20,41 @@ type GetUsers struct {
 	Role            string    `json:"role"`
 	IsEmailVerified bool      `json:"is_email_verified"`
 }
+
+// User is the public representation of model.User; it never carries the password hash or tokens
+type User struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Username      *string   `json:"username"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	VerifiedEmail bool      `json:"verified_email"`
+	IsActive      bool      `json:"is_active"`
+}
+
+// NewUser maps a user model to its response DTO
+func NewUser(user *model.User) User {
+	return User{
+		ID:            user.ID,
+		Name:          user.Name,
+		Username:      user.Username,
+		Email:         user.Email,
+		Role:          user.Role,
+		VerifiedEmail: user.VerifiedEmail,
+		IsActive:      user.IsActive,
+	}
+}
+
+// UsernameAvailability tells a client whether a username can be chosen; Reason is "reserved" or "taken"
+type UsernameAvailability struct {
+	Username  string `json:"username"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type SuccessWithUsernameAvailability struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	UsernameAvailability
+}
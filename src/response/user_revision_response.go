@@ -0,0 +1,33 @@
+package response
+
+import (
+	"app/src/model"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserRevision is one recorded change to a user; Changes maps columns to their values before and after
+type UserRevision struct {
+	ID           uuid.UUID       `json:"id"`
+	UserID       uuid.UUID       `json:"user_id"`
+	ActorID      *uuid.UUID      `json:"actor_id"`
+	Action       string          `json:"action"`
+	Changes      json.RawMessage `json:"changes"`
+	RestoredFrom *uuid.UUID      `json:"restored_from,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// NewUserRevision maps a user revision to its response DTO
+func NewUserRevision(revision *model.UserRevision) UserRevision {
+	return UserRevision{
+		ID:           revision.ID,
+		UserID:       revision.UserID,
+		ActorID:      revision.ActorID,
+		Action:       revision.Action,
+		Changes:      json.RawMessage(revision.Changes),
+		RestoredFrom: revision.RestoredFrom,
+		CreatedAt:    revision.CreatedAt,
+	}
+}
@@ -0,0 +1,8 @@
+package response
+
+type SuccessWithTags struct {
+	Code    int      `json:"code"`
+	Status  string   `json:"status"`
+	Message string   `json:"message"`
+	Tags    []string `json:"tags"`
+}
@@ -0,0 +1,122 @@
+// Package retention deletes rows once they are older than the retention of their policy. Rows are
+// deleted oldest first in batches, so a large backlog never holds locks or a transaction for long
+// and a purge cut short resumes where it stopped.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/src/config"
+	"app/src/metrics"
+
+	"gorm.io/gorm"
+)
+
+// Policy covers the rows of one table, or part of one, kept for Retention
+type Policy struct {
+	Name  string
+	Table string
+	// Column is the timestamp the age of a row is measured from
+	Column string
+	// Condition narrows the policy to some rows of Table; empty covers them all
+	Condition string
+	// UserColumn names the user a row belongs to; rows of users under legal hold are kept
+	UserColumn string
+	Retention  time.Duration
+}
+
+// policies describes what each configured policy covers. Login history is the login.* part of the
+// audit log, text messages are the only notifications stored, and the reports of finished bulk
+// actions the only exports; their items are deleted with them. Exports and the unique visitor
+// sketches of past days and months belong to no user, so legal holds do not keep them.
+var policies = map[string]Policy{
+	config.RetentionAuditLogs: {
+		Table: "audit_logs", Column: "created_at", Condition: "action NOT LIKE 'login.%'", UserColumn: "user_id",
+	},
+	config.RetentionLoginHistory: {
+		Table: "audit_logs", Column: "created_at", Condition: "action LIKE 'login.%'", UserColumn: "user_id",
+	},
+	config.RetentionNotifications: {
+		Table: "text_messages", Column: "created_at", UserColumn: "user_id",
+	},
+	config.RetentionExports: {
+		Table: "bulk_jobs", Column: "finished_at",
+	},
+	config.RetentionAnalytics: {
+		Table: "analytics_sketches", Column: "updated_at",
+	},
+}
+
+// Policies returns the configured policies in purge order, leaving out those keeping rows forever
+func Policies(cfg config.RetentionConfig) []Policy {
+	var configured []Policy
+	for _, name := range config.RetentionPolicies {
+		days, ok := cfg.Days[name]
+		if !ok {
+			continue
+		}
+
+		policy := policies[name]
+		policy.Name = name
+		policy.Retention = time.Duration(days) * 24 * time.Hour
+		configured = append(configured, policy)
+	}
+	return configured
+}
+
+// Cutoff is the time rows created before are past their retention
+func (p Policy) Cutoff(now time.Time) time.Time {
+	return now.Add(-p.Retention)
+}
+
+// Due counts the rows older than cutoff
+func Due(ctx context.Context, db *gorm.DB, policy Policy, cutoff time.Time) (int64, error) {
+	var due int64
+	err := db.WithContext(ctx).Table(policy.Table).Where(policy.where(), cutoff).Count(&due).Error
+	if err == nil {
+		metrics.RetentionDue(policy.Name, due)
+	}
+	return due, err
+}
+
+// Purge deletes the rows older than cutoff, at most batchSize per statement, until none are left or
+// ctx is done. The rows deleted are returned on error too, as earlier batches stay deleted.
+func Purge(ctx context.Context, db *gorm.DB, policy Policy, cutoff time.Time, batchSize int) (int64, error) {
+	statement := fmt.Sprintf(
+		"DELETE FROM %[1]s WHERE id IN (SELECT id FROM %[1]s WHERE %[2]s ORDER BY %[3]s LIMIT ?)",
+		policy.Table, policy.where(), policy.Column,
+	)
+
+	var purged int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+
+		result := db.WithContext(ctx).Exec(statement, cutoff, batchSize)
+		if result.Error != nil {
+			return purged, result.Error
+		}
+		purged += result.RowsAffected
+		metrics.RetentionPurged(policy.Name, result.RowsAffected)
+
+		if result.RowsAffected < int64(batchSize) {
+			return purged, nil
+		}
+	}
+}
+
+func (p Policy) where() string {
+	where := p.Column + " < ?"
+	if p.Condition != "" {
+		where += " AND " + p.Condition
+	}
+	if p.UserColumn != "" {
+		where += fmt.Sprintf(
+			" AND NOT EXISTS (SELECT 1 FROM legal_holds WHERE legal_holds.user_id = %s.%s)", p.Table, p.UserColumn,
+		)
+	}
+	return where
+}
@@ -0,0 +1,182 @@
+package revision
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"app/src/model"
+	"app/src/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// beforeKey stores the users an update is about to change between its callbacks
+const beforeKey = "revision:before"
+
+// GormPlugin records a revision for every user inserted or updated through GORM. Updates load the
+// matched users before and after the statement, in its transaction if any, and record the columns
+// that changed. Raw SQL is not tracked. A revision that cannot be recorded is logged and does not
+// fail the change.
+type GormPlugin struct{}
+
+var _ gorm.Plugin = GormPlugin{}
+
+func (GormPlugin) Name() string {
+	return "revision"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().After("gorm:create").Register("revision:after_create", afterCreate),
+		callbacks.Update().Before("gorm:update").Register("revision:before_update", beforeUpdate),
+		callbacks.Update().After("gorm:update").Register("revision:after_update", afterUpdate),
+	)
+}
+
+// userType is the model whose rows are tracked
+var userType = reflect.TypeOf(model.User{})
+
+func tracked(db *gorm.DB) bool {
+	return db.Error == nil && db.Statement.Schema != nil && db.Statement.Schema.ModelType == userType
+}
+
+func afterCreate(db *gorm.DB) {
+	if !tracked(db) {
+		return
+	}
+
+	var revisions []model.UserRevision
+	eachUser(db.Statement.ReflectValue, func(user *model.User) {
+		revisions = append(revisions, newRevision(db, user.ID, model.UserRevisionCreated, Created(user)))
+	})
+	record(db, revisions)
+}
+
+func beforeUpdate(db *gorm.DB) {
+	if !tracked(db) {
+		return
+	}
+
+	// Without conditions gorm:update refuses the statement anyway
+	conditions := updateConditions(db)
+	if len(conditions) == 0 {
+		return
+	}
+
+	var users []model.User
+	if err := session(db).Clauses(clause.Where{Exprs: conditions}).Find(&users).Error; err != nil {
+		utils.Log.Errorf("Failed to load users before update, revisions not recorded: %+v", err)
+		return
+	}
+	db.InstanceSet(beforeKey, users)
+}
+
+func afterUpdate(db *gorm.DB) {
+	value, ok := db.InstanceGet(beforeKey)
+	if !ok || !tracked(db) || db.RowsAffected == 0 {
+		return
+	}
+
+	before := value.([]model.User)
+	if len(before) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(before))
+	for i := range before {
+		ids[i] = before[i].ID
+	}
+
+	var after []model.User
+	if err := session(db).Where("id IN ?", ids).Find(&after).Error; err != nil {
+		utils.Log.Errorf("Failed to load users after update, revisions not recorded: %+v", err)
+		return
+	}
+
+	updated := make(map[uuid.UUID]*model.User, len(after))
+	for i := range after {
+		updated[after[i].ID] = &after[i]
+	}
+
+	action := model.UserRevisionUpdated
+	if RestoredFrom(db.Statement.Context) != nil {
+		action = model.UserRevisionRestored
+	}
+
+	var revisions []model.UserRevision
+	for i := range before {
+		user, ok := updated[before[i].ID]
+		if !ok {
+			continue
+		}
+		if changes := Diff(&before[i], user); len(changes) > 0 {
+			revisions = append(revisions, newRevision(db, user.ID, action, changes))
+		}
+	}
+	record(db, revisions)
+}
+
+// updateConditions returns the conditions of an update. Updates through a loaded user, such as Save,
+// get its primary key condition only inside gorm:update, so it is added here.
+func updateConditions(db *gorm.DB) []clause.Expression {
+	var conditions []clause.Expression
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			conditions = append(conditions, where.Exprs...)
+		}
+	}
+
+	if value := db.Statement.ReflectValue; value.IsValid() && value.Type() == userType && value.CanAddr() {
+		if user := value.Addr().Interface().(*model.User); user.ID != uuid.Nil {
+			conditions = append(conditions, clause.Eq{Column: clause.Column{Name: "id"}, Value: user.ID})
+		}
+	}
+
+	return conditions
+}
+
+// session runs queries on the statement's connection, so they share its transaction
+func session(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Model(new(model.User))
+}
+
+func newRevision(
+	db *gorm.DB, userID uuid.UUID, action string, changes map[string]model.FieldChange,
+) model.UserRevision {
+	body, _ := json.Marshal(changes)
+	return model.UserRevision{
+		UserID:       userID,
+		ActorID:      Actor(db.Statement.Context),
+		Action:       action,
+		Changes:      string(body),
+		RestoredFrom: RestoredFrom(db.Statement.Context),
+	}
+}
+
+func record(db *gorm.DB, revisions []model.UserRevision) {
+	if len(revisions) == 0 {
+		return
+	}
+
+	if err := db.Session(&gorm.Session{NewDB: true}).Create(&revisions).Error; err != nil {
+		utils.Log.Errorf("Failed to record user revisions: %+v", err)
+	}
+}
+
+// eachUser calls fn with every user a statement wrote, one or a batch
+func eachUser(value reflect.Value, fn func(*model.User)) {
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.CanAddr() {
+			fn(value.Addr().Interface().(*model.User))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			eachUser(reflect.Indirect(value.Index(i)), fn)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package revision keeps the history of user rows. GormPlugin records every insert and update of a
+// user as a model.UserRevision holding the changed columns, the acting user and, for rollbacks, the
+// revision restored from.
+package revision
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"app/src/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
+)
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	restoredFromKey
+)
+
+// WithActor returns ctx with the user making the changes written under it
+func WithActor(ctx context.Context, actorID uuid.UUID) context.Context {
+	return context.WithValue(ctx, actorKey, actorID)
+}
+
+// Actor returns the user set by WithActor, or nil outside a request
+func Actor(ctx context.Context) *uuid.UUID {
+	if actorID, ok := ctx.Value(actorKey).(uuid.UUID); ok {
+		return &actorID
+	}
+	return nil
+}
+
+// WithRestoredFrom returns ctx with updates recorded as a rollback to the revision
+func WithRestoredFrom(ctx context.Context, revisionID uuid.UUID) context.Context {
+	return context.WithValue(ctx, restoredFromKey, revisionID)
+}
+
+// RestoredFrom returns the revision set by WithRestoredFrom, or nil for a plain update
+func RestoredFrom(ctx context.Context) *uuid.UUID {
+	if revisionID, ok := ctx.Value(restoredFromKey).(uuid.UUID); ok {
+		return &revisionID
+	}
+	return nil
+}
+
+// userFields are the user columns revisions track; timestamps and relations are left out
+var userFields = sync.OnceValue(func() []*schema.Field {
+	userSchema, err := schema.Parse(new(model.User), new(sync.Map), schema.NamingStrategy{})
+	if err != nil {
+		panic(err)
+	}
+
+	var fields []*schema.Field
+	for _, field := range userSchema.Fields {
+		if field.DBName != "" && field.AutoCreateTime == 0 && field.AutoUpdateTime == 0 {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+})
+
+// Diff returns the columns that differ between two versions of a user, by column name
+func Diff(before, after *model.User) map[string]model.FieldChange {
+	changes := map[string]model.FieldChange{}
+	for _, field := range userFields() {
+		from := field.ReflectValueOf(context.Background(), reflect.ValueOf(before).Elem()).Interface()
+		to := field.ReflectValueOf(context.Background(), reflect.ValueOf(after).Elem()).Interface()
+		if !reflect.DeepEqual(from, to) {
+			changes[field.DBName] = change(field, from, to)
+		}
+	}
+	return changes
+}
+
+// Created returns every column of a new user as changed from null, so the first revision holds the
+// whole row
+func Created(user *model.User) map[string]model.FieldChange {
+	changes := map[string]model.FieldChange{}
+	for _, field := range userFields() {
+		value := field.ReflectValueOf(context.Background(), reflect.ValueOf(user).Elem()).Interface()
+		changes[field.DBName] = change(field, nil, value)
+	}
+	return changes
+}
+
+// change keeps the values of a column unless it is hidden from JSON
+func change(field *schema.Field, from, to any) model.FieldChange {
+	if field.Tag.Get("json") == "-" {
+		return model.FieldChange{Redacted: true}
+	}
+	return model.FieldChange{From: from, To: to}
+}
+
+// State returns the column values the user had right after revisions[target], from revisions
+// oldest first. Columns changed neither up to the target nor after it are left out, as are
+// redacted ones.
+func State(revisions []map[string]model.FieldChange, target int) map[string]any {
+	state := map[string]any{}
+	for i, changes := range revisions {
+		for column, change := range changes {
+			if change.Redacted {
+				continue
+			}
+			if i <= target {
+				state[column] = change.To
+			} else if _, ok := state[column]; !ok {
+				// The first later change started from the value the column had at the target
+				state[column] = change.From
+			}
+		}
+	}
+	return state
+}
@@ -0,0 +1,298 @@
+package revocation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"app/src/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Channel is the Redis pub/sub channel revocations are broadcast on
+const Channel = "revocation:users"
+
+// Revocation reasons
+const (
+	ReasonDeleted     = "deleted"
+	ReasonRoleChanged = "role_changed"
+	ReasonSuspended   = "suspended"
+	ReasonEvicted     = "evicted"
+	ReasonReplayed    = "replayed"
+)
+
+// Event announces that a user's existing sessions, or only the session SessionID, must no longer
+// be trusted
+type Event struct {
+	UserID    string    `json:"user_id"`
+	SessionID string    `json:"session_id,omitempty"`
+	Reason    string    `json:"reason"`
+	RevokedAt time.Time `json:"revoked_at"`
+	// Origin is the publishing instance, which has already applied the event locally
+	Origin string `json:"origin"`
+}
+
+// Bus broadcasts user revocations to every instance and applies them to in-process state:
+// it remembers revoked users and sessions for auth and closes the users' long-lived connections
+type Bus struct {
+	redisClient *redis.RedisClient
+	instanceID  string
+	// retention is how long a revocation is remembered (the access token lifetime)
+	retention time.Duration
+
+	mu       sync.Mutex
+	revoked  map[string]time.Time
+	sessions map[string]time.Time
+	conns    map[string]map[int]func()
+	handlers map[int]func(Event)
+	nextID   int
+}
+
+// NewBus creates a revocation bus; without Redis, revocations only apply to this instance
+func NewBus(redisClient *redis.RedisClient, retention time.Duration) *Bus {
+	return &Bus{
+		redisClient: redisClient,
+		instanceID:  newInstanceID(),
+		retention:   retention,
+		revoked:     make(map[string]time.Time),
+		sessions:    make(map[string]time.Time),
+		conns:       make(map[string]map[int]func()),
+		handlers:    make(map[int]func(Event)),
+	}
+}
+
+// Start listens for revocations from other instances until ctx is cancelled.
+// go-redis re-subscribes on its own after connection loss.
+func (b *Bus) Start(ctx context.Context) {
+	if b == nil || b.redisClient == nil {
+		return
+	}
+
+	pubsub := b.redisClient.GetClient().Subscribe(ctx, b.redisClient.Key(Channel))
+	defer pubsub.Close()
+
+	logrus.Infof("Listening for session revocations on %s", b.redisClient.Key(Channel))
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				logrus.Warnf("Ignoring malformed revocation message: %v", err)
+				continue
+			}
+			if event.Origin == b.instanceID {
+				continue
+			}
+
+			b.apply(event)
+		}
+	}
+}
+
+// Publish revokes the user on this instance immediately and broadcasts it to the others.
+// Broadcasting is best-effort: a returned error means other instances may only notice
+// once their cached sessions expire.
+func (b *Bus) Publish(ctx context.Context, userID, reason string) error {
+	return b.publish(ctx, Event{UserID: userID, Reason: reason})
+}
+
+// PublishSession revokes one session of the user like Publish, leaving the user's other sessions
+// and connections alone
+func (b *Bus) PublishSession(ctx context.Context, userID, sessionID, reason string) error {
+	return b.publish(ctx, Event{UserID: userID, SessionID: sessionID, Reason: reason})
+}
+
+func (b *Bus) publish(ctx context.Context, event Event) error {
+	if b == nil {
+		return nil
+	}
+
+	event.RevokedAt = time.Now().UTC()
+	event.Origin = b.instanceID
+
+	b.apply(event)
+
+	// Single-node deployments have nobody to tell
+	if b.redisClient == nil {
+		return nil
+	}
+	if !redis.IsAvailable() {
+		return redis.ErrRedisUnavailable
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
+		return nil, b.redisClient.GetClient().Publish(ctx, b.redisClient.Key(Channel), payload).Err()
+	})
+	return err
+}
+
+// Subscribe registers fn to run for every revocation, local or remote.
+// The returned function removes the subscription.
+func (b *Bus) Subscribe(fn func(Event)) func() {
+	if b == nil {
+		return func() {}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = fn
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// Track registers a long-lived connection (WebSocket, SSE) for the user; closeFn is called
+// when the user is revoked. The returned function must be called when the connection ends.
+func (b *Bus) Track(userID string, closeFn func()) func() {
+	if b == nil {
+		return func() {}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	if b.conns[userID] == nil {
+		b.conns[userID] = make(map[int]func())
+	}
+	b.conns[userID][id] = closeFn
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.conns[userID], id)
+		if len(b.conns[userID]) == 0 {
+			delete(b.conns, userID)
+		}
+	}
+}
+
+// IsRevoked reports whether a token issued at issuedAt predates a revocation of the user
+func (b *Bus) IsRevoked(userID string, issuedAt time.Time) bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	revokedAt, ok := b.revoked[userID]
+	if !ok {
+		return false
+	}
+	if time.Since(revokedAt) > b.retention {
+		delete(b.revoked, userID)
+		return false
+	}
+
+	// Token iat has second precision, so a token from the same second is treated as revoked
+	return !issuedAt.After(revokedAt.Truncate(time.Second))
+}
+
+// IsSessionRevoked reports whether the session was revoked; it has no tokens issued since
+func (b *Bus) IsSessionRevoked(sessionID string) bool {
+	if b == nil || sessionID == "" {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	revokedAt, ok := b.sessions[sessionID]
+	if !ok {
+		return false
+	}
+	if time.Since(revokedAt) > b.retention {
+		delete(b.sessions, sessionID)
+		return false
+	}
+	return true
+}
+
+// apply records the revocation and tears down the user's connections and handlers' state. A
+// session revocation leaves the connections open, since they are not tracked by session.
+func (b *Bus) apply(event Event) {
+	b.mu.Lock()
+	b.pruneLocked()
+	if event.SessionID != "" {
+		b.sessions[event.SessionID] = event.RevokedAt
+		handlers := slices.Collect(maps.Values(b.handlers))
+		b.mu.Unlock()
+
+		for _, fn := range handlers {
+			fn(event)
+		}
+		logrus.Infof("Revoked session %s of user %s (%s)", event.SessionID, event.UserID, event.Reason)
+		return
+	}
+	b.revoked[event.UserID] = event.RevokedAt
+
+	closers := make([]func(), 0, len(b.conns[event.UserID]))
+	for _, closeFn := range b.conns[event.UserID] {
+		closers = append(closers, closeFn)
+	}
+	delete(b.conns, event.UserID)
+
+	handlers := make([]func(Event), 0, len(b.handlers))
+	for _, fn := range b.handlers {
+		handlers = append(handlers, fn)
+	}
+	b.mu.Unlock()
+
+	for _, closeFn := range closers {
+		closeFn()
+	}
+	for _, fn := range handlers {
+		fn(event)
+	}
+
+	logrus.Infof("Revoked sessions for user %s (%s), closed %d connections", event.UserID, event.Reason, len(closers))
+}
+
+// pruneLocked drops revocations older than any token still accepted
+func (b *Bus) pruneLocked() {
+	for userID, revokedAt := range b.revoked {
+		if time.Since(revokedAt) > b.retention {
+			delete(b.revoked, userID)
+		}
+	}
+	for sessionID, revokedAt := range b.sessions {
+		if time.Since(revokedAt) > b.retention {
+			delete(b.sessions, sessionID)
+		}
+	}
+}
+
+func newInstanceID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().Format(time.RFC3339Nano)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,74 @@
+package risk
+
+import (
+	"strconv"
+	"strings"
+
+	"app/src/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Location is where a login came from; empty fields are unknown
+type Location struct {
+	Country   string   `json:"country,omitempty"`
+	ASN       string   `json:"asn,omitempty"`
+	Latitude  *float64 `json:"lat,omitempty"`
+	Longitude *float64 `json:"lon,omitempty"`
+}
+
+// HasCoordinates reports whether the location can be used for travel checks
+func (l Location) HasCoordinates() bool {
+	return l.Latitude != nil && l.Longitude != nil
+}
+
+// GeoResolver locates a request; implement it to plug in a GeoIP database
+type GeoResolver interface {
+	Resolve(c *fiber.Ctx) Location
+}
+
+// HeaderResolver reads geo data from headers set by a CDN or proxy (e.g. Cloudflare's CF-IPCountry)
+type HeaderResolver struct {
+	CountryHeader   string
+	ASNHeader       string
+	LatitudeHeader  string
+	LongitudeHeader string
+}
+
+// NewHeaderResolver creates a resolver for the configured geo headers
+func NewHeaderResolver(cfg config.RiskConfig) *HeaderResolver {
+	return &HeaderResolver{
+		CountryHeader:   cfg.CountryHeader,
+		ASNHeader:       cfg.ASNHeader,
+		LatitudeHeader:  cfg.LatitudeHeader,
+		LongitudeHeader: cfg.LongitudeHeader,
+	}
+}
+
+func (r *HeaderResolver) Resolve(c *fiber.Ctx) Location {
+	location := Location{
+		Country: strings.ToUpper(header(c, r.CountryHeader)),
+		ASN:     header(c, r.ASNHeader),
+	}
+
+	// "XX" and "T1" are Cloudflare's unknown and Tor markers, not countries
+	if location.Country == "XX" || location.Country == "T1" {
+		location.Country = ""
+	}
+
+	lat, latErr := strconv.ParseFloat(header(c, r.LatitudeHeader), 64)
+	lon, lonErr := strconv.ParseFloat(header(c, r.LongitudeHeader), 64)
+	if latErr == nil && lonErr == nil {
+		location.Latitude = &lat
+		location.Longitude = &lon
+	}
+
+	return location
+}
+
+func header(c *fiber.Ctx, name string) string {
+	if name == "" {
+		return ""
+	}
+	return strings.TrimSpace(c.Get(name))
+}
@@ -0,0 +1,316 @@
+package risk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"app/src/cache"
+	"app/src/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Decision is the engine's verdict on a login
+type Decision string
+
+const (
+	DecisionAllow     Decision = "allow"
+	DecisionChallenge Decision = "challenge"
+	DecisionBlock     Decision = "block"
+)
+
+// maxHistory is the number of recent login locations kept per user
+const maxHistory = 20
+
+// minTravelDistance (km) below which travel checks are skipped, since IP geolocation is approximate
+const minTravelDistance = 100
+
+// Attempt is a login with a correct password, about to be assessed
+type Attempt struct {
+	UserID   string
+	IP       string
+	Location Location
+	Time     time.Time
+}
+
+// Assessment is the outcome of evaluating an attempt against the enabled rules
+type Assessment struct {
+	Score    int      `json:"score"`
+	Decision Decision `json:"decision"`
+	Signals  []string `json:"signals"`
+}
+
+// historyEntry is a previously confirmed login location
+type historyEntry struct {
+	Location
+	At time.Time `json:"at"`
+}
+
+// Engine scores logins for suspicious activity
+// A nil engine allows every login
+type Engine struct {
+	cfg   config.RiskConfig
+	store cache.Store
+	geo   GeoResolver
+	tor   *TorList
+}
+
+// NewEngine creates a risk engine; returns nil if detection is disabled
+// geo and tor are optional - rules that need them never fire without them
+func NewEngine(cfg config.RiskConfig, store cache.Store, geo GeoResolver, tor *TorList) *Engine {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return &Engine{
+		cfg:   cfg,
+		store: store,
+		geo:   geo,
+		tor:   tor,
+	}
+}
+
+// NewAttempt describes the login in the current request
+func (e *Engine) NewAttempt(c *fiber.Ctx, userID string) Attempt {
+	attempt := Attempt{
+		UserID: userID,
+		IP:     c.IP(),
+		Time:   time.Now().UTC(),
+	}
+	if e != nil && e.geo != nil {
+		attempt.Location = e.geo.Resolve(c)
+	}
+	return attempt
+}
+
+// Assess scores an attempt and decides whether to allow, challenge or block it
+func (e *Engine) Assess(attempt Attempt) Assessment {
+	assessment := Assessment{Decision: DecisionAllow, Signals: []string{}}
+	if e == nil {
+		return assessment
+	}
+
+	history := e.history(attempt.UserID)
+
+	for _, rule := range e.rules() {
+		if e.fires(rule, attempt, history) {
+			assessment.Score += e.cfg.Rules[rule]
+			assessment.Signals = append(assessment.Signals, rule)
+		}
+	}
+
+	switch {
+	case assessment.Score >= e.cfg.BlockScore:
+		assessment.Decision = DecisionBlock
+	case assessment.Score >= e.cfg.ChallengeScore:
+		assessment.Decision = DecisionChallenge
+	}
+
+	return assessment
+}
+
+// RecordSuccess remembers the attempt's location as known for the user
+func (e *Engine) RecordSuccess(attempt Attempt) {
+	if e == nil || !cache.IsStoreAvailable(e.store) {
+		return
+	}
+
+	history := append(e.history(attempt.UserID), historyEntry{Location: attempt.Location, At: attempt.Time})
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return
+	}
+	if err := e.store.Set(historyKey(attempt.UserID), data, e.cfg.HistoryTTL); err != nil {
+		logrus.Warnf("Failed to record login location: %v", err)
+	}
+}
+
+// Hold keeps a challenged attempt until its confirmation token is used, so the confirmed
+// location is learned rather than the location of whoever opens the email
+func (e *Engine) Hold(token string, attempt Attempt) {
+	if e == nil || !cache.IsStoreAvailable(e.store) {
+		return
+	}
+
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return
+	}
+	if err := e.store.Set(pendingKey(token), data, e.cfg.ConfirmExp); err != nil {
+		logrus.Warnf("Failed to hold challenged login: %v", err)
+	}
+}
+
+// Release returns and forgets the attempt held for a confirmation token
+func (e *Engine) Release(token string) (Attempt, bool) {
+	var attempt Attempt
+	if e == nil || !cache.IsStoreAvailable(e.store) {
+		return attempt, false
+	}
+
+	key := pendingKey(token)
+	data, err := e.store.Get(key)
+	if err != nil || data == nil {
+		return attempt, false
+	}
+	_ = e.store.Delete(key)
+
+	if err := json.Unmarshal(data, &attempt); err != nil {
+		return attempt, false
+	}
+	return attempt, true
+}
+
+// RecordFailure counts a failed login from ip towards the velocity rule
+func (e *Engine) RecordFailure(ip string) {
+	if e == nil || !cache.IsStoreAvailable(e.store) {
+		return
+	}
+	if _, ok := e.cfg.Rules[config.RiskRuleVelocity]; !ok {
+		return
+	}
+
+	key := velocityKey(ip)
+	if err := e.store.Set(key, []byte(strconv.Itoa(e.failures(ip)+1)), e.cfg.VelocityWindow); err != nil {
+		logrus.Warnf("Failed to record login failure: %v", err)
+	}
+}
+
+// rules returns the enabled rule names in a stable order
+func (e *Engine) rules() []string {
+	rules := make([]string, 0, len(e.cfg.Rules))
+	for rule := range e.cfg.Rules {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+func (e *Engine) fires(rule string, attempt Attempt, history []historyEntry) bool {
+	switch rule {
+	case config.RiskRuleNewCountry:
+		return isNew(history, attempt.Location.Country, func(entry historyEntry) string { return entry.Country })
+	case config.RiskRuleNewASN:
+		return isNew(history, attempt.Location.ASN, func(entry historyEntry) string { return entry.ASN })
+	case config.RiskRuleImpossibleTravel:
+		return e.impossibleTravel(attempt, history)
+	case config.RiskRuleTorExit:
+		return e.tor.Contains(attempt.IP)
+	case config.RiskRuleVelocity:
+		return e.failures(attempt.IP) >= e.cfg.VelocityMax
+	default:
+		logrus.Warnf("Unknown risk rule %q ignored", rule)
+		return false
+	}
+}
+
+// isNew reports whether value has never been seen before; a user without history has nothing to compare
+func isNew(history []historyEntry, value string, field func(historyEntry) string) bool {
+	if value == "" || len(history) == 0 {
+		return false
+	}
+	for _, entry := range history {
+		if field(entry) == value {
+			return false
+		}
+	}
+	return true
+}
+
+// impossibleTravel compares the attempt with the most recent located login
+func (e *Engine) impossibleTravel(attempt Attempt, history []historyEntry) bool {
+	if !attempt.Location.HasCoordinates() {
+		return false
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		previous := history[i]
+		if !previous.HasCoordinates() {
+			continue
+		}
+
+		distance := Distance(previous.Location, attempt.Location)
+		if distance < minTravelDistance {
+			return false
+		}
+
+		hours := attempt.Time.Sub(previous.At).Hours()
+		if hours <= 0 {
+			return true
+		}
+		return distance/hours > e.cfg.MaxTravelSpeed
+	}
+
+	return false
+}
+
+func (e *Engine) history(userID string) []historyEntry {
+	if !cache.IsStoreAvailable(e.store) {
+		return nil
+	}
+
+	data, err := e.store.Get(historyKey(userID))
+	if err != nil || data == nil {
+		return nil
+	}
+
+	var history []historyEntry
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil
+	}
+	return history
+}
+
+func (e *Engine) failures(ip string) int {
+	if !cache.IsStoreAvailable(e.store) {
+		return 0
+	}
+
+	data, err := e.store.Get(velocityKey(ip))
+	if err != nil || data == nil {
+		return 0
+	}
+	failures, _ := strconv.Atoi(string(data))
+	return failures
+}
+
+// Distance returns the great-circle distance between two located points in kilometres
+func Distance(a, b Location) float64 {
+	const earthRadius = 6371.0
+
+	lat1, lat2 := radians(*a.Latitude), radians(*b.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(*b.Longitude - *a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func historyKey(userID string) string {
+	return fmt.Sprintf("%shistory:%s", cache.RiskKeyPrefix, userID)
+}
+
+// pendingKey hashes the token so a cache dump can't be replayed as confirmation links
+func pendingKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%spending:%s", cache.RiskKeyPrefix, hex.EncodeToString(sum[:]))
+}
+
+func velocityKey(ip string) string {
+	return fmt.Sprintf("%svelocity:%s", cache.RiskKeyPrefix, ip)
+}
@@ -0,0 +1,132 @@
+package risk
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"app/src/httpclient"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TorList is an in-memory set of Tor exit node IPs refreshed from a downloadable list
+type TorList struct {
+	url    string
+	client *http.Client
+
+	mu  sync.RWMutex
+	ips map[string]struct{}
+}
+
+// NewTorList creates an empty list that downloads from url
+func NewTorList(url string) *TorList {
+	return &TorList{
+		url:    url,
+		client: httpclient.New("tor-list", httpclient.WithTimeout(30*time.Second)),
+		ips:    make(map[string]struct{}),
+	}
+}
+
+// Contains reports whether ip is a known exit node
+func (t *TorList) Contains(ip string) bool {
+	if t == nil {
+		return false
+	}
+
+	if parsed := net.ParseIP(ip); parsed != nil {
+		ip = parsed.String()
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	_, ok := t.ips[ip]
+	return ok
+}
+
+// Len returns the number of known exit nodes
+func (t *TorList) Len() int {
+	if t == nil {
+		return 0
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.ips)
+}
+
+// Refresh downloads the list and replaces the current set; on failure the old set is kept
+func (t *TorList) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download tor exit list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download tor exit list: status %d", resp.StatusCode)
+	}
+
+	ips, err := parseTorList(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.ips = ips
+	t.mu.Unlock()
+
+	return nil
+}
+
+// Start refreshes the list immediately and then every interval until ctx is cancelled
+func (t *TorList) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Refresh(ctx); err != nil {
+			logrus.Warnf("Tor exit list refresh failed: %v", err)
+		} else {
+			logrus.Debugf("Tor exit list refreshed (%d nodes)", t.Len())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseTorList reads one IP per line, skipping blanks, comments and invalid entries
+func parseTorList(r io.Reader) (map[string]struct{}, error) {
+	ips := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if ip := net.ParseIP(line); ip != nil {
+			ips[ip.String()] = struct{}{}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read tor exit list: %w", err)
+	}
+	return ips, nil
+}
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	middlewareCache "app/src/middleware/cache"
+	"app/src/model"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ACLRoutes(v1 fiber.Router, a service.ACLService, u service.UserService, s service.SessionService) {
+	aclController := controller.NewACLController(a)
+
+	// Only owners of a record (or admins) see and change who it is shared with
+	owner := m.RequirePermissionParam(a, "resourceType", "resourceId", model.ACLOwner)
+
+	entries := v1.Group("/acl/:resourceType/:resourceId", middlewareCache.Policy(middlewareCache.PolicyPrivate))
+
+	entries.Get("/", m.Auth(u, s), owner, aclController.GetEntries)
+	entries.Post("/", m.Auth(u, s), owner, aclController.GrantEntry)
+	entries.Delete("/:entryId", m.ValidateIDs("entryId"), m.Auth(u, s), owner, aclController.RevokeEntry)
+}
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"app/src/config"
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ActivityRoutes(v1 fiber.Router, a service.ActivityService, u service.UserService, s service.SessionService) {
+	activityController := controller.NewActivityController(a)
+
+	adminUsers := v1.Group("/admin/users")
+
+	// The timeline unions several tables per request, so cap how many run at once
+	bulkhead := m.NewBulkhead(config.BulkheadActivity, config.Bulkhead)
+
+	adminUsers.Get("/:userId/activity", m.ValidateIDs("userId"), m.Auth(u, s, "viewUserActivity"), bulkhead, activityController.GetUserActivity)
+}
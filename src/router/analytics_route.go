@@ -0,0 +1,18 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AnalyticsRoutes(v1 fiber.Router, as service.AnalyticsService, u service.UserService, s service.SessionService) {
+	analyticsController := controller.NewAnalyticsController(as)
+
+	adminAnalytics := v1.Group("/admin/analytics")
+
+	adminAnalytics.Get("/active-users", m.Auth(u, s, "viewAnalytics"), analyticsController.GetActiveUsers)
+	adminAnalytics.Get("/endpoints", m.Auth(u, s, "viewAnalytics"), analyticsController.GetEndpointVisitors)
+}
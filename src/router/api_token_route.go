@@ -0,0 +1,20 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func APITokenRoutes(v1 fiber.Router, a service.APITokenService, u service.UserService, s service.SessionService) {
+	apiTokenController := controller.NewAPITokenController(a)
+
+	// Tokens cannot mint or manage other tokens; these routes require an interactive session
+	tokens := v1.Group("/users/me/tokens", m.RequireInteractive())
+
+	tokens.Get("/", m.Auth(u, s, "manageApiTokens"), apiTokenController.GetTokens)
+	tokens.Post("/", m.Auth(u, s, "manageApiTokens"), apiTokenController.CreateToken)
+	tokens.Delete("/:tokenId", m.ValidateIDs("tokenId"), m.Auth(u, s, "manageApiTokens"), apiTokenController.RevokeToken)
+}
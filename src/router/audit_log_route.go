@@ -0,0 +1,17 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AuditLogRoutes(v1 fiber.Router, a service.AuditExportService, u service.UserService, s service.SessionService) {
+	auditLogController := controller.NewAuditLogController(a)
+
+	auditLogs := v1.Group("/admin/audit-logs")
+
+	auditLogs.Get("/export", m.Auth(u, s, "exportAuditLogs"), auditLogController.ExportAuditLogs)
+}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"app/src/cache"
+	"app/src/captcha"
 	"app/src/config"
 	"app/src/controller"
 	m "app/src/middleware"
@@ -9,23 +11,62 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// Per-email throttle names, shared with the rate limit admin routes that inspect them
+const (
+	forgotPasswordThrottleName = "forgot-password"
+	verificationThrottleName   = "send-verification-email"
+)
+
 func AuthRoutes(
 	v1 fiber.Router, a service.AuthService, u service.UserService,
-	t service.TokenService, e service.EmailService, s service.SessionService,
+	t service.TokenService, e service.EmailService, au service.AuditService,
+	g service.GoogleOAuthService, ap service.AppleOAuthService, b service.BillingService,
+	s service.SessionService, store cache.Store,
 ) {
-	authController := controller.NewAuthController(a, u, t, e)
-	config.GoogleConfig()
+	authController := controller.NewAuthController(a, u, t, e, au, g, ap, b)
+
+	verifier := captcha.New(config.Captcha)
+
+	// Per-email throttles stop one inbox being flooded from many IPs; repeat offenders must solve a CAPTCHA
+	forgotPasswordThrottle := m.NewTargetThrottle(
+		store, forgotPasswordThrottleName, config.Throttle, m.EmailFromBody, verifier.Hook("forgot_password"),
+	)
+	verificationThrottle := m.NewTargetThrottle(
+		store, verificationThrottleName, config.Throttle, m.EmailFromUser, verifier.Hook("verify_email"),
+	)
+
+	// Failed logins delay the next attempt for that email, however many IPs the attempts come from
+	loginDelay := m.NewLoginDelay(store, config.LoginDelay, m.EmailFromBody)
+
+	registerCaptcha := optional(config.Captcha.Register, verifier.Require("register"))
+	forgotPasswordCaptcha := optional(config.Captcha.ForgotPassword, verifier.Require("forgot_password"))
+	loginCaptcha := verifier.RequireAfterFailures(
+		store, "login", config.Captcha.LoginFailures, config.Throttle.Window, m.EmailFromBody,
+	)
 
 	auth := v1.Group("/auth")
 
-	auth.Post("/register", authController.Register)
-	auth.Post("/login", authController.Login)
+	auth.Post("/register", registerCaptcha, authController.Register)
+	auth.Post("/login", loginDelay, loginCaptcha, authController.Login)
 	auth.Post("/logout", authController.Logout)
 	auth.Post("/refresh-tokens", authController.RefreshTokens)
-	auth.Post("/forgot-password", authController.ForgotPassword)
+	auth.Post("/forgot-password", forgotPasswordCaptcha, forgotPasswordThrottle, authController.ForgotPassword)
 	auth.Post("/reset-password", authController.ResetPassword)
-	auth.Post("/send-verification-email", m.Auth(u, s), authController.SendVerificationEmail)
+	auth.Post("/send-verification-email", m.Auth(u, s), verificationThrottle, authController.SendVerificationEmail)
 	auth.Post("/verify-email", authController.VerifyEmail)
+	auth.Post("/confirm-login", authController.ConfirmLogin)
 	auth.Get("/google", authController.GoogleLogin)
 	auth.Get("/google-callback", authController.GoogleCallback)
+	auth.Get("/apple", authController.AppleLogin)
+	auth.Post("/apple-callback", authController.AppleCallback)
+}
+
+// optional returns handler when enabled, otherwise a pass-through
+func optional(enabled bool, handler fiber.Handler) fiber.Handler {
+	if enabled {
+		return handler
+	}
+	return func(c *fiber.Ctx) error {
+		return c.Next()
+	}
 }
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"app/src/controller"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func BillingRoutes(v1 fiber.Router, b service.BillingService) {
+	billingController := controller.NewBillingController(b)
+
+	billing := v1.Group("/billing")
+
+	// Stripe authenticates with the signature header, not a bearer token
+	billing.Post("/webhook", billingController.Webhook)
+}
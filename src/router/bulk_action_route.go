@@ -0,0 +1,19 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func BulkActionRoutes(v1 fiber.Router, b service.BulkActionService, u service.UserService, s service.SessionService) {
+	bulkActionController := controller.NewBulkActionController(b)
+
+	bulk := v1.Group("/admin/users/bulk")
+
+	bulk.Post("/", m.Auth(u, s, "manageUsers"), bulkActionController.SubmitBulkAction)
+	bulk.Get("/:jobId", m.ValidateIDs("jobId"), m.Auth(u, s, "manageUsers"), bulkActionController.GetBulkJob)
+	bulk.Get("/:jobId/report", m.ValidateIDs("jobId"), m.Auth(u, s, "manageUsers"), bulkActionController.GetBulkJobReport)
+}
@@ -0,0 +1,29 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CacheRoutes(
+	v1 fiber.Router, c service.CacheService, r service.CacheSkipRuleService, u service.UserService, s service.SessionService,
+) {
+	cacheController := controller.NewCacheController(c)
+	cacheSkipRuleController := controller.NewCacheSkipRuleController(r)
+
+	adminCache := v1.Group("/admin/cache")
+
+	adminCache.Get("/stats", m.Auth(u, s, "manageCache"), cacheController.GetStats)
+	adminCache.Post("/purge", m.Auth(u, s, "manageCache"), cacheController.Purge)
+	adminCache.Post("/purge/dry-run", m.Auth(u, s, "manageCache"), cacheController.DryRunPurge)
+	adminCache.Put("/state", m.Auth(u, s, "manageCache"), cacheController.SetState)
+
+	adminCache.Get("/skip-rules", m.Auth(u, s, "manageCache"), cacheSkipRuleController.GetRules)
+	adminCache.Post("/skip-rules", m.Auth(u, s, "manageCache"), cacheSkipRuleController.CreateRule)
+	adminCache.Delete(
+		"/skip-rules/:ruleId", m.ValidateIDs("ruleId"), m.Auth(u, s, "manageCache"), cacheSkipRuleController.DeleteRule,
+	)
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CircuitBreakerRoutes(v1 fiber.Router, c service.CircuitBreakerService, u service.UserService, s service.SessionService) {
+	circuitBreakerController := controller.NewCircuitBreakerController(c)
+
+	breaker := v1.Group("/admin/circuit-breaker")
+
+	breaker.Get("/", m.Auth(u, s, "manageCircuitBreaker"), circuitBreakerController.GetStatus)
+	breaker.Post("/trip", m.Auth(u, s, "manageCircuitBreaker"), circuitBreakerController.Trip)
+	breaker.Post("/reset", m.Auth(u, s, "manageCircuitBreaker"), circuitBreakerController.Reset)
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func DebugRoutes(v1 fiber.Router, d service.DebugService, u service.UserService, s service.SessionService) {
+	debugController := controller.NewDebugController(d)
+
+	adminDebug := v1.Group("/admin/debug")
+
+	adminDebug.Get("/captures/:requestId", m.Auth(u, s, "debugRequests"), debugController.GetCapture)
+	adminDebug.Get("/requests/:requestId", m.Auth(u, s, "debugRequests"), debugController.GetRequestTrace)
+}
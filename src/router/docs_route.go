@@ -4,12 +4,15 @@ import (
 	// initialize the Swagger documentation
 	_ "app/src/docs"
 
+	middlewareCache "app/src/middleware/cache"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
 )
 
 func DocsRoutes(v1 fiber.Router) {
-	docs := v1.Group("/docs")
+	// The same for everyone, so cached even when RESPONSE_CACHE_DEFAULT_POLICY is not public
+	docs := v1.Group("/docs", middlewareCache.Policy(middlewareCache.PolicyPublic))
 
 	docs.Get("/*", swagger.HandlerDefault)
 }
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func EmailDomainRoutes(v1 fiber.Router, e service.EmailDomainService, u service.UserService, s service.SessionService) {
+	emailDomainController := controller.NewEmailDomainController(e)
+
+	rules := v1.Group("/admin/email-domain-rules")
+
+	rules.Get("/", m.Auth(u, s, "manageEmailDomains"), emailDomainController.GetRules)
+	rules.Post("/", m.Auth(u, s, "manageEmailDomains"), emailDomainController.CreateRule)
+	rules.Delete("/:ruleId", m.ValidateIDs("ruleId"), m.Auth(u, s, "manageEmailDomains"), emailDomainController.DeleteRule)
+}
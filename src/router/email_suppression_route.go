@@ -0,0 +1,25 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func EmailSuppressionRoutes(
+	v1 fiber.Router, e service.EmailSuppressionService, u service.UserService, s service.SessionService,
+) {
+	emailSuppressionController := controller.NewEmailSuppressionController(e)
+
+	// Providers authenticate with the token query parameter, not a bearer token
+	v1.Post("/email/webhooks/:provider", emailSuppressionController.Webhook)
+
+	suppressions := v1.Group("/admin/email/suppressions")
+
+	suppressions.Get("/", m.Auth(u, s, "manageEmailSuppressions"), emailSuppressionController.GetSuppressions)
+	suppressions.Post("/", m.Auth(u, s, "manageEmailSuppressions"), emailSuppressionController.CreateSuppression)
+	suppressions.Delete("/:suppressionId", m.ValidateIDs("suppressionId"),
+		m.Auth(u, s, "manageEmailSuppressions"), emailSuppressionController.DeleteSuppression)
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"app/src/controller"
+	m "app/src/middleware"
 	"app/src/service"
 
 	"github.com/gofiber/fiber/v2"
@@ -10,6 +11,10 @@ import (
 func HealthCheckRoutes(v1 fiber.Router, h service.HealthCheckService) {
 	healthCheckController := controller.NewHealthCheckController(h)
 
-	healthCheck := v1.Group("/health-check")
+	// The detailed check reports dependency errors, so it stays off the public listener;
+	// /readyz is left for load balancer probes
+	healthCheck := v1.Group("/health-check", m.InternalOnly(), m.InternalBasicAuth())
 	healthCheck.Get("/", healthCheckController.Check)
+
+	v1.Get("/readyz", healthCheckController.Ready)
 }
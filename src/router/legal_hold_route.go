@@ -0,0 +1,19 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func LegalHoldRoutes(v1 fiber.Router, l service.LegalHoldService, u service.UserService, s service.SessionService) {
+	legalHoldController := controller.NewLegalHoldController(l)
+
+	holds := v1.Group("/admin/legal-holds")
+
+	holds.Get("/", m.Auth(u, s, "manageLegalHolds"), legalHoldController.GetLegalHolds)
+	holds.Post("/", m.Auth(u, s, "manageLegalHolds"), legalHoldController.PlaceLegalHold)
+	holds.Delete("/:userId", m.ValidateIDs("userId"), m.Auth(u, s, "manageLegalHolds"), legalHoldController.LiftLegalHold)
+}
@@ -0,0 +1,12 @@
+package router
+
+import (
+	"app/src/metrics"
+	m "app/src/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func MetricsRoutes(app *fiber.App) {
+	app.Get("/metrics", m.InternalOnly(), m.InternalBasicAuth(), metrics.Handler())
+}
@@ -0,0 +1,39 @@
+package router
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// phoneVerificationThrottleName names the per-account code throttle, also inspected by the rate limit admin routes
+const phoneVerificationThrottleName = "phone-verification"
+
+func NotificationRoutes(
+	v1 fiber.Router, n service.NotificationService, u service.UserService, s service.SessionService, store cache.Store,
+) {
+	notificationController := controller.NewNotificationController(n)
+
+	// Every code costs a text message, so they are limited per account like verification emails
+	phoneVerificationThrottle := m.NewTargetThrottle(
+		store, phoneVerificationThrottleName, config.Throttle, m.EmailFromUser, nil,
+	)
+
+	// Providers authenticate with their own signatures, not a bearer token
+	v1.Post("/notifications/callbacks/:provider", notificationController.StatusCallback)
+
+	notifications := v1.Group("/users/me/notifications", m.RequireInteractive())
+
+	notifications.Get("/", m.Auth(u, s), notificationController.GetSettings)
+	notifications.Put("/preferences/:kind", m.Auth(u, s), notificationController.UpdatePreference)
+	notifications.Put("/phone", m.Auth(u, s), phoneVerificationThrottle, notificationController.SetPhone)
+	notifications.Post("/phone/verify", m.Auth(u, s), notificationController.VerifyPhone)
+	notifications.Delete("/phone", m.Auth(u, s), notificationController.RemovePhone)
+	notifications.Get("/devices", m.Auth(u, s), notificationController.GetPushDevices)
+	notifications.Post("/devices", m.Auth(u, s), notificationController.RegisterPushDevice)
+	notifications.Delete("/devices/:deviceId", m.Auth(u, s), notificationController.RemovePushDevice)
+}
@@ -0,0 +1,32 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	middlewareCache "app/src/middleware/cache"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func OAuthProviderRoutes(v1 fiber.Router, o service.OAuthProviderService, u service.UserService, s service.SessionService) {
+	oauthController := controller.NewOAuthProviderController(o)
+
+	clients := v1.Group("/admin/oauth/clients")
+
+	clients.Get("/", m.Auth(u, s, "manageOAuthClients"), oauthController.GetClients)
+	clients.Post("/", m.Auth(u, s, "manageOAuthClients"), oauthController.CreateClient)
+	clients.Delete("/:clientId", m.ValidateIDs("clientId"), m.Auth(u, s, "manageOAuthClients"), oauthController.DeleteClient)
+
+	// Codes and tokens must never be cached; consent can only be given from an interactive session
+	oauth := v1.Group("/oauth", middlewareCache.Policy(middlewareCache.PolicyNoStore))
+
+	oauth.Get("/authorize", m.RequireInteractive(), m.Auth(u, s), oauthController.GetAuthorization)
+	oauth.Post("/authorize", m.RequireInteractive(), m.Auth(u, s), oauthController.Authorize)
+	oauth.Post("/token", oauthController.Token)
+
+	consents := v1.Group("/users/me/oauth/consents", m.RequireInteractive())
+
+	consents.Get("/", m.Auth(u, s), oauthController.GetConsents)
+	consents.Delete("/:clientId", m.ValidateIDs("clientId"), m.Auth(u, s), oauthController.RevokeConsent)
+}
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func PartnerRoutes(v1 fiber.Router, p service.PartnerService, u service.UserService, s service.SessionService) {
+	partnerController := controller.NewPartnerController(p)
+
+	partners := v1.Group("/admin/partners")
+
+	partners.Get("/", m.Auth(u, s, "managePartners"), partnerController.GetPartners)
+	partners.Post("/", m.Auth(u, s, "managePartners"), partnerController.CreatePartner)
+	partners.Delete("/:partnerId", m.ValidateIDs("partnerId"), m.Auth(u, s, "managePartners"), partnerController.DeletePartner)
+
+	// Partner API: every route here requires a signed request
+	partner := v1.Group("/partner", m.RequireSignature(p))
+
+	partner.Get("/me", partnerController.GetCurrentPartner)
+}
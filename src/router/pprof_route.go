@@ -0,0 +1,13 @@
+package router
+
+import (
+	m "app/src/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
+)
+
+// PprofRoutes serves Go's profiler at /debug/pprof; only registered with an internal listener
+func PprofRoutes(app *fiber.App) {
+	app.Use("/debug/pprof", m.InternalOnly(), m.InternalBasicAuth(), pprof.New())
+}
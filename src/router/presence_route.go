@@ -0,0 +1,25 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	middlewareCache "app/src/middleware/cache"
+	"app/src/policy"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func PresenceRoutes(v1 fiber.Router, p service.PresenceService, u service.UserService, s service.SessionService) {
+	presenceController := controller.NewPresenceController(p)
+	auth := m.GroupAuth("users", u, s)
+
+	// Only people are online; tokens acting for them keep no presence. The handshake is never cached.
+	v1.Get("/presence/ws", middlewareCache.Policy(middlewareCache.PolicyNoStore), m.WebSocketUpgrade(),
+		m.RequireInteractive(), m.Auth(u, s), presenceController.Socket())
+
+	// The /users group of UserRoutes already puts these behind its bulkhead and private caching
+	v1.Post("/users/presence", auth(policy.HasRights("getUsers")), presenceController.LookupPresence)
+	v1.Get("/users/:userId/presence", m.ValidateIDs("userId"),
+		auth(policy.AnyOf(policy.HasRights("getUsers"), policy.IsOwner("userId"))), presenceController.GetPresence)
+}
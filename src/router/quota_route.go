@@ -0,0 +1,21 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func QuotaRoutes(v1 fiber.Router, q service.QuotaService, u service.UserService, s service.SessionService) {
+	quotaController := controller.NewQuotaController(q)
+
+	adminQuotas := v1.Group("/admin/quotas")
+	userID, tokenID := m.ValidateIDs("userId"), m.ValidateIDs("tokenId")
+
+	adminQuotas.Get("/users/:userId", userID, m.Auth(u, s, "manageQuotas"), quotaController.GetUserQuota)
+	adminQuotas.Put("/users/:userId", userID, m.Auth(u, s, "manageQuotas"), quotaController.UpdateUserQuota)
+	adminQuotas.Get("/tokens/:tokenId", tokenID, m.Auth(u, s, "manageQuotas"), quotaController.GetTokenQuota)
+	adminQuotas.Put("/tokens/:tokenId", tokenID, m.Auth(u, s, "manageQuotas"), quotaController.UpdateTokenQuota)
+}
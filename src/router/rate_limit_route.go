@@ -0,0 +1,18 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RateLimitRoutes(v1 fiber.Router, r service.RateLimitService, u service.UserService, s service.SessionService) {
+	rateLimitController := controller.NewRateLimitController(r)
+
+	rateLimits := v1.Group("/admin/rate-limits")
+
+	rateLimits.Get("/", m.Auth(u, s, "manageRateLimits"), rateLimitController.GetRateLimits)
+	rateLimits.Delete("/", m.Auth(u, s, "manageRateLimits"), rateLimitController.ResetRateLimits)
+}
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReadOnlyPath is the admin switch of read-only mode, which must keep accepting writes under it
+const ReadOnlyPath = "/v1/admin/read-only"
+
+func ReadOnlyRoutes(v1 fiber.Router, r service.ReadOnlyService, u service.UserService, s service.SessionService) {
+	readOnlyController := controller.NewReadOnlyController(r)
+
+	adminReadOnly := v1.Group("/admin/read-only")
+
+	adminReadOnly.Get("/", m.Auth(u, s, "manageReadOnly"), readOnlyController.GetReadOnly)
+	adminReadOnly.Put("/", m.Auth(u, s, "manageReadOnly"), readOnlyController.SetReadOnly)
+}
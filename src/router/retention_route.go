@@ -0,0 +1,18 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RetentionRoutes(v1 fiber.Router, r service.RetentionService, u service.UserService, s service.SessionService) {
+	retentionController := controller.NewRetentionController(r)
+
+	adminRetention := v1.Group("/admin/retention")
+
+	adminRetention.Get("/", m.Auth(u, s, "manageRetention"), retentionController.GetReport)
+	adminRetention.Post("/purge", m.Auth(u, s, "manageRetention"), retentionController.Purge)
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RouteTableRoutes(v1 fiber.Router, r service.RouteTableService, u service.UserService, s service.SessionService) {
+	routeTableController := controller.NewRouteTableController(r)
+
+	v1.Get("/admin/routes", m.Auth(u, s, "viewRoutes"), routeTableController.GetRoutes)
+}
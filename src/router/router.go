@@ -1,13 +1,31 @@
 package router
 
 import (
+	"app/src/accesstoken"
+	"app/src/analytics"
 	"app/src/cache"
+	"app/src/chaos"
+	"app/src/clock"
 	"app/src/config"
+	"app/src/emaildomain"
+	"app/src/job"
+	"app/src/leader"
+	"app/src/locks"
 	"app/src/middleware"
 	middlewareCache "app/src/middleware/cache"
+	"app/src/presence"
+	"app/src/push"
+	"app/src/readonly"
 	"app/src/redis"
+	"app/src/revocation"
+	"app/src/risk"
+	"app/src/search"
 	"app/src/service"
+	"app/src/siem"
+	"app/src/sms"
 	"app/src/validation"
+	"context"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -34,14 +52,8 @@ func Routes(app *fiber.App, db *gorm.DB) {
 
 		// Initialize and start health monitor
 		if redisClient != nil {
-			healthMonitor := redis.InitHealthMonitor(30*time.Second, func(available bool) {
-				// State change callback - can be used for cache warm-up in Phase 2-6
-				if available {
-					logrus.Info("Redis state changed to available")
-				} else {
-					logrus.Warn("Redis state changed to unavailable")
-				}
-			})
+			// Components react to availability changes via redis.Subscribe
+			healthMonitor := redis.InitHealthMonitor(30 * time.Second)
 			if healthMonitor != nil {
 				redis.StartHealthMonitor()
 				logrus.Info("Redis health monitor started")
@@ -53,36 +65,84 @@ func Routes(app *fiber.App, db *gorm.DB) {
 		logrus.Info("Redis disabled or not configured")
 	}
 
-	healthCheckService := service.NewHealthCheckService(db, redis.GetHealthMonitor())
-	emailService := service.NewEmailService()
+	// Read-only mode, forced by READ_ONLY or switched by admins for every instance through Redis
+	readonly.Force(config.ReadOnly.Forced)
+	readOnlySwitch := readonly.NewSwitch(redisClient)
+	go readOnlySwitch.Start(context.Background(), config.ReadOnly.SyncInterval)
+
+	// Elect a single instance to run background workers (always leader without Redis)
+	elector := leader.NewElector(redisClient, "background-workers", time.Duration(config.LeaderLeaseTTL)*time.Second)
+	go elector.Start()
+
+	// Writes of background jobs are fenced by their lock, so a holder whose lock expired cannot overwrite the next one
+	if err := db.Use(locks.NewGormPlugin()); err != nil {
+		logrus.Warnf("Failed to install lock fencing hooks: %v", err)
+	}
+
+	healthCheckService := service.NewHealthCheckService(db, redis.GetHealthMonitor(), elector)
 
 	// Load rate limit configuration
 	rateLimitConfig := config.LoadRateLimiterConfig()
 
+	// Initialize cache store (Redis, or in-memory for single-node deployments)
+	store := cache.NewStore(config.CacheBackend, redisClient)
+
 	// Initialize session service
 	var sessionService service.SessionService
-	if redisClient != nil {
-		sessionService = service.NewSessionService(redisClient)
+	if store != nil {
+		sessionService = service.NewSessionService(store)
 		logrus.Info("Session service initialized")
+
+		if config.SessionCacheWarmup > 0 {
+			// A shared cache is warmed by one instance at a time; in-memory caches each by their own
+			var warmupLocker *locks.Locker
+			if store.Backend() == cache.BackendRedis {
+				warmupLocker = locks.NewLocker(redisClient)
+			}
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
+				err := warmupLocker.WithLock(ctx, "session-cache-warmup", 30*time.Second, func(ctx context.Context) error {
+					warmed, err := service.WarmSessionCache(ctx, db, sessionService, config.SessionCacheWarmup, clock.System)
+					if err != nil {
+						return err
+					}
+					logrus.Infof("Session cache warmed for %d users", warmed)
+					return nil
+				})
+
+				switch {
+				case errors.Is(err, locks.ErrLockNotAcquired):
+					logrus.Debug("Session cache warm-up skipped - running on another instance")
+				case err != nil:
+					logrus.Warnf("Session cache warm-up failed: %v", err)
+				}
+			}()
+		}
 	} else {
-		logrus.Warn("Session service disabled (Redis unavailable)")
+		logrus.Warn("Session service disabled (cache store unavailable)")
 	}
 
 	// Initialize cache invalidator
 	var cacheInvalidator *cache.CacheInvalidator
-	if redisClient != nil {
-		cacheInvalidator = cache.NewCacheInvalidator(redisClient)
+	if store != nil {
+		cacheInvalidator = cache.NewCacheInvalidator(store)
 		if cacheInvalidator != nil {
 			logrus.Info("Cache invalidator initialized")
+
+			// Writes to cached entities drop their entries themselves, see cache.InvalidationRules
+			if err := db.Use(cache.NewGormPlugin(cacheInvalidator)); err != nil {
+				logrus.Warnf("Failed to install cache invalidation hooks: %v", err)
+			}
 		}
 	} else {
-		logrus.Info("Cache invalidator disabled (Redis unavailable)")
+		logrus.Info("Cache invalidator disabled (cache store unavailable)")
 	}
 
 	// Initialize rate limiter middleware
 	var rateLimiterMiddleware fiber.Handler
-	if redisClient != nil {
-		rateLimiterMiddleware = middleware.NewRateLimiterMiddleware(redisClient, rateLimitConfig)
+	if store != nil {
+		rateLimiterMiddleware = middleware.NewRateLimiterMiddleware(store, rateLimitConfig)
 		if rateLimiterMiddleware != nil {
 			logrus.Infof("Rate limiter initialized (max: %d requests per %v for unauthenticated, %d per %v for authenticated)",
 				rateLimitConfig.DefaultMax, rateLimitConfig.DefaultWindow,
@@ -91,31 +151,307 @@ func Routes(app *fiber.App, db *gorm.DB) {
 			logrus.Info("Rate limiter disabled (configuration disabled)")
 		}
 	} else {
-		logrus.Info("Rate limiter disabled (Redis unavailable)")
+		logrus.Info("Rate limiter disabled (cache store unavailable)")
+	}
+
+	circuitBreakerService := service.NewCircuitBreakerService()
+	// Admins switch the response cache on and off for every instance through the cache store
+	responseCacheSwitch := cache.NewResponseCacheSwitch(store)
+	// Initialize negative cache for not-found user lookups
+	negativeCache := cache.NewNegativeCache(store, time.Duration(config.NegativeCacheTTL)*time.Second)
+
+	// Broadcast user revocations so every instance drops stale auth state immediately
+	revocations := revocation.NewBus(redisClient, time.Duration(config.JWTAccessExp)*time.Minute)
+	go revocations.Start(context.Background())
+	middleware.EnableRevocation(revocations)
+
+	// Opaque access tokens live in the cache store, so revoked users lose them at once. Every instance
+	// handles the revocation and deletes the same keys, which is harmless.
+	if config.AccessTokenMode == config.AccessTokenOpaque {
+		if _, ok := store.(cache.Indexer); ok {
+			opaqueTokens := accesstoken.NewStore(store)
+			accesstoken.Enable(opaqueTokens)
+			revocations.Subscribe(func(event revocation.Event) {
+				var err error
+				if event.SessionID != "" {
+					err = opaqueTokens.RevokeSession(context.Background(), event.UserID, event.SessionID)
+				} else {
+					err = opaqueTokens.RevokeUser(context.Background(), event.UserID)
+				}
+				if err != nil {
+					logrus.Warnf("Failed to revoke access tokens of user %s: %v", event.UserID, err)
+				}
+			})
+			logrus.Info("Access tokens: opaque")
+		} else {
+			logrus.Warn("Opaque access tokens need a cache store, issuing JWTs instead")
+		}
+	}
+
+	// Usage metering of requests and business events, rolled up daily for reporting and billing
+	usageService := service.NewUsageService(db, validate, store, config.Metering, clock.System)
+
+	auditService := service.NewAuditService(db)
+	if config.Metering.Enabled {
+		if _, ok := store.(cache.Counter); ok {
+			auditService = service.NewMeteredAuditService(auditService, usageService)
+			middleware.EnableUsageMetering(usageService)
+
+			usageRollupJob := job.NewUsageRollupJob(
+				usageService, locks.NewLocker(redisClient), elector, config.Metering.RollupInterval,
+			)
+			go usageRollupJob.Start()
+			logrus.Infof("Usage metering enabled (rolled up every %s)", config.Metering.RollupInterval)
+		} else {
+			logrus.Warn("Usage metering disabled (cache store unavailable)")
+		}
+	}
+
+	// Cache purges are audited, so the cache service comes after the audit service
+	cacheService := service.NewCacheService(
+		validate, auditService, cache.NewCacheAdmin(store, cacheInvalidator), responseCacheSwitch,
+	)
+
+	// Sign-up email domain rules, optionally with a periodically refreshed disposable domain list
+	var disposableList *emaildomain.DisposableList
+	if config.EmailDomains.BlockDisposable && config.EmailDomains.DisposableListURL != "" {
+		disposableList = emaildomain.NewDisposableList(config.EmailDomains.DisposableListURL)
+		go disposableList.Start(context.Background(), config.EmailDomains.DisposableRefresh)
+	}
+	emailDomainService := service.NewEmailDomainService(db, validate, auditService, disposableList)
+
+	// Outbound email skips suppressed recipients and is throttled per recipient and across instances
+	emailSuppressionService := service.NewEmailSuppressionService(db, validate, auditService)
+	emailService := service.NewEmailService(store, emailSuppressionService, clock.System)
+
+	// Notifications go out by email and, with a text message provider or push credentials, by SMS,
+	// WhatsApp and push
+	smsProvider := sms.New(config.SMS)
+	if smsProvider != nil {
+		logrus.Infof("Text notifications enabled (%s)", smsProvider.Name())
+	}
+	pushSenders := push.New(config.Push)
+	for platform := range pushSenders {
+		logrus.Infof("Push notifications enabled (%s)", platform)
+	}
+	notificationService := service.NewNotificationService(
+		db, validate, store, emailService, smsProvider, pushSenders, auditService, clock.System,
+	)
+	// Users are notified of new sign-ins and password changes as they are audited
+	auditService = service.NewNotifyingAuditService(auditService, notificationService)
+
+	// Requests kept out of the response cache, from the environment and admin rules in the database
+	responseCacheConfig := config.LoadResponseCacheConfig()
+	go responseCacheSwitch.Start(context.Background(), responseCacheConfig.SyncInterval)
+	cacheSkipRuleService := service.NewCacheSkipRuleService(db, validate, auditService, responseCacheConfig)
+	if err := cacheSkipRuleService.Reload(context.Background()); err != nil {
+		logrus.Warnf("Failed to load cache skip rules, using configured rules only: %v", err)
+	}
+	go cacheSkipRuleService.Watch(context.Background(), service.CacheSkipRulesReload)
+
+	// Online presence from WebSocket heartbeats, shared through Redis when it is configured
+	presenceTracker := presence.NewTracker(redisClient, config.Presence, clock.System)
+	go presenceTracker.Start(context.Background(), config.Presence.HeartbeatInterval)
+	presenceService := service.NewPresenceService(db, validate, presenceTracker, revocations, clock.System)
+
+	userService := service.NewUserService(
+		db, validate, sessionService, cacheInvalidator, negativeCache, revocations, auditService, emailDomainService,
+	)
+	tokenService := service.NewTokenService(db, validate, userService, sessionService, revocations, clock.System)
+	apiTokenService := service.NewAPITokenService(db, validate, userService, clock.System)
+	middleware.EnableAPITokens(apiTokenService)
+	oauthProviderService := service.NewOAuthProviderService(db, validate, store, userService, auditService, clock.System)
+	middleware.EnableOAuthClients(oauthProviderService)
+	serviceAccountService := service.NewServiceAccountService(db, validate, userService, auditService, clock.System)
+	middleware.EnableClientCertificates(serviceAccountService)
+	middleware.EnableTagRateLimits(store, config.TagRateLimits)
+
+	// Score logins for suspicious activity (new country/ASN, impossible travel, Tor, stuffing velocity)
+	var torList *risk.TorList
+	if config.Risk.Enabled && config.Risk.TorListURL != "" {
+		if _, ok := config.Risk.Rules[config.RiskRuleTorExit]; ok {
+			torList = risk.NewTorList(config.Risk.TorListURL)
+			go torList.Start(context.Background(), config.Risk.TorRefresh)
+		}
 	}
+	riskEngine := risk.NewEngine(config.Risk, store, risk.NewHeaderResolver(config.Risk), torList)
+	riskService := service.NewRiskService(
+		db, validate, riskEngine, userService, tokenService, notificationService, auditService,
+	)
 
-	userService := service.NewUserService(db, validate, sessionService, cacheInvalidator)
-	tokenService := service.NewTokenService(db, validate, userService, sessionService)
-	authService := service.NewAuthService(db, validate, userService, tokenService, cacheInvalidator, sessionService)
+	// Emails that must not be lost, such as the verification email on registration, are retried from the outbox
+	emailOutboxService := service.NewEmailOutboxService(db, emailService, config.EmailOutbox, clock.System)
+	emailOutboxJob := job.NewEmailOutboxJob(emailOutboxService, config.EmailOutbox.PollInterval)
+	go emailOutboxJob.Start()
+
+	authService := service.NewAuthService(
+		db, validate, userService, tokenService, cacheInvalidator, sessionService, negativeCache, riskService, auditService,
+		emailDomainService, emailOutboxService,
+	)
+
+	// Start expired token cleanup, guarded by a distributed lock across instances
+	if config.TokenCleanupInterval > 0 {
+		tokenCleanupJob := job.NewTokenCleanupJob(
+			tokenService, locks.NewLocker(redisClient), elector, time.Duration(config.TokenCleanupInterval)*time.Minute,
+		)
+		go tokenCleanupJob.Start()
+		logrus.Infof("Token cleanup job started (every %d minutes)", config.TokenCleanupInterval)
+	}
+
+	// Track when sessions were last used; the job flushes buffered activity and ends idle sessions
+	sessionActivityService := service.NewSessionActivityService(db, store, config.SessionActivity, clock.System)
+	if config.SessionActivity.Enabled {
+		middleware.EnableSessionActivity(sessionActivityService)
+
+		sessionActivityJob := job.NewSessionActivityJob(
+			sessionActivityService, locks.NewLocker(redisClient), elector, config.SessionActivity.FlushInterval,
+		)
+		go sessionActivityJob.Start()
+		logrus.Infof("Session activity job started (every %s)", config.SessionActivity.FlushInterval)
+	}
+
+	// Plan-based request quotas, counted in the cache store and persisted for usage billing
+	quotaService := service.NewQuotaService(
+		db, validate, store, userService, sessionService, auditService, config.Quota, clock.System,
+	)
+	if config.Quota.Enabled {
+		if _, ok := store.(cache.Counter); ok {
+			middleware.EnableQuotas(quotaService)
+
+			quotaPersistJob := job.NewQuotaPersistJob(
+				quotaService, locks.NewLocker(redisClient), elector, config.Quota.PersistInterval,
+			)
+			go quotaPersistJob.Start()
+			logrus.Infof("Request quotas enabled (usage persisted every %s)", config.Quota.PersistInterval)
+		} else {
+			logrus.Warn("Request quotas disabled (cache store unavailable)")
+		}
+	}
+
+	// Bulk user actions are queued in the database and run by whichever instance claims them
+	bulkActionService := service.NewBulkActionService(
+		db, validate, sessionService, cacheInvalidator, negativeCache, revocations, auditService,
+		config.BulkActions, clock.System,
+	)
+	bulkActionJob := job.NewBulkActionJob(bulkActionService, config.BulkActions.PollInterval)
+	go bulkActionJob.Start()
+
+	// Weekly security digest for admins, rendered from the audit log
+	if config.SecurityDigest.Enabled {
+		securityDigestJob := job.NewSecurityDigestJob(
+			service.NewSecurityDigestService(db, emailService, auditService), locks.NewLocker(redisClient), elector,
+			config.SecurityDigest.Weekday, config.SecurityDigest.Hour,
+		)
+		go securityDigestJob.Start()
+		logrus.Infof("Security digest job started (%s at %02d:00 UTC)", config.SecurityDigest.Weekday, config.SecurityDigest.Hour)
+	}
+
+	// Delete audit logs, login history, notifications and exports once past their retention
+	retentionService := service.NewRetentionService(db, auditService, config.Retention, clock.System)
+	if config.Retention.Enabled {
+		retentionJob := job.NewRetentionJob(
+			retentionService, locks.NewLocker(redisClient), elector, config.Retention.Interval,
+		)
+		go retentionJob.Start()
+		logrus.Infof("Retention job started (every %s, dry run: %t)", config.Retention.Interval, config.Retention.DryRun)
+	}
+
+	// Stream the audit log to a SIEM from the leader
+	if config.SIEM.Sink != "" {
+		if sink, err := siem.NewSink(config.SIEM); err != nil {
+			logrus.Warnf("SIEM forwarding disabled: %v", err)
+		} else {
+			siemForwardJob := job.NewSIEMForwardJob(
+				siem.NewForwarder(db, sink, config.SIEM, clock.System), sink, elector, config.SIEM.PollInterval,
+			)
+			go siemForwardJob.Start()
+			logrus.Infof("SIEM forwarding enabled (%s)", config.SIEM.Sink)
+		}
+	}
+
+	// Unique visitors counted in Redis HyperLogLogs and saved to the database, so counts survive Redis losing them
+	analyticsTracker := analytics.NewTracker(redisClient, clock.System)
+	analyticsService := service.NewAnalyticsService(db, validate, analyticsTracker, clock.System)
+	trackVisitors := false
+	if config.Analytics.Enabled {
+		if redisClient != nil {
+			trackVisitors = true
+			go analyticsTracker.Start(context.Background(), config.Analytics.FlushInterval)
+
+			analyticsPersistJob := job.NewAnalyticsPersistJob(
+				analyticsService, locks.NewLocker(redisClient), elector, config.Analytics.PersistInterval,
+			)
+			go analyticsPersistJob.Start()
+			logrus.Infof("Unique visitor analytics enabled (persisted every %s)", config.Analytics.PersistInterval)
+		} else {
+			logrus.Warn("Unique visitor analytics disabled (Redis unavailable)")
+		}
+	}
 
 	// Initialize cache middleware
 	var cacheMiddleware fiber.Handler
-	if redisClient != nil {
-		cacheMiddleware = middlewareCache.NewResponseCacheMiddleware(redisClient)
+	if store != nil {
+		cacheMiddleware = middlewareCache.NewResponseCacheMiddleware(store, responseCacheConfig)
 		if cacheMiddleware != nil {
 			logrus.Info("Cache middleware initialized")
 		}
 	} else {
-		logrus.Info("Cache middleware disabled (Redis unavailable)")
+		logrus.Info("Cache middleware disabled (cache store unavailable)")
 	}
 
+	// Prometheus scrape endpoint lives outside /v1 so it is not rate limited
+	if config.MetricsEnabled {
+		MetricsRoutes(app)
+	}
+	if config.Internal.Pprof {
+		PprofRoutes(app)
+	}
+
+	// Lookups repeated within a request are served from memory
+	app.Use(middleware.Memo())
+
 	v1 := app.Group("/v1")
 
+	// With an internal listener, the admin API is not served through the public one
+	v1.Use("/admin", middleware.InternalOnly())
+
+	// Count unique visitors per endpoint and active users, once the route is matched
+	if trackVisitors {
+		v1.Use(middleware.Analytics(analyticsTracker))
+	}
+
+	// Refuse writes in read-only mode; admins must still be able to switch it off
+	v1.Use(middleware.ReadOnly(append([]string{ReadOnlyPath}, config.ReadOnly.Allow...)))
+
 	// Apply rate limiter middleware to all /v1 routes
 	if rateLimiterMiddleware != nil {
 		v1.Use(rateLimiterMiddleware)
 	}
 
+	// Capture sanitized bodies for sampled or explicitly flagged requests
+	if config.DebugCapture.Enabled && store != nil {
+		v1.Use(middleware.NewDebugCapture(store, config.DebugCapture))
+		logrus.Infof("Debug body capture enabled (%.2f%% sampled)", config.DebugCapture.SamplePercent)
+	}
+
+	// Copy a sample of sanitized requests to a shadow deployment, e.g. to try an upgrade on live traffic
+	if config.TrafficMirror.Enabled {
+		v1.Use(middleware.NewTrafficMirror(config.TrafficMirror))
+		logrus.Infof("Traffic mirroring enabled (%.2f%% to %s)", config.TrafficMirror.SamplePercent, config.TrafficMirror.TargetURL)
+	}
+
+	// Inject configured faults into requests, Redis and the database (never in production)
+	if config.Chaos.Enabled {
+		chaos.Load(config.Chaos)
+		v1.Use(middleware.Chaos())
+		logrus.Warnf("Chaos mode enabled for %d dependencies", len(config.Chaos.Faults))
+	}
+
+	// Coalesce identical concurrent GETs that missed the response cache
+	if config.RequestDedupEnabled {
+		v1.Use(middlewareCache.NewRequestDedupMiddleware())
+	}
+
 	// Apply cache middleware to all routes
 	// The middleware's Next() function will skip auth endpoints and write operations automatically
 	if cacheMiddleware != nil {
@@ -123,11 +459,60 @@ func Routes(app *fiber.App, db *gorm.DB) {
 	}
 
 	HealthCheckRoutes(v1, healthCheckService)
-	AuthRoutes(v1, authService, userService, tokenService, emailService, sessionService)
-	UserRoutes(v1, userService, tokenService, sessionService)
+	googleOAuthService := service.NewGoogleOAuthService(store, userService, config.GoogleConfig())
+	appleOAuthService := service.NewAppleOAuthService(store, userService, config.Apple, service.AppleEndpoint)
+	billingService := service.NewBillingService(db, sessionService, config.Billing)
+	AuthRoutes(
+		v1, authService, userService, tokenService, emailService, auditService,
+		googleOAuthService, appleOAuthService, billingService, sessionService, store,
+	)
+	APITokenRoutes(v1, apiTokenService, userService, sessionService)
+	OAuthProviderRoutes(v1, oauthProviderService, userService, sessionService)
+	UserRoutes(v1, userService, tokenService, sessionService, store)
+	UserTagRoutes(v1, service.NewUserTagService(db, validate, userService, sessionService, cacheInvalidator),
+		userService, sessionService)
+	ACLRoutes(v1, service.NewACLService(db, validate, auditService), userService, sessionService)
+	CacheRoutes(v1, cacheService, cacheSkipRuleService, userService, sessionService)
+	CircuitBreakerRoutes(v1, circuitBreakerService, userService, sessionService)
+	DebugRoutes(v1, service.NewDebugService(db, store), userService, sessionService)
+	ActivityRoutes(v1, service.NewActivityService(db, validate, userService), userService, sessionService)
+	SessionActivityRoutes(v1, sessionActivityService, userService, sessionService)
+	EmailDomainRoutes(v1, emailDomainService, userService, sessionService)
+	EmailSuppressionRoutes(v1, emailSuppressionService, userService, sessionService)
+	NotificationRoutes(v1, notificationService, userService, sessionService, store)
+	PresenceRoutes(v1, presenceService, userService, sessionService)
+	QuotaRoutes(v1, quotaService, userService, sessionService)
+	BillingRoutes(v1, billingService)
+	UsageRoutes(v1, usageService, userService, sessionService)
+	RateLimitRoutes(v1, service.NewRateLimitService(
+		validate, store, auditService,
+		middleware.NewRateLimitInspector(store, rateLimitConfig),
+		middleware.NewThrottleInspector(store, forgotPasswordThrottleName, config.Throttle),
+		middleware.NewThrottleInspector(store, verificationThrottleName, config.Throttle),
+		middleware.NewThrottleInspector(store, phoneVerificationThrottleName, config.Throttle),
+		middleware.NewLoginDelayInspector(store, config.LoginDelay),
+	), userService, sessionService)
+	PartnerRoutes(v1, service.NewPartnerService(db, validate, store, auditService, clock.System), userService, sessionService)
+	ServiceAccountRoutes(v1, serviceAccountService, apiTokenService, userService, sessionService)
+	BulkActionRoutes(v1, bulkActionService, userService, sessionService)
+	RouteTableRoutes(v1, service.NewRouteTableService(app), userService, sessionService)
+	// Pass an external engine implementing search.Engine here to search outside Postgres
+	searchService := service.NewSearchService(validate, search.NewPostgres(db, clock.System))
+	SearchRoutes(v1, searchService, userService, sessionService)
+	RetentionRoutes(v1, retentionService, userService, sessionService)
+	LegalHoldRoutes(v1, service.NewLegalHoldService(db, validate, auditService), userService, sessionService)
+	ReadOnlyRoutes(v1, service.NewReadOnlyService(validate, readOnlySwitch, auditService, clock.System),
+		userService, sessionService)
+	AuditLogRoutes(v1, service.NewAuditExportService(db, validate, auditService), userService, sessionService)
+	AnalyticsRoutes(v1, analyticsService, userService, sessionService)
 	// TODO: add another routes here...
 
 	if !config.IsProd {
 		DocsRoutes(v1)
 	}
+
+	// Frontend and SPA fallback (outside /v1, so not rate limited)
+	if config.Static.Enabled {
+		StaticRoutes(app)
+	}
 }
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"app/src/config"
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SearchRoutes(v1 fiber.Router, search service.SearchService, u service.UserService, s service.SessionService) {
+	searchController := controller.NewSearchController(search)
+
+	// Each search runs a full-text query per entity type, so cap how many run at once
+	bulkhead := m.NewBulkhead(config.BulkheadSearch, config.Bulkhead)
+
+	v1.Get("/admin/search", m.Auth(u, s, "adminSearch"), bulkhead, searchController.Search)
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ServiceAccountRoutes(
+	v1 fiber.Router, sa service.ServiceAccountService, t service.APITokenService,
+	u service.UserService, s service.SessionService,
+) {
+	serviceAccountController := controller.NewServiceAccountController(sa, t)
+
+	accounts := v1.Group("/admin/service-accounts", m.RequireInteractive())
+
+	accounts.Get("/", m.Auth(u, s, "manageServiceAccounts"), serviceAccountController.GetServiceAccounts)
+	accounts.Post("/", m.Auth(u, s, "manageServiceAccounts"), serviceAccountController.CreateServiceAccount)
+	accounts.Delete("/:serviceAccountId", m.ValidateIDs("serviceAccountId"), m.Auth(u, s, "manageServiceAccounts"),
+		serviceAccountController.DeleteServiceAccount)
+
+	tokens := accounts.Group("/:serviceAccountId/tokens", m.ValidateIDs("serviceAccountId"))
+
+	tokens.Get("/", m.Auth(u, s, "manageServiceAccounts"), serviceAccountController.GetTokens)
+	tokens.Post("/", m.Auth(u, s, "manageServiceAccounts"), serviceAccountController.CreateToken)
+	tokens.Delete("/:tokenId", m.ValidateIDs("tokenId"), m.Auth(u, s, "manageServiceAccounts"),
+		serviceAccountController.RevokeToken)
+}
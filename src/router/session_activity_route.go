@@ -0,0 +1,19 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SessionActivityRoutes(
+	v1 fiber.Router, a service.SessionActivityService, u service.UserService, s service.SessionService,
+) {
+	sessionActivityController := controller.NewSessionActivityController(a)
+
+	adminSessions := v1.Group("/admin/sessions")
+
+	adminSessions.Get("/activity", m.Auth(u, s, "viewUserActivity"), sessionActivityController.GetActivity)
+}
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"app/src/config"
+	"app/src/static"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// StaticRoutes serves the frontend; register it after the API routes so they take precedence
+func StaticRoutes(app *fiber.App) {
+	handler, err := static.New(config.Static)
+	if err != nil {
+		logrus.Errorf("Static file serving disabled: %v", err)
+		return
+	}
+
+	app.Use(handler)
+	logrus.Info("Static file serving enabled")
+}
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	middlewareCache "app/src/middleware/cache"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func UsageRoutes(v1 fiber.Router, us service.UsageService, u service.UserService, s service.SessionService) {
+	usageController := controller.NewUsageController(us)
+
+	v1.Get("/usage", middlewareCache.Policy(middlewareCache.PolicyPrivate), m.Auth(u, s), usageController.GetUsage)
+
+	adminUsage := v1.Group("/admin/usage")
+
+	adminUsage.Get("/", m.Auth(u, s, "viewUsage"), usageController.GetReport)
+	adminUsage.Get("/users/:userId", m.ValidateIDs("userId"), m.Auth(u, s, "viewUsage"), usageController.GetUserUsage)
+}
@@ -1,21 +1,55 @@
 package router
 
 import (
+	"app/src/cache"
+	"app/src/config"
 	"app/src/controller"
 	m "app/src/middleware"
+	middlewareCache "app/src/middleware/cache"
+	"app/src/policy"
 	"app/src/service"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func UserRoutes(v1 fiber.Router, u service.UserService, t service.TokenService, s service.SessionService) {
+func UserRoutes(
+	v1 fiber.Router, u service.UserService, t service.TokenService, s service.SessionService, store cache.Store,
+) {
 	userController := controller.NewUserController(u, t)
+	auth := m.GroupAuth("users", u, s)
 
-	user := v1.Group("/users")
+	// Availability checks are public, so they are limited per IP to slow down username enumeration
+	usernameCheckThrottle := m.NewTargetThrottle(
+		store, "check-username", config.Usernames.CheckThrottle, m.ClientIP, nil,
+	)
 
-	user.Get("/", m.Auth(u, s, "getUsers"), userController.GetUsers)
-	user.Post("/", m.Auth(u, s, "manageUsers"), userController.CreateUser)
-	user.Get("/:userId", m.Auth(u, s, "getUsers"), userController.GetUserByID)
-	user.Patch("/:userId", m.Auth(u, s, "manageUsers"), userController.UpdateUser)
-	user.Delete("/:userId", m.Auth(u, s, "manageUsers"), userController.DeleteUser)
+	// Users may read and manage their own account without the admin rights
+	readUser := policy.AnyOf(policy.HasRights("getUsers"), policy.IsOwner("userId"))
+	manageUser := policy.AnyOf(policy.HasRights("manageUsers"), policy.IsOwner("userId"))
+
+	// Malformed IDs are rejected before the ownership policies compare them
+	userID := m.ValidateIDs("userId")
+
+	// Third-party clients may read profiles; with profile:read only their user's own
+	readProfiles := m.OAuthScope("users:read")
+	readProfile := m.OAuthScope("profile:read", "users:read")
+
+	// Responses depend on the caller's rights, and a cached one would be served before auth runs; this
+	// also covers the tag and API token routes under /users
+	user := v1.Group("/users",
+		m.NewBulkhead(config.BulkheadUsers, config.Bulkhead), middlewareCache.Policy(middlewareCache.PolicyPrivate),
+	)
+
+	user.Get("/", readProfiles, auth(policy.HasRights("getUsers")), userController.GetUsers)
+	user.Post("/", auth(policy.HasRights("manageUsers")), userController.CreateUser)
+	user.Get("/check-username", usernameCheckThrottle, userController.CheckUsername)
+	user.Get("/handle/:username", readProfiles, auth(policy.HasRights("getUsers")), userController.GetUserByHandle)
+	user.Get("/:userId", userID, readProfile, auth(readUser), userController.GetUserByID)
+	user.Patch("/:userId", userID, auth(manageUser), userController.UpdateUser)
+	user.Delete("/:userId", userID, auth(manageUser), userController.DeleteUser)
+	user.Post("/:userId/suspend", userID, auth(policy.HasRights("manageUsers")), userController.SuspendUser)
+	user.Post("/:userId/reactivate", userID, auth(policy.HasRights("manageUsers")), userController.ReactivateUser)
+	user.Get("/:userId/revisions", userID, auth(readUser), userController.GetUserRevisions)
+	user.Post("/:userId/revisions/:revisionId/restore", m.ValidateIDs("userId", "revisionId"),
+		auth(policy.HasRights("manageUsers")), userController.RestoreUserRevision)
 }
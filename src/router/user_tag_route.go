@@ -0,0 +1,20 @@
+package router
+
+import (
+	"app/src/controller"
+	m "app/src/middleware"
+	"app/src/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func UserTagRoutes(v1 fiber.Router, t service.UserTagService, u service.UserService, s service.SessionService) {
+	userTagController := controller.NewUserTagController(t)
+
+	// Registered under /users, so the users bulkhead applies here too
+	tags := v1.Group("/users/:userId/tags", m.ValidateIDs("userId"))
+
+	tags.Get("/", m.Auth(u, s, "getUsers"), userTagController.GetTags)
+	tags.Post("/", m.Auth(u, s, "manageUsers"), userTagController.AddTags)
+	tags.Delete("/:tag", m.Auth(u, s, "manageUsers"), userTagController.RemoveTag)
+}
@@ -0,0 +1,156 @@
+// Package routetable lists the routes registered on a Fiber app with the middleware chain each one
+// runs, so teams extending the boilerplate can audit what is exposed from the app actually built
+// rather than from the router code. Middleware enforcing access, rate limits or caching describe
+// their policy with Describe; other handlers are listed by function name.
+package routetable
+
+import (
+	"app/src/response"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Kinds of described middleware; the route table collects auth, rate limit and cache policies
+// into their own columns
+const (
+	KindAuth       = "auth"
+	KindRateLimit  = "rate_limit"
+	KindCache      = "cache"
+	KindMiddleware = "middleware"
+)
+
+// Info is what the route table shows for a middleware
+type Info struct {
+	Kind string
+	// Detail is the middleware's policy, e.g. the rights it requires
+	Detail string
+	// DetailFor, when set, gives the policy for one route instead, e.g. its cache TTL
+	DetailFor func(method, path string) string
+}
+
+// described holds the Info of handlers by closure address; unlike the code pointer it tells apart
+// two handlers built by the same constructor
+var described sync.Map
+
+// Describe records what the route table shows for a handler and returns the handler. Handlers are
+// built once per route, so the records live as long as the app.
+func Describe(handler fiber.Handler, info Info) fiber.Handler {
+	if handler != nil {
+		described.Store(closure(handler), info)
+	}
+	return handler
+}
+
+func closure(handler fiber.Handler) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&handler))
+}
+
+// Table returns the routes of app by path. The chain of a route is the handlers of the middleware
+// registered with Use before it under a matching prefix, then its own, as Fiber runs them. HEAD
+// routes are left out since Fiber adds one for every GET.
+func Table(app *fiber.App) []response.Route {
+	// Fiber does not export which routes are middleware, but leaves them out of GetRoutes(true),
+	// which keeps the stack order
+	regular := app.GetRoutes(true)
+
+	routes := []response.Route{}
+	for _, stack := range app.Stack() {
+		var middleware []*fiber.Route
+		for _, route := range stack {
+			if len(regular) == 0 || !same(route, &regular[0]) {
+				middleware = append(middleware, route)
+				continue
+			}
+
+			regular = regular[1:]
+			if route.Method != fiber.MethodHead && len(route.Handlers) > 0 {
+				routes = append(routes, newRoute(route, middleware))
+			}
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Path < routes[j].Path
+	})
+	return routes
+}
+
+func newRoute(route *fiber.Route, middleware []*fiber.Route) response.Route {
+	var handlers []fiber.Handler
+	for _, use := range middleware {
+		if matches(use.Path, route.Path) {
+			handlers = append(handlers, use.Handlers...)
+		}
+	}
+	handlers = append(handlers, route.Handlers...)
+	last := len(handlers) - 1
+
+	result := response.Route{
+		Method:     route.Method,
+		Path:       route.Path,
+		Name:       route.Name,
+		Access:     []string{},
+		RateLimits: []string{},
+		Cache:      "off",
+		Middleware: []response.RouteStep{},
+		Handler:    handlerName(handlers[last]),
+	}
+
+	for _, handler := range handlers[:last] {
+		step := response.RouteStep{Name: handlerName(handler), Kind: KindMiddleware}
+		if value, ok := described.Load(closure(handler)); ok {
+			info := value.(Info)
+			step.Kind, step.Detail = info.Kind, info.Detail
+			if info.DetailFor != nil {
+				step.Detail = info.DetailFor(route.Method, route.Path)
+			}
+		}
+
+		switch step.Kind {
+		case KindAuth:
+			result.Access = append(result.Access, step.Detail)
+		case KindRateLimit:
+			result.RateLimits = append(result.RateLimits, step.Detail)
+		case KindCache:
+			result.Cache = step.Detail
+		}
+		result.Middleware = append(result.Middleware, step)
+	}
+
+	return result
+}
+
+// same reports whether two routes are copies of one, which share their handlers
+func same(a, b *fiber.Route) bool {
+	if a.Method != b.Method || a.Path != b.Path || len(a.Handlers) != len(b.Handlers) {
+		return false
+	}
+	return len(a.Handlers) == 0 || &a.Handlers[0] == &b.Handlers[0]
+}
+
+// matches reports whether middleware registered under prefix runs for path, by Fiber's plain prefix
+// match
+func matches(prefix, path string) bool {
+	prefix = strings.TrimRight(strings.ToLower(prefix), "/")
+	return prefix == "" || strings.HasPrefix(strings.ToLower(path), prefix)
+}
+
+// handlerName names a handler after the function that built it, e.g. "middleware.AuthPolicy" for
+// its closure or "controller.(*UserController).GetUsers" for a method value
+func handlerName(handler fiber.Handler) string {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	name = name[strings.LastIndex(name, "/")+1:]
+
+	return closureSuffix.ReplaceAllString(name, "")
+}
+
+// closureSuffix ends the names of closures: pkg.Func.func1, or pkg.Func.func1.2 when nested
+var closureSuffix = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
@@ -0,0 +1,47 @@
+// Package saga runs operations that span several writes without a shared transaction. Each step
+// may register a compensation; when a later step fails, the compensations of the steps done so far
+// run in reverse order, so the failed operation leaves no partial state behind.
+package saga
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Step is one write of a saga. Undo reverts it and is nil for steps with nothing to revert.
+type Step struct {
+	Name string
+	Do   func(ctx context.Context) error
+	Undo func(ctx context.Context) error
+}
+
+// Run performs the steps in order and returns the error of the first one that fails, after
+// compensating the steps before it. Compensations run even when ctx is cancelled; one that fails
+// is logged and the others still run.
+func Run(ctx context.Context, log *logrus.Logger, name string, steps ...Step) error {
+	for i, step := range steps {
+		err := step.Do(ctx)
+		if err == nil {
+			continue
+		}
+
+		log.Warnf("Saga %s failed at %s, compensating: %v", name, step.Name, err)
+		compensate(context.WithoutCancel(ctx), log, name, steps[:i])
+		return err
+	}
+
+	return nil
+}
+
+func compensate(ctx context.Context, log *logrus.Logger, name string, done []Step) {
+	for i := len(done) - 1; i >= 0; i-- {
+		if done[i].Undo == nil {
+			continue
+		}
+
+		if err := done[i].Undo(ctx); err != nil {
+			log.Errorf("Saga %s failed to compensate %s, manual cleanup needed: %+v", name, done[i].Name, err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Package schema checks that the database schema fits this build, for blue/green deploys where the
+// old and the new release run against the same database. Migrations are expand-only by default: code
+// keeps working on a database migrated past the version it was written for. A migration old code
+// cannot run against (dropping or renaming what it uses, or requiring what it never sets) is
+// breaking and records itself in schema_breaking_changes:
+//
+//	INSERT INTO schema_breaking_changes (version, reason) VALUES
+//	    (20261101000000, 'users.legacy_role is dropped')
+//	ON CONFLICT (version) DO NOTHING;
+//
+// Code is then compatible with a database at or past its version, up to the next breaking migration.
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"app/src/database/migrations"
+
+	"gorm.io/gorm"
+)
+
+// Migration is one migration of the embedded set
+type Migration struct {
+	Version int64
+	Name    string
+	// Breaking is why code written before the migration cannot run after it; empty when it can
+	Breaking string
+}
+
+var (
+	migrationFile  = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+	breakingInsert = regexp.MustCompile(`(?is)INSERT\s+INTO\s+schema_breaking_changes\b.*?VALUES(.*?)(;|$)`)
+	breakingValue  = regexp.MustCompile(`\(\s*(\d+)\s*,\s*'((?:[^']|'')*)'\s*\)`)
+)
+
+// Migrations returns the migrations this build was written for, oldest first
+func Migrations() ([]Migration, error) {
+	return Parse(migrations.FS)
+}
+
+// Parse reads the up migrations of files and which of them are breaking
+func Parse(files fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(files, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Migration
+	byVersion := map[int64]int{}
+	breaking := map[int64]string{}
+	for _, name := range names {
+		match := migrationFile.FindStringSubmatch(path.Base(name))
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", name, err)
+		}
+		if _, ok := byVersion[version]; ok {
+			return nil, fmt.Errorf("migration version %d is used twice", version)
+		}
+		byVersion[version] = len(found)
+		found = append(found, Migration{Version: version, Name: match[2]})
+
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, insert := range breakingInsert.FindAllStringSubmatch(string(content), -1) {
+			for _, value := range breakingValue.FindAllStringSubmatch(insert[1], -1) {
+				marked, _ := strconv.ParseInt(value[1], 10, 64)
+				if marked > version {
+					return nil, fmt.Errorf("migration %s marks the later migration %d as breaking", name, marked)
+				}
+				breaking[marked] = strings.ReplaceAll(value[2], "''", "'")
+			}
+		}
+	}
+
+	for version, reason := range breaking {
+		index, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("migration %d is marked as breaking but does not exist", version)
+		}
+		found[index].Breaking = reason
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Version < found[j].Version })
+	return found, nil
+}
+
+// Expected is the schema version this build was written for, its last migration
+func Expected(all []Migration) int64 {
+	if len(all) == 0 {
+		return 0
+	}
+	return all[len(all)-1].Version
+}
+
+// Version returns the last migration applied to db, refusing a database left dirty by a failed one
+func Version(ctx context.Context, db *gorm.DB) (int64, error) {
+	var migration struct {
+		Version int64
+		Dirty   bool
+	}
+	result := db.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&migration)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, errors.New("database is not migrated")
+	}
+	if migration.Dirty {
+		return 0, fmt.Errorf("database migration %d is dirty, fix it and force the version", migration.Version)
+	}
+	return migration.Version, nil
+}
+
+// Compatibility is how code written for one schema version fits a database at another
+type Compatibility struct {
+	Code     int64
+	Database int64
+	// Reason explains an incompatibility; empty when compatible
+	Reason string
+}
+
+// Compatible reports whether the code can run against the database
+func (c Compatibility) Compatible() bool {
+	return c.Reason == ""
+}
+
+// Compare tells whether code written for version code runs against a database at version database,
+// given the breaking migrations between them
+func Compare(code, database int64, breaking []Migration) Compatibility {
+	compatibility := Compatibility{Code: code, Database: database}
+	if database < code {
+		compatibility.Reason = fmt.Sprintf(
+			"database is at schema version %d but this build needs %d, run the migrations first", database, code)
+		return compatibility
+	}
+
+	for _, migration := range breaking {
+		if migration.Breaking != "" && migration.Version > code && migration.Version <= database {
+			compatibility.Reason = fmt.Sprintf(
+				"database is at schema version %d, past migration %d which breaks builds written for %d: %s",
+				database, migration.Version, code, migration.Breaking)
+			return compatibility
+		}
+	}
+	return compatibility
+}
+
+// Check compares this build with the schema of db. The breaking migrations are read from the
+// database, as a database ahead of the build may have been migrated by a newer release.
+func Check(ctx context.Context, db *gorm.DB) (Compatibility, error) {
+	all, err := Migrations()
+	if err != nil {
+		return Compatibility{}, err
+	}
+	code := Expected(all)
+
+	database, err := Version(ctx, db)
+	if err != nil {
+		return Compatibility{Code: code}, err
+	}
+
+	var breaking []Migration
+	if database > code {
+		err := db.WithContext(ctx).Raw(
+			"SELECT version, reason AS breaking FROM schema_breaking_changes "+
+				"WHERE version > ? AND version <= ? ORDER BY version", code, database,
+		).Scan(&breaking).Error
+		if err != nil {
+			return Compatibility{Code: code, Database: database}, err
+		}
+	}
+
+	return Compare(code, database, breaking), nil
+}
+
+// Range is how far code written for a migration and a database migrated to it reach
+type Range struct {
+	Migration
+	// OldestCode is the oldest version the code running against a database at this migration may be
+	// written for
+	OldestCode int64
+	// NewestDatabase is the last version code written for this migration runs against, 0 for any
+	NewestDatabase int64
+}
+
+// Matrix returns the compatibility range of every migration, for planning which releases can run
+// side by side during a deploy
+func Matrix(all []Migration) []Range {
+	ranges := make([]Range, len(all))
+	oldest := int64(0)
+	for i, migration := range all {
+		if i == 0 || migration.Breaking != "" {
+			oldest = migration.Version
+		}
+		ranges[i] = Range{Migration: migration, OldestCode: oldest}
+	}
+
+	newest := int64(0)
+	for i := len(all) - 1; i >= 0; i-- {
+		ranges[i].NewestDatabase = newest
+		if all[i].Breaking != "" && i > 0 {
+			newest = all[i-1].Version
+		}
+	}
+	return ranges
+}
@@ -0,0 +1,135 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"strings"
+
+	"app/src/clock"
+	"app/src/config"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Each query ranks its rows by ts_rank over a 'simple' text search document (no stemming, so names
+// and identifiers match as typed), plus 1 for an exact match of an ID, email, IP or token prefix and
+// 0.5 for a prefix match of a name or email. @id is the query as a UUID, NULL when it is not one.
+var typeSQL = map[string]string{
+	TypeUser: `
+SELECT id::text AS id, id AS user_id, name AS title, email AS detail, created_at AS occurred_at,
+	ts_rank(to_tsvector('simple', name || ' ' || COALESCE(username, '') || ' ' || email),
+			websearch_to_tsquery('simple', @text))
+		+ CASE WHEN id = @id OR LOWER(email) = LOWER(@text) OR LOWER(username) = LOWER(@text) THEN 1 ELSE 0 END
+		+ CASE WHEN email ILIKE @prefix OR name ILIKE @prefix OR username ILIKE @prefix THEN 0.5 ELSE 0 END AS score
+FROM users
+WHERE to_tsvector('simple', name || ' ' || COALESCE(username, '') || ' ' || email)
+		@@ websearch_to_tsquery('simple', @text)
+	OR id = @id OR email ILIKE @prefix OR name ILIKE @prefix OR username ILIKE @prefix
+ORDER BY score DESC, created_at DESC
+LIMIT @limit`,
+
+	// The document matches idx_audit_logs_search. Actions are indexed whole and split on dots and
+	// underscores, so "login" finds login.failed; IPs are found through the document too.
+	TypeAudit: `
+SELECT id::text AS id, user_id, action AS title, COALESCE(ip, '') AS detail, created_at AS occurred_at,
+	ts_rank(to_tsvector('simple', action || ' ' || translate(action, '._', '  ') || ' ' || COALESCE(ip, '') || ' ' ||
+			COALESCE(user_agent, '') || ' ' || metadata::text), websearch_to_tsquery('simple', @text))
+		+ CASE WHEN id = @id OR user_id = @id OR request_id = @text OR ip = @text THEN 1 ELSE 0 END AS score
+FROM audit_logs
+WHERE to_tsvector('simple', action || ' ' || translate(action, '._', '  ') || ' ' || COALESCE(ip, '') || ' ' ||
+		COALESCE(user_agent, '') || ' ' || metadata::text) @@ websearch_to_tsquery('simple', @text)
+	OR id = @id OR user_id = @id OR request_id = @text
+ORDER BY score DESC, created_at DESC
+LIMIT @limit`,
+
+	// A whole token pasted into the search finds its record by prefix
+	TypeToken: `
+SELECT id::text AS id, user_id, name AS title, prefix AS detail, created_at AS occurred_at,
+	ts_rank(to_tsvector('simple', name), websearch_to_tsquery('simple', @text))
+		+ CASE WHEN id = @id OR user_id = @id OR starts_with(@text, prefix) THEN 1 ELSE 0 END
+		+ CASE WHEN name ILIKE @prefix THEN 0.5 ELSE 0 END AS score
+FROM api_tokens
+WHERE to_tsvector('simple', name) @@ websearch_to_tsquery('simple', @text)
+	OR id = @id OR user_id = @id OR starts_with(@text, prefix) OR name ILIKE @prefix
+ORDER BY score DESC, created_at DESC
+LIMIT @limit`,
+
+	// A live session is its unconsumed, unexpired refresh token; sessions are found by their owner
+	TypeSession: `
+SELECT COALESCE(t.session_id, t.id)::text AS id, t.user_id, u.email AS title, u.name AS detail,
+	COALESCE(t.last_active_at, t.created_at) AS occurred_at,
+	ts_rank(to_tsvector('simple', u.name || ' ' || u.email), websearch_to_tsquery('simple', @text))
+		+ CASE WHEN COALESCE(t.session_id, t.id) = @id OR t.user_id = @id OR LOWER(u.email) = LOWER(@text)
+			THEN 1 ELSE 0 END
+		+ CASE WHEN u.email ILIKE @prefix OR u.name ILIKE @prefix THEN 0.5 ELSE 0 END AS score
+FROM tokens t
+	JOIN users u ON u.id = t.user_id
+WHERE t.type = @refresh AND t.consumed_at IS NULL AND t.expires > @now
+	AND (to_tsvector('simple', u.name || ' ' || u.email) @@ websearch_to_tsquery('simple', @text)
+		OR COALESCE(t.session_id, t.id) = @id OR t.user_id = @id OR u.email ILIKE @prefix OR u.name ILIKE @prefix)
+ORDER BY score DESC, occurred_at DESC
+LIMIT @limit`,
+}
+
+type postgres struct {
+	db    *gorm.DB
+	clock clock.Clock
+}
+
+// NewPostgres searches the app database with Postgres full-text search
+func NewPostgres(db *gorm.DB, clk clock.Clock) Engine {
+	return &postgres{db: db, clock: clock.OrSystem(clk)}
+}
+
+// Search runs one query per entity type, each returning at most query.Limit hits, and merges them
+func (p *postgres) Search(ctx context.Context, query Query) ([]Hit, error) {
+	types := query.Types
+	if len(types) == 0 {
+		types = Types
+	}
+
+	// Wildcards typed in the query match themselves
+	prefix := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query.Text) + "%"
+	var id any
+	if parsed, err := uuid.Parse(query.Text); err == nil {
+		id = parsed
+	}
+	args := []any{
+		sql.Named("text", query.Text),
+		sql.Named("id", id),
+		sql.Named("prefix", prefix),
+		sql.Named("limit", query.Limit),
+		sql.Named("refresh", config.TokenTypeRefresh),
+		sql.Named("now", p.clock.Now()),
+	}
+
+	hits := []Hit{}
+	for _, entityType := range Types {
+		if !slices.Contains(types, entityType) {
+			continue
+		}
+
+		var rows []Hit
+		if err := p.db.WithContext(ctx).Raw(typeSQL[entityType], args...).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			rows[i].Type = entityType
+		}
+		hits = append(hits, rows...)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].OccurredAt.After(hits[j].OccurredAt)
+	})
+	if len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+	return hits, nil
+}
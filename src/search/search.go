@@ -0,0 +1,49 @@
+// Package search finds records of several entity types from one query, for the admin search.
+// Engine is the extension point: NewPostgres searches the app database with full-text search, and
+// an external engine (OpenSearch, Meilisearch, ...) can be used instead by implementing Engine.
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity types
+const (
+	TypeUser    = "user"
+	TypeAudit   = "audit"
+	TypeToken   = "token"
+	TypeSession = "session"
+)
+
+// Types are all the entity types, searched when a query names none
+var Types = []string{TypeUser, TypeAudit, TypeToken, TypeSession}
+
+// Query is free text matched against the given entity types
+type Query struct {
+	Text string
+	// Types limits the search to some entity types; empty searches them all
+	Types []string
+	// Limit caps the hits returned across all types
+	Limit int
+}
+
+// Hit is one matching record
+type Hit struct {
+	Type string
+	ID   string
+	// UserID is the user the record belongs to, if any
+	UserID *uuid.UUID
+	Title  string
+	Detail string
+	// Score ranks hits across types; exact matches of an ID, email, IP or token prefix rank first
+	Score      float64
+	OccurredAt time.Time
+}
+
+// Engine runs searches, returning hits best first
+type Engine interface {
+	Search(ctx context.Context, query Query) ([]Hit, error)
+}
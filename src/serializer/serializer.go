@@ -0,0 +1,95 @@
+package serializer
+
+import (
+	"fmt"
+	"strings"
+
+	"app/src/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Serializer turns models into JSON objects, applying per-role field visibility
+// and client-selected sparse fieldsets (?fields=id,name)
+type Serializer[T any] struct {
+	fields     []string
+	extract    func(item *T) map[string]interface{}
+	visibility map[string][]string
+}
+
+// New creates a serializer. fields lists every field extract can produce; visibility restricts
+// fields to the given roles, and fields without an entry are visible to everyone.
+func New[T any](fields []string, extract func(item *T) map[string]interface{}, visibility map[string][]string) *Serializer[T] {
+	return &Serializer[T]{
+		fields:     fields,
+		extract:    extract,
+		visibility: visibility,
+	}
+}
+
+// Fields parses the ?fields= query parameter, returning nil when absent (all visible fields)
+func (s *Serializer[T]) Fields(c *fiber.Ctx) ([]string, error) {
+	raw := strings.TrimSpace(c.Query("fields"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var selected []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !s.known(field) {
+			return nil, validation.FieldErrors{"fields": fmt.Sprintf("Unknown field '%s'", field)}
+		}
+		selected = append(selected, field)
+	}
+
+	return selected, nil
+}
+
+// One serializes a single item for the given role, limited to the selected fields when non-empty
+func (s *Serializer[T]) One(item *T, role string, selected []string) map[string]interface{} {
+	values := s.extract(item)
+	result := make(map[string]interface{}, len(values))
+
+	for _, field := range s.fields {
+		if len(selected) > 0 && !contains(selected, field) {
+			continue
+		}
+		if !s.visible(field, role) {
+			continue
+		}
+		result[field] = values[field]
+	}
+
+	return result
+}
+
+// Many serializes a list of items
+func (s *Serializer[T]) Many(items []T, role string, selected []string) []map[string]interface{} {
+	results := make([]map[string]interface{}, len(items))
+	for i := range items {
+		results[i] = s.One(&items[i], role, selected)
+	}
+	return results
+}
+
+func (s *Serializer[T]) known(field string) bool {
+	return contains(s.fields, field)
+}
+
+func (s *Serializer[T]) visible(field, role string) bool {
+	roles, restricted := s.visibility[field]
+	return !restricted || contains(roles, role)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
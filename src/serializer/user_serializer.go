@@ -0,0 +1,44 @@
+package serializer
+
+import (
+	"app/src/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// User serializes users; account metadata is only visible to admins
+var User = New(
+	[]string{"id", "name", "username", "email", "role", "type", "verified_email", "is_active", "timezone", "tags", "created_at", "updated_at"},
+	func(user *model.User) map[string]interface{} {
+		return map[string]interface{}{
+			"id":             user.ID,
+			"name":           user.Name,
+			"username":       user.Username,
+			"email":          user.Email,
+			"role":           user.Role,
+			"type":           user.Type,
+			"verified_email": user.VerifiedEmail,
+			"is_active":      user.IsActive,
+			"timezone":       user.Timezone,
+			"tags":           user.TagNames(),
+			"created_at":     user.CreatedAt,
+			"updated_at":     user.UpdatedAt,
+		}
+	},
+	map[string][]string{
+		"type":           {"admin"},
+		"verified_email": {"admin"},
+		"is_active":      {"admin"},
+		"tags":           {"admin"},
+		"created_at":     {"admin"},
+		"updated_at":     {"admin"},
+	},
+)
+
+// Role returns the authenticated caller's role, or "" for anonymous requests
+func Role(c *fiber.Ctx) string {
+	if user, ok := c.Locals("user").(*model.User); ok && user != nil {
+		return user.Role
+	}
+	return ""
+}
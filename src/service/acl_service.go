@@ -0,0 +1,286 @@
+package service
+
+import (
+	"app/src/dbretry"
+	"app/src/model"
+	"app/src/utils"
+	"app/src/utils/id"
+	"app/src/validation"
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// resourceTypePattern matches the resource types records can be shared under, such as "document"
+var resourceTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+
+// maxACLResourceID matches the acl_entries.resource_id column
+const maxACLResourceID = 64
+
+type ACLService interface {
+	Check(ctx context.Context, user *model.User, resourceType, resourceID, permission string) (bool, error)
+	ResourceIDs(ctx context.Context, user *model.User, resourceType, permission string) ([]string, error)
+	Grant(ctx context.Context, resourceType, resourceID, principal, permission string, grantedBy *uuid.UUID) error
+	DeleteResource(ctx context.Context, resourceType, resourceID string) error
+	ListEntries(c *fiber.Ctx, resourceType, resourceID string) ([]model.ACLEntry, error)
+	AddEntry(c *fiber.Ctx, resourceType, resourceID string, req *validation.GrantACL) (*model.ACLEntry, error)
+	RemoveEntry(c *fiber.Ctx, resourceType, resourceID, entryID string) error
+}
+
+type aclService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	AuditService AuditService
+}
+
+// NewACLService shares individual records of application resources with users, roles and tags.
+// Apps grant the creator ownership with Grant and guard routes with middleware.RequirePermission.
+func NewACLService(db *gorm.DB, validate *validator.Validate, auditService AuditService) ACLService {
+	return &aclService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		AuditService: auditService,
+	}
+}
+
+// Check reports whether the user holds the permission on the record, directly or through their role
+// or tags; a stronger permission grants the weaker ones
+func (s *aclService) Check(
+	ctx context.Context, user *model.User, resourceType, resourceID, permission string,
+) (bool, error) {
+	var found []uuid.UUID
+	err := dbretry.Read(ctx, s.DB, func(tx *gorm.DB) error {
+		return tx.Model(new(model.ACLEntry)).
+			Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+			Where("principal IN ? AND permission IN ?", user.Principals(), model.ImpliedBy(permission)).
+			Limit(1).
+			Pluck("id", &found).Error
+	})
+
+	if err != nil {
+		s.Log.Errorf("Failed to check ACL: %+v", err)
+		return false, err
+	}
+
+	return len(found) > 0, nil
+}
+
+// ResourceIDs returns the IDs of the records of the type the user holds the permission on, so
+// list endpoints can filter their query by them
+func (s *aclService) ResourceIDs(
+	ctx context.Context, user *model.User, resourceType, permission string,
+) ([]string, error) {
+	ids := []string{}
+	err := dbretry.Read(ctx, s.DB, func(tx *gorm.DB) error {
+		return tx.Model(new(model.ACLEntry)).
+			Where("resource_type = ?", resourceType).
+			Where("principal IN ? AND permission IN ?", user.Principals(), model.ImpliedBy(permission)).
+			Distinct().
+			Order("resource_id").
+			Pluck("resource_id", &ids).Error
+	})
+
+	if err != nil {
+		s.Log.Errorf("Failed to list ACL resources: %+v", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Grant gives the principal the permission on the record, such as ownership to the user who created
+// it; granting it again changes nothing
+func (s *aclService) Grant(
+	ctx context.Context, resourceType, resourceID, principal, permission string, grantedBy *uuid.UUID,
+) error {
+	if !slices.Contains(model.ACLPermissions, permission) {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid permission")
+	}
+
+	principal, err := s.normalize(resourceType, resourceID, principal)
+	if err != nil {
+		return err
+	}
+
+	entry := &model.ACLEntry{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Principal:    principal,
+		Permission:   permission,
+		GrantedBy:    grantedBy,
+	}
+	if err := s.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(entry).Error; err != nil {
+		s.Log.Errorf("Failed to grant ACL entry: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteResource drops every entry of the record; call it when the record is deleted
+func (s *aclService) DeleteResource(ctx context.Context, resourceType, resourceID string) error {
+	err := s.DB.WithContext(ctx).
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Delete(new(model.ACLEntry)).Error
+
+	if err != nil {
+		s.Log.Errorf("Failed to delete ACL entries: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListEntries returns who the record is shared with, oldest grant first
+func (s *aclService) ListEntries(c *fiber.Ctx, resourceType, resourceID string) ([]model.ACLEntry, error) {
+	if _, err := s.normalize(resourceType, resourceID, ""); err != nil {
+		return nil, err
+	}
+
+	entries := []model.ACLEntry{}
+	err := s.DB.WithContext(c.UserContext()).
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Order("created_at, id").
+		Find(&entries).Error
+
+	if err != nil {
+		s.Log.Errorf("Failed to list ACL entries: %+v", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// AddEntry shares the record with a principal on behalf of the caller
+func (s *aclService) AddEntry(
+	c *fiber.Ctx, resourceType, resourceID string, req *validation.GrantACL,
+) (*model.ACLEntry, error) {
+	req.Principal = strings.TrimSpace(req.Principal)
+
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	principal, err := s.normalize(resourceType, resourceID, req.Principal)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &model.ACLEntry{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Principal:    principal,
+		Permission:   req.Permission,
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		entry.GrantedBy = &actor.ID
+	}
+
+	err = s.DB.WithContext(c.UserContext()).Create(entry).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Permission already granted")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to grant ACL entry: %+v", err)
+		return nil, err
+	}
+
+	s.audit(c, model.AuditActionACLGranted, entry)
+
+	return entry, nil
+}
+
+// RemoveEntry revokes an entry of the record. The last owner cannot be removed, so someone can
+// always manage who the record is shared with.
+func (s *aclService) RemoveEntry(c *fiber.Ctx, resourceType, resourceID, entryID string) error {
+	if _, err := uuid.Parse(entryID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid entry ID")
+	}
+
+	// RETURNING fills in the removed entry for the owner check and the audit entry
+	entry := new(model.ACLEntry)
+	err := s.DB.WithContext(c.UserContext()).Transaction(func(tx *gorm.DB) error {
+		// Lock the owners so concurrent removals cannot leave the record without one
+		var owners []uuid.UUID
+		err := tx.Model(new(model.ACLEntry)).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("resource_type = ? AND resource_id = ? AND permission = ?", resourceType, resourceID, model.ACLOwner).
+			Pluck("id", &owners).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.Returning{}).
+			Where("id = ? AND resource_type = ? AND resource_id = ?", entryID, resourceType, resourceID).
+			Delete(entry)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "Entry not found")
+		}
+
+		if entry.Permission == model.ACLOwner && len(owners) == 1 {
+			return fiber.NewError(fiber.StatusConflict, "Cannot remove the last owner")
+		}
+		return nil
+	})
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return err
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to revoke ACL entry: %+v", err)
+		return err
+	}
+
+	s.audit(c, model.AuditActionACLRevoked, entry)
+
+	return nil
+}
+
+// normalize checks the resource and returns the principal with user IDs in canonical form
+func (s *aclService) normalize(resourceType, resourceID, principal string) (string, error) {
+	if !resourceTypePattern.MatchString(resourceType) {
+		return "", fiber.NewError(fiber.StatusBadRequest, "Invalid resource type")
+	}
+	if resourceID == "" || len(resourceID) > maxACLResourceID {
+		return "", fiber.NewError(fiber.StatusBadRequest, "Invalid resource ID")
+	}
+
+	if userID, ok := strings.CutPrefix(principal, model.ACLPrincipalUser); ok {
+		parsed, err := id.Parse(userID)
+		if err != nil {
+			return "", fiber.NewError(fiber.StatusBadRequest, "Invalid principal")
+		}
+		return model.ACLPrincipalUser + parsed.String(), nil
+	}
+
+	return principal, nil
+}
+
+func (s *aclService) audit(c *fiber.Ctx, action string, entry *model.ACLEntry) {
+	metadata := map[string]any{
+		"entry_id":      entry.ID,
+		"resource_type": entry.ResourceType,
+		"resource_id":   entry.ResourceID,
+		"principal":     entry.Principal,
+		"permission":    entry.Permission,
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, nil, action, metadata)
+}
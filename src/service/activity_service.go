@@ -0,0 +1,135 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// activitySQL merges a user's audit entries with the tokens issued to them. Audit actions are grouped
+// into timeline types by their prefix (login.*, email.*, message.*, token.*); anything else is plain "audit"
+const activitySQL = `
+SELECT id,
+	CASE
+		WHEN action LIKE 'login.%' THEN 'login'
+		WHEN action LIKE 'email.%' THEN 'email'
+		WHEN action LIKE 'message.%' THEN 'message'
+		WHEN action LIKE 'token.%' THEN 'token'
+		ELSE 'audit'
+	END AS type,
+	action, ip, user_agent, metadata, created_at AS occurred_at
+FROM audit_logs WHERE user_id = @user
+UNION ALL
+SELECT id, 'token', 'token.issued', NULL, NULL,
+	jsonb_build_object('type', type, 'expires', expires), created_at
+FROM tokens WHERE user_id = @user
+UNION ALL
+SELECT id, 'token', 'token.issued', NULL, NULL,
+	jsonb_build_object('type', 'api', 'name', name, 'prefix', prefix, 'scopes', scopes, 'expires', expires_at), created_at
+FROM api_tokens WHERE user_id = @user`
+
+type ActivityService interface {
+	GetUserActivity(c *fiber.Ctx, userID string, params *validation.QueryActivity) ([]response.ActivityEvent, int64, error)
+}
+
+type activityService struct {
+	Log         *logrus.Logger
+	DB          *gorm.DB
+	Validate    *validator.Validate
+	UserService UserService
+}
+
+func NewActivityService(db *gorm.DB, validate *validator.Validate, userService UserService) ActivityService {
+	return &activityService{
+		Log:         utils.Log,
+		DB:          db,
+		Validate:    validate,
+		UserService: userService,
+	}
+}
+
+type activityRow struct {
+	ID         uuid.UUID
+	Type       string
+	Action     string
+	IP         *string
+	UserAgent  *string
+	Metadata   string
+	OccurredAt time.Time
+}
+
+// GetUserActivity returns a page of the user's timeline, newest first, optionally limited to some event types
+// and to the days between From and To
+func (s *activityService) GetUserActivity(
+	c *fiber.Ctx, userID string, params *validation.QueryActivity,
+) ([]response.ActivityEvent, int64, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, 0, err
+	}
+
+	if _, err := s.UserService.GetUserByID(c, userID); err != nil {
+		return nil, 0, err
+	}
+
+	db := s.DB.WithContext(c.UserContext())
+	query := db.Table("(?) AS activity", db.Raw(activitySQL, sql.Named("user", userID)))
+
+	if len(params.Types) > 0 {
+		query = query.Where("type IN ?", params.Types)
+	}
+
+	// Days start at midnight in the caller's timezone; formats were checked by the validator
+	caller, _ := c.Locals("user").(*model.User)
+	location := userLocation(caller)
+	if params.From != "" {
+		from, _ := time.ParseInLocation(time.DateOnly, params.From, location)
+		query = query.Where("occurred_at >= ?", from.UTC())
+	}
+	if params.To != "" {
+		to, _ := time.ParseInLocation(time.DateOnly, params.To, location)
+		query = query.Where("occurred_at < ?", to.AddDate(0, 0, 1).UTC())
+	}
+	query = query.Session(&gorm.Session{})
+
+	var totalResults int64
+	if err := query.Count(&totalResults).Error; err != nil {
+		s.Log.Errorf("Failed to count user activity: %+v", err)
+		return nil, 0, err
+	}
+
+	var rows []activityRow
+	offset := (params.Page - 1) * params.Limit
+	if err := query.Order("occurred_at DESC, id DESC").Limit(params.Limit).Offset(offset).Scan(&rows).Error; err != nil {
+		s.Log.Errorf("Failed to get user activity: %+v", err)
+		return nil, 0, err
+	}
+
+	events := make([]response.ActivityEvent, len(rows))
+	for i, row := range rows {
+		events[i] = response.ActivityEvent{
+			ID:         row.ID,
+			Type:       row.Type,
+			Action:     row.Action,
+			Metadata:   json.RawMessage(row.Metadata),
+			OccurredAt: row.OccurredAt,
+		}
+		if row.IP != nil {
+			events[i].IP = *row.IP
+		}
+		if row.UserAgent != nil {
+			events[i].UserAgent = *row.UserAgent
+		}
+	}
+
+	return events, totalResults, nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"app/src/analytics"
+	"app/src/clock"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AnalyticsService interface {
+	Persist(ctx context.Context) (int, error)
+	GetActiveUsers(c *fiber.Ctx, query *validation.QueryActiveUsers) (*response.ActiveUsers, error)
+	GetEndpointVisitors(c *fiber.Ctx, query *validation.QueryEndpointVisitors) (*response.EndpointVisitors, error)
+}
+
+type analyticsService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+	Tracker  *analytics.Tracker
+	Clock    clock.Clock
+}
+
+// NewAnalyticsService reports the unique visitors counted by tracker. Persist saves the sketches of
+// the current periods to the database; reports read the saved sketches and the live ones in Redis,
+// so they are complete while either has them. Days are timed with clk, the wall clock if nil.
+func NewAnalyticsService(
+	db *gorm.DB, validate *validator.Validate, tracker *analytics.Tracker, clk clock.Clock,
+) AnalyticsService {
+	return &analyticsService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+		Tracker:  tracker,
+		Clock:    clock.OrSystem(clk),
+	}
+}
+
+// Persist saves the sketches of today and yesterday, and of their months, and returns how many it
+// wrote. Yesterday is included so the visits flushed after midnight are saved too. Each sketch is
+// merged with the saved one first, which restores a sketch Redis lost and lets saved counts only grow.
+func (s *analyticsService) Persist(ctx context.Context) (int, error) {
+	now := s.Clock.Now().UTC()
+	days := []time.Time{now, now.AddDate(0, 0, -1)}
+
+	sketches := []analytics.Sketch{analytics.MonthlyUsers(days[0])}
+	if month := analytics.MonthlyUsers(days[1]); month != sketches[0] {
+		sketches = append(sketches, month)
+	}
+
+	for _, day := range days {
+		sketches = append(sketches, analytics.DailyUsers(day))
+
+		period := day.Format(analytics.DayFormat)
+		endpoints, err := s.Tracker.Endpoints(ctx, period)
+		if err != nil {
+			// Without Redis there is nothing new to save
+			return 0, nil
+		}
+
+		// Endpoints saved before Redis lost its data are restored as well
+		var saved []string
+		err = s.DB.WithContext(ctx).
+			Model(new(model.AnalyticsSketch)).
+			Where("kind = ? AND period = ?", analytics.KindEndpointIPs, period).
+			Pluck("endpoint", &saved).Error
+		if err != nil {
+			return 0, err
+		}
+
+		for _, endpoint := range slices.Compact(slices.Sorted(slices.Values(append(endpoints, saved...)))) {
+			sketches = append(sketches, analytics.EndpointIPs(day, endpoint))
+		}
+	}
+
+	written := 0
+	for _, sketch := range sketches {
+		saved := new(model.AnalyticsSketch)
+		err := s.DB.WithContext(ctx).
+			Where("kind = ? AND period = ? AND endpoint = ?", sketch.Kind, sketch.Period, sketch.Endpoint).
+			Limit(1).
+			Find(saved).Error
+		if err != nil {
+			return written, err
+		}
+
+		registers, count, err := s.Tracker.Merge(ctx, sketch, saved.Sketch)
+		if err != nil {
+			return written, err
+		}
+		if registers == nil {
+			continue
+		}
+
+		err = s.DB.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&model.AnalyticsSketch{
+			Kind:      sketch.Kind,
+			Period:    sketch.Period,
+			Endpoint:  sketch.Endpoint,
+			Sketch:    registers,
+			Count:     count,
+			UpdatedAt: now,
+		}).Error
+		if err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	return written, nil
+}
+
+func (s *analyticsService) GetActiveUsers(
+	c *fiber.Ctx, query *validation.QueryActiveUsers,
+) (*response.ActiveUsers, error) {
+	if err := s.Validate.Struct(query); err != nil {
+		return nil, err
+	}
+
+	from, to, err := usageRange(s.Clock.Now(), query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var sketches []analytics.Sketch
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		sketches = append(sketches, analytics.DailyUsers(day))
+	}
+	days := len(sketches)
+	for month := from.AddDate(0, 0, 1-from.Day()); !month.After(to); month = month.AddDate(0, 1, 0) {
+		sketches = append(sketches, analytics.MonthlyUsers(month))
+	}
+
+	counts, err := s.counts(c.UserContext(), sketches)
+	if err != nil {
+		return nil, err
+	}
+
+	activeUsers := &response.ActiveUsers{
+		From:   from.Format(time.DateOnly),
+		To:     to.Format(time.DateOnly),
+		Days:   make([]response.ActivePeriod, 0, days),
+		Months: make([]response.ActivePeriod, 0, len(sketches)-days),
+	}
+	for i, sketch := range sketches {
+		period := response.ActivePeriod{Period: sketch.Period, Users: counts[i]}
+		if i < days {
+			activeUsers.Days = append(activeUsers.Days, period)
+		} else {
+			activeUsers.Months = append(activeUsers.Months, period)
+		}
+	}
+
+	return activeUsers, nil
+}
+
+func (s *analyticsService) GetEndpointVisitors(
+	c *fiber.Ctx, query *validation.QueryEndpointVisitors,
+) (*response.EndpointVisitors, error) {
+	if err := s.Validate.Struct(query); err != nil {
+		return nil, err
+	}
+
+	day := s.Clock.Now().UTC()
+	if query.Day != "" {
+		// The format was checked by the validator
+		day, _ = time.Parse(time.DateOnly, query.Day)
+	}
+	period := day.Format(analytics.DayFormat)
+
+	var endpoints []string
+	err := s.DB.WithContext(c.UserContext()).
+		Model(new(model.AnalyticsSketch)).
+		Where("kind = ? AND period = ?", analytics.KindEndpointIPs, period).
+		Pluck("endpoint", &endpoints).Error
+	if err != nil {
+		s.Log.Errorf("Failed to get endpoint visitors: %+v", err)
+		return nil, err
+	}
+	if live, err := s.Tracker.Endpoints(c.UserContext(), period); err == nil {
+		endpoints = slices.Compact(slices.Sorted(slices.Values(append(endpoints, live...))))
+	}
+
+	sketches := make([]analytics.Sketch, len(endpoints))
+	for i, endpoint := range endpoints {
+		sketches[i] = analytics.EndpointIPs(day, endpoint)
+	}
+	counts, err := s.counts(c.UserContext(), sketches)
+	if err != nil {
+		return nil, err
+	}
+
+	visitors := &response.EndpointVisitors{Day: period, Endpoints: make([]response.EndpointVisitor, len(endpoints))}
+	for i, endpoint := range endpoints {
+		visitors.Endpoints[i] = response.EndpointVisitor{Endpoint: endpoint, UniqueIPs: counts[i]}
+	}
+	slices.SortStableFunc(visitors.Endpoints, func(a, b response.EndpointVisitor) int {
+		return cmp.Compare(b.UniqueIPs, a.UniqueIPs)
+	})
+	if len(visitors.Endpoints) > query.Limit {
+		visitors.Endpoints = visitors.Endpoints[:query.Limit]
+	}
+
+	return visitors, nil
+}
+
+// counts returns the larger of the saved and the live count of each sketch: Redis may have lost a
+// sketch, and the saved one misses the visits since the last persist
+func (s *analyticsService) counts(ctx context.Context, sketches []analytics.Sketch) ([]int64, error) {
+	counts := make([]int64, len(sketches))
+	if len(sketches) == 0 {
+		return counts, nil
+	}
+
+	index := make(map[analytics.Sketch]int, len(sketches))
+	kinds := map[string]bool{}
+	periods := map[string]bool{}
+	for i, sketch := range sketches {
+		index[sketch] = i
+		kinds[sketch.Kind] = true
+		periods[sketch.Period] = true
+	}
+
+	var rows []model.AnalyticsSketch
+	err := s.DB.WithContext(ctx).
+		Select("kind", "period", "endpoint", "count").
+		Where("kind IN ? AND period IN ?", slices.Collect(maps.Keys(kinds)), slices.Collect(maps.Keys(periods))).
+		Find(&rows).Error
+	if err != nil {
+		s.Log.Errorf("Failed to get analytics: %+v", err)
+		return nil, err
+	}
+	for _, row := range rows {
+		if i, ok := index[analytics.Sketch{Kind: row.Kind, Period: row.Period, Endpoint: row.Endpoint}]; ok {
+			counts[i] = row.Count
+		}
+	}
+
+	// Without Redis the saved counts are reported
+	live, err := s.Tracker.Count(ctx, sketches)
+	if err != nil {
+		s.Log.Debugf("Reporting saved analytics only: %v", err)
+		return counts, nil
+	}
+	for i, count := range live {
+		counts[i] = max(counts[i], count)
+	}
+
+	return counts, nil
+}
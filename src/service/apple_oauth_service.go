@@ -0,0 +1,252 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/deadline"
+	"app/src/httpclient"
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+)
+
+// appleIssuer is the iss of Apple ID tokens and the aud of client secrets
+const appleIssuer = "https://appleid.apple.com"
+
+// appleClientSecretTTL is how long a generated client secret is used; Apple allows up to six months
+const appleClientSecretTTL = time.Hour
+
+// AppleEndpoint is Apple's authorization server
+var AppleEndpoint = oauth2.Endpoint{
+	AuthURL:   appleIssuer + "/auth/authorize",
+	TokenURL:  appleIssuer + "/auth/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+type AppleOAuthService interface {
+	AuthURL(c *fiber.Ctx) (string, error)
+	Login(c *fiber.Ctx) (*model.User, error)
+}
+
+type appleOAuthService struct {
+	Log         *logrus.Logger
+	State       oauthState
+	UserService UserService
+	Config      config.AppleConfig
+	Endpoint    oauth2.Endpoint
+	Client      *http.Client
+
+	key          *ecdsa.PrivateKey
+	mu           sync.Mutex
+	secret       string
+	secretExpiry time.Time
+}
+
+// appleClaims are the ID token claims used to sign the user in
+type appleClaims struct {
+	jwt.RegisteredClaims
+	Email         string    `json:"email"`
+	EmailVerified appleBool `json:"email_verified"`
+	Nonce         string    `json:"nonce"`
+}
+
+// appleBool decodes Apple's booleans, which arrive as either true or "true"
+type appleBool bool
+
+func (b *appleBool) UnmarshalJSON(data []byte) error {
+	*b = appleBool(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+// appleUser is the "user" form field Apple posts on the first authorization only
+type appleUser struct {
+	Name struct {
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	} `json:"name"`
+}
+
+func NewAppleOAuthService(
+	store cache.Store, userService UserService, cfg config.AppleConfig, endpoint oauth2.Endpoint,
+) AppleOAuthService {
+	s := &appleOAuthService{
+		Log: utils.Log,
+		// Apple posts the callback from its own origin, so the state cookie must be sent cross-site
+		State:       oauthState{Store: store, Cookie: "apple_oauth_state", CrossSite: true},
+		UserService: userService,
+		Config:      cfg,
+		Endpoint:    endpoint,
+		Client:      httpclient.New("apple"),
+	}
+
+	if cfg.ClientID != "" {
+		key, err := jwt.ParseECPrivateKeyFromPEM([]byte(cfg.PrivateKey))
+		if err != nil {
+			s.Log.Errorf("Sign in with Apple disabled: invalid private key: %v", err)
+		}
+		s.key = key
+	}
+
+	return s
+}
+
+// AuthURL starts a login with the form_post response mode and returns Apple's consent URL
+func (s *appleOAuthService) AuthURL(c *fiber.Ctx) (string, error) {
+	if s.key == nil {
+		return "", fiber.NewError(fiber.StatusNotFound, "Sign in with Apple is not configured")
+	}
+
+	nonce, err := randomURLToken()
+	if err != nil {
+		return "", err
+	}
+
+	state, err := s.State.begin(c, pendingLogin{Nonce: nonce})
+	if err != nil {
+		return "", err
+	}
+
+	return s.oauth2Config("").AuthCodeURL(state,
+		oauth2.SetAuthURLParam("response_mode", "form_post"),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	), nil
+}
+
+// Login completes Apple's form_post callback. The name in the "user" field is only sent the first
+// time a user authorizes the app, so it is passed on to be stored with the new account.
+func (s *appleOAuthService) Login(c *fiber.Ctx) (*model.User, error) {
+	if s.key == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Sign in with Apple is not configured")
+	}
+
+	pending, err := s.State.consume(c, c.FormValue("state"))
+	if err != nil {
+		return nil, err
+	}
+
+	if reason := c.FormValue("error"); reason != "" {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Apple login failed: "+reason)
+	}
+
+	secret, err := s.clientSecret()
+	if err != nil {
+		s.Log.Errorf("Failed to sign Apple client secret: %+v", err)
+		return nil, err
+	}
+
+	ctx, cancel := deadline.For(c.UserContext(), config.DeadlineOAuth)
+	defer cancel()
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, s.Client)
+	token, err := s.oauth2Config(secret).Exchange(ctx, c.FormValue("code"))
+	if err != nil {
+		s.Log.Warnf("Apple code exchange failed: %v", err)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid authorization code")
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	claims, err := s.verifyIDToken(rawIDToken, pending.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.UserService.CreateAppleUser(c, &validation.AppleLogin{
+		Subject:       claims.Subject,
+		Name:          appleName(c.FormValue("user")),
+		Email:         strings.ToLower(claims.Email),
+		VerifiedEmail: bool(claims.EmailVerified),
+	})
+}
+
+func (s *appleOAuthService) oauth2Config(clientSecret string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     s.Config.ClientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  s.Config.RedirectURL,
+		Scopes:       []string{"name", "email"},
+		Endpoint:     s.Endpoint,
+	}
+}
+
+// clientSecret returns an ES256 JWT signed with the .p8 key, reused until shortly before it expires
+func (s *appleOAuthService) clientSecret() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.secret != "" && now.Before(s.secretExpiry.Add(-5*time.Minute)) {
+		return s.secret, nil
+	}
+
+	expiry := now.Add(appleClientSecretTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    s.Config.TeamID,
+		Subject:   s.Config.ClientID,
+		Audience:  jwt.ClaimStrings{appleIssuer},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiry),
+	})
+	token.Header["kid"] = s.Config.KeyID
+
+	secret, err := token.SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+
+	s.secret, s.secretExpiry = secret, expiry
+	return secret, nil
+}
+
+// verifyIDToken checks the ID token's claims; like Google's, it comes straight from Apple's token
+// endpoint over TLS, so its signature does not need to be checked again
+func (s *appleOAuthService) verifyIDToken(rawIDToken, nonce string) (*appleClaims, error) {
+	invalid := fiber.NewError(fiber.StatusUnauthorized, "Invalid Apple ID token")
+	if rawIDToken == "" {
+		return nil, invalid
+	}
+
+	claims := new(appleClaims)
+	if _, _, err := jwt.NewParser().ParseUnverified(rawIDToken, claims); err != nil {
+		return nil, invalid
+	}
+
+	validator := jwt.NewValidator(
+		jwt.WithAudience(s.Config.ClientID), jwt.WithIssuer(appleIssuer),
+		jwt.WithExpirationRequired(), jwt.WithLeeway(time.Minute),
+	)
+	if err := validator.Validate(claims); err != nil || claims.Subject == "" || !nonceMatches(claims.Nonce, nonce) {
+		return nil, invalid
+	}
+
+	return claims, nil
+}
+
+// appleName joins the first and last name from the "user" form field, if Apple sent one
+func appleName(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	user := new(appleUser)
+	if err := json.Unmarshal([]byte(raw), user); err != nil {
+		return ""
+	}
+
+	name := strings.TrimSpace(user.Name.FirstName + " " + user.Name.LastName)
+	if runes := []rune(name); len(runes) > 50 {
+		name = string(runes[:50])
+	}
+	return name
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/siem"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"io"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// auditExportBatch caps the audit entries read per query of an export
+const auditExportBatch = 1000
+
+// AuditExport writes the entries of an export to w as they are read
+type AuditExport func(ctx context.Context, w io.Writer) error
+
+type AuditExportService interface {
+	ExportAuditLogs(c *fiber.Ctx, query *validation.ExportAuditLogs) (AuditExport, error)
+}
+
+type auditExportService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	AuditService AuditService
+}
+
+// NewAuditExportService exports the audit log, e.g. for a SIEM that does not take it as it is recorded
+func NewAuditExportService(db *gorm.DB, validate *validator.Validate, auditService AuditService) AuditExportService {
+	return &auditExportService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		AuditService: auditService,
+	}
+}
+
+// ExportAuditLogs checks the query and audits the export; the entries are written once the
+// returned export runs, oldest first
+func (s *auditExportService) ExportAuditLogs(c *fiber.Ctx, query *validation.ExportAuditLogs) (AuditExport, error) {
+	if err := s.Validate.Struct(query); err != nil {
+		return nil, err
+	}
+
+	from, _ := time.Parse(time.DateOnly, query.From)
+	to, _ := time.Parse(time.DateOnly, query.To)
+	to = to.AddDate(0, 0, 1)
+
+	metadata := map[string]any{"from": query.From, "to": query.To, "format": query.Format}
+	if query.Action != "" {
+		metadata["action"] = query.Action
+	}
+	if query.UserID != "" {
+		metadata["user_id"] = query.UserID
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, nil, model.AuditActionAuditLogsExported, metadata)
+
+	return func(ctx context.Context, w io.Writer) error {
+		encoder, err := siem.NewEncoder(w, query.Format)
+		if err != nil {
+			return err
+		}
+
+		// Entries are read in pages after the last one written, so no query stays open while a slow
+		// client downloads
+		var lastCreatedAt time.Time
+		var lastID uuid.UUID
+		for {
+			page := s.DB.WithContext(ctx).Where("created_at >= ? AND created_at < ?", from, to)
+			if lastID != uuid.Nil {
+				page = page.Where("(created_at, id) > (?, ?)", lastCreatedAt, lastID)
+			}
+			if query.Action != "" {
+				page = page.Where("action = ?", query.Action)
+			}
+			if query.UserID != "" {
+				page = page.Where("user_id = ?", query.UserID)
+			}
+
+			var entries []*model.AuditLog
+			if err := page.Order("created_at, id").Limit(auditExportBatch).Find(&entries).Error; err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if err := encoder.Encode(siem.NewEvent(entry)); err != nil {
+					return err
+				}
+			}
+			if err := encoder.Flush(); err != nil {
+				return err
+			}
+
+			if len(entries) < auditExportBatch {
+				return nil
+			}
+			last := entries[len(entries)-1]
+			lastCreatedAt, lastID = last.CreatedAt, last.ID
+		}
+	}, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/tracing"
+	"app/src/utils"
+	"context"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// maxUserAgent matches the audit_logs.user_agent column
+const maxUserAgent = 255
+
+type AuditService interface {
+	Record(c *fiber.Ctx, userID *uuid.UUID, action string, metadata map[string]any)
+	RecordSystem(ctx context.Context, userID *uuid.UUID, action string, metadata map[string]any)
+}
+
+type auditService struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) AuditService {
+	return &auditService{
+		Log: utils.Log,
+		DB:  db,
+	}
+}
+
+// Record appends an audit entry for the current request
+// Auditing is best-effort: failures are logged, never returned to the caller
+func (s *auditService) Record(c *fiber.Ctx, userID *uuid.UUID, action string, metadata map[string]any) {
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if len(userAgent) > maxUserAgent {
+		userAgent = userAgent[:maxUserAgent]
+	}
+
+	entry := &model.AuditLog{
+		UserID:    userID,
+		Action:    action,
+		IP:        c.IP(),
+		UserAgent: userAgent,
+	}
+	// The actor is whoever authenticated the request, which is not always the user the entry is about
+	if actor, ok := c.Locals("user").(*model.User); ok && actor != nil {
+		entry.ActorType = &actor.Type
+	}
+
+	s.create(c.UserContext(), entry, metadata)
+}
+
+// RecordSystem appends an audit entry for work done outside a request, such as background jobs;
+// userID is the user the work concerns, if any
+func (s *auditService) RecordSystem(ctx context.Context, userID *uuid.UUID, action string, metadata map[string]any) {
+	actorType := model.AuditActorSystem
+	s.create(ctx, &model.AuditLog{UserID: userID, ActorType: &actorType, Action: action}, metadata)
+}
+
+func (s *auditService) create(ctx context.Context, entry *model.AuditLog, metadata map[string]any) {
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		s.Log.Errorf("Failed encode audit metadata for %s: %+v", entry.Action, err)
+		data = []byte("{}")
+	}
+	entry.Metadata = string(data)
+	if requestID := tracing.RequestID(ctx); requestID != "" {
+		entry.RequestID = &requestID
+	}
+
+	if err := s.DB.WithContext(ctx).Create(entry).Error; err != nil {
+		s.Log.Errorf("Failed record audit log %s: %+v", entry.Action, err)
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"app/src/config"
 	"app/src/model"
 	"app/src/response"
+	"app/src/saga"
 	"app/src/utils"
 	"app/src/validation"
+	"context"
 	"errors"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -16,12 +19,13 @@ import (
 )
 
 type AuthService interface {
-	Register(c *fiber.Ctx, req *validation.Register) (*model.User, error)
+	Register(c *fiber.Ctx, req *validation.Register) (*model.User, *response.Tokens, error)
 	Login(c *fiber.Ctx, req *validation.Login) (*model.User, error)
 	Logout(c *fiber.Ctx, req *validation.Logout) error
 	RefreshAuth(c *fiber.Ctx, req *validation.RefreshToken) (*response.Tokens, error)
 	ResetPassword(c *fiber.Ctx, query *validation.Token, req *validation.UpdatePassOrVerify) error
 	VerifyEmail(c *fiber.Ctx, query *validation.Token) error
+	ConfirmLogin(c *fiber.Ctx, query *validation.Token) (*model.User, error)
 }
 
 type authService struct {
@@ -32,10 +36,17 @@ type authService struct {
 	TokenService     TokenService
 	CacheInvalidator *cache.CacheInvalidator
 	SessionService   SessionService
+	NegativeCache    *cache.NegativeCache
+	RiskService      RiskService
+	AuditService     AuditService
+	EmailDomains     EmailDomainService
+	EmailOutbox      EmailOutboxService
 }
 
 func NewAuthService(
-	db *gorm.DB, validate *validator.Validate, userService UserService, tokenService TokenService, cacheInvalidator *cache.CacheInvalidator, sessionService SessionService,
+	db *gorm.DB, validate *validator.Validate, userService UserService, tokenService TokenService,
+	cacheInvalidator *cache.CacheInvalidator, sessionService SessionService, negativeCache *cache.NegativeCache,
+	riskService RiskService, auditService AuditService, emailDomains EmailDomainService, emailOutbox EmailOutboxService,
 ) AuthService {
 	return &authService{
 		Log:              utils.Log,
@@ -45,36 +56,116 @@ func NewAuthService(
 		TokenService:     tokenService,
 		CacheInvalidator: cacheInvalidator,
 		SessionService:   sessionService,
+		NegativeCache:    negativeCache,
+		RiskService:      riskService,
+		AuditService:     auditService,
+		EmailDomains:     emailDomains,
+		EmailOutbox:      emailOutbox,
 	}
 }
 
-func (s *authService) Register(c *fiber.Ctx, req *validation.Register) (*model.User, error) {
+// Register creates the user and signs them in as a saga: when the tokens cannot be stored, the user
+// is deleted again so the email can be used for another attempt. The verification email is queued
+// in the outbox afterwards, which retries it until it is sent.
+func (s *authService) Register(c *fiber.Ctx, req *validation.Register) (*model.User, *response.Tokens, error) {
 	if err := s.Validate.Struct(req); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if err := checkEmailDomain(c.UserContext(), s.EmailDomains, req.Email); err != nil {
+		return nil, nil, err
+	}
+
+	if isReservedUsername(req.Username) {
+		return nil, nil, ErrUsernameReserved
 	}
 
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
 		s.Log.Errorf("Failed hash password: %+v", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	user := &model.User{
 		Name:     req.Name,
+		Username: optionalUsername(req.Username),
 		Email:    req.Email,
 		Password: hashedPassword,
+		Timezone: optionalTimezone(req.Timezone),
+	}
+
+	var tokens *response.Tokens
+	err = saga.Run(c.UserContext(), s.Log, "register",
+		saga.Step{
+			Name: "create user",
+			Do: func(ctx context.Context) error {
+				return s.createUser(ctx, user, req.Username)
+			},
+			Undo: func(ctx context.Context) error {
+				return s.deleteUser(ctx, user)
+			},
+		},
+		saga.Step{
+			Name: "issue tokens",
+			Do: func(context.Context) (err error) {
+				tokens, err = s.TokenService.GenerateAuthTokens(c, user)
+				return err
+			},
+		},
+	)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	result := s.DB.WithContext(c.Context()).Create(user)
+	s.queueVerificationEmail(c, user)
+
+	return user, tokens, nil
+}
+
+func (s *authService) createUser(ctx context.Context, user *model.User, username string) error {
+	result := s.DB.WithContext(ctx).Create(user)
 	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
-		return nil, fiber.NewError(fiber.StatusConflict, "Email already taken")
+		return duplicateUserError(ctx, s.DB, username, "Email already taken")
 	}
 
 	if result.Error != nil {
 		s.Log.Errorf("Failed create user: %+v", result.Error)
+		return result.Error
 	}
 
-	return user, result.Error
+	// The user exists now - drop any stale not-found marker
+	s.NegativeCache.Forget(ctx, cache.NegativeKindUserEmail, user.Email)
+
+	return nil
+}
+
+// deleteUser compensates a registration that failed after the user was created. Tokens stored
+// before the failure go with the user.
+func (s *authService) deleteUser(ctx context.Context, user *model.User) error {
+	if err := s.DB.WithContext(ctx).Delete(&model.User{}, "id = ?", user.ID).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// queueVerificationEmail sends the new user a verification link through the outbox. The account is
+// usable without it, so failures are only logged; the user can ask for another link.
+func (s *authService) queueVerificationEmail(c *fiber.Ctx, user *model.User) {
+	if s.EmailOutbox == nil {
+		return
+	}
+
+	token, err := s.TokenService.GenerateVerifyEmailToken(c, user)
+	if err == nil {
+		err = s.EmailOutbox.QueueVerificationEmail(c.UserContext(), user.Email, *token)
+	}
+	if err != nil {
+		s.Log.Warnf("Failed to queue verification email on registration: %v", err)
+		return
+	}
+
+	s.AuditService.Record(c, &user.ID, model.AuditActionEmailSent, map[string]any{"kind": "verify_email"})
 }
 
 func (s *authService) Login(c *fiber.Ctx, req *validation.Login) (*model.User, error) {
@@ -83,14 +174,28 @@ func (s *authService) Login(c *fiber.Ctx, req *validation.Login) (*model.User, e
 	}
 
 	user, err := s.UserService.GetUserByEmail(c, req.Email)
-	if err != nil {
+	if err != nil || !utils.CheckPasswordHash(req.Password, user.Password) {
+		s.RiskService.RecordFailure(c)
+		if err == nil {
+			s.AuditService.Record(c, &user.ID, model.AuditActionLoginFailed, map[string]any{
+				"method": "password",
+				"reason": "invalid_password",
+			})
+		}
 		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
 	}
 
-	if !utils.CheckPasswordHash(req.Password, user.Password) {
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
+	if !user.IsActive {
+		return nil, ErrAccountSuspended
 	}
 
+	// A correct password from an unusual place may still be blocked or need email confirmation
+	if err := s.RiskService.Evaluate(c, user); err != nil {
+		return nil, err
+	}
+
+	s.AuditService.Record(c, &user.ID, model.AuditActionLoginSucceeded, map[string]any{"method": "password"})
+
 	return user, nil
 }
 
@@ -104,25 +209,9 @@ func (s *authService) Logout(c *fiber.Ctx, req *validation.Logout) error {
 		return fiber.NewError(fiber.StatusNotFound, "Token not found")
 	}
 
-	err = s.TokenService.DeleteToken(c, config.TokenTypeRefresh, token.UserID.String())
-
-	// Invalidate session cache after successful logout (INVL-05)
-	if err == nil && s.SessionService != nil {
-		if invalidateErr := s.SessionService.InvalidateSession(c.Context(), token.UserID.String()); invalidateErr != nil {
-			s.Log.Warnf("failed to invalidate session cache on logout: %v", invalidateErr)
-			// Don't fail logout - cache invalidation is best-effort
-		}
-	}
-
-	// Invalidate API response cache after successful logout
-	if err == nil && s.CacheInvalidator != nil {
-		if invalidateErr := s.CacheInvalidator.InvalidateUserRelatedCache(c.Context(), token.UserID.String()); invalidateErr != nil {
-			s.Log.Warnf("failed to invalidate user cache on logout: %v", invalidateErr)
-			// Don't fail logout - cache invalidation is best-effort
-		}
-	}
-
-	return err
+	// Only this device's session ends; the user's other sessions stay signed in. Deleting the token
+	// drops the cached session (INVL-05).
+	return s.TokenService.EndSession(c, token)
 }
 
 func (s *authService) RefreshAuth(c *fiber.Ctx, req *validation.RefreshToken) (*response.Tokens, error) {
@@ -132,35 +221,37 @@ func (s *authService) RefreshAuth(c *fiber.Ctx, req *validation.RefreshToken) (*
 
 	token, err := s.TokenService.GetTokenByUserID(c, req.RefreshToken)
 	if err != nil {
+		if s.SessionService != nil && s.SessionService.IsEvicted(c.UserContext(), req.RefreshToken) {
+			return nil, fiber.NewError(fiber.StatusUnauthorized, "Session ended because you signed in on another device")
+		}
 		return nil, fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
 	}
 
+	if err := s.TokenService.CheckBinding(c, token); err != nil {
+		if errors.Is(err, ErrRefreshTokenReplayed) {
+			s.AuditService.Record(c, &token.UserID, model.AuditActionRefreshReplayed, map[string]any{"session_id": token.SessionID})
+		}
+		return nil, err
+	}
+
 	user, err := s.UserService.GetUserByID(c, token.UserID.String())
 	if err != nil {
 		return nil, fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
 	}
+	if !user.IsActive {
+		return nil, ErrAccountSuspended
+	}
 
-	// Invalidate old session cache before generating new tokens (INVL-03)
-	if s.SessionService != nil {
-		if invalidateErr := s.SessionService.InvalidateSession(c.Context(), user.ID.String()); invalidateErr != nil {
-			s.Log.Warnf("failed to invalidate old session cache on token refresh: %v", invalidateErr)
-			// Don't fail refresh - cache invalidation is best-effort
-		}
+	// Rotate: the presented refresh token is spent, its replacement continues the same session
+	if err := s.TokenService.EndSession(c, token); err != nil {
+		return nil, err
 	}
 
-	newTokens, err := s.TokenService.GenerateAuthTokens(c, user)
+	newTokens, err := s.TokenService.RotateAuthTokens(c, user, token)
 	if err != nil {
 		return nil, fiber.ErrInternalServerError
 	}
 
-	// Invalidate API response cache after successful token refresh
-	if s.CacheInvalidator != nil {
-		if invalidateErr := s.CacheInvalidator.InvalidateUserRelatedCache(c.Context(), user.ID.String()); invalidateErr != nil {
-			s.Log.Warnf("failed to invalidate user cache on token refresh: %v", invalidateErr)
-			// Don't fail refresh - cache invalidation is best-effort
-		}
-	}
-
 	return newTokens, err
 }
 
@@ -195,18 +286,26 @@ func (s *authService) VerifyEmail(c *fiber.Ctx, query *validation.Token) error {
 		return err
 	}
 
-	userID, err := utils.VerifyToken(query.Token, config.JWTSecret, config.TokenTypeVerifyEmail)
+	if _, err := utils.VerifyToken(query.Token, config.JWTSecret, config.TokenTypeVerifyEmail); err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid Token")
+	}
+
+	token, err := s.TokenService.ConsumeToken(c, query.Token, config.TokenTypeVerifyEmail)
+	if errors.Is(err, ErrTokenConsumed) {
+		return fiber.NewError(fiber.StatusConflict, "Verification link has already been used")
+	}
 	if err != nil {
 		return fiber.NewError(fiber.StatusUnauthorized, "Invalid Token")
 	}
 
-	user, err := s.UserService.GetUserByID(c, userID)
+	user, err := s.UserService.GetUserByID(c, token.UserID.String())
 	if err != nil {
 		return fiber.NewError(fiber.StatusUnauthorized, "Verify email failed")
 	}
 
-	if errToken := s.TokenService.DeleteToken(c, config.TokenTypeVerifyEmail, user.ID.String()); errToken != nil {
-		return errToken
+	// Links issued before binding was introduced carry no email and are treated as stale
+	if token.Email == nil || !strings.EqualFold(*token.Email, user.Email) {
+		return fiber.NewError(fiber.StatusUnauthorized, "Verification link is no longer valid for this email address")
 	}
 
 	updateBody := &validation.UpdatePassOrVerify{
@@ -219,3 +318,7 @@ func (s *authService) VerifyEmail(c *fiber.Ctx, query *validation.Token) error {
 
 	return nil
 }
+
+func (s *authService) ConfirmLogin(c *fiber.Ctx, query *validation.Token) (*model.User, error) {
+	return s.RiskService.ConfirmLogin(c, query)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"app/src/billing"
+	"app/src/config"
+	"app/src/dbretry"
+	"app/src/model"
+	"app/src/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// billingCustomerTimeout bounds a background customer creation, retries included
+const billingCustomerTimeout = 30 * time.Second
+
+type BillingService interface {
+	EnsureCustomer(user *model.User)
+	HandleWebhook(c *fiber.Ctx) error
+}
+
+type billingService struct {
+	Log            *logrus.Logger
+	DB             *gorm.DB
+	Stripe         *billing.Client
+	SessionService SessionService
+	Config         config.BillingConfig
+}
+
+// NewBillingService creates Stripe customers for users and keeps their subscriptions and plan in
+// sync from webhooks. Customers are only created with a Stripe secret key.
+func NewBillingService(db *gorm.DB, sessionService SessionService, cfg config.BillingConfig) BillingService {
+	s := &billingService{
+		Log:            utils.Log,
+		DB:             db,
+		SessionService: sessionService,
+		Config:         cfg,
+	}
+
+	if cfg.SecretKey != "" {
+		s.Stripe = billing.NewClient(cfg.APIURL, cfg.SecretKey)
+	}
+
+	return s
+}
+
+// EnsureCustomer creates the user's Stripe customer in the background if they have none yet, so
+// sign-up and login never wait on Stripe. A failed attempt is retried at the user's next login.
+func (s *billingService) EnsureCustomer(user *model.User) {
+	if s.Stripe == nil || user.StripeCustomerID != nil {
+		return
+	}
+
+	userID, email, name := user.ID, user.Email, user.Name
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), billingCustomerTimeout)
+		defer cancel()
+
+		customer, err := s.Stripe.CreateCustomer(ctx, userID.String(), email, name)
+		if err != nil {
+			s.Log.Warnf("Failed to create Stripe customer for user %s: %v", userID, err)
+			return
+		}
+
+		// Concurrent logins get the same customer back thanks to the idempotency key
+		err = s.DB.WithContext(ctx).
+			Model(new(model.User)).
+			Where("id = ? AND stripe_customer_id IS NULL", userID).
+			Update("stripe_customer_id", customer.ID).Error
+		if err != nil {
+			s.Log.Errorf("Failed to save Stripe customer: %+v", err)
+		}
+	}()
+}
+
+// HandleWebhook verifies a Stripe webhook and applies it once; redelivered events are acknowledged
+// without being applied again. Errors roll the event back so Stripe retries it.
+func (s *billingService) HandleWebhook(c *fiber.Ctx) error {
+	if s.Config.WebhookSecret == "" {
+		return fiber.NewError(fiber.StatusNotFound, "Billing is not configured")
+	}
+
+	event, err := billing.ConstructEvent(
+		c.Body(), c.Get(billing.SignatureHeader), s.Config.WebhookSecret, s.Config.WebhookTolerance, time.Now(),
+	)
+	if err != nil {
+		s.Log.Warnf("Rejected Stripe webhook: %v", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid webhook signature")
+	}
+
+	// The event ID makes the transaction idempotent: a retry after it committed changes nothing
+	var planChanged *uuid.UUID
+	err = dbretry.Transaction(c.UserContext(), s.DB, func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.BillingEvent{ID: event.ID, Type: event.Type})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		switch event.Type {
+		case billing.EventSubscriptionCreated, billing.EventSubscriptionUpdated, billing.EventSubscriptionDeleted:
+			planChanged, err = s.syncSubscription(tx, event)
+			return err
+		}
+		return nil
+	}, dbretry.Idempotent())
+
+	if err != nil {
+		s.Log.Errorf("Failed to process Stripe webhook %s: %+v", event.ID, err)
+		return err
+	}
+
+	// The plan is read from the cached session. The transaction's own update dropped it before the
+	// commit, so a request in between may have cached the old plan again.
+	if planChanged != nil && s.SessionService != nil {
+		if err := s.SessionService.InvalidateSession(c.UserContext(), planChanged.String()); err != nil {
+			s.Log.Warnf("Failed to invalidate session on plan change: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// syncSubscription stores the subscription from the event, unless a newer event was already applied,
+// and moves its user to the plan of their entitled subscription. It returns the user ID if the plan
+// changed.
+func (s *billingService) syncSubscription(tx *gorm.DB, event *billing.Event) (*uuid.UUID, error) {
+	subscription := new(billing.Subscription)
+	if err := json.Unmarshal(event.Data.Object, subscription); err != nil || subscription.ID == "" {
+		return nil, errors.New("invalid subscription object")
+	}
+
+	user := new(model.User)
+	err := tx.Where("stripe_customer_id = ?", subscription.Customer).First(user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Not ours, or the user was deleted - acknowledge it so Stripe stops retrying
+		s.Log.Warnf("Ignoring Stripe subscription %s of unknown customer %s", subscription.ID, subscription.Customer)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	row := &model.Subscription{
+		ID:                subscription.ID,
+		UserID:            user.ID,
+		Status:            subscription.Status,
+		PriceID:           subscription.PriceID(),
+		CurrentPeriodEnd:  subscription.PeriodEnd(),
+		CancelAtPeriodEnd: subscription.CancelAtPeriodEnd,
+		SyncedAt:          time.Unix(event.Created, 0).UTC(),
+	}
+	if plan, ok := s.Config.PricePlans[row.PriceID]; ok {
+		row.Plan = &plan
+	} else {
+		s.Log.Warnf("Stripe price %s is not in STRIPE_PRICE_PLANS, subscription %s grants no plan", row.PriceID, row.ID)
+	}
+
+	// Stripe does not guarantee delivery order, so an older event must not overwrite a newer one
+	err = tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"status", "price_id", "plan", "current_period_end", "cancel_at_period_end", "synced_at", "updated_at",
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "subscriptions.synced_at <= EXCLUDED.synced_at"}}},
+	}).Create(row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []string
+	err = tx.Model(new(model.Subscription)).
+		Where("user_id = ? AND status IN ? AND plan IS NOT NULL", user.ID, billing.EntitledStatuses).
+		Order("synced_at DESC").
+		Pluck("plan", &plans).Error
+	if err != nil {
+		return nil, err
+	}
+
+	plan := config.Quota.DefaultPlan
+	if len(plans) > 0 {
+		plan = plans[0]
+	}
+	if plan == user.Plan {
+		return nil, nil
+	}
+
+	if err := tx.Model(user).Update("plan", plan).Error; err != nil {
+		return nil, err
+	}
+	return &user.ID, nil
+}
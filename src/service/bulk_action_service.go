@@ -0,0 +1,523 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/dryrun"
+	"app/src/model"
+	"app/src/readonly"
+	"app/src/response"
+	"app/src/revision"
+	"app/src/revocation"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bulkActionBatchSize is how many items are written or loaded at once
+const bulkActionBatchSize = 500
+
+type BulkActionService interface {
+	SubmitBulkAction(c *fiber.Ctx, req *validation.BulkUserAction) (*model.BulkJob, error)
+	GetBulkJob(c *fiber.Ctx, id string) (*model.BulkJob, error)
+	GetBulkJobItems(c *fiber.Ctx, id string) ([]model.BulkJobItem, error)
+	// RunNext claims the oldest queued bulk job, or a running one whose worker stopped, and runs it
+	// to the end. It reports whether there was a job to run.
+	RunNext(ctx context.Context) (bool, error)
+}
+
+type bulkActionService struct {
+	Log              *logrus.Logger
+	DB               *gorm.DB
+	Validate         *validator.Validate
+	SessionService   SessionService
+	CacheInvalidator *cache.CacheInvalidator
+	NegativeCache    *cache.NegativeCache
+	Revocations      *revocation.Bus
+	AuditService     AuditService
+	Config           config.BulkActionConfig
+	Clock            clock.Clock
+}
+
+func NewBulkActionService(
+	db *gorm.DB, validate *validator.Validate, sessionService SessionService,
+	cacheInvalidator *cache.CacheInvalidator, negativeCache *cache.NegativeCache, revocations *revocation.Bus,
+	auditService AuditService, cfg config.BulkActionConfig, clk clock.Clock,
+) BulkActionService {
+	return &bulkActionService{
+		Log:              utils.Log,
+		DB:               db,
+		Validate:         validate,
+		SessionService:   sessionService,
+		CacheInvalidator: cacheInvalidator,
+		NegativeCache:    negativeCache,
+		Revocations:      revocations,
+		AuditService:     auditService,
+		Config:           cfg,
+		Clock:            clock.OrSystem(clk),
+	}
+}
+
+// SubmitBulkAction resolves the targeted users and queues the action for them. Listed IDs that match
+// no user are recorded as failed right away.
+func (s *bulkActionService) SubmitBulkAction(c *fiber.Ctx, req *validation.BulkUserAction) (*model.BulkJob, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// The validator allows a role only with assign-role and a tag only with add-tag
+	value := req.Role
+	if req.Action == model.BulkActionAddTag {
+		value = req.Tag
+	}
+
+	found, missing, err := s.targets(c, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(found) == 0 && len(missing) == 0 {
+		return nil, fiber.NewError(fiber.StatusNotFound, "No users match the filter")
+	}
+	if len(found) > s.Config.MaxUsers {
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("Bulk actions are limited to %d users, narrow the filter", s.Config.MaxUsers))
+	}
+
+	if dryrun.Requested(c) {
+		sample := make([]string, 0, dryrun.SampleSize)
+		for _, userID := range found[:min(len(found), dryrun.SampleSize)] {
+			sample = append(sample, userID.String())
+		}
+		return nil, dryrun.Stop(response.DryRun{
+			Action:   "user.bulk." + req.Action,
+			Affected: map[string]int64{"users": int64(len(found))},
+			Sample:   sample,
+		})
+	}
+
+	job := &model.BulkJob{
+		Action: req.Action,
+		Value:  value,
+		Status: model.BulkJobQueued,
+		Total:  len(found) + len(missing),
+		Failed: len(missing),
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		job.CreatedBy = &actor.ID
+	}
+
+	items := make([]model.BulkJobItem, 0, job.Total)
+	for _, userID := range found {
+		items = append(items, model.BulkJobItem{UserID: userID, Status: model.BulkItemPending})
+	}
+	for _, userID := range missing {
+		items = append(items, model.BulkJobItem{UserID: userID, Status: model.BulkItemFailed, Reason: "User not found"})
+	}
+
+	err = s.DB.WithContext(c.UserContext()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].JobID = job.ID
+		}
+		return tx.CreateInBatches(&items, bulkActionBatchSize).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to queue bulk action: %+v", err)
+		return nil, err
+	}
+
+	metadata := map[string]any{"job_id": job.ID, "action": job.Action, "users": job.Total}
+	if job.Value != "" {
+		metadata["value"] = job.Value
+	}
+	if job.CreatedBy != nil {
+		metadata["actor_id"] = *job.CreatedBy
+	}
+	s.AuditService.Record(c, nil, model.AuditActionBulkActionQueued, metadata)
+
+	return job, nil
+}
+
+// targets returns the IDs of the users the action applies to, oldest first, and the listed IDs that
+// match no user. A filter resolves to at most one user over the limit, enough to reject it.
+func (s *bulkActionService) targets(c *fiber.Ctx, req *validation.BulkUserAction) ([]uuid.UUID, []uuid.UUID, error) {
+	query := s.DB.WithContext(c.UserContext()).Model(new(model.User)).Order("created_at asc")
+
+	var found []uuid.UUID
+	if req.Filter != nil {
+		err := filterUsers(query, req.Filter).Limit(s.Config.MaxUsers+1).Pluck("id", &found).Error
+		if err != nil {
+			s.Log.Errorf("Failed to resolve bulk action users: %+v", err)
+		}
+		return found, nil, err
+	}
+
+	listed := make([]uuid.UUID, 0, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		// Validated as UUIDs already
+		listed = append(listed, uuid.MustParse(userID))
+	}
+	slices.SortFunc(listed, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	listed = slices.Compact(listed)
+
+	if err := query.Where("id IN ?", listed).Pluck("id", &found).Error; err != nil {
+		s.Log.Errorf("Failed to resolve bulk action users: %+v", err)
+		return nil, nil, err
+	}
+
+	var missing []uuid.UUID
+	for _, userID := range listed {
+		if !slices.Contains(found, userID) {
+			missing = append(missing, userID)
+		}
+	}
+	return found, missing, nil
+}
+
+func (s *bulkActionService) GetBulkJob(c *fiber.Ctx, id string) (*model.BulkJob, error) {
+	job := new(model.BulkJob)
+
+	result := s.DB.WithContext(c.UserContext()).First(job, "id = ?", id)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Bulk job not found")
+	}
+
+	if result.Error != nil {
+		s.Log.Errorf("Failed to get bulk job: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	return job, nil
+}
+
+// GetBulkJobItems returns the outcome for every user of the job; users not reached yet are pending
+func (s *bulkActionService) GetBulkJobItems(c *fiber.Ctx, id string) ([]model.BulkJobItem, error) {
+	if _, err := s.GetBulkJob(c, id); err != nil {
+		return nil, err
+	}
+
+	var items []model.BulkJobItem
+	if err := s.DB.WithContext(c.UserContext()).Where("job_id = ?", id).Order("user_id").Find(&items).Error; err != nil {
+		s.Log.Errorf("Failed to get bulk job items: %+v", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (s *bulkActionService) RunNext(ctx context.Context) (bool, error) {
+	job, err := s.claim(ctx)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	// Revisions of the changed users name the admin who queued the action
+	if job.CreatedBy != nil {
+		ctx = revision.WithActor(ctx, *job.CreatedBy)
+	}
+
+	return true, s.run(ctx, job)
+}
+
+// claim marks the next job as running. Rows locked by another instance are skipped, so every
+// instance can poll the queue.
+func (s *bulkActionService) claim(ctx context.Context) (*model.BulkJob, error) {
+	now := s.Clock.Now()
+	job := new(model.BulkJob)
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND updated_at < ?)",
+				model.BulkJobQueued, model.BulkJobRunning, now.Add(-s.Config.StaleAfter)).
+			Order("created_at").
+			First(job).Error
+		if err != nil {
+			return err
+		}
+
+		if job.StartedAt == nil {
+			job.StartedAt = &now
+		}
+		job.Status = model.BulkJobRunning
+		return tx.Model(job).Updates(map[string]any{
+			"status": job.Status, "started_at": job.StartedAt, "updated_at": now,
+		}).Error
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to claim bulk job: %+v", err)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// run applies the action to the job's pending items. When it stops early the job stays running and
+// is claimed again once stale.
+func (s *bulkActionService) run(ctx context.Context, job *model.BulkJob) error {
+	for {
+		var items []model.BulkJobItem
+		err := s.DB.WithContext(ctx).
+			Where("job_id = ? AND status = ?", job.ID, model.BulkItemPending).
+			Order("id").
+			Limit(bulkActionBatchSize).
+			Find(&items).Error
+		if err != nil {
+			s.Log.Errorf("Failed to load bulk job items: %+v", err)
+			return err
+		}
+
+		if len(items) == 0 {
+			break
+		}
+
+		// Read-only mode stops the job between batches; it is claimed again once stale
+		if readonly.Enabled() {
+			return readonly.ErrReadOnly
+		}
+
+		if err := s.runBatch(ctx, job, items); err != nil {
+			return err
+		}
+	}
+
+	now := s.Clock.Now()
+	job.Status = model.BulkJobCompleted
+	job.FinishedAt = &now
+	err := s.DB.WithContext(ctx).Model(job).Updates(map[string]any{
+		"status": job.Status, "finished_at": job.FinishedAt,
+	}).Error
+	if err != nil {
+		s.Log.Errorf("Failed to complete bulk job: %+v", err)
+		return err
+	}
+
+	s.AuditService.RecordSystem(ctx, nil, model.AuditActionBulkActionCompleted, map[string]any{
+		"job_id":    job.ID,
+		"action":    job.Action,
+		"succeeded": job.Succeeded,
+		"skipped":   job.Skipped,
+		"failed":    job.Failed,
+	})
+
+	return nil
+}
+
+// runBatch applies the action to a batch of items. Every succeeded action changes what the cached
+// session holds, so the sessions are dropped together in one round trip, even when the batch stops early.
+func (s *bulkActionService) runBatch(ctx context.Context, job *model.BulkJob, items []model.BulkJobItem) error {
+	changed := make([]string, 0, len(items))
+	defer func() {
+		if s.SessionService == nil || len(changed) == 0 {
+			return
+		}
+		if err := s.SessionService.InvalidateSessions(context.WithoutCancel(ctx), changed...); err != nil {
+			s.Log.Warn("Failed to invalidate cache in bulk action", "error", err)
+		}
+	}()
+
+	for i := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		status, reason := s.apply(ctx, job, items[i].UserID)
+		if status == model.BulkItemSucceeded {
+			changed = append(changed, items[i].UserID.String())
+		}
+		if err := s.finishItem(ctx, job, &items[i], status, reason); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// finishItem stores the outcome of an item and counts it on the job, which also shows the job is alive
+func (s *bulkActionService) finishItem(
+	ctx context.Context, job *model.BulkJob, item *model.BulkJobItem, status, reason string,
+) error {
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(item).Updates(map[string]any{"status": status, "reason": reason}).Error
+		if err != nil {
+			return err
+		}
+		// The counter columns are named after the outcomes
+		return tx.Model(job).Updates(map[string]any{status: gorm.Expr(status + " + 1")}).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to record bulk job item: %+v", err)
+		return err
+	}
+
+	switch status {
+	case model.BulkItemSucceeded:
+		job.Succeeded++
+	case model.BulkItemSkipped:
+		job.Skipped++
+	default:
+		job.Failed++
+	}
+	return nil
+}
+
+// apply runs the job's action for one user and returns the item's outcome and why it did not succeed
+func (s *bulkActionService) apply(ctx context.Context, job *model.BulkJob, userID uuid.UUID) (string, string) {
+	user := new(model.User)
+	if err := s.DB.WithContext(ctx).First(user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.BulkItemFailed, "User not found"
+		}
+		s.Log.Errorf("Failed to load user for bulk action: %+v", err)
+		return model.BulkItemFailed, "Failed to load user"
+	}
+
+	if job.Action != model.BulkActionAddTag && job.CreatedBy != nil && *job.CreatedBy == user.ID {
+		return model.BulkItemSkipped, "Cannot apply to your own account"
+	}
+
+	var skipped string
+	var err error
+	switch job.Action {
+	case model.BulkActionDeactivate:
+		skipped, err = s.deactivate(ctx, job, user)
+	case model.BulkActionDelete:
+		skipped, err = s.delete(ctx, user)
+	case model.BulkActionAssignRole:
+		skipped, err = s.assignRole(ctx, job, user)
+	case model.BulkActionAddTag:
+		skipped, err = s.addTag(ctx, job, user)
+	default:
+		return model.BulkItemFailed, "Unknown action"
+	}
+
+	switch {
+	case err != nil:
+		s.Log.Errorf("Failed to %s user %s in bulk: %+v", job.Action, user.ID, err)
+		return model.BulkItemFailed, "Failed to " + job.Action + " user"
+	case skipped != "":
+		return model.BulkItemSkipped, skipped
+	}
+	return model.BulkItemSucceeded, ""
+}
+
+func (s *bulkActionService) deactivate(ctx context.Context, job *model.BulkJob, user *model.User) (string, error) {
+	if !user.IsActive {
+		return "User is already suspended", nil
+	}
+
+	if err := s.DB.WithContext(ctx).Model(user).Update("is_active", false).Error; err != nil {
+		return "", err
+	}
+
+	s.revoke(ctx, user, revocation.ReasonSuspended)
+	s.audit(ctx, job, user, model.AuditActionSuspended, map[string]any{})
+
+	return "", nil
+}
+
+func (s *bulkActionService) delete(ctx context.Context, user *model.User) (string, error) {
+	if err := checkLegalHold(ctx, s.DB, user.ID.String()); err != nil {
+		if errors.Is(err, ErrLegalHold) {
+			return ErrLegalHold.Message, nil
+		}
+		return "", err
+	}
+
+	result := s.DB.WithContext(ctx).Delete(user)
+	if legalHoldViolation(result.Error) {
+		return ErrLegalHold.Message, nil
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "User is already deleted", nil
+	}
+
+	// Deleted users stay rejected without hitting the database while their tokens linger
+	s.NegativeCache.MarkMissing(ctx, cache.NegativeKindUserID, user.ID.String())
+	s.revoke(ctx, user, revocation.ReasonDeleted)
+
+	return "", nil
+}
+
+func (s *bulkActionService) assignRole(ctx context.Context, job *model.BulkJob, user *model.User) (string, error) {
+	switch {
+	case user.IsService():
+		return "Service accounts hold their own rights", nil
+	case user.Role == job.Value:
+		return "User already has the role", nil
+	}
+
+	from := user.Role
+	if err := s.DB.WithContext(ctx).Model(user).Update("role", job.Value).Error; err != nil {
+		return "", err
+	}
+
+	s.revoke(ctx, user, revocation.ReasonRoleChanged)
+	s.audit(ctx, job, user, model.AuditActionRoleChanged, map[string]any{"from": from, "to": job.Value})
+
+	return "", nil
+}
+
+func (s *bulkActionService) addTag(ctx context.Context, job *model.BulkJob, user *model.User) (string, error) {
+	result := s.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.UserTag{UserID: user.ID, Tag: job.Value})
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "User already has the tag", nil
+	}
+
+	return "", nil
+}
+
+// revoke drops the user's cached state on every instance, as the single-user endpoints do; the
+// session itself is dropped with the rest of the batch
+func (s *bulkActionService) revoke(ctx context.Context, user *model.User, reason string) {
+	id := user.ID.String()
+
+	if s.CacheInvalidator != nil {
+		if err := s.CacheInvalidator.InvalidateUserRelatedCache(ctx, id); err != nil {
+			s.Log.Warnf("failed to invalidate user cache in bulk action: %v", err)
+		}
+	}
+
+	if err := s.Revocations.Publish(ctx, id, reason); err != nil {
+		s.Log.Warnf("failed to broadcast revocation in bulk action: %v", err)
+	}
+}
+
+// audit records a change made by the job about the user, naming the admin who queued it
+func (s *bulkActionService) audit(
+	ctx context.Context, job *model.BulkJob, user *model.User, action string, metadata map[string]any,
+) {
+	metadata["job_id"] = job.ID
+	if job.CreatedBy != nil {
+		metadata["actor_id"] = *job.CreatedBy
+	}
+
+	s.AuditService.RecordSystem(ctx, &user.ID, action, metadata)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/dryrun"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type CacheService interface {
+	GetStats(c *fiber.Ctx) (*response.CacheStats, error)
+	Purge(c *fiber.Ctx, req *validation.PurgeCache) (int, error)
+	DryRunPurge(c *fiber.Ctx, req *validation.PurgeCache) (*response.CacheInvalidation, error)
+	SetEnabled(c *fiber.Ctx, req *validation.CacheState) error
+}
+
+type cacheService struct {
+	Log          *logrus.Logger
+	Validate     *validator.Validate
+	AuditService AuditService
+	CacheAdmin   *cache.CacheAdmin
+	Switch       *cache.ResponseCacheSwitch
+}
+
+// NewCacheService administers the cache; the response cache is switched on and off for every
+// instance through responseCacheSwitch. Purges are audited.
+func NewCacheService(
+	validate *validator.Validate, auditService AuditService, cacheAdmin *cache.CacheAdmin,
+	responseCacheSwitch *cache.ResponseCacheSwitch,
+) CacheService {
+	return &cacheService{
+		Log:          utils.Log,
+		Validate:     validate,
+		AuditService: auditService,
+		CacheAdmin:   cacheAdmin,
+		Switch:       responseCacheSwitch,
+	}
+}
+
+// errNotPurgeable answers purge patterns outside the cache namespaces
+var errNotPurgeable = fiber.NewError(fiber.StatusBadRequest,
+	"Pattern must start with one of "+strings.Join(cache.PurgeablePrefixes, ", "))
+
+func (s *cacheService) GetStats(c *fiber.Ctx) (*response.CacheStats, error) {
+	if s.CacheAdmin == nil {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Cache unavailable")
+	}
+
+	stats, err := s.CacheAdmin.Stats(c.UserContext())
+	if err != nil {
+		s.Log.Errorf("Failed to get cache stats: %+v", err)
+		return nil, err
+	}
+
+	return &response.CacheStats{
+		Enabled:     stats.Enabled,
+		Backend:     stats.Backend,
+		KeyCounts:   stats.KeyCounts,
+		Hits:        stats.Hits,
+		Misses:      stats.Misses,
+		HitRatio:    stats.HitRatio,
+		MemoryUsage: stats.MemoryUsage,
+	}, nil
+}
+
+func (s *cacheService) Purge(c *fiber.Ctx, req *validation.PurgeCache) (int, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return 0, err
+	}
+
+	if s.CacheAdmin == nil {
+		return 0, fiber.NewError(fiber.StatusServiceUnavailable, "Cache unavailable")
+	}
+	if req.Tag == "" && !cache.IsPurgeable(req.Pattern) {
+		return 0, errNotPurgeable
+	}
+
+	if dryrun.Requested(c) {
+		result, err := s.CacheAdmin.DryRun(c.UserContext(), req.Pattern, req.Tag)
+		if err != nil {
+			s.Log.Errorf("Failed to dry-run cache purge: %+v", err)
+			return 0, err
+		}
+		return 0, dryrun.Stop(response.DryRun{
+			Action:   "cache.purge",
+			Affected: map[string]int64{"keys": int64(result.Matched)},
+			Sample:   result.Keys,
+		})
+	}
+
+	var result cache.InvalidationResult
+	var err error
+
+	if req.Tag != "" {
+		result, err = s.CacheAdmin.PurgeByTag(c.UserContext(), req.Tag)
+	} else {
+		result, err = s.CacheAdmin.PurgeByPattern(c.UserContext(), req.Pattern)
+	}
+
+	if err != nil {
+		s.Log.Errorf("Failed to purge cache: %+v", err)
+		return 0, err
+	}
+
+	s.Log.Infof("Purged %d cache keys (pattern: %q, tag: %q)", result.Deleted, req.Pattern, req.Tag)
+
+	metadata := map[string]any{"pattern": result.Pattern, "deleted": result.Deleted}
+	if req.Tag != "" {
+		metadata["tag"] = req.Tag
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, nil, model.AuditActionCachePurged, metadata)
+
+	return result.Deleted, nil
+}
+
+// DryRunPurge reports the keys a purge would delete so patterns can be checked before running it
+func (s *cacheService) DryRunPurge(c *fiber.Ctx, req *validation.PurgeCache) (*response.CacheInvalidation, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	if s.CacheAdmin == nil {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Cache unavailable")
+	}
+	if req.Tag == "" && !cache.IsPurgeable(req.Pattern) {
+		return nil, errNotPurgeable
+	}
+
+	result, err := s.CacheAdmin.DryRun(c.UserContext(), req.Pattern, req.Tag)
+	if err != nil {
+		s.Log.Errorf("Failed to dry-run cache purge: %+v", err)
+		return nil, err
+	}
+
+	keys := result.Keys
+	if keys == nil {
+		keys = []string{}
+	}
+
+	return &response.CacheInvalidation{
+		Pattern:    result.Pattern,
+		Matched:    result.Matched,
+		Keys:       keys,
+		Truncated:  result.Matched > len(keys),
+		DurationMs: float64(result.Duration.Microseconds()) / 1000,
+	}, nil
+}
+
+func (s *cacheService) SetEnabled(c *fiber.Ctx, req *validation.CacheState) error {
+	if err := s.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := s.Switch.Set(c.UserContext(), *req.Enabled); err != nil {
+		if errors.Is(err, cache.ErrStoreUnavailable) {
+			return fiber.NewError(fiber.StatusServiceUnavailable, "Cache unavailable")
+		}
+		s.Log.Errorf("Failed to switch the response cache: %+v", err)
+		return err
+	}
+	s.Log.Warnf("Response cache enabled set to %t", *req.Enabled)
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CacheSkipRulesReload is how often admin skip rules are reloaded, so other instances pick up
+// changes within this delay
+const CacheSkipRulesReload = time.Minute
+
+type CacheSkipRuleService interface {
+	ListRules(c *fiber.Ctx) ([]response.CacheSkipRule, error)
+	CreateRule(c *fiber.Ctx, req *validation.CreateCacheSkipRule) (*model.CacheSkipRule, error)
+	DeleteRule(c *fiber.Ctx, id string) error
+	// Reload applies the configured rules and the admin rules stored in the database
+	Reload(ctx context.Context) error
+	// Watch reloads the rules every interval until ctx is cancelled
+	Watch(ctx context.Context, interval time.Duration)
+}
+
+type cacheSkipRuleService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	AuditService AuditService
+	Configured   []cache.SkipRule
+}
+
+// NewCacheSkipRuleService manages the requests kept out of the response cache: the rules from
+// cacheConfig followed by the admin rules stored in the database
+func NewCacheSkipRuleService(
+	db *gorm.DB, validate *validator.Validate, auditService AuditService, cacheConfig *config.ResponseCacheConfig,
+) CacheSkipRuleService {
+	return &cacheSkipRuleService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		AuditService: auditService,
+		Configured:   configuredSkipRules(cacheConfig),
+	}
+}
+
+func configuredSkipRules(cacheConfig *config.ResponseCacheConfig) []cache.SkipRule {
+	var rules []cache.SkipRule
+	add := func(kind string, values []string) {
+		for _, value := range values {
+			rules = append(rules, cache.SkipRule{Kind: kind, Value: value})
+		}
+	}
+
+	add(cache.SkipPath, cacheConfig.SkipPaths)
+	add(cache.SkipPattern, cacheConfig.SkipPatterns)
+	add(cache.SkipMethod, cacheConfig.SkipMethods)
+	add(cache.SkipHeader, cacheConfig.SkipHeaders)
+
+	return rules
+}
+
+// ListRules returns the configured rules followed by the admin rules
+func (s *cacheSkipRuleService) ListRules(c *fiber.Ctx) ([]response.CacheSkipRule, error) {
+	var stored []model.CacheSkipRule
+	if err := s.DB.WithContext(c.UserContext()).Order("created_at").Find(&stored).Error; err != nil {
+		s.Log.Errorf("Failed to list cache skip rules: %+v", err)
+		return nil, err
+	}
+
+	rules := []response.CacheSkipRule{}
+	for _, rule := range s.Configured {
+		rules = append(rules, response.CacheSkipRule{Kind: rule.Kind, Value: rule.Value, Source: "config"})
+	}
+	for i := range stored {
+		rules = append(rules, response.NewCacheSkipRule(&stored[i]))
+	}
+
+	return rules, nil
+}
+
+// CreateRule stores an admin rule; it applies on this instance at once and on others within
+// CacheSkipRulesReload
+func (s *cacheSkipRuleService) CreateRule(
+	c *fiber.Ctx, req *validation.CreateCacheSkipRule,
+) (*model.CacheSkipRule, error) {
+	req.Value = strings.TrimSpace(req.Value)
+	if req.Kind == cache.SkipMethod {
+		req.Value = strings.ToUpper(req.Value)
+	}
+
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if err := cache.ValidateSkipRule(cache.SkipRule{Kind: req.Kind, Value: req.Value}); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	rule := &model.CacheSkipRule{Kind: req.Kind, Value: req.Value}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		rule.CreatedBy = &actor.ID
+	}
+
+	err := s.DB.WithContext(c.UserContext()).Create(rule).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Rule already exists")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to create cache skip rule: %+v", err)
+		return nil, err
+	}
+
+	s.reloadAfterChange(c)
+	s.audit(c, model.AuditActionCacheSkipRuleAdded, rule)
+
+	return rule, nil
+}
+
+// DeleteRule removes an admin rule; configured rules can only be changed in the environment
+func (s *cacheSkipRuleService) DeleteRule(c *fiber.Ctx, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid rule ID")
+	}
+
+	// RETURNING fills in the deleted rule for the audit entry
+	rule := new(model.CacheSkipRule)
+	result := s.DB.WithContext(c.UserContext()).Clauses(clause.Returning{}).Where("id = ?", id).Delete(rule)
+	if result.Error != nil {
+		s.Log.Errorf("Failed to delete cache skip rule: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Rule not found")
+	}
+
+	s.reloadAfterChange(c)
+	s.audit(c, model.AuditActionCacheSkipRuleRemoved, rule)
+
+	return nil
+}
+
+// Reload compiles the configured and admin rules and applies them to requests. Rules that don't
+// compile are skipped. If the admin rules cannot be loaded, the previous rules are kept, or the
+// configured ones applied if there are none yet.
+func (s *cacheSkipRuleService) Reload(ctx context.Context) error {
+	var stored []model.CacheSkipRule
+	err := s.DB.WithContext(ctx).Order("created_at").Find(&stored).Error
+	if err != nil && cache.CurrentSkipList() != nil {
+		return err
+	}
+
+	rules := slices.Clone(s.Configured)
+	for _, rule := range stored {
+		rules = append(rules, cache.SkipRule{Kind: rule.Kind, Value: rule.Value})
+	}
+
+	valid := make([]cache.SkipRule, 0, len(rules))
+	for _, rule := range rules {
+		if ruleErr := cache.ValidateSkipRule(rule); ruleErr != nil {
+			s.Log.Warnf("Ignoring cache skip rule: %v", ruleErr)
+			continue
+		}
+		valid = append(valid, rule)
+	}
+
+	// Every rule compiled on its own above
+	list, _ := cache.NewSkipList(valid)
+	cache.SetSkipList(list)
+
+	return err
+}
+
+func (s *cacheSkipRuleService) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.Reload(ctx); err != nil {
+			s.Log.Warnf("Failed to reload cache skip rules, using previous rules: %v", err)
+		}
+	}
+}
+
+// reloadAfterChange applies a rule change on this instance without waiting for the next reload
+func (s *cacheSkipRuleService) reloadAfterChange(c *fiber.Ctx) {
+	if err := s.Reload(c.UserContext()); err != nil {
+		s.Log.Warnf("Failed to reload cache skip rules: %v", err)
+	}
+}
+
+func (s *cacheSkipRuleService) audit(c *fiber.Ctx, action string, rule *model.CacheSkipRule) {
+	metadata := map[string]any{"rule_id": rule.ID, "kind": rule.Kind, "value": rule.Value}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, nil, action, metadata)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"app/src/redis"
+	"app/src/response"
+	"app/src/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type CircuitBreakerService interface {
+	GetStatus(c *fiber.Ctx) (*response.CircuitBreakerStatus, error)
+	Trip(c *fiber.Ctx) (*response.CircuitBreakerStatus, error)
+	Reset(c *fiber.Ctx) (*response.CircuitBreakerStatus, error)
+}
+
+type circuitBreakerService struct {
+	Log *logrus.Logger
+}
+
+func NewCircuitBreakerService() CircuitBreakerService {
+	return &circuitBreakerService{
+		Log: utils.Log,
+	}
+}
+
+func (s *circuitBreakerService) GetStatus(c *fiber.Ctx) (*response.CircuitBreakerStatus, error) {
+	status, err := redis.GetBreakerStatus()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Redis circuit breaker not initialized")
+	}
+
+	return toCircuitBreakerResponse(status), nil
+}
+
+func (s *circuitBreakerService) Trip(c *fiber.Ctx) (*response.CircuitBreakerStatus, error) {
+	if err := redis.TripBreaker(); err != nil {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Redis circuit breaker not initialized")
+	}
+
+	s.Log.Warnf("Redis circuit breaker tripped via admin API from %s", c.IP())
+
+	return s.GetStatus(c)
+}
+
+func (s *circuitBreakerService) Reset(c *fiber.Ctx) (*response.CircuitBreakerStatus, error) {
+	if err := redis.ResetBreaker(); err != nil {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Redis circuit breaker not initialized")
+	}
+
+	s.Log.Warnf("Redis circuit breaker reset via admin API from %s", c.IP())
+
+	return s.GetStatus(c)
+}
+
+func toCircuitBreakerResponse(status *redis.BreakerStatus) *response.CircuitBreakerStatus {
+	transitions := make([]response.CircuitBreakerTransition, len(status.Transitions))
+	for i, t := range status.Transitions {
+		transitions[i] = response.CircuitBreakerTransition{From: t.From, To: t.To, At: t.At}
+	}
+
+	return &response.CircuitBreakerStatus{
+		Name:   status.Name,
+		State:  status.State,
+		Forced: status.Forced,
+		Counts: response.CircuitBreakerCounts{
+			Requests:             status.Counts.Requests,
+			TotalSuccesses:       status.Counts.TotalSuccesses,
+			TotalFailures:        status.Counts.TotalFailures,
+			ConsecutiveSuccesses: status.Counts.ConsecutiveSuccesses,
+			ConsecutiveFailures:  status.Counts.ConsecutiveFailures,
+		},
+		Transitions: transitions,
+	}
+}
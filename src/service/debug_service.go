@@ -0,0 +1,118 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/model"
+	"app/src/response"
+	"app/src/tracing"
+	"app/src/utils"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type DebugService interface {
+	GetCapture(c *fiber.Ctx, requestID string) (*response.DebugCapture, error)
+	// GetRequestTrace returns the audit entries, queued emails and capture of a request, so support
+	// can troubleshoot from the request ID a user quotes
+	GetRequestTrace(c *fiber.Ctx, requestID string) (*response.RequestTrace, error)
+}
+
+type debugService struct {
+	Log   *logrus.Logger
+	DB    *gorm.DB
+	Store cache.Store
+}
+
+func NewDebugService(db *gorm.DB, store cache.Store) DebugService {
+	return &debugService{
+		Log:   utils.Log,
+		DB:    db,
+		Store: store,
+	}
+}
+
+func (s *debugService) GetCapture(_ *fiber.Ctx, requestID string) (*response.DebugCapture, error) {
+	if !cache.IsStoreAvailable(s.Store) {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Cache unavailable")
+	}
+
+	data, err := s.Store.Get(cache.GetDebugCaptureKey(requestID))
+	if err != nil {
+		s.Log.Errorf("Failed to get debug capture: %+v", err)
+		return nil, err
+	}
+	if data == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Capture not found")
+	}
+
+	capture := new(response.DebugCapture)
+	if err := json.Unmarshal(data, capture); err != nil {
+		s.Log.Errorf("Failed to decode debug capture: %+v", err)
+		return nil, err
+	}
+
+	return capture, nil
+}
+
+func (s *debugService) GetRequestTrace(c *fiber.Ctx, requestID string) (*response.RequestTrace, error) {
+	if !tracing.ValidRequestID(requestID) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid request ID")
+	}
+
+	var entries []model.AuditLog
+	if err := s.DB.WithContext(c.UserContext()).
+		Where("request_id = ?", requestID).Order("created_at, id").Find(&entries).Error; err != nil {
+		s.Log.Errorf("Failed to get audit logs of request: %+v", err)
+		return nil, err
+	}
+
+	var emails []model.OutboxEmail
+	if err := s.DB.WithContext(c.UserContext()).
+		Where("request_id = ?", requestID).Order("created_at, id").Find(&emails).Error; err != nil {
+		s.Log.Errorf("Failed to get outbox emails of request: %+v", err)
+		return nil, err
+	}
+
+	trace := &response.RequestTrace{
+		RequestID: requestID,
+		AuditLogs: make([]response.RequestTraceAudit, len(entries)),
+		Emails:    make([]response.RequestTraceEmail, len(emails)),
+	}
+	for i, entry := range entries {
+		trace.AuditLogs[i] = response.RequestTraceAudit{
+			ID:        entry.ID,
+			UserID:    entry.UserID,
+			ActorType: entry.ActorType,
+			Action:    entry.Action,
+			IP:        entry.IP,
+			UserAgent: entry.UserAgent,
+			Metadata:  json.RawMessage(entry.Metadata),
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+	for i, email := range emails {
+		trace.Emails[i] = response.RequestTraceEmail{
+			ID:        email.ID,
+			Recipient: utils.Redact(email.Recipient),
+			Subject:   email.Subject,
+			Status:    email.Status,
+			Attempts:  email.Attempts,
+			LastError: utils.Redact(email.LastError),
+			CreatedAt: email.CreatedAt,
+		}
+	}
+
+	// Captures are optional and expire; a trace without one is still worth returning
+	if capture, err := s.GetCapture(c, requestID); err == nil {
+		trace.Capture = capture
+	}
+
+	if len(entries) == 0 && len(emails) == 0 && trace.Capture == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Request not found")
+	}
+
+	return trace, nil
+}
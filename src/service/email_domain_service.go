@@ -0,0 +1,215 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/emaildomain"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// emailDomainRulesTTL is how long admin rules are served from memory, so other instances
+// pick up changes within this delay
+const emailDomainRulesTTL = time.Minute
+
+// ErrEmailDomainNotAllowed rejects sign-ups from blocked, disposable or unlisted domains; 422 keeps it
+// apart from the 400 of malformed input
+var ErrEmailDomainNotAllowed = fiber.NewError(fiber.StatusUnprocessableEntity, "Email domain is not allowed")
+
+type EmailDomainService interface {
+	Check(ctx context.Context, email string) error
+	ListRules(c *fiber.Ctx) (*response.EmailDomainRules, error)
+	CreateRule(c *fiber.Ctx, req *validation.CreateEmailDomainRule) (*model.EmailDomainRule, error)
+	DeleteRule(c *fiber.Ctx, id string) error
+}
+
+type emailDomainService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	AuditService AuditService
+	Disposable   *emaildomain.DisposableList
+
+	mu       sync.Mutex
+	allow    []string
+	block    []string
+	loadedAt time.Time
+}
+
+// NewEmailDomainService checks sign-up emails against the configured rules, the admin rules
+// stored in the database and, when enabled, the disposable domain list (which may be nil)
+func NewEmailDomainService(
+	db *gorm.DB, validate *validator.Validate, auditService AuditService, disposable *emaildomain.DisposableList,
+) EmailDomainService {
+	return &emailDomainService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		AuditService: auditService,
+		Disposable:   disposable,
+	}
+}
+
+// Check rejects the email if its domain is blocked, disposable (unless explicitly allowed) or,
+// when an allowlist exists, not on it
+func (s *emailDomainService) Check(ctx context.Context, email string) error {
+	domain := emaildomain.Domain(email)
+	allow, block := s.rules(ctx)
+
+	if emaildomain.MatchesAny(block, domain) {
+		return ErrEmailDomainNotAllowed
+	}
+
+	allowed := emaildomain.MatchesAny(allow, domain)
+	if !allowed && config.EmailDomains.BlockDisposable && s.Disposable.Contains(domain) {
+		return ErrEmailDomainNotAllowed
+	}
+
+	if len(allow) > 0 && !allowed {
+		return ErrEmailDomainNotAllowed
+	}
+
+	return nil
+}
+
+// checkEmailDomain applies the sign-up domain rules, if configured
+func checkEmailDomain(ctx context.Context, domains EmailDomainService, email string) error {
+	if domains == nil {
+		return nil
+	}
+	return domains.Check(ctx, email)
+}
+
+// ListRules returns the configured rules followed by the admin rules
+func (s *emailDomainService) ListRules(c *fiber.Ctx) (*response.EmailDomainRules, error) {
+	var stored []model.EmailDomainRule
+	if err := s.DB.WithContext(c.UserContext()).Order("created_at").Find(&stored).Error; err != nil {
+		s.Log.Errorf("Failed to list email domain rules: %+v", err)
+		return nil, err
+	}
+
+	rules := []response.EmailDomainRule{}
+	for _, pattern := range config.EmailDomains.Allow {
+		rules = append(rules, response.EmailDomainRule{Pattern: pattern, Action: model.EmailDomainAllow, Source: "config"})
+	}
+	for _, pattern := range config.EmailDomains.Block {
+		rules = append(rules, response.EmailDomainRule{Pattern: pattern, Action: model.EmailDomainBlock, Source: "config"})
+	}
+	for i := range stored {
+		rules = append(rules, response.NewEmailDomainRule(&stored[i]))
+	}
+
+	return &response.EmailDomainRules{
+		Rules:             rules,
+		BlockDisposable:   config.EmailDomains.BlockDisposable,
+		DisposableDomains: s.Disposable.Len(),
+	}, nil
+}
+
+// CreateRule stores an admin rule; it applies on this instance at once and on others within a minute
+func (s *emailDomainService) CreateRule(
+	c *fiber.Ctx, req *validation.CreateEmailDomainRule,
+) (*model.EmailDomainRule, error) {
+	req.Pattern = strings.ToLower(strings.TrimSpace(req.Pattern))
+
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	rule := &model.EmailDomainRule{Pattern: req.Pattern, Action: req.Action}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		rule.CreatedBy = &actor.ID
+	}
+
+	err := s.DB.WithContext(c.UserContext()).Create(rule).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Rule already exists")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to create email domain rule: %+v", err)
+		return nil, err
+	}
+
+	s.forget()
+	s.audit(c, model.AuditActionDomainRuleAdded, rule)
+
+	return rule, nil
+}
+
+// DeleteRule removes an admin rule; configured rules can only be changed in the environment
+func (s *emailDomainService) DeleteRule(c *fiber.Ctx, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid rule ID")
+	}
+
+	// RETURNING fills in the deleted rule for the audit entry
+	rule := new(model.EmailDomainRule)
+	result := s.DB.WithContext(c.UserContext()).Clauses(clause.Returning{}).Where("id = ?", id).Delete(rule)
+	if result.Error != nil {
+		s.Log.Errorf("Failed to delete email domain rule: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Rule not found")
+	}
+
+	s.forget()
+	s.audit(c, model.AuditActionDomainRuleRemoved, rule)
+
+	return nil
+}
+
+// rules returns the configured and admin allow and block patterns, reloading admin rules once
+// they are older than emailDomainRulesTTL. If reloading fails the previous rules are kept.
+func (s *emailDomainService) rules(ctx context.Context) ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.loadedAt) >= emailDomainRulesTTL {
+		var stored []model.EmailDomainRule
+		if err := s.DB.WithContext(ctx).Find(&stored).Error; err != nil {
+			s.Log.Warnf("Failed to load email domain rules, using previous rules: %v", err)
+		} else {
+			s.allow, s.block = nil, nil
+			for _, rule := range stored {
+				if rule.Action == model.EmailDomainAllow {
+					s.allow = append(s.allow, rule.Pattern)
+				} else {
+					s.block = append(s.block, rule.Pattern)
+				}
+			}
+		}
+		s.loadedAt = time.Now()
+	}
+
+	return slices.Concat(config.EmailDomains.Allow, s.allow), slices.Concat(config.EmailDomains.Block, s.block)
+}
+
+// forget makes the next check reload the admin rules
+func (s *emailDomainService) forget() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *emailDomainService) audit(c *fiber.Ctx, action string, rule *model.EmailDomainRule) {
+	metadata := map[string]any{"rule_id": rule.ID, "pattern": rule.Pattern, "action": rule.Action}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, nil, action, metadata)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/tracing"
+	"app/src/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// outboxBatchSize is how many emails one delivery round claims
+	outboxBatchSize = 50
+	// outboxLease holds claimed emails back from other instances while they are sent; an instance
+	// that dies mid-send leaves them to be retried once it passes
+	outboxLease = 2 * time.Minute
+	// maxOutboxError matches what is worth keeping of an SMTP error
+	maxOutboxError = 1000
+)
+
+type EmailOutboxService interface {
+	// QueueVerificationEmail stores the email verifying the address, to be sent by DeliverDue
+	QueueVerificationEmail(ctx context.Context, to, token string) error
+	// DeliverDue sends the emails due for an attempt and returns how many were sent
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type emailOutboxService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	EmailService EmailService
+	Config       config.EmailOutboxConfig
+	Clock        clock.Clock
+}
+
+// NewEmailOutboxService queues emails in the database so a failed send is retried later, by any
+// instance, instead of failing the request that caused it
+func NewEmailOutboxService(
+	db *gorm.DB, emailService EmailService, cfg config.EmailOutboxConfig, clk clock.Clock,
+) EmailOutboxService {
+	return &emailOutboxService{
+		Log:          utils.Log,
+		DB:           db,
+		EmailService: emailService,
+		Config:       cfg,
+		Clock:        clock.OrSystem(clk),
+	}
+}
+
+func (s *emailOutboxService) QueueVerificationEmail(ctx context.Context, to, token string) error {
+	subject, body := verificationEmail(token)
+	return s.enqueue(ctx, to, subject, body)
+}
+
+func (s *emailOutboxService) enqueue(ctx context.Context, to, subject, body string) error {
+	email := &model.OutboxEmail{
+		Recipient:     to,
+		Subject:       subject,
+		Body:          body,
+		Status:        model.OutboxEmailPending,
+		NextAttemptAt: s.Clock.Now(),
+		RequestID:     tracing.RequestID(ctx),
+	}
+
+	if err := s.DB.WithContext(ctx).Create(email).Error; err != nil {
+		s.Log.Errorf("Failed to queue email: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *emailOutboxService) DeliverDue(ctx context.Context) (int, error) {
+	emails, err := s.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range emails {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		email := &emails[i]
+		sendCtx := ctx
+		if email.RequestID != "" {
+			sendCtx = tracing.WithRequestID(ctx, email.RequestID)
+		}
+		err := s.EmailService.SendEmail(sendCtx, email.Recipient, email.Subject, email.Body)
+		if errors.Is(err, ErrEmailThrottled) {
+			s.postpone(ctx, email)
+			continue
+		}
+		if err != nil {
+			s.retry(ctx, email, err)
+			continue
+		}
+
+		if err := s.DB.WithContext(ctx).Delete(email).Error; err != nil {
+			// The email went out; if the row stays, it is sent again once the lease passes
+			s.Log.Errorf("Failed to remove sent email from the outbox: %+v", err)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// claim leases the emails due, skipping those another instance is claiming at the same time
+func (s *emailOutboxService) claim(ctx context.Context) ([]model.OutboxEmail, error) {
+	now := s.Clock.Now()
+	var emails []model.OutboxEmail
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.OutboxEmailPending, now).
+			Order("next_attempt_at").
+			Limit(outboxBatchSize).
+			Find(&emails).Error
+		if err != nil || len(emails) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(emails))
+		for i := range emails {
+			ids[i] = emails[i].ID
+		}
+		return tx.Model(new(model.OutboxEmail)).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(outboxLease)).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to claim outbox emails: %+v", err)
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// retry schedules the next attempt of an email that failed to send, or gives up on it
+func (s *emailOutboxService) retry(ctx context.Context, email *model.OutboxEmail, sendErr error) {
+	attempts := email.Attempts + 1
+	message := sendErr.Error()
+	if len(message) > maxOutboxError {
+		message = message[:maxOutboxError]
+	}
+
+	updates := map[string]any{
+		"attempts":        attempts,
+		"last_error":      message,
+		"next_attempt_at": s.Clock.Now().Add(s.backoff(attempts)),
+	}
+	if attempts >= s.Config.MaxAttempts {
+		updates["status"] = model.OutboxEmailFailed
+		s.Log.Errorf("Giving up on email %q after %d attempts: %v", email.Subject, attempts, sendErr)
+	}
+
+	if err := s.DB.WithContext(ctx).Model(email).Updates(updates).Error; err != nil {
+		s.Log.Errorf("Failed to schedule email retry: %+v", err)
+	}
+}
+
+// postpone schedules a throttled email for after the retry backoff without counting an attempt, so
+// throttling alone never makes an email fail
+func (s *emailOutboxService) postpone(ctx context.Context, email *model.OutboxEmail) {
+	err := s.DB.WithContext(ctx).Model(email).
+		Update("next_attempt_at", s.Clock.Now().Add(s.Config.RetryBackoff)).Error
+	if err != nil {
+		s.Log.Errorf("Failed to postpone throttled email: %+v", err)
+	}
+}
+
+// backoff returns the wait before the attempt after the given number of failed ones
+func (s *emailOutboxService) backoff(attempts int) time.Duration {
+	wait := s.Config.RetryBackoff
+	for i := 1; i < attempts && wait < s.Config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, s.Config.MaxBackoff)
+}
@@ -1,27 +1,48 @@
 package service
 
 import (
+	"app/src/cache"
+	"app/src/clock"
 	"app/src/config"
+	"app/src/deadline"
+	"app/src/model"
+	"app/src/tracing"
 	"app/src/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/gomail.v2"
 )
 
+// ErrEmailThrottled means the recipient or all instances together have been sent as many emails as
+// the throttles allow; the outbox tries such emails again later
+var ErrEmailThrottled = fiber.NewError(fiber.StatusTooManyRequests, "Too many emails sent. Please try again later.")
+
 type EmailService interface {
-	SendEmail(to, subject, body string) error
-	SendResetPasswordEmail(to, token string) error
-	SendVerificationEmail(to, token string) error
+	SendEmail(ctx context.Context, to, subject, body string) error
+	SendResetPasswordEmail(ctx context.Context, to, token string) error
+	SendVerificationEmail(ctx context.Context, to, token string) error
 }
 
 type emailService struct {
-	Log    *logrus.Logger
-	Dialer *gomail.Dialer
+	Log          *logrus.Logger
+	Dialer       *gomail.Dialer
+	Suppressions EmailSuppressionService
+	Store        cache.Store
+	Counter      cache.Counter
+	Clock        clock.Clock
 }
 
-func NewEmailService() EmailService {
-	return &emailService{
+// NewEmailService sends email over SMTP, skipping suppressed recipients (suppressions may be nil)
+// and applying the outbound throttles while the cache store is available
+func NewEmailService(store cache.Store, suppressions EmailSuppressionService, clk clock.Clock) EmailService {
+	s := &emailService{
 		Log: utils.Log,
 		Dialer: gomail.NewDialer(
 			config.SMTPHost,
@@ -29,17 +50,65 @@ func NewEmailService() EmailService {
 			config.SMTPUsername,
 			config.SMTPPassword,
 		),
+		Suppressions: suppressions,
+		Store:        store,
+		Clock:        clock.OrSystem(clk),
+	}
+	if counter, ok := store.(cache.Counter); ok {
+		s.Counter = counter
 	}
+
+	return s
 }
 
-func (s *emailService) SendEmail(to, subject, body string) error {
+// SendEmail sends a plain text email, giving up at the SMTP deadline derived from ctx. The SMTP
+// client takes no context, so a send given up on still finishes in the background.
+func (s *emailService) SendEmail(ctx context.Context, to, subject, body string) error {
+	// Service accounts have no mailbox; their addresses are reserved and never delivered to
+	if strings.HasSuffix(strings.ToLower(to), "@"+model.ServiceAccountEmailDomain) {
+		s.Log.Debugf("Skipped email %q to a service account", subject)
+		return nil
+	}
+
+	if s.Suppressions != nil {
+		suppressed, err := s.Suppressions.IsSuppressed(ctx, to)
+		if err != nil {
+			return err
+		}
+		// Sending to bounced or complaining addresses hurts the sender's reputation with every provider
+		if suppressed {
+			s.Log.Debugf("Skipped email %q to a suppressed recipient", subject)
+			return nil
+		}
+	}
+
+	if err := s.throttle(ctx, to); err != nil {
+		return err
+	}
+
 	mailer := gomail.NewMessage()
 	mailer.SetHeader("From", config.EmailFrom)
 	mailer.SetHeader("To", to)
 	mailer.SetHeader("Subject", subject)
 	mailer.SetBody("text/plain", body)
+	// Lets support trace a received email back to the request that sent it
+	if requestID := tracing.RequestID(ctx); requestID != "" {
+		mailer.SetHeader(fiber.HeaderXRequestID, requestID)
+	}
+
+	ctx, cancel := deadline.For(ctx, config.DeadlineSMTP)
+	defer cancel()
+
+	sent := make(chan error, 1)
+	go func() { sent <- s.Dialer.DialAndSend(mailer) }()
 
-	if err := s.Dialer.DialAndSend(mailer); err != nil {
+	var err error
+	select {
+	case err = <-sent:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
 		s.Log.Errorf("Failed to send email: %v", err)
 		return err
 	}
@@ -47,7 +116,48 @@ func (s *emailService) SendEmail(to, subject, body string) error {
 	return nil
 }
 
-func (s *emailService) SendResetPasswordEmail(to, token string) error {
+// throttle counts the email against the recipient and global limits, in fixed windows shared by all
+// instances. Without the cache store emails are not throttled.
+func (s *emailService) throttle(ctx context.Context, to string) error {
+	if s.Counter == nil || !cache.IsStoreAvailable(s.Store) {
+		return nil
+	}
+
+	cfg := config.EmailSending
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(to))))
+	limits := []struct {
+		name   string
+		scope  string
+		limit  int
+		window time.Duration
+	}{
+		{"recipient", "recipient:" + hex.EncodeToString(sum[:]), cfg.RecipientLimit, cfg.RecipientWindow},
+		{"global", "global", cfg.GlobalLimit, cfg.GlobalWindow},
+	}
+
+	now := s.Clock.Now()
+	for _, l := range limits {
+		if l.limit <= 0 {
+			continue
+		}
+
+		start := now.Truncate(l.window)
+		key := fmt.Sprintf("%s%s:%d", cache.EmailRateKeyPrefix, l.scope, start.Unix())
+		count, err := s.Counter.IncrBy(ctx, key, 1, l.window)
+		if err != nil {
+			s.Log.Warnf("Failed to count outbound email: %v", err)
+			return nil
+		}
+		if count > int64(l.limit) {
+			s.Log.Warnf("Throttled email: %s limit of %d per %s reached", l.name, l.limit, l.window)
+			return ErrEmailThrottled
+		}
+	}
+
+	return nil
+}
+
+func (s *emailService) SendResetPasswordEmail(ctx context.Context, to, token string) error {
 	subject := "Reset password"
 
 	// TODO: replace this url with the link to the reset password page of your front-end app
@@ -57,10 +167,16 @@ func (s *emailService) SendResetPasswordEmail(to, token string) error {
 To reset your password, click on this link: %s
 
 If you did not request any password resets, then ignore this email.`, resetPasswordURL)
-	return s.SendEmail(to, subject, body)
+	return s.SendEmail(ctx, to, subject, body)
+}
+
+func (s *emailService) SendVerificationEmail(ctx context.Context, to, token string) error {
+	subject, body := verificationEmail(token)
+	return s.SendEmail(ctx, to, subject, body)
 }
 
-func (s *emailService) SendVerificationEmail(to, token string) error {
+// verificationEmail renders the email with the link verifying an address
+func verificationEmail(token string) (string, string) {
 	subject := "Email Verification"
 
 	// TODO: replace this url with the link to the email verification page of your front-end app
@@ -70,5 +186,5 @@ func (s *emailService) SendVerificationEmail(to, token string) error {
 To verify your email, click on this link: %s
 
 If you did not create an account, then ignore this email.`, verificationEmailURL)
-	return s.SendEmail(to, subject, body)
+	return subject, body
 }
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/emailfeedback"
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSuppressionDetail keeps provider diagnostics to what is worth reading
+const maxSuppressionDetail = 500
+
+type EmailSuppressionService interface {
+	// IsSuppressed reports whether email must not be sent to
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+	ListSuppressions(c *fiber.Ctx, params *validation.QueryEmailSuppression) ([]model.EmailSuppression, int64, error)
+	CreateSuppression(c *fiber.Ctx, req *validation.CreateEmailSuppression) (*model.EmailSuppression, error)
+	DeleteSuppression(c *fiber.Ctx, id string) error
+	// HandleWebhook suppresses the recipients a provider reports as bounced, complaining or unsubscribed
+	HandleWebhook(c *fiber.Ctx, provider string) error
+}
+
+type emailSuppressionService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	AuditService AuditService
+}
+
+// NewEmailSuppressionService keeps the recipients emails are no longer sent to, fed by provider
+// webhooks and admins
+func NewEmailSuppressionService(
+	db *gorm.DB, validate *validator.Validate, auditService AuditService,
+) EmailSuppressionService {
+	return &emailSuppressionService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		AuditService: auditService,
+	}
+}
+
+func (s *emailSuppressionService) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := s.DB.WithContext(ctx).
+		Model(new(model.EmailSuppression)).
+		Where("email = ?", strings.ToLower(strings.TrimSpace(email))).
+		Count(&count).Error
+	if err != nil {
+		s.Log.Errorf("Failed to check email suppression: %+v", err)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (s *emailSuppressionService) ListSuppressions(
+	c *fiber.Ctx, params *validation.QueryEmailSuppression,
+) ([]model.EmailSuppression, int64, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, 0, err
+	}
+
+	query := s.DB.WithContext(c.UserContext()).Model(new(model.EmailSuppression))
+	if params.Search != "" {
+		query = query.Where("email LIKE ?", "%"+strings.ToLower(params.Search)+"%")
+	}
+	if params.Reason != "" {
+		query = query.Where("reason = ?", params.Reason)
+	}
+
+	var totalResults int64
+	if err := query.Count(&totalResults).Error; err != nil {
+		s.Log.Errorf("Failed to count email suppressions: %+v", err)
+		return nil, 0, err
+	}
+
+	var suppressions []model.EmailSuppression
+	offset := (params.Page - 1) * params.Limit
+	if err := query.Order("created_at DESC, id").Limit(params.Limit).Offset(offset).Find(&suppressions).Error; err != nil {
+		s.Log.Errorf("Failed to list email suppressions: %+v", err)
+		return nil, 0, err
+	}
+
+	return suppressions, totalResults, nil
+}
+
+func (s *emailSuppressionService) CreateSuppression(
+	c *fiber.Ctx, req *validation.CreateEmailSuppression,
+) (*model.EmailSuppression, error) {
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	suppression := &model.EmailSuppression{
+		Email:  req.Email,
+		Reason: model.EmailSuppressionManual,
+		Source: "admin",
+		Detail: req.Detail,
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		suppression.CreatedBy = &actor.ID
+	}
+
+	err := s.DB.WithContext(c.UserContext()).Create(suppression).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Email is already suppressed")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to create email suppression: %+v", err)
+		return nil, err
+	}
+
+	s.audit(c, model.AuditActionSuppressionAdded, suppression)
+
+	return suppression, nil
+}
+
+// DeleteSuppression lets emails reach the recipient again, such as after a mailbox was fixed
+func (s *emailSuppressionService) DeleteSuppression(c *fiber.Ctx, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid suppression ID")
+	}
+
+	// RETURNING fills in the deleted suppression for the audit entry
+	suppression := new(model.EmailSuppression)
+	result := s.DB.WithContext(c.UserContext()).Clauses(clause.Returning{}).Where("id = ?", id).Delete(suppression)
+	if result.Error != nil {
+		s.Log.Errorf("Failed to delete email suppression: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Suppression not found")
+	}
+
+	s.audit(c, model.AuditActionSuppressionRemoved, suppression)
+
+	return nil
+}
+
+// HandleWebhook authenticates the webhook with the token query parameter, since neither SNS nor
+// SendGrid can sign with a shared secret. SNS subscription confirmations are logged for an operator
+// to visit rather than fetched, so a forged one cannot make the app request arbitrary URLs.
+func (s *emailSuppressionService) HandleWebhook(c *fiber.Ctx, provider string) error {
+	secret := config.EmailSending.WebhookSecret
+	if secret == "" {
+		return fiber.NewError(fiber.StatusNotFound, "Email webhooks are not configured")
+	}
+	if subtle.ConstantTimeCompare([]byte(c.Query("token")), []byte(secret)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid webhook token")
+	}
+
+	feedback, err := emailfeedback.Parse(provider, c.Body())
+	if errors.Is(err, emailfeedback.ErrUnknownProvider) {
+		return fiber.NewError(fiber.StatusNotFound, "Unknown email provider")
+	}
+	if err != nil {
+		s.Log.Warnf("Rejected %s email webhook: %v", provider, err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid webhook payload")
+	}
+
+	if feedback.SubscribeURL != "" {
+		s.Log.Infof("SNS subscription for %s email feedback awaits confirmation at %s", provider, feedback.SubscribeURL)
+		return nil
+	}
+	if len(feedback.Events) == 0 {
+		return nil
+	}
+
+	suppressions := make([]model.EmailSuppression, len(feedback.Events))
+	for i, event := range feedback.Events {
+		detail := event.Detail
+		if len(detail) > maxSuppressionDetail {
+			detail = detail[:maxSuppressionDetail]
+		}
+		suppressions[i] = model.EmailSuppression{Email: event.Email, Reason: event.Reason, Source: provider, Detail: detail}
+	}
+
+	// Redelivered notifications and already suppressed recipients keep their first reason; errors
+	// make the provider deliver the notification again
+	result := s.DB.WithContext(c.UserContext()).Clauses(clause.OnConflict{DoNothing: true}).Create(&suppressions)
+	if result.Error != nil {
+		s.Log.Errorf("Failed to store email suppressions: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		s.Log.Infof("Suppressed %d email recipients reported by %s", result.RowsAffected, provider)
+	}
+
+	return nil
+}
+
+func (s *emailSuppressionService) audit(c *fiber.Ctx, action string, suppression *model.EmailSuppression) {
+	metadata := map[string]any{
+		"suppression_id": suppression.ID, "email": suppression.Email, "reason": suppression.Reason,
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, nil, action, metadata)
+}
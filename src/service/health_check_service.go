@@ -1,7 +1,9 @@
 package service
 
 import (
+	"app/src/leader"
 	"app/src/redis"
+	"app/src/response"
 	"app/src/utils"
 	"errors"
 	"runtime"
@@ -14,19 +16,23 @@ type HealthCheckService interface {
 	GormCheck() error
 	MemoryHeapCheck() error
 	RedisCheck() bool
+	IsLeader() bool
+	DBPoolStats() (*response.DBPoolStats, error)
 }
 
 type healthCheckService struct {
 	Log           *logrus.Logger
 	DB            *gorm.DB
 	HealthMonitor *redis.HealthMonitor
+	Elector       *leader.Elector
 }
 
-func NewHealthCheckService(db *gorm.DB, healthMonitor *redis.HealthMonitor) HealthCheckService {
+func NewHealthCheckService(db *gorm.DB, healthMonitor *redis.HealthMonitor, elector *leader.Elector) HealthCheckService {
 	return &healthCheckService{
 		Log:           utils.Log,
 		DB:            db,
 		HealthMonitor: healthMonitor,
+		Elector:       elector,
 	}
 }
 
@@ -54,6 +60,33 @@ func (s *healthCheckService) RedisCheck() bool {
 	return s.HealthMonitor.IsAvailable()
 }
 
+// DBPoolStats returns the current sql.DB connection pool statistics
+func (s *healthCheckService) DBPoolStats() (*response.DBPoolStats, error) {
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		s.Log.Errorf("failed to access the database connection pool: %v", err)
+		return nil, err
+	}
+
+	stats := sqlDB.Stats()
+
+	return &response.DBPoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}, nil
+}
+
+// IsLeader returns true if this instance runs the background workers
+func (s *healthCheckService) IsLeader() bool {
+	return s.Elector.IsLeader()
+}
+
 // MemoryHeapCheck checks if heap memory usage exceeds a threshold
 func (s *healthCheckService) MemoryHeapCheck() error {
 	var memStats runtime.MemStats
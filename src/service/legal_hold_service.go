@@ -0,0 +1,142 @@
+package service
+
+import (
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrLegalHold rejects deleting a user under legal hold
+var ErrLegalHold = fiber.NewError(fiber.StatusConflict, "User is under legal hold")
+
+type LegalHoldService interface {
+	ListLegalHolds(c *fiber.Ctx, params *validation.QueryLegalHold) ([]model.LegalHold, int64, error)
+	PlaceLegalHold(c *fiber.Ctx, req *validation.CreateLegalHold) (*model.LegalHold, error)
+	LiftLegalHold(c *fiber.Ctx, userID string) error
+}
+
+type legalHoldService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	AuditService AuditService
+}
+
+// NewLegalHoldService places and lifts the legal holds keeping users from being deleted
+func NewLegalHoldService(db *gorm.DB, validate *validator.Validate, auditService AuditService) LegalHoldService {
+	return &legalHoldService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		AuditService: auditService,
+	}
+}
+
+func (s *legalHoldService) ListLegalHolds(
+	c *fiber.Ctx, params *validation.QueryLegalHold,
+) ([]model.LegalHold, int64, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, 0, err
+	}
+
+	query := s.DB.WithContext(c.UserContext()).Model(new(model.LegalHold))
+
+	var totalResults int64
+	if err := query.Count(&totalResults).Error; err != nil {
+		s.Log.Errorf("Failed to count legal holds: %+v", err)
+		return nil, 0, err
+	}
+
+	var holds []model.LegalHold
+	offset := (params.Page - 1) * params.Limit
+	if err := query.Order("created_at DESC, user_id").Limit(params.Limit).Offset(offset).Find(&holds).Error; err != nil {
+		s.Log.Errorf("Failed to list legal holds: %+v", err)
+		return nil, 0, err
+	}
+
+	return holds, totalResults, nil
+}
+
+func (s *legalHoldService) PlaceLegalHold(c *fiber.Ctx, req *validation.CreateLegalHold) (*model.LegalHold, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	hold := &model.LegalHold{UserID: uuid.MustParse(req.UserID), Reason: req.Reason}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		hold.PlacedBy = &actor.ID
+	}
+
+	err := s.DB.WithContext(c.UserContext()).Create(hold).Error
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return nil, fiber.NewError(fiber.StatusConflict, "User is already under legal hold")
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	case err != nil:
+		s.Log.Errorf("Failed to place legal hold: %+v", err)
+		return nil, err
+	}
+
+	s.audit(c, model.AuditActionLegalHoldPlaced, hold)
+
+	return hold, nil
+}
+
+// LiftLegalHold lets the user be deleted again; their data past its retention goes with the next purge
+func (s *legalHoldService) LiftLegalHold(c *fiber.Ctx, userID string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// RETURNING fills in the lifted hold for the audit entry
+	hold := new(model.LegalHold)
+	result := s.DB.WithContext(c.UserContext()).Clauses(clause.Returning{}).Where("user_id = ?", userID).Delete(hold)
+	if result.Error != nil {
+		s.Log.Errorf("Failed to lift legal hold: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "User is not under legal hold")
+	}
+
+	s.audit(c, model.AuditActionLegalHoldLifted, hold)
+
+	return nil
+}
+
+func (s *legalHoldService) audit(c *fiber.Ctx, action string, hold *model.LegalHold) {
+	metadata := map[string]any{"reason": hold.Reason}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, &hold.UserID, action, metadata)
+}
+
+// checkLegalHold returns ErrLegalHold when the user is under legal hold. The foreign key of
+// legal_holds also fails the delete of a user held in the meantime; see legalHoldViolation.
+func checkLegalHold(ctx context.Context, db *gorm.DB, userID string) error {
+	var held int64
+	if err := db.WithContext(ctx).Model(new(model.LegalHold)).Where("user_id = ?", userID).Count(&held).Error; err != nil {
+		return err
+	}
+	if held > 0 {
+		return ErrLegalHold
+	}
+	return nil
+}
+
+// legalHoldViolation reports whether deleting a user failed because of their legal hold, the only
+// foreign key restricting it
+func legalHoldViolation(err error) bool {
+	return errors.Is(err, gorm.ErrForeignKeyViolated)
+}
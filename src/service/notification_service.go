@@ -0,0 +1,767 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/encryption"
+	"app/src/model"
+	"app/src/notification"
+	"app/src/push"
+	"app/src/response"
+	"app/src/sms"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxPhoneCodeAttempts is how many codes may be tried before a new one must be requested
+	maxPhoneCodeAttempts = 5
+	// maxTextMessageError keeps provider error descriptions to what is worth reading
+	maxTextMessageError = 500
+	// pushSendTimeout bounds delivering one notification to all of a user's devices
+	pushSendTimeout = 30 * time.Second
+)
+
+func init() {
+	// The model package cannot import encryption, so its encrypted columns are registered here
+	encryption.Register("user_phones", "phone")
+}
+
+type NotificationService interface {
+	// Notify sends a notification to user over the channels they chose for its kind
+	Notify(c *fiber.Ctx, user *model.User, kind string, data notification.Data) error
+	GetSettings(c *fiber.Ctx, userID uuid.UUID) (*response.NotificationSettings, error)
+	UpdatePreference(
+		c *fiber.Ctx, userID uuid.UUID, kind string, req *validation.UpdateNotificationPreference,
+	) (*response.NotificationPreference, error)
+	// SetPhone sends a verification code to a phone number; it is only saved once verified
+	SetPhone(c *fiber.Ctx, userID uuid.UUID, req *validation.SetPhone) error
+	VerifyPhone(c *fiber.Ctx, userID uuid.UUID, req *validation.VerifyPhone) error
+	RemovePhone(c *fiber.Ctx, userID uuid.UUID) error
+	// HandleStatusCallback records the delivery status a text message provider reports
+	HandleStatusCallback(c *fiber.Ctx, provider string) error
+	// NotifyUser is Notify for callers that only have the user's ID; failures are logged, not returned
+	NotifyUser(c *fiber.Ctx, userID uuid.UUID, kind string, data notification.Data)
+	GetPushDevices(c *fiber.Ctx, userID uuid.UUID) ([]response.PushDevice, error)
+	// RegisterPushDevice adds a device, or moves it to the user when another account registered its token
+	RegisterPushDevice(c *fiber.Ctx, userID uuid.UUID, req *validation.RegisterPushDevice) (*response.PushDevice, error)
+	RemovePushDevice(c *fiber.Ctx, userID uuid.UUID, deviceID string) error
+}
+
+type notificationService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	Store        cache.Store
+	Counter      cache.Counter
+	EmailService EmailService
+	Provider     sms.Provider
+	Senders      map[string]push.Sender
+	AuditService AuditService
+	Clock        clock.Clock
+}
+
+// pendingPhone is a phone number awaiting verification, kept in the cache store
+type pendingPhone struct {
+	Phone    string `json:"phone"`
+	CodeHash string `json:"code_hash"`
+}
+
+// NewNotificationService delivers notifications by email, with a provider by SMS and WhatsApp, and
+// with senders (keyed by platform) by push. Phone verification codes are kept in the cache store,
+// so without one no phone can be added.
+func NewNotificationService(
+	db *gorm.DB, validate *validator.Validate, store cache.Store, emailService EmailService,
+	provider sms.Provider, senders map[string]push.Sender, auditService AuditService, clk clock.Clock,
+) NotificationService {
+	counter, _ := store.(cache.Counter)
+	return &notificationService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		Store:        store,
+		Counter:      counter,
+		EmailService: emailService,
+		Provider:     provider,
+		Senders:      senders,
+		AuditService: auditService,
+		Clock:        clock.OrSystem(clk),
+	}
+}
+
+// Notify tries every chosen channel and only fails when none delivered. Text channels are skipped
+// while the user has no verified phone, push while they have no device, and any channel that is not
+// configured. Kinds the user cannot do without then fall back to email; the others are not sent.
+func (s *notificationService) Notify(c *fiber.Ctx, user *model.User, kind string, data notification.Data) error {
+	phone, err := s.getPhone(c.UserContext(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	channels, err := s.preferredChannels(c.UserContext(), user.ID, kind)
+	if err != nil {
+		return err
+	}
+
+	var devices []model.PushDevice
+	if slices.Contains(channels, notification.ChannelPush) && s.channelAvailable(notification.ChannelPush) {
+		if devices, err = s.pushDevices(c.UserContext(), user.ID); err != nil {
+			return err
+		}
+	}
+
+	available := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		switch channel {
+		case notification.ChannelEmail:
+			available = append(available, channel)
+		case notification.ChannelPush:
+			if len(devices) > 0 {
+				available = append(available, channel)
+			}
+		default:
+			if phone != nil && s.channelAvailable(channel) {
+				available = append(available, channel)
+			}
+		}
+	}
+	if len(available) == 0 {
+		if !notification.Required(kind) {
+			return nil
+		}
+		available = []string{notification.ChannelEmail}
+	}
+
+	var lastErr error
+	delivered := 0
+	for _, channel := range available {
+		switch channel {
+		case notification.ChannelEmail:
+			err = s.sendEmail(c, user, kind, data)
+		case notification.ChannelPush:
+			err = s.sendPush(c.UserContext(), user.ID, kind, devices, data)
+		default:
+			err = s.sendText(c, user.ID, phone.Phone, kind, channel, data)
+		}
+		if err != nil {
+			s.Log.Warnf("Failed to send %s notification over %s to user %s: %v", kind, channel, user.ID, err)
+			lastErr = err
+			continue
+		}
+		delivered++
+	}
+
+	if delivered == 0 {
+		return lastErr
+	}
+	return nil
+}
+
+func (s *notificationService) NotifyUser(c *fiber.Ctx, userID uuid.UUID, kind string, data notification.Data) {
+	user := new(model.User)
+	if err := s.DB.WithContext(c.UserContext()).Where("id = ?", userID).Take(user).Error; err != nil {
+		s.Log.Errorf("Failed to load user %s to notify: %+v", userID, err)
+		return
+	}
+	// Service accounts have no one to notify
+	if user.IsService() {
+		return
+	}
+
+	if err := s.Notify(c, user, kind, data); err != nil {
+		s.Log.Warnf("Failed to send %s notification to user %s: %v", kind, userID, err)
+	}
+}
+
+func (s *notificationService) GetSettings(c *fiber.Ctx, userID uuid.UUID) (*response.NotificationSettings, error) {
+	phone, err := s.getPhone(c.UserContext(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var preferences []model.NotificationPreference
+	if err := s.DB.WithContext(c.UserContext()).Where("user_id = ?", userID).Find(&preferences).Error; err != nil {
+		s.Log.Errorf("Failed to load notification preferences: %+v", err)
+		return nil, err
+	}
+	chosen := make(map[string][]string, len(preferences))
+	for i := range preferences {
+		chosen[preferences[i].Kind] = preferences[i].ChannelList()
+	}
+
+	settings := &response.NotificationSettings{
+		Channels:    s.availableChannels(),
+		Preferences: make([]response.NotificationPreference, 0, len(notification.Preferable)),
+	}
+	if phone != nil {
+		settings.Phone = &phone.Phone
+		settings.PhoneVerifiedAt = &phone.VerifiedAt
+	}
+	for kind, defaults := range notification.Preferable {
+		channels, ok := chosen[kind]
+		if !ok {
+			channels = defaults
+		}
+		settings.Preferences = append(settings.Preferences, response.NotificationPreference{Kind: kind, Channels: channels})
+	}
+	sort.Slice(settings.Preferences, func(i, j int) bool {
+		return settings.Preferences[i].Kind < settings.Preferences[j].Kind
+	})
+
+	return settings, nil
+}
+
+// UpdatePreference only accepts text channels once the user has a verified phone
+func (s *notificationService) UpdatePreference(
+	c *fiber.Ctx, userID uuid.UUID, kind string, req *validation.UpdateNotificationPreference,
+) (*response.NotificationPreference, error) {
+	if _, ok := notification.Preferable[kind]; !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Notification kind not found")
+	}
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if len(req.Channels) == 0 && notification.Required(kind) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "This notification cannot be turned off")
+	}
+
+	phone, err := s.getPhone(c.UserContext(), userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range req.Channels {
+		if channel == notification.ChannelEmail {
+			continue
+		}
+		if !s.channelAvailable(channel) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Channel '%s' is not available", channel))
+		}
+		// Push goes to whichever devices are registered at the time, so none are needed yet
+		if channel != notification.ChannelPush && phone == nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Verify a phone number before choosing text channels")
+		}
+	}
+
+	preference := &model.NotificationPreference{UserID: userID, Kind: kind, Channels: strings.Join(req.Channels, ",")}
+	err = s.DB.WithContext(c.UserContext()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "kind"}},
+		DoUpdates: clause.AssignmentColumns([]string{"channels", "updated_at"}),
+	}).Create(preference).Error
+	if err != nil {
+		s.Log.Errorf("Failed to save notification preference: %+v", err)
+		return nil, err
+	}
+
+	return &response.NotificationPreference{Kind: kind, Channels: req.Channels}, nil
+}
+
+func (s *notificationService) SetPhone(c *fiber.Ctx, userID uuid.UUID, req *validation.SetPhone) error {
+	if err := s.Validate.Struct(req); err != nil {
+		return err
+	}
+	if req.Channel == "" {
+		req.Channel = notification.ChannelSMS
+	}
+	if !s.channelAvailable(req.Channel) {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Channel '%s' is not available", req.Channel))
+	}
+	// Phone numbers are only stored encrypted
+	if encryption.Default() == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Phone numbers cannot be stored. Please try again later")
+	}
+	if s.Counter == nil || !cache.IsStoreAvailable(s.Store) {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Please try again later")
+	}
+
+	code, err := generatePhoneCode()
+	if err != nil {
+		s.Log.Errorf("Failed to generate phone verification code: %+v", err)
+		return err
+	}
+
+	pending, err := json.Marshal(pendingPhone{Phone: req.Phone, CodeHash: hashPhoneCode(req.Phone, code)})
+	if err != nil {
+		return err
+	}
+	key := phoneVerificationKey(userID)
+	if err := s.Store.Set(key, pending, config.SMS.CodeTTL); err != nil {
+		s.Log.Errorf("Failed to store phone verification: %+v", err)
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Please try again later")
+	}
+	// A new code gets a fresh set of attempts
+	if err := s.Store.Delete(key + ":attempts"); err != nil {
+		s.Log.Warnf("Failed to reset phone verification attempts: %v", err)
+	}
+
+	data := notification.Data{Code: code}
+	return s.sendText(c, userID, req.Phone, notification.KindPhoneVerification, req.Channel, data)
+}
+
+func (s *notificationService) VerifyPhone(c *fiber.Ctx, userID uuid.UUID, req *validation.VerifyPhone) error {
+	if err := s.Validate.Struct(req); err != nil {
+		return err
+	}
+	if s.Counter == nil || !cache.IsStoreAvailable(s.Store) {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Please try again later")
+	}
+
+	invalidCode := fiber.NewError(fiber.StatusBadRequest, "Invalid or expired verification code")
+
+	key := phoneVerificationKey(userID)
+	data, err := s.Store.Get(key)
+	if err != nil {
+		s.Log.Errorf("Failed to load phone verification: %+v", err)
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Please try again later")
+	}
+	pending := new(pendingPhone)
+	if data == nil || json.Unmarshal(data, pending) != nil {
+		return invalidCode
+	}
+
+	attempts, err := s.Counter.IncrBy(c.UserContext(), key+":attempts", 1, config.SMS.CodeTTL)
+	if err != nil {
+		s.Log.Errorf("Failed to count phone verification attempts: %+v", err)
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Please try again later")
+	}
+	if attempts > maxPhoneCodeAttempts {
+		s.clearPendingPhone(key)
+		return fiber.NewError(fiber.StatusBadRequest, "Too many attempts. Please request a new code")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashPhoneCode(pending.Phone, req.Code)), []byte(pending.CodeHash)) != 1 {
+		return invalidCode
+	}
+
+	phone := &model.UserPhone{UserID: userID, Phone: pending.Phone, VerifiedAt: s.Clock.Now()}
+	err = s.DB.WithContext(c.UserContext()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"phone", "verified_at"}),
+	}).Create(phone).Error
+	if err != nil {
+		s.Log.Errorf("Failed to save phone: %+v", err)
+		return err
+	}
+	s.clearPendingPhone(key)
+
+	s.AuditService.Record(c, &userID, model.AuditActionPhoneVerified, map[string]any{})
+
+	return nil
+}
+
+// RemovePhone keeps the preferences; text channels in them are skipped until a phone is verified again
+func (s *notificationService) RemovePhone(c *fiber.Ctx, userID uuid.UUID) error {
+	result := s.DB.WithContext(c.UserContext()).Where("user_id = ?", userID).Delete(new(model.UserPhone))
+	if result.Error != nil {
+		s.Log.Errorf("Failed to remove phone: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Phone not found")
+	}
+
+	s.AuditService.Record(c, &userID, model.AuditActionPhoneRemoved, map[string]any{})
+
+	return nil
+}
+
+// HandleStatusCallback acknowledges callbacks about unknown messages and statuses so the provider
+// does not retry them. Callbacks may arrive out of order; a status never moves a message back.
+func (s *notificationService) HandleStatusCallback(c *fiber.Ctx, provider string) error {
+	if s.Provider == nil || s.Provider.Name() != provider {
+		return fiber.NewError(fiber.StatusNotFound, "Unknown SMS provider")
+	}
+
+	status, err := s.Provider.ParseCallback(sms.Callback{Header: http.Header(c.GetReqHeaders()), Body: c.Body()})
+	if errors.Is(err, sms.ErrInvalidSignature) {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid callback signature")
+	}
+	if err != nil {
+		s.Log.Warnf("Rejected %s status callback: %v", provider, err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid callback payload")
+	}
+	if status == nil {
+		return nil
+	}
+
+	ctx := c.UserContext()
+	message := new(model.TextMessage)
+	err = s.DB.WithContext(ctx).
+		Where("provider = ? AND provider_message_id = ?", provider, status.MessageID).
+		Take(message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to load text message: %+v", err)
+		return err
+	}
+	if !sms.Advances(message.Status, status.Status) {
+		return nil
+	}
+
+	detail := status.Error
+	if len(detail) > maxTextMessageError {
+		detail = detail[:maxTextMessageError]
+	}
+
+	// The status condition keeps concurrent callbacks from both moving the message
+	result := s.DB.WithContext(ctx).Model(message).Where("status = ?", message.Status).
+		Updates(map[string]any{"status": status.Status, "error": detail})
+	if result.Error != nil {
+		s.Log.Errorf("Failed to update text message status: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	var action string
+	switch status.Status {
+	case sms.StatusDelivered:
+		action = model.AuditActionMessageDelivered
+	case sms.StatusFailed:
+		action = model.AuditActionMessageFailed
+	default:
+		return nil
+	}
+	s.AuditService.RecordSystem(ctx, &message.UserID, action, map[string]any{
+		"message_id": message.ID, "kind": message.Kind, "channel": message.Channel, "provider": message.Provider,
+		"error": detail,
+	})
+
+	return nil
+}
+
+func (s *notificationService) GetPushDevices(c *fiber.Ctx, userID uuid.UUID) ([]response.PushDevice, error) {
+	var devices []model.PushDevice
+	err := s.DB.WithContext(c.UserContext()).Where("user_id = ?", userID).Order("updated_at DESC").Find(&devices).Error
+	if err != nil {
+		s.Log.Errorf("Failed to load push devices: %+v", err)
+		return nil, err
+	}
+
+	result := make([]response.PushDevice, 0, len(devices))
+	for i := range devices {
+		result = append(result, pushDeviceResponse(&devices[i]))
+	}
+	return result, nil
+}
+
+// RegisterPushDevice keeps at most config.Push.MaxDevices per user, dropping the least recently
+// registered. Apps register their token on every launch, which keeps it at the top.
+func (s *notificationService) RegisterPushDevice(
+	c *fiber.Ctx, userID uuid.UUID, req *validation.RegisterPushDevice,
+) (*response.PushDevice, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if _, ok := s.Senders[req.Platform]; !ok {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Platform '%s' is not available", req.Platform))
+	}
+
+	device := &model.PushDevice{UserID: userID, Platform: req.Platform, Token: req.Token, Name: req.Name}
+	err := s.DB.WithContext(c.UserContext()).Transaction(func(tx *gorm.DB) error {
+		// A token identifies the install, so signing in as someone else on it moves it to them
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "token"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "name", "updated_at"}),
+		}).Create(device).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("token = ?", req.Token).Take(device).Error; err != nil {
+			return err
+		}
+
+		kept := tx.Model(new(model.PushDevice)).Select("id").Where("user_id = ?", userID).
+			Order("updated_at DESC").Limit(config.Push.MaxDevices)
+		return tx.Where("user_id = ? AND id NOT IN (?)", userID, kept).Delete(new(model.PushDevice)).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to register push device: %+v", err)
+		return nil, err
+	}
+
+	result := pushDeviceResponse(device)
+	return &result, nil
+}
+
+func (s *notificationService) RemovePushDevice(c *fiber.Ctx, userID uuid.UUID, deviceID string) error {
+	id, err := uuid.Parse(deviceID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Push device not found")
+	}
+
+	result := s.DB.WithContext(c.UserContext()).Where("id = ? AND user_id = ?", id, userID).Delete(new(model.PushDevice))
+	if result.Error != nil {
+		s.Log.Errorf("Failed to remove push device: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Push device not found")
+	}
+	return nil
+}
+
+// sendEmail records the email like the other emails sent to users
+func (s *notificationService) sendEmail(c *fiber.Ctx, user *model.User, kind string, data notification.Data) error {
+	message, err := notification.Render(kind, notification.ChannelEmail, data)
+	if err != nil {
+		return err
+	}
+	if err := s.EmailService.SendEmail(c.UserContext(), user.Email, message.Subject, message.Body); err != nil {
+		return err
+	}
+
+	s.AuditService.Record(c, &user.ID, model.AuditActionEmailSent, map[string]any{"kind": kind})
+	return nil
+}
+
+// sendText stores the message so status callbacks can be matched to it
+func (s *notificationService) sendText(
+	c *fiber.Ctx, userID uuid.UUID, phone, kind, channel string, data notification.Data,
+) error {
+	message, err := notification.Render(kind, channel, data)
+	if err != nil {
+		return err
+	}
+
+	messageID, err := s.Provider.Send(c.UserContext(), sms.Message{
+		To: phone, Text: message.Body, WhatsApp: channel == notification.ChannelWhatsApp,
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to send %s message: %+v", channel, err)
+		return fiber.NewError(fiber.StatusBadGateway, "Failed to send text message")
+	}
+
+	textMessage := &model.TextMessage{
+		UserID:            userID,
+		Kind:              kind,
+		Channel:           channel,
+		Provider:          s.Provider.Name(),
+		ProviderMessageID: messageID,
+		Status:            sms.StatusAccepted,
+	}
+	// The message is already on its way; failing to track it is not worth failing the request
+	if err := s.DB.WithContext(c.UserContext()).Create(textMessage).Error; err != nil {
+		s.Log.Errorf("Failed to store text message: %+v", err)
+		return nil
+	}
+
+	s.AuditService.Record(c, &userID, model.AuditActionMessageSent, map[string]any{
+		"message_id": textMessage.ID, "kind": kind, "channel": channel, "provider": textMessage.Provider,
+	})
+	return nil
+}
+
+// sendPush delivers in the background so the request never waits on FCM or APNs, keeping the
+// request's values such as its ID but not its deadline. Tokens the platform reports as invalid are
+// removed.
+func (s *notificationService) sendPush(
+	ctx context.Context, userID uuid.UUID, kind string, devices []model.PushDevice, data notification.Data,
+) error {
+	message, err := notification.Render(kind, notification.ChannelPush, data)
+	if err != nil {
+		return err
+	}
+	msg := push.Message{Title: message.Subject, Body: message.Body, Data: map[string]string{"kind": kind}}
+
+	byPlatform := map[string][]model.PushDevice{}
+	for _, device := range devices {
+		byPlatform[device.Platform] = append(byPlatform[device.Platform], device)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pushSendTimeout)
+		defer cancel()
+
+		sent, failed := 0, 0
+		invalid := []uuid.UUID{}
+		for platform, platformDevices := range byPlatform {
+			tokens := make([]string, len(platformDevices))
+			for i := range platformDevices {
+				tokens[i] = platformDevices[i].Token
+			}
+
+			for i, err := range push.SendBatch(ctx, s.Senders[platform], tokens, msg, config.Push.BatchSize) {
+				switch {
+				case err == nil:
+					sent++
+				case errors.Is(err, push.ErrInvalidToken):
+					invalid = append(invalid, platformDevices[i].ID)
+				default:
+					failed++
+					s.Log.Warnf("Failed to send %s push to device %s: %v", kind, platformDevices[i].ID, err)
+				}
+			}
+		}
+
+		if len(invalid) > 0 {
+			if err := s.DB.WithContext(ctx).Where("id IN ?", invalid).Delete(new(model.PushDevice)).Error; err != nil {
+				s.Log.Errorf("Failed to remove invalid push devices: %+v", err)
+			}
+		}
+		if sent == 0 {
+			return
+		}
+
+		s.AuditService.RecordSystem(ctx, &userID, model.AuditActionMessageSent, map[string]any{
+			"kind": kind, "channel": notification.ChannelPush, "devices": sent, "failed": failed + len(invalid),
+		})
+	}()
+
+	return nil
+}
+
+// pushDevices returns the user's devices on platforms this deployment can send to
+func (s *notificationService) pushDevices(ctx context.Context, userID uuid.UUID) ([]model.PushDevice, error) {
+	platforms := make([]string, 0, len(s.Senders))
+	for platform := range s.Senders {
+		platforms = append(platforms, platform)
+	}
+
+	var devices []model.PushDevice
+	err := s.DB.WithContext(ctx).Where("user_id = ? AND platform IN ?", userID, platforms).Find(&devices).Error
+	if err != nil {
+		s.Log.Errorf("Failed to load push devices: %+v", err)
+		return nil, err
+	}
+	return devices, nil
+}
+
+func (s *notificationService) getPhone(ctx context.Context, userID uuid.UUID) (*model.UserPhone, error) {
+	phone := new(model.UserPhone)
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Take(phone).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to load phone: %+v", err)
+		return nil, err
+	}
+	return phone, nil
+}
+
+func (s *notificationService) preferredChannels(ctx context.Context, userID uuid.UUID, kind string) ([]string, error) {
+	preference := new(model.NotificationPreference)
+	err := s.DB.WithContext(ctx).Where("user_id = ? AND kind = ?", userID, kind).Take(preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if defaults, ok := notification.Preferable[kind]; ok {
+			return defaults, nil
+		}
+		return []string{notification.ChannelEmail}, nil
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to load notification preference: %+v", err)
+		return nil, err
+	}
+	return preference.ChannelList(), nil
+}
+
+// channelAvailable reports whether messages can be sent over channel in this deployment
+func (s *notificationService) channelAvailable(channel string) bool {
+	switch channel {
+	case notification.ChannelEmail:
+		return true
+	case notification.ChannelSMS:
+		return s.Provider != nil
+	case notification.ChannelWhatsApp:
+		return s.Provider != nil && config.SMS.WhatsAppFrom != ""
+	case notification.ChannelPush:
+		return len(s.Senders) > 0
+	default:
+		return false
+	}
+}
+
+func (s *notificationService) availableChannels() []string {
+	channels := []string{}
+	for _, channel := range []string{
+		notification.ChannelEmail, notification.ChannelSMS, notification.ChannelWhatsApp, notification.ChannelPush,
+	} {
+		if s.channelAvailable(channel) {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+func (s *notificationService) clearPendingPhone(key string) {
+	if err := s.Store.DeleteKeys(context.Background(), key, key+":attempts"); err != nil {
+		s.Log.Warnf("Failed to clear phone verification: %v", err)
+	}
+}
+
+// generatePhoneCode returns a random six-digit code
+func generatePhoneCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashPhoneCode binds a code to the number it was sent to
+func hashPhoneCode(phone, code string) string {
+	return hashAPIToken(phone + ":" + code)
+}
+
+func phoneVerificationKey(userID uuid.UUID) string {
+	return cache.PhoneVerificationKeyPrefix + userID.String()
+}
+
+func pushDeviceResponse(device *model.PushDevice) response.PushDevice {
+	return response.PushDevice{
+		ID:        device.ID,
+		Platform:  device.Platform,
+		Name:      device.Name,
+		CreatedAt: device.CreatedAt,
+		UpdatedAt: device.UpdatedAt,
+	}
+}
+
+// securityNotifications maps the audited security events users are notified about to their kind
+var securityNotifications = map[string]string{
+	model.AuditActionLoginSucceeded:  notification.KindNewLogin,
+	model.AuditActionLoginConfirm:    notification.KindNewLogin,
+	model.AuditActionPasswordChanged: notification.KindPasswordChanged,
+}
+
+type notifyingAuditService struct {
+	AuditService
+	NotificationService NotificationService
+}
+
+// NewNotifyingAuditService records audit entries with audit and notifies users of the security
+// events concerning them, such as a new sign-in or a password change
+func NewNotifyingAuditService(audit AuditService, notifications NotificationService) AuditService {
+	return &notifyingAuditService{AuditService: audit, NotificationService: notifications}
+}
+
+func (s *notifyingAuditService) Record(c *fiber.Ctx, userID *uuid.UUID, action string, metadata map[string]any) {
+	s.AuditService.Record(c, userID, action, metadata)
+
+	if kind, ok := securityNotifications[action]; ok && userID != nil {
+		s.NotificationService.NotifyUser(c, *userID, kind, notification.Data{IP: c.IP()})
+	}
+}
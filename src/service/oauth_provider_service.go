@@ -0,0 +1,573 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// OAuthTokenPrefix marks access tokens delegated to third-party clients
+	OAuthTokenPrefix = "oat_"
+	// OAuthClientSecretPrefix marks third-party client secrets
+	OAuthClientSecretPrefix = "ocs_"
+)
+
+// OAuthError is an error of the token endpoint, reported in the RFC 6749 format rather than the
+// API's own error format
+type OAuthError struct {
+	Status      int
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// authorizationCode is kept in the store between the user's consent and the client's token request
+type authorizationCode struct {
+	ClientID    string   `json:"client_id"`
+	UserID      string   `json:"user_id"`
+	RedirectURI string   `json:"redirect_uri"`
+	Scopes      []string `json:"scopes"`
+	Challenge   string   `json:"challenge,omitempty"`
+}
+
+type OAuthProviderService interface {
+	CreateClient(c *fiber.Ctx, req *validation.CreateOAuthClient) (*model.OAuthClient, string, error)
+	ListClients(c *fiber.Ctx) ([]model.OAuthClient, error)
+	DeleteClient(c *fiber.Ctx, id string) error
+	DescribeAuthorization(c *fiber.Ctx, user *model.User, req *validation.OAuthAuthorize) (*response.OAuthAuthorization, error)
+	Authorize(c *fiber.Ctx, user *model.User, req *validation.OAuthConsent) (string, error)
+	Exchange(c *fiber.Ctx, req *validation.OAuthToken) (*response.OAuthToken, error)
+	ListConsents(c *fiber.Ctx, userID string) ([]model.OAuthConsent, error)
+	RevokeConsent(c *fiber.Ctx, userID, clientID string) error
+	// LimitClient counts a request made with one of the client's tokens against its rate limit
+	LimitClient(c *fiber.Ctx, client *model.OAuthClient) error
+}
+
+type oauthProviderService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	Store        cache.Store
+	Counter      cache.Counter
+	UserService  UserService
+	AuditService AuditService
+	Clock        clock.Clock
+}
+
+// NewOAuthProviderService runs the authorization-code grant for third-party clients, keeping codes
+// and per-client request counts in the cache store. Without a store, no code can be issued; without
+// one that counts (Redis or memory), clients are not rate limited.
+func NewOAuthProviderService(
+	db *gorm.DB, validate *validator.Validate, store cache.Store, userService UserService,
+	auditService AuditService, clk clock.Clock,
+) OAuthProviderService {
+	counter, _ := store.(cache.Counter)
+	return &oauthProviderService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		Store:        store,
+		Counter:      counter,
+		UserService:  userService,
+		AuditService: auditService,
+		Clock:        clock.OrSystem(clk),
+	}
+}
+
+// CreateClient registers a third-party client; its secret is only returned once
+func (s *oauthProviderService) CreateClient(
+	c *fiber.Ctx, req *validation.CreateOAuthClient,
+) (*model.OAuthClient, string, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, "", err
+	}
+	for _, scope := range req.Scopes {
+		if _, ok := config.OAuthScopes[scope]; !ok {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Unknown scope '%s'", scope))
+		}
+	}
+
+	secret, err := generateAPIToken(OAuthClientSecretPrefix)
+	if err != nil {
+		s.Log.Errorf("Failed to generate OAuth client secret: %+v", err)
+		return nil, "", err
+	}
+
+	client := &model.OAuthClient{
+		Name:         req.Name,
+		SecretHash:   hashAPIToken(secret),
+		Prefix:       secret[:len(OAuthClientSecretPrefix)+6],
+		RedirectURIs: strings.Join(req.RedirectURIs, " "),
+		Scopes:       strings.Join(req.Scopes, ","),
+		RateLimit:    req.RateLimit,
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		client.CreatedBy = &actor.ID
+	}
+
+	if err := s.DB.WithContext(c.UserContext()).Create(client).Error; err != nil {
+		s.Log.Errorf("Failed to create OAuth client: %+v", err)
+		return nil, "", err
+	}
+
+	s.audit(c, nil, model.AuditActionOAuthClientCreated, map[string]any{"client_id": client.ID, "name": client.Name})
+
+	return client, secret, nil
+}
+
+func (s *oauthProviderService) ListClients(c *fiber.Ctx) ([]model.OAuthClient, error) {
+	var clients []model.OAuthClient
+	if err := s.DB.WithContext(c.UserContext()).Order("created_at").Find(&clients).Error; err != nil {
+		s.Log.Errorf("Failed to list OAuth clients: %+v", err)
+		return nil, err
+	}
+	return clients, nil
+}
+
+// DeleteClient removes a client along with its users' consents and tokens
+func (s *oauthProviderService) DeleteClient(c *fiber.Ctx, id string) error {
+	// RETURNING fills in the deleted client for the audit entry
+	client := new(model.OAuthClient)
+	result := s.DB.WithContext(c.UserContext()).Clauses(clause.Returning{}).Where("id = ?", id).Delete(client)
+	if result.Error != nil {
+		s.Log.Errorf("Failed to delete OAuth client: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "OAuth client not found")
+	}
+
+	s.audit(c, nil, model.AuditActionOAuthClientDeleted, map[string]any{"client_id": client.ID, "name": client.Name})
+
+	return nil
+}
+
+// DescribeAuthorization checks an authorization request and returns what the consent screen shows
+func (s *oauthProviderService) DescribeAuthorization(
+	c *fiber.Ctx, user *model.User, req *validation.OAuthAuthorize,
+) (*response.OAuthAuthorization, error) {
+	client, scopes, err := s.checkAuthorization(c, user, req)
+	if err != nil {
+		return nil, err
+	}
+
+	consent, err := s.findConsent(c, user.ID, client.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	authorization := &response.OAuthAuthorization{
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Scopes:     make([]response.OAuthScope, len(scopes)),
+		Consented:  consent != nil && containsAll(consent.ScopeList(), scopes),
+	}
+	for i, scope := range scopes {
+		authorization.Scopes[i] = response.OAuthScope{Name: scope, Description: config.OAuthScopes[scope].Description}
+	}
+
+	return authorization, nil
+}
+
+// Authorize records the user's answer to an authorization request and returns the client redirect
+// URI carrying either a single-use code or the access_denied error
+func (s *oauthProviderService) Authorize(c *fiber.Ctx, user *model.User, req *validation.OAuthConsent) (string, error) {
+	client, scopes, err := s.checkAuthorization(c, user, &req.OAuthAuthorize)
+	if err != nil {
+		return "", err
+	}
+
+	if !req.Approve {
+		return redirectWith(req.RedirectURI, url.Values{"error": {"access_denied"}}, req.State)
+	}
+
+	if !cache.IsStoreAvailable(s.Store) {
+		return "", fiber.NewError(fiber.StatusServiceUnavailable, "Authorization is temporarily unavailable")
+	}
+
+	if err := s.saveConsent(c, user.ID, client.ID, scopes); err != nil {
+		return "", err
+	}
+
+	code, err := randomURLToken()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(authorizationCode{
+		ClientID:    client.ID.String(),
+		UserID:      user.ID.String(),
+		RedirectURI: req.RedirectURI,
+		Scopes:      scopes,
+		Challenge:   req.CodeChallenge,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := s.Store.Set(authorizationCodeKey(code), data, config.OAuthProvider.CodeTTL); err != nil {
+		s.Log.Errorf("Failed to save authorization code: %+v", err)
+		return "", fiber.NewError(fiber.StatusServiceUnavailable, "Authorization is temporarily unavailable")
+	}
+
+	s.audit(c, &user.ID, model.AuditActionOAuthConsentGranted, map[string]any{
+		"client_id": client.ID, "scopes": scopes,
+	})
+
+	return redirectWith(req.RedirectURI, url.Values{"code": {code}}, req.State)
+}
+
+// Exchange trades an authorization code for a delegated access token. Errors are *OAuthError.
+func (s *oauthProviderService) Exchange(c *fiber.Ctx, req *validation.OAuthToken) (*response.OAuthToken, error) {
+	if req.GrantType != "authorization_code" {
+		return nil, oauthError(fiber.StatusBadRequest, "unsupported_grant_type", "Only the authorization_code grant is supported")
+	}
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, oauthError(fiber.StatusBadRequest, "invalid_request", "Missing or invalid parameters")
+	}
+
+	client := new(model.OAuthClient)
+	err := s.DB.WithContext(c.UserContext()).Where("id = ?", req.ClientID).First(client).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.Log.Errorf("Failed to look up OAuth client: %+v", err)
+		return nil, err
+	}
+	if err != nil || subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashAPIToken(req.ClientSecret))) != 1 {
+		return nil, oauthError(fiber.StatusUnauthorized, "invalid_client", "Client authentication failed")
+	}
+
+	code, err := s.consumeCode(c, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidGrant := oauthError(fiber.StatusBadRequest, "invalid_grant", "Authorization code is invalid or expired")
+	if code.ClientID != client.ID.String() || code.RedirectURI != req.RedirectURI {
+		return nil, invalidGrant
+	}
+	if (code.Challenge != "" || req.CodeVerifier != "") && !pkceMatches(req.CodeVerifier, code.Challenge) {
+		return nil, oauthError(fiber.StatusBadRequest, "invalid_grant", "Code verifier does not match the challenge")
+	}
+
+	user, err := s.UserService.GetUserByID(c, code.UserID)
+	if err != nil || !user.IsActive {
+		return nil, invalidGrant
+	}
+
+	// The consent may have been revoked while the code waited
+	consent, err := s.findConsent(c, user.ID, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	if consent == nil || !containsAll(consent.ScopeList(), code.Scopes) {
+		return nil, invalidGrant
+	}
+
+	rawToken, err := generateAPIToken(OAuthTokenPrefix)
+	if err != nil {
+		s.Log.Errorf("Failed to generate OAuth access token: %+v", err)
+		return nil, err
+	}
+
+	ttl := config.OAuthProvider.AccessTokenTTL
+	token := &model.APIToken{
+		UserID:    user.ID,
+		ClientID:  &client.ID,
+		Name:      client.Name,
+		TokenHash: hashAPIToken(rawToken),
+		Prefix:    rawToken[:len(OAuthTokenPrefix)+6],
+		Scopes:    strings.Join(code.Scopes, ","),
+		ExpiresAt: s.Clock.Now().Add(ttl),
+	}
+	if err := s.DB.WithContext(c.UserContext()).Create(token).Error; err != nil {
+		s.Log.Errorf("Failed to create OAuth access token: %+v", err)
+		return nil, err
+	}
+
+	return &response.OAuthToken{
+		AccessToken: rawToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(ttl.Seconds()),
+		Scope:       strings.Join(code.Scopes, " "),
+	}, nil
+}
+
+// ListConsents returns the clients the user granted access to
+func (s *oauthProviderService) ListConsents(c *fiber.Ctx, userID string) ([]model.OAuthConsent, error) {
+	var consents []model.OAuthConsent
+
+	result := s.DB.WithContext(c.UserContext()).
+		Preload("Client").
+		Where("user_id = ?", userID).
+		Order("created_at desc").
+		Find(&consents)
+
+	if result.Error != nil {
+		s.Log.Errorf("Failed to list OAuth consents: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	return consents, nil
+}
+
+// RevokeConsent withdraws the user's consent to a client and deletes the tokens it was given
+func (s *oauthProviderService) RevokeConsent(c *fiber.Ctx, userID, clientID string) error {
+	var revoked int64
+
+	err := s.DB.WithContext(c.UserContext()).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND client_id = ?", userID, clientID).Delete(&model.OAuthConsent{})
+		if result.Error != nil {
+			return result.Error
+		}
+		revoked = result.RowsAffected
+
+		return tx.Where("user_id = ? AND client_id = ?", userID, clientID).Delete(&model.APIToken{}).Error
+	})
+	if err != nil {
+		s.Log.Errorf("Failed to revoke OAuth consent: %+v", err)
+		return err
+	}
+	if revoked == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Consent not found")
+	}
+
+	if id, err := uuid.Parse(userID); err == nil {
+		s.audit(c, &id, model.AuditActionOAuthConsentRevoked, map[string]any{"client_id": clientID})
+	}
+
+	return nil
+}
+
+// LimitClient counts requests in fixed one-minute windows shared by all of the client's tokens.
+// It lets requests through when the store cannot count.
+func (s *oauthProviderService) LimitClient(c *fiber.Ctx, client *model.OAuthClient) error {
+	limit := client.RateLimit
+	if limit == 0 {
+		limit = config.OAuthProvider.ClientRateLimit
+	}
+	if limit <= 0 || s.Counter == nil || !cache.IsStoreAvailable(s.Store) {
+		return nil
+	}
+
+	now := s.Clock.Now()
+	window := now.Truncate(time.Minute)
+	key := fmt.Sprintf("%s%s:%d", cache.OAuthClientRateKeyPrefix, client.ID, window.Unix())
+
+	count, err := s.Counter.IncrBy(c.UserContext(), key, 1, time.Minute)
+	if err != nil {
+		s.Log.Warnf("Failed to count OAuth client request: %v", err)
+		return nil
+	}
+	if count > int64(limit) {
+		retryAfter := int(window.Add(time.Minute).Sub(now).Seconds()) + 1
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		return fiber.NewError(fiber.StatusTooManyRequests, "Client rate limit exceeded. Please try again later.")
+	}
+
+	return nil
+}
+
+// checkAuthorization validates an authorization request for the user and returns the client and
+// the requested scopes. Unknown clients and unregistered redirect URIs are never redirected to.
+func (s *oauthProviderService) checkAuthorization(
+	c *fiber.Ctx, user *model.User, req *validation.OAuthAuthorize,
+) (*model.OAuthClient, []string, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, nil, err
+	}
+
+	client := new(model.OAuthClient)
+	result := s.DB.WithContext(c.UserContext()).Where("id = ?", req.ClientID).First(client)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "Unknown client")
+	}
+	if result.Error != nil {
+		s.Log.Errorf("Failed to look up OAuth client: %+v", result.Error)
+		return nil, nil, result.Error
+	}
+
+	if !slices.Contains(client.RedirectURIList(), req.RedirectURI) {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "Redirect URI is not registered for this client")
+	}
+
+	rights := RightsOf(user)
+	scopes := []string{}
+	for _, scope := range strings.Fields(req.Scope) {
+		if slices.Contains(scopes, scope) {
+			continue
+		}
+		definition, known := config.OAuthScopes[scope]
+		if !known || !slices.Contains(client.ScopeList(), scope) {
+			return nil, nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Client cannot request scope '%s'", scope))
+		}
+		if !containsAll(rights, definition.Rights) {
+			return nil, nil, fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("Cannot grant scope '%s'", scope))
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "No scope requested")
+	}
+
+	return client, scopes, nil
+}
+
+func (s *oauthProviderService) findConsent(c *fiber.Ctx, userID, clientID uuid.UUID) (*model.OAuthConsent, error) {
+	consent := new(model.OAuthConsent)
+	result := s.DB.WithContext(c.UserContext()).Where("user_id = ? AND client_id = ?", userID, clientID).First(consent)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		s.Log.Errorf("Failed to look up OAuth consent: %+v", result.Error)
+		return nil, result.Error
+	}
+	return consent, nil
+}
+
+// saveConsent adds scopes to those the user already granted the client
+func (s *oauthProviderService) saveConsent(c *fiber.Ctx, userID, clientID uuid.UUID, scopes []string) error {
+	consent, err := s.findConsent(c, userID, clientID)
+	if err != nil {
+		return err
+	}
+
+	granted := scopes
+	if consent != nil {
+		granted = consent.ScopeList()
+		for _, scope := range scopes {
+			if !slices.Contains(granted, scope) {
+				granted = append(granted, scope)
+			}
+		}
+	}
+
+	err = s.DB.WithContext(c.UserContext()).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"scopes", "updated_at"}),
+		}).
+		Create(&model.OAuthConsent{UserID: userID, ClientID: clientID, Scopes: strings.Join(granted, ",")}).Error
+	if err != nil {
+		s.Log.Errorf("Failed to save OAuth consent: %+v", err)
+	}
+	return err
+}
+
+// consumeCode loads and deletes an authorization code. With a store that counts, only the first of
+// concurrent requests for a code gets it.
+func (s *oauthProviderService) consumeCode(c *fiber.Ctx, rawCode string) (*authorizationCode, error) {
+	invalidGrant := oauthError(fiber.StatusBadRequest, "invalid_grant", "Authorization code is invalid or expired")
+	unavailable := oauthError(fiber.StatusServiceUnavailable, "temporarily_unavailable", "Please try again later")
+
+	if !cache.IsStoreAvailable(s.Store) {
+		return nil, unavailable
+	}
+
+	key := authorizationCodeKey(rawCode)
+	if s.Counter != nil {
+		claims, err := s.Counter.IncrBy(c.UserContext(), key+":claimed", 1, config.OAuthProvider.CodeTTL)
+		if err != nil {
+			s.Log.Errorf("Failed to claim authorization code: %+v", err)
+			return nil, unavailable
+		}
+		if claims > 1 {
+			return nil, invalidGrant
+		}
+	}
+
+	data, err := s.Store.Get(key)
+	if err != nil {
+		s.Log.Errorf("Failed to load authorization code: %+v", err)
+		return nil, unavailable
+	}
+	if data == nil {
+		return nil, invalidGrant
+	}
+	if err := s.Store.Delete(key); err != nil {
+		s.Log.Warnf("Failed to delete authorization code: %v", err)
+	}
+
+	code := new(authorizationCode)
+	if err := json.Unmarshal(data, code); err != nil {
+		return nil, invalidGrant
+	}
+	return code, nil
+}
+
+func (s *oauthProviderService) audit(c *fiber.Ctx, userID *uuid.UUID, action string, metadata map[string]any) {
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, userID, action, metadata)
+}
+
+// authorizationCodeKey hashes the code so it never appears in the cache keyspace
+func authorizationCodeKey(code string) string {
+	return cache.OAuthCodeKeyPrefix + hashAPIToken(code)
+}
+
+// pkceMatches checks a code verifier against an S256 challenge
+func pkceMatches(verifier, challenge string) bool {
+	if verifier == "" || challenge == "" {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(sum[:])), []byte(challenge)) == 1
+}
+
+// redirectWith adds params and the client's state to a registered redirect URI
+func redirectWith(redirectURI string, params url.Values, state string) (string, error) {
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, "Invalid redirect URI")
+	}
+
+	query := target.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	target.RawQuery = query.Encode()
+
+	return target.String(), nil
+}
+
+func oauthError(status int, code, description string) *OAuthError {
+	return &OAuthError{Status: status, Code: code, Description: description}
+}
+
+func containsAll(list, values []string) bool {
+	for _, value := range values {
+		if !slices.Contains(list, value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/utils"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// pendingLogin is kept in the store between the redirect to the provider and the callback
+type pendingLogin struct {
+	Verifier string `json:"verifier,omitempty"`
+	Nonce    string `json:"nonce"`
+}
+
+// oauthState keeps pending logins in the cache store under a signed, single-use state that is
+// also set in a cookie, so a callback only completes in the browser that started the login
+type oauthState struct {
+	Store  cache.Store
+	Cookie string
+	// CrossSite is needed when the provider posts the callback (Apple's form_post);
+	// SameSite=Lax cookies are not sent on cross-site POSTs
+	CrossSite bool
+}
+
+// begin stores pending, sets the state cookie and returns the state to send to the provider
+func (o oauthState) begin(c *fiber.Ctx, pending pendingLogin) (string, error) {
+	if !cache.IsStoreAvailable(o.Store) {
+		return "", fiber.NewError(fiber.StatusServiceUnavailable, "Login is temporarily unavailable")
+	}
+
+	stateID, err := randomURLToken()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return "", err
+	}
+	if err := o.Store.Set(cache.GetOAuthStateKey(stateID), data, config.OAuthStateTTL); err != nil {
+		utils.Log.Errorf("Failed to save OAuth state: %+v", err)
+		return "", fiber.NewError(fiber.StatusServiceUnavailable, "Login is temporarily unavailable")
+	}
+
+	cookie := &fiber.Cookie{
+		Name:     o.Cookie,
+		Value:    stateID + "." + signState(stateID),
+		MaxAge:   int(config.OAuthStateTTL.Seconds()),
+		Path:     "/",
+		Secure:   config.IsProd,
+		HTTPOnly: true,
+		SameSite: "Lax",
+	}
+	if o.CrossSite {
+		cookie.SameSite, cookie.Secure = "None", true
+	}
+	c.Cookie(cookie)
+
+	return cookie.Value, nil
+}
+
+// consume checks state against the cookie and its signature, then loads and deletes the pending login
+func (o oauthState) consume(c *fiber.Ctx, state string) (*pendingLogin, error) {
+	cookie := c.Cookies(o.Cookie)
+	c.ClearCookie(o.Cookie)
+
+	stateID, signature, ok := strings.Cut(state, ".")
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(cookie)) != 1 ||
+		!ok || !hmac.Equal([]byte(signature), []byte(signState(stateID))) {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid OAuth state")
+	}
+
+	if !cache.IsStoreAvailable(o.Store) {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Login is temporarily unavailable")
+	}
+
+	key := cache.GetOAuthStateKey(stateID)
+	data, err := o.Store.Get(key)
+	if err != nil {
+		utils.Log.Errorf("Failed to load OAuth state: %+v", err)
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Login is temporarily unavailable")
+	}
+	if data == nil {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Login expired, please try again")
+	}
+	if err := o.Store.Delete(key); err != nil {
+		utils.Log.Warnf("Failed to delete OAuth state: %v", err)
+	}
+
+	pending := new(pendingLogin)
+	if err := json.Unmarshal(data, pending); err != nil {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid OAuth state")
+	}
+
+	return pending, nil
+}
+
+// signState ties a state ID to this server's secret so forged states are rejected before any lookup
+func signState(stateID string) string {
+	mac := hmac.New(sha256.New, []byte(config.JWTSecret))
+	mac.Write([]byte("oauth-state:" + stateID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// nonceMatches compares an ID token's nonce with the one stored for the login
+func nonceMatches(claimed, expected string) bool {
+	return claimed != "" && subtle.ConstantTimeCompare([]byte(claimed), []byte(expected)) == 1
+}
+
+func randomURLToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/encryption"
+	"app/src/model"
+	"app/src/requestsig"
+	"app/src/utils"
+	"app/src/validation"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PartnerSecretPrefix marks partner signing secrets
+const PartnerSecretPrefix = "psk_"
+
+// partnerUsageInterval throttles last_used_at writes for busy partners
+const partnerUsageInterval = time.Minute
+
+func init() {
+	encryption.Register("partners", "signing_key")
+}
+
+type PartnerService interface {
+	CreatePartner(c *fiber.Ctx, req *validation.CreatePartner) (*model.Partner, string, error)
+	ListPartners(c *fiber.Ctx) ([]model.Partner, error)
+	DeletePartner(c *fiber.Ctx, id string) error
+	Authenticate(c *fiber.Ctx) (*model.Partner, error)
+}
+
+type partnerService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	AuditService AuditService
+	Verifier     *requestsig.Verifier
+	Clock        clock.Clock
+}
+
+// NewPartnerService manages partners and verifies their signed requests, claiming nonces in the
+// cache store. Without a store that counts (Redis or memory), signed requests are rejected.
+func NewPartnerService(
+	db *gorm.DB, validate *validator.Validate, store cache.Store, auditService AuditService, clk clock.Clock,
+) PartnerService {
+	nonces, _ := store.(cache.Counter)
+	return &partnerService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		AuditService: auditService,
+		Verifier:     requestsig.NewVerifier(nonces, config.RequestSigningMaxSkew, clk),
+		Clock:        clock.OrSystem(clk),
+	}
+}
+
+// CreatePartner registers a partner; its secret is only returned once
+func (s *partnerService) CreatePartner(c *fiber.Ctx, req *validation.CreatePartner) (*model.Partner, string, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, "", err
+	}
+
+	secret, err := generatePartnerSecret()
+	if err != nil {
+		s.Log.Errorf("Failed to generate partner secret: %+v", err)
+		return nil, "", err
+	}
+
+	partner := &model.Partner{
+		Name:       req.Name,
+		SigningKey: hex.EncodeToString(requestsig.Key(secret)),
+		Prefix:     secret[:len(PartnerSecretPrefix)+6],
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		partner.CreatedBy = &actor.ID
+	}
+
+	err = s.DB.WithContext(c.UserContext()).Create(partner).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, "", fiber.NewError(fiber.StatusConflict, "Partner already exists")
+	}
+	if errors.Is(err, encryption.ErrNoKeys) {
+		s.Log.Error("Partners need ENCRYPTION_KEYS to store their signing keys")
+		return nil, "", fiber.NewError(fiber.StatusServiceUnavailable, "Encryption keys are not configured")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to create partner: %+v", err)
+		return nil, "", err
+	}
+
+	s.audit(c, model.AuditActionPartnerCreated, partner)
+
+	return partner, secret, nil
+}
+
+func (s *partnerService) ListPartners(c *fiber.Ctx) ([]model.Partner, error) {
+	var partners []model.Partner
+	if err := s.DB.WithContext(c.UserContext()).Order("created_at").Find(&partners).Error; err != nil {
+		s.Log.Errorf("Failed to list partners: %+v", err)
+		return nil, err
+	}
+	return partners, nil
+}
+
+// DeletePartner removes a partner; its requests are rejected from then on
+func (s *partnerService) DeletePartner(c *fiber.Ctx, id string) error {
+	// RETURNING fills in the deleted partner for the audit entry
+	partner := new(model.Partner)
+	result := s.DB.WithContext(c.UserContext()).Clauses(clause.Returning{}).Where("id = ?", id).Delete(partner)
+	if result.Error != nil {
+		s.Log.Errorf("Failed to delete partner: %+v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Partner not found")
+	}
+
+	s.audit(c, model.AuditActionPartnerDeleted, partner)
+
+	return nil
+}
+
+// Authenticate verifies the signature headers of a partner request
+func (s *partnerService) Authenticate(c *fiber.Ctx) (*model.Partner, error) {
+	invalid := fiber.NewError(fiber.StatusUnauthorized, "Invalid request signature")
+
+	partnerID, err := uuid.Parse(c.Get(requestsig.HeaderPartnerID))
+	if err != nil {
+		return nil, invalid
+	}
+
+	partner := new(model.Partner)
+	result := s.DB.WithContext(c.UserContext()).Where("id = ?", partnerID).First(partner)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, invalid
+	}
+	if result.Error != nil {
+		s.Log.Errorf("Failed to look up partner: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	key, err := hex.DecodeString(partner.SigningKey)
+	if err != nil {
+		s.Log.Errorf("Failed to decode the signing key of partner %s: %+v", partner.ID, err)
+		return nil, invalid
+	}
+
+	err = s.Verifier.Verify(c.UserContext(), partner.ID.String(), key, requestsig.Request{
+		Timestamp: c.Get(requestsig.HeaderTimestamp),
+		Nonce:     c.Get(requestsig.HeaderNonce),
+		Method:    c.Method(),
+		URI:       string(c.Request().RequestURI()),
+		Body:      c.Body(),
+	}, c.Get(requestsig.HeaderSignature))
+
+	switch {
+	case err == nil:
+	case errors.Is(err, requestsig.ErrStale):
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Request timestamp is outside the allowed skew")
+	case errors.Is(err, requestsig.ErrReplayed):
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Request nonce was already used")
+	case errors.Is(err, requestsig.ErrNonceStoreUnavailable):
+		s.Log.Warnf("Rejected signed request of partner %s: %v", partner.ID, err)
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Request verification is temporarily unavailable")
+	default:
+		return nil, invalid
+	}
+
+	// Record usage at most once per interval to keep busy partners from writing on every request
+	now := s.Clock.Now()
+	if partner.LastUsedAt == nil || now.Sub(*partner.LastUsedAt) > partnerUsageInterval {
+		if err := s.DB.WithContext(c.UserContext()).Model(partner).Update("last_used_at", now).Error; err != nil {
+			s.Log.Warnf("Failed to record partner usage: %v", err)
+		}
+	}
+
+	return partner, nil
+}
+
+func (s *partnerService) audit(c *fiber.Ctx, action string, partner *model.Partner) {
+	metadata := map[string]any{"partner_id": partner.ID, "name": partner.Name}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, nil, action, metadata)
+}
+
+// generatePartnerSecret returns a random secret with the partner secret prefix
+func generatePartnerSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return PartnerSecretPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
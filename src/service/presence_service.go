@@ -0,0 +1,160 @@
+package service
+
+import (
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/presence"
+	"app/src/response"
+	"app/src/revocation"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// PresenceSocket is the WebSocket connection a user's presence is tracked over
+type PresenceSocket interface {
+	ReadMessage() (messageType int, data []byte, err error)
+	WriteJSON(v interface{}) error
+	SetReadDeadline(t time.Time) error
+	Close() error
+}
+
+type PresenceService interface {
+	// Serve keeps the user online for as long as the socket sends heartbeats, and returns once it closes
+	Serve(ctx context.Context, socket PresenceSocket, userID uuid.UUID)
+	GetPresence(c *fiber.Ctx, userID string) (*response.Presence, error)
+	LookupPresence(c *fiber.Ctx, req *validation.LookupPresence) ([]response.Presence, error)
+}
+
+type presenceService struct {
+	Log         *logrus.Logger
+	DB          *gorm.DB
+	Validate    *validator.Validate
+	Tracker     *presence.Tracker
+	Revocations *revocation.Bus
+	Clock       clock.Clock
+}
+
+// NewPresenceService tracks presence with tracker; sockets of revoked users are closed through revocations
+func NewPresenceService(
+	db *gorm.DB, validate *validator.Validate, tracker *presence.Tracker, revocations *revocation.Bus, clk clock.Clock,
+) PresenceService {
+	return &presenceService{
+		Log:         utils.Log,
+		DB:          db,
+		Validate:    validate,
+		Tracker:     tracker,
+		Revocations: revocations,
+		Clock:       clock.OrSystem(clk),
+	}
+}
+
+// Serve treats any message from the client as a heartbeat, but records at most one per half
+// interval so a chatty client cannot flood the store. A socket silent for config.Presence.TTL is closed.
+func (s *presenceService) Serve(ctx context.Context, socket PresenceSocket, userID uuid.UUID) {
+	defer socket.Close()
+
+	connID, err := s.Tracker.Connect(ctx, userID.String())
+	if err != nil {
+		s.Log.Warnf("Failed to track presence of user %s: %v", userID, err)
+		return
+	}
+	defer func() {
+		// The request context may already be gone once the socket closes
+		if err := s.Tracker.Disconnect(context.WithoutCancel(ctx), userID.String(), connID); err != nil {
+			s.Log.Warnf("Failed to end presence of user %s: %v", userID, err)
+		}
+	}()
+
+	untrack := s.Revocations.Track(userID.String(), func() { _ = socket.Close() })
+	defer untrack()
+
+	welcome := response.PresenceWelcome{
+		Type:              "welcome",
+		HeartbeatInterval: int(config.Presence.HeartbeatInterval.Seconds()),
+	}
+	if err := socket.WriteJSON(welcome); err != nil {
+		return
+	}
+
+	lastHeartbeat := s.Clock.Now()
+	for {
+		if err := socket.SetReadDeadline(s.Clock.Now().Add(config.Presence.TTL)); err != nil {
+			return
+		}
+		if _, _, err := socket.ReadMessage(); err != nil {
+			return
+		}
+
+		now := s.Clock.Now()
+		if now.Sub(lastHeartbeat) < config.Presence.HeartbeatInterval/2 {
+			continue
+		}
+		lastHeartbeat = now
+		if err := s.Tracker.Heartbeat(ctx, userID.String(), connID); err != nil {
+			s.Log.Warnf("Failed to record presence heartbeat of user %s: %v", userID, err)
+		}
+	}
+}
+
+func (s *presenceService) GetPresence(c *fiber.Ctx, userID string) (*response.Presence, error) {
+	user := new(model.User)
+	err := s.DB.WithContext(c.UserContext()).Select("id").Where("id = ?", userID).Take(user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to get user: %+v", err)
+		return nil, err
+	}
+
+	presences, err := s.lookup(c.UserContext(), []string{user.ID.String()})
+	if err != nil {
+		return nil, err
+	}
+	return &presences[0], nil
+}
+
+// LookupPresence reports unknown users as offline rather than failing the whole lookup
+func (s *presenceService) LookupPresence(c *fiber.Ctx, req *validation.LookupPresence) ([]response.Presence, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if len(req.UserIDs) > config.Presence.MaxLookup {
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("At most %d users can be looked up at once", config.Presence.MaxLookup))
+	}
+
+	return s.lookup(c.UserContext(), req.UserIDs)
+}
+
+func (s *presenceService) lookup(ctx context.Context, userIDs []string) ([]response.Presence, error) {
+	presences, err := s.Tracker.Lookup(ctx, userIDs)
+	if err != nil {
+		s.Log.Errorf("Failed to look up presence: %+v", err)
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Presence unavailable")
+	}
+
+	result := make([]response.Presence, len(presences))
+	for i, p := range presences {
+		result[i] = response.Presence{
+			UserID:   uuid.MustParse(p.UserID),
+			Status:   presence.StatusOffline,
+			LastSeen: p.LastSeen,
+		}
+		if p.Online {
+			result[i].Status = presence.StatusOnline
+		}
+	}
+	return result, nil
+}
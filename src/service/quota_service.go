@@ -0,0 +1,623 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/metrics"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// quotaOverridesTTL is how long admin overrides are served from memory, so other instances
+// pick up changes within this delay
+const quotaOverridesTTL = time.Minute
+
+// quotaExemptRight exempts its holders from quotas, so admins cannot lock themselves out
+const quotaExemptRight = "manageQuotas"
+
+var (
+	// ErrDailyQuotaExceeded rejects requests until the next UTC day
+	ErrDailyQuotaExceeded = fiber.NewError(fiber.StatusTooManyRequests, "Daily request quota exceeded")
+
+	// ErrMonthlyQuotaExceeded rejects requests until the next month or a plan upgrade; 402 tells it apart
+	// from rate limiting
+	ErrMonthlyQuotaExceeded = fiber.NewError(fiber.StatusPaymentRequired, "Monthly request quota exceeded")
+)
+
+// QuotaUsage is where a caller stands against the quota of one period
+type QuotaUsage struct {
+	Period string
+	Limit  int64
+	Used   int64
+	Reset  time.Time
+}
+
+// Remaining returns the requests left in the period
+func (u QuotaUsage) Remaining() int64 {
+	return max(u.Limit-u.Used, 0)
+}
+
+type QuotaService interface {
+	Consume(ctx context.Context, user *model.User, token *model.APIToken) ([]QuotaUsage, error)
+	Persist(ctx context.Context) (int, error)
+	GetUserQuota(c *fiber.Ctx, userID string) (*response.Quota, error)
+	UpdateUserQuota(c *fiber.Ctx, userID string, req *validation.UpdateUserQuota) (*response.Quota, error)
+	GetTokenQuota(c *fiber.Ctx, tokenID string) (*response.Quota, error)
+	UpdateTokenQuota(c *fiber.Ctx, tokenID string, req *validation.UpdateTokenQuota) (*response.Quota, error)
+}
+
+type quotaService struct {
+	Log            *logrus.Logger
+	DB             *gorm.DB
+	Validate       *validator.Validate
+	Store          cache.Store
+	UserService    UserService
+	SessionService SessionService
+	AuditService   AuditService
+	Config         config.QuotaConfig
+	Clock          clock.Clock
+
+	mu        sync.Mutex
+	overrides map[string]model.RequestQuota
+	loadedAt  time.Time
+}
+
+// quotaSubject is a user or API token counted against its limits
+type quotaSubject struct {
+	subject string
+	limits  config.QuotaLimits
+}
+
+// quotaPeriod is the UTC day or calendar month a request is counted in
+type quotaPeriod struct {
+	name  string
+	start time.Time
+	reset time.Time
+}
+
+// NewQuotaService counts requests against plan quotas in the cache store, which must support
+// atomic increments; Persist copies the counters to the database so they survive a cache flush
+// and can be billed. Periods are timed with clk, the wall clock if nil.
+func NewQuotaService(
+	db *gorm.DB, validate *validator.Validate, store cache.Store, userService UserService,
+	sessionService SessionService, auditService AuditService, cfg config.QuotaConfig, clk clock.Clock,
+) QuotaService {
+	return &quotaService{
+		Log:            utils.Log,
+		DB:             db,
+		Validate:       validate,
+		Store:          store,
+		UserService:    userService,
+		SessionService: sessionService,
+		AuditService:   auditService,
+		Config:         cfg,
+		Clock:          clock.OrSystem(clk),
+	}
+}
+
+// Consume counts a request against the user's quotas and, when an admin gave it its own limits, the
+// API token's. It returns the tightest usage per limited period. A rejected request is not counted.
+// Quotas fail open: if the cache store cannot count, the request is let through.
+func (s *quotaService) Consume(ctx context.Context, user *model.User, token *model.APIToken) ([]QuotaUsage, error) {
+	counter, ok := s.Store.(cache.Counter)
+	if !s.Config.Enabled || !ok || !cache.IsStoreAvailable(s.Store) {
+		return nil, nil
+	}
+
+	if slices.Contains(config.RoleRights[user.Role], quotaExemptRight) {
+		return nil, nil
+	}
+
+	subjects := s.subjects(ctx, user, token)
+	now := s.Clock.Now()
+	var (
+		counted  []string
+		usage    []QuotaUsage
+		exceeded error
+	)
+
+	for _, period := range quotaPeriods(now) {
+		tightest := QuotaUsage{Period: period.name, Reset: period.reset}
+
+		for _, subject := range subjects {
+			key := cache.GetQuotaKey(subject.subject, period.name, period.start.Format(time.DateOnly))
+			used, err := s.increment(ctx, counter, key, subject.subject, period, now)
+			if err != nil {
+				s.Log.Warnf("Failed to count request quota, letting the request through: %v", err)
+				s.rollback(ctx, counter, counted)
+				return nil, nil
+			}
+			counted = append(counted, key)
+
+			limit := period.limit(subject.limits)
+			if limit == 0 {
+				continue
+			}
+
+			current := QuotaUsage{Period: period.name, Limit: limit, Used: min(used, limit), Reset: period.reset}
+			if tightest.Limit == 0 || current.Remaining() < tightest.Remaining() {
+				tightest = current
+			}
+
+			if used > limit && exceeded != ErrMonthlyQuotaExceeded {
+				exceeded = period.err()
+			}
+		}
+
+		if tightest.Limit > 0 {
+			usage = append(usage, tightest)
+		}
+	}
+
+	if exceeded != nil {
+		s.rollback(ctx, counter, counted)
+		if exceeded == ErrMonthlyQuotaExceeded {
+			metrics.QuotaExceeded(model.QuotaMonthly)
+		} else {
+			metrics.QuotaExceeded(model.QuotaDaily)
+		}
+	}
+
+	return usage, exceeded
+}
+
+// Persist copies the counters in the cache store to the database and returns how many it wrote.
+// Stored counts only grow, so a counter restarted after a cache flush cannot lower them.
+func (s *quotaService) Persist(ctx context.Context) (int, error) {
+	if s.Store == nil || !cache.IsStoreAvailable(s.Store) {
+		return 0, nil
+	}
+
+	keys, err := s.Store.Keys(ctx, cache.QuotaKeyPrefix+"*")
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.Clock.Now()
+	rows := make([]model.RequestQuotaUsage, 0, len(keys))
+	for _, key := range keys {
+		subject, period, start, ok := parseQuotaKey(key)
+		if !ok {
+			continue
+		}
+
+		data, err := s.Store.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+
+		used, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		rows = append(rows, model.RequestQuotaUsage{
+			Subject: subject, Period: period, PeriodStart: start, Used: used, UpdatedAt: now,
+		})
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	err = s.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "subject"}, {Name: "period"}, {Name: "period_start"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"used":       gorm.Expr("GREATEST(request_quota_usage.used, EXCLUDED.used)"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).CreateInBatches(&rows, 500).Error
+
+	if err != nil {
+		s.Log.Errorf("Failed to persist request quota usage: %+v", err)
+		return 0, err
+	}
+
+	return len(rows), nil
+}
+
+// GetUserQuota returns the user's plan, limits and usage in the current day and month
+func (s *quotaService) GetUserQuota(c *fiber.Ctx, userID string) (*response.Quota, error) {
+	user, err := s.UserService.GetUserByID(c, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userQuota(c.UserContext(), user)
+}
+
+// UpdateUserQuota moves the user to another plan, if given, and replaces their quota override.
+// Changes apply on this instance at once and on others within a minute.
+func (s *quotaService) UpdateUserQuota(
+	c *fiber.Ctx, userID string, req *validation.UpdateUserQuota,
+) (*response.Quota, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserService.GetUserByID(c, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan string
+	if req.Plan != nil {
+		plan = strings.ToLower(strings.TrimSpace(*req.Plan))
+		if _, ok := s.Config.Plans[plan]; !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Unknown plan")
+		}
+
+		if err := s.DB.WithContext(c.UserContext()).Model(user).Update("plan", plan).Error; err != nil {
+			s.Log.Errorf("Failed to update user plan: %+v", err)
+			return nil, err
+		}
+		user.Plan = plan
+	}
+
+	subject := model.UserQuotaSubject(user.ID)
+	if err := s.saveOverride(c, subject, req.DailyLimit, req.MonthlyLimit); err != nil {
+		return nil, err
+	}
+
+	s.audit(c, subject, plan, req.DailyLimit, req.MonthlyLimit)
+
+	return s.userQuota(c.UserContext(), user)
+}
+
+// GetTokenQuota returns the API token's own limits and usage in the current day and month
+func (s *quotaService) GetTokenQuota(c *fiber.Ctx, tokenID string) (*response.Quota, error) {
+	token, err := s.getToken(c, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokenQuota(c.UserContext(), token)
+}
+
+// UpdateTokenQuota replaces the API token's own limits; requests made with it still count against
+// its owner's quota as well
+func (s *quotaService) UpdateTokenQuota(
+	c *fiber.Ctx, tokenID string, req *validation.UpdateTokenQuota,
+) (*response.Quota, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	token, err := s.getToken(c, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := model.TokenQuotaSubject(token.ID)
+	if err := s.saveOverride(c, subject, req.DailyLimit, req.MonthlyLimit); err != nil {
+		return nil, err
+	}
+
+	s.audit(c, subject, "", req.DailyLimit, req.MonthlyLimit)
+
+	return s.tokenQuota(c.UserContext(), token)
+}
+
+// subjects returns the quotas the request counts against: always the user's, and the token's
+// when an admin gave it its own limits
+func (s *quotaService) subjects(ctx context.Context, user *model.User, token *model.APIToken) []quotaSubject {
+	overrides := s.loadOverrides(ctx)
+
+	userSubject := model.UserQuotaSubject(user.ID)
+	override, ok := overrides[userSubject]
+	subjects := []quotaSubject{{subject: userSubject, limits: s.userLimits(user.Plan, override, ok)}}
+
+	if token != nil {
+		tokenSubject := model.TokenQuotaSubject(token.ID)
+		if override, ok := overrides[tokenSubject]; ok {
+			subjects = append(subjects, quotaSubject{subject: tokenSubject, limits: tokenLimits(override)})
+		}
+	}
+
+	return subjects
+}
+
+// increment counts one request; a new counter first catches up with the persisted count, so usage
+// survives the cache store losing its data
+func (s *quotaService) increment(
+	ctx context.Context, counter cache.Counter, key, subject string, period quotaPeriod, now time.Time,
+) (int64, error) {
+	// Keep the counter until a persist after the period ended has seen it
+	ttl := period.reset.Sub(now) + 2*s.Config.PersistInterval
+
+	used, err := counter.IncrBy(ctx, key, 1, ttl)
+	if err != nil || used != 1 || s.DB == nil {
+		return used, err
+	}
+
+	persisted, err := s.persisted(ctx, subject, period.name, period.start)
+	if err != nil {
+		s.Log.Warnf("Failed to load persisted request quota usage: %v", err)
+		return used, nil
+	}
+	if persisted == 0 {
+		return used, nil
+	}
+
+	return counter.IncrBy(ctx, key, persisted, ttl)
+}
+
+// rollback uncounts a rejected request
+func (s *quotaService) rollback(ctx context.Context, counter cache.Counter, keys []string) {
+	for _, key := range keys {
+		if _, err := counter.IncrBy(ctx, key, -1, 0); err != nil {
+			s.Log.Warnf("Failed to roll back request quota counter: %v", err)
+		}
+	}
+}
+
+// loadOverrides returns the admin overrides by subject, reloading them once they are older than
+// quotaOverridesTTL. If reloading fails the previous overrides are kept.
+func (s *quotaService) loadOverrides(ctx context.Context) map[string]model.RequestQuota {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Without a database there are no overrides and usage is not persisted
+	if s.DB == nil {
+		return nil
+	}
+
+	if s.Clock.Now().Sub(s.loadedAt) >= quotaOverridesTTL {
+		var stored []model.RequestQuota
+		if err := s.DB.WithContext(ctx).Find(&stored).Error; err != nil {
+			s.Log.Warnf("Failed to load request quota overrides, using previous overrides: %v", err)
+		} else {
+			s.overrides = make(map[string]model.RequestQuota, len(stored))
+			for _, override := range stored {
+				s.overrides[override.Subject] = override
+			}
+		}
+		s.loadedAt = s.Clock.Now()
+	}
+
+	return s.overrides
+}
+
+// forget makes the next request reload the admin overrides
+func (s *quotaService) forget() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// saveOverride stores the subject's limits, or removes its override when both are omitted
+func (s *quotaService) saveOverride(c *fiber.Ctx, subject string, daily, monthly *int64) error {
+	db := s.DB.WithContext(c.UserContext())
+
+	var err error
+	if daily == nil && monthly == nil {
+		err = db.Where("subject = ?", subject).Delete(new(model.RequestQuota)).Error
+	} else {
+		override := &model.RequestQuota{Subject: subject, DailyLimit: daily, MonthlyLimit: monthly}
+		if actor, ok := c.Locals("user").(*model.User); ok {
+			override.UpdatedBy = &actor.ID
+		}
+		err = db.Clauses(clause.OnConflict{UpdateAll: true}).Create(override).Error
+	}
+
+	if err != nil {
+		s.Log.Errorf("Failed to save request quota override: %+v", err)
+		return err
+	}
+
+	s.forget()
+	return nil
+}
+
+// override returns the subject's admin override straight from the database
+func (s *quotaService) override(ctx context.Context, subject string) (model.RequestQuota, bool, error) {
+	var override model.RequestQuota
+	err := s.DB.WithContext(ctx).Where("subject = ?", subject).First(&override).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return override, false, nil
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to get request quota override: %+v", err)
+		return override, false, err
+	}
+	return override, true, nil
+}
+
+// persisted returns the last persisted count of the subject in the period
+func (s *quotaService) persisted(ctx context.Context, subject, period string, start time.Time) (int64, error) {
+	var used int64
+	err := s.DB.WithContext(ctx).
+		Model(new(model.RequestQuotaUsage)).
+		Select("used").
+		Where("subject = ? AND period = ? AND period_start = ?", subject, period, start).
+		Scan(&used).Error
+	return used, err
+}
+
+func (s *quotaService) userQuota(ctx context.Context, user *model.User) (*response.Quota, error) {
+	subject := model.UserQuotaSubject(user.ID)
+	override, ok, err := s.override(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := user.Plan
+	if _, known := s.Config.Plans[plan]; !known {
+		plan = s.Config.DefaultPlan
+	}
+
+	quota := s.report(ctx, subject, s.userLimits(user.Plan, override, ok))
+	quota.Plan = plan
+	quota.Overridden = ok
+	return quota, nil
+}
+
+func (s *quotaService) tokenQuota(ctx context.Context, token *model.APIToken) (*response.Quota, error) {
+	subject := model.TokenQuotaSubject(token.ID)
+	override, ok, err := s.override(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	quota := s.report(ctx, subject, tokenLimits(override))
+	quota.Overridden = ok
+	return quota, nil
+}
+
+// report reads the subject's usage in the current periods from the cache store, falling back to
+// the persisted counts
+func (s *quotaService) report(ctx context.Context, subject string, limits config.QuotaLimits) *response.Quota {
+	quota := &response.Quota{Subject: subject}
+
+	for _, period := range quotaPeriods(s.Clock.Now()) {
+		used, err := s.persisted(ctx, subject, period.name, period.start)
+		if err != nil {
+			s.Log.Warnf("Failed to load persisted request quota usage: %v", err)
+		}
+
+		if s.Store != nil && cache.IsStoreAvailable(s.Store) {
+			data, _ := s.Store.Get(cache.GetQuotaKey(subject, period.name, period.start.Format(time.DateOnly)))
+			if counted, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+				used = max(used, counted)
+			}
+		}
+
+		usage := response.QuotaPeriod{Limit: period.limit(limits), Used: used, ResetsAt: period.reset}
+		if period.name == model.QuotaDaily {
+			quota.Daily = usage
+		} else {
+			quota.Monthly = usage
+		}
+	}
+
+	return quota
+}
+
+func (s *quotaService) getToken(c *fiber.Ctx, tokenID string) (*model.APIToken, error) {
+	if _, err := uuid.Parse(tokenID); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid token ID")
+	}
+
+	token := new(model.APIToken)
+	err := s.DB.WithContext(c.UserContext()).Where("id = ?", tokenID).First(token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "API token not found")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to get API token: %+v", err)
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (s *quotaService) audit(c *fiber.Ctx, subject, plan string, daily, monthly *int64) {
+	metadata := map[string]any{"subject": subject, "daily_limit": daily, "monthly_limit": monthly}
+	if plan != "" {
+		metadata["plan"] = plan
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, nil, model.AuditActionQuotaUpdated, metadata)
+}
+
+// userLimits returns the plan's limits with the admin override, if any, applied on top
+func (s *quotaService) userLimits(plan string, override model.RequestQuota, overridden bool) config.QuotaLimits {
+	limits := s.Config.PlanLimits(plan)
+	if !overridden {
+		return limits
+	}
+
+	if override.DailyLimit != nil {
+		limits.Daily = *override.DailyLimit
+	}
+	if override.MonthlyLimit != nil {
+		limits.Monthly = *override.MonthlyLimit
+	}
+	return limits
+}
+
+// tokenLimits returns an API token's own limits; tokens have no plan, so omitted limits are unlimited
+func tokenLimits(override model.RequestQuota) config.QuotaLimits {
+	var limits config.QuotaLimits
+	if override.DailyLimit != nil {
+		limits.Daily = *override.DailyLimit
+	}
+	if override.MonthlyLimit != nil {
+		limits.Monthly = *override.MonthlyLimit
+	}
+	return limits
+}
+
+// quotaPeriods returns the UTC day and calendar month containing now
+func quotaPeriods(now time.Time) []quotaPeriod {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return []quotaPeriod{
+		{name: model.QuotaDaily, start: day, reset: day.AddDate(0, 0, 1)},
+		{name: model.QuotaMonthly, start: month, reset: month.AddDate(0, 1, 0)},
+	}
+}
+
+func (p quotaPeriod) limit(limits config.QuotaLimits) int64 {
+	if p.name == model.QuotaDaily {
+		return limits.Daily
+	}
+	return limits.Monthly
+}
+
+func (p quotaPeriod) err() error {
+	if p.name == model.QuotaDaily {
+		return ErrDailyQuotaExceeded
+	}
+	return ErrMonthlyQuotaExceeded
+}
+
+// parseQuotaKey splits a counter key into its subject, period and period start
+func parseQuotaKey(key string) (string, string, time.Time, bool) {
+	rest := strings.TrimPrefix(key, cache.QuotaKeyPrefix)
+
+	rest, start, ok := cutLast(rest, ":")
+	if !ok {
+		return "", "", time.Time{}, false
+	}
+	subject, period, ok := cutLast(rest, ":")
+	if !ok || (period != model.QuotaDaily && period != model.QuotaMonthly) {
+		return "", "", time.Time{}, false
+	}
+
+	periodStart, err := time.Parse(time.DateOnly, start)
+	if err != nil {
+		return "", "", time.Time{}, false
+	}
+
+	return subject, period, periodStart, true
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
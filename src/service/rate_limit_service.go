@@ -0,0 +1,137 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimitInspector reads and clears one limiter's stored state for a subject
+type RateLimitInspector interface {
+	Name() string
+	Inspect(subject string) (*response.RateLimitState, error)
+	Reset(subject string) (bool, error)
+}
+
+type RateLimitService interface {
+	Inspect(c *fiber.Ctx, query *validation.RateLimitSubject) ([]response.RateLimitState, error)
+	Reset(c *fiber.Ctx, query *validation.RateLimitSubject) (int, error)
+}
+
+type rateLimitService struct {
+	Log          *logrus.Logger
+	Validate     *validator.Validate
+	Store        cache.Store
+	AuditService AuditService
+	Limiter      RateLimitInspector
+	Throttles    []RateLimitInspector
+}
+
+// NewRateLimitService inspects the global limiter (keyed by user or IP) and the per-email throttles
+func NewRateLimitService(
+	validate *validator.Validate, store cache.Store, auditService AuditService,
+	limiter RateLimitInspector, throttles ...RateLimitInspector,
+) RateLimitService {
+	return &rateLimitService{
+		Log:          utils.Log,
+		Validate:     validate,
+		Store:        store,
+		AuditService: auditService,
+		Limiter:      limiter,
+		Throttles:    throttles,
+	}
+}
+
+func (s *rateLimitService) Inspect(
+	c *fiber.Ctx, query *validation.RateLimitSubject,
+) ([]response.RateLimitState, error) {
+	subject, inspectors, err := s.resolve(query)
+	if err != nil {
+		return nil, err
+	}
+
+	states := []response.RateLimitState{}
+	for _, inspector := range inspectors {
+		state, err := inspector.Inspect(subject)
+		if err != nil {
+			s.Log.Errorf("Failed to inspect %s state: %+v", inspector.Name(), err)
+			return nil, err
+		}
+		if state != nil {
+			states = append(states, *state)
+		}
+	}
+
+	return states, nil
+}
+
+// Reset clears every limiter holding state for the subject and audits the manual reset
+func (s *rateLimitService) Reset(c *fiber.Ctx, query *validation.RateLimitSubject) (int, error) {
+	subject, inspectors, err := s.resolve(query)
+	if err != nil {
+		return 0, err
+	}
+
+	var cleared []string
+	for _, inspector := range inspectors {
+		ok, err := inspector.Reset(subject)
+		if err != nil {
+			s.Log.Errorf("Failed to reset %s state: %+v", inspector.Name(), err)
+			return 0, err
+		}
+		if ok {
+			cleared = append(cleared, inspector.Name())
+		}
+	}
+
+	metadata := map[string]any{"subject": subject, "limiters": cleared}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+
+	// Resets for a user land on their timeline; IP and email resets are kept as system entries
+	var userID *uuid.UUID
+	if id, err := uuid.Parse(query.UserID); err == nil {
+		userID = &id
+	}
+	s.AuditService.Record(c, userID, model.AuditActionRateLimitReset, metadata)
+
+	s.Log.Infof("Reset rate limits for %s (%s)", subject, strings.Join(cleared, ", "))
+
+	return len(cleared), nil
+}
+
+// resolve maps the query to the limiter subject and the inspectors keyed by it
+func (s *rateLimitService) resolve(query *validation.RateLimitSubject) (string, []RateLimitInspector, error) {
+	if err := s.Validate.Struct(query); err != nil {
+		return "", nil, err
+	}
+
+	if !cache.IsStoreAvailable(s.Store) {
+		return "", nil, fiber.NewError(fiber.StatusServiceUnavailable, "Cache unavailable")
+	}
+
+	switch {
+	case query.UserID != "":
+		return "user:" + query.UserID, s.limiter(), nil
+	case query.IP != "":
+		return "ip:" + query.IP, s.limiter(), nil
+	default:
+		return strings.ToLower(strings.TrimSpace(query.Email)), s.Throttles, nil
+	}
+}
+
+func (s *rateLimitService) limiter() []RateLimitInspector {
+	if s.Limiter == nil {
+		return nil
+	}
+	return []RateLimitInspector{s.Limiter}
+}
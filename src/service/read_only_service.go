@@ -0,0 +1,98 @@
+package service
+
+import (
+	"app/src/clock"
+	"app/src/model"
+	"app/src/readonly"
+	"app/src/redis"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ReadOnlyService interface {
+	GetReadOnly(c *fiber.Ctx) response.ReadOnly
+	SetReadOnly(c *fiber.Ctx, req *validation.SetReadOnly) (response.ReadOnly, error)
+}
+
+type readOnlyService struct {
+	Log          *logrus.Logger
+	Validate     *validator.Validate
+	Switch       *readonly.Switch
+	AuditService AuditService
+	Clock        clock.Clock
+}
+
+// NewReadOnlyService flips the read-only switch shared by every instance
+func NewReadOnlyService(
+	validate *validator.Validate, readOnlySwitch *readonly.Switch, auditService AuditService, clk clock.Clock,
+) ReadOnlyService {
+	return &readOnlyService{
+		Log:          utils.Log,
+		Validate:     validate,
+		Switch:       readOnlySwitch,
+		AuditService: auditService,
+		Clock:        clock.OrSystem(clk),
+	}
+}
+
+func (s *readOnlyService) GetReadOnly(_ *fiber.Ctx) response.ReadOnly {
+	return readOnlyResponse(readonly.Current())
+}
+
+// SetReadOnly switches read-only mode on or off for every instance. READ_ONLY keeps it on whatever
+// the switch says, so switching it off is refused then.
+func (s *readOnlyService) SetReadOnly(c *fiber.Ctx, req *validation.SetReadOnly) (response.ReadOnly, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return response.ReadOnly{}, err
+	}
+	if !*req.Enabled && readonly.Current().Forced {
+		return response.ReadOnly{}, fiber.NewError(fiber.StatusConflict, "Read-only mode is forced by READ_ONLY")
+	}
+
+	state := readonly.State{Enabled: *req.Enabled}
+	metadata := map[string]any{}
+	if state.Enabled {
+		now := s.Clock.Now()
+		state.Reason, state.Since = req.Reason, &now
+		metadata["reason"] = req.Reason
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+		if state.Enabled {
+			state.By = actor.ID.String()
+		}
+	}
+
+	if err := s.Switch.Set(c.UserContext(), state); err != nil {
+		if errors.Is(err, redis.ErrRedisUnavailable) {
+			return response.ReadOnly{}, fiber.NewError(fiber.StatusServiceUnavailable,
+				"Redis is unavailable, read-only mode cannot be shared with the other instances")
+		}
+		s.Log.Errorf("Failed to set read-only mode: %+v", err)
+		return response.ReadOnly{}, err
+	}
+
+	action := model.AuditActionReadOnlyDisabled
+	if state.Enabled {
+		action = model.AuditActionReadOnlyEnabled
+	}
+	s.AuditService.Record(c, nil, action, metadata)
+
+	return readOnlyResponse(readonly.Current()), nil
+}
+
+func readOnlyResponse(state readonly.State) response.ReadOnly {
+	return response.ReadOnly{
+		Enabled: state.Enabled,
+		Reason:  state.Reason,
+		Since:   state.Since,
+		By:      state.By,
+		Forced:  state.Forced,
+	}
+}
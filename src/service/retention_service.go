@@ -0,0 +1,166 @@
+package service
+
+import (
+	"app/src/clock"
+	"app/src/config"
+	"app/src/dryrun"
+	"app/src/model"
+	"app/src/response"
+	"app/src/retention"
+	"app/src/utils"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type RetentionService interface {
+	Purge(ctx context.Context) ([]response.RetentionPolicy, error)
+	GetReport(c *fiber.Ctx) ([]response.RetentionPolicy, error)
+	PurgeNow(c *fiber.Ctx) ([]response.RetentionPolicy, error)
+}
+
+type retentionService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	AuditService AuditService
+	Config       config.RetentionConfig
+	Clock        clock.Clock
+}
+
+func NewRetentionService(
+	db *gorm.DB, auditService AuditService, cfg config.RetentionConfig, clk clock.Clock,
+) RetentionService {
+	return &retentionService{
+		Log:          utils.Log,
+		DB:           db,
+		AuditService: auditService,
+		Config:       cfg,
+		Clock:        clock.OrSystem(clk),
+	}
+}
+
+// Purge deletes the rows past the retention of every policy, for the retention job. In dry-run mode
+// they are only counted and logged.
+func (s *retentionService) Purge(ctx context.Context) ([]response.RetentionPolicy, error) {
+	if s.Config.DryRun {
+		report, err := s.report(ctx)
+		if err != nil {
+			s.Log.Errorf("Failed to count rows past retention: %+v", err)
+			return nil, err
+		}
+		for _, policy := range report {
+			s.Log.Infof("Retention dry run: %d rows of %s are older than %d days",
+				policy.Rows, policy.Name, policy.RetentionDays)
+		}
+		return report, nil
+	}
+
+	purged, err := s.purge(ctx)
+	if metadata := purgedMetadata(purged); metadata != nil {
+		s.AuditService.RecordSystem(ctx, nil, model.AuditActionRetentionPurged, metadata)
+	}
+	return purged, err
+}
+
+// GetReport counts the rows of every policy past its retention, which the next run would purge
+func (s *retentionService) GetReport(c *fiber.Ctx) ([]response.RetentionPolicy, error) {
+	report, err := s.report(c.UserContext())
+	if err != nil {
+		s.Log.Errorf("Failed to count rows past retention: %+v", err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// PurgeNow purges every policy at once for an admin, whether or not the job is enabled; with
+// ?dryRun=true it reports the rows instead. A purge cut short by the request's deadline is finished
+// by later runs.
+func (s *retentionService) PurgeNow(c *fiber.Ctx) ([]response.RetentionPolicy, error) {
+	if dryrun.Requested(c) {
+		report, err := s.GetReport(c)
+		if err != nil {
+			return nil, err
+		}
+
+		affected := make(map[string]int64, len(report))
+		for _, policy := range report {
+			affected[policy.Name] = policy.Rows
+		}
+		return nil, dryrun.Stop(response.DryRun{Action: "retention.purge", Affected: affected, Sample: []string{}})
+	}
+
+	purged, err := s.purge(c.UserContext())
+	if metadata := purgedMetadata(purged); metadata != nil {
+		if actor, ok := c.Locals("user").(*model.User); ok {
+			metadata["actor_id"] = actor.ID
+		}
+		s.AuditService.Record(c, nil, model.AuditActionRetentionPurged, metadata)
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to purge rows past retention: %+v", err)
+		return nil, err
+	}
+
+	return purged, nil
+}
+
+func (s *retentionService) report(ctx context.Context) ([]response.RetentionPolicy, error) {
+	now := s.Clock.Now()
+	policies := retention.Policies(s.Config)
+
+	report := make([]response.RetentionPolicy, 0, len(policies))
+	for _, policy := range policies {
+		due, err := retention.Due(ctx, s.DB, policy, policy.Cutoff(now))
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, retentionPolicyResponse(policy, now, due))
+	}
+
+	return report, nil
+}
+
+// purge returns what it deleted before an error too, so it can still be audited
+func (s *retentionService) purge(ctx context.Context) ([]response.RetentionPolicy, error) {
+	now := s.Clock.Now()
+	policies := retention.Policies(s.Config)
+
+	purged := make([]response.RetentionPolicy, 0, len(policies))
+	for _, policy := range policies {
+		rows, err := retention.Purge(ctx, s.DB, policy, policy.Cutoff(now), s.Config.BatchSize)
+		purged = append(purged, retentionPolicyResponse(policy, now, rows))
+		if err != nil {
+			return purged, err
+		}
+	}
+
+	return purged, nil
+}
+
+func retentionPolicyResponse(policy retention.Policy, now time.Time, rows int64) response.RetentionPolicy {
+	return response.RetentionPolicy{
+		Name:          policy.Name,
+		Table:         policy.Table,
+		RetentionDays: int(policy.Retention.Hours() / 24),
+		Cutoff:        policy.Cutoff(now),
+		Rows:          rows,
+	}
+}
+
+// purgedMetadata maps each policy to the rows it deleted, or returns nil when nothing was deleted
+func purgedMetadata(purged []response.RetentionPolicy) map[string]any {
+	metadata := map[string]any{}
+	for _, policy := range purged {
+		if policy.Rows > 0 {
+			metadata[policy.Name] = policy.Rows
+		}
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
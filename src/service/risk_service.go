@@ -0,0 +1,135 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/notification"
+	"app/src/risk"
+	"app/src/utils"
+	"app/src/validation"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type RiskService interface {
+	Evaluate(c *fiber.Ctx, user *model.User) error
+	RecordFailure(c *fiber.Ctx)
+	ConfirmLogin(c *fiber.Ctx, query *validation.Token) (*model.User, error)
+}
+
+type riskService struct {
+	Log                 *logrus.Logger
+	DB                  *gorm.DB
+	Validate            *validator.Validate
+	Engine              *risk.Engine
+	UserService         UserService
+	TokenService        TokenService
+	NotificationService NotificationService
+	AuditService        AuditService
+}
+
+func NewRiskService(
+	db *gorm.DB, validate *validator.Validate, engine *risk.Engine, userService UserService,
+	tokenService TokenService, notificationService NotificationService, auditService AuditService,
+) RiskService {
+	return &riskService{
+		Log:                 utils.Log,
+		DB:                  db,
+		Validate:            validate,
+		Engine:              engine,
+		UserService:         userService,
+		TokenService:        tokenService,
+		NotificationService: notificationService,
+		AuditService:        auditService,
+	}
+}
+
+// Evaluate scores a login with a correct password; it returns 403 when the login is blocked
+// or held for email confirmation
+func (s *riskService) Evaluate(c *fiber.Ctx, user *model.User) error {
+	if s.Engine == nil {
+		return nil
+	}
+
+	attempt := s.Engine.NewAttempt(c, user.ID.String())
+	assessment := s.Engine.Assess(attempt)
+
+	s.AuditService.Record(c, &user.ID, model.AuditActionLoginAssessed, map[string]any{
+		"decision": assessment.Decision,
+		"score":    assessment.Score,
+		"signals":  assessment.Signals,
+		"country":  attempt.Location.Country,
+		"asn":      attempt.Location.ASN,
+	})
+
+	switch assessment.Decision {
+	case risk.DecisionBlock:
+		s.Log.Warnf("Blocked suspicious login for user %s (score %d: %v)", user.ID, assessment.Score, assessment.Signals)
+		return fiber.NewError(fiber.StatusForbidden, "Login blocked due to suspicious activity")
+
+	case risk.DecisionChallenge:
+		token, err := s.TokenService.GenerateConfirmLoginToken(c, user)
+		if err != nil {
+			return err
+		}
+		s.Engine.Hold(token, attempt)
+
+		// TODO: replace this url with the link to the login confirmation page of your front-end app
+		confirmLoginURL := fmt.Sprintf("http://link-to-app/confirm-login?token=%s", token)
+		data := notification.Data{URL: confirmLoginURL}
+		if err := s.NotificationService.Notify(c, user, notification.KindConfirmLogin, data); err != nil {
+			return err
+		}
+		return fiber.NewError(fiber.StatusForbidden,
+			"Unusual sign-in detected. Check your email or phone to confirm this login")
+	}
+
+	s.Engine.RecordSuccess(attempt)
+	return nil
+}
+
+// RecordFailure counts a failed login from the client IP towards the velocity rule
+func (s *riskService) RecordFailure(c *fiber.Ctx) {
+	s.Engine.RecordFailure(c.IP())
+}
+
+// ConfirmLogin consumes an emailed confirmation token and returns the user to sign in
+func (s *riskService) ConfirmLogin(c *fiber.Ctx, query *validation.Token) (*model.User, error) {
+	if err := s.Validate.Struct(query); err != nil {
+		return nil, err
+	}
+
+	userID, err := utils.VerifyToken(query.Token, config.JWTSecret, config.TokenTypeConfirmLogin)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid Token")
+	}
+
+	// Deleting the stored token makes the link single-use
+	result := s.DB.WithContext(c.UserContext()).
+		Where("token = ? AND user_id = ? AND type = ?", query.Token, userID, config.TokenTypeConfirmLogin).
+		Delete(new(model.Token))
+	if result.Error != nil {
+		s.Log.Errorf("Failed delete confirm login token: %+v", result.Error)
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid Token")
+	}
+
+	user, err := s.UserService.GetUserByID(c, userID)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Confirm login failed")
+	}
+
+	if attempt, ok := s.Engine.Release(query.Token); ok {
+		s.Engine.RecordSuccess(attempt)
+	}
+
+	s.AuditService.Record(c, &user.ID, model.AuditActionLoginConfirm, nil)
+
+	return user, nil
+}
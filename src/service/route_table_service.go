@@ -0,0 +1,32 @@
+package service
+
+import (
+	"app/src/response"
+	"app/src/routetable"
+	"app/src/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type RouteTableService interface {
+	GetRoutes(c *fiber.Ctx) []response.Route
+}
+
+type routeTableService struct {
+	Log *logrus.Logger
+	App *fiber.App
+}
+
+func NewRouteTableService(app *fiber.App) RouteTableService {
+	return &routeTableService{
+		Log: utils.Log,
+		App: app,
+	}
+}
+
+// GetRoutes lists the routes the app serves, read from the app at request time so routes
+// registered after this service was built are included
+func (s *routeTableService) GetRoutes(_ *fiber.Ctx) []response.Route {
+	return routetable.Table(s.App)
+}
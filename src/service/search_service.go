@@ -0,0 +1,61 @@
+package service
+
+import (
+	"app/src/response"
+	"app/src/search"
+	"app/src/utils"
+	"app/src/validation"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type SearchService interface {
+	Search(c *fiber.Ctx, params *validation.Search) ([]response.SearchHit, error)
+}
+
+type searchService struct {
+	Log      *logrus.Logger
+	Validate *validator.Validate
+	Engine   search.Engine
+}
+
+// NewSearchService runs admin searches on engine, which may be search.NewPostgres or an external
+// engine implementing search.Engine
+func NewSearchService(validate *validator.Validate, engine search.Engine) SearchService {
+	return &searchService{
+		Log:      utils.Log,
+		Validate: validate,
+		Engine:   engine,
+	}
+}
+
+// Search returns the users, audit entries, API tokens and live sessions matching the query, best first
+func (s *searchService) Search(c *fiber.Ctx, params *validation.Search) ([]response.SearchHit, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, err
+	}
+
+	query := search.Query{Text: params.Query, Types: params.Types, Limit: params.Limit}
+	hits, err := s.Engine.Search(c.UserContext(), query)
+	if err != nil {
+		s.Log.Errorf("Failed to search: %+v", err)
+		return nil, err
+	}
+
+	results := make([]response.SearchHit, len(hits))
+	for i, hit := range hits {
+		results[i] = response.SearchHit{
+			Type:       hit.Type,
+			ID:         hit.ID,
+			UserID:     hit.UserID,
+			Title:      hit.Title,
+			Detail:     hit.Detail,
+			Score:      hit.Score,
+			OccurredAt: hit.OccurredAt,
+		}
+	}
+
+	return results, nil
+}
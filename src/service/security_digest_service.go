@@ -0,0 +1,271 @@
+package service
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// digestTopAccounts caps how many of the most targeted accounts the digest lists
+const digestTopAccounts = 10
+
+// SecurityDigest summarises security-relevant audit entries between From and To
+type SecurityDigest struct {
+	From              time.Time
+	To                time.Time
+	FailedLogins      int64
+	BlockedLogins     int64
+	TopFailedAccounts []DigestAccount
+	RoleChanges       []DigestRoleChange
+	NewAdmins         []DigestRoleChange
+}
+
+type DigestAccount struct {
+	Email string
+	Count int64
+}
+
+type DigestRoleChange struct {
+	Email     string
+	From      string
+	To        string
+	CreatedAt time.Time
+}
+
+type SecurityDigestService interface {
+	Build(ctx context.Context, from, to time.Time) (*SecurityDigest, error)
+	Send(ctx context.Context, from, to time.Time) error
+	SentSince(ctx context.Context, since time.Time) (bool, error)
+}
+
+type securityDigestService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	EmailService EmailService
+	AuditService AuditService
+}
+
+func NewSecurityDigestService(db *gorm.DB, emailService EmailService, auditService AuditService) SecurityDigestService {
+	return &securityDigestService{
+		Log:          utils.Log,
+		DB:           db,
+		EmailService: emailService,
+		AuditService: auditService,
+	}
+}
+
+// Build reads the digest figures from the audit log
+func (s *securityDigestService) Build(ctx context.Context, from, to time.Time) (*SecurityDigest, error) {
+	digest := &SecurityDigest{From: from, To: to}
+
+	entries := func() *gorm.DB {
+		return s.DB.WithContext(ctx).
+			Table("audit_logs").
+			Where("audit_logs.created_at >= ? AND audit_logs.created_at < ?", from, to)
+	}
+	withEmail := func() *gorm.DB {
+		return entries().Joins("LEFT JOIN users ON users.id = audit_logs.user_id")
+	}
+
+	if err := entries().Where("action = ?", model.AuditActionLoginFailed).Count(&digest.FailedLogins).Error; err != nil {
+		s.Log.Errorf("Failed to count failed logins for digest: %+v", err)
+		return nil, err
+	}
+
+	if err := entries().
+		Where("action = ? AND metadata->>'decision' = ?", model.AuditActionLoginAssessed, "block").
+		Count(&digest.BlockedLogins).Error; err != nil {
+		s.Log.Errorf("Failed to count blocked logins for digest: %+v", err)
+		return nil, err
+	}
+
+	if err := withEmail().
+		Select("COALESCE(users.email, audit_logs.user_id::text) AS email, COUNT(*) AS count").
+		Where("action = ?", model.AuditActionLoginFailed).
+		Group("COALESCE(users.email, audit_logs.user_id::text)").
+		Order("count DESC").
+		Limit(digestTopAccounts).
+		Scan(&digest.TopFailedAccounts).Error; err != nil {
+		s.Log.Errorf("Failed to list targeted accounts for digest: %+v", err)
+		return nil, err
+	}
+
+	roleChanges := "COALESCE(users.email, audit_logs.user_id::text) AS email, " +
+		"audit_logs.metadata->>'from' AS \"from\", audit_logs.metadata->>'to' AS \"to\", audit_logs.created_at"
+
+	if err := withEmail().
+		Select(roleChanges).
+		Where("action = ?", model.AuditActionRoleChanged).
+		Order("audit_logs.created_at").
+		Scan(&digest.RoleChanges).Error; err != nil {
+		s.Log.Errorf("Failed to list role changes for digest: %+v", err)
+		return nil, err
+	}
+
+	// New admins are either created as admin or promoted to it
+	if err := withEmail().
+		Select(roleChanges).
+		Where("(action = ? AND audit_logs.metadata->>'to' = ?) OR (action = ? AND audit_logs.metadata->>'role' = ?)",
+			model.AuditActionRoleChanged, "admin", model.AuditActionUserCreated, "admin").
+		Order("audit_logs.created_at").
+		Scan(&digest.NewAdmins).Error; err != nil {
+		s.Log.Errorf("Failed to list new admins for digest: %+v", err)
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// Send emails the digest to the configured recipients, or every admin when none are configured
+func (s *securityDigestService) Send(ctx context.Context, from, to time.Time) error {
+	digest, err := s.Build(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	recipients, err := s.recipients(ctx)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return errors.New("no security digest recipients")
+	}
+
+	// Each recipient reads the dates in their own timezone
+	var sent int
+	for _, recipient := range recipients {
+		body, err := RenderSecurityDigest(digest, recipient.location)
+		if err != nil {
+			s.Log.Errorf("Failed to render security digest: %+v", err)
+			return err
+		}
+
+		subject := fmt.Sprintf("Security digest %s - %s",
+			from.In(recipient.location).Format("2 Jan"), to.In(recipient.location).Format("2 Jan 2006"))
+
+		if err := s.EmailService.SendEmail(ctx, recipient.email, subject, body); err != nil {
+			s.Log.Warnf("Failed to send security digest to %s: %v", recipient.email, err)
+			continue
+		}
+		sent++
+	}
+
+	if sent == 0 {
+		return errors.New("security digest could not be delivered to any recipient")
+	}
+
+	s.AuditService.RecordSystem(ctx, nil, model.AuditActionDigestSent, map[string]any{
+		"from":       from,
+		"to":         to,
+		"recipients": sent,
+	})
+
+	return nil
+}
+
+// SentSince reports whether a digest was already sent at or after since, so a leadership
+// change around the scheduled time does not send it twice
+func (s *securityDigestService) SentSince(ctx context.Context, since time.Time) (bool, error) {
+	var count int64
+
+	err := s.DB.WithContext(ctx).
+		Model(new(model.AuditLog)).
+		Where("action = ? AND created_at >= ?", model.AuditActionDigestSent, since).
+		Count(&count).Error
+
+	if err != nil {
+		s.Log.Errorf("Failed to check last security digest: %+v", err)
+	}
+
+	return count > 0, err
+}
+
+// digestRecipient is an address the digest is sent to and the timezone its dates are shown in
+type digestRecipient struct {
+	email    string
+	location *time.Location
+}
+
+// recipients returns the configured recipients in the default timezone, or every admin in their own
+func (s *securityDigestService) recipients(ctx context.Context) ([]digestRecipient, error) {
+	var recipients []digestRecipient
+	if len(config.SecurityDigest.Recipients) > 0 {
+		for _, email := range config.SecurityDigest.Recipients {
+			recipients = append(recipients, digestRecipient{email: email, location: config.DefaultTimezone})
+		}
+		return recipients, nil
+	}
+
+	var admins []model.User
+	err := s.DB.WithContext(ctx).
+		Select("email", "timezone").
+		Where("role = ?", "admin").
+		Find(&admins).Error
+
+	if err != nil {
+		s.Log.Errorf("Failed to list admins for security digest: %+v", err)
+		return nil, err
+	}
+
+	for i := range admins {
+		recipients = append(recipients, digestRecipient{email: admins[i].Email, location: userLocation(&admins[i])})
+	}
+
+	return recipients, nil
+}
+
+// securityDigestTemplate is cloned for each render to show dates in the recipient's timezone
+var securityDigestTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.UTC().Format(digestDateLayout) },
+}).Parse(strings.TrimSpace(`
+Security digest for {{date .From}} to {{date .To}}
+
+Failed logins: {{.FailedLogins}}
+Blocked logins: {{.BlockedLogins}}
+{{- if .TopFailedAccounts}}
+
+Most targeted accounts:
+{{- range .TopFailedAccounts}}
+  - {{.Email}}: {{.Count}}
+{{- end}}
+{{- end}}
+
+Role changes: {{len .RoleChanges}}
+{{- range .RoleChanges}}
+  - {{.Email}}: {{.From}} -> {{.To}} ({{date .CreatedAt}})
+{{- end}}
+
+New admin accounts: {{len .NewAdmins}}
+{{- range .NewAdmins}}
+  - {{.Email}}{{if .From}} (promoted from {{.From}}){{else}} (created){{end}} ({{date .CreatedAt}})
+{{- end}}
+`)))
+
+// digestDateLayout names the zone so readers can tell which timezone a date is in
+const digestDateLayout = "2006-01-02 15:04 MST"
+
+// RenderSecurityDigest formats the digest as the plain-text email body, with dates in location
+func RenderSecurityDigest(digest *SecurityDigest, location *time.Location) (string, error) {
+	tmpl, err := securityDigestTemplate.Clone()
+	if err != nil {
+		return "", err
+	}
+	tmpl.Funcs(template.FuncMap{
+		"date": func(t time.Time) string { return t.In(location).Format(digestDateLayout) },
+	})
+
+	var body strings.Builder
+	if err := tmpl.Execute(&body, digest); err != nil {
+		return "", err
+	}
+	return body.String() + "\n", nil
+}
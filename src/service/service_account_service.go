@@ -0,0 +1,210 @@
+package service
+
+import (
+	"app/src/clock"
+	"app/src/config"
+	"app/src/listener"
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// serviceAccountUsageInterval throttles last_used_at writes for busy service accounts
+const serviceAccountUsageInterval = time.Minute
+
+type ServiceAccountService interface {
+	CreateServiceAccount(c *fiber.Ctx, req *validation.CreateServiceAccount) (*model.ServiceAccount, error)
+	ListServiceAccounts(c *fiber.Ctx) ([]model.ServiceAccount, error)
+	GetServiceAccount(c *fiber.Ctx, id string) (*model.ServiceAccount, error)
+	DeleteServiceAccount(c *fiber.Ctx, id string) error
+	AuthenticateCertificate(c *fiber.Ctx, cert *x509.Certificate) (*model.ServiceAccount, error)
+}
+
+type serviceAccountService struct {
+	Log          *logrus.Logger
+	DB           *gorm.DB
+	Validate     *validator.Validate
+	UserService  UserService
+	AuditService AuditService
+	Clock        clock.Clock
+}
+
+func NewServiceAccountService(
+	db *gorm.DB, validate *validator.Validate, userService UserService, auditService AuditService, clk clock.Clock,
+) ServiceAccountService {
+	return &serviceAccountService{
+		Log:          utils.Log,
+		DB:           db,
+		Validate:     validate,
+		UserService:  userService,
+		AuditService: auditService,
+		Clock:        clock.OrSystem(clk),
+	}
+}
+
+// RightsOf returns the rights a user holds: a service account's own, or those of the user's role
+func RightsOf(user *model.User) []string {
+	if user.IsService() {
+		if user.ServiceAccount == nil {
+			return []string{}
+		}
+		return user.ServiceAccount.RightList()
+	}
+	return config.RightsForRole(user.Role)
+}
+
+// CreateServiceAccount creates a service user with a certificate identity and a subset of the
+// caller's rights. It has no password and its email address is never delivered to.
+func (s *serviceAccountService) CreateServiceAccount(
+	c *fiber.Ctx, req *validation.CreateServiceAccount,
+) (*model.ServiceAccount, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	account := &model.ServiceAccount{
+		Name:     req.Name,
+		Identity: req.Identity,
+		Rights:   strings.Join(req.Rights, ","),
+		User: &model.User{
+			Name:          req.Name,
+			Type:          model.UserTypeService,
+			VerifiedEmail: true,
+			IsActive:      true,
+		},
+	}
+
+	actor, _ := c.Locals("user").(*model.User)
+	var rights []string
+	if actor != nil {
+		rights = RightsOf(actor)
+		account.CreatedBy = &actor.ID
+	}
+	for _, right := range req.Rights {
+		if !containsString(rights, right) {
+			return nil, fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("Cannot grant right '%s'", right))
+		}
+	}
+
+	// The user is created first, in the same transaction, through the belongs-to association
+	err := s.DB.WithContext(c.UserContext()).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(account).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, fiber.NewError(fiber.StatusConflict, "Service account name or identity already exists")
+	}
+	if err != nil {
+		s.Log.Errorf("Failed to create service account: %+v", err)
+		return nil, err
+	}
+
+	s.audit(c, model.AuditActionServiceAccountCreated, account)
+
+	return account, nil
+}
+
+func (s *serviceAccountService) ListServiceAccounts(c *fiber.Ctx) ([]model.ServiceAccount, error) {
+	var accounts []model.ServiceAccount
+	if err := s.DB.WithContext(c.UserContext()).Preload("User").Order("created_at").Find(&accounts).Error; err != nil {
+		s.Log.Errorf("Failed to list service accounts: %+v", err)
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// GetServiceAccount returns a service account with its user loaded and attached to it
+func (s *serviceAccountService) GetServiceAccount(c *fiber.Ctx, id string) (*model.ServiceAccount, error) {
+	account := new(model.ServiceAccount)
+	result := s.DB.WithContext(c.UserContext()).Preload("User").Where("id = ?", id).First(account)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Service account not found")
+	}
+	if result.Error != nil {
+		s.Log.Errorf("Failed to get service account: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	account.User.ServiceAccount = account
+	return account, nil
+}
+
+// DeleteServiceAccount deletes the service user, with its account and API tokens; its certificate
+// and tokens are rejected from then on
+func (s *serviceAccountService) DeleteServiceAccount(c *fiber.Ctx, id string) error {
+	account, err := s.GetServiceAccount(c, id)
+	if err != nil {
+		return err
+	}
+
+	// Deleting the user also revokes it on every instance and cascades to the account
+	if err := s.UserService.DeleteUser(c, account.UserID.String()); err != nil {
+		return err
+	}
+
+	s.audit(c, model.AuditActionServiceAccountDeleted, account)
+
+	return nil
+}
+
+// AuthenticateCertificate finds the service account of a verified client certificate, matching the
+// most specific identity the certificate names. Its User is loaded, with the account attached.
+func (s *serviceAccountService) AuthenticateCertificate(
+	c *fiber.Ctx, cert *x509.Certificate,
+) (*model.ServiceAccount, error) {
+	identities := listener.CertificateIdentities(cert)
+	if len(identities) == 0 {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var accounts []model.ServiceAccount
+	if err := s.DB.WithContext(c.UserContext()).Preload("User").Where("identity IN ?", identities).Find(&accounts).Error; err != nil {
+		s.Log.Errorf("Failed to look up service account: %+v", err)
+		return nil, err
+	}
+
+	byIdentity := make(map[string]*model.ServiceAccount, len(accounts))
+	for i := range accounts {
+		byIdentity[accounts[i].Identity] = &accounts[i]
+	}
+
+	var account *model.ServiceAccount
+	for _, identity := range identities {
+		if account = byIdentity[identity]; account != nil {
+			break
+		}
+	}
+	if account == nil || account.User == nil {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+	account.User.ServiceAccount = account
+
+	// Record usage at most once per interval to keep busy services from writing on every request
+	now := s.Clock.Now()
+	if account.LastUsedAt == nil || now.Sub(*account.LastUsedAt) > serviceAccountUsageInterval {
+		if err := s.DB.WithContext(c.UserContext()).Model(account).Update("last_used_at", now).Error; err != nil {
+			s.Log.Warnf("Failed to record service account usage: %v", err)
+		}
+	}
+
+	return account, nil
+}
+
+func (s *serviceAccountService) audit(c *fiber.Ctx, action string, account *model.ServiceAccount) {
+	metadata := map[string]any{
+		"service_account_id": account.ID, "name": account.Name, "identity": account.Identity, "rights": account.Rights,
+	}
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+	s.AuditService.Record(c, &account.UserID, action, metadata)
+}
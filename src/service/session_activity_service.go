@@ -0,0 +1,208 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type SessionActivityService interface {
+	Touch(ctx context.Context, sessionID string)
+	Flush(ctx context.Context) (int, error)
+	ReapIdle(ctx context.Context) (int64, error)
+	GetActivity(c *fiber.Ctx) (*response.SessionActivity, error)
+}
+
+type sessionActivityService struct {
+	Log    *logrus.Logger
+	DB     *gorm.DB
+	Store  cache.Store
+	Config config.SessionActivityConfig
+	Clock  clock.Clock
+
+	mu          sync.Mutex
+	written     map[string]struct{}
+	windowStart time.Time
+}
+
+// NewSessionActivityService records when sessions were last used. Writes are throttled per session and
+// buffered in the cache store until Flush copies them to the database; without a store they go
+// straight to the database. Idle timeouts are timed with clk, the wall clock if nil.
+func NewSessionActivityService(
+	db *gorm.DB, store cache.Store, cfg config.SessionActivityConfig, clk clock.Clock,
+) SessionActivityService {
+	return &sessionActivityService{
+		Log:     utils.Log,
+		DB:      db,
+		Store:   store,
+		Config:  cfg,
+		Clock:   clock.OrSystem(clk),
+		written: make(map[string]struct{}),
+	}
+}
+
+// Touch marks the session as active now, at most once per WriteInterval on this instance
+func (s *sessionActivityService) Touch(ctx context.Context, sessionID string) {
+	if !s.Config.Enabled || sessionID == "" {
+		return
+	}
+
+	now := s.Clock.Now()
+	if !s.due(sessionID, now) {
+		return
+	}
+
+	if s.Store != nil && cache.IsStoreAvailable(s.Store) {
+		value := []byte(strconv.FormatInt(now.Unix(), 10))
+		// Keep the entry until at least one flush has seen it
+		err := s.Store.Set(cache.GetSessionActivityKey(sessionID), value, 2*s.Config.FlushInterval)
+		if err == nil {
+			return
+		}
+		s.Log.Warnf("Failed to buffer session activity, writing it to the database: %v", err)
+	}
+
+	if err := s.record(ctx, map[string]time.Time{sessionID: now}); err != nil {
+		s.Log.Warnf("Failed to record session activity: %v", err)
+	}
+}
+
+// Flush copies the buffered activity to the database and returns the number of sessions it covered.
+// Entries are left to expire rather than deleted, so a write racing the flush is not lost.
+func (s *sessionActivityService) Flush(ctx context.Context) (int, error) {
+	if s.Store == nil || !cache.IsStoreAvailable(s.Store) {
+		return 0, nil
+	}
+
+	keys, err := s.Store.Keys(ctx, cache.SessionActivityKeyPrefix+"*")
+	if err != nil {
+		return 0, err
+	}
+
+	activity := make(map[string]time.Time, len(keys))
+	for _, key := range keys {
+		data, err := s.Store.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+
+		unix, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		activity[strings.TrimPrefix(key, cache.SessionActivityKeyPrefix)] = time.Unix(unix, 0).UTC()
+	}
+
+	return len(activity), s.record(ctx, activity)
+}
+
+// ReapIdle ends sessions that have not been used for IdleTimeout by deleting their refresh tokens.
+// Access tokens already issued to them stay valid until they expire.
+func (s *sessionActivityService) ReapIdle(ctx context.Context) (int64, error) {
+	if s.Config.IdleTimeout <= 0 {
+		return 0, nil
+	}
+
+	cutoff := s.Clock.Now().Add(-s.Config.IdleTimeout)
+	result := s.DB.WithContext(ctx).
+		Where("type = ? AND COALESCE(last_active_at, created_at) < ?", config.TokenTypeRefresh, cutoff).
+		Delete(new(model.Token))
+
+	if result.Error != nil {
+		s.Log.Errorf("Failed to reap idle sessions: %+v", result.Error)
+	}
+
+	return result.RowsAffected, result.Error
+}
+
+// GetActivity counts the sessions and users active within each of the SessionActivityWindows.
+// Buffered activity is flushed first so the counts include every instance's latest writes.
+func (s *sessionActivityService) GetActivity(c *fiber.Ctx) (*response.SessionActivity, error) {
+	if _, err := s.Flush(c.UserContext()); err != nil {
+		s.Log.Warnf("Failed to flush session activity, counts may lag: %v", err)
+	}
+
+	now := s.Clock.Now()
+	activity := &response.SessionActivity{
+		Windows:     make([]response.SessionActivityWindow, 0, len(config.SessionActivityWindows)),
+		IdleTimeout: int(s.Config.IdleTimeout.Minutes()),
+	}
+
+	for _, minutes := range config.SessionActivityWindows {
+		window := response.SessionActivityWindow{Minutes: minutes}
+
+		err := s.DB.WithContext(c.UserContext()).
+			Model(new(model.Token)).
+			Select("COUNT(DISTINCT session_id) AS sessions, COUNT(DISTINCT user_id) AS users").
+			Where("type = ? AND expires > ? AND last_active_at >= ?",
+				config.TokenTypeRefresh, now, now.Add(-time.Duration(minutes)*time.Minute)).
+			Scan(&window).Error
+
+		if err != nil {
+			s.Log.Errorf("Failed to count active sessions: %+v", err)
+			return nil, err
+		}
+
+		activity.Windows = append(activity.Windows, window)
+	}
+
+	return activity, nil
+}
+
+// due reports whether the session's activity should be written now, allowing one write per session
+// per WriteInterval
+func (s *sessionActivityService) due(sessionID string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Start over every interval so the set only holds recently seen sessions
+	if now.Sub(s.windowStart) >= s.Config.WriteInterval {
+		s.written = make(map[string]struct{})
+		s.windowStart = now
+	}
+
+	if _, ok := s.written[sessionID]; ok {
+		return false
+	}
+	s.written[sessionID] = struct{}{}
+
+	return true
+}
+
+// record moves last_active_at forward for each session; older timestamps never overwrite newer ones
+func (s *sessionActivityService) record(ctx context.Context, activity map[string]time.Time) error {
+	if len(activity) == 0 {
+		return nil
+	}
+
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for sessionID, at := range activity {
+			if _, err := uuid.Parse(sessionID); err != nil {
+				continue
+			}
+
+			err := tx.Model(new(model.Token)).
+				Where("session_id = ? AND type = ?", sessionID, config.TokenTypeRefresh).
+				Where("last_active_at IS NULL OR last_active_at < ?", at).
+				Update("last_active_at", at).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
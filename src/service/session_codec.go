@@ -0,0 +1,83 @@
+package service
+
+import (
+	"app/src/config"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// sessionSchemaVersion is stored with every cached session. Bump it whenever SessionData changes in
+// a way older instances would misread (a renamed field, a changed meaning); sessions of any other
+// version then read as misses and are reloaded from the database instead of being trusted.
+const sessionSchemaVersion = 1
+
+// maxSessionSize caps how much a compressed session may inflate to
+const maxSessionSize = 1 << 20
+
+// sessionEnvelope wraps cached session data with its schema version. The data is kept as plain
+// JSON, or gzipped once it reaches config.SessionCompressMin bytes.
+type sessionEnvelope struct {
+	Version int             `json:"v"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Gzip    []byte          `json:"gzip,omitempty"`
+}
+
+// encodeSession serializes session data into a versioned envelope
+func encodeSession(sessionData *SessionData) ([]byte, error) {
+	data, err := json.Marshal(sessionData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session data: %w", err)
+	}
+
+	envelope := sessionEnvelope{Version: sessionSchemaVersion, Data: data}
+	if config.SessionCompressMin > 0 && len(data) >= config.SessionCompressMin {
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		if _, err := writer.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to compress session data: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress session data: %w", err)
+		}
+		envelope = sessionEnvelope{Version: sessionSchemaVersion, Gzip: compressed.Bytes()}
+	}
+
+	return json.Marshal(envelope)
+}
+
+// decodeSession reads cached session data. Anything that cannot be trusted - another schema
+// version, a corrupt entry - is ErrCacheMiss, so the caller reloads the user instead of failing.
+// Sessions cached before envelopes existed are plain SessionData and are still read as such.
+func decodeSession(payload []byte) (*SessionData, error) {
+	var envelope sessionEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, ErrCacheMiss
+	}
+
+	data := envelope.Data
+	switch {
+	case envelope.Version == 0:
+		data = payload
+	case envelope.Version != sessionSchemaVersion:
+		return nil, ErrCacheMiss
+	case envelope.Gzip != nil:
+		reader, err := gzip.NewReader(bytes.NewReader(envelope.Gzip))
+		if err != nil {
+			return nil, ErrCacheMiss
+		}
+		data, err = io.ReadAll(io.LimitReader(reader, maxSessionSize))
+		if err != nil {
+			return nil, ErrCacheMiss
+		}
+	}
+
+	sessionData := new(SessionData)
+	if err := json.Unmarshal(data, sessionData); err != nil || sessionData.ID == "" {
+		return nil, ErrCacheMiss
+	}
+
+	return sessionData, nil
+}
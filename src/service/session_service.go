@@ -1,29 +1,33 @@
 package service
 
 import (
+	"app/src/cache"
 	"app/src/config"
 	"app/src/model"
-	"app/src/redis"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
-	"encoding/json"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
-
-	goredis "github.com/redis/go-redis/v9"
 )
 
 // SessionData represents cached user session data
 type SessionData struct {
-	ID            string `json:"id"`
-	Name          string `json:"name"`
-	Email         string `json:"email"`
-	Role          string `json:"role"`
-	VerifiedEmail bool   `json:"verified_email"`
-	SessionID     string `json:"session_id"` // For SESS-07 privilege elevation tracking
-	CreatedAt     int64  `json:"created_at"` // For cache freshness tracking
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Username      *string  `json:"username,omitempty"`
+	Email         string   `json:"email"`
+	Role          string   `json:"role"`
+	VerifiedEmail bool     `json:"verified_email"`
+	Suspended     bool     `json:"suspended,omitempty"` // inverse of IsActive so entries cached before it decode as active
+	Tags          []string `json:"tags,omitempty"`
+	Plan          string   `json:"plan,omitempty"`
+	Timezone      *string  `json:"timezone,omitempty"`
+	SessionID     string   `json:"session_id"` // For SESS-07 privilege elevation tracking
+	CreatedAt     int64    `json:"created_at"` // For cache freshness tracking
 }
 
 // ErrCacheMiss indicates the requested session is not in the cache
@@ -34,137 +38,197 @@ type SessionService interface {
 	CacheUserSession(ctx context.Context, userID string, user *model.User) error
 	GetUserSession(ctx context.Context, userID string) (*SessionData, error)
 	InvalidateSession(ctx context.Context, userID string) error
+	// CacheUserSessions caches the sessions of many users in one round trip
+	CacheUserSessions(ctx context.Context, users []model.User) error
+	// GetUserSessions reads many sessions in one round trip, keyed by user ID; misses are left out
+	GetUserSessions(ctx context.Context, userIDs []string) (map[string]*SessionData, error)
+	// InvalidateSessions removes many sessions in one round trip
+	InvalidateSessions(ctx context.Context, userIDs ...string) error
 	GenerateSessionID() (string, error)
+	// MarkEvicted remembers the refresh tokens of sessions ended by the session limit until they expire
+	MarkEvicted(ctx context.Context, sessions []model.Token) error
+	IsEvicted(ctx context.Context, refreshToken string) bool
 }
 
 // sessionService implements SessionService interface
 type sessionService struct {
-	redisClient *redis.RedisClient
+	store cache.Store
 }
 
-// NewSessionService creates a new session service instance
-func NewSessionService(redisClient *redis.RedisClient) SessionService {
+// NewSessionService creates a new session service instance backed by the configured cache store
+func NewSessionService(store cache.Store) SessionService {
+	// Invalidations issued while Redis was down were lost, so cached sessions may be stale on recovery
+	cache.SubscribeAvailability(store, func(available bool) {
+		if available {
+			go cache.PurgePrefix(store, cache.SessionKeyPrefix)
+		}
+	})
+
 	return &sessionService{
-		redisClient: redisClient,
+		store: store,
+	}
+}
+
+// CacheUserSession stores user session data in the cache store
+func (s *sessionService) CacheUserSession(_ context.Context, userID string, user *model.User) error {
+	serialized, err := s.newSession(user)
+	if err != nil {
+		return err
 	}
+
+	ttl := time.Duration(config.SessionCacheTTL) * time.Minute
+	if err := s.store.Set(cache.GetSessionKey(userID), serialized, ttl); err != nil {
+		if errors.Is(err, cache.ErrStoreUnavailable) {
+			// Graceful degradation - return nil instead of error (SESS-05)
+			return nil
+		}
+		return fmt.Errorf("failed to cache session: %w", err)
+	}
+
+	return nil
 }
 
-// CacheUserSession stores user session data in Redis cache
-func (s *sessionService) CacheUserSession(ctx context.Context, userID string, user *model.User) error {
-	// Check if Redis is available
-	if !redis.IsAvailable() {
-		// Graceful degradation - return nil instead of error (SESS-05)
-		return nil
+// CacheUserSessions stores the sessions of all users with one pipelined write
+func (s *sessionService) CacheUserSessions(ctx context.Context, users []model.User) error {
+	ttl := time.Duration(config.SessionCacheTTL) * time.Minute
+	entries := make([]cache.Entry, 0, len(users))
+	for i := range users {
+		serialized, err := s.newSession(&users[i])
+		if err != nil {
+			return err
+		}
+		entries = append(entries, cache.Entry{Key: cache.GetSessionKey(users[i].ID.String()), Value: serialized, TTL: ttl})
 	}
 
+	if err := cache.SetMany(ctx, s.store, entries); err != nil {
+		if errors.Is(err, cache.ErrStoreUnavailable) {
+			return nil
+		}
+		return fmt.Errorf("failed to cache sessions: %w", err)
+	}
+
+	return nil
+}
+
+// newSession serializes the session data of user with a fresh session ID
+func (s *sessionService) newSession(user *model.User) ([]byte, error) {
 	// Generate secure session ID
 	sessionID, err := s.GenerateSessionID()
 	if err != nil {
-		return fmt.Errorf("failed to generate session ID: %w", err)
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
 	}
 
 	// Create session data from user model
 	sessionData := &SessionData{
 		ID:            user.ID.String(),
 		Name:          user.Name,
+		Username:      user.Username,
 		Email:         user.Email,
 		Role:          user.Role,
 		VerifiedEmail: user.VerifiedEmail,
+		Suspended:     !user.IsActive,
+		Tags:          user.TagNames(),
+		Plan:          user.Plan,
+		Timezone:      user.Timezone,
 		SessionID:     sessionID,
 		CreatedAt:     time.Now().Unix(),
 	}
 
-	// Serialize to JSON
-	serialized, err := json.Marshal(sessionData)
-	if err != nil {
-		return fmt.Errorf("failed to marshal session data: %w", err)
-	}
-
-	// Execute through circuit breaker
-	result, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
-		key := fmt.Sprintf("session:user:%s", userID)
-		ttl := time.Duration(config.SessionCacheTTL) * time.Minute
-		return nil, s.redisClient.GetClient().Set(ctx, key, serialized, ttl).Err()
-	})
+	return encodeSession(sessionData)
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to cache session: %w", err)
+// GetUserSession retrieves user session data from the cache store
+func (s *sessionService) GetUserSession(_ context.Context, userID string) (*SessionData, error) {
+	data, err := s.store.Get(cache.GetSessionKey(userID))
+	if err != nil || data == nil {
+		// Store errors are treated as a miss to trigger DB fallback (graceful degradation, SESS-05)
+		return nil, ErrCacheMiss
 	}
 
-	// Result is nil for Set operations
-	_ = result
-
-	return nil
+	// Entries of another schema version or that fail to decode are misses too, so the user is reloaded
+	return decodeSession(data)
 }
 
-// GetUserSession retrieves user session data from Redis cache
-func (s *sessionService) GetUserSession(ctx context.Context, userID string) (*SessionData, error) {
-	// Check if Redis is available
-	if !redis.IsAvailable() {
-		// Return ErrCacheMiss to trigger DB fallback (graceful degradation, SESS-05)
-		return nil, ErrCacheMiss
+// GetUserSessions reads the sessions of all users with one MGET; misses and entries that fail to
+// decode are left out
+func (s *sessionService) GetUserSessions(ctx context.Context, userIDs []string) (map[string]*SessionData, error) {
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = cache.GetSessionKey(userID)
 	}
 
-	// Execute through circuit breaker
-	result, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
-		key := fmt.Sprintf("session:user:%s", userID)
-		data, err := s.redisClient.GetClient().Get(ctx, key).Bytes()
-		if err != nil {
-			if errors.Is(err, goredis.Nil) {
-				// Cache miss - return ErrCacheMiss
-				return nil, ErrCacheMiss
-			}
-			// Redis error - treat as unavailable (graceful degradation)
-			return nil, ErrCacheMiss
-		}
-		return data, nil
-	})
-
+	sessions := make(map[string]*SessionData, len(userIDs))
+	values, err := cache.GetMany(ctx, s.store, keys)
 	if err != nil {
-		if errors.Is(err, ErrCacheMiss) {
-			return nil, ErrCacheMiss
-		}
-		// Unexpected error - treat as cache miss for graceful degradation
-		return nil, ErrCacheMiss
+		// Like a single lookup, an unavailable store reads as all misses
+		return sessions, nil
 	}
 
-	// Extract bytes from result
-	data, ok := result.([]byte)
-	if !ok {
-		return nil, ErrCacheMiss
+	for i, data := range values {
+		if data == nil {
+			continue
+		}
+		if sessionData, err := decodeSession(data); err == nil {
+			sessions[userIDs[i]] = sessionData
+		}
 	}
 
-	// Unmarshal JSON to SessionData
-	var sessionData SessionData
-	if err := json.Unmarshal(data, &sessionData); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal session data: %w", err)
-	}
+	return sessions, nil
+}
 
-	return &sessionData, nil
+// InvalidateSession removes user session data from the cache store
+func (s *sessionService) InvalidateSession(_ context.Context, userID string) error {
+	// Errors are swallowed - the session expires with its TTL (graceful degradation)
+	_ = s.store.Delete(cache.GetSessionKey(userID))
+	return nil
 }
 
-// InvalidateSession removes user session data from Redis cache
-func (s *sessionService) InvalidateSession(ctx context.Context, userID string) error {
-	// Check if Redis is available
-	if !redis.IsAvailable() {
-		// Graceful degradation - return nil instead of error
-		return nil
+// InvalidateSessions removes the sessions of all users with a single DEL
+func (s *sessionService) InvalidateSessions(ctx context.Context, userIDs ...string) error {
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = cache.GetSessionKey(userID)
 	}
 
-	// Execute through circuit breaker
-	_, err := s.redisClient.ExecuteWithCircuitBreaker(ctx, func() (interface{}, error) {
-		key := fmt.Sprintf("session:user:%s", userID)
-		return nil, s.redisClient.GetClient().Del(ctx, key).Err()
-	})
+	// Errors are swallowed - the sessions expire with their TTL (graceful degradation)
+	_ = s.store.DeleteKeys(ctx, keys...)
+	return nil
+}
 
-	if err != nil {
-		// Log error but don't fail the operation (graceful degradation)
-		return nil
+// MarkEvicted remembers that refresh tokens were ended by the session limit, so the devices
+// can be told why on their next refresh instead of getting a bare 401
+func (s *sessionService) MarkEvicted(ctx context.Context, sessions []model.Token) error {
+	entries := make([]cache.Entry, 0, len(sessions))
+	for _, session := range sessions {
+		ttl := time.Until(session.Expires)
+		if ttl <= 0 {
+			continue
+		}
+		key := cache.GetEvictedSessionKey(hashSessionToken(session.Token))
+		entries = append(entries, cache.Entry{Key: key, Value: []byte("1"), TTL: ttl})
 	}
 
+	if err := cache.SetMany(ctx, s.store, entries); err != nil {
+		if errors.Is(err, cache.ErrStoreUnavailable) {
+			return nil
+		}
+		return fmt.Errorf("failed to mark sessions evicted: %w", err)
+	}
 	return nil
 }
 
+// IsEvicted reports whether the refresh token was ended by the session limit
+func (s *sessionService) IsEvicted(_ context.Context, refreshToken string) bool {
+	data, err := s.store.Get(cache.GetEvictedSessionKey(hashSessionToken(refreshToken)))
+	return err == nil && data != nil
+}
+
+// hashSessionToken keeps raw refresh tokens out of the cache keyspace
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // GenerateSessionID generates a cryptographically secure session ID
 func (s *sessionService) GenerateSessionID() (string, error) {
 	// Generate 32 random bytes (256 bits of entropy)
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/utils"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WarmSessionCache caches the sessions of up to limit users most recently active on a refresh token,
+// so the first requests after a start with an empty cache don't all fall through to the database.
+// Sessions already cached are kept; the rest are written in one pipeline. It returns how many were cached.
+// Activity is timed with clk, the wall clock if nil.
+func WarmSessionCache(
+	ctx context.Context, db *gorm.DB, sessions SessionService, limit int, clk clock.Clock,
+) (int, error) {
+	now := clock.OrSystem(clk).Now()
+	active := db.Model(new(model.Token)).
+		Select("user_id").
+		Where("type = ? AND expires > ? AND last_active_at >= ?",
+			config.TokenTypeRefresh, now, now.Add(-time.Duration(config.SessionCacheTTL)*time.Minute)).
+		Group("user_id").
+		Order("MAX(last_active_at) DESC").
+		Limit(limit)
+
+	var users []model.User
+	err := db.WithContext(ctx).Preload("Tags").Where("id IN (?)", active).Find(&users).Error
+	if err != nil {
+		utils.Log.Errorf("Failed to load users for session warm-up: %+v", err)
+		return 0, err
+	}
+
+	ids := make([]string, len(users))
+	for i := range users {
+		ids[i] = users[i].ID.String()
+	}
+
+	cached, err := sessions.GetUserSessions(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	missing := make([]model.User, 0, len(users))
+	for i := range users {
+		if _, ok := cached[ids[i]]; !ok {
+			missing = append(missing, users[i])
+		}
+	}
+
+	if err := sessions.CacheUserSessions(ctx, missing); err != nil {
+		utils.Log.Warnf("Failed to warm session cache: %v", err)
+		return 0, err
+	}
+
+	return len(missing), nil
+}
@@ -1,11 +1,23 @@
 package service
 
 import (
+	"app/src/accesstoken"
+	"app/src/cache"
+	"app/src/clock"
 	"app/src/config"
 	"app/src/model"
 	res "app/src/response"
+	"app/src/revocation"
 	"app/src/utils"
+	"app/src/utils/id"
 	"app/src/validation"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -14,17 +26,34 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrTokenConsumed is returned when a single-use token is presented again after it was spent
+var ErrTokenConsumed = errors.New("token already consumed")
+
+// ErrRefreshTokenReplayed rejects a refresh token presented without the device binding it was issued
+// with, which suggests it was stolen
+var ErrRefreshTokenReplayed = fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+
 type TokenService interface {
 	GenerateToken(userID string, expires time.Time, tokenType string) (string, error)
+	GenerateAccessToken(user *model.User, expires time.Time) (string, error)
 	SaveToken(c *fiber.Ctx, token, userID, tokenType string, expires time.Time) error
 	DeleteToken(c *fiber.Ctx, tokenType string, userID string) error
 	DeleteAllToken(c *fiber.Ctx, userID string) error
+	EndSession(c *fiber.Ctx, token *model.Token) error
 	GetTokenByUserID(c *fiber.Ctx, tokenStr string) (*model.Token, error)
 	GenerateAuthTokens(c *fiber.Ctx, user *model.User) (*res.Tokens, error)
+	RotateAuthTokens(c *fiber.Ctx, user *model.User, previous *model.Token) (*res.Tokens, error)
+	// CheckBinding verifies that a refresh token comes from the device it was issued to. On a
+	// mismatch every token of its session is deleted and ErrRefreshTokenReplayed returned.
+	CheckBinding(c *fiber.Ctx, token *model.Token) error
 	GenerateResetPasswordToken(c *fiber.Ctx, req *validation.ForgotPassword) (string, error)
 	GenerateVerifyEmailToken(c *fiber.Ctx, user *model.User) (*string, error)
+	GenerateConfirmLoginToken(c *fiber.Ctx, user *model.User) (string, error)
+	ConsumeToken(c *fiber.Ctx, tokenStr, tokenType string) (*model.Token, error)
+	DeleteExpiredTokens(ctx context.Context) (int64, error)
 }
 
 type tokenService struct {
@@ -33,25 +62,91 @@ type tokenService struct {
 	Validate       *validator.Validate
 	UserService    UserService
 	SessionService SessionService
+	Revocations    *revocation.Bus
+	Clock          clock.Clock
 }
 
-func NewTokenService(db *gorm.DB, validate *validator.Validate, userService UserService, sessionService SessionService) TokenService {
+// NewTokenService issues and stores tokens, timing their expiry with clk (the wall clock if nil).
+// Sessions ended by the session limit are revoked through revocations.
+func NewTokenService(
+	db *gorm.DB, validate *validator.Validate, userService UserService, sessionService SessionService,
+	revocations *revocation.Bus, clk clock.Clock,
+) TokenService {
 	return &tokenService{
 		Log:            utils.Log,
 		DB:             db,
 		Validate:       validate,
 		UserService:    userService,
 		SessionService: sessionService,
+		Revocations:    revocations,
+		Clock:          clock.OrSystem(clk),
 	}
 }
 
 func (s *tokenService) GenerateToken(userID string, expires time.Time, tokenType string) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":  userID,
-		"iat":  time.Now().Unix(),
+		"iat":  s.Clock.Now().Unix(),
 		"exp":  expires.Unix(),
 		"type": tokenType,
+		// Keeps tokens issued in the same second distinct so concurrent sessions don't collide
+		"jti": id.NewString(),
 	}
+
+	return s.signToken(claims)
+}
+
+// GenerateAccessToken issues an access token carrying the user's role and resolved rights as scopes,
+// so stateless routes can authorize without a session or database lookup
+func (s *tokenService) GenerateAccessToken(user *model.User, expires time.Time) (string, error) {
+	return s.generateAccessToken(user, expires, nil)
+}
+
+// generateAccessToken issues an access token, tied to a session with a "sid" claim when one is given.
+// In opaque mode the claims are stored in the cache store under a random token instead of signed.
+func (s *tokenService) generateAccessToken(user *model.User, expires time.Time, sessionID *uuid.UUID) (string, error) {
+	if opaque := accesstoken.Current(); opaque != nil {
+		claims := utils.AccessClaims{
+			UserID:   user.ID.String(),
+			Role:     user.Role,
+			Scopes:   config.RightsForRole(user.Role),
+			Plan:     user.Plan,
+			IssuedAt: s.Clock.Now(),
+		}
+		if sessionID != nil {
+			claims.SessionID = sessionID.String()
+		}
+
+		token, err := opaque.Issue(claims, expires)
+		if errors.Is(err, cache.ErrStoreUnavailable) {
+			return "", fiber.NewError(fiber.StatusServiceUnavailable, "Cache unavailable")
+		}
+		return token, err
+	}
+
+	claims := jwt.MapClaims{
+		"sub":    user.ID.String(),
+		"iat":    s.Clock.Now().Unix(),
+		"exp":    expires.Unix(),
+		"type":   config.TokenTypeAccess,
+		"role":   user.Role,
+		"scopes": config.RightsForRole(user.Role),
+	}
+	if sessionID != nil {
+		claims["sid"] = sessionID.String()
+	}
+	if user.Plan != "" {
+		claims["plan"] = user.Plan
+	}
+
+	return s.signToken(claims)
+}
+
+// signToken signs claims with this app's issuer and audience, which VerifyToken requires
+func (s *tokenService) signToken(claims jwt.MapClaims) (string, error) {
+	claims["iss"] = config.JWTIssuer
+	claims["aud"] = config.JWTAudience
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	return token.SignedString([]byte(config.JWTSecret))
@@ -62,14 +157,26 @@ func (s *tokenService) SaveToken(c *fiber.Ctx, token, userID, tokenType string,
 		return err
 	}
 
-	tokenDoc := &model.Token{
+	return s.createToken(c, token, userID, tokenType, expires)
+}
+
+// createToken stores a token alongside any existing tokens of the same type
+func (s *tokenService) createToken(c *fiber.Ctx, token, userID, tokenType string, expires time.Time) error {
+	ownerID, err := id.Parse(userID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	return s.insertToken(c, &model.Token{
 		Token:   token,
-		UserID:  uuid.MustParse(userID),
+		UserID:  ownerID,
 		Type:    tokenType,
 		Expires: expires,
-	}
+	})
+}
 
-	result := s.DB.WithContext(c.Context()).Create(tokenDoc)
+func (s *tokenService) insertToken(c *fiber.Ctx, tokenDoc *model.Token) error {
+	result := s.DB.WithContext(c.UserContext()).Create(tokenDoc)
 
 	if result.Error != nil {
 		s.Log.Errorf("Failed save token: %+v", result.Error)
@@ -81,7 +188,7 @@ func (s *tokenService) SaveToken(c *fiber.Ctx, token, userID, tokenType string,
 func (s *tokenService) DeleteToken(c *fiber.Ctx, tokenType string, userID string) error {
 	tokenDoc := new(model.Token)
 
-	result := s.DB.WithContext(c.Context()).
+	result := s.DB.WithContext(c.UserContext()).
 		Where("type = ? AND user_id = ?", tokenType, userID).
 		Delete(tokenDoc)
 
@@ -89,27 +196,119 @@ func (s *tokenService) DeleteToken(c *fiber.Ctx, tokenType string, userID string
 		s.Log.Errorf("Failed to delete token: %+v", result.Error)
 	}
 
-	// Invalidate session cache after successful token deletion (INVL-04)
-	if result.Error == nil && s.SessionService != nil {
-		if invalidateErr := s.SessionService.InvalidateSession(c.Context(), userID); invalidateErr != nil {
-			s.Log.Warnf("failed to invalidate session cache on token deletion: %v", invalidateErr)
-			// Don't fail deletion - cache invalidation is best-effort
+	return result.Error
+}
+
+// EndSession deletes a single session's refresh token and its opaque access tokens, leaving the
+// user's other sessions intact
+func (s *tokenService) EndSession(c *fiber.Ctx, token *model.Token) error {
+	result := s.DB.WithContext(c.UserContext()).Delete(token)
+
+	if result.Error != nil {
+		s.Log.Errorf("Failed to end session: %+v", result.Error)
+		return result.Error
+	}
+
+	s.revokeAccessTokens(c.UserContext(), token.UserID.String(), token.SessionID)
+
+	return nil
+}
+
+// revokeAccessTokens drops the opaque access tokens of a session, or of every session of the user
+// when sessionID is nil. JWTs cannot be revoked and stay valid until they expire.
+func (s *tokenService) revokeAccessTokens(ctx context.Context, userID string, sessionID *uuid.UUID) {
+	opaque := accesstoken.Current()
+	if opaque == nil {
+		return
+	}
+
+	var err error
+	if sessionID != nil {
+		err = opaque.RevokeSession(ctx, userID, sessionID.String())
+	} else {
+		err = opaque.RevokeUser(ctx, userID)
+	}
+	if err != nil {
+		s.Log.Warnf("Failed to revoke access tokens of user %s: %v", userID, err)
+	}
+}
+
+// enforceSessionLimit makes room for a new session under MAX_SESSIONS_PER_USER, either
+// rejecting the login or deleting the oldest sessions depending on SESSION_LIMIT_POLICY, and
+// returns the sessions it deleted. It must run in the transaction that locked the user.
+func (s *tokenService) enforceSessionLimit(c *fiber.Ctx, tx *gorm.DB, userID string) ([]model.Token, error) {
+	var sessions []model.Token
+	result := tx.Where("type = ? AND user_id = ? AND expires > ?", config.TokenTypeRefresh, userID, s.Clock.Now()).
+		Order("created_at ASC").
+		Find(&sessions)
+
+	if result.Error != nil {
+		s.Log.Errorf("Failed to count active sessions: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	excess := len(sessions) - config.MaxSessionsPerUser + 1
+	if excess <= 0 {
+		return nil, nil
+	}
+
+	if config.SessionLimitPolicy == config.SessionLimitReject {
+		return nil, fiber.NewError(fiber.StatusConflict, "Maximum number of active sessions reached")
+	}
+
+	evicted := sessions[:excess]
+	ids := make([]uuid.UUID, len(evicted))
+	for i := range evicted {
+		ids[i] = evicted[i].ID
+	}
+
+	if err := tx.Where("id IN ?", ids).Delete(new(model.Token)).Error; err != nil {
+		s.Log.Errorf("Failed to evict sessions: %+v", err)
+		return nil, err
+	}
+
+	return evicted, nil
+}
+
+// endEvicted revokes the access tokens of the sessions the limit deleted, on every instance, and
+// remembers them so their devices learn why on their next refresh
+func (s *tokenService) endEvicted(ctx context.Context, userID string, evicted []model.Token) {
+	for i := range evicted {
+		if evicted[i].SessionID == nil {
+			continue
+		}
+		if s.Revocations == nil {
+			s.revokeAccessTokens(ctx, userID, evicted[i].SessionID)
+			continue
+		}
+		err := s.Revocations.PublishSession(ctx, userID, evicted[i].SessionID.String(), revocation.ReasonEvicted)
+		if err != nil {
+			s.Log.Warnf("Failed to broadcast eviction of session %s: %v", evicted[i].SessionID, err)
 		}
 	}
 
-	return result.Error
+	if s.SessionService != nil {
+		if err := s.SessionService.MarkEvicted(ctx, evicted); err != nil {
+			s.Log.Warnf("failed to record session eviction: %v", err)
+		}
+	}
+
+	s.Log.Infof("Evicted %d oldest sessions for user %s (limit %d)", len(evicted), userID, config.MaxSessionsPerUser)
 }
 
 func (s *tokenService) DeleteAllToken(c *fiber.Ctx, userID string) error {
 	tokenDoc := new(model.Token)
 
-	result := s.DB.WithContext(c.Context()).Where("user_id = ?", userID).Delete(tokenDoc)
+	result := s.DB.WithContext(c.UserContext()).Where("user_id = ?", userID).Delete(tokenDoc)
 
 	if result.Error != nil {
 		s.Log.Errorf("Failed to delete all token: %+v", result.Error)
+		return result.Error
 	}
 
-	return result.Error
+	s.revokeAccessTokens(c.UserContext(), userID, nil)
+
+	return nil
 }
 
 func (s *tokenService) GetTokenByUserID(c *fiber.Ctx, tokenStr string) (*model.Token, error) {
@@ -120,7 +319,7 @@ func (s *tokenService) GetTokenByUserID(c *fiber.Ctx, tokenStr string) (*model.T
 
 	tokenDoc := new(model.Token)
 
-	result := s.DB.WithContext(c.Context()).
+	result := s.DB.WithContext(c.UserContext()).
 		Where("token = ? AND user_id = ?", tokenStr, userID).
 		First(tokenDoc)
 
@@ -133,27 +332,126 @@ func (s *tokenService) GetTokenByUserID(c *fiber.Ctx, tokenStr string) (*model.T
 }
 
 func (s *tokenService) GenerateAuthTokens(c *fiber.Ctx, user *model.User) (*res.Tokens, error) {
-	accessTokenExpires := time.Now().UTC().Add(time.Minute * time.Duration(config.JWTAccessExp))
-	accessToken, err := s.GenerateToken(user.ID.String(), accessTokenExpires, config.TokenTypeAccess)
+	// Every sign-in ends here, including OAuth and confirmed logins
+	if !user.IsActive {
+		return nil, ErrAccountSuspended
+	}
+
+	if config.MaxSessionsPerUser <= 0 {
+		return s.issueAuthTokens(c, user, id.New())
+	}
+
+	// Concurrent logins of the user wait on its row lock, so each counts the sessions of the others
+	var tokens *res.Tokens
+	var evicted []model.Token
+	err := s.DB.WithContext(c.UserContext()).Transaction(func(tx *gorm.DB) error {
+		var locked []uuid.UUID
+		err := tx.Model(new(model.User)).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", user.ID).
+			Pluck("id", &locked).Error
+		if err != nil {
+			return err
+		}
+
+		if evicted, err = s.enforceSessionLimit(c, tx, user.ID.String()); err != nil {
+			return err
+		}
+
+		// The new refresh token is stored in the same transaction
+		inTx := *s
+		inTx.DB = tx
+		tokens, err = inTx.issueAuthTokens(c, user, id.New())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(evicted) > 0 {
+		s.endEvicted(c.UserContext(), user.ID.String(), evicted)
+	}
+
+	return tokens, nil
+}
+
+// RotateAuthTokens issues the tokens that replace a spent refresh token. They continue the same
+// session, so its ID and activity carry over and the session limit is not applied again.
+func (s *tokenService) RotateAuthTokens(c *fiber.Ctx, user *model.User, previous *model.Token) (*res.Tokens, error) {
+	if !user.IsActive {
+		return nil, ErrAccountSuspended
+	}
+
+	// Refresh tokens issued before sessions were tracked start one now
+	sessionID := id.New()
+	if previous.SessionID != nil {
+		sessionID = *previous.SessionID
+	}
+
+	return s.issueAuthTokens(c, user, sessionID)
+}
+
+// issueAuthTokens signs an access and a refresh token for the session and stores the refresh token
+func (s *tokenService) issueAuthTokens(c *fiber.Ctx, user *model.User, sessionID uuid.UUID) (*res.Tokens, error) {
+	accessTokenExpires := s.Clock.Now().Add(time.Minute * time.Duration(config.JWTAccessExp))
+	accessToken, err := s.generateAccessToken(user, accessTokenExpires, &sessionID)
 	if err != nil {
 		s.Log.Errorf("Failed generate token: %+v", err)
 		return nil, err
 	}
 
-	refreshTokenExpires := time.Now().UTC().Add(time.Hour * 24 * time.Duration(config.JWTRefreshExp))
+	refreshTokenExpires := s.Clock.Now().Add(time.Hour * 24 * time.Duration(config.JWTRefreshExp))
 	refreshToken, err := s.GenerateToken(user.ID.String(), refreshTokenExpires, config.TokenTypeRefresh)
 	if err != nil {
 		s.Log.Errorf("Failed generate token: %+v", err)
 		return nil, err
 	}
 
-	if err = s.SaveToken(c, refreshToken, user.ID.String(), config.TokenTypeRefresh, refreshTokenExpires); err != nil {
+	// Each login is its own session; signing in or refreshing counts as activity
+	now := s.Clock.Now()
+	tokenDoc := &model.Token{
+		Token:        refreshToken,
+		UserID:       user.ID,
+		Type:         config.TokenTypeRefresh,
+		Expires:      refreshTokenExpires,
+		SessionID:    &sessionID,
+		LastActiveAt: &now,
+	}
+
+	var binding string
+	if config.RefreshBinding {
+		if binding, err = randomBinding(); err != nil {
+			s.Log.Errorf("Failed generate refresh token binding: %+v", err)
+			return nil, err
+		}
+		hash := bindingHash(c, binding)
+		tokenDoc.BindingHash = &hash
+	}
+
+	if err := s.insertToken(c, tokenDoc); err != nil {
 		return nil, err
 	}
 
+	// The binding is only sent to the auth routes, for as long as the refresh token lasts
+	if binding != "" {
+		cookie := &fiber.Cookie{
+			Name:     config.RefreshBindingCookie,
+			Value:    binding,
+			Expires:  refreshTokenExpires,
+			Path:     "/v1/auth",
+			Secure:   config.IsProd,
+			HTTPOnly: true,
+			SameSite: "Lax",
+		}
+		if config.RefreshBindingCrossSite {
+			cookie.SameSite, cookie.Secure = "None", true
+		}
+		c.Cookie(cookie)
+	}
+
 	// Cache user session with session ID generation (SESS-01, SESS-07)
 	if s.SessionService != nil {
-		if cacheErr := s.SessionService.CacheUserSession(c.Context(), user.ID.String(), user); cacheErr != nil {
+		if cacheErr := s.SessionService.CacheUserSession(c.UserContext(), user.ID.String(), user); cacheErr != nil {
 			s.Log.Warn("Failed to cache user session, continuing without cache", "error", cacheErr)
 			// Continue with token generation - graceful degradation
 		} else {
@@ -187,6 +485,65 @@ func (s *tokenService) GenerateAuthTokens(c *fiber.Ctx, user *model.User) (*res.
 	}, nil
 }
 
+func (s *tokenService) CheckBinding(c *fiber.Ctx, token *model.Token) error {
+	// Tokens issued before bindings existed, or while they were off, are not bound
+	if !config.RefreshBinding || token.BindingHash == nil {
+		return nil
+	}
+
+	presented := bindingHash(c, c.Cookies(config.RefreshBindingCookie))
+	if subtle.ConstantTimeCompare([]byte(presented), []byte(*token.BindingHash)) == 1 {
+		return nil
+	}
+
+	// Whoever holds the token is not the device it was issued to; end the session on every device
+	query := s.DB.WithContext(c.UserContext()).Where("id = ?", token.ID)
+	if token.SessionID != nil {
+		query = s.DB.WithContext(c.UserContext()).Where("session_id = ?", *token.SessionID)
+	}
+	if err := query.Delete(new(model.Token)).Error; err != nil {
+		s.Log.Errorf("Failed to revoke session of replayed refresh token: %+v", err)
+		return err
+	}
+
+	// Access tokens of the session, JWTs included, stop working on every instance
+	if token.SessionID != nil {
+		if s.Revocations == nil {
+			s.revokeAccessTokens(c.UserContext(), token.UserID.String(), token.SessionID)
+		} else {
+			err := s.Revocations.PublishSession(c.UserContext(), token.UserID.String(), token.SessionID.String(),
+				revocation.ReasonReplayed)
+			if err != nil {
+				s.Log.Warnf("Failed to broadcast revocation of replayed session %s: %v", token.SessionID, err)
+			}
+		}
+	}
+
+	s.Log.Warnf("Refresh token of user %s presented without its device binding; session revoked", token.UserID)
+
+	return ErrRefreshTokenReplayed
+}
+
+// randomBinding returns a new device binding secret
+func randomBinding() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// bindingHash hashes a binding secret, with the User-Agent when REFRESH_TOKEN_BIND_USER_AGENT is set
+func bindingHash(c *fiber.Ctx, binding string) string {
+	hash := sha256.New()
+	hash.Write([]byte(binding))
+	if config.RefreshBindUserAgent {
+		hash.Write([]byte{0})
+		hash.Write([]byte(c.Get(fiber.HeaderUserAgent)))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 func (s *tokenService) GenerateResetPasswordToken(c *fiber.Ctx, req *validation.ForgotPassword) (string, error) {
 	if err := s.Validate.Struct(req); err != nil {
 		return "", err
@@ -197,7 +554,7 @@ func (s *tokenService) GenerateResetPasswordToken(c *fiber.Ctx, req *validation.
 		return "", err
 	}
 
-	expires := time.Now().UTC().Add(time.Minute * time.Duration(config.JWTResetPasswordExp))
+	expires := s.Clock.Now().Add(time.Minute * time.Duration(config.JWTResetPasswordExp))
 	resetPasswordToken, err := s.GenerateToken(user.ID.String(), expires, config.TokenTypeResetPassword)
 	if err != nil {
 		s.Log.Errorf("Failed generate token: %+v", err)
@@ -212,16 +569,94 @@ func (s *tokenService) GenerateResetPasswordToken(c *fiber.Ctx, req *validation.
 }
 
 func (s *tokenService) GenerateVerifyEmailToken(c *fiber.Ctx, user *model.User) (*string, error) {
-	expires := time.Now().UTC().Add(time.Minute * time.Duration(config.JWTVerifyEmailExp))
+	expires := s.Clock.Now().Add(time.Minute * time.Duration(config.JWTVerifyEmailExp))
 	verifyEmailToken, err := s.GenerateToken(user.ID.String(), expires, config.TokenTypeVerifyEmail)
 	if err != nil {
 		s.Log.Errorf("Failed generate token: %+v", err)
 		return nil, err
 	}
 
-	if err = s.SaveToken(c, verifyEmailToken, user.ID.String(), config.TokenTypeVerifyEmail, expires); err != nil {
+	if err = s.DeleteToken(c, config.TokenTypeVerifyEmail, user.ID.String()); err != nil {
+		return nil, err
+	}
+
+	// Bind the link to the current address so changing email afterwards invalidates it
+	email := user.Email
+	if err = s.insertToken(c, &model.Token{
+		Token:   verifyEmailToken,
+		UserID:  user.ID,
+		Type:    config.TokenTypeVerifyEmail,
+		Expires: expires,
+		Email:   &email,
+	}); err != nil {
 		return nil, err
 	}
 
 	return &verifyEmailToken, nil
 }
+
+// ConsumeToken atomically marks a stored single-use token as spent and returns it. The row is kept
+// until it expires so a replay yields ErrTokenConsumed; an unknown or expired token yields gorm.ErrRecordNotFound
+func (s *tokenService) ConsumeToken(c *fiber.Ctx, tokenStr, tokenType string) (*model.Token, error) {
+	tokenDoc := new(model.Token)
+	now := s.Clock.Now()
+
+	result := s.DB.WithContext(c.UserContext()).
+		Model(tokenDoc).
+		Clauses(clause.Returning{}).
+		Where("token = ? AND type = ? AND expires > ? AND consumed_at IS NULL", tokenStr, tokenType, now).
+		Update("consumed_at", now)
+
+	if result.Error != nil {
+		s.Log.Errorf("Failed to consume token: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		return tokenDoc, nil
+	}
+
+	var consumed int64
+	if err := s.DB.WithContext(c.UserContext()).
+		Model(new(model.Token)).
+		Where("token = ? AND type = ? AND consumed_at IS NOT NULL", tokenStr, tokenType).
+		Count(&consumed).Error; err != nil {
+		s.Log.Errorf("Failed to look up consumed token: %+v", err)
+		return nil, err
+	}
+
+	if consumed > 0 {
+		return nil, ErrTokenConsumed
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// GenerateConfirmLoginToken issues the emailed link that approves a login flagged as suspicious
+func (s *tokenService) GenerateConfirmLoginToken(c *fiber.Ctx, user *model.User) (string, error) {
+	expires := s.Clock.Now().Add(config.Risk.ConfirmExp)
+	confirmLoginToken, err := s.GenerateToken(user.ID.String(), expires, config.TokenTypeConfirmLogin)
+	if err != nil {
+		s.Log.Errorf("Failed generate token: %+v", err)
+		return "", err
+	}
+
+	if err = s.SaveToken(c, confirmLoginToken, user.ID.String(), config.TokenTypeConfirmLogin, expires); err != nil {
+		return "", err
+	}
+
+	return confirmLoginToken, nil
+}
+
+// DeleteExpiredTokens removes all tokens past their expiry; used by the background cleanup job
+func (s *tokenService) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	result := s.DB.WithContext(ctx).
+		Where("expires < ?", s.Clock.Now()).
+		Delete(new(model.Token))
+
+	if result.Error != nil {
+		s.Log.Errorf("Failed to delete expired tokens: %+v", result.Error)
+	}
+
+	return result.RowsAffected, result.Error
+}
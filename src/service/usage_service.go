@@ -0,0 +1,391 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/response"
+	"app/src/utils"
+	"app/src/validation"
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// usageMaxDays bounds the date range of a usage query
+const usageMaxDays = 366
+
+type UsageService interface {
+	RecordRequest(ctx context.Context, user *model.User, token *model.APIToken)
+	RecordEvent(ctx context.Context, userID uuid.UUID, event string)
+	Rollup(ctx context.Context) (int, error)
+	GetUsage(c *fiber.Ctx, userID string, query *validation.QueryUsage) (*response.Usage, error)
+	GetReport(c *fiber.Ctx, query *validation.QueryUsageReport) (*response.UsageReport, error)
+}
+
+type usageService struct {
+	Log      *logrus.Logger
+	DB       *gorm.DB
+	Validate *validator.Validate
+	Store    cache.Store
+	Config   config.MeteringConfig
+	Clock    clock.Clock
+}
+
+// NewUsageService meters requests and business events in the cache store, which must support
+// atomic increments; Rollup copies the counters into daily rows that usage reports are read from.
+// Days are timed with clk, the wall clock if nil.
+func NewUsageService(
+	db *gorm.DB, validate *validator.Validate, store cache.Store, cfg config.MeteringConfig, clk clock.Clock,
+) UsageService {
+	return &usageService{
+		Log:      utils.Log,
+		DB:       db,
+		Validate: validate,
+		Store:    store,
+		Config:   cfg,
+		Clock:    clock.OrSystem(clk),
+	}
+}
+
+// RecordRequest counts an authenticated request for the user, or for the API token it was made with
+func (s *usageService) RecordRequest(ctx context.Context, user *model.User, token *model.APIToken) {
+	subject := model.UserQuotaSubject(user.ID)
+	if token != nil {
+		subject = model.TokenQuotaSubject(token.ID)
+	}
+
+	s.record(ctx, user.ID, subject, model.UsageMetricRequests)
+}
+
+// RecordEvent counts a business event of the user if it is one of the metered events
+func (s *usageService) RecordEvent(ctx context.Context, userID uuid.UUID, event string) {
+	if !slices.Contains(s.Config.Events, event) {
+		return
+	}
+
+	s.record(ctx, userID, model.UserQuotaSubject(userID), event)
+}
+
+// record increments the metric of the subject today. Metering is best-effort: if the cache store
+// cannot count, the usage is lost rather than failing the request.
+func (s *usageService) record(ctx context.Context, userID uuid.UUID, subject, metric string) {
+	counter, ok := s.Store.(cache.Counter)
+	if !s.Config.Enabled || !ok || !cache.IsStoreAvailable(s.Store) {
+		return
+	}
+
+	now := s.Clock.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	key := cache.GetUsageKey(day.Format(time.DateOnly), userID.String(), subject, metric)
+
+	// Keep the counter until a rollup after the day ended has seen it
+	ttl := day.AddDate(0, 0, 1).Sub(now) + 2*s.Config.RollupInterval
+
+	count, err := counter.IncrBy(ctx, key, 1, ttl)
+	if err != nil {
+		s.Log.Warnf("Failed to meter %s usage: %v", metric, err)
+		return
+	}
+	if count != 1 || s.DB == nil {
+		return
+	}
+
+	// A new counter catches up with the rolled up count, so usage survives the cache store losing its data
+	var rolledUp int64
+	err = s.DB.WithContext(ctx).
+		Model(new(model.UsageRecord)).
+		Select("count").
+		Where("day = ? AND subject = ? AND metric = ?", day, subject, metric).
+		Scan(&rolledUp).Error
+	if err != nil {
+		s.Log.Warnf("Failed to load rolled up usage: %v", err)
+		return
+	}
+	if rolledUp > 0 {
+		if _, err := counter.IncrBy(ctx, key, rolledUp, ttl); err != nil {
+			s.Log.Warnf("Failed to meter %s usage: %v", metric, err)
+		}
+	}
+}
+
+// Rollup copies the counters in the cache store into daily rows and returns how many it wrote.
+// Stored counts only grow, so a counter restarted after a cache flush cannot lower them.
+func (s *usageService) Rollup(ctx context.Context) (int, error) {
+	if s.Store == nil || !cache.IsStoreAvailable(s.Store) {
+		return 0, nil
+	}
+
+	keys, err := s.Store.Keys(ctx, cache.UsageKeyPrefix+"*")
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.Clock.Now()
+	rows := make([]model.UsageRecord, 0, len(keys))
+	for _, key := range keys {
+		row, ok := parseUsageKey(key)
+		if !ok {
+			continue
+		}
+
+		data, err := s.Store.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+
+		row.Count, err = strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		row.UpdatedAt = now
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	err = s.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "subject"}, {Name: "metric"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":      gorm.Expr("GREATEST(usage_records.count, EXCLUDED.count)"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).CreateInBatches(&rows, 500).Error
+
+	if err != nil {
+		s.Log.Errorf("Failed to roll up usage: %+v", err)
+		return 0, err
+	}
+
+	return len(rows), nil
+}
+
+// GetUsage returns the user's rolled up usage per day and per API token. Usage outlives the user, so
+// deleted users can still be billed.
+func (s *usageService) GetUsage(c *fiber.Ctx, userID string, query *validation.QueryUsage) (*response.Usage, error) {
+	if err := s.Validate.Struct(query); err != nil {
+		return nil, err
+	}
+
+	from, to, err := usageRange(s.Clock.Now(), query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var rows []model.UsageRecord
+	err = s.DB.WithContext(c.UserContext()).
+		Where("user_id = ? AND day BETWEEN ? AND ?", id, from, to).
+		Order("day, metric").
+		Find(&rows).Error
+	if err != nil {
+		s.Log.Errorf("Failed to get usage: %+v", err)
+		return nil, err
+	}
+
+	usage := &response.Usage{
+		From:   from.Format(time.DateOnly),
+		To:     to.Format(time.DateOnly),
+		Totals: map[string]int64{},
+		Days:   []response.UsageDay{},
+		Keys:   []response.UsageKey{},
+	}
+
+	keys := map[string]int{}
+	for _, row := range rows {
+		usage.Totals[row.Metric] += row.Count
+		usage.Days = addUsageDay(usage.Days, row.Day, row.Metric, row.Count)
+
+		tokenID, ok := strings.CutPrefix(row.Subject, "token:")
+		if !ok {
+			continue
+		}
+		i, seen := keys[tokenID]
+		if !seen {
+			id, err := uuid.Parse(tokenID)
+			if err != nil {
+				continue
+			}
+			i = len(usage.Keys)
+			keys[tokenID] = i
+			usage.Keys = append(usage.Keys, response.UsageKey{TokenID: id, Metrics: map[string]int64{}})
+		}
+		usage.Keys[i].Metrics[row.Metric] += row.Count
+	}
+
+	return usage, nil
+}
+
+// GetReport aggregates the rolled up usage of all users and ranks them by one metric
+func (s *usageService) GetReport(c *fiber.Ctx, query *validation.QueryUsageReport) (*response.UsageReport, error) {
+	if err := s.Validate.Struct(query); err != nil {
+		return nil, err
+	}
+
+	from, to, err := usageRange(s.Clock.Now(), query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	db := s.DB.WithContext(c.UserContext())
+	inRange := db.Model(new(model.UsageRecord)).Where("day BETWEEN ? AND ?", from, to).Session(&gorm.Session{})
+
+	var days []struct {
+		Day    time.Time
+		Metric string
+		Count  int64
+	}
+	err = inRange.
+		Select("day, metric, SUM(count) AS count").
+		Group("day, metric").
+		Order("day, metric").
+		Scan(&days).Error
+	if err != nil {
+		s.Log.Errorf("Failed to get usage report: %+v", err)
+		return nil, err
+	}
+
+	report := &response.UsageReport{
+		From:     from.Format(time.DateOnly),
+		To:       to.Format(time.DateOnly),
+		Totals:   map[string]int64{},
+		Days:     []response.UsageDay{},
+		Metric:   query.Metric,
+		TopUsers: []response.UsageUser{},
+	}
+	for _, day := range days {
+		report.Totals[day.Metric] += day.Count
+		report.Days = addUsageDay(report.Days, day.Day, day.Metric, day.Count)
+	}
+
+	err = inRange.Distinct("user_id").Count(&report.ActiveUsers).Error
+	if err != nil {
+		s.Log.Errorf("Failed to count active users: %+v", err)
+		return nil, err
+	}
+
+	err = inRange.
+		Select("usage_records.user_id, COALESCE(users.email, '') AS email, SUM(usage_records.count) AS count").
+		Joins("LEFT JOIN users ON users.id = usage_records.user_id").
+		Where("usage_records.metric = ?", query.Metric).
+		Group("usage_records.user_id, users.email").
+		Order("count DESC").
+		Limit(query.Limit).
+		Scan(&report.TopUsers).Error
+	if err != nil {
+		s.Log.Errorf("Failed to rank users by usage: %+v", err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// usageRange parses the days of a usage query, defaulting to the current UTC month up to today
+func usageRange(now time.Time, fromParam, toParam string) (time.Time, time.Time, error) {
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	// Formats were checked by the validator
+	if toParam != "" {
+		to, _ = time.Parse(time.DateOnly, toParam)
+		if fromParam == "" {
+			from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+	}
+	if fromParam != "" {
+		from, _ = time.Parse(time.DateOnly, fromParam)
+	}
+
+	// The validator rejects explicit reversed ranges; a from date after today still crosses the default
+	if to.Before(from) {
+		return from, to, fiber.NewError(fiber.StatusBadRequest, "The from date must not be after the to date")
+	}
+	if to.Sub(from) >= usageMaxDays*24*time.Hour {
+		return from, to, fiber.NewError(fiber.StatusBadRequest,
+			"Date range must not exceed "+strconv.Itoa(usageMaxDays)+" days")
+	}
+
+	return from, to, nil
+}
+
+// addUsageDay adds a count to the last day of days, appending the day if it is a new one; rows must
+// come ordered by day
+func addUsageDay(days []response.UsageDay, day time.Time, metric string, count int64) []response.UsageDay {
+	date := day.Format(time.DateOnly)
+	if len(days) == 0 || days[len(days)-1].Date != date {
+		days = append(days, response.UsageDay{Date: date, Metrics: map[string]int64{}})
+	}
+	days[len(days)-1].Metrics[metric] += count
+	return days
+}
+
+// parseUsageKey splits a counter key into the day, owner, subject and metric of its usage record
+func parseUsageKey(key string) (model.UsageRecord, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(key, cache.UsageKeyPrefix), ":", 5)
+	if len(parts) != 5 || parts[4] == "" {
+		return model.UsageRecord{}, false
+	}
+
+	day, err := time.Parse(time.DateOnly, parts[0])
+	if err != nil {
+		return model.UsageRecord{}, false
+	}
+	userID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return model.UsageRecord{}, false
+	}
+
+	// The subject is "user:{id}" or "token:{id}"
+	return model.UsageRecord{
+		Day:     day,
+		Subject: parts[2] + ":" + parts[3],
+		Metric:  parts[4],
+		UserID:  userID,
+	}, true
+}
+
+type meteredAuditService struct {
+	AuditService
+	UsageService UsageService
+}
+
+// NewMeteredAuditService records audit entries with audit and meters the actions concerning a user
+// that are listed in METERING_EVENTS as business events of that user
+func NewMeteredAuditService(audit AuditService, usage UsageService) AuditService {
+	return &meteredAuditService{AuditService: audit, UsageService: usage}
+}
+
+func (s *meteredAuditService) Record(c *fiber.Ctx, userID *uuid.UUID, action string, metadata map[string]any) {
+	s.AuditService.Record(c, userID, action, metadata)
+
+	if userID != nil {
+		s.UsageService.RecordEvent(c.UserContext(), *userID, action)
+	}
+}
+
+func (s *meteredAuditService) RecordSystem(
+	ctx context.Context, userID *uuid.UUID, action string, metadata map[string]any,
+) {
+	s.AuditService.RecordSystem(ctx, userID, action, metadata)
+
+	if userID != nil {
+		s.UsageService.RecordEvent(ctx, *userID, action)
+	}
+}
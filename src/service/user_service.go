@@ -1,30 +1,66 @@
 package service
 
 import (
+	"app/src/binding"
 	"app/src/cache"
 	"app/src/config"
+	"app/src/dbretry"
+	"app/src/dryrun"
+	"app/src/memo"
 	"app/src/model"
+	"app/src/response"
+	"app/src/revision"
+	"app/src/revocation"
 	"app/src/utils"
 	"app/src/validation"
+	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
+// ErrAccountSuspended rejects suspended users; 423 keeps it apart from the 401/403 of other auth failures
+var ErrAccountSuspended = fiber.NewError(fiber.StatusLocked, "Account suspended")
+
+// memoKindUser memoizes users by ID within a request
+const memoKindUser = "user"
+
+// restorableColumns are the columns a rollback puts back. The password is never kept, and account
+// state (verification, suspension, plan) changes through its own endpoints.
+var restorableColumns = []string{"name", "username", "email", "role", "timezone"}
+
+var (
+	ErrUsernameTaken    = fiber.NewError(fiber.StatusConflict, "Username is already taken")
+	ErrUsernameReserved = fiber.NewError(fiber.StatusConflict, "Username is reserved")
+)
+
 type UserService interface {
 	GetUsers(c *fiber.Ctx, params *validation.QueryUser) ([]model.User, int64, error)
 	GetUserByID(c *fiber.Ctx, id string) (*model.User, error)
 	GetUserByEmail(c *fiber.Ctx, email string) (*model.User, error)
+	GetUserByHandle(c *fiber.Ctx, username string) (*model.User, error)
+	CheckUsername(c *fiber.Ctx, req *validation.CheckUsername) (*response.UsernameAvailability, error)
 	CreateUser(c *fiber.Ctx, req *validation.CreateUser) (*model.User, error)
 	UpdatePassOrVerify(c *fiber.Ctx, req *validation.UpdatePassOrVerify, id string) error
 	UpdateUser(c *fiber.Ctx, req *validation.UpdateUser, id string) (*model.User, error)
+	PatchUser(c *fiber.Ctx, id string) (*model.User, error)
 	DeleteUser(c *fiber.Ctx, id string) error
+	SuspendUser(c *fiber.Ctx, id string) (*model.User, error)
+	ReactivateUser(c *fiber.Ctx, id string) (*model.User, error)
+	GetUserRevisions(c *fiber.Ctx, id string, params *validation.QueryUserRevisions) ([]model.UserRevision, int64, error)
+	RestoreUserRevision(c *fiber.Ctx, id, revisionID string) (*model.User, error)
 	CreateGoogleUser(c *fiber.Ctx, req *validation.GoogleLogin) (*model.User, error)
+	CreateAppleUser(c *fiber.Ctx, req *validation.AppleLogin) (*model.User, error)
 }
 
 type userService struct {
@@ -33,15 +69,27 @@ type userService struct {
 	Validate         *validator.Validate
 	SessionService   SessionService
 	CacheInvalidator *cache.CacheInvalidator
+	NegativeCache    *cache.NegativeCache
+	Revocations      *revocation.Bus
+	AuditService     AuditService
+	EmailDomains     EmailDomainService
 }
 
-func NewUserService(db *gorm.DB, validate *validator.Validate, sessionService SessionService, cacheInvalidator *cache.CacheInvalidator) UserService {
+func NewUserService(
+	db *gorm.DB, validate *validator.Validate, sessionService SessionService,
+	cacheInvalidator *cache.CacheInvalidator, negativeCache *cache.NegativeCache, revocations *revocation.Bus,
+	auditService AuditService, emailDomains EmailDomainService,
+) UserService {
 	return &userService{
 		Log:              utils.Log,
 		DB:               db,
 		Validate:         validate,
 		SessionService:   sessionService,
 		CacheInvalidator: cacheInvalidator,
+		NegativeCache:    negativeCache,
+		Revocations:      revocations,
+		AuditService:     auditService,
+		EmailDomains:     emailDomains,
 	}
 }
 
@@ -54,12 +102,11 @@ func (s *userService) GetUsers(c *fiber.Ctx, params *validation.QueryUser) ([]mo
 	}
 
 	offset := (params.Page - 1) * params.Limit
-	query := s.DB.WithContext(c.Context()).Order("created_at asc")
+	query := s.DB.WithContext(c.UserContext()).Order("created_at asc")
 
-	if search := params.Search; search != "" {
-		query = query.Where("name LIKE ? OR email LIKE ? OR role LIKE ?",
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
-	}
+	query = filterUsers(query, &validation.UserFilter{
+		Search: params.Search, Tag: params.Tag, Username: params.Username, Type: params.Type,
+	})
 
 	result := query.Find(&users).Count(&totalResults)
 	if result.Error != nil {
@@ -67,7 +114,7 @@ func (s *userService) GetUsers(c *fiber.Ctx, params *validation.QueryUser) ([]mo
 		return nil, 0, result.Error
 	}
 
-	result = query.Limit(params.Limit).Offset(offset).Find(&users)
+	result = query.Preload("Tags").Limit(params.Limit).Offset(offset).Find(&users)
 	if result.Error != nil {
 		s.Log.Errorf("Failed to get all users: %+v", result.Error)
 		return nil, 0, result.Error
@@ -77,42 +124,123 @@ func (s *userService) GetUsers(c *fiber.Ctx, params *validation.QueryUser) ([]mo
 }
 
 func (s *userService) GetUserByID(c *fiber.Ctx, id string) (*model.User, error) {
+	// Lookups repeated within a request, e.g. by the auth middleware and then the handler, are served from memory
+	user, err := memo.Load(c.UserContext(), memoKindUser, id, func() (*model.User, error) {
+		return s.getUserByID(c, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers may change the user they get, so each gets its own copy
+	clone := *user
+	clone.Tags = slices.Clone(user.Tags)
+	return &clone, nil
+}
+
+func (s *userService) getUserByID(c *fiber.Ctx, id string) (*model.User, error) {
+	// Known-missing IDs are rejected without a database round trip (also shields the auth middleware)
+	if s.NegativeCache.IsMissing(c.UserContext(), cache.NegativeKindUserID, id) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+
+	user := new(model.User)
+
+	// Every authenticated request without a cached session goes through here, so it rides out failovers
+	err := dbretry.Read(c.UserContext(), s.DB, func(tx *gorm.DB) error {
+		return tx.Preload("Tags").First(user, "id = ?", id).Error
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		s.NegativeCache.MarkMissing(c.UserContext(), cache.NegativeKindUserID, id)
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+
+	if err != nil {
+		s.Log.Errorf("Failed get user by id: %+v", err)
+	}
+
+	return user, err
+}
+
+func (s *userService) GetUserByEmail(c *fiber.Ctx, email string) (*model.User, error) {
+	if s.NegativeCache.IsMissing(c.UserContext(), cache.NegativeKindUserEmail, email) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+
 	user := new(model.User)
 
-	result := s.DB.WithContext(c.Context()).First(user, "id = ?", id)
+	// Service accounts cannot sign in or reset a password, so flows looking users up by email skip them
+	result := s.DB.WithContext(c.UserContext()).Where("email = ? AND type = ?", email, model.UserTypeHuman).First(user)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		s.NegativeCache.MarkMissing(c.UserContext(), cache.NegativeKindUserEmail, email)
 		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
 
 	if result.Error != nil {
-		s.Log.Errorf("Failed get user by id: %+v", result.Error)
+		s.Log.Errorf("Failed get user by email: %+v", result.Error)
 	}
 
 	return user, result.Error
 }
 
-func (s *userService) GetUserByEmail(c *fiber.Ctx, email string) (*model.User, error) {
+// GetUserByHandle finds a user by username, ignoring case
+func (s *userService) GetUserByHandle(c *fiber.Ctx, username string) (*model.User, error) {
 	user := new(model.User)
 
-	result := s.DB.WithContext(c.Context()).Where("email = ?", email).First(user)
+	result := s.DB.WithContext(c.UserContext()).Preload("Tags").First(user, "LOWER(username) = LOWER(?)", username)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
 
 	if result.Error != nil {
-		s.Log.Errorf("Failed get user by email: %+v", result.Error)
+		s.Log.Errorf("Failed get user by username: %+v", result.Error)
 	}
 
 	return user, result.Error
 }
 
+// CheckUsername reports whether a username can still be chosen and, if not, why
+func (s *userService) CheckUsername(
+	c *fiber.Ctx, req *validation.CheckUsername,
+) (*response.UsernameAvailability, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	availability := &response.UsernameAvailability{Username: req.Username, Available: true}
+
+	taken, err := usernameTaken(c.UserContext(), s.DB, req.Username)
+	if err != nil {
+		s.Log.Errorf("Failed to check username: %+v", err)
+		return nil, err
+	}
+
+	switch {
+	case isReservedUsername(req.Username):
+		availability.Available, availability.Reason = false, "reserved"
+	case taken:
+		availability.Available, availability.Reason = false, "taken"
+	}
+
+	return availability, nil
+}
+
 func (s *userService) CreateUser(c *fiber.Ctx, req *validation.CreateUser) (*model.User, error) {
 	if err := s.Validate.Struct(req); err != nil {
 		return nil, err
 	}
 
+	if err := checkEmailDomain(c.UserContext(), s.EmailDomains, req.Email); err != nil {
+		return nil, err
+	}
+
+	if isReservedUsername(req.Username) {
+		return nil, ErrUsernameReserved
+	}
+
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
 		s.Log.Errorf("Failed hash password: %+v", err)
@@ -121,22 +249,30 @@ func (s *userService) CreateUser(c *fiber.Ctx, req *validation.CreateUser) (*mod
 
 	user := &model.User{
 		Name:     req.Name,
+		Username: optionalUsername(req.Username),
 		Email:    req.Email,
 		Password: hashedPassword,
 		Role:     req.Role,
+		Timezone: optionalTimezone(req.Timezone),
 	}
 
-	result := s.DB.WithContext(c.Context()).Create(user)
+	result := s.DB.WithContext(c.UserContext()).Create(user)
 
 	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
-		return nil, fiber.NewError(fiber.StatusConflict, "Email is already in use")
+		return nil, duplicateUserError(c.UserContext(), s.DB, req.Username, "Email is already in use")
 	}
 
 	if result.Error != nil {
 		s.Log.Errorf("Failed to create user: %+v", result.Error)
+		return nil, result.Error
 	}
 
-	return user, result.Error
+	// The user exists now - drop any stale not-found marker
+	s.NegativeCache.Forget(c.UserContext(), cache.NegativeKindUserEmail, user.Email)
+
+	s.audit(c, &user.ID, model.AuditActionUserCreated, map[string]any{"role": user.Role})
+
+	return user, nil
 }
 
 func (s *userService) UpdateUser(c *fiber.Ctx, req *validation.UpdateUser, id string) (*model.User, error) {
@@ -144,111 +280,176 @@ func (s *userService) UpdateUser(c *fiber.Ctx, req *validation.UpdateUser, id st
 		return nil, err
 	}
 
-	if req.Email == "" && req.Name == "" && req.Password == "" && req.Role == "" {
-		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid Request")
+	currentUser, err := s.GetUserByID(c, id)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get current user to detect role changes
+	return s.changeUser(c, currentUser, req)
+}
+
+// PatchUser applies the JSON Patch or merge patch in the request body to the user. "test"
+// operations make the update conditional on the tested fields not having changed meanwhile.
+func (s *userService) PatchUser(c *fiber.Ctx, id string) (*model.User, error) {
 	currentUser, err := s.GetUserByID(c, id)
 	if err != nil {
 		return nil, err
 	}
 
+	patch, err := binding.BindPatch(c, userPatchDocument(currentUser))
+	if err != nil {
+		return nil, err
+	}
+	if len(patch.Changes) == 0 {
+		return currentUser, nil
+	}
+
+	// The changed fields decode like a JSON body, nulls included
+	body, err := json.Marshal(patch.Changes)
+	if err != nil {
+		return nil, err
+	}
+	req := &validation.UpdateUser{Tests: patch.Tests}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, binding.ErrInvalidBody
+	}
+
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	return s.changeUser(c, currentUser, req)
+}
+
+// changeUser applies a validated update to the user, refusing fields the caller's role may not change
+func (s *userService) changeUser(c *fiber.Ctx, currentUser *model.User, req *validation.UpdateUser) (*model.User, error) {
+	// Users may not e.g. make themselves admin
+	caller, _ := c.Locals("user").(*model.User)
+	allowed := config.UserFieldsForRole("")
+	if caller != nil {
+		allowed = config.UserFieldsForRole(caller.Role)
+	}
+	for _, field := range req.Fields() {
+		if !slices.Contains(allowed, field) {
+			return nil, fiber.NewError(fiber.StatusForbidden, "You don't have permission to change "+field)
+		}
+	}
+
+	if req.Username.Present() && isReservedUsername(req.Username.Value) {
+		return nil, ErrUsernameReserved
+	}
+
+	id := currentUser.ID.String()
+
 	// Check if role is changing (privilege elevation detection, SESS-07)
-	roleChanged := req.Role != "" && req.Role != currentUser.Role
+	roleChanged := req.Role.Present() && req.Role.Value != currentUser.Role
+
+	// Absent fields are left alone; null clears the nullable ones
+	columns := map[string]any{}
+	for column, value := range map[string]validation.Optional[string]{
+		"name": req.Name, "username": req.Username, "email": req.Email,
+		"role": req.Role, "timezone": req.Timezone,
+	} {
+		if value.Set {
+			columns[column] = value.Ptr()
+		}
+	}
 
-	if req.Password != "" {
-		hashedPassword, err := utils.HashPassword(req.Password)
+	if req.Password.Present() {
+		hashedPassword, err := utils.HashPassword(req.Password.Value)
 		if err != nil {
 			return nil, err
 		}
-		req.Password = hashedPassword
+		columns["password"] = hashedPassword
+	}
+
+	// Tested fields must still hold the tested values, or another update got there first
+	query := s.DB.WithContext(c.UserContext()).Model(new(model.User)).Where("id = ?", id)
+	for field, value := range req.Tests {
+		switch {
+		case field == "password":
+			// Write-only, it always reads as null
+		case value == nil:
+			query = query.Where(field + " IS NULL")
+		default:
+			query = query.Where(field+" = ?", value)
+		}
 	}
 
-	updateBody := &model.User{
-		Name:     req.Name,
-		Password: req.Password,
-		Email:    req.Email,
-		Role:     req.Role,
+	if dryrun.Requested(c) {
+		return nil, s.previewUpdate(query, currentUser, columns, len(req.Tests) > 0)
 	}
 
-	result := s.DB.WithContext(c.Context()).Where("id = ?", id).Updates(updateBody)
+	result := query.Updates(columns)
 
 	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
-		return nil, fiber.NewError(fiber.StatusConflict, "Email is already in use")
+		return nil, duplicateUserError(c.UserContext(), s.DB, req.Username.Value, "Email is already in use")
+	}
+
+	if result.Error != nil {
+		s.Log.Errorf("Failed to update user: %+v", result.Error)
+		return nil, result.Error
 	}
 
 	if result.RowsAffected == 0 {
-		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+		return nil, unmatchedUpdateError(len(req.Tests) > 0)
 	}
 
-	if result.Error != nil {
-		s.Log.Errorf("Failed to update user: %+v", result.Error)
+	if req.Password.Present() {
+		s.audit(c, &currentUser.ID, model.AuditActionPasswordChanged, map[string]any{})
 	}
 
-	// Invalidate API response cache after successful update
-	if result.Error == nil && s.CacheInvalidator != nil {
-		if err := s.CacheInvalidator.InvalidateUserRelatedCache(c.Context(), id); err != nil {
-			s.Log.Warnf("failed to invalidate user cache on update: %v", err)
-			// Don't fail the operation - cache invalidation is best-effort
-		}
+	// The new email now resolves to this user - drop any stale not-found marker
+	if req.Email.Present() {
+		s.NegativeCache.Forget(c.UserContext(), cache.NegativeKindUserEmail, req.Email.Value)
 	}
 
-	// Handle cache invalidation and session regeneration
-	if s.SessionService != nil {
-		if roleChanged {
-			// Role changed - regenerate session ID for security (SESS-07 privilege elevation)
-			bytes := make([]byte, 32)
-			if _, err := rand.Read(bytes); err != nil {
-				s.Log.Warn("Failed to generate new session ID, using cache invalidation only", "error", err)
-				// Invalidate old cache
-				if invalidateErr := s.SessionService.InvalidateSession(c.Context(), id); invalidateErr != nil {
-					s.Log.Warn("Failed to invalidate cache", "error", invalidateErr)
-				}
-				return nil, fiber.NewError(fiber.StatusInternalServerError, "Session update failed")
-			}
-			newSessionID := base64.URLEncoding.EncodeToString(bytes)
+	// Tell every instance to drop in-process state and connections tied to the old role
+	if roleChanged {
+		if err := s.Revocations.Publish(c.UserContext(), id, revocation.ReasonRoleChanged); err != nil {
+			s.Log.Warnf("failed to broadcast revocation on role change: %v", err)
+		}
 
-			// Invalidate old cache and set new one with new session ID
-			if invalidateErr := s.SessionService.InvalidateSession(c.Context(), id); invalidateErr != nil {
-				s.Log.Warn("Failed to invalidate old cache", "error", invalidateErr)
-			}
+		s.audit(c, &currentUser.ID, model.AuditActionRoleChanged, map[string]any{
+			"from": currentUser.Role,
+			"to":   req.Role.Value,
+		})
+	}
 
-			// Get updated user data
-			updatedUser, err := s.GetUserByID(c, id)
-			if err != nil {
-				return nil, err
-			}
+	// The update dropped the cached session (SESS-03). A role change also regenerates the session ID
+	// for security (SESS-07 privilege elevation).
+	if s.SessionService != nil && roleChanged {
+		bytes := make([]byte, 32)
+		if _, err := rand.Read(bytes); err != nil {
+			s.Log.Warn("Failed to generate new session ID, using cache invalidation only", "error", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Session update failed")
+		}
+		newSessionID := base64.URLEncoding.EncodeToString(bytes)
 
-			// Cache user with new session ID
-			if cacheErr := s.SessionService.CacheUserSession(c.Context(), id, updatedUser); cacheErr != nil {
-				s.Log.Warn("Failed to cache user with new session", "error", cacheErr)
-			}
+		// Get updated user data
+		updatedUser, err := s.GetUserByID(c, id)
+		if err != nil {
+			return nil, err
+		}
 
-			// Update session cookie
-			c.Cookie(&fiber.Cookie{
-				Name:     "session_id",
-				Value:    newSessionID,
-				MaxAge:   config.SessionCacheTTL * 60, // Convert minutes to seconds
-				Path:     "/",
-				Secure:   config.IsProd,
-				HTTPOnly: true,
-				SameSite: "Lax",
-			})
-		} else {
-			// Profile changed but not role - just invalidate cache (SESS-03)
-			if invalidateErr := s.SessionService.InvalidateSession(c.Context(), id); invalidateErr != nil {
-				s.Log.Warn("Failed to invalidate cache on user update", "error", invalidateErr)
-			}
+		// Cache user with new session ID
+		if cacheErr := s.SessionService.CacheUserSession(c.UserContext(), id, updatedUser); cacheErr != nil {
+			s.Log.Warn("Failed to cache user with new session", "error", cacheErr)
 		}
-	}
 
-	user, err := s.GetUserByID(c, id)
-	if err != nil {
-		return nil, err
+		// Update session cookie
+		c.Cookie(&fiber.Cookie{
+			Name:     "session_id",
+			Value:    newSessionID,
+			MaxAge:   config.SessionCacheTTL * 60, // Convert minutes to seconds
+			Path:     "/",
+			Secure:   config.IsProd,
+			HTTPOnly: true,
+			SameSite: "Lax",
+		})
 	}
 
-	return user, result.Error
+	return s.GetUserByID(c, id)
 }
 
 func (s *userService) UpdatePassOrVerify(c *fiber.Ctx, req *validation.UpdatePassOrVerify, id string) error {
@@ -256,10 +457,6 @@ func (s *userService) UpdatePassOrVerify(c *fiber.Ctx, req *validation.UpdatePas
 		return err
 	}
 
-	if req.Password == "" && !req.VerifiedEmail {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid Request")
-	}
-
 	if req.Password != "" {
 		hashedPassword, err := utils.HashPassword(req.Password)
 		if err != nil {
@@ -273,7 +470,7 @@ func (s *userService) UpdatePassOrVerify(c *fiber.Ctx, req *validation.UpdatePas
 		VerifiedEmail: req.VerifiedEmail,
 	}
 
-	result := s.DB.WithContext(c.Context()).Where("id = ?", id).Updates(updateBody)
+	result := s.DB.WithContext(c.UserContext()).Where("id = ?", id).Updates(updateBody)
 
 	if result.RowsAffected == 0 {
 		return fiber.NewError(fiber.StatusNotFound, "User not found")
@@ -281,23 +478,38 @@ func (s *userService) UpdatePassOrVerify(c *fiber.Ctx, req *validation.UpdatePas
 
 	if result.Error != nil {
 		s.Log.Errorf("Failed to update user password or verifiedEmail: %+v", result.Error)
+		return result.Error
 	}
 
-	// Invalidate API response cache after successful password/verification update
-	if result.Error == nil && s.CacheInvalidator != nil {
-		if err := s.CacheInvalidator.InvalidateUserRelatedCache(c.Context(), id); err != nil {
-			s.Log.Warnf("failed to invalidate user cache after password change: %v", err)
-			// Don't fail the operation - cache invalidation is best-effort
+	if req.Password != "" {
+		if userID, err := uuid.Parse(id); err == nil {
+			s.audit(c, &userID, model.AuditActionPasswordChanged, map[string]any{"reset": true})
 		}
 	}
 
-	return result.Error
+	return nil
 }
 
+// DeleteUser deletes the account for good, unless it is under legal hold
 func (s *userService) DeleteUser(c *fiber.Ctx, id string) error {
+	if err := checkLegalHold(c.UserContext(), s.DB, id); err != nil {
+		if !errors.Is(err, ErrLegalHold) {
+			s.Log.Errorf("Failed to check legal hold: %+v", err)
+		}
+		return err
+	}
+
+	if dryrun.Requested(c) {
+		return s.previewDelete(c, id)
+	}
+
 	user := new(model.User)
 
-	result := s.DB.WithContext(c.Context()).Delete(user, "id = ?", id)
+	result := s.DB.WithContext(c.UserContext()).Delete(user, "id = ?", id)
+
+	if legalHoldViolation(result.Error) {
+		return ErrLegalHold
+	}
 
 	if result.RowsAffected == 0 {
 		return fiber.NewError(fiber.StatusNotFound, "User not found")
@@ -307,25 +519,246 @@ func (s *userService) DeleteUser(c *fiber.Ctx, id string) error {
 		s.Log.Errorf("Failed to delete user: %+v", result.Error)
 	}
 
-	// Invalidate API response cache after successful deletion
-	if result.Error == nil && s.CacheInvalidator != nil {
-		if err := s.CacheInvalidator.InvalidateUserRelatedCache(c.Context(), id); err != nil {
-			s.Log.Warnf("failed to invalidate user cache on deletion: %v", err)
-			// Don't fail deletion - graceful degradation
-		}
+	// Deleted users stay rejected without hitting the database while their tokens linger
+	if result.Error == nil {
+		s.NegativeCache.MarkMissing(c.UserContext(), cache.NegativeKindUserID, id)
 	}
 
-	// Invalidate cache after successful deletion (SESS-04)
-	if s.SessionService != nil {
-		if invalidateErr := s.SessionService.InvalidateSession(c.Context(), id); invalidateErr != nil {
-			s.Log.Warn("Failed to invalidate cache on user deletion", "error", invalidateErr)
-			// Don't fail deletion - graceful degradation
+	// Revoke the user on every instance, not just the shared session key
+	if result.Error == nil {
+		if err := s.Revocations.Publish(c.UserContext(), id, revocation.ReasonDeleted); err != nil {
+			s.Log.Warnf("failed to broadcast revocation on deletion: %v", err)
 		}
 	}
 
 	return result.Error
 }
 
+// previewUpdate reports the columns an update would change, failing as the update would when the
+// user is gone or a tested field changed. Unique constraints are only checked by the update itself.
+func (s *userService) previewUpdate(query *gorm.DB, currentUser *model.User, columns map[string]any, tested bool) error {
+	var matched int64
+	if err := query.Count(&matched).Error; err != nil {
+		s.Log.Errorf("Failed to dry-run user update: %+v", err)
+		return err
+	}
+	if matched == 0 {
+		return unmatchedUpdateError(tested)
+	}
+
+	current := userPatchDocument(currentUser)
+	changes := make(map[string]any, len(columns))
+	for column, value := range columns {
+		if column == "password" {
+			changes[column] = model.FieldChange{Redacted: true}
+			continue
+		}
+		changes[column] = model.FieldChange{From: current[column], To: value}
+	}
+
+	return dryrun.Stop(response.DryRun{
+		Action:   "user.update",
+		Affected: map[string]int64{"users": 1},
+		Sample:   []string{currentUser.ID.String()},
+		Changes:  changes,
+	})
+}
+
+// previewDelete counts the user and the rows deleted along with them
+func (s *userService) previewDelete(c *fiber.Ctx, id string) error {
+	user, err := s.GetUserByID(c, id)
+	if err != nil {
+		return err
+	}
+
+	affected := map[string]int64{"users": 1}
+	for kind, related := range map[string]any{
+		"tokens": new(model.Token), "api_tokens": new(model.APIToken),
+		"user_tags": new(model.UserTag), "user_revisions": new(model.UserRevision),
+		"subscriptions": new(model.Subscription),
+	} {
+		var count int64
+		if err := s.DB.WithContext(c.UserContext()).Model(related).Where("user_id = ?", id).Count(&count).Error; err != nil {
+			s.Log.Errorf("Failed to dry-run user deletion: %+v", err)
+			return err
+		}
+		affected[kind] = count
+	}
+
+	return dryrun.Stop(response.DryRun{
+		Action:   "user.delete",
+		Affected: affected,
+		Sample:   []string{user.ID.String()},
+	})
+}
+
+// unmatchedUpdateError explains an update that matched no row
+func unmatchedUpdateError(tested bool) error {
+	if tested {
+		return fiber.NewError(fiber.StatusConflict, "User was changed since the patch was tested")
+	}
+	return fiber.NewError(fiber.StatusNotFound, "User not found")
+}
+
+// SuspendUser deactivates the account without deleting it: the user is revoked on every instance and
+// their cached session dropped, and sign-ins are refused until the account is reactivated
+func (s *userService) SuspendUser(c *fiber.Ctx, id string) (*model.User, error) {
+	if actor, ok := c.Locals("user").(*model.User); ok && actor.ID.String() == id {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "You cannot suspend your own account")
+	}
+
+	user, err := s.setActive(c, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Revocations.Publish(c.UserContext(), id, revocation.ReasonSuspended); err != nil {
+		s.Log.Warnf("failed to broadcast revocation on suspension: %v", err)
+	}
+
+	s.audit(c, &user.ID, model.AuditActionSuspended, map[string]any{})
+
+	return user, nil
+}
+
+// ReactivateUser lets a suspended user sign in again; tokens revoked by the suspension stay revoked
+func (s *userService) ReactivateUser(c *fiber.Ctx, id string) (*model.User, error) {
+	user, err := s.setActive(c, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(c, &user.ID, model.AuditActionReactivated, map[string]any{})
+
+	return user, nil
+}
+
+// setActive flips is_active and drops the caches holding the previous state
+func (s *userService) setActive(c *fiber.Ctx, id string, active bool) (*model.User, error) {
+	user, err := s.GetUserByID(c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsActive == active {
+		if active {
+			return nil, fiber.NewError(fiber.StatusConflict, "User is not suspended")
+		}
+		return nil, fiber.NewError(fiber.StatusConflict, "User is already suspended")
+	}
+
+	if err := s.DB.WithContext(c.UserContext()).Model(user).Update("is_active", active).Error; err != nil {
+		s.Log.Errorf("Failed to update user status: %+v", err)
+		return nil, err
+	}
+	user.IsActive = active
+
+	return user, nil
+}
+
+// GetUserRevisions returns a page of the changes made to the user, newest first
+func (s *userService) GetUserRevisions(
+	c *fiber.Ctx, id string, params *validation.QueryUserRevisions,
+) ([]model.UserRevision, int64, error) {
+	if err := s.Validate.Struct(params); err != nil {
+		return nil, 0, err
+	}
+
+	if _, err := s.GetUserByID(c, id); err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []model.UserRevision
+	var totalResults int64
+
+	query := s.DB.WithContext(c.UserContext()).Model(new(model.UserRevision)).Where("user_id = ?", id).
+		Session(&gorm.Session{})
+
+	if err := query.Count(&totalResults).Error; err != nil {
+		s.Log.Errorf("Failed to count user revisions: %+v", err)
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	if err := query.Order("created_at DESC, id DESC").Limit(params.Limit).Offset(offset).Find(&revisions).Error; err != nil {
+		s.Log.Errorf("Failed to get user revisions: %+v", err)
+		return nil, 0, err
+	}
+
+	return revisions, totalResults, nil
+}
+
+// RestoreUserRevision puts the user's profile back the way it was right after the revision. The
+// rollback goes through the regular update, so it is itself recorded as a revision.
+func (s *userService) RestoreUserRevision(c *fiber.Ctx, id, revisionID string) (*model.User, error) {
+	currentUser, err := s.GetUserByID(c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []model.UserRevision
+	err = s.DB.WithContext(c.UserContext()).Where("user_id = ?", id).Order("created_at, id").Find(&revisions).Error
+	if err != nil {
+		s.Log.Errorf("Failed to get user revisions: %+v", err)
+		return nil, err
+	}
+
+	target := slices.IndexFunc(revisions, func(r model.UserRevision) bool { return r.ID.String() == revisionID })
+	if target < 0 {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Revision not found")
+	}
+
+	history := make([]map[string]model.FieldChange, len(revisions))
+	for i := range revisions {
+		if err := json.Unmarshal([]byte(revisions[i].Changes), &history[i]); err != nil {
+			s.Log.Errorf("Failed to decode user revision %s: %+v", revisions[i].ID, err)
+			return nil, err
+		}
+	}
+	state := revision.State(history, target)
+
+	// Only columns that differ from today are set, so the rollback records just those
+	req := new(validation.UpdateUser)
+	fields := map[string]*validation.Optional[string]{
+		"name": &req.Name, "username": &req.Username, "email": &req.Email, "role": &req.Role, "timezone": &req.Timezone,
+	}
+	current := userPatchDocument(currentUser)
+	for _, column := range restorableColumns {
+		value, ok := state[column]
+		if !ok || value == current[column] {
+			continue
+		}
+		if text, ok := value.(string); ok {
+			*fields[column] = validation.Some(text)
+		} else {
+			*fields[column] = validation.Null[string]()
+		}
+	}
+
+	if len(req.Fields()) == 0 {
+		return currentUser, nil
+	}
+
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	restoredFrom := revisions[target].ID
+	c.SetUserContext(revision.WithRestoredFrom(c.UserContext(), restoredFrom))
+
+	user, err := s.changeUser(c, currentUser, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(c, &user.ID, model.AuditActionUserRestored, map[string]any{
+		"revision_id": restoredFrom,
+		"fields":      req.Fields(),
+	})
+
+	return user, nil
+}
+
 func (s *userService) CreateGoogleUser(c *fiber.Ctx, req *validation.GoogleLogin) (*model.User, error) {
 	if err := s.Validate.Struct(req); err != nil {
 		return nil, err
@@ -334,17 +767,23 @@ func (s *userService) CreateGoogleUser(c *fiber.Ctx, req *validation.GoogleLogin
 	userFromDB, err := s.GetUserByEmail(c, req.Email)
 	if err != nil {
 		if err.Error() == "User not found" {
+			if err := checkEmailDomain(c.UserContext(), s.EmailDomains, req.Email); err != nil {
+				return nil, err
+			}
+
 			user := &model.User{
 				Name:          req.Name,
 				Email:         req.Email,
 				VerifiedEmail: req.VerifiedEmail,
 			}
 
-			if createErr := s.DB.WithContext(c.Context()).Create(user).Error; createErr != nil {
+			if createErr := s.DB.WithContext(c.UserContext()).Create(user).Error; createErr != nil {
 				s.Log.Errorf("Failed to create user: %+v", createErr)
 				return nil, createErr
 			}
 
+			s.NegativeCache.Forget(c.UserContext(), cache.NegativeKindUserEmail, user.Email)
+
 			return user, nil
 		}
 
@@ -352,10 +791,180 @@ func (s *userService) CreateGoogleUser(c *fiber.Ctx, req *validation.GoogleLogin
 	}
 
 	userFromDB.VerifiedEmail = req.VerifiedEmail
-	if updateErr := s.DB.WithContext(c.Context()).Save(userFromDB).Error; updateErr != nil {
+	if updateErr := s.DB.WithContext(c.UserContext()).Save(userFromDB).Error; updateErr != nil {
+		s.Log.Errorf("Failed to update user: %+v", updateErr)
+		return nil, updateErr
+	}
+
+	return userFromDB, nil
+}
+
+// CreateAppleUser finds the user by Apple subject, links an existing account with the same verified
+// email, or creates one. Apple sends the name only on the first authorization, so a later login
+// without it keeps the stored name.
+func (s *userService) CreateAppleUser(c *fiber.Ctx, req *validation.AppleLogin) (*model.User, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	user := new(model.User)
+	err := s.DB.WithContext(c.UserContext()).First(user, "apple_id = ?", req.Subject).Error
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.Log.Errorf("Failed get user by apple id: %+v", err)
+		return nil, err
+	}
+
+	if req.Email == "" {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Apple did not share an email address")
+	}
+
+	userFromDB, err := s.GetUserByEmail(c, req.Email)
+	if err != nil {
+		if err.Error() != "User not found" {
+			return nil, err
+		}
+
+		if err := checkEmailDomain(c.UserContext(), s.EmailDomains, req.Email); err != nil {
+			return nil, err
+		}
+
+		name := req.Name
+		if name == "" {
+			name, _, _ = strings.Cut(req.Email, "@")
+		}
+
+		user = &model.User{
+			Name:          name,
+			Email:         req.Email,
+			VerifiedEmail: req.VerifiedEmail,
+			AppleID:       &req.Subject,
+		}
+		if createErr := s.DB.WithContext(c.UserContext()).Create(user).Error; createErr != nil {
+			s.Log.Errorf("Failed to create user: %+v", createErr)
+			return nil, createErr
+		}
+
+		s.NegativeCache.Forget(c.UserContext(), cache.NegativeKindUserEmail, user.Email)
+
+		return user, nil
+	}
+
+	// Only a verified Apple address may take over an existing account
+	if !req.VerifiedEmail {
+		return nil, fiber.NewError(fiber.StatusConflict, "Email already taken")
+	}
+
+	userFromDB.AppleID = &req.Subject
+	userFromDB.VerifiedEmail = true
+	if updateErr := s.DB.WithContext(c.UserContext()).Save(userFromDB).Error; updateErr != nil {
 		s.Log.Errorf("Failed to update user: %+v", updateErr)
 		return nil, updateErr
 	}
 
 	return userFromDB, nil
 }
+
+// filterUsers narrows query to the users matching filter
+func filterUsers(query *gorm.DB, filter *validation.UserFilter) *gorm.DB {
+	if search := filter.Search; search != "" {
+		query = query.Where("name LIKE ? OR email LIKE ? OR username LIKE ? OR role LIKE ?",
+			"%"+search+"%", "%"+search+"%", "%"+search+"%", "%"+search+"%")
+	}
+
+	if username := filter.Username; username != "" {
+		query = query.Where("LOWER(username) = LOWER(?)", username)
+	}
+
+	if userType := filter.Type; userType != "" {
+		query = query.Where("type = ?", userType)
+	}
+
+	if tag := filter.Tag; tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM user_tags WHERE user_tags.user_id = users.id AND user_tags.tag = ?)", tag)
+	}
+
+	return query
+}
+
+// isReservedUsername reports whether username is on the reserved list, ignoring case
+func isReservedUsername(username string) bool {
+	return username != "" && slices.Contains(config.Usernames.Reserved, strings.ToLower(username))
+}
+
+// usernameTaken reports whether any user has the username, ignoring case
+func usernameTaken(ctx context.Context, db *gorm.DB, username string) (bool, error) {
+	var count int64
+	err := db.WithContext(ctx).Model(new(model.User)).Where("LOWER(username) = LOWER(?)", username).Count(&count).Error
+	return count > 0, err
+}
+
+// duplicateUserError tells a username conflict from an email conflict; the translated unique
+// violation no longer says which index it hit
+func duplicateUserError(ctx context.Context, db *gorm.DB, username, emailMessage string) error {
+	if username != "" {
+		if taken, err := usernameTaken(ctx, db, username); err == nil && taken {
+			return ErrUsernameTaken
+		}
+	}
+	return fiber.NewError(fiber.StatusConflict, emailMessage)
+}
+
+// optionalUsername maps an omitted username to NULL, which the unique index allows many times
+func optionalUsername(username string) *string {
+	if username == "" {
+		return nil
+	}
+	return &username
+}
+
+// userPatchDocument is the document patches on a user apply to. The password is write-only, so it
+// reads as null.
+func userPatchDocument(user *model.User) map[string]any {
+	doc := map[string]any{
+		"name": user.Name, "username": nil, "email": user.Email, "password": nil, "role": user.Role, "timezone": nil,
+	}
+	if user.Username != nil {
+		doc["username"] = *user.Username
+	}
+	if user.Timezone != nil {
+		doc["timezone"] = *user.Timezone
+	}
+	return doc
+}
+
+// optionalTimezone maps an omitted timezone to NULL, which falls back to the default timezone
+func optionalTimezone(timezone string) *string {
+	if timezone == "" {
+		return nil
+	}
+	return &timezone
+}
+
+// userLocation returns the user's timezone, or the default timezone if they have none
+func userLocation(user *model.User) *time.Location {
+	if user == nil || user.Timezone == nil {
+		return config.DefaultTimezone
+	}
+
+	location, err := time.LoadLocation(*user.Timezone)
+	if err != nil {
+		return config.DefaultTimezone
+	}
+	return location
+}
+
+// audit records a user management event with the acting user, if auditing is configured
+func (s *userService) audit(c *fiber.Ctx, userID *uuid.UUID, action string, metadata map[string]any) {
+	if s.AuditService == nil {
+		return
+	}
+
+	if actor, ok := c.Locals("user").(*model.User); ok {
+		metadata["actor_id"] = actor.ID
+	}
+
+	s.AuditService.Record(c, userID, action, metadata)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"app/src/cache"
+	"app/src/model"
+	"app/src/utils"
+	"app/src/validation"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserTagService interface {
+	ListTags(c *fiber.Ctx, userID string) ([]string, error)
+	AddTags(c *fiber.Ctx, userID string, req *validation.UserTags) ([]string, error)
+	RemoveTag(c *fiber.Ctx, userID, tag string) ([]string, error)
+}
+
+type userTagService struct {
+	Log              *logrus.Logger
+	DB               *gorm.DB
+	Validate         *validator.Validate
+	UserService      UserService
+	SessionService   SessionService
+	CacheInvalidator *cache.CacheInvalidator
+}
+
+func NewUserTagService(
+	db *gorm.DB, validate *validator.Validate, userService UserService,
+	sessionService SessionService, cacheInvalidator *cache.CacheInvalidator,
+) UserTagService {
+	return &userTagService{
+		Log:              utils.Log,
+		DB:               db,
+		Validate:         validate,
+		UserService:      userService,
+		SessionService:   sessionService,
+		CacheInvalidator: cacheInvalidator,
+	}
+}
+
+// ListTags returns the user's tags in alphabetical order
+func (s *userTagService) ListTags(c *fiber.Ctx, userID string) ([]string, error) {
+	if _, err := s.UserService.GetUserByID(c, userID); err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	err := s.DB.WithContext(c.UserContext()).
+		Model(new(model.UserTag)).
+		Where("user_id = ?", userID).
+		Order("tag").
+		Pluck("tag", &tags).Error
+
+	if err != nil {
+		s.Log.Errorf("Failed to list user tags: %+v", err)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// AddTags attaches the tags to the user; tags the user already has are left as they are
+func (s *userTagService) AddTags(c *fiber.Ctx, userID string, req *validation.UserTags) ([]string, error) {
+	for i, tag := range req.Tags {
+		req.Tags[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserService.GetUserByID(c, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(req.Tags)
+	tags := make([]model.UserTag, 0, len(req.Tags))
+	for _, tag := range slices.Compact(req.Tags) {
+		tags = append(tags, model.UserTag{UserID: user.ID, Tag: tag})
+	}
+
+	if err := s.DB.WithContext(c.UserContext()).Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		s.Log.Errorf("Failed to add user tags: %+v", err)
+		return nil, err
+	}
+
+	return s.ListTags(c, userID)
+}
+
+// RemoveTag detaches a tag from the user
+func (s *userTagService) RemoveTag(c *fiber.Ctx, userID, tag string) ([]string, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	result := s.DB.WithContext(c.UserContext()).
+		Where("user_id = ? AND tag = ?", userID, strings.ToLower(tag)).
+		Delete(new(model.UserTag))
+
+	if result.Error != nil {
+		s.Log.Errorf("Failed to remove user tag: %+v", result.Error)
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Tag not found")
+	}
+
+	return s.ListTags(c, userID)
+}
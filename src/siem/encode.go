@@ -0,0 +1,87 @@
+package siem
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Export formats
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// CSVColumns is the header of CSV exports
+var CSVColumns = []string{"id", "time", "action", "user_id", "actor_type", "ip", "user_agent", "request_id", "metadata"}
+
+// Encoder writes events as CSV or NDJSON, one row or line per event
+type Encoder struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+// NewEncoder writes events to w in format, starting CSV with its header
+func NewEncoder(w io.Writer, format string) (*Encoder, error) {
+	switch format {
+	case FormatCSV:
+		encoder := &Encoder{csv: csv.NewWriter(w)}
+		return encoder, encoder.csv.Write(CSVColumns)
+	case FormatNDJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &Encoder{json: encoder}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// Encode writes one event
+func (e *Encoder) Encode(event Event) error {
+	if e.json != nil {
+		return e.json.Encode(event)
+	}
+
+	var userID string
+	if event.UserID != nil {
+		userID = event.UserID.String()
+	}
+	return e.csv.Write([]string{
+		event.ID.String(),
+		event.Time.Format(time.RFC3339Nano),
+		cell(event.Action),
+		userID,
+		cell(deref(event.ActorType)),
+		cell(event.IP),
+		cell(event.UserAgent),
+		cell(deref(event.RequestID)),
+		cell(string(event.Metadata)),
+	})
+}
+
+// Flush writes buffered CSV rows; NDJSON lines are written as they are encoded
+func (e *Encoder) Flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+// cell keeps spreadsheets from running a value as a formula, as user agents and request IDs come
+// from clients
+func cell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
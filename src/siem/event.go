@@ -0,0 +1,49 @@
+// Package siem streams the audit log to a SIEM. The audit_logs table is the buffer: a forwarder
+// reads the entries recorded after the last one delivered, in order, and sends them in batches to
+// a syslog collector, an HTTPS webhook or a Kafka topic. A sink that is down or slow holds the
+// forwarder back without losing entries or slowing the requests recording them. Delivery is at
+// least once, so a SIEM should deduplicate entries by ID.
+package siem
+
+import (
+	"encoding/json"
+	"time"
+
+	"app/src/model"
+
+	"github.com/google/uuid"
+)
+
+// Event is an audit entry as forwarded and exported
+type Event struct {
+	ID        uuid.UUID  `json:"id"`
+	Time      time.Time  `json:"time"`
+	Action    string     `json:"action"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	ActorType *string    `json:"actor_type,omitempty"`
+	IP        string     `json:"ip,omitempty"`
+	UserAgent string     `json:"user_agent,omitempty"`
+	RequestID *string    `json:"request_id,omitempty"`
+	// Metadata is the JSON object recorded with the entry
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+// NewEvent converts an audit entry
+func NewEvent(entry *model.AuditLog) Event {
+	metadata := json.RawMessage(entry.Metadata)
+	if !json.Valid(metadata) {
+		metadata = json.RawMessage("{}")
+	}
+
+	return Event{
+		ID:        entry.ID,
+		Time:      entry.CreatedAt.UTC(),
+		Action:    entry.Action,
+		UserID:    entry.UserID,
+		ActorType: entry.ActorType,
+		IP:        entry.IP,
+		UserAgent: entry.UserAgent,
+		RequestID: entry.RequestID,
+		Metadata:  metadata,
+	}
+}
@@ -0,0 +1,191 @@
+package siem
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/src/clock"
+	"app/src/config"
+	"app/src/metrics"
+	"app/src/model"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// settle is how old an entry must be before it is read. Entries are read in the order they were
+// recorded, and an insert may commit after a later one; waiting lets it land before the cursor
+// moves past it.
+const settle = 2 * time.Second
+
+// Bounds of the wait between attempts at a batch the sink refused
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = time.Minute
+)
+
+// cursor is the position of an entry in the order entries are forwarded
+type cursor struct {
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+// Forwarder sends the audit log to a sink as it grows
+type Forwarder struct {
+	db    *gorm.DB
+	sink  Sink
+	cfg   config.SIEMConfig
+	clock clock.Clock
+}
+
+// NewForwarder creates a forwarder of the audit log to sink
+func NewForwarder(db *gorm.DB, sink Sink, cfg config.SIEMConfig, clk clock.Clock) *Forwarder {
+	return &Forwarder{db: db, sink: sink, cfg: cfg, clock: clock.OrSystem(clk)}
+}
+
+// Run forwards entries until ctx is done. One goroutine reads the entries after the cursor into a
+// buffer of cfg.BufferSize, another sends them in batches and moves the cursor past each batch
+// delivered. A sink that fails is retried with backoff; meanwhile the buffer fills up and reading
+// waits, so a sink that is down costs neither memory nor queries. A sink forwarded to for the
+// first time starts with the entries recorded from then on.
+func (f *Forwarder) Run(ctx context.Context) error {
+	from, err := f.loadCursor(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	buffer := make(chan Event, f.cfg.BufferSize)
+	reading := make(chan struct{})
+	go func() {
+		defer close(reading)
+		f.read(ctx, from, buffer)
+	}()
+
+	f.send(ctx, buffer)
+	cancel()
+	<-reading
+	metrics.SIEMBuffered(f.cfg.Sink, 0)
+	return nil
+}
+
+// loadCursor returns the cursor of the sink, starting it at the current time on first use
+func (f *Forwarder) loadCursor(ctx context.Context) (cursor, error) {
+	saved := new(model.SIEMCursor)
+	err := f.db.WithContext(ctx).First(saved, "sink = ?", f.cfg.Sink).Error
+	if err == nil {
+		return cursor{createdAt: saved.LastCreatedAt, id: saved.LastID}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return cursor{}, err
+	}
+
+	start := cursor{createdAt: f.clock.Now().UTC().Add(-settle)}
+	return start, f.saveCursor(ctx, start)
+}
+
+func (f *Forwarder) saveCursor(ctx context.Context, at cursor) error {
+	return f.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&model.SIEMCursor{
+		Sink: f.cfg.Sink, LastCreatedAt: at.createdAt, LastID: at.id,
+	}).Error
+}
+
+// read pages through the entries after from into buffer, waiting cfg.PollInterval whenever it is
+// done with the entries recorded so far or the database fails
+func (f *Forwarder) read(ctx context.Context, from cursor, buffer chan<- Event) {
+	for ctx.Err() == nil {
+		var entries []*model.AuditLog
+		err := f.db.WithContext(ctx).
+			Where("(created_at, id) > (?, ?) AND created_at < ?", from.createdAt, from.id, f.clock.Now().UTC().Add(-settle)).
+			Order("created_at, id").
+			Limit(f.cfg.BatchSize).
+			Find(&entries).Error
+		if err != nil && ctx.Err() == nil {
+			logrus.Warnf("Failed to read audit logs to forward to the SIEM: %v", err)
+		}
+
+		for _, entry := range entries {
+			select {
+			case buffer <- NewEvent(entry):
+			case <-ctx.Done():
+				return
+			}
+			from = cursor{createdAt: entry.CreatedAt, id: entry.ID}
+		}
+		metrics.SIEMBuffered(f.cfg.Sink, len(buffer))
+
+		if err == nil && len(entries) == f.cfg.BatchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(f.cfg.PollInterval):
+		}
+	}
+}
+
+// send delivers the buffered events in batches of up to cfg.BatchSize, without waiting for a
+// batch to fill up
+func (f *Forwarder) send(ctx context.Context, buffer <-chan Event) {
+	batch := make([]Event, 0, f.cfg.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-buffer:
+			batch = append(batch[:0], event)
+		}
+	drain:
+		for len(batch) < f.cfg.BatchSize {
+			select {
+			case event := <-buffer:
+				batch = append(batch, event)
+			default:
+				break drain
+			}
+		}
+		metrics.SIEMBuffered(f.cfg.Sink, len(buffer))
+
+		if !f.deliver(ctx, batch) {
+			return
+		}
+
+		last := batch[len(batch)-1]
+		metrics.SIEMForwarded(f.cfg.Sink, len(batch), f.clock.Now().Sub(last.Time).Seconds())
+		// A cursor left behind only sends the batch again after a restart
+		if err := f.saveCursor(ctx, cursor{createdAt: last.Time, id: last.ID}); err != nil && ctx.Err() == nil {
+			logrus.Warnf("Failed to save the SIEM cursor: %v", err)
+		}
+	}
+}
+
+// deliver sends the batch until the sink takes it, reporting false if ctx is done first
+func (f *Forwarder) deliver(ctx context.Context, batch []Event) bool {
+	delay := retryBaseDelay
+	for {
+		sendCtx, cancel := context.WithTimeout(ctx, f.cfg.Timeout)
+		err := f.sink.Send(sendCtx, batch)
+		cancel()
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		metrics.SIEMFailed(f.cfg.Sink)
+		logrus.Warnf("Failed to forward %d audit logs to the SIEM, retrying in %s: %v", len(batch), delay, err)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
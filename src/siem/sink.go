@@ -0,0 +1,32 @@
+package siem
+
+import (
+	"context"
+	"fmt"
+
+	"app/src/config"
+	"app/src/httpclient"
+)
+
+// Sink delivers batches of events to a SIEM. A batch that fails is sent again, so Send must leave
+// nothing half-done that a retry cannot repair, and the SIEM may receive an event twice.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// NewSink creates the sink selected by cfg.Sink
+func NewSink(cfg config.SIEMConfig) (Sink, error) {
+	switch cfg.Sink {
+	case config.SIEMSyslog:
+		return NewSyslogSink(cfg.SyslogNetwork, cfg.SyslogAddress, cfg.Timeout), nil
+	case config.SIEMWebhook, config.SIEMKafka:
+		// Batches are retried by the forwarder, with backoff for as long as the sink is down
+		client := httpclient.New("siem", httpclient.WithTimeout(cfg.Timeout), httpclient.WithRetries(0))
+		if cfg.Sink == config.SIEMKafka {
+			return NewKafkaSink(cfg.KafkaRESTURL, cfg.KafkaTopic, client), nil
+		}
+		return NewWebhookSink(cfg.WebhookURL, cfg.WebhookAuthorization, client), nil
+	}
+	return nil, fmt.Errorf("unknown SIEM sink %q", cfg.Sink)
+}
@@ -0,0 +1,126 @@
+package siem
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// syslogPriority is facility authpriv (10) at severity informational (6), per RFC 5424
+const syslogPriority = 10*8 + 6
+
+// syslogAppName is the APP-NAME of the messages
+const syslogAppName = "fiber-api"
+
+// SyslogSink sends each event as an RFC 5424 message whose MSGID is the action and whose body is
+// the event as JSON. Over tcp and tls messages are framed by octet counting (RFC 6587); over udp
+// each message is one datagram.
+type SyslogSink struct {
+	network  string
+	address  string
+	timeout  time.Duration
+	hostname string
+	conn     net.Conn
+}
+
+// NewSyslogSink creates a sink for the collector at address, reached over udp, tcp or tls
+func NewSyslogSink(network, address string, timeout time.Duration) *SyslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{network: network, address: address, timeout: timeout, hostname: hostname}
+}
+
+// Send writes the events over the connection, dialing it first if needed. A failed write drops the
+// connection, so the retry starts on a new one.
+func (s *SyslogSink) Send(ctx context.Context, events []Event) error {
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	deadline := time.Now().Add(s.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := s.conn.SetWriteDeadline(deadline); err != nil {
+		return s.fail(err)
+	}
+
+	for _, event := range events {
+		message, err := s.format(event)
+		if err != nil {
+			return err
+		}
+		if s.network != "udp" {
+			message = append([]byte(fmt.Sprintf("%d ", len(message))), message...)
+		}
+		if _, err := s.conn.Write(message); err != nil {
+			return s.fail(err)
+		}
+	}
+	return nil
+}
+
+// Close closes the connection to the collector
+func (s *SyslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	if s.network == "tls" {
+		host, _, _ := net.SplitHostPort(s.address)
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		return tlsDialer.DialContext(ctx, "tcp", s.address)
+	}
+	return dialer.DialContext(ctx, s.network, s.address)
+}
+
+func (s *SyslogSink) fail(err error) error {
+	_ = s.Close()
+	return err
+}
+
+// format renders the RFC 5424 message of an event, without structured data
+func (s *SyslogSink) format(event Event) ([]byte, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("<%d>1 %s %s %s %d %s - ", syslogPriority, event.Time.Format(time.RFC3339Nano),
+		s.hostname, syslogAppName, os.Getpid(), messageID(event.Action))
+	return append([]byte(header), body...), nil
+}
+
+// messageID fits an action into a MSGID: at most 32 printable ASCII characters without spaces
+func messageID(action string) string {
+	id := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, action)
+	if id == "" {
+		return "-"
+	}
+	if len(id) > 32 {
+		id = id[:32]
+	}
+	return id
+}
@@ -0,0 +1,72 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookSink posts each batch to a URL as NDJSON, one event per line
+type WebhookSink struct {
+	url           string
+	authorization string
+	client        *http.Client
+}
+
+// NewWebhookSink creates a sink posting to url, sending authorization (e.g. "Bearer <token>" or
+// "Splunk <token>") as the Authorization header when it is not empty
+func NewWebhookSink(url, authorization string, client *http.Client) *WebhookSink {
+	return &WebhookSink{url: url, authorization: authorization, client: client}
+}
+
+// Send posts the batch; any status but 2xx fails it
+func (s *WebhookSink) Send(ctx context.Context, events []Event) error {
+	var body bytes.Buffer
+	encoder, err := NewEncoder(&body, FormatNDJSON)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+
+	return send(s.client, req, nil)
+}
+
+// Close has nothing to release
+func (s *WebhookSink) Close() error {
+	return nil
+}
+
+// send runs req and fails on any status but 2xx; the body of a 2xx response is read into decode
+// when it is not nil
+func send(client *http.Client, req *http.Request, decode func(io.Reader) error) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s answered %d: %s", req.URL.Host, resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	if decode != nil {
+		return decode(resp.Body)
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
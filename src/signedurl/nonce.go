@@ -0,0 +1,54 @@
+package signedurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/src/redis"
+)
+
+// NonceKeyPrefix is the prefix for consumed one-time URL nonces
+// Format: signedurl:nonce:{nonce}
+const NonceKeyPrefix = "signedurl:nonce:"
+
+// ErrNonceStoreUnavailable is returned when a one-time URL cannot be checked for replay;
+// verification fails closed rather than letting the URL be reused
+var ErrNonceStoreUnavailable = errors.New("nonce store unavailable")
+
+// NonceStore records consumed nonces for one-time URLs
+type NonceStore interface {
+	// Consume marks the nonce as used for ttl and reports whether it was unused before
+	Consume(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// RedisNonceStore shares consumed nonces across instances via SET NX
+type RedisNonceStore struct {
+	redisClient *redis.RedisClient
+}
+
+// NewRedisNonceStore creates a Redis-backed nonce store
+// Returns nil if redisClient is nil (one-time URLs are then rejected)
+func NewRedisNonceStore(redisClient *redis.RedisClient) NonceStore {
+	if redisClient == nil {
+		return nil
+	}
+	return &RedisNonceStore{
+		redisClient: redisClient,
+	}
+}
+
+// Consume atomically claims the nonce
+func (r *RedisNonceStore) Consume(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	if !redis.IsAvailable() {
+		return false, ErrNonceStoreUnavailable
+	}
+
+	fresh, err := r.redisClient.GetClient().SetNX(ctx, r.redisClient.Key(NonceKeyPrefix+nonce), 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume nonce: %w", err)
+	}
+
+	return fresh, nil
+}
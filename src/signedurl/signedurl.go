@@ -0,0 +1,196 @@
+package signedurl
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"app/src/config"
+	"app/src/redis"
+)
+
+// Query parameters added to signed URLs
+const (
+	ParamExpires   = "expires"
+	ParamKeyID     = "kid"
+	ParamNonce     = "nonce"
+	ParamSignature = "signature"
+)
+
+var (
+	// ErrInvalidSignature is returned for tampered, unsigned or malformed URLs
+	ErrInvalidSignature = errors.New("invalid url signature")
+
+	// ErrExpired is returned once the URL's expiry has passed
+	ErrExpired = errors.New("signed url expired")
+
+	// ErrUnknownKey is returned when the URL was signed with a key that has been retired
+	ErrUnknownKey = errors.New("unknown signing key")
+
+	// ErrAlreadyUsed is returned when a one-time URL is presented a second time
+	ErrAlreadyUsed = errors.New("signed url already used")
+)
+
+// Key is a named HMAC secret; the ID is embedded in URLs so old keys keep verifying after rotation
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Signer mints and verifies expiring HMAC-signed URLs
+type Signer struct {
+	active Key
+	keys   map[string][]byte
+	nonces NonceStore
+}
+
+// NewSigner creates a signer that signs with the first key and verifies with any of them.
+// nonces may be nil, in which case one-time URLs cannot be verified.
+func NewSigner(keys []Key, nonces NonceStore) (*Signer, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("signedurl: at least one key is required")
+	}
+
+	byID := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if key.ID == "" || len(key.Secret) == 0 {
+			return nil, errors.New("signedurl: keys need an id and a secret")
+		}
+		if _, ok := byID[key.ID]; ok {
+			return nil, fmt.Errorf("signedurl: duplicate key id %q", key.ID)
+		}
+		byID[key.ID] = key.Secret
+	}
+
+	return &Signer{
+		active: keys[0],
+		keys:   byID,
+		nonces: nonces,
+	}, nil
+}
+
+// Sign returns rawURL with expiry, key id and signature parameters appended.
+// One-time URLs also carry a nonce that is consumed on first successful verification.
+func (s *Signer) Sign(rawURL string, ttl time.Duration, oneTime bool) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Del(ParamSignature)
+	query.Set(ParamExpires, strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+	query.Set(ParamKeyID, s.active.ID)
+	query.Del(ParamNonce)
+	if oneTime {
+		nonce, err := randomNonce()
+		if err != nil {
+			return "", err
+		}
+		query.Set(ParamNonce, nonce)
+	}
+
+	query.Set(ParamSignature, sign(s.active.Secret, u.Path, query))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Verify checks a full URL or a request URI (path and query)
+func (s *Signer) Verify(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	return s.VerifyQuery(ctx, u.Path, u.Query())
+}
+
+// VerifyQuery checks the signature, expiry and, for one-time URLs, consumes the nonce
+func (s *Signer) VerifyQuery(ctx context.Context, path string, query url.Values) error {
+	signature := query.Get(ParamSignature)
+	if signature == "" {
+		return ErrInvalidSignature
+	}
+
+	secret, ok := s.keys[query.Get(ParamKeyID)]
+	if !ok {
+		return ErrUnknownKey
+	}
+
+	unsigned := cloneValues(query)
+	unsigned.Del(ParamSignature)
+	if !hmac.Equal([]byte(signature), []byte(sign(secret, path, unsigned))) {
+		return ErrInvalidSignature
+	}
+
+	expires, err := strconv.ParseInt(query.Get(ParamExpires), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	remaining := time.Until(time.Unix(expires, 0))
+	if remaining <= 0 {
+		return ErrExpired
+	}
+
+	nonce := query.Get(ParamNonce)
+	if nonce == "" {
+		return nil
+	}
+	if s.nonces == nil {
+		return ErrNonceStoreUnavailable
+	}
+
+	// The nonce only needs to be remembered until the URL would have expired anyway
+	fresh, err := s.nonces.Consume(ctx, nonce, remaining)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrAlreadyUsed
+	}
+
+	return nil
+}
+
+// sign computes the signature over the path and the canonical (sorted) query string
+func sign(secret []byte, path string, query url.Values) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(path))
+	mac.Write([]byte{'?'})
+	mac.Write([]byte(query.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for k, v := range values {
+		clone[k] = append([]string(nil), v...)
+	}
+	return clone
+}
+
+func randomNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewFromConfig creates a signer from SIGNED_URL_KEYS with a Redis nonce store
+func NewFromConfig(redisClient *redis.RedisClient) (*Signer, error) {
+	keys := make([]Key, len(config.SignedURLKeys))
+	for i, key := range config.SignedURLKeys {
+		keys[i] = Key{ID: key.ID, Secret: []byte(key.Secret)}
+	}
+
+	return NewSigner(keys, NewRedisNonceStore(redisClient))
+}
@@ -0,0 +1,91 @@
+// Package sms sends text messages over SMS or WhatsApp through Twilio or Vonage, and verifies and
+// decodes the delivery-status callbacks they send back.
+package sms
+
+import (
+	"app/src/config"
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Delivery statuses, normalized across providers
+const (
+	// StatusAccepted means the provider took the message and has not yet handed it on
+	StatusAccepted  = "accepted"
+	StatusSent      = "sent"
+	StatusDelivered = "delivered"
+	// StatusRead is only reported for WhatsApp
+	StatusRead   = "read"
+	StatusFailed = "failed"
+)
+
+// statusRanks orders statuses so a late callback never moves a message back; delivered and failed
+// are final, read only follows delivered
+var statusRanks = map[string]int{
+	StatusAccepted:  1,
+	StatusSent:      2,
+	StatusDelivered: 3,
+	StatusFailed:    3,
+	StatusRead:      4,
+}
+
+var (
+	// ErrInvalidSignature means a callback was not signed with the provider credentials
+	ErrInvalidSignature = errors.New("invalid callback signature")
+
+	// ErrInvalidCallback means a callback could not be decoded
+	ErrInvalidCallback = errors.New("invalid callback payload")
+)
+
+// Message is a text to send to a number in E.164 format
+type Message struct {
+	To       string
+	Text     string
+	WhatsApp bool
+}
+
+// Callback is a delivery-status request received from a provider
+type Callback struct {
+	Header http.Header
+	Body   []byte
+}
+
+// Status reports the delivery status of a sent message
+type Status struct {
+	MessageID string
+	Status    string
+	// Error is the provider's error code or description for failed messages
+	Error string
+}
+
+// Provider sends text messages through a third-party service
+type Provider interface {
+	// Name identifies the provider in callback URLs and stored messages
+	Name() string
+	// Send submits a message and returns the provider's ID for it
+	Send(ctx context.Context, msg Message) (string, error)
+	// ParseCallback verifies a delivery-status callback and decodes it; callbacks for statuses the
+	// app does not track return a nil Status
+	ParseCallback(callback Callback) (*Status, error)
+}
+
+// New creates the configured provider
+// Returns nil if no provider is configured (text channels are then unavailable)
+func New(cfg config.SMSConfig) Provider {
+	switch cfg.Provider {
+	case config.SMSProviderTwilio:
+		return NewTwilio(cfg.TwilioAPIURL, cfg.TwilioAccountSID, cfg.TwilioAuthToken, cfg.From, cfg.WhatsAppFrom,
+			cfg.StatusCallbackURL)
+	case config.SMSProviderVonage:
+		return NewVonage(cfg.VonageAPIURL, cfg.VonageAPIKey, cfg.VonageAPISecret, cfg.VonageSignatureSecret,
+			cfg.From, cfg.WhatsAppFrom)
+	default:
+		return nil
+	}
+}
+
+// Advances reports whether a message may move from one status to another
+func Advances(from, to string) bool {
+	return statusRanks[to] > statusRanks[from]
+}
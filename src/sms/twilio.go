@@ -0,0 +1,141 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // Twilio signs callbacks with HMAC-SHA1
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"app/src/httpclient"
+)
+
+// TwilioSignatureHeader carries the signature of Twilio callbacks
+const TwilioSignatureHeader = "X-Twilio-Signature"
+
+// twilioStatuses maps Twilio message statuses to ours; inbound and in-between statuses are left out
+var twilioStatuses = map[string]string{
+	"accepted":    StatusAccepted,
+	"scheduled":   StatusAccepted,
+	"queued":      StatusAccepted,
+	"sending":     StatusAccepted,
+	"sent":        StatusSent,
+	"delivered":   StatusDelivered,
+	"read":        StatusRead,
+	"undelivered": StatusFailed,
+	"failed":      StatusFailed,
+	"canceled":    StatusFailed,
+}
+
+type twilio struct {
+	baseURL      string
+	accountSID   string
+	authToken    string
+	from         string
+	whatsAppFrom string
+	callbackURL  string
+	client       *http.Client
+}
+
+// NewTwilio creates a Twilio provider. Messages ask for status callbacks at callbackURL, which is
+// also the URL their signatures are checked against; without it no callbacks are sent.
+func NewTwilio(baseURL, accountSID, authToken, from, whatsAppFrom, callbackURL string) Provider {
+	return &twilio{
+		baseURL:      baseURL,
+		accountSID:   accountSID,
+		authToken:    authToken,
+		from:         from,
+		whatsAppFrom: whatsAppFrom,
+		callbackURL:  callbackURL,
+		client:       httpclient.New("twilio"),
+	}
+}
+
+func (p *twilio) Name() string {
+	return "twilio"
+}
+
+func (p *twilio) Send(ctx context.Context, msg Message) (string, error) {
+	form := url.Values{
+		"To":   {msg.To},
+		"From": {p.from},
+		"Body": {msg.Text},
+	}
+	if msg.WhatsApp {
+		form.Set("To", "whatsapp:"+msg.To)
+		form.Set("From", "whatsapp:"+p.whatsAppFrom)
+	}
+	if p.callbackURL != "" {
+		form.Set("StatusCallback", p.callbackURL)
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", p.baseURL, url.PathEscape(p.accountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(p.accountSID, p.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("twilio request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		SID     string `json:"sid"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("twilio returned status %d: %d %s", resp.StatusCode, result.Code, result.Message)
+	}
+	return result.SID, nil
+}
+
+// ParseCallback checks the X-Twilio-Signature header: the base64 HMAC-SHA1, keyed with the auth token,
+// of the callback URL followed by every form parameter name and value, sorted by name
+func (p *twilio) ParseCallback(callback Callback) (*Status, error) {
+	form, err := url.ParseQuery(string(callback.Body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCallback, err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(callback.Header.Get(TwilioSignatureHeader))
+	if err != nil || p.callbackURL == "" || !hmac.Equal(signature, TwilioSignature(p.authToken, p.callbackURL, form)) {
+		return nil, ErrInvalidSignature
+	}
+
+	status, ok := twilioStatuses[form.Get("MessageStatus")]
+	if !ok || form.Get("MessageSid") == "" {
+		return nil, nil
+	}
+
+	return &Status{MessageID: form.Get("MessageSid"), Status: status, Error: form.Get("ErrorCode")}, nil
+}
+
+// TwilioSignature computes the signature Twilio sends for a callback to callbackURL with form
+func TwilioSignature(authToken, callbackURL string, form url.Values) []byte {
+	names := make([]string, 0, len(form))
+	for name := range form {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(callbackURL))
+	for _, name := range names {
+		for _, value := range form[name] {
+			mac.Write([]byte(name + value))
+		}
+	}
+	return mac.Sum(nil)
+}
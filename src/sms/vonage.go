@@ -0,0 +1,145 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"app/src/httpclient"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// vonageStatuses maps Vonage Messages API statuses to ours
+var vonageStatuses = map[string]string{
+	"submitted":     StatusSent,
+	"delivered":     StatusDelivered,
+	"read":          StatusRead,
+	"rejected":      StatusFailed,
+	"undeliverable": StatusFailed,
+}
+
+type vonage struct {
+	baseURL         string
+	apiKey          string
+	apiSecret       string
+	signatureSecret string
+	from            string
+	whatsAppFrom    string
+	client          *http.Client
+}
+
+// NewVonage creates a Vonage Messages API provider. Status callbacks go to the status URL of the
+// Vonage application and are verified with its signature secret.
+func NewVonage(baseURL, apiKey, apiSecret, signatureSecret, from, whatsAppFrom string) Provider {
+	return &vonage{
+		baseURL:         baseURL,
+		apiKey:          apiKey,
+		apiSecret:       apiSecret,
+		signatureSecret: signatureSecret,
+		from:            from,
+		whatsAppFrom:    whatsAppFrom,
+		client:          httpclient.New("vonage"),
+	}
+}
+
+func (p *vonage) Name() string {
+	return "vonage"
+}
+
+// Send posts to the Messages API, which takes numbers without the leading "+"
+func (p *vonage) Send(ctx context.Context, msg Message) (string, error) {
+	payload := map[string]string{
+		"message_type": "text",
+		"text":         msg.Text,
+		"to":           strings.TrimPrefix(msg.To, "+"),
+		"from":         strings.TrimPrefix(p.from, "+"),
+		"channel":      "sms",
+	}
+	if msg.WhatsApp {
+		payload["from"] = strings.TrimPrefix(p.whatsAppFrom, "+")
+		payload["channel"] = "whatsapp"
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(p.apiKey, p.apiSecret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vonage request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		MessageUUID string `json:"message_uuid"`
+		Title       string `json:"title"`
+		Detail      string `json:"detail"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("vonage returned status %d: %s %s", resp.StatusCode, result.Title, result.Detail)
+	}
+	return result.MessageUUID, nil
+}
+
+// ParseCallback checks the bearer JWT Vonage signs with the signature secret (HS256), whose
+// payload_hash claim is the SHA-256 of the body
+func (p *vonage) ParseCallback(callback Callback) (*Status, error) {
+	if p.signatureSecret == "" {
+		return nil, ErrInvalidSignature
+	}
+
+	bearer, _ := strings.CutPrefix(callback.Header.Get("Authorization"), "Bearer ")
+	token, err := jwt.Parse(bearer, func(_ *jwt.Token) (interface{}, error) {
+		return []byte(p.signatureSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidSignature
+	}
+
+	claims, _ := token.Claims.(jwt.MapClaims)
+	payloadHash, _ := claims["payload_hash"].(string)
+	sum := sha256.Sum256(callback.Body)
+	if subtle.ConstantTimeCompare([]byte(strings.ToLower(payloadHash)), []byte(hex.EncodeToString(sum[:]))) != 1 {
+		return nil, ErrInvalidSignature
+	}
+
+	var event struct {
+		MessageUUID string `json:"message_uuid"`
+		Status      string `json:"status"`
+		Error       struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(callback.Body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCallback, err)
+	}
+
+	status, ok := vonageStatuses[event.Status]
+	if !ok || event.MessageUUID == "" {
+		return nil, nil
+	}
+
+	detail := event.Error.Title
+	if event.Error.Detail != "" {
+		detail = strings.TrimSpace(detail + " " + event.Error.Detail)
+	}
+	return &Status{MessageID: event.MessageUUID, Status: status, Error: detail}, nil
+}
@@ -0,0 +1,112 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"app/src/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// initialBackoff is the delay after the first failed check
+	initialBackoff = 500 * time.Millisecond
+
+	// maxBackoff caps the delay between checks
+	maxBackoff = 10 * time.Second
+)
+
+// Dependency is an external service the app needs before serving traffic
+type Dependency struct {
+	Name string
+	// Required dependencies abort startup when they stay unreachable;
+	// optional ones only log a warning and the app starts degraded
+	Required bool
+	Check    func(ctx context.Context) error
+}
+
+// TCPCheck returns a check that succeeds once host:port accepts TCP connections
+func TCPCheck(host string, port int) func(ctx context.Context) error {
+	address := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// Wait checks all dependencies concurrently, retrying each with exponential backoff
+// until it succeeds or timeout elapses. It returns an error if any required dependency
+// is still unreachable.
+func Wait(ctx context.Context, deps []Dependency, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(deps))
+
+	for i, dep := range deps {
+		wg.Add(1)
+		go func(i int, dep Dependency) {
+			defer wg.Done()
+			errs[i] = waitFor(ctx, dep)
+		}(i, dep)
+	}
+	wg.Wait()
+
+	for i, dep := range deps {
+		if errs[i] == nil {
+			continue
+		}
+		if dep.Required {
+			return fmt.Errorf("required dependency %s unreachable: %w", dep.Name, errs[i])
+		}
+		utils.Log.WithFields(logrus.Fields{"dependency": dep.Name}).
+			Warnf("Optional dependency unreachable, starting degraded: %v", errs[i])
+	}
+
+	return nil
+}
+
+// waitFor retries a single dependency check until it succeeds or ctx is done
+func waitFor(ctx context.Context, dep Dependency) error {
+	start := time.Now()
+	backoff := initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		checkCtx, cancel := context.WithTimeout(ctx, maxBackoff)
+		err := dep.Check(checkCtx)
+		cancel()
+
+		fields := logrus.Fields{
+			"dependency": dep.Name,
+			"attempt":    attempt,
+			"elapsed":    time.Since(start).Round(time.Millisecond).String(),
+		}
+
+		if err == nil {
+			utils.Log.WithFields(fields).Info("Dependency ready")
+			return nil
+		}
+
+		utils.Log.WithFields(fields).Infof("Waiting for dependency: %v (retry in %v)", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
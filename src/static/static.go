@@ -0,0 +1,78 @@
+package static
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+
+	"app/src/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+)
+
+// dist holds the embedded frontend build; replace its contents with your build output
+//
+//go:embed all:dist
+var dist embed.FS
+
+// indexFile is served for unknown paths so client-side (history API) routing works
+const indexFile = "index.html"
+
+// apiPrefixes are never handled by the SPA fallback so unknown API paths still return JSON 404s
+var apiPrefixes = []string{"/v1", "/metrics"}
+
+// New returns a handler serving the frontend from cfg.Dir, or the embedded build when empty
+// Compression is applied by the global compress middleware
+func New(cfg config.StaticConfig) (fiber.Handler, error) {
+	root, err := root(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := filesystem.New(filesystem.Config{
+		Root:         http.FS(root),
+		Index:        indexFile,
+		NotFoundFile: indexFile,
+		MaxAge:       cfg.MaxAge,
+		Next: func(c *fiber.Ctx) bool {
+			return IsAPIPath(c.Path())
+		},
+	})
+
+	return func(c *fiber.Ctx) error {
+		if err := handler(c); err != nil {
+			return err
+		}
+
+		// Always revalidate the HTML shell so new deploys are picked up immediately
+		if strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMETextHTML) {
+			c.Set(fiber.HeaderCacheControl, "no-cache")
+		}
+
+		return nil
+	}, nil
+}
+
+// IsAPIPath reports whether the path belongs to the API rather than the frontend
+func IsAPIPath(path string) bool {
+	for _, prefix := range apiPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// root resolves the file system to serve from
+func root(dir string) (fs.FS, error) {
+	if dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, err
+		}
+		return os.DirFS(dir), nil
+	}
+	return fs.Sub(dist, "dist")
+}
@@ -0,0 +1,33 @@
+// Package tracing carries the ID of a request (the X-Request-ID header) to the work it causes, so
+// error responses, audit entries, emails and log lines can all be traced back to it.
+package tracing
+
+import (
+	"context"
+	"regexp"
+)
+
+// MaxRequestIDLength matches the request_id columns
+const MaxRequestIDLength = 128
+
+// requestIDPattern is what a request ID given by a client or proxy may look like; anything else is
+// replaced so it cannot forge log lines or email headers
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+type contextKey struct{}
+
+// WithRequestID returns ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, requestID)
+}
+
+// RequestID returns the ID set by WithRequestID, or "" for work not caused by a request
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(contextKey{}).(string)
+	return requestID
+}
+
+// ValidRequestID reports whether an incoming request ID can be kept as is
+func ValidRequestID(requestID string) bool {
+	return len(requestID) <= MaxRequestIDLength && requestIDPattern.MatchString(requestID)
+}
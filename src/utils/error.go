@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"app/src/dryrun"
 	"app/src/response"
 	"app/src/validation"
 	"errors"
@@ -9,15 +10,35 @@ import (
 )
 
 func ErrorHandler(c *fiber.Ctx, err error) error {
+	// A dry run stops before changing anything and reports what it would have done
+	var preview *dryrun.Preview
+	if errors.As(err, &preview) {
+		return c.Status(fiber.StatusOK).JSON(response.SuccessWithDryRun{
+			Code:    fiber.StatusOK,
+			Status:  "success",
+			Message: "Dry run, nothing was changed",
+			DryRun:  preview.Report,
+		})
+	}
+
 	if errorsMap := validation.CustomErrorMessages(err); len(errorsMap) > 0 {
 		return response.Error(c, fiber.StatusBadRequest, "Bad Request", errorsMap)
 	}
 
+	var fieldErrors validation.FieldErrors
+	if errors.As(err, &fieldErrors) {
+		return response.Error(c, fiber.StatusBadRequest, "Bad Request", fieldErrors)
+	}
+
 	var fiberErr *fiber.Error
 	if errors.As(err, &fiberErr) {
 		return response.Error(c, fiberErr.Code, fiberErr.Message, nil)
 	}
 
+	// Unexpected errors are logged with the request ID the user is given, so support can find them
+	Log.WithField("request_id", c.GetRespHeader(fiber.HeaderXRequestID)).
+		Errorf("Unhandled error on %s %s: %v", c.Method(), c.Path(), err)
+
 	return response.Error(c, fiber.StatusInternalServerError, "Internal Server Error", nil)
 }
 
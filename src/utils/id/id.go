@@ -0,0 +1,144 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ID formats; both are time-ordered so new rows land at the end of primary key indexes
+const (
+	// FormatUUIDv7 generates RFC 9562 version 7 UUIDs
+	FormatUUIDv7 = "uuidv7"
+	// FormatULID generates ULIDs, stored in the same 16 bytes as a UUID
+	FormatULID = "ulid"
+)
+
+// crockford is the ULID text alphabet
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLength is the length of a ULID in text form
+const ulidLength = 26
+
+var (
+	mu     sync.Mutex
+	format = FormatUUIDv7
+
+	// lastULID is the previous ULID, so ULIDs generated within the same millisecond stay ordered
+	lastULID uuid.UUID
+)
+
+// Configure selects the format of new IDs
+func Configure(f string) error {
+	if f != FormatUUIDv7 && f != FormatULID {
+		return fmt.Errorf("unknown ID format %q", f)
+	}
+
+	mu.Lock()
+	format = f
+	mu.Unlock()
+	return nil
+}
+
+// New returns a new time-ordered ID in the configured format
+func New() uuid.UUID {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if format == FormatULID {
+		return newULID(time.Now())
+	}
+
+	// NewV7 only fails if the random source does
+	return uuid.Must(uuid.NewV7())
+}
+
+// NewString returns a new ID as a string
+func NewString() string {
+	return New().String()
+}
+
+// Parse reads an ID in UUID or ULID text form. IDs created before time-ordered IDs were
+// introduced are random UUIDs and still parse.
+func Parse(s string) (uuid.UUID, error) {
+	if len(s) == ulidLength {
+		return ParseULID(s)
+	}
+	return uuid.Parse(s)
+}
+
+// ULID returns the ULID text form of an ID
+func ULID(u uuid.UUID) string {
+	var out [ulidLength]byte
+
+	// The 128 bits are read five at a time, the first character holding only the top three
+	hi, lo := binary.BigEndian.Uint64(u[:8]), binary.BigEndian.Uint64(u[8:])
+	for i := ulidLength - 1; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(out[:])
+}
+
+// ParseULID reads an ID in ULID text form, ignoring case
+func ParseULID(s string) (uuid.UUID, error) {
+	var u uuid.UUID
+	if len(s) != ulidLength {
+		return u, errors.New("invalid ULID length")
+	}
+
+	var hi, lo uint64
+	for i, c := range strings.ToUpper(s) {
+		value := strings.IndexRune(crockford, c)
+		if value < 0 || (i == 0 && value > 7) {
+			return u, errors.New("invalid ULID")
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(value)
+	}
+
+	binary.BigEndian.PutUint64(u[:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	return u, nil
+}
+
+// newULID returns a ULID for now: 48 bits of Unix milliseconds followed by 80 random bits, which
+// are incremented instead within the millisecond of the previous ULID
+func newULID(now time.Time) uuid.UUID {
+	var u uuid.UUID
+	ms := uint64(now.UnixMilli())
+	binary.BigEndian.PutUint64(u[:8], ms<<16)
+
+	if ms <= ulidTime(lastULID) && incrementRandom(&lastULID) {
+		u = lastULID
+	} else if _, err := rand.Read(u[6:]); err != nil {
+		panic(fmt.Sprintf("id: failed to read random bytes: %v", err))
+	}
+
+	lastULID = u
+	return u
+}
+
+// ulidTime returns the milliseconds of a ULID
+func ulidTime(u uuid.UUID) uint64 {
+	return binary.BigEndian.Uint64(u[:8]) >> 16
+}
+
+// incrementRandom adds one to the random bits of a ULID, reporting false if they overflow
+func incrementRandom(u *uuid.UUID) bool {
+	for i := len(u) - 1; i >= 6; i-- {
+		u[i]++
+		if u[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -25,4 +25,7 @@ func init() {
 	})
 
 	Log.SetOutput(os.Stdout)
+
+	// Scrub emails, tokens and credentials from every entry (see redact.go)
+	Log.AddHook(redactHook)
 }
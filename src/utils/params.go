@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// paramIDLocals prefixes the locals holding route params parsed as IDs
+const paramIDLocals = "param_id:"
+
+// SetParamID stores the ID parsed from a route param
+func SetParamID(c *fiber.Ctx, name string, id uuid.UUID) {
+	c.Locals(paramIDLocals+name, id)
+}
+
+// ParamID returns a route param parsed by middleware.ValidateIDs in canonical UUID form, so IDs given
+// as ULIDs match stored ones. Routes without the middleware get the raw param.
+func ParamID(c *fiber.Ctx, name string) string {
+	if id, ok := c.Locals(paramIDLocals + name).(uuid.UUID); ok {
+		return id.String()
+	}
+	return c.Params(name)
+}
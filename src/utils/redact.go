@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted replaces sensitive values in log output
+const Redacted = "[REDACTED]"
+
+// redactRule replaces every match of pattern; replacement may reference groups (${1})
+type redactRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// defaultRedactRules cover credentials and emails that end up in messages, mostly via wrapped errors
+// (e.g. Postgres: Key (email)=(user@example.com) already exists)
+var defaultRedactRules = []redactRule{
+	// Authorization header values and bearer credentials
+	{regexp.MustCompile(`(?i)(authorization"?\s*[:=]\s*"?)[^",\r\n]+`), "${1}" + Redacted},
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`), "${1} " + Redacted},
+	// JWTs, opaque access tokens, personal access tokens, OAuth client tokens and secrets and partner
+	// signing secrets anywhere in the text
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), Redacted},
+	{regexp.MustCompile(`\b(?:at|pat|oat|ocs|psk)_[A-Za-z0-9_-]+`), Redacted},
+	// Secret-looking keys in JSON bodies and query strings
+	{
+		regexp.MustCompile(`(?i)("(?:password|passwd|secret|token|refresh_token|access_token|api_key|captcha_token)"\s*:\s*")[^"]*`),
+		"${1}" + Redacted,
+	},
+	{
+		regexp.MustCompile(`(?i)\b((?:password|passwd|secret|token|refresh_token|access_token|api_key|signature)=)[^&\s"]+`),
+		"${1}" + Redacted,
+	},
+	// Emails keep their first character and domain so logs stay useful for debugging
+	{regexp.MustCompile(`\b([A-Za-z0-9])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})\b`), "${1}***@${2}"},
+}
+
+// defaultRedactFields are structured log fields (logrus.Fields) whose values are always dropped
+var defaultRedactFields = []string{
+	"password", "passwd", "secret", "token", "refresh_token", "access_token", "api_key", "authorization", "cookie",
+}
+
+// Redactor scrubs PII and credentials from strings
+type Redactor struct {
+	rules  []redactRule
+	fields map[string]bool
+}
+
+// NewRedactor creates a redactor with the default rules plus extra patterns (matches are replaced
+// with [REDACTED]) and extra field names
+func NewRedactor(patterns, fields []string) (*Redactor, error) {
+	r := &Redactor{
+		rules:  append([]redactRule(nil), defaultRedactRules...),
+		fields: make(map[string]bool),
+	}
+
+	for _, raw := range patterns {
+		pattern, err := regexp.Compile(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", raw, err)
+		}
+		r.rules = append(r.rules, redactRule{pattern: pattern, replacement: Redacted})
+	}
+
+	for _, field := range append(defaultRedactFields, fields...) {
+		r.fields[strings.ToLower(field)] = true
+	}
+
+	return r, nil
+}
+
+// Redact returns s with every sensitive match replaced
+func (r *Redactor) Redact(s string) string {
+	for _, rule := range r.rules {
+		s = rule.pattern.ReplaceAllString(s, rule.replacement)
+	}
+	return s
+}
+
+// RedactHook scrubs messages and fields of every log entry before it is formatted
+type RedactHook struct {
+	redactor atomic.Pointer[Redactor]
+}
+
+func (h *RedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactHook) Fire(entry *logrus.Entry) error {
+	r := h.redactor.Load()
+	if r == nil {
+		return nil
+	}
+
+	entry.Message = r.Redact(entry.Message)
+
+	for key, value := range entry.Data {
+		if r.fields[strings.ToLower(key)] {
+			entry.Data[key] = Redacted
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = r.Redact(v)
+		case error:
+			entry.Data[key] = r.Redact(v.Error())
+		}
+	}
+
+	return nil
+}
+
+// defaultRedactor backs Redact when log redaction is disabled
+var defaultRedactor, _ = NewRedactor(nil, nil)
+
+// Redact scrubs s with the active log redaction rules, or the defaults when log redaction is off
+func Redact(s string) string {
+	if r := redactHook.redactor.Load(); r != nil {
+		return r.Redact(s)
+	}
+	return defaultRedactor.Redact(s)
+}
+
+// redactHook is installed on Log and the standard logrus logger, so both utils.Log and
+// package-level logrus calls are scrubbed
+var redactHook = newRedactHook()
+
+func newRedactHook() *RedactHook {
+	hook := new(RedactHook)
+	redactor, _ := NewRedactor(nil, nil)
+	hook.redactor.Store(redactor)
+	logrus.AddHook(hook)
+	return hook
+}
+
+// ConfigureRedaction replaces the active redaction rules; disabling turns scrubbing off entirely
+func ConfigureRedaction(enabled bool, patterns, fields []string) error {
+	if !enabled {
+		redactHook.redactor.Store(nil)
+		return nil
+	}
+
+	redactor, err := NewRedactor(patterns, fields)
+	if err != nil {
+		return err
+	}
+	redactHook.redactor.Store(redactor)
+	return nil
+}
@@ -3,33 +3,150 @@ package utils
 
 import (
 	"errors"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Claims every verified token must carry, set with ConfigureTokenClaims; an empty issuer or audience
+// is not checked
+var (
+	tokenIssuer   string
+	tokenAudience string
+	tokenLeeway   time.Duration
+)
+
+// ConfigureTokenClaims sets the issuer and audience tokens must carry, and the clock skew tolerated
+// on their expiry
+func ConfigureTokenClaims(issuer, audience string, leeway time.Duration) {
+	tokenIssuer = issuer
+	tokenAudience = audience
+	tokenLeeway = leeway
+}
+
+// AccessClaims holds the authorization data embedded in an access token
+type AccessClaims struct {
+	UserID    string
+	SessionID string // empty for tokens issued without a session
+	Role      string
+	Scopes    []string
+	Plan      string // empty for tokens issued before plans existed
+	IssuedAt  time.Time
+}
+
 func VerifyToken(tokenStr, secret, tokenType string) (string, error) {
+	claims, err := parseClaims(tokenStr, secret, tokenType)
+	if err != nil {
+		return "", err
+	}
+
+	userID, ok := claims["sub"].(string)
+	if !ok {
+		return "", errors.New("invalid token sub")
+	}
+
+	return userID, nil
+}
+
+// VerifySessionToken validates a token and returns its subject and session ID; tokens issued
+// without a session return an empty session ID
+func VerifySessionToken(tokenStr, secret, tokenType string) (string, string, error) {
+	claims, err := parseClaims(tokenStr, secret, tokenType)
+	if err != nil {
+		return "", "", err
+	}
+
+	userID, ok := claims["sub"].(string)
+	if !ok {
+		return "", "", errors.New("invalid token sub")
+	}
+
+	sessionID, _ := claims["sid"].(string)
+
+	return userID, sessionID, nil
+}
+
+// VerifyAccessToken validates an access token and returns its embedded role and scopes
+func VerifyAccessToken(tokenStr, secret, tokenType string) (*AccessClaims, error) {
+	claims, err := parseClaims(tokenStr, secret, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, ok := claims["sub"].(string)
+	if !ok {
+		return nil, errors.New("invalid token sub")
+	}
+
+	role, ok := claims["role"].(string)
+	if !ok {
+		return nil, errors.New("invalid token role")
+	}
+
+	rawScopes, ok := claims["scopes"].([]interface{})
+	if !ok {
+		return nil, errors.New("invalid token scopes")
+	}
+
+	scopes := make([]string, 0, len(rawScopes))
+	for _, raw := range rawScopes {
+		scope, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("invalid token scopes")
+		}
+		scopes = append(scopes, scope)
+	}
+
+	// iat is optional; a missing one leaves IssuedAt zero, which revocation checks treat as oldest
+	var issuedAt time.Time
+	if iat, ok := claims["iat"].(float64); ok {
+		issuedAt = time.Unix(int64(iat), 0)
+	}
+
+	sessionID, _ := claims["sid"].(string)
+	plan, _ := claims["plan"].(string)
+
+	return &AccessClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		Role:      role,
+		Scopes:    scopes,
+		Plan:      plan,
+		IssuedAt:  issuedAt,
+	}, nil
+}
+
+// parseClaims verifies the token's signature, expiry, issuer and audience, and that it is of tokenType.
+// Tokens without an issuer or audience are rejected once they are configured.
+func parseClaims(tokenStr, secret, tokenType string) (jwt.MapClaims, error) {
+	options := []jwt.ParserOption{jwt.WithLeeway(tokenLeeway)}
+	if tokenIssuer != "" {
+		options = append(options, jwt.WithIssuer(tokenIssuer))
+	}
+	if tokenAudience != "" {
+		options = append(options, jwt.WithAudience(tokenAudience))
+	}
+
 	token, err := jwt.Parse(tokenStr, func(_ *jwt.Token) (interface{}, error) {
 		return []byte(secret), nil
-	})
+	}, options...)
 
 	if err != nil || !token.Valid {
-		return "", err
+		if err == nil {
+			err = errors.New("invalid token")
+		}
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", errors.New("invalid token claims")
+		return nil, errors.New("invalid token claims")
 	}
 
 	jwtType, ok := claims["type"].(string)
 	if !ok || jwtType != tokenType {
-		return "", errors.New("invalid token type")
+		return nil, errors.New("invalid token type")
 	}
 
-	userID, ok := claims["sub"].(string)
-	if !ok {
-		return "", errors.New("invalid token sub")
-	}
-
-	return userID, nil
+	return claims, nil
 }
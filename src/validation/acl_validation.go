@@ -0,0 +1,6 @@
+package validation
+
+type GrantACL struct {
+	Principal  string `json:"principal" validate:"required,max=128,acl_principal" example:"user:01928f6e-7a3c-7cc2-9b1e-3f5a2d4c8e10"`
+	Permission string `json:"permission" validate:"required,oneof=read write owner" example:"write"`
+}
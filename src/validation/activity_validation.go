@@ -0,0 +1,10 @@
+package validation
+
+type QueryActivity struct {
+	Page  int      `validate:"required,min=1"`
+	Limit int      `validate:"required,min=1,max=100"`
+	Types []string `validate:"omitempty,dive,oneof=login token email message audit"`
+	// From and To are days in the caller's timezone, both inclusive, as YYYY-MM-DD
+	From string `validate:"omitempty,datetime=2006-01-02"`
+	To   string `validate:"omitempty,datetime=2006-01-02"`
+}
@@ -0,0 +1,13 @@
+package validation
+
+// QueryActiveUsers selects the UTC days of the active user counts, both inclusive, as YYYY-MM-DD
+type QueryActiveUsers struct {
+	From string `validate:"omitempty,datetime=2006-01-02"`
+	To   string `validate:"omitempty,datetime=2006-01-02"`
+}
+
+// QueryEndpointVisitors selects the UTC day of the unique visitors per endpoint, as YYYY-MM-DD
+type QueryEndpointVisitors struct {
+	Day   string `validate:"omitempty,datetime=2006-01-02"`
+	Limit int    `validate:"required,min=1,max=500"`
+}
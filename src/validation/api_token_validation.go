@@ -0,0 +1,7 @@
+package validation
+
+type CreateAPIToken struct {
+	Name          string   `json:"name" validate:"required,max=100" example:"reporting"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,required,max=50" example:"getUsers"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"required,min=1,max=365" example:"30"`
+}
@@ -0,0 +1,10 @@
+package validation
+
+// ExportAuditLogs selects the audit entries of an export by UTC day, both inclusive, as YYYY-MM-DD
+type ExportAuditLogs struct {
+	From   string `validate:"required,datetime=2006-01-02"`
+	To     string `validate:"required,datetime=2006-01-02"`
+	Format string `validate:"required,oneof=csv ndjson"`
+	Action string `validate:"omitempty,max=100"`
+	UserID string `validate:"omitempty,uuid"`
+}
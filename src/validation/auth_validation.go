@@ -2,8 +2,13 @@ package validation
 
 type Register struct {
 	Name     string `json:"name" validate:"required,max=50" example:"fake name"`
+	Username string `json:"username,omitempty" validate:"omitempty,min=3,max=30,username" example:"fake_name"`
 	Email    string `json:"email" validate:"required,email,max=50" example:"fake@example.com"`
 	Password string `json:"password" validate:"required,min=8,max=20,password" example:"password1"`
+	// PasswordConfirmation is optional; when given it must match Password
+	PasswordConfirmation string `json:"password_confirmation,omitempty" example:"password1"`
+	// Timezone renders the user's emails and date filters; DEFAULT_TIMEZONE is used when omitted
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,max=64,timezone" example:"Asia/Jakarta"`
 }
 
 type Login struct {
@@ -17,6 +22,13 @@ type GoogleLogin struct {
 	VerifiedEmail bool   `json:"verified_email" validate:"required"`
 }
 
+type AppleLogin struct {
+	Subject       string `json:"sub" validate:"required,max=255"`
+	Name          string `json:"name" validate:"max=50"`
+	Email         string `json:"email" validate:"omitempty,email,max=50"`
+	VerifiedEmail bool   `json:"email_verified"`
+}
+
 type Logout struct {
 	RefreshToken string `json:"refresh_token" validate:"required,max=255"`
 }
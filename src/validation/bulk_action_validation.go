@@ -0,0 +1,20 @@
+package validation
+
+// BulkUserAction applies an action to the users listed in UserIDs, or else to those Filter selects
+type BulkUserAction struct {
+	Action string `json:"action" validate:"required,oneof=deactivate delete assign-role add-tag" example:"add-tag"`
+	// Role is the role assign-role gives
+	Role string `json:"role,omitempty" validate:"required_if=Action assign-role,excluded_unless=Action assign-role,omitempty,oneof=user admin" example:"user"`
+	// Tag is the tag add-tag adds
+	Tag     string      `json:"tag,omitempty" validate:"required_if=Action add-tag,excluded_unless=Action add-tag,omitempty,max=32,tag" example:"beta"`
+	UserIDs []string    `json:"user_ids,omitempty" validate:"required_without=Filter,excluded_with=Filter,omitempty,max=1000,dive,uuid" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Filter  *UserFilter `json:"filter,omitempty" validate:"required_without=UserIDs,excluded_with=UserIDs"`
+}
+
+// UserFilter selects users like the query parameters of GET /v1/users
+type UserFilter struct {
+	Search   string `json:"search,omitempty" validate:"omitempty,max=50,safe_search_string" example:"fake"`
+	Tag      string `json:"tag,omitempty" validate:"omitempty,max=32,tag" example:"trial"`
+	Username string `json:"username,omitempty" validate:"omitempty,max=30" example:"fake_name"`
+	Type     string `json:"type,omitempty" validate:"omitempty,oneof=human service" example:"human"`
+}
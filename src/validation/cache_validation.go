@@ -0,0 +1,15 @@
+package validation
+
+type PurgeCache struct {
+	Pattern string `json:"pattern,omitempty" validate:"required_without=Tag,omitempty,max=255" example:"api:response:GET:/v1/users*"`
+	Tag     string `json:"tag,omitempty" validate:"required_without=Pattern,omitempty,oneof=responses sessions users" example:"users"`
+}
+
+type CacheState struct {
+	Enabled *bool `json:"enabled" validate:"required" example:"false"`
+}
+
+type CreateCacheSkipRule struct {
+	Kind  string `json:"kind" validate:"required,oneof=path pattern method header" example:"path"`
+	Value string `json:"value" validate:"required,max=255" example:"/v1/reports"`
+}
@@ -2,10 +2,47 @@ package validation
 
 import (
 	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 )
 
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Tag accepts user tags: lowercase letters, digits, "-" and "_"
+func Tag(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	return !ok || tagPattern.MatchString(value)
+}
+
+var domainPatternRegex = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9-]{2,63}$`)
+
+// DomainPattern accepts a lowercase domain, optionally prefixed with "*." to match its subdomains
+func DomainPattern(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	return !ok || domainPatternRegex.MatchString(value)
+}
+
+var aclPrincipalPattern = regexp.MustCompile(`^(user:[0-9A-Za-z-]{26,36}|(role|tag):[a-z0-9][a-z0-9_-]{0,49})$`)
+
+// ACLPrincipal accepts ACL principals: "user:<id>", "role:<role>" or "tag:<tag>"
+func ACLPrincipal(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	return !ok || aclPrincipalPattern.MatchString(value)
+}
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Username accepts letters, digits and "_"; length and reserved words are checked separately
+func Username(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	return !ok || usernamePattern.MatchString(value)
+}
+
 func Password(field validator.FieldLevel) bool {
 	value, ok := field.Field().Interface().(string)
 	if ok {
@@ -19,3 +56,68 @@ func Password(field validator.FieldLevel) bool {
 
 	return true
 }
+
+var (
+	upperPattern  = regexp.MustCompile(`\p{Lu}`)
+	lowerPattern  = regexp.MustCompile(`\p{Ll}`)
+	digitPattern  = regexp.MustCompile(`\p{Nd}`)
+	symbolPattern = regexp.MustCompile(`[^\p{L}\p{Nd}\s]`)
+)
+
+// StrongPassword requires upper and lower case letters, a digit and a symbol; length is checked separately
+func StrongPassword(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	return !ok || (upperPattern.MatchString(value) && lowerPattern.MatchString(value) &&
+		digitPattern.MatchString(value) && symbolPattern.MatchString(value))
+}
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// E164Phone accepts phone numbers in E.164 format: "+", the country code and at most 15 digits
+func E164Phone(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	return !ok || e164Pattern.MatchString(value)
+}
+
+// UUIDList accepts a comma-separated list of UUIDs, such as an ids query parameter
+func UUIDList(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	if !ok {
+		return true
+	}
+
+	for _, id := range strings.Split(value, ",") {
+		if _, err := uuid.Parse(strings.TrimSpace(id)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// SafeSearchString accepts free text that can go into a LIKE pattern as is: no control characters,
+// no % wildcard and no \ escape
+func SafeSearchString(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	if !ok {
+		return true
+	}
+
+	return utf8.ValidString(value) && !strings.ContainsFunc(value, func(r rune) bool {
+		return unicode.IsControl(r) || r == '%' || r == '\\'
+	})
+}
+
+// Timezone accepts IANA time zone names such as Asia/Jakarta or UTC; "Local" is rejected as it
+// depends on the server
+func Timezone(field validator.FieldLevel) bool {
+	value, ok := field.Field().Interface().(string)
+	if !ok {
+		return true
+	}
+	if value == "" || value == "Local" {
+		return false
+	}
+
+	_, err := time.LoadLocation(value)
+	return err == nil
+}
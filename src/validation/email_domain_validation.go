@@ -0,0 +1,6 @@
+package validation
+
+type CreateEmailDomainRule struct {
+	Pattern string `json:"pattern" validate:"required,max=255,domain_pattern" example:"*.example.com"`
+	Action  string `json:"action" validate:"required,oneof=allow block" example:"block"`
+}
@@ -0,0 +1,14 @@
+package validation
+
+type CreateEmailSuppression struct {
+	Email  string `json:"email" validate:"required,email,max=255" example:"bounced@example.com"`
+	Detail string `json:"detail" validate:"max=500" example:"Asked by phone to stop all emails"`
+}
+
+type QueryEmailSuppression struct {
+	Page  int `validate:"required,min=1"`
+	Limit int `validate:"required,min=1,max=100"`
+	// Search matches part of the address
+	Search string `validate:"max=255"`
+	Reason string `validate:"omitempty,oneof=hard_bounce complaint unsubscribe manual"`
+}
@@ -0,0 +1,11 @@
+package validation
+
+type CreateLegalHold struct {
+	UserID string `json:"user_id" validate:"required,uuid" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+	Reason string `json:"reason" validate:"required,max=500" example:"Preserved for litigation case 2026-114"`
+}
+
+type QueryLegalHold struct {
+	Page  int `validate:"required,min=1"`
+	Limit int `validate:"required,min=1,max=100"`
+}
@@ -0,0 +1,22 @@
+package validation
+
+type UpdateNotificationPreference struct {
+	// Channels may be empty to turn off notifications the user can do without
+	Channels []string `json:"channels" validate:"required,unique,dive,oneof=email sms whatsapp push" example:"email,push"`
+}
+
+type SetPhone struct {
+	Phone string `json:"phone" validate:"required,e164_phone" example:"+14155552671"`
+	// Channel is where the verification code is sent
+	Channel string `json:"channel" validate:"omitempty,oneof=sms whatsapp" example:"sms"`
+}
+
+type VerifyPhone struct {
+	Code string `json:"code" validate:"required,len=6,numeric" example:"123456"`
+}
+
+type RegisterPushDevice struct {
+	Platform string `json:"platform" validate:"required,oneof=fcm apns" example:"fcm"`
+	Token    string `json:"token" validate:"required,max=4096" example:"dGhpcyBpcyBhbiBGQ00gdG9rZW4"`
+	Name     string `json:"name" validate:"max=100" example:"Pixel 8"`
+}
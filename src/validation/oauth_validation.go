@@ -0,0 +1,38 @@
+package validation
+
+type CreateOAuthClient struct {
+	Name         string   `json:"name" validate:"required,max=100" example:"Acme Reports"`
+	RedirectURIs []string `json:"redirect_uris" validate:"required,min=1,max=10,dive,required,url,max=500" example:"https://reports.example.com/callback"`
+	Scopes       []string `json:"scopes" validate:"required,min=1,dive,required,max=50" example:"profile:read"`
+	// RateLimit is the requests per minute allowed to all of the client's tokens; 0 uses the default
+	RateLimit int `json:"rate_limit" validate:"min=0,max=100000" example:"600"`
+}
+
+// OAuthAuthorize is an authorization request, passed on from the client's redirect to the consent screen
+type OAuthAuthorize struct {
+	ResponseType string `json:"response_type" query:"response_type" validate:"required,eq=code" example:"code"`
+	ClientID     string `json:"client_id" query:"client_id" validate:"required,uuid" example:"0192f1a4-7c2e-7b61-9d3a-5e8f2c4b6a10"`
+	RedirectURI  string `json:"redirect_uri" query:"redirect_uri" validate:"required,max=500" example:"https://reports.example.com/callback"`
+	// Scope lists the requested scopes, separated by spaces
+	Scope string `json:"scope" query:"scope" validate:"required,max=500" example:"profile:read"`
+	State string `json:"state" query:"state" validate:"max=500" example:"af0ifjsldkj"`
+	// CodeChallenge is the PKCE challenge: the base64url SHA-256 of the client's code verifier
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge" validate:"omitempty,min=43,max=128" example:"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method" validate:"omitempty,eq=S256" example:"S256"`
+}
+
+// OAuthConsent is the user's answer to an authorization request
+type OAuthConsent struct {
+	OAuthAuthorize
+	Approve bool `json:"approve" example:"true"`
+}
+
+// OAuthToken is a token request of the authorization-code grant, form-encoded as RFC 6749 requires
+type OAuthToken struct {
+	GrantType    string `form:"grant_type" validate:"required" example:"authorization_code"`
+	Code         string `form:"code" validate:"required,max=100" example:"SplxlOBeZQQYbYS6WxSbIA"`
+	RedirectURI  string `form:"redirect_uri" validate:"required,max=500" example:"https://reports.example.com/callback"`
+	ClientID     string `form:"client_id" validate:"required,uuid" example:"0192f1a4-7c2e-7b61-9d3a-5e8f2c4b6a10"`
+	ClientSecret string `form:"client_secret" validate:"required,max=100" example:"ocs_Q3vT8kZ1x7tWcVbN3sLpE0yHuJdK4gOiTqAeF6MmRa9"`
+	CodeVerifier string `form:"code_verifier" validate:"omitempty,min=43,max=128" example:"dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"`
+}
@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Optional is a request field that tells an absent field from one set to null and from one set
+// to its zero value, so an update can leave a field unchanged, clear it or empty it. Validation
+// tags apply to the value when it is set and not null; pair them with omitnil.
+type Optional[T any] struct {
+	// Set is true when the field was in the body
+	Set bool
+	// Null is true when the field was set to null
+	Null  bool
+	Value T
+}
+
+// Some returns an Optional set to value
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Set: true, Value: value}
+}
+
+// Null returns an Optional set to null
+func Null[T any]() Optional[T] {
+	return Optional[T]{Set: true, Null: true}
+}
+
+// Present reports whether the field was set to a value, not null
+func (o Optional[T]) Present() bool {
+	return o.Set && !o.Null
+}
+
+// IsNull reports whether the field was set to null
+func (o Optional[T]) IsNull() bool {
+	return o.Set && o.Null
+}
+
+// Ptr returns the value, or nil when the field is absent or null
+func (o Optional[T]) Ptr() *T {
+	if !o.Present() {
+		return nil
+	}
+	return &o.Value
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	*o = Optional[T]{Set: true}
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// optionalTypes are the Optional types the validator looks into
+var optionalTypes = []any{Optional[string]{}, Optional[bool]{}, Optional[int]{}}
+
+// optionalValue hands the validator the value of an Optional, or a nil pointer that omitnil skips
+func optionalValue(field reflect.Value) any {
+	present := field.FieldByName("Set").Bool() && !field.FieldByName("Null").Bool()
+	value := field.FieldByName("Value")
+	if !present {
+		return reflect.Zero(reflect.PointerTo(value.Type())).Interface()
+	}
+
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	return ptr.Interface()
+}
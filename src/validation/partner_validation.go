@@ -0,0 +1,5 @@
+package validation
+
+type CreatePartner struct {
+	Name string `json:"name" validate:"required,max=100" example:"Acme Payments"`
+}
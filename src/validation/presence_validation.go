@@ -0,0 +1,5 @@
+package validation
+
+type LookupPresence struct {
+	UserIDs []string `json:"user_ids" validate:"required,min=1,unique,dive,uuid" example:"e088d183-9eea-4a11-8d5d-74d7ec91bdf5"`
+}
@@ -0,0 +1,16 @@
+package validation
+
+// UpdateUserQuota changes a user's plan and replaces their quota override; omitted limits fall back
+// to the plan and 0 means unlimited
+type UpdateUserQuota struct {
+	Plan         *string `json:"plan,omitempty" validate:"omitempty,max=30" example:"pro"`
+	DailyLimit   *int64  `json:"daily_limit,omitempty" validate:"omitempty,min=0" example:"5000"`
+	MonthlyLimit *int64  `json:"monthly_limit,omitempty" validate:"omitempty,min=0" example:"100000"`
+}
+
+// UpdateTokenQuota replaces an API token's own quota; omitted limits leave that period to the owner's
+// quota only and 0 means unlimited
+type UpdateTokenQuota struct {
+	DailyLimit   *int64 `json:"daily_limit,omitempty" validate:"omitempty,min=0" example:"1000"`
+	MonthlyLimit *int64 `json:"monthly_limit,omitempty" validate:"omitempty,min=0" example:"20000"`
+}
@@ -0,0 +1,7 @@
+package validation
+
+type RateLimitSubject struct {
+	UserID string `validate:"required_without_all=IP Email,excluded_with=IP Email,omitempty,uuid"`
+	IP     string `validate:"required_without_all=UserID Email,excluded_with=UserID Email,omitempty,ip"`
+	Email  string `validate:"required_without_all=UserID IP,excluded_with=UserID IP,omitempty,email,max=50"`
+}
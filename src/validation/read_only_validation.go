@@ -0,0 +1,6 @@
+package validation
+
+type SetReadOnly struct {
+	Enabled *bool  `json:"enabled" validate:"required" example:"true"`
+	Reason  string `json:"reason" validate:"required_if=Enabled true,max=255" example:"Database migration"`
+}
@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Rule is a custom validation tag and the message reported when a field fails it
+type Rule struct {
+	Tag  string
+	Func validator.Func
+	// Message is formatted with the field name and, if it has a second %s, the tag's parameter
+	Message string
+}
+
+// builtinRules are the domain rules every validator knows
+var builtinRules = []Rule{
+	{Tag: "password", Func: Password, Message: "Field %s must contain at least 1 letter and 1 number"},
+	{
+		Tag: "strong_password", Func: StrongPassword,
+		Message: "Field %s must contain upper and lower case letters, a number and a symbol",
+	},
+	{Tag: "tag", Func: Tag, Message: "Field %s must contain only lowercase letters, numbers, - and _"},
+	{Tag: "domain_pattern", Func: DomainPattern, Message: "Field %s must be a domain such as example.com or *.example.com"},
+	{Tag: "acl_principal", Func: ACLPrincipal, Message: "Field %s must be user:<id>, role:<role> or tag:<tag>"},
+	{Tag: "username", Func: Username, Message: "Field %s must contain only letters, numbers and _"},
+	{Tag: "e164_phone", Func: E164Phone, Message: "Field %s must be a phone number in international format such as +14155552671"},
+	{Tag: "uuid_list", Func: UUIDList, Message: "Field %s must be a comma-separated list of UUIDs"},
+	{Tag: "safe_search_string", Func: SafeSearchString, Message: "Field %s must not contain control characters, %% or \\"},
+	{Tag: "timezone", Func: Timezone, Message: "Field %s must be an IANA time zone such as Asia/Jakarta"},
+}
+
+var (
+	registryMu        sync.RWMutex
+	registered        []Rule
+	registeredStructs []StructRule
+	messages          = map[string]string{}
+)
+
+func init() {
+	RegisterRule(builtinRules...)
+	RegisterStructRule(builtinStructRules...)
+}
+
+// RegisterRule adds rules to the validators created afterwards by Validator, so projects built on this
+// one can add their own tags from an init function without editing this package. A rule registered
+// under an existing tag replaces it. RegisterRule panics on a rule without a tag or function.
+func RegisterRule(rules ...Rule) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, rule := range rules {
+		if rule.Tag == "" || rule.Func == nil {
+			panic("validation: rule needs a tag and a function")
+		}
+
+		replaced := false
+		for i := range registered {
+			if registered[i].Tag == rule.Tag {
+				registered[i], replaced = rule, true
+			}
+		}
+		if !replaced {
+			registered = append(registered, rule)
+		}
+
+		if rule.Message != "" {
+			messages[rule.Tag] = rule.Message
+		} else {
+			delete(messages, rule.Tag)
+		}
+	}
+}
+
+// RegisterStructRule adds cross-field rules to the validators created afterwards by Validator. The
+// validator keeps one struct rule per type, so a type registered again gets the later rule.
+// RegisterStructRule panics on a rule without types or function.
+func RegisterStructRule(rules ...StructRule) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, rule := range rules {
+		if len(rule.Types) == 0 || rule.Func == nil {
+			panic("validation: struct rule needs types and a function")
+		}
+
+		registeredStructs = append(registeredStructs, rule)
+		for tag, msg := range rule.Messages {
+			messages[tag] = msg
+		}
+	}
+}
+
+// rules returns the registered rules
+func rules() []Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]Rule(nil), registered...)
+}
+
+// structRules returns the registered struct rules in registration order
+func structRules() []StructRule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]StructRule(nil), registeredStructs...)
+}
+
+// message returns the message of a tag, preferring registered rules over the standard tags
+func message(tag string) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if msg, ok := messages[tag]; ok {
+		return msg
+	}
+	return customMessages[tag]
+}
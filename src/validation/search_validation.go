@@ -0,0 +1,7 @@
+package validation
+
+type Search struct {
+	Query string   `validate:"required,max=100,safe_search_string"`
+	Types []string `validate:"omitempty,unique,dive,oneof=user audit token session"`
+	Limit int      `validate:"required,min=1,max=100"`
+}
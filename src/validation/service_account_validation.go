@@ -0,0 +1,7 @@
+package validation
+
+type CreateServiceAccount struct {
+	Name     string   `json:"name" validate:"required,max=100" example:"billing-worker"`
+	Identity string   `json:"identity" validate:"required,max=255" example:"spiffe://example.com/billing-worker"`
+	Rights   []string `json:"rights" validate:"required,min=1,dive,required,max=50" example:"getUsers"`
+}
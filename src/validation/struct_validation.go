@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// StructRule checks the fields of a struct against each other. Func reports failures on a field
+// with StructLevel.ReportError under a tag, whose message is taken from Messages or the other rules.
+type StructRule struct {
+	Types    []any
+	Func     validator.StructLevelFunc
+	Messages map[string]string
+}
+
+// builtinStructRules are the cross-field rules of the request schemas
+var builtinStructRules = []StructRule{
+	{
+		Types: []any{UpdateUser{}},
+		Func: all(
+			AtLeastOne("Name", "Username", "Email", "Password", "Role", "Timezone"),
+			NotNull("Name", "Email", "Password", "Role"),
+		),
+		Messages: map[string]string{
+			"at_least_one": "Field %s or one of %s must be filled",
+			"not_null":     "Field %s cannot be null",
+		},
+	},
+	{
+		Types:    []any{Register{}},
+		Func:     Confirmed("Password"),
+		Messages: map[string]string{"confirmed": "Field %s must match %s"},
+	},
+	{Types: []any{UpdatePassOrVerify{}}, Func: all(AtLeastOne("Password", "VerifiedEmail"), Confirmed("Password"))},
+	{
+		Types:    []any{QueryUsage{}, QueryUsageReport{}, QueryActivity{}, ExportAuditLogs{}, QueryActiveUsers{}},
+		Func:     NotBefore("From", "To"),
+		Messages: map[string]string{"not_before": "Field %s must not be before %s"},
+	},
+}
+
+// AtLeastOne requires one of the fields to be set, reporting the first field as "at_least_one"
+// with the others as parameter
+func AtLeastOne(fields ...string) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		for _, field := range fields {
+			if !sl.Current().FieldByName(field).IsZero() {
+				return
+			}
+		}
+
+		first := sl.Current().FieldByName(fields[0])
+		sl.ReportError(first.Interface(), fields[0], fields[0], "at_least_one", strings.Join(fields[1:], ", "))
+	}
+}
+
+// Confirmed requires field+"Confirmation" to match the field when given, reporting it as "confirmed"
+func Confirmed(field string) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		confirmation := sl.Current().FieldByName(field + "Confirmation")
+		if !confirmation.IsValid() || confirmation.IsZero() ||
+			confirmation.Interface() == sl.Current().FieldByName(field).Interface() {
+			return
+		}
+
+		sl.ReportError(confirmation.Interface(), field+"Confirmation", field+"Confirmation", "confirmed", field)
+	}
+}
+
+// NotBefore requires the end of a window not to be before its start when both are given, reporting
+// the end as "not_before". Fields are times, numbers or strings compared as text, such as
+// YYYY-MM-DD dates.
+func NotBefore(start, end string) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		from, to := sl.Current().FieldByName(start), sl.Current().FieldByName(end)
+		if from.IsZero() || to.IsZero() {
+			return
+		}
+
+		var before bool
+		switch value := to.Interface().(type) {
+		case time.Time:
+			before = value.Before(from.Interface().(time.Time))
+		case string:
+			before = value < from.String()
+		default:
+			if to.CanInt() {
+				before = to.Int() < from.Int()
+			} else if to.CanFloat() {
+				before = to.Float() < from.Float()
+			}
+		}
+
+		if before {
+			sl.ReportError(to.Interface(), end, end, "not_before", start)
+		}
+	}
+}
+
+// NotNull rejects Optional fields set to null, reporting them as "not_null"
+func NotNull(fields ...string) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		for _, field := range fields {
+			value := sl.Current().FieldByName(field).Interface()
+			if nullable, ok := value.(interface{ IsNull() bool }); ok && nullable.IsNull() {
+				sl.ReportError(value, field, field, "not_null", "")
+			}
+		}
+	}
+}
+
+// all runs several struct rules on the same type, which the validator only allows one of
+func all(funcs ...validator.StructLevelFunc) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		for _, fn := range funcs {
+			fn(sl)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package validation
+
+// QueryUsage selects the UTC days of a usage report, both inclusive, as YYYY-MM-DD
+type QueryUsage struct {
+	From string `validate:"omitempty,datetime=2006-01-02"`
+	To   string `validate:"omitempty,datetime=2006-01-02"`
+}
+
+// QueryUsageReport selects the days of the admin usage report and the metric users are ranked by
+type QueryUsageReport struct {
+	From   string `validate:"omitempty,datetime=2006-01-02"`
+	To     string `validate:"omitempty,datetime=2006-01-02"`
+	Metric string `validate:"required,max=60"`
+	Limit  int    `validate:"required,min=1,max=100"`
+}
@@ -1,26 +1,70 @@
 package validation
 
+import "slices"
+
 type CreateUser struct {
 	Name     string `json:"name" validate:"required,max=50" example:"fake name"`
+	Username string `json:"username,omitempty" validate:"omitempty,min=3,max=30,username" example:"fake_name"`
 	Email    string `json:"email" validate:"required,email,max=50" example:"fake@example.com"`
 	Password string `json:"password" validate:"required,min=8,max=20,password" example:"password1"`
 	Role     string `json:"role" validate:"required,oneof=user admin,max=50" example:"user"`
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,max=64,timezone" example:"Asia/Jakarta"`
 }
 
+// UpdateUser changes the fields present in the body. A field set to "" is emptied, one set to null
+// is cleared (only the username and timezone can be) and an absent one is left unchanged.
 type UpdateUser struct {
-	Name     string `json:"name,omitempty" validate:"omitempty,max=50" example:"fake name"`
-	Email    string `json:"email,omitempty" validate:"omitempty,email,max=50" example:"fake@example.com"`
-	Password string `json:"password,omitempty" validate:"omitempty,min=8,max=20,password" example:"password1"`
-	Role     string `json:"role,omitempty" validate:"omitempty,oneof=user admin" example:"user"`
+	Name     Optional[string] `json:"name,omitzero" validate:"omitnil,max=50" swaggertype:"string" example:"fake name"`
+	Username Optional[string] `json:"username,omitzero" validate:"omitnil,min=3,max=30,username" swaggertype:"string" example:"fake_name"`
+	Email    Optional[string] `json:"email,omitzero" validate:"omitnil,email,max=50" swaggertype:"string" example:"fake@example.com"`
+	Password Optional[string] `json:"password,omitzero" validate:"omitnil,min=8,max=20,password" swaggertype:"string" example:"password1"`
+	Role     Optional[string] `json:"role,omitzero" validate:"omitnil,oneof=user admin" swaggertype:"string" example:"user"`
+	Timezone Optional[string] `json:"timezone,omitzero" validate:"omitnil,max=64,timezone" swaggertype:"string" example:"Asia/Jakarta"`
+	// Tests maps fields to the value they must still have for the update to apply (nil for unset),
+	// from the "test" operations of a JSON Patch
+	Tests map[string]any `json:"-" swaggerignore:"true"`
+}
+
+// Fields lists the fields the update sets or clears
+func (r *UpdateUser) Fields() []string {
+	var fields []string
+	for name, value := range map[string]Optional[string]{
+		"name": r.Name, "username": r.Username, "email": r.Email,
+		"password": r.Password, "role": r.Role, "timezone": r.Timezone,
+	} {
+		if value.Set {
+			fields = append(fields, name)
+		}
+	}
+	slices.Sort(fields)
+	return fields
 }
 
 type UpdatePassOrVerify struct {
-	Password      string `json:"password,omitempty" validate:"omitempty,min=8,max=20,password" example:"password1"`
-	VerifiedEmail bool   `json:"verified_email" swaggerignore:"true" validate:"omitempty,boolean"`
+	Password string `json:"password,omitempty" validate:"omitempty,min=8,max=20,password" example:"password1"`
+	// PasswordConfirmation is optional; when given it must match Password
+	PasswordConfirmation string `json:"password_confirmation,omitempty" example:"password1"`
+	VerifiedEmail        bool   `json:"verified_email" swaggerignore:"true" validate:"omitempty,boolean"`
 }
 
 type QueryUser struct {
-	Page   int    `validate:"omitempty,number,max=50"`
-	Limit  int    `validate:"omitempty,number,max=50"`
-	Search string `validate:"omitempty,max=50"`
+	Page     int    `validate:"omitempty,number,max=50"`
+	Limit    int    `validate:"omitempty,number,max=50"`
+	Search   string `validate:"omitempty,max=50,safe_search_string"`
+	Tag      string `validate:"omitempty,max=32,tag"`
+	Username string `validate:"omitempty,max=30"`
+	Type     string `validate:"omitempty,oneof=human service"`
+}
+
+type CheckUsername struct {
+	Username string `validate:"required,min=3,max=30,username"`
+}
+
+type UserTags struct {
+	Tags []string `json:"tags" validate:"required,min=1,max=20,dive,required,max=32,tag" example:"beta"`
+}
+
+type QueryUserRevisions struct {
+	Page  int `validate:"required,min=1"`
+	Limit int `validate:"required,min=1,max=100"`
 }
@@ -3,6 +3,7 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -17,7 +18,7 @@ var customMessages = map[string]string{
 	"positive": "Field %s must be a positive number",
 	"alphanum": "Field %s must contain only alphanumeric characters",
 	"oneof":    "Invalid value for field %s",
-	"password": "Field %s must contain at least 1 letter and 1 number",
+	"datetime": "Field %s has an invalid date format",
 }
 
 func CustomErrorMessages(err error) map[string]string {
@@ -34,9 +35,9 @@ func generateErrorMessages(validationErrors validator.ValidationErrors) map[stri
 		fieldName := err.StructNamespace()
 		tag := err.Tag()
 
-		customMessage := customMessages[tag]
+		customMessage := message(tag)
 		if customMessage != "" {
-			errorsMap[fieldName] = formatErrorMessage(customMessage, err, tag)
+			errorsMap[fieldName] = formatErrorMessage(customMessage, err)
 		} else {
 			errorsMap[fieldName] = defaultErrorMessage(err)
 		}
@@ -44,8 +45,10 @@ func generateErrorMessages(validationErrors validator.ValidationErrors) map[stri
 	return errorsMap
 }
 
-func formatErrorMessage(customMessage string, err validator.FieldError, tag string) string {
-	if tag == "min" || tag == "max" || tag == "len" {
+// formatErrorMessage fills in the field name and, for messages with a second %s, the tag's parameter
+// such as the 8 of min=8
+func formatErrorMessage(customMessage string, err validator.FieldError) string {
+	if strings.Count(customMessage, "%s") == 2 {
 		return fmt.Sprintf(customMessage, err.Field(), err.Param())
 	}
 	return fmt.Sprintf(customMessage, err.Field())
@@ -55,12 +58,28 @@ func defaultErrorMessage(err validator.FieldError) string {
 	return fmt.Sprintf("Field validation for '%s' failed on the '%s' tag", err.Field(), err.Tag())
 }
 
+// Validator returns a validator knowing the built-in rules and those added with RegisterRule and
+// RegisterStructRule
 func Validator() *validator.Validate {
 	validate := validator.New()
 
-	if err := validate.RegisterValidation("password", Password); err != nil {
-		return nil
+	for _, rule := range rules() {
+		if err := validate.RegisterValidation(rule.Tag, rule.Func); err != nil {
+			return nil
+		}
+	}
+	for _, rule := range structRules() {
+		validate.RegisterStructValidation(rule.Func, rule.Types...)
 	}
+	validate.RegisterCustomTypeFunc(optionalValue, optionalTypes...)
 
 	return validate
 }
+
+// FieldErrors reports per-field input errors that are not produced by the validator
+// (e.g. malformed bodies or rejected file uploads), rendered like validation errors
+type FieldErrors map[string]string
+
+func (e FieldErrors) Error() string {
+	return fmt.Sprintf("invalid fields: %v", map[string]string(e))
+}
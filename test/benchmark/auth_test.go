@@ -0,0 +1,108 @@
+package benchmark_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/service"
+	"app/src/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+)
+
+// scenario is one way the session cache can answer an authenticated request
+type scenario struct {
+	name  string
+	store func() cache.Store
+}
+
+var scenarios = []scenario{
+	// The session is served from the cache store
+	{"CacheHit", func() cache.Store { return cache.NewMemoryStore() }},
+	// Every lookup misses, so the user is loaded from the user service and cached again
+	{"CacheMiss", func() cache.Store { return missStore{cache.NewMemoryStore()} }},
+	// Redis is unavailable, so every lookup fails fast and falls back to the user service
+	{"RedisDown", func() cache.Store { return cache.NewRedisStore(nil) }},
+}
+
+// missStore never finds a key, like a cache that has just been flushed
+type missStore struct {
+	*cache.MemoryStore
+}
+
+func (missStore) Get(string) ([]byte, error) {
+	return nil, nil
+}
+
+// stubUsers answers user lookups from memory, so the fallback path costs no database round trip
+type stubUsers struct {
+	service.UserService
+	user *model.User
+}
+
+func (s stubUsers) GetUserByID(_ *fiber.Ctx, _ string) (*model.User, error) {
+	return s.user, nil
+}
+
+func BenchmarkAuthenticatedGet(b *testing.B) {
+	for _, s := range scenarios {
+		b.Run(s.name, func(b *testing.B) {
+			runAuthenticatedGet(b, s)
+		})
+	}
+}
+
+// runAuthenticatedGet serves GET /v1/users/:userId behind middleware.Auth straight through the
+// fasthttp handler, so the numbers cover routing, token verification and the session lookup only
+func runAuthenticatedGet(b *testing.B, s scenario) {
+	secret := config.JWTSecret
+	config.JWTSecret = "benchmark-secret"
+	defer func() { config.JWTSecret = secret }()
+
+	user := &model.User{ID: uuid.New(), Name: "Bench", Email: "bench@example.com", Role: "user", IsActive: true}
+	store := s.store()
+	sessions := service.NewSessionService(store)
+	if err := sessions.CacheUserSession(context.Background(), user.ID.String(), user); err != nil {
+		b.Fatal(err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Get("/v1/users/:userId", middleware.Auth(stubUsers{user: user}, sessions), func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"id": c.Params("userId")})
+	})
+	handler := app.Handler()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  user.ID.String(),
+		"iat":  time.Now().Unix(),
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"type": config.TokenTypeAccess,
+		"iss":  config.JWTIssuer,
+		"aud":  config.JWTAudience,
+	}).SignedString([]byte(config.JWTSecret))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.Header.SetMethod(fiber.MethodGet)
+	ctx.Request.SetRequestURI("/v1/users/" + user.ID.String())
+	ctx.Request.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		handler(ctx)
+		if status := ctx.Response.StatusCode(); status != fiber.StatusOK {
+			b.Fatalf("unexpected status %d: %s", status, ctx.Response.Body())
+		}
+		ctx.Response.Reset()
+	}
+}
@@ -0,0 +1,71 @@
+package benchmark_test
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// baselineFile records the expected cost of each scenario
+const baselineFile = "baseline.json"
+
+// allocsTolerance is how far allocations may grow over the baseline; they barely vary between
+// machines, so a small margin catches real regressions
+const allocsTolerance = 1.1
+
+type baseline struct {
+	NsPerOp     int64 `json:"ns_per_op"`
+	BytesPerOp  int64 `json:"bytes_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+}
+
+// TestBaseline fails when a scenario gets slower or allocates more than recorded in baseline.json.
+// It only runs with BENCH_CHECK=1 (make bench-check); BENCH_UPDATE=1 records new baselines instead.
+// Timings depend on the machine, so they are compared with BENCH_TOLERANCE (default 1.5x).
+func TestBaseline(t *testing.T) {
+	update := os.Getenv("BENCH_UPDATE") == "1"
+	if os.Getenv("BENCH_CHECK") != "1" && !update {
+		t.Skip("set BENCH_CHECK=1 to compare benchmarks against baseline.json")
+	}
+
+	tolerance := 1.5
+	if value, err := strconv.ParseFloat(os.Getenv("BENCH_TOLERANCE"), 64); err == nil && value >= 1 {
+		tolerance = value
+	}
+
+	baselines := map[string]baseline{}
+	if data, err := os.ReadFile(baselineFile); err == nil {
+		assert.NoError(t, json.Unmarshal(data, &baselines))
+	}
+
+	for _, s := range scenarios {
+		result := testing.Benchmark(func(b *testing.B) {
+			runAuthenticatedGet(b, s)
+		})
+		measured := baseline{NsPerOp: result.NsPerOp(), BytesPerOp: result.AllocedBytesPerOp(), AllocsPerOp: result.AllocsPerOp()}
+		t.Logf("%s: %d ns/op, %d B/op, %d allocs/op", s.name, measured.NsPerOp, measured.BytesPerOp, measured.AllocsPerOp)
+
+		if update {
+			baselines[s.name] = measured
+			continue
+		}
+
+		expected, ok := baselines[s.name]
+		if !assert.True(t, ok, "no baseline for %s, run make bench-baseline", s.name) {
+			continue
+		}
+		assert.LessOrEqual(t, float64(measured.NsPerOp), float64(expected.NsPerOp)*tolerance,
+			"%s is slower than its baseline of %d ns/op", s.name, expected.NsPerOp)
+		assert.LessOrEqual(t, float64(measured.AllocsPerOp), float64(expected.AllocsPerOp)*allocsTolerance,
+			"%s allocates more than its baseline of %d allocs/op", s.name, expected.AllocsPerOp)
+	}
+
+	if update {
+		data, err := json.MarshalIndent(baselines, "", "  ")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(baselineFile, append(data, '\n'), 0o644))
+	}
+}
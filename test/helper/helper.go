@@ -1,21 +1,96 @@
 package helper
 
 import (
+	"app/src/analytics"
+	"app/src/cache"
 	"app/src/config"
 	"app/src/model"
 	"app/src/utils"
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 func ClearAll(db *gorm.DB) {
 	ClearToken(db)
+	ClearAuditLogs(db)
+	ClearQuotas(db)
+	ClearBillingEvents(db)
+	ClearUsage(db)
+	ClearACL(db)
+	ClearPartners(db)
+	ClearOAuthClients(db)
+	ClearServiceAccounts(db)
+	ClearBulkJobs(db)
+	ClearOutboxEmails(db)
+	ClearEmailSuppressions(db)
+	ClearNotifications(db)
+	ClearLegalHolds(db)
+	ClearSIEMCursors(db)
+	ClearAnalytics(db)
 	ClearUsers(db)
+	ClearNegativeCache()
+	ClearThrottles()
+	ClearLoginDelays()
+	ClearEmailRates()
+	ClearPhoneVerifications()
+}
+
+// ClearNegativeCache removes not-found markers so users inserted directly into the database are visible
+func ClearNegativeCache() {
+	clearCacheKeys(cache.NegativeKeyPrefix + "*")
+}
+
+// ClearThrottles removes per-email throttling state so repeated test runs start unthrottled
+func ClearThrottles() {
+	clearCacheKeys(cache.ThrottleKeyPrefix + "*")
+}
+
+// ClearLoginDelays removes failed login counts so a test's wrong passwords don't delay the next test
+func ClearLoginDelays() {
+	clearCacheKeys(cache.LoginDelayKeyPrefix + "*")
+}
+
+// ClearEmailRates removes outbound email counters so emails sent by earlier tests don't throttle the next
+func ClearEmailRates() {
+	clearCacheKeys(cache.EmailRateKeyPrefix + "*")
+}
+
+// ClearPhoneVerifications removes pending phone numbers and their verification attempts
+func ClearPhoneVerifications() {
+	clearCacheKeys(cache.PhoneVerificationKeyPrefix + "*")
+}
+
+func clearCacheKeys(pattern string) {
+	redisConfig, err := config.LoadRedisConfig()
+	if err != nil || !redisConfig.Enabled {
+		return
+	}
+
+	client := goredis.NewClient(&goredis.Options{
+		Addr:     fmt.Sprintf("%s:%d", redisConfig.Host, redisConfig.Port),
+		Username: redisConfig.Username,
+		Password: redisConfig.Password,
+		DB:       redisConfig.DB,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+	iter := client.Scan(ctx, 0, redisConfig.Namespace+pattern, 0).Iterator()
+	for iter.Next(ctx) {
+		client.Del(ctx, iter.Val())
+	}
+
+	if err := iter.Err(); err != nil {
+		logrus.Errorf("Failed clear cache keys %s : %+v", pattern, err)
+	}
 }
 
 func ClearUsers(db *gorm.DB) {
@@ -32,6 +107,106 @@ func ClearToken(db *gorm.DB) {
 	}
 }
 
+func ClearAuditLogs(db *gorm.DB) {
+	err := db.Where("id is not null").Delete(&model.AuditLog{}).Error
+	if err != nil {
+		logrus.Fatalf("Failed clear audit logs : %+v", err)
+	}
+}
+
+// ClearQuotas removes quota overrides, persisted usage and the counters in the cache store
+func ClearQuotas(db *gorm.DB) {
+	if err := db.Where("subject is not null").Delete(&model.RequestQuota{}).Error; err != nil {
+		logrus.Fatalf("Failed clear request quotas : %+v", err)
+	}
+	if err := db.Where("subject is not null").Delete(&model.RequestQuotaUsage{}).Error; err != nil {
+		logrus.Fatalf("Failed clear request quota usage : %+v", err)
+	}
+	clearCacheKeys(cache.QuotaKeyPrefix + "*")
+}
+
+func ClearACL(db *gorm.DB) {
+	if err := db.Where("id is not null").Delete(&model.ACLEntry{}).Error; err != nil {
+		logrus.Fatalf("Failed clear ACL entries : %+v", err)
+	}
+}
+
+func ClearPartners(db *gorm.DB) {
+	if err := db.Where("id is not null").Delete(&model.Partner{}).Error; err != nil {
+		logrus.Fatalf("Failed clear partners : %+v", err)
+	}
+}
+
+// ClearOAuthClients removes third-party clients along with their consents and tokens
+func ClearOAuthClients(db *gorm.DB) {
+	if err := db.Where("id is not null").Delete(&model.OAuthClient{}).Error; err != nil {
+		logrus.Fatalf("Failed clear OAuth clients : %+v", err)
+	}
+}
+
+func ClearServiceAccounts(db *gorm.DB) {
+	if err := db.Where("id is not null").Delete(&model.ServiceAccount{}).Error; err != nil {
+		logrus.Fatalf("Failed clear service accounts : %+v", err)
+	}
+}
+
+func ClearBulkJobs(db *gorm.DB) {
+	if err := db.Where("id is not null").Delete(&model.BulkJob{}).Error; err != nil {
+		logrus.Fatalf("Failed clear bulk jobs : %+v", err)
+	}
+}
+
+func ClearOutboxEmails(db *gorm.DB) {
+	if err := db.Where("id is not null").Delete(&model.OutboxEmail{}).Error; err != nil {
+		logrus.Fatalf("Failed clear outbox emails : %+v", err)
+	}
+}
+
+func ClearEmailSuppressions(db *gorm.DB) {
+	if err := db.Where("id is not null").Delete(&model.EmailSuppression{}).Error; err != nil {
+		logrus.Fatalf("Failed clear email suppressions : %+v", err)
+	}
+}
+
+// ClearLegalHolds lifts every hold; users under hold cannot be deleted
+func ClearLegalHolds(db *gorm.DB) {
+	if err := db.Where("user_id is not null").Delete(&model.LegalHold{}).Error; err != nil {
+		logrus.Fatalf("Failed clear legal holds : %+v", err)
+	}
+}
+
+func ClearSIEMCursors(db *gorm.DB) {
+	if err := db.Where("sink is not null").Delete(&model.SIEMCursor{}).Error; err != nil {
+		logrus.Fatalf("Failed clear SIEM cursors : %+v", err)
+	}
+}
+
+func ClearNotifications(db *gorm.DB) {
+	if err := db.Where("id is not null").Delete(&model.TextMessage{}).Error; err != nil {
+		logrus.Fatalf("Failed clear text messages : %+v", err)
+	}
+	if err := db.Where("user_id is not null").Delete(&model.NotificationPreference{}).Error; err != nil {
+		logrus.Fatalf("Failed clear notification preferences : %+v", err)
+	}
+	if err := db.Where("user_id is not null").Delete(&model.UserPhone{}).Error; err != nil {
+		logrus.Fatalf("Failed clear user phones : %+v", err)
+	}
+	if err := db.Where("id is not null").Delete(&model.PushDevice{}).Error; err != nil {
+		logrus.Fatalf("Failed clear push devices : %+v", err)
+	}
+}
+
+// InsertACLEntry grants a permission directly, as an app would when a record is created
+func InsertACLEntry(db *gorm.DB, resourceType, resourceID, principal, permission string) *model.ACLEntry {
+	entry := &model.ACLEntry{
+		ResourceType: resourceType, ResourceID: resourceID, Principal: principal, Permission: permission,
+	}
+	if err := db.Create(entry).Error; err != nil {
+		logrus.Errorf("Failed to create ACL entry: %+v", err)
+	}
+	return entry
+}
+
 func CreateUser(db *gorm.DB, email, password, name string) {
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
@@ -85,6 +260,25 @@ func SaveToken(db *gorm.DB, token, userID, tokenType string, expires time.Time)
 	return result.Error
 }
 
+func SaveVerifyEmailToken(db *gorm.DB, token string, user *model.User, expires time.Time) error {
+	if err := DeleteToken(db, config.TokenTypeVerifyEmail, user.ID.String()); err != nil {
+		return err
+	}
+
+	email := user.Email
+	tokenDoc := &model.Token{
+		Token:   token,
+		UserID:  user.ID,
+		Type:    config.TokenTypeVerifyEmail,
+		Expires: expires,
+		Email:   &email,
+	}
+
+	result := db.Create(tokenDoc)
+
+	return result.Error
+}
+
 func DeleteToken(db *gorm.DB, tokenType, userID string) error {
 	tokenDoc := new(model.Token)
 
@@ -101,6 +295,8 @@ func GenerateToken(
 		"iat":  time.Now().Unix(),
 		"exp":  expires.Unix(),
 		"type": tokenType,
+		"iss":  config.JWTIssuer,
+		"aud":  config.JWTAudience,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -115,6 +311,8 @@ func GenerateInvalidToken(
 		"iat":  time.Now().Unix(),
 		"exp":  expires.Unix(),
 		"type": tokenType,
+		"iss":  config.JWTIssuer,
+		"aud":  config.JWTAudience,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -165,3 +363,33 @@ func GetUserByID(db *gorm.DB, id string) (*model.User, error) {
 
 	return user, result.Error
 }
+
+// ClearBillingEvents forgets processed webhooks; subscriptions go with their users
+func ClearBillingEvents(db *gorm.DB) {
+	if err := db.Where("id is not null").Delete(&model.BillingEvent{}).Error; err != nil {
+		logrus.Fatalf("Failed clear billing events : %+v", err)
+	}
+}
+
+// ClearUsage removes rolled up usage, which outlives users, and the counters in the cache store
+func ClearUsage(db *gorm.DB) {
+	if err := db.Where("subject is not null").Delete(&model.UsageRecord{}).Error; err != nil {
+		logrus.Fatalf("Failed clear usage records : %+v", err)
+	}
+	clearCacheKeys(cache.UsageKeyPrefix + "*")
+}
+
+// ClearAnalytics removes saved unique visitor sketches and the live ones in Redis
+func ClearAnalytics(db *gorm.DB) {
+	if err := db.Where("kind is not null").Delete(&model.AnalyticsSketch{}).Error; err != nil {
+		logrus.Fatalf("Failed clear analytics sketches : %+v", err)
+	}
+	clearCacheKeys(analytics.KeyPrefix + "*")
+}
+
+// ClearLockFences removes the fencing tokens recorded by writes made under a lock
+func ClearLockFences(db *gorm.DB) {
+	if err := db.Exec("DELETE FROM lock_fences").Error; err != nil {
+		logrus.Fatalf("Failed clear lock fences : %+v", err)
+	}
+}
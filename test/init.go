@@ -1,9 +1,12 @@
 package test
 
 import (
+	"app/src/config"
 	"app/src/database"
+	"app/src/middleware"
 	"app/src/router"
 	"app/src/utils"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -18,7 +21,17 @@ var Log = utils.Log
 
 func init() {
 	// TODO: You can modify host and database configuration for tests
+	// Install the fault injectors; tests switch faults on with chaos.Set
+	config.Chaos.Enabled = true
+	// Plans for the quota admin routes; quotas themselves stay disabled
+	config.Quota.Plans = map[string]config.QuotaLimits{"free": {Daily: 1000, Monthly: 20000}, "pro": {Monthly: 1000000}}
+	// Webhooks are signed with this secret in the billing tests
+	config.Billing.WebhookSecret = "whsec_test"
+	config.Billing.PricePlans = map[string]string{"price_pro": "pro"}
+	// Bulk actions run soon after they are queued, so the tests can wait for them
+	config.BulkActions.PollInterval = 100 * time.Millisecond
 	DB = database.Connect("localhost", "testdb")
+	App.Use(middleware.RequestID())
 	router.Routes(App, DB)
 	App.Use(utils.NotFoundHandler)
 }
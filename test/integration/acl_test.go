@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACLRoutes(t *testing.T) {
+	grant := func(t *testing.T, token, principal, permission string) (int, *response.SuccessWithACLEntry) {
+		bodyJSON, err := json.Marshal(&validation.GrantACL{Principal: principal, Permission: permission})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/acl/document/42", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithACLEntry)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	list := func(t *testing.T, token string) (int, *response.SuccessWithACLEntries) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/acl/document/42", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithACLEntries)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	revoke := func(t *testing.T, token, entryID string) int {
+		request := httptest.NewRequest(http.MethodDelete, "/v1/acl/document/42/"+entryID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		return apiResponse.StatusCode
+	}
+
+	t.Run("POST /v1/acl/:resourceType/:resourceId", func(t *testing.T) {
+		t.Run("should return 201 and let the owner share the record", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+			helper.InsertACLEntry(test.DB, "document", "42", "user:"+fixture.UserOne.ID.String(), model.ACLOwner)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status, body := grant(t, userOneAccessToken, "user:"+fixture.UserTwo.ID.String(), model.ACLWrite)
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Equal(t, "write", body.Entry.Permission)
+			assert.Equal(t, &fixture.UserOne.ID, body.Entry.GrantedBy)
+
+			status, _ = grant(t, userOneAccessToken, "user:"+fixture.UserTwo.ID.String(), model.ACLWrite)
+			assert.Equal(t, http.StatusConflict, status)
+		})
+
+		t.Run("should return 403 if the caller only has write permission", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+			helper.InsertACLEntry(test.DB, "document", "42", "user:"+fixture.UserTwo.ID.String(), model.ACLWrite)
+
+			userTwoAccessToken, err := fixture.AccessToken(fixture.UserTwo)
+			assert.Nil(t, err)
+
+			status, _ := grant(t, userTwoAccessToken, "role:user", model.ACLRead)
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+
+		t.Run("should return 400 if the principal is invalid", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := grant(t, adminAccessToken, "everyone", model.ACLRead)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	})
+
+	t.Run("GET /v1/acl/:resourceType/:resourceId", func(t *testing.T) {
+		t.Run("should return 200 and the entries to admins without a grant", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+			helper.InsertACLEntry(test.DB, "document", "42", "user:"+fixture.UserOne.ID.String(), model.ACLOwner)
+			helper.InsertACLEntry(test.DB, "document", "42", "tag:beta", model.ACLRead)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, body := list(t, adminAccessToken)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Len(t, body.Entries, 2)
+		})
+
+		t.Run("should let a role grant stand in for a user grant", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+			helper.InsertACLEntry(test.DB, "document", "42", "role:user", model.ACLOwner)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status, _ := list(t, userOneAccessToken)
+			assert.Equal(t, http.StatusOK, status)
+		})
+	})
+
+	t.Run("DELETE /v1/acl/:resourceType/:resourceId/:entryId", func(t *testing.T) {
+		t.Run("should return 200 and revoke the entry", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+			helper.InsertACLEntry(test.DB, "document", "42", "user:"+fixture.UserOne.ID.String(), model.ACLOwner)
+			entry := helper.InsertACLEntry(test.DB, "document", "42", "user:"+fixture.UserTwo.ID.String(), model.ACLRead)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusOK, revoke(t, userOneAccessToken, entry.ID.String()))
+			assert.Equal(t, http.StatusNotFound, revoke(t, userOneAccessToken, entry.ID.String()))
+		})
+
+		t.Run("should return 409 when removing the last owner", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+			owner := helper.InsertACLEntry(test.DB, "document", "42", "user:"+fixture.UserOne.ID.String(), model.ACLOwner)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusConflict, revoke(t, userOneAccessToken, owner.ID.String()))
+		})
+	})
+}
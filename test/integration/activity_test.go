@@ -0,0 +1,150 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityRoutes(t *testing.T) {
+	login := func(t *testing.T, password string) {
+		bodyJSON, err := json.Marshal(&validation.Login{Email: "test@gmail.com", Password: password})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+
+		_, err = test.App.Test(request)
+		assert.Nil(t, err)
+	}
+
+	getActivity := func(t *testing.T, token, url string) (int, *response.SuccessWithPaginate[response.ActivityEvent]) {
+		request := httptest.NewRequest(http.MethodGet, url, nil)
+		request.Header.Set("Accept", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithPaginate[response.ActivityEvent])
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	t.Run("GET /v1/admin/users/:userId/activity", func(t *testing.T) {
+		t.Run("should return 200 and the user's logins and issued tokens newest first", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+			helper.CreateUser(test.DB, "test@gmail.com", "test1234", "Test User")
+
+			login(t, "wrong-password")
+			login(t, "test1234")
+
+			user := new(struct{ ID string })
+			assert.Nil(t, test.DB.Table("users").Where("email = ?", "test@gmail.com").Scan(user).Error)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, body := getActivity(t, adminAccessToken, "/v1/admin/users/"+user.ID+"/activity")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, int64(3), body.TotalResults)
+
+			actions := make([]string, len(body.Results))
+			for i, event := range body.Results {
+				actions[i] = event.Action
+			}
+			assert.Contains(t, actions, "login.failed")
+			assert.Contains(t, actions, "login.succeeded")
+			assert.Contains(t, actions, "token.issued")
+			for i := 1; i < len(body.Results); i++ {
+				assert.False(t, body.Results[i].OccurredAt.After(body.Results[i-1].OccurredAt))
+			}
+
+			status, body = getActivity(t, adminAccessToken, "/v1/admin/users/"+user.ID+"/activity?types=token&limit=1")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, int64(1), body.TotalResults)
+			assert.Equal(t, "token", body.Results[0].Type)
+		})
+
+		t.Run("should filter by days in the caller's timezone", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			jakarta := "Asia/Jakarta"
+			admin := *fixture.Admin
+			admin.Timezone = &jakarta
+			helper.InsertUser(test.DB, &admin, fixture.UserOne)
+
+			// 2026-03-14 17:00 and 2026-03-15 03:00 in Jakarta (UTC+7)
+			for _, at := range []time.Time{
+				time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 14, 20, 0, 0, 0, time.UTC),
+			} {
+				assert.Nil(t, test.DB.Create(&model.AuditLog{
+					UserID: &fixture.UserOne.ID, Action: model.AuditActionLoginSucceeded, CreatedAt: at,
+				}).Error)
+			}
+
+			adminAccessToken, err := fixture.AccessToken(&admin)
+			assert.Nil(t, err)
+
+			url := "/v1/admin/users/" + fixture.UserOne.ID.String() + "/activity?from=2026-03-15&to=2026-03-15"
+			status, body := getActivity(t, adminAccessToken, url)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, int64(1), body.TotalResults)
+			assert.Equal(t, time.Date(2026, 3, 14, 20, 0, 0, 0, time.UTC), body.Results[0].OccurredAt.UTC())
+
+			url = "/v1/admin/users/" + fixture.UserOne.ID.String() + "/activity?from=2026-03-15&to=2026-03-14"
+			status, _ = getActivity(t, adminAccessToken, url)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("should return 400 for an unknown event type", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := getActivity(t, adminAccessToken, "/v1/admin/users/"+fixture.UserOne.ID.String()+"/activity?types=payments")
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("should return 403 if the caller is not an admin", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			accessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status, _ := getActivity(t, accessToken, "/v1/admin/users/"+fixture.UserOne.ID.String()+"/activity")
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+
+		t.Run("should return 404 if the user does not exist", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := getActivity(t, adminAccessToken, "/v1/admin/users/"+fixture.UserTwo.ID.String()+"/activity")
+			assert.Equal(t, http.StatusNotFound, status)
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"app/src/analytics"
+	"app/src/model"
+	"app/src/response"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsRoutes(t *testing.T) {
+	insertSketches := func(t *testing.T) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+		// Reports read the saved counts; the registers are only merged back into Redis
+		sketches := []model.AnalyticsSketch{
+			{Kind: analytics.KindDailyUsers, Period: "2026-09-01", Sketch: []byte("HYLL"), Count: 12},
+			{Kind: analytics.KindDailyUsers, Period: "2026-09-02", Sketch: []byte("HYLL"), Count: 20},
+			{Kind: analytics.KindMonthlyUsers, Period: "2026-09", Sketch: []byte("HYLL"), Count: 30},
+			{
+				Kind: analytics.KindEndpointIPs, Period: "2026-09-01", Endpoint: "GET /v1/users",
+				Sketch: []byte("HYLL"), Count: 40,
+			},
+			{
+				Kind: analytics.KindEndpointIPs, Period: "2026-09-01", Endpoint: "POST /v1/auth/login",
+				Sketch: []byte("HYLL"), Count: 90,
+			},
+		}
+		assert.Nil(t, test.DB.Create(&sketches).Error)
+	}
+
+	get := func(t *testing.T, user *model.User, url string, body any) int {
+		accessToken, err := fixture.AccessToken(user)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodGet, url, nil)
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+		_ = json.Unmarshal(bytes, body)
+
+		return apiResponse.StatusCode
+	}
+
+	t.Run("GET /v1/admin/analytics/active-users", func(t *testing.T) {
+		t.Run("should return 200 and the active users of every day and month of the range", func(t *testing.T) {
+			insertSketches(t)
+
+			body := new(response.SuccessWithActiveUsers)
+			status := get(t, fixture.Admin, "/v1/admin/analytics/active-users?from=2026-09-01&to=2026-09-03", body)
+			assert.Equal(t, http.StatusOK, status)
+
+			assert.Equal(t, []response.ActivePeriod{
+				{Period: "2026-09-01", Users: 12},
+				{Period: "2026-09-02", Users: 20},
+				{Period: "2026-09-03", Users: 0},
+			}, body.ActiveUsers.Days)
+			assert.Equal(t, []response.ActivePeriod{{Period: "2026-09", Users: 30}}, body.ActiveUsers.Months)
+		})
+
+		t.Run("should return 400 if the range is reversed", func(t *testing.T) {
+			insertSketches(t)
+
+			url := "/v1/admin/analytics/active-users?from=2026-09-30&to=2026-09-01"
+			assert.Equal(t, http.StatusBadRequest, get(t, fixture.Admin, url, new(response.ErrorDetails)))
+		})
+
+		t.Run("should return 403 without the viewAnalytics right", func(t *testing.T) {
+			insertSketches(t)
+
+			url := "/v1/admin/analytics/active-users"
+			assert.Equal(t, http.StatusForbidden, get(t, fixture.UserOne, url, new(response.ErrorDetails)))
+		})
+	})
+
+	t.Run("GET /v1/admin/analytics/endpoints", func(t *testing.T) {
+		t.Run("should return 200 and the endpoints ranked by unique IPs", func(t *testing.T) {
+			insertSketches(t)
+
+			body := new(response.SuccessWithEndpointVisitors)
+			status := get(t, fixture.Admin, "/v1/admin/analytics/endpoints?day=2026-09-01", body)
+			assert.Equal(t, http.StatusOK, status)
+
+			assert.Equal(t, "2026-09-01", body.Visitors.Day)
+			assert.Equal(t, []response.EndpointVisitor{
+				{Endpoint: "POST /v1/auth/login", UniqueIPs: 90},
+				{Endpoint: "GET /v1/users", UniqueIPs: 40},
+			}, body.Visitors.Endpoints)
+		})
+
+		t.Run("should return at most limit endpoints", func(t *testing.T) {
+			insertSketches(t)
+
+			body := new(response.SuccessWithEndpointVisitors)
+			status := get(t, fixture.Admin, "/v1/admin/analytics/endpoints?day=2026-09-01&limit=1", body)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Len(t, body.Visitors.Endpoints, 1)
+		})
+
+		t.Run("should return 400 for an invalid day", func(t *testing.T) {
+			insertSketches(t)
+
+			url := "/v1/admin/analytics/endpoints?day=01-09-2026"
+			assert.Equal(t, http.StatusBadRequest, get(t, fixture.Admin, url, new(response.ErrorDetails)))
+		})
+	})
+}
@@ -0,0 +1,199 @@
+package integration
+
+import (
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/siem"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogRoutes(t *testing.T) {
+	exportAs := func(t *testing.T, user *model.User, query string) (int, string) {
+		accessToken, err := fixture.AccessToken(user)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodGet, "/v1/admin/audit-logs/export?"+query, nil)
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		responseBody, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+		return apiResponse.StatusCode, string(responseBody)
+	}
+
+	seed := func(t *testing.T) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+
+		for _, entry := range []*model.AuditLog{
+			{UserID: &fixture.UserOne.ID, Action: model.AuditActionRoleChanged, Metadata: `{"role":"admin"}`,
+				CreatedAt: time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)},
+			{UserID: &fixture.UserOne.ID, Action: model.AuditActionLegalHoldPlaced, Metadata: `{}`,
+				CreatedAt: time.Date(2026, 10, 2, 23, 59, 0, 0, time.UTC)},
+			{Action: model.AuditActionRetentionPurged, Metadata: `{}`,
+				CreatedAt: time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)},
+		} {
+			assert.Nil(t, test.DB.Create(entry).Error)
+		}
+	}
+
+	t.Run("GET /v1/admin/audit-logs/export", func(t *testing.T) {
+		t.Run("should stream the entries of the days as CSV, oldest first", func(t *testing.T) {
+			seed(t)
+
+			statusCode, body := exportAs(t, fixture.Admin, "from=2026-10-01&to=2026-10-02")
+			assert.Equal(t, http.StatusOK, statusCode)
+
+			rows, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+			assert.Nil(t, err)
+			assert.Len(t, rows, 3)
+			assert.Equal(t, siem.CSVColumns, rows[0])
+			assert.Equal(t, model.AuditActionRoleChanged, rows[1][2])
+			assert.Equal(t, model.AuditActionLegalHoldPlaced, rows[2][2])
+
+			var audited int64
+			test.DB.Model(new(model.AuditLog)).Where("action = ?", model.AuditActionAuditLogsExported).Count(&audited)
+			assert.Equal(t, int64(1), audited)
+		})
+
+		t.Run("should narrow NDJSON exports to an action", func(t *testing.T) {
+			seed(t)
+
+			statusCode, body := exportAs(t, fixture.Admin,
+				"from=2026-10-01&to=2026-10-03&format=ndjson&action="+model.AuditActionRoleChanged)
+			assert.Equal(t, http.StatusOK, statusCode)
+
+			lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+			assert.Len(t, lines, 1)
+			var event siem.Event
+			assert.Nil(t, json.Unmarshal([]byte(lines[0]), &event))
+			assert.Equal(t, fixture.UserOne.ID, *event.UserID)
+			assert.JSONEq(t, `{"role":"admin"}`, string(event.Metadata))
+		})
+
+		t.Run("should return 400 error if the range ends before it starts", func(t *testing.T) {
+			seed(t)
+
+			statusCode, _ := exportAs(t, fixture.Admin, "from=2026-10-03&to=2026-10-01")
+			assert.Equal(t, http.StatusBadRequest, statusCode)
+		})
+
+		t.Run("should return 403 error if user is not admin", func(t *testing.T) {
+			seed(t)
+
+			statusCode, _ := exportAs(t, fixture.UserOne, "from=2026-10-01&to=2026-10-03")
+			assert.Equal(t, http.StatusForbidden, statusCode)
+		})
+	})
+}
+
+// captureSink keeps the batches sent to it, failing the first failures calls
+type captureSink struct {
+	mu       sync.Mutex
+	failures int
+	events   []siem.Event
+}
+
+func (s *captureSink) Send(_ context.Context, events []siem.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return io.ErrUnexpectedEOF
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *captureSink) Close() error {
+	return nil
+}
+
+func (s *captureSink) received() []siem.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]siem.Event(nil), s.events...)
+}
+
+func TestSIEMForwarder(t *testing.T) {
+	cfg := config.SIEMConfig{
+		Sink: "test", PollInterval: 50 * time.Millisecond, BatchSize: 2, BufferSize: 2, Timeout: time.Second,
+	}
+
+	t.Run("should forward new entries in order and resume after the last delivered", func(t *testing.T) {
+		helper.ClearAll(test.DB)
+
+		start := time.Now().UTC().Truncate(time.Millisecond).Add(-time.Minute)
+		clk := clock.NewMock(start)
+		sink := &captureSink{failures: 1}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.Nil(t, siem.NewForwarder(test.DB, sink, cfg, clk).Run(ctx))
+		}()
+
+		// The forwarder starts its cursor on first use, before the clock moves
+		assert.Eventually(t, func() bool {
+			return test.DB.First(new(model.SIEMCursor), "sink = ?", cfg.Sink).Error == nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// Recorded before the sink was enabled, so never forwarded
+		assert.Nil(t, test.DB.Create(&model.AuditLog{
+			Action: model.AuditActionRoleChanged, Metadata: `{}`, CreatedAt: start.Add(-time.Hour),
+		}).Error)
+		for i := 1; i <= 5; i++ {
+			assert.Nil(t, test.DB.Create(&model.AuditLog{
+				Action: model.AuditActionRetentionPurged, Metadata: `{}`,
+				CreatedAt: start.Add(time.Duration(i) * time.Second),
+			}).Error)
+		}
+		clk.Advance(time.Minute)
+
+		// The first batch is refused once and retried after a second
+		assert.Eventually(t, func() bool { return len(sink.received()) == 5 }, 5*time.Second, 20*time.Millisecond)
+		cancel()
+		<-done
+
+		events := sink.received()
+		for i := 1; i < len(events); i++ {
+			assert.True(t, events[i-1].Time.Before(events[i].Time))
+		}
+
+		saved := new(model.SIEMCursor)
+		assert.Nil(t, test.DB.First(saved, "sink = ?", cfg.Sink).Error)
+		assert.Equal(t, events[4].ID, saved.LastID)
+
+		// A new run resumes after the cursor
+		assert.Nil(t, test.DB.Create(&model.AuditLog{
+			Action: model.AuditActionRoleChanged, Metadata: `{}`, CreatedAt: start.Add(10 * time.Second),
+		}).Error)
+		resumed := &captureSink{}
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			_ = siem.NewForwarder(test.DB, resumed, cfg, clk).Run(ctx)
+		}()
+
+		assert.Eventually(t, func() bool { return len(resumed.received()) == 1 }, 5*time.Second, 20*time.Millisecond)
+		assert.Equal(t, model.AuditActionRoleChanged, resumed.received()[0].Action)
+	})
+}
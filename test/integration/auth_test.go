@@ -2,6 +2,7 @@ package integration
 
 import (
 	"app/src/config"
+	"app/src/model"
 	"app/src/response"
 	"app/src/utils"
 	"app/src/validation"
@@ -13,7 +14,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -71,6 +74,41 @@ func TestAuthRoutes(t *testing.T) {
 			assert.Equal(t, user.VerifiedEmail, false)
 		})
 
+		t.Run("should queue a verification email for the new user", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			bodyJSON, err := json.Marshal(requestBody)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/auth/register", strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+
+			apiResponse, err := test.App.Test(request, 2000)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusCreated, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithTokens)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+
+			var verifyTokens int64
+			test.DB.Model(new(model.Token)).
+				Where("user_id = ? AND type = ?", responseBody.User.ID, config.TokenTypeVerifyEmail).
+				Count(&verifyTokens)
+			assert.Equal(t, int64(1), verifyTokens)
+
+			var queued int64
+			test.DB.Model(new(model.OutboxEmail)).Where("recipient = ?", requestBody.Email).Count(&queued)
+			var sent int64
+			test.DB.Model(new(model.AuditLog)).
+				Where("user_id = ? AND action = ?", responseBody.User.ID, model.AuditActionEmailSent).
+				Count(&sent)
+			// The outbox job may already have sent and removed the email
+			assert.LessOrEqual(t, queued, int64(1))
+			assert.Equal(t, int64(1), sent)
+		})
+
 		t.Run("should return 400 error if email is invalid", func(t *testing.T) {
 			helper.ClearAll(test.DB)
 			requestBody.Email = "invalidEmail"
@@ -250,6 +288,122 @@ func TestAuthRoutes(t *testing.T) {
 			assert.Equal(t, "error", responseBody["status"])
 			assert.Equal(t, "Invalid email or password", responseBody["message"])
 		})
+
+		login := func(t *testing.T) *http.Response {
+			bodyJSON, err := json.Marshal(&validation.Login{Email: "test@gmail.com", Password: "test1234"})
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Accept", "application/json")
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			return apiResponse
+		}
+
+		t.Run("should delay the next login for that email after a wrong password", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.CreateUser(test.DB, "test@gmail.com", "test1234", "Test User")
+
+			bodyJSON, err := json.Marshal(&validation.Login{Email: "test@gmail.com", Password: "wrongPassword1"})
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+
+			apiResponse = login(t)
+			assert.Equal(t, http.StatusTooManyRequests, apiResponse.StatusCode)
+			assert.Equal(t, "1", apiResponse.Header.Get("Retry-After"))
+		})
+
+		t.Run("should evict the oldest session when the session limit is reached", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.CreateUser(test.DB, "test@gmail.com", "test1234", "Test User")
+
+			defer func(max int, policy string) {
+				config.MaxSessionsPerUser, config.SessionLimitPolicy = max, policy
+			}(config.MaxSessionsPerUser, config.SessionLimitPolicy)
+			config.MaxSessionsPerUser, config.SessionLimitPolicy = 2, config.SessionLimitEvictOldest
+
+			refreshTokens := make([]string, 3)
+			accessTokens := make([]string, 3)
+			for i := range refreshTokens {
+				apiResponse := login(t)
+				assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+				responseBody := new(response.SuccessWithTokens)
+				bytes, err := io.ReadAll(apiResponse.Body)
+				assert.Nil(t, err)
+				assert.Nil(t, json.Unmarshal(bytes, responseBody))
+				refreshTokens[i] = responseBody.Tokens.Refresh.Token
+				accessTokens[i] = responseBody.Tokens.Access.Token
+			}
+
+			evicted, _ := helper.GetTokenByUserID(test.DB, refreshTokens[0])
+			assert.Nil(t, evicted)
+			for _, refreshToken := range refreshTokens[1:] {
+				active, err := helper.GetTokenByUserID(test.DB, refreshToken)
+				assert.Nil(t, err)
+				assert.NotNil(t, active)
+			}
+
+			// The evicted session's access token stops working before it expires
+			authenticate := func(accessToken string) int {
+				request := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+				request.Header.Set("Authorization", "Bearer "+accessToken)
+
+				apiResponse, err := test.App.Test(request)
+				assert.Nil(t, err)
+				return apiResponse.StatusCode
+			}
+			assert.Equal(t, http.StatusUnauthorized, authenticate(accessTokens[0]))
+			assert.NotEqual(t, http.StatusUnauthorized, authenticate(accessTokens[2]))
+		})
+
+		t.Run("should not exceed the session limit with concurrent logins", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.CreateUser(test.DB, "test@gmail.com", "test1234", "Test User")
+
+			defer func(max int, policy string) {
+				config.MaxSessionsPerUser, config.SessionLimitPolicy = max, policy
+			}(config.MaxSessionsPerUser, config.SessionLimitPolicy)
+			config.MaxSessionsPerUser, config.SessionLimitPolicy = 2, config.SessionLimitReject
+
+			var wg sync.WaitGroup
+			statuses := make([]int, 5)
+			for i := range statuses {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					statuses[i] = login(t).StatusCode
+				}()
+			}
+			wg.Wait()
+
+			var sessions int64
+			err := test.DB.Model(new(model.Token)).Where("type = ?", config.TokenTypeRefresh).Count(&sessions).Error
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), sessions)
+			assert.Equal(t, []int{200, 200, 409, 409, 409}, slices.Sorted(slices.Values(statuses)))
+		})
+
+		t.Run("should return 409 error when the session limit is reached and the policy is reject", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.CreateUser(test.DB, "test@gmail.com", "test1234", "Test User")
+
+			defer func(max int, policy string) {
+				config.MaxSessionsPerUser, config.SessionLimitPolicy = max, policy
+			}(config.MaxSessionsPerUser, config.SessionLimitPolicy)
+			config.MaxSessionsPerUser, config.SessionLimitPolicy = 1, config.SessionLimitReject
+
+			assert.Equal(t, http.StatusOK, login(t).StatusCode)
+			assert.Equal(t, http.StatusConflict, login(t).StatusCode)
+		})
 	})
 	t.Run("POST /v1/auth/logout", func(t *testing.T) {
 		t.Run("should return 200 if refresh token is valid", func(t *testing.T) {
@@ -347,6 +501,72 @@ func TestAuthRoutes(t *testing.T) {
 			assert.Equal(t, dbRefreshTokenDoc.Type, config.TokenTypeRefresh)
 		})
 
+		t.Run("should revoke the session if a bound refresh token comes without its cookie", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			refresh := func(refreshToken string, binding *http.Cookie) (*http.Response, *response.RefreshToken) {
+				bodyJSON, err := json.Marshal(validation.RefreshToken{RefreshToken: refreshToken})
+				assert.Nil(t, err)
+
+				request := httptest.NewRequest(http.MethodPost, "/v1/auth/refresh-tokens", strings.NewReader(string(bodyJSON)))
+				request.Header.Set("Content-Type", "application/json")
+				if binding != nil {
+					request.AddCookie(binding)
+				}
+
+				apiResponse, err := test.App.Test(request)
+				assert.Nil(t, err)
+
+				responseBody := new(response.RefreshToken)
+				bytes, err := io.ReadAll(apiResponse.Body)
+				assert.Nil(t, err)
+				_ = json.Unmarshal(bytes, responseBody)
+
+				return apiResponse, responseBody
+			}
+			bindingCookie := func(apiResponse *http.Response) *http.Cookie {
+				for _, cookie := range apiResponse.Cookies() {
+					if cookie.Name == config.RefreshBindingCookie {
+						return cookie
+					}
+				}
+				return nil
+			}
+
+			// Tokens issued before bindings existed are accepted once and replaced by bound ones
+			refreshToken, err := fixture.RefreshToken(fixture.UserOne)
+			assert.Nil(t, err)
+			err = helper.SaveToken(test.DB, refreshToken, fixture.UserOne.ID.String(), config.TokenTypeRefresh, fixture.ExpiresRefreshToken)
+			assert.Nil(t, err)
+
+			apiResponse, body := refresh(refreshToken, nil)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+			binding := bindingCookie(apiResponse)
+			assert.NotNil(t, binding)
+
+			apiResponse, body = refresh(body.Tokens.Refresh.Token, binding)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			apiResponse, _ = refresh(body.Tokens.Refresh.Token, &http.Cookie{Name: config.RefreshBindingCookie, Value: "stolen"})
+			assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+
+			// The session's access token stops working before it expires
+			request := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+			request.Header.Set("Authorization", "Bearer "+body.Tokens.Access.Token)
+			apiResponse, err = test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+
+			var remaining int64
+			test.DB.Model(new(model.Token)).Where("user_id = ? AND type = ?", fixture.UserOne.ID, config.TokenTypeRefresh).Count(&remaining)
+			assert.Zero(t, remaining)
+
+			var audited int64
+			test.DB.Model(new(model.AuditLog)).Where("action = ?", model.AuditActionRefreshReplayed).Count(&audited)
+			assert.Equal(t, int64(1), audited)
+		})
+
 		t.Run("should return 400 error if refresh token is missing from request body", func(t *testing.T) {
 			request := httptest.NewRequest(http.MethodPost, "/v1/auth/refresh-tokens", nil)
 			request.Header.Set("Content-Type", "application/json")
@@ -673,7 +893,7 @@ func TestAuthRoutes(t *testing.T) {
 			verifyEmailToken, err := fixture.VerifyEmailToken(fixture.UserOne)
 			assert.Nil(t, err)
 
-			err = helper.SaveToken(test.DB, verifyEmailToken, fixture.UserOne.ID.String(), config.TokenTypeVerifyEmail, fixture.ExpiresVerifyEmailToken)
+			err = helper.SaveVerifyEmailToken(test.DB, verifyEmailToken, fixture.UserOne, fixture.ExpiresVerifyEmailToken)
 			assert.Nil(t, err)
 
 			request := httptest.NewRequest(http.MethodPost, "/v1/auth/verify-email?token="+verifyEmailToken, nil)
@@ -690,8 +910,59 @@ func TestAuthRoutes(t *testing.T) {
 
 			assert.True(t, user.VerifiedEmail)
 
-			dbVerifyEmailTokenDoc, _ := helper.GetTokenByUserID(test.DB, verifyEmailToken)
-			assert.Nil(t, dbVerifyEmailTokenDoc)
+			dbVerifyEmailTokenDoc, err := helper.GetTokenByType(test.DB, fixture.UserOne.ID.String(), config.TokenTypeVerifyEmail)
+			assert.Nil(t, err)
+			assert.NotNil(t, dbVerifyEmailTokenDoc.ConsumedAt)
+		})
+
+		t.Run("should return 409 if the verification link was already used", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			verifyEmailToken, err := fixture.VerifyEmailToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			err = helper.SaveVerifyEmailToken(test.DB, verifyEmailToken, fixture.UserOne, fixture.ExpiresVerifyEmailToken)
+			assert.Nil(t, err)
+
+			for _, status := range []int{http.StatusOK, http.StatusConflict} {
+				request := httptest.NewRequest(http.MethodPost, "/v1/auth/verify-email?token="+verifyEmailToken, nil)
+				request.Header.Set("Content-Type", "application/json")
+				request.Header.Set("Accept", "application/json")
+
+				apiResponse, err := test.App.Test(request)
+				assert.Nil(t, err)
+
+				assert.Equal(t, status, apiResponse.StatusCode)
+			}
+		})
+
+		t.Run("should return 401 if the email changed after the link was issued", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			verifyEmailToken, err := fixture.VerifyEmailToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			err = helper.SaveVerifyEmailToken(test.DB, verifyEmailToken, fixture.UserOne, fixture.ExpiresVerifyEmailToken)
+			assert.Nil(t, err)
+
+			err = test.DB.Model(&model.User{}).Where("id = ?", fixture.UserOne.ID).Update("email", "changed@example.com").Error
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/auth/verify-email?token="+verifyEmailToken, nil)
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Accept", "application/json")
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+
+			user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+
+			assert.False(t, user.VerifiedEmail)
 		})
 
 		t.Run("should return 400 if verify email token is missing", func(t *testing.T) {
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"app/src/backup"
+	"app/src/model"
+	"app/src/schema"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+
+	seed := func(t *testing.T) *model.APIToken {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+
+		token := &model.APIToken{
+			UserID: fixture.UserOne.ID, Name: "ci", TokenHash: "hash-ci", Prefix: "pat_ci",
+			Scopes: "getUsers", ExpiresAt: time.Now().Add(24 * time.Hour),
+		}
+		assert.Nil(t, test.DB.Create(token).Error)
+		assert.Nil(t, test.DB.Create(&model.AuditLog{
+			UserID: &fixture.UserOne.ID, Action: model.AuditActionRoleChanged,
+			Metadata: `{"actor_id":"` + fixture.Admin.ID.String() + `"}`,
+		}).Error)
+		assert.Nil(t, test.DB.Create(&model.LegalHold{UserID: fixture.UserOne.ID, Reason: "Case 7"}).Error)
+		return token
+	}
+
+	t.Run("should restore an exported archive into an empty database", func(t *testing.T) {
+		token := seed(t)
+
+		var archive bytes.Buffer
+		exported, err := backup.Export(ctx, test.DB, &archive, "test", "correct horse")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), exported["users"])
+		assert.Equal(t, int64(1), exported["api_tokens"])
+
+		_, err = backup.Restore(ctx, test.DB, bytes.NewReader(archive.Bytes()), "correct horse")
+		assert.ErrorContains(t, err, "table users is not empty")
+
+		helper.ClearAll(test.DB)
+		restored, err := backup.Restore(ctx, test.DB, bytes.NewReader(archive.Bytes()), "correct horse")
+		assert.Nil(t, err)
+		assert.Equal(t, exported, restored)
+
+		user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+		assert.Nil(t, err)
+		assert.Equal(t, fixture.UserOne.Email, user.Email)
+		assert.Equal(t, fixture.UserOne.Role, user.Role)
+
+		restoredToken := new(model.APIToken)
+		assert.Nil(t, test.DB.First(restoredToken, "id = ?", token.ID).Error)
+		assert.Equal(t, token.TokenHash, restoredToken.TokenHash)
+
+		var held int64
+		test.DB.Model(new(model.LegalHold)).Where("user_id = ?", fixture.UserOne.ID).Count(&held)
+		assert.Equal(t, int64(1), held)
+	})
+
+	t.Run("should refuse dangling references and restore nothing", func(t *testing.T) {
+		helper.ClearAll(test.DB)
+
+		version, err := schema.Version(ctx, test.DB)
+		assert.Nil(t, err)
+
+		var archive bytes.Buffer
+		writer, err := backup.NewWriter(&archive, backup.Manifest{SchemaVersion: version}, "")
+		assert.Nil(t, err)
+
+		user, err := json.Marshal(map[string]any{
+			"id": fixture.Admin.ID, "name": "Admin", "email": "admin@example.com", "password": "x",
+			"role": "admin", "verified_email": true, "is_active": true, "type": "human",
+			"created_at": time.Now(), "updated_at": time.Now(),
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, writer.WriteRow("users", user))
+		assert.Nil(t, writer.WriteRow("api_tokens", json.RawMessage(
+			`{"id":"8f7c6a52-42e5-4f0e-9d63-2f6f3a0f1a11","user_id":"`+fixture.UserOne.ID.String()+`"}`,
+		)))
+		_, err = writer.Close()
+		assert.Nil(t, err)
+
+		_, err = backup.Restore(ctx, test.DB, &archive, "")
+		integrity := new(backup.IntegrityError)
+		assert.ErrorAs(t, err, &integrity)
+		assert.Equal(t, "api_tokens", integrity.Table)
+		assert.Equal(t, "user_id", integrity.Column)
+		assert.Equal(t, fixture.UserOne.ID.String(), integrity.Value)
+
+		var users int64
+		test.DB.Model(new(model.User)).Count(&users)
+		assert.Equal(t, int64(0), users)
+	})
+
+	t.Run("should refuse an archive made at another schema version", func(t *testing.T) {
+		helper.ClearAll(test.DB)
+
+		var archive bytes.Buffer
+		writer, err := backup.NewWriter(&archive, backup.Manifest{SchemaVersion: 20240929085103}, "")
+		assert.Nil(t, err)
+		_, err = writer.Close()
+		assert.Nil(t, err)
+
+		_, err = backup.Restore(ctx, test.DB, &archive, "")
+		assert.ErrorContains(t, err, "schema version 20240929085103")
+	})
+}
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"app/src/billing"
+	"app/src/model"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBillingRoutes(t *testing.T) {
+	event := func(id, eventType, status string, created time.Time) string {
+		return fmt.Sprintf(`{"id":%q,"type":%q,"created":%d,"data":{"object":{`+
+			`"id":"sub_1","customer":"cus_1","status":%q,"cancel_at_period_end":false,`+
+			`"items":{"data":[{"price":{"id":"price_pro"},"current_period_end":%d}]}}}}`,
+			id, eventType, created.Unix(), status, created.Add(30*24*time.Hour).Unix())
+	}
+
+	deliver := func(t *testing.T, payload, secret string) int {
+		now := time.Now().Unix()
+		signature := fmt.Sprintf("t=%d,v1=%s", now, billing.Sign([]byte(payload), secret, now))
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/billing/webhook", strings.NewReader(payload))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(billing.SignatureHeader, signature)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		return apiResponse.StatusCode
+	}
+
+	insertCustomer := func(t *testing.T) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserOne)
+		assert.Nil(t, test.DB.Model(fixture.UserOne).Update("stripe_customer_id", "cus_1").Error)
+	}
+
+	plan := func(t *testing.T) string {
+		user := new(model.User)
+		assert.Nil(t, test.DB.First(user, "id = ?", fixture.UserOne.ID).Error)
+		return user.Plan
+	}
+
+	t.Run("POST /v1/billing/webhook", func(t *testing.T) {
+		t.Run("should move the customer to the plan of their subscription", func(t *testing.T) {
+			insertCustomer(t)
+
+			status := deliver(t, event("evt_1", billing.EventSubscriptionCreated, "active", time.Now()), "whsec_test")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, "pro", plan(t))
+
+			subscription := new(model.Subscription)
+			assert.Nil(t, test.DB.First(subscription, "id = ?", "sub_1").Error)
+			assert.Equal(t, fixture.UserOne.ID, subscription.UserID)
+			assert.Equal(t, "price_pro", subscription.PriceID)
+		})
+
+		t.Run("should apply a redelivered event only once", func(t *testing.T) {
+			insertCustomer(t)
+
+			payload := event("evt_1", billing.EventSubscriptionCreated, "active", time.Now())
+			assert.Equal(t, http.StatusOK, deliver(t, payload, "whsec_test"))
+
+			// Downgrade by hand; replaying the event must not upgrade the user again
+			assert.Nil(t, test.DB.Model(fixture.UserOne).Update("plan", "free").Error)
+			assert.Equal(t, http.StatusOK, deliver(t, payload, "whsec_test"))
+			assert.Equal(t, "free", plan(t))
+		})
+
+		t.Run("should return the customer to the default plan when the subscription ends", func(t *testing.T) {
+			insertCustomer(t)
+
+			now := time.Now()
+			deliver(t, event("evt_1", billing.EventSubscriptionCreated, "active", now.Add(-time.Minute)), "whsec_test")
+
+			status := deliver(t, event("evt_2", billing.EventSubscriptionDeleted, "canceled", now), "whsec_test")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, "free", plan(t))
+		})
+
+		t.Run("should ignore an event older than the one already applied", func(t *testing.T) {
+			insertCustomer(t)
+
+			now := time.Now()
+			deliver(t, event("evt_2", billing.EventSubscriptionDeleted, "canceled", now), "whsec_test")
+
+			status := deliver(t, event("evt_1", billing.EventSubscriptionCreated, "active", now.Add(-time.Minute)), "whsec_test")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, "free", plan(t))
+		})
+
+		t.Run("should return 400 if the signature does not match", func(t *testing.T) {
+			insertCustomer(t)
+
+			status := deliver(t, event("evt_1", billing.EventSubscriptionCreated, "active", time.Now()), "whsec_other")
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Equal(t, "free", plan(t))
+		})
+	})
+}
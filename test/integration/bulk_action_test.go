@@ -0,0 +1,189 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkActionRoutes(t *testing.T) {
+	submit := func(t *testing.T, token, query string, req *validation.BulkUserAction) (int, *response.SuccessWithBulkJob) {
+		bodyJSON, err := json.Marshal(req)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/admin/users/bulk"+query, strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithBulkJob)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	// wait polls the job until the worker completes it
+	wait := func(t *testing.T, token string, jobID uuid.UUID) response.BulkJob {
+		var job response.BulkJob
+		assert.Eventually(t, func() bool {
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/users/bulk/"+jobID.String(), nil)
+			request.Header.Set("Authorization", "Bearer "+token)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithBulkJob)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+
+			job = responseBody.BulkJob
+			return job.Status == model.BulkJobCompleted
+		}, 5*time.Second, 50*time.Millisecond)
+		return job
+	}
+
+	t.Run("POST /v1/admin/users/bulk", func(t *testing.T) {
+		t.Run("should tag the listed users and report unknown IDs as failed", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne, fixture.UserTwo)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			unknown := uuid.New()
+			status, body := submit(t, adminAccessToken, "", &validation.BulkUserAction{
+				Action:  model.BulkActionAddTag,
+				Tag:     "beta",
+				UserIDs: []string{fixture.UserOne.ID.String(), fixture.UserTwo.ID.String(), unknown.String()},
+			})
+			assert.Equal(t, http.StatusAccepted, status)
+			assert.Equal(t, 3, body.BulkJob.Total)
+
+			job := wait(t, adminAccessToken, body.BulkJob.ID)
+			assert.Equal(t, 3, job.Processed)
+			assert.Equal(t, 2, job.Succeeded)
+			assert.Equal(t, 1, job.Failed)
+
+			var tagged int64
+			test.DB.Model(new(model.UserTag)).Where("tag = ?", "beta").Count(&tagged)
+			assert.Equal(t, int64(2), tagged)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/users/bulk/"+job.ID.String()+"/report", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+			assert.Contains(t, apiResponse.Header.Get("Content-Disposition"), "attachment")
+
+			rows, err := csv.NewReader(apiResponse.Body).ReadAll()
+			assert.Nil(t, err)
+			assert.Len(t, rows, 4)
+			assert.Contains(t, rows, []string{unknown.String(), model.BulkItemFailed, "User not found"})
+		})
+
+		t.Run("should deactivate the users a filter selects, skipping the caller", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne, fixture.UserTwo)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, body := submit(t, adminAccessToken, "", &validation.BulkUserAction{
+				Action: model.BulkActionDeactivate,
+				Filter: &validation.UserFilter{Type: model.UserTypeHuman},
+			})
+			assert.Equal(t, http.StatusAccepted, status)
+
+			job := wait(t, adminAccessToken, body.BulkJob.ID)
+			assert.Equal(t, 2, job.Succeeded)
+			assert.Equal(t, 1, job.Skipped)
+
+			var suspended int64
+			test.DB.Model(new(model.User)).Where("is_active = ?", false).Count(&suspended)
+			assert.Equal(t, int64(2), suspended)
+		})
+
+		t.Run("should only count the targeted users on a dry run", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := submit(t, adminAccessToken, "?dryRun=true", &validation.BulkUserAction{
+				Action:  model.BulkActionDelete,
+				UserIDs: []string{fixture.UserOne.ID.String()},
+			})
+			assert.Equal(t, http.StatusOK, status)
+
+			var jobs int64
+			test.DB.Model(new(model.BulkJob)).Count(&jobs)
+			assert.Zero(t, jobs)
+		})
+
+		t.Run("should return 400 error if both user IDs and a filter are given", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := submit(t, adminAccessToken, "", &validation.BulkUserAction{
+				Action:  model.BulkActionDeactivate,
+				UserIDs: []string{fixture.Admin.ID.String()},
+				Filter:  &validation.UserFilter{},
+			})
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("should return 400 error if assign-role has no role", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := submit(t, adminAccessToken, "", &validation.BulkUserAction{
+				Action: model.BulkActionAssignRole,
+				Filter: &validation.UserFilter{},
+			})
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("should return 403 error if user is not an admin", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status, _ := submit(t, userOneAccessToken, "", &validation.BulkUserAction{
+				Action: model.BulkActionDeactivate,
+				Filter: &validation.UserFilter{},
+			})
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+	})
+}
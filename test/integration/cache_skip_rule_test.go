@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"app/src/cache"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheSkipRuleRoutes(t *testing.T) {
+	createRule := func(t *testing.T, token, kind, value string) (int, *response.SuccessWithCacheSkipRule) {
+		bodyJSON, err := json.Marshal(&validation.CreateCacheSkipRule{Kind: kind, Value: value})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/admin/cache/skip-rules", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithCacheSkipRule)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	deleteRule := func(t *testing.T, token, id string) int {
+		request := httptest.NewRequest(http.MethodDelete, "/v1/admin/cache/skip-rules/"+id, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		return apiResponse.StatusCode
+	}
+
+	t.Run("POST /v1/admin/cache/skip-rules", func(t *testing.T) {
+		t.Run("should keep matching requests out of the cache until the rule is deleted", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, body := createRule(t, adminAccessToken, cache.SkipPattern, "^/v1/users/[^/]+$")
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Equal(t, "admin", body.Rule.Source)
+
+			path := "/v1/users/" + fixture.UserOne.ID.String()
+			assert.True(t, cache.CurrentSkipList().Skips(http.MethodGet, path, nil))
+
+			request := httptest.NewRequest(http.MethodGet, path, nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+			assert.Empty(t, apiResponse.Header.Get("X-Cache"))
+
+			status, _ = createRule(t, adminAccessToken, cache.SkipPattern, "^/v1/users/[^/]+$")
+			assert.Equal(t, http.StatusConflict, status)
+
+			assert.Equal(t, http.StatusOK, deleteRule(t, adminAccessToken, body.Rule.ID.String()))
+			assert.False(t, cache.CurrentSkipList().Skips(http.MethodGet, path, nil))
+		})
+
+		t.Run("should list the configured rules before the admin rules", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, body := createRule(t, adminAccessToken, cache.SkipMethod, "head")
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Equal(t, http.MethodHead, body.Rule.Value)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/cache/skip-rules", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithCacheSkipRules)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+			assert.Equal(t, response.CacheSkipRule{Kind: cache.SkipPath, Value: "/v1/auth", Source: "config"}, responseBody.Rules[0])
+			assert.Equal(t, body.Rule.ID, responseBody.Rules[len(responseBody.Rules)-1].ID)
+
+			assert.Equal(t, http.StatusOK, deleteRule(t, adminAccessToken, body.Rule.ID.String()))
+		})
+
+		t.Run("should return 400 if the pattern does not compile", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := createRule(t, adminAccessToken, cache.SkipPattern, "^/v1/(users")
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("should return 403 if a non-admin is adding a rule", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status, _ := createRule(t, userOneAccessToken, cache.SkipPath, "/v1/users")
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+	})
+
+	t.Run("DELETE /v1/admin/cache/skip-rules/:ruleId", func(t *testing.T) {
+		t.Run("should return 404 if the rule does not exist", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusNotFound, deleteRule(t, adminAccessToken, "e088d183-9eea-4a11-8d5d-74d7ec91bdf5"))
+		})
+	})
+}
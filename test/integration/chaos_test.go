@@ -0,0 +1,56 @@
+package integration
+
+import (
+	"app/src/chaos"
+	"app/src/config"
+	"app/src/redis"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaos(t *testing.T) {
+	getUser := func(t *testing.T, userID string) int {
+		adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodGet, "/v1/users/"+userID, nil)
+		request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+		return apiResponse.StatusCode
+	}
+
+	t.Run("should fall back to the database when every Redis call fails", func(t *testing.T) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+		chaos.Set(config.ChaosRedis, config.ChaosFault{ErrorPercent: 100})
+		t.Cleanup(func() {
+			chaos.Reset()
+			_ = redis.ResetBreaker()
+		})
+
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, http.StatusOK, getUser(t, fixture.UserOne.ID.String()))
+		}
+	})
+
+	t.Run("should fail the request when every database call fails", func(t *testing.T) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserTwo, fixture.Admin)
+
+		chaos.Set(config.ChaosDB, config.ChaosFault{ErrorPercent: 100})
+		t.Cleanup(chaos.Reset)
+
+		// A user not fetched before, so no cached response can hide the failure; without a cached
+		// session authentication fails first (401), otherwise the lookup does (500)
+		assert.Contains(t, []int{http.StatusUnauthorized, http.StatusInternalServerError}, getUser(t, fixture.UserTwo.ID.String()))
+	})
+}
@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	send := func(t *testing.T, request *http.Request) (int, *response.SuccessWithDryRun) {
+		adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+		assert.Nil(t, err)
+		request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithDryRun)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	t.Run("PATCH /v1/users/:userId?dryRun=true", func(t *testing.T) {
+		t.Run("should report a role change without making it", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			bodyJSON, err := json.Marshal(validation.UpdateUser{Role: validation.Some("admin")})
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPatch,
+				"/v1/users/"+fixture.UserOne.ID.String()+"?dryRun=true", strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+
+			status, responseBody := send(t, request)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, "user.update", responseBody.DryRun.Action)
+			assert.Equal(t, int64(1), responseBody.DryRun.Affected["users"])
+			assert.Equal(t, []string{fixture.UserOne.ID.String()}, responseBody.DryRun.Sample)
+			assert.Equal(t, map[string]any{"from": "user", "to": "admin"}, responseBody.DryRun.Changes["role"])
+
+			user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+			assert.Equal(t, "user", user.Role)
+		})
+
+		t.Run("should return 404 error if user is not found", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			bodyJSON, err := json.Marshal(validation.UpdateUser{Role: validation.Some("admin")})
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPatch,
+				"/v1/users/"+fixture.UserOne.ID.String()+"?dryRun=true", strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+
+			status, _ := send(t, request)
+			assert.Equal(t, http.StatusNotFound, status)
+		})
+	})
+
+	t.Run("DELETE /v1/users/:userId?dryRun=true", func(t *testing.T) {
+		t.Run("should report the user without deleting it", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			request := httptest.NewRequest(http.MethodDelete, "/v1/users/"+fixture.UserOne.ID.String()+"?dryRun=true", nil)
+
+			status, responseBody := send(t, request)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, "user.delete", responseBody.DryRun.Action)
+			assert.Equal(t, int64(1), responseBody.DryRun.Affected["users"])
+			assert.Contains(t, responseBody.DryRun.Affected, "tokens")
+			assert.Equal(t, []string{fixture.UserOne.ID.String()}, responseBody.DryRun.Sample)
+
+			user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+			assert.NotNil(t, user)
+		})
+	})
+}
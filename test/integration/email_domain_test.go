@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailDomainRoutes(t *testing.T) {
+	createRule := func(t *testing.T, token, pattern, action string) (int, *response.SuccessWithEmailDomainRule) {
+		bodyJSON, err := json.Marshal(&validation.CreateEmailDomainRule{Pattern: pattern, Action: action})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/admin/email-domain-rules", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithEmailDomainRule)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	deleteRule := func(t *testing.T, token, id string) int {
+		request := httptest.NewRequest(http.MethodDelete, "/v1/admin/email-domain-rules/"+id, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		return apiResponse.StatusCode
+	}
+
+	register := func(t *testing.T, email string) int {
+		bodyJSON, err := json.Marshal(&validation.Register{Name: "Test", Email: email, Password: "password1"})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/auth/register", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+
+		apiResponse, err := test.App.Test(request, 2000)
+		assert.Nil(t, err)
+
+		return apiResponse.StatusCode
+	}
+
+	t.Run("POST /v1/admin/email-domain-rules", func(t *testing.T) {
+		t.Run("should reject sign-ups from a blocked domain with 422 until the rule is deleted", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, body := createRule(t, adminAccessToken, "*.Blocked.test", "block")
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Equal(t, "*.blocked.test", body.Rule.Pattern)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, register(t, "test@mail.blocked.test"))
+			assert.Equal(t, http.StatusCreated, register(t, "test@blocked.test"))
+
+			status, _ = createRule(t, adminAccessToken, "*.blocked.test", "block")
+			assert.Equal(t, http.StatusConflict, status)
+
+			assert.Equal(t, http.StatusOK, deleteRule(t, adminAccessToken, body.Rule.ID.String()))
+			assert.Equal(t, http.StatusCreated, register(t, "test@mail.blocked.test"))
+		})
+
+		t.Run("should return 400 if the pattern is not a domain", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := createRule(t, adminAccessToken, "not a domain", "block")
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("should return 403 if a non-admin is adding a rule", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status, _ := createRule(t, userOneAccessToken, "blocked.test", "block")
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+	})
+
+	t.Run("DELETE /v1/admin/email-domain-rules/:ruleId", func(t *testing.T) {
+		t.Run("should return 404 if the rule does not exist", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusNotFound, deleteRule(t, adminAccessToken, "e088d183-9eea-4a11-8d5d-74d7ec91bdf5"))
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"app/src/config"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailSuppressionRoutes(t *testing.T) {
+	const secret = "email-webhook-secret"
+
+	previous := config.EmailSending.WebhookSecret
+	config.EmailSending.WebhookSecret = secret
+	t.Cleanup(func() { config.EmailSending.WebhookSecret = previous })
+
+	send := func(t *testing.T, request *http.Request, accessToken string, target any) int {
+		if accessToken != "" {
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		if target != nil {
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+			assert.Nil(t, json.Unmarshal(bytes, target))
+		}
+		return apiResponse.StatusCode
+	}
+
+	webhook := func(t *testing.T, provider, token, payload string) int {
+		request := httptest.NewRequest(http.MethodPost, "/v1/email/webhooks/"+provider+"?token="+token,
+			strings.NewReader(payload))
+		request.Header.Set("Content-Type", "application/json")
+		return send(t, request, "", nil)
+	}
+
+	list := func(t *testing.T) *response.SuccessWithPaginate[response.EmailSuppression] {
+		adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+		assert.Nil(t, err)
+
+		suppressions := new(response.SuccessWithPaginate[response.EmailSuppression])
+		request := httptest.NewRequest(http.MethodGet, "/v1/admin/email/suppressions", nil)
+		assert.Equal(t, http.StatusOK, send(t, request, adminAccessToken, suppressions))
+		return suppressions
+	}
+
+	create := func(t *testing.T, accessToken, email string, target any) int {
+		bodyJSON, err := json.Marshal(&validation.CreateEmailSuppression{Email: email})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/admin/email/suppressions", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		return send(t, request, accessToken, target)
+	}
+
+	t.Run("POST /v1/email/webhooks/:provider", func(t *testing.T) {
+		t.Run("should suppress the recipients a provider reports once", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			payload := `[
+				{"email": "Gone@Example.com", "event": "bounce", "type": "bounce", "reason": "550 5.1.1 user unknown"},
+				{"email": "happy@example.com", "event": "delivered"}
+			]`
+			assert.Equal(t, http.StatusOK, webhook(t, "sendgrid", secret, payload))
+			assert.Equal(t, http.StatusOK, webhook(t, "sendgrid", secret, payload))
+
+			suppressions := list(t)
+			assert.Len(t, suppressions.Results, 1)
+			assert.Equal(t, "gone@example.com", suppressions.Results[0].Email)
+			assert.Equal(t, "hard_bounce", suppressions.Results[0].Reason)
+			assert.Equal(t, "sendgrid", suppressions.Results[0].Source)
+		})
+
+		t.Run("should return 401 error without the webhook secret", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+
+			assert.Equal(t, http.StatusUnauthorized, webhook(t, "ses", "wrong", `{"Type":"Notification","Message":"{}"}`))
+		})
+
+		t.Run("should return 404 error for an unknown provider", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+
+			assert.Equal(t, http.StatusNotFound, webhook(t, "mailgun", secret, `{}`))
+		})
+	})
+
+	t.Run("POST /v1/admin/email/suppressions", func(t *testing.T) {
+		t.Run("should stop emails to the address until the suppression is deleted", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			created := new(response.SuccessWithEmailSuppression)
+			assert.Equal(t, http.StatusCreated, create(t, adminAccessToken, fixture.UserOne.Email, created))
+			assert.Equal(t, "manual", created.Suppression.Reason)
+			assert.Equal(t, http.StatusConflict, create(t, adminAccessToken, fixture.UserOne.Email, nil))
+
+			// The reset email is skipped rather than sent, and the response does not tell
+			request := httptest.NewRequest(http.MethodPost, "/v1/auth/forgot-password",
+				strings.NewReader(`{"email":"`+fixture.UserOne.Email+`"}`))
+			request.Header.Set("Content-Type", "application/json")
+			assert.Equal(t, http.StatusOK, send(t, request, "", nil))
+
+			path := "/v1/admin/email/suppressions/" + created.Suppression.ID.String()
+			request = httptest.NewRequest(http.MethodDelete, path, nil)
+			assert.Equal(t, http.StatusOK, send(t, request, adminAccessToken, nil))
+			assert.Empty(t, list(t).Results)
+		})
+
+		t.Run("should return 403 error if a non-admin is suppressing an address", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusForbidden, create(t, userOneAccessToken, "someone@example.com", nil))
+		})
+	})
+}
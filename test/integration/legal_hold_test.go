@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegalHoldRoutes(t *testing.T) {
+	requestAs := func(t *testing.T, user *model.User, method, url string, body any) (int, []byte) {
+		accessToken, err := fixture.AccessToken(user)
+		assert.Nil(t, err)
+
+		var reader io.Reader
+		if body != nil {
+			bodyJSON, err := json.Marshal(body)
+			assert.Nil(t, err)
+			reader = bytes.NewReader(bodyJSON)
+		}
+
+		request := httptest.NewRequest(method, url, reader)
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		responseBody, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+		return apiResponse.StatusCode, responseBody
+	}
+
+	placeHold := func(t *testing.T) {
+		statusCode, body := requestAs(t, fixture.Admin, http.MethodPost, "/v1/admin/legal-holds",
+			validation.CreateLegalHold{UserID: fixture.UserOne.ID.String(), Reason: "Litigation case 2026-114"})
+		assert.Equal(t, http.StatusCreated, statusCode)
+
+		responseBody := new(response.SuccessWithLegalHold)
+		assert.Nil(t, json.Unmarshal(body, responseBody))
+		assert.Equal(t, fixture.UserOne.ID, responseBody.LegalHold.UserID)
+		assert.Equal(t, fixture.Admin.ID, *responseBody.LegalHold.PlacedBy)
+	}
+
+	t.Run("POST /v1/admin/legal-holds", func(t *testing.T) {
+		t.Run("should place a hold and audit who placed it", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+
+			placeHold(t)
+
+			var entry model.AuditLog
+			assert.Nil(t, test.DB.Where("action = ? AND user_id = ?", model.AuditActionLegalHoldPlaced,
+				fixture.UserOne.ID).First(&entry).Error)
+			assert.Contains(t, entry.Metadata, fixture.Admin.ID.String())
+		})
+
+		t.Run("should return 409 error if the user is already under hold", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+			placeHold(t)
+
+			statusCode, _ := requestAs(t, fixture.Admin, http.MethodPost, "/v1/admin/legal-holds",
+				validation.CreateLegalHold{UserID: fixture.UserOne.ID.String(), Reason: "Again"})
+			assert.Equal(t, http.StatusConflict, statusCode)
+		})
+
+		t.Run("should return 403 error if user is not admin", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			statusCode, _ := requestAs(t, fixture.UserOne, http.MethodPost, "/v1/admin/legal-holds",
+				validation.CreateLegalHold{UserID: fixture.UserOne.ID.String(), Reason: "Mine"})
+			assert.Equal(t, http.StatusForbidden, statusCode)
+		})
+	})
+
+	t.Run("DELETE /v1/users/:userId under legal hold", func(t *testing.T) {
+		t.Run("should refuse to delete the user until the hold is lifted", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+			placeHold(t)
+
+			userURL := "/v1/users/" + fixture.UserOne.ID.String()
+			statusCode, _ := requestAs(t, fixture.Admin, http.MethodDelete, userURL, nil)
+			assert.Equal(t, http.StatusConflict, statusCode)
+
+			statusCode, _ = requestAs(t, fixture.UserOne, http.MethodDelete, userURL, nil)
+			assert.Equal(t, http.StatusConflict, statusCode)
+			_, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+
+			statusCode, _ = requestAs(t, fixture.Admin, http.MethodDelete,
+				"/v1/admin/legal-holds/"+fixture.UserOne.ID.String(), nil)
+			assert.Equal(t, http.StatusOK, statusCode)
+
+			statusCode, _ = requestAs(t, fixture.Admin, http.MethodDelete, userURL, nil)
+			assert.Equal(t, http.StatusOK, statusCode)
+		})
+	})
+
+	t.Run("POST /v1/admin/retention/purge under legal hold", func(t *testing.T) {
+		t.Run("should keep the held user's rows past retention", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne, fixture.UserTwo)
+			placeHold(t)
+
+			for _, user := range []*model.User{fixture.UserOne, fixture.UserTwo} {
+				assert.Nil(t, test.DB.Create(&model.AuditLog{
+					UserID: &user.ID, Action: model.AuditActionLoginFailed, CreatedAt: time.Now().AddDate(-2, 0, 0),
+				}).Error)
+			}
+
+			statusCode, _ := requestAs(t, fixture.Admin, http.MethodPost, "/v1/admin/retention/purge", nil)
+			assert.Equal(t, http.StatusOK, statusCode)
+
+			var kept []model.AuditLog
+			assert.Nil(t, test.DB.Where("action = ?", model.AuditActionLoginFailed).Find(&kept).Error)
+			assert.Len(t, kept, 1)
+			assert.Equal(t, fixture.UserOne.ID, *kept[0].UserID)
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"app/src/locks"
+	"app/src/model"
+	"app/test"
+	"app/test/helper"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestLockFencing(t *testing.T) {
+	sketch := func(period string) *model.AnalyticsSketch {
+		return &model.AnalyticsSketch{Kind: "dau", Period: period, Sketch: []byte("HYLL"), Count: 1}
+	}
+	count := func(t *testing.T) int64 {
+		var rows int64
+		assert.Nil(t, test.DB.Model(new(model.AnalyticsSketch)).Count(&rows).Error)
+		return rows
+	}
+
+	t.Run("should reject writes of a holder once a newer one wrote", func(t *testing.T) {
+		helper.ClearAnalytics(test.DB)
+		helper.ClearLockFences(test.DB)
+
+		stale := locks.WithFencingToken(context.Background(), "fence-test", 1)
+		current := locks.WithFencingToken(context.Background(), "fence-test", 2)
+
+		assert.Nil(t, test.DB.WithContext(stale).Create(sketch("2026-09-01")).Error)
+		assert.Nil(t, test.DB.WithContext(current).Create(sketch("2026-09-02")).Error)
+		assert.Nil(t, test.DB.WithContext(current).Create(sketch("2026-09-03")).Error)
+
+		err := test.DB.WithContext(stale).Create(sketch("2026-09-04")).Error
+		assert.ErrorIs(t, err, locks.ErrFenced)
+		err = test.DB.WithContext(stale).Where("period = ?", "2026-09-01").Delete(new(model.AnalyticsSketch)).Error
+		assert.ErrorIs(t, err, locks.ErrFenced)
+		assert.Equal(t, int64(3), count(t))
+	})
+
+	t.Run("should roll back the transaction of a stale holder", func(t *testing.T) {
+		helper.ClearAnalytics(test.DB)
+		helper.ClearLockFences(test.DB)
+
+		assert.Nil(t, test.DB.WithContext(locks.WithFencingToken(context.Background(), "fence-test", 5)).
+			Create(sketch("2026-09-01")).Error)
+
+		stale := locks.WithFencingToken(context.Background(), "fence-test", 4)
+		err := test.DB.WithContext(stale).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(new(model.AnalyticsSketch)).Where("period = ?", "2026-09-01").
+				Update("count", 10).Error; err != nil {
+				return err
+			}
+			return tx.Create(sketch("2026-09-02")).Error
+		})
+		assert.ErrorIs(t, err, locks.ErrFenced)
+		assert.Equal(t, int64(1), count(t))
+	})
+
+	t.Run("should not fence writes outside a lock", func(t *testing.T) {
+		helper.ClearAnalytics(test.DB)
+		helper.ClearLockFences(test.DB)
+
+		assert.Nil(t, test.DB.WithContext(locks.WithFencingToken(context.Background(), "fence-test", 5)).
+			Create(sketch("2026-09-01")).Error)
+		assert.Nil(t, test.DB.Create(sketch("2026-09-02")).Error)
+		assert.Equal(t, int64(2), count(t))
+	})
+}
@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"app/src/response"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationRoutes(t *testing.T) {
+	send := func(t *testing.T, method, path, body string, target any) int {
+		accessToken, err := fixture.AccessToken(fixture.UserOne)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		if target != nil {
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+			assert.Nil(t, json.Unmarshal(bytes, target))
+		}
+		return apiResponse.StatusCode
+	}
+
+	t.Run("GET /v1/users/me/notifications", func(t *testing.T) {
+		t.Run("should default to email without a phone or text provider", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			settings := new(response.SuccessWithNotificationSettings)
+			assert.Equal(t, http.StatusOK, send(t, http.MethodGet, "/v1/users/me/notifications", "", settings))
+			assert.Nil(t, settings.Settings.Phone)
+			assert.Equal(t, []string{"email"}, settings.Settings.Channels)
+			assert.Equal(t, []response.NotificationPreference{
+				{Kind: "confirm_login", Channels: []string{"email"}},
+				{Kind: "new_login", Channels: []string{"push"}},
+				{Kind: "password_changed", Channels: []string{"push"}},
+			}, settings.Settings.Preferences)
+		})
+	})
+
+	t.Run("PUT /v1/users/me/notifications/preferences/:kind", func(t *testing.T) {
+		t.Run("should save the chosen channels", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			path := "/v1/users/me/notifications/preferences/confirm_login"
+			assert.Equal(t, http.StatusOK, send(t, http.MethodPut, path, `{"channels":["email"]}`, nil))
+			assert.Equal(t, http.StatusOK, send(t, http.MethodPut, path, `{"channels":["email"]}`, nil))
+		})
+
+		t.Run("should turn off kinds the user can do without", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			path := "/v1/users/me/notifications/preferences/new_login"
+			assert.Equal(t, http.StatusOK, send(t, http.MethodPut, path, `{"channels":[]}`, nil))
+
+			path = "/v1/users/me/notifications/preferences/confirm_login"
+			assert.Equal(t, http.StatusBadRequest, send(t, http.MethodPut, path, `{"channels":[]}`, nil))
+		})
+
+		t.Run("should return 400 error for a channel that is not configured", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			path := "/v1/users/me/notifications/preferences/confirm_login"
+			assert.Equal(t, http.StatusBadRequest, send(t, http.MethodPut, path, `{"channels":["sms"]}`, nil))
+		})
+
+		t.Run("should return 404 error for an unknown kind", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			path := "/v1/users/me/notifications/preferences/newsletter"
+			assert.Equal(t, http.StatusNotFound, send(t, http.MethodPut, path, `{"channels":["email"]}`, nil))
+		})
+	})
+
+	t.Run("PUT /v1/users/me/notifications/phone", func(t *testing.T) {
+		t.Run("should return 400 error without a text provider", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			body := `{"phone":"+14155552671"}`
+			assert.Equal(t, http.StatusBadRequest, send(t, http.MethodPut, "/v1/users/me/notifications/phone", body, nil))
+		})
+
+		t.Run("should return 404 error when removing a phone that was never verified", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			assert.Equal(t, http.StatusNotFound, send(t, http.MethodDelete, "/v1/users/me/notifications/phone", "", nil))
+		})
+	})
+
+	t.Run("/v1/users/me/notifications/devices", func(t *testing.T) {
+		t.Run("should list no devices", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			devices := new(response.SuccessWithPushDevices)
+			assert.Equal(t, http.StatusOK, send(t, http.MethodGet, "/v1/users/me/notifications/devices", "", devices))
+			assert.Empty(t, devices.Devices)
+		})
+
+		t.Run("should return 400 error when the platform is not configured", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			body := `{"platform":"fcm","token":"device-token"}`
+			assert.Equal(t, http.StatusBadRequest, send(t, http.MethodPost, "/v1/users/me/notifications/devices", body, nil))
+		})
+
+		t.Run("should return 404 error when removing an unknown device", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			path := "/v1/users/me/notifications/devices/" + uuid.NewString()
+			assert.Equal(t, http.StatusNotFound, send(t, http.MethodDelete, path, "", nil))
+		})
+	})
+
+	t.Run("POST /v1/notifications/callbacks/:provider", func(t *testing.T) {
+		t.Run("should return 404 error when the provider is not configured", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/notifications/callbacks/twilio",
+				strings.NewReader("MessageSid=SM42&MessageStatus=delivered"))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusNotFound, apiResponse.StatusCode)
+		})
+	})
+}
@@ -0,0 +1,253 @@
+package integration
+
+import (
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuthProviderRoutes(t *testing.T) {
+	const redirectURI = "https://reports.example.com/callback"
+	const verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	send := func(t *testing.T, request *http.Request, accessToken string, target any) int {
+		if accessToken != "" {
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		if target != nil {
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+			assert.Nil(t, json.Unmarshal(bytes, target))
+		}
+		return apiResponse.StatusCode
+	}
+
+	sendJSON := func(t *testing.T, method, path, accessToken string, body, target any) int {
+		bodyJSON, err := json.Marshal(body)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(method, path, strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		return send(t, request, accessToken, target)
+	}
+
+	createClient := func(t *testing.T) *response.SuccessWithOAuthClient {
+		adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+		assert.Nil(t, err)
+
+		created := new(response.SuccessWithOAuthClient)
+		status := sendJSON(t, http.MethodPost, "/v1/admin/oauth/clients", adminAccessToken, &validation.CreateOAuthClient{
+			Name:         "Acme Reports",
+			RedirectURIs: []string{redirectURI},
+			Scopes:       []string{"profile:read"},
+		}, created)
+		assert.Equal(t, http.StatusCreated, status)
+
+		return created
+	}
+
+	authorizeRequest := func(clientID string) validation.OAuthAuthorize {
+		return validation.OAuthAuthorize{
+			ResponseType:        "code",
+			ClientID:            clientID,
+			RedirectURI:         redirectURI,
+			Scope:               "profile:read",
+			State:               "xyz",
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: "S256",
+		}
+	}
+
+	// authorize approves the request as UserOne and returns the code from the redirect URI
+	authorize := func(t *testing.T, clientID string) string {
+		userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+		assert.Nil(t, err)
+
+		redirect := new(response.SuccessWithOAuthRedirect)
+		status := sendJSON(t, http.MethodPost, "/v1/oauth/authorize", userOneAccessToken, &validation.OAuthConsent{
+			OAuthAuthorize: authorizeRequest(clientID),
+			Approve:        true,
+		}, redirect)
+		assert.Equal(t, http.StatusOK, status)
+
+		target, err := url.Parse(redirect.RedirectURI)
+		assert.Nil(t, err)
+		assert.Equal(t, "xyz", target.Query().Get("state"))
+
+		return target.Query().Get("code")
+	}
+
+	exchange := func(t *testing.T, clientID, secret, code, codeVerifier string, target any) int {
+		form := url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {code},
+			"redirect_uri":  {redirectURI},
+			"code_verifier": {codeVerifier},
+		}
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/oauth/token", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.SetBasicAuth(clientID, secret)
+		return send(t, request, "", target)
+	}
+
+	t.Run("GET /v1/oauth/authorize", func(t *testing.T) {
+		t.Run("should describe the client and the requested scopes", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			created := createClient(t)
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			query := url.Values{
+				"response_type": {"code"},
+				"client_id":     {created.Client.ID.String()},
+				"redirect_uri":  {redirectURI},
+				"scope":         {"profile:read"},
+			}
+			request := httptest.NewRequest(http.MethodGet, "/v1/oauth/authorize?"+query.Encode(), nil)
+
+			described := new(response.SuccessWithOAuthAuthorization)
+			assert.Equal(t, http.StatusOK, send(t, request, userOneAccessToken, described))
+			assert.Equal(t, "Acme Reports", described.Authorization.ClientName)
+			assert.Len(t, described.Authorization.Scopes, 1)
+			assert.False(t, described.Authorization.Consented)
+		})
+
+		t.Run("should return 400 error for an unregistered redirect URI", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			created := createClient(t)
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			query := url.Values{
+				"response_type": {"code"},
+				"client_id":     {created.Client.ID.String()},
+				"redirect_uri":  {"https://evil.example.com/callback"},
+				"scope":         {"profile:read"},
+			}
+			request := httptest.NewRequest(http.MethodGet, "/v1/oauth/authorize?"+query.Encode(), nil)
+
+			assert.Equal(t, http.StatusBadRequest, send(t, request, userOneAccessToken, nil))
+		})
+	})
+
+	t.Run("POST /v1/oauth/token", func(t *testing.T) {
+		t.Run("should issue a token limited to the granted scopes", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo, fixture.Admin)
+
+			created := createClient(t)
+			code := authorize(t, created.Client.ID.String())
+
+			token := new(response.OAuthToken)
+			status := exchange(t, created.Client.ID.String(), created.ClientSecret, code, verifier, token)
+			assert.Equal(t, http.StatusOK, status)
+			assert.True(t, strings.HasPrefix(token.AccessToken, "oat_"))
+			assert.Equal(t, "profile:read", token.Scope)
+
+			get := func(path string) int {
+				return send(t, httptest.NewRequest(http.MethodGet, path, nil), token.AccessToken, nil)
+			}
+			assert.Equal(t, http.StatusOK, get("/v1/users/"+fixture.UserOne.ID.String()))
+			assert.Equal(t, http.StatusForbidden, get("/v1/users/"+fixture.UserTwo.ID.String()))
+			assert.Equal(t, http.StatusForbidden, get("/v1/users/me/oauth/consents"))
+
+			patch := httptest.NewRequest(http.MethodPatch, "/v1/users/"+fixture.UserOne.ID.String(),
+				strings.NewReader(`{"name":"Changed"}`))
+			patch.Header.Set("Content-Type", "application/json")
+			assert.Equal(t, http.StatusForbidden, send(t, patch, token.AccessToken, nil))
+		})
+
+		t.Run("should accept a code only once", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			created := createClient(t)
+			code := authorize(t, created.Client.ID.String())
+
+			assert.Equal(t, http.StatusOK, exchange(t, created.Client.ID.String(), created.ClientSecret, code, verifier, nil))
+
+			failed := new(response.OAuthError)
+			status := exchange(t, created.Client.ID.String(), created.ClientSecret, code, verifier, failed)
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Equal(t, "invalid_grant", failed.Error)
+		})
+
+		t.Run("should return 400 error if the code verifier does not match", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			created := createClient(t)
+			code := authorize(t, created.Client.ID.String())
+
+			failed := new(response.OAuthError)
+			status := exchange(t, created.Client.ID.String(), created.ClientSecret, code, strings.Repeat("x", 43), failed)
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Equal(t, "invalid_grant", failed.Error)
+		})
+
+		t.Run("should return 401 error for a wrong client secret", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			created := createClient(t)
+			code := authorize(t, created.Client.ID.String())
+
+			failed := new(response.OAuthError)
+			status := exchange(t, created.Client.ID.String(), "ocs_wrong", code, verifier, failed)
+			assert.Equal(t, http.StatusUnauthorized, status)
+			assert.Equal(t, "invalid_client", failed.Error)
+		})
+	})
+
+	t.Run("DELETE /v1/users/me/oauth/consents/:clientId", func(t *testing.T) {
+		t.Run("should revoke the client's tokens", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			created := createClient(t)
+			code := authorize(t, created.Client.ID.String())
+
+			token := new(response.OAuthToken)
+			assert.Equal(t, http.StatusOK, exchange(t, created.Client.ID.String(), created.ClientSecret, code, verifier, token))
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			consents := new(response.SuccessWithOAuthConsents)
+			request := httptest.NewRequest(http.MethodGet, "/v1/users/me/oauth/consents", nil)
+			assert.Equal(t, http.StatusOK, send(t, request, userOneAccessToken, consents))
+			assert.Len(t, consents.Consents, 1)
+
+			request = httptest.NewRequest(http.MethodDelete, "/v1/users/me/oauth/consents/"+created.Client.ID.String(), nil)
+			assert.Equal(t, http.StatusOK, send(t, request, userOneAccessToken, nil))
+
+			request = httptest.NewRequest(http.MethodGet, "/v1/users/"+fixture.UserOne.ID.String(), nil)
+			assert.Equal(t, http.StatusUnauthorized, send(t, request, token.AccessToken, nil))
+		})
+	})
+}
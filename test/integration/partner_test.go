@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"app/src/encryption"
+	"app/src/requestsig"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartnerRoutes(t *testing.T) {
+	// Signing keys are stored encrypted
+	keyring, err := encryption.NewKeyring(map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")}, "k1")
+	assert.Nil(t, err)
+	encryption.Use(keyring)
+	defer encryption.Use(nil)
+
+	createPartner := func(t *testing.T, name string) *response.SuccessWithPartner {
+		adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+		assert.Nil(t, err)
+
+		bodyJSON, err := json.Marshal(&validation.CreatePartner{Name: name})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/admin/partners", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, apiResponse.StatusCode)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithPartner)
+		assert.Nil(t, json.Unmarshal(bytes, responseBody))
+
+		return responseBody
+	}
+
+	signedRequest := func(partnerID, secret, nonce string) *http.Request {
+		r := requestsig.Request{
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			Nonce:     nonce,
+			Method:    http.MethodGet,
+			URI:       "/v1/partner/me",
+		}
+
+		request := httptest.NewRequest(r.Method, r.URI, nil)
+		request.Header.Set(requestsig.HeaderPartnerID, partnerID)
+		request.Header.Set(requestsig.HeaderTimestamp, r.Timestamp)
+		request.Header.Set(requestsig.HeaderNonce, r.Nonce)
+		request.Header.Set(requestsig.HeaderSignature, requestsig.Sign(requestsig.Key(secret), r))
+		return request
+	}
+
+	t.Run("POST /v1/admin/partners", func(t *testing.T) {
+		t.Run("should return 201 and the signing secret once", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			created := createPartner(t, "Acme Payments")
+
+			assert.Equal(t, "Acme Payments", created.Partner.Name)
+			assert.True(t, strings.HasPrefix(created.SigningSecret, created.Partner.Prefix))
+
+			var stored string
+			err := test.DB.Raw("SELECT signing_key FROM partners WHERE id = ?", created.Partner.ID).Scan(&stored).Error
+			assert.Nil(t, err)
+			assert.True(t, encryption.IsEncrypted(stored))
+		})
+
+		t.Run("should return 403 error if user is not an admin", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/partners", nil)
+			request.Header.Set("Authorization", "Bearer "+userOneAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusForbidden, apiResponse.StatusCode)
+		})
+	})
+
+	t.Run("GET /v1/partner/me", func(t *testing.T) {
+		t.Run("should return 200 for a signed request and reject its replay", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			created := createPartner(t, "Acme Payments")
+			nonce := uuid.NewString()
+
+			apiResponse, err := test.App.Test(signedRequest(created.Partner.ID.String(), created.SigningSecret, nonce))
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithPartner)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+			assert.Equal(t, created.Partner.ID, responseBody.Partner.ID)
+			assert.Empty(t, responseBody.SigningSecret)
+
+			apiResponse, err = test.App.Test(signedRequest(created.Partner.ID.String(), created.SigningSecret, nonce))
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+		})
+
+		t.Run("should return 401 error if the signature is wrong", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			created := createPartner(t, "Acme Payments")
+
+			apiResponse, err := test.App.Test(signedRequest(created.Partner.ID.String(), "psk_wrong", uuid.NewString()))
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+		})
+
+		t.Run("should return 401 error if the partner was deleted", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			created := createPartner(t, "Acme Payments")
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodDelete, "/v1/admin/partners/"+created.Partner.ID.String(), nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			apiResponse, err = test.App.Test(signedRequest(created.Partner.ID.String(), created.SigningSecret, uuid.NewString()))
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+		})
+	})
+}
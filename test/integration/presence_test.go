@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresenceRoutes(t *testing.T) {
+	send := func(t *testing.T, user *model.User, method, path, body string, target any) int {
+		accessToken, err := fixture.AccessToken(user)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		if target != nil {
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+			assert.Nil(t, json.Unmarshal(bytes, target))
+		}
+		return apiResponse.StatusCode
+	}
+
+	t.Run("GET /v1/users/:userId/presence", func(t *testing.T) {
+		t.Run("should report a user without connections as offline", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			path := "/v1/users/" + fixture.UserOne.ID.String() + "/presence"
+			presence := new(response.SuccessWithPresence)
+			assert.Equal(t, http.StatusOK, send(t, fixture.UserOne, http.MethodGet, path, "", presence))
+			assert.Equal(t, fixture.UserOne.ID, presence.Presence.UserID)
+			assert.Equal(t, "offline", presence.Presence.Status)
+		})
+
+		t.Run("should return 403 error for another user's presence without the getUsers right", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+
+			path := "/v1/users/" + fixture.UserTwo.ID.String() + "/presence"
+			assert.Equal(t, http.StatusForbidden, send(t, fixture.UserOne, http.MethodGet, path, "", nil))
+		})
+
+		t.Run("should return 404 error for an unknown user", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			path := "/v1/users/" + uuid.NewString() + "/presence"
+			assert.Equal(t, http.StatusNotFound, send(t, fixture.Admin, http.MethodGet, path, "", nil))
+		})
+	})
+
+	t.Run("POST /v1/users/presence", func(t *testing.T) {
+		t.Run("should return the presence of each user in order", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+
+			unknown := uuid.New()
+			body := `{"user_ids":["` + fixture.UserOne.ID.String() + `","` + unknown.String() + `"]}`
+			presences := new(response.SuccessWithPresences)
+			assert.Equal(t, http.StatusOK, send(t, fixture.Admin, http.MethodPost, "/v1/users/presence", body, presences))
+			assert.Len(t, presences.Presences, 2)
+			assert.Equal(t, fixture.UserOne.ID, presences.Presences[0].UserID)
+			assert.Equal(t, unknown, presences.Presences[1].UserID)
+		})
+
+		t.Run("should return 403 error without the getUsers right", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			body := `{"user_ids":["` + fixture.UserOne.ID.String() + `"]}`
+			assert.Equal(t, http.StatusForbidden, send(t, fixture.UserOne, http.MethodPost, "/v1/users/presence", body, nil))
+		})
+	})
+
+	t.Run("GET /v1/presence/ws", func(t *testing.T) {
+		t.Run("should return 426 error for requests that are not WebSocket handshakes", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			assert.Equal(t, http.StatusUpgradeRequired, send(t, fixture.UserOne, http.MethodGet, "/v1/presence/ws", "", nil))
+		})
+	})
+}
@@ -0,0 +1,171 @@
+package integration
+
+import (
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaRoutes(t *testing.T) {
+	updateUserQuota := func(t *testing.T, token, userID string, req *validation.UpdateUserQuota) (int, *response.SuccessWithQuota) {
+		bodyJSON, err := json.Marshal(req)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/v1/admin/quotas/users/"+userID, strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithQuota)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	t.Run("GET /v1/admin/quotas/users/:userId", func(t *testing.T) {
+		t.Run("should return 200 and the plan's limits", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/quotas/users/"+fixture.UserOne.ID.String(), nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithQuota)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+			assert.Equal(t, "free", responseBody.Quota.Plan)
+			assert.False(t, responseBody.Quota.Overridden)
+			assert.Equal(t, int64(1000), responseBody.Quota.Daily.Limit)
+			assert.Equal(t, int64(20000), responseBody.Quota.Monthly.Limit)
+		})
+
+		t.Run("should return 403 if a non-admin is viewing a quota", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/quotas/users/"+fixture.UserOne.ID.String(), nil)
+			request.Header.Set("Authorization", "Bearer "+userOneAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusForbidden, apiResponse.StatusCode)
+		})
+
+		t.Run("should return 400 before the lookup if the user ID is malformed", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/quotas/users/not-a-uuid", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusBadRequest, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.ErrorDetails)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+			assert.Equal(t, "Invalid user ID", responseBody.Message)
+		})
+	})
+
+	t.Run("PUT /v1/admin/quotas/users/:userId", func(t *testing.T) {
+		t.Run("should return 200 and apply the new plan and overrides", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			plan, daily := "pro", int64(5000)
+			status, body := updateUserQuota(t, adminAccessToken, fixture.UserOne.ID.String(),
+				&validation.UpdateUserQuota{Plan: &plan, DailyLimit: &daily})
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, "pro", body.Quota.Plan)
+			assert.True(t, body.Quota.Overridden)
+			assert.Equal(t, int64(5000), body.Quota.Daily.Limit)
+			assert.Equal(t, int64(1000000), body.Quota.Monthly.Limit)
+
+			status, body = updateUserQuota(t, adminAccessToken, fixture.UserOne.ID.String(), &validation.UpdateUserQuota{})
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, "pro", body.Quota.Plan)
+			assert.False(t, body.Quota.Overridden)
+			assert.Equal(t, int64(0), body.Quota.Daily.Limit)
+		})
+
+		t.Run("should return 400 if the plan is unknown", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			plan := "enterprise"
+			status, _ := updateUserQuota(t, adminAccessToken, fixture.UserOne.ID.String(),
+				&validation.UpdateUserQuota{Plan: &plan})
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("should return 400 if a limit is negative", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			monthly := int64(-1)
+			status, _ := updateUserQuota(t, adminAccessToken, fixture.UserOne.ID.String(),
+				&validation.UpdateUserQuota{MonthlyLimit: &monthly})
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	})
+
+	t.Run("GET /v1/admin/quotas/tokens/:tokenId", func(t *testing.T) {
+		t.Run("should return 404 if the token does not exist", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/quotas/tokens/"+fixture.UserOne.ID.String(), nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusNotFound, apiResponse.StatusCode)
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyRoutes(t *testing.T) {
+	requestAs := func(t *testing.T, user *model.User, method, url string, body any) (int, []byte) {
+		accessToken, err := fixture.AccessToken(user)
+		assert.Nil(t, err)
+
+		var reader io.Reader
+		if body != nil {
+			bodyJSON, err := json.Marshal(body)
+			assert.Nil(t, err)
+			reader = bytes.NewReader(bodyJSON)
+		}
+
+		request := httptest.NewRequest(method, url, reader)
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		responseBody, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+		return apiResponse.StatusCode, responseBody
+	}
+
+	on, off := true, false
+	switchReadOnly := func(t *testing.T, req validation.SetReadOnly) response.ReadOnly {
+		statusCode, body := requestAs(t, fixture.Admin, http.MethodPut, "/v1/admin/read-only", req)
+		assert.Equal(t, http.StatusOK, statusCode)
+
+		responseBody := new(response.SuccessWithReadOnly)
+		assert.Nil(t, json.Unmarshal(body, responseBody))
+		return responseBody.ReadOnly
+	}
+	t.Cleanup(func() {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.Admin)
+		switchReadOnly(t, validation.SetReadOnly{Enabled: &off})
+	})
+
+	t.Run("PUT /v1/admin/read-only", func(t *testing.T) {
+		t.Run("should refuse writes and keep reads until switched off", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+
+			state := switchReadOnly(t, validation.SetReadOnly{Enabled: &on, Reason: "Database migration"})
+			assert.True(t, state.Enabled)
+			assert.Equal(t, "Database migration", state.Reason)
+			assert.Equal(t, fixture.Admin.ID.String(), state.By)
+			assert.NotNil(t, state.Since)
+
+			userURL := "/v1/users/" + fixture.UserOne.ID.String()
+			statusCode, body := requestAs(t, fixture.Admin, http.MethodDelete, userURL, nil)
+			assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+			assert.Contains(t, string(body), `"code":"read_only"`)
+
+			statusCode, _ = requestAs(t, fixture.Admin, http.MethodGet, userURL, nil)
+			assert.Equal(t, http.StatusOK, statusCode)
+
+			statusCode, _ = requestAs(t, fixture.Admin, http.MethodGet, "/v1/admin/read-only", nil)
+			assert.Equal(t, http.StatusOK, statusCode)
+
+			state = switchReadOnly(t, validation.SetReadOnly{Enabled: &off})
+			assert.False(t, state.Enabled)
+
+			statusCode, _ = requestAs(t, fixture.Admin, http.MethodDelete, userURL, nil)
+			assert.Equal(t, http.StatusOK, statusCode)
+
+			var audited int64
+			test.DB.Model(new(model.AuditLog)).
+				Where("action IN ?", []string{model.AuditActionReadOnlyEnabled, model.AuditActionReadOnlyDisabled}).
+				Count(&audited)
+			assert.Equal(t, int64(2), audited)
+		})
+
+		t.Run("should return 400 error if switching on without a reason", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			statusCode, _ := requestAs(t, fixture.Admin, http.MethodPut, "/v1/admin/read-only",
+				validation.SetReadOnly{Enabled: &on})
+			assert.Equal(t, http.StatusBadRequest, statusCode)
+		})
+
+		t.Run("should return 403 error if user is not admin", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			statusCode, _ := requestAs(t, fixture.UserOne, http.MethodPut, "/v1/admin/read-only",
+				validation.SetReadOnly{Enabled: &on, Reason: "Mine"})
+			assert.Equal(t, http.StatusForbidden, statusCode)
+		})
+	})
+}
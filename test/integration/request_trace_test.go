@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTraceRoutes(t *testing.T) {
+	login := func(t *testing.T, requestID, password string) (*http.Response, *response.Common) {
+		bodyJSON, err := json.Marshal(&validation.Login{Email: fixture.UserOne.Email, Password: password})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Request-ID", requestID)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.Common)
+		_ = json.Unmarshal(bytes, responseBody)
+		return apiResponse, responseBody
+	}
+
+	getTrace := func(t *testing.T, user *model.User, requestID string) (int, *response.SuccessWithRequestTrace) {
+		accessToken, err := fixture.AccessToken(user)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodGet, "/v1/admin/debug/requests/"+requestID, nil)
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithRequestTrace)
+		_ = json.Unmarshal(bytes, responseBody)
+		return apiResponse.StatusCode, responseBody
+	}
+
+	t.Run("error responses", func(t *testing.T) {
+		t.Run("should carry the request ID given by the client", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			apiResponse, body := login(t, "support-case-42", "wrong-password")
+			assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+			assert.Equal(t, "support-case-42", apiResponse.Header.Get("X-Request-ID"))
+			assert.Equal(t, "support-case-42", body.RequestID)
+		})
+
+		t.Run("should replace a malformed request ID", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			apiResponse, body := login(t, "forged\r\nX-Admin: 1", "wrong-password")
+			assert.NotEmpty(t, body.RequestID)
+			assert.NotContains(t, body.RequestID, "forged")
+			assert.Equal(t, apiResponse.Header.Get("X-Request-ID"), body.RequestID)
+		})
+	})
+
+	t.Run("GET /v1/admin/debug/requests/:requestId", func(t *testing.T) {
+		t.Run("should return 200 and the audit entries recorded by the request", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+
+			login(t, "support-case-43", "wrong-password")
+			login(t, "support-case-44", "wrong-password")
+
+			statusCode, body := getTrace(t, fixture.Admin, "support-case-43")
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Equal(t, "support-case-43", body.Trace.RequestID)
+			assert.Len(t, body.Trace.AuditLogs, 1)
+			assert.Equal(t, model.AuditActionLoginFailed, body.Trace.AuditLogs[0].Action)
+		})
+
+		t.Run("should return 404 error for a request that left nothing behind", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			statusCode, _ := getTrace(t, fixture.Admin, "support-case-45")
+			assert.Equal(t, http.StatusNotFound, statusCode)
+		})
+
+		t.Run("should return 400 error for a malformed request ID", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			statusCode, _ := getTrace(t, fixture.Admin, "bad%20id")
+			assert.Equal(t, http.StatusBadRequest, statusCode)
+		})
+
+		t.Run("should return 403 error without the debugRequests right", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			statusCode, _ := getTrace(t, fixture.UserOne, "support-case-46")
+			assert.Equal(t, http.StatusForbidden, statusCode)
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/response"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionRoutes(t *testing.T) {
+	requestAs := func(t *testing.T, user *model.User, method, url string) (int, []response.RetentionPolicy) {
+		accessToken, err := fixture.AccessToken(user)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(method, url, nil)
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithRetentionPolicies)
+		_ = json.Unmarshal(bytes, responseBody)
+		return apiResponse.StatusCode, responseBody.Policies
+	}
+
+	rowsByPolicy := func(policies []response.RetentionPolicy) map[string]int64 {
+		rows := map[string]int64{}
+		for _, policy := range policies {
+			rows[policy.Name] = policy.Rows
+		}
+		return rows
+	}
+
+	insertAuditLogs := func(t *testing.T) {
+		now := time.Now()
+		for _, entry := range []model.AuditLog{
+			{Action: model.AuditActionRoleChanged, CreatedAt: now.AddDate(-2, 0, 0)},
+			{Action: model.AuditActionRoleChanged, CreatedAt: now.AddDate(0, -6, 0)},
+			{Action: model.AuditActionLoginFailed, CreatedAt: now.AddDate(0, -6, 0)},
+			{Action: model.AuditActionLoginFailed, CreatedAt: now.AddDate(0, 0, -1)},
+		} {
+			entry.UserID = &fixture.UserOne.ID
+			assert.Nil(t, test.DB.Create(&entry).Error)
+		}
+	}
+
+	t.Run("GET /v1/admin/retention", func(t *testing.T) {
+		t.Run("should count the rows past each policy's retention", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+			insertAuditLogs(t)
+
+			statusCode, policies := requestAs(t, fixture.Admin, http.MethodGet, "/v1/admin/retention")
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Len(t, policies, len(config.Retention.Days))
+
+			rows := rowsByPolicy(policies)
+			assert.Equal(t, int64(1), rows[config.RetentionAuditLogs])
+			assert.Equal(t, int64(1), rows[config.RetentionLoginHistory])
+			assert.Equal(t, int64(0), rows[config.RetentionExports])
+		})
+
+		t.Run("should return 403 error if user is not admin", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			statusCode, _ := requestAs(t, fixture.UserOne, http.MethodGet, "/v1/admin/retention")
+			assert.Equal(t, http.StatusForbidden, statusCode)
+		})
+	})
+
+	t.Run("POST /v1/admin/retention/purge", func(t *testing.T) {
+		t.Run("should delete only the rows past their retention and audit the purge", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+			insertAuditLogs(t)
+
+			statusCode, policies := requestAs(t, fixture.Admin, http.MethodPost, "/v1/admin/retention/purge")
+			assert.Equal(t, http.StatusOK, statusCode)
+
+			rows := rowsByPolicy(policies)
+			assert.Equal(t, int64(1), rows[config.RetentionAuditLogs])
+			assert.Equal(t, int64(1), rows[config.RetentionLoginHistory])
+
+			var remaining int64
+			assert.Nil(t, test.DB.Model(&model.AuditLog{}).
+				Where("action <> ?", model.AuditActionRetentionPurged).Count(&remaining).Error)
+			assert.Equal(t, int64(2), remaining)
+
+			var purged model.AuditLog
+			assert.Nil(t, test.DB.Where("action = ?", model.AuditActionRetentionPurged).First(&purged).Error)
+		})
+
+		t.Run("should only count the rows in a dry run", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+			insertAuditLogs(t)
+
+			statusCode, _ := requestAs(t, fixture.Admin, http.MethodPost, "/v1/admin/retention/purge?dryRun=true")
+			assert.Equal(t, http.StatusOK, statusCode)
+
+			var count int64
+			assert.Nil(t, test.DB.Model(&model.AuditLog{}).Count(&count).Error)
+			assert.Equal(t, int64(4), count)
+		})
+	})
+}
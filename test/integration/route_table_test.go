@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"app/src/response"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteTableRoutes(t *testing.T) {
+	t.Run("GET /v1/admin/routes", func(t *testing.T) {
+		t.Run("should list the routes with their access policies and middleware", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/routes", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithRoutes)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+
+			var updateUser *response.Route
+			for i, route := range responseBody.Routes {
+				if route.Method == http.MethodPatch && route.Path == "/v1/users/:userId" {
+					updateUser = &responseBody.Routes[i]
+				}
+			}
+
+			assert.NotNil(t, updateUser)
+			assert.Equal(t, []string{"anyOf(rights(manageUsers), owner(userId))"}, updateUser.Access)
+			assert.Equal(t, "controller.(*UserController).UpdateUser", updateUser.Handler)
+			assert.NotEmpty(t, updateUser.Middleware)
+		})
+
+		t.Run("should return 403 error if user is not an admin", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/routes", nil)
+			request.Header.Set("Authorization", "Bearer "+userOneAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusForbidden, apiResponse.StatusCode)
+		})
+	})
+}
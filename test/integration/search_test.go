@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"app/src/config"
+	"app/src/model"
+	"app/src/response"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchRoutes(t *testing.T) {
+	searchAs := func(t *testing.T, user *model.User, query url.Values) (int, []response.SearchHit) {
+		accessToken, err := fixture.AccessToken(user)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodGet, "/v1/admin/search?"+query.Encode(), nil)
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithSearchHits)
+		_ = json.Unmarshal(bytes, responseBody)
+		return apiResponse.StatusCode, responseBody.Results
+	}
+
+	t.Run("GET /v1/admin/search", func(t *testing.T) {
+		t.Run("should find users by email prefix", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne, fixture.UserTwo)
+
+			statusCode, hits := searchAs(t, fixture.Admin, url.Values{"q": {"test1@"}, "types": {"user"}})
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Len(t, hits, 1)
+			assert.Equal(t, "user", hits[0].Type)
+			assert.Equal(t, fixture.UserOne.ID.String(), hits[0].ID)
+		})
+
+		t.Run("should rank exact ID matches first across types", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin, fixture.UserOne)
+			assert.Nil(t, helper.SaveToken(test.DB, "refresh-token", fixture.UserOne.ID.String(),
+				config.TokenTypeRefresh, time.Now().Add(time.Hour)))
+
+			statusCode, hits := searchAs(t, fixture.Admin, url.Values{"q": {fixture.UserOne.ID.String()}})
+			assert.Equal(t, http.StatusOK, statusCode)
+			types := map[string]bool{}
+			for _, hit := range hits {
+				types[hit.Type] = true
+				assert.Equal(t, fixture.UserOne.ID, *hit.UserID)
+				assert.GreaterOrEqual(t, hit.Score, 1.0)
+			}
+			assert.True(t, types["user"])
+			assert.True(t, types["session"])
+		})
+
+		t.Run("should find audit entries by action words", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+			assert.Nil(t, test.DB.Create(&model.AuditLog{UserID: &fixture.Admin.ID, Action: model.AuditActionLoginFailed,
+				IP: "203.0.113.7", Metadata: "{}"}).Error)
+
+			statusCode, hits := searchAs(t, fixture.Admin, url.Values{"q": {"login failed"}, "types": {"audit"}})
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Len(t, hits, 1)
+			assert.Equal(t, model.AuditActionLoginFailed, hits[0].Title)
+		})
+
+		t.Run("should return 400 error for an unknown entity type", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			statusCode, _ := searchAs(t, fixture.Admin, url.Values{"q": {"test"}, "types": {"invoice"}})
+			assert.Equal(t, http.StatusBadRequest, statusCode)
+		})
+
+		t.Run("should return 403 error without the adminSearch right", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			statusCode, _ := searchAs(t, fixture.UserOne, url.Values{"q": {"test"}})
+			assert.Equal(t, http.StatusForbidden, statusCode)
+		})
+	})
+}
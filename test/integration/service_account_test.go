@@ -0,0 +1,186 @@
+package integration
+
+import (
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceAccountRoutes(t *testing.T) {
+	create := func(t *testing.T, token string, req *validation.CreateServiceAccount) (int, *response.SuccessWithServiceAccount) {
+		bodyJSON, err := json.Marshal(req)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/admin/service-accounts", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithServiceAccount)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	billing := &validation.CreateServiceAccount{
+		Name: "billing-worker", Identity: "spiffe://example.com/billing-worker", Rights: []string{"getUsers"},
+	}
+
+	t.Run("POST /v1/admin/service-accounts", func(t *testing.T) {
+		t.Run("should return 201 and the created service account", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, body := create(t, adminAccessToken, billing)
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Equal(t, "spiffe://example.com/billing-worker", body.ServiceAccount.Identity)
+			assert.Equal(t, []string{"getUsers"}, body.ServiceAccount.Rights)
+		})
+
+		t.Run("should return 409 error if the identity is taken", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := create(t, adminAccessToken, billing)
+			assert.Equal(t, http.StatusCreated, status)
+
+			status, _ = create(t, adminAccessToken, &validation.CreateServiceAccount{
+				Name: "other-worker", Identity: billing.Identity, Rights: []string{"getUsers"},
+			})
+			assert.Equal(t, http.StatusConflict, status)
+		})
+
+		t.Run("should return 403 error if a right is not held by the admin", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := create(t, adminAccessToken, &validation.CreateServiceAccount{
+				Name: "billing-worker", Identity: "billing.internal", Rights: []string{"unknownRight"},
+			})
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+
+		t.Run("should return 403 error if user is not an admin", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status, _ := create(t, userOneAccessToken, billing)
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+	})
+
+	t.Run("DELETE /v1/admin/service-accounts/:serviceAccountId", func(t *testing.T) {
+		t.Run("should delete the service account and list it no more", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			_, created := create(t, adminAccessToken, billing)
+
+			request := httptest.NewRequest(http.MethodDelete, "/v1/admin/service-accounts/"+created.ServiceAccount.ID.String(), nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			request = httptest.NewRequest(http.MethodGet, "/v1/admin/service-accounts", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err = test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithServiceAccounts)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+			assert.Empty(t, responseBody.ServiceAccounts)
+		})
+	})
+	t.Run("POST /v1/admin/service-accounts/:serviceAccountId/tokens", func(t *testing.T) {
+		mint := func(t *testing.T, adminAccessToken, serviceAccountID string, scopes []string) (int, string) {
+			bodyJSON, err := json.Marshal(&validation.CreateAPIToken{Name: "billing", Scopes: scopes, ExpiresInDays: 30})
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost,
+				"/v1/admin/service-accounts/"+serviceAccountID+"/tokens", strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithAPIToken)
+			_ = json.Unmarshal(bytes, responseBody)
+
+			return apiResponse.StatusCode, responseBody.Token
+		}
+
+		t.Run("should return a token that authenticates as the service account", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			_, created := create(t, adminAccessToken, billing)
+
+			status, token := mint(t, adminAccessToken, created.ServiceAccount.ID.String(), []string{"getUsers"})
+			assert.Equal(t, http.StatusCreated, status)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/users?type=service", nil)
+			request.Header.Set("Authorization", "Bearer "+token)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+		})
+
+		t.Run("should return 403 error if a scope is not a right of the service account", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			_, created := create(t, adminAccessToken, billing)
+
+			status, _ := mint(t, adminAccessToken, created.ServiceAccount.ID.String(), []string{"manageUsers"})
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+	})
+}
@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionActivityRoutes(t *testing.T) {
+	t.Run("GET /v1/admin/sessions/activity", func(t *testing.T) {
+		t.Run("should count a session signed in just now in every window", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			// InsertUser hashes the password in place, so sign in with a user of our own
+			admin := &model.User{Name: "Admin", Email: "sessions@gmail.com", Password: "password1", Role: "admin"}
+			helper.InsertUser(test.DB, admin)
+
+			bodyJSON, err := json.Marshal(&validation.Login{Email: admin.Email, Password: "password1"})
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(string(bodyJSON)))
+			request.Header.Set("Content-Type", "application/json")
+
+			apiResponse, err := test.App.Test(request, 2000)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			login := new(response.SuccessWithTokens)
+			assert.Nil(t, json.Unmarshal(bytes, login))
+
+			request = httptest.NewRequest(http.MethodGet, "/v1/admin/sessions/activity", nil)
+			request.Header.Set("Authorization", "Bearer "+login.Tokens.Access.Token)
+
+			apiResponse, err = test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err = io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithSessionActivity)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+			assert.Len(t, responseBody.Windows, 3)
+			for _, window := range responseBody.Windows {
+				assert.Equal(t, int64(1), window.Sessions)
+				assert.Equal(t, int64(1), window.Users)
+			}
+		})
+
+		t.Run("should return 403 if a non-admin is reading activity", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/sessions/activity", nil)
+			request.Header.Set("Authorization", "Bearer "+userOneAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusForbidden, apiResponse.StatusCode)
+		})
+	})
+}
@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageRoutes(t *testing.T) {
+	day := func(date string) time.Time {
+		parsed, _ := time.Parse(time.DateOnly, date)
+		return parsed
+	}
+	tokenID := uuid.New()
+
+	insertUsage := func(t *testing.T) {
+		helper.ClearAll(test.DB)
+		helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo, fixture.Admin)
+
+		userOne := model.UserQuotaSubject(fixture.UserOne.ID)
+		records := []model.UsageRecord{
+			{Day: day("2026-09-01"), Subject: userOne, Metric: model.UsageMetricRequests, UserID: fixture.UserOne.ID, Count: 10},
+			{Day: day("2026-09-01"), Subject: userOne, Metric: model.AuditActionLoginSucceeded, UserID: fixture.UserOne.ID, Count: 2},
+			{Day: day("2026-09-02"), Subject: userOne, Metric: model.UsageMetricRequests, UserID: fixture.UserOne.ID, Count: 5},
+			{
+				Day: day("2026-09-02"), Subject: model.TokenQuotaSubject(tokenID), Metric: model.UsageMetricRequests,
+				UserID: fixture.UserOne.ID, Count: 7,
+			},
+			{
+				Day: day("2026-09-02"), Subject: model.UserQuotaSubject(fixture.UserTwo.ID), Metric: model.UsageMetricRequests,
+				UserID: fixture.UserTwo.ID, Count: 3,
+			},
+			{Day: day("2026-10-01"), Subject: userOne, Metric: model.UsageMetricRequests, UserID: fixture.UserOne.ID, Count: 100},
+		}
+		assert.Nil(t, test.DB.Create(&records).Error)
+	}
+
+	get := func(t *testing.T, token, url string, body any) int {
+		request := httptest.NewRequest(http.MethodGet, url, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+		_ = json.Unmarshal(bytes, body)
+
+		return apiResponse.StatusCode
+	}
+
+	t.Run("GET /v1/usage", func(t *testing.T) {
+		t.Run("should return 200 and the user's usage per day and per API token", func(t *testing.T) {
+			insertUsage(t)
+
+			accessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			body := new(response.SuccessWithUsage)
+			status := get(t, accessToken, "/v1/usage?from=2026-09-01&to=2026-09-30", body)
+			assert.Equal(t, http.StatusOK, status)
+
+			assert.Equal(t, map[string]int64{"requests": 22, "login.succeeded": 2}, body.Usage.Totals)
+			assert.Len(t, body.Usage.Days, 2)
+			assert.Equal(t, "2026-09-02", body.Usage.Days[1].Date)
+			assert.Equal(t, int64(12), body.Usage.Days[1].Metrics["requests"])
+			assert.Len(t, body.Usage.Keys, 1)
+			assert.Equal(t, tokenID, body.Usage.Keys[0].TokenID)
+			assert.Equal(t, int64(7), body.Usage.Keys[0].Metrics["requests"])
+		})
+
+		t.Run("should return 400 if the range is reversed or too long", func(t *testing.T) {
+			insertUsage(t)
+
+			accessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status := get(t, accessToken, "/v1/usage?from=2026-09-30&to=2026-09-01", new(response.ErrorDetails))
+			assert.Equal(t, http.StatusBadRequest, status)
+
+			status = get(t, accessToken, "/v1/usage?from=2024-01-01&to=2026-09-01", new(response.ErrorDetails))
+			assert.Equal(t, http.StatusBadRequest, status)
+
+			status = get(t, accessToken, "/v1/usage?from=09-01-2026", new(response.ErrorDetails))
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("should return 401 without an access token", func(t *testing.T) {
+			apiResponse, err := test.App.Test(httptest.NewRequest(http.MethodGet, "/v1/usage", nil))
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusUnauthorized, apiResponse.StatusCode)
+		})
+	})
+
+	t.Run("GET /v1/admin/usage", func(t *testing.T) {
+		t.Run("should return 200 and the usage of all users ranked by the metric", func(t *testing.T) {
+			insertUsage(t)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			body := new(response.SuccessWithUsageReport)
+			status := get(t, adminAccessToken, "/v1/admin/usage?from=2026-09-01&to=2026-09-30", body)
+			assert.Equal(t, http.StatusOK, status)
+
+			assert.Equal(t, int64(25), body.Report.Totals["requests"])
+			assert.Equal(t, int64(2), body.Report.ActiveUsers)
+			assert.Len(t, body.Report.TopUsers, 2)
+			assert.Equal(t, fixture.UserOne.ID, body.Report.TopUsers[0].UserID)
+			assert.Equal(t, fixture.UserOne.Email, body.Report.TopUsers[0].Email)
+			assert.Equal(t, int64(22), body.Report.TopUsers[0].Count)
+		})
+
+		t.Run("should return 403 if a non-admin is reading the report", func(t *testing.T) {
+			insertUsage(t)
+
+			accessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status := get(t, accessToken, "/v1/admin/usage", new(response.ErrorDetails))
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+	})
+
+	t.Run("GET /v1/admin/usage/users/:userId", func(t *testing.T) {
+		t.Run("should return 200 and the user's usage", func(t *testing.T) {
+			insertUsage(t)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			body := new(response.SuccessWithUsage)
+			url := "/v1/admin/usage/users/" + fixture.UserTwo.ID.String() + "?from=2026-09-01&to=2026-09-30"
+			status := get(t, adminAccessToken, url, body)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, map[string]int64{"requests": 3}, body.Usage.Totals)
+		})
+
+		t.Run("should return 400 if the user ID is invalid", func(t *testing.T) {
+			insertUsage(t)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status := get(t, adminAccessToken, "/v1/admin/usage/users/not-a-uuid", new(response.ErrorDetails))
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	})
+}
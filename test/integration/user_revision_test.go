@@ -0,0 +1,182 @@
+package integration
+
+import (
+	"app/src/model"
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRevisionRoutes(t *testing.T) {
+	update := func(t *testing.T, token string, body validation.UpdateUser) {
+		bodyJSON, err := json.Marshal(body)
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPatch, "/v1/users/"+fixture.UserOne.ID.String(), strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+	}
+
+	list := func(t *testing.T, token string) (int, *response.SuccessWithPaginate[response.UserRevision]) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/users/"+fixture.UserOne.ID.String()+"/revisions", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithPaginate[response.UserRevision])
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	restore := func(t *testing.T, token, revisionID string) (int, *response.SuccessWithUser) {
+		request := httptest.NewRequest(http.MethodPost,
+			"/v1/users/"+fixture.UserOne.ID.String()+"/revisions/"+revisionID+"/restore", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithUser)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	changes := func(t *testing.T, revision response.UserRevision) map[string]model.FieldChange {
+		fields := map[string]model.FieldChange{}
+		assert.Nil(t, json.Unmarshal(revision.Changes, &fields))
+		return fields
+	}
+
+	t.Run("GET /v1/users/:userId/revisions", func(t *testing.T) {
+		t.Run("should return the changes newest first with the acting user", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			update(t, adminAccessToken, validation.UpdateUser{
+				Name: validation.Some("Renamed"), Password: validation.Some("newPassword1"),
+			})
+
+			status, body := list(t, userOneAccessToken)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, int64(2), body.TotalResults)
+
+			updated := body.Results[0]
+			assert.Equal(t, model.UserRevisionUpdated, updated.Action)
+			assert.Equal(t, fixture.Admin.ID, *updated.ActorID)
+
+			fields := changes(t, updated)
+			assert.Equal(t, fixture.UserOne.Name, fields["name"].From)
+			assert.Equal(t, "Renamed", fields["name"].To)
+			assert.True(t, fields["password"].Redacted)
+			assert.NotContains(t, string(updated.Changes), "$2a$")
+
+			created := body.Results[1]
+			assert.Equal(t, model.UserRevisionCreated, created.Action)
+			assert.Nil(t, created.ActorID)
+		})
+
+		t.Run("should return 403 if user is reading another user's revisions", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
+
+			userTwoAccessToken, err := fixture.AccessToken(fixture.UserTwo)
+			assert.Nil(t, err)
+
+			status, _ := list(t, userTwoAccessToken)
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+	})
+
+	t.Run("POST /v1/users/:userId/revisions/:revisionId/restore", func(t *testing.T) {
+		t.Run("should restore the profile and record the rollback", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			update(t, adminAccessToken, validation.UpdateUser{Name: validation.Some("First")})
+			update(t, adminAccessToken, validation.UpdateUser{
+				Name: validation.Some("Second"), Timezone: validation.Some("Asia/Jakarta"),
+			})
+
+			_, body := list(t, adminAccessToken)
+			assert.Equal(t, int64(3), body.TotalResults)
+			first := body.Results[1]
+
+			status, restored := restore(t, adminAccessToken, first.ID.String())
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, "First", restored.User.Name)
+
+			user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+			assert.Equal(t, "First", user.Name)
+			assert.Nil(t, user.Timezone)
+
+			_, body = list(t, adminAccessToken)
+			assert.Equal(t, int64(4), body.TotalResults)
+
+			rollback := body.Results[0]
+			assert.Equal(t, model.UserRevisionRestored, rollback.Action)
+			assert.Equal(t, first.ID, *rollback.RestoredFrom)
+
+			fields := changes(t, rollback)
+			assert.Equal(t, "Second", fields["name"].From)
+			assert.Equal(t, "First", fields["name"].To)
+			assert.Nil(t, fields["timezone"].To)
+		})
+
+		t.Run("should return 403 if a non-admin is restoring a revision", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			_, body := list(t, userOneAccessToken)
+			assert.NotEmpty(t, body.Results)
+
+			status, _ := restore(t, userOneAccessToken, body.Results[0].ID.String())
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+
+		t.Run("should return 404 if the revision is not the user's", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := restore(t, adminAccessToken, fixture.Admin.ID.String())
+			assert.Equal(t, http.StatusNotFound, status)
+		})
+	})
+}
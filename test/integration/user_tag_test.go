@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserTagRoutes(t *testing.T) {
+	addTags := func(t *testing.T, token, userID string, tags ...string) (int, *response.SuccessWithTags) {
+		bodyJSON, err := json.Marshal(&validation.UserTags{Tags: tags})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/users/"+userID+"/tags", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithTags)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	t.Run("POST /v1/users/:userId/tags", func(t *testing.T) {
+		t.Run("should return 200 and the user's tags, lowercased and deduplicated", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, body := addTags(t, adminAccessToken, fixture.UserOne.ID.String(), "VIP", "beta", "vip")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, []string{"beta", "vip"}, body.Tags)
+
+			status, body = addTags(t, adminAccessToken, fixture.UserOne.ID.String(), "beta", "abuser")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, []string{"abuser", "beta", "vip"}, body.Tags)
+		})
+
+		t.Run("should return 400 if a tag has invalid characters", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			status, _ := addTags(t, adminAccessToken, fixture.UserOne.ID.String(), "not a tag")
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("should return 403 if a non-admin is tagging a user", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			status, _ := addTags(t, userOneAccessToken, fixture.UserOne.ID.String(), "vip")
+			assert.Equal(t, http.StatusForbidden, status)
+		})
+	})
+
+	t.Run("DELETE /v1/users/:userId/tags/:tag", func(t *testing.T) {
+		t.Run("should return 200 and the remaining tags", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			addTags(t, adminAccessToken, fixture.UserOne.ID.String(), "beta", "vip")
+
+			request := httptest.NewRequest(http.MethodDelete, "/v1/users/"+fixture.UserOne.ID.String()+"/tags/vip", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithTags)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+			assert.Equal(t, []string{"beta"}, responseBody.Tags)
+		})
+
+		t.Run("should return 404 if the user does not have the tag", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodDelete, "/v1/users/"+fixture.UserOne.ID.String()+"/tags/vip", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusNotFound, apiResponse.StatusCode)
+		})
+	})
+
+	t.Run("GET /v1/users?tag=", func(t *testing.T) {
+		t.Run("should return only users carrying the tag", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			addTags(t, adminAccessToken, fixture.UserTwo.ID.String(), "beta")
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/users?tag=beta", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithPaginate[map[string]interface{}])
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+			assert.Equal(t, int64(1), responseBody.TotalResults)
+			assert.Equal(t, fixture.UserTwo.ID.String(), responseBody.Results[0]["id"])
+			assert.Equal(t, []interface{}{"beta"}, responseBody.Results[0]["tags"])
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"app/src/config"
 	"app/src/model"
 	"app/src/response"
 	"app/src/validation"
@@ -621,14 +622,134 @@ func TestUserRoutes(t *testing.T) {
 		})
 	})
 
+	t.Run("POST /v1/users/:userId/suspend", func(t *testing.T) {
+		t.Run("should return 200, suspend the user and revoke their tokens", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			refreshToken, err := fixture.RefreshToken(fixture.UserOne)
+			assert.Nil(t, err)
+			err = helper.SaveToken(test.DB, refreshToken, fixture.UserOne.ID.String(), config.TokenTypeRefresh, fixture.ExpiresRefreshToken)
+			assert.Nil(t, err)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/users/"+fixture.UserOne.ID.String()+"/suspend", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+			assert.False(t, user.IsActive)
+
+			token, _ := helper.GetTokenByUserID(test.DB, refreshToken)
+			assert.Nil(t, token)
+		})
+
+		t.Run("should return 423 when a suspended user makes a request", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+			test.DB.Model(fixture.UserOne).Update("is_active", false)
+			defer func() { fixture.UserOne.IsActive = true }()
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/users/"+fixture.UserOne.ID.String(), nil)
+			request.Header.Set("Authorization", "Bearer "+userOneAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusLocked, apiResponse.StatusCode)
+		})
+
+		t.Run("should return 403 if a non-admin is suspending a user", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/users/"+fixture.UserOne.ID.String()+"/suspend", nil)
+			request.Header.Set("Authorization", "Bearer "+userOneAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusForbidden, apiResponse.StatusCode)
+		})
+
+		t.Run("should return 400 if admin is suspending themselves", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/users/"+fixture.Admin.ID.String()+"/suspend", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, apiResponse.StatusCode)
+		})
+	})
+
+	t.Run("POST /v1/users/:userId/reactivate", func(t *testing.T) {
+		t.Run("should return 200 and reactivate the user", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+			test.DB.Model(fixture.UserOne).Update("is_active", false)
+			defer func() { fixture.UserOne.IsActive = true }()
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/users/"+fixture.UserOne.ID.String()+"/reactivate", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+			assert.True(t, user.IsActive)
+		})
+
+		t.Run("should return 409 if the user is not suspended", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/users/"+fixture.UserOne.ID.String()+"/reactivate", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusConflict, apiResponse.StatusCode)
+		})
+	})
+
 	t.Run("PATCH /v1/users/:userId", func(t *testing.T) {
 		t.Run("should return 200 and successfully update user if data is ok", func(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.UserOne)
 			updateBody := validation.UpdateUser{
-				Name:     "Golang",
-				Email:    "golang@gmail.com",
-				Password: "newPassword1",
+				Name:     validation.Some("Golang"),
+				Email:    validation.Some("golang@gmail.com"),
+				Password: validation.Some("newPassword1"),
 			}
 
 			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
@@ -657,8 +778,8 @@ func TestUserRoutes(t *testing.T) {
 			assert.Equal(t, "success", responseBody.Status)
 			assert.NotContains(t, string(bytes), "password")
 			assert.Equal(t, fixture.UserOne.ID, responseBody.User.ID)
-			assert.Equal(t, updateBody.Name, responseBody.User.Name)
-			assert.Equal(t, updateBody.Email, responseBody.User.Email)
+			assert.Equal(t, updateBody.Name.Value, responseBody.User.Name)
+			assert.Equal(t, updateBody.Email.Value, responseBody.User.Email)
 			assert.Equal(t, "user", responseBody.User.Role)
 			assert.Equal(t, false, responseBody.User.VerifiedEmail)
 
@@ -666,9 +787,9 @@ func TestUserRoutes(t *testing.T) {
 			assert.Nil(t, err)
 
 			assert.NotNil(t, user)
-			assert.NotEqual(t, user.Password, updateBody.Password)
-			assert.Equal(t, user.Name, updateBody.Name)
-			assert.Equal(t, user.Email, updateBody.Email)
+			assert.NotEqual(t, user.Password, updateBody.Password.Value)
+			assert.Equal(t, user.Name, updateBody.Name.Value)
+			assert.Equal(t, user.Email, updateBody.Email.Value)
 			assert.Equal(t, user.Role, "user")
 		})
 
@@ -676,7 +797,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.UserOne)
 			updateBody := validation.UpdateUser{
-				Name: "Golang",
+				Name: validation.Some("Golang"),
 			}
 
 			bodyJSON, err := json.Marshal(updateBody)
@@ -696,7 +817,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
 			updateBody := validation.UpdateUser{
-				Name: "Golang",
+				Name: validation.Some("Golang"),
 			}
 
 			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
@@ -720,7 +841,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.UserOne, fixture.Admin)
 			updateBody := validation.UpdateUser{
-				Name: "Golang",
+				Name: validation.Some("Golang"),
 			}
 
 			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
@@ -744,7 +865,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.Admin)
 			updateBody := validation.UpdateUser{
-				Name: "Golang",
+				Name: validation.Some("Golang"),
 			}
 
 			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
@@ -768,7 +889,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.Admin)
 			updateBody := validation.UpdateUser{
-				Name: "Golang",
+				Name: validation.Some("Golang"),
 			}
 
 			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
@@ -792,7 +913,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.UserOne)
 			updateBody := validation.UpdateUser{
-				Email: "invalidEmail",
+				Email: validation.Some("invalidEmail"),
 			}
 
 			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
@@ -816,7 +937,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.UserOne, fixture.UserTwo)
 			updateBody := validation.UpdateUser{
-				Email: fixture.UserTwo.Email,
+				Email: validation.Some(fixture.UserTwo.Email),
 			}
 
 			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
@@ -840,7 +961,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.UserOne)
 			updateBody := validation.UpdateUser{
-				Email: fixture.UserOne.Email,
+				Email: validation.Some(fixture.UserOne.Email),
 			}
 
 			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
@@ -864,7 +985,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.UserOne)
 			updateBody := validation.UpdateUser{
-				Password: "passwo1",
+				Password: validation.Some("passwo1"),
 			}
 
 			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
@@ -888,7 +1009,7 @@ func TestUserRoutes(t *testing.T) {
 			helper.ClearAll(test.DB)
 			helper.InsertUser(test.DB, fixture.UserOne)
 			updateBody := validation.UpdateUser{
-				Password: "password",
+				Password: validation.Some("password"),
 			}
 
 			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
@@ -907,7 +1028,7 @@ func TestUserRoutes(t *testing.T) {
 
 			assert.Equal(t, http.StatusBadRequest, apiResponse.StatusCode)
 
-			updateBody.Password = "11111111"
+			updateBody.Password = validation.Some("11111111")
 
 			bodyJSON, err = json.Marshal(updateBody)
 			assert.Nil(t, err)
@@ -922,5 +1043,102 @@ func TestUserRoutes(t *testing.T) {
 
 			assert.Equal(t, http.StatusBadRequest, apiResponse.StatusCode)
 		})
+
+		patchUser := func(t *testing.T, accessToken, userID, contentType, body string) *http.Response {
+			request := httptest.NewRequest(http.MethodPatch, "/v1/users/"+userID, strings.NewReader(body))
+			request.Header.Set("Content-Type", contentType)
+			request.Header.Set("Accept", "application/json")
+			request.Header.Set("Authorization", "Bearer "+accessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			return apiResponse
+		}
+
+		t.Run("should clear fields set to null and empty those set to empty strings", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			apiResponse := patchUser(t, userOneAccessToken, fixture.UserOne.ID.String(),
+				"application/merge-patch+json", `{"username": "test_one", "timezone": "Asia/Jakarta"}`)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			apiResponse = patchUser(t, userOneAccessToken, fixture.UserOne.ID.String(),
+				"application/merge-patch+json", `{"username": null, "timezone": null, "name": ""}`)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+			assert.Nil(t, user.Username)
+			assert.Nil(t, user.Timezone)
+			assert.Equal(t, "", user.Name)
+			assert.Equal(t, fixture.UserOne.Email, user.Email)
+		})
+
+		t.Run("should return 400 if a merge patch clears a required field", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			apiResponse := patchUser(t, userOneAccessToken, fixture.UserOne.ID.String(),
+				"application/merge-patch+json", `{"email": null}`)
+			assert.Equal(t, http.StatusBadRequest, apiResponse.StatusCode)
+		})
+
+		t.Run("should apply a JSON Patch only if its tests pass", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			apiResponse := patchUser(t, userOneAccessToken, fixture.UserOne.ID.String(), "application/json-patch+json",
+				`[{"op": "test", "path": "/name", "value": "Someone else"}, {"op": "replace", "path": "/name", "value": "Golang"}]`)
+			assert.Equal(t, http.StatusConflict, apiResponse.StatusCode)
+
+			apiResponse = patchUser(t, userOneAccessToken, fixture.UserOne.ID.String(), "application/json-patch+json",
+				`[{"op": "test", "path": "/name", "value": "Test1"}, {"op": "replace", "path": "/name", "value": "Golang"}]`)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+			assert.Equal(t, "Golang", user.Name)
+		})
+
+		t.Run("should return 422 if a JSON Patch path cannot be patched", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			apiResponse := patchUser(t, userOneAccessToken, fixture.UserOne.ID.String(), "application/json-patch+json",
+				`[{"op": "replace", "path": "/verified_email", "value": true}]`)
+			assert.Equal(t, http.StatusUnprocessableEntity, apiResponse.StatusCode)
+		})
+
+		t.Run("should return 403 if a user changes their own role", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.UserOne)
+
+			userOneAccessToken, err := fixture.AccessToken(fixture.UserOne)
+			assert.Nil(t, err)
+
+			apiResponse := patchUser(t, userOneAccessToken, fixture.UserOne.ID.String(), "application/json-patch+json",
+				`[{"op": "replace", "path": "/role", "value": "admin"}]`)
+			assert.Equal(t, http.StatusForbidden, apiResponse.StatusCode)
+
+			apiResponse = patchUser(t, userOneAccessToken, fixture.UserOne.ID.String(), "application/json", `{"role": "admin"}`)
+			assert.Equal(t, http.StatusForbidden, apiResponse.StatusCode)
+
+			user, err := helper.GetUserByID(test.DB, fixture.UserOne.ID.String())
+			assert.Nil(t, err)
+			assert.Equal(t, "user", user.Role)
+		})
 	})
 }
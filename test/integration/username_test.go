@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"app/src/response"
+	"app/src/validation"
+	"app/test"
+	"app/test/fixture"
+	"app/test/helper"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsernameRoutes(t *testing.T) {
+	register := func(t *testing.T, email, username string) (int, *response.SuccessWithTokens) {
+		bodyJSON, err := json.Marshal(&validation.Register{
+			Name: "Test", Username: username, Email: email, Password: "password1",
+		})
+		assert.Nil(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/auth/register", strings.NewReader(string(bodyJSON)))
+		request.Header.Set("Content-Type", "application/json")
+
+		apiResponse, err := test.App.Test(request, 2000)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithTokens)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	checkUsername := func(t *testing.T, username string) (int, *response.SuccessWithUsernameAvailability) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/users/check-username?username="+username, nil)
+
+		apiResponse, err := test.App.Test(request)
+		assert.Nil(t, err)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithUsernameAvailability)
+		_ = json.Unmarshal(bytes, responseBody)
+
+		return apiResponse.StatusCode, responseBody
+	}
+
+	t.Run("POST /v1/auth/register", func(t *testing.T) {
+		t.Run("should keep the username's casing and reject it in another case", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+
+			status, body := register(t, "one@gmail.com", "Jane_Doe")
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Equal(t, "Jane_Doe", *body.User.Username)
+
+			status, _ = register(t, "two@gmail.com", "jane_doe")
+			assert.Equal(t, http.StatusConflict, status)
+		})
+
+		t.Run("should return 409 for a reserved username", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+
+			status, _ := register(t, "one@gmail.com", "Admin")
+			assert.Equal(t, http.StatusConflict, status)
+		})
+
+		t.Run("should let several users register without a username", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+
+			status, body := register(t, "one@gmail.com", "")
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Nil(t, body.User.Username)
+
+			status, _ = register(t, "two@gmail.com", "")
+			assert.Equal(t, http.StatusCreated, status)
+		})
+	})
+
+	t.Run("GET /v1/users/check-username", func(t *testing.T) {
+		t.Run("should report free, taken and reserved usernames", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			register(t, "one@gmail.com", "jane_doe")
+
+			status, body := checkUsername(t, "john_doe")
+			assert.Equal(t, http.StatusOK, status)
+			assert.True(t, body.Available)
+
+			_, body = checkUsername(t, "JANE_DOE")
+			assert.False(t, body.Available)
+			assert.Equal(t, "taken", body.Reason)
+
+			_, body = checkUsername(t, "root")
+			assert.False(t, body.Available)
+			assert.Equal(t, "reserved", body.Reason)
+		})
+
+		t.Run("should return 400 for an invalid username", func(t *testing.T) {
+			status, _ := checkUsername(t, "a-b")
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	})
+
+	t.Run("GET /v1/users/handle/:username", func(t *testing.T) {
+		t.Run("should find the user regardless of case", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+			_, registered := register(t, "one@gmail.com", "Jane_Doe")
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/users/handle/jane_doe", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+			bytes, err := io.ReadAll(apiResponse.Body)
+			assert.Nil(t, err)
+
+			responseBody := new(response.SuccessWithFields)
+			assert.Nil(t, json.Unmarshal(bytes, responseBody))
+			assert.Equal(t, registered.User.ID.String(), responseBody.User["id"])
+			assert.Equal(t, "Jane_Doe", responseBody.User["username"])
+		})
+
+		t.Run("should return 404 if no user has the username", func(t *testing.T) {
+			helper.ClearAll(test.DB)
+			helper.InsertUser(test.DB, fixture.Admin)
+
+			adminAccessToken, err := fixture.AccessToken(fixture.Admin)
+			assert.Nil(t, err)
+
+			request := httptest.NewRequest(http.MethodGet, "/v1/users/handle/nobody", nil)
+			request.Header.Set("Authorization", "Bearer "+adminAccessToken)
+
+			apiResponse, err := test.App.Test(request)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusNotFound, apiResponse.StatusCode)
+		})
+	})
+}
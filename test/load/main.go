@@ -0,0 +1,210 @@
+// Command load writes k6 scripts or vegeta targets for the authenticated GET hot path.
+//
+//	go run ./test/load -tool k6 -scenario hit -tokens tokens.txt > hit.js && k6 run hit.js
+//	go run ./test/load -tool vegeta -scenario miss -tokens tokens.txt > miss.txt
+//	vegeta attack -targets miss.txt -rate 100 -duration 30s | vegeta report
+//
+// Each line of the tokens file is an access token; requests fetch the token owner's profile.
+// The redis-down profile expects the server to run with Redis stopped or CHAOS_REDIS=error:100.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// profile is the traffic shape and pass criteria of a scenario
+type profile struct {
+	// Description is written at the top of generated k6 scripts
+	Description string
+	// UniqueQuery adds a query parameter per request so the response cache never answers
+	UniqueQuery bool
+	// RotateTokens spreads requests over every token, so sessions are rarely cached
+	RotateTokens bool
+	// P95 is the 95th percentile latency threshold
+	P95 time.Duration
+	// MaxFailRate is the highest share of failed requests (0-1)
+	MaxFailRate float64
+}
+
+var profiles = map[string]profile{
+	"hit": {
+		Description: "one user, repeated requests served from the session and response caches",
+		P95:         50 * time.Millisecond,
+		MaxFailRate: 0.01,
+	},
+	"miss": {
+		Description:  "every token in turn, with cache-busting queries so responses are rebuilt",
+		UniqueQuery:  true,
+		RotateTokens: true,
+		P95:          150 * time.Millisecond,
+		MaxFailRate:  0.01,
+	},
+	"redis-down": {
+		Description:  "cache-miss traffic while Redis is unavailable, served from the database",
+		UniqueQuery:  true,
+		RotateTokens: true,
+		P95:          250 * time.Millisecond,
+		MaxFailRate:  0.01,
+	},
+}
+
+// target is one authenticated request
+type target struct {
+	URL   string
+	Token string
+}
+
+func main() {
+	tool := flag.String("tool", "k6", "output format: k6 or vegeta")
+	scenario := flag.String("scenario", "hit", "traffic profile: hit, miss or redis-down")
+	baseURL := flag.String("url", "http://localhost:3000", "base URL of the server under test")
+	tokensFile := flag.String("tokens", "", "file with one access token per line")
+	rate := flag.Int("rate", 100, "requests per second (k6)")
+	duration := flag.Duration("duration", 30*time.Second, "test duration (k6)")
+	requests := flag.Int("requests", 1000, "targets to write for cache-busting profiles (vegeta)")
+	flag.Parse()
+
+	p, ok := profiles[*scenario]
+	if !ok {
+		log.Fatalf("unknown scenario %q (available: hit, miss, redis-down)", *scenario)
+	}
+
+	targets, err := loadTargets(*tokensFile, strings.TrimSuffix(*baseURL, "/"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !p.RotateTokens {
+		targets = targets[:1]
+	}
+
+	switch *tool {
+	case "k6":
+		err = writeK6(os.Stdout, *scenario, p, targets, *rate, *duration)
+	case "vegeta":
+		err = writeVegeta(os.Stdout, p, targets, *requests)
+	default:
+		err = fmt.Errorf("unknown tool %q (available: k6, vegeta)", *tool)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadTargets reads the tokens and points each at its owner's profile, taken from the sub claim
+func loadTargets(path, baseURL string) ([]target, error) {
+	if path == "" {
+		return nil, fmt.Errorf("-tokens is required")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var targets []target
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		token := strings.TrimSpace(scanner.Text())
+		if token == "" {
+			continue
+		}
+
+		claims := jwt.MapClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+			return nil, fmt.Errorf("invalid token on line %d: %w", len(targets)+1, err)
+		}
+		userID, _ := claims.GetSubject()
+		targets = append(targets, target{URL: baseURL + "/v1/users/" + userID, Token: token})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no tokens in %s", path)
+	}
+
+	return targets, nil
+}
+
+var k6Script = template.Must(template.New("k6").Parse(`// {{.Scenario}}: {{.Profile.Description}}
+import http from 'k6/http';
+import exec from 'k6/execution';
+import { check } from 'k6';
+
+const targets = [
+{{- range .Targets}}
+  { url: '{{.URL}}', token: '{{.Token}}' },
+{{- end}}
+];
+
+export const options = {
+  scenarios: {
+    {{.Name}}: {
+      executor: 'constant-arrival-rate',
+      rate: {{.Rate}},
+      timeUnit: '1s',
+      duration: '{{.Duration}}',
+      preAllocatedVUs: {{.VUs}},
+    },
+  },
+  thresholds: {
+    http_req_duration: ['p(95)<{{.P95}}'],
+    http_req_failed: ['rate<{{.Profile.MaxFailRate}}'],
+  },
+};
+
+export default function () {
+  const i = exec.scenario.iterationInTest;
+  const target = targets[i % targets.length];
+  const url = {{if .Profile.UniqueQuery}}target.url + '?nocache=' + i{{else}}target.url{{end}};
+  const res = http.get(url, { headers: { Authorization: 'Bearer ' + target.token } });
+  check(res, { 'status is 200': (r) => r.status === 200 });
+}
+`))
+
+func writeK6(w io.Writer, scenario string, p profile, targets []target, rate int, duration time.Duration) error {
+	return k6Script.Execute(w, map[string]any{
+		"Scenario": scenario,
+		"Name":     strings.ReplaceAll(scenario, "-", "_"),
+		"Profile":  p,
+		"Targets":  targets,
+		"Rate":     rate,
+		"Duration": duration.String(),
+		"VUs":      max(rate/10, 10),
+		"P95":      p.P95.Milliseconds(),
+	})
+}
+
+// writeVegeta writes targets in vegeta's HTTP format; vegeta has no thresholds, so they are
+// printed for comparing against "vegeta report"
+func writeVegeta(w io.Writer, p profile, targets []target, requests int) error {
+	count := len(targets)
+	if p.UniqueQuery {
+		count = max(requests, len(targets))
+	}
+
+	for i := 0; i < count; i++ {
+		t := targets[i%len(targets)]
+		url := t.URL
+		if p.UniqueQuery {
+			url += fmt.Sprintf("?nocache=%d", i)
+		}
+		if _, err := fmt.Fprintf(w, "GET %s\nAuthorization: Bearer %s\n\n", url, t.Token); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("thresholds: p95 < %v, error rate < %.0f%%", p.P95, p.MaxFailRate*100)
+	return nil
+}
@@ -0,0 +1,138 @@
+package accesstoken_test
+
+import (
+	"app/src/accesstoken"
+	"app/src/cache"
+	"app/src/utils"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	claims := func(userID, sessionID string) utils.AccessClaims {
+		return utils.AccessClaims{
+			UserID:    userID,
+			SessionID: sessionID,
+			Role:      "admin",
+			Scopes:    []string{"getUsers", "manageUsers"},
+			Plan:      "pro",
+			IssuedAt:  time.Now().Truncate(time.Second),
+		}
+	}
+
+	t.Run("should resolve an issued token to its claims", func(t *testing.T) {
+		memory := cache.NewMemoryStore()
+		defer memory.Close()
+		store := accesstoken.NewStore(memory)
+
+		issued := claims("user-1", "session-1")
+		token, err := store.Issue(issued, time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(token, accesstoken.Prefix))
+
+		resolved, err := store.Resolve(token)
+		assert.NoError(t, err)
+		assert.Equal(t, issued.UserID, resolved.UserID)
+		assert.Equal(t, issued.SessionID, resolved.SessionID)
+		assert.Equal(t, issued.Role, resolved.Role)
+		assert.Equal(t, issued.Scopes, resolved.Scopes)
+		assert.Equal(t, issued.Plan, resolved.Plan)
+		assert.True(t, issued.IssuedAt.Equal(resolved.IssuedAt))
+
+		keys, err := memory.Keys(ctx, "*")
+		assert.NoError(t, err)
+		for _, key := range keys {
+			assert.NotContains(t, key, token, "tokens are only stored hashed")
+		}
+	})
+
+	t.Run("should reject unknown and expired tokens", func(t *testing.T) {
+		memory := cache.NewMemoryStore()
+		defer memory.Close()
+		store := accesstoken.NewStore(memory)
+
+		_, err := store.Resolve(accesstoken.Prefix + "unknown")
+		assert.ErrorIs(t, err, accesstoken.ErrInvalidToken)
+
+		token, err := store.Issue(claims("user-1", ""), time.Now().Add(50*time.Millisecond))
+		assert.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+
+		_, err = store.Resolve(token)
+		assert.ErrorIs(t, err, accesstoken.ErrInvalidToken)
+	})
+
+	t.Run("should revoke only the tokens of the session", func(t *testing.T) {
+		memory := cache.NewMemoryStore()
+		defer memory.Close()
+		store := accesstoken.NewStore(memory)
+		expires := time.Now().Add(time.Minute)
+
+		revoked, err := store.Issue(claims("user-1", "session-1"), expires)
+		assert.NoError(t, err)
+		kept, err := store.Issue(claims("user-1", "session-2"), expires)
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.RevokeSession(ctx, "user-1", "session-1"))
+
+		_, err = store.Resolve(revoked)
+		assert.ErrorIs(t, err, accesstoken.ErrInvalidToken)
+		_, err = store.Resolve(kept)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should revoke every token of the user", func(t *testing.T) {
+		memory := cache.NewMemoryStore()
+		defer memory.Close()
+		store := accesstoken.NewStore(memory)
+		expires := time.Now().Add(time.Minute)
+
+		first, err := store.Issue(claims("user-1", "session-1"), expires)
+		assert.NoError(t, err)
+		second, err := store.Issue(claims("user-1", ""), expires)
+		assert.NoError(t, err)
+		other, err := store.Issue(claims("user-2", "session-3"), expires)
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.RevokeUser(ctx, "user-1"))
+
+		for _, token := range []string{first, second} {
+			_, err = store.Resolve(token)
+			assert.ErrorIs(t, err, accesstoken.ErrInvalidToken)
+		}
+		_, err = store.Resolve(other)
+		assert.NoError(t, err, "other users keep their tokens")
+
+		keys, err := memory.Keys(ctx, cache.AccessTokenKeyPrefix+"*user-1*")
+		assert.NoError(t, err)
+		assert.Empty(t, keys, "the indexes of the user are deleted with the tokens")
+	})
+
+	t.Run("should index every token of a session", func(t *testing.T) {
+		memory := cache.NewMemoryStore()
+		defer memory.Close()
+		store := accesstoken.NewStore(memory)
+
+		first, err := store.Issue(claims("user-1", "session-1"), time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		second, err := store.Issue(claims("user-1", "session-1"), time.Now().Add(2*time.Minute))
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.RevokeUser(ctx, "user-1"))
+
+		for _, token := range []string{first, second} {
+			_, err = store.Resolve(token)
+			assert.ErrorIs(t, err, accesstoken.ErrInvalidToken)
+		}
+	})
+
+	t.Run("should reject opaque tokens while access tokens are JWTs", func(t *testing.T) {
+		_, err := accesstoken.Verify(accesstoken.Prefix + "anything")
+		assert.ErrorIs(t, err, accesstoken.ErrInvalidToken)
+	})
+}
@@ -0,0 +1,68 @@
+package analytics_test
+
+import (
+	"app/src/analytics"
+	"app/src/clock"
+	"app/src/redis"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSketches(t *testing.T) {
+	day := time.Date(2026, time.October, 17, 23, 59, 0, 0, time.UTC)
+
+	t.Run("should key users by UTC day and month", func(t *testing.T) {
+		assert.Equal(t, analytics.Sketch{Kind: analytics.KindDailyUsers, Period: "2026-10-17"}, analytics.DailyUsers(day))
+		assert.Equal(t, analytics.Sketch{Kind: analytics.KindMonthlyUsers, Period: "2026-10"}, analytics.MonthlyUsers(day))
+	})
+
+	t.Run("should key client IPs by day and endpoint", func(t *testing.T) {
+		assert.Equal(t, analytics.Sketch{
+			Kind: analytics.KindEndpointIPs, Period: "2026-10-17", Endpoint: "GET /v1/users/:userId",
+		}, analytics.EndpointIPs(day, "GET /v1/users/:userId"))
+	})
+}
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock(time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC))
+
+	t.Run("should report Redis unavailable without a client", func(t *testing.T) {
+		tracker := analytics.NewTracker(nil, clk)
+
+		_, err := tracker.Count(ctx, []analytics.Sketch{analytics.DailyUsers(clk.Now())})
+		assert.ErrorIs(t, err, redis.ErrRedisUnavailable)
+
+		_, err = tracker.Endpoints(ctx, "2026-10-17")
+		assert.ErrorIs(t, err, redis.ErrRedisUnavailable)
+
+		_, _, err = tracker.Merge(ctx, analytics.DailyUsers(clk.Now()), []byte("HYLL"))
+		assert.ErrorIs(t, err, redis.ErrRedisUnavailable)
+	})
+
+	t.Run("should drop visits rather than fail while Redis is unavailable", func(t *testing.T) {
+		tracker := analytics.NewTracker(nil, clk)
+		userID := uuid.New()
+		tracker.Record("GET /v1/users", "203.0.113.7", &userID)
+		tracker.Record("GET /v1/users", "203.0.113.8", nil)
+
+		flushCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			tracker.Start(flushCtx, time.Millisecond)
+		}()
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("tracker did not stop")
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package backup_test
+
+import (
+	"app/src/backup"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type row struct {
+	table string
+	row   json.RawMessage
+}
+
+var rows = []row{
+	{"users", json.RawMessage(`{"id":"u1","email":"one@example.com","role":"admin"}`)},
+	{"users", json.RawMessage(`{"id":"u2","email":"two@example.com","role":"user"}`)},
+	{"api_tokens", json.RawMessage(`{"id":"t1","user_id":"u2","name":"<ci> & deploy"}`)},
+}
+
+func writeArchive(t *testing.T, passphrase string) []byte {
+	var archive bytes.Buffer
+	writer, err := backup.NewWriter(&archive, backup.Manifest{
+		SchemaVersion: 20261017100000,
+		Environment:   "staging",
+		CreatedAt:     time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+		Tables:        []string{"users", "api_tokens"},
+	}, passphrase)
+	assert.Nil(t, err)
+
+	for _, entry := range rows {
+		assert.Nil(t, writer.WriteRow(entry.table, entry.row))
+	}
+	counts, err := writer.Close()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"users": 2, "api_tokens": 1}, counts)
+
+	return archive.Bytes()
+}
+
+func readArchive(archive []byte, passphrase string) ([]row, error) {
+	reader, err := backup.NewReader(bytes.NewReader(archive), passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	var read []row
+	for {
+		table, entry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return read, nil
+		}
+		if err != nil {
+			return read, err
+		}
+		read = append(read, row{table, entry})
+	}
+}
+
+func TestArchive(t *testing.T) {
+	t.Run("should read back the manifest and rows written", func(t *testing.T) {
+		archive := writeArchive(t, "")
+
+		reader, err := backup.NewReader(bytes.NewReader(archive), "")
+		assert.Nil(t, err)
+		manifest := reader.Manifest()
+		assert.Equal(t, backup.Format, manifest.Format)
+		assert.Equal(t, backup.Version, manifest.Version)
+		assert.Equal(t, int64(20261017100000), manifest.SchemaVersion)
+		assert.Equal(t, "staging", manifest.Environment)
+
+		read, err := readArchive(archive, "")
+		assert.Nil(t, err)
+		assert.Equal(t, rows, read)
+	})
+
+	t.Run("should encrypt the archive with a passphrase", func(t *testing.T) {
+		archive := writeArchive(t, "correct horse")
+		assert.NotContains(t, string(archive), "one@example.com")
+
+		_, err := gzip.NewReader(bytes.NewReader(archive))
+		assert.NotNil(t, err)
+
+		read, err := readArchive(archive, "correct horse")
+		assert.Nil(t, err)
+		assert.Equal(t, rows, read)
+	})
+
+	t.Run("should refuse an encrypted archive without the right passphrase", func(t *testing.T) {
+		archive := writeArchive(t, "correct horse")
+
+		_, err := readArchive(archive, "")
+		assert.ErrorContains(t, err, "BACKUP_PASSPHRASE")
+
+		_, err = readArchive(archive, "wrong horse")
+		assert.ErrorIs(t, err, backup.ErrDecrypt)
+	})
+
+	t.Run("should refuse truncated archives", func(t *testing.T) {
+		for _, passphrase := range []string{"", "correct horse"} {
+			archive := writeArchive(t, passphrase)
+			for _, size := range []int{len(archive) / 2, len(archive) - 1} {
+				_, err := readArchive(archive[:size], passphrase)
+				assert.NotNil(t, err, "passphrase %q, %d bytes", passphrase, size)
+			}
+		}
+	})
+
+	t.Run("should refuse data appended to an encrypted archive", func(t *testing.T) {
+		archive := writeArchive(t, "correct horse")
+
+		_, err := readArchive(append(archive, 0), "correct horse")
+		assert.ErrorIs(t, err, backup.ErrCorrupt)
+	})
+
+	t.Run("should refuse edited rows", func(t *testing.T) {
+		archive := rewrite(t, writeArchive(t, ""), func(content string) string {
+			return strings.Replace(content, `"role":"user"`, `"role":"admin"`, 1)
+		})
+
+		_, err := readArchive(archive, "")
+		assert.ErrorIs(t, err, backup.ErrCorrupt)
+	})
+
+	t.Run("should refuse a dropped row", func(t *testing.T) {
+		archive := rewrite(t, writeArchive(t, ""), func(content string) string {
+			lines := strings.SplitAfter(content, "\n")
+			return strings.Join(append(lines[:2], lines[3:]...), "")
+		})
+
+		_, err := readArchive(archive, "")
+		assert.ErrorIs(t, err, backup.ErrCorrupt)
+	})
+
+	t.Run("should refuse archives of a newer version", func(t *testing.T) {
+		archive := rewrite(t, writeArchive(t, ""), func(content string) string {
+			return strings.Replace(content, `"version":1`, `"version":2`, 1)
+		})
+
+		_, err := readArchive(archive, "")
+		assert.ErrorContains(t, err, "version 2 is newer")
+	})
+
+	t.Run("should refuse files that are not archives", func(t *testing.T) {
+		_, err := readArchive([]byte("id,email\nu1,one@example.com\n"), "")
+		assert.ErrorIs(t, err, backup.ErrCorrupt)
+	})
+}
+
+// rewrite edits the content of an unencrypted archive
+func rewrite(t *testing.T, archive []byte, edit func(string) string) []byte {
+	unzipped, err := gzip.NewReader(bytes.NewReader(archive))
+	assert.Nil(t, err)
+	content, err := io.ReadAll(unzipped)
+	assert.Nil(t, err)
+
+	var rewritten bytes.Buffer
+	zipped := gzip.NewWriter(&rewritten)
+	_, err = zipped.Write([]byte(edit(string(content))))
+	assert.Nil(t, err)
+	assert.Nil(t, zipped.Close())
+	return rewritten.Bytes()
+}
@@ -0,0 +1,107 @@
+package billing_test
+
+import (
+	"app/src/billing"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstructEvent(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"customer.subscription.created","created":1760000000,"data":{"object":{"id":"sub_1"}}}`)
+	now := time.Unix(1760000000, 0)
+
+	header := func(secret string, signedAt time.Time) string {
+		return fmt.Sprintf("t=%d,v1=%s", signedAt.Unix(), billing.Sign(payload, secret, signedAt.Unix()))
+	}
+
+	t.Run("should decode a correctly signed event", func(t *testing.T) {
+		event, err := billing.ConstructEvent(payload, header("whsec_test", now), "whsec_test", 5*time.Minute, now)
+		assert.NoError(t, err)
+		assert.Equal(t, "evt_1", event.ID)
+		assert.Equal(t, billing.EventSubscriptionCreated, event.Type)
+	})
+
+	t.Run("should accept any of several signatures while the secret is rolled", func(t *testing.T) {
+		rolled := header("whsec_old", now) + ",v1=" + billing.Sign(payload, "whsec_test", now.Unix())
+
+		_, err := billing.ConstructEvent(payload, rolled, "whsec_test", 5*time.Minute, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject events signed with another secret or tampered with", func(t *testing.T) {
+		_, err := billing.ConstructEvent(payload, header("whsec_other", now), "whsec_test", 5*time.Minute, now)
+		assert.ErrorIs(t, err, billing.ErrInvalidSignature)
+
+		tampered := []byte(`{"id":"evt_2","type":"customer.subscription.created"}`)
+		_, err = billing.ConstructEvent(tampered, header("whsec_test", now), "whsec_test", 5*time.Minute, now)
+		assert.ErrorIs(t, err, billing.ErrInvalidSignature)
+
+		_, err = billing.ConstructEvent(payload, "", "whsec_test", 5*time.Minute, now)
+		assert.ErrorIs(t, err, billing.ErrInvalidSignature)
+	})
+
+	t.Run("should reject events signed outside the tolerance", func(t *testing.T) {
+		signed := header("whsec_test", now.Add(-10*time.Minute))
+
+		_, err := billing.ConstructEvent(payload, signed, "whsec_test", 5*time.Minute, now)
+		assert.ErrorIs(t, err, billing.ErrExpiredSignature)
+	})
+}
+
+func TestSubscription(t *testing.T) {
+	t.Run("should read the period end from the item on newer API versions", func(t *testing.T) {
+		subscription := new(billing.Subscription)
+		subscription.Items.Data = append(subscription.Items.Data, struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		}{CurrentPeriodEnd: 1760000000})
+
+		assert.Equal(t, time.Unix(1760000000, 0).UTC(), *subscription.PeriodEnd())
+	})
+
+	t.Run("should keep the plan while a payment is retried", func(t *testing.T) {
+		assert.True(t, billing.Entitled("past_due"))
+		assert.False(t, billing.Entitled("canceled"))
+		assert.False(t, billing.Entitled("incomplete"))
+	})
+}
+
+func TestCreateCustomer(t *testing.T) {
+	t.Run("should create the customer with the user ID as idempotency key", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _, _ := r.BasicAuth()
+			assert.Equal(t, "sk_test", user)
+			assert.Equal(t, "/v1/customers", r.URL.Path)
+			assert.Equal(t, "customer-42", r.Header.Get("Idempotency-Key"))
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "jane@example.com", r.PostForm.Get("email"))
+			assert.Equal(t, "42", r.PostForm.Get("metadata[user_id]"))
+
+			_, _ = w.Write([]byte(`{"id":"cus_1"}`))
+		}))
+		defer server.Close()
+
+		customer, err := billing.NewClient(server.URL, "sk_test").CreateCustomer(context.Background(), "42", "jane@example.com", "Jane")
+		assert.NoError(t, err)
+		assert.Equal(t, "cus_1", customer.ID)
+	})
+
+	t.Run("should return Stripe's error message", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"Invalid API Key provided"}}`))
+		}))
+		defer server.Close()
+
+		_, err := billing.NewClient(server.URL, "sk_bad").CreateCustomer(context.Background(), "42", "jane@example.com", "Jane")
+		assert.ErrorContains(t, err, "Invalid API Key provided")
+	})
+}
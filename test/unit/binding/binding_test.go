@@ -0,0 +1,128 @@
+package binding_test
+
+import (
+	"app/src/binding"
+	"app/src/utils"
+	"app/src/validation"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type importRequest struct {
+	Name string `json:"name" form:"name" validate:"required,max=20"`
+}
+
+// pngHeader is enough for content sniffing to detect image/png
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newApp() *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	validate := validation.Validator()
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		req := new(importRequest)
+		if err := binding.Bind(c, validate, req); err != nil {
+			return err
+		}
+
+		file, err := binding.File(c, "avatar", binding.FileRule{
+			MaxSize:      1024,
+			AllowedTypes: []string{"image/png"},
+		})
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(fiber.Map{"name": req.Name, "has_file": file != nil})
+	})
+
+	return app
+}
+
+func multipartRequest(t *testing.T, name string, file []byte) *http.Request {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	assert.NoError(t, writer.WriteField("name", name))
+	if file != nil {
+		part, err := writer.CreateFormFile("avatar", "avatar.png")
+		assert.NoError(t, err)
+		_, err = part.Write(file)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", body)
+	req.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+	return req
+}
+
+func decode(t *testing.T, resp *http.Response) map[string]interface{} {
+	raw, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(raw, &result))
+	return result
+}
+
+func TestBind(t *testing.T) {
+	app := newApp()
+
+	t.Run("should bind JSON payloads", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(`{"name":"alice"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "alice", decode(t, resp)["name"])
+	})
+
+	t.Run("should bind multipart payloads with a file part", func(t *testing.T) {
+		resp, err := app.Test(multipartRequest(t, "bob", pngHeader))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, true, decode(t, resp)["has_file"])
+	})
+
+	t.Run("should report validation errors per field", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(`{"name":""}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, decode(t, resp)["errors"], "importRequest.Name")
+	})
+
+	t.Run("should reject files with a disallowed type", func(t *testing.T) {
+		resp, err := app.Test(multipartRequest(t, "bob", []byte("name,email\nbob,bob@example.com")))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, decode(t, resp)["errors"], "avatar")
+	})
+
+	t.Run("should reject files over the size limit", func(t *testing.T) {
+		resp, err := app.Test(multipartRequest(t, "bob", append(pngHeader, make([]byte, 2048)...)))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, decode(t, resp)["errors"], "avatar")
+	})
+
+	t.Run("should reject unsupported content types", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("name=alice"))
+		req.Header.Set(fiber.HeaderContentType, "text/plain")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+}
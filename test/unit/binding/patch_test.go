@@ -0,0 +1,101 @@
+package binding_test
+
+import (
+	"app/src/binding"
+	"app/src/utils"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPatchApp() *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+
+	app.Patch("/", func(c *fiber.Ctx) error {
+		doc := map[string]any{"name": "Alice", "username": "alice", "timezone": nil}
+		patch, err := binding.BindPatch(c, doc)
+		if err != nil {
+			return err
+		}
+		return c.JSON(patch)
+	})
+
+	return app
+}
+
+func patch(t *testing.T, contentType, body string) (int, *binding.Patch) {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+	request.Header.Set("Content-Type", contentType)
+
+	resp, err := newPatchApp().Test(request)
+	assert.NoError(t, err)
+
+	result := new(binding.Patch)
+	if resp.StatusCode == http.StatusOK {
+		raw, _ := io.ReadAll(resp.Body)
+		assert.NoError(t, json.Unmarshal(raw, result))
+	}
+	return resp.StatusCode, result
+}
+
+func TestBindPatch(t *testing.T) {
+	t.Run("should clear fields set to null by a merge patch", func(t *testing.T) {
+		status, result := patch(t, binding.MIMEMergePatch, `{"username": null, "timezone": "Asia/Jakarta"}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, map[string]any{"username": nil, "timezone": "Asia/Jakarta"}, result.Changes)
+	})
+
+	t.Run("should apply JSON Patch operations in order", func(t *testing.T) {
+		status, result := patch(t, binding.MIMEJSONPatch, `[
+			{"op": "test", "path": "/name", "value": "Alice"},
+			{"op": "remove", "path": "/username"},
+			{"op": "copy", "from": "/name", "path": "/username"},
+			{"op": "replace", "path": "/name", "value": "Bob"}
+		]`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, map[string]any{"name": "Bob", "username": "Alice"}, result.Changes)
+		assert.Equal(t, map[string]any{"name": "Alice"}, result.Tests)
+	})
+
+	t.Run("should not record tests of fields the patch already changed", func(t *testing.T) {
+		status, result := patch(t, binding.MIMEJSONPatch, `[
+			{"op": "replace", "path": "/name", "value": "Bob"},
+			{"op": "test", "path": "/name", "value": "Bob"}
+		]`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Empty(t, result.Tests)
+	})
+
+	t.Run("should return 409 if a test fails", func(t *testing.T) {
+		status, _ := patch(t, binding.MIMEJSONPatch, `[{"op": "test", "path": "/name", "value": "Bob"}]`)
+		assert.Equal(t, http.StatusConflict, status)
+	})
+
+	t.Run("should return 422 for unknown or nested paths", func(t *testing.T) {
+		status, _ := patch(t, binding.MIMEJSONPatch, `[{"op": "add", "path": "/role", "value": "admin"}]`)
+		assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+		status, _ = patch(t, binding.MIMEJSONPatch, `[{"op": "add", "path": "/name/first", "value": "A"}]`)
+		assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+		status, _ = patch(t, binding.MIMEMergePatch, `{"role": "admin"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, status)
+	})
+
+	t.Run("should return 400 for malformed patches", func(t *testing.T) {
+		status, _ := patch(t, binding.MIMEJSONPatch, `{"op": "add"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = patch(t, binding.MIMEJSONPatch, `[{"op": "increment", "path": "/name"}]`)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = patch(t, binding.MIMEJSONPatch, `[{"op": "replace", "path": "/name"}]`)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
@@ -0,0 +1,48 @@
+package cache_test
+
+import (
+	"app/src/cache"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheAdminPurge(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should only purge patterns inside the cache namespaces", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		assert.NoError(t, store.Set("api:response:GET:/v1/users", []byte("{}"), 0))
+		assert.NoError(t, store.Set("access:token:abc", []byte("{}"), 0))
+		assert.NoError(t, store.Set("lock:session-cache-warmup", []byte("1"), 0))
+		admin := cache.NewCacheAdmin(store, cache.NewCacheInvalidator(store))
+
+		for _, pattern := range []string{"*", "a*", "access:*", "api:response*"} {
+			_, err := admin.PurgeByPattern(ctx, pattern)
+			assert.ErrorIs(t, err, cache.ErrNotPurgeable, pattern)
+			_, err = admin.DryRun(ctx, pattern, "")
+			assert.ErrorIs(t, err, cache.ErrNotPurgeable, pattern)
+		}
+
+		result, err := admin.PurgeByPattern(ctx, "api:response:*")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Deleted)
+
+		keys, err := store.Keys(ctx, "*")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"access:token:abc", "lock:session-cache-warmup"}, keys)
+	})
+
+	t.Run("should purge by tag", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		assert.NoError(t, store.Set("session:user:1", []byte("{}"), 0))
+		admin := cache.NewCacheAdmin(store, cache.NewCacheInvalidator(store))
+
+		result, err := admin.PurgeByTag(ctx, "sessions")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Deleted)
+	})
+}
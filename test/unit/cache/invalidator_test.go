@@ -0,0 +1,68 @@
+package cache_test
+
+import (
+	"app/src/cache"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheInvalidator(t *testing.T) {
+	ctx := context.Background()
+
+	seed := func(store cache.Store, n int) {
+		for i := 0; i < n; i++ {
+			assert.NoError(t, store.Set(fmt.Sprintf("api:response:GET:/v1/users?page=%d", i), []byte("{}"), 0))
+		}
+		assert.NoError(t, store.Set("session:user:1", []byte("{}"), 0))
+	}
+
+	t.Run("should report matched and deleted keys", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		seed(store, 3)
+
+		result, err := cache.NewCacheInvalidator(store).InvalidateByPattern(ctx, "api:response:*")
+		assert.NoError(t, err)
+		assert.Equal(t, "api:response:*", result.Pattern)
+		assert.Equal(t, 3, result.Matched)
+		assert.Equal(t, 3, result.Deleted)
+		assert.False(t, result.DryRun)
+
+		keys, _ := store.Keys(ctx, "*")
+		assert.Equal(t, []string{"session:user:1"}, keys)
+	})
+
+	t.Run("should not delete anything on a dry run", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		seed(store, cache.MaxDryRunKeys+5)
+
+		result, err := cache.NewCacheInvalidator(store).DryRunByPattern(ctx, "api:response:*")
+		assert.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, cache.MaxDryRunKeys+5, result.Matched)
+		assert.Equal(t, 0, result.Deleted)
+		assert.Len(t, result.Keys, cache.MaxDryRunKeys)
+
+		keys, _ := store.Keys(ctx, "api:response:*")
+		assert.Len(t, keys, cache.MaxDryRunKeys+5)
+	})
+
+	t.Run("should resolve purge tags for dry runs", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		seed(store, 2)
+
+		admin := cache.NewCacheAdmin(store, cache.NewCacheInvalidator(store))
+
+		result, err := admin.DryRun(ctx, "", "sessions")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"session:user:1"}, result.Keys)
+
+		_, err = admin.DryRun(ctx, "", "unknown")
+		assert.Error(t, err)
+	})
+}
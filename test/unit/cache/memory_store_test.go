@@ -0,0 +1,112 @@
+package cache_test
+
+import (
+	"app/src/cache"
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should return stored values until they expire", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+
+		assert.NoError(t, store.Set("session:user:1", []byte("data"), 50*time.Millisecond))
+
+		value, err := store.Get("session:user:1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("data"), value)
+
+		time.Sleep(60 * time.Millisecond)
+
+		value, err = store.Get("session:user:1")
+		assert.NoError(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("should increment counters and expire them from creation", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+
+		value, err := store.IncrBy(ctx, "quota:user:1:day:2026-10-16", 1, 50*time.Millisecond)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), value)
+
+		value, err = store.IncrBy(ctx, "quota:user:1:day:2026-10-16", 4, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), value)
+
+		time.Sleep(60 * time.Millisecond)
+
+		value, err = store.IncrBy(ctx, "quota:user:1:day:2026-10-16", 1, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), value)
+	})
+
+	t.Run("should expire the members of a set one by one", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+
+		assert.NoError(t, store.AddMember(ctx, "access:user:1", "session-1", 50*time.Millisecond))
+		assert.NoError(t, store.AddMember(ctx, "access:user:1", "session-2", time.Minute))
+
+		members, err := store.Members(ctx, "access:user:1")
+		assert.NoError(t, err)
+		sort.Strings(members)
+		assert.Equal(t, []string{"session-1", "session-2"}, members)
+
+		time.Sleep(60 * time.Millisecond)
+
+		members, err = store.Members(ctx, "access:user:1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"session-2"}, members)
+
+		assert.NoError(t, store.DeleteKeys(ctx, "access:user:1"))
+		members, err = store.Members(ctx, "access:user:1")
+		assert.NoError(t, err)
+		assert.Empty(t, members)
+	})
+
+	t.Run("should return nil for missing keys", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+
+		value, err := store.Get("missing")
+		assert.NoError(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("should match keys with redis-style glob patterns", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+
+		assert.NoError(t, store.Set("api:response:GET:/v1/users?_GET", []byte("a"), 0))
+		assert.NoError(t, store.Set("api:response:GET:/v1/users/1?_GET", []byte("b"), 0))
+		assert.NoError(t, store.Set("session:user:1", []byte("c"), 0))
+
+		keys, err := store.Keys(ctx, "api:response:*/v1/users*")
+		assert.NoError(t, err)
+		sort.Strings(keys)
+		assert.Equal(t, []string{"api:response:GET:/v1/users/1?_GET", "api:response:GET:/v1/users?_GET"}, keys)
+	})
+
+	t.Run("should delete keys in bulk", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+
+		assert.NoError(t, store.Set("a", []byte("1"), 0))
+		assert.NoError(t, store.Set("b", []byte("2"), 0))
+
+		assert.NoError(t, store.DeleteKeys(ctx, "a", "b"))
+
+		keys, err := store.Keys(ctx, "*")
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+}
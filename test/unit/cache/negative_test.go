@@ -0,0 +1,64 @@
+package cache_test
+
+import (
+	"app/src/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegativeCache(t *testing.T) {
+	ctx := context.Background()
+
+	newCache := func(t *testing.T) (*cache.NegativeCache, *cache.MemoryStore) {
+		store := cache.NewMemoryStore()
+		t.Cleanup(func() { _ = store.Close() })
+		return cache.NewNegativeCache(store, time.Minute), store
+	}
+
+	t.Run("should report a lookup missing once it is marked", func(t *testing.T) {
+		negative, _ := newCache(t)
+
+		assert.False(t, negative.IsMissing(ctx, cache.NegativeKindUserEmail, "ghost@example.com"))
+		negative.MarkMissing(ctx, cache.NegativeKindUserEmail, "ghost@example.com")
+		assert.True(t, negative.IsMissing(ctx, cache.NegativeKindUserEmail, "ghost@example.com"))
+		assert.False(t, negative.IsMissing(ctx, cache.NegativeKindUserID, "ghost@example.com"))
+	})
+
+	t.Run("should not report another casing of a missing email missing", func(t *testing.T) {
+		negative, _ := newCache(t)
+
+		negative.MarkMissing(ctx, cache.NegativeKindUserEmail, "Ghost@Example.com")
+		assert.True(t, negative.IsMissing(ctx, cache.NegativeKindUserEmail, "Ghost@Example.com"))
+		assert.False(t, negative.IsMissing(ctx, cache.NegativeKindUserEmail, "ghost@example.com"))
+	})
+
+	t.Run("should forget a marker whatever the case of the email", func(t *testing.T) {
+		negative, _ := newCache(t)
+
+		negative.MarkMissing(ctx, cache.NegativeKindUserEmail, "ghost@example.com")
+		negative.Forget(ctx, cache.NegativeKindUserEmail, "GHOST@example.com")
+		assert.False(t, negative.IsMissing(ctx, cache.NegativeKindUserEmail, "ghost@example.com"))
+	})
+
+	t.Run("should keep emails out of the keyspace", func(t *testing.T) {
+		negative, store := newCache(t)
+
+		negative.MarkMissing(ctx, cache.NegativeKindUserEmail, "ghost@example.com")
+		keys, err := store.Keys(ctx, cache.NegativeKeyPrefix+"*")
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.NotContains(t, keys[0], "ghost")
+		assert.NotContains(t, keys[0], "example.com")
+	})
+
+	t.Run("should do nothing without a store", func(t *testing.T) {
+		negative := cache.NewNegativeCache(nil, time.Minute)
+
+		negative.MarkMissing(ctx, cache.NegativeKindUserID, "42")
+		assert.False(t, negative.IsMissing(ctx, cache.NegativeKindUserID, "42"))
+		negative.Forget(ctx, cache.NegativeKindUserID, "42")
+	})
+}
@@ -0,0 +1,41 @@
+package cache_test
+
+import (
+	"app/src/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCacheConfig(t *testing.T) {
+	cfg := &config.ResponseCacheConfig{
+		DefaultTTL: 30 * time.Minute,
+		RouteTTLs: map[string]time.Duration{
+			"/v1/users":          5 * time.Minute,
+			"/v1/users/settings": time.Minute,
+			"/v1/notifications/": 10 * time.Second,
+		},
+		BypassRoles: []string{"admin"},
+	}
+
+	t.Run("should use the longest route matching on whole segments", func(t *testing.T) {
+		assert.Equal(t, 5*time.Minute, cfg.TTLFor("/v1/users"))
+		assert.Equal(t, 5*time.Minute, cfg.TTLFor("/v1/users/42"))
+		assert.Equal(t, time.Minute, cfg.TTLFor("/v1/users/settings/theme"))
+		assert.Equal(t, 10*time.Second, cfg.TTLFor("/v1/notifications"))
+		assert.Equal(t, 10*time.Second, cfg.TTLFor("/v1/notifications/7"))
+	})
+
+	t.Run("should fall back to the default TTL for paths only sharing a prefix", func(t *testing.T) {
+		assert.Equal(t, 30*time.Minute, cfg.TTLFor("/v1/users-export"))
+		assert.Equal(t, 30*time.Minute, cfg.TTLFor("/v1/notificationsettings"))
+		assert.Equal(t, 30*time.Minute, cfg.TTLFor("/v1/health-check"))
+	})
+
+	t.Run("should let only the bypass roles skip the cache", func(t *testing.T) {
+		assert.True(t, cfg.CanBypass("admin"))
+		assert.False(t, cfg.CanBypass("user"))
+		assert.False(t, cfg.CanBypass(""))
+	})
+}
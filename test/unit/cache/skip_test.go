@@ -0,0 +1,55 @@
+package cache_test
+
+import (
+	"app/src/cache"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipList(t *testing.T) {
+	headers := func(values map[string]string) func(string) string {
+		return func(name string) string { return values[name] }
+	}
+
+	list, err := cache.NewSkipList([]cache.SkipRule{
+		{Kind: cache.SkipPath, Value: "/v1/admin/"},
+		{Kind: cache.SkipPattern, Value: `^/v1/users/[^/]+/revisions$`},
+		{Kind: cache.SkipMethod, Value: "head"},
+		{Kind: cache.SkipHeader, Value: "X-Preview"},
+		{Kind: cache.SkipHeader, Value: "X-Debug=1"},
+	})
+	assert.NoError(t, err)
+
+	t.Run("should match path prefixes on whole segments", func(t *testing.T) {
+		assert.True(t, list.Skips(http.MethodGet, "/v1/admin", nil))
+		assert.True(t, list.Skips(http.MethodGet, "/v1/admin/cache/stats", nil))
+		assert.False(t, list.Skips(http.MethodGet, "/v1/administrators", nil))
+	})
+
+	t.Run("should match patterns and methods", func(t *testing.T) {
+		assert.True(t, list.Skips(http.MethodGet, "/v1/users/42/revisions", nil))
+		assert.False(t, list.Skips(http.MethodGet, "/v1/users/42", nil))
+		assert.True(t, list.Skips(http.MethodHead, "/v1/users/42", nil))
+	})
+
+	t.Run("should match headers by name or value", func(t *testing.T) {
+		assert.True(t, list.Skips(http.MethodGet, "/v1/users", headers(map[string]string{"X-Preview": "yes"})))
+		assert.True(t, list.Skips(http.MethodGet, "/v1/users", headers(map[string]string{"X-Debug": "1"})))
+		assert.False(t, list.Skips(http.MethodGet, "/v1/users", headers(map[string]string{"X-Debug": "0"})))
+		assert.False(t, list.Skips(http.MethodGet, "/v1/users", nil))
+	})
+
+	t.Run("should skip nothing without a list", func(t *testing.T) {
+		var empty *cache.SkipList
+		assert.False(t, empty.Skips(http.MethodGet, "/v1/admin", nil))
+	})
+
+	t.Run("should reject invalid rules", func(t *testing.T) {
+		assert.Error(t, cache.ValidateSkipRule(cache.SkipRule{Kind: cache.SkipPath, Value: "v1/users"}))
+		assert.Error(t, cache.ValidateSkipRule(cache.SkipRule{Kind: cache.SkipPattern, Value: "^/v1/(users"}))
+		assert.Error(t, cache.ValidateSkipRule(cache.SkipRule{Kind: cache.SkipHeader, Value: "=1"}))
+		assert.Error(t, cache.ValidateSkipRule(cache.SkipRule{Kind: "cookie", Value: "session"}))
+	})
+}
@@ -0,0 +1,45 @@
+package cache_test
+
+import (
+	"app/src/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCacheSwitch(t *testing.T) {
+	ctx := context.Background()
+	defer cache.SetResponseCacheEnabled(true)
+
+	t.Run("should make other instances follow the switch through the store", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		admin := cache.NewResponseCacheSwitch(store)
+		other := cache.NewResponseCacheSwitch(store)
+
+		assert.NoError(t, admin.Set(ctx, false))
+		assert.False(t, cache.IsResponseCacheEnabled())
+
+		// Another instance still caching picks the switch up on its next sync
+		cache.SetResponseCacheEnabled(true)
+		syncCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go other.Start(syncCtx, 5*time.Millisecond)
+		assert.Eventually(t, func() bool { return !cache.IsResponseCacheEnabled() }, time.Second, 5*time.Millisecond)
+
+		assert.NoError(t, admin.Set(ctx, true))
+		cache.SetResponseCacheEnabled(false)
+		assert.Eventually(t, cache.IsResponseCacheEnabled, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("should apply to this instance only without a store", func(t *testing.T) {
+		local := cache.NewResponseCacheSwitch(nil)
+
+		assert.NoError(t, local.Set(ctx, false))
+		assert.False(t, cache.IsResponseCacheEnabled())
+		assert.NoError(t, local.Set(ctx, true))
+		assert.True(t, cache.IsResponseCacheEnabled())
+	})
+}
@@ -0,0 +1,193 @@
+package captcha_test
+
+import (
+	"app/src/cache"
+	"app/src/captcha"
+	"app/src/config"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProvider struct {
+	result *captcha.Result
+	err    error
+	scored bool
+	calls  int
+}
+
+func (p *fakeProvider) Verify(_ context.Context, _, _ string) (*captcha.Result, error) {
+	p.calls++
+	return p.result, p.err
+}
+
+func (p *fakeProvider) Scored() bool {
+	return p.scored
+}
+
+func newApp(handler fiber.Handler, status int) *fiber.App {
+	app := fiber.New()
+	app.Post("/", handler, func(c *fiber.Ctx) error {
+		if status >= fiber.StatusBadRequest {
+			return fiber.NewError(status)
+		}
+		return c.SendStatus(status)
+	})
+	return app
+}
+
+func send(t *testing.T, app *fiber.App, body string, headers ...string) int {
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	res, err := app.Test(req)
+	assert.NoError(t, err)
+	return res.StatusCode
+}
+
+func TestVerifier(t *testing.T) {
+	t.Run("should pass every request when disabled", func(t *testing.T) {
+		verifier := captcha.New(config.CaptchaConfig{Enabled: false})
+		assert.Nil(t, verifier)
+		assert.Nil(t, verifier.Hook("register"))
+
+		app := newApp(verifier.Require("register"), fiber.StatusOK)
+		assert.Equal(t, fiber.StatusOK, send(t, app, `{}`))
+	})
+
+	t.Run("should skip verification in bypass mode", func(t *testing.T) {
+		verifier := captcha.New(config.CaptchaConfig{Enabled: true, Bypass: true})
+
+		app := newApp(verifier.Require("register"), fiber.StatusOK)
+		assert.Equal(t, fiber.StatusOK, send(t, app, `{}`))
+	})
+
+	t.Run("should reject a missing token", func(t *testing.T) {
+		provider := &fakeProvider{result: &captcha.Result{Success: true}}
+		app := newApp(captcha.NewVerifier(provider, 0.5).Require("register"), fiber.StatusOK)
+
+		assert.Equal(t, fiber.StatusForbidden, send(t, app, `{}`))
+		assert.Zero(t, provider.calls)
+	})
+
+	t.Run("should accept a token from the header or body", func(t *testing.T) {
+		provider := &fakeProvider{result: &captcha.Result{Success: true}}
+		app := newApp(captcha.NewVerifier(provider, 0.5).Require("register"), fiber.StatusOK)
+
+		assert.Equal(t, fiber.StatusOK, send(t, app, `{}`, captcha.HeaderToken, "token"))
+		assert.Equal(t, fiber.StatusOK, send(t, app, `{"captcha_token":"token"}`))
+		assert.Equal(t, 2, provider.calls)
+	})
+
+	t.Run("should reject a low reCAPTCHA score", func(t *testing.T) {
+		provider := &fakeProvider{result: &captcha.Result{Success: true, Score: 0.3, Action: "register"}, scored: true}
+		app := newApp(captcha.NewVerifier(provider, 0.5).Require("register"), fiber.StatusOK)
+
+		assert.Equal(t, fiber.StatusForbidden, send(t, app, `{}`, captcha.HeaderToken, "token"))
+	})
+
+	t.Run("should reject a reCAPTCHA token issued for another action", func(t *testing.T) {
+		provider := &fakeProvider{result: &captcha.Result{Success: true, Score: 0.9, Action: "login"}, scored: true}
+		app := newApp(captcha.NewVerifier(provider, 0.5).Require("register"), fiber.StatusOK)
+
+		assert.Equal(t, fiber.StatusForbidden, send(t, app, `{}`, captcha.HeaderToken, "token"))
+	})
+
+	t.Run("should ignore score for unscored providers", func(t *testing.T) {
+		provider := &fakeProvider{result: &captcha.Result{Success: true}}
+		app := newApp(captcha.NewVerifier(provider, 0.9).Require("register"), fiber.StatusOK)
+
+		assert.Equal(t, fiber.StatusOK, send(t, app, `{}`, captcha.HeaderToken, "token"))
+	})
+
+	t.Run("should fail closed when the provider is unreachable", func(t *testing.T) {
+		provider := &fakeProvider{err: errors.New("timeout")}
+		app := newApp(captcha.NewVerifier(provider, 0.5).Require("register"), fiber.StatusOK)
+
+		assert.Equal(t, fiber.StatusServiceUnavailable, send(t, app, `{}`, captcha.HeaderToken, "token"))
+	})
+}
+
+func TestProviders(t *testing.T) {
+	t.Run("should parse reCAPTCHA siteverify responses", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "secret", r.PostForm.Get("secret"))
+			assert.Equal(t, "token", r.PostForm.Get("response"))
+			_, _ = w.Write([]byte(`{"success":true,"score":0.7,"action":"login"}`))
+		}))
+		defer server.Close()
+
+		provider := captcha.NewSiteVerifyProvider(server.URL, "secret", true)
+
+		result, err := provider.Verify(context.Background(), "token", "127.0.0.1")
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, 0.7, result.Score)
+		assert.Equal(t, "login", result.Action)
+		assert.True(t, provider.Scored())
+	})
+
+	t.Run("should treat a non-200 siteverify response as an error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		_, err := captcha.NewSiteVerifyProvider(server.URL, "secret", false).Verify(context.Background(), "token", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("should report reCAPTCHA as scored and hCaptcha as unscored", func(t *testing.T) {
+		assert.True(t, captcha.NewRecaptcha("secret").Scored())
+		assert.False(t, captcha.NewHCaptcha("secret").Scored())
+	})
+}
+
+func TestRequireAfterFailures(t *testing.T) {
+	const body = `{"email":"user@example.com"}`
+
+	email := func(c *fiber.Ctx) string {
+		return strings.ToLower(string(c.Body()))
+	}
+
+	t.Run("should require a CAPTCHA after repeated failures", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+
+		provider := &fakeProvider{result: &captcha.Result{Success: true}}
+		verifier := captcha.NewVerifier(provider, 0.5)
+		app := newApp(verifier.RequireAfterFailures(store, "login", 2, time.Minute, email), fiber.StatusUnauthorized)
+
+		assert.Equal(t, fiber.StatusUnauthorized, send(t, app, body))
+		assert.Equal(t, fiber.StatusUnauthorized, send(t, app, body))
+		assert.Equal(t, fiber.StatusForbidden, send(t, app, body))
+		assert.Equal(t, fiber.StatusUnauthorized, send(t, app, body, captcha.HeaderToken, "token"))
+
+		assert.Equal(t, fiber.StatusUnauthorized, send(t, app, `{"email":"other@example.com"}`))
+	})
+
+	t.Run("should reset the counter after a successful request", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+
+		provider := &fakeProvider{result: &captcha.Result{Success: true}}
+		verifier := captcha.NewVerifier(provider, 0.5)
+		failing := newApp(verifier.RequireAfterFailures(store, "login", 1, time.Minute, email), fiber.StatusUnauthorized)
+		passing := newApp(verifier.RequireAfterFailures(store, "login", 1, time.Minute, email), fiber.StatusOK)
+
+		assert.Equal(t, fiber.StatusUnauthorized, send(t, failing, body))
+		assert.Equal(t, fiber.StatusForbidden, send(t, passing, body))
+		assert.Equal(t, fiber.StatusOK, send(t, passing, body, captcha.HeaderToken, "token"))
+		assert.Equal(t, fiber.StatusOK, send(t, passing, body))
+	})
+}
@@ -0,0 +1,70 @@
+package chaos_test
+
+import (
+	"app/src/chaos"
+	"app/src/config"
+	"app/src/middleware"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInject(t *testing.T) {
+	t.Cleanup(chaos.Reset)
+
+	t.Run("should pass calls to dependencies without faults", func(t *testing.T) {
+		chaos.Reset()
+		assert.Equal(t, chaos.Pass, chaos.Inject(context.Background(), config.ChaosRedis))
+	})
+
+	t.Run("should fail or drop calls at a 100% rate", func(t *testing.T) {
+		chaos.Reset()
+		chaos.Set(config.ChaosRedis, config.ChaosFault{ErrorPercent: 100})
+		chaos.Set(config.ChaosDB, config.ChaosFault{DropPercent: 100})
+
+		assert.ErrorIs(t, chaos.Inject(context.Background(), config.ChaosRedis).Err(), chaos.ErrInjected)
+		assert.ErrorIs(t, chaos.Inject(context.Background(), config.ChaosDB).Err(), chaos.ErrDropped)
+		assert.Equal(t, chaos.Pass, chaos.Inject(context.Background(), config.ChaosHTTP))
+	})
+
+	t.Run("should add latency but stop waiting when the context is done", func(t *testing.T) {
+		chaos.Reset()
+		chaos.Set(config.ChaosDB, config.ChaosFault{Latency: 50 * time.Millisecond, LatencyPercent: 100})
+
+		start := time.Now()
+		assert.Equal(t, chaos.Pass, chaos.Inject(context.Background(), config.ChaosDB))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+		chaos.Set(config.ChaosDB, config.ChaosFault{Latency: time.Minute, LatencyPercent: 100})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start = time.Now()
+		chaos.Inject(ctx, config.ChaosDB)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("should answer requests with 503 at a 100% http error rate", func(t *testing.T) {
+		chaos.Reset()
+		chaos.Set(config.ChaosHTTP, config.ChaosFault{ErrorPercent: 100})
+
+		app := fiber.New()
+		app.Use(middleware.Chaos())
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusServiceUnavailable, res.StatusCode)
+
+		chaos.Reset()
+		res, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	})
+}
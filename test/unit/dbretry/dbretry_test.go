@@ -0,0 +1,101 @@
+package dbretry_test
+
+import (
+	"app/src/config"
+	"app/src/dbretry"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestClassify(t *testing.T) {
+	t.Run("should classify transient errors", func(t *testing.T) {
+		assert.Equal(t, dbretry.Serialization, dbretry.Classify(&pgconn.PgError{Code: "40001"}))
+		assert.Equal(t, dbretry.Serialization, dbretry.Classify(&pgconn.PgError{Code: "40P01"}))
+		assert.Equal(t, dbretry.Failover, dbretry.Classify(&pgconn.PgError{Code: "57P01"}))
+		assert.Equal(t, dbretry.Failover, dbretry.Classify(fmt.Errorf("update: %w", &pgconn.PgError{Code: "25006"})))
+		assert.Equal(t, dbretry.Connection, dbretry.Classify(&pgconn.PgError{Code: "08006"}))
+		assert.Equal(t, dbretry.Connection, dbretry.Classify(driver.ErrBadConn))
+		assert.Equal(t, dbretry.Connection, dbretry.Classify(fmt.Errorf("read: %w", syscall.ECONNRESET)))
+	})
+
+	t.Run("should classify other errors as permanent", func(t *testing.T) {
+		assert.Equal(t, dbretry.Permanent, dbretry.Classify(nil))
+		assert.Equal(t, dbretry.Permanent, dbretry.Classify(gorm.ErrRecordNotFound))
+		assert.Equal(t, dbretry.Permanent, dbretry.Classify(&pgconn.PgError{Code: "23505"}))
+		assert.Equal(t, dbretry.Permanent, dbretry.Classify(context.DeadlineExceeded))
+		assert.False(t, dbretry.Permanent.Transient())
+	})
+}
+
+func TestRetry(t *testing.T) {
+	saved := config.DatabaseRetry
+	t.Cleanup(func() { config.DatabaseRetry = saved })
+	config.DatabaseRetry = config.DatabaseRetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	// Never connects: the operations below fail on their own
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{DisableAutomaticPing: true})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	failing := func(calls *int, failures int, err error) func(*gorm.DB) error {
+		return func(*gorm.DB) error {
+			*calls++
+			if *calls <= failures {
+				return err
+			}
+			return nil
+		}
+	}
+
+	t.Run("should retry reads after transient errors", func(t *testing.T) {
+		calls := 0
+		err := dbretry.Read(ctx, db, failing(&calls, 2, driver.ErrBadConn))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("should give up after the last retry", func(t *testing.T) {
+		calls := 0
+		err := dbretry.Read(ctx, db, failing(&calls, 5, &pgconn.PgError{Code: "40001"}))
+		assert.Error(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("should not retry permanent errors", func(t *testing.T) {
+		calls := 0
+		err := dbretry.Read(ctx, db, failing(&calls, 1, gorm.ErrRecordNotFound))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should only retry writes declared idempotent", func(t *testing.T) {
+		calls := 0
+		err := dbretry.Write(ctx, db, failing(&calls, 1, driver.ErrBadConn))
+		assert.ErrorIs(t, err, driver.ErrBadConn)
+		assert.Equal(t, 1, calls)
+
+		calls = 0
+		err = dbretry.Write(ctx, db, failing(&calls, 1, driver.ErrBadConn), dbretry.Idempotent())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should stop retrying once the context is done", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		calls := 0
+		err := dbretry.Read(cancelled, db, failing(&calls, 5, driver.ErrBadConn), dbretry.MaxRetries(10))
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
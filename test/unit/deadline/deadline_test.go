@@ -0,0 +1,76 @@
+package deadline_test
+
+import (
+	"app/src/config"
+	"app/src/deadline"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFor(t *testing.T) {
+	saved := config.Deadlines
+	t.Cleanup(func() { config.Deadlines = saved })
+
+	config.Deadlines = config.DeadlineConfig{
+		Margin: 100 * time.Millisecond,
+		Limits: map[string]time.Duration{config.DeadlineDB: 5 * time.Second, config.DeadlineSMTP: 0},
+	}
+
+	t.Run("should end at the dependency limit without a request deadline", func(t *testing.T) {
+		start := time.Now()
+		ctx, cancel := deadline.For(context.Background(), config.DeadlineDB)
+		defer cancel()
+
+		end, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, start.Add(5*time.Second), end, 50*time.Millisecond)
+	})
+
+	t.Run("should end the margin before an earlier request deadline", func(t *testing.T) {
+		request, cancelRequest := context.WithTimeout(context.Background(), time.Second)
+		defer cancelRequest()
+		requestEnd, _ := request.Deadline()
+
+		ctx, cancel := deadline.For(request, config.DeadlineDB)
+		defer cancel()
+
+		end, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, requestEnd.Add(-100*time.Millisecond), end)
+	})
+
+	t.Run("should keep the dependency limit before a later request deadline", func(t *testing.T) {
+		request, cancelRequest := context.WithTimeout(context.Background(), time.Minute)
+		defer cancelRequest()
+
+		start := time.Now()
+		ctx, cancel := deadline.For(request, config.DeadlineDB)
+		defer cancel()
+
+		end, _ := ctx.Deadline()
+		assert.WithinDuration(t, start.Add(5*time.Second), end, 50*time.Millisecond)
+	})
+
+	t.Run("should leave a call without limit or request deadline unbounded", func(t *testing.T) {
+		ctx, cancel := deadline.For(context.Background(), config.DeadlineSMTP)
+
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+
+		cancel()
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("should be done at once when the request has less time left than the margin", func(t *testing.T) {
+		request, cancelRequest := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancelRequest()
+
+		ctx, cancel := deadline.For(request, config.DeadlineDB)
+		defer cancel()
+
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+}
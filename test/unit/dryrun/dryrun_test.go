@@ -0,0 +1,61 @@
+package dryrun_test
+
+import (
+	"app/src/dryrun"
+	"app/src/response"
+	"app/src/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Delete("/things", func(c *fiber.Ctx) error {
+		if dryrun.Requested(c) {
+			sample := make([]string, 25)
+			for i := range sample {
+				sample[i] = fmt.Sprintf("thing-%d", i)
+			}
+			return dryrun.Stop(response.DryRun{
+				Action: "things.delete", Affected: map[string]int64{"things": 25}, Sample: sample,
+			})
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	t.Run("should answer a dry run with its report and a truncated sample", func(t *testing.T) {
+		apiResponse, err := app.Test(httptest.NewRequest(http.MethodDelete, "/things?dryRun=true", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, apiResponse.StatusCode)
+
+		bytes, err := io.ReadAll(apiResponse.Body)
+		assert.Nil(t, err)
+
+		responseBody := new(response.SuccessWithDryRun)
+		assert.Nil(t, json.Unmarshal(bytes, responseBody))
+		assert.Equal(t, "things.delete", responseBody.DryRun.Action)
+		assert.Equal(t, int64(25), responseBody.DryRun.Affected["things"])
+		assert.Len(t, responseBody.DryRun.Sample, dryrun.SampleSize)
+		assert.Equal(t, "thing-0", responseBody.DryRun.Sample[0])
+	})
+
+	t.Run("should act without the flag", func(t *testing.T) {
+		apiResponse, err := app.Test(httptest.NewRequest(http.MethodDelete, "/things", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNoContent, apiResponse.StatusCode)
+	})
+
+	t.Run("should list an empty sample rather than null", func(t *testing.T) {
+		var preview *dryrun.Preview
+		assert.ErrorAs(t, dryrun.Stop(response.DryRun{Action: "noop"}), &preview)
+		assert.NotNil(t, preview.Report.Sample)
+		assert.Empty(t, preview.Report.Sample)
+	})
+}
@@ -0,0 +1,88 @@
+package emaildomain_test
+
+import (
+	"app/src/emaildomain"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomain(t *testing.T) {
+	t.Run("should return the lowercased domain", func(t *testing.T) {
+		assert.Equal(t, "example.com", emaildomain.Domain("Jane@Example.COM"))
+		assert.Equal(t, "mail.example.com", emaildomain.Domain(`"a@b"@mail.example.com.`))
+	})
+
+	t.Run("should return empty for an address without a domain", func(t *testing.T) {
+		assert.Equal(t, "", emaildomain.Domain("jane"))
+	})
+}
+
+func TestMatches(t *testing.T) {
+	t.Run("should match an exact domain only", func(t *testing.T) {
+		assert.True(t, emaildomain.Matches("example.com", "example.com"))
+		assert.False(t, emaildomain.Matches("example.com", "mail.example.com"))
+		assert.False(t, emaildomain.Matches("example.com", "notexample.com"))
+	})
+
+	t.Run("should match subdomains of a wildcard but not the domain itself", func(t *testing.T) {
+		assert.True(t, emaildomain.Matches("*.example.com", "mail.example.com"))
+		assert.True(t, emaildomain.Matches("*.example.com", "a.b.example.com"))
+		assert.False(t, emaildomain.Matches("*.example.com", "example.com"))
+		assert.False(t, emaildomain.Matches("*.example.com", "badexample.com"))
+	})
+
+	t.Run("should match any of several patterns", func(t *testing.T) {
+		patterns := []string{"example.com", "*.example.org"}
+
+		assert.True(t, emaildomain.MatchesAny(patterns, "mail.example.org"))
+		assert.False(t, emaildomain.MatchesAny(patterns, "example.org"))
+		assert.False(t, emaildomain.MatchesAny(nil, "example.com"))
+	})
+}
+
+func TestDisposableList(t *testing.T) {
+	t.Run("should flag listed domains and their subdomains", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("# disposable\nMailinator.com\n\n10minutemail.com\n"))
+		}))
+		defer server.Close()
+
+		list := emaildomain.NewDisposableList(server.URL)
+		assert.NoError(t, list.Refresh(context.Background()))
+		assert.Equal(t, 2, list.Len())
+
+		assert.True(t, list.Contains("mailinator.com"))
+		assert.True(t, list.Contains("eu.mailinator.com"))
+		assert.False(t, list.Contains("example.com"))
+	})
+
+	t.Run("should keep the old list when a refresh fails", func(t *testing.T) {
+		healthy := true
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if !healthy {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("mailinator.com\n"))
+		}))
+		defer server.Close()
+
+		list := emaildomain.NewDisposableList(server.URL)
+		assert.NoError(t, list.Refresh(context.Background()))
+
+		healthy = false
+		assert.Error(t, list.Refresh(context.Background()))
+		assert.True(t, list.Contains("mailinator.com"))
+	})
+
+	t.Run("should treat a missing list as empty", func(t *testing.T) {
+		var list *emaildomain.DisposableList
+
+		assert.False(t, list.Contains("mailinator.com"))
+		assert.Equal(t, 0, list.Len())
+	})
+}
@@ -0,0 +1,104 @@
+package emailfeedback_test
+
+import (
+	"app/src/emailfeedback"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSES(t *testing.T) {
+	// envelope wraps an SES notification in an SNS message, as SES delivers it by default
+	envelope := func(t *testing.T, notification string) []byte {
+		payload, err := json.Marshal(map[string]string{"Type": "Notification", "Message": notification})
+		assert.NoError(t, err)
+		return payload
+	}
+
+	t.Run("should report the recipients of a permanent bounce", func(t *testing.T) {
+		feedback, err := emailfeedback.Parse(emailfeedback.ProviderSES, envelope(t, `{
+			"notificationType": "Bounce",
+			"bounce": {
+				"bounceType": "Permanent",
+				"bounceSubType": "General",
+				"bouncedRecipients": [
+					{"emailAddress": "Gone@Example.com", "diagnosticCode": "smtp; 550 5.1.1 user unknown"},
+					{"emailAddress": "missing@example.com"}
+				]
+			}
+		}`))
+		assert.NoError(t, err)
+		assert.Equal(t, []emailfeedback.Event{
+			{Email: "gone@example.com", Reason: emailfeedback.ReasonHardBounce, Detail: "smtp; 550 5.1.1 user unknown"},
+			{Email: "missing@example.com", Reason: emailfeedback.ReasonHardBounce, Detail: "General"},
+		}, feedback.Events)
+	})
+
+	t.Run("should ignore transient bounces", func(t *testing.T) {
+		feedback, err := emailfeedback.Parse(emailfeedback.ProviderSES, envelope(t, `{
+			"notificationType": "Bounce",
+			"bounce": {"bounceType": "Transient", "bouncedRecipients": [{"emailAddress": "full@example.com"}]}
+		}`))
+		assert.NoError(t, err)
+		assert.Empty(t, feedback.Events)
+	})
+
+	t.Run("should report complaints from event publishing without an envelope", func(t *testing.T) {
+		feedback, err := emailfeedback.Parse(emailfeedback.ProviderSES, []byte(`{
+			"eventType": "Complaint",
+			"complaint": {"complaintFeedbackType": "abuse", "complainedRecipients": [{"emailAddress": "angry@example.com"}]}
+		}`))
+		assert.NoError(t, err)
+		assert.Equal(t, []emailfeedback.Event{
+			{Email: "angry@example.com", Reason: emailfeedback.ReasonComplaint, Detail: "abuse"},
+		}, feedback.Events)
+	})
+
+	t.Run("should return the URL confirming a subscription", func(t *testing.T) {
+		feedback, err := emailfeedback.Parse(emailfeedback.ProviderSES, []byte(`{
+			"Type": "SubscriptionConfirmation",
+			"SubscribeURL": "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=abc"
+		}`))
+		assert.NoError(t, err)
+		assert.Equal(t, "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=abc", feedback.SubscribeURL)
+		assert.Empty(t, feedback.Events)
+	})
+
+	t.Run("should reject malformed payloads", func(t *testing.T) {
+		_, err := emailfeedback.Parse(emailfeedback.ProviderSES, []byte(`not json`))
+		assert.ErrorIs(t, err, emailfeedback.ErrInvalidPayload)
+
+		_, err = emailfeedback.Parse(emailfeedback.ProviderSES, envelope(t, `not json`))
+		assert.ErrorIs(t, err, emailfeedback.ErrInvalidPayload)
+	})
+}
+
+func TestParseSendGrid(t *testing.T) {
+	t.Run("should report bounces, spam reports and unsubscribes", func(t *testing.T) {
+		feedback, err := emailfeedback.Parse(emailfeedback.ProviderSendGrid, []byte(`[
+			{"email": "gone@example.com", "event": "bounce", "type": "bounce", "reason": "550 5.1.1 user unknown"},
+			{"email": "busy@example.com", "event": "bounce", "type": "blocked", "reason": "421 try again later"},
+			{"email": "angry@example.com", "event": "spamreport"},
+			{"email": "done@example.com", "event": "group_unsubscribe"},
+			{"email": "happy@example.com", "event": "delivered"},
+			{"email": "", "event": "unsubscribe"}
+		]`))
+		assert.NoError(t, err)
+		assert.Equal(t, []emailfeedback.Event{
+			{Email: "gone@example.com", Reason: emailfeedback.ReasonHardBounce, Detail: "550 5.1.1 user unknown"},
+			{Email: "angry@example.com", Reason: emailfeedback.ReasonComplaint, Detail: "spamreport"},
+			{Email: "done@example.com", Reason: emailfeedback.ReasonUnsubscribe, Detail: "group_unsubscribe"},
+		}, feedback.Events)
+	})
+
+	t.Run("should reject payloads that are not a batch", func(t *testing.T) {
+		_, err := emailfeedback.Parse(emailfeedback.ProviderSendGrid, []byte(`{"email": "gone@example.com"}`))
+		assert.ErrorIs(t, err, emailfeedback.ErrInvalidPayload)
+	})
+}
+
+func TestParseUnknownProvider(t *testing.T) {
+	_, err := emailfeedback.Parse("mailgun", []byte(`{}`))
+	assert.ErrorIs(t, err, emailfeedback.ErrUnknownProvider)
+}
@@ -0,0 +1,192 @@
+package encryption_test
+
+import (
+	"app/src/config"
+	"app/src/encryption"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	oldKey = bytes.Repeat([]byte{1}, 32)
+	newKey = bytes.Repeat([]byte{2}, 32)
+)
+
+func TestKeyring(t *testing.T) {
+	t.Run("should round-trip a value", func(t *testing.T) {
+		keyring, err := encryption.NewKeyring(map[string][]byte{"k1": oldKey}, "k1")
+		assert.NoError(t, err)
+
+		sealed, err := keyring.Encrypt([]byte("+6281234567890"))
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(sealed, encryption.Prefix+"k1:"))
+		assert.NotContains(t, sealed, "6281234567890")
+
+		plaintext, err := keyring.Decrypt(sealed)
+		assert.NoError(t, err)
+		assert.Equal(t, "+6281234567890", string(plaintext))
+	})
+
+	t.Run("should use a fresh nonce for every encryption", func(t *testing.T) {
+		keyring, _ := encryption.NewKeyring(map[string][]byte{"k1": oldKey}, "k1")
+
+		first, _ := keyring.Encrypt([]byte("secret"))
+		second, _ := keyring.Encrypt([]byte("secret"))
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("should decrypt values sealed with a rotated-out key", func(t *testing.T) {
+		before, _ := encryption.NewKeyring(map[string][]byte{"k1": oldKey}, "k1")
+		sealed, _ := before.Encrypt([]byte("secret"))
+
+		after, err := encryption.NewKeyring(map[string][]byte{"k1": oldKey, "k2": newKey}, "k2")
+		assert.NoError(t, err)
+		assert.True(t, after.NeedsRotation(sealed))
+
+		plaintext, err := after.Decrypt(sealed)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", string(plaintext))
+
+		resealed, _ := after.Encrypt(plaintext)
+		assert.False(t, after.NeedsRotation(resealed))
+	})
+
+	t.Run("should pass through plaintext written before encryption was enabled", func(t *testing.T) {
+		keyring, _ := encryption.NewKeyring(map[string][]byte{"k1": oldKey}, "k1")
+
+		plaintext, err := keyring.Decrypt("legacy value")
+		assert.NoError(t, err)
+		assert.Equal(t, "legacy value", string(plaintext))
+		assert.True(t, keyring.NeedsRotation("legacy value"))
+	})
+
+	t.Run("should reject unknown keys and tampered values", func(t *testing.T) {
+		keyring, _ := encryption.NewKeyring(map[string][]byte{"k1": oldKey}, "k1")
+		sealed, _ := keyring.Encrypt([]byte("secret"))
+
+		_, err := keyring.Decrypt(strings.Replace(sealed, ":k1:", ":k9:", 1))
+		assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+
+		tampered := sealed[:len(sealed)-2] + "AA"
+		if tampered == sealed {
+			tampered = sealed[:len(sealed)-2] + "BB"
+		}
+		_, err = keyring.Decrypt(tampered)
+		assert.ErrorIs(t, err, encryption.ErrMalformed)
+	})
+
+	t.Run("should reject invalid key configuration", func(t *testing.T) {
+		_, err := encryption.NewKeyring(map[string][]byte{"k1": oldKey}, "k2")
+		assert.Error(t, err)
+
+		_, err = encryption.NewKeyring(map[string][]byte{"k1": []byte("short")}, "k1")
+		assert.Error(t, err)
+	})
+
+	t.Run("should refuse to encrypt without keys", func(t *testing.T) {
+		var keyring *encryption.Keyring
+
+		_, err := keyring.Encrypt([]byte("secret"))
+		assert.ErrorIs(t, err, encryption.ErrNoKeys)
+	})
+}
+
+type fakeKMS struct{}
+
+func (fakeKMS) Decrypt(_ context.Context, wrapped []byte) ([]byte, error) {
+	if !bytes.HasPrefix(wrapped, []byte("wrapped:")) {
+		return nil, errors.New("not wrapped by this KMS")
+	}
+	return bytes.TrimPrefix(wrapped, []byte("wrapped:")), nil
+}
+
+func TestNewFromConfig(t *testing.T) {
+	defer func() {
+		config.EncryptionKeys = nil
+		config.EncryptionKMS = false
+	}()
+
+	t.Run("should return no keyring when no keys are configured", func(t *testing.T) {
+		config.EncryptionKeys = nil
+
+		keyring, err := encryption.NewFromConfig(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Nil(t, keyring)
+	})
+
+	t.Run("should use the first configured key as primary", func(t *testing.T) {
+		config.EncryptionKeys = []config.EncryptionKey{
+			{ID: "2026", Key: base64.StdEncoding.EncodeToString(newKey)},
+			{ID: "2025", Key: base64.StdEncoding.EncodeToString(oldKey)},
+		}
+
+		keyring, err := encryption.NewFromConfig(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "2026", keyring.Primary())
+	})
+
+	t.Run("should unwrap keys with the KMS client", func(t *testing.T) {
+		config.EncryptionKMS = true
+		config.EncryptionKeys = []config.EncryptionKey{
+			{ID: "kms", Key: base64.StdEncoding.EncodeToString(append([]byte("wrapped:"), newKey...))},
+		}
+
+		_, err := encryption.NewFromConfig(context.Background(), nil)
+		assert.Error(t, err)
+
+		keyring, err := encryption.NewFromConfig(context.Background(), fakeKMS{})
+		assert.NoError(t, err)
+		assert.Equal(t, "kms", keyring.Primary())
+	})
+}
+
+type contact struct {
+	ID    string
+	Phone string  `gorm:"serializer:encrypted"`
+	Note  *string `gorm:"serializer:encrypted"`
+}
+
+func TestSerializer(t *testing.T) {
+	keyring, _ := encryption.NewKeyring(map[string][]byte{"k1": oldKey}, "k1")
+	encryption.Use(keyring)
+	defer encryption.Use(nil)
+
+	s, err := schema.Parse(&contact{}, &sync.Map{}, schema.NamingStrategy{})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	serializer := encryption.Serializer{}
+
+	t.Run("should encrypt on write and decrypt on read", func(t *testing.T) {
+		field := s.LookUpField("Phone")
+
+		stored, err := serializer.Value(ctx, field, reflect.Value{}, "+6281234567890")
+		assert.NoError(t, err)
+		assert.True(t, encryption.IsEncrypted(stored.(string)))
+
+		var loaded contact
+		assert.NoError(t, serializer.Scan(ctx, field, reflect.ValueOf(&loaded), stored))
+		assert.Equal(t, "+6281234567890", loaded.Phone)
+	})
+
+	t.Run("should keep NULL for nil pointers", func(t *testing.T) {
+		field := s.LookUpField("Note")
+
+		stored, err := serializer.Value(ctx, field, reflect.Value{}, (*string)(nil))
+		assert.NoError(t, err)
+		assert.Nil(t, stored)
+
+		var loaded contact
+		assert.NoError(t, serializer.Scan(ctx, field, reflect.ValueOf(&loaded), nil))
+		assert.Nil(t, loaded.Note)
+	})
+}
@@ -0,0 +1,89 @@
+package httpclient_test
+
+import (
+	"app/src/httpclient"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	// server fails the first failures requests with status, then answers 200
+	server := func(failures int32, status int) (*httptest.Server, *atomic.Int32) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) <= failures {
+				w.WriteHeader(status)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &calls
+	}
+
+	newClient := func(opts ...httpclient.Option) *http.Client {
+		return httpclient.New("test", append([]httpclient.Option{
+			httpclient.WithTimeout(5 * time.Second),
+			httpclient.WithRetryDelay(time.Millisecond, 5*time.Millisecond),
+			httpclient.WithBreaker(100, time.Minute),
+		}, opts...)...)
+	}
+
+	t.Run("should retry idempotent requests on server errors", func(t *testing.T) {
+		srv, calls := server(2, http.StatusServiceUnavailable)
+		client := newClient(httpclient.WithRetries(2))
+
+		resp, err := client.Get(srv.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("should return the last response once retries are exhausted", func(t *testing.T) {
+		srv, calls := server(10, http.StatusBadGateway)
+		client := newClient(httpclient.WithRetries(1))
+
+		resp, err := client.Get(srv.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should not retry POSTs or client errors", func(t *testing.T) {
+		srv, calls := server(1, http.StatusServiceUnavailable)
+		client := newClient(httpclient.WithRetries(3))
+
+		resp, err := client.Post(srv.URL, "application/x-www-form-urlencoded", strings.NewReader("code=abc"))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+
+		srv, calls = server(1, http.StatusNotFound)
+		resp, err = client.Get(srv.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should open the host's circuit after consecutive failures", func(t *testing.T) {
+		srv, calls := server(100, http.StatusInternalServerError)
+		client := newClient(httpclient.WithRetries(0), httpclient.WithBreaker(2, time.Minute))
+
+		for range 2 {
+			resp, err := client.Get(srv.URL)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		}
+
+		_, err := client.Get(srv.URL)
+		assert.True(t, errors.Is(err, httpclient.ErrCircuitOpen))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
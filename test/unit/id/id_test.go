@@ -0,0 +1,72 @@
+package id_test
+
+import (
+	"app/src/utils/id"
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Cleanup(func() { _ = id.Configure(id.FormatUUIDv7) })
+
+	t.Run("should generate time-ordered UUIDv7 by default", func(t *testing.T) {
+		previous := id.New()
+		assert.Equal(t, uuid.Version(7), previous.Version())
+
+		for i := 0; i < 1000; i++ {
+			next := id.New()
+			assert.Equal(t, -1, bytes.Compare(previous[:], next[:]))
+			previous = next
+		}
+	})
+
+	t.Run("should generate ULIDs that stay ordered within a millisecond", func(t *testing.T) {
+		assert.NoError(t, id.Configure(id.FormatULID))
+		t.Cleanup(func() { _ = id.Configure(id.FormatUUIDv7) })
+
+		previous := id.New()
+		for i := 0; i < 1000; i++ {
+			next := id.New()
+			assert.Equal(t, -1, bytes.Compare(previous[:], next[:]))
+			previous = next
+		}
+	})
+
+	t.Run("should reject an unknown format", func(t *testing.T) {
+		assert.Error(t, id.Configure("uuidv4"))
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("should parse UUIDs, including random ones created before time-ordered IDs", func(t *testing.T) {
+		random := uuid.New()
+
+		parsed, err := id.Parse(random.String())
+		assert.NoError(t, err)
+		assert.Equal(t, random, parsed)
+	})
+
+	t.Run("should parse the ULID text form of an ID, ignoring case", func(t *testing.T) {
+		value := id.New()
+		text := id.ULID(value)
+		assert.Len(t, text, 26)
+
+		parsed, err := id.Parse(text)
+		assert.NoError(t, err)
+		assert.Equal(t, value, parsed)
+
+		parsed, err = id.Parse("01arz3ndektsv4rrffq69g5fav")
+		assert.NoError(t, err)
+		assert.Equal(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV", id.ULID(parsed))
+	})
+
+	t.Run("should reject malformed IDs", func(t *testing.T) {
+		for _, value := range []string{"", "not-an-id", "81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAU"} {
+			_, err := id.Parse(value)
+			assert.Error(t, err, value)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package job_test
+
+import (
+	"app/src/job"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextWeeklyRun(t *testing.T) {
+	// 2026-10-14 is a Wednesday
+	wednesday := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+
+	t.Run("should schedule later the same week", func(t *testing.T) {
+		next := job.NextWeeklyRun(wednesday, time.Friday, 8)
+		assert.Equal(t, time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("should roll over to next week once the day has passed", func(t *testing.T) {
+		next := job.NextWeeklyRun(wednesday, time.Monday, 8)
+		assert.Equal(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("should wait a full week when the hour has passed today", func(t *testing.T) {
+		assert.Equal(t, time.Date(2026, 10, 21, 8, 0, 0, 0, time.UTC), job.NextWeeklyRun(wednesday, time.Wednesday, 8))
+		assert.Equal(t, time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC), job.NextWeeklyRun(wednesday, time.Wednesday, 11))
+	})
+
+	t.Run("should not fire twice at the scheduled instant", func(t *testing.T) {
+		scheduled := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+		assert.Equal(t, scheduled.AddDate(0, 0, 7), job.NextWeeklyRun(scheduled, time.Monday, 8))
+	})
+
+	t.Run("should compute in UTC regardless of the caller's zone", func(t *testing.T) {
+		jakarta := time.FixedZone("WIB", 7*60*60)
+		// 2026-10-19 02:00 WIB is still Sunday 19:00 UTC
+		next := job.NextWeeklyRun(time.Date(2026, 10, 19, 2, 0, 0, 0, jakarta), time.Monday, 8)
+		assert.Equal(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), next)
+	})
+}
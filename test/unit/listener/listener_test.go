@@ -0,0 +1,136 @@
+package listener_test
+
+import (
+	"app/src/config"
+	"app/src/listener"
+	"app/src/middleware"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadListenerConfig(t *testing.T) {
+	listeners := config.Listeners
+	t.Cleanup(func() {
+		viper.Set("LISTENERS", "")
+		config.Listeners = listeners
+	})
+
+	t.Run("should parse named tcp, unix and systemd listeners", func(t *testing.T) {
+		viper.Set("LISTENERS", "public=0.0.0.0:3000, admin=tcp://127.0.0.1:3001,sidecar=unix:///run/app.sock,web=systemd://http")
+		config.LoadListenerConfig()
+
+		assert.Equal(t, []config.ListenerConfig{
+			{Name: "public", Network: config.ListenerTCP, Address: "0.0.0.0:3000"},
+			{Name: "admin", Network: config.ListenerTCP, Address: "127.0.0.1:3001"},
+			{Name: "sidecar", Network: config.ListenerUnix, Address: "/run/app.sock"},
+			{Name: "web", Network: config.ListenerSystemd, Address: "http"},
+		}, config.Listeners)
+	})
+
+	t.Run("should default to a single public listener", func(t *testing.T) {
+		viper.Set("LISTENERS", "")
+		config.LoadListenerConfig()
+
+		assert.Len(t, config.Listeners, 1)
+		assert.Equal(t, config.DefaultListener, config.Listeners[0].Name)
+		assert.Equal(t, config.ListenerTCP, config.Listeners[0].Network)
+	})
+}
+
+func TestOpen(t *testing.T) {
+	listeners := config.Listeners
+	config.Listeners = []config.ListenerConfig{{Name: "public", Network: config.ListenerTCP, Address: "127.0.0.1:0"}}
+	t.Cleanup(func() { config.Listeners = listeners })
+
+	app := fiber.New()
+	app.Get("/whoami", func(c *fiber.Ctx) error {
+		return c.SendString(listener.Name(c.Context().Conn()))
+	})
+	app.Get("/internal", middleware.OnListener("sidecar"), func(c *fiber.Ctx) error {
+		return c.SendString("internal")
+	})
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	public, err := listener.Open(config.ListenerConfig{Name: "public", Network: config.ListenerTCP, Address: "127.0.0.1:0"})
+	assert.NoError(t, err)
+	go func() { _ = app.Listener(public) }()
+
+	socket := filepath.Join(t.TempDir(), "app.sock")
+	sidecar, err := listener.Open(config.ListenerConfig{Name: "sidecar", Network: config.ListenerUnix, Address: socket})
+	assert.NoError(t, err)
+	go func() { _ = app.Listener(sidecar) }()
+
+	tcpClient := http.DefaultClient
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, "unix", socket)
+		},
+	}}
+
+	get := func(client *http.Client, url string) (int, string) {
+		res, err := client.Get(url)
+		if !assert.NoError(t, err) {
+			return 0, ""
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return res.StatusCode, string(body)
+	}
+
+	t.Run("should name the listener that accepted the connection", func(t *testing.T) {
+		_, name := get(tcpClient, "http://"+public.Addr().String()+"/whoami")
+		assert.Equal(t, "public", name)
+
+		_, name = get(unixClient, "http://unix/whoami")
+		assert.Equal(t, "sidecar", name)
+	})
+
+	t.Run("should hide routes limited to other listeners", func(t *testing.T) {
+		status, _ := get(tcpClient, "http://"+public.Addr().String()+"/internal")
+		assert.Equal(t, fiber.StatusNotFound, status)
+
+		status, body := get(unixClient, "http://unix/internal")
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, "internal", body)
+	})
+
+	t.Run("should create the socket with the configured mode", func(t *testing.T) {
+		info, err := os.Stat(socket)
+		assert.NoError(t, err)
+		assert.Equal(t, config.UnixSocketMode, info.Mode().Perm())
+	})
+}
+
+func TestCertificateIdentities(t *testing.T) {
+	uri, err := url.Parse("spiffe://example.com/billing")
+	assert.NoError(t, err)
+
+	t.Run("should list SANs before the common name", func(t *testing.T) {
+		cert := &x509.Certificate{
+			Subject:        pkix.Name{CommonName: "billing"},
+			URIs:           []*url.URL{uri},
+			DNSNames:       []string{"billing.internal"},
+			EmailAddresses: []string{"billing@example.com"},
+		}
+
+		assert.Equal(t, []string{
+			"spiffe://example.com/billing", "billing.internal", "billing@example.com", "billing",
+		}, listener.CertificateIdentities(cert))
+	})
+
+	t.Run("should return nothing for a certificate without names", func(t *testing.T) {
+		assert.Empty(t, listener.CertificateIdentities(&x509.Certificate{}))
+	})
+}
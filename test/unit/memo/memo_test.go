@@ -0,0 +1,91 @@
+package memo_test
+
+import (
+	"app/src/memo"
+	"app/src/middleware"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	counting := func(calls *int, value string, err error) func() (string, error) {
+		return func() (string, error) {
+			*calls++
+			return value, err
+		}
+	}
+
+	t.Run("should load once per key within a request", func(t *testing.T) {
+		ctx, done := memo.WithCache(context.Background())
+		defer done()
+
+		calls := 0
+		for range 3 {
+			value, err := memo.Load(ctx, "user", "1", counting(&calls, "one", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, "one", value)
+		}
+		_, _ = memo.Load(ctx, "user", "2", counting(&calls, "two", nil))
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should not memoize errors", func(t *testing.T) {
+		ctx, done := memo.WithCache(context.Background())
+		defer done()
+
+		calls := 0
+		_, err := memo.Load(ctx, "user", "1", counting(&calls, "", errors.New("boom")))
+		assert.Error(t, err)
+		_, err = memo.Load(ctx, "user", "1", counting(&calls, "one", nil))
+		assert.NoError(t, err)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should load again after a flush, outside a request and once it ended", func(t *testing.T) {
+		ctx, done := memo.WithCache(context.Background())
+
+		calls := 0
+		_, _ = memo.Load(ctx, "user", "1", counting(&calls, "one", nil))
+		memo.Flush(ctx)
+		_, _ = memo.Load(ctx, "user", "1", counting(&calls, "one", nil))
+		assert.Equal(t, 2, calls)
+
+		done()
+		_, _ = memo.Load(ctx, "user", "1", counting(&calls, "one", nil))
+		_, _ = memo.Load(ctx, "user", "1", counting(&calls, "one", nil))
+		assert.Equal(t, 4, calls)
+
+		_, _ = memo.Load(context.Background(), "user", "1", counting(&calls, "one", nil))
+		_, _ = memo.Load(context.Background(), "user", "1", counting(&calls, "one", nil))
+		assert.Equal(t, 6, calls)
+	})
+
+	t.Run("should give each request its own cache", func(t *testing.T) {
+		calls := 0
+		app := fiber.New()
+		app.Use(middleware.Memo())
+		app.Get("/", func(c *fiber.Ctx) error {
+			for range 2 {
+				if _, err := memo.Load(c.UserContext(), "user", "1", counting(&calls, "one", nil)); err != nil {
+					return err
+				}
+			}
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		for range 2 {
+			res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		}
+
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -0,0 +1,55 @@
+package metrics_test
+
+import (
+	"app/src/metrics"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutcome(t *testing.T) {
+	t.Run("should classify errors by cause", func(t *testing.T) {
+		assert.Equal(t, metrics.OutcomeSuccess, metrics.Outcome(nil))
+		assert.Equal(t, metrics.OutcomeInvalid, metrics.Outcome(validator.ValidationErrors{}))
+		assert.Equal(t, metrics.OutcomeRejected, metrics.Outcome(fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")))
+		assert.Equal(t, metrics.OutcomeError, metrics.Outcome(fiber.ErrInternalServerError))
+		assert.Equal(t, metrics.OutcomeError, metrics.Outcome(errors.New("connection refused")))
+	})
+}
+
+func TestAuthMetrics(t *testing.T) {
+	t.Run("should export every login series and count attempts", func(t *testing.T) {
+		before, err := testutil.GatherAndCount(metrics.Registry, "app_auth_logins_total")
+		assert.NoError(t, err)
+		assert.Equal(t, 16, before, "4 methods x 4 outcomes")
+
+		metrics.RecordLogin(metrics.MethodPassword, nil)
+		metrics.RecordLogin(metrics.MethodPassword, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password"))
+		metrics.RecordRegistration(nil)
+
+		families, err := metrics.Registry.Gather()
+		assert.NoError(t, err)
+
+		values := map[string]float64{}
+		for _, family := range families {
+			for _, m := range family.GetMetric() {
+				key := family.GetName()
+				for _, label := range m.GetLabel() {
+					key += "," + label.GetValue()
+				}
+				if m.GetCounter() != nil {
+					values[key] = m.GetCounter().GetValue()
+				}
+			}
+		}
+
+		assert.Equal(t, 1.0, values["app_auth_logins_total,password,success"])
+		assert.Equal(t, 1.0, values["app_auth_logins_total,password,rejected"])
+		assert.Equal(t, 0.0, values["app_auth_logins_total,google,success"])
+		assert.Equal(t, 1.0, values["app_auth_registrations_total,success"])
+	})
+}
@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/service"
+	"app/src/utils"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubACLService grants the listed "type/id/permission" entries to every caller
+type stubACLService struct {
+	service.ACLService
+	grants  []string
+	err     error
+	checked []string
+}
+
+func (s *stubACLService) Check(_ context.Context, _ *model.User, resourceType, resourceID, permission string) (bool, error) {
+	s.checked = append(s.checked, resourceType+"/"+resourceID)
+	return slices.Contains(s.grants, resourceType+"/"+resourceID+"/"+permission), s.err
+}
+
+func TestRequirePermission(t *testing.T) {
+	request := func(acl service.ACLService, user *model.User, scopes []string, path string) int {
+		app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+		auth := func(c *fiber.Ctx) error {
+			if user != nil {
+				c.Locals("user", user)
+			}
+			if scopes != nil {
+				c.Locals("scopes", scopes)
+			}
+			return c.Next()
+		}
+		ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+
+		app.Get("/documents/:documentId", auth, middleware.RequirePermission(acl, "document", "documentId", "write"), ok)
+		app.Get("/acl/:type/:id", auth, middleware.RequirePermissionParam(acl, "type", "id", "owner"), ok)
+
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		assert.NoError(t, err)
+		return res.StatusCode
+	}
+	user := &model.User{ID: uuid.New(), Role: "user"}
+
+	t.Run("should let through callers granted the permission on the record", func(t *testing.T) {
+		acl := &stubACLService{grants: []string{"document/42/write"}}
+		assert.Equal(t, fiber.StatusOK, request(acl, user, nil, "/documents/42"))
+		assert.Equal(t, []string{"document/42"}, acl.checked)
+	})
+
+	t.Run("should answer callers without the permission with 403", func(t *testing.T) {
+		acl := &stubACLService{grants: []string{"document/7/write", "document/42/read"}}
+		assert.Equal(t, fiber.StatusForbidden, request(acl, user, nil, "/documents/42"))
+	})
+
+	t.Run("should take the resource type from the route", func(t *testing.T) {
+		acl := &stubACLService{grants: []string{"folder/9/owner"}}
+		assert.Equal(t, fiber.StatusOK, request(acl, user, nil, "/acl/folder/9"))
+		assert.Equal(t, fiber.StatusForbidden, request(acl, user, nil, "/acl/document/9"))
+	})
+
+	t.Run("should let admins through without a grant", func(t *testing.T) {
+		acl := &stubACLService{}
+		assert.Equal(t, fiber.StatusOK, request(acl, &model.User{ID: uuid.New(), Role: "admin"}, nil, "/documents/42"))
+		assert.Empty(t, acl.checked)
+	})
+
+	t.Run("should not let admin tokens without the manageAcl scope skip the check", func(t *testing.T) {
+		admin := &model.User{ID: uuid.New(), Role: "admin"}
+		assert.Equal(t, fiber.StatusForbidden, request(&stubACLService{}, admin, []string{"getUsers"}, "/documents/42"))
+	})
+
+	t.Run("should answer unauthenticated requests with 401", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusUnauthorized, request(&stubACLService{}, nil, nil, "/documents/42"))
+	})
+
+	t.Run("should fail the request when the check fails", func(t *testing.T) {
+		acl := &stubACLService{err: errors.New("connection refused")}
+		assert.Equal(t, fiber.StatusInternalServerError, request(acl, user, nil, "/documents/42"))
+	})
+}
@@ -0,0 +1,37 @@
+package middleware_test
+
+import (
+	"app/src/middleware"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireInteractive(t *testing.T) {
+	app := fiber.New()
+	app.Get("/v1/users/me/tokens", middleware.RequireInteractive(), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	request := func(auth string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/v1/users/me/tokens", nil)
+		req.Header.Set(fiber.HeaderAuthorization, auth)
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res.StatusCode
+	}
+
+	t.Run("should reject personal access tokens", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusForbidden, request("Bearer pat_abc123"))
+	})
+
+	t.Run("should reject tokens delegated to third-party clients", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusForbidden, request("Bearer oat_abc123"))
+	})
+
+	t.Run("should let JWTs through to the auth middleware", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusOK, request("Bearer eyJhbGciOiJIUzI1NiJ9.e30.sig"))
+	})
+}
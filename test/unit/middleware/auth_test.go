@@ -0,0 +1,196 @@
+package middleware_test
+
+import (
+	"app/src/accesstoken"
+	"app/src/cache"
+	"app/src/config"
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/service"
+	"app/src/utils"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthSuspendedUser(t *testing.T) {
+	secret := config.JWTSecret
+	config.JWTSecret = "testsecret"
+	t.Cleanup(func() { config.JWTSecret = secret })
+
+	store := cache.NewMemoryStore()
+	sessions := service.NewSessionService(store)
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	// Only cached sessions are used here, so the user service is never reached
+	app.Get("/me", middleware.Auth(nil, sessions), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	request := func(userID uuid.UUID) int {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":  userID.String(),
+			"iat":  time.Now().Unix(),
+			"exp":  time.Now().Add(time.Minute).Unix(),
+			"type": config.TokenTypeAccess,
+			"iss":  config.JWTIssuer,
+			"aud":  config.JWTAudience,
+		}).SignedString([]byte(config.JWTSecret))
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(fiber.MethodGet, "/me", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res.StatusCode
+	}
+
+	t.Run("should reject a suspended user from the cached session", func(t *testing.T) {
+		user := &model.User{ID: uuid.New(), Role: "user", IsActive: false}
+		assert.NoError(t, sessions.CacheUserSession(context.Background(), user.ID.String(), user))
+
+		assert.Equal(t, fiber.StatusLocked, request(user.ID))
+	})
+
+	t.Run("should let an active user through", func(t *testing.T) {
+		user := &model.User{ID: uuid.New(), Role: "user", IsActive: true}
+		assert.NoError(t, sessions.CacheUserSession(context.Background(), user.ID.String(), user))
+
+		assert.Equal(t, fiber.StatusOK, request(user.ID))
+	})
+
+	t.Run("should treat sessions cached before suspension existed as active", func(t *testing.T) {
+		userID := uuid.New()
+		legacy := `{"id":"` + userID.String() + `","role":"user","session_id":"s","created_at":1}`
+		assert.NoError(t, store.Set(cache.GetSessionKey(userID.String()), []byte(legacy), time.Minute))
+
+		assert.Equal(t, fiber.StatusOK, request(userID))
+	})
+}
+
+func TestAuthTagRateLimits(t *testing.T) {
+	secret := config.JWTSecret
+	config.JWTSecret = "testsecret"
+	t.Cleanup(func() { config.JWTSecret = secret })
+
+	store := cache.NewMemoryStore()
+	sessions := service.NewSessionService(store)
+
+	middleware.EnableTagRateLimits(store, config.TagRateLimitConfig{
+		Limits:     map[string]int{"abuser": 2, "trial": 5},
+		Window:     time.Minute,
+		Backoff:    time.Minute,
+		MaxBackoff: time.Minute,
+	})
+	t.Cleanup(func() { middleware.EnableTagRateLimits(store, config.TagRateLimitConfig{}) })
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Get("/me", middleware.Auth(nil, sessions), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	cached := func(tags ...string) string {
+		user := &model.User{ID: uuid.New(), Role: "user", IsActive: true}
+		for _, tag := range tags {
+			user.Tags = append(user.Tags, model.UserTag{UserID: user.ID, Tag: tag})
+		}
+		assert.NoError(t, sessions.CacheUserSession(context.Background(), user.ID.String(), user))
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":  user.ID.String(),
+			"exp":  time.Now().Add(time.Minute).Unix(),
+			"type": config.TokenTypeAccess,
+			"iss":  config.JWTIssuer,
+			"aud":  config.JWTAudience,
+		}).SignedString([]byte(config.JWTSecret))
+		assert.NoError(t, err)
+		return token
+	}
+
+	request := func(token string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/me", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res.StatusCode
+	}
+
+	t.Run("should apply the strictest limit among the user's tags", func(t *testing.T) {
+		token := cached("trial", "abuser")
+
+		assert.Equal(t, fiber.StatusOK, request(token))
+		assert.Equal(t, fiber.StatusOK, request(token))
+		assert.Equal(t, fiber.StatusTooManyRequests, request(token))
+	})
+
+	t.Run("should not limit users without limited tags", func(t *testing.T) {
+		token := cached("beta")
+
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, fiber.StatusOK, request(token))
+		}
+	})
+}
+
+func TestAuthOpaqueAccessTokens(t *testing.T) {
+	store := cache.NewMemoryStore()
+	sessions := service.NewSessionService(store)
+	opaque := accesstoken.NewStore(store)
+
+	accesstoken.Enable(opaque)
+	t.Cleanup(func() { accesstoken.Enable(nil) })
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Get("/me", middleware.Auth(nil, sessions), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/users", middleware.StatelessAuth("getUsers"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	user := &model.User{ID: uuid.New(), Role: "admin", IsActive: true}
+	assert.NoError(t, sessions.CacheUserSession(context.Background(), user.ID.String(), user))
+
+	request := func(path, token string) int {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res.StatusCode
+	}
+
+	token, err := service.NewTokenService(nil, nil, nil, nil, nil, nil).GenerateAccessToken(user, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, accesstoken.IsOpaque(token))
+
+	t.Run("should accept an opaque token in session and stateless auth", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusOK, request("/me", token))
+		assert.Equal(t, fiber.StatusOK, request("/users", token))
+	})
+
+	t.Run("should keep accepting JWTs issued before the switch", func(t *testing.T) {
+		jwtToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":  user.ID.String(),
+			"exp":  time.Now().Add(time.Minute).Unix(),
+			"type": config.TokenTypeAccess,
+			"iss":  config.JWTIssuer,
+			"aud":  config.JWTAudience,
+		}).SignedString([]byte(config.JWTSecret))
+		assert.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusOK, request("/me", jwtToken))
+	})
+
+	t.Run("should reject an opaque token once revoked", func(t *testing.T) {
+		assert.NoError(t, opaque.RevokeUser(context.Background(), user.ID.String()))
+
+		assert.Equal(t, fiber.StatusUnauthorized, request("/me", token))
+		assert.Equal(t, fiber.StatusUnauthorized, request("/users", token))
+	})
+}
@@ -0,0 +1,97 @@
+package middleware_test
+
+import (
+	"app/src/config"
+	"app/src/middleware"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkhead(t *testing.T) {
+	newApp := func(cfg config.BulkheadConfig, release <-chan struct{}, started chan<- struct{}) *fiber.App {
+		app := fiber.New()
+		app.Get("/export", middleware.NewBulkhead("exports", cfg), func(c *fiber.Ctx) error {
+			started <- struct{}{}
+			<-release
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	send := func(app *fiber.App) (int, string) {
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/export", nil), -1)
+		assert.NoError(t, err)
+		return res.StatusCode, res.Header.Get(fiber.HeaderRetryAfter)
+	}
+
+	// fill starts limit requests that hold their slot until release is closed
+	fill := func(app *fiber.App, limit int, started <-chan struct{}) *sync.WaitGroup {
+		var wg sync.WaitGroup
+		for range limit {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				status, _ := send(app)
+				assert.Equal(t, fiber.StatusOK, status)
+			}()
+		}
+		for range limit {
+			<-started
+		}
+		return &wg
+	}
+
+	t.Run("should reject requests beyond the limit with 503 and Retry-After", func(t *testing.T) {
+		release, started := make(chan struct{}), make(chan struct{}, 3)
+		app := newApp(config.BulkheadConfig{
+			Enabled:    true,
+			Limits:     map[string]int{"exports": 2},
+			RetryAfter: 7 * time.Second,
+		}, release, started)
+
+		wg := fill(app, 2, started)
+
+		status, retryAfter := send(app)
+		assert.Equal(t, fiber.StatusServiceUnavailable, status)
+		assert.Equal(t, "7", retryAfter)
+
+		close(release)
+		wg.Wait()
+
+		status, _ = send(app)
+		assert.Equal(t, fiber.StatusOK, status)
+	})
+
+	t.Run("should let a queued request through when a slot frees up in time", func(t *testing.T) {
+		release, started := make(chan struct{}), make(chan struct{}, 2)
+		app := newApp(config.BulkheadConfig{
+			Enabled: true,
+			Limits:  map[string]int{"exports": 1},
+			Wait:    time.Second,
+		}, release, started)
+
+		wg := fill(app, 1, started)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+		}()
+
+		status, _ := send(app)
+		assert.Equal(t, fiber.StatusOK, status)
+		wg.Wait()
+	})
+
+	t.Run("should pass through groups without a limit", func(t *testing.T) {
+		release, started := make(chan struct{}), make(chan struct{}, 1)
+		close(release)
+		app := newApp(config.BulkheadConfig{Enabled: true, Limits: map[string]int{}}, release, started)
+
+		status, _ := send(app)
+		assert.Equal(t, fiber.StatusOK, status)
+	})
+}
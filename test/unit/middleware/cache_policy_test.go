@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	middlewareCache "app/src/middleware/cache"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachePolicy(t *testing.T) {
+	newApp := func(defaultPolicy string) *fiber.App {
+		store := cache.NewMemoryStore()
+		t.Cleanup(func() { _ = store.Close() })
+
+		app := fiber.New()
+		app.Use(middlewareCache.NewResponseCacheMiddleware(store, &config.ResponseCacheConfig{
+			DefaultTTL:    time.Minute,
+			DefaultPolicy: defaultPolicy,
+		}))
+
+		handler := func(c *fiber.Ctx) error { return c.SendString("ok") }
+		app.Get("/v1/reports", handler)
+		app.Get("/v1/public", middlewareCache.Policy(middlewareCache.PolicyPublic), handler)
+		app.Get("/v1/me", middlewareCache.Policy(middlewareCache.PolicyPrivate), handler)
+		app.Get("/v1/secret", middlewareCache.Policy(middlewareCache.PolicyNoStore), handler)
+		return app
+	}
+
+	// get requests the path twice and returns the second response's X-Cache and Cache-Control
+	get := func(app *fiber.App, path string) (string, string) {
+		var xCache, cacheControl string
+		for i := 0; i < 2; i++ {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+			assert.NoError(t, err)
+			xCache, cacheControl = resp.Header.Get("X-Cache"), resp.Header.Get(fiber.HeaderCacheControl)
+		}
+		return xCache, cacheControl
+	}
+
+	t.Run("should share public responses and keep private and no-store ones out", func(t *testing.T) {
+		app := newApp(middlewareCache.PolicyPublic)
+
+		xCache, _ := get(app, "/v1/reports")
+		assert.Equal(t, "hit", xCache)
+
+		xCache, cacheControl := get(app, "/v1/me")
+		assert.NotEqual(t, "hit", xCache)
+		assert.Equal(t, "private", cacheControl)
+
+		xCache, cacheControl = get(app, "/v1/secret")
+		assert.NotEqual(t, "hit", xCache)
+		assert.Equal(t, "no-store", cacheControl)
+	})
+
+	t.Run("should only cache routes declared public under a private default", func(t *testing.T) {
+		app := newApp(middlewareCache.PolicyPrivate)
+
+		xCache, _ := get(app, "/v1/reports")
+		assert.NotEqual(t, "hit", xCache)
+
+		xCache, _ = get(app, "/v1/public")
+		assert.Equal(t, "hit", xCache)
+	})
+
+	t.Run("should not cache WebSocket handshakes", func(t *testing.T) {
+		app := newApp(middlewareCache.PolicyPublic)
+		app.Get("/v1/ws", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusSwitchingProtocols)
+		})
+
+		for i := 0; i < 2; i++ {
+			req := httptest.NewRequest(fiber.MethodGet, "/v1/ws", nil)
+			req.Header.Set(fiber.HeaderConnection, "Upgrade")
+			req.Header.Set(fiber.HeaderUpgrade, "websocket")
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.NotEqual(t, "hit", resp.Header.Get("X-Cache"))
+		}
+	})
+
+	t.Run("should panic on an unknown policy", func(t *testing.T) {
+		assert.Panics(t, func() { middlewareCache.Policy("shared") })
+	})
+}
@@ -0,0 +1,203 @@
+package middleware_test
+
+import (
+	"app/src/config"
+	"app/src/listener"
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/service"
+	"app/src/utils"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubServiceAccountService knows service accounts by identity
+type stubServiceAccountService struct {
+	service.ServiceAccountService
+	accounts map[string]*model.ServiceAccount
+}
+
+func (s *stubServiceAccountService) AuthenticateCertificate(
+	_ *fiber.Ctx, cert *x509.Certificate,
+) (*model.ServiceAccount, error) {
+	for _, identity := range listener.CertificateIdentities(cert) {
+		if account, ok := s.accounts[identity]; ok {
+			account.User.ServiceAccount = account
+			return account, nil
+		}
+	}
+	return nil, fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+}
+
+// testCA issues certificates for the listener and its clients
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, template *x509.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// writePEM stores a certificate and its key as PEM files, as the listener loads them
+func writePEM(t *testing.T, dir, name string, cert tls.Certificate) (string, string) {
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestClientCertificateAuth(t *testing.T) {
+	listeners := config.Listeners
+	config.Listeners = []config.ListenerConfig{{Name: config.MTLSListener}}
+	t.Cleanup(func() {
+		config.Listeners = listeners
+		middleware.EnableClientCertificates(nil)
+	})
+
+	serviceUser := func(active bool) *model.User {
+		return &model.User{ID: uuid.New(), Role: "user", Type: model.UserTypeService, IsActive: active}
+	}
+	middleware.EnableClientCertificates(&stubServiceAccountService{accounts: map[string]*model.ServiceAccount{
+		"spiffe://example.com/billing": {Name: "billing", Rights: "getUsers", User: serviceUser(true)},
+		"reporting.internal":           {Name: "reporting", Rights: "viewUsage", User: serviceUser(true)},
+		"suspended.internal":           {Name: "suspended", Rights: "getUsers", User: serviceUser(false)},
+	}})
+
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.crt")
+	assert.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), 0o600))
+	serverCert, serverKey := writePEM(t, dir, "server", ca.issue(t, &x509.Certificate{
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}))
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	// Client certificates are checked before the services, which are never reached here
+	app.Get("/users", middleware.Auth(nil, nil, "getUsers"), func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("user").(*model.User).ServiceAccount.Name)
+	})
+	app.Get("/users/me/tokens", middleware.RequireInteractive(), middleware.Auth(nil, nil), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	ln, err := listener.Open(config.ListenerConfig{
+		Name: config.MTLSListener, Network: config.ListenerTCP, Address: "127.0.0.1:0",
+		TLSCert: serverCert, TLSKey: serverKey, ClientCA: caFile,
+	})
+	assert.NoError(t, err)
+	go func() { _ = app.Listener(ln) }()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	get := func(path string, client tls.Certificate) (int, string) {
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs: roots, Certificates: []tls.Certificate{client},
+		}}}
+		res, err := httpClient.Get("https://" + ln.Addr().String() + path)
+		if !assert.NoError(t, err) {
+			return 0, ""
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return res.StatusCode, string(body)
+	}
+	clientCert := func(commonName string, uris ...string) tls.Certificate {
+		template := &x509.Certificate{
+			Subject:     pkix.Name{CommonName: commonName},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		for _, uri := range uris {
+			parsed, err := url.Parse(uri)
+			assert.NoError(t, err)
+			template.URIs = append(template.URIs, parsed)
+		}
+		return ca.issue(t, template)
+	}
+
+	t.Run("should authenticate the service account of the certificate's URI SAN", func(t *testing.T) {
+		status, body := get("/users", clientCert("reporting.internal", "spiffe://example.com/billing"))
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, "billing", body)
+	})
+
+	t.Run("should fall back to the common name", func(t *testing.T) {
+		status, _ := get("/users", clientCert("reporting.internal"))
+		assert.Equal(t, fiber.StatusForbidden, status)
+	})
+
+	t.Run("should reject certificates of unknown identities", func(t *testing.T) {
+		status, _ := get("/users", clientCert("unknown.internal"))
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+	})
+
+	t.Run("should reject suspended service accounts", func(t *testing.T) {
+		status, _ := get("/users", clientCert("suspended.internal"))
+		assert.Equal(t, fiber.StatusLocked, status)
+	})
+
+	t.Run("should keep service accounts off interactive routes", func(t *testing.T) {
+		status, _ := get("/users/me/tokens", clientCert("billing", "spiffe://example.com/billing"))
+		assert.Equal(t, fiber.StatusForbidden, status)
+	})
+
+	t.Run("should refuse clients without a certificate", func(t *testing.T) {
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+		_, err := httpClient.Get("https://" + ln.Addr().String() + "/users")
+		assert.Error(t, err)
+	})
+}
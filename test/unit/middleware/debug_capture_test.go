@@ -0,0 +1,148 @@
+package middleware_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/response"
+	"app/src/service"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugCapture(t *testing.T) {
+	secret := config.JWTSecret
+	config.JWTSecret = "debug-capture-secret"
+	defer func() { config.JWTSecret = secret }()
+
+	accessToken := func(role string) string {
+		user := &model.User{ID: uuid.New(), Role: role}
+		token, err := service.NewTokenService(nil, nil, nil, nil, nil, nil).GenerateAccessToken(user, time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		return token
+	}
+
+	newApp := func(store cache.Store, samplePercent float64) *fiber.App {
+		app := fiber.New()
+		app.Use(middleware.NewDebugCapture(store, config.DebugCaptureConfig{
+			Enabled:       true,
+			SamplePercent: samplePercent,
+			TTL:           time.Minute,
+			MaxBodyBytes:  64,
+		}))
+		app.Post("/login", func(c *fiber.Ctx) error {
+			return c.JSON(fiber.Map{"user": "victim@example.com"})
+		})
+		app.Post("/fail", func(_ *fiber.Ctx) error {
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
+		})
+		app.Post("/upload", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+		return app
+	}
+
+	send := func(app *fiber.App, path, contentType, body string, headers ...string) string {
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, contentType)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res.Header.Get(fiber.HeaderXRequestID)
+	}
+
+	load := func(store cache.Store, requestID string) *response.DebugCapture {
+		data, err := store.Get(cache.GetDebugCaptureKey(requestID))
+		assert.NoError(t, err)
+		if data == nil {
+			return nil
+		}
+		capture := new(response.DebugCapture)
+		assert.NoError(t, json.Unmarshal(data, capture))
+		return capture
+	}
+
+	t.Run("should capture sampled requests with sanitized bodies and headers", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store, 100)
+
+		requestID := send(app, "/login", fiber.MIMEApplicationJSON,
+			`{"email":"victim@example.com","password":"hunter22"}`,
+			fiber.HeaderAuthorization, "Bearer abc.def.ghi")
+
+		capture := load(store, requestID)
+		if assert.NotNil(t, capture) {
+			assert.Equal(t, middleware.CaptureReasonSampled, capture.Reason)
+			assert.Equal(t, fiber.StatusOK, capture.Status)
+			assert.NotContains(t, capture.RequestBody, "hunter22")
+			assert.NotContains(t, capture.RequestBody, "victim@example.com")
+			assert.NotContains(t, capture.ResponseBody, "victim@example.com")
+			assert.Equal(t, "[REDACTED]", capture.RequestHeaders[fiber.HeaderAuthorization])
+		}
+	})
+
+	t.Run("should not capture unsampled requests", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store, 0)
+
+		requestID := send(app, "/login", fiber.MIMEApplicationJSON, `{}`)
+		assert.Empty(t, requestID)
+	})
+
+	t.Run("should honour the debug header only for callers with the debugRequests right", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store, 0)
+
+		requestID := send(app, "/login", fiber.MIMEApplicationJSON, `{}`,
+			config.DebugCaptureHeader, "1", fiber.HeaderAuthorization, "Bearer "+accessToken("user"))
+		assert.Empty(t, requestID)
+
+		requestID = send(app, "/login", fiber.MIMEApplicationJSON, `{}`,
+			config.DebugCaptureHeader, "1", fiber.HeaderAuthorization, "Bearer "+accessToken("admin"))
+		capture := load(store, requestID)
+		if assert.NotNil(t, capture) {
+			assert.Equal(t, middleware.CaptureReasonHeader, capture.Reason)
+		}
+	})
+
+	t.Run("should record handler errors and summarise binary bodies", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store, 100)
+
+		capture := load(store, send(app, "/fail", fiber.MIMEApplicationJSON, `{}`))
+		if assert.NotNil(t, capture) {
+			assert.Equal(t, fiber.StatusUnauthorized, capture.Status)
+			assert.Equal(t, "Invalid email or password", capture.Error)
+		}
+
+		capture = load(store, send(app, "/upload", "application/octet-stream", strings.Repeat("x", 100)))
+		if assert.NotNil(t, capture) {
+			assert.Equal(t, "[application/octet-stream body, 100 bytes]", capture.RequestBody)
+		}
+	})
+
+	t.Run("should truncate large bodies", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store, 100)
+
+		capture := load(store, send(app, "/upload", fiber.MIMETextPlain, strings.Repeat("a", 100)))
+		if assert.NotNil(t, capture) {
+			assert.Equal(t, strings.Repeat("a", 64)+"...[truncated]", capture.RequestBody)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package middleware_test
+
+import (
+	"app/src/config"
+	"app/src/listener"
+	middlewareCache "app/src/middleware/cache"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestDedupMiddleware(t *testing.T) {
+	var executions atomic.Int32
+
+	app := fiber.New()
+	app.Use(middlewareCache.NewRequestDedupMiddleware())
+	app.Get("/v1/reports", func(c *fiber.Ctx) error {
+		executions.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		return c.JSON(fiber.Map{"auth": c.Get(fiber.HeaderAuthorization)})
+	})
+
+	fire := func(n int, auth func(i int) string) []string {
+		bodies := make([]string, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				req := httptest.NewRequest(fiber.MethodGet, "/v1/reports?b=2&a=1", nil)
+				req.Header.Set(fiber.HeaderAuthorization, auth(i))
+				resp, err := app.Test(req, -1)
+				assert.NoError(t, err)
+				body, _ := io.ReadAll(resp.Body)
+				bodies[i] = string(body)
+			}(i)
+		}
+		wg.Wait()
+		return bodies
+	}
+
+	t.Run("should execute identical concurrent requests once", func(t *testing.T) {
+		executions.Store(0)
+
+		bodies := fire(5, func(int) string { return "Bearer same" })
+
+		assert.Equal(t, int32(1), executions.Load())
+		for _, body := range bodies {
+			assert.JSONEq(t, `{"auth":"Bearer same"}`, body)
+		}
+	})
+
+	t.Run("should not share responses between different callers", func(t *testing.T) {
+		executions.Store(0)
+
+		bodies := fire(2, func(i int) string { return []string{"Bearer alice", "Bearer bob"}[i] })
+
+		assert.Equal(t, int32(2), executions.Load())
+		assert.JSONEq(t, `{"auth":"Bearer alice"}`, bodies[0])
+		assert.JSONEq(t, `{"auth":"Bearer bob"}`, bodies[1])
+	})
+}
+
+func TestRequestDedupMiddlewareCredentials(t *testing.T) {
+	listeners := config.Listeners
+	config.Listeners = []config.ListenerConfig{{Name: config.MTLSListener}}
+	t.Cleanup(func() { config.Listeners = listeners })
+
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.crt")
+	assert.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), 0o600))
+	serverCert, serverKey := writePEM(t, dir, "server", ca.issue(t, &x509.Certificate{
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}))
+
+	var executions atomic.Int32
+	app := fiber.New()
+	app.Use(middlewareCache.NewRequestDedupMiddleware())
+	app.Get("/v1/reports", func(c *fiber.Ctx) error {
+		executions.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		caller := listener.Name(c.Context().Conn())
+		if cert := listener.ClientCertificate(c.Context().Conn()); cert != nil {
+			caller = cert.Subject.CommonName
+		}
+		return c.SendString(caller)
+	})
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	mtls, err := listener.Open(config.ListenerConfig{
+		Name: config.MTLSListener, Network: config.ListenerTCP, Address: "127.0.0.1:0",
+		TLSCert: serverCert, TLSKey: serverKey, ClientCA: caFile,
+	})
+	assert.NoError(t, err)
+	go func() { _ = app.Listener(mtls) }()
+
+	public, err := listener.Open(config.ListenerConfig{Name: "public", Network: config.ListenerTCP, Address: "127.0.0.1:0"})
+	assert.NoError(t, err)
+	go func() { _ = app.Listener(public) }()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	mtlsClient := func(commonName string) *http.Client {
+		cert := ca.issue(t, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: commonName},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs: roots, Certificates: []tls.Certificate{cert},
+		}}}
+	}
+
+	// fire sends the requests at once, without an Authorization header
+	fire := func(clients []*http.Client, urls []string) []string {
+		bodies := make([]string, len(clients))
+		var wg sync.WaitGroup
+		for i := range clients {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := clients[i].Get(urls[i])
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer res.Body.Close()
+				body, _ := io.ReadAll(res.Body)
+				bodies[i] = string(body)
+			}()
+		}
+		wg.Wait()
+		return bodies
+	}
+
+	mtlsURL := "https://" + mtls.Addr().String() + "/v1/reports"
+	publicURL := "http://" + public.Addr().String() + "/v1/reports"
+
+	t.Run("should not share responses between client certificates", func(t *testing.T) {
+		executions.Store(0)
+
+		bodies := fire([]*http.Client{mtlsClient("billing"), mtlsClient("reporting")}, []string{mtlsURL, mtlsURL})
+
+		assert.Equal(t, int32(2), executions.Load())
+		assert.Equal(t, []string{"billing", "reporting"}, bodies)
+	})
+
+	t.Run("should not share responses between listeners", func(t *testing.T) {
+		executions.Store(0)
+
+		bodies := fire([]*http.Client{mtlsClient("billing"), http.DefaultClient}, []string{mtlsURL, publicURL})
+
+		assert.Equal(t, int32(2), executions.Load())
+		assert.Equal(t, []string{"billing", "public"}, bodies)
+	})
+
+	t.Run("should still share responses between anonymous callers of a listener", func(t *testing.T) {
+		executions.Store(0)
+
+		bodies := fire([]*http.Client{http.DefaultClient, http.DefaultClient}, []string{publicURL, publicURL})
+
+		assert.Equal(t, int32(1), executions.Load())
+		assert.Equal(t, []string{"public", "public"}, bodies)
+	})
+}
@@ -0,0 +1,56 @@
+package middleware_test
+
+import (
+	"app/src/middleware"
+	"app/src/utils"
+	"app/src/utils/id"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIDs(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Get("/users/:userId/tokens/:tokenId", middleware.ValidateIDs("userId", "tokenId"), func(c *fiber.Ctx) error {
+		return c.SendString(utils.ParamID(c, "userId") + " " + utils.ParamID(c, "tokenId"))
+	})
+
+	request := func(path string) (int, string) {
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		assert.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, err)
+		return res.StatusCode, string(body)
+	}
+
+	t.Run("should pass parsed IDs to the handler", func(t *testing.T) {
+		userID, tokenID := uuid.New(), uuid.New()
+
+		status, body := request("/users/" + userID.String() + "/tokens/" + tokenID.String())
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, userID.String()+" "+tokenID.String(), body)
+	})
+
+	t.Run("should pass IDs given as ULIDs in canonical form", func(t *testing.T) {
+		userID, tokenID := id.New(), id.New()
+
+		status, body := request("/users/" + id.ULID(userID) + "/tokens/" + tokenID.String())
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, userID.String()+" "+tokenID.String(), body)
+	})
+
+	t.Run("should answer malformed IDs with 400 naming the param", func(t *testing.T) {
+		status, body := request("/users/" + uuid.NewString() + "/tokens/123")
+		assert.Equal(t, fiber.StatusBadRequest, status)
+
+		var response map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(body), &response))
+		assert.Equal(t, "Invalid token ID", response["message"])
+	})
+}
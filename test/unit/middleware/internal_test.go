@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"app/src/config"
+	"app/src/middleware"
+	"app/src/utils"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInternal(t *testing.T) {
+	listeners, internal := config.Listeners, config.Internal
+	t.Cleanup(func() { config.Listeners, config.Internal = listeners, internal })
+
+	newApp := func() *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+		app.Get("/metrics", middleware.InternalOnly(), middleware.InternalBasicAuth(), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	// app.Test connections are not accepted by a named listener, so they count as the first one
+	request := func(app *fiber.App, user, password string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/metrics", nil)
+		if user != "" {
+			req.SetBasicAuth(user, password)
+		}
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res.StatusCode
+	}
+
+	t.Run("should serve everywhere without an internal listener", func(t *testing.T) {
+		config.Listeners = []config.ListenerConfig{{Name: config.DefaultListener}}
+		config.Internal = config.InternalConfig{}
+
+		assert.Equal(t, fiber.StatusOK, request(newApp(), "", ""))
+	})
+
+	t.Run("should hide internal routes everywhere when closed", func(t *testing.T) {
+		config.Listeners = []config.ListenerConfig{{Name: config.DefaultListener}}
+		config.Internal = config.InternalConfig{Closed: true}
+
+		assert.Equal(t, fiber.StatusNotFound, request(newApp(), "", ""))
+	})
+
+	t.Run("should hide internal routes from the public listener", func(t *testing.T) {
+		config.Listeners = []config.ListenerConfig{{Name: config.DefaultListener}, {Name: config.InternalListener}}
+		config.Internal = config.InternalConfig{Enabled: true, BasicAuthUser: "ops", BasicAuthPassword: "secret"}
+
+		assert.Equal(t, fiber.StatusNotFound, request(newApp(), "ops", "secret"))
+	})
+
+	t.Run("should require the basic auth credentials on the internal listener", func(t *testing.T) {
+		config.Listeners = []config.ListenerConfig{{Name: config.InternalListener}}
+		config.Internal = config.InternalConfig{Enabled: true, BasicAuthUser: "ops", BasicAuthPassword: "secret"}
+		app := newApp()
+
+		assert.Equal(t, fiber.StatusUnauthorized, request(app, "", ""))
+		assert.Equal(t, fiber.StatusUnauthorized, request(app, "ops", "wrong"))
+		assert.Equal(t, fiber.StatusOK, request(app, "ops", "secret"))
+	})
+}
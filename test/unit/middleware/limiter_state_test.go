@@ -0,0 +1,142 @@
+package middleware_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/middleware"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitInspector(t *testing.T) {
+	cfg := &config.RateLimiterConfig{
+		Enabled:       true,
+		DefaultMax:    3,
+		DefaultWindow: time.Minute,
+		AuthMax:       2,
+		AuthWindow:    time.Minute,
+	}
+
+	newApp := func(store cache.Store) *fiber.App {
+		app := fiber.New()
+		app.Use(middleware.NewRateLimiterMiddleware(store, cfg))
+		// The limiter only counts failures, read from the status before the error handler runs
+		app.Get("/fail", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		})
+		return app
+	}
+
+	fail := func(app *fiber.App, ip string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/fail", nil)
+		req.Header.Set("X-Forwarded-For", ip)
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res.StatusCode
+	}
+
+	t.Run("should report hits and remaining requests for an IP", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store)
+		inspector := middleware.NewRateLimitInspector(store, cfg)
+
+		fail(app, "203.0.113.7")
+		fail(app, "203.0.113.7")
+
+		state, err := inspector.Inspect("ip:203.0.113.7")
+		assert.NoError(t, err)
+		if assert.NotNil(t, state) {
+			assert.Equal(t, "rate_limit", state.Limiter)
+			assert.Equal(t, 2, state.Hits)
+			assert.Equal(t, 3, state.Limit)
+			assert.Equal(t, 1, state.Remaining)
+			assert.False(t, state.Blocked)
+			assert.NotNil(t, state.ResetAt)
+		}
+
+		state, err = inspector.Inspect("ip:198.51.100.1")
+		assert.NoError(t, err)
+		assert.Nil(t, state)
+	})
+
+	t.Run("should unblock a subject after a reset", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store)
+		inspector := middleware.NewRateLimitInspector(store, cfg)
+
+		for range 3 {
+			fail(app, "203.0.113.7")
+		}
+		assert.Equal(t, fiber.StatusTooManyRequests, fail(app, "203.0.113.7"))
+
+		state, err := inspector.Inspect("ip:203.0.113.7")
+		assert.NoError(t, err)
+		if assert.NotNil(t, state) {
+			assert.True(t, state.Blocked)
+			assert.Equal(t, 0, state.Remaining)
+		}
+
+		reset, err := inspector.Reset("ip:203.0.113.7")
+		assert.NoError(t, err)
+		assert.True(t, reset)
+		assert.Equal(t, fiber.StatusUnauthorized, fail(app, "203.0.113.7"))
+
+		reset, err = inspector.Reset("ip:198.51.100.1")
+		assert.NoError(t, err)
+		assert.False(t, reset)
+	})
+}
+
+func TestThrottleInspector(t *testing.T) {
+	cfg := config.ThrottleConfig{
+		Enabled:     true,
+		Max:         1,
+		Window:      time.Minute,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  4 * time.Minute,
+	}
+
+	t.Run("should report a blocked email and clear it on reset", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+
+		app := fiber.New()
+		app.Post("/forgot-password",
+			middleware.NewTargetThrottle(store, "forgot-password", cfg, middleware.EmailFromBody, nil),
+			func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) },
+		)
+		send := func() int {
+			req := httptest.NewRequest(fiber.MethodPost, "/forgot-password", strings.NewReader(`{"email":"victim@example.com"}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			res, err := app.Test(req)
+			assert.NoError(t, err)
+			return res.StatusCode
+		}
+
+		send()
+		assert.Equal(t, fiber.StatusTooManyRequests, send())
+
+		inspector := middleware.NewThrottleInspector(store, "forgot-password", cfg)
+		assert.Equal(t, "throttle:forgot-password", inspector.Name())
+
+		state, err := inspector.Inspect("victim@example.com")
+		assert.NoError(t, err)
+		if assert.NotNil(t, state) {
+			assert.True(t, state.Blocked)
+			assert.Equal(t, 1, state.Strikes)
+			assert.NotNil(t, state.BlockedUntil)
+		}
+
+		reset, err := inspector.Reset("victim@example.com")
+		assert.NoError(t, err)
+		assert.True(t, reset)
+		assert.Equal(t, fiber.StatusOK, send())
+	})
+}
@@ -0,0 +1,123 @@
+package middleware_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginDelay(t *testing.T) {
+	cfg := config.LoginDelayConfig{
+		Enabled:   true,
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+		Reset:     time.Minute,
+	}
+
+	newApp := func(store cache.Store) *fiber.App {
+		app := fiber.New()
+		app.Post("/login",
+			middleware.NewLoginDelay(store, cfg, middleware.EmailFromBody),
+			func(c *fiber.Ctx) error {
+				if c.Get("X-Password") != "right" {
+					return fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
+				}
+				return c.SendStatus(fiber.StatusOK)
+			},
+		)
+		return app
+	}
+
+	send := func(app *fiber.App, email, password string, headers ...string) *http.Response {
+		req := httptest.NewRequest(fiber.MethodPost, "/login", strings.NewReader(`{"email":"`+email+`"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set("X-Password", password)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res
+	}
+
+	t.Run("should delay the next attempt per email regardless of IP", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store)
+
+		assert.Equal(t, fiber.StatusUnauthorized, send(app, "victim@example.com", "wrong", "X-Forwarded-For", "1.1.1.1").StatusCode)
+
+		res := send(app, "Victim@Example.com", "right", "X-Forwarded-For", "2.2.2.2")
+		assert.Equal(t, fiber.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "1", res.Header.Get(fiber.HeaderRetryAfter))
+
+		assert.Equal(t, fiber.StatusOK, send(app, "other@example.com", "right").StatusCode, "other targets are unaffected")
+
+		time.Sleep(cfg.BaseDelay)
+		assert.Equal(t, fiber.StatusOK, send(app, "victim@example.com", "right").StatusCode)
+		assert.Equal(t, fiber.StatusUnauthorized, send(app, "victim@example.com", "wrong").StatusCode, "a success clears the count")
+	})
+
+	t.Run("should double the delay with every failure", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store)
+
+		assert.Equal(t, fiber.StatusUnauthorized, send(app, "victim@example.com", "wrong").StatusCode)
+		time.Sleep(cfg.BaseDelay)
+		assert.Equal(t, fiber.StatusUnauthorized, send(app, "victim@example.com", "wrong").StatusCode)
+
+		time.Sleep(cfg.BaseDelay)
+		assert.Equal(t, fiber.StatusTooManyRequests, send(app, "victim@example.com", "right").StatusCode, "still within the second delay")
+
+		time.Sleep(cfg.BaseDelay)
+		assert.Equal(t, fiber.StatusOK, send(app, "victim@example.com", "right").StatusCode)
+	})
+
+	t.Run("should report and clear the count through the inspector", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store)
+		inspector := middleware.NewLoginDelayInspector(store, cfg)
+
+		assert.Equal(t, fiber.StatusUnauthorized, send(app, "victim@example.com", "wrong").StatusCode)
+
+		state, err := inspector.Inspect("victim@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, state.Hits)
+		assert.True(t, state.Blocked)
+
+		cleared, err := inspector.Reset("victim@example.com")
+		assert.NoError(t, err)
+		assert.True(t, cleared)
+		assert.Equal(t, fiber.StatusOK, send(app, "victim@example.com", "right").StatusCode)
+	})
+
+	t.Run("should pass through when disabled", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		disabled := cfg
+		disabled.Enabled = false
+
+		app := fiber.New()
+		app.Post("/login", middleware.NewLoginDelay(store, disabled, middleware.EmailFromBody), func(c *fiber.Ctx) error {
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
+		})
+
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest(fiber.MethodPost, "/login", strings.NewReader(`{"email":"victim@example.com"}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			res, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusUnauthorized, res.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package middleware_test
+
+import (
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/policy"
+	"app/src/service"
+	"app/src/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAPITokens resolves every token to the same token and user
+type stubAPITokens struct {
+	service.APITokenService
+	token *model.APIToken
+	user  *model.User
+}
+
+func (s *stubAPITokens) Authenticate(_ *fiber.Ctx, _ string) (*model.APIToken, *model.User, error) {
+	return s.token, s.user, nil
+}
+
+// stubOAuthProvider counts the client requests it is asked to limit, rejecting those over max
+type stubOAuthProvider struct {
+	service.OAuthProviderService
+	max      int
+	requests int
+}
+
+func (s *stubOAuthProvider) LimitClient(_ *fiber.Ctx, _ *model.OAuthClient) error {
+	s.requests++
+	if s.requests > s.max {
+		return fiber.NewError(fiber.StatusTooManyRequests, "Client rate limit exceeded. Please try again later.")
+	}
+	return nil
+}
+
+func TestOAuthScope(t *testing.T) {
+	user := &model.User{ID: uuid.New(), Role: "user", IsActive: true}
+	client := &model.OAuthClient{ID: uuid.New(), Name: "Acme Reports"}
+	tokens := &stubAPITokens{user: user}
+	provider := &stubOAuthProvider{max: 100}
+
+	middleware.EnableAPITokens(tokens)
+	middleware.EnableOAuthClients(provider)
+	t.Cleanup(func() {
+		middleware.EnableAPITokens(nil)
+		middleware.EnableOAuthClients(nil)
+	})
+
+	ok := func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Get("/users/:userId", middleware.OAuthScope("profile:read", "users:read"),
+		middleware.AuthPolicy(nil, nil, policy.AnyOf(policy.HasRights("getUsers"), policy.IsOwner("userId"))), ok)
+	app.Get("/users", middleware.OAuthScope("users:read"), middleware.Auth(nil, nil, "getUsers"), ok)
+	app.Patch("/users/:userId", middleware.AuthPolicy(nil, nil, policy.IsOwner("userId")), ok)
+
+	request := func(method, path string, scopes string) *http.Response {
+		tokens.token = &model.APIToken{
+			UserID:    user.ID,
+			ClientID:  &client.ID,
+			Client:    client,
+			Scopes:    scopes,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+service.OAuthTokenPrefix+"abc123")
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res
+	}
+
+	t.Run("should let a client token reach a route declaring its scope", func(t *testing.T) {
+		res := request(fiber.MethodGet, "/users/"+user.ID.String(), "profile:read")
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	})
+
+	t.Run("should still apply the route policy", func(t *testing.T) {
+		res := request(fiber.MethodGet, "/users/"+uuid.NewString(), "profile:read")
+		assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("should reject routes that accept no scope", func(t *testing.T) {
+		res := request(fiber.MethodPatch, "/users/"+user.ID.String(), "profile:read,users:read")
+		assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("should reject a token without the route's scope", func(t *testing.T) {
+		res := request(fiber.MethodGet, "/users", "profile:read")
+		assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+		assert.Contains(t, res.Header.Get(fiber.HeaderWWWAuthenticate), `scope="users:read"`)
+	})
+
+	t.Run("should only grant the rights of a scope that the user holds", func(t *testing.T) {
+		res := request(fiber.MethodGet, "/users", "users:read")
+		assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+
+		user.Role = "admin"
+		t.Cleanup(func() { user.Role = "user" })
+
+		res = request(fiber.MethodGet, "/users", "users:read")
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	})
+
+	t.Run("should apply the client's rate limit", func(t *testing.T) {
+		provider.requests, provider.max = 0, 1
+
+		assert.Equal(t, fiber.StatusOK, request(fiber.MethodGet, "/users/"+user.ID.String(), "profile:read").StatusCode)
+		assert.Equal(t, fiber.StatusTooManyRequests,
+			request(fiber.MethodGet, "/users/"+user.ID.String(), "profile:read").StatusCode)
+	})
+}
@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"app/src/config"
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/utils"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePlan(t *testing.T) {
+	defaultPlan := config.Quota.DefaultPlan
+	config.Quota.DefaultPlan = "free"
+	t.Cleanup(func() { config.Quota.DefaultPlan = defaultPlan })
+
+	request := func(plan string) int {
+		app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+		app.Get("/reports", func(c *fiber.Ctx) error {
+			c.Locals("user", &model.User{Plan: plan})
+			return c.Next()
+		}, middleware.RequirePlan("pro", "team"), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/reports", nil))
+		assert.NoError(t, err)
+		return res.StatusCode
+	}
+
+	t.Run("should let users on a listed plan through", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusOK, request("team"))
+	})
+
+	t.Run("should answer users on other plans with 402", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusPaymentRequired, request("free"))
+	})
+
+	t.Run("should treat users without a plan as on the default plan", func(t *testing.T) {
+		config.Quota.DefaultPlan = "pro"
+		assert.Equal(t, fiber.StatusOK, request(""))
+	})
+}
@@ -0,0 +1,82 @@
+package middleware_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/middleware"
+	"app/src/model"
+	"app/src/service"
+	"app/src/utils"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthQuotas(t *testing.T) {
+	secret := config.JWTSecret
+	config.JWTSecret = "testsecret"
+	t.Cleanup(func() { config.JWTSecret = secret })
+
+	store := cache.NewMemoryStore()
+	sessions := service.NewSessionService(store)
+
+	cfg := config.QuotaConfig{
+		Enabled:         true,
+		Plans:           map[string]config.QuotaLimits{"free": {Daily: 1, Monthly: 10}},
+		DefaultPlan:     "free",
+		PersistInterval: time.Minute,
+	}
+	middleware.EnableQuotas(service.NewQuotaService(nil, nil, store, nil, nil, nil, cfg, nil))
+	t.Cleanup(func() { middleware.EnableQuotas(nil) })
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Get("/me", middleware.Auth(nil, sessions), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	user := &model.User{ID: uuid.New(), Role: "user", IsActive: true, Plan: "free"}
+	assert.NoError(t, sessions.CacheUserSession(context.Background(), user.ID.String(), user))
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  user.ID.String(),
+		"exp":  time.Now().Add(time.Minute).Unix(),
+		"type": config.TokenTypeAccess,
+		"iss":  config.JWTIssuer,
+		"aud":  config.JWTAudience,
+	}).SignedString([]byte(config.JWTSecret))
+	assert.NoError(t, err)
+
+	request := func() (int, http.Header) {
+		req := httptest.NewRequest(fiber.MethodGet, "/me", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res.StatusCode, res.Header
+	}
+
+	t.Run("should report the remaining quota in the headers", func(t *testing.T) {
+		status, header := request()
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, []string{"1"}, header["X-Quota-Daily-Limit"])
+		assert.Equal(t, []string{"0"}, header["X-Quota-Daily-Remaining"])
+		assert.Equal(t, []string{"9"}, header["X-Quota-Monthly-Remaining"])
+	})
+
+	t.Run("should reject with 429 and Retry-After once the daily quota is used up", func(t *testing.T) {
+		status, header := request()
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+
+		retryAfter, err := strconv.Atoi(header.Get(fiber.HeaderRetryAfter))
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, retryAfter, 24*60*60)
+		assert.Equal(t, header.Get(fiber.HeaderRetryAfter), header.Get("X-Quota-Daily-Reset"))
+	})
+}
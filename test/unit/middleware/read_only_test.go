@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"app/src/middleware"
+	"app/src/readonly"
+	"app/src/response"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.ReadOnly([]string{"/v1/admin/read-only", "/v1/auth/login"}))
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Get("/v1/users", ok)
+	app.Post("/v1/users", ok)
+	app.Put("/v1/admin/read-only", ok)
+	app.Post("/v1/auth/login", ok)
+
+	request := func(method, url string) (int, []byte) {
+		resp, err := app.Test(httptest.NewRequest(method, url, nil))
+		assert.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return resp.StatusCode, body
+	}
+	status := func(method, url string) int {
+		code, _ := request(method, url)
+		return code
+	}
+
+	// Without Redis the switch applies to this instance only
+	readOnlySwitch := readonly.NewSwitch(nil)
+	t.Cleanup(func() {
+		readonly.Force(false)
+		assert.NoError(t, readOnlySwitch.Set(context.Background(), readonly.State{}))
+	})
+
+	t.Run("should let writes through while off", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusOK, status(fiber.MethodPost, "/v1/users"))
+	})
+
+	t.Run("should refuse writes with the code read_only while on", func(t *testing.T) {
+		assert.NoError(t, readOnlySwitch.Set(context.Background(), readonly.State{Enabled: true, Reason: "Migration"}))
+
+		code, responseBody := request(fiber.MethodPost, "/v1/users")
+		assert.Equal(t, fiber.StatusServiceUnavailable, code)
+
+		var body struct {
+			Errors response.ReadOnlyRefusal `json:"errors"`
+		}
+		assert.NoError(t, json.Unmarshal(responseBody, &body))
+		assert.Equal(t, "read_only", body.Errors.Code)
+		assert.Equal(t, "Migration", body.Errors.Reason)
+	})
+
+	t.Run("should let reads, dry runs and allowed paths through while on", func(t *testing.T) {
+		assert.True(t, readonly.Enabled())
+
+		assert.Equal(t, fiber.StatusOK, status(fiber.MethodGet, "/v1/users"))
+		assert.Equal(t, fiber.StatusOK, status(fiber.MethodPost, "/v1/users?dryRun=true"))
+		assert.Equal(t, fiber.StatusOK, status(fiber.MethodPut, "/v1/admin/read-only"))
+		assert.Equal(t, fiber.StatusOK, status(fiber.MethodPost, "/v1/auth/login"))
+	})
+
+	t.Run("should stay on while forced whatever the switch says", func(t *testing.T) {
+		readonly.Force(true)
+		assert.NoError(t, readOnlySwitch.Set(context.Background(), readonly.State{}))
+
+		state := readonly.Current()
+		assert.True(t, state.Enabled)
+		assert.True(t, state.Forced)
+		assert.Equal(t, fiber.StatusServiceUnavailable, status(fiber.MethodPost, "/v1/users"))
+
+		readonly.Force(false)
+		assert.Equal(t, fiber.StatusOK, status(fiber.MethodPost, "/v1/users"))
+	})
+}
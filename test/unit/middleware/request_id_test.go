@@ -0,0 +1,71 @@
+package middleware_test
+
+import (
+	"app/src/middleware"
+	"app/src/response"
+	"app/src/tracing"
+	"app/src/utils"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Use(middleware.RequestID())
+	app.Get("/context", func(c *fiber.Ctx) error {
+		return c.SendString(tracing.RequestID(c.UserContext()))
+	})
+	app.Get("/fail", func(_ *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusConflict, "Already exists")
+	})
+
+	request := func(path, requestID string) (string, string) {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		if requestID != "" {
+			req.Header.Set(fiber.HeaderXRequestID, requestID)
+		}
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, err)
+		return res.Header.Get(fiber.HeaderXRequestID), string(body)
+	}
+
+	t.Run("should generate an ID and carry it in the user context", func(t *testing.T) {
+		header, body := request("/context", "")
+		assert.NotEmpty(t, header)
+		assert.Equal(t, header, body)
+	})
+
+	t.Run("should keep a well-formed ID from the client", func(t *testing.T) {
+		header, body := request("/context", "edge-7f3a:42")
+		assert.Equal(t, "edge-7f3a:42", header)
+		assert.Equal(t, "edge-7f3a:42", body)
+	})
+
+	t.Run("should replace malformed or oversized IDs", func(t *testing.T) {
+		for _, requestID := range []string{"two words", "<script>", strings.Repeat("a", tracing.MaxRequestIDLength+1)} {
+			header, body := request("/context", requestID)
+			assert.NotEqual(t, requestID, header)
+			assert.True(t, tracing.ValidRequestID(header))
+			assert.Equal(t, header, body)
+		}
+	})
+
+	t.Run("should put the ID in error responses", func(t *testing.T) {
+		header, body := request("/fail", "support-case-1")
+		assert.Equal(t, "support-case-1", header)
+
+		errorBody := new(response.Common)
+		assert.NoError(t, json.Unmarshal([]byte(body), errorBody))
+		assert.Equal(t, fiber.StatusConflict, errorBody.Code)
+		assert.Equal(t, "support-case-1", errorBody.RequestID)
+	})
+}
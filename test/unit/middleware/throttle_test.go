@@ -0,0 +1,107 @@
+package middleware_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/middleware"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetThrottle(t *testing.T) {
+	cfg := config.ThrottleConfig{
+		Enabled:      true,
+		Max:          2,
+		Window:       time.Minute,
+		BaseBackoff:  time.Minute,
+		MaxBackoff:   4 * time.Minute,
+		CaptchaAfter: 1,
+	}
+
+	newApp := func(store cache.Store, captcha middleware.CaptchaHook) *fiber.App {
+		app := fiber.New()
+		app.Post("/forgot-password",
+			middleware.NewTargetThrottle(store, "forgot-password", cfg, middleware.EmailFromBody, captcha),
+			func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) },
+		)
+		return app
+	}
+
+	send := func(app *fiber.App, email string, headers ...string) *http.Response {
+		req := httptest.NewRequest(fiber.MethodPost, "/forgot-password", strings.NewReader(`{"email":"`+email+`"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res
+	}
+
+	t.Run("should throttle per email regardless of IP", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app := newApp(store, nil)
+
+		assert.Equal(t, fiber.StatusOK, send(app, "victim@example.com", "X-Forwarded-For", "1.1.1.1").StatusCode)
+		assert.Equal(t, fiber.StatusOK, send(app, "victim@example.com", "X-Forwarded-For", "2.2.2.2").StatusCode)
+
+		res := send(app, "Victim@Example.com", "X-Forwarded-For", "3.3.3.3")
+		assert.Equal(t, fiber.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "60", res.Header.Get(fiber.HeaderRetryAfter))
+
+		assert.Equal(t, fiber.StatusTooManyRequests, send(app, "victim@example.com").StatusCode, "blocked during backoff")
+		assert.Equal(t, fiber.StatusOK, send(app, "other@example.com").StatusCode, "other targets are unaffected")
+	})
+
+	t.Run("should require the CAPTCHA hook after repeated violations", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		captcha := func(c *fiber.Ctx) error {
+			if c.Get("X-Captcha") != "ok" {
+				return fiber.NewError(fiber.StatusForbidden, "CAPTCHA required")
+			}
+			return nil
+		}
+		app := newApp(store, captcha)
+
+		assert.Equal(t, fiber.StatusOK, send(app, "victim@example.com").StatusCode)
+		assert.Equal(t, fiber.StatusOK, send(app, "victim@example.com").StatusCode)
+		assert.Equal(t, fiber.StatusTooManyRequests, send(app, "victim@example.com").StatusCode)
+
+		// Simulate the backoff elapsing while keeping the strike
+		keys, _ := store.Keys(t.Context(), cache.ThrottleKeyPrefix+"*")
+		assert.Len(t, keys, 1)
+		data, _ := store.Get(keys[0])
+		state := map[string]int64{}
+		assert.NoError(t, json.Unmarshal(data, &state))
+		state["blocked_until"] = 0
+		data, _ = json.Marshal(state)
+		assert.NoError(t, store.Set(keys[0], data, time.Minute))
+
+		assert.Equal(t, fiber.StatusForbidden, send(app, "victim@example.com").StatusCode)
+		assert.Equal(t, fiber.StatusOK, send(app, "victim@example.com", "X-Captcha", "ok").StatusCode)
+	})
+
+	t.Run("should pass through without a store or target", func(t *testing.T) {
+		app := newApp(nil, func(*fiber.Ctx) error { return errors.New("unexpected") })
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, fiber.StatusOK, send(app, "victim@example.com").StatusCode)
+		}
+
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		app = newApp(store, nil)
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, fiber.StatusOK, send(app, "").StatusCode)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"app/src/middleware"
+	"app/src/utils"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Use(middleware.Timeout(20 * time.Millisecond))
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		<-c.UserContext().Done()
+		return c.UserContext().Err()
+	})
+	app.Get("/fast", func(c *fiber.Ctx) error {
+		_, ok := c.UserContext().Deadline()
+		assert.True(t, ok)
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		<-c.UserContext().Done()
+		return fiber.NewError(fiber.StatusNotFound, "User not found")
+	})
+
+	request := func(path string) int {
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		assert.NoError(t, err)
+		return res.StatusCode
+	}
+
+	t.Run("should answer 408 when the handler fails on the deadline", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusRequestTimeout, request("/slow"))
+	})
+
+	t.Run("should give fast handlers a deadline and let them through", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusOK, request("/fast"))
+	})
+
+	t.Run("should keep errors the handler already mapped to a status", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusNotFound, request("/missing"))
+	})
+}
@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"app/src/config"
+	"app/src/middleware"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrafficMirror(t *testing.T) {
+	type mirrored struct {
+		path   string
+		query  string
+		header http.Header
+		body   string
+	}
+
+	received := make(chan mirrored, 10)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- mirrored{path: r.URL.Path, query: r.URL.RawQuery, header: r.Header, body: string(body)}
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer shadow.Close()
+
+	newApp := func(samplePercent float64) *fiber.App {
+		app := fiber.New()
+		app.Use(middleware.NewTrafficMirror(config.TrafficMirrorConfig{
+			Enabled:       true,
+			TargetURL:     shadow.URL,
+			SamplePercent: samplePercent,
+			ExcludedPaths: config.TrafficMirrorExcludedPaths,
+			MaxBodyBytes:  256,
+			Timeout:       time.Second,
+			Workers:       1,
+			QueueSize:     10,
+		}))
+		app.All("/*", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	send := func(app *fiber.App, path, contentType, body string) {
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, contentType)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer secret-token")
+		req.Header.Set(fiber.HeaderCookie, "session=abc")
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		req.Header.Set("X-Client", "mobile")
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	}
+
+	next := func() (mirrored, bool) {
+		select {
+		case m := <-received:
+			return m, true
+		case <-time.After(200 * time.Millisecond):
+			return mirrored{}, false
+		}
+	}
+
+	t.Run("should mirror a scrubbed copy of the request", func(t *testing.T) {
+		app := newApp(100)
+		send(app, "/v1/users?email=victim@example.com", fiber.MIMEApplicationJSON, `{"email":"victim@example.com"}`)
+
+		m, ok := next()
+		assert.True(t, ok)
+		assert.Equal(t, "/v1/users", m.path)
+		assert.NotContains(t, m.query, "victim@example.com")
+		assert.NotContains(t, m.body, "victim@example.com")
+		assert.Empty(t, m.header.Get(fiber.HeaderAuthorization))
+		assert.Empty(t, m.header.Get(fiber.HeaderCookie))
+		assert.Empty(t, m.header.Get("X-Forwarded-For"))
+		assert.Equal(t, "mobile", m.header.Get("X-Client"))
+		assert.Equal(t, "1", m.header.Get(middleware.TrafficMirrorHeader))
+	})
+
+	t.Run("should never mirror auth or admin requests", func(t *testing.T) {
+		app := newApp(100)
+		send(app, "/v1/auth/login", fiber.MIMEApplicationJSON, `{"password":"hunter2"}`)
+		send(app, "/v1/admin/users", fiber.MIMEApplicationJSON, `{}`)
+
+		_, ok := next()
+		assert.False(t, ok)
+	})
+
+	t.Run("should skip binary and oversized bodies", func(t *testing.T) {
+		app := newApp(100)
+		send(app, "/v1/files", "application/octet-stream", "\x00\x01")
+		send(app, "/v1/files", fiber.MIMEApplicationJSON, `{"data":"`+strings.Repeat("a", 300)+`"}`)
+
+		_, ok := next()
+		assert.False(t, ok)
+	})
+
+	t.Run("should mirror nothing at a zero sample rate", func(t *testing.T) {
+		app := newApp(0)
+		send(app, "/v1/users", fiber.MIMEApplicationJSON, `{}`)
+
+		_, ok := next()
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,41 @@
+package model_test
+
+import (
+	"app/src/model"
+	"app/src/validation"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACLEntryModel(t *testing.T) {
+	t.Run("should let stronger permissions imply weaker ones", func(t *testing.T) {
+		assert.Equal(t, []string{"read", "write", "owner"}, model.ImpliedBy(model.ACLRead))
+		assert.Equal(t, []string{"write", "owner"}, model.ImpliedBy(model.ACLWrite))
+		assert.Equal(t, []string{"owner"}, model.ImpliedBy(model.ACLOwner))
+		assert.Equal(t, []string{"comment"}, model.ImpliedBy("comment"))
+	})
+
+	t.Run("should act as the user, their role and their tags", func(t *testing.T) {
+		user := &model.User{ID: uuid.New(), Role: "user", Tags: []model.UserTag{{Tag: "beta"}}}
+		assert.Equal(t, []string{"user:" + user.ID.String(), "role:user", "tag:beta"}, user.Principals())
+	})
+
+	t.Run("should accept user, role and tag principals", func(t *testing.T) {
+		for _, principal := range []string{
+			"user:" + uuid.NewString(), "user:01ARZ3NDEKTSV4RRFFQ69G5FAV", "role:admin", "tag:beta-testers",
+		} {
+			assert.NoError(t, validate.Struct(validation.GrantACL{Principal: principal, Permission: "read"}), principal)
+		}
+
+		for _, principal := range []string{"admin", "group:staff", "user:", "role:Admin", "tag:"} {
+			assert.Error(t, validate.Struct(validation.GrantACL{Principal: principal, Permission: "read"}), principal)
+		}
+	})
+
+	t.Run("should only accept known permissions", func(t *testing.T) {
+		err := validate.Struct(validation.GrantACL{Principal: "role:user", Permission: "delete"})
+		assert.Error(t, err)
+	})
+}
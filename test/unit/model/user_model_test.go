@@ -58,9 +58,9 @@ func TestUserModel(t *testing.T) {
 
 	t.Run("Update user validation", func(t *testing.T) {
 		var updateUser = validation.UpdateUser{
-			Name:     "John Doe",
-			Email:    "johndoe@gmail.com",
-			Password: "password1",
+			Name:     validation.Some("John Doe"),
+			Email:    validation.Some("johndoe@gmail.com"),
+			Password: validation.Some("password1"),
 		}
 
 		t.Run("should correctly validate a valid user", func(t *testing.T) {
@@ -69,25 +69,25 @@ func TestUserModel(t *testing.T) {
 		})
 
 		t.Run("should throw a validation error if email is invalid", func(t *testing.T) {
-			updateUser.Email = "invalidEmail"
+			updateUser.Email = validation.Some("invalidEmail")
 			err := validate.Struct(updateUser)
 			assert.Error(t, err)
 		})
 
 		t.Run("should throw a validation error if password length is less than 8 characters", func(t *testing.T) {
-			updateUser.Password = "passwo1"
+			updateUser.Password = validation.Some("passwo1")
 			err := validate.Struct(updateUser)
 			assert.Error(t, err)
 		})
 
 		t.Run("should throw a validation error if password does not contain numbers", func(t *testing.T) {
-			updateUser.Password = "password"
+			updateUser.Password = validation.Some("password")
 			err := validate.Struct(updateUser)
 			assert.Error(t, err)
 		})
 
 		t.Run("should throw a validation error if password does not contain letters", func(t *testing.T) {
-			updateUser.Password = "11111111"
+			updateUser.Password = validation.Some("11111111")
 			err := validate.Struct(updateUser)
 			assert.Error(t, err)
 		})
@@ -0,0 +1,58 @@
+package notification_test
+
+import (
+	"app/src/notification"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	t.Run("should render emails with a subject", func(t *testing.T) {
+		message, err := notification.Render(notification.KindConfirmLogin, notification.ChannelEmail,
+			notification.Data{URL: "https://app.example.com/confirm-login?token=abc"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Confirm your sign-in", message.Subject)
+		assert.Contains(t, message.Body, "https://app.example.com/confirm-login?token=abc")
+	})
+
+	t.Run("should render text messages without a subject", func(t *testing.T) {
+		message, err := notification.Render(notification.KindPhoneVerification, notification.ChannelSMS,
+			notification.Data{Code: "042133"})
+		assert.NoError(t, err)
+		assert.Empty(t, message.Subject)
+		assert.Contains(t, message.Body, "042133")
+		assert.LessOrEqual(t, len(message.Body), 160)
+	})
+
+	t.Run("should render pushes with a title", func(t *testing.T) {
+		message, err := notification.Render(notification.KindNewLogin, notification.ChannelPush,
+			notification.Data{IP: "203.0.113.7"})
+		assert.NoError(t, err)
+		assert.Equal(t, "New sign-in", message.Subject)
+		assert.Contains(t, message.Body, "203.0.113.7")
+	})
+
+	t.Run("should not send phone verification codes by email", func(t *testing.T) {
+		assert.False(t, notification.Supports(notification.KindPhoneVerification, notification.ChannelEmail))
+
+		_, err := notification.Render(notification.KindPhoneVerification, notification.ChannelEmail, notification.Data{})
+		assert.ErrorIs(t, err, notification.ErrNoTemplate)
+	})
+
+	t.Run("should have a template for every default channel", func(t *testing.T) {
+		for kind, channels := range notification.Preferable {
+			for _, channel := range channels {
+				assert.True(t, notification.Supports(kind, channel), "%s over %s", kind, channel)
+			}
+		}
+	})
+}
+
+func TestRequired(t *testing.T) {
+	t.Run("should only fall back to email for kinds the user cannot do without", func(t *testing.T) {
+		assert.True(t, notification.Required(notification.KindConfirmLogin))
+		assert.False(t, notification.Required(notification.KindNewLogin))
+		assert.False(t, notification.Required(notification.KindPasswordChanged))
+	})
+}
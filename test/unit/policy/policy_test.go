@@ -0,0 +1,83 @@
+package policy_test
+
+import (
+	"app/src/policy"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// evaluate runs the policy against a request to /users/:userId
+func evaluate(t *testing.T, p policy.Policy, path string, subject policy.Subject) bool {
+	t.Helper()
+
+	var allowed bool
+	app := fiber.New()
+	app.Get("/users/:userId", func(c *fiber.Ctx) error {
+		allowed = p(c, subject)
+		return nil
+	})
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+	assert.NoError(t, err)
+	return allowed
+}
+
+func TestPolicies(t *testing.T) {
+	admin := policy.Subject{UserID: "admin-1", Role: "admin", Rights: []string{"getUsers", "manageUsers"}}
+	user := policy.Subject{UserID: "user-1", Role: "user"}
+	manageUser := policy.AnyOf(policy.HasRights("manageUsers"), policy.IsOwner("userId"))
+
+	t.Run("should allow subjects with the required rights", func(t *testing.T) {
+		assert.True(t, evaluate(t, manageUser, "/users/user-2", admin))
+	})
+
+	t.Run("should allow owners without the required rights", func(t *testing.T) {
+		assert.True(t, evaluate(t, manageUser, "/users/user-1", user))
+	})
+
+	t.Run("should deny other users' resources", func(t *testing.T) {
+		assert.False(t, evaluate(t, manageUser, "/users/user-2", user))
+	})
+
+	t.Run("should require every policy in AllOf", func(t *testing.T) {
+		ownerAdmin := policy.AllOf(policy.HasRole("admin"), policy.IsOwner("userId"))
+
+		assert.True(t, evaluate(t, ownerAdmin, "/users/admin-1", admin))
+		assert.False(t, evaluate(t, ownerAdmin, "/users/user-1", admin))
+	})
+
+	t.Run("should allow any authenticated subject when no rights are required", func(t *testing.T) {
+		assert.True(t, evaluate(t, policy.HasRights(), "/users/user-2", user))
+	})
+
+	t.Run("should allow subjects carrying one of the tags", func(t *testing.T) {
+		beta := policy.Subject{UserID: "user-3", Role: "user", Tags: []string{"beta"}}
+
+		assert.True(t, evaluate(t, policy.HasTag("beta", "vip"), "/users/user-3", beta))
+		assert.False(t, evaluate(t, policy.HasTag("beta", "vip"), "/users/user-1", user))
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	t.Run("should describe composed policies", func(t *testing.T) {
+		p := policy.AnyOf(policy.HasRights("getUsers"), policy.AllOf(policy.IsOwner("userId"), policy.HasTag("beta")))
+		assert.Equal(t, "anyOf(rights(getUsers), allOf(owner(userId), tag(beta)))", policy.Describe(p))
+	})
+
+	t.Run("should tell policies built by the same constructor apart", func(t *testing.T) {
+		assert.Equal(t, "rights(getUsers)", policy.Describe(policy.HasRights("getUsers")))
+		assert.Equal(t, "rights(manageUsers)", policy.Describe(policy.HasRights("manageUsers")))
+		assert.Equal(t, "role(admin)", policy.Describe(policy.HasRole("admin")))
+	})
+
+	t.Run("should describe a policy without rights as requiring a login", func(t *testing.T) {
+		assert.Equal(t, "authenticated", policy.Describe(policy.HasRights()))
+	})
+
+	t.Run("should describe other policies as custom", func(t *testing.T) {
+		assert.Equal(t, "custom", policy.Describe(func(*fiber.Ctx, policy.Subject) bool { return true }))
+	})
+}
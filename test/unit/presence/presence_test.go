@@ -0,0 +1,107 @@
+package presence_test
+
+import (
+	"app/src/clock"
+	"app/src/config"
+	"app/src/presence"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.PresenceConfig{
+		HeartbeatInterval: 25 * time.Second,
+		TTL:               60 * time.Second,
+		LastSeenRetention: 24 * time.Hour,
+	}
+
+	newTracker := func() (*presence.Tracker, *clock.Mock, *[]presence.Event) {
+		clk := clock.NewMock(time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC))
+		// Without Redis the tracker keeps presence in memory
+		tracker := presence.NewTracker(nil, cfg, clk)
+		events := &[]presence.Event{}
+		tracker.Subscribe(func(event presence.Event) {
+			*events = append(*events, event)
+		})
+		return tracker, clk, events
+	}
+
+	statuses := func(events []presence.Event) []string {
+		result := make([]string, len(events))
+		for i, event := range events {
+			result[i] = event.Status
+		}
+		return result
+	}
+
+	t.Run("should announce the first connection and the last disconnection", func(t *testing.T) {
+		tracker, _, events := newTracker()
+
+		phone, err := tracker.Connect(ctx, "user-1")
+		assert.NoError(t, err)
+		laptop, err := tracker.Connect(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.NotEqual(t, phone, laptop)
+
+		assert.NoError(t, tracker.Disconnect(ctx, "user-1", phone))
+		assert.Equal(t, []string{presence.StatusOnline}, statuses(*events))
+
+		assert.NoError(t, tracker.Disconnect(ctx, "user-1", laptop))
+		assert.Equal(t, []string{presence.StatusOnline, presence.StatusOffline}, statuses(*events))
+		assert.Equal(t, "user-1", (*events)[1].UserID)
+	})
+
+	t.Run("should report online users and when they were last seen", func(t *testing.T) {
+		tracker, clk, _ := newTracker()
+
+		connID, err := tracker.Connect(ctx, "user-1")
+		assert.NoError(t, err)
+		clk.Advance(time.Minute)
+		assert.NoError(t, tracker.Heartbeat(ctx, "user-1", connID))
+
+		presences, err := tracker.Lookup(ctx, []string{"user-1", "user-2"})
+		assert.NoError(t, err)
+		assert.True(t, presences[0].Online)
+		assert.Equal(t, clk.Now(), *presences[0].LastSeen)
+		assert.Equal(t, presence.Presence{UserID: "user-2"}, presences[1])
+	})
+
+	t.Run("should take users offline once their heartbeats stop", func(t *testing.T) {
+		tracker, clk, events := newTracker()
+
+		_, err := tracker.Connect(ctx, "user-1")
+		assert.NoError(t, err)
+		seenAt := clk.Now()
+
+		clk.Advance(30 * time.Second)
+		assert.NoError(t, tracker.Sweep(ctx))
+		assert.Len(t, *events, 1)
+
+		clk.Advance(31 * time.Second)
+		presences, err := tracker.Lookup(ctx, []string{"user-1"})
+		assert.NoError(t, err)
+		assert.False(t, presences[0].Online)
+		assert.Equal(t, seenAt, *presences[0].LastSeen)
+
+		assert.NoError(t, tracker.Sweep(ctx))
+		assert.Equal(t, []string{presence.StatusOnline, presence.StatusOffline}, statuses(*events))
+	})
+
+	t.Run("should announce a user only once when a heartbeat follows a sweep", func(t *testing.T) {
+		tracker, clk, events := newTracker()
+
+		connID, err := tracker.Connect(ctx, "user-1")
+		assert.NoError(t, err)
+		clk.Advance(2 * time.Minute)
+		assert.NoError(t, tracker.Sweep(ctx))
+		assert.NoError(t, tracker.Heartbeat(ctx, "user-1", connID))
+		assert.NoError(t, tracker.Heartbeat(ctx, "user-1", connID))
+
+		assert.Equal(t,
+			[]string{presence.StatusOnline, presence.StatusOffline, presence.StatusOnline}, statuses(*events))
+	})
+}
@@ -0,0 +1,214 @@
+package push_test
+
+import (
+	"app/src/push"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFCM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	assert.NoError(t, err)
+
+	var tokenRequests atomic.Int32
+	var sent map[string]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/token":
+			tokenRequests.Add(1)
+			_, _ = w.Write([]byte(`{"access_token": "ya29.access", "token_type": "Bearer", "expires_in": 3600}`))
+		case r.URL.Path == "/v1/projects/demo-project/messages:send":
+			assert.Equal(t, "Bearer ya29.access", r.Header.Get("Authorization"))
+			body, _ := io.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(body, &sent))
+
+			if sent["message"]["token"] == "stale-token" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error": {"code": 404, "status": "NOT_FOUND", "message": "Requested entity was not found.",
+					"details": [{"@type": "type.googleapis.com/google.firebase.fcm.v1.FcmError", "errorCode": "UNREGISTERED"}]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"name": "projects/demo-project/messages/1"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": {"code": 500, "status": "INTERNAL", "message": "Internal error"}}`))
+		}
+	}))
+	defer server.Close()
+
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "demo-project",
+		"client_email": "push@demo-project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})),
+		"token_uri":    server.URL + "/token",
+	})
+	assert.NoError(t, err)
+
+	t.Run("should send with a reused access token", func(t *testing.T) {
+		sender, err := push.NewFCM(server.URL, credentials)
+		assert.NoError(t, err)
+
+		msg := push.Message{Title: "New sign-in", Body: "Hi", Data: map[string]string{"kind": "new_login"}}
+		assert.NoError(t, sender.Send(context.Background(), "device-token", msg))
+		assert.NoError(t, sender.Send(context.Background(), "device-token", msg))
+
+		assert.Equal(t, int32(1), tokenRequests.Load())
+		assert.Equal(t, "device-token", sent["message"]["token"])
+		assert.Equal(t, map[string]any{"title": "New sign-in", "body": "Hi"}, sent["message"]["notification"])
+	})
+
+	t.Run("should flag unregistered tokens", func(t *testing.T) {
+		sender, err := push.NewFCM(server.URL, credentials)
+		assert.NoError(t, err)
+
+		err = sender.Send(context.Background(), "stale-token", push.Message{Title: "Hi"})
+		assert.ErrorIs(t, err, push.ErrInvalidToken)
+	})
+
+	t.Run("should not flag other failures", func(t *testing.T) {
+		sender, err := push.NewFCM(server.URL+"/unavailable", credentials)
+		assert.NoError(t, err)
+
+		err = sender.Send(context.Background(), "device-token", push.Message{Title: "Hi"})
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, push.ErrInvalidToken))
+	})
+
+	t.Run("should reject credentials without a project", func(t *testing.T) {
+		_, err := push.NewFCM(server.URL, []byte(`{"type": "service_account"}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestAPNs(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	assert.NoError(t, err)
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+
+	var header http.Header
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &payload))
+
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "uninstalled":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason": "Unregistered", "timestamp": 1760000000000}`))
+		case "malformed":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason": "BadDeviceToken"}`))
+		case "busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"reason": "TooManyRequests"}`))
+		}
+	}))
+	defer server.Close()
+
+	sender, err := push.NewAPNs(server.URL, keyPEM, "ABC123DEFG", "TEAM123456", "com.example.app")
+	assert.NoError(t, err)
+
+	t.Run("should send alerts with a provider token signed by the key", func(t *testing.T) {
+		msg := push.Message{Title: "Password changed", Body: "Hi", Data: map[string]string{"kind": "password_changed"}}
+		assert.NoError(t, sender.Send(context.Background(), "device-token", msg))
+
+		assert.Equal(t, "com.example.app", header.Get("apns-topic"))
+		assert.Equal(t, "alert", header.Get("apns-push-type"))
+		assert.Equal(t, "password_changed", payload["kind"])
+		assert.Equal(t, map[string]any{"title": "Password changed", "body": "Hi"}, payload["aps"].(map[string]any)["alert"])
+
+		bearer := strings.TrimPrefix(header.Get("Authorization"), "bearer ")
+		token, err := jwt.Parse(bearer, func(_ *jwt.Token) (interface{}, error) {
+			return &ecKey.PublicKey, nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}))
+		assert.NoError(t, err)
+		assert.Equal(t, "ABC123DEFG", token.Header["kid"])
+		issuer, _ := token.Claims.GetIssuer()
+		assert.Equal(t, "TEAM123456", issuer)
+	})
+
+	t.Run("should flag tokens that are gone or malformed", func(t *testing.T) {
+		assert.ErrorIs(t, sender.Send(context.Background(), "uninstalled", push.Message{}), push.ErrInvalidToken)
+		assert.ErrorIs(t, sender.Send(context.Background(), "malformed", push.Message{}), push.ErrInvalidToken)
+	})
+
+	t.Run("should not flag other failures", func(t *testing.T) {
+		err := sender.Send(context.Background(), "busy", push.Message{})
+		assert.ErrorContains(t, err, "TooManyRequests")
+		assert.False(t, errors.Is(err, push.ErrInvalidToken))
+	})
+
+	t.Run("should reject keys that are not EC keys", func(t *testing.T) {
+		_, err := push.NewAPNs(server.URL, "not a key", "ABC123DEFG", "TEAM123456", "com.example.app")
+		assert.Error(t, err)
+	})
+}
+
+type recordingSender struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *recordingSender) Platform() string {
+	return push.PlatformFCM
+}
+
+func (s *recordingSender) Send(_ context.Context, token string, _ push.Message) error {
+	s.mu.Lock()
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+
+	// Long enough for the rest of the batch to start
+	time.Sleep(10 * time.Millisecond)
+
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+
+	if token == "stale" {
+		return push.ErrInvalidToken
+	}
+	return nil
+}
+
+func TestSendBatch(t *testing.T) {
+	t.Run("should send at most a batch at a time and return each token's error in order", func(t *testing.T) {
+		sender := &recordingSender{}
+
+		tokens := []string{"a", "stale", "c", "d", "e"}
+		errs := push.SendBatch(context.Background(), sender, tokens, push.Message{}, 2)
+
+		assert.Len(t, errs, 5)
+		assert.NoError(t, errs[0])
+		assert.ErrorIs(t, errs[1], push.ErrInvalidToken)
+		assert.NoError(t, errs[4])
+		assert.Equal(t, 2, sender.maxInFlight)
+	})
+}
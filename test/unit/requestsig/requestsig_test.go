@@ -0,0 +1,83 @@
+package requestsig_test
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/requestsig"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	key := requestsig.Key("psk_secret")
+
+	newVerifier := func() *requestsig.Verifier {
+		return requestsig.NewVerifier(cache.NewMemoryStore(), 5*time.Minute, clock.NewMock(now))
+	}
+
+	signed := func(at time.Time, nonce string) (requestsig.Request, string) {
+		r := requestsig.Request{
+			Timestamp: strconv.FormatInt(at.Unix(), 10),
+			Nonce:     nonce,
+			Method:    "POST",
+			URI:       "/v1/partner/orders?dry=1",
+			Body:      []byte(`{"amount":100}`),
+		}
+		return r, requestsig.Sign(key, r)
+	}
+
+	t.Run("should accept a fresh signed request once", func(t *testing.T) {
+		verifier := newVerifier()
+		r, signature := signed(now.Add(-time.Minute), "nonce-0123456789ab")
+
+		assert.NoError(t, verifier.Verify(context.Background(), "partner-1", key, r, signature))
+		assert.ErrorIs(t, verifier.Verify(context.Background(), "partner-1", key, r, signature), requestsig.ErrReplayed)
+	})
+
+	t.Run("should keep nonces apart per partner", func(t *testing.T) {
+		verifier := newVerifier()
+		r, signature := signed(now, "nonce-0123456789ab")
+
+		assert.NoError(t, verifier.Verify(context.Background(), "partner-1", key, r, signature))
+		assert.NoError(t, verifier.Verify(context.Background(), "partner-2", key, r, signature))
+	})
+
+	t.Run("should reject a tampered body", func(t *testing.T) {
+		r, signature := signed(now, "nonce-0123456789ab")
+		r.Body = []byte(`{"amount":1000}`)
+
+		assert.ErrorIs(t, newVerifier().Verify(context.Background(), "partner-1", key, r, signature), requestsig.ErrInvalidSignature)
+	})
+
+	t.Run("should reject a request signed with another key", func(t *testing.T) {
+		r, _ := signed(now, "nonce-0123456789ab")
+		signature := requestsig.Sign(requestsig.Key("psk_other"), r)
+
+		assert.ErrorIs(t, newVerifier().Verify(context.Background(), "partner-1", key, r, signature), requestsig.ErrInvalidSignature)
+	})
+
+	t.Run("should reject timestamps outside the skew either way", func(t *testing.T) {
+		r, signature := signed(now.Add(-6*time.Minute), "nonce-0123456789ab")
+		assert.ErrorIs(t, newVerifier().Verify(context.Background(), "partner-1", key, r, signature), requestsig.ErrStale)
+
+		r, signature = signed(now.Add(6*time.Minute), "nonce-0123456789ab")
+		assert.ErrorIs(t, newVerifier().Verify(context.Background(), "partner-1", key, r, signature), requestsig.ErrStale)
+	})
+
+	t.Run("should reject short nonces", func(t *testing.T) {
+		r, signature := signed(now, "short")
+		assert.ErrorIs(t, newVerifier().Verify(context.Background(), "partner-1", key, r, signature), requestsig.ErrInvalidSignature)
+	})
+
+	t.Run("should fail closed without a nonce store", func(t *testing.T) {
+		verifier := requestsig.NewVerifier(nil, 5*time.Minute, clock.NewMock(now))
+		r, signature := signed(now, "nonce-0123456789ab")
+
+		assert.ErrorIs(t, verifier.Verify(context.Background(), "partner-1", key, r, signature), requestsig.ErrNonceStoreUnavailable)
+	})
+}
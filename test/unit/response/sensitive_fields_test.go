@@ -0,0 +1,106 @@
+package response_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// srcDir is the application source root relative to this test package
+const srcDir = "../../../src"
+
+// sensitiveFields must never appear in a response body
+var sensitiveFields = []string{"Password", "PasswordHash", "TokenHash", "Secret"}
+
+// handlerDirs contain code that writes response bodies
+var handlerDirs = []string{"controller", "middleware", "middleware/cache", "utils"}
+
+func parseDir(t *testing.T, dir string) []*ast.File {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+
+	var files []*ast.File
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, 0)
+		assert.NoError(t, err)
+		files = append(files, file)
+	}
+	return files
+}
+
+// referencesModel reports whether a type expression mentions the model package
+func referencesModel(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "model" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func TestResponseTypesHideSensitiveFields(t *testing.T) {
+	for _, dir := range []string{"response", "response/example"} {
+		for _, file := range parseDir(t, filepath.Join(srcDir, dir)) {
+			ast.Inspect(file, func(n ast.Node) bool {
+				spec, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+				structType, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					return true
+				}
+
+				for _, field := range structType.Fields.List {
+					assert.Falsef(t, referencesModel(field.Type),
+						"%s.%s embeds a model type; map it to a response DTO instead", dir, spec.Name.Name)
+					for _, name := range field.Names {
+						assert.NotContainsf(t, sensitiveFields, name.Name,
+							"%s.%s exposes sensitive field %s", dir, spec.Name.Name, name.Name)
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+func TestHandlersOnlySerializeResponseTypes(t *testing.T) {
+	for _, dir := range handlerDirs {
+		for _, file := range parseDir(t, filepath.Join(srcDir, dir)) {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) == 0 {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "JSON" {
+					return true
+				}
+
+				lit, ok := call.Args[0].(*ast.CompositeLit)
+				if !assert.Truef(t, ok, "%s: JSON() must be given a response type literal", dir) {
+					return true
+				}
+				assert.Falsef(t, referencesModel(lit.Type), "%s: JSON() must not serialize model types", dir)
+				return true
+			})
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package retention_test
+
+import (
+	"app/src/config"
+	"app/src/retention"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRetentionConfig(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("RETENTION_DAYS", "")
+		config.LoadRetentionConfig()
+	})
+
+	t.Run("should keep the default retention of every policy", func(t *testing.T) {
+		viper.Set("RETENTION_DAYS", "")
+		config.LoadRetentionConfig()
+
+		assert.Equal(t, map[string]int{
+			config.RetentionAuditLogs:     365,
+			config.RetentionLoginHistory:  90,
+			config.RetentionNotifications: 30,
+			config.RetentionExports:       7,
+			config.RetentionAnalytics:     400,
+		}, config.Retention.Days)
+		assert.Equal(t, 1000, config.Retention.BatchSize)
+	})
+
+	t.Run("should apply overrides and keep rows forever for 0 days", func(t *testing.T) {
+		viper.Set("RETENTION_DAYS", "audit_logs:730, notifications:0, unknown:5, exports:-1, login_history")
+		config.LoadRetentionConfig()
+
+		assert.Equal(t, map[string]int{
+			config.RetentionAuditLogs:    730,
+			config.RetentionLoginHistory: 90,
+			config.RetentionExports:      7,
+			config.RetentionAnalytics:    400,
+		}, config.Retention.Days)
+	})
+}
+
+func TestPolicies(t *testing.T) {
+	t.Run("should return configured policies in purge order with their cutoff", func(t *testing.T) {
+		policies := retention.Policies(config.RetentionConfig{Days: map[string]int{
+			config.RetentionExports:   7,
+			config.RetentionAuditLogs: 365,
+		}})
+
+		assert.Len(t, policies, 2)
+		assert.Equal(t, config.RetentionAuditLogs, policies[0].Name)
+		assert.Equal(t, "audit_logs", policies[0].Table)
+		assert.Equal(t, config.RetentionExports, policies[1].Name)
+		assert.Equal(t, "bulk_jobs", policies[1].Table)
+
+		now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC), policies[1].Cutoff(now))
+	})
+
+	t.Run("should split the audit log between audit and login history policies", func(t *testing.T) {
+		policies := retention.Policies(config.RetentionConfig{Days: map[string]int{
+			config.RetentionAuditLogs:    365,
+			config.RetentionLoginHistory: 90,
+		}})
+
+		assert.Len(t, policies, 2)
+		assert.Equal(t, policies[0].Table, policies[1].Table)
+		assert.Contains(t, policies[0].Condition, "NOT LIKE 'login.%'")
+		assert.Contains(t, policies[1].Condition, "LIKE 'login.%'")
+	})
+}
@@ -0,0 +1,83 @@
+package revision_test
+
+import (
+	"app/src/model"
+	"app/src/revision"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	timezone := "Asia/Jakarta"
+	before := &model.User{ID: uuid.New(), Name: "Before", Email: "user@example.com", Password: "hash-1", Role: "user"}
+
+	t.Run("should keep only the changed columns", func(t *testing.T) {
+		after := *before
+		after.Name = "After"
+		after.Timezone = &timezone
+
+		changes := revision.Diff(before, &after)
+		assert.Equal(t, map[string]model.FieldChange{
+			"name":     {From: "Before", To: "After"},
+			"timezone": {From: (*string)(nil), To: &timezone},
+		}, changes)
+	})
+
+	t.Run("should redact columns hidden from JSON", func(t *testing.T) {
+		after := *before
+		after.Password = "hash-2"
+
+		changes := revision.Diff(before, &after)
+		assert.Equal(t, map[string]model.FieldChange{"password": {Redacted: true}}, changes)
+	})
+
+	t.Run("should ignore timestamps", func(t *testing.T) {
+		after := *before
+		after.UpdatedAt = after.UpdatedAt.Add(1)
+
+		assert.Empty(t, revision.Diff(before, &after))
+	})
+
+	t.Run("should record every column of a new user", func(t *testing.T) {
+		changes := revision.Created(before)
+		assert.Equal(t, model.FieldChange{To: "Before"}, changes["name"])
+		assert.Equal(t, model.FieldChange{Redacted: true}, changes["password"])
+		assert.Contains(t, changes, "timezone")
+		assert.NotContains(t, changes, "updated_at")
+	})
+}
+
+func TestState(t *testing.T) {
+	history := []map[string]model.FieldChange{
+		{"name": {To: "First"}, "password": {Redacted: true}},
+		{"name": {From: "First", To: "Second"}},
+		{"email": {From: "old@example.com", To: "new@example.com"}},
+		{"name": {From: "Second", To: "Third"}},
+	}
+
+	t.Run("should take the latest value up to the revision", func(t *testing.T) {
+		assert.Equal(t, map[string]any{"name": "Second", "email": "old@example.com"}, revision.State(history, 1))
+	})
+
+	t.Run("should take the values right after the last revision", func(t *testing.T) {
+		assert.Equal(t, map[string]any{"name": "Third", "email": "new@example.com"}, revision.State(history, 3))
+	})
+}
+
+func TestContext(t *testing.T) {
+	t.Run("should carry the actor and restored revision", func(t *testing.T) {
+		actorID, revisionID := uuid.New(), uuid.New()
+		ctx := revision.WithRestoredFrom(revision.WithActor(context.Background(), actorID), revisionID)
+
+		assert.Equal(t, &actorID, revision.Actor(ctx))
+		assert.Equal(t, &revisionID, revision.RestoredFrom(ctx))
+	})
+
+	t.Run("should have neither outside a request", func(t *testing.T) {
+		assert.Nil(t, revision.Actor(context.Background()))
+		assert.Nil(t, revision.RestoredFrom(context.Background()))
+	})
+}
@@ -0,0 +1,105 @@
+package revocation_test
+
+import (
+	"app/src/revocation"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should revoke tokens issued before the revocation", func(t *testing.T) {
+		bus := revocation.NewBus(nil, time.Minute)
+		issued := time.Now().Add(-10 * time.Second)
+
+		assert.False(t, bus.IsRevoked("user-1", issued))
+		assert.NoError(t, bus.Publish(ctx, "user-1", revocation.ReasonRoleChanged))
+
+		assert.True(t, bus.IsRevoked("user-1", issued))
+		assert.True(t, bus.IsRevoked("user-1", time.Time{}), "tokens without iat are treated as oldest")
+		assert.False(t, bus.IsRevoked("user-1", time.Now().Add(2*time.Second)))
+		assert.False(t, bus.IsRevoked("user-2", issued))
+	})
+
+	t.Run("should forget revocations after the retention period", func(t *testing.T) {
+		bus := revocation.NewBus(nil, 20*time.Millisecond)
+		assert.NoError(t, bus.Publish(ctx, "user-1", revocation.ReasonDeleted))
+
+		time.Sleep(30 * time.Millisecond)
+
+		assert.False(t, bus.IsRevoked("user-1", time.Now().Add(-time.Hour)))
+	})
+
+	t.Run("should close tracked connections of the revoked user only", func(t *testing.T) {
+		bus := revocation.NewBus(nil, time.Minute)
+
+		var closedA, closedB, closedOther bool
+		bus.Track("user-1", func() { closedA = true })
+		untrack := bus.Track("user-1", func() { closedB = true })
+		bus.Track("user-2", func() { closedOther = true })
+		untrack()
+
+		assert.NoError(t, bus.Publish(ctx, "user-1", revocation.ReasonDeleted))
+
+		assert.True(t, closedA)
+		assert.False(t, closedB, "untracked connections are not closed")
+		assert.False(t, closedOther)
+	})
+
+	t.Run("should notify subscribers", func(t *testing.T) {
+		bus := revocation.NewBus(nil, time.Minute)
+
+		var events []revocation.Event
+		unsubscribe := bus.Subscribe(func(event revocation.Event) { events = append(events, event) })
+
+		assert.NoError(t, bus.Publish(ctx, "user-1", revocation.ReasonDeleted))
+		unsubscribe()
+		assert.NoError(t, bus.Publish(ctx, "user-2", revocation.ReasonDeleted))
+
+		assert.Len(t, events, 1)
+		assert.Equal(t, "user-1", events[0].UserID)
+		assert.Equal(t, revocation.ReasonDeleted, events[0].Reason)
+	})
+
+	t.Run("should revoke a single session", func(t *testing.T) {
+		bus := revocation.NewBus(nil, time.Minute)
+
+		var closed bool
+		bus.Track("user-1", func() { closed = true })
+		var events []revocation.Event
+		bus.Subscribe(func(event revocation.Event) { events = append(events, event) })
+
+		assert.NoError(t, bus.PublishSession(ctx, "user-1", "session-1", revocation.ReasonEvicted))
+
+		assert.True(t, bus.IsSessionRevoked("session-1"))
+		assert.False(t, bus.IsSessionRevoked("session-2"))
+		assert.False(t, bus.IsSessionRevoked(""))
+		assert.False(t, bus.IsRevoked("user-1", time.Now().Add(-time.Hour)), "the user's other sessions stay valid")
+		assert.False(t, closed)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "session-1", events[0].SessionID)
+	})
+
+	t.Run("should forget session revocations after the retention period", func(t *testing.T) {
+		bus := revocation.NewBus(nil, 20*time.Millisecond)
+		assert.NoError(t, bus.PublishSession(ctx, "user-1", "session-1", revocation.ReasonEvicted))
+
+		time.Sleep(30 * time.Millisecond)
+
+		assert.False(t, bus.IsSessionRevoked("session-1"))
+	})
+
+	t.Run("should be a no-op on a nil bus", func(t *testing.T) {
+		var bus *revocation.Bus
+
+		assert.NoError(t, bus.Publish(ctx, "user-1", revocation.ReasonDeleted))
+		assert.False(t, bus.IsRevoked("user-1", time.Time{}))
+		assert.NoError(t, bus.PublishSession(ctx, "user-1", "session-1", revocation.ReasonEvicted))
+		assert.False(t, bus.IsSessionRevoked("session-1"))
+		bus.Track("user-1", func() {})()
+	})
+}
@@ -0,0 +1,205 @@
+package risk_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/risk"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func location(country, asn string, lat, lon float64) risk.Location {
+	return risk.Location{Country: country, ASN: asn, Latitude: &lat, Longitude: &lon}
+}
+
+func newConfig(rules map[string]int) config.RiskConfig {
+	return config.RiskConfig{
+		Enabled:        true,
+		Rules:          rules,
+		ChallengeScore: 40,
+		BlockScore:     80,
+		MaxTravelSpeed: 900,
+		VelocityMax:    3,
+		VelocityWindow: time.Minute,
+		ConfirmExp:     time.Minute,
+		HistoryTTL:     time.Hour,
+	}
+}
+
+func TestEngine(t *testing.T) {
+	jakarta := location("ID", "AS7713", -6.2, 106.8)
+	bandung := location("ID", "AS7713", -6.9, 107.6)
+	london := location("GB", "AS2856", 51.5, -0.1)
+	now := time.Now().UTC()
+
+	t.Run("should allow every login when disabled", func(t *testing.T) {
+		engine := risk.NewEngine(config.RiskConfig{}, nil, nil, nil)
+		assert.Nil(t, engine)
+
+		assessment := engine.Assess(risk.Attempt{UserID: "user", Location: london})
+		assert.Equal(t, risk.DecisionAllow, assessment.Decision)
+	})
+
+	t.Run("should not flag the first login of a user", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		engine := risk.NewEngine(newConfig(map[string]int{config.RiskRuleNewCountry: 50}), store, nil, nil)
+
+		assessment := engine.Assess(risk.Attempt{UserID: "user", Location: london, Time: now})
+		assert.Equal(t, risk.DecisionAllow, assessment.Decision)
+		assert.Empty(t, assessment.Signals)
+	})
+
+	t.Run("should challenge a login from a new country", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		engine := risk.NewEngine(newConfig(map[string]int{
+			config.RiskRuleNewCountry: 40,
+			config.RiskRuleNewASN:     10,
+		}), store, nil, nil)
+
+		engine.RecordSuccess(risk.Attempt{UserID: "user", Location: jakarta, Time: now.Add(-48 * time.Hour)})
+
+		assessment := engine.Assess(risk.Attempt{UserID: "user", Location: bandung, Time: now})
+		assert.Equal(t, risk.DecisionAllow, assessment.Decision)
+
+		assessment = engine.Assess(risk.Attempt{UserID: "user", Location: london, Time: now})
+		assert.Equal(t, risk.DecisionChallenge, assessment.Decision)
+		assert.Equal(t, 50, assessment.Score)
+		assert.Equal(t, []string{config.RiskRuleNewASN, config.RiskRuleNewCountry}, assessment.Signals)
+	})
+
+	t.Run("should block impossible travel", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		engine := risk.NewEngine(newConfig(map[string]int{config.RiskRuleImpossibleTravel: 80}), store, nil, nil)
+
+		engine.RecordSuccess(risk.Attempt{UserID: "user", Location: jakarta, Time: now.Add(-time.Hour)})
+
+		assessment := engine.Assess(risk.Attempt{UserID: "user", Location: london, Time: now})
+		assert.Equal(t, risk.DecisionBlock, assessment.Decision)
+
+		// ~12,000 km in a day is a plausible flight
+		assessment = engine.Assess(risk.Attempt{UserID: "user", Location: london, Time: now.Add(23 * time.Hour)})
+		assert.Equal(t, risk.DecisionAllow, assessment.Decision)
+	})
+
+	t.Run("should flag credential stuffing velocity from one IP", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		engine := risk.NewEngine(newConfig(map[string]int{config.RiskRuleVelocity: 80}), store, nil, nil)
+
+		for i := 0; i < 3; i++ {
+			engine.RecordFailure("10.0.0.1")
+		}
+
+		assert.Equal(t, risk.DecisionBlock, engine.Assess(risk.Attempt{UserID: "user", IP: "10.0.0.1"}).Decision)
+		assert.Equal(t, risk.DecisionAllow, engine.Assess(risk.Attempt{UserID: "user", IP: "10.0.0.2"}).Decision)
+	})
+
+	t.Run("should flag Tor exit nodes", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("# exit nodes\n185.220.101.1\nnot-an-ip\n\n2001:db8::1\n"))
+		}))
+		defer server.Close()
+
+		tor := risk.NewTorList(server.URL)
+		assert.NoError(t, tor.Refresh(context.Background()))
+		assert.Equal(t, 2, tor.Len())
+
+		engine := risk.NewEngine(newConfig(map[string]int{config.RiskRuleTorExit: 50}), nil, nil, tor)
+
+		assert.Equal(t, risk.DecisionChallenge, engine.Assess(risk.Attempt{IP: "185.220.101.1"}).Decision)
+		assert.Equal(t, risk.DecisionChallenge, engine.Assess(risk.Attempt{IP: "2001:0db8::1"}).Decision)
+		assert.Equal(t, risk.DecisionAllow, engine.Assess(risk.Attempt{IP: "8.8.8.8"}).Decision)
+	})
+
+	t.Run("should keep the old Tor list when a refresh fails", func(t *testing.T) {
+		healthy := true
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if !healthy {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("185.220.101.1\n"))
+		}))
+		defer server.Close()
+
+		tor := risk.NewTorList(server.URL)
+		assert.NoError(t, tor.Refresh(context.Background()))
+
+		healthy = false
+		assert.Error(t, tor.Refresh(context.Background()))
+		assert.True(t, tor.Contains("185.220.101.1"))
+	})
+
+	t.Run("should learn a held location only once it is released", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		engine := risk.NewEngine(newConfig(map[string]int{config.RiskRuleNewCountry: 40}), store, nil, nil)
+
+		engine.RecordSuccess(risk.Attempt{UserID: "user", Location: jakarta, Time: now})
+		engine.Hold("token", risk.Attempt{UserID: "user", Location: london, Time: now})
+
+		attempt, ok := engine.Release("token")
+		assert.True(t, ok)
+		assert.Equal(t, "GB", attempt.Location.Country)
+
+		_, ok = engine.Release("token")
+		assert.False(t, ok)
+
+		engine.RecordSuccess(attempt)
+		assert.Equal(t, risk.DecisionAllow, engine.Assess(risk.Attempt{UserID: "user", Location: london}).Decision)
+	})
+}
+
+func TestHeaderResolver(t *testing.T) {
+	resolver := risk.NewHeaderResolver(config.RiskConfig{
+		CountryHeader:   "CF-IPCountry",
+		ASNHeader:       "X-ASN",
+		LatitudeHeader:  "CF-IPLatitude",
+		LongitudeHeader: "CF-IPLongitude",
+	})
+
+	resolve := func(headers map[string]string) risk.Location {
+		var location risk.Location
+		app := fiber.New()
+		app.Get("/", func(c *fiber.Ctx) error {
+			location = resolver.Resolve(c)
+			return nil
+		})
+
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		_, err := app.Test(req)
+		assert.NoError(t, err)
+		return location
+	}
+
+	t.Run("should read country, ASN and coordinates", func(t *testing.T) {
+		location := resolve(map[string]string{
+			"CF-IPCountry":   "gb",
+			"X-ASN":          "AS2856",
+			"CF-IPLatitude":  "51.5",
+			"CF-IPLongitude": "-0.1",
+		})
+
+		assert.Equal(t, "GB", location.Country)
+		assert.Equal(t, "AS2856", location.ASN)
+		assert.True(t, location.HasCoordinates())
+	})
+
+	t.Run("should treat unknown and Tor country codes as unknown", func(t *testing.T) {
+		assert.Empty(t, resolve(map[string]string{"CF-IPCountry": "XX"}).Country)
+		assert.Empty(t, resolve(map[string]string{"CF-IPCountry": "T1"}).Country)
+		assert.False(t, resolve(map[string]string{"CF-IPLatitude": "51.5"}).HasCoordinates())
+	})
+}
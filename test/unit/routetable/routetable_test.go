@@ -0,0 +1,91 @@
+package routetable_test
+
+import (
+	"app/src/middleware"
+	"app/src/response"
+	"app/src/routetable"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func next(c *fiber.Ctx) error {
+	return c.Next()
+}
+
+func handler(c *fiber.Ctx) error {
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func find(t *testing.T, routes []response.Route, method, path string) response.Route {
+	t.Helper()
+	for _, route := range routes {
+		if route.Method == method && route.Path == path {
+			return route
+		}
+	}
+	t.Fatalf("route %s %s not listed", method, path)
+	return response.Route{}
+}
+
+func TestTable(t *testing.T) {
+	app := fiber.New()
+	app.Use(routetable.Describe(func(c *fiber.Ctx) error { return c.Next() }, routetable.Info{
+		Kind: routetable.KindCache,
+		DetailFor: func(method, _ string) string {
+			if method == fiber.MethodGet {
+				return "ttl 1m0s"
+			}
+			return "off"
+		},
+	}))
+
+	api := app.Group("/api")
+	api.Use("/admin", middleware.OnListener("internal"))
+	api.Use(routetable.Describe(func(c *fiber.Ctx) error { return c.Next() }, routetable.Info{
+		Kind: routetable.KindRateLimit, Detail: "10 per 1m0s",
+	}))
+
+	api.Get("/reports", middleware.RequirePlan("pro"), handler)
+	api.Post("/reports", handler)
+	api.Get("/admin/stats", handler)
+	// Registered after the routes, so it never runs for them
+	api.Use(next)
+
+	routes := routetable.Table(app)
+
+	t.Run("should list the middleware registered before a route, then its own", func(t *testing.T) {
+		route := find(t, routes, fiber.MethodGet, "/api/reports")
+
+		assert.Len(t, route.Middleware, 3)
+		assert.Equal(t, routetable.KindCache, route.Middleware[0].Kind)
+		assert.Equal(t, routetable.KindRateLimit, route.Middleware[1].Kind)
+		assert.Equal(t, response.RouteStep{Name: "middleware.RequirePlan", Kind: routetable.KindAuth, Detail: "plan(pro)"},
+			route.Middleware[2])
+		assert.Equal(t, "routetable_test.handler", route.Handler)
+	})
+
+	t.Run("should collect access, rate limit and cache policies", func(t *testing.T) {
+		route := find(t, routes, fiber.MethodGet, "/api/reports")
+		assert.Equal(t, []string{"plan(pro)"}, route.Access)
+		assert.Equal(t, []string{"10 per 1m0s"}, route.RateLimits)
+		assert.Equal(t, "ttl 1m0s", route.Cache)
+
+		route = find(t, routes, fiber.MethodPost, "/api/reports")
+		assert.Empty(t, route.Access)
+		assert.Equal(t, "off", route.Cache)
+	})
+
+	t.Run("should only apply middleware to paths under its prefix", func(t *testing.T) {
+		assert.Equal(t, []string{"listener(internal)"}, find(t, routes, fiber.MethodGet, "/api/admin/stats").Access)
+		assert.NotContains(t, find(t, routes, fiber.MethodGet, "/api/reports").Access, "listener(internal)")
+	})
+
+	t.Run("should leave out the HEAD routes added for GET", func(t *testing.T) {
+		for _, route := range routes {
+			assert.NotEqual(t, fiber.MethodHead, route.Method)
+		}
+		assert.Len(t, routes, 3)
+	})
+}
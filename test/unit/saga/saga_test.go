@@ -0,0 +1,97 @@
+package saga_test
+
+import (
+	"app/src/saga"
+	"app/src/utils"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	// step records what it does and undoes, failing when told to
+	step := func(log *[]string, name string, fail bool) saga.Step {
+		return saga.Step{
+			Name: name,
+			Do: func(context.Context) error {
+				*log = append(*log, "do "+name)
+				if fail {
+					return errFailed
+				}
+				return nil
+			},
+			Undo: func(ctx context.Context) error {
+				*log = append(*log, "undo "+name)
+				return ctx.Err()
+			},
+		}
+	}
+
+	t.Run("should run every step when none fails", func(t *testing.T) {
+		var log []string
+
+		err := saga.Run(context.Background(), utils.Log, "test", step(&log, "a", false), step(&log, "b", false))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"do a", "do b"}, log)
+	})
+
+	t.Run("should compensate the steps done in reverse order", func(t *testing.T) {
+		var log []string
+
+		err := saga.Run(context.Background(), utils.Log, "test",
+			step(&log, "a", false), step(&log, "b", false), step(&log, "c", true), step(&log, "d", false))
+
+		assert.ErrorIs(t, err, errFailed)
+		assert.Equal(t, []string{"do a", "do b", "do c", "undo b", "undo a"}, log)
+	})
+
+	t.Run("should skip steps without a compensation", func(t *testing.T) {
+		var log []string
+		noUndo := step(&log, "b", false)
+		noUndo.Undo = nil
+
+		err := saga.Run(context.Background(), utils.Log, "test", step(&log, "a", false), noUndo, step(&log, "c", true))
+
+		assert.ErrorIs(t, err, errFailed)
+		assert.Equal(t, []string{"do a", "do b", "do c", "undo a"}, log)
+	})
+
+	t.Run("should compensate after the context is cancelled", func(t *testing.T) {
+		var log []string
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelled := saga.Step{Name: "b", Do: func(context.Context) error {
+			cancel()
+			return context.Canceled
+		}}
+		var undoErr error
+		first := step(&log, "a", false)
+		first.Undo = func(ctx context.Context) error {
+			undoErr = ctx.Err()
+			return nil
+		}
+
+		err := saga.Run(ctx, utils.Log, "test", first, cancelled)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NoError(t, undoErr)
+	})
+
+	t.Run("should keep compensating after a compensation fails", func(t *testing.T) {
+		var log []string
+		broken := step(&log, "b", false)
+		broken.Undo = func(context.Context) error {
+			log = append(log, "undo b")
+			return errors.New("cannot undo")
+		}
+
+		err := saga.Run(context.Background(), utils.Log, "test", step(&log, "a", false), broken, step(&log, "c", true))
+
+		assert.ErrorIs(t, err, errFailed)
+		assert.Equal(t, []string{"do a", "do b", "do c", "undo b", "undo a"}, log)
+	})
+}
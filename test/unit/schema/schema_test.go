@@ -0,0 +1,100 @@
+package schema_test
+
+import (
+	"app/src/schema"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrations(t *testing.T) {
+	t.Run("should embed every migration and mark the breaking ones", func(t *testing.T) {
+		migrations, err := schema.Migrations()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(20240929085103), migrations[0].Version)
+		assert.Equal(t, "create-table-users", migrations[0].Name)
+
+		breaking := map[int64]string{}
+		for _, migration := range migrations {
+			if migration.Breaking != "" {
+				breaking[migration.Version] = migration.Breaking
+			}
+		}
+		assert.Equal(t, map[int64]string{
+			20261016220000: "IDs are no longer generated by the database",
+			20261016234000: "service accounts need a service user",
+		}, breaking)
+	})
+}
+
+func TestParse(t *testing.T) {
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+
+	t.Run("should read breaking migrations from their inserts", func(t *testing.T) {
+		migrations, err := schema.Parse(fstest.MapFS{
+			"2_drop-legacy.up.sql": file("ALTER TABLE users DROP COLUMN legacy;\n" +
+				"INSERT INTO schema_breaking_changes (version, reason) VALUES\n" +
+				"    (2, 'users.legacy is dropped, the app''s old reads fail')\nON CONFLICT (version) DO NOTHING;"),
+			"2_drop-legacy.down.sql": file("ALTER TABLE users ADD COLUMN legacy TEXT;"),
+			"1_create-users.up.sql":  file("CREATE TABLE users(id UUID PRIMARY KEY);"),
+			"migrations.go":          file("package migrations"),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []schema.Migration{
+			{Version: 1, Name: "create-users"},
+			{Version: 2, Name: "drop-legacy", Breaking: "users.legacy is dropped, the app's old reads fail"},
+		}, migrations)
+	})
+
+	t.Run("should refuse marking a later or unknown migration as breaking", func(t *testing.T) {
+		_, err := schema.Parse(fstest.MapFS{
+			"1_a.up.sql": file("INSERT INTO schema_breaking_changes (version, reason) VALUES (2, 'later');"),
+			"2_b.up.sql": file("SELECT 1;"),
+		})
+		assert.ErrorContains(t, err, "marks the later migration 2")
+
+		_, err = schema.Parse(fstest.MapFS{
+			"2_b.up.sql": file("INSERT INTO schema_breaking_changes (version, reason) VALUES (1, 'gone');"),
+		})
+		assert.ErrorContains(t, err, "migration 1 is marked as breaking but does not exist")
+	})
+}
+
+func TestCompare(t *testing.T) {
+	migrations := []schema.Migration{
+		{Version: 1}, {Version: 2}, {Version: 3, Breaking: "drops a column"}, {Version: 4}, {Version: 5},
+	}
+
+	t.Run("should accept the same version and databases ahead up to a breaking migration", func(t *testing.T) {
+		assert.True(t, schema.Compare(2, 2, migrations).Compatible())
+		assert.True(t, schema.Compare(1, 2, migrations).Compatible())
+		assert.True(t, schema.Compare(3, 5, migrations).Compatible())
+	})
+
+	t.Run("should refuse databases behind the build", func(t *testing.T) {
+		compatibility := schema.Compare(4, 3, migrations)
+		assert.False(t, compatibility.Compatible())
+		assert.Contains(t, compatibility.Reason, "run the migrations first")
+	})
+
+	t.Run("should refuse databases past a breaking migration", func(t *testing.T) {
+		compatibility := schema.Compare(2, 4, migrations)
+		assert.False(t, compatibility.Compatible())
+		assert.Contains(t, compatibility.Reason, "past migration 3 which breaks builds written for 2: drops a column")
+	})
+
+	t.Run("should tell how far each migration reaches", func(t *testing.T) {
+		ranges := schema.Matrix(migrations)
+
+		oldest := make([]int64, len(ranges))
+		newest := make([]int64, len(ranges))
+		for i, row := range ranges {
+			oldest[i], newest[i] = row.OldestCode, row.NewestDatabase
+		}
+		assert.Equal(t, []int64{1, 1, 3, 3, 3}, oldest)
+		assert.Equal(t, []int64{2, 2, 0, 0, 0}, newest)
+	})
+}
@@ -0,0 +1,78 @@
+package serializer_test
+
+import (
+	"app/src/model"
+	"app/src/serializer"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSerializer(t *testing.T) {
+	username := "alice"
+	user := &model.User{
+		ID:            uuid.New(),
+		Name:          "Alice",
+		Username:      &username,
+		Email:         "alice@example.com",
+		Password:      "hashed",
+		Role:          "user",
+		VerifiedEmail: true,
+		IsActive:      true,
+	}
+
+	t.Run("should hide admin-only fields from other roles", func(t *testing.T) {
+		result := serializer.User.One(user, "user", nil)
+
+		assert.Equal(t, "Alice", result["name"])
+		assert.Equal(t, &username, result["username"])
+		assert.NotContains(t, result, "verified_email")
+		assert.NotContains(t, result, "is_active")
+		assert.NotContains(t, result, "created_at")
+		assert.NotContains(t, result, "password")
+	})
+
+	t.Run("should show admin-only fields to admins", func(t *testing.T) {
+		result := serializer.User.One(user, "admin", nil)
+
+		assert.Equal(t, true, result["verified_email"])
+		assert.Equal(t, true, result["is_active"])
+		assert.Contains(t, result, "created_at")
+	})
+
+	t.Run("should return only the selected fields", func(t *testing.T) {
+		result := serializer.User.One(user, "admin", []string{"id", "email"})
+
+		assert.Len(t, result, 2)
+		assert.Equal(t, user.ID, result["id"])
+		assert.Equal(t, "alice@example.com", result["email"])
+	})
+
+	t.Run("should not reveal restricted fields when selected explicitly", func(t *testing.T) {
+		result := serializer.User.One(user, "user", []string{"name", "verified_email"})
+
+		assert.Equal(t, map[string]interface{}{"name": "Alice"}, result)
+	})
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/", func(c *fiber.Ctx) error {
+			fields, err := serializer.User.Fields(c)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, err.Error())
+			}
+			return c.JSON(fields)
+		})
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/?fields=id,password", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/?fields=id,+name", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
@@ -0,0 +1,184 @@
+package service_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/service"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestAppleOAuth(t *testing.T) {
+	secret, ttl := config.JWTSecret, config.OAuthStateTTL
+	config.JWTSecret, config.OAuthStateTTL = "apple-oauth-secret", time.Minute
+	defer func() { config.JWTSecret, config.OAuthStateTTL = secret, ttl }()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	p8 := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	// The fake token endpoint only accepts a client secret signed with the .p8 key
+	var clientSecret *jwt.Token
+	claims := jwt.MapClaims{}
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		clientSecret, err = jwt.Parse(r.PostForm.Get("client_secret"), func(*jwt.Token) (any, error) {
+			return &key.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"ES256"}))
+		if err != nil || r.PostForm.Get("client_id") != "com.example.web" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusBadRequest)
+			return
+		}
+
+		idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("apple"))
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access", "token_type": "Bearer", "expires_in": 3600, "id_token": idToken,
+		})
+	}))
+	defer tokenServer.Close()
+
+	store := cache.NewMemoryStore()
+	defer store.Close()
+
+	appleOAuth := service.NewAppleOAuthService(store, stubUserService{}, config.AppleConfig{
+		ClientID:    "com.example.web",
+		TeamID:      "TEAM123456",
+		KeyID:       "KEY1234567",
+		RedirectURL: "https://app.example/v1/auth/apple-callback",
+		PrivateKey:  p8,
+	}, oauth2.Endpoint{AuthURL: "https://appleid.example/auth/authorize", TokenURL: tokenServer.URL})
+
+	app := fiber.New()
+	app.Get("/apple", func(c *fiber.Ctx) error {
+		authURL, err := appleOAuth.AuthURL(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(authURL)
+	})
+	app.Post("/callback", func(c *fiber.Ctx) error {
+		user, err := appleOAuth.Login(c)
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"name": user.Name, "email": user.Email, "sub": *user.AppleID})
+	})
+
+	begin := func() (url.Values, *http.Cookie) {
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/apple", nil))
+		assert.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, err)
+
+		authURL, err := url.Parse(string(body))
+		assert.NoError(t, err)
+		return authURL.Query(), res.Cookies()[0]
+	}
+
+	callback := func(form url.Values, cookie *http.Cookie) (int, map[string]string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/callback", strings.NewReader(form.Encode()))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		req.AddCookie(cookie)
+		res, err := app.Test(req, 5000)
+		assert.NoError(t, err)
+
+		user := map[string]string{}
+		_ = json.NewDecoder(res.Body).Decode(&user)
+		return res.StatusCode, user
+	}
+
+	validClaims := func(nonce string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": "https://appleid.apple.com", "aud": "com.example.web", "sub": "001234.abcd",
+			"exp": time.Now().Add(time.Hour).Unix(), "email": "Jane@privaterelay.appleid.com",
+			"email_verified": "true", "nonce": nonce,
+		}
+	}
+
+	t.Run("should request a form_post response with a cross-site state cookie", func(t *testing.T) {
+		params, cookie := begin()
+		assert.Equal(t, "form_post", params.Get("response_mode"))
+		assert.Equal(t, "name email", params.Get("scope"))
+		assert.NotEmpty(t, params.Get("nonce"))
+		assert.Equal(t, http.SameSiteNoneMode, cookie.SameSite)
+		assert.True(t, cookie.Secure)
+	})
+
+	t.Run("should sign the client secret and pass the first-authorization name on", func(t *testing.T) {
+		params, cookie := begin()
+		claims = validClaims(params.Get("nonce"))
+
+		status, user := callback(url.Values{
+			"state": {params.Get("state")},
+			"code":  {"abc"},
+			"user":  {`{"name":{"firstName":"Jane","lastName":"Appleseed"},"email":"jane@privaterelay.appleid.com"}`},
+		}, cookie)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, map[string]string{
+			"name": "Jane Appleseed", "email": "jane@privaterelay.appleid.com", "sub": "001234.abcd",
+		}, user)
+
+		if assert.NotNil(t, clientSecret) {
+			assert.Equal(t, "KEY1234567", clientSecret.Header["kid"])
+			secretClaims := clientSecret.Claims.(jwt.MapClaims)
+			assert.Equal(t, "TEAM123456", secretClaims["iss"])
+			assert.Equal(t, "com.example.web", secretClaims["sub"])
+		}
+	})
+
+	t.Run("should sign in without a name on later authorizations", func(t *testing.T) {
+		params, cookie := begin()
+		claims = validClaims(params.Get("nonce"))
+
+		status, user := callback(url.Values{"state": {params.Get("state")}, "code": {"abc"}}, cookie)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, "", user["name"])
+		assert.Equal(t, "001234.abcd", user["sub"])
+	})
+
+	t.Run("should reject an ID token from another issuer or with the wrong nonce", func(t *testing.T) {
+		params, cookie := begin()
+		claims = validClaims(params.Get("nonce"))
+		claims["iss"] = "https://accounts.google.com"
+		status, _ := callback(url.Values{"state": {params.Get("state")}, "code": {"abc"}}, cookie)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+
+		params, cookie = begin()
+		claims = validClaims("other-nonce")
+		status, _ = callback(url.Values{"state": {params.Get("state")}, "code": {"abc"}}, cookie)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+	})
+
+	t.Run("should report Apple sign-in as not configured without a key", func(t *testing.T) {
+		unconfigured := service.NewAppleOAuthService(store, stubUserService{}, config.AppleConfig{}, service.AppleEndpoint)
+		app := fiber.New()
+		app.Get("/apple", func(c *fiber.Ctx) error {
+			_, err := unconfigured.AuthURL(c)
+			return err
+		})
+
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/apple", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, res.StatusCode)
+	})
+}
@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSuppressions suppresses a fixed set of addresses
+type stubSuppressions struct {
+	service.EmailSuppressionService
+	emails map[string]bool
+}
+
+func (s *stubSuppressions) IsSuppressed(_ context.Context, email string) (bool, error) {
+	return s.emails[email], nil
+}
+
+func TestSendEmailGuards(t *testing.T) {
+	previous := config.EmailSending
+	t.Cleanup(func() { config.EmailSending = previous })
+
+	store := cache.NewMemoryStore()
+	t.Cleanup(func() { _ = store.Close() })
+	clk := clock.NewMock(time.Date(2026, time.March, 14, 10, 0, 0, 0, time.UTC))
+	emails := service.NewEmailService(store, &stubSuppressions{emails: map[string]bool{"gone@example.com": true}}, clk)
+
+	// A cancelled context gives up on SMTP at once, so sends that pass the guards fail without waiting on a server
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	passes := func(to string) bool {
+		err := emails.SendEmail(cancelled, to, "Hello", "Body")
+		return err != nil && !errors.Is(err, service.ErrEmailThrottled)
+	}
+
+	t.Run("should skip suppressed recipients", func(t *testing.T) {
+		config.EmailSending = config.EmailSendingConfig{RecipientLimit: 1, RecipientWindow: time.Hour}
+
+		for range 3 {
+			assert.NoError(t, emails.SendEmail(cancelled, "gone@example.com", "Hello", "Body"))
+		}
+	})
+
+	t.Run("should throttle a recipient until the window passes", func(t *testing.T) {
+		config.EmailSending = config.EmailSendingConfig{RecipientLimit: 2, RecipientWindow: time.Hour}
+
+		assert.True(t, passes("user@example.com"))
+		assert.True(t, passes("User@Example.com"))
+		assert.ErrorIs(t, emails.SendEmail(cancelled, "user@example.com", "Hello", "Body"), service.ErrEmailThrottled)
+		assert.True(t, passes("other@example.com"))
+
+		clk.Advance(time.Hour)
+		assert.True(t, passes("user@example.com"))
+	})
+
+	t.Run("should throttle all recipients together", func(t *testing.T) {
+		config.EmailSending = config.EmailSendingConfig{GlobalLimit: 1, GlobalWindow: time.Minute}
+		clk.Advance(time.Minute)
+
+		assert.True(t, passes("first@example.com"))
+		assert.ErrorIs(t, emails.SendEmail(cancelled, "second@example.com", "Hello", "Body"), service.ErrEmailThrottled)
+	})
+}
@@ -0,0 +1,188 @@
+package service_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/model"
+	"app/src/service"
+	"app/src/validation"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+// stubUserService returns the OAuth user without touching the database
+type stubUserService struct {
+	service.UserService
+}
+
+func (stubUserService) CreateGoogleUser(_ *fiber.Ctx, req *validation.GoogleLogin) (*model.User, error) {
+	return &model.User{Name: req.Name, Email: req.Email, VerifiedEmail: req.VerifiedEmail}, nil
+}
+
+func (stubUserService) CreateAppleUser(_ *fiber.Ctx, req *validation.AppleLogin) (*model.User, error) {
+	return &model.User{Name: req.Name, Email: req.Email, VerifiedEmail: req.VerifiedEmail, AppleID: &req.Subject}, nil
+}
+
+func TestGoogleOAuth(t *testing.T) {
+	secret, ttl, domains := config.JWTSecret, config.OAuthStateTTL, config.GoogleAllowedDomains
+	config.JWTSecret, config.OAuthStateTTL = "google-oauth-secret", time.Minute
+	defer func() { config.JWTSecret, config.OAuthStateTTL, config.GoogleAllowedDomains = secret, ttl, domains }()
+
+	// The fake token endpoint checks the PKCE verifier and returns an ID token with these claims
+	var challenge string
+	claims := jwt.MapClaims{}
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("google"))
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access", "token_type": "Bearer", "expires_in": 3600, "id_token": idToken,
+		})
+	}))
+	defer tokenServer.Close()
+
+	store := cache.NewMemoryStore()
+	defer store.Close()
+
+	googleOAuth := service.NewGoogleOAuthService(store, stubUserService{}, oauth2.Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost/callback",
+		Endpoint:     oauth2.Endpoint{AuthURL: "https://accounts.example/auth", TokenURL: tokenServer.URL},
+	})
+
+	app := fiber.New()
+	app.Get("/google", func(c *fiber.Ctx) error {
+		authURL, err := googleOAuth.AuthURL(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(authURL)
+	})
+	app.Get("/callback", func(c *fiber.Ctx) error {
+		user, err := googleOAuth.Login(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(user.Email)
+	})
+
+	// begin starts a login and returns the consent URL params and the state cookie
+	begin := func() (url.Values, string) {
+		res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/google", nil))
+		assert.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, err)
+
+		authURL, err := url.Parse(string(body))
+		assert.NoError(t, err)
+		params := authURL.Query()
+		challenge = params.Get("code_challenge")
+		return params, strings.Split(res.Header.Get(fiber.HeaderSetCookie), ";")[0]
+	}
+
+	callback := func(state, cookie string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodGet, "/callback?code=abc&state="+url.QueryEscape(state), nil)
+		req.Header.Set(fiber.HeaderCookie, cookie)
+		res, err := app.Test(req, 5000)
+		assert.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, err)
+		return res.StatusCode, string(body)
+	}
+
+	validClaims := func(nonce string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": "https://accounts.google.com", "aud": "client-id", "exp": time.Now().Add(time.Hour).Unix(),
+			"email": "jane@corp.example", "email_verified": true, "name": "Jane", "nonce": nonce, "hd": "corp.example",
+		}
+	}
+
+	t.Run("should send a PKCE challenge and nonce and sign the user in", func(t *testing.T) {
+		params, cookie := begin()
+		assert.Equal(t, "S256", params.Get("code_challenge_method"))
+		assert.NotEmpty(t, params.Get("nonce"))
+
+		claims = validClaims(params.Get("nonce"))
+		status, body := callback(params.Get("state"), cookie)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, "jane@corp.example", body)
+	})
+
+	t.Run("should reject a replayed state", func(t *testing.T) {
+		params, cookie := begin()
+		claims = validClaims(params.Get("nonce"))
+
+		status, _ := callback(params.Get("state"), cookie)
+		assert.Equal(t, fiber.StatusOK, status)
+
+		status, _ = callback(params.Get("state"), cookie)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+	})
+
+	t.Run("should reject a state that does not match the cookie or its signature", func(t *testing.T) {
+		params, cookie := begin()
+		claims = validClaims(params.Get("nonce"))
+
+		status, _ := callback(params.Get("state"), "oauth_state=other")
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+
+		stateID, _, _ := strings.Cut(params.Get("state"), ".")
+		status, _ = callback(stateID+".forged", "oauth_state="+stateID+".forged")
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+
+		status, _ = callback(params.Get("state"), cookie)
+		assert.Equal(t, fiber.StatusOK, status)
+	})
+
+	t.Run("should reject an ID token with the wrong nonce or audience", func(t *testing.T) {
+		params, cookie := begin()
+		claims = validClaims("other-nonce")
+		status, _ := callback(params.Get("state"), cookie)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+
+		params, cookie = begin()
+		claims = validClaims(params.Get("nonce"))
+		claims["aud"] = "another-client"
+		status, _ = callback(params.Get("state"), cookie)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+	})
+
+	t.Run("should only allow the configured hosted domains", func(t *testing.T) {
+		config.GoogleAllowedDomains = []string{"corp.example"}
+		defer func() { config.GoogleAllowedDomains = nil }()
+
+		params, cookie := begin()
+		assert.Equal(t, "corp.example", params.Get("hd"))
+
+		claims = validClaims(params.Get("nonce"))
+		delete(claims, "hd")
+		status, _ := callback(params.Get("state"), cookie)
+		assert.Equal(t, fiber.StatusForbidden, status)
+
+		params, cookie = begin()
+		claims = validClaims(params.Get("nonce"))
+		status, _ = callback(params.Get("state"), cookie)
+		assert.Equal(t, fiber.StatusOK, status)
+	})
+}
@@ -0,0 +1,49 @@
+package service_test
+
+import (
+	"app/src/model"
+	"app/src/notification"
+	"app/src/service"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedNotifications struct {
+	service.NotificationService
+	kinds []string
+	data  []notification.Data
+}
+
+func (n *recordedNotifications) NotifyUser(_ *fiber.Ctx, _ uuid.UUID, kind string, data notification.Data) {
+	n.kinds = append(n.kinds, kind)
+	n.data = append(n.data, data)
+}
+
+func TestNotifyingAuditService(t *testing.T) {
+	audit := &recordedAudit{}
+	notifications := &recordedNotifications{}
+	notifying := service.NewNotifyingAuditService(audit, notifications)
+	userID := uuid.New()
+
+	app := fiber.New()
+	app.Post("/events", func(c *fiber.Ctx) error {
+		notifying.Record(c, &userID, model.AuditActionLoginSucceeded, map[string]any{})
+		notifying.Record(c, &userID, model.AuditActionPasswordChanged, map[string]any{})
+		notifying.Record(c, &userID, model.AuditActionEmailSent, map[string]any{})
+		notifying.Record(c, nil, model.AuditActionLoginSucceeded, map[string]any{})
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	t.Run("should record every entry and notify users of their security events", func(t *testing.T) {
+		_, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/events", nil))
+		assert.NoError(t, err)
+
+		assert.Len(t, audit.actions, 4)
+		assert.Equal(t, []string{notification.KindNewLogin, notification.KindPasswordChanged}, notifications.kinds)
+		assert.Equal(t, "0.0.0.0", notifications.data[0].IP)
+	})
+}
@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"app/src/clock"
+	"app/src/config"
+	"app/src/presence"
+	"app/src/response"
+	"app/src/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptedSocket delivers its messages, advancing the clock before each, then fails like a closed socket
+type scriptedSocket struct {
+	clock    *clock.Mock
+	gaps     []time.Duration
+	written  []any
+	deadline time.Time
+	closed   bool
+}
+
+func (s *scriptedSocket) ReadMessage() (int, []byte, error) {
+	if len(s.gaps) == 0 {
+		return 0, nil, errors.New("closed")
+	}
+	s.clock.Advance(s.gaps[0])
+	s.gaps = s.gaps[1:]
+	return 1, []byte(`{"type":"heartbeat"}`), nil
+}
+
+func (s *scriptedSocket) WriteJSON(v interface{}) error {
+	s.written = append(s.written, v)
+	return nil
+}
+
+func (s *scriptedSocket) SetReadDeadline(t time.Time) error {
+	s.deadline = t
+	return nil
+}
+
+func (s *scriptedSocket) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestPresenceServe(t *testing.T) {
+	cfg := config.PresenceConfig{
+		HeartbeatInterval: 20 * time.Second,
+		TTL:               50 * time.Second,
+		LastSeenRetention: time.Hour,
+		MaxLookup:         100,
+	}
+	original := config.Presence
+	config.Presence = cfg
+	t.Cleanup(func() { config.Presence = original })
+
+	t.Run("should keep the user online until the socket closes", func(t *testing.T) {
+		clk := clock.NewMock(time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC))
+		tracker := presence.NewTracker(nil, cfg, clk)
+		presenceService := service.NewPresenceService(nil, nil, tracker, nil, clk)
+		userID := uuid.New()
+
+		events := []presence.Event{}
+		tracker.Subscribe(func(event presence.Event) { events = append(events, event) })
+
+		// The second message comes too soon after the first to count as a heartbeat
+		socket := &scriptedSocket{clock: clk, gaps: []time.Duration{15 * time.Second, 2 * time.Second}}
+		presenceService.Serve(context.Background(), socket, userID)
+
+		assert.True(t, socket.closed)
+		assert.Equal(t, []any{response.PresenceWelcome{Type: "welcome", HeartbeatInterval: 20}}, socket.written)
+		assert.Equal(t, clk.Now().Add(cfg.TTL), socket.deadline)
+		assert.Len(t, events, 2)
+		assert.Equal(t, presence.StatusOffline, events[1].Status)
+
+		presences, err := tracker.Lookup(context.Background(), []string{userID.String()})
+		assert.NoError(t, err)
+		assert.False(t, presences[0].Online)
+		assert.Equal(t, clk.Now(), *presences[0].LastSeen)
+	})
+}
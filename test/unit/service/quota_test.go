@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/service"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaConsume(t *testing.T) {
+	cfg := config.QuotaConfig{
+		Enabled:         true,
+		Plans:           map[string]config.QuotaLimits{"free": {Daily: 2, Monthly: 3}, "pro": {Monthly: 100}},
+		DefaultPlan:     "free",
+		PersistInterval: time.Minute,
+	}
+	ctx := context.Background()
+	now := time.Date(2026, time.March, 14, 23, 59, 0, 0, time.UTC)
+
+	newService := func() (service.QuotaService, *cache.MemoryStore, *clock.Mock) {
+		store := cache.NewMemoryStore()
+		t.Cleanup(func() { _ = store.Close() })
+		// Without a database there are no overrides and counters live in the store only
+		clk := clock.NewMock(now)
+		return service.NewQuotaService(nil, nil, store, nil, nil, nil, cfg, clk), store, clk
+	}
+
+	t.Run("should reject requests over the daily quota with 429 without counting them", func(t *testing.T) {
+		quotas, store, _ := newService()
+		user := &model.User{ID: uuid.New(), Role: "user", Plan: "free"}
+
+		usage, err := quotas.Consume(ctx, user, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), usage[0].Remaining())
+
+		_, err = quotas.Consume(ctx, user, nil)
+		assert.NoError(t, err)
+
+		usage, err = quotas.Consume(ctx, user, nil)
+		assert.ErrorIs(t, err, service.ErrDailyQuotaExceeded)
+		assert.Equal(t, model.QuotaDaily, usage[0].Period)
+		assert.Equal(t, int64(0), usage[0].Remaining())
+		assert.Equal(t, time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC), usage[0].Reset)
+
+		data, err := store.Get(cache.GetQuotaKey(model.UserQuotaSubject(user.ID), model.QuotaDaily, "2026-03-14"))
+		assert.NoError(t, err)
+		assert.Equal(t, "2", string(data))
+	})
+
+	t.Run("should start a new daily quota at midnight UTC", func(t *testing.T) {
+		quotas, _, clk := newService()
+		user := &model.User{ID: uuid.New(), Role: "user", Plan: "free"}
+
+		for i := 0; i < 2; i++ {
+			_, err := quotas.Consume(ctx, user, nil)
+			assert.NoError(t, err)
+		}
+		_, err := quotas.Consume(ctx, user, nil)
+		assert.ErrorIs(t, err, service.ErrDailyQuotaExceeded)
+
+		// The monthly quota of 3 now runs out first
+		clk.Advance(time.Minute)
+		_, err = quotas.Consume(ctx, user, nil)
+		assert.NoError(t, err)
+		_, err = quotas.Consume(ctx, user, nil)
+		assert.ErrorIs(t, err, service.ErrMonthlyQuotaExceeded)
+	})
+
+	t.Run("should report the monthly quota with 402 once it is used up", func(t *testing.T) {
+		quotas, store, _ := newService()
+		user := &model.User{ID: uuid.New(), Role: "user", Plan: "free"}
+
+		_, err := store.IncrBy(ctx, cache.GetQuotaKey(model.UserQuotaSubject(user.ID), model.QuotaMonthly, "2026-03-01"), 3, time.Hour)
+		assert.NoError(t, err)
+
+		_, err = quotas.Consume(ctx, user, nil)
+		assert.ErrorIs(t, err, service.ErrMonthlyQuotaExceeded)
+	})
+
+	t.Run("should use the default plan for unknown plans and only limit limited periods", func(t *testing.T) {
+		quotas, _, _ := newService()
+
+		usage, err := quotas.Consume(ctx, &model.User{ID: uuid.New(), Role: "user", Plan: "legacy"}, nil)
+		assert.NoError(t, err)
+		assert.Len(t, usage, 2)
+		assert.Equal(t, int64(2), usage[0].Limit)
+
+		usage, err = quotas.Consume(ctx, &model.User{ID: uuid.New(), Role: "user", Plan: "pro"}, nil)
+		assert.NoError(t, err)
+		assert.Len(t, usage, 1)
+		assert.Equal(t, model.QuotaMonthly, usage[0].Period)
+	})
+
+	t.Run("should exempt callers who manage quotas", func(t *testing.T) {
+		quotas, _, _ := newService()
+		admin := &model.User{ID: uuid.New(), Role: "admin", Plan: "free"}
+
+		for i := 0; i < 5; i++ {
+			_, err := quotas.Consume(ctx, admin, nil)
+			assert.NoError(t, err)
+		}
+	})
+}
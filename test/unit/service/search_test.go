@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"app/src/search"
+	"app/src/service"
+	"app/src/validation"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingEngine returns fixed hits and keeps the last query it was given
+type recordingEngine struct {
+	hits  []search.Hit
+	query *search.Query
+}
+
+func (e *recordingEngine) Search(_ context.Context, query search.Query) ([]search.Hit, error) {
+	e.query = &query
+	return e.hits, nil
+}
+
+func TestSearch(t *testing.T) {
+	userID := uuid.New()
+	engine := &recordingEngine{hits: []search.Hit{
+		{Type: search.TypeUser, ID: userID.String(), UserID: &userID, Title: "Jane", Detail: "jane@example.com",
+			Score: 1.5, OccurredAt: time.Date(2026, time.March, 14, 10, 0, 0, 0, time.UTC)},
+	}}
+	searchService := service.NewSearchService(validation.Validator(), engine)
+
+	run := func(params *validation.Search) (int, error) {
+		var hits int
+		var searchErr error
+		app := fiber.New()
+		app.Get("/", func(c *fiber.Ctx) error {
+			results, err := searchService.Search(c, params)
+			hits, searchErr = len(results), err
+			return nil
+		})
+		_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.NoError(t, err)
+		return hits, searchErr
+	}
+
+	t.Run("should pass the query to the engine and return its hits", func(t *testing.T) {
+		engine.query = nil
+
+		hits, err := run(&validation.Search{Query: "jane", Types: []string{"user", "session"}, Limit: 20})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, hits)
+		assert.Equal(t, &search.Query{Text: "jane", Types: []string{"user", "session"}, Limit: 20}, engine.query)
+	})
+
+	t.Run("should reject invalid queries before reaching the engine", func(t *testing.T) {
+		for _, params := range []*validation.Search{
+			{Query: "", Limit: 20},
+			{Query: "50%", Limit: 20},
+			{Query: "jane", Types: []string{"invoice"}, Limit: 20},
+			{Query: "jane", Types: []string{"user", "user"}, Limit: 20},
+			{Query: "jane", Limit: 101},
+		} {
+			engine.query = nil
+
+			_, err := run(params)
+			assert.Error(t, err, "%+v", params)
+			assert.Nil(t, engine.query)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package service_test
+
+import (
+	"app/src/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderSecurityDigest(t *testing.T) {
+	from := time.Date(2026, 10, 12, 8, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	t.Run("should list every section of the digest", func(t *testing.T) {
+		body, err := service.RenderSecurityDigest(&service.SecurityDigest{
+			From:              from,
+			To:                to,
+			FailedLogins:      42,
+			BlockedLogins:     3,
+			TopFailedAccounts: []service.DigestAccount{{Email: "victim@example.com", Count: 30}},
+			RoleChanges: []service.DigestRoleChange{
+				{Email: "ops@example.com", From: "user", To: "admin", CreatedAt: from.Add(time.Hour)},
+			},
+			NewAdmins: []service.DigestRoleChange{
+				{Email: "ops@example.com", From: "user", To: "admin", CreatedAt: from.Add(time.Hour)},
+				{Email: "new@example.com", CreatedAt: from.Add(2 * time.Hour)},
+			},
+		}, time.UTC)
+		assert.NoError(t, err)
+
+		assert.Contains(t, body, "Security digest for 2026-10-12 08:00 UTC to 2026-10-19 08:00 UTC")
+		assert.Contains(t, body, "Failed logins: 42")
+		assert.Contains(t, body, "Blocked logins: 3")
+		assert.Contains(t, body, "  - victim@example.com: 30")
+		assert.Contains(t, body, "  - ops@example.com: user -> admin (2026-10-12 09:00 UTC)")
+		assert.Contains(t, body, "  - ops@example.com (promoted from user)")
+		assert.Contains(t, body, "  - new@example.com (created)")
+	})
+
+	t.Run("should render a quiet week", func(t *testing.T) {
+		body, err := service.RenderSecurityDigest(&service.SecurityDigest{From: from, To: to}, time.UTC)
+		assert.NoError(t, err)
+
+		assert.Contains(t, body, "Failed logins: 0")
+		assert.Contains(t, body, "Role changes: 0")
+		assert.Contains(t, body, "New admin accounts: 0")
+		assert.NotContains(t, body, "Most targeted accounts")
+	})
+
+	t.Run("should show dates in the recipient's timezone", func(t *testing.T) {
+		jakarta, err := time.LoadLocation("Asia/Jakarta")
+		assert.NoError(t, err)
+
+		body, err := service.RenderSecurityDigest(&service.SecurityDigest{
+			From:        from,
+			To:          to,
+			RoleChanges: []service.DigestRoleChange{{Email: "ops@example.com", From: "user", To: "admin", CreatedAt: from}},
+		}, jakarta)
+		assert.NoError(t, err)
+
+		assert.Contains(t, body, "Security digest for 2026-10-12 15:00 WIB to 2026-10-19 15:00 WIB")
+		assert.Contains(t, body, "  - ops@example.com: user -> admin (2026-10-12 15:00 WIB)")
+	})
+}
@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/service"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionActivityTouch(t *testing.T) {
+	cfg := config.SessionActivityConfig{Enabled: true, WriteInterval: time.Hour, FlushInterval: time.Minute}
+	now := time.Date(2026, time.March, 14, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should buffer activity in the cache store", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		// Buffered writes never reach the database, so none is needed
+		activity := service.NewSessionActivityService(nil, store, cfg, clock.NewMock(now))
+
+		sessionID := uuid.NewString()
+		activity.Touch(context.Background(), sessionID)
+
+		data, err := store.Get(cache.GetSessionActivityKey(sessionID))
+		assert.NoError(t, err)
+
+		unix, err := strconv.ParseInt(string(data), 10, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, now.Unix(), unix)
+	})
+
+	t.Run("should write a session at most once per interval", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		clk := clock.NewMock(now)
+		activity := service.NewSessionActivityService(nil, store, cfg, clk)
+
+		sessionID := uuid.NewString()
+		activity.Touch(context.Background(), sessionID)
+		assert.NoError(t, store.Delete(cache.GetSessionActivityKey(sessionID)))
+
+		clk.Advance(59 * time.Minute)
+		activity.Touch(context.Background(), sessionID)
+
+		data, err := store.Get(cache.GetSessionActivityKey(sessionID))
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+
+		clk.Advance(time.Minute)
+		activity.Touch(context.Background(), sessionID)
+
+		data, err = store.Get(cache.GetSessionActivityKey(sessionID))
+		assert.NoError(t, err)
+		assert.Equal(t, strconv.FormatInt(clk.Now().Unix(), 10), string(data))
+	})
+
+	t.Run("should not track anything when disabled", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		activity := service.NewSessionActivityService(nil, store, config.SessionActivityConfig{}, nil)
+
+		sessionID := uuid.NewString()
+		activity.Touch(context.Background(), sessionID)
+
+		data, err := store.Get(cache.GetSessionActivityKey(sessionID))
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+	})
+}
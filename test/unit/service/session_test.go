@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"app/src/cache"
+	"app/src/config"
+	"app/src/model"
+	"app/src/service"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionServiceBatch(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{
+		{ID: uuid.New(), Name: "One", Email: "one@example.com", Role: "user", IsActive: true},
+		{ID: uuid.New(), Name: "Two", Email: "two@example.com", Role: "admin", IsActive: true},
+	}
+
+	t.Run("should read back the sessions cached together and leave out misses", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		sessions := service.NewSessionService(store)
+
+		assert.NoError(t, sessions.CacheUserSessions(ctx, users))
+
+		unknown := uuid.NewString()
+		found, err := sessions.GetUserSessions(ctx, []string{users[0].ID.String(), unknown, users[1].ID.String()})
+		assert.NoError(t, err)
+		assert.Len(t, found, 2)
+		assert.Equal(t, "one@example.com", found[users[0].ID.String()].Email)
+		assert.Equal(t, "admin", found[users[1].ID.String()].Role)
+		assert.NotContains(t, found, unknown)
+	})
+
+	t.Run("should drop all the given sessions", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		sessions := service.NewSessionService(store)
+
+		assert.NoError(t, sessions.CacheUserSessions(ctx, users))
+		assert.NoError(t, sessions.InvalidateSessions(ctx, users[0].ID.String(), users[1].ID.String()))
+
+		found, err := sessions.GetUserSessions(ctx, []string{users[0].ID.String(), users[1].ID.String()})
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("should mark evicted sessions that have not expired yet", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		sessions := service.NewSessionService(store)
+
+		evicted := []model.Token{
+			{Token: "refresh-live", Expires: time.Now().Add(time.Hour)},
+			{Token: "refresh-expired", Expires: time.Now().Add(-time.Minute)},
+		}
+		assert.NoError(t, sessions.MarkEvicted(ctx, evicted))
+
+		assert.True(t, sessions.IsEvicted(ctx, "refresh-live"))
+		assert.False(t, sessions.IsEvicted(ctx, "refresh-expired"))
+		assert.False(t, sessions.IsEvicted(ctx, "refresh-other"))
+	})
+}
+
+func TestSessionServiceEnvelope(t *testing.T) {
+	ctx := context.Background()
+	user := &model.User{ID: uuid.New(), Name: "One", Email: "one@example.com", Role: "user", IsActive: true}
+	key := cache.GetSessionKey(user.ID.String())
+
+	t.Run("should gzip large sessions and read them back", func(t *testing.T) {
+		compressMin := config.SessionCompressMin
+		config.SessionCompressMin = 1
+		t.Cleanup(func() { config.SessionCompressMin = compressMin })
+
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		sessions := service.NewSessionService(store)
+
+		assert.NoError(t, sessions.CacheUserSession(ctx, user.ID.String(), user))
+
+		raw, err := store.Get(key)
+		assert.NoError(t, err)
+		assert.Contains(t, string(raw), `"gzip"`)
+		assert.NotContains(t, string(raw), "one@example.com")
+
+		session, err := sessions.GetUserSession(ctx, user.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "one@example.com", session.Email)
+	})
+
+	t.Run("should read sessions cached before the envelope", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		sessions := service.NewSessionService(store)
+
+		legacy := `{"id":"` + user.ID.String() + `","email":"one@example.com","role":"user","session_id":"s"}`
+		assert.NoError(t, store.Set(key, []byte(legacy), time.Minute))
+
+		session, err := sessions.GetUserSession(ctx, user.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "one@example.com", session.Email)
+	})
+
+	t.Run("should miss sessions of another schema version or that are corrupt", func(t *testing.T) {
+		store := cache.NewMemoryStore()
+		defer store.Close()
+		sessions := service.NewSessionService(store)
+
+		for _, payload := range []string{
+			`{"v":99,"data":{"id":"` + user.ID.String() + `","role":"admin"}}`,
+			`{"v":1,"gzip":"bm90IGd6aXA="}`,
+			`{"id":42}`,
+			`not json`,
+		} {
+			assert.NoError(t, store.Set(key, []byte(payload), time.Minute))
+
+			_, err := sessions.GetUserSession(ctx, user.ID.String())
+			assert.ErrorIs(t, err, service.ErrCacheMiss, payload)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package service_test
+
+import (
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/service"
+	"app/src/utils"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenServiceClock(t *testing.T) {
+	user := &model.User{ID: uuid.New(), Role: "user"}
+
+	t.Run("should issue tokens at the time of the clock", func(t *testing.T) {
+		issued := time.Date(2026, time.March, 14, 12, 0, 0, 0, time.UTC)
+		tokens := service.NewTokenService(nil, nil, nil, nil, nil, clock.NewMock(issued))
+
+		token, err := tokens.GenerateToken(user.ID.String(), issued.Add(time.Minute), config.TokenTypeRefresh)
+		assert.NoError(t, err)
+
+		claims := jwt.MapClaims{}
+		_, _, err = jwt.NewParser().ParseUnverified(token, claims)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(issued.Unix()), claims["iat"])
+		assert.Equal(t, float64(issued.Add(time.Minute).Unix()), claims["exp"])
+	})
+
+	t.Run("should reject a token once the clock passes its expiry", func(t *testing.T) {
+		clk := clock.NewMock(time.Now().Add(-time.Hour))
+		tokens := service.NewTokenService(nil, nil, nil, nil, nil, clk)
+
+		token, err := tokens.GenerateAccessToken(user, clk.Now().Add(30*time.Minute))
+		assert.NoError(t, err)
+
+		_, err = utils.VerifyAccessToken(token, config.JWTSecret, config.TokenTypeAccess)
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	})
+}
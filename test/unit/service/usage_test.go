@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"app/src/cache"
+	"app/src/clock"
+	"app/src/config"
+	"app/src/model"
+	"app/src/service"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageRecord(t *testing.T) {
+	cfg := config.MeteringConfig{
+		Enabled:        true,
+		Events:         []string{model.AuditActionLoginSucceeded},
+		RollupInterval: time.Minute,
+	}
+	ctx := context.Background()
+	clk := clock.NewMock(time.Date(2026, time.March, 14, 12, 0, 0, 0, time.UTC))
+	today := "2026-03-14"
+
+	newService := func(cfg config.MeteringConfig) (service.UsageService, *cache.MemoryStore) {
+		store := cache.NewMemoryStore()
+		t.Cleanup(func() { _ = store.Close() })
+		// Without a database counters live in the store only
+		return service.NewUsageService(nil, nil, store, cfg, clk), store
+	}
+
+	count := func(store *cache.MemoryStore, userID uuid.UUID, subject, metric string) string {
+		data, err := store.Get(cache.GetUsageKey(today, userID.String(), subject, metric))
+		assert.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("should count requests for the user or the API token they were made with", func(t *testing.T) {
+		usage, store := newService(cfg)
+		user := &model.User{ID: uuid.New()}
+		token := &model.APIToken{ID: uuid.New(), UserID: user.ID}
+
+		usage.RecordRequest(ctx, user, nil)
+		usage.RecordRequest(ctx, user, nil)
+		usage.RecordRequest(ctx, user, token)
+
+		assert.Equal(t, "2", count(store, user.ID, model.UserQuotaSubject(user.ID), model.UsageMetricRequests))
+		assert.Equal(t, "1", count(store, user.ID, model.TokenQuotaSubject(token.ID), model.UsageMetricRequests))
+	})
+
+	t.Run("should only count the metered events", func(t *testing.T) {
+		usage, store := newService(cfg)
+		userID := uuid.New()
+
+		usage.RecordEvent(ctx, userID, model.AuditActionLoginSucceeded)
+		usage.RecordEvent(ctx, userID, model.AuditActionEmailSent)
+
+		assert.Equal(t, "1", count(store, userID, model.UserQuotaSubject(userID), model.AuditActionLoginSucceeded))
+
+		keys, err := store.Keys(ctx, cache.UsageKeyPrefix+"*")
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+	})
+
+	t.Run("should count nothing while metering is disabled", func(t *testing.T) {
+		usage, store := newService(config.MeteringConfig{Events: cfg.Events})
+
+		usage.RecordRequest(ctx, &model.User{ID: uuid.New()}, nil)
+
+		keys, err := store.Keys(ctx, cache.UsageKeyPrefix+"*")
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+}
+
+type recordedAudit struct {
+	service.AuditService
+	actions []string
+}
+
+func (a *recordedAudit) Record(_ *fiber.Ctx, _ *uuid.UUID, action string, _ map[string]any) {
+	a.actions = append(a.actions, action)
+}
+
+func TestMeteredAuditService(t *testing.T) {
+	store := cache.NewMemoryStore()
+	t.Cleanup(func() { _ = store.Close() })
+
+	usage := service.NewUsageService(nil, nil, store, config.MeteringConfig{
+		Enabled:        true,
+		Events:         []string{model.AuditActionLoginSucceeded},
+		RollupInterval: time.Minute,
+	}, nil)
+	audit := &recordedAudit{}
+	metered := service.NewMeteredAuditService(audit, usage)
+	userID := uuid.New()
+
+	app := fiber.New()
+	app.Post("/login", func(c *fiber.Ctx) error {
+		metered.Record(c, &userID, model.AuditActionLoginSucceeded, nil)
+		metered.Record(c, nil, model.AuditActionLoginSucceeded, nil)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	t.Run("should record every entry and meter those concerning a user", func(t *testing.T) {
+		_, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/login", nil))
+		assert.NoError(t, err)
+
+		assert.Len(t, audit.actions, 2)
+
+		data, err := store.Get(cache.GetUsageKey(
+			time.Now().UTC().Format(time.DateOnly), userID.String(), model.UserQuotaSubject(userID),
+			model.AuditActionLoginSucceeded,
+		))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", string(data))
+	})
+}
@@ -0,0 +1,70 @@
+package siem_test
+
+import (
+	"app/src/siem"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var userID = uuid.MustParse("0f8fad5b-d9cb-469f-a165-70867728950e")
+
+func event(action string) siem.Event {
+	return siem.Event{
+		ID:        uuid.MustParse("7c9e6679-7425-40de-944b-e07fc1f90ae7"),
+		Time:      time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+		Action:    action,
+		UserID:    &userID,
+		IP:        "203.0.113.7",
+		UserAgent: "=HYPERLINK(\"https://evil.example\")",
+		Metadata:  json.RawMessage(`{"role":"admin","note":"<b>"}`),
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	t.Run("should write CSV with a header and formulas neutralized", func(t *testing.T) {
+		var out bytes.Buffer
+		encoder, err := siem.NewEncoder(&out, siem.FormatCSV)
+		assert.NoError(t, err)
+		assert.NoError(t, encoder.Encode(event("user.role_changed")))
+		assert.NoError(t, encoder.Flush())
+
+		rows, err := csv.NewReader(&out).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, siem.CSVColumns, rows[0])
+		assert.Equal(t, "user.role_changed", rows[1][2])
+		assert.Equal(t, userID.String(), rows[1][3])
+		assert.Equal(t, "'=HYPERLINK(\"https://evil.example\")", rows[1][6])
+		assert.Equal(t, `{"role":"admin","note":"<b>"}`, rows[1][8])
+	})
+
+	t.Run("should write one JSON object per line", func(t *testing.T) {
+		var out bytes.Buffer
+		encoder, err := siem.NewEncoder(&out, siem.FormatNDJSON)
+		assert.NoError(t, err)
+		assert.NoError(t, encoder.Encode(event("user.role_changed")))
+		assert.NoError(t, encoder.Encode(event("user.deleted")))
+		assert.NoError(t, encoder.Flush())
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		assert.Len(t, lines, 2)
+
+		var decoded siem.Event
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+		assert.Equal(t, "user.deleted", decoded.Action)
+		assert.JSONEq(t, `{"role":"admin","note":"<b>"}`, string(decoded.Metadata))
+		assert.Contains(t, lines[0], `"note":"<b>"`)
+	})
+
+	t.Run("should refuse unknown formats", func(t *testing.T) {
+		_, err := siem.NewEncoder(&bytes.Buffer{}, "xml")
+		assert.Error(t, err)
+	})
+}
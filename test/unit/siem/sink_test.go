@@ -0,0 +1,148 @@
+package siem_test
+
+import (
+	"app/src/siem"
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyslogSink(t *testing.T) {
+	t.Run("should send RFC 5424 messages framed by octet counting over tcp", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer listener.Close()
+
+		received := make(chan []string, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			reader := bufio.NewReader(conn)
+			var messages []string
+			for len(messages) < 2 {
+				length, err := reader.ReadString(' ')
+				if err != nil {
+					break
+				}
+				size, _ := strconv.Atoi(strings.TrimSpace(length))
+				message := make([]byte, size)
+				if _, err := io.ReadFull(reader, message); err != nil {
+					break
+				}
+				messages = append(messages, string(message))
+			}
+			received <- messages
+		}()
+
+		sink := siem.NewSyslogSink("tcp", listener.Addr().String(), time.Second)
+		defer sink.Close()
+		assert.NoError(t, sink.Send(context.Background(), []siem.Event{event("user.role_changed"), event("user.deleted")}))
+
+		messages := <-received
+		assert.Len(t, messages, 2)
+		assert.True(t, strings.HasPrefix(messages[0], "<86>1 2026-10-17T12:00:00Z "), messages[0])
+		assert.Contains(t, messages[0], " fiber-api ")
+		assert.Contains(t, messages[1], " user.deleted - {")
+
+		var decoded siem.Event
+		assert.NoError(t, json.Unmarshal([]byte(messages[1][strings.Index(messages[1], "{"):]), &decoded))
+		assert.Equal(t, "user.deleted", decoded.Action)
+	})
+
+	t.Run("should fail while the collector is unreachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		address := listener.Addr().String()
+		listener.Close()
+
+		sink := siem.NewSyslogSink("tcp", address, time.Second)
+		assert.Error(t, sink.Send(context.Background(), []siem.Event{event("user.deleted")}))
+	})
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Run("should post batches as NDJSON with the configured authorization", func(t *testing.T) {
+		var authorization, contentType string
+		var lines []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization, contentType = r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+			body, _ := io.ReadAll(r.Body)
+			lines = strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		sink := siem.NewWebhookSink(server.URL, "Splunk secret", server.Client())
+		assert.NoError(t, sink.Send(context.Background(), []siem.Event{event("user.role_changed"), event("user.deleted")}))
+
+		assert.Equal(t, "Splunk secret", authorization)
+		assert.Equal(t, "application/x-ndjson", contentType)
+		assert.Len(t, lines, 2)
+	})
+
+	t.Run("should fail on statuses other than 2xx", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "index is read-only", http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := siem.NewWebhookSink(server.URL, "", server.Client()).
+			Send(context.Background(), []siem.Event{event("user.deleted")})
+		assert.ErrorContains(t, err, "503")
+		assert.ErrorContains(t, err, "index is read-only")
+	})
+}
+
+func TestKafkaSink(t *testing.T) {
+	var path, contentType string
+	var produced struct {
+		Records []struct {
+			Key   *string    `json:"key"`
+			Value siem.Event `json:"value"`
+		} `json:"records"`
+	}
+	answer := `{"offsets":[{"partition":0,"offset":1}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&produced)
+		_, _ = w.Write([]byte(answer))
+	}))
+	defer server.Close()
+
+	sink := siem.NewKafkaSink(server.URL+"/", "audit.events", server.Client())
+
+	t.Run("should produce records keyed by user through the REST Proxy", func(t *testing.T) {
+		anonymous := event("login.failed")
+		anonymous.UserID = nil
+
+		assert.NoError(t, sink.Send(context.Background(), []siem.Event{event("user.deleted"), anonymous}))
+
+		assert.Equal(t, "/topics/audit.events", path)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", contentType)
+		assert.Len(t, produced.Records, 2)
+		assert.Equal(t, userID.String(), *produced.Records[0].Key)
+		assert.Equal(t, "user.deleted", produced.Records[0].Value.Action)
+		assert.Nil(t, produced.Records[1].Key)
+	})
+
+	t.Run("should fail when a record is refused", func(t *testing.T) {
+		answer = `{"offsets":[{"partition":null,"offset":null,"error_code":50003,"error":"Kafka error"}]}`
+
+		err := sink.Send(context.Background(), []siem.Event{event("user.deleted")})
+		assert.ErrorContains(t, err, "50003")
+	})
+}
@@ -0,0 +1,104 @@
+package signedurl_test
+
+import (
+	"app/src/signedurl"
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryNonces struct {
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+func (m *memoryNonces) Consume(_ context.Context, nonce string, _ time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.used[nonce] {
+		return false, nil
+	}
+	m.used[nonce] = true
+	return true, nil
+}
+
+func newSigner(t *testing.T, keys ...signedurl.Key) *signedurl.Signer {
+	signer, err := signedurl.NewSigner(keys, &memoryNonces{used: map[string]bool{}})
+	assert.NoError(t, err)
+	return signer
+}
+
+func TestSigner(t *testing.T) {
+	ctx := context.Background()
+	current := signedurl.Key{ID: "k2", Secret: []byte("new-secret")}
+	previous := signedurl.Key{ID: "k1", Secret: []byte("old-secret")}
+
+	t.Run("should verify a freshly signed url", func(t *testing.T) {
+		signer := newSigner(t, current)
+		signed, err := signer.Sign("https://example.com/v1/exports/42?format=csv", time.Minute, false)
+		assert.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(ctx, signed))
+		assert.NoError(t, signer.Verify(ctx, signed), "reusable urls can be verified repeatedly")
+	})
+
+	t.Run("should reject tampered paths and parameters", func(t *testing.T) {
+		signer := newSigner(t, current)
+		signed, _ := signer.Sign("/v1/exports/42?format=csv", time.Minute, false)
+
+		u, _ := url.Parse(signed)
+		u.Path = "/v1/exports/43"
+		assert.ErrorIs(t, signer.Verify(ctx, u.String()), signedurl.ErrInvalidSignature)
+
+		u, _ = url.Parse(signed)
+		q := u.Query()
+		q.Set("format", "json")
+		u.RawQuery = q.Encode()
+		assert.ErrorIs(t, signer.Verify(ctx, u.String()), signedurl.ErrInvalidSignature)
+
+		assert.ErrorIs(t, signer.Verify(ctx, "/v1/exports/42?format=csv"), signedurl.ErrInvalidSignature)
+	})
+
+	t.Run("should reject expired urls", func(t *testing.T) {
+		signer := newSigner(t, current)
+		signed, _ := signer.Sign("/v1/exports/42", -time.Second, false)
+
+		assert.ErrorIs(t, signer.Verify(ctx, signed), signedurl.ErrExpired)
+	})
+
+	t.Run("should keep verifying urls signed with a rotated key", func(t *testing.T) {
+		old := newSigner(t, previous)
+		signed, _ := old.Sign("/v1/avatars/7", time.Minute, false)
+
+		rotated := newSigner(t, current, previous)
+		assert.NoError(t, rotated.Verify(ctx, signed))
+
+		retired := newSigner(t, current)
+		assert.ErrorIs(t, retired.Verify(ctx, signed), signedurl.ErrUnknownKey)
+	})
+
+	t.Run("should only accept one-time urls once", func(t *testing.T) {
+		signer := newSigner(t, current)
+		signed, _ := signer.Sign("/v1/auth/verify-email?token=abc", time.Minute, true)
+
+		assert.NoError(t, signer.Verify(ctx, signed))
+		assert.ErrorIs(t, signer.Verify(ctx, signed), signedurl.ErrAlreadyUsed)
+	})
+
+	t.Run("should fail closed for one-time urls without a nonce store", func(t *testing.T) {
+		signer, err := signedurl.NewSigner([]signedurl.Key{current}, nil)
+		assert.NoError(t, err)
+		signed, _ := signer.Sign("/v1/auth/verify-email", time.Minute, true)
+
+		assert.ErrorIs(t, signer.Verify(ctx, signed), signedurl.ErrNonceStoreUnavailable)
+	})
+
+	t.Run("should require at least one key", func(t *testing.T) {
+		_, err := signedurl.NewSigner(nil, nil)
+		assert.Error(t, err)
+	})
+}
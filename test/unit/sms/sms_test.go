@@ -0,0 +1,162 @@
+package sms_test
+
+import (
+	"app/src/sms"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwilio(t *testing.T) {
+	const (
+		authToken   = "twilio-auth-token"
+		callbackURL = "https://api.example.com/v1/notifications/callbacks/twilio"
+	)
+
+	callback := func(form url.Values, token string) sms.Callback {
+		signature := base64.StdEncoding.EncodeToString(sms.TwilioSignature(token, callbackURL, form))
+		return sms.Callback{
+			Header: http.Header{sms.TwilioSignatureHeader: {signature}},
+			Body:   []byte(form.Encode()),
+		}
+	}
+
+	t.Run("should send WhatsApp messages from the WhatsApp number with a status callback", func(t *testing.T) {
+		var form url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+			sid, token, _ := r.BasicAuth()
+			assert.Equal(t, "AC123", sid)
+			assert.Equal(t, authToken, token)
+
+			assert.NoError(t, r.ParseForm())
+			form = r.PostForm
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"sid": "SM42"}`))
+		}))
+		defer server.Close()
+
+		provider := sms.NewTwilio(server.URL, "AC123", authToken, "+15005550006", "+14155238886", callbackURL)
+		id, err := provider.Send(context.Background(), sms.Message{To: "+14155552671", Text: "Hi", WhatsApp: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "SM42", id)
+		assert.Equal(t, "whatsapp:+14155552671", form.Get("To"))
+		assert.Equal(t, "whatsapp:+14155238886", form.Get("From"))
+		assert.Equal(t, callbackURL, form.Get("StatusCallback"))
+	})
+
+	t.Run("should report failed sends", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code": 21211, "message": "Invalid 'To' Phone Number"}`))
+		}))
+		defer server.Close()
+
+		provider := sms.NewTwilio(server.URL, "AC123", authToken, "+15005550006", "", "")
+		_, err := provider.Send(context.Background(), sms.Message{To: "+1", Text: "Hi"})
+		assert.ErrorContains(t, err, "21211")
+	})
+
+	t.Run("should decode signed status callbacks", func(t *testing.T) {
+		provider := sms.NewTwilio("", "AC123", authToken, "", "", callbackURL)
+		form := url.Values{"MessageSid": {"SM42"}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}
+
+		status, err := provider.ParseCallback(callback(form, authToken))
+		assert.NoError(t, err)
+		assert.Equal(t, &sms.Status{MessageID: "SM42", Status: sms.StatusFailed, Error: "30003"}, status)
+	})
+
+	t.Run("should reject callbacks signed with another token", func(t *testing.T) {
+		provider := sms.NewTwilio("", "AC123", authToken, "", "", callbackURL)
+		form := url.Values{"MessageSid": {"SM42"}, "MessageStatus": {"delivered"}}
+
+		_, err := provider.ParseCallback(callback(form, "another-token"))
+		assert.ErrorIs(t, err, sms.ErrInvalidSignature)
+	})
+
+	t.Run("should ignore statuses that are not tracked", func(t *testing.T) {
+		provider := sms.NewTwilio("", "AC123", authToken, "", "", callbackURL)
+		form := url.Values{"MessageSid": {"SM42"}, "MessageStatus": {"receiving"}}
+
+		status, err := provider.ParseCallback(callback(form, authToken))
+		assert.NoError(t, err)
+		assert.Nil(t, status)
+	})
+}
+
+func TestVonage(t *testing.T) {
+	const secret = "vonage-signature-secret"
+
+	callback := func(t *testing.T, body []byte, signedBody []byte, key string) sms.Callback {
+		sum := sha256.Sum256(signedBody)
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"payload_hash": hex.EncodeToString(sum[:]),
+		}).SignedString([]byte(key))
+		assert.NoError(t, err)
+		return sms.Callback{Header: http.Header{"Authorization": {"Bearer " + token}}, Body: body}
+	}
+
+	t.Run("should send SMS without the leading plus", func(t *testing.T) {
+		var payload map[string]string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/messages", r.URL.Path)
+			body, _ := io.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(body, &payload))
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"message_uuid": "aaaaaaaa-bbbb"}`))
+		}))
+		defer server.Close()
+
+		provider := sms.NewVonage(server.URL, "key", "secret", secret, "+15005550006", "")
+		id, err := provider.Send(context.Background(), sms.Message{To: "+14155552671", Text: "Hi"})
+		assert.NoError(t, err)
+		assert.Equal(t, "aaaaaaaa-bbbb", id)
+		assert.Equal(t, "14155552671", payload["to"])
+		assert.Equal(t, "15005550006", payload["from"])
+		assert.Equal(t, "sms", payload["channel"])
+	})
+
+	t.Run("should decode signed status callbacks", func(t *testing.T) {
+		provider := sms.NewVonage("", "key", "secret", secret, "", "")
+		body := []byte(`{"message_uuid": "aaaaaaaa-bbbb", "status": "rejected", "error": {"title": "Invalid number"}}`)
+
+		status, err := provider.ParseCallback(callback(t, body, body, secret))
+		assert.NoError(t, err)
+		assert.Equal(t, &sms.Status{MessageID: "aaaaaaaa-bbbb", Status: sms.StatusFailed, Error: "Invalid number"}, status)
+	})
+
+	t.Run("should reject callbacks whose body does not match the signed hash", func(t *testing.T) {
+		provider := sms.NewVonage("", "key", "secret", secret, "", "")
+		body := []byte(`{"message_uuid": "aaaaaaaa-bbbb", "status": "delivered"}`)
+
+		_, err := provider.ParseCallback(callback(t, body, []byte(`{}`), secret))
+		assert.ErrorIs(t, err, sms.ErrInvalidSignature)
+	})
+
+	t.Run("should reject callbacks signed with another secret", func(t *testing.T) {
+		provider := sms.NewVonage("", "key", "secret", secret, "", "")
+		body := []byte(`{"message_uuid": "aaaaaaaa-bbbb", "status": "delivered"}`)
+
+		_, err := provider.ParseCallback(callback(t, body, body, "another-secret"))
+		assert.ErrorIs(t, err, sms.ErrInvalidSignature)
+	})
+}
+
+func TestAdvances(t *testing.T) {
+	t.Run("should only move messages forward", func(t *testing.T) {
+		assert.True(t, sms.Advances(sms.StatusAccepted, sms.StatusDelivered))
+		assert.True(t, sms.Advances(sms.StatusDelivered, sms.StatusRead))
+		assert.False(t, sms.Advances(sms.StatusDelivered, sms.StatusSent))
+		assert.False(t, sms.Advances(sms.StatusFailed, sms.StatusDelivered))
+	})
+}
@@ -0,0 +1,58 @@
+package startup_test
+
+import (
+	"app/src/startup"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	t.Run("should retry until the dependency becomes reachable", func(t *testing.T) {
+		var calls atomic.Int32
+		deps := []startup.Dependency{{
+			Name:     "postgres",
+			Required: true,
+			Check: func(context.Context) error {
+				if calls.Add(1) < 3 {
+					return errDown
+				}
+				return nil
+			},
+		}}
+
+		err := startup.Wait(context.Background(), deps, 5*time.Second)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("should fail when a required dependency stays unreachable", func(t *testing.T) {
+		deps := []startup.Dependency{{
+			Name:     "postgres",
+			Required: true,
+			Check:    func(context.Context) error { return errDown },
+		}}
+
+		err := startup.Wait(context.Background(), deps, 100*time.Millisecond)
+
+		assert.ErrorIs(t, err, errDown)
+	})
+
+	t.Run("should start degraded when an optional dependency stays unreachable", func(t *testing.T) {
+		deps := []startup.Dependency{
+			{Name: "postgres", Required: true, Check: func(context.Context) error { return nil }},
+			{Name: "smtp", Check: func(context.Context) error { return errDown }},
+		}
+
+		err := startup.Wait(context.Background(), deps, 100*time.Millisecond)
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,53 @@
+package static_test
+
+import (
+	"app/src/config"
+	"app/src/static"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatic(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>app</html>"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.js"), []byte("console.log(1)"), 0o600))
+
+	handler, err := static.New(config.StaticConfig{Enabled: true, Dir: dir, MaxAge: 3600})
+	assert.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(handler)
+	app.Use(func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).SendString("api not found")
+	})
+
+	t.Run("should serve assets with cache headers", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/app.js", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "public, max-age=3600", resp.Header.Get(fiber.HeaderCacheControl))
+	})
+
+	t.Run("should fall back to index.html for client-side routes", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/dashboard/settings", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-cache", resp.Header.Get(fiber.HeaderCacheControl))
+	})
+
+	t.Run("should leave unknown API paths to the API", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/unknown", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should fail for a missing directory", func(t *testing.T) {
+		_, err := static.New(config.StaticConfig{Enabled: true, Dir: filepath.Join(dir, "missing")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,169 @@
+package utils_test
+
+import (
+	"app/src/config"
+	"app/src/utils"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const secret = "testsecret"
+
+// withTokenClaims verifies tokens against issuer, audience and leeway until the test ends
+func withTokenClaims(t *testing.T, issuer, audience string, leeway time.Duration) {
+	utils.ConfigureTokenClaims(issuer, audience, leeway)
+	t.Cleanup(func() {
+		utils.ConfigureTokenClaims(config.JWTIssuer, config.JWTAudience, config.JWTLeeway)
+	})
+}
+
+func signClaims(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return token
+}
+
+func TestVerifyAccessToken(t *testing.T) {
+	// These tokens carry no issuer or audience
+	withTokenClaims(t, "", "", 0)
+	expires := time.Now().Add(time.Minute).Unix()
+
+	t.Run("should return role and scopes embedded in the token", func(t *testing.T) {
+		token := signClaims(t, jwt.MapClaims{
+			"sub":    "user-id",
+			"exp":    expires,
+			"type":   "access",
+			"role":   "admin",
+			"scopes": []string{"getUsers", "manageUsers"},
+		})
+
+		claims, err := utils.VerifyAccessToken(token, secret, "access")
+		assert.NoError(t, err)
+		assert.Equal(t, "user-id", claims.UserID)
+		assert.Equal(t, "admin", claims.Role)
+		assert.Equal(t, []string{"getUsers", "manageUsers"}, claims.Scopes)
+	})
+
+	t.Run("should return the issue time when present", func(t *testing.T) {
+		issuedAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+		token := signClaims(t, jwt.MapClaims{
+			"sub":    "user-id",
+			"iat":    issuedAt.Unix(),
+			"exp":    expires,
+			"type":   "access",
+			"role":   "user",
+			"scopes": []string{},
+		})
+
+		claims, err := utils.VerifyAccessToken(token, secret, "access")
+		assert.NoError(t, err)
+		assert.True(t, issuedAt.Equal(claims.IssuedAt))
+	})
+
+	t.Run("should reject a token without scope claims", func(t *testing.T) {
+		token := signClaims(t, jwt.MapClaims{
+			"sub":  "user-id",
+			"exp":  expires,
+			"type": "access",
+		})
+
+		_, err := utils.VerifyAccessToken(token, secret, "access")
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject a token of another type", func(t *testing.T) {
+		token := signClaims(t, jwt.MapClaims{
+			"sub":    "user-id",
+			"exp":    expires,
+			"type":   "refresh",
+			"role":   "user",
+			"scopes": []string{},
+		})
+
+		_, err := utils.VerifyAccessToken(token, secret, "access")
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject a token signed with another secret", func(t *testing.T) {
+		token := signClaims(t, jwt.MapClaims{
+			"sub":    "user-id",
+			"exp":    expires,
+			"type":   "access",
+			"role":   "user",
+			"scopes": []string{},
+		})
+
+		_, err := utils.VerifyAccessToken(token, "otherSecret", "access")
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifyTokenClaims(t *testing.T) {
+	withTokenClaims(t, "issuer", "audience", 30*time.Second)
+
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		base := jwt.MapClaims{
+			"sub":  "user-id",
+			"exp":  time.Now().Add(time.Minute).Unix(),
+			"type": "refresh",
+			"iss":  "issuer",
+			"aud":  "audience",
+		}
+		for key, value := range overrides {
+			if value == nil {
+				delete(base, key)
+				continue
+			}
+			base[key] = value
+		}
+		return base
+	}
+
+	t.Run("should accept a token with the configured issuer and audience", func(t *testing.T) {
+		userID, err := utils.VerifyToken(signClaims(t, claims(nil)), secret, "refresh")
+		assert.NoError(t, err)
+		assert.Equal(t, "user-id", userID)
+	})
+
+	t.Run("should accept an audience list containing the configured audience", func(t *testing.T) {
+		token := signClaims(t, claims(jwt.MapClaims{"aud": []string{"other", "audience"}}))
+
+		_, err := utils.VerifyToken(token, secret, "refresh")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject a token from another issuer", func(t *testing.T) {
+		_, err := utils.VerifyToken(signClaims(t, claims(jwt.MapClaims{"iss": "staging"})), secret, "refresh")
+		assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+	})
+
+	t.Run("should reject a token for another audience", func(t *testing.T) {
+		_, err := utils.VerifyToken(signClaims(t, claims(jwt.MapClaims{"aud": "other-app"})), secret, "refresh")
+		assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+	})
+
+	t.Run("should reject a token without issuer or audience", func(t *testing.T) {
+		_, err := utils.VerifyToken(signClaims(t, claims(jwt.MapClaims{"iss": nil})), secret, "refresh")
+		assert.Error(t, err)
+
+		_, err = utils.VerifyToken(signClaims(t, claims(jwt.MapClaims{"aud": nil})), secret, "refresh")
+		assert.Error(t, err)
+	})
+
+	t.Run("should tolerate clock skew within the leeway", func(t *testing.T) {
+		token := signClaims(t, claims(jwt.MapClaims{"exp": time.Now().Add(-10 * time.Second).Unix()}))
+
+		_, err := utils.VerifyToken(token, secret, "refresh")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject a token expired beyond the leeway", func(t *testing.T) {
+		token := signClaims(t, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}))
+
+		_, err := utils.VerifyToken(token, secret, "refresh")
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	})
+}
@@ -0,0 +1,207 @@
+package validation_test
+
+import (
+	"app/src/validation"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinRules(t *testing.T) {
+	validate := validation.Validator()
+
+	cases := []struct {
+		tag     string
+		valid   []string
+		invalid []string
+	}{
+		{"strong_password", []string{"Passw0rd!", "Ünïcode9#x"}, []string{"password1", "PASSWORD1!", "Password!!", "Password1 "}},
+		{"e164_phone", []string{"+14155552671", "+628123456789"}, []string{"08123456789", "+0123456", "+1415555267123456"}},
+		{
+			"uuid_list",
+			[]string{"0f8fad5b-d9cb-469f-a165-70867728950e", "0f8fad5b-d9cb-469f-a165-70867728950e, 7c9e6679-7425-40de-944b-e07fc1f90ae7"},
+			[]string{"0f8fad5b-d9cb-469f-a165-70867728950e,", "not-a-uuid"},
+		},
+		{"safe_search_string", []string{"john doe", "o'brien@example.com", "user_1"}, []string{"100%", `a\b`, "a\x00b", "line\nbreak"}},
+		{"timezone", []string{"UTC", "Asia/Jakarta", "America/New_York"}, []string{"Local", "Mars/Olympus", "GMT+7"}},
+	}
+
+	for _, c := range cases {
+		t.Run("should apply "+c.tag, func(t *testing.T) {
+			for _, value := range c.valid {
+				assert.NoError(t, validate.Var(value, c.tag), value)
+			}
+			for _, value := range c.invalid {
+				assert.Error(t, validate.Var(value, c.tag), value)
+			}
+		})
+	}
+}
+
+type order struct {
+	Reference string `validate:"required,order_ref"`
+	Quantity  int    `validate:"max_batch=5"`
+}
+
+func TestRegisterRule(t *testing.T) {
+	validation.RegisterRule(
+		validation.Rule{
+			Tag:     "order_ref",
+			Func:    func(fl validator.FieldLevel) bool { return len(fl.Field().String()) == 8 },
+			Message: "Field %s must be an 8 character order reference",
+		},
+		validation.Rule{
+			Tag:     "max_batch",
+			Func:    func(fl validator.FieldLevel) bool { return fl.Field().Int() <= 5 },
+			Message: "Field %s must not exceed a batch of %s",
+		},
+	)
+	validate := validation.Validator()
+
+	t.Run("should apply registered rules with their messages", func(t *testing.T) {
+		err := validate.Struct(order{Reference: "ABC", Quantity: 9})
+
+		assert.Equal(t, map[string]string{
+			"order.Reference": "Field Reference must be an 8 character order reference",
+			"order.Quantity":  "Field Quantity must not exceed a batch of 5",
+		}, validation.CustomErrorMessages(err))
+	})
+
+	t.Run("should replace a rule registered under the same tag", func(t *testing.T) {
+		validation.RegisterRule(validation.Rule{
+			Tag:     "order_ref",
+			Func:    func(fl validator.FieldLevel) bool { return len(fl.Field().String()) == 3 },
+			Message: "Field %s must be a short order reference",
+		})
+
+		assert.NoError(t, validation.Validator().Struct(order{Reference: "ABC", Quantity: 1}))
+	})
+
+	t.Run("should panic on a rule without a function", func(t *testing.T) {
+		assert.Panics(t, func() { validation.RegisterRule(validation.Rule{Tag: "broken"}) })
+	})
+
+	t.Run("should report built-in rules with their messages", func(t *testing.T) {
+		err := validate.Struct(validation.QueryUser{Search: "50%"})
+
+		assert.Equal(t, map[string]string{
+			"QueryUser.Search": "Field Search must not contain control characters, % or \\",
+		}, validation.CustomErrorMessages(err))
+	})
+}
+
+type window struct {
+	Start time.Time
+	End   time.Time
+}
+
+func TestStructRules(t *testing.T) {
+	validate := validation.Validator()
+
+	t.Run("should require at least one field of an update", func(t *testing.T) {
+		err := validate.Struct(&validation.UpdateUser{})
+
+		assert.Equal(t, map[string]string{
+			"UpdateUser.Name": "Field Name or one of Username, Email, Password, Role, Timezone must be filled",
+		}, validation.CustomErrorMessages(err))
+		assert.NoError(t, validate.Struct(&validation.UpdateUser{Role: validation.Some("admin")}))
+	})
+
+	t.Run("should require a given password confirmation to match", func(t *testing.T) {
+		req := &validation.Register{
+			Name: "John Doe", Email: "john@example.com", Password: "password1", PasswordConfirmation: "password2",
+		}
+
+		assert.Equal(t, map[string]string{
+			"Register.PasswordConfirmation": "Field PasswordConfirmation must match Password",
+		}, validation.CustomErrorMessages(validate.Struct(req)))
+
+		req.PasswordConfirmation = ""
+		assert.NoError(t, validate.Struct(req))
+		req.PasswordConfirmation = "password1"
+		assert.NoError(t, validate.Struct(req))
+	})
+
+	t.Run("should combine rules on the same type", func(t *testing.T) {
+		assert.Error(t, validate.Struct(&validation.UpdatePassOrVerify{}))
+		assert.Error(t, validate.Struct(&validation.UpdatePassOrVerify{Password: "password1", PasswordConfirmation: "x"}))
+		assert.NoError(t, validate.Struct(&validation.UpdatePassOrVerify{VerifiedEmail: true}))
+	})
+
+	t.Run("should reject windows ending before they start", func(t *testing.T) {
+		err := validate.Struct(&validation.QueryUsage{From: "2026-10-02", To: "2026-10-01"})
+
+		assert.Equal(t, map[string]string{
+			"QueryUsage.To": "Field To must not be before From",
+		}, validation.CustomErrorMessages(err))
+		assert.NoError(t, validate.Struct(&validation.QueryUsage{From: "2026-10-01", To: "2026-10-01"}))
+		assert.NoError(t, validate.Struct(&validation.QueryUsage{From: "2026-10-01"}))
+	})
+
+	t.Run("should require a reason only to switch read-only mode on", func(t *testing.T) {
+		on, off := true, false
+
+		assert.Error(t, validate.Struct(&validation.SetReadOnly{}))
+		assert.Error(t, validate.Struct(&validation.SetReadOnly{Enabled: &on}))
+		assert.NoError(t, validate.Struct(&validation.SetReadOnly{Enabled: &on, Reason: "Database migration"}))
+		assert.NoError(t, validate.Struct(&validation.SetReadOnly{Enabled: &off}))
+	})
+
+	t.Run("should apply registered struct rules", func(t *testing.T) {
+		validation.RegisterStructRule(validation.StructRule{
+			Types: []any{window{}},
+			Func:  validation.NotBefore("Start", "End"),
+		})
+		now := time.Now()
+
+		err := validation.Validator().Struct(window{Start: now, End: now.Add(-time.Hour)})
+		assert.Equal(t, map[string]string{
+			"window.End": "Field End must not be before Start",
+		}, validation.CustomErrorMessages(err))
+		assert.NoError(t, validation.Validator().Struct(window{Start: now, End: now.Add(time.Hour)}))
+	})
+}
+
+func TestOptional(t *testing.T) {
+	validate := validation.Validator()
+
+	decode := func(t *testing.T, body string) *validation.UpdateUser {
+		req := new(validation.UpdateUser)
+		assert.NoError(t, json.Unmarshal([]byte(body), req))
+		return req
+	}
+
+	t.Run("should tell absent, null and empty fields apart", func(t *testing.T) {
+		req := decode(t, `{"name": "", "timezone": null}`)
+
+		assert.Equal(t, validation.Some(""), req.Name)
+		assert.Equal(t, validation.Null[string](), req.Timezone)
+		assert.False(t, req.Username.Set)
+		assert.Equal(t, []string{"name", "timezone"}, req.Fields())
+		assert.NoError(t, validate.Struct(req))
+	})
+
+	t.Run("should validate only values that are set", func(t *testing.T) {
+		assert.Error(t, validate.Struct(decode(t, `{"username": ""}`)))
+		assert.Error(t, validate.Struct(decode(t, `{"timezone": "Mars/Olympus"}`)))
+		assert.NoError(t, validate.Struct(decode(t, `{"username": null}`)))
+	})
+
+	t.Run("should reject null for required fields", func(t *testing.T) {
+		err := validate.Struct(decode(t, `{"email": null, "name": null}`))
+
+		assert.Equal(t, map[string]string{
+			"UpdateUser.Email": "Field Email cannot be null",
+			"UpdateUser.Name":  "Field Name cannot be null",
+		}, validation.CustomErrorMessages(err))
+	})
+
+	t.Run("should leave absent fields out when encoded", func(t *testing.T) {
+		body, err := json.Marshal(validation.UpdateUser{Name: validation.Some("Golang"), Timezone: validation.Null[string]()})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name": "Golang", "timezone": null}`, string(body))
+	})
+}